@@ -0,0 +1,293 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package alerts
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/storage"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const diskSpaceAlertID = "disk_space"
+
+var _ Alerter = &alerter{}
+
+// Config describes what a node alerts about and where the alerts are sent
+type Config struct {
+	// WebhookURL receives the notifications. If empty, alerting is disabled.
+	WebhookURL string `json:"webhookURL"`
+
+	// Format of the notifications, either [SlackFormat] or [PagerDutyFormat]
+	Format string `json:"format"`
+
+	// PagerDutyRoutingKey is the integration key of the PagerDuty service the
+	// events are sent to
+	PagerDutyRoutingKey string `json:"-"`
+
+	// CheckFrequency is the time between two evaluations of the alerts
+	CheckFrequency time.Duration `json:"checkFrequency"`
+
+	// Cooldown is the minimum time between two notifications that the same
+	// alert is firing
+	Cooldown time.Duration `json:"cooldown"`
+
+	// MinDiskSpace is the number of bytes available at [DiskPath] below which
+	// an alert fires. If 0, the disk space isn't watched.
+	MinDiskSpace uint64 `json:"minDiskSpace"`
+	DiskPath     string `json:"diskPath"`
+
+	// MetricRules fire alerts based on the values of metrics
+	MetricRules []MetricRule `json:"metricRules"`
+}
+
+// MetricRule fires an alert for each series of a metric whose value is above
+// [Threshold]. Only counters, gauges and untyped metrics are supported.
+type MetricRule struct {
+	// Name of the metric. A metric also matches if its name ends with
+	// "_"+[Name], so that a rule applies to the metric of every chain.
+	Name string `json:"name"`
+
+	// Threshold the value of the series must exceed for the alert to fire
+	Threshold float64 `json:"threshold"`
+
+	// Increase compares the increase of the series since the previous check,
+	// instead of its value, to [Threshold]
+	Increase bool `json:"increase"`
+}
+
+func (r *MetricRule) matches(name string) bool {
+	return name == r.Name || strings.HasSuffix(name, "_"+r.Name)
+}
+
+// Alert is a condition of the node that an operator should be notified about
+type Alert struct {
+	// ID identifies the condition. The same condition always has the same ID.
+	ID      string
+	Summary string
+}
+
+// Alerter periodically evaluates the alerts of a node and notifies a webhook
+// when they fire and once they're resolved. While an alert keeps firing, it's
+// notified again at most once per cooldown.
+type Alerter interface {
+	// Dispatch evaluates the alerts until Stop is called
+	Dispatch()
+
+	// Stop stops evaluating the alerts
+	Stop()
+}
+
+type alerter struct {
+	log      logging.Logger
+	config   Config
+	health   health.Reporter
+	gatherer prometheus.Gatherer
+	notifier notifier
+	clock    mockable.Clock
+
+	// firing are the alerts that were notified and haven't been resolved yet
+	firing map[string]Alert
+	// lastNotified is the last time each alert was notified as firing
+	lastNotified map[string]time.Time
+	// lastValues is the value of each series watched by an increase rule when
+	// the alerts were last checked
+	lastValues map[string]float64
+
+	closer sync.Once
+	closed chan struct{}
+}
+
+// New returns an Alerter for the node [nodeID] that watches the health checks
+// of [health] and the metrics of [gatherer]
+func New(
+	log logging.Logger,
+	config Config,
+	nodeID ids.ShortID,
+	health health.Reporter,
+	gatherer prometheus.Gatherer,
+) (Alerter, error) {
+	n, err := newNotifier(config, nodeID, &http.Client{Timeout: config.CheckFrequency})
+	if err != nil {
+		return nil, err
+	}
+	return &alerter{
+		log:          log,
+		config:       config,
+		health:       health,
+		gatherer:     gatherer,
+		notifier:     n,
+		firing:       make(map[string]Alert),
+		lastNotified: make(map[string]time.Time),
+		lastValues:   make(map[string]float64),
+		closed:       make(chan struct{}),
+	}, nil
+}
+
+func (a *alerter) Dispatch() {
+	ticker := time.NewTicker(a.config.CheckFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-a.closed:
+			return
+		}
+
+		a.check()
+	}
+}
+
+func (a *alerter) Stop() {
+	a.closer.Do(func() {
+		close(a.closed)
+	})
+}
+
+// check evaluates the alerts and sends the notifications
+func (a *alerter) check() {
+	now := a.clock.Time()
+	current := a.evaluate()
+
+	for id, alert := range current {
+		lastNotified, notified := a.lastNotified[id]
+		if notified && now.Sub(lastNotified) < a.config.Cooldown {
+			continue
+		}
+		if err := a.notifier.notify(alert, true); err != nil {
+			a.log.Warn("failed to notify alert %q: %s", id, err)
+			continue
+		}
+		a.firing[id] = alert
+		a.lastNotified[id] = now
+	}
+
+	for id, alert := range a.firing {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		if err := a.notifier.notify(alert, false); err != nil {
+			a.log.Warn("failed to notify resolution of alert %q: %s", id, err)
+			continue
+		}
+		delete(a.firing, id)
+	}
+}
+
+// evaluate returns the alerts that are currently firing
+func (a *alerter) evaluate() map[string]Alert {
+	alerts := make(map[string]Alert)
+
+	results, _ := a.health.Health()
+	for name, result := range results {
+		if result.Error == nil {
+			continue
+		}
+		id := "health/" + name
+		alerts[id] = Alert{
+			ID:      id,
+			Summary: fmt.Sprintf("health check %q is failing: %s", name, *result.Error),
+		}
+	}
+
+	if a.config.MinDiskSpace > 0 {
+		available, err := storage.OsDiskStat(a.config.DiskPath)
+		switch {
+		case err != nil:
+			a.log.Warn("failed to get the disk space available at %s: %s", a.config.DiskPath, err)
+		case available < a.config.MinDiskSpace:
+			alerts[diskSpaceAlertID] = Alert{
+				ID: diskSpaceAlertID,
+				Summary: fmt.Sprintf(
+					"only %d MiB are available at %s, less than %d MiB",
+					available/units.MiB,
+					a.config.DiskPath,
+					a.config.MinDiskSpace/units.MiB,
+				),
+			}
+		}
+	}
+
+	if len(a.config.MetricRules) == 0 {
+		return alerts
+	}
+	families, err := a.gatherer.Gather()
+	if err != nil {
+		a.log.Warn("failed to gather the metrics to check: %s", err)
+		return alerts
+	}
+	for _, family := range families {
+		for i := range a.config.MetricRules {
+			rule := &a.config.MetricRules[i]
+			if rule.matches(family.GetName()) {
+				a.evaluateRule(rule, family, alerts)
+			}
+		}
+	}
+	return alerts
+}
+
+func (a *alerter) evaluateRule(rule *MetricRule, family *dto.MetricFamily, alerts map[string]Alert) {
+	for _, metric := range family.GetMetric() {
+		var value float64
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			value = metric.GetCounter().GetValue()
+		case dto.MetricType_GAUGE:
+			value = metric.GetGauge().GetValue()
+		case dto.MetricType_UNTYPED:
+			value = metric.GetUntyped().GetValue()
+		default:
+			continue
+		}
+
+		series := seriesName(family.GetName(), metric.GetLabel())
+		summaryFormat := "%s is %v, above %v"
+		if rule.Increase {
+			previous, ok := a.lastValues[series]
+			a.lastValues[series] = value
+			if !ok {
+				continue
+			}
+			value -= previous
+			summaryFormat = "%s increased by %v since the previous check, above %v"
+		}
+		if value <= rule.Threshold {
+			continue
+		}
+
+		id := "metric/" + series
+		alerts[id] = Alert{
+			ID:      id,
+			Summary: fmt.Sprintf(summaryFormat, series, value, rule.Threshold),
+		}
+	}
+}
+
+// seriesName formats [name] and [labels] like the Prometheus exposition format
+func seriesName(name string, labels []*dto.LabelPair) string {
+	if len(labels) == 0 {
+		return name
+	}
+	pairs := make([]string, len(labels))
+	for i, label := range labels {
+		pairs[i] = fmt.Sprintf("%s=%q", label.GetName(), label.GetValue())
+	}
+	sort.Strings(pairs)
+	return fmt.Sprintf("%s{%s}", name, strings.Join(pairs, ","))
+}
@@ -0,0 +1,231 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package alerts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type testReporter struct {
+	results map[string]health.Result
+}
+
+func (r *testReporter) Readiness() (map[string]health.Result, bool) { return r.results, true }
+func (r *testReporter) Health() (map[string]health.Result, bool)    { return r.results, true }
+func (r *testReporter) Liveness() (map[string]health.Result, bool)  { return r.results, true }
+
+type notification struct {
+	alert  Alert
+	firing bool
+}
+
+type testNotifier struct {
+	notifications []notification
+}
+
+func (n *testNotifier) notify(alert Alert, firing bool) error {
+	n.notifications = append(n.notifications, notification{
+		alert:  alert,
+		firing: firing,
+	})
+	return nil
+}
+
+func newTestAlerter(config Config, reporter health.Reporter, gatherer prometheus.Gatherer) (*alerter, *testNotifier) {
+	n := &testNotifier{}
+	return &alerter{
+		log:          logging.NoLog{},
+		config:       config,
+		health:       reporter,
+		gatherer:     gatherer,
+		notifier:     n,
+		firing:       make(map[string]Alert),
+		lastNotified: make(map[string]time.Time),
+		lastValues:   make(map[string]float64),
+		closed:       make(chan struct{}),
+	}, n
+}
+
+func TestAlerterHealthChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	errStr := "unhealthy"
+	reporter := &testReporter{
+		results: map[string]health.Result{
+			"network": {Error: &errStr},
+			"C":       {},
+		},
+	}
+	a, n := newTestAlerter(Config{Cooldown: time.Minute}, reporter, prometheus.NewRegistry())
+	now := time.Now()
+	a.clock.Set(now)
+
+	a.check()
+	assert.Len(n.notifications, 1)
+	assert.True(n.notifications[0].firing)
+	assert.Equal("health/network", n.notifications[0].alert.ID)
+
+	// The alert isn't notified again during the cooldown
+	a.clock.Set(now.Add(time.Second))
+	a.check()
+	assert.Len(n.notifications, 1)
+
+	a.clock.Set(now.Add(time.Minute))
+	a.check()
+	assert.Len(n.notifications, 2)
+	assert.True(n.notifications[1].firing)
+
+	// Once the check passes, the resolution is notified once
+	reporter.results["network"] = health.Result{}
+	a.check()
+	assert.Len(n.notifications, 3)
+	assert.False(n.notifications[2].firing)
+	assert.Equal("health/network", n.notifications[2].alert.ID)
+
+	a.check()
+	assert.Len(n.notifications, 3)
+}
+
+func TestAlerterMetricRules(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	missed := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "avalanche_C_vm",
+		Name:      "proposer_windows_missed",
+	})
+	benched := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "avalanche",
+		Name:      "benched_num",
+	})
+	assert.NoError(registry.Register(missed))
+	assert.NoError(registry.Register(benched))
+
+	config := Config{
+		MetricRules: []MetricRule{
+			{
+				Name:     "proposer_windows_missed",
+				Increase: true,
+			},
+			{
+				Name:      "benched_num",
+				Threshold: 1,
+			},
+		},
+	}
+	a, n := newTestAlerter(config, &testReporter{}, registry)
+
+	// The first check only records the value of the counter
+	missed.Add(5)
+	benched.Set(1)
+	a.check()
+	assert.Empty(n.notifications)
+
+	missed.Inc()
+	benched.Set(2)
+	a.check()
+	assert.Len(n.notifications, 2)
+	alertIDs := map[string]bool{}
+	for _, notification := range n.notifications {
+		assert.True(notification.firing)
+		alertIDs[notification.alert.ID] = true
+	}
+	assert.True(alertIDs["metric/avalanche_C_vm_proposer_windows_missed"])
+	assert.True(alertIDs["metric/avalanche_benched_num"])
+
+	// The counter didn't increase and the gauge went back to the threshold
+	benched.Set(1)
+	a.check()
+	assert.Len(n.notifications, 4)
+	assert.False(n.notifications[2].firing)
+	assert.False(n.notifications[3].firing)
+}
+
+func TestSeriesName(t *testing.T) {
+	assert := assert.New(t)
+
+	name0, value0 := "op", "get"
+	name1, value1 := "chain", "C"
+	assert.Equal("metric", seriesName("metric", nil))
+	assert.Equal(
+		`metric{chain="C",op="get"}`,
+		seriesName("metric", []*dto.LabelPair{
+			{Name: &name0, Value: &value0},
+			{Name: &name1, Value: &value1},
+		}),
+	)
+}
+
+func TestPagerDutyNotifier(t *testing.T) {
+	assert := assert.New(t)
+
+	events := []pagerDutyEvent(nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := pagerDutyEvent{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	config := Config{
+		WebhookURL:          server.URL,
+		Format:              PagerDutyFormat,
+		PagerDutyRoutingKey: "key",
+	}
+	n, err := newNotifier(config, ids.ShortEmpty, server.Client())
+	assert.NoError(err)
+
+	alert := Alert{
+		ID:      diskSpaceAlertID,
+		Summary: "low disk space",
+	}
+	assert.NoError(n.notify(alert, true))
+	assert.NoError(n.notify(alert, false))
+	assert.Len(events, 2)
+
+	assert.Equal("key", events[0].RoutingKey)
+	assert.Equal("trigger", events[0].EventAction)
+	assert.NotNil(events[0].Payload)
+	assert.Equal("low disk space", events[0].Payload.Summary)
+
+	assert.Equal("resolve", events[1].EventAction)
+	assert.Nil(events[1].Payload)
+	assert.Equal(events[0].DedupKey, events[1].DedupKey)
+}
+
+func TestSlackNotifierUnexpectedResponse(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	config := Config{
+		WebhookURL: server.URL,
+		Format:     SlackFormat,
+	}
+	n, err := newNotifier(config, ids.ShortEmpty, server.Client())
+	assert.NoError(err)
+	assert.ErrorIs(n.notify(Alert{}, true), errUnexpectedResponse)
+}
+
+func TestNewNotifierUnknownFormat(t *testing.T) {
+	_, err := newNotifier(Config{Format: "email"}, ids.ShortEmpty, http.DefaultClient)
+	assert.ErrorIs(t, err, errUnknownFormat)
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+const (
+	// SlackFormat sends the notifications as the messages of a Slack incoming
+	// webhook
+	SlackFormat = "slack"
+	// PagerDutyFormat sends the notifications as PagerDuty Events API v2
+	// events. The resolution of an alert resolves its incident.
+	PagerDutyFormat = "pagerduty"
+)
+
+var (
+	errUnknownFormat      = errors.New("unknown alert format")
+	errMissingRoutingKey  = errors.New("missing PagerDuty routing key")
+	errUnexpectedResponse = errors.New("unexpected webhook response")
+)
+
+// notifier sends the notifications about an alert to the webhook
+type notifier interface {
+	// notify sends that [alert] is firing, or that it was resolved if
+	// [firing] is false
+	notify(alert Alert, firing bool) error
+}
+
+func newNotifier(config Config, nodeID ids.ShortID, client *http.Client) (notifier, error) {
+	webhook := webhook{
+		url:    config.WebhookURL,
+		client: client,
+	}
+	source := nodeID.PrefixedString(constants.NodeIDPrefix)
+	switch config.Format {
+	case SlackFormat:
+		return &slackNotifier{
+			webhook: webhook,
+			source:  source,
+		}, nil
+	case PagerDutyFormat:
+		if config.PagerDutyRoutingKey == "" {
+			return nil, errMissingRoutingKey
+		}
+		return &pagerDutyNotifier{
+			webhook:    webhook,
+			source:     source,
+			routingKey: config.PagerDutyRoutingKey,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownFormat, config.Format)
+	}
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+// post sends [body], encoded as JSON, to the webhook
+func (w *webhook) post(body interface{}) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%w: status code %d", errUnexpectedResponse, resp.StatusCode)
+	}
+	return nil
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+type slackNotifier struct {
+	webhook
+	source string
+}
+
+func (n *slackNotifier) notify(alert Alert, firing bool) error {
+	status := "FIRING"
+	if !firing {
+		status = "RESOLVED"
+	}
+	return n.post(slackMessage{
+		Text: fmt.Sprintf("[%s] %s: %s", status, n.source, alert.Summary),
+	})
+}
+
+type pagerDutyPayload struct {
+	Summary  string `json:"summary"`
+	Source   string `json:"source"`
+	Severity string `json:"severity"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string `json:"routing_key"`
+	EventAction string `json:"event_action"`
+	// DedupKey groups the events of the same alert into one incident
+	DedupKey string            `json:"dedup_key"`
+	Payload  *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyNotifier struct {
+	webhook
+	source     string
+	routingKey string
+}
+
+func (n *pagerDutyNotifier) notify(alert Alert, firing bool) error {
+	event := pagerDutyEvent{
+		RoutingKey:  n.routingKey,
+		EventAction: "resolve",
+		DedupKey:    fmt.Sprintf("%s/%s", n.source, alert.ID),
+	}
+	if firing {
+		event.EventAction = "trigger"
+		event.Payload = &pagerDutyPayload{
+			Summary:  alert.Summary,
+			Source:   n.source,
+			Severity: "error",
+		}
+	}
+	return n.post(event)
+}
@@ -5,10 +5,16 @@ package admin
 
 import (
 	"context"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
 )
 
 // Interface compliance
@@ -22,9 +28,22 @@ type Client interface {
 	LockProfile(context.Context, ...rpc.Option) (bool, error)
 	Alias(ctx context.Context, endpoint string, alias string, options ...rpc.Option) (bool, error)
 	AliasChain(ctx context.Context, chainID string, alias string, options ...rpc.Option) (bool, error)
+	RemoveChainAlias(ctx context.Context, alias string, options ...rpc.Option) (bool, error)
 	GetChainAliases(ctx context.Context, chainID string, options ...rpc.Option) ([]string, error)
+	ListChainAliases(ctx context.Context, options ...rpc.Option) ([]chains.ChainAliases, error)
+	StopChain(ctx context.Context, chain string, options ...rpc.Option) (bool, error)
+	StartChain(ctx context.Context, chain string, options ...rpc.Option) (bool, error)
 	Stacktrace(context.Context, ...rpc.Option) (bool, error)
 	LoadVMs(context.Context, ...rpc.Option) (map[ids.ID][]string, map[ids.ID]string, error)
+	ReloadMessagePolicies(context.Context, ...rpc.Option) (bool, error)
+	InspectSharedMemory(ctx context.Context, sourceChain string, destinationChain string, options ...rpc.Option) (*InspectSharedMemoryReply, error)
+	GetRejectedBlocks(ctx context.Context, chain string, options ...rpc.Option) ([]proposervm.RejectedBlock, error)
+	GetConflictGraph(ctx context.Context, chain string, options ...rpc.Option) (*snowstorm.ConflictGraph, error)
+	GetStallDiagnostics(ctx context.Context, chain string, options ...rpc.Option) (*chains.StallDiagnostics, error)
+	GetChainVMInfo(ctx context.Context, chain string, options ...rpc.Option) (*GetChainVMInfoReply, error)
+	BanPeer(ctx context.Context, target BanTargetArgs, duration time.Duration, options ...rpc.Option) (bool, error)
+	UnbanPeer(ctx context.Context, target BanTargetArgs, options ...rpc.Option) (bool, error)
+	GetBannedPeers(context.Context, ...rpc.Option) ([]APIBan, error)
 }
 
 // Client implementation for the Avalanche Platform Info API Endpoint
@@ -81,6 +100,14 @@ func (c *client) AliasChain(ctx context.Context, chain, alias string, options ..
 	return res.Success, err
 }
 
+func (c *client) RemoveChainAlias(ctx context.Context, alias string, options ...rpc.Option) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "removeChainAlias", &RemoveChainAliasArgs{
+		Alias: alias,
+	}, res, options...)
+	return res.Success, err
+}
+
 func (c *client) GetChainAliases(ctx context.Context, chain string, options ...rpc.Option) ([]string, error) {
 	res := &GetChainAliasesReply{}
 	err := c.requester.SendRequest(ctx, "getChainAliases", &GetChainAliasesArgs{
@@ -89,6 +116,28 @@ func (c *client) GetChainAliases(ctx context.Context, chain string, options ...r
 	return res.Aliases, err
 }
 
+func (c *client) ListChainAliases(ctx context.Context, options ...rpc.Option) ([]chains.ChainAliases, error) {
+	res := &ListChainAliasesReply{}
+	err := c.requester.SendRequest(ctx, "listChainAliases", struct{}{}, res, options...)
+	return res.Chains, err
+}
+
+func (c *client) StopChain(ctx context.Context, chain string, options ...rpc.Option) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "stopChain", &StopChainArgs{
+		Chain: chain,
+	}, res, options...)
+	return res.Success, err
+}
+
+func (c *client) StartChain(ctx context.Context, chain string, options ...rpc.Option) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "startChain", &StartChainArgs{
+		Chain: chain,
+	}, res, options...)
+	return res.Success, err
+}
+
 func (c *client) Stacktrace(ctx context.Context, options ...rpc.Option) (bool, error) {
 	res := &api.SuccessResponse{}
 	err := c.requester.SendRequest(ctx, "stacktrace", struct{}{}, res, options...)
@@ -100,3 +149,73 @@ func (c *client) LoadVMs(ctx context.Context, options ...rpc.Option) (map[ids.ID
 	err := c.requester.SendRequest(ctx, "loadVMs", struct{}{}, res, options...)
 	return res.NewVMs, res.FailedVMs, err
 }
+
+func (c *client) ReloadMessagePolicies(ctx context.Context, options ...rpc.Option) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "reloadMessagePolicies", struct{}{}, res, options...)
+	return res.Success, err
+}
+
+func (c *client) InspectSharedMemory(ctx context.Context, sourceChain, destinationChain string, options ...rpc.Option) (*InspectSharedMemoryReply, error) {
+	res := &InspectSharedMemoryReply{}
+	err := c.requester.SendRequest(ctx, "inspectSharedMemory", &InspectSharedMemoryArgs{
+		SourceChain:      sourceChain,
+		DestinationChain: destinationChain,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) GetRejectedBlocks(ctx context.Context, chain string, options ...rpc.Option) ([]proposervm.RejectedBlock, error) {
+	res := &GetRejectedBlocksReply{}
+	err := c.requester.SendRequest(ctx, "getRejectedBlocks", &GetRejectedBlocksArgs{
+		Chain: chain,
+	}, res, options...)
+	return res.Blocks, err
+}
+
+func (c *client) GetConflictGraph(ctx context.Context, chain string, options ...rpc.Option) (*snowstorm.ConflictGraph, error) {
+	res := &GetConflictGraphReply{}
+	err := c.requester.SendRequest(ctx, "getConflictGraph", &GetConflictGraphArgs{
+		Chain: chain,
+	}, res, options...)
+	return &res.ConflictGraph, err
+}
+
+func (c *client) GetStallDiagnostics(ctx context.Context, chain string, options ...rpc.Option) (*chains.StallDiagnostics, error) {
+	res := &GetStallDiagnosticsReply{
+		StallDiagnostics: &chains.StallDiagnostics{},
+	}
+	err := c.requester.SendRequest(ctx, "getStallDiagnostics", &GetStallDiagnosticsArgs{
+		Chain: chain,
+	}, res, options...)
+	return res.StallDiagnostics, err
+}
+
+func (c *client) GetChainVMInfo(ctx context.Context, chain string, options ...rpc.Option) (*GetChainVMInfoReply, error) {
+	res := &GetChainVMInfoReply{}
+	err := c.requester.SendRequest(ctx, "getChainVMInfo", &GetChainVMInfoArgs{
+		Chain: chain,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) BanPeer(ctx context.Context, target BanTargetArgs, duration time.Duration, options ...rpc.Option) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "banPeer", &BanPeerArgs{
+		BanTargetArgs: target,
+		Duration:      cjson.Uint64(duration / time.Second),
+	}, res, options...)
+	return res.Success, err
+}
+
+func (c *client) UnbanPeer(ctx context.Context, target BanTargetArgs, options ...rpc.Option) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "unbanPeer", &target, res, options...)
+	return res.Success, err
+}
+
+func (c *client) GetBannedPeers(ctx context.Context, options ...rpc.Option) ([]APIBan, error) {
+	res := &GetBannedPeersReply{}
+	err := c.requester.SendRequest(ctx, "getBannedPeers", struct{}{}, res, options...)
+	return res.Bans, err
+}
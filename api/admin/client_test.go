@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
@@ -66,6 +67,9 @@ func (mc *mockClient) SendRequest(ctx context.Context, method string, params int
 	case *LoadVMsReply:
 		response := mc.response.(*LoadVMsReply)
 		*p = *response
+	case *ListChainAliasesReply:
+		response := mc.response.(*ListChainAliasesReply)
+		*p = *response
 	default:
 		panic("illegal type")
 	}
@@ -186,6 +190,25 @@ func TestAliasChain(t *testing.T) {
 	}
 }
 
+func TestRemoveChainAlias(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.RemoveChainAlias(context.Background(), "chain-alias")
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
 func TestGetChainAliases(t *testing.T) {
 	t.Run("successful", func(t *testing.T) {
 		expectedReply := []string{"alias1", "alias2"}
@@ -207,6 +230,68 @@ func TestGetChainAliases(t *testing.T) {
 	})
 }
 
+func TestListChainAliases(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := []chains.ChainAliases{{
+			ChainID: ids.GenerateTestID(),
+			Aliases: []string{"alias1", "alias2"},
+		}}
+		mockClient := client{requester: NewMockClient(&ListChainAliasesReply{
+			Chains: expectedReply,
+		}, nil)}
+
+		reply, err := mockClient.ListChainAliases(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := client{requester: NewMockClient(&ListChainAliasesReply{}, errors.New("some error"))}
+
+		_, err := mockClient.ListChainAliases(context.Background())
+
+		assert.EqualError(t, err, "some error")
+	})
+}
+
+func TestStopChain(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.StopChain(context.Background(), "chain")
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
+func TestStartChain(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.StartChain(context.Background(), "chain")
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
 func TestStacktrace(t *testing.T) {
 	tests := GetSuccessResponseTests()
 
@@ -226,6 +311,25 @@ func TestStacktrace(t *testing.T) {
 	}
 }
 
+func TestReloadMessagePolicies(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.ReloadMessagePolicies(context.Background())
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
 func TestReloadInstalledVMs(t *testing.T) {
 	t.Run("successful", func(t *testing.T) {
 		expectedNewVMs := map[ids.ID][]string{
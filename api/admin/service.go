@@ -5,20 +5,25 @@ package admin
 
 import (
 	"errors"
+	"net"
 	"net/http"
-
-	"github.com/gorilla/rpc/v2"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/doc"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
 	"github.com/ava-labs/avalanchego/vms/registry"
 
 	cjson "github.com/ava-labs/avalanchego/utils/json"
@@ -34,6 +39,10 @@ const (
 var (
 	errAliasTooLong = errors.New("alias length is too long")
 	errNoLogLevel   = errors.New("need to specify either displayLevel or logLevel")
+	errPrimaryAlias = errors.New("can't remove a chain's ID from its aliases")
+	errSameChain    = errors.New("a chain doesn't share memory with itself")
+	errInvalidIP    = errors.New("invalid IP")
+	errNotBanned    = errors.New("peer isn't banned")
 )
 
 type Config struct {
@@ -42,9 +51,14 @@ type Config struct {
 	LogFactory   logging.Factory
 	NodeConfig   interface{}
 	ChainManager chains.Manager
+	ChainAliases *chains.PersistedAliases
+	AtomicMemory *atomic.Memory
 	HTTPServer   server.PathAdderWithReadLock
 	VMRegistry   registry.VMRegistry
 	VMManager    vms.Manager
+	Network      network.Network
+	// MessagePolicyReloader re-reads the router's per-subnet message policies
+	MessagePolicyReloader func() error
 }
 
 // Admin is the API service for node admin management
@@ -56,7 +70,7 @@ type Admin struct {
 // NewService returns a new admin API service.
 // All of the fields in [config] must be set.
 func NewService(config Config) (*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+	newServer := doc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
@@ -124,7 +138,8 @@ type AliasChainArgs struct {
 	Alias string `json:"alias"`
 }
 
-// AliasChain attempts to alias a chain to a new name
+// AliasChain attempts to alias a chain to a new name. The alias is persisted,
+// so it's restored when the node restarts.
 func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *api.SuccessResponse) error {
 	service.Log.Debug("Admin: AliasChain called with Chain: %s, Alias: %s", args.Chain, args.Alias)
 
@@ -136,12 +151,40 @@ func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *a
 		return err
 	}
 
-	if err := service.ChainManager.Alias(chainID, args.Alias); err != nil {
+	if err := service.ChainAliases.Alias(chainID, args.Alias); err != nil {
 		return err
 	}
 
+	// The API server resolves chain aliases when a request is routed, so the
+	// new alias is reachable under /ext/bc/ without registering new routes.
 	reply.Success = true
-	return service.HTTPServer.AddAliasesWithReadLock(constants.ChainAliasPrefix+chainID.String(), constants.ChainAliasPrefix+args.Alias)
+	return nil
+}
+
+// RemoveChainAliasArgs are the arguments for calling RemoveChainAlias
+type RemoveChainAliasArgs struct {
+	Alias string `json:"alias"`
+}
+
+// RemoveChainAlias removes an alias of a chain. Requests to the chain's API
+// under the removed alias stop being routed immediately. If the alias was
+// given by AliasChain, it's no longer restored when the node restarts.
+func (service *Admin) RemoveChainAlias(_ *http.Request, args *RemoveChainAliasArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: RemoveChainAlias called with Alias: %s", args.Alias)
+
+	chainID, err := service.ChainManager.Lookup(args.Alias)
+	if err != nil {
+		return err
+	}
+	if args.Alias == chainID.String() {
+		return errPrimaryAlias
+	}
+	if err := service.ChainAliases.RemoveAlias(args.Alias); err != nil {
+		return err
+	}
+
+	reply.Success = true
+	return nil
 }
 
 // GetChainAliasesArgs are the arguments for calling GetChainAliases
@@ -167,6 +210,63 @@ func (service *Admin) GetChainAliases(_ *http.Request, args *GetChainAliasesArgs
 	return err
 }
 
+// ListChainAliasesReply are the aliases given by AliasChain
+type ListChainAliasesReply struct {
+	Chains []chains.ChainAliases `json:"chains"`
+}
+
+// ListChainAliases returns the aliases given by AliasChain that weren't
+// removed, by chain. The aliases from the genesis aren't included.
+func (service *Admin) ListChainAliases(_ *http.Request, _ *struct{}, reply *ListChainAliasesReply) error {
+	service.Log.Debug("Admin: ListChainAliases called")
+
+	var err error
+	reply.Chains, err = service.ChainAliases.List()
+	return err
+}
+
+// StopChainArgs are the arguments for calling StopChain
+type StopChainArgs struct {
+	Chain string `json:"chain"`
+}
+
+// StopChain shuts down a chain's engine and VM without stopping the node
+func (service *Admin) StopChain(_ *http.Request, args *StopChainArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: StopChain called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	if err := service.ChainManager.StopChain(chainID); err != nil {
+		return err
+	}
+
+	reply.Success = true
+	return nil
+}
+
+// StartChainArgs are the arguments for calling StartChain
+type StartChainArgs struct {
+	Chain string `json:"chain"`
+}
+
+// StartChain restarts a chain that was stopped with StopChain
+func (service *Admin) StartChain(_ *http.Request, args *StartChainArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: StartChain called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	if err := service.ChainManager.StartChain(chainID); err != nil {
+		return err
+	}
+
+	reply.Success = true
+	return nil
+}
+
 // Stacktrace returns the current global stacktrace
 func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
 	service.Log.Debug("Admin: Stacktrace called")
@@ -301,3 +401,266 @@ func (service *Admin) LoadVMs(_ *http.Request, _ *struct{}, reply *LoadVMsReply)
 	reply.NewVMs, err = ids.GetRelevantAliases(service.VMManager, loadedVMs)
 	return err
 }
+
+// ReloadMessagePolicies re-reads the per-subnet message policies from the
+// node's message policy file and applies them to the router.
+func (service *Admin) ReloadMessagePolicies(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: ReloadMessagePolicies called")
+
+	if err := service.MessagePolicyReloader(); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// InspectSharedMemoryArgs are the arguments for calling InspectSharedMemory
+type InspectSharedMemoryArgs struct {
+	// Chain that put the values
+	SourceChain string `json:"sourceChain"`
+	// Chain the values were put for
+	DestinationChain string `json:"destinationChain"`
+}
+
+// InspectSharedMemoryReply is the response from calling InspectSharedMemory
+type InspectSharedMemoryReply struct {
+	atomic.Inspection
+	// True if the chains run on this node and are validated by the same
+	// subnet, so that they can exchange atomic requests
+	SameSubnet bool `json:"sameSubnet"`
+}
+
+// InspectSharedMemory returns a summary of the values the source chain put in
+// shared memory for the destination chain
+func (service *Admin) InspectSharedMemory(_ *http.Request, args *InspectSharedMemoryArgs, reply *InspectSharedMemoryReply) error {
+	service.Log.Debug("Admin: InspectSharedMemory called with %s and %s", args.SourceChain, args.DestinationChain)
+
+	sourceChainID, err := service.ChainManager.Lookup(args.SourceChain)
+	if err != nil {
+		return err
+	}
+	destinationChainID, err := service.ChainManager.Lookup(args.DestinationChain)
+	if err != nil {
+		return err
+	}
+	if sourceChainID == destinationChainID {
+		return errSameChain
+	}
+
+	reply.Inspection, err = service.AtomicMemory.Inspect(sourceChainID, destinationChainID)
+	if err != nil {
+		return err
+	}
+
+	sourceSubnetID, err := service.ChainManager.SubnetID(sourceChainID)
+	if err != nil {
+		return nil
+	}
+	destinationSubnetID, err := service.ChainManager.SubnetID(destinationChainID)
+	if err != nil {
+		return nil
+	}
+	reply.SameSubnet = sourceSubnetID == destinationSubnetID
+	return nil
+}
+
+// GetRejectedBlocksArgs are the arguments for calling GetRejectedBlocks
+type GetRejectedBlocksArgs struct {
+	Chain string `json:"chain"`
+}
+
+// GetRejectedBlocksReply is the response from calling GetRejectedBlocks
+type GetRejectedBlocksReply struct {
+	// Rejected blocks, from newest to oldest
+	Blocks []proposervm.RejectedBlock `json:"blocks"`
+}
+
+// GetRejectedBlocks returns why the most recently rejected blocks of a snowman
+// chain were rejected
+func (service *Admin) GetRejectedBlocks(_ *http.Request, args *GetRejectedBlocksArgs, reply *GetRejectedBlocksReply) error {
+	service.Log.Debug("Admin: GetRejectedBlocks called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	reply.Blocks, err = service.ChainManager.RejectedBlocks(chainID)
+	return err
+}
+
+// GetConflictGraphArgs are the arguments for calling GetConflictGraph
+type GetConflictGraphArgs struct {
+	Chain string `json:"chain"`
+}
+
+// GetConflictGraphReply is the response from calling GetConflictGraph
+type GetConflictGraphReply struct {
+	snowstorm.ConflictGraph
+}
+
+// GetConflictGraph returns the txs being decided by an avalanche chain, along
+// with their confidence and the conflicts between them, to help diagnose why a
+// tx isn't being finalized
+func (service *Admin) GetConflictGraph(_ *http.Request, args *GetConflictGraphArgs, reply *GetConflictGraphReply) error {
+	service.Log.Debug("Admin: GetConflictGraph called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	reply.ConflictGraph, err = service.ChainManager.ConflictGraph(chainID)
+	return err
+}
+
+// GetStallDiagnosticsArgs are the arguments for calling GetStallDiagnostics
+type GetStallDiagnosticsArgs struct {
+	Chain string `json:"chain"`
+}
+
+// GetStallDiagnosticsReply is the response from calling GetStallDiagnostics
+type GetStallDiagnosticsReply struct {
+	*chains.StallDiagnostics
+}
+
+// GetStallDiagnostics returns the diagnostics bundle captured when a snowman
+// chain last stopped accepting blocks
+func (service *Admin) GetStallDiagnostics(_ *http.Request, args *GetStallDiagnosticsArgs, reply *GetStallDiagnosticsReply) error {
+	service.Log.Debug("Admin: GetStallDiagnostics called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	reply.StallDiagnostics, err = service.ChainManager.StallDiagnostics(chainID)
+	return err
+}
+
+// GetChainVMInfoArgs are the arguments for calling GetChainVMInfo
+type GetChainVMInfoArgs struct {
+	Chain string `json:"chain"`
+}
+
+// GetChainVMInfoReply is the response from calling GetChainVMInfo
+type GetChainVMInfoReply struct {
+	VMID      ids.ID   `json:"vmID"`
+	VMAliases []string `json:"vmAliases"`
+	proposervm.InnerVMInfo
+}
+
+// GetChainVMInfo returns the VM run by a snowman chain, along with its version
+// and the optional capabilities it implements. Optimizations that rely on a
+// capability aren't active for chains whose VM doesn't implement it.
+func (service *Admin) GetChainVMInfo(_ *http.Request, args *GetChainVMInfoArgs, reply *GetChainVMInfoReply) error {
+	service.Log.Debug("Admin: GetChainVMInfo called with Chain: %s", args.Chain)
+
+	chainID, err := service.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	reply.VMID, reply.InnerVMInfo, err = service.ChainManager.ChainVMInfo(chainID)
+	if err != nil {
+		return err
+	}
+	reply.VMAliases, err = service.VMManager.Aliases(reply.VMID)
+	return err
+}
+
+// BanTargetArgs identify the peers a ban applies to. Exactly one of the fields
+// must be set.
+type BanTargetArgs struct {
+	// NodeID of the banned peer
+	NodeID string `json:"nodeID"`
+	// IP the banned peers connect from
+	IP string `json:"ip"`
+}
+
+func (args *BanTargetArgs) target() (network.BanTarget, error) {
+	target := network.BanTarget{}
+	if args.NodeID != "" {
+		nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return target, err
+		}
+		target.NodeID = nodeID
+	}
+	if args.IP != "" {
+		target.IP = net.ParseIP(args.IP)
+		if target.IP == nil {
+			return target, errInvalidIP
+		}
+	}
+	return target, nil
+}
+
+// BanPeerArgs are the arguments for calling BanPeer
+type BanPeerArgs struct {
+	BanTargetArgs
+	// Duration of the ban, in seconds
+	Duration cjson.Uint64 `json:"duration"`
+}
+
+// BanPeer disconnects from the peers matching the target and refuses any
+// connection with them until the ban expires. The ban is kept across restarts.
+func (service *Admin) BanPeer(_ *http.Request, args *BanPeerArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: BanPeer called with NodeID: %q, IP: %q, Duration: %d", args.NodeID, args.IP, args.Duration)
+
+	target, err := args.target()
+	if err != nil {
+		return err
+	}
+	if err := service.Network.Ban(target, time.Duration(args.Duration)*time.Second); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UnbanPeer lifts the ban of the target
+func (service *Admin) UnbanPeer(_ *http.Request, args *BanTargetArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: UnbanPeer called with NodeID: %q, IP: %q", args.NodeID, args.IP)
+
+	target, err := args.target()
+	if err != nil {
+		return err
+	}
+	banned, err := service.Network.Unban(target)
+	if err != nil {
+		return err
+	}
+	if !banned {
+		return errNotBanned
+	}
+	reply.Success = true
+	return nil
+}
+
+// APIBan is a ban in effect
+type APIBan struct {
+	NodeID string `json:"nodeID,omitempty"`
+	IP     string `json:"ip,omitempty"`
+	// Expiry is the unix time the ban is lifted at
+	Expiry cjson.Uint64 `json:"expiry"`
+}
+
+// GetBannedPeersReply is the response from calling GetBannedPeers
+type GetBannedPeersReply struct {
+	Bans []APIBan `json:"bans"`
+}
+
+// GetBannedPeers returns the bans in effect, sorted by expiry
+func (service *Admin) GetBannedPeers(_ *http.Request, _ *struct{}, reply *GetBannedPeersReply) error {
+	service.Log.Debug("Admin: GetBannedPeers called")
+
+	bans := service.Network.Bans()
+	reply.Bans = make([]APIBan, len(bans))
+	for i, ban := range bans {
+		apiBan := APIBan{Expiry: cjson.Uint64(ban.Expiry.Unix())}
+		if ban.IP != nil {
+			apiBan.IP = ban.IP.String()
+		} else {
+			apiBan.NodeID = ban.NodeID.PrefixedString(constants.NodeIDPrefix)
+		}
+		reply.Bans[i] = apiBan
+	}
+	return nil
+}
@@ -5,13 +5,19 @@ package admin
 
 import (
 	"errors"
+	"net"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms"
 	"github.com/ava-labs/avalanchego/vms/registry"
@@ -120,3 +126,92 @@ func TestLoadVMsGetAliasesFails(t *testing.T) {
 
 	assert.Equal(t, err, errOops)
 }
+
+func TestInspectSharedMemory(t *testing.T) {
+	assert := assert.New(t)
+
+	memory := &atomic.Memory{}
+	assert.NoError(memory.Initialize(logging.NoLog{}, memdb.New()))
+	admin := &Admin{Config: Config{
+		Log:          logging.NoLog{},
+		ChainManager: chains.MockManager{},
+		AtomicMemory: memory,
+	}}
+
+	chainID0 := ids.GenerateTestID()
+	chainID1 := ids.GenerateTestID()
+	sm := memory.NewSharedMemory(chainID0)
+	assert.NoError(sm.Apply(map[ids.ID]*atomic.Requests{chainID1: {PutRequests: []*atomic.Element{{
+		Key:   []byte{0},
+		Value: []byte{1},
+	}}}}))
+
+	reply := InspectSharedMemoryReply{}
+	assert.NoError(admin.InspectSharedMemory(nil, &InspectSharedMemoryArgs{
+		SourceChain:      chainID0.String(),
+		DestinationChain: chainID1.String(),
+	}, &reply))
+	assert.Equal(1, reply.Values)
+	assert.Zero(reply.PendingRemovals)
+	assert.True(reply.SameSubnet)
+
+	assert.ErrorIs(admin.InspectSharedMemory(nil, &InspectSharedMemoryArgs{
+		SourceChain:      chainID0.String(),
+		DestinationChain: chainID0.String(),
+	}, &reply), errSameChain)
+}
+
+func TestPersistedChainAliases(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	aliaser := ids.NewAliaser()
+	admin := &Admin{Config: Config{
+		Log:          logging.NoLog{},
+		ChainManager: chains.MockManager{},
+		ChainAliases: chains.NewPersistedAliases(aliaser, memdb.New()),
+	}}
+
+	reply := api.SuccessResponse{}
+	assert.NoError(admin.AliasChain(nil, &AliasChainArgs{
+		Chain: chainID.String(),
+		Alias: "alias",
+	}, &reply))
+	assert.True(reply.Success)
+	lookedUp, err := aliaser.Lookup("alias")
+	assert.NoError(err)
+	assert.Equal(chainID, lookedUp)
+
+	listReply := ListChainAliasesReply{}
+	assert.NoError(admin.ListChainAliases(nil, nil, &listReply))
+	assert.Equal([]chains.ChainAliases{{ChainID: chainID, Aliases: []string{"alias"}}}, listReply.Chains)
+
+	assert.NoError(admin.RemoveChainAlias(nil, &RemoveChainAliasArgs{Alias: "alias"}, &reply))
+	_, err = aliaser.Lookup("alias")
+	assert.Error(err)
+
+	listReply = ListChainAliasesReply{}
+	assert.NoError(admin.ListChainAliases(nil, nil, &listReply))
+	assert.Empty(listReply.Chains)
+}
+
+func TestBanTargetArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	target, err := (&BanTargetArgs{NodeID: nodeID.PrefixedString(constants.NodeIDPrefix)}).target()
+	assert.NoError(err)
+	assert.Equal(nodeID, target.NodeID)
+	assert.Nil(target.IP)
+
+	target, err = (&BanTargetArgs{IP: "1.2.3.4"}).target()
+	assert.NoError(err)
+	assert.Equal(ids.ShortEmpty, target.NodeID)
+	assert.True(net.IPv4(1, 2, 3, 4).Equal(target.IP))
+
+	_, err = (&BanTargetArgs{IP: "1.2.3"}).target()
+	assert.ErrorIs(err, errInvalidIP)
+
+	_, err = (&BanTargetArgs{NodeID: "1.2.3.4"}).target()
+	assert.Error(err)
+}
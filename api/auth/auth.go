@@ -16,8 +16,7 @@ import (
 
 	"github.com/golang-jwt/jwt"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api/doc"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
@@ -223,12 +222,21 @@ func (a *auth) AuthenticateToken(tokenStr, url string) error {
 		return errTokenRevoked
 	}
 
-	for _, endpoint := range claims.Endpoints {
+	if allowsEndpoint(claims.Endpoints, url) {
+		return nil
+	}
+	return errTokenInsufficientPermission
+}
+
+// allowsEndpoint returns true if [url] ends with one of [endpoints], or if one
+// of [endpoints] is "*"
+func allowsEndpoint(endpoints []string, url string) bool {
+	for _, endpoint := range endpoints {
 		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
-			return nil
+			return true
 		}
 	}
-	return errTokenInsufficientPermission
+	return false
 }
 
 func (a *auth) ChangePassword(oldPW, newPW string) error {
@@ -256,7 +264,7 @@ func (a *auth) ChangePassword(oldPW, newPW string) error {
 }
 
 func (a *auth) CreateHandler() (http.Handler, error) {
-	server := rpc.NewServer()
+	server := doc.NewServer()
 	codec := cjson.NewCodec()
 	server.RegisterCodec(codec, "application/json")
 	server.RegisterCodec(codec, "application/json;charset=UTF-8")
@@ -0,0 +1,52 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	errNoClientCert                     = errors.New("client TLS certificate not provided")
+	errUnknownClientCert                = errors.New("the provided client TLS certificate isn't mapped to any endpoint")
+	errClientCertInsufficientPermission = errors.New("the provided client TLS certificate does not allow access to this endpoint")
+)
+
+// ClientCertWrapper only lets a request through if the TLS certificate of its
+// client allows access to the requested endpoint. The certificates themselves
+// are verified by the TLS server, which must require them.
+type ClientCertWrapper struct {
+	scopes map[string][]string
+}
+
+// NewClientCertWrapper returns a wrapper where [scopes] maps the common name
+// of client certificates to the endpoints they give access to, with the same
+// semantics as the endpoints of auth tokens.
+func NewClientCertWrapper(scopes map[string][]string) *ClientCertWrapper {
+	return &ClientCertWrapper{
+		scopes: scopes,
+	}
+}
+
+func (c *ClientCertWrapper) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			writeUnauthorizedResponse(w, errNoClientCert)
+			return
+		}
+
+		endpoints, ok := c.scopes[r.TLS.PeerCertificates[0].Subject.CommonName]
+		if !ok {
+			writeUnauthorizedResponse(w, errUnknownClientCert)
+			return
+		}
+		if !allowsEndpoint(endpoints, r.URL.Path) {
+			writeUnauthorizedResponse(w, errClientCertInsufficientPermission)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCertWrapper(t *testing.T) {
+	assert := assert.New(t)
+
+	wrappedHandler := NewClientCertWrapper(map[string][]string{
+		"ops":    {"*"},
+		"wallet": {"/ext/bc/X"},
+	}).WrapHandler(dummyHandler)
+
+	serve := func(commonName string, endpoint string) int {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650"+endpoint, strings.NewReader(""))
+		if commonName != "" {
+			req.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{
+					Subject: pkix.Name{CommonName: commonName},
+				}},
+			}
+		}
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(http.StatusOK, serve("ops", "/ext/admin"))
+	assert.Equal(http.StatusOK, serve("wallet", "/ext/bc/X"))
+	assert.Equal(http.StatusUnauthorized, serve("wallet", "/ext/admin"))
+	assert.Equal(http.StatusUnauthorized, serve("unknown", "/ext/bc/X"))
+	assert.Equal(http.StatusUnauthorized, serve("", "/ext/bc/X"))
+}
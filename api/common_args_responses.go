@@ -108,6 +108,45 @@ type FormattedTx struct {
 	Encoding formatting.Encoding `json:"encoding"`
 }
 
+// SimulateTxReply is the outcome of verifying a tx without issuing it
+type SimulateTxReply struct {
+	TxID ids.ID `json:"txID"`
+	// True if the tx would be accepted into the mempool if it were issued now
+	Valid bool `json:"valid"`
+	// Reason is why the tx is invalid. Empty if the tx is valid.
+	Reason string `json:"reason,omitempty"`
+}
+
+// DecodeTxReply is a signed tx decoded into JSON
+type DecodeTxReply struct {
+	TxID ids.ID `json:"txID"`
+	// The unsigned tx, including its inputs, outputs and memo, and its
+	// credentials
+	Tx interface{} `json:"tx"`
+}
+
+// GetImportStatusArgs are the arguments for GetImportStatus
+type GetImportStatusArgs struct {
+	// The chain the outputs were exported from
+	SourceChain string `json:"sourceChain"`
+	// The export tx on [SourceChain]
+	ExportTxID ids.ID `json:"exportTxID"`
+}
+
+// ImportedOutput is an exported output and the import tx that consumed it
+type ImportedOutput struct {
+	OutputIndex json.Uint32 `json:"outputIndex"`
+	ImportTxID  ids.ID      `json:"importTxID"`
+}
+
+// GetImportStatusReply reports which outputs of an export tx were imported
+// into this chain, and by which txs
+type GetImportStatusReply struct {
+	// True if at least one output of the export tx was imported
+	Imported bool             `json:"imported"`
+	Imports  []ImportedOutput `json:"imports"`
+}
+
 // Index is an address and an associated UTXO.
 // Marks a starting or stopping point when fetching UTXOs. Used for pagination.
 type Index struct {
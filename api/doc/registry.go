@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package doc
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+var _ http.Handler = &Registry{}
+
+// Registry collects the descriptions of the API's endpoints and serves them.
+//
+// A GET request returns the OpenRPC documents of all the endpoints, keyed by
+// endpoint. If the request has an endpoint query parameter, only the document
+// of that endpoint is returned.
+type Registry struct {
+	lock sync.RWMutex
+	// Maps endpoints to the handlers that describe them
+	describers map[string]Describer
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		describers: make(map[string]Describer),
+	}
+}
+
+// Add the description of [handler] as the description of [endpoint]. If
+// [handler] isn't a Describer, [endpoint] is no longer described.
+func (r *Registry) Add(endpoint string, handler http.Handler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if describer, ok := handler.(Describer); ok {
+		r.describers[endpoint] = describer
+	} else {
+		delete(r.describers, endpoint)
+	}
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var reply interface{}
+	if endpoint := req.URL.Query().Get("endpoint"); endpoint != "" {
+		describer, ok := r.describers[endpoint]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		reply = describer.Describe(endpoint)
+	} else {
+		docs := make(map[string]*Document, len(r.describers))
+		for endpoint, describer := range r.describers {
+			docs[endpoint] = describer.Describe(endpoint)
+		}
+		reply = docs
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(reply)
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package doc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	rawMessage    = reflect.TypeOf(json.RawMessage{})
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// Schema is a JSON schema
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// field is a property of an object, in the order it's marshalled in
+type field struct {
+	name     string
+	schema   *Schema
+	required bool
+}
+
+// schemaGenerator describes Go types as they're marshalled by encoding/json.
+// Named structs are described once, in [schemas], and referenced everywhere
+// they're used.
+type schemaGenerator struct {
+	// Maps the names of structs to their schemas
+	schemas map[string]*Schema
+	// Maps structs to their names
+	names map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schema returns the schema of values of type [t].
+//
+// Types that marshal themselves are described as strings, as that's how the
+// IDs, addresses and numbers in the API are encoded.
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	switch {
+	case t == rawMessage:
+		return &Schema{}
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() != reflect.Ptr && (marshalsItself(t) || marshalsItself(reflect.PtrTo(t))):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// Byte slices are base64 encoded
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && !marshalsItself(reflect.PtrTo(t.Elem())) {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: schemaRefPrefix + g.name(t)}
+	default:
+		// Interfaces may hold any value
+		return &Schema{}
+	}
+}
+
+// name returns the name that the schema of the struct [t] is stored under.
+// The schema is generated the first time [t] is named.
+func (g *schemaGenerator) name(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := fmt.Sprintf("%s.%s", path.Base(t.PkgPath()), t.Name())
+	for i := 2; g.schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s.%s%d", path.Base(t.PkgPath()), t.Name(), i)
+	}
+
+	// The name is reserved before the fields are described, so that recursive
+	// structs reference themselves.
+	g.names[t] = name
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.structSchema(t)
+	return name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	for _, f := range g.fields(t) {
+		s.Properties[f.name] = f.schema
+		if f.required {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s
+}
+
+// fields returns the properties that the struct [t] is marshalled with.
+// Fields of embedded structs are promoted, unless they're shadowed.
+func (g *schemaGenerator) fields(t reflect.Type) []field {
+	var (
+		fields   []field
+		seen     = make(map[string]bool)
+		embedded []reflect.Type
+	)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			name, opts = tag[:i], tag[i:]
+		}
+
+		fieldType := f.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if f.Anonymous && name == "" && fieldType.Kind() == reflect.Struct && !marshalsItself(reflect.PtrTo(fieldType)) {
+			embedded = append(embedded, fieldType)
+			continue
+		}
+		if f.PkgPath != "" {
+			// Unexported fields aren't marshalled
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		seen[name] = true
+		fields = append(fields, field{
+			name:     name,
+			schema:   g.schema(f.Type),
+			required: !strings.Contains(opts, ",omitempty"),
+		})
+	}
+
+	// Fields of embedded structs are promoted after the struct's own fields
+	// are known, so that the struct's fields shadow them
+	for _, embeddedType := range embedded {
+		for _, promoted := range g.fields(embeddedType) {
+			if !seen[promoted.name] {
+				seen[promoted.name] = true
+				fields = append(fields, promoted)
+			}
+		}
+	}
+	return fields
+}
+
+func marshalsItself(t reflect.Type) bool {
+	return t.Implements(jsonMarshaler) || t.Implements(textMarshaler)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package doc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+type embedded struct {
+	Shadowed string `json:"shadowed"`
+	Promoted int    `json:"promoted"`
+}
+
+type node struct {
+	Children []*node `json:"children"`
+}
+
+type testStruct struct {
+	embedded
+
+	ID       ids.ID            `json:"id"`
+	Amount   json.Uint64       `json:"amount"`
+	Bytes    []byte            `json:"bytes"`
+	Names    []string          `json:"names,omitempty"`
+	Balances map[string]uint64 `json:"balances"`
+	Any      interface{}       `json:"any"`
+	Shadowed bool              `json:"shadowed"`
+	Untagged bool
+	Tree     *node `json:"tree"`
+	Inline   struct {
+		Value float64 `json:"value"`
+	} `json:"inline"`
+
+	Ignored    string `json:"-"`
+	unexported string
+}
+
+func TestSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	g := newSchemaGenerator()
+	s := g.schema(reflect.TypeOf(&testStruct{}))
+	assert.Equal(&Schema{Ref: "#/components/schemas/doc.testStruct"}, s)
+
+	nodeRef := &Schema{Ref: "#/components/schemas/doc.node"}
+	assert.Equal(map[string]*Schema{
+		"doc.testStruct": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":       {Type: "string"},
+				"amount":   {Type: "string"},
+				"bytes":    {Type: "string", Format: "byte"},
+				"names":    {Type: "array", Items: &Schema{Type: "string"}},
+				"balances": {Type: "object", AdditionalProperties: &Schema{Type: "integer"}},
+				"any":      {},
+				"shadowed": {Type: "boolean"},
+				"Untagged": {Type: "boolean"},
+				"tree":     nodeRef,
+				"inline": {
+					Type:       "object",
+					Properties: map[string]*Schema{"value": {Type: "number"}},
+					Required:   []string{"value"},
+				},
+				"promoted": {Type: "integer"},
+			},
+			Required: []string{"id", "amount", "bytes", "balances", "any", "shadowed", "Untagged", "tree", "inline", "promoted"},
+		},
+		"doc.node": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"children": {Type: "array", Items: nodeRef},
+			},
+			Required: []string{"children"},
+		},
+	}, g.schemas)
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package doc
+
+import (
+	"net/http"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// openRPCVersion is the version of the OpenRPC specification that documents
+// are written in
+const openRPCVersion = "1.2.6"
+
+var (
+	httpRequestType = reflect.TypeOf((*http.Request)(nil))
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+
+	_ Describer = &Server{}
+	_ Describer = &handler{}
+)
+
+// Describer describes the JSON-RPC services that it serves
+type Describer interface {
+	// Describe returns an OpenRPC document titled [title] that describes the
+	// services
+	Describe(title string) *Document
+}
+
+// Document describes JSON-RPC services in the OpenRPC format
+type Document struct {
+	OpenRPC    string     `json:"openrpc"`
+	Info       Info       `json:"info"`
+	Methods    []Method   `json:"methods"`
+	Components Components `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Method struct {
+	Name string `json:"name"`
+	// by-name if the params are an object whose fields are [Params], or
+	// by-position if the params are a single value
+	ParamStructure string              `json:"paramStructure"`
+	Params         []ContentDescriptor `json:"params"`
+	Result         ContentDescriptor   `json:"result"`
+}
+
+type ContentDescriptor struct {
+	Name     string  `json:"name"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Server is a JSON-RPC server that describes the services registered with
+// it. The params and results of the services' methods are described as they're
+// marshalled to JSON.
+type Server struct {
+	*rpc.Server
+
+	services []service
+}
+
+type service struct {
+	name     string
+	receiver reflect.Type
+}
+
+// NewServer returns a JSON-RPC server with no services
+func NewServer() *Server {
+	return &Server{Server: rpc.NewServer()}
+}
+
+// RegisterService registers the methods of [receiver] as the service [name]
+func (s *Server) RegisterService(receiver interface{}, name string) error {
+	if err := s.Server.RegisterService(receiver, name); err != nil {
+		return err
+	}
+	s.services = append(s.services, service{
+		name:     name,
+		receiver: reflect.TypeOf(receiver),
+	})
+	return nil
+}
+
+func (s *Server) Describe(title string) *Document {
+	g := newSchemaGenerator()
+	doc := &Document{
+		OpenRPC: openRPCVersion,
+		Info: Info{
+			Title:   title,
+			Version: version.Current.String(),
+		},
+		Methods: []Method{},
+	}
+	for _, service := range s.services {
+		for i := 0; i < service.receiver.NumMethod(); i++ {
+			method := service.receiver.Method(i)
+			if !isRPCMethod(method) {
+				continue
+			}
+			doc.Methods = append(doc.Methods, describeMethod(g, service.name, method))
+		}
+	}
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+// isRPCMethod returns true if [method] is served the way gorilla/rpc serves
+// methods, which is if it's of the form:
+//
+//	func (*Service) Method(*http.Request, *Args, *Reply) error
+func isRPCMethod(method reflect.Method) bool {
+	t := method.Type
+	return method.PkgPath == "" &&
+		t.NumIn() == 4 &&
+		t.In(1) == httpRequestType &&
+		t.In(2).Kind() == reflect.Ptr &&
+		t.In(3).Kind() == reflect.Ptr &&
+		t.NumOut() == 1 &&
+		t.Out(0) == errorType
+}
+
+func describeMethod(g *schemaGenerator, serviceName string, method reflect.Method) Method {
+	args := method.Type.In(2).Elem()
+	reply := method.Type.In(3).Elem()
+
+	// Methods are called with their first letter in lowercase
+	firstRune, runeLen := utf8.DecodeRuneInString(method.Name)
+	m := Method{
+		Name:           serviceName + "." + string(unicode.ToLower(firstRune)) + method.Name[runeLen:],
+		ParamStructure: "by-name",
+		Params:         []ContentDescriptor{},
+		Result: ContentDescriptor{
+			Name:   "result",
+			Schema: g.schema(reply),
+		},
+	}
+	if args.Kind() != reflect.Struct || marshalsItself(reflect.PtrTo(args)) {
+		m.ParamStructure = "by-position"
+		m.Params = append(m.Params, ContentDescriptor{
+			Name:     "params",
+			Required: true,
+			Schema:   g.schema(args),
+		})
+		return m
+	}
+	// Params that aren't provided are left empty, rather than rejected, so
+	// none of them are required
+	for _, f := range g.fields(args) {
+		m.Params = append(m.Params, ContentDescriptor{
+			Name:   f.name,
+			Schema: f.schema,
+		})
+	}
+	return m
+}
+
+// NewHandler returns a handler that serves requests with [h] and is described
+// by [d]. It allows handlers that serve more than a JSON-RPC server to be
+// described.
+func NewHandler(h http.Handler, d Describer) http.Handler {
+	return &handler{
+		Handler:   h,
+		Describer: d,
+	}
+}
+
+type handler struct {
+	http.Handler
+	Describer
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package doc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/version"
+)
+
+type GetBalanceArgs struct {
+	Address string `json:"address"`
+	AssetID string `json:"assetID,omitempty"`
+}
+
+type GetBalanceReply struct {
+	Balance uint64 `json:"balance"`
+}
+
+type testService struct{}
+
+func (*testService) GetBalance(_ *http.Request, _ *GetBalanceArgs, _ *GetBalanceReply) error {
+	return nil
+}
+
+// Methods that gorilla/rpc doesn't serve aren't described
+func (*testService) Close() error { return nil }
+
+func TestServerDescribe(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer()
+	assert.NoError(s.RegisterService(&testService{}, "test"))
+
+	doc := s.Describe("/ext/test")
+	assert.Equal(openRPCVersion, doc.OpenRPC)
+	assert.Equal(Info{Title: "/ext/test", Version: version.Current.String()}, doc.Info)
+	assert.Equal([]Method{{
+		Name:           "test.getBalance",
+		ParamStructure: "by-name",
+		Params: []ContentDescriptor{
+			{Name: "address", Schema: &Schema{Type: "string"}},
+			{Name: "assetID", Schema: &Schema{Type: "string"}},
+		},
+		Result: ContentDescriptor{
+			Name:   "result",
+			Schema: &Schema{Ref: "#/components/schemas/doc.GetBalanceReply"},
+		},
+	}}, doc.Methods)
+	assert.Equal(map[string]*Schema{
+		"doc.GetBalanceReply": {
+			Type:       "object",
+			Properties: map[string]*Schema{"balance": {Type: "integer"}},
+			Required:   []string{"balance"},
+		},
+	}, doc.Components.Schemas)
+}
+
+func TestRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer()
+	assert.NoError(s.RegisterService(&testService{}, "test"))
+
+	r := NewRegistry()
+	r.Add("/ext/test", s)
+	r.Add("/ext/health", NewHandler(http.NotFoundHandler(), s))
+	// Handlers that can't be described aren't served
+	r.Add("/ext/metrics", http.NotFoundHandler())
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ext/doc", nil))
+	assert.Equal(http.StatusOK, recorder.Code)
+	docs := map[string]*Document{}
+	assert.NoError(json.Unmarshal(recorder.Body.Bytes(), &docs))
+	assert.Len(docs, 2)
+	assert.Contains(docs, "/ext/test")
+	assert.Contains(docs, "/ext/health")
+
+	recorder = httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ext/doc?endpoint=/ext/test", nil))
+	assert.Equal(http.StatusOK, recorder.Code)
+	doc := &Document{}
+	assert.NoError(json.Unmarshal(recorder.Body.Bytes(), doc))
+	assert.Equal("/ext/test", doc.Info.Title)
+	assert.Len(doc.Methods, 1)
+
+	recorder = httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ext/doc?endpoint=/ext/metrics", nil))
+	assert.Equal(http.StatusNotFound, recorder.Code)
+
+	// Replacing a described handler with one that can't be described removes
+	// its description
+	r.Add("/ext/test", http.NotFoundHandler())
+	recorder = httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ext/doc?endpoint=/ext/test", nil))
+	assert.Equal(http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/ext/doc", nil))
+	assert.Equal(http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evidence
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+// Interface compliance
+var _ Client = &client{}
+
+// Client interface for the Avalanche Evidence API Endpoint
+type Client interface {
+	GetEvidence(ctx context.Context, evidenceID ids.ID, options ...rpc.Option) (APIEvidence, error)
+	GetEvidenceByNodeID(ctx context.Context, nodeID string, options ...rpc.Option) ([]APIEvidence, error)
+}
+
+// Client implementation for the Avalanche Evidence API Endpoint
+type client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a new Evidence API Client
+func NewClient(uri string) Client {
+	return &client{
+		requester: rpc.NewEndpointRequester(uri, "/ext/evidence", "evidence"),
+	}
+}
+
+func (c *client) GetEvidence(ctx context.Context, evidenceID ids.ID, options ...rpc.Option) (APIEvidence, error) {
+	res := &GetEvidenceReply{}
+	err := c.requester.SendRequest(ctx, "getEvidence", &GetEvidenceArgs{
+		ID: evidenceID,
+	}, res, options...)
+	return res.Evidence, err
+}
+
+func (c *client) GetEvidenceByNodeID(ctx context.Context, nodeID string, options ...rpc.Option) ([]APIEvidence, error) {
+	res := &GetEvidenceByNodeIDReply{}
+	err := c.requester.SendRequest(ctx, "getEvidenceByNodeID", &GetEvidenceByNodeIDArgs{
+		NodeID: nodeID,
+	}, res, options...)
+	return res.Evidence, err
+}
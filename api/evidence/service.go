@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evidence
+
+import (
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/api/doc"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/evidence"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+// Evidence is the API service for querying recorded misbehavior
+type Evidence struct {
+	log   logging.Logger
+	store evidence.Store
+}
+
+// NewService returns a new evidence API service
+func NewService(log logging.Logger, store evidence.Store) (*common.HTTPHandler, error) {
+	newServer := doc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := newServer.RegisterService(&Evidence{
+		log:   log,
+		store: store,
+	}, "evidence"); err != nil {
+		return nil, err
+	}
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: newServer}, nil
+}
+
+// APIEvidence is the API representation of an evidence entry
+type APIEvidence struct {
+	ID         ids.ID              `json:"id"`
+	Type       string              `json:"type"`
+	ChainID    ids.ID              `json:"chainID"`
+	NodeID     string              `json:"nodeID"`
+	Timestamp  cjson.Uint64        `json:"timestamp"`
+	Containers []string            `json:"containers"`
+	Encoding   formatting.Encoding `json:"encoding"`
+}
+
+func newAPIEvidence(e *evidence.Evidence) (APIEvidence, error) {
+	containers := make([]string, len(e.Containers))
+	for i, container := range e.Containers {
+		str, err := formatting.EncodeWithChecksum(formatting.Hex, container)
+		if err != nil {
+			return APIEvidence{}, err
+		}
+		containers[i] = str
+	}
+	return APIEvidence{
+		ID:         e.ID(),
+		Type:       e.Type.String(),
+		ChainID:    e.ChainID,
+		NodeID:     e.NodeID.PrefixedString(constants.NodeIDPrefix),
+		Timestamp:  cjson.Uint64(e.Timestamp),
+		Containers: containers,
+		Encoding:   formatting.Hex,
+	}, nil
+}
+
+// GetEvidenceArgs are the arguments for GetEvidence
+type GetEvidenceArgs struct {
+	ID ids.ID `json:"id"`
+}
+
+// GetEvidenceReply is the response from GetEvidence
+type GetEvidenceReply struct {
+	Evidence APIEvidence `json:"evidence"`
+}
+
+// GetEvidence returns the evidence entry with the given ID
+func (service *Evidence) GetEvidence(_ *http.Request, args *GetEvidenceArgs, reply *GetEvidenceReply) error {
+	service.log.Debug("Evidence: GetEvidence called with %s", args.ID)
+
+	e, err := service.store.Get(args.ID)
+	if err != nil {
+		return err
+	}
+	reply.Evidence, err = newAPIEvidence(e)
+	return err
+}
+
+// GetEvidenceByNodeIDArgs are the arguments for GetEvidenceByNodeID
+type GetEvidenceByNodeIDArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// GetEvidenceByNodeIDReply is the response from GetEvidenceByNodeID
+type GetEvidenceByNodeIDReply struct {
+	Evidence []APIEvidence `json:"evidence"`
+}
+
+// GetEvidenceByNodeID returns all evidence recorded against the given node
+func (service *Evidence) GetEvidenceByNodeID(_ *http.Request, args *GetEvidenceByNodeIDArgs, reply *GetEvidenceByNodeIDReply) error {
+	service.log.Debug("Evidence: GetEvidenceByNodeID called with %s", args.NodeID)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return err
+	}
+	entries, err := service.store.GetByNodeID(nodeID)
+	if err != nil {
+		return err
+	}
+	reply.Evidence = make([]APIEvidence, len(entries))
+	for i, e := range entries {
+		reply.Evidence[i], err = newAPIEvidence(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
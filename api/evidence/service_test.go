@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evidence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/evidence"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestGetEvidenceByNodeID(t *testing.T) {
+	assert := assert.New(t)
+
+	store, err := evidence.NewStore(memdb.New())
+	assert.NoError(err)
+	service := &Evidence{
+		log:   logging.NoLog{},
+		store: store,
+	}
+
+	nodeID := ids.GenerateTestShortID()
+	e := &evidence.Evidence{
+		Type:       evidence.ConflictingBlocks,
+		ChainID:    ids.GenerateTestID(),
+		NodeID:     nodeID,
+		Containers: [][]byte{{1}, {2}},
+	}
+	assert.NoError(store.Record(e))
+
+	reply := GetEvidenceByNodeIDReply{}
+	assert.NoError(service.GetEvidenceByNodeID(nil, &GetEvidenceByNodeIDArgs{
+		NodeID: nodeID.PrefixedString(constants.NodeIDPrefix),
+	}, &reply))
+	assert.Len(reply.Evidence, 1)
+	assert.Equal(e.ID(), reply.Evidence[0].ID)
+	assert.Equal("conflictingBlocks", reply.Evidence[0].Type)
+	assert.Len(reply.Evidence[0].Containers, 2)
+
+	getReply := GetEvidenceReply{}
+	assert.NoError(service.GetEvidence(nil, &GetEvidenceArgs{ID: e.ID()}, &getReply))
+	assert.Equal(reply.Evidence[0], getReply.Evidence)
+
+	assert.Error(service.GetEvidence(nil, &GetEvidenceArgs{ID: ids.GenerateTestID()}, &getReply))
+}
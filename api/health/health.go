@@ -26,6 +26,10 @@ type Registerer interface {
 	RegisterReadinessCheck(name string, checker Checker) error
 	RegisterHealthCheck(name string, checker Checker) error
 	RegisterLivenessCheck(name string, checker Checker) error
+
+	// DeregisterHealthCheck removes the health check registered as [name], if
+	// there is one.
+	DeregisterHealthCheck(name string)
 }
 
 // Reporter returns the current health status.
@@ -68,6 +72,10 @@ func (h *health) RegisterHealthCheck(name string, checker Checker) error {
 	return h.health.RegisterCheck(name, checker)
 }
 
+func (h *health) DeregisterHealthCheck(name string) {
+	h.health.DeregisterCheck(name)
+}
+
 func (h *health) RegisterLivenessCheck(name string, checker Checker) error {
 	return h.liveness.RegisterCheck(name, checker)
 }
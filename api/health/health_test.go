@@ -78,6 +78,34 @@ func TestDuplicatedRegistations(t *testing.T) {
 	assert.ErrorIs(err, errDuplicateCheck)
 }
 
+func TestDeregisterHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	check := CheckerFunc(func() (interface{}, error) {
+		return "", nil
+	})
+
+	h, err := New(prometheus.NewRegistry())
+	assert.NoError(err)
+
+	err = h.RegisterHealthCheck("check", check)
+	assert.NoError(err)
+	_, health := h.Health()
+	assert.False(health)
+
+	h.DeregisterHealthCheck("check")
+	healthResult, health := h.Health()
+	assert.Empty(healthResult)
+	assert.True(health)
+
+	// Deregistering an unknown check does nothing
+	h.DeregisterHealthCheck("check")
+
+	// The check can be registered again once it's deregistered
+	err = h.RegisterHealthCheck("check", check)
+	assert.NoError(err)
+}
+
 func TestDefaultFailing(t *testing.T) {
 	assert := assert.New(t)
 
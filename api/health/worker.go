@@ -58,6 +58,24 @@ func (w *worker) RegisterCheck(name string, checker Checker) error {
 	return nil
 }
 
+func (w *worker) DeregisterCheck(name string) {
+	w.checksLock.Lock()
+	defer w.checksLock.Unlock()
+
+	w.resultsLock.Lock()
+	defer w.resultsLock.Unlock()
+
+	result, ok := w.results[name]
+	if !ok {
+		return
+	}
+	if result.Error != nil {
+		w.metrics.failingChecks.Dec()
+	}
+	delete(w.checks, name)
+	delete(w.results, name)
+}
+
 func (w *worker) RegisterMonotonicCheck(name string, checker Checker) error {
 	var result utils.AtomicInterface
 	return w.RegisterCheck(name, CheckerFunc(func() (interface{}, error) {
@@ -151,7 +169,11 @@ func (w *worker) runCheck(wg *sync.WaitGroup, name string, check Checker) {
 
 	w.resultsLock.Lock()
 	defer w.resultsLock.Unlock()
-	prevResult := w.results[name]
+	prevResult, ok := w.results[name]
+	if !ok {
+		// The check was deregistered while it was running
+		return
+	}
 	if err != nil {
 		errString := err.Error()
 		result.Error = &errString
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
 
@@ -24,8 +25,11 @@ type Client interface {
 	Peers(context.Context, ...rpc.Option) ([]Peer, error)
 	IsBootstrapped(context.Context, string, ...rpc.Option) (bool, error)
 	GetTxFee(context.Context, ...rpc.Option) (*GetTxFeeResponse, error)
+	EstimateFees(ctx context.Context, chain string, txType string, size uint64, options ...rpc.Option) ([]ChainFees, error)
 	Uptime(context.Context, ...rpc.Option) (*UptimeResponse, error)
 	GetVMs(context.Context, ...rpc.Option) (map[ids.ID][]string, error)
+	GetUpgrades(context.Context, ...rpc.Option) ([]APIUpgrade, error)
+	GetPeerPolicy(context.Context, ...rpc.Option) (*GetPeerPolicyReply, error)
 }
 
 // Client implementation for an Info API Client
@@ -98,6 +102,16 @@ func (c *client) GetTxFee(ctx context.Context, options ...rpc.Option) (*GetTxFee
 	return res, err
 }
 
+func (c *client) EstimateFees(ctx context.Context, chain string, txType string, size uint64, options ...rpc.Option) ([]ChainFees, error) {
+	res := &EstimateFeesReply{}
+	err := c.requester.SendRequest(ctx, "estimateFees", &EstimateFeesArgs{
+		Chain:  chain,
+		TxType: txType,
+		Size:   json.Uint64(size),
+	}, res, options...)
+	return res.Chains, err
+}
+
 func (c *client) Uptime(ctx context.Context, options ...rpc.Option) (*UptimeResponse, error) {
 	res := &UptimeResponse{}
 	err := c.requester.SendRequest(ctx, "uptime", struct{}{}, res, options...)
@@ -109,3 +123,15 @@ func (c *client) GetVMs(ctx context.Context, options ...rpc.Option) (map[ids.ID]
 	err := c.requester.SendRequest(ctx, "getVMs", struct{}{}, res, options...)
 	return res.VMs, err
 }
+
+func (c *client) GetUpgrades(ctx context.Context, options ...rpc.Option) ([]APIUpgrade, error) {
+	res := &GetUpgradesReply{}
+	err := c.requester.SendRequest(ctx, "getUpgrades", struct{}{}, res, options...)
+	return res.Upgrades, err
+}
+
+func (c *client) GetPeerPolicy(ctx context.Context, options ...rpc.Option) (*GetPeerPolicyReply, error) {
+	res := &GetPeerPolicyReply{}
+	err := c.requester.SendRequest(ctx, "getPeerPolicy", struct{}{}, res, options...)
+	return res, err
+}
@@ -7,9 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api/doc"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
@@ -52,6 +52,8 @@ type Parameters struct {
 	CreateSubnetTxFee     uint64
 	CreateBlockchainTxFee uint64
 	VMManager             vms.Manager
+	Upgrades              *version.UpgradeSchedule
+	PeerPolicy            *version.PeerPolicy
 }
 
 // NewService returns a new admin API service
@@ -66,7 +68,7 @@ func NewService(
 	validators validators.Set,
 	benchlist benchlist.Manager,
 ) (*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+	newServer := doc.NewServer()
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
@@ -307,6 +309,98 @@ func (service *Info) GetTxFee(_ *http.Request, args *struct{}, reply *GetTxFeeRe
 	return nil
 }
 
+// nativeChainAliases are the aliases of the chains whose fees are estimated
+// when no chain is given to EstimateFees
+var nativeChainAliases = []string{"P", "X", "C"}
+
+// EstimateFeesArgs are the arguments for calling EstimateFees
+type EstimateFeesArgs struct {
+	// Alias or ID of the chain to estimate the fees of. If empty, the fees of
+	// the native chains are estimated.
+	Chain string `json:"chain"`
+	// Type of the tx to estimate the fee of. If empty, the fees of all the tx
+	// types of the chains are estimated.
+	TxType string `json:"txType"`
+	// Size of the tx in bytes. Only used by chains whose fees change with
+	// their load.
+	Size json.Uint64 `json:"size"`
+}
+
+// ChainFees are the estimated fees of the txs issued to a chain
+type ChainFees struct {
+	ChainID ids.ID `json:"chainID"`
+	// ID of the asset the fees are paid in
+	FeeAssetID ids.ID `json:"feeAssetID"`
+	// Maps a tx type to its fee
+	Fees map[string]json.Uint64 `json:"fees"`
+	// Only set if the fees of the chain change with its load
+	BaseFee *json.Uint64 `json:"baseFee,omitempty"`
+}
+
+// EstimateFeesReply are the results from calling EstimateFees
+type EstimateFeesReply struct {
+	Chains []ChainFees `json:"chains"`
+}
+
+// EstimateFees returns the fees that txs issued now to [args.Chain], or to each
+// of the native chains, must pay. When the native chains are estimated, the
+// chains that aren't running, or whose VM doesn't estimate fees, are skipped.
+func (service *Info) EstimateFees(_ *http.Request, args *EstimateFeesArgs, reply *EstimateFeesReply) error {
+	service.log.Debug("Info: EstimateFees called with chain: %s", args.Chain)
+
+	reply.Chains = []ChainFees{}
+	if args.Chain != "" {
+		chainID, err := service.chainManager.Lookup(args.Chain)
+		if err != nil {
+			return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+		}
+		fees, err := service.estimateChainFees(chainID, args.TxType, uint64(args.Size))
+		if err != nil {
+			return err
+		}
+		reply.Chains = append(reply.Chains, fees)
+		return nil
+	}
+
+	for _, alias := range nativeChainAliases {
+		chainID, err := service.chainManager.Lookup(alias)
+		if err != nil {
+			continue
+		}
+		fees, err := service.estimateChainFees(chainID, args.TxType, uint64(args.Size))
+		if errors.Is(err, common.ErrUnknownTxType) {
+			// Tx types are specific to a chain
+			continue
+		}
+		if err != nil {
+			service.log.Debug("couldn't estimate the fees of chain %s: %s", alias, err)
+			continue
+		}
+		reply.Chains = append(reply.Chains, fees)
+	}
+	return nil
+}
+
+func (service *Info) estimateChainFees(chainID ids.ID, txType string, size uint64) (ChainFees, error) {
+	estimate, err := service.chainManager.EstimateFees(chainID, txType, size)
+	if err != nil {
+		return ChainFees{}, fmt.Errorf("couldn't estimate the fees of chain %s: %w", chainID, err)
+	}
+	fees := ChainFees{
+		ChainID:    chainID,
+		FeeAssetID: estimate.FeeAssetID,
+		Fees:       make(map[string]json.Uint64, len(estimate.Fees)),
+	}
+	for txType, fee := range estimate.Fees {
+		fees.Fees[txType] = json.Uint64(fee)
+	}
+	if estimate.BaseFee != nil {
+		baseFee := json.Uint64(*estimate.BaseFee)
+		fees.BaseFee = &baseFee
+	}
+	return fees, nil
+}
+
 // GetVMsReply contains the response metadata for GetVMs
 type GetVMsReply struct {
 	VMs map[ids.ID][]string `json:"vms"`
@@ -325,3 +419,63 @@ func (service *Info) GetVMs(_ *http.Request, _ *struct{}, reply *GetVMsReply) er
 	reply.VMs, err = ids.GetRelevantAliases(service.VMManager, vmIDs)
 	return err
 }
+
+// APIUpgrade describes a network upgrade and whether it has activated
+type APIUpgrade struct {
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	ActivationTime time.Time `json:"activationTime"`
+	Activated      bool      `json:"activated"`
+}
+
+// GetUpgradesReply contains the response metadata for GetUpgrades
+type GetUpgradesReply struct {
+	Upgrades []APIUpgrade `json:"upgrades"`
+}
+
+// GetUpgrades returns the network upgrades known to this node, in the order
+// they were introduced, and whether they have activated
+func (service *Info) GetUpgrades(_ *http.Request, _ *struct{}, reply *GetUpgradesReply) error {
+	service.log.Debug("Info: GetUpgrades called")
+
+	now := time.Now()
+	upgrades := service.Upgrades.Upgrades()
+	reply.Upgrades = make([]APIUpgrade, len(upgrades))
+	for i, upgrade := range upgrades {
+		reply.Upgrades[i] = APIUpgrade{
+			Name:           upgrade.Name,
+			Description:    upgrade.Description,
+			ActivationTime: upgrade.Time,
+			Activated:      service.Upgrades.IsActivated(upgrade.Name, now),
+		}
+	}
+	return nil
+}
+
+// GetPeerPolicyReply contains the response metadata for GetPeerPolicy
+type GetPeerPolicyReply struct {
+	// Empty if no additional minimum version is enforced
+	MinVersion string `json:"minVersion,omitempty"`
+	// Empty if no maximum version is enforced
+	MaxVersion string `json:"maxVersion,omitempty"`
+	// Maps a feature to the first version that supports it
+	FeatureVersions map[version.Feature]string `json:"featureVersions"`
+}
+
+// GetPeerPolicy returns the peer versions this node accepts and the versions
+// from which peers are considered to support each feature
+func (service *Info) GetPeerPolicy(_ *http.Request, _ *struct{}, reply *GetPeerPolicyReply) error {
+	service.log.Debug("Info: GetPeerPolicy called")
+
+	if service.PeerPolicy.MinVersion != nil {
+		reply.MinVersion = service.PeerPolicy.MinVersion.String()
+	}
+	if service.PeerPolicy.MaxVersion != nil {
+		reply.MaxVersion = service.PeerPolicy.MaxVersion.String()
+	}
+	reply.FeatureVersions = make(map[version.Feature]string, len(service.PeerPolicy.FeatureVersions))
+	for feature, featureVersion := range service.PeerPolicy.FeatureVersions {
+		reply.FeatureVersions[feature] = featureVersion.String()
+	}
+	return nil
+}
@@ -6,13 +6,18 @@ package info
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms"
 )
 
@@ -107,3 +112,93 @@ func TestGetVMsGetAliasesFails(t *testing.T) {
 
 	assert.Equal(t, err, errOops)
 }
+
+func TestGetUpgrades(t *testing.T) {
+	assert := assert.New(t)
+
+	resources := initGetVMsTest(t)
+	defer resources.ctrl.Finish()
+
+	past := version.Upgrade{Name: "past", Time: time.Now().Add(-time.Hour)}
+	future := version.Upgrade{Name: "future", Time: time.Now().Add(time.Hour)}
+	resources.info.Upgrades = version.NewUpgradeSchedule(past, future)
+	resources.mockLog.EXPECT().Debug(gomock.Any()).Times(1)
+
+	reply := GetUpgradesReply{}
+	assert.NoError(resources.info.GetUpgrades(nil, nil, &reply))
+	assert.Len(reply.Upgrades, 2)
+	assert.Equal("past", reply.Upgrades[0].Name)
+	assert.True(reply.Upgrades[0].Activated)
+	assert.Equal("future", reply.Upgrades[1].Name)
+	assert.False(reply.Upgrades[1].Activated)
+}
+
+type feeChainManager struct {
+	chains.MockManager
+	aliases   map[string]ids.ID
+	estimates map[ids.ID]common.FeeEstimate
+}
+
+func (m *feeChainManager) Lookup(alias string) (ids.ID, error) {
+	chainID, ok := m.aliases[alias]
+	if !ok {
+		return ids.ID{}, errOops
+	}
+	return chainID, nil
+}
+
+func (m *feeChainManager) EstimateFees(chainID ids.ID, txType string, _ uint64) (common.FeeEstimate, error) {
+	estimate, ok := m.estimates[chainID]
+	if !ok {
+		return common.FeeEstimate{}, errOops
+	}
+	if txType == "" {
+		return estimate, nil
+	}
+	fee, ok := estimate.Fees[txType]
+	if !ok {
+		return common.FeeEstimate{}, common.ErrUnknownTxType
+	}
+	estimate.Fees = map[string]uint64{txType: fee}
+	return estimate, nil
+}
+
+func TestEstimateFees(t *testing.T) {
+	assert := assert.New(t)
+
+	pChainID := ids.GenerateTestID()
+	xChainID := ids.GenerateTestID()
+	cChainID := ids.GenerateTestID()
+	baseFee := uint64(25)
+	service := Info{
+		log: logging.NoLog{},
+		chainManager: &feeChainManager{
+			aliases: map[string]ids.ID{"P": pChainID, "X": xChainID, "C": cChainID},
+			estimates: map[ids.ID]common.FeeEstimate{
+				pChainID: {Fees: map[string]uint64{"createSubnet": 100, "import": 1}},
+				xChainID: {Fees: map[string]uint64{"createAsset": 10, "import": 1}},
+				cChainID: {Fees: map[string]uint64{"import": 2}, BaseFee: &baseFee},
+			},
+		},
+	}
+
+	reply := EstimateFeesReply{}
+	assert.NoError(service.EstimateFees(nil, &EstimateFeesArgs{}, &reply))
+	assert.Len(reply.Chains, 3)
+	assert.Equal(pChainID, reply.Chains[0].ChainID)
+	assert.Equal(json.Uint64(100), reply.Chains[0].Fees["createSubnet"])
+	assert.Nil(reply.Chains[0].BaseFee)
+	assert.Equal(cChainID, reply.Chains[2].ChainID)
+	assert.Equal(json.Uint64(baseFee), *reply.Chains[2].BaseFee)
+
+	// Chains that don't issue the tx type are skipped
+	reply = EstimateFeesReply{}
+	assert.NoError(service.EstimateFees(nil, &EstimateFeesArgs{TxType: "createAsset"}, &reply))
+	assert.Len(reply.Chains, 1)
+	assert.Equal(xChainID, reply.Chains[0].ChainID)
+	assert.Equal(map[string]json.Uint64{"createAsset": 10}, reply.Chains[0].Fees)
+
+	// Unless the chain is given explicitly
+	err := service.EstimateFees(nil, &EstimateFeesArgs{Chain: "P", TxType: "createAsset"}, &EstimateFeesReply{})
+	assert.ErrorIs(err, common.ErrUnknownTxType)
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/encdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+)
+
+const (
+	// maxAddressLen is the maximum allowed length of an address in an address
+	// book
+	maxAddressLen = 1024
+	// maxLabelLen is the maximum allowed length of the label of an address
+	maxLabelLen = 1024
+)
+
+var (
+	errEmptyAddress       = errors.New("empty address")
+	errEmptyLabel         = errors.New("empty label")
+	errAddressMaxLength   = fmt.Errorf("address exceeds maximum length of %d chars", maxAddressLen)
+	errLabelMaxLength     = fmt.Errorf("label exceeds maximum length of %d chars", maxLabelLen)
+	errUnknownBookAddress = errors.New("address isn't in the address book")
+
+	// The address book of a user is stored next to the databases of its
+	// blockchains, so that it is exported, imported and deleted with the user.
+	addressBookPrefix = []byte("addressBook")
+)
+
+// AddressBookEntry is a labelled address in the address book of a user
+type AddressBookEntry struct {
+	Address string `json:"address"`
+	Label   string `json:"label"`
+}
+
+func (ks *keystore) SetAddressLabel(username, pw, address, label string) error {
+	switch {
+	case address == "":
+		return errEmptyAddress
+	case len(address) > maxAddressLen:
+		return errAddressMaxLength
+	case label == "":
+		return errEmptyLabel
+	case len(label) > maxLabelLen:
+		return errLabelMaxLength
+	}
+
+	db, err := ks.getAddressBook(username, pw)
+	if err != nil {
+		return err
+	}
+	return db.Put([]byte(address), []byte(label))
+}
+
+func (ks *keystore) GetAddressLabel(username, pw, address string) (string, error) {
+	db, err := ks.getAddressBook(username, pw)
+	if err != nil {
+		return "", err
+	}
+	label, err := db.Get([]byte(address))
+	if err == database.ErrNotFound {
+		return "", fmt.Errorf("%w: %q", errUnknownBookAddress, address)
+	}
+	return string(label), err
+}
+
+func (ks *keystore) GetAddressBook(username, pw string) ([]AddressBookEntry, error) {
+	db, err := ks.getAddressBook(username, pw)
+	if err != nil {
+		return nil, err
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	// The iterator returns the entries sorted by address
+	entries := []AddressBookEntry{}
+	for it.Next() {
+		entries = append(entries, AddressBookEntry{
+			Address: string(it.Key()),
+			Label:   string(it.Value()),
+		})
+	}
+	return entries, it.Error()
+}
+
+func (ks *keystore) DeleteAddressLabel(username, pw, address string) error {
+	db, err := ks.getAddressBook(username, pw)
+	if err != nil {
+		return err
+	}
+
+	key := []byte(address)
+	has, err := db.Has(key)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return fmt.Errorf("%w: %q", errUnknownBookAddress, address)
+	}
+	return db.Delete(key)
+}
+
+// getAddressBook returns the database that holds the address book of
+// [username], with its labels encrypted by [pw].
+func (ks *keystore) getAddressBook(username, pw string) (*encdb.Database, error) {
+	if username == "" {
+		return nil, errEmptyUsername
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	passwordHash, err := ks.getPassword(username)
+	if err != nil {
+		return nil, err
+	}
+	if passwordHash == nil || !passwordHash.Check(pw) {
+		return nil, fmt.Errorf("incorrect password for user %q", username)
+	}
+
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	return encdb.New([]byte(pw), prefixdb.NewNested(addressBookPrefix, userDB))
+}
@@ -25,6 +25,14 @@ type Client interface {
 	ImportUser(ctx context.Context, importTo api.UserPass, exportedUser []byte, options ...rpc.Option) (bool, error)
 	// Delete the given user
 	DeleteUser(context.Context, api.UserPass, ...rpc.Option) (bool, error)
+	// Label [address] in the address book of the given user
+	SetAddressLabel(ctx context.Context, user api.UserPass, address string, label string, options ...rpc.Option) (bool, error)
+	// Returns the label of [address] in the address book of the given user
+	GetAddressLabel(ctx context.Context, user api.UserPass, address string, options ...rpc.Option) (string, error)
+	// Returns the labelled addresses of the given user
+	GetAddressBook(context.Context, api.UserPass, ...rpc.Option) ([]AddressBookEntry, error)
+	// Remove [address] from the address book of the given user
+	DeleteAddressLabel(ctx context.Context, user api.UserPass, address string, options ...rpc.Option) (bool, error)
 }
 
 // Client implementation for Avalanche Keystore API Endpoint
@@ -81,3 +89,37 @@ func (c *client) DeleteUser(ctx context.Context, user api.UserPass, options ...r
 	err := c.requester.SendRequest(ctx, "deleteUser", &user, res, options...)
 	return res.Success, err
 }
+
+func (c *client) SetAddressLabel(ctx context.Context, user api.UserPass, address string, label string, options ...rpc.Option) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "setAddressLabel", &SetAddressLabelArgs{
+		UserPass: user,
+		Address:  address,
+		Label:    label,
+	}, res, options...)
+	return res.Success, err
+}
+
+func (c *client) GetAddressLabel(ctx context.Context, user api.UserPass, address string, options ...rpc.Option) (string, error) {
+	res := &GetAddressLabelReply{}
+	err := c.requester.SendRequest(ctx, "getAddressLabel", &AddressLabelArgs{
+		UserPass: user,
+		Address:  address,
+	}, res, options...)
+	return res.Label, err
+}
+
+func (c *client) GetAddressBook(ctx context.Context, user api.UserPass, options ...rpc.Option) ([]AddressBookEntry, error) {
+	res := &GetAddressBookReply{}
+	err := c.requester.SendRequest(ctx, "getAddressBook", &user, res, options...)
+	return res.Entries, err
+}
+
+func (c *client) DeleteAddressLabel(ctx context.Context, user api.UserPass, address string, options ...rpc.Option) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "deleteAddressLabel", &AddressLabelArgs{
+		UserPass: user,
+		Address:  address,
+	}, res, options...)
+	return res.Success, err
+}
@@ -9,8 +9,7 @@ import (
 	"net/http"
 	"sync"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api/doc"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/encdb"
@@ -74,6 +73,22 @@ type Keystore interface {
 	// with encrypted database values.
 	ExportUser(username, pw string) ([]byte, error)
 
+	// SetAddressLabel labels [address] in the address book of [username],
+	// replacing its previous label if there was one.
+	SetAddressLabel(username, pw, address, label string) error
+
+	// GetAddressLabel returns the label of [address] in the address book of
+	// [username].
+	GetAddressLabel(username, pw, address string) (string, error)
+
+	// GetAddressBook returns all the labelled addresses of [username], sorted
+	// by address.
+	GetAddressBook(username, pw string) ([]AddressBookEntry, error)
+
+	// DeleteAddressLabel removes [address] from the address book of
+	// [username].
+	DeleteAddressLabel(username, pw, address string) error
+
 	// Get the password that is used by [username]. If [username] doesn't exist,
 	// no error is returned and a nil password hash is returned.
 	getPassword(username string) (*password.Hash, error)
@@ -106,8 +121,8 @@ type keystore struct {
 	//    UserDB        BlockchainDB
 	//                 /      |     \
 	//               Usr     Usr    Usr
-	//             /  |  \
-	//          BID  BID  BID
+	//             /  |  \     \
+	//          BID  BID  BID  AddressBook
 }
 
 func New(log logging.Logger, dbManager manager.Manager) Keystore {
@@ -121,7 +136,7 @@ func New(log logging.Logger, dbManager manager.Manager) Keystore {
 }
 
 func (ks *keystore) CreateHandler() (http.Handler, error) {
-	newServer := rpc.NewServer()
+	newServer := doc.NewServer()
 	codec := jsoncodec.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
@@ -98,6 +98,60 @@ func (s *service) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Expor
 	return nil
 }
 
+type SetAddressLabelArgs struct {
+	// The username and password of the owner of the address book
+	api.UserPass
+	// The address being labelled. Addresses of any chain may be labelled.
+	Address string `json:"address"`
+	// The label of [Address]
+	Label string `json:"label"`
+}
+
+func (s *service) SetAddressLabel(_ *http.Request, args *SetAddressLabelArgs, reply *api.SuccessResponse) error {
+	s.ks.log.Debug("Keystore: SetAddressLabel called for %s", args.Username)
+
+	reply.Success = true
+	return s.ks.SetAddressLabel(args.Username, args.Password, args.Address, args.Label)
+}
+
+type AddressLabelArgs struct {
+	// The username and password of the owner of the address book
+	api.UserPass
+	// The labelled address
+	Address string `json:"address"`
+}
+
+type GetAddressLabelReply struct {
+	Label string `json:"label"`
+}
+
+func (s *service) GetAddressLabel(_ *http.Request, args *AddressLabelArgs, reply *GetAddressLabelReply) error {
+	s.ks.log.Debug("Keystore: GetAddressLabel called for %s", args.Username)
+
+	var err error
+	reply.Label, err = s.ks.GetAddressLabel(args.Username, args.Password, args.Address)
+	return err
+}
+
+type GetAddressBookReply struct {
+	Entries []AddressBookEntry `json:"entries"`
+}
+
+func (s *service) GetAddressBook(_ *http.Request, args *api.UserPass, reply *GetAddressBookReply) error {
+	s.ks.log.Debug("Keystore: GetAddressBook called for %s", args.Username)
+
+	var err error
+	reply.Entries, err = s.ks.GetAddressBook(args.Username, args.Password)
+	return err
+}
+
+func (s *service) DeleteAddressLabel(_ *http.Request, args *AddressLabelArgs, reply *api.SuccessResponse) error {
+	s.ks.log.Debug("Keystore: DeleteAddressLabel called for %s", args.Username)
+
+	reply.Success = true
+	return s.ks.DeleteAddressLabel(args.Username, args.Password, args.Address)
+}
+
 // CreateTestKeystore returns a new keystore that can be utilized for testing
 func CreateTestKeystore() (Keystore, error) {
 	dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
@@ -10,6 +10,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
@@ -427,3 +429,99 @@ func TestServiceDeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAddressBook(t *testing.T) {
+	assert := assert.New(t)
+
+	ks, err := CreateTestKeystore()
+	assert.NoError(err)
+	s := service{ks: ks.(*keystore)}
+
+	user := api.UserPass{
+		Username: "bob",
+		Password: strongPassword,
+	}
+	assert.NoError(s.CreateUser(nil, &user, &api.SuccessResponse{}))
+
+	setLabel := func(address, label string) error {
+		return s.SetAddressLabel(nil, &SetAddressLabelArgs{
+			UserPass: user,
+			Address:  address,
+			Label:    label,
+		}, &api.SuccessResponse{})
+	}
+	assert.NoError(setLabel("X-local1b", "exchange"))
+	assert.NoError(setLabel("P-local1a", "staking"))
+	assert.NoError(setLabel("X-local1b", "cold storage")) // relabel
+	assert.ErrorIs(setLabel("", "nothing"), errEmptyAddress)
+	assert.ErrorIs(setLabel("C-0x01", ""), errEmptyLabel)
+
+	// The labels are only available to the owner of the address book
+	assert.Error(s.SetAddressLabel(nil, &SetAddressLabelArgs{
+		UserPass: api.UserPass{Username: "bob"},
+		Address:  "C-0x01",
+		Label:    "contract",
+	}, &api.SuccessResponse{}))
+
+	labelReply := GetAddressLabelReply{}
+	assert.NoError(s.GetAddressLabel(nil, &AddressLabelArgs{
+		UserPass: user,
+		Address:  "X-local1b",
+	}, &labelReply))
+	assert.Equal("cold storage", labelReply.Label)
+
+	expectedEntries := []AddressBookEntry{
+		{Address: "P-local1a", Label: "staking"},
+		{Address: "X-local1b", Label: "cold storage"},
+	}
+	bookReply := GetAddressBookReply{}
+	assert.NoError(s.GetAddressBook(nil, &user, &bookReply))
+	assert.Equal(expectedEntries, bookReply.Entries)
+
+	// The address book doesn't leak into the blockchain databases of the user
+	db, err := ks.GetDatabase(ids.Empty, user.Username, user.Password)
+	assert.NoError(err)
+	it := db.NewIterator()
+	assert.False(it.Next())
+	it.Release()
+
+	// The address book is exported with the user
+	exportReply := ExportUserReply{}
+	assert.NoError(s.ExportUser(nil, &ExportUserArgs{
+		UserPass: user,
+		Encoding: formatting.Hex,
+	}, &exportReply))
+
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+	newS := service{ks: newKS.(*keystore)}
+	assert.NoError(newS.ImportUser(nil, &ImportUserArgs{
+		UserPass: user,
+		User:     exportReply.User,
+		Encoding: formatting.Hex,
+	}, &api.SuccessResponse{}))
+
+	bookReply = GetAddressBookReply{}
+	assert.NoError(newS.GetAddressBook(nil, &user, &bookReply))
+	assert.Equal(expectedEntries, bookReply.Entries)
+
+	// Deleted labels are removed from the address book
+	deleteArgs := AddressLabelArgs{
+		UserPass: user,
+		Address:  "P-local1a",
+	}
+	assert.NoError(s.DeleteAddressLabel(nil, &deleteArgs, &api.SuccessResponse{}))
+	assert.ErrorIs(s.DeleteAddressLabel(nil, &deleteArgs, &api.SuccessResponse{}), errUnknownBookAddress)
+	assert.ErrorIs(s.GetAddressLabel(nil, &deleteArgs, &GetAddressLabelReply{}), errUnknownBookAddress)
+
+	bookReply = GetAddressBookReply{}
+	assert.NoError(s.GetAddressBook(nil, &user, &bookReply))
+	assert.Equal(expectedEntries[1:], bookReply.Entries)
+
+	// The address book is deleted with the user
+	assert.NoError(s.DeleteUser(nil, &user, &api.SuccessResponse{}))
+	assert.NoError(s.CreateUser(nil, &user, &api.SuccessResponse{}))
+	bookReply = GetAddressBookReply{}
+	assert.NoError(s.GetAddressBook(nil, &user, &bookReply))
+	assert.Empty(bookReply.Entries)
+}
@@ -28,6 +28,10 @@ type MultiGatherer interface {
 	// Register adds the outputs of [gatherer] to the results of future calls to
 	// Gather with the provided [namespace] added to the metrics.
 	Register(namespace string, gatherer prometheus.Gatherer) error
+
+	// Deregister removes the gatherer registered with [namespace]. Returns
+	// true if there was a gatherer registered with [namespace].
+	Deregister(namespace string) bool
 }
 
 type multiGatherer struct {
@@ -84,6 +88,15 @@ func (g *multiGatherer) Register(namespace string, gatherer prometheus.Gatherer)
 	return nil
 }
 
+func (g *multiGatherer) Deregister(namespace string) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	_, exists := g.gatherers[namespace]
+	delete(g.gatherers, namespace)
+	return exists
+}
+
 type sortMetricsData []*dto.MetricFamily
 
 func (m sortMetricsData) Less(i, j int) bool { return *m[i].Name < *m[j].Name }
@@ -38,6 +38,23 @@ func TestMultiGathererDuplicatedPrefix(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestMultiGathererDeregister(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewMultiGatherer()
+	og := NewOptionalGatherer()
+
+	assert.False(g.Deregister("lol"))
+
+	err := g.Register("lol", og)
+	assert.NoError(err)
+	assert.True(g.Deregister("lol"))
+	assert.False(g.Deregister("lol"))
+
+	err = g.Register("lol", og)
+	assert.NoError(err)
+}
+
 func TestMultiGathererAddedError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+const (
+	// PushGatewayProtocol pushes the metrics to a Prometheus Pushgateway,
+	// replacing the metrics previously pushed with the same job and labels
+	PushGatewayProtocol = "pushgateway"
+	// RemoteWriteProtocol sends the metrics to an endpoint that implements
+	// the Prometheus remote-write protocol
+	RemoteWriteProtocol = "remote-write"
+)
+
+var (
+	errUnknownPushProtocol = errors.New("unknown push protocol")
+
+	_ Pusher = &pusher{}
+)
+
+// PushConfig describes where and how often the metrics of a node are pushed,
+// for nodes that can't be scraped
+type PushConfig struct {
+	// URL is the address of the Pushgateway or of the remote-write endpoint.
+	// If empty, metrics aren't pushed.
+	URL string `json:"url"`
+
+	// Protocol is either [PushGatewayProtocol] or [RemoteWriteProtocol]
+	Protocol string `json:"protocol"`
+
+	// Interval is the time between pushes
+	Interval time.Duration `json:"interval"`
+
+	// Job is the value of the job label of the pushed metrics
+	Job string `json:"job"`
+
+	// Labels are added to all the pushed metrics
+	Labels map[string]string `json:"labels"`
+}
+
+// Pusher periodically pushes the metrics of a gatherer
+type Pusher interface {
+	// Dispatch pushes the metrics every interval until Stop is called
+	Dispatch()
+
+	// Stop stops pushing metrics
+	Stop()
+}
+
+type pusher struct {
+	log      logging.Logger
+	config   PushConfig
+	gatherer prometheus.Gatherer
+	client   *http.Client
+	clock    mockable.Clock
+
+	// pushGateway is nil if the metrics are sent with the remote-write
+	// protocol
+	pushGateway *push.Pusher
+
+	closer sync.Once
+	closed chan struct{}
+}
+
+// NewPusher returns a Pusher that pushes the metrics of [gatherer] as
+// described by [config]
+func NewPusher(log logging.Logger, config PushConfig, gatherer prometheus.Gatherer) (Pusher, error) {
+	p := &pusher{
+		log:      log,
+		config:   config,
+		gatherer: gatherer,
+		client:   &http.Client{Timeout: config.Interval},
+		closed:   make(chan struct{}),
+	}
+	switch config.Protocol {
+	case PushGatewayProtocol:
+		p.pushGateway = push.New(config.URL, config.Job).
+			Gatherer(gatherer).
+			Client(p.client)
+		for name, value := range config.Labels {
+			p.pushGateway = p.pushGateway.Grouping(name, value)
+		}
+	case RemoteWriteProtocol:
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownPushProtocol, config.Protocol)
+	}
+	return p, nil
+}
+
+func (p *pusher) Dispatch() {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.closed:
+			return
+		}
+
+		if err := p.push(); err != nil {
+			p.log.Warn("failed to push metrics to %s: %s", p.config.URL, err)
+		}
+	}
+}
+
+func (p *pusher) Stop() {
+	p.closer.Do(func() {
+		close(p.closed)
+	})
+}
+
+func (p *pusher) push() error {
+	if p.pushGateway != nil {
+		return p.pushGateway.Push()
+	}
+	return p.remoteWrite()
+}
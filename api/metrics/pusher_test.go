@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTestRegistry(t *testing.T) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "requests",
+		Help: "Number of requests",
+	})
+	counter.Add(3)
+	assert.NoError(t, registry.Register(counter))
+	return registry
+}
+
+func TestPusherUnknownProtocol(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewPusher(logging.NoLog{}, PushConfig{
+		URL:      "http://localhost",
+		Protocol: "carrier-pigeon",
+		Interval: time.Second,
+		Job:      "avalanchego",
+	}, newTestRegistry(t))
+	assert.ErrorIs(err, errUnknownPushProtocol)
+}
+
+func TestPusherPushGateway(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		method, path string
+		body         []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	p, err := NewPusher(logging.NoLog{}, PushConfig{
+		URL:      server.URL,
+		Protocol: PushGatewayProtocol,
+		Interval: time.Second,
+		Job:      "avalanchego",
+		Labels:   map[string]string{"instance": "validator-1"},
+	}, newTestRegistry(t))
+	assert.NoError(err)
+	assert.NoError(p.(*pusher).push())
+
+	// The metrics under the job and labels are replaced
+	assert.Equal(http.MethodPut, method)
+	assert.Equal("/metrics/job/avalanchego/instance/validator-1", path)
+	assert.NotEmpty(body)
+}
+
+func TestPusherRemoteWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		header http.Header
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p, err := NewPusher(logging.NoLog{}, PushConfig{
+		URL:      server.URL,
+		Protocol: RemoteWriteProtocol,
+		Interval: time.Second,
+		Job:      "avalanchego",
+	}, newTestRegistry(t))
+	assert.NoError(err)
+	p.(*pusher).clock.Set(time.Unix(10, 0))
+	assert.NoError(p.(*pusher).push())
+
+	assert.Equal("snappy", header.Get("Content-Encoding"))
+	assert.Equal("application/x-protobuf", header.Get("Content-Type"))
+
+	request, err := snappy.Decode(nil, body)
+	assert.NoError(err)
+	assert.Equal(encodeWriteRequest([]timeSeries{{
+		labels: []label{
+			{name: nameLabel, value: "requests"},
+			{name: jobLabel, value: "avalanchego"},
+		},
+		value:     3,
+		timestamp: 10000,
+	}}), request)
+}
+
+func TestPusherRemoteWriteFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	p, err := NewPusher(logging.NoLog{}, PushConfig{
+		URL:      server.URL,
+		Protocol: RemoteWriteProtocol,
+		Interval: time.Second,
+		Job:      "avalanchego",
+	}, newTestRegistry(t))
+	assert.NoError(err)
+	assert.Error(p.(*pusher).push())
+}
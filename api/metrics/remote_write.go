@@ -0,0 +1,210 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	nameLabel     = "__name__"
+	jobLabel      = "job"
+	bucketLabel   = "le"
+	quantileLabel = "quantile"
+)
+
+type label struct {
+	name, value string
+}
+
+// timeSeries is a single sample of a metric, as sent in a remote-write request
+type timeSeries struct {
+	// labels are sorted by name
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// remoteWrite sends the current metrics to the remote-write endpoint
+func (p *pusher) remoteWrite() error {
+	families, err := p.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	labels := make(map[string]string, len(p.config.Labels)+1)
+	for name, value := range p.config.Labels {
+		labels[name] = value
+	}
+	labels[jobLabel] = p.config.Job
+	series := toTimeSeries(families, labels, p.clock.Time().UnixNano()/int64(1e6))
+	body := snappy.Encode(nil, encodeWriteRequest(series))
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.Interval)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Encoding", "snappy")
+	request.Header.Set("Content-Type", "application/x-protobuf")
+	request.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// toTimeSeries flattens [families] into the time series of the remote-write
+// protocol, the same way Prometheus would after scraping them. [labels] are
+// added to every time series, unless the metric already has a label with the
+// same name. Samples without a timestamp are given [timestamp], in
+// milliseconds.
+func toTimeSeries(families []*dto.MetricFamily, labels map[string]string, timestamp int64) []timeSeries {
+	series := []timeSeries(nil)
+	for _, family := range families {
+		name := family.GetName()
+		for _, metric := range family.GetMetric() {
+			ts := timestamp
+			if metric.TimestampMs != nil {
+				ts = metric.GetTimestampMs()
+			}
+			add := func(name string, value float64, extra ...label) {
+				series = append(series, timeSeries{
+					labels:    seriesLabels(name, metric.GetLabel(), extra, labels),
+					value:     value,
+					timestamp: ts,
+				})
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, metric.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, metric.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add(name, metric.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				summary := metric.GetSummary()
+				for _, quantile := range summary.GetQuantile() {
+					add(name, quantile.GetValue(), label{
+						name:  quantileLabel,
+						value: formatFloat(quantile.GetQuantile()),
+					})
+				}
+				add(name+"_sum", summary.GetSampleSum())
+				add(name+"_count", float64(summary.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				histogram := metric.GetHistogram()
+				hasInf := false
+				for _, bucket := range histogram.GetBucket() {
+					upperBound := bucket.GetUpperBound()
+					hasInf = hasInf || math.IsInf(upperBound, 1)
+					add(name+"_bucket", float64(bucket.GetCumulativeCount()), label{
+						name:  bucketLabel,
+						value: formatFloat(upperBound),
+					})
+				}
+				if !hasInf {
+					add(name+"_bucket", float64(histogram.GetSampleCount()), label{
+						name:  bucketLabel,
+						value: formatFloat(math.Inf(1)),
+					})
+				}
+				add(name+"_sum", histogram.GetSampleSum())
+				add(name+"_count", float64(histogram.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+func seriesLabels(name string, metricLabels []*dto.LabelPair, extra []label, labels map[string]string) []label {
+	result := make([]label, 0, 1+len(metricLabels)+len(extra)+len(labels))
+	result = append(result, label{name: nameLabel, value: name})
+	names := map[string]struct{}{nameLabel: {}}
+	for _, pair := range metricLabels {
+		result = append(result, label{name: pair.GetName(), value: pair.GetValue()})
+		names[pair.GetName()] = struct{}{}
+	}
+	for _, l := range extra {
+		result = append(result, l)
+		names[l.name] = struct{}{}
+	}
+	for labelName, value := range labels {
+		if _, exists := names[labelName]; !exists {
+			result = append(result, label{name: labelName, value: value})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+	return result
+}
+
+// formatFloat formats [f] like Prometheus formats bucket bounds and quantiles
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+// encodeWriteRequest returns the protobuf encoding of a prometheus.WriteRequest
+// with [series]. See prompb/remote.proto and prompb/types.proto in the
+// Prometheus repository.
+func encodeWriteRequest(series []timeSeries) []byte {
+	var request []byte
+	for _, s := range series {
+		var encodedSeries []byte
+		for _, l := range s.labels {
+			var encodedLabel []byte
+			encodedLabel = protowire.AppendTag(encodedLabel, 1, protowire.BytesType)
+			encodedLabel = protowire.AppendString(encodedLabel, l.name)
+			encodedLabel = protowire.AppendTag(encodedLabel, 2, protowire.BytesType)
+			encodedLabel = protowire.AppendString(encodedLabel, l.value)
+
+			encodedSeries = protowire.AppendTag(encodedSeries, 1, protowire.BytesType)
+			encodedSeries = protowire.AppendBytes(encodedSeries, encodedLabel)
+		}
+
+		var encodedSample []byte
+		encodedSample = protowire.AppendTag(encodedSample, 1, protowire.Fixed64Type)
+		encodedSample = protowire.AppendFixed64(encodedSample, math.Float64bits(s.value))
+		encodedSample = protowire.AppendTag(encodedSample, 2, protowire.VarintType)
+		encodedSample = protowire.AppendVarint(encodedSample, uint64(s.timestamp))
+
+		encodedSeries = protowire.AppendTag(encodedSeries, 2, protowire.BytesType)
+		encodedSeries = protowire.AppendBytes(encodedSeries, encodedSample)
+
+		request = protowire.AppendTag(request, 1, protowire.BytesType)
+		request = protowire.AppendBytes(request, encodedSeries)
+	}
+	return request
+}
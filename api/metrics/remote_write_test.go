@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestToTimeSeries(t *testing.T) {
+	assert := assert.New(t)
+
+	families := []*dto.MetricFamily{
+		{
+			Name: proto.String("gauge"),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{
+				Label: []*dto.LabelPair{
+					{Name: proto.String("job"), Value: proto.String("chain")},
+				},
+				Gauge:       &dto.Gauge{Value: proto.Float64(2)},
+				TimestampMs: proto.Int64(5),
+			}},
+		},
+		{
+			Name: proto.String("latency"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(3),
+					SampleSum:   proto.Float64(4.5),
+					Bucket: []*dto.Bucket{
+						{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(1)},
+					},
+				},
+			}},
+		},
+	}
+	series := toTimeSeries(families, map[string]string{
+		"job":      "avalanchego",
+		"instance": "validator-1",
+	}, 10)
+
+	// The labels of the metric take precedence over the added ones
+	expected := []timeSeries{
+		{
+			labels: []label{
+				{name: nameLabel, value: "gauge"},
+				{name: "instance", value: "validator-1"},
+				{name: "job", value: "chain"},
+			},
+			value:     2,
+			timestamp: 5,
+		},
+		{
+			labels: []label{
+				{name: nameLabel, value: "latency_bucket"},
+				{name: "instance", value: "validator-1"},
+				{name: "job", value: "avalanchego"},
+				{name: bucketLabel, value: "1"},
+			},
+			value:     1,
+			timestamp: 10,
+		},
+		{
+			labels: []label{
+				{name: nameLabel, value: "latency_bucket"},
+				{name: "instance", value: "validator-1"},
+				{name: "job", value: "avalanchego"},
+				{name: bucketLabel, value: "+Inf"},
+			},
+			value:     3,
+			timestamp: 10,
+		},
+		{
+			labels: []label{
+				{name: nameLabel, value: "latency_sum"},
+				{name: "instance", value: "validator-1"},
+				{name: "job", value: "avalanchego"},
+			},
+			value:     4.5,
+			timestamp: 10,
+		},
+		{
+			labels: []label{
+				{name: nameLabel, value: "latency_count"},
+				{name: "instance", value: "validator-1"},
+				{name: "job", value: "avalanchego"},
+			},
+			value:     3,
+			timestamp: 10,
+		},
+	}
+	assert.Equal(expected, series)
+}
+
+func TestEncodeWriteRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	request := encodeWriteRequest([]timeSeries{{
+		labels:    []label{{name: nameLabel, value: "up"}},
+		value:     1,
+		timestamp: 7,
+	}})
+
+	// WriteRequest.timeseries
+	num, typ, n := protowire.ConsumeTag(request)
+	assert.EqualValues(1, num)
+	assert.Equal(protowire.BytesType, typ)
+	series, m := protowire.ConsumeBytes(request[n:])
+	assert.Len(request, n+m)
+
+	// TimeSeries.labels
+	num, _, n = protowire.ConsumeTag(series)
+	assert.EqualValues(1, num)
+	l, m := protowire.ConsumeBytes(series[n:])
+	series = series[n+m:]
+
+	num, _, n = protowire.ConsumeTag(l)
+	assert.EqualValues(1, num)
+	name, m := protowire.ConsumeString(l[n:])
+	assert.Equal(nameLabel, name)
+	l = l[n+m:]
+	num, _, n = protowire.ConsumeTag(l)
+	assert.EqualValues(2, num)
+	value, _ := protowire.ConsumeString(l[n:])
+	assert.Equal("up", value)
+
+	// TimeSeries.samples
+	num, _, n = protowire.ConsumeTag(series)
+	assert.EqualValues(2, num)
+	sample, _ := protowire.ConsumeBytes(series[n:])
+
+	num, _, n = protowire.ConsumeTag(sample)
+	assert.EqualValues(1, num)
+	bits, m := protowire.ConsumeFixed64(sample[n:])
+	assert.Equal(1.0, math.Float64frombits(bits))
+	sample = sample[n+m:]
+	num, _, n = protowire.ConsumeTag(sample)
+	assert.EqualValues(2, num)
+	timestamp, _ := protowire.ConsumeVarint(sample[n:])
+	assert.EqualValues(7, timestamp)
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/utils/rpc"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+// Interface compliance
+var _ Client = &client{}
+
+// Client interface for the Avalanche Networking API Endpoint
+type Client interface {
+	DroppedMessages(ctx context.Context, limit uint32, options ...rpc.Option) (*DroppedMessagesReply, error)
+}
+
+// Client implementation for the Avalanche Networking API Endpoint
+type client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a new Networking API Client
+func NewClient(uri string) Client {
+	return &client{
+		requester: rpc.NewEndpointRequester(uri, "/ext/networking", "networking"),
+	}
+}
+
+func (c *client) DroppedMessages(ctx context.Context, limit uint32, options ...rpc.Option) (*DroppedMessagesReply, error) {
+	res := &DroppedMessagesReply{}
+	err := c.requester.SendRequest(ctx, "droppedMessages", &DroppedMessagesArgs{
+		Limit: cjson.Uint32(limit),
+	}, res, options...)
+	return res, err
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/doc"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+// Networking is the API service for debugging the message flow of this node
+type Networking struct {
+	log   logging.Logger
+	drops drops.Tracker
+}
+
+// NewService returns a new networking API service
+func NewService(log logging.Logger, tracker drops.Tracker) (*common.HTTPHandler, error) {
+	newServer := doc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := newServer.RegisterService(&Networking{
+		log:   log,
+		drops: tracker,
+	}, "networking"); err != nil {
+		return nil, err
+	}
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: newServer}, nil
+}
+
+// APIDrop is the API representation of a dropped message
+type APIDrop struct {
+	Time    time.Time    `json:"time"`
+	NodeID  string       `json:"nodeID"`
+	ChainID ids.ID       `json:"chainID"`
+	Op      string       `json:"op"`
+	Reason  drops.Reason `json:"reason"`
+}
+
+// DroppedMessagesArgs are the arguments for DroppedMessages
+type DroppedMessagesArgs struct {
+	// Maximum number of drops to return. If 0, all the drops this node
+	// remembers are returned.
+	Limit cjson.Uint32 `json:"limit"`
+}
+
+// DroppedMessagesReply is the response from DroppedMessages
+type DroppedMessagesReply struct {
+	// Number of messages dropped since this node started
+	Total cjson.Uint64 `json:"total"`
+	// The most recent drops, most recent first
+	Drops []APIDrop `json:"drops"`
+	// Number of drops of each reason in [Drops]
+	Reasons map[drops.Reason]int `json:"reasons"`
+}
+
+// DroppedMessages returns the messages most recently dropped by this node
+func (service *Networking) DroppedMessages(_ *http.Request, args *DroppedMessagesArgs, reply *DroppedMessagesReply) error {
+	service.log.Debug("Networking: DroppedMessages called with limit %d", args.Limit)
+
+	reply.Total = cjson.Uint64(service.drops.Total())
+	recent := service.drops.Recent(int(args.Limit))
+	reply.Drops = make([]APIDrop, len(recent))
+	reply.Reasons = make(map[drops.Reason]int)
+	for i, drop := range recent {
+		reply.Drops[i] = APIDrop{
+			Time:    drop.Time,
+			NodeID:  drop.NodeID.PrefixedString(constants.NodeIDPrefix),
+			ChainID: drop.ChainID,
+			Op:      drop.Op.String(),
+			Reason:  drop.Reason,
+		}
+		reply.Reasons[drop.Reason]++
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestDroppedMessages(t *testing.T) {
+	assert := assert.New(t)
+
+	tracker, err := drops.NewTracker(logging.NoLog{}, drops.Config{HistorySize: 10}, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	service := &Networking{
+		log:   logging.NoLog{},
+		drops: tracker,
+	}
+
+	nodeID := ids.GenerateTestShortID()
+	chainID := ids.GenerateTestID()
+	tracker.Record(nodeID, chainID, message.PullQuery, drops.Expired)
+	tracker.Record(nodeID, chainID, message.PullQuery, drops.Expired)
+	tracker.Record(nodeID, chainID, message.Get, drops.Benched)
+
+	reply := DroppedMessagesReply{}
+	assert.NoError(service.DroppedMessages(nil, &DroppedMessagesArgs{}, &reply))
+	assert.EqualValues(3, reply.Total)
+	assert.Len(reply.Drops, 3)
+	assert.Equal(nodeID.PrefixedString(constants.NodeIDPrefix), reply.Drops[0].NodeID)
+	assert.Equal(chainID, reply.Drops[0].ChainID)
+	assert.Equal("get", reply.Drops[0].Op)
+	assert.Equal(drops.Benched, reply.Drops[0].Reason)
+	assert.Equal(map[drops.Reason]int{drops.Expired: 2, drops.Benched: 1}, reply.Reasons)
+
+	reply = DroppedMessagesReply{}
+	assert.NoError(service.DroppedMessages(nil, &DroppedMessagesArgs{Limit: 1}, &reply))
+	assert.EqualValues(3, reply.Total)
+	assert.Len(reply.Drops, 1)
+	assert.Equal(map[drops.Reason]int{drops.Benched: 1}, reply.Reasons)
+}
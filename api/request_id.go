@@ -0,0 +1,27 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import "context"
+
+// RequestIDHeader is the header that carries the ID of an API request. It's
+// set on every response, and an ID provided by the client in the request is
+// used instead of generating a new one.
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of [ctx] that carries [requestID]
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the API request that [ctx] belongs to, or the
+// empty string if it doesn't belong to one. Handlers can include it in their
+// logs so that they can be matched with the request tracing logs of the API
+// server.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
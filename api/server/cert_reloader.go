@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+// certCheckFrequency is the minimum amount of time between checks of whether
+// the certificate files have changed.
+const certCheckFrequency = 10 * time.Second
+
+// certReloader provides the TLS certificate of the HTTPS server. The
+// certificate is reloaded from disk once its files are modified, so that
+// renewed certificates are served without restarting the node. If the
+// modified files can't be loaded, the previous certificate keeps being served.
+type certReloader struct {
+	log      logging.Logger
+	certFile string
+	keyFile  string
+	clock    mockable.Clock
+
+	lock        sync.Mutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	lastCheck   time.Time
+}
+
+func newCertReloader(log logging.Logger, certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{
+		log:      log,
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	certModTime, keyModTime, err := r.modTimes()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(certModTime, keyModTime); err != nil {
+		return nil, err
+	}
+	r.lastCheck = r.clock.Time()
+	return r, nil
+}
+
+// GetCertificate implements tls.Config's GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Time()
+	if now.Sub(r.lastCheck) < certCheckFrequency {
+		return r.cert, nil
+	}
+	r.lastCheck = now
+
+	certModTime, keyModTime, err := r.modTimes()
+	if err != nil {
+		r.log.Warn("couldn't check HTTPS certificate files for changes: %s", err)
+		return r.cert, nil
+	}
+	if certModTime.Equal(r.certModTime) && keyModTime.Equal(r.keyModTime) {
+		return r.cert, nil
+	}
+	if err := r.load(certModTime, keyModTime); err != nil {
+		r.log.Warn("couldn't reload HTTPS certificate, continuing to use the previous certificate: %s", err)
+		return r.cert, nil
+	}
+	r.log.Info("reloaded HTTPS certificate from %q", r.certFile)
+	return r.cert, nil
+}
+
+func (r *certReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
+
+// load assumes [r.lock] is held or that [r] hasn't been shared yet.
+func (r *certReloader) load(certModTime, keyModTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert = &cert
+	r.certModTime = certModTime
+	r.keyModTime = keyModTime
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func writeCert(t *testing.T, certFile, keyFile string, modTime time.Time) []byte {
+	assert := assert.New(t)
+
+	certBytes, keyBytes, err := staking.NewCertAndKeyBytes()
+	assert.NoError(err)
+	assert.NoError(os.WriteFile(certFile, certBytes, 0o600))
+	assert.NoError(os.WriteFile(keyFile, keyBytes, 0o600))
+	assert.NoError(os.Chtimes(certFile, modTime, modTime))
+	assert.NoError(os.Chtimes(keyFile, modTime, modTime))
+
+	cert, err := staking.LoadTLSCertFromBytes(keyBytes, certBytes)
+	assert.NoError(err)
+	return cert.Certificate[0]
+}
+
+func TestCertReloader(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+
+	_, err := newCertReloader(logging.NoLog{}, certFile, keyFile)
+	assert.Error(err)
+
+	start := time.Unix(1000, 0)
+	firstCert := writeCert(t, certFile, keyFile, start)
+
+	r, err := newCertReloader(logging.NoLog{}, certFile, keyFile)
+	assert.NoError(err)
+	now := time.Now()
+	r.clock.Set(now)
+
+	cert, err := r.GetCertificate(nil)
+	assert.NoError(err)
+	assert.Equal(firstCert, cert.Certificate[0])
+
+	// The files aren't checked again until [certCheckFrequency] has passed
+	secondCert := writeCert(t, certFile, keyFile, start.Add(time.Second))
+	cert, err = r.GetCertificate(nil)
+	assert.NoError(err)
+	assert.Equal(firstCert, cert.Certificate[0])
+
+	now = now.Add(certCheckFrequency)
+	r.clock.Set(now)
+	cert, err = r.GetCertificate(nil)
+	assert.NoError(err)
+	assert.Equal(secondCert, cert.Certificate[0])
+
+	// An invalid certificate isn't loaded
+	assert.NoError(os.WriteFile(certFile, []byte("invalid"), 0o600))
+	modTime := start.Add(2 * time.Second)
+	assert.NoError(os.Chtimes(certFile, modTime, modTime))
+	now = now.Add(certCheckFrequency)
+	r.clock.Set(now)
+	cert, err = r.GetCertificate(nil)
+	assert.NoError(err)
+	assert.Equal(secondCert, cert.Certificate[0])
+
+	// Missing files don't stop the previous certificate from being served
+	assert.NoError(os.Remove(certFile))
+	now = now.Add(certCheckFrequency)
+	r.clock.Set(now)
+	cert, err = r.GetCertificate(nil)
+	assert.NoError(err)
+	assert.Equal(secondCert, cert.Certificate[0])
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/rs/cors"
+)
+
+type corsGroup struct {
+	prefix  string
+	handler http.Handler
+}
+
+// corsHandler applies the CORS policy of the endpoint group that a request is
+// sent to. Groups are keyed by the base of their endpoints, e.g. "info" or
+// "bc/X", and the longest base that prefixes the request path takes
+// precedence. Requests outside of every group use the default policy.
+type corsHandler struct {
+	// sorted by decreasing prefix length
+	groups         []corsGroup
+	defaultHandler http.Handler
+}
+
+func newCORSHandler(h http.Handler, allowedOrigins []string, endpointAllowedOrigins map[string][]string) http.Handler {
+	handler := &corsHandler{
+		defaultHandler: newCORS(h, allowedOrigins),
+	}
+	for base, origins := range endpointAllowedOrigins {
+		handler.groups = append(handler.groups, corsGroup{
+			prefix:  baseURL + "/" + strings.Trim(base, "/"),
+			handler: newCORS(h, origins),
+		})
+	}
+	sort.Slice(handler.groups, func(i, j int) bool {
+		return len(handler.groups[i].prefix) > len(handler.groups[j].prefix)
+	})
+	return handler
+}
+
+func newCORS(h http.Handler, allowedOrigins []string) http.Handler {
+	return cors.New(cors.Options{
+		AllowedOrigins:   allowedOrigins,
+		AllowCredentials: true,
+	}).Handler(h)
+}
+
+func (h *corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	for _, group := range h.groups {
+		if path == group.prefix || strings.HasPrefix(path, group.prefix+"/") {
+			group.handler.ServeHTTP(w, r)
+			return
+		}
+	}
+	h.defaultHandler.ServeHTTP(w, r)
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSHandlerEndpointGroups(t *testing.T) {
+	h := newCORSHandler(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+		[]string{"https://default.com"},
+		map[string][]string{
+			"bc":   {"https://chains.com"},
+			"bc/X": {"https://x.com"},
+			"info": {"https://*.info.com"},
+		},
+	)
+
+	tests := []struct {
+		path    string
+		origin  string
+		allowed bool
+	}{
+		{path: "/ext/admin", origin: "https://default.com", allowed: true},
+		{path: "/ext/admin", origin: "https://x.com", allowed: false},
+		{path: "/ext/info", origin: "https://api.info.com", allowed: true},
+		{path: "/ext/infos", origin: "https://api.info.com", allowed: false},
+		{path: "/ext/bc/X", origin: "https://x.com", allowed: true},
+		{path: "/ext/bc/X/events", origin: "https://x.com", allowed: true},
+		{path: "/ext/bc/X", origin: "https://chains.com", allowed: false},
+		{path: "/ext/bc/P", origin: "https://chains.com", allowed: true},
+		{path: "/ext/bc/P", origin: "https://default.com", allowed: false},
+	}
+	for _, test := range tests {
+		t.Run(test.path+" "+test.origin, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, test.path, nil)
+			request.Header.Set("Origin", test.origin)
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, request)
+
+			allowedOrigin := recorder.Header().Get("Access-Control-Allow-Origin")
+			if test.allowed {
+				assert.Equal(t, test.origin, allowedOrigin)
+			} else {
+				assert.Empty(t, allowedOrigin)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const forwardedForHeader = "X-Forwarded-For"
+
+var _ Wrapper = &forwardedForWrapper{}
+
+// ParseTrustedProxies parses a list of IPs and CIDR ranges, e.g. "10.0.0.1"
+// or "10.0.0.0/8".
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("couldn't parse trusted proxy %q", proxy)
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			networks = append(networks, &net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+			})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse trusted proxy %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// forwardedForWrapper sets the remote address of requests sent through a
+// trusted proxy to the address of the client, as reported by the
+// X-Forwarded-For header. The header is walked from the right, skipping the
+// addresses of trusted proxies, so a client can't spoof its address by
+// sending its own header. Requests that didn't come from a trusted proxy are
+// left untouched.
+type forwardedForWrapper struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewForwardedForWrapper returns a wrapper that trusts the X-Forwarded-For
+// header of requests sent by [trustedProxies].
+func NewForwardedForWrapper(trustedProxies []*net.IPNet) Wrapper {
+	return &forwardedForWrapper{
+		trustedProxies: trustedProxies,
+	}
+}
+
+func (f *forwardedForWrapper) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if clientIP, ok := f.clientIP(r); ok {
+			r.RemoteAddr = net.JoinHostPort(clientIP.String(), "0")
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (f *forwardedForWrapper) clientIP(r *http.Request) (net.IP, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil, false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !f.isTrusted(ip) {
+		return nil, false
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values(forwardedForHeader) {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+	clientIP := ip
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if forwardedIP == nil {
+			break
+		}
+		clientIP = forwardedIP
+		if !f.isTrusted(forwardedIP) {
+			break
+		}
+	}
+	return clientIP, !clientIP.Equal(ip)
+}
+
+func (f *forwardedForWrapper) isTrusted(ip net.IP) bool {
+	for _, network := range f.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
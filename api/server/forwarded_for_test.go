@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	assert := assert.New(t)
+
+	networks, err := ParseTrustedProxies([]string{"10.0.0.0/8", "127.0.0.1", "::1"})
+	assert.NoError(err)
+	assert.Len(networks, 3)
+	assert.Equal("10.0.0.0/8", networks[0].String())
+	assert.Equal("127.0.0.1/32", networks[1].String())
+	assert.Equal("::1/128", networks[2].String())
+
+	_, err = ParseTrustedProxies([]string{"not an ip"})
+	assert.Error(err)
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(err)
+}
+
+func TestForwardedForWrapper(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	assert.NoError(t, err)
+	wrapper := NewForwardedForWrapper(trustedProxies)
+
+	tests := []struct {
+		name               string
+		remoteAddr         string
+		forwardedFor       []string
+		expectedRemoteAddr string
+	}{
+		{
+			name:               "untrusted remote",
+			remoteAddr:         "1.2.3.4:5",
+			forwardedFor:       []string{"6.7.8.9"},
+			expectedRemoteAddr: "1.2.3.4:5",
+		},
+		{
+			name:               "trusted remote without header",
+			remoteAddr:         "10.0.0.1:5",
+			expectedRemoteAddr: "10.0.0.1:5",
+		},
+		{
+			name:               "trusted remote",
+			remoteAddr:         "10.0.0.1:5",
+			forwardedFor:       []string{"6.7.8.9"},
+			expectedRemoteAddr: "6.7.8.9:0",
+		},
+		{
+			name:               "spoofed header",
+			remoteAddr:         "10.0.0.1:5",
+			forwardedFor:       []string{"1.1.1.1, 6.7.8.9"},
+			expectedRemoteAddr: "6.7.8.9:0",
+		},
+		{
+			name:               "chained proxies",
+			remoteAddr:         "10.0.0.1:5",
+			forwardedFor:       []string{"1.1.1.1, 6.7.8.9", "10.0.0.2"},
+			expectedRemoteAddr: "6.7.8.9:0",
+		},
+		{
+			name:               "invalid entry",
+			remoteAddr:         "10.0.0.1:5",
+			forwardedFor:       []string{"6.7.8.9, garbage, 10.0.0.2"},
+			expectedRemoteAddr: "10.0.0.2:0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var remoteAddr string
+			h := wrapper.WrapHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				remoteAddr = r.RemoteAddr
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, "/ext/info", nil)
+			request.RemoteAddr = test.remoteAddr
+			for _, header := range test.forwardedFor {
+				request.Header.Add(forwardedForHeader, header)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), request)
+			assert.Equal(t, test.expectedRemoteAddr, remoteAddr)
+		})
+	}
+}
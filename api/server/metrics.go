@@ -0,0 +1,208 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// unknownLabel is reported as the service and method of calls to methods
+	// that haven't been called successfully yet
+	unknownLabel = "unknown"
+
+	// maxMethodLabels is the maximum number of methods that are labeled
+	// individually. Calls to other methods are reported as unknown.
+	maxMethodLabels = 1024
+
+	// responsePrefixLen is the number of bytes of a response that are read to
+	// tell whether the call failed
+	responsePrefixLen = 256
+)
+
+var (
+	_ Wrapper       = &metricsWrapper{}
+	_ http.Flusher  = &metricsResponseWriter{}
+	_ http.Hijacker = &metricsResponseWriter{}
+)
+
+// metricsWrapper records the number of JSON-RPC calls made to each method,
+// the number of them that failed and how long they took. Calls are labeled by
+// the service and the method they call, e.g. avm and issueTx for
+// avm.issueTx.
+//
+// Clients choose the method they call, so a method is only labeled
+// individually once a call to it succeeded. Before that, and for calls to
+// methods that don't exist, the service and method are reported as unknown.
+// Requests that aren't JSON-RPC calls aren't recorded.
+type metricsWrapper struct {
+	clock mockable.Clock
+
+	calls, errors *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+
+	lock sync.RWMutex
+	// Methods that were called successfully, and so are labeled individually
+	knownMethods map[string]struct{}
+}
+
+// NewMetricsWrapper returns a wrapper that records metrics about the JSON-RPC
+// calls made to the API server
+func NewMetricsWrapper(namespace string, registerer prometheus.Registerer) (Wrapper, error) {
+	labels := []string{"service", "method"}
+	m := &metricsWrapper{
+		calls: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "calls",
+				Help:      "Number of JSON-RPC calls made to the API server",
+			},
+			labels,
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "call_errors",
+				Help:      "Number of JSON-RPC calls made to the API server that returned an error",
+			},
+			labels,
+		),
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "call_duration_seconds",
+				Help:      "Time spent handling JSON-RPC calls made to the API server",
+				Buckets:   prometheus.DefBuckets,
+			},
+			labels,
+		),
+		knownMethods: make(map[string]struct{}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.calls),
+		registerer.Register(m.errors),
+		registerer.Register(m.duration),
+	)
+	return m, errs.Err
+}
+
+func (m *metricsWrapper) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, _ := readRPCMethod(r)
+		if method == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		writer := &metricsResponseWriter{
+			tracedResponseWriter: tracedResponseWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			},
+		}
+		start := m.clock.Time()
+		h.ServeHTTP(writer, r)
+		duration := m.clock.Time().Sub(start)
+
+		failed := writer.failed()
+		service, name := m.labels(method, failed)
+		m.calls.WithLabelValues(service, name).Inc()
+		if failed {
+			m.errors.WithLabelValues(service, name).Inc()
+		}
+		m.duration.WithLabelValues(service, name).Observe(duration.Seconds())
+	})
+}
+
+// labels returns the service and method labels of a call to [method]. If the
+// call didn't fail, [method] is labeled individually from now on.
+func (m *metricsWrapper) labels(method string, failed bool) (string, string) {
+	m.lock.RLock()
+	_, known := m.knownMethods[method]
+	m.lock.RUnlock()
+
+	if !known {
+		if failed {
+			return unknownLabel, unknownLabel
+		}
+
+		m.lock.Lock()
+		if len(m.knownMethods) < maxMethodLabels {
+			m.knownMethods[method] = struct{}{}
+			known = true
+		}
+		m.lock.Unlock()
+
+		if !known {
+			return unknownLabel, unknownLabel
+		}
+	}
+
+	// JSON-RPC methods are named service.method
+	if i := strings.IndexByte(method, '.'); i >= 0 {
+		return method[:i], method[i+1:]
+	}
+	return "", method
+}
+
+// metricsResponseWriter records the status code and the start of a response,
+// so that it can be told whether the call failed
+type metricsResponseWriter struct {
+	tracedResponseWriter
+	prefix []byte
+}
+
+func (w *metricsResponseWriter) Write(b []byte) (int, error) {
+	if remaining := responsePrefixLen - len(w.prefix); remaining > 0 {
+		if remaining > len(b) {
+			remaining = len(b)
+		}
+		w.prefix = append(w.prefix, b[:remaining]...)
+	}
+	return w.tracedResponseWriter.Write(b)
+}
+
+// failed returns true if the response has an error status code or is a
+// JSON-RPC error
+func (w *metricsResponseWriter) failed() bool {
+	if w.status >= http.StatusBadRequest {
+		return true
+	}
+
+	// A JSON-RPC response has either a result or an error. The response may
+	// have been cut off, so only the keys before the first large value can be
+	// read.
+	decoder := json.NewDecoder(bytes.NewReader(w.prefix))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return false
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		switch key {
+		case "error":
+			return true
+		case "result":
+			return false
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+// gatherCalls returns the values of the metric [name] by service/method. For
+// histograms, the number of observations is returned.
+func gatherCalls(t *testing.T, registry *prometheus.Registry, name string) map[string]float64 {
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			key := labels["service"] + "/" + labels["method"]
+			if histogram := metric.GetHistogram(); histogram != nil {
+				values[key] = float64(histogram.GetSampleCount())
+			} else {
+				values[key] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return values
+}
+
+func TestMetricsWrapper(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	wrapper, err := NewMetricsWrapper("api", registry)
+	assert.NoError(err)
+	m := wrapper.(*metricsWrapper)
+	m.clock.Set(time.Now())
+
+	var body []byte
+	h := m.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		assert.NoError(err)
+		m.clock.Set(m.clock.Time().Add(time.Second))
+
+		switch {
+		case strings.Contains(string(body), "avm.issueTx"):
+			_, _ = io.WriteString(w, `{"jsonrpc":"2.0","result":{"txID":"abc"},"id":1}`)
+		case strings.Contains(string(body), "platform.getHeight"):
+			_, _ = io.WriteString(w, `{"jsonrpc":"2.0","error":{"code":-32000,"message":"failed"},"id":1}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	call := func(method string) {
+		request := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":{}}`, method)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(request)))
+		// The handler can still read the body
+		assert.Equal(request, string(body))
+	}
+
+	call("avm.issueTx")
+	call("avm.issueTx")
+	// Methods that never succeeded aren't labeled individually
+	call("platform.getHeight")
+	call("avm.doesNotExist")
+
+	// Requests that aren't JSON-RPC calls aren't recorded
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ext/metrics", strings.NewReader("not json")))
+
+	assert.Equal(map[string]float64{
+		"avm/issueTx":     2,
+		"unknown/unknown": 2,
+	}, gatherCalls(t, registry, "api_calls"))
+	assert.Equal(map[string]float64{
+		"unknown/unknown": 2,
+	}, gatherCalls(t, registry, "api_call_errors"))
+	assert.Equal(map[string]float64{
+		"avm/issueTx":     2,
+		"unknown/unknown": 2,
+	}, gatherCalls(t, registry, "api_call_duration_seconds"))
+
+	// Once a method succeeded, its errors are labeled individually
+	m.knownMethods["platform.getHeight"] = struct{}{}
+	call("platform.getHeight")
+	assert.Equal(map[string]float64{
+		"platform/getHeight": 1,
+		"unknown/unknown":    2,
+	}, gatherCalls(t, registry, "api_call_errors"))
+}
+
+func TestMetricsWrapperMaxMethodLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	wrapper, err := NewMetricsWrapper("api", prometheus.NewRegistry())
+	assert.NoError(err)
+	m := wrapper.(*metricsWrapper)
+
+	for i := 0; i < maxMethodLabels; i++ {
+		service, method := m.labels(fmt.Sprintf("service.method%d", i), false)
+		assert.Equal("service", service)
+		assert.Equal(fmt.Sprintf("method%d", i), method)
+	}
+
+	service, method := m.labels("service.oneTooMany", false)
+	assert.Equal(unknownLabel, service)
+	assert.Equal(unknownLabel, method)
+
+	// Methods that are already labeled still are
+	service, method = m.labels("service.method0", true)
+	assert.Equal("service", service)
+	assert.Equal("method0", method)
+}
+
+func TestMetricsResponseWriterFailed(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		failed   bool
+	}{
+		{"result", http.StatusOK, `{"jsonrpc":"2.0","result":{},"id":1}`, false},
+		{"error", http.StatusOK, `{"jsonrpc":"2.0","error":{"code":-32000},"id":1}`, true},
+		{"error status", http.StatusInternalServerError, "", true},
+		{"not json", http.StatusOK, "ok", false},
+		{"cut off result", http.StatusOK, `{"jsonrpc":"2.0","result":"` + strings.Repeat("a", 2*responsePrefixLen) + `"}`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &metricsResponseWriter{
+				tracedResponseWriter: tracedResponseWriter{
+					ResponseWriter: httptest.NewRecorder(),
+					status:         http.StatusOK,
+				},
+			}
+			w.WriteHeader(test.status)
+			_, err := io.WriteString(w, test.response)
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, len(w.prefix), responsePrefixLen)
+			assert.Equal(t, test.failed, w.failed())
+		})
+	}
+}
@@ -8,7 +8,9 @@
 package server
 
 import (
+	x509 "crypto/x509"
 	io "io"
+	http "net/http"
 	reflect "reflect"
 	sync "sync"
 	time "time"
@@ -250,23 +252,51 @@ func (mr *MockServerMockRecorder) Dispatch() *gomock.Call {
 }
 
 // DispatchTLS mocks base method.
-func (m *MockServer) DispatchTLS(certBytes, keyBytes []byte) error {
+func (m *MockServer) DispatchTLS(certBytes, keyBytes []byte, clientCAs *x509.CertPool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DispatchTLS", certBytes, keyBytes)
+	ret := m.ctrl.Call(m, "DispatchTLS", certBytes, keyBytes, clientCAs)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DispatchTLS indicates an expected call of DispatchTLS.
-func (mr *MockServerMockRecorder) DispatchTLS(certBytes, keyBytes interface{}) *gomock.Call {
+func (mr *MockServerMockRecorder) DispatchTLS(certBytes, keyBytes, clientCAs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchTLS", reflect.TypeOf((*MockServer)(nil).DispatchTLS), certBytes, keyBytes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchTLS", reflect.TypeOf((*MockServer)(nil).DispatchTLS), certBytes, keyBytes, clientCAs)
+}
+
+// DispatchTLSFromFiles mocks base method.
+func (m *MockServer) DispatchTLSFromFiles(certFile, keyFile string, clientCAs *x509.CertPool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchTLSFromFiles", certFile, keyFile, clientCAs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchTLSFromFiles indicates an expected call of DispatchTLSFromFiles.
+func (mr *MockServerMockRecorder) DispatchTLSFromFiles(certFile, keyFile, clientCAs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchTLSFromFiles", reflect.TypeOf((*MockServer)(nil).DispatchTLSFromFiles), certFile, keyFile, clientCAs)
+}
+
+// Documentation mocks base method.
+func (m *MockServer) Documentation() http.Handler {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Documentation")
+	ret0, _ := ret[0].(http.Handler)
+	return ret0
+}
+
+// Documentation indicates an expected call of Documentation.
+func (mr *MockServerMockRecorder) Documentation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Documentation", reflect.TypeOf((*MockServer)(nil).Documentation))
 }
 
 // Initialize mocks base method.
-func (m *MockServer) Initialize(log logging.Logger, factory logging.Factory, host string, port uint16, allowedOrigins []string, shutdownTimeout time.Duration, nodeID ids.ShortID, wrappers ...Wrapper) {
+func (m *MockServer) Initialize(log logging.Logger, factory logging.Factory, host string, port uint16, allowedOrigins []string, endpointAllowedOrigins map[string][]string, shutdownTimeout time.Duration, nodeID ids.ShortID, wrappers ...Wrapper) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{log, factory, host, port, allowedOrigins, shutdownTimeout, nodeID}
+	varargs := []interface{}{log, factory, host, port, allowedOrigins, endpointAllowedOrigins, shutdownTimeout, nodeID}
 	for _, a := range wrappers {
 		varargs = append(varargs, a)
 	}
@@ -274,9 +304,9 @@ func (m *MockServer) Initialize(log logging.Logger, factory logging.Factory, hos
 }
 
 // Initialize indicates an expected call of Initialize.
-func (mr *MockServerMockRecorder) Initialize(log, factory, host, port, allowedOrigins, shutdownTimeout, nodeID interface{}, wrappers ...interface{}) *gomock.Call {
+func (mr *MockServerMockRecorder) Initialize(log, factory, host, port, allowedOrigins, endpointAllowedOrigins, shutdownTimeout, nodeID interface{}, wrappers ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{log, factory, host, port, allowedOrigins, shutdownTimeout, nodeID}, wrappers...)
+	varargs := append([]interface{}{log, factory, host, port, allowedOrigins, endpointAllowedOrigins, shutdownTimeout, nodeID}, wrappers...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Initialize", reflect.TypeOf((*MockServer)(nil).Initialize), varargs...)
 }
 
@@ -292,6 +322,18 @@ func (mr *MockServerMockRecorder) RegisterChain(chainName, engine interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterChain", reflect.TypeOf((*MockServer)(nil).RegisterChain), chainName, engine)
 }
 
+// SetChainAliases mocks base method.
+func (m *MockServer) SetChainAliases(aliases ids.AliaserReader) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetChainAliases", aliases)
+}
+
+// SetChainAliases indicates an expected call of SetChainAliases.
+func (mr *MockServerMockRecorder) SetChainAliases(aliases interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChainAliases", reflect.TypeOf((*MockServer)(nil).SetChainAliases), aliases)
+}
+
 // Shutdown mocks base method.
 func (m *MockServer) Shutdown() error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+const (
+	requestIDLen = 16
+
+	// maxProvidedRequestIDLen is the maximum length of a request ID provided
+	// by a client. Longer IDs are replaced.
+	maxProvidedRequestIDLen = 64
+)
+
+var (
+	errNotHijacker = errors.New("response writer doesn't support hijacking")
+
+	_ Wrapper       = &requestTracer{}
+	_ http.Flusher  = &tracedResponseWriter{}
+	_ http.Hijacker = &tracedResponseWriter{}
+)
+
+// requestTracer assigns an ID to every API request. The ID is attached to
+// the request's context, where handlers can read it with api.RequestID, and
+// is returned to the client in the [api.RequestIDHeader] header.
+//
+// Requests that take at least [slowThreshold] are written to [slowLog] with
+// their JSON-RPC method and the size of their parameters. To report the
+// method, the body of each request is buffered before it's handled, so this
+// is skipped if [slowThreshold] is 0.
+type requestTracer struct {
+	log           logging.Logger
+	slowLog       logging.Logger
+	slowThreshold time.Duration
+	clock         mockable.Clock
+}
+
+// NewRequestTracer returns a wrapper that traces API requests, logging the
+// ones that take at least [slowThreshold] to [slowLog]. If [slowThreshold] is
+// 0, no requests are logged as slow.
+func NewRequestTracer(log, slowLog logging.Logger, slowThreshold time.Duration) Wrapper {
+	return &requestTracer{
+		log:           log,
+		slowLog:       slowLog,
+		slowThreshold: slowThreshold,
+	}
+}
+
+func (t *requestTracer) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(api.RequestIDHeader)
+		if requestID == "" || len(requestID) > maxProvidedRequestIDLen {
+			requestID = newRequestID()
+		}
+		w.Header().Set(api.RequestIDHeader, requestID)
+		r = r.WithContext(api.WithRequestID(r.Context(), requestID))
+
+		var (
+			method     string
+			paramsSize int
+		)
+		if t.slowThreshold > 0 {
+			method, paramsSize = readRPCMethod(r)
+		}
+
+		writer := &tracedResponseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+		start := t.clock.Time()
+		h.ServeHTTP(writer, r)
+		duration := t.clock.Time().Sub(start)
+
+		t.log.Verbo("API request %s from %s to %s returned %d after %s",
+			requestID, r.RemoteAddr, r.URL.Path, writer.status, duration)
+		if t.slowThreshold > 0 && duration >= t.slowThreshold {
+			t.slowLog.Warn("slow API request %s from %s to %s calling %q with %d bytes of params returned %d after %s",
+				requestID, r.RemoteAddr, r.URL.Path, method, paramsSize, writer.status, duration)
+		}
+	})
+}
+
+func newRequestID() string {
+	requestID := make([]byte, requestIDLen)
+	_, _ = rand.Read(requestID)
+	return hex.EncodeToString(requestID)
+}
+
+// readRPCMethod returns the JSON-RPC method and the size of the parameters of
+// [r]. The body of [r] is replaced so that it can still be read by the
+// handler. If the body isn't a JSON-RPC request, the method is empty and the
+// size is of the whole body.
+func readRPCMethod(r *http.Request) (string, int) {
+	if r.Body == nil {
+		return "", 0
+	}
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return "", len(body)
+	}
+
+	request := struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return "", len(body)
+	}
+	return request.Method, len(request.Params)
+}
+
+// tracedResponseWriter records the status code of a response
+type tracedResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *tracedResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *tracedResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush allows handlers that stream their responses to be traced
+func (w *tracedResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack allows websocket connections to be traced
+func (w *tracedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errNotHijacker
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type warnLog struct {
+	logging.NoLog
+	warnings []string
+}
+
+func (l *warnLog) Warn(format string, args ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
+
+func TestRequestTracerRequestID(t *testing.T) {
+	assert := assert.New(t)
+
+	var requestID string
+	h := NewRequestTracer(logging.NoLog{}, logging.NoLog{}, 0).WrapHandler(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			requestID = api.RequestID(r.Context())
+		}),
+	)
+
+	// An ID is generated if the client didn't provide one
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/ext/info", nil))
+	assert.Len(requestID, 2*requestIDLen)
+	assert.Equal(requestID, recorder.Header().Get(api.RequestIDHeader))
+
+	firstID := requestID
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ext/info", nil))
+	assert.NotEqual(firstID, requestID)
+
+	// The client's ID is used if it's short enough
+	request := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+	request.Header.Set(api.RequestIDHeader, "client-id")
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	assert.Equal("client-id", requestID)
+	assert.Equal("client-id", recorder.Header().Get(api.RequestIDHeader))
+
+	longID := strings.Repeat("a", maxProvidedRequestIDLen+1)
+	request = httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+	request.Header.Set(api.RequestIDHeader, longID)
+	h.ServeHTTP(httptest.NewRecorder(), request)
+	assert.NotEqual(longID, requestID)
+	assert.Len(requestID, 2*requestIDLen)
+}
+
+func TestRequestTracerSlowLog(t *testing.T) {
+	assert := assert.New(t)
+
+	slowLog := &warnLog{}
+	tracer := &requestTracer{
+		log:           logging.NoLog{},
+		slowLog:       slowLog,
+		slowThreshold: time.Second,
+	}
+	now := time.Now()
+	tracer.clock.Set(now)
+
+	var (
+		requestDuration time.Duration
+		body            []byte
+	)
+	h := tracer.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		assert.NoError(err)
+		tracer.clock.Set(tracer.clock.Time().Add(requestDuration))
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	const rpcRequest = `{"jsonrpc":"2.0","id":1,"method":"info.getNodeID","params":{"a":1}}`
+
+	// Fast requests aren't logged
+	requestDuration = time.Second - 1
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ext/info", strings.NewReader(rpcRequest)))
+	assert.Empty(slowLog.warnings)
+	// The handler can still read the body
+	assert.Equal(rpcRequest, string(body))
+
+	requestDuration = time.Second
+	request := httptest.NewRequest(http.MethodPost, "/ext/info", strings.NewReader(rpcRequest))
+	request.Header.Set(api.RequestIDHeader, "slow")
+	h.ServeHTTP(httptest.NewRecorder(), request)
+	assert.Equal(rpcRequest, string(body))
+	assert.Len(slowLog.warnings, 1)
+	warning := slowLog.warnings[0]
+	assert.Contains(warning, "slow API request slow")
+	assert.Contains(warning, `calling "info.getNodeID" with 7 bytes of params`)
+	assert.Contains(warning, "returned 418 after 1s")
+
+	// Requests that aren't JSON-RPC calls are logged without a method
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ext/metrics", strings.NewReader("not json")))
+	assert.Len(slowLog.warnings, 2)
+	assert.Contains(slowLog.warnings[1], `calling "" with 8 bytes of params`)
+}
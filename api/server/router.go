@@ -7,9 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/gorilla/mux"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
 )
 
 var (
@@ -25,15 +29,21 @@ type router struct {
 	reservedRoutes map[string]bool                    // Reserves routes so that there can't be alias that conflict
 	aliases        map[string][]string                // Maps a route to a set of reserved routes
 	routes         map[string]map[string]http.Handler // Maps routes to a handler
+
+	// Resolves the aliases of chains in requests to /ext/bc/<alias>. Nil if
+	// chain aliases aren't resolved.
+	chainAliases ids.AliaserReader
 }
 
 func newRouter() *router {
-	return &router{
+	r := &router{
 		router:         mux.NewRouter(),
 		reservedRoutes: make(map[string]bool),
 		aliases:        make(map[string][]string),
 		routes:         make(map[string]map[string]http.Handler),
 	}
+	r.router.NotFoundHandler = http.HandlerFunc(r.serveChainAlias)
+	return r
 }
 
 func (r *router) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
@@ -43,6 +53,47 @@ func (r *router) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	r.router.ServeHTTP(writer, request)
 }
 
+// SetChainAliases resolves requests to /ext/bc/<alias> using [aliases]. The
+// aliases are looked up when requests are made, so aliases that are added or
+// removed at runtime are routed without reserving any routes.
+func (r *router) SetChainAliases(aliases ids.AliaserReader) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.chainAliases = aliases
+}
+
+// serveChainAlias handles requests that don't match any route. If the request
+// is to an alias of a chain, it's served by the chain's handler. Assumes
+// [r.lock] is read locked.
+func (r *router) serveChainAlias(writer http.ResponseWriter, request *http.Request) {
+	chainPrefix := fmt.Sprintf("%s/%s", baseURL, constants.ChainAliasPrefix)
+	if r.chainAliases == nil || !strings.HasPrefix(request.URL.Path, chainPrefix) {
+		http.NotFound(writer, request)
+		return
+	}
+
+	alias := strings.TrimPrefix(request.URL.Path, chainPrefix)
+	endpoint := ""
+	if i := strings.Index(alias, "/"); i >= 0 {
+		alias, endpoint = alias[:i], alias[i:]
+	}
+	chainID, err := r.chainAliases.Lookup(alias)
+	// If the alias is the chain's ID, the chain doesn't have a handler for
+	// this endpoint.
+	if err != nil || chainID.String() == alias {
+		http.NotFound(writer, request)
+		return
+	}
+
+	url := *request.URL
+	url.Path = chainPrefix + chainID.String() + endpoint
+	url.RawPath = ""
+	aliased := *request
+	aliased.URL = &url
+	r.router.ServeHTTP(writer, &aliased)
+}
+
 func (r *router) GetHandler(base, endpoint string) (http.Handler, error) {
 	r.routeLock.Lock()
 	defer r.routeLock.Unlock()
@@ -105,6 +156,39 @@ func (r *router) forceAddRouter(base, endpoint string, handler http.Handler) err
 	return err
 }
 
+// ReplaceRouter routes [base]+[endpoint] to [handler]. If there's already a
+// handler for the route, it's replaced along with the handlers of the route's
+// aliases.
+func (r *router) ReplaceRouter(base, endpoint string, handler http.Handler) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	if _, exists := r.routes[base][endpoint]; !exists {
+		return r.addRouter(base, endpoint, handler)
+	}
+	return r.replaceRouter(base, endpoint, handler)
+}
+
+func (r *router) replaceRouter(base, endpoint string, handler http.Handler) error {
+	url := base + endpoint
+	route := r.router.Get(url)
+	if route == nil {
+		return fmt.Errorf("couldn't find route for %s", url)
+	}
+	route.Handler(handler)
+	r.routes[base][endpoint] = handler
+
+	var err error
+	for _, alias := range r.aliases[base] {
+		if innerErr := r.replaceRouter(alias, endpoint, handler); err == nil {
+			err = innerErr
+		}
+	}
+	return err
+}
+
 func (r *router) AddAlias(base string, aliases ...string) error {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -5,7 +5,10 @@ package server
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
 )
 
 type testHandler struct{ called bool }
@@ -75,3 +78,89 @@ func TestBlock(t *testing.T) {
 		t.Fatalf("Permanently locked %s", "1")
 	}
 }
+
+func TestChainAliasRouting(t *testing.T) {
+	r := newRouter()
+
+	chainID := ids.GenerateTestID()
+	aliaser := ids.NewAliaser()
+	if err := aliaser.Alias(chainID, chainID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.Alias(chainID, "chain"); err != nil {
+		t.Fatal(err)
+	}
+	r.SetChainAliases(aliaser)
+
+	handler := &testHandler{}
+	if err := r.AddRouter("/ext/bc/"+chainID.String(), "/rpc", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(path string) int {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, nil))
+		return recorder.Code
+	}
+
+	if code := serve("/ext/bc/chain/rpc"); code != http.StatusOK || !handler.called {
+		t.Fatalf("Should have routed the alias to the chain, got status %d", code)
+	}
+
+	// Aliases of the chain's ID don't resolve to endpoints it doesn't have
+	handler.called = false
+	if code := serve("/ext/bc/" + chainID.String() + "/ws"); code != http.StatusNotFound || handler.called {
+		t.Fatalf("Should have returned not found, got status %d", code)
+	}
+
+	// Aliases added at runtime are routed
+	if err := aliaser.Alias(chainID, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if code := serve("/ext/bc/other/rpc"); code != http.StatusOK || !handler.called {
+		t.Fatalf("Should have routed the new alias to the chain, got status %d", code)
+	}
+
+	// Aliases removed at runtime stop being routed
+	handler.called = false
+	if err := aliaser.RemoveAlias("chain"); err != nil {
+		t.Fatal(err)
+	}
+	if code := serve("/ext/bc/chain/rpc"); code != http.StatusNotFound || handler.called {
+		t.Fatalf("Should have returned not found, got status %d", code)
+	}
+}
+
+func TestReplaceRouter(t *testing.T) {
+	r := newRouter()
+
+	if err := r.AddAlias("/1", "/2"); err != nil {
+		t.Fatal(err)
+	}
+
+	handler1 := &testHandler{}
+	if err := r.ReplaceRouter("/1", "/rpc", handler1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddRouter("/1", "/rpc", handler1); err == nil {
+		t.Fatalf("Should have already added %s", "/1/rpc")
+	}
+
+	handler2 := &testHandler{}
+	if err := r.ReplaceRouter("/1", "/rpc", handler2); err != nil {
+		t.Fatal(err)
+	}
+	for _, base := range []string{"/1", "/2"} {
+		if handler, err := r.GetHandler(base, "/rpc"); err != nil {
+			t.Fatal(err)
+		} else if handler != handler2 {
+			t.Fatalf("Should have replaced the handler of %s", base)
+		}
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, base+"/rpc", nil))
+		if handler1.called || !handler2.called {
+			t.Fatalf("Should have routed %s to the new handler", base)
+		}
+		handler2.called = false
+	}
+}
@@ -6,6 +6,7 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
@@ -16,8 +17,7 @@ import (
 
 	"github.com/NYTimes/gziphandler"
 
-	"github.com/rs/cors"
-
+	"github.com/ava-labs/avalanchego/api/doc"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -63,13 +63,21 @@ type Server interface {
 		host string,
 		port uint16,
 		allowedOrigins []string,
+		endpointAllowedOrigins map[string][]string,
 		shutdownTimeout time.Duration,
 		nodeID ids.ShortID,
 		wrappers ...Wrapper)
 	// Dispatch starts the API server
 	Dispatch() error
-	// DispatchTLS starts the API server with the provided TLS certificate
-	DispatchTLS(certBytes, keyBytes []byte) error
+	// DispatchTLS starts the API server with the provided TLS certificate. If
+	// [clientCAs] isn't nil, clients must present a certificate signed by one
+	// of them.
+	DispatchTLS(certBytes, keyBytes []byte, clientCAs *x509.CertPool) error
+	// DispatchTLSFromFiles starts the API server with the TLS certificate in
+	// the provided files. The certificate is reloaded when the files change.
+	// If [clientCAs] isn't nil, clients must present a certificate signed by
+	// one of them.
+	DispatchTLSFromFiles(certFile, keyFile string, clientCAs *x509.CertPool) error
 	// RegisterChain registers the API endpoints associated with this chain. That is,
 	// add <route, handler> pairs to server so that API calls can be made to the VM.
 	// This method runs in a goroutine to avoid a deadlock in the event that the caller
@@ -84,6 +92,13 @@ type Server interface {
 		ctx *snow.ConsensusContext,
 		base, endpoint string,
 	) error
+	// SetChainAliases routes requests to /ext/bc/<alias> to the chain that
+	// [aliases] gives <alias> to. Aliases that change at runtime are routed
+	// accordingly.
+	SetChainAliases(aliases ids.AliaserReader)
+	// Documentation returns a handler that serves the descriptions of the
+	// JSON-RPC services added to this server
+	Documentation() http.Handler
 	// Shutdown this server
 	Shutdown() error
 }
@@ -103,6 +118,8 @@ type server struct {
 
 	// Maps endpoints to handlers
 	router *router
+	// Describes the endpoints that are JSON-RPC services
+	docs *doc.Registry
 
 	srv *http.Server
 }
@@ -118,6 +135,7 @@ func (s *server) Initialize(
 	host string,
 	port uint16,
 	allowedOrigins []string,
+	endpointAllowedOrigins map[string][]string,
 	shutdownTimeout time.Duration,
 	nodeID ids.ShortID,
 	wrappers ...Wrapper,
@@ -128,13 +146,14 @@ func (s *server) Initialize(
 	s.listenPort = port
 	s.shutdownTimeout = shutdownTimeout
 	s.router = newRouter()
+	s.docs = doc.NewRegistry()
 
 	s.log.Info("API created with allowed origins: %v", allowedOrigins)
+	for base, origins := range endpointAllowedOrigins {
+		s.log.Info("API endpoints under %s/%s created with allowed origins: %v", baseURL, base, origins)
+	}
 
-	corsHandler := cors.New(cors.Options{
-		AllowedOrigins:   allowedOrigins,
-		AllowCredentials: true,
-	}).Handler(s.router)
+	corsHandler := newCORSHandler(s.router, allowedOrigins, endpointAllowedOrigins)
 	gzipHandler := gziphandler.GzipHandler(corsHandler)
 	s.handler = http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
@@ -167,17 +186,35 @@ func (s *server) Dispatch() error {
 	return s.srv.Serve(listener)
 }
 
-func (s *server) DispatchTLS(certBytes, keyBytes []byte) error {
-	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
+func (s *server) DispatchTLS(certBytes, keyBytes []byte, clientCAs *x509.CertPool) error {
 	cert, err := tls.X509KeyPair(certBytes, keyBytes)
 	if err != nil {
 		return err
 	}
-	config := &tls.Config{
+	return s.dispatchTLS(&tls.Config{
 		MinVersion:   tls.VersionTLS12,
 		Certificates: []tls.Certificate{cert},
+	}, clientCAs)
+}
+
+func (s *server) DispatchTLSFromFiles(certFile, keyFile string, clientCAs *x509.CertPool) error {
+	reloader, err := newCertReloader(s.log, certFile, keyFile)
+	if err != nil {
+		return err
 	}
+	return s.dispatchTLS(&tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}, clientCAs)
+}
 
+func (s *server) dispatchTLS(config *tls.Config, clientCAs *x509.CertPool) error {
+	if clientCAs != nil {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.ClientCAs = clientCAs
+	}
+
+	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
 	listener, err := tls.Listen("tcp", listenAddress, config)
 	if err != nil {
 		return err
@@ -242,7 +279,13 @@ func (s *server) AddChainRoute(handler *common.HTTPHandler, ctx *snow.ConsensusC
 	}
 	// Apply middleware to reject calls to the handler before the chain finishes bootstrapping
 	h = rejectMiddleware(h, ctx)
-	return s.router.AddRouter(url, endpoint, h)
+	// If the chain was stopped and started again, the handlers of its previous
+	// instance are replaced.
+	if err := s.router.ReplaceRouter(url, endpoint, h); err != nil {
+		return err
+	}
+	s.docs.Add(url+endpoint, handler.Handler)
+	return nil
 }
 
 func (s *server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string) error {
@@ -263,7 +306,11 @@ func (s *server) addRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	if err != nil {
 		return err
 	}
-	return s.router.AddRouter(url, endpoint, h)
+	if err := s.router.AddRouter(url, endpoint, h); err != nil {
+		return err
+	}
+	s.docs.Add(url+endpoint, handler.Handler)
+	return nil
 }
 
 // Wraps a handler by grabbing and releasing a lock before calling the handler.
@@ -292,12 +339,16 @@ func lockMiddleware(handler http.Handler, lockOption common.LockOption, lock *sy
 // not done bootstrapping, writes back an error.
 func rejectMiddleware(handler http.Handler, ctx *snow.ConsensusContext) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { // If chain isn't done bootstrapping, ignore API calls
-		if ctx.GetState() != snow.NormalOp {
+		switch ctx.GetState() {
+		case snow.NormalOp:
+			handler.ServeHTTP(w, r)
+		case snow.Stopped:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("API call rejected because chain is stopped"))
+		default:
 			w.WriteHeader(http.StatusServiceUnavailable)
 			// Doesn't matter if there's an error while writing. They'll get the StatusServiceUnavailable code.
 			_, _ = w.Write([]byte("API call rejected because chain is not done bootstrapping"))
-		} else {
-			handler.ServeHTTP(w, r)
 		}
 	})
 }
@@ -321,6 +372,12 @@ func (s *server) AddAliasesWithReadLock(endpoint string, aliases ...string) erro
 	return s.AddAliases(endpoint, aliases...)
 }
 
+func (s *server) SetChainAliases(aliases ids.AliaserReader) {
+	s.router.SetChainAliases(aliases)
+}
+
+func (s *server) Documentation() http.Handler { return s.docs }
+
 func (s *server) Shutdown() error {
 	if s.srv == nil {
 		return nil
@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+// Interface compliance
+var _ Client = &client{}
+
+// Client interface for the Avalanche Wallet API Endpoint
+type Client interface {
+	// GetBalances returns the balances of [user], keyed by chain alias and
+	// then asset ID
+	GetBalances(ctx context.Context, user api.UserPass, options ...rpc.Option) (map[string]map[ids.ID]uint64, error)
+	// EstimateSendFee returns the fee that Send would burn with the same
+	// arguments
+	EstimateSendFee(ctx context.Context, user api.UserPass, chain string, assetID ids.ID, amount uint64, to string, options ...rpc.Option) (uint64, error)
+	// Send transfers [amount] of [assetID] on [chain] to [to]. Returns the ID
+	// of the tx and the fee it burned.
+	Send(ctx context.Context, user api.UserPass, chain string, assetID ids.ID, amount uint64, to string, options ...rpc.Option) (ids.ID, uint64, error)
+	// Transfer moves [amount] of AVAX from [sourceChain] to [to] on
+	// [destinationChain]. Returns the IDs of the export and import txs.
+	Transfer(ctx context.Context, user api.UserPass, sourceChain, destinationChain string, amount uint64, to string, options ...rpc.Option) (ids.ID, ids.ID, error)
+}
+
+// Client implementation for the Avalanche Wallet API Endpoint
+type client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a new Wallet API Client
+func NewClient(uri string) Client {
+	return &client{
+		requester: rpc.NewEndpointRequester(uri, "/ext/wallet", "wallet"),
+	}
+}
+
+func (c *client) GetBalances(ctx context.Context, user api.UserPass, options ...rpc.Option) (map[string]map[ids.ID]uint64, error) {
+	res := &GetBalancesReply{}
+	if err := c.requester.SendRequest(ctx, "getBalances", &user, res, options...); err != nil {
+		return nil, err
+	}
+	balances := make(map[string]map[ids.ID]uint64, len(res.Balances))
+	for chain, chainBalances := range res.Balances {
+		balances[chain] = make(map[ids.ID]uint64, len(chainBalances))
+		for assetID, amount := range chainBalances {
+			balances[chain][assetID] = uint64(amount)
+		}
+	}
+	return balances, nil
+}
+
+func (c *client) EstimateSendFee(ctx context.Context, user api.UserPass, chain string, assetID ids.ID, amount uint64, to string, options ...rpc.Option) (uint64, error) {
+	res := &EstimateSendFeeReply{}
+	err := c.requester.SendRequest(ctx, "estimateSendFee", &SendArgs{
+		UserPass: user,
+		Chain:    chain,
+		AssetID:  assetID,
+		Amount:   cjson.Uint64(amount),
+		To:       to,
+	}, res, options...)
+	return uint64(res.Fee), err
+}
+
+func (c *client) Send(ctx context.Context, user api.UserPass, chain string, assetID ids.ID, amount uint64, to string, options ...rpc.Option) (ids.ID, uint64, error) {
+	res := &SendReply{}
+	err := c.requester.SendRequest(ctx, "send", &SendArgs{
+		UserPass: user,
+		Chain:    chain,
+		AssetID:  assetID,
+		Amount:   cjson.Uint64(amount),
+		To:       to,
+	}, res, options...)
+	return res.TxID, uint64(res.Fee), err
+}
+
+func (c *client) Transfer(ctx context.Context, user api.UserPass, sourceChain, destinationChain string, amount uint64, to string, options ...rpc.Option) (ids.ID, ids.ID, error) {
+	res := &TransferReply{}
+	err := c.requester.SendRequest(ctx, "transfer", &TransferArgs{
+		UserPass:         user,
+		SourceChain:      sourceChain,
+		DestinationChain: destinationChain,
+		Amount:           cjson.Uint64(amount),
+		To:               to,
+	}, res, options...)
+	return res.ExportTxID, res.ImportTxID, err
+}
@@ -0,0 +1,201 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/doc"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+// Service is the API service for the node's wallet
+type Service struct {
+	log      logging.Logger
+	wallet   Wallet
+	xChainID ids.ID
+}
+
+// NewService returns a new wallet API service
+func NewService(log logging.Logger, wallet Wallet, xChainID ids.ID) (*common.HTTPHandler, error) {
+	newServer := doc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := newServer.RegisterService(&Service{
+		log:      log,
+		wallet:   wallet,
+		xChainID: xChainID,
+	}, "wallet"); err != nil {
+		return nil, err
+	}
+	// Requests wait for their txs to be decided, so they must not hold the
+	// context lock
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: newServer}, nil
+}
+
+// GetBalancesReply is the response from GetBalances
+type GetBalancesReply struct {
+	// Chain alias -> asset ID -> amount
+	Balances map[string]map[ids.ID]cjson.Uint64 `json:"balances"`
+}
+
+// GetBalances returns the balances of a user on the P-chain and X-chain
+func (service *Service) GetBalances(r *http.Request, args *api.UserPass, reply *GetBalancesReply) error {
+	service.log.Debug("Wallet: GetBalances called for user %q", args.Username)
+
+	balances, err := service.wallet.GetBalances(r.Context(), args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	reply.Balances = make(map[string]map[ids.ID]cjson.Uint64, len(balances))
+	for chainID, chainBalances := range balances {
+		apiBalances := make(map[ids.ID]cjson.Uint64, len(chainBalances))
+		for assetID, amount := range chainBalances {
+			apiBalances[assetID] = cjson.Uint64(amount)
+		}
+		reply.Balances[service.chainAlias(chainID)] = apiBalances
+	}
+	return nil
+}
+
+// SendArgs are the arguments for Send and EstimateSendFee
+type SendArgs struct {
+	api.UserPass
+	// Chain to send the funds on. Either "P", "X" or a chain ID.
+	Chain   string       `json:"chain"`
+	AssetID ids.ID       `json:"assetID"`
+	Amount  cjson.Uint64 `json:"amount"`
+	To      string       `json:"to"`
+}
+
+// SendReply is the response from Send
+type SendReply struct {
+	TxID ids.ID       `json:"txID"`
+	Fee  cjson.Uint64 `json:"fee"`
+}
+
+// EstimateSendFeeReply is the response from EstimateSendFee
+type EstimateSendFeeReply struct {
+	Fee cjson.Uint64 `json:"fee"`
+}
+
+// EstimateSendFee returns the fee that Send would burn, without issuing a tx
+func (service *Service) EstimateSendFee(r *http.Request, args *SendArgs, reply *EstimateSendFeeReply) error {
+	service.log.Debug("Wallet: EstimateSendFee called for user %q on %s", args.Username, args.Chain)
+
+	chainID, to, err := service.parseSendArgs(args)
+	if err != nil {
+		return err
+	}
+	fee, err := service.wallet.EstimateSendFee(r.Context(), args.Username, args.Password, chainID, args.AssetID, uint64(args.Amount), to)
+	reply.Fee = cjson.Uint64(fee)
+	return err
+}
+
+// Send transfers an asset on a chain and returns once the tx is decided
+func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply) error {
+	service.log.Debug("Wallet: Send called for user %q on %s", args.Username, args.Chain)
+
+	chainID, to, err := service.parseSendArgs(args)
+	if err != nil {
+		return err
+	}
+	txID, fee, err := service.wallet.Send(r.Context(), args.Username, args.Password, chainID, args.AssetID, uint64(args.Amount), to)
+	if err != nil {
+		return fmt.Errorf("couldn't send funds: %w", err)
+	}
+	reply.TxID = txID
+	reply.Fee = cjson.Uint64(fee)
+	return nil
+}
+
+// TransferArgs are the arguments for Transfer
+type TransferArgs struct {
+	api.UserPass
+	SourceChain      string       `json:"sourceChain"`
+	DestinationChain string       `json:"destinationChain"`
+	Amount           cjson.Uint64 `json:"amount"`
+	To               string       `json:"to"`
+}
+
+// TransferReply is the response from Transfer
+type TransferReply struct {
+	ExportTxID ids.ID `json:"exportTxID"`
+	ImportTxID ids.ID `json:"importTxID"`
+}
+
+// Transfer moves AVAX between the P-chain and the X-chain
+func (service *Service) Transfer(r *http.Request, args *TransferArgs, reply *TransferReply) error {
+	service.log.Debug("Wallet: Transfer called for user %q from %s to %s", args.Username, args.SourceChain, args.DestinationChain)
+
+	sourceChainID, err := service.parseChain(args.SourceChain)
+	if err != nil {
+		return err
+	}
+	destinationChainID, err := service.parseChain(args.DestinationChain)
+	if err != nil {
+		return err
+	}
+	to, err := parseAddress(args.To)
+	if err != nil {
+		return err
+	}
+	reply.ExportTxID, reply.ImportTxID, err = service.wallet.Transfer(r.Context(), args.Username, args.Password, sourceChainID, destinationChainID, uint64(args.Amount), to)
+	return err
+}
+
+func (service *Service) parseSendArgs(args *SendArgs) (ids.ID, ids.ShortID, error) {
+	chainID, err := service.parseChain(args.Chain)
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, err
+	}
+	to, err := parseAddress(args.To)
+	return chainID, to, err
+}
+
+func (service *Service) parseChain(chain string) (ids.ID, error) {
+	switch chain {
+	case "P":
+		return constants.PlatformChainID, nil
+	case "X":
+		return service.xChainID, nil
+	}
+	chainID, err := ids.FromString(chain)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("couldn't parse chain %q: %w", chain, err)
+	}
+	return chainID, nil
+}
+
+func (service *Service) chainAlias(chainID ids.ID) string {
+	switch chainID {
+	case constants.PlatformChainID:
+		return "P"
+	case service.xChainID:
+		return "X"
+	default:
+		return chainID.String()
+	}
+}
+
+// parseAddress parses an address with or without a chain prefix
+func parseAddress(addrStr string) (ids.ShortID, error) {
+	_, _, addrBytes, err := formatting.ParseAddress(addrStr)
+	if err != nil {
+		_, addrBytes, err = formatting.ParseBech32(addrStr)
+	}
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+	}
+	return ids.ToShortID(addrBytes)
+}
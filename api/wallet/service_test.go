@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestServiceGetBalances(t *testing.T) {
+	assert := assert.New(t)
+
+	w, ks, state := newTestWallet(t)
+	defer w.Shutdown()
+
+	key := newTestKey(t)
+	putKey(t, ks, constants.PlatformChainID, key)
+	state.addUTXO(constants.PlatformChainID, key.PublicKey().Address(), 5)
+
+	service := &Service{
+		log:      logging.NoLog{},
+		wallet:   w,
+		xChainID: testXChainID,
+	}
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+	reply := GetBalancesReply{}
+	assert.NoError(service.GetBalances(request, &api.UserPass{
+		Username: testUsername,
+		Password: testPassword,
+	}, &reply))
+	assert.EqualValues(5, reply.Balances["P"][testAVAXAssetID])
+	assert.Empty(reply.Balances["X"])
+}
+
+func TestServiceParseArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &Service{xChainID: testXChainID}
+
+	chainID, err := service.parseChain("P")
+	assert.NoError(err)
+	assert.Equal(constants.PlatformChainID, chainID)
+	chainID, err = service.parseChain("X")
+	assert.NoError(err)
+	assert.Equal(testXChainID, chainID)
+	chainID, err = service.parseChain(testXChainID.String())
+	assert.NoError(err)
+	assert.Equal(testXChainID, chainID)
+	_, err = service.parseChain("not a chain")
+	assert.Error(err)
+
+	assert.Equal("P", service.chainAlias(constants.PlatformChainID))
+	assert.Equal("X", service.chainAlias(testXChainID))
+
+	addr := ids.GenerateTestShortID()
+	hrp := constants.GetHRP(constants.UnitTestID)
+	for _, chain := range []string{"X", "P"} {
+		addrStr, err := formatting.FormatAddress(chain, hrp, addr[:])
+		assert.NoError(err)
+		parsed, err := parseAddress(addrStr)
+		assert.NoError(err)
+		assert.Equal(addr, parsed)
+	}
+
+	// Addresses may omit the chain
+	addrStr, err := formatting.FormatBech32(hrp, addr[:])
+	assert.NoError(err)
+	parsed, err := parseAddress(addrStr)
+	assert.NoError(err)
+	assert.Equal(addr, parsed)
+
+	_, err = parseAddress("not an address")
+	assert.Error(err)
+}
@@ -0,0 +1,450 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p"
+	"github.com/ava-labs/avalanchego/wallet/chain/x"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary/common"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+	vmkeystore "github.com/ava-labs/avalanchego/vms/components/keystore"
+)
+
+// Accounts that haven't been used for this long are no longer scanned, and
+// their keys are dropped from memory.
+const idleAccountTimeout = 10 * time.Minute
+
+var (
+	errUnknownChain = errors.New("wallet doesn't support the chain")
+	errNoKeys       = errors.New("user doesn't control any keys")
+	errZeroAmount   = errors.New("amount must be positive")
+	errSameChain    = errors.New("source and destination chains must differ")
+
+	_ Wallet = &wallet{}
+)
+
+// StateFetcher returns the contexts of the P-chain and X-chain, and the UTXOs
+// on those chains that reference any of [addrs].
+type StateFetcher func(ctx context.Context, addrs ids.ShortSet) (p.Context, x.Context, primary.UTXOs, error)
+
+// Config describes the wallet
+type Config struct {
+	Log      logging.Logger
+	Keystore keystore.Keystore
+	// ID of the X-chain
+	XChainID ids.ID
+	// URI of this node's API server. Txs are issued through it.
+	URI string
+	// Time between scans of the UTXOs of the tracked accounts
+	ScanFrequency time.Duration
+	// If nil, the state is fetched from [URI]
+	FetchState StateFetcher
+}
+
+// Wallet tracks the UTXOs of keystore users on the P-chain and X-chain, and
+// builds, signs and issues txs on their behalf. Keys are read from the
+// keystore databases of both chains, so a key imported on either chain can be
+// spent on both.
+//
+// The UTXOs of a user are fetched on the user's first request and rescanned
+// in the background, so funds received from other wallets become spendable
+// without a restart.
+type Wallet interface {
+	// GetBalances returns the amount of each asset that [username] controls on
+	// each chain.
+	// Returns chain ID -> asset ID -> amount
+	GetBalances(ctx context.Context, username, password string) (map[ids.ID]map[ids.ID]uint64, error)
+
+	// EstimateSendFee returns the fee that Send would burn with the same
+	// arguments, without issuing a tx.
+	EstimateSendFee(ctx context.Context, username, password string, chainID ids.ID, assetID ids.ID, amount uint64, to ids.ShortID) (uint64, error)
+
+	// Send transfers [amount] of [assetID] on [chainID] to [to] and waits for
+	// the tx to be decided. Returns the ID of the tx and the fee it burned.
+	Send(ctx context.Context, username, password string, chainID ids.ID, assetID ids.ID, amount uint64, to ids.ShortID) (ids.ID, uint64, error)
+
+	// Transfer moves [amount] of AVAX from [sourceChainID] to [to] on
+	// [destinationChainID]. Returns the IDs of the export and import txs.
+	Transfer(ctx context.Context, username, password string, sourceChainID, destinationChainID ids.ID, amount uint64, to ids.ShortID) (ids.ID, ids.ID, error)
+
+	// Shutdown stops scanning the UTXOs of the tracked accounts
+	Shutdown()
+}
+
+type account struct {
+	// serializes the txs of the account, so they don't spend the same UTXOs
+	lock     sync.Mutex
+	keychain *secp256k1fx.Keychain
+	wallet   primary.Wallet
+	lastUsed time.Time
+}
+
+type wallet struct {
+	config Config
+	clock  mockable.Clock
+
+	lock sync.Mutex
+	// username -> account
+	accounts map[string]*account
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// New returns a wallet and starts scanning the UTXOs of the accounts it
+// tracks
+func New(config Config) Wallet {
+	if config.FetchState == nil {
+		uri := config.URI
+		config.FetchState = func(ctx context.Context, addrs ids.ShortSet) (p.Context, x.Context, primary.UTXOs, error) {
+			return primary.FetchState(ctx, uri, addrs)
+		}
+	}
+	w := &wallet{
+		config:   config,
+		accounts: make(map[string]*account),
+		closed:   make(chan struct{}),
+	}
+	go w.scan()
+	return w
+}
+
+func (w *wallet) GetBalances(ctx context.Context, username, password string) (map[ids.ID]map[ids.ID]uint64, error) {
+	acct, err := w.getAccount(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	options := common.WithContext(ctx)
+	pBalances, err := acct.wallet.P().Builder().GetBalance(options)
+	if err != nil {
+		return nil, err
+	}
+	xBalances, err := acct.wallet.X().Builder().GetFTBalance(options)
+	if err != nil {
+		return nil, err
+	}
+	return map[ids.ID]map[ids.ID]uint64{
+		constants.PlatformChainID: pBalances,
+		w.config.XChainID:         xBalances,
+	}, nil
+}
+
+func (w *wallet) EstimateSendFee(ctx context.Context, username, password string, chainID ids.ID, assetID ids.ID, amount uint64, to ids.ShortID) (uint64, error) {
+	acct, err := w.getAccount(ctx, username, password)
+	if err != nil {
+		return 0, err
+	}
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	_, fee, err := w.issueSend(ctx, acct, chainID, assetID, amount, to, false)
+	return fee, err
+}
+
+func (w *wallet) Send(ctx context.Context, username, password string, chainID ids.ID, assetID ids.ID, amount uint64, to ids.ShortID) (ids.ID, uint64, error) {
+	acct, err := w.getAccount(ctx, username, password)
+	if err != nil {
+		return ids.ID{}, 0, err
+	}
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	return w.issueSend(ctx, acct, chainID, assetID, amount, to, true)
+}
+
+// issueSend builds the tx that sends [amount] of [assetID] to [to] on
+// [chainID], and issues it if [issue] is true. Returns the ID of the issued
+// tx and the fee that the tx burns.
+//
+// Invariant: [acct.lock] must be held.
+func (w *wallet) issueSend(ctx context.Context, acct *account, chainID ids.ID, assetID ids.ID, amount uint64, to ids.ShortID, issue bool) (ids.ID, uint64, error) {
+	if amount == 0 {
+		return ids.ID{}, 0, errZeroAmount
+	}
+	outputs := []*avax.TransferableOutput{{
+		Asset: avax.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{to},
+			},
+		},
+	}}
+	options := common.WithContext(ctx)
+
+	switch chainID {
+	case constants.PlatformChainID:
+		// The P-chain doesn't have a tx for value transfers, so the transfer
+		// is made by creating a subnet
+		pWallet := acct.wallet.P()
+		utx, err := pWallet.Builder().NewBaseTx(outputs, options)
+		if err != nil {
+			return ids.ID{}, 0, fmt.Errorf("couldn't build tx: %w", err)
+		}
+		fee, err := burnedAmount(pWallet.AVAXAssetID(), utx.Ins, utx.Outs)
+		if err != nil || !issue {
+			return ids.ID{}, fee, err
+		}
+		txID, err := pWallet.IssueUnsignedTx(utx, options)
+		return txID, fee, err
+	case w.config.XChainID:
+		xWallet := acct.wallet.X()
+		utx, err := xWallet.Builder().NewBaseTx(outputs, options)
+		if err != nil {
+			return ids.ID{}, 0, fmt.Errorf("couldn't build tx: %w", err)
+		}
+		fee, err := burnedAmount(xWallet.AVAXAssetID(), utx.Ins, utx.Outs)
+		if err != nil || !issue {
+			return ids.ID{}, fee, err
+		}
+		txID, err := xWallet.IssueUnsignedTx(utx, options)
+		return txID, fee, err
+	default:
+		return ids.ID{}, 0, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+}
+
+func (w *wallet) Transfer(ctx context.Context, username, password string, sourceChainID, destinationChainID ids.ID, amount uint64, to ids.ShortID) (ids.ID, ids.ID, error) {
+	if sourceChainID == destinationChainID {
+		return ids.ID{}, ids.ID{}, errSameChain
+	}
+	for _, chainID := range []ids.ID{sourceChainID, destinationChainID} {
+		if chainID != constants.PlatformChainID && chainID != w.config.XChainID {
+			return ids.ID{}, ids.ID{}, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+		}
+	}
+	if amount == 0 {
+		return ids.ID{}, ids.ID{}, errZeroAmount
+	}
+
+	acct, err := w.getAccount(ctx, username, password)
+	if err != nil {
+		return ids.ID{}, ids.ID{}, err
+	}
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	// The exported funds are owned by one of the user's keys until they're
+	// imported to [to]
+	owner := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{acct.keychain.Addrs.List()[0]},
+	}
+	outputs := []*avax.TransferableOutput{{
+		Out: &secp256k1fx.TransferOutput{
+			Amt:          amount,
+			OutputOwners: *owner,
+		},
+	}}
+	recipient := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{to},
+	}
+	options := common.WithContext(ctx)
+
+	var exportTxID, importTxID ids.ID
+	if sourceChainID == constants.PlatformChainID {
+		outputs[0].Asset = avax.Asset{ID: acct.wallet.P().AVAXAssetID()}
+		exportTxID, err = acct.wallet.P().IssueExportTx(destinationChainID, outputs, options)
+		if err != nil {
+			return exportTxID, ids.ID{}, fmt.Errorf("couldn't export funds: %w", err)
+		}
+		importTxID, err = acct.wallet.X().IssueImportTx(sourceChainID, recipient, options)
+	} else {
+		outputs[0].Asset = avax.Asset{ID: acct.wallet.X().AVAXAssetID()}
+		exportTxID, err = acct.wallet.X().IssueExportTx(destinationChainID, outputs, options)
+		if err != nil {
+			return exportTxID, ids.ID{}, fmt.Errorf("couldn't export funds: %w", err)
+		}
+		importTxID, err = acct.wallet.P().IssueImportTx(sourceChainID, recipient, options)
+	}
+	if err != nil {
+		return exportTxID, importTxID, fmt.Errorf("couldn't import funds: %w", err)
+	}
+	return exportTxID, importTxID, nil
+}
+
+func (w *wallet) Shutdown() {
+	w.closeOnce.Do(func() {
+		close(w.closed)
+	})
+}
+
+// getAccount returns the account of [username], fetching its UTXOs if it
+// isn't tracked yet. The keys of the user are read on every call, so the
+// password is always checked and newly imported keys are picked up.
+func (w *wallet) getAccount(ctx context.Context, username, password string) (*account, error) {
+	keychain, err := w.getKeychain(username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	w.lock.Lock()
+	acct, ok := w.accounts[username]
+	if ok && acct.keychain.Addrs.Equals(keychain.Addrs) {
+		acct.lastUsed = w.clock.Time()
+		w.lock.Unlock()
+		return acct, nil
+	}
+	w.lock.Unlock()
+
+	wallet, err := w.newWallet(ctx, keychain)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch UTXOs of %q: %w", username, err)
+	}
+	acct = &account{
+		keychain: keychain,
+		wallet:   wallet,
+		lastUsed: w.clock.Time(),
+	}
+
+	w.lock.Lock()
+	w.accounts[username] = acct
+	w.lock.Unlock()
+	return acct, nil
+}
+
+// getKeychain returns the keys that [username] stored on the P-chain and the
+// X-chain
+func (w *wallet) getKeychain(username, password string) (*secp256k1fx.Keychain, error) {
+	var keys []*crypto.PrivateKeySECP256K1R
+	for _, chainID := range []ids.ID{constants.PlatformChainID, w.config.XChainID} {
+		db, err := w.config.Keystore.GetDatabase(chainID, username, password)
+		if err != nil {
+			return nil, fmt.Errorf("problem retrieving user %q: %w", username, err)
+		}
+		user := vmkeystore.NewUserFromDB(db)
+		addrs, err := user.GetAddresses()
+		if err != nil {
+			_ = user.Close()
+			return nil, fmt.Errorf("couldn't get addresses of %q: %w", username, err)
+		}
+		for _, addr := range addrs {
+			key, err := user.GetKey(addr)
+			if err != nil {
+				_ = user.Close()
+				return nil, fmt.Errorf("couldn't get key of %q: %w", username, err)
+			}
+			keys = append(keys, key)
+		}
+		if err := user.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errNoKeys
+	}
+	return secp256k1fx.NewKeychain(keys...), nil
+}
+
+func (w *wallet) newWallet(ctx context.Context, keychain *secp256k1fx.Keychain) (primary.Wallet, error) {
+	pCTX, xCTX, utxos, err := w.config.FetchState(ctx, keychain.Addrs)
+	if err != nil {
+		return nil, err
+	}
+	return primary.NewWalletWithState(w.config.URI, pCTX, xCTX, utxos, keychain), nil
+}
+
+// scan refreshes the UTXOs of the tracked accounts every [ScanFrequency]
+// until the wallet is shut down
+func (w *wallet) scan() {
+	ticker := time.NewTicker(w.config.ScanFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.rescan()
+		case <-w.closed:
+			return
+		}
+	}
+}
+
+// rescan drops the idle accounts and refreshes the UTXOs of the others
+func (w *wallet) rescan() {
+	now := w.clock.Time()
+	w.lock.Lock()
+	accounts := make(map[string]*account, len(w.accounts))
+	for username, acct := range w.accounts {
+		if now.Sub(acct.lastUsed) > idleAccountTimeout {
+			w.config.Log.Debug("no longer tracking the UTXOs of idle user %q", username)
+			delete(w.accounts, username)
+			continue
+		}
+		accounts[username] = acct
+	}
+	w.lock.Unlock()
+
+	for username, acct := range accounts {
+		w.rescanAccount(username, acct)
+	}
+}
+
+// rescanAccount replaces the UTXOs of [acct] with the UTXOs currently on the
+// chains. The account is locked during the scan, so that a tx issued during
+// the scan isn't undone by the result of the scan.
+func (w *wallet) rescanAccount(username string, acct *account) {
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.config.ScanFrequency)
+	defer cancel()
+
+	wallet, err := w.newWallet(ctx, acct.keychain)
+	if err != nil {
+		w.config.Log.Debug("couldn't scan the UTXOs of %q: %s", username, err)
+		return
+	}
+	acct.wallet = wallet
+}
+
+// burnedAmount returns the amount of [assetID] consumed by [ins] but not
+// produced by [outs]
+func burnedAmount(assetID ids.ID, ins []*avax.TransferableInput, outs []*avax.TransferableOutput) (uint64, error) {
+	var (
+		consumed uint64
+		produced uint64
+		err      error
+	)
+	for _, in := range ins {
+		if in.AssetID() != assetID {
+			continue
+		}
+		consumed, err = safemath.Add64(consumed, in.In.Amount())
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, out := range outs {
+		if out.AssetID() != assetID {
+			continue
+		}
+		produced, err = safemath.Add64(produced, out.Out.Amount())
+		if err != nil {
+			return 0, err
+		}
+	}
+	return safemath.Sub64(consumed, produced)
+}
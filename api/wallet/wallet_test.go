@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p"
+	"github.com/ava-labs/avalanchego/wallet/chain/x"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+
+	vmkeystore "github.com/ava-labs/avalanchego/vms/components/keystore"
+)
+
+const (
+	testUsername = "walletUser"
+	testPassword = "walletPassword1Zoinks!"
+
+	testBaseTxFee         = 1
+	testCreateSubnetTxFee = 10
+)
+
+var (
+	testXChainID    = ids.GenerateTestID()
+	testAVAXAssetID = ids.GenerateTestID()
+)
+
+// testState is the state of the chains returned by the test state fetcher
+type testState struct {
+	numFetches int
+	// chain ID -> UTXOs on that chain
+	utxos map[ids.ID][]*avax.UTXO
+}
+
+func (s *testState) fetch(ctx context.Context, addrs ids.ShortSet) (p.Context, x.Context, primary.UTXOs, error) {
+	s.numFetches++
+	utxos := primary.NewUTXOs()
+	for chainID, chainUTXOs := range s.utxos {
+		for _, utxo := range chainUTXOs {
+			out := utxo.Out.(*secp256k1fx.TransferOutput)
+			if !addrs.Contains(out.Addrs[0]) {
+				continue
+			}
+			if err := utxos.AddUTXO(ctx, chainID, chainID, utxo); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	pCTX := p.NewContext(constants.UnitTestID, testAVAXAssetID, testBaseTxFee, testCreateSubnetTxFee, testCreateSubnetTxFee)
+	xCTX := x.NewContext(constants.UnitTestID, testXChainID, testAVAXAssetID, testBaseTxFee, testCreateSubnetTxFee)
+	return pCTX, xCTX, utxos, nil
+}
+
+func (s *testState) addUTXO(chainID ids.ID, addr ids.ShortID, amount uint64) {
+	s.utxos[chainID] = append(s.utxos[chainID], &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: testAVAXAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	})
+}
+
+func newTestKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.NewPrivateKey()
+	assert.NoError(t, err)
+	return key.(*crypto.PrivateKeySECP256K1R)
+}
+
+// putKey stores [key] in the keystore database of [chainID] of the test user
+func putKey(t *testing.T, ks keystore.Keystore, chainID ids.ID, key *crypto.PrivateKeySECP256K1R) {
+	assert := assert.New(t)
+
+	db, err := ks.GetDatabase(chainID, testUsername, testPassword)
+	assert.NoError(err)
+	user := vmkeystore.NewUserFromDB(db)
+	assert.NoError(user.PutKeys(key))
+	assert.NoError(user.Close())
+}
+
+func newTestWallet(t *testing.T) (*wallet, keystore.Keystore, *testState) {
+	ks := keystore.New(logging.NoLog{}, manager.NewMemDB(version.DefaultVersion1_0_0))
+	assert.NoError(t, ks.CreateUser(testUsername, testPassword))
+
+	state := &testState{utxos: make(map[ids.ID][]*avax.UTXO)}
+	w := New(Config{
+		Log:           logging.NoLog{},
+		Keystore:      ks,
+		XChainID:      testXChainID,
+		ScanFrequency: time.Hour,
+		FetchState:    state.fetch,
+	}).(*wallet)
+	return w, ks, state
+}
+
+func TestGetBalances(t *testing.T) {
+	assert := assert.New(t)
+
+	w, ks, state := newTestWallet(t)
+	defer w.Shutdown()
+
+	ctx := context.Background()
+	_, err := w.GetBalances(ctx, testUsername, testPassword)
+	assert.ErrorIs(err, errNoKeys)
+
+	_, err = w.GetBalances(ctx, testUsername, "wrong password")
+	assert.Error(err)
+
+	// Keys stored on either chain are spendable on both chains
+	pKey := newTestKey(t)
+	xKey := newTestKey(t)
+	putKey(t, ks, constants.PlatformChainID, pKey)
+	putKey(t, ks, testXChainID, xKey)
+	state.addUTXO(constants.PlatformChainID, pKey.PublicKey().Address(), 1)
+	state.addUTXO(constants.PlatformChainID, xKey.PublicKey().Address(), 2)
+	state.addUTXO(testXChainID, pKey.PublicKey().Address(), 4)
+	state.addUTXO(testXChainID, ids.GenerateTestShortID(), 8)
+
+	balances, err := w.GetBalances(ctx, testUsername, testPassword)
+	assert.NoError(err)
+	assert.Equal(map[ids.ID]map[ids.ID]uint64{
+		constants.PlatformChainID: {testAVAXAssetID: 3},
+		testXChainID:              {testAVAXAssetID: 4},
+	}, balances)
+
+	// The UTXOs of a tracked account aren't fetched again
+	_, err = w.GetBalances(ctx, testUsername, testPassword)
+	assert.NoError(err)
+	assert.Equal(1, state.numFetches)
+
+	// Importing a key refetches the UTXOs
+	newKey := newTestKey(t)
+	putKey(t, ks, testXChainID, newKey)
+	state.addUTXO(testXChainID, newKey.PublicKey().Address(), 16)
+	balances, err = w.GetBalances(ctx, testUsername, testPassword)
+	assert.NoError(err)
+	assert.Equal(uint64(20), balances[testXChainID][testAVAXAssetID])
+	assert.Equal(2, state.numFetches)
+}
+
+func TestEstimateSendFee(t *testing.T) {
+	assert := assert.New(t)
+
+	w, ks, state := newTestWallet(t)
+	defer w.Shutdown()
+
+	key := newTestKey(t)
+	putKey(t, ks, testXChainID, key)
+	state.addUTXO(constants.PlatformChainID, key.PublicKey().Address(), 100)
+	state.addUTXO(testXChainID, key.PublicKey().Address(), 100)
+
+	ctx := context.Background()
+	to := ids.GenerateTestShortID()
+	fee, err := w.EstimateSendFee(ctx, testUsername, testPassword, testXChainID, testAVAXAssetID, 50, to)
+	assert.NoError(err)
+	assert.Equal(uint64(testBaseTxFee), fee)
+
+	// Transfers on the P-chain are made by creating a subnet
+	fee, err = w.EstimateSendFee(ctx, testUsername, testPassword, constants.PlatformChainID, testAVAXAssetID, 50, to)
+	assert.NoError(err)
+	assert.Equal(uint64(testCreateSubnetTxFee), fee)
+
+	_, err = w.EstimateSendFee(ctx, testUsername, testPassword, testXChainID, testAVAXAssetID, 100, to)
+	assert.Error(err, "the fee can't be paid")
+
+	_, err = w.EstimateSendFee(ctx, testUsername, testPassword, testXChainID, testAVAXAssetID, 0, to)
+	assert.ErrorIs(err, errZeroAmount)
+
+	_, err = w.EstimateSendFee(ctx, testUsername, testPassword, ids.GenerateTestID(), testAVAXAssetID, 1, to)
+	assert.ErrorIs(err, errUnknownChain)
+
+	// Estimating a fee doesn't spend the UTXOs
+	balances, err := w.GetBalances(ctx, testUsername, testPassword)
+	assert.NoError(err)
+	assert.Equal(uint64(100), balances[testXChainID][testAVAXAssetID])
+}
+
+func TestTransferInvalidChains(t *testing.T) {
+	assert := assert.New(t)
+
+	w, _, _ := newTestWallet(t)
+	defer w.Shutdown()
+
+	ctx := context.Background()
+	to := ids.GenerateTestShortID()
+	_, _, err := w.Transfer(ctx, testUsername, testPassword, testXChainID, testXChainID, 1, to)
+	assert.ErrorIs(err, errSameChain)
+
+	_, _, err = w.Transfer(ctx, testUsername, testPassword, testXChainID, ids.GenerateTestID(), 1, to)
+	assert.ErrorIs(err, errUnknownChain)
+
+	_, _, err = w.Transfer(ctx, testUsername, testPassword, testXChainID, constants.PlatformChainID, 0, to)
+	assert.ErrorIs(err, errZeroAmount)
+}
+
+func TestRescan(t *testing.T) {
+	assert := assert.New(t)
+
+	w, ks, state := newTestWallet(t)
+	defer w.Shutdown()
+
+	key := newTestKey(t)
+	putKey(t, ks, constants.PlatformChainID, key)
+	state.addUTXO(testXChainID, key.PublicKey().Address(), 1)
+
+	ctx := context.Background()
+	balances, err := w.GetBalances(ctx, testUsername, testPassword)
+	assert.NoError(err)
+	assert.Equal(uint64(1), balances[testXChainID][testAVAXAssetID])
+
+	// Funds received from elsewhere are found by the next scan
+	state.addUTXO(testXChainID, key.PublicKey().Address(), 2)
+	balances, err = w.GetBalances(ctx, testUsername, testPassword)
+	assert.NoError(err)
+	assert.Equal(uint64(1), balances[testXChainID][testAVAXAssetID])
+
+	w.rescan()
+	balances, err = w.GetBalances(ctx, testUsername, testPassword)
+	assert.NoError(err)
+	assert.Equal(uint64(3), balances[testXChainID][testAVAXAssetID])
+	assert.Equal(2, state.numFetches)
+
+	// Idle accounts are no longer scanned
+	w.clock.Set(w.clock.Time().Add(idleAccountTimeout + time.Second))
+	w.rescan()
+	assert.Empty(w.accounts)
+	assert.Equal(2, state.numFetches)
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	uriKey     = "uri"
+	timeoutKey = "timeout"
+
+	defaultURI     = "http://127.0.0.1:9650"
+	defaultTimeout = 10 * time.Second
+)
+
+var errWrongNumArgs = errors.New("wrong number of arguments")
+
+// env is what a command needs to call the APIs of the node
+type env struct {
+	// ctx expires once the timeout of the command elapsed
+	ctx     context.Context
+	timeout time.Duration
+	uri     string
+	out     io.Writer
+	flags   *pflag.FlagSet
+}
+
+// command is a subcommand of the node binary that calls the APIs of a running
+// node, instead of running one
+type command struct {
+	name string
+	// args describes the positional arguments of the command
+	args        string
+	description string
+	numArgs     int
+	// addFlags, if non-nil, adds the flags specific to the command
+	addFlags func(fs *pflag.FlagSet)
+	run      func(e *env, args []string) error
+}
+
+func (c *command) usage() string {
+	if c.args == "" {
+		return c.name
+	}
+	return fmt.Sprintf("%s %s", c.name, c.args)
+}
+
+var commands = map[string]*command{}
+
+func init() {
+	for _, c := range []*command{
+		peersCommand,
+		healthCommand,
+		chainStatusCommand,
+		getBlockCommand,
+		issueTxCommand,
+		topCommand,
+		exportEraCommand,
+		simulateCommand,
+	} {
+		commands[c.name] = c
+	}
+}
+
+// IsCommand returns true if [name] is a subcommand handled by Run
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Usage describes the subcommands handled by Run
+func Usage() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sb := strings.Builder{}
+	sb.WriteString("commands:\n")
+	for _, name := range names {
+		c := commands[name]
+		sb.WriteString(fmt.Sprintf("  %-32s %s\n", c.usage(), c.description))
+	}
+	return sb.String()
+}
+
+// Run executes the subcommand [args][0] with the arguments [args][1:] and
+// writes its output to [out]
+func Run(args []string, out io.Writer) error {
+	if len(args) == 0 || !IsCommand(args[0]) {
+		return fmt.Errorf("unknown command\n%s", Usage())
+	}
+	c := commands[args[0]]
+
+	fs := pflag.NewFlagSet(c.name, pflag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.Usage = func() {
+		fmt.Fprintf(out, "usage: %s [flags]\n%s\n\nflags:\n%s", c.usage(), c.description, fs.FlagUsages())
+	}
+	uri := fs.String(uriKey, defaultURI, "URI of the API of the node")
+	timeout := fs.Duration(timeoutKey, defaultTimeout, "Timeout of the command's API calls")
+	if c.addFlags != nil {
+		c.addFlags(fs)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != c.numArgs {
+		fs.Usage()
+		return fmt.Errorf("%w: %s expects %d but got %d", errWrongNumArgs, c.name, c.numArgs, fs.NArg())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	return c.run(&env{
+		ctx:     ctx,
+		timeout: *timeout,
+		uri:     strings.TrimRight(*uri, "/"),
+		out:     out,
+		flags:   fs,
+	}, fs.Args())
+}
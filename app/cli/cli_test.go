@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+type rpcRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	ID     uint64          `json:"id"`
+}
+
+// newTestServer returns a JSON-RPC server that replies to each method with
+// the result returned by [handlers]
+func newTestServer(t *testing.T, handlers map[string]func(params json.RawMessage) interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := rpcRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		handler, ok := handlers[request.Method]
+		if !ok {
+			t.Fatalf("unexpected method %s", request.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"result":  handler(request.Params),
+			"id":      request.ID,
+		})
+	}))
+}
+
+func reply(result interface{}) func(json.RawMessage) interface{} {
+	return func(json.RawMessage) interface{} {
+		return result
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(IsCommand("run"))
+	assert.Error(Run([]string{"run"}, &bytes.Buffer{}))
+	assert.Error(Run(nil, &bytes.Buffer{}))
+}
+
+func TestWrongNumArgs(t *testing.T) {
+	err := Run([]string{"chain-status"}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, errWrongNumArgs)
+}
+
+func TestPeers(t *testing.T) {
+	assert := assert.New(t)
+
+	server := newTestServer(t, map[string]func(json.RawMessage) interface{}{
+		"info.peers": reply(map[string]interface{}{
+			"numPeers": "1",
+			"peers": []map[string]interface{}{{
+				"ip":             "1.2.3.4:9651",
+				"nodeID":         "NodeID-111111111111111111116DBWJs",
+				"version":        "avalanche/1.7.3",
+				"observedUptime": "99",
+				"benched":        []string{},
+			}},
+		}),
+	})
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	assert.NoError(Run([]string{"peers", "--uri", server.URL}, out))
+	assert.Contains(out.String(), "NodeID-111111111111111111116DBWJs")
+	assert.Contains(out.String(), "1.2.3.4:9651")
+	assert.Contains(out.String(), "99%")
+	assert.Contains(out.String(), "never")
+}
+
+func TestHealth(t *testing.T) {
+	assert := assert.New(t)
+
+	healthy := false
+	server := newTestServer(t, map[string]func(json.RawMessage) interface{}{
+		"health.health": func(json.RawMessage) interface{} {
+			checks := map[string]interface{}{
+				"network": map[string]interface{}{},
+			}
+			if !healthy {
+				checks["P"] = map[string]interface{}{"error": "not bootstrapped"}
+			}
+			return map[string]interface{}{
+				"checks":  checks,
+				"healthy": healthy,
+			}
+		},
+	})
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	assert.ErrorIs(Run([]string{"health", "--uri", server.URL}, out), errUnhealthy)
+	assert.Contains(out.String(), "not bootstrapped")
+
+	healthy = true
+	out.Reset()
+	assert.NoError(Run([]string{"health", "--uri", server.URL}, out))
+	assert.NotContains(out.String(), "unhealthy")
+}
+
+func TestIssueTx(t *testing.T) {
+	assert := assert.New(t)
+
+	txBytes := []byte{1, 2, 3}
+	txID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	server := newTestServer(t, map[string]func(json.RawMessage) interface{}{
+		"info.getBlockchainID": reply(map[string]interface{}{
+			"blockchainID": chainID,
+		}),
+		"avm.issueTx": func(params json.RawMessage) interface{} {
+			args := struct {
+				Tx       string              `json:"tx"`
+				Encoding formatting.Encoding `json:"encoding"`
+			}{}
+			assert.NoError(json.Unmarshal(params, &args))
+			gotBytes, err := formatting.Decode(args.Encoding, args.Tx)
+			assert.NoError(err)
+			assert.Equal(txBytes, gotBytes)
+			return map[string]interface{}{
+				"txID": txID,
+			}
+		},
+	})
+	defer server.Close()
+
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, txBytes)
+	assert.NoError(err)
+	txFile := filepath.Join(t.TempDir(), "tx.hex")
+	assert.NoError(os.WriteFile(txFile, []byte(txStr+"\n"), 0o600))
+
+	out := &bytes.Buffer{}
+	assert.NoError(Run([]string{"issue-tx", "--uri", server.URL, "X", txFile}, out))
+	assert.Equal(txID.String()+"\n", out.String())
+}
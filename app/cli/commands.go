@@ -0,0 +1,221 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+var errUnhealthy = errors.New("node is unhealthy")
+
+var peersCommand = &command{
+	name:        "peers",
+	description: "List the peers of the node",
+	run: func(e *env, _ []string) error {
+		peers, err := info.NewClient(e.uri).Peers(e.ctx)
+		if err != nil {
+			return err
+		}
+		sort.Slice(peers, func(i, j int) bool {
+			return peers[i].ID < peers[j].ID
+		})
+
+		w := tabwriter.NewWriter(e.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NODE ID\tIP\tVERSION\tLAST RECEIVED\tOBSERVED UPTIME\tBENCHED")
+		for _, peer := range peers {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d%%\t%d\n",
+				peer.ID,
+				peer.IP,
+				peer.Version,
+				formatSince(peer.LastReceived),
+				peer.ObservedUptime,
+				len(peer.Benched),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var healthCommand = &command{
+	name:        "health",
+	description: "Show the health checks of the node. Fails if the node is unhealthy",
+	run: func(e *env, _ []string) error {
+		reply, err := health.NewClient(e.uri).Health(e.ctx)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(reply.Checks))
+		for name := range reply.Checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		w := tabwriter.NewWriter(e.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHECK\tSTATUS\tERROR")
+		for _, name := range names {
+			status, errStr := checkStatus(reply.Checks[name])
+			fmt.Fprintf(w, "%s\t%s\t%s\n", name, status, errStr)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if !reply.Healthy {
+			return errUnhealthy
+		}
+		return nil
+	},
+}
+
+var chainStatusCommand = &command{
+	name:        "chain-status",
+	args:        "<chain>",
+	description: "Show whether the chain is bootstrapped and healthy",
+	numArgs:     1,
+	run: func(e *env, args []string) error {
+		alias := args[0]
+		infoClient := info.NewClient(e.uri)
+		chainID, err := infoClient.GetBlockchainID(e.ctx, alias)
+		if err != nil {
+			return err
+		}
+		bootstrapped, err := infoClient.IsBootstrapped(e.ctx, chainID.String())
+		if err != nil {
+			return err
+		}
+		reply, err := health.NewClient(e.uri).Health(e.ctx)
+		if err != nil {
+			return err
+		}
+		// The health check of a chain is named after its primary alias
+		result, ok := reply.Checks[alias]
+		if !ok {
+			result, ok = reply.Checks[chainID.String()]
+		}
+
+		w := tabwriter.NewWriter(e.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "chain ID:\t%s\n", chainID)
+		fmt.Fprintf(w, "bootstrapped:\t%t\n", bootstrapped)
+		if ok {
+			status, errStr := checkStatus(result)
+			if errStr != "" {
+				status = fmt.Sprintf("%s (%s)", status, errStr)
+			}
+			fmt.Fprintf(w, "health:\t%s\n", status)
+		}
+		if chainID == constants.PlatformChainID {
+			height, err := platformvm.NewClient(e.uri).GetHeight(e.ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "height:\t%d\n", height)
+		}
+		return w.Flush()
+	},
+}
+
+var getBlockCommand = &command{
+	name: "get-block",
+	args: "<chain> <block ID>",
+	description: "Print the hex encoding of an accepted block. Blocks of chains other than " +
+		"the P-chain are read from the index API, which must be enabled",
+	numArgs: 2,
+	run: func(e *env, args []string) error {
+		alias := args[0]
+		blkID, err := ids.FromString(args[1])
+		if err != nil {
+			return fmt.Errorf("couldn't parse block ID: %w", err)
+		}
+		chainID, err := info.NewClient(e.uri).GetBlockchainID(e.ctx, alias)
+		if err != nil {
+			return err
+		}
+
+		var blkBytes []byte
+		if chainID == constants.PlatformChainID {
+			blkBytes, err = platformvm.NewClient(e.uri).GetBlock(e.ctx, blkID)
+		} else {
+			var container indexer.Container
+			container, err = indexer.NewClient(e.uri, fmt.Sprintf("/ext/index/%s/block", alias)).
+				GetContainerByID(e.ctx, &indexer.GetIndexArgs{
+					ContainerID: blkID,
+					Encoding:    formatting.Hex,
+				})
+			blkBytes = container.Bytes
+		}
+		if err != nil {
+			return err
+		}
+
+		blkStr, err := formatting.EncodeWithChecksum(formatting.Hex, blkBytes)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(e.out, blkStr)
+		return err
+	},
+}
+
+var issueTxCommand = &command{
+	name: "issue-tx",
+	args: "<chain> <file>",
+	description: "Issue the transaction in the file, encoded in hex, to the P-chain or to " +
+		"an AVM chain and print its ID",
+	numArgs: 2,
+	run: func(e *env, args []string) error {
+		alias := args[0]
+		txStr, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		txBytes, err := formatting.Decode(formatting.Hex, strings.TrimSpace(string(txStr)))
+		if err != nil {
+			return fmt.Errorf("couldn't decode transaction: %w", err)
+		}
+		chainID, err := info.NewClient(e.uri).GetBlockchainID(e.ctx, alias)
+		if err != nil {
+			return err
+		}
+
+		var txID ids.ID
+		if chainID == constants.PlatformChainID {
+			txID, err = platformvm.NewClient(e.uri).IssueTx(e.ctx, txBytes)
+		} else {
+			txID, err = avm.NewClient(e.uri, alias).IssueTx(e.ctx, txBytes)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(e.out, txID)
+		return err
+	},
+}
+
+func checkStatus(result health.Result) (string, string) {
+	if result.Error != nil {
+		return "unhealthy", *result.Error
+	}
+	return "healthy", ""
+}
+
+func formatSince(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return fmt.Sprintf("%s ago", time.Since(t).Truncate(time.Second))
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/era"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	segmentLengthKey     = "segment-length"
+	defaultSegmentLength = 8192
+)
+
+var exportEraCommand = &command{
+	name: "export-era",
+	args: "<chain> <dir>",
+	description: "Export the accepted blocks of a snowman chain from the index API to era segments in " +
+		"<dir>/<chain ID>. Resumes from the last exported block",
+	numArgs: 2,
+	addFlags: func(fs *pflag.FlagSet) {
+		fs.Uint64(segmentLengthKey, defaultSegmentLength, "Number of blocks per segment")
+	},
+	run: func(e *env, args []string) error {
+		alias := args[0]
+		segmentLength, err := e.flags.GetUint64(segmentLengthKey)
+		if err != nil {
+			return err
+		}
+		if segmentLength == 0 {
+			return fmt.Errorf("--%s must be > 0", segmentLengthKey)
+		}
+
+		chainID, err := info.NewClient(e.uri).GetBlockchainID(e.ctx, alias)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(args[1], chainID.String())
+		archive, err := era.OpenArchive(dir, chainID)
+		if err != nil {
+			return err
+		}
+
+		client := indexer.NewClient(e.uri, fmt.Sprintf("/ext/index/%s/block", alias))
+		lastAccepted, err := client.GetLastAccepted(e.ctx, &indexer.GetLastAcceptedArgs{
+			Encoding: formatting.Hex,
+		})
+		if err != nil {
+			return err
+		}
+		lastIndex, err := client.GetIndex(e.ctx, &indexer.GetIndexArgs{
+			ContainerID: lastAccepted.ID,
+			Encoding:    formatting.Hex,
+		})
+		if err != nil {
+			return err
+		}
+
+		for nextIndex := archive.NextIndex(); nextIndex <= lastIndex; nextIndex = archive.NextIndex() {
+			numContainers := lastIndex - nextIndex + 1
+			if numContainers > segmentLength {
+				numContainers = segmentLength
+			}
+			containers, err := getContainers(e, client, nextIndex, numContainers)
+			if err != nil {
+				return err
+			}
+			if err := archive.Append(nextIndex, containers); err != nil {
+				return err
+			}
+			fmt.Fprintf(e.out, "exported blocks %d to %d of %d\n", nextIndex, nextIndex+numContainers-1, lastIndex)
+		}
+		fmt.Fprintf(e.out, "%s holds %d segments\n", dir, archive.NumSegments())
+		return nil
+	},
+}
+
+// getContainers fetches [numContainers] containers starting at [startIndex].
+// Each request gets its own timeout, as a segment can take many requests.
+func getContainers(e *env, client indexer.Client, startIndex, numContainers uint64) ([]era.Container, error) {
+	containers := make([]era.Container, 0, numContainers)
+	for uint64(len(containers)) < numContainers {
+		numToFetch := numContainers - uint64(len(containers))
+		if numToFetch > indexer.MaxFetchedByRange {
+			numToFetch = indexer.MaxFetchedByRange
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+		fetched, err := client.GetContainerRange(ctx, &indexer.GetContainerRangeArgs{
+			StartIndex: json.Uint64(startIndex + uint64(len(containers))),
+			NumToFetch: json.Uint64(numToFetch),
+			Encoding:   formatting.Hex,
+		})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		if len(fetched) == 0 {
+			return nil, fmt.Errorf("index returned no containers at index %d", startIndex+uint64(len(containers)))
+		}
+		for _, container := range fetched {
+			containers = append(containers, era.Container{
+				ID:        container.ID,
+				Timestamp: container.Timestamp,
+				Bytes:     container.Bytes,
+			})
+		}
+	}
+	if uint64(len(containers)) > numContainers {
+		containers = containers[:numContainers]
+	}
+	return containers, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/era"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+func TestExportEra(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	containers := make([]indexer.FormattedContainer, 5)
+	for i := range containers {
+		containerBytes, err := formatting.EncodeWithChecksum(formatting.Hex, []byte{byte(i)})
+		assert.NoError(err)
+		containers[i] = indexer.FormattedContainer{
+			ID:        ids.GenerateTestID(),
+			Bytes:     containerBytes,
+			Timestamp: time.Unix(int64(i), 0),
+			Encoding:  formatting.Hex,
+			Index:     cjson.Uint64(i),
+		}
+	}
+	lastIndex := 2
+
+	server := newTestServer(t, map[string]func(json.RawMessage) interface{}{
+		"info.getBlockchainID": reply(map[string]interface{}{
+			"blockchainID": chainID,
+		}),
+		"index.getLastAccepted": func(json.RawMessage) interface{} {
+			return containers[lastIndex]
+		},
+		"index.getIndex": func(json.RawMessage) interface{} {
+			return indexer.GetIndexResponse{Index: cjson.Uint64(lastIndex)}
+		},
+		"index.getContainerRange": func(params json.RawMessage) interface{} {
+			args := indexer.GetContainerRangeArgs{}
+			assert.NoError(json.Unmarshal(params, &args))
+			end := int(args.StartIndex) + int(args.NumToFetch)
+			if end > lastIndex+1 {
+				end = lastIndex + 1
+			}
+			return indexer.GetContainerRangeResponse{
+				Containers: containers[args.StartIndex:end],
+			}
+		},
+	})
+	defer server.Close()
+
+	dir := t.TempDir()
+	args := []string{"export-era", "--uri", server.URL, "--segment-length", "2", "C", dir}
+	assert.NoError(Run(args, &bytes.Buffer{}))
+
+	archive, err := era.OpenArchive(filepath.Join(dir, chainID.String()), chainID)
+	assert.NoError(err)
+	assert.Equal(2, archive.NumSegments())
+	assert.EqualValues(3, archive.NextIndex())
+
+	// Exporting again resumes after the last exported block
+	lastIndex = 4
+	assert.NoError(Run(args, &bytes.Buffer{}))
+
+	archive, err = era.OpenArchive(filepath.Join(dir, chainID.String()), chainID)
+	assert.NoError(err)
+	assert.Equal(3, archive.NumSegments())
+	for i, expected := range containers {
+		container, err := archive.Get(uint64(i))
+		assert.NoError(err)
+		assert.Equal(expected.ID, container.ID)
+		assert.Equal([]byte{byte(i)}, container.Bytes)
+		assert.EqualValues(i, container.Timestamp)
+	}
+}
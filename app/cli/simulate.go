@@ -0,0 +1,181 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/simulator"
+)
+
+const (
+	consensusKey           = "consensus"
+	nodesKey               = "nodes"
+	byzantineKey           = "byzantine"
+	choicesKey             = "choices"
+	latencyDistributionKey = "latency-distribution"
+	latencyMeanKey         = "latency-mean"
+	latencyDeviationKey    = "latency-deviation"
+	packetLossKey          = "packet-loss"
+	queryTimeoutKey        = "query-timeout"
+	maxTimeKey             = "max-time"
+	trialsKey              = "trials"
+	seedKey                = "seed"
+
+	kKey                 = "k"
+	alphaKey             = "alpha"
+	betaVirtuousKey      = "beta-virtuous"
+	betaRogueKey         = "beta-rogue"
+	concurrentRepollsKey = "concurrent-repolls"
+
+	// candidatePrefix prefixes the consensus parameters of the candidate
+	candidatePrefix = "candidate-"
+)
+
+var paramKeys = []string{kKey, alphaKey, betaVirtuousKey, betaRogueKey, concurrentRepollsKey}
+
+var simulateCommand = &command{
+	name: "simulate",
+	description: "Simulate the consensus of a network with the given parameters. If a --candidate- parameter " +
+		"is set, the candidate parameters are simulated in the same network and compared with the others",
+	addFlags: func(fs *pflag.FlagSet) {
+		defaults := simulator.DefaultConfig()
+		fs.String(consensusKey, string(defaults.Consensus), "Consensus of the nodes, snowman or avalanche")
+		fs.Int(nodesKey, defaults.NumNodes, "Number of nodes, including the byzantine nodes")
+		fs.Int(byzantineKey, defaults.NumByzantine, "Number of byzantine nodes, which vote against the preference of the querying node")
+		fs.Int(choicesKey, defaults.NumChoices, "Number of conflicting choices, initially preferred evenly by the honest nodes")
+		fs.String(latencyDistributionKey, string(defaults.Latency.Distribution), "Distribution of the message latencies: constant, uniform, normal or exponential")
+		fs.Duration(latencyMeanKey, defaults.Latency.Mean, "Mean latency of a message")
+		fs.Duration(latencyDeviationKey, defaults.Latency.Deviation, "Deviation of the latency of a message")
+		fs.Float64(packetLossKey, defaults.PacketLoss, "Probability that a message is lost")
+		fs.Duration(queryTimeoutKey, defaults.QueryTimeout, "Time after which a query that wasn't answered fails")
+		fs.Duration(maxTimeKey, defaults.MaxTime, "Simulated time after which the nodes that didn't finalize are liveness failures")
+		fs.Int(trialsKey, defaults.Trials, "Number of times the network is simulated")
+		fs.Int64(seedKey, defaults.Seed, "Seed of the first trial")
+
+		for _, prefix := range []string{"", candidatePrefix} {
+			fs.Int(prefix+kKey, defaults.Params.K, "Number of nodes sampled by each poll")
+			fs.Int(prefix+alphaKey, defaults.Params.Alpha, "Number of votes for a choice required for a successful poll")
+			fs.Int(prefix+betaVirtuousKey, defaults.Params.BetaVirtuous, "Number of consecutive successful polls required to accept a virtuous choice")
+			fs.Int(prefix+betaRogueKey, defaults.Params.BetaRogue, "Number of consecutive successful polls required to accept a rogue choice")
+			fs.Int(prefix+concurrentRepollsKey, defaults.Params.ConcurrentRepolls, "Number of polls each node keeps outstanding")
+		}
+	},
+	run: func(e *env, _ []string) error {
+		baseline, err := getSimulatorConfig(e.flags, "")
+		if err != nil {
+			return err
+		}
+		compare := false
+		for _, key := range paramKeys {
+			compare = compare || e.flags.Changed(candidatePrefix+key)
+		}
+		if !compare {
+			summary, err := simulator.Run(baseline)
+			if err != nil {
+				return err
+			}
+			return writeSummaries(e.out, []string{"parameters"}, []simulator.Config{baseline}, []*simulator.Summary{summary})
+		}
+
+		candidate, err := getSimulatorConfig(e.flags, candidatePrefix)
+		if err != nil {
+			return err
+		}
+		baselineSummary, candidateSummary, err := simulator.Compare(baseline, candidate)
+		if err != nil {
+			return err
+		}
+		return writeSummaries(
+			e.out,
+			[]string{"baseline", "candidate"},
+			[]simulator.Config{baseline, candidate},
+			[]*simulator.Summary{baselineSummary, candidateSummary},
+		)
+	},
+}
+
+// getSimulatorConfig returns the simulated network described by [fs], with the
+// consensus parameters of the flags prefixed by [prefix]
+func getSimulatorConfig(fs *pflag.FlagSet, prefix string) (simulator.Config, error) {
+	config := simulator.DefaultConfig()
+	consensus, err := fs.GetString(consensusKey)
+	if err != nil {
+		return config, err
+	}
+	config.Consensus = simulator.Consensus(consensus)
+	distribution, err := fs.GetString(latencyDistributionKey)
+	if err != nil {
+		return config, err
+	}
+	config.Latency.Distribution = simulator.Distribution(distribution)
+
+	ints := map[string]*int{
+		nodesKey:                      &config.NumNodes,
+		byzantineKey:                  &config.NumByzantine,
+		choicesKey:                    &config.NumChoices,
+		trialsKey:                     &config.Trials,
+		prefix + kKey:                 &config.Params.K,
+		prefix + alphaKey:             &config.Params.Alpha,
+		prefix + betaVirtuousKey:      &config.Params.BetaVirtuous,
+		prefix + betaRogueKey:         &config.Params.BetaRogue,
+		prefix + concurrentRepollsKey: &config.Params.ConcurrentRepolls,
+	}
+	for key, value := range ints {
+		if *value, err = fs.GetInt(key); err != nil {
+			return config, err
+		}
+	}
+	durations := map[string]*time.Duration{
+		latencyMeanKey:      &config.Latency.Mean,
+		latencyDeviationKey: &config.Latency.Deviation,
+		queryTimeoutKey:     &config.QueryTimeout,
+		maxTimeKey:          &config.MaxTime,
+	}
+	for key, value := range durations {
+		if *value, err = fs.GetDuration(key); err != nil {
+			return config, err
+		}
+	}
+	if config.PacketLoss, err = fs.GetFloat64(packetLossKey); err != nil {
+		return config, err
+	}
+	config.Seed, err = fs.GetInt64(seedKey)
+	return config, err
+}
+
+// writeSummaries writes a table with a column per simulated config
+func writeSummaries(w io.Writer, names []string, configs []simulator.Config, summaries []*simulator.Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(name string, value func(i int) interface{}) {
+		fmt.Fprint(tw, name)
+		for i := range names {
+			fmt.Fprintf(tw, "\t%v", value(i))
+		}
+		fmt.Fprintln(tw)
+	}
+
+	row("", func(i int) interface{} { return names[i] })
+	row(kKey, func(i int) interface{} { return configs[i].Params.K })
+	row(alphaKey, func(i int) interface{} { return configs[i].Params.Alpha })
+	row(betaVirtuousKey, func(i int) interface{} { return configs[i].Params.BetaVirtuous })
+	row(betaRogueKey, func(i int) interface{} { return configs[i].Params.BetaRogue })
+	row(concurrentRepollsKey, func(i int) interface{} { return configs[i].Params.ConcurrentRepolls })
+	row("safety violations", func(i int) interface{} {
+		return fmt.Sprintf("%d/%d", summaries[i].SafetyViolations, summaries[i].Trials)
+	})
+	row("liveness failures", func(i int) interface{} {
+		return fmt.Sprintf("%d/%d", summaries[i].LivenessFailures, summaries[i].Trials)
+	})
+	row("median finalization", func(i int) interface{} { return summaries[i].MedianFinalization.Round(time.Millisecond) })
+	row("p99 finalization", func(i int) interface{} { return summaries[i].P99Finalization.Round(time.Millisecond) })
+	row("max finalization", func(i int) interface{} { return summaries[i].MaxFinalization.Round(time.Millisecond) })
+	row("queries per node", func(i int) interface{} { return fmt.Sprintf("%.1f", summaries[i].QueriesPerNode) })
+	return tw.Flush()
+}
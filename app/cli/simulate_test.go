@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var smallNetworkArgs = []string{
+	"--nodes=20",
+	"--k=5",
+	"--alpha=4",
+	"--beta-virtuous=3",
+	"--beta-rogue=4",
+	"--trials=2",
+}
+
+func TestSimulate(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &bytes.Buffer{}
+	assert.NoError(Run(append([]string{"simulate"}, smallNetworkArgs...), out))
+	assert.Contains(out.String(), "parameters")
+	assert.Contains(out.String(), "safety violations")
+	assert.NotContains(out.String(), "candidate")
+}
+
+func TestSimulateCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	args := append([]string{"simulate"}, smallNetworkArgs...)
+	args = append(args, "--candidate-k=5", "--candidate-alpha=5", "--candidate-beta-virtuous=3", "--candidate-beta-rogue=4")
+	out := &bytes.Buffer{}
+	assert.NoError(Run(args, out))
+	assert.Contains(out.String(), "baseline")
+	assert.Contains(out.String(), "candidate")
+
+	// The candidate parameters are verified
+	args = append(args, "--candidate-alpha=2")
+	assert.Error(Run(args, &bytes.Buffer{}))
+}
@@ -0,0 +1,281 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/spf13/pflag"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	refreshKey     = "refresh"
+	defaultRefresh = 2 * time.Second
+
+	// clearScreen moves the cursor to the top left corner of the terminal and
+	// clears it
+	clearScreen = "\033[H\033[2J"
+)
+
+var (
+	metricsPrefix = constants.PlatformName + "_"
+	// The network metrics are registered with the "network" namespace
+	networkPrefix = metricsPrefix + "network_"
+)
+
+var topCommand = &command{
+	name:        "top",
+	description: "Show a live dashboard of the chains, peers and bandwidth of the node, until interrupted",
+	addFlags: func(fs *pflag.FlagSet) {
+		fs.Duration(refreshKey, defaultRefresh, "Time between refreshes of the dashboard")
+	},
+	run: func(e *env, _ []string) error {
+		refresh, err := e.flags.GetDuration(refreshKey)
+		if err != nil {
+			return err
+		}
+		if refresh <= 0 {
+			return fmt.Errorf("--%s must be > 0", refreshKey)
+		}
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+
+		ticker := time.NewTicker(refresh)
+		defer ticker.Stop()
+
+		var previous *snapshot
+		for {
+			current, err := takeSnapshot(e)
+			fmt.Fprint(e.out, clearScreen)
+			if err != nil {
+				fmt.Fprintf(e.out, "couldn't query %s: %s\n", e.uri, err)
+			} else {
+				if err := current.render(e.out, e.uri, previous); err != nil {
+					return err
+				}
+				previous = current
+			}
+
+			select {
+			case <-ticker.C:
+			case <-signals:
+				return nil
+			}
+		}
+	},
+}
+
+type chainSnapshot struct {
+	alias        string
+	bootstrapped bool
+	// height is only reported by snowman chains
+	hasHeight bool
+	height    float64
+	// bootstrapFetched and bootstrapAccepted are the number of blocks, or
+	// vertices, fetched and accepted while bootstrapping
+	bootstrapFetched  float64
+	bootstrapAccepted float64
+	windowsHit        float64
+	windowsMissed     float64
+}
+
+// snapshot is the state of the node shown by the dashboard at a given time
+type snapshot struct {
+	time          time.Time
+	peers         float64
+	sentBytes     float64
+	receivedBytes float64
+	// chains are sorted by alias
+	chains        []*chainSnapshot
+	failingChecks []string
+}
+
+func takeSnapshot(e *env) (*snapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	families, err := getMetrics(ctx, e.uri)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := health.NewClient(e.uri).Health(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newSnapshot(time.Now(), families, reply.Checks), nil
+}
+
+// getMetrics returns the metrics served by the metrics API of the node at
+// [uri]
+func getMetrics(ctx context.Context, uri string) (map[string]*dto.MetricFamily, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, uri+"/ext/metrics", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metrics API returned status code %d", resp.StatusCode)
+	}
+	parser := expfmt.TextParser{}
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+func newSnapshot(now time.Time, families map[string]*dto.MetricFamily, checks map[string]health.Result) *snapshot {
+	s := &snapshot{
+		time:  now,
+		peers: sum(families[networkPrefix+"peers"]),
+	}
+	for name, family := range families {
+		if !strings.HasPrefix(name, networkPrefix) || strings.Contains(name, "_compression_saved_") {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, "_sent_bytes"):
+			s.sentBytes += sum(family)
+		case strings.HasSuffix(name, "_received_bytes"):
+			s.receivedBytes += sum(family)
+		}
+	}
+
+	// Every engine reports whether its chain finished bootstrapping, so it's
+	// used to find the chains
+	for name, family := range families {
+		if !strings.HasPrefix(name, metricsPrefix) || !strings.HasSuffix(name, "_bootstrap_finished") {
+			continue
+		}
+		alias := strings.TrimSuffix(strings.TrimPrefix(name, metricsPrefix), "_bootstrap_finished")
+
+		chainPrefix := metricsPrefix + alias + "_"
+		height, hasHeight := families[chainPrefix+"last_accepted_height"]
+		chain := &chainSnapshot{
+			alias:             alias,
+			bootstrapped:      sum(family) > 0,
+			hasHeight:         hasHeight,
+			height:            sum(height),
+			bootstrapFetched:  sum(families[chainPrefix+"bs_fetched"]) + sum(families[chainPrefix+"bs_fetched_vts"]),
+			bootstrapAccepted: sum(families[chainPrefix+"bs_accepted"]) + sum(families[chainPrefix+"bs_accepted_vts"]),
+			windowsHit:        sum(families[chainPrefix+"vm_proposervm_proposer_windows_hit"]),
+			windowsMissed:     sum(families[chainPrefix+"vm_proposervm_proposer_windows_missed"]),
+		}
+		s.chains = append(s.chains, chain)
+	}
+	sort.Slice(s.chains, func(i, j int) bool {
+		return s.chains[i].alias < s.chains[j].alias
+	})
+
+	for name, result := range checks {
+		if result.Error != nil {
+			s.failingChecks = append(s.failingChecks, fmt.Sprintf("%s: %s", name, *result.Error))
+		}
+	}
+	sort.Strings(s.failingChecks)
+	return s
+}
+
+// render writes the dashboard to [w]. Rates are computed since [previous],
+// which may be nil.
+func (s *snapshot) render(w io.Writer, uri string, previous *snapshot) error {
+	var (
+		sentRate     = "-"
+		receivedRate = "-"
+		elapsed      float64
+	)
+	if previous != nil {
+		elapsed = s.time.Sub(previous.time).Seconds()
+	}
+	if elapsed > 0 {
+		sentRate = formatBytes((s.sentBytes-previous.sentBytes)/elapsed) + "/s"
+		receivedRate = formatBytes((s.receivedBytes-previous.receivedBytes)/elapsed) + "/s"
+	}
+
+	fmt.Fprintf(w, "%s top - %s - %s\n\n", constants.AppName, uri, s.time.Format("15:04:05"))
+	fmt.Fprintf(w, "peers: %.0f    bandwidth: in %s, out %s\n\n", s.peers, receivedRate, sentRate)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHAIN\tHEIGHT\tBOOTSTRAP\tWINDOWS HIT\tWINDOWS MISSED")
+	for _, chain := range s.chains {
+		height := "-"
+		if chain.hasHeight {
+			height = fmt.Sprintf("%.0f", chain.height)
+		}
+		bootstrap := "done"
+		if !chain.bootstrapped {
+			bootstrap = fmt.Sprintf("%.0f/%.0f accepted", chain.bootstrapAccepted, chain.bootstrapFetched)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.0f\t%.0f\n",
+			chain.alias,
+			height,
+			bootstrap,
+			chain.windowsHit,
+			chain.windowsMissed,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(s.failingChecks) == 0 {
+		_, err := fmt.Fprintln(w, "\nall health checks pass")
+		return err
+	}
+	fmt.Fprintln(w, "\nfailing health checks:")
+	for _, check := range s.failingChecks {
+		fmt.Fprintf(w, "  %s\n", check)
+	}
+	return nil
+}
+
+// sum returns the sum of the values of the series of [family], which may be
+// nil
+func sum(family *dto.MetricFamily) float64 {
+	total := 0.0
+	for _, metric := range family.GetMetric() {
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			total += metric.GetCounter().GetValue()
+		case dto.MetricType_GAUGE:
+			total += metric.GetGauge().GetValue()
+		case dto.MetricType_UNTYPED:
+			total += metric.GetUntyped().GetValue()
+		}
+	}
+	return total
+}
+
+func formatBytes(b float64) string {
+	switch {
+	case b >= units.GiB:
+		return fmt.Sprintf("%.1f GiB", b/units.GiB)
+	case b >= units.MiB:
+		return fmt.Sprintf("%.1f MiB", b/units.MiB)
+	case b >= units.KiB:
+		return fmt.Sprintf("%.1f KiB", b/units.KiB)
+	default:
+		return fmt.Sprintf("%.0f B", b)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api/health"
+)
+
+const testMetrics = `
+# TYPE avalanche_network_peers gauge
+avalanche_network_peers 12
+# TYPE avalanche_network_push_query_sent_bytes counter
+avalanche_network_push_query_sent_bytes 1000
+# TYPE avalanche_network_push_query_received_bytes counter
+avalanche_network_push_query_received_bytes 3000
+# TYPE avalanche_network_push_query_compression_saved_sent_bytes summary
+avalanche_network_push_query_compression_saved_sent_bytes_sum 500
+avalanche_network_push_query_compression_saved_sent_bytes_count 1
+# TYPE avalanche_P_bootstrap_finished gauge
+avalanche_P_bootstrap_finished 1
+# TYPE avalanche_P_last_accepted_height gauge
+avalanche_P_last_accepted_height 42
+# TYPE avalanche_P_vm_proposervm_proposer_windows_hit counter
+avalanche_P_vm_proposervm_proposer_windows_hit 3
+# TYPE avalanche_P_vm_proposervm_proposer_windows_missed counter
+avalanche_P_vm_proposervm_proposer_windows_missed 1
+# TYPE avalanche_X_bootstrap_finished gauge
+avalanche_X_bootstrap_finished 0
+# TYPE avalanche_X_bs_fetched_vts counter
+avalanche_X_bs_fetched_vts 100
+# TYPE avalanche_X_bs_accepted_vts counter
+avalanche_X_bs_accepted_vts 60
+`
+
+func TestSnapshot(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(strings.NewReader(testMetrics))
+	assert.NoError(err)
+
+	errStr := "not bootstrapped"
+	now := time.Now()
+	s := newSnapshot(now, families, map[string]health.Result{
+		"X":       {Error: &errStr},
+		"network": {},
+	})
+	assert.Equal(12.0, s.peers)
+	assert.Equal(1000.0, s.sentBytes)
+	assert.Equal(3000.0, s.receivedBytes)
+	assert.Equal([]string{"X: not bootstrapped"}, s.failingChecks)
+
+	assert.Len(s.chains, 2)
+	p, x := s.chains[0], s.chains[1]
+	assert.Equal("P", p.alias)
+	assert.True(p.bootstrapped)
+	assert.True(p.hasHeight)
+	assert.Equal(42.0, p.height)
+	assert.Equal(3.0, p.windowsHit)
+	assert.Equal(1.0, p.windowsMissed)
+
+	assert.Equal("X", x.alias)
+	assert.False(x.bootstrapped)
+	assert.False(x.hasHeight)
+	assert.Equal(100.0, x.bootstrapFetched)
+	assert.Equal(60.0, x.bootstrapAccepted)
+
+	// Rates are computed since the previous snapshot
+	previous := &snapshot{
+		time:          now.Add(-2 * time.Second),
+		receivedBytes: 3000 - 2*2048,
+	}
+	out := &bytes.Buffer{}
+	assert.NoError(s.render(out, defaultURI, previous))
+	assert.Contains(out.String(), "in 2.0 KiB/s, out 500 B/s")
+	assert.Contains(out.String(), "60/100 accepted")
+	assert.Contains(out.String(), "X: not bootstrapped")
+
+	out.Reset()
+	assert.NoError(s.render(out, defaultURI, nil))
+	assert.Contains(out.String(), "in -, out -")
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+var _ Cacher = &SizedLRU{}
+
+type sizedEntry struct {
+	entry
+	size int
+}
+
+// SizedLRU is a key value store bounded by the total size of its values,
+// rather than by their number. If the size is attempted to be exceeded, then
+// the least recently used values are removed from the cache. A value that is
+// larger than the maximum size isn't cached.
+type SizedLRU struct {
+	lock        sync.Mutex
+	entryMap    map[interface{}]*list.Element
+	entryList   *list.List
+	currentSize int
+
+	// MaxSize is the maximum total size of the cached values
+	MaxSize int
+	// Size returns the size of [value], which is cached at [key]
+	Size func(key, value interface{}) int
+}
+
+func (c *SizedLRU) Put(key, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.put(key, value)
+}
+
+func (c *SizedLRU) Get(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.get(key)
+}
+
+func (c *SizedLRU) Evict(key interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.evict(key)
+}
+
+func (c *SizedLRU) Flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.flush()
+}
+
+// Len returns the number of cached values
+func (c *SizedLRU) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.init()
+	return c.entryList.Len()
+}
+
+// CurrentSize returns the total size of the cached values
+func (c *SizedLRU) CurrentSize() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.currentSize
+}
+
+func (c *SizedLRU) init() {
+	if c.entryMap == nil {
+		c.entryMap = make(map[interface{}]*list.Element, minCacheSize)
+	}
+	if c.entryList == nil {
+		c.entryList = list.New()
+	}
+}
+
+// resize evicts the least recently used values until at most [maxSize] bytes
+// are cached
+func (c *SizedLRU) resize(maxSize int) {
+	for c.currentSize > maxSize {
+		e := c.entryList.Front()
+		c.remove(e)
+	}
+}
+
+func (c *SizedLRU) remove(e *list.Element) {
+	c.entryList.Remove(e)
+
+	val := e.Value.(*sizedEntry)
+	delete(c.entryMap, val.Key)
+	c.currentSize -= val.size
+}
+
+func (c *SizedLRU) put(key, value interface{}) {
+	c.init()
+
+	if e, ok := c.entryMap[key]; ok {
+		c.remove(e)
+	}
+
+	size := c.Size(key, value)
+	if size > c.MaxSize {
+		c.resize(c.MaxSize)
+		return
+	}
+	c.resize(c.MaxSize - size)
+
+	c.entryMap[key] = c.entryList.PushBack(&sizedEntry{
+		entry: entry{
+			Key:   key,
+			Value: value,
+		},
+		size: size,
+	})
+	c.currentSize += size
+}
+
+func (c *SizedLRU) get(key interface{}) (interface{}, bool) {
+	c.init()
+	c.resize(c.MaxSize)
+
+	if e, ok := c.entryMap[key]; ok {
+		c.entryList.MoveToBack(e)
+
+		val := e.Value.(*sizedEntry)
+		return val.Value, true
+	}
+	return struct{}{}, false
+}
+
+func (c *SizedLRU) evict(key interface{}) {
+	c.init()
+	c.resize(c.MaxSize)
+
+	if e, ok := c.entryMap[key]; ok {
+		c.remove(e)
+	}
+}
+
+func (c *SizedLRU) flush() {
+	c.entryMap = make(map[interface{}]*list.Element, minCacheSize)
+	c.entryList = list.New()
+	c.currentSize = 0
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func unitSize(interface{}, interface{}) int { return 1 }
+
+func bytesSize(_ interface{}, value interface{}) int { return len(value.([]byte)) }
+
+func TestSizedLRU(t *testing.T) {
+	for _, test := range CacherTests {
+		cache := &SizedLRU{MaxSize: test.Size, Size: unitSize}
+		test.Func(t, cache)
+	}
+}
+
+func TestSizedLRUEvictsBySize(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := &SizedLRU{MaxSize: 10, Size: bytesSize}
+
+	id1 := ids.ID{1}
+	id2 := ids.ID{2}
+	id3 := ids.ID{3}
+
+	cache.Put(id1, make([]byte, 4))
+	cache.Put(id2, make([]byte, 4))
+	assert.Equal(2, cache.Len())
+	assert.Equal(8, cache.CurrentSize())
+
+	// [id1] is the most recently used value, so [id2] is evicted
+	_, found := cache.Get(id1)
+	assert.True(found)
+	cache.Put(id3, make([]byte, 4))
+	_, found = cache.Get(id2)
+	assert.False(found)
+	_, found = cache.Get(id1)
+	assert.True(found)
+	assert.Equal(8, cache.CurrentSize())
+
+	// Replacing a value accounts for its new size
+	cache.Put(id1, make([]byte, 6))
+	assert.Equal(10, cache.CurrentSize())
+	assert.Equal(2, cache.Len())
+}
+
+func TestSizedLRUTooLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := &SizedLRU{MaxSize: 10, Size: bytesSize}
+
+	id1 := ids.ID{1}
+	id2 := ids.ID{2}
+
+	cache.Put(id1, make([]byte, 4))
+	cache.Put(id2, make([]byte, 11))
+	_, found := cache.Get(id2)
+	assert.False(found)
+	_, found = cache.Get(id1)
+	assert.True(found)
+	assert.Equal(4, cache.CurrentSize())
+}
+
+func TestSizedLRUResize(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := &SizedLRU{MaxSize: 10, Size: bytesSize}
+
+	id1 := ids.ID{1}
+	id2 := ids.ID{2}
+
+	cache.Put(id1, make([]byte, 4))
+	cache.Put(id2, make([]byte, 4))
+
+	cache.MaxSize = 5
+	_, found := cache.Get(id1)
+	assert.False(found)
+	_, found = cache.Get(id2)
+	assert.True(found)
+	assert.Equal(4, cache.CurrentSize())
+
+	cache.Flush()
+	assert.Equal(0, cache.Len())
+	assert.Equal(0, cache.CurrentSize())
+}
@@ -99,6 +99,41 @@ func (m *Memory) releaseLock(sharedID ids.ID) *sync.Mutex {
 	return &rc.lock
 }
 
+// Inspection summarizes the values a chain put for a peer chain
+type Inspection struct {
+	// Values is the number of values the peer chain can get and remove
+	Values int `json:"values"`
+	// PendingRemovals is the number of values the peer chain removed before
+	// they were put. They're removed as soon as they're put.
+	PendingRemovals int `json:"pendingRemovals"`
+}
+
+// Inspect returns a summary of the values [sourceChainID] put for
+// [destinationChainID], which haven't been removed yet
+func (m *Memory) Inspect(sourceChainID, destinationChainID ids.ID) (Inspection, error) {
+	sharedID := m.sharedID(sourceChainID, destinationChainID)
+	db := m.GetSharedDatabase(m.db, sharedID)
+	defer m.ReleaseSharedDatabase(sharedID)
+
+	valueDB := inbound.getValueDB(destinationChainID, sourceChainID, db)
+	iter := valueDB.NewIterator()
+	defer iter.Release()
+
+	inspection := Inspection{}
+	for iter.Next() {
+		value := dbElement{}
+		if _, err := m.codec.Unmarshal(iter.Value(), &value); err != nil {
+			return Inspection{}, err
+		}
+		if value.Present {
+			inspection.Values++
+		} else {
+			inspection.PendingRemovals++
+		}
+	}
+	return inspection, iter.Error()
+}
+
 // sharedID calculates the ID of the shared memory space
 func (m *Memory) sharedID(id1, id2 ids.ID) ids.ID {
 	if bytes.Compare(id1[:], id2[:]) == 1 {
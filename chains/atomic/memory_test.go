@@ -6,6 +6,8 @@ package atomic
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -76,3 +78,30 @@ func TestMemoryUnknownFree(t *testing.T) {
 
 	m.releaseLock(sharedID)
 }
+
+func TestMemoryInspect(t *testing.T) {
+	assert := assert.New(t)
+
+	m := Memory{}
+	err := m.Initialize(logging.NoLog{}, memdb.New())
+	assert.NoError(err)
+
+	sm0 := m.NewSharedMemory(blockchainID0)
+	sm1 := m.NewSharedMemory(blockchainID1)
+
+	err = sm0.Apply(map[ids.ID]*Requests{blockchainID1: {PutRequests: []*Element{
+		{Key: []byte{0}, Value: []byte{0}},
+		{Key: []byte{1}, Value: []byte{1}},
+	}}})
+	assert.NoError(err)
+	err = sm1.Apply(map[ids.ID]*Requests{blockchainID0: {RemoveRequests: [][]byte{{1}, {2}}}})
+	assert.NoError(err)
+
+	inspection, err := m.Inspect(blockchainID0, blockchainID1)
+	assert.NoError(err)
+	assert.Equal(Inspection{Values: 1, PendingRemovals: 1}, inspection)
+
+	inspection, err = m.Inspect(blockchainID1, blockchainID0)
+	assert.NoError(err)
+	assert.Equal(Inspection{}, inspection)
+}
@@ -4,12 +4,24 @@
 package atomic
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 )
 
-var _ SharedMemory = &sharedMemory{}
+var (
+	// ErrConflictingRequests is returned when requests applied atomically
+	// operate on the same key more than once
+	ErrConflictingRequests = errors.New("conflicting shared memory requests")
+
+	errSelfRequests = errors.New("a chain can't send requests to itself")
+
+	_ SharedMemory = &sharedMemory{}
+)
 
 type Requests struct {
 	RemoveRequests [][]byte   `serialize:"true"`
@@ -39,6 +51,11 @@ type SharedMemory interface {
 		lastKey []byte,
 		err error,
 	)
+	// Apply removes the values [requests] remove from the shared memory of
+	// this chain and the peer chains they're keyed by, and puts the values
+	// they put for the peer chains, atomically with [batches]. The peer chains
+	// may be any chains other than this chain. It's up to the VMs to only
+	// exchange values with the chains their subnet validates.
 	Apply(requests map[ids.ID]*Requests, batches ...database.Batch) error
 }
 
@@ -103,6 +120,10 @@ func (sm *sharedMemory) Indexed(
 }
 
 func (sm *sharedMemory) Apply(requests map[ids.ID]*Requests, batches ...database.Batch) error {
+	if err := verifyRequests(sm.thisChainID, requests); err != nil {
+		return err
+	}
+
 	// Sorting here introduces an ordering over the locks to prevent any
 	// deadlocks
 	sharedIDs := make([]ids.ID, 0, len(requests))
@@ -151,3 +172,34 @@ func (sm *sharedMemory) Apply(requests map[ids.ID]*Requests, batches ...database
 
 	return WriteAll(batch, batches...)
 }
+
+// verifyRequests returns an error if [requests] of [thisChainID] conflict with
+// each other. Removing a present key twice would otherwise leave an optimistic
+// removal of the key behind, which would remove the key the next time it's
+// put.
+func verifyRequests(thisChainID ids.ID, requests map[ids.ID]*Requests) error {
+	for peerChainID, request := range requests {
+		if peerChainID == thisChainID {
+			return errSelfRequests
+		}
+
+		removed := ids.Set{}
+		for _, key := range request.RemoveRequests {
+			keyID := hashing.ComputeHash256Array(key)
+			if removed.Contains(keyID) {
+				return fmt.Errorf("%w: key 0x%x is removed twice from %s", ErrConflictingRequests, key, peerChainID)
+			}
+			removed.Add(keyID)
+		}
+
+		put := ids.Set{}
+		for _, elem := range request.PutRequests {
+			keyID := hashing.ComputeHash256Array(elem.Key)
+			if put.Contains(keyID) {
+				return fmt.Errorf("%w: key 0x%x is put twice for %s", ErrConflictingRequests, elem.Key, peerChainID)
+			}
+			put.Add(keyID)
+		}
+	}
+	return nil
+}
@@ -35,3 +35,15 @@ func TestSharedMemory(t *testing.T) {
 		test(t, chainID0, chainID1, sm0, sm1, testDB)
 	}
 }
+
+func TestSharedMemoryConflictingRequestsError(t *testing.T) {
+	assert := assert.New(t)
+
+	m := Memory{}
+	err := m.Initialize(logging.NoLog{}, memdb.New())
+	assert.NoError(err)
+
+	sm := m.NewSharedMemory(blockchainID0)
+	err = sm.Apply(map[ids.ID]*Requests{blockchainID1: {RemoveRequests: [][]byte{{0}, {0}}}})
+	assert.ErrorIs(err, ErrConflictingRequests)
+}
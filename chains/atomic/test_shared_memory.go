@@ -21,6 +21,7 @@ var SharedMemoryTests = []func(t *testing.T, chainID0, chainID1 ids.ID, sm0, sm1
 	TestSharedMemoryLargeIndexed,
 	TestSharedMemoryCantDuplicatePut,
 	TestSharedMemoryCantDuplicateRemove,
+	TestSharedMemoryConflictingRequests,
 	TestSharedMemoryCommitOnPut,
 	TestSharedMemoryCommitOnRemove,
 	TestSharedMemoryLargeBatchSize,
@@ -224,6 +225,29 @@ func TestSharedMemoryCantDuplicateRemove(t *testing.T, _, chainID1 ids.ID, sm0,
 	assert.Error(err, "shouldn't be able to remove duplicated keys")
 }
 
+func TestSharedMemoryConflictingRequests(t *testing.T, chainID0, chainID1 ids.ID, sm0, sm1 SharedMemory, _ database.Database) {
+	assert := assert.New(t)
+	err := sm0.Apply(map[ids.ID]*Requests{chainID1: {PutRequests: []*Element{{
+		Key:   []byte{0},
+		Value: []byte{1},
+	}}}})
+	assert.NoError(err)
+
+	err = sm1.Apply(map[ids.ID]*Requests{chainID0: {RemoveRequests: [][]byte{{0}, {0}}}})
+	assert.Error(err, "shouldn't be able to remove a key twice atomically")
+
+	// The value is still there
+	values, err := sm1.Get(chainID0, [][]byte{{0}})
+	assert.NoError(err)
+	assert.Equal([][]byte{{1}}, values)
+
+	err = sm0.Apply(map[ids.ID]*Requests{chainID0: {PutRequests: []*Element{{
+		Key:   []byte{1},
+		Value: []byte{1},
+	}}}})
+	assert.Error(err, "shouldn't be able to send requests to itself")
+}
+
 func TestSharedMemoryCommitOnPut(t *testing.T, _, chainID1 ids.ID, sm0, _ SharedMemory, db database.Database) {
 	assert := assert.New(t)
 
@@ -8,6 +8,8 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -19,17 +21,24 @@ import (
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/quotadb"
+	"github.com/ava-labs/avalanchego/database/tieredb"
+	"github.com/ava-labs/avalanchego/era"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/network/lightclient"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/state"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/common/queue"
 	"github.com/ava-labs/avalanchego/snow/engine/common/tracker"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/evidence"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
@@ -38,10 +47,14 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/cachevm"
 	"github.com/ava-labs/avalanchego/vms/metervm"
 	"github.com/ava-labs/avalanchego/vms/proposervm"
+	"github.com/ava-labs/avalanchego/vms/throttlevm"
 
 	dbManager "github.com/ava-labs/avalanchego/database/manager"
 
@@ -59,10 +72,19 @@ import (
 const defaultChannelSize = 1
 
 var (
-	errUnknownChainID   = errors.New("unknown chain ID")
-	errUnknownVMType    = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
-	errCreatePlatformVM = errors.New("attempted to create a chain running the PlatformVM")
-	errNotBootstrapped  = errors.New("chains not bootstrapped")
+	errUnknownChainID    = errors.New("unknown chain ID")
+	errUnknownVMType     = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
+	errCreatePlatformVM  = errors.New("attempted to create a chain running the PlatformVM")
+	errNotBootstrapped   = errors.New("chains not bootstrapped")
+	errStopCriticalChain = errors.New("can't stop a critical chain")
+	errChainStopped      = errors.New("chain is already stopped")
+	errChainRunning      = errors.New("chain is already running")
+	errNotSnowmanChain   = errors.New("chain doesn't run snowman consensus")
+	errNotAvalancheChain = errors.New("chain doesn't run avalanche consensus")
+	errBootstrapping     = errors.New("chain is bootstrapping")
+	errNoStallWatchdog   = errors.New("chain isn't watched for finality stalls")
+	errNotStalled        = errors.New("chain hasn't stalled")
+	errNoFeeEstimator    = errors.New("chain's VM doesn't estimate fees")
 
 	_ Manager = &manager{}
 )
@@ -101,6 +123,39 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// StopChain shuts down the engine and VM of the chain without stopping
+	// the node. The chain can be started again with StartChain.
+	StopChain(chainID ids.ID) error
+
+	// StartChain starts a chain that was stopped by StopChain.
+	StartChain(chainID ids.ID) error
+
+	// RejectedBlocks returns the most recently rejected blocks of the running
+	// snowman chain, and why they were rejected.
+	RejectedBlocks(chainID ids.ID) ([]proposervm.RejectedBlock, error)
+
+	// ChainVMInfo returns the ID of the VM run by the running snowman chain,
+	// along with the version and capabilities of the VM.
+	ChainVMInfo(chainID ids.ID) (ids.ID, proposervm.InnerVMInfo, error)
+
+	// SetSubnetVMConfig records the config published by [subnetID] for the
+	// VMs of its chains. The chains created or restarted afterwards are
+	// initialized with it, and the running chains whose VM implements
+	// common.SubnetVMConfigurable are given it.
+	SetSubnetVMConfig(subnetID ids.ID, config []byte)
+
+	// EstimateFees returns the fees of the txs issued to the running chain,
+	// as estimated by its VM. See common.FeeEstimator.
+	EstimateFees(chainID ids.ID, txType string, size uint64) (common.FeeEstimate, error)
+
+	// ConflictGraph returns a snapshot of the txs being decided by the running
+	// avalanche chain and of the conflicts between them.
+	ConflictGraph(chainID ids.ID) (snowstorm.ConflictGraph, error)
+
+	// StallDiagnostics returns the diagnostics bundle captured when the
+	// running snowman chain last stopped accepting blocks.
+	StallDiagnostics(chainID ids.ID) (*StallDiagnostics, error)
+
 	Shutdown()
 }
 
@@ -113,6 +168,11 @@ type ChainParameters struct {
 	FxAliases   []string // The IDs of the feature extensions this chain is running
 
 	CustomBeacons validators.Set // Should only be set if the default beacons can't be used.
+
+	FeeAssetID   ids.ID      // The asset the subnet declared for the fees of its chains, if any
+	FeeCollector ids.ShortID // The address the subnet declared to receive the fees of its chains, if any
+
+	SubnetVMConfig []byte // The latest config the subnet published for the VMs of its chains, if any
 }
 
 type chain struct {
@@ -120,6 +180,29 @@ type chain struct {
 	Engine  common.Engine
 	Handler handler.Handler
 	Beacons validators.Set
+	// ProposerVM is nil if the chain doesn't run snowman consensus
+	ProposerVM *proposervm.VM
+	// ConfigurableVM is nil if the VM of the chain doesn't implement
+	// common.SubnetVMConfigurable
+	ConfigurableVM common.SubnetVMConfigurable
+	// FeeEstimator is nil if the VM of the chain doesn't implement
+	// common.FeeEstimator
+	FeeEstimator common.FeeEstimator
+	// AvalancheEngine is nil if the chain doesn't run avalanche consensus
+	AvalancheEngine aveng.Engine
+	// StallWatchdog is nil if the chain doesn't run snowman consensus or if
+	// finality stalls aren't watched for
+	StallWatchdog *stallWatchdog
+}
+
+// chainInstance is the state of a created chain that outlives the chain's
+// engine and VM, so that the chain can be restarted after being stopped.
+type chainInstance struct {
+	params ChainParameters
+	// name is the alias the chain's log, metrics and health check are
+	// registered under
+	name string
+	log  logging.Logger
 }
 
 // ChainConfig is configuration settings for the current execution.
@@ -149,6 +232,8 @@ type ManagerConfig struct {
 	Server                      server.Server      // Handles HTTP API calls
 	Keystore                    keystore.Keystore
 	AtomicMemory                *atomic.Memory
+	Evidence                    evidence.Recorder // Records misbehavior observed on chains
+	Drops                       drops.Recorder    // Notified of the messages dropped by the chains
 	AVAXAssetID                 ids.ID
 	XChainID                    ids.ID
 	CriticalChains              ids.Set         // Chains that can't exit gracefully
@@ -163,8 +248,30 @@ type ManagerConfig struct {
 	ShutdownNodeFunc func(exitCode int)
 	MeterVMEnabled   bool // Should each VM be wrapped with a MeterVM
 	Metrics          metrics.MultiGatherer
+	// If enabled, each snowman VM is delayed once it used up its share of the
+	// CPU
+	VMThrottlerConfig throttlevm.Config
+	// If non-nil, accepted snowman blocks are served from this cache
+	DecidedBlocks *cachevm.DecidedBlocks
+	// If non-nil, the headers of snowman chains are served to light clients
+	LightClientServer lightclient.Server
+	// If non-nil, chains follow consensus without building blocks or voting
+	// while it returns true
+	IsReadOnly func() bool
+	// If true, chains never finish bootstrapping, so they only serve
+	// bootstrapping requests
+	BootstrapOnly bool
+	// Fraction of the beacons' stake that must have accepted a container for
+	// bootstrapping to sync to it
+	BootstrapFrontierQuorum float64
 
 	ConsensusGossipFrequency time.Duration
+	// If true, snowman engines batch the queries they send to a validator
+	ConsensusBatchQueriesEnabled bool
+	// If non-zero, snowman chains that have blocks processing but haven't
+	// accepted a block for this long are reported unhealthy, and a
+	// diagnostics bundle is captured
+	FinalityStallTimeout time.Duration
 
 	GossipConfig sender.GossipConfig
 
@@ -177,10 +284,50 @@ type ManagerConfig struct {
 	// containers in an ancestors message it receives.
 	BootstrapAncestorsMaxContainersReceived int
 
-	ApricotPhase4Time            time.Time
+	// Activation times of the network upgrades
+	Upgrades                     *version.UpgradeSchedule
 	ApricotPhase4MinPChainHeight uint64
 
 	ResetProposerVMHeightIndex bool
+
+	// Stop building blocks if another node is detected proposing blocks with
+	// this node's staking key
+	StopProposingOnDuplicateIdentity bool
+
+	// If non-nil, snowman chains timestamp the blocks they build with the
+	// median of the times recently reported by peers
+	PeerTime *timer.PeerTime
+
+	// Clock tells the time of the node. Snowman chains tell time with it, so
+	// that tests can control their proposer windows. If nil, the local clock is
+	// used.
+	Clock *mockable.Clock
+
+	// If non-empty, snowman chains import the blocks of the era archive in
+	// the subdirectory named after their chain ID before bootstrapping
+	EraImportDir string
+
+	// Bytes of jobs that each bootstrapping job queue holds in memory. If 0,
+	// the queues' default budget is used.
+	BootstrapJobsMemoryBudget int
+
+	// If true, the chains make expensive runtime assertions in consensus, the
+	// proposervm and their databases. Violations fail the operation that
+	// caused them.
+	InvariantChecks bool
+
+	// If non-empty, the accepted blocks of this chain are re-executed by a
+	// second instance of its VM before the chain bootstraps. The blocks from
+	// [ReplayFromHeight] on are compared in detail with the stored blocks.
+	ReplayChain      string
+	ReplayFromHeight uint64
+
+	// If [ColdStorageS3] has an endpoint, the accepted blocks of snowman
+	// chains that are more than [ColdStorageDepth] blocks deep are offloaded
+	// to it
+	ColdStorageS3        tieredb.S3Config
+	ColdStorageDepth     uint64
+	ColdStorageCacheSize int
 }
 
 type manager struct {
@@ -203,9 +350,42 @@ type manager struct {
 	// Key: Chain's ID
 	// Value: The chain
 	chains map[ids.ID]handler.Handler
+	// Key: Chain's ID
+	// Value: The ProposerVM of the running snowman chain
+	proposerVMs map[ids.ID]*proposervm.VM
+	// Key: Chain's ID
+	// Value: The VM of the running chain, if it can apply new subnet VM
+	// configs
+	configurableVMs map[ids.ID]common.SubnetVMConfigurable
+	// Key: Chain's ID
+	// Value: The VM of the running chain, if it estimates fees
+	feeEstimators map[ids.ID]common.FeeEstimator
+	// Key: Chain's ID
+	// Value: The engine of the running avalanche chain
+	avalancheEngines map[ids.ID]aveng.Engine
+	// Key: Chain's ID
+	// Value: The finality stall watchdog of the running snowman chain
+	stallWatchdogs map[ids.ID]*stallWatchdog
+	// Key: Chain's ID
+	// Value: The chain, whether it's running or stopped
+	instances map[ids.ID]*chainInstance
+
+	// Serializes stopping and starting chains
+	stopLock sync.Mutex
 
 	// snowman++ related interface to allow validators retrival
 	validatorState validators.State
+
+	quotasLock sync.Mutex
+	// Key: Subnet's ID
+	// Value: The disk quota shared by the subnet's chains
+	diskQuotas map[ids.ID]*quotadb.Quota
+	// Key: Chain's ID
+	// Value: The chain's database, counted against its subnet's disk quota
+	chainQuotaDBs map[ids.ID]*quotadb.Database
+	// Key: Subnet's ID
+	// Value: The accepted blocks of the subnet's chains
+	subnetDecidedBlocks map[ids.ID]*cachevm.DecidedBlocks
 }
 
 // New returns a new Manager
@@ -215,6 +395,17 @@ func New(config *ManagerConfig) Manager {
 		ManagerConfig: *config,
 		subnets:       make(map[ids.ID]Subnet),
 		chains:        make(map[ids.ID]handler.Handler),
+		proposerVMs:   make(map[ids.ID]*proposervm.VM),
+		instances:     make(map[ids.ID]*chainInstance),
+
+		configurableVMs:  make(map[ids.ID]common.SubnetVMConfigurable),
+		feeEstimators:    make(map[ids.ID]common.FeeEstimator),
+		avalancheEngines: make(map[ids.ID]aveng.Engine),
+		stallWatchdogs:   make(map[ids.ID]*stallWatchdog),
+
+		diskQuotas:          make(map[ids.ID]*quotadb.Quota),
+		chainQuotaDBs:       make(map[ids.ID]*quotadb.Database),
+		subnetDecidedBlocks: make(map[ids.ID]*cachevm.DecidedBlocks),
 	}
 }
 
@@ -257,11 +448,28 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 		chainParams.VMAlias,
 	)
 
+	if err := m.startChain(chainParams); err != nil {
+		if m.CriticalChains.Contains(chainParams.ID) {
+			// Shut down if we fail to create a required chain (i.e. X, P or C)
+			m.Log.Fatal("error creating required chain %s: %s", chainParams.ID, err)
+			go m.ShutdownNodeFunc(1)
+			return
+		}
+		m.Log.Error("error creating chain %s: %s", chainParams.ID, err)
+	}
+}
+
+// startChain builds the chain and starts processing its messages. If the chain
+// was stopped, it's restarted with the same name and aliases.
+func (m *manager) startChain(chainParams ChainParameters) error {
+	m.chainsLock.Lock()
+	_, restarting := m.instances[chainParams.ID]
 	sb, exists := m.subnets[chainParams.SubnetID]
 	if !exists {
 		sb = newSubnet()
 		m.subnets[chainParams.SubnetID] = sb
 	}
+	m.chainsLock.Unlock()
 
 	sb.addChain(chainParams.ID)
 
@@ -273,22 +481,40 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 	chain, err := m.buildChain(chainParams, sb)
 	if err != nil {
 		sb.removeChain(chainParams.ID)
-		if m.CriticalChains.Contains(chainParams.ID) {
-			// Shut down if we fail to create a required chain (i.e. X, P or C)
-			m.Log.Fatal("error creating required chain %s: %s", chainParams.ID, err)
-			go m.ShutdownNodeFunc(1)
-			return
-		}
-		m.Log.Error("error creating chain %s: %s", chainParams.ID, err)
-		return
+		return err
 	}
 
+	ctx := chain.Handler.Context()
 	m.chainsLock.Lock()
 	m.chains[chainParams.ID] = chain.Handler
+	if chain.ProposerVM != nil {
+		m.proposerVMs[chainParams.ID] = chain.ProposerVM
+	}
+	if chain.ConfigurableVM != nil {
+		m.configurableVMs[chainParams.ID] = chain.ConfigurableVM
+	}
+	if chain.FeeEstimator != nil {
+		m.feeEstimators[chainParams.ID] = chain.FeeEstimator
+	}
+	if chain.AvalancheEngine != nil {
+		m.avalancheEngines[chainParams.ID] = chain.AvalancheEngine
+	}
+	if chain.StallWatchdog != nil {
+		m.stallWatchdogs[chainParams.ID] = chain.StallWatchdog
+	}
+	if !restarting {
+		m.instances[chainParams.ID] = &chainInstance{
+			params: chainParams,
+			name:   chain.Name,
+			log:    ctx.Log,
+		}
+	}
 	m.chainsLock.Unlock()
 
 	// Associate the newly created chain with its default alias
-	m.Log.AssertNoError(m.Alias(chainParams.ID, chainParams.ID.String()))
+	if !restarting {
+		m.Log.AssertNoError(m.Alias(chainParams.ID, chainParams.ID.String()))
+	}
 
 	// Notify those that registered to be notified when a new chain is created
 	m.notifyRegistrants(chain.Name, chain.Engine)
@@ -298,7 +524,6 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 	// handler is started.
 	m.ManagerConfig.Router.AddChain(chain.Handler)
 
-	ctx := chain.Handler.Context()
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
@@ -320,6 +545,109 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 			chain.Handler.StopWithError(err)
 		}
 	}
+	return nil
+}
+
+func (m *manager) StopChain(chainID ids.ID) error {
+	m.stopLock.Lock()
+	defer m.stopLock.Unlock()
+
+	// The node shuts down when a critical chain stops
+	if m.CriticalChains.Contains(chainID) {
+		return errStopCriticalChain
+	}
+
+	m.chainsLock.Lock()
+	chain, running := m.chains[chainID]
+	instance, exists := m.instances[chainID]
+	if !exists {
+		m.chainsLock.Unlock()
+		return errUnknownChainID
+	}
+	if !running {
+		m.chainsLock.Unlock()
+		return errChainStopped
+	}
+	delete(m.chains, chainID)
+	delete(m.proposerVMs, chainID)
+	delete(m.configurableVMs, chainID)
+	delete(m.feeEstimators, chainID)
+	delete(m.avalancheEngines, chainID)
+	delete(m.stallWatchdogs, chainID)
+	sb := m.subnets[instance.params.SubnetID]
+	m.chainsLock.Unlock()
+
+	m.Log.Info("stopping chain %s", chainID)
+
+	// Shutting down the handler shuts down the chain's engine and VM
+	m.ManagerConfig.Router.RemoveChain(chainID)
+	chain.Context().SetState(snow.Stopped)
+
+	// Other chains in the subnet shouldn't wait for this chain to bootstrap
+	sb.Bootstrapped(chainID)
+	m.deregisterChain(chainID, instance.name)
+	return nil
+}
+
+func (m *manager) StartChain(chainID ids.ID) error {
+	m.stopLock.Lock()
+	defer m.stopLock.Unlock()
+
+	m.chainsLock.Lock()
+	_, running := m.chains[chainID]
+	instance, exists := m.instances[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return errUnknownChainID
+	}
+	if running {
+		return errChainRunning
+	}
+
+	m.Log.Info("restarting chain %s", chainID)
+	if err := m.startChain(instance.params); err != nil {
+		// Remove anything the failed attempt registered so that starting the
+		// chain can be retried
+		m.deregisterChain(chainID, instance.name)
+		return err
+	}
+	return nil
+}
+
+// deregisterChain removes the registrations made while building the chain
+// named [name] so that it can be built again.
+func (m *manager) deregisterChain(chainID ids.ID, name string) {
+	chainNamespace := fmt.Sprintf("%s_%s", constants.PlatformName, name)
+	m.Metrics.Deregister(chainNamespace)
+	m.Metrics.Deregister(fmt.Sprintf("%s_vm", chainNamespace))
+	m.TimeoutManager.DeregisterChain(chainID)
+	if err := m.ConsensusEvents.DeregisterChain(chainID, "gossip"); err != nil {
+		m.Log.Debug("couldn't deregister gossip of chain %s: %s", chainID, err)
+	}
+	if m.LightClientServer != nil {
+		m.LightClientServer.DeregisterChain(chainID)
+	}
+	m.Health.DeregisterHealthCheck(name)
+	m.Health.DeregisterHealthCheck(finalityCheckName(name))
+}
+
+// finalityCheckName returns the name the finality stall health check of the
+// chain named [name] is registered under
+func finalityCheckName(name string) string {
+	return fmt.Sprintf("%s-finality", name)
+}
+
+// chainName returns the name the chain's log, metrics and health check are
+// registered under. Chains keep the name they were created with when they're
+// restarted, even if their primary alias changed.
+func (m *manager) chainName(chainID ids.ID) string {
+	m.chainsLock.Lock()
+	instance, exists := m.instances[chainID]
+	m.chainsLock.Unlock()
+	if exists {
+		return instance.name
+	}
+	return m.PrimaryAliasOrDefault(chainID)
 }
 
 // Create a chain
@@ -332,12 +660,21 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	if chainParams.ID != constants.PlatformChainID && vmID == constants.PlatformVMID {
 		return nil, errCreatePlatformVM
 	}
-	primaryAlias := m.PrimaryAliasOrDefault(chainParams.ID)
+	primaryAlias := m.chainName(chainParams.ID)
 
-	// Create the log and context of the chain
-	chainLog, err := m.LogFactory.MakeChain(primaryAlias)
-	if err != nil {
-		return nil, fmt.Errorf("error while creating chain's log %w", err)
+	// Create the log and context of the chain. The log of a restarted chain
+	// is reused, as the log factory doesn't allow it to be created again.
+	m.chainsLock.Lock()
+	instance, restarting := m.instances[chainParams.ID]
+	m.chainsLock.Unlock()
+	var chainLog logging.Logger
+	if restarting {
+		chainLog = instance.log
+	} else {
+		chainLog, err = m.LogFactory.MakeChain(primaryAlias)
+		if err != nil {
+			return nil, fmt.Errorf("error while creating chain's log %w", err)
+		}
 	}
 
 	consensusMetrics := prometheus.NewRegistry()
@@ -362,12 +699,21 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			XChainID:    m.XChainID,
 			AVAXAssetID: m.AVAXAssetID,
 
+			FeeAssetID:     chainParams.FeeAssetID,
+			FeeCollector:   chainParams.FeeCollector,
+			SubnetVMConfig: chainParams.SubnetVMConfig,
+
+			InvariantChecks: m.InvariantChecks,
+			Clock:           m.Clock,
+
 			Log:          chainLog,
 			Keystore:     m.Keystore.NewBlockchainKeyStore(chainParams.ID),
 			SharedMemory: m.AtomicMemory.NewSharedMemory(chainParams.ID),
 			BCLookup:     m,
 			SNLookup:     m,
 			Metrics:      vmMetrics,
+			Evidence:     m.Evidence,
+			Drops:        m.Drops,
 
 			ValidatorState:    m.validatorState,
 			StakingCertLeaf:   m.StakingCert.Leaf,
@@ -401,28 +747,17 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	}
 	// TODO: Shutdown VM if an error occurs
 
-	fxs := make([]*common.Fx, len(chainParams.FxAliases))
-	for i, fxAlias := range chainParams.FxAliases {
-		fxID, err := m.VMManager.Lookup(fxAlias)
-		if err != nil {
-			return nil, fmt.Errorf("error while looking up Fx: %w", err)
-		}
+	fxs, err := m.createFxs(ctx.Context, chainParams.FxAliases)
+	if err != nil {
+		return nil, err
+	}
 
-		// Get a factory for the fx we want to use on our chain
-		fxFactory, err := m.VMManager.GetFactory(fxID)
+	// The chain being replayed is also run by a second instance of its VM
+	var replay *replayTarget
+	if m.isReplayChain(chainParams.ID) {
+		replay, err = m.newReplayTarget(ctx.Context, vmFactory, chainParams.FxAliases)
 		if err != nil {
-			return nil, fmt.Errorf("error while getting fxFactory: %w", err)
-		}
-
-		fx, err := fxFactory.New(ctx.Context)
-		if err != nil {
-			return nil, fmt.Errorf("error while creating fx: %w", err)
-		}
-
-		// Create the fx
-		fxs[i] = &common.Fx{
-			ID: fxID,
-			Fx: fx,
+			return nil, fmt.Errorf("error while creating the replay vm: %w", err)
 		}
 	}
 
@@ -453,6 +788,9 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	var chain *chain
 	switch vm := vm.(type) {
 	case vertex.DAGVM:
+		if replay != nil {
+			ctx.Log.Warn("only snowman chains can be replayed")
+		}
 		chain, err = m.createAvalancheChain(
 			ctx,
 			chainParams.GenesisData,
@@ -478,6 +816,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			consensusParams.Parameters,
 			bootstrapWeight,
 			sb,
+			replay,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error while creating new snowman vm %w", err)
@@ -485,6 +824,12 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	default:
 		return nil, errUnknownVMType
 	}
+	if configurableVM, ok := vm.(common.SubnetVMConfigurable); ok {
+		chain.ConfigurableVM = configurableVM
+	}
+	if feeEstimator, ok := vm.(common.FeeEstimator); ok {
+		chain.FeeEstimator = feeEstimator
+	}
 
 	// Register the chain with the timeout manager
 	if err := m.TimeoutManager.RegisterChain(ctx); err != nil {
@@ -494,6 +839,35 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	return chain, nil
 }
 
+// createFxs returns new instances of the feature extensions [fxAliases]
+func (m *manager) createFxs(ctx *snow.Context, fxAliases []string) ([]*common.Fx, error) {
+	fxs := make([]*common.Fx, len(fxAliases))
+	for i, fxAlias := range fxAliases {
+		fxID, err := m.VMManager.Lookup(fxAlias)
+		if err != nil {
+			return nil, fmt.Errorf("error while looking up Fx: %w", err)
+		}
+
+		// Get a factory for the fx we want to use on our chain
+		fxFactory, err := m.VMManager.GetFactory(fxID)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting fxFactory: %w", err)
+		}
+
+		fx, err := fxFactory.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error while creating fx: %w", err)
+		}
+
+		// Create the fx
+		fxs[i] = &common.Fx{
+			ID: fxID,
+			Fx: fx,
+		}
+	}
+	return fxs, nil
+}
+
 func (m *manager) AddRegistrant(r Registrant) { m.registrants = append(m.registrants, r) }
 
 func (m *manager) unblockChains() {
@@ -520,11 +894,14 @@ func (m *manager) createAvalancheChain(
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
-	meterDBManager, err := m.DBManager.NewMeterDBManager("db", ctx.Registerer)
+	chainDBManager, err := m.chainDBManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefixDBManager, err := chainDBManager.NewMeterDBManager("db", ctx.Registerer)
 	if err != nil {
 		return nil, err
 	}
-	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
 	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
 
 	db := prefixDBManager.Current()
@@ -540,6 +917,10 @@ func (m *manager) createAvalancheChain(
 	if err != nil {
 		return nil, err
 	}
+	if m.BootstrapJobsMemoryBudget > 0 {
+		vtxBlocker.SetMemoryBudget(m.BootstrapJobsMemoryBudget)
+		txBlocker.SetMemoryBudget(m.BootstrapJobsMemoryBudget)
+	}
 
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
@@ -584,7 +965,7 @@ func (m *manager) createAvalancheChain(
 			VM:                  vm,
 			DB:                  vertexDB,
 			Log:                 ctx.Log,
-			XChainMigrationTime: version.GetXChainMigrationTime(ctx.NetworkID),
+			XChainMigrationTime: m.Upgrades.ActivationTime(version.XChainMigration),
 		},
 	)
 	if err := vm.Initialize(
@@ -617,6 +998,10 @@ func (m *manager) createAvalancheChain(
 	if err != nil {
 		return nil, fmt.Errorf("error initializing network handler: %w", err)
 	}
+	handler, err = m.boundHandler(ctx, handler)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't bound the pending messages of the handler: %w", err)
+	}
 
 	commonCfg := common.Config{
 		Ctx:                            ctx,
@@ -624,12 +1009,13 @@ func (m *manager) createAvalancheChain(
 		Beacons:                        beacons,
 		SampleK:                        sampleK,
 		StartupAlpha:                   (3*bootstrapWeight + 3) / 4,
-		Alpha:                          bootstrapWeight/2 + 1, // must be > 50%
+		Alpha:                          common.QuorumWeight(bootstrapWeight, m.BootstrapFrontierQuorum),
 		Sender:                         sender,
 		Subnet:                         sb,
 		Timer:                          handler,
 		RetryBootstrap:                 m.RetryBootstrap,
 		RetryBootstrapWarnFrequency:    m.RetryBootstrapWarnFrequency,
+		BootstrapOnly:                  m.BootstrapOnly,
 		MaxTimeGetAncestors:            m.BootstrapMaxTimeGetAncestors,
 		AncestorsMaxContainersSent:     m.BootstrapAncestorsMaxContainersSent,
 		AncestorsMaxContainersReceived: m.BootstrapAncestorsMaxContainersReceived,
@@ -673,6 +1059,7 @@ func (m *manager) createAvalancheChain(
 		Validators:    vdrs,
 		Params:        consensusParams,
 		Consensus:     &avcon.Topological{},
+		IsReadOnly:    m.IsReadOnly,
 	}
 	engine, err := aveng.New(engineConfig)
 	if err != nil {
@@ -681,7 +1068,7 @@ func (m *manager) createAvalancheChain(
 	handler.SetConsensus(engine)
 
 	// Register health check for this chain
-	chainAlias := m.PrimaryAliasOrDefault(ctx.ChainID)
+	chainAlias := m.chainName(ctx.ChainID)
 
 	// Grab the context lock before calling the chain's health check
 	check := health.CheckerFunc(func() (interface{}, error) {
@@ -701,9 +1088,10 @@ func (m *manager) createAvalancheChain(
 	}
 
 	return &chain{
-		Name:    chainAlias,
-		Engine:  engine,
-		Handler: handler,
+		Name:            chainAlias,
+		Engine:          engine,
+		Handler:         handler,
+		AvalancheEngine: engine,
 	}, nil
 }
 
@@ -718,24 +1106,32 @@ func (m *manager) createSnowmanChain(
 	consensusParams snowball.Parameters,
 	bootstrapWeight uint64,
 	sb Subnet,
+	replay *replayTarget,
 ) (*chain, error) {
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
-	meterDBManager, err := m.DBManager.NewMeterDBManager("db", ctx.Registerer)
+	chainDBManager, err := m.chainDBManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefixDBManager, err := chainDBManager.NewMeterDBManager("db", ctx.Registerer)
 	if err != nil {
 		return nil, err
 	}
-	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
 	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
 
 	db := prefixDBManager.Current()
 	bootstrappingDB := prefixdb.New([]byte("bs"), db.Database)
+	processingDB := prefixdb.New([]byte("processing"), db.Database)
 
 	blocked, err := queue.NewWithMissing(bootstrappingDB, "block", ctx.Registerer)
 	if err != nil {
 		return nil, err
 	}
+	if m.BootstrapJobsMemoryBudget > 0 {
+		blocked.SetMemoryBudget(m.BootstrapJobsMemoryBudget)
+	}
 
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
@@ -774,6 +1170,16 @@ func (m *manager) createSnowmanChain(
 			// Initialize the validator state for future chains.
 			m.validatorState = validators.NewLockedState(&ctx.Lock, valState)
 
+			if m.LightClientServer != nil {
+				prover, ok := vm.(lightclient.ValidatorSetProver)
+				if !ok {
+					return nil, fmt.Errorf("expected lightclient.ValidatorSetProver but got %T", vm)
+				}
+				if err := m.LightClientServer.SetValidatorSetProver(lightclient.NewLockedValidatorSetProver(&ctx.Lock, prover)); err != nil {
+					return nil, err
+				}
+			}
+
 			// Notice that this context is left unlocked. This is because the
 			// lock will already be held when accessing these values on the
 			// P-chain.
@@ -790,12 +1196,48 @@ func (m *manager) createSnowmanChain(
 		return nil, fmt.Errorf("error while fetching chain config: %w", err)
 	}
 
+	// Cache the accepted blocks of both the inner VM and the ProposerVM, so
+	// that both the ProposerVM and the engine can avoid repeated lookups.
+	decidedBlocks, err := m.decidedBlocks(ctx.SubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create the decided block cache: %w", err)
+	}
+	if decidedBlocks != nil {
+		vm = cachevm.NewBlockVM(vm, decidedBlocks)
+	}
+
+	coldStorage, err := m.coldStorageConfig(ctx.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create the cold storage: %w", err)
+	}
+
+	var blockTimeSource proposervm.TimeSource
+	if m.PeerTime != nil {
+		blockTimeSource = m.PeerTime
+	}
+
 	// enable ProposerVM on this VM
-	vm = proposervm.New(vm, m.ApricotPhase4Time, m.ApricotPhase4MinPChainHeight, m.ResetProposerVMHeightIndex)
+	proposerVM := proposervm.New(
+		vm,
+		m.Upgrades.ActivationTime(version.ApricotPhase4),
+		m.ApricotPhase4MinPChainHeight,
+		m.ResetProposerVMHeightIndex,
+		m.StopProposingOnDuplicateIdentity,
+		coldStorage,
+		blockTimeSource,
+	)
+	vm = proposerVM
+
+	if decidedBlocks != nil {
+		vm = cachevm.NewBlockVM(vm, decidedBlocks)
+	}
 
 	if m.MeterVMEnabled {
 		vm = metervm.NewBlockVM(vm)
 	}
+	if m.VMThrottlerConfig.Enabled() {
+		vm = throttlevm.NewBlockVM(vm, m.VMThrottlerConfig)
+	}
 	if err := vm.Initialize(
 		ctx.Context,
 		vmDBManager,
@@ -809,6 +1251,20 @@ func (m *manager) createSnowmanChain(
 		return nil, err
 	}
 
+	if m.LightClientServer != nil {
+		if err := m.LightClientServer.RegisterChain(ctx.ChainID, ctx.SubnetID, proposerVM); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.importEra(ctx, vm); err != nil {
+		return nil, fmt.Errorf("couldn't import era archive: %w", err)
+	}
+
+	if replay != nil {
+		m.replay(ctx, replay, proposerVM, prefixDBManager, genesisData, chainConfig)
+	}
+
 	sampleK := consensusParams.K
 	if uint64(sampleK) > bootstrapWeight {
 		sampleK = int(bootstrapWeight)
@@ -826,6 +1282,10 @@ func (m *manager) createSnowmanChain(
 	if err != nil {
 		return nil, fmt.Errorf("couldn't initialize message handler: %w", err)
 	}
+	handler, err = m.boundHandler(ctx, handler)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't bound the pending messages of the handler: %w", err)
+	}
 
 	commonCfg := common.Config{
 		Ctx:                            ctx,
@@ -833,12 +1293,13 @@ func (m *manager) createSnowmanChain(
 		Beacons:                        beacons,
 		SampleK:                        sampleK,
 		StartupAlpha:                   (3*bootstrapWeight + 3) / 4,
-		Alpha:                          bootstrapWeight/2 + 1, // must be > 50%
+		Alpha:                          common.QuorumWeight(bootstrapWeight, m.BootstrapFrontierQuorum),
 		Sender:                         sender,
 		Subnet:                         sb,
 		Timer:                          handler,
 		RetryBootstrap:                 m.RetryBootstrap,
 		RetryBootstrapWarnFrequency:    m.RetryBootstrapWarnFrequency,
+		BootstrapOnly:                  m.BootstrapOnly,
 		MaxTimeGetAncestors:            m.BootstrapMaxTimeGetAncestors,
 		AncestorsMaxContainersSent:     m.BootstrapAncestorsMaxContainersSent,
 		AncestorsMaxContainersReceived: m.BootstrapAncestorsMaxContainersReceived,
@@ -880,6 +1341,10 @@ func (m *manager) createSnowmanChain(
 		Validators:    vdrs,
 		Params:        consensusParams,
 		Consensus:     &smcon.Topological{},
+		IsReadOnly:    m.IsReadOnly,
+		ProcessingDB:  processingDB,
+		BatchQueries:  m.ConsensusBatchQueriesEnabled,
+		Timer:         handler,
 	}
 	engine, err := smeng.New(engineConfig)
 	if err != nil {
@@ -888,7 +1353,7 @@ func (m *manager) createSnowmanChain(
 	handler.SetConsensus(engine)
 
 	// Register health checks
-	chainAlias := m.PrimaryAliasOrDefault(ctx.ChainID)
+	chainAlias := m.chainName(ctx.ChainID)
 
 	check := health.CheckerFunc(func() (interface{}, error) {
 		ctx.Lock.Lock()
@@ -906,10 +1371,31 @@ func (m *manager) createSnowmanChain(
 		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
 	}
 
+	var watchdog *stallWatchdog
+	if m.FinalityStallTimeout > 0 {
+		watchdog = newStallWatchdog(ctx.ChainID, m.FinalityStallTimeout, engine, proposerVM, ctx.Log)
+		finalityCheck := health.CheckerFunc(func() (interface{}, error) {
+			ctx.Lock.Lock()
+			defer ctx.Lock.Unlock()
+			if ctx.GetState() != snow.NormalOp {
+				// Blocks are only accepted by consensus once the chain has
+				// bootstrapped
+				return nil, nil
+			}
+			return watchdog.HealthCheck()
+		})
+		checkName := finalityCheckName(chainAlias)
+		if err := m.Health.RegisterHealthCheck(checkName, finalityCheck); err != nil {
+			return nil, fmt.Errorf("couldn't add finality health check for chain %s: %w", chainAlias, err)
+		}
+	}
+
 	return &chain{
-		Name:    chainAlias,
-		Engine:  engine,
-		Handler: handler,
+		Name:          chainAlias,
+		Engine:        engine,
+		Handler:       handler,
+		ProposerVM:    proposerVM,
+		StallWatchdog: watchdog,
 	}, nil
 }
 
@@ -924,6 +1410,159 @@ func (m *manager) SubnetID(chainID ids.ID) (ids.ID, error) {
 	return chain.Context().SubnetID, nil
 }
 
+func (m *manager) RejectedBlocks(chainID ids.ID) ([]proposervm.RejectedBlock, error) {
+	m.chainsLock.Lock()
+	_, running := m.chains[chainID]
+	proposerVM, isSnowman := m.proposerVMs[chainID]
+	m.chainsLock.Unlock()
+
+	if !running {
+		return nil, errUnknownChainID
+	}
+	if !isSnowman {
+		return nil, errNotSnowmanChain
+	}
+	return proposerVM.RejectedBlocks(), nil
+}
+
+func (m *manager) ChainVMInfo(chainID ids.ID) (ids.ID, proposervm.InnerVMInfo, error) {
+	m.chainsLock.Lock()
+	_, running := m.chains[chainID]
+	proposerVM, isSnowman := m.proposerVMs[chainID]
+	instance := m.instances[chainID]
+	m.chainsLock.Unlock()
+
+	if !running {
+		return ids.ID{}, proposervm.InnerVMInfo{}, errUnknownChainID
+	}
+	if !isSnowman {
+		return ids.ID{}, proposervm.InnerVMInfo{}, errNotSnowmanChain
+	}
+	vmID, err := m.VMManager.Lookup(instance.params.VMAlias)
+	if err != nil {
+		return ids.ID{}, proposervm.InnerVMInfo{}, err
+	}
+	return vmID, proposerVM.InnerVMInfo(), nil
+}
+
+func (m *manager) SetSubnetVMConfig(subnetID ids.ID, config []byte) {
+	// Chains waiting to be created are created with the new config
+	for i, chainParams := range m.blockedChains {
+		if chainParams.SubnetID == subnetID {
+			m.blockedChains[i].SubnetVMConfig = config
+		}
+	}
+
+	m.chainsLock.Lock()
+	defer m.chainsLock.Unlock()
+
+	for chainID, instance := range m.instances {
+		if instance.params.SubnetID != subnetID {
+			continue
+		}
+		// Restarted chains are initialized with the new config
+		instance.params.SubnetVMConfig = config
+
+		configurableVM, ok := m.configurableVMs[chainID]
+		if !ok {
+			continue
+		}
+		ctx := m.chains[chainID].Context()
+
+		// This is called by the P-chain with its lock held, so the lock of
+		// the chain is grabbed asynchronously to avoid deadlocking with a
+		// chain that is waiting on the P-chain.
+		go func() {
+			ctx.Lock.Lock()
+			defer ctx.Lock.Unlock()
+
+			if ctx.GetState() == snow.Stopped {
+				return
+			}
+			if err := configurableVM.SetSubnetVMConfig(config); err != nil {
+				ctx.Log.Error("couldn't apply the subnet VM config: %s", err)
+				return
+			}
+			ctx.Log.Info("applied a new subnet VM config")
+		}()
+	}
+}
+
+func (m *manager) EstimateFees(chainID ids.ID, txType string, size uint64) (common.FeeEstimate, error) {
+	m.chainsLock.Lock()
+	chain, running := m.chains[chainID]
+	feeEstimator, estimatesFees := m.feeEstimators[chainID]
+	m.chainsLock.Unlock()
+
+	if !running {
+		return common.FeeEstimate{}, errUnknownChainID
+	}
+	if !estimatesFees {
+		return common.FeeEstimate{}, errNoFeeEstimator
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	if ctx.GetState() == snow.Stopped {
+		return common.FeeEstimate{}, errChainStopped
+	}
+	return feeEstimator.EstimateFees(txType, size)
+}
+
+func (m *manager) ConflictGraph(chainID ids.ID) (snowstorm.ConflictGraph, error) {
+	m.chainsLock.Lock()
+	chain, running := m.chains[chainID]
+	engine, isAvalanche := m.avalancheEngines[chainID]
+	m.chainsLock.Unlock()
+
+	if !running {
+		return snowstorm.ConflictGraph{}, errUnknownChainID
+	}
+	if !isAvalanche {
+		return snowstorm.ConflictGraph{}, errNotAvalancheChain
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	switch ctx.GetState() {
+	case snow.Stopped:
+		return snowstorm.ConflictGraph{}, errChainStopped
+	case snow.NormalOp:
+		return engine.ConflictGraph(), nil
+	default:
+		// Consensus is only started once the chain has bootstrapped
+		return snowstorm.ConflictGraph{}, errBootstrapping
+	}
+}
+
+func (m *manager) StallDiagnostics(chainID ids.ID) (*StallDiagnostics, error) {
+	m.chainsLock.Lock()
+	chain, running := m.chains[chainID]
+	watchdog, watched := m.stallWatchdogs[chainID]
+	m.chainsLock.Unlock()
+
+	if !running {
+		return nil, errUnknownChainID
+	}
+	if !watched {
+		return nil, errNoStallWatchdog
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	diagnostics := watchdog.Diagnostics()
+	if diagnostics == nil {
+		return nil, errNotStalled
+	}
+	return diagnostics, nil
+}
+
 func (m *manager) IsBootstrapped(id ids.ID) bool {
 	m.chainsLock.Lock()
 	chain, exists := m.chains[id]
@@ -1018,3 +1657,48 @@ func (m *manager) getChainConfig(id ids.ID) (ChainConfig, error) {
 
 	return ChainConfig{}, nil
 }
+
+// importEra accepts the blocks of the era archive of the chain, if there is
+// one in [m.EraImportDir]
+// Assumes [ctx.Lock] is held
+func (m *manager) importEra(ctx *snow.ConsensusContext, vm block.ChainVM) error {
+	if m.EraImportDir == "" {
+		return nil
+	}
+	dir := filepath.Join(m.EraImportDir, ctx.ChainID.String())
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	archive, err := era.OpenArchive(dir, ctx.ChainID)
+	if err != nil {
+		return err
+	}
+	ctx.Log.Info("importing the blocks of %d segments from %s", archive.NumSegments(), dir)
+	numAccepted, err := era.Import(ctx, vm, archive)
+	if err != nil {
+		return fmt.Errorf("failed after accepting %d blocks: %w", numAccepted, err)
+	}
+	ctx.Log.Info("imported %d blocks from %s", numAccepted, dir)
+	return nil
+}
+
+// coldStorageConfig returns where the proposervm of [chainID] offloads its
+// accepted blocks. The blocks of each chain are stored under their own prefix
+// of [m.ColdStorageS3].
+func (m *manager) coldStorageConfig(chainID ids.ID) (proposervm.ColdStorageConfig, error) {
+	if m.ColdStorageS3.Endpoint == "" {
+		return proposervm.ColdStorageConfig{}, nil
+	}
+	s3Config := m.ColdStorageS3
+	s3Config.Prefix += chainID.String() + "/"
+	store, err := tieredb.NewS3Store(s3Config)
+	if err != nil {
+		return proposervm.ColdStorageConfig{}, err
+	}
+	return proposervm.ColdStorageConfig{
+		Store:     store,
+		Depth:     m.ColdStorageDepth,
+		CacheSize: m.ColdStorageCacheSize,
+	}, nil
+}
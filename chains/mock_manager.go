@@ -5,7 +5,10 @@ package chains
 
 import (
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
 )
 
 var _ Manager = MockManager{}
@@ -22,10 +25,35 @@ func (mm MockManager) Aliases(ids.ID) ([]string, error)    { return nil, nil }
 func (mm MockManager) PrimaryAlias(ids.ID) (string, error) { return "", nil }
 func (mm MockManager) PrimaryAliasOrDefault(ids.ID) string { return "" }
 func (mm MockManager) Alias(ids.ID, string) error          { return nil }
+func (mm MockManager) RemoveAlias(string) error            { return nil }
 func (mm MockManager) RemoveAliases(ids.ID)                {}
 func (mm MockManager) Shutdown()                           {}
 func (mm MockManager) SubnetID(ids.ID) (ids.ID, error)     { return ids.ID{}, nil }
 func (mm MockManager) IsBootstrapped(ids.ID) bool          { return false }
+func (mm MockManager) StopChain(ids.ID) error              { return nil }
+func (mm MockManager) StartChain(ids.ID) error             { return nil }
+
+func (mm MockManager) RejectedBlocks(ids.ID) ([]proposervm.RejectedBlock, error) {
+	return nil, nil
+}
+
+func (mm MockManager) ChainVMInfo(ids.ID) (ids.ID, proposervm.InnerVMInfo, error) {
+	return ids.ID{}, proposervm.InnerVMInfo{}, nil
+}
+
+func (mm MockManager) SetSubnetVMConfig(ids.ID, []byte) {}
+
+func (mm MockManager) EstimateFees(ids.ID, string, uint64) (common.FeeEstimate, error) {
+	return common.FeeEstimate{}, nil
+}
+
+func (mm MockManager) ConflictGraph(ids.ID) (snowstorm.ConflictGraph, error) {
+	return snowstorm.ConflictGraph{}, nil
+}
+
+func (mm MockManager) StallDiagnostics(ids.ID) (*StallDiagnostics, error) {
+	return nil, nil
+}
 
 func (mm MockManager) Lookup(s string) (ids.ID, error) {
 	id, err := ids.FromString(s)
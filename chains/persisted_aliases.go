@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// ChainAliases are the persisted aliases of a chain
+type ChainAliases struct {
+	ChainID ids.ID   `json:"chainID"`
+	Aliases []string `json:"aliases"`
+}
+
+// PersistedAliases gives chains aliases that are persisted, so that they're
+// restored when the node restarts. Persisted aliases are keyed by the alias,
+// and the value is the ID of the aliased chain.
+type PersistedAliases struct {
+	aliaser ids.Aliaser
+	db      database.Database
+
+	// Held while an alias is added or removed, so that the aliaser and the
+	// database don't diverge
+	lock sync.Mutex
+}
+
+func NewPersistedAliases(aliaser ids.Aliaser, db database.Database) *PersistedAliases {
+	return &PersistedAliases{
+		aliaser: aliaser,
+		db:      db,
+	}
+}
+
+// Restore gives the chains the aliases that were persisted. An alias that is
+// already used, e.g. by the genesis, isn't restored.
+func (p *PersistedAliases) Restore(log logging.Logger) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	it := p.db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		alias := string(it.Key())
+		chainID, err := ids.ToID(it.Value())
+		if err != nil {
+			return err
+		}
+		if err := p.aliaser.Alias(chainID, alias); err != nil {
+			log.Warn("couldn't restore alias %q of chain %s: %s", alias, chainID, err)
+		}
+	}
+	return it.Error()
+}
+
+// Alias gives [chainID] the alias [alias] and persists it
+func (p *PersistedAliases) Alias(chainID ids.ID, alias string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if err := p.aliaser.Alias(chainID, alias); err != nil {
+		return err
+	}
+	if err := p.db.Put([]byte(alias), chainID[:]); err != nil {
+		// Don't leave an alias behind that would be lost on restart
+		_ = p.aliaser.RemoveAlias(alias)
+		return err
+	}
+	return nil
+}
+
+// RemoveAlias removes [alias] from the chain it was given to. If [alias] was
+// persisted, it's no longer restored, even if it couldn't be restored this
+// time. Aliases that weren't persisted, such as the genesis aliases, are
+// restored by their own source on restart.
+func (p *PersistedAliases) RemoveAlias(alias string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := []byte(alias)
+	persisted, err := p.db.Has(key)
+	if err != nil {
+		return err
+	}
+	if err := p.aliaser.RemoveAlias(alias); err != nil && !persisted {
+		return err
+	}
+	if !persisted {
+		return nil
+	}
+	return p.db.Delete(key)
+}
+
+// List returns the persisted aliases, by chain, ordered by chain ID and then
+// by alias
+func (p *PersistedAliases) List() ([]ChainAliases, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	it := p.db.NewIterator()
+	defer it.Release()
+
+	aliases := make(map[ids.ID][]string)
+	for it.Next() {
+		chainID, err := ids.ToID(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		aliases[chainID] = append(aliases[chainID], string(it.Key()))
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	chainIDs := make([]ids.ID, 0, len(aliases))
+	for chainID := range aliases {
+		chainIDs = append(chainIDs, chainID)
+	}
+	ids.SortIDs(chainIDs)
+
+	list := make([]ChainAliases, len(chainIDs))
+	for i, chainID := range chainIDs {
+		chainAliases := aliases[chainID]
+		sort.Strings(chainAliases)
+		list[i] = ChainAliases{
+			ChainID: chainID,
+			Aliases: chainAliases,
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestPersistedAliases(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	otherChainID := ids.GenerateTestID()
+	db := memdb.New()
+
+	aliaser := ids.NewAliaser()
+	aliases := NewPersistedAliases(aliaser, db)
+	assert.NoError(aliases.Alias(chainID, "b"))
+	assert.NoError(aliases.Alias(chainID, "a"))
+	assert.NoError(aliases.Alias(otherChainID, "c"))
+	// An alias can't be given twice
+	assert.Error(aliases.Alias(otherChainID, "a"))
+
+	lookedUp, err := aliaser.Lookup("a")
+	assert.NoError(err)
+	assert.Equal(chainID, lookedUp)
+
+	assert.NoError(aliases.RemoveAlias("c"))
+	_, err = aliaser.Lookup("c")
+	assert.Error(err)
+	assert.Error(aliases.RemoveAlias("c"))
+
+	list, err := aliases.List()
+	assert.NoError(err)
+	assert.Equal([]ChainAliases{{ChainID: chainID, Aliases: []string{"a", "b"}}}, list)
+
+	// After a restart, the persisted aliases are restored, except for the ones
+	// that are already used
+	aliaser = ids.NewAliaser()
+	assert.NoError(aliaser.Alias(otherChainID, "b"))
+	aliases = NewPersistedAliases(aliaser, db)
+	assert.NoError(aliases.Restore(logging.NoLog{}))
+
+	lookedUp, err = aliaser.Lookup("a")
+	assert.NoError(err)
+	assert.Equal(chainID, lookedUp)
+	lookedUp, err = aliaser.Lookup("b")
+	assert.NoError(err)
+	assert.Equal(otherChainID, lookedUp)
+	_, err = aliaser.Lookup("c")
+	assert.Error(err)
+
+	// An alias that couldn't be restored can still be forgotten
+	assert.NoError(aliases.RemoveAlias("b"))
+	list, err = aliases.List()
+	assert.NoError(err)
+	assert.Equal([]ChainAliases{{ChainID: chainID, Aliases: []string{"a"}}}, list)
+}
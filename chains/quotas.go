@@ -0,0 +1,207 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/checkdb"
+	"github.com/ava-labs/avalanchego/database/quotadb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/networking/handler"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/cachevm"
+
+	dbManager "github.com/ava-labs/avalanchego/database/manager"
+)
+
+var (
+	errNegativeQuota = errors.New("resource quotas can't be negative")
+
+	_ handler.Handler = &quotaHandler{}
+)
+
+// ResourceQuotas bound the resources that the chains of a subnet may use, so
+// that a misbehaving subnet can't exhaust the resources of a node that
+// validates many subnets. A zero quota leaves the resource unbounded.
+type ResourceQuotas struct {
+	// MaxPendingMessages is the number of messages that may wait to be
+	// handled by each chain of the subnet. Once it's reached, the requests
+	// and gossip sent to the chain are dropped. Responses and internal
+	// messages are always queued, as the chain waits for them.
+	MaxPendingMessages int `json:"maxPendingMessages"`
+
+	// MaxDecidedBlocks is the number of accepted blocks of the subnet's
+	// snowman chains that are cached. If set, the subnet's chains share their
+	// own cache of this size rather than the cache of the node.
+	MaxDecidedBlocks int `json:"maxDecidedBlocks"`
+
+	// MaxDiskBytes is the number of bytes of keys and values that the chains
+	// of the subnet may store in the current database. Once it's reached, the
+	// writes that would store more fail.
+	MaxDiskBytes uint64 `json:"maxDiskBytes"`
+}
+
+// Verify returns nil if the quotas are valid
+func (q *ResourceQuotas) Verify() error {
+	if q.MaxPendingMessages < 0 || q.MaxDecidedBlocks < 0 {
+		return errNegativeQuota
+	}
+	return nil
+}
+
+// subnetQuotas returns the resource quotas of [subnetID]. The primary network
+// is never bounded.
+func (m *manager) subnetQuotas(subnetID ids.ID) ResourceQuotas {
+	if subnetID == constants.PrimaryNetworkID {
+		return ResourceQuotas{}
+	}
+	return m.SubnetConfigs[subnetID].ResourceQuotas
+}
+
+// chainDBManager returns the databases of the chain of [ctx], prefixed by the
+// chain's ID. If the chain's subnet has a disk quota, the writes to the
+// current database are counted against it. If invariant checks are enabled,
+// the writes to the current database are read back.
+func (m *manager) chainDBManager(ctx *snow.ConsensusContext) (dbManager.Manager, error) {
+	chainDBManager, err := m.quotaDBManager(ctx)
+	if err != nil || !m.InvariantChecks {
+		return chainDBManager, err
+	}
+	return replaceCurrentDB(chainDBManager, checkdb.New(chainDBManager.Current().Database))
+}
+
+// quotaDBManager returns the databases of the chain of [ctx], prefixed by the
+// chain's ID, with the disk quota of the chain's subnet applied
+func (m *manager) quotaDBManager(ctx *snow.ConsensusContext) (dbManager.Manager, error) {
+	chainDBManager := m.DBManager.NewPrefixDBManager(ctx.ChainID[:])
+	maxDiskBytes := m.subnetQuotas(ctx.SubnetID).MaxDiskBytes
+	if maxDiskBytes == 0 {
+		return chainDBManager, nil
+	}
+
+	m.quotasLock.Lock()
+	defer m.quotasLock.Unlock()
+
+	// The database of a chain is kept when the chain stops, so that its
+	// stored bytes are only added to the quota once.
+	quotaDB, ok := m.chainQuotaDBs[ctx.ChainID]
+	if !ok {
+		quota, ok := m.diskQuotas[ctx.SubnetID]
+		if !ok {
+			quota = quotadb.NewQuota(maxDiskBytes)
+			m.diskQuotas[ctx.SubnetID] = quota
+		}
+		var err error
+		quotaDB, err = quotadb.New(quota, chainDBManager.Current().Database)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't apply the disk quota: %w", err)
+		}
+		m.chainQuotaDBs[ctx.ChainID] = quotaDB
+		ctx.Log.Info("the chains of subnet %s store %d bytes out of a quota of %d bytes", ctx.SubnetID, quota.Size(), maxDiskBytes)
+	}
+	return replaceCurrentDB(chainDBManager, quotaDB)
+}
+
+// replaceCurrentDB returns the databases of [manager], with [db] in place of
+// the current database
+func replaceCurrentDB(manager dbManager.Manager, db database.Database) (dbManager.Manager, error) {
+	dbs := manager.GetDatabases()
+	newDBs := make([]*dbManager.VersionedDatabase, len(dbs))
+	copy(newDBs, dbs)
+	newDBs[0] = &dbManager.VersionedDatabase{
+		Database: db,
+		Version:  dbs[0].Version,
+	}
+	return dbManager.NewManagerFromDBs(newDBs)
+}
+
+// decidedBlocks returns the cache of the accepted blocks of the chains of
+// [subnetID], or nil if they aren't cached
+func (m *manager) decidedBlocks(subnetID ids.ID) (*cachevm.DecidedBlocks, error) {
+	maxDecidedBlocks := m.subnetQuotas(subnetID).MaxDecidedBlocks
+	if maxDecidedBlocks == 0 {
+		return m.DecidedBlocks, nil
+	}
+
+	m.quotasLock.Lock()
+	defer m.quotasLock.Unlock()
+
+	if decidedBlocks, ok := m.subnetDecidedBlocks[subnetID]; ok {
+		return decidedBlocks, nil
+	}
+	registry := prometheus.NewRegistry()
+	if err := m.Metrics.Register(fmt.Sprintf("%s_subnet_%s", constants.PlatformName, subnetID), registry); err != nil {
+		return nil, err
+	}
+	decidedBlocks, err := cachevm.NewDecidedBlocks(maxDecidedBlocks, "decided_block_cache", registry)
+	if err != nil {
+		return nil, err
+	}
+	m.subnetDecidedBlocks[subnetID] = decidedBlocks
+	return decidedBlocks, nil
+}
+
+// boundHandler returns [h] with the pending message quota of the subnet of
+// [ctx] enforced
+func (m *manager) boundHandler(ctx *snow.ConsensusContext, h handler.Handler) (handler.Handler, error) {
+	maxPendingMessages := m.subnetQuotas(ctx.SubnetID).MaxPendingMessages
+	if maxPendingMessages == 0 {
+		return h, nil
+	}
+	dropped := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "handler",
+		Name:      "quota_dropped",
+		Help:      "Number of messages dropped because the subnet's quota of pending messages was reached",
+	})
+	if err := ctx.Registerer.Register(dropped); err != nil {
+		return nil, err
+	}
+	return &quotaHandler{
+		Handler:            h,
+		maxPendingMessages: maxPendingMessages,
+		dropped:            dropped,
+	}, nil
+}
+
+// quotaHandler drops the requests and gossip pushed to its handler while the
+// handler has too many pending messages. The bound isn't exact, as messages
+// may be pushed concurrently.
+type quotaHandler struct {
+	handler.Handler
+	maxPendingMessages int
+	dropped            prometheus.Counter
+}
+
+func (h *quotaHandler) Push(msg message.InboundMessage) {
+	if h.Len() < h.maxPendingMessages || !isUnrequested(msg) {
+		h.Handler.Push(msg)
+		return
+	}
+	ctx := h.Context()
+	ctx.Log.Verbo("dropping %s from %s%s due to the pending message quota of subnet %s",
+		msg.Op(), constants.NodeIDPrefix, msg.NodeID(), ctx.SubnetID)
+	h.dropped.Inc()
+	msg.OnFinishedHandling()
+}
+
+// isUnrequested returns true if [msg] isn't a response to a request of this
+// node, nor a message created by this node
+func isUnrequested(msg message.InboundMessage) bool {
+	op := msg.Op()
+	if _, ok := message.UnrequestedOps[op]; ok {
+		return true
+	}
+	if op != message.Put {
+		return false
+	}
+	requestID, ok := msg.Get(message.RequestID).(uint32)
+	return ok && requestID == constants.GossipMsgRequestID
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/networking/handler"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// queueHandler queues the messages pushed to it without handling them
+type queueHandler struct {
+	handler.Handler
+	ctx    *snow.ConsensusContext
+	queued []message.InboundMessage
+}
+
+func (h *queueHandler) Context() *snow.ConsensusContext { return h.ctx }
+func (h *queueHandler) Len() int                        { return len(h.queued) }
+func (h *queueHandler) Push(msg message.InboundMessage) { h.queued = append(h.queued, msg) }
+
+func TestQuotaHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(err)
+
+	subnetID := ids.GenerateTestID()
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.SubnetID = subnetID
+	m := &manager{
+		ManagerConfig: ManagerConfig{
+			SubnetConfigs: map[ids.ID]SubnetConfig{
+				subnetID: {ResourceQuotas: ResourceQuotas{MaxPendingMessages: 1}},
+			},
+		},
+	}
+	inner := &queueHandler{ctx: ctx}
+	h, err := m.boundHandler(ctx, inner)
+	assert.NoError(err)
+
+	chainID := ctx.ChainID
+	nodeID := ids.GenerateTestShortID()
+	h.Push(mc.InboundPullQuery(chainID, 1, time.Second, ids.GenerateTestID(), nodeID))
+	assert.Len(inner.queued, 1)
+
+	// Once the quota is reached, requests and gossip are dropped
+	h.Push(mc.InboundPullQuery(chainID, 2, time.Second, ids.GenerateTestID(), nodeID))
+	h.Push(mc.InboundPut(chainID, constants.GossipMsgRequestID, ids.GenerateTestID(), []byte{1}, nodeID))
+	assert.Len(inner.queued, 1)
+
+	// Responses and internal messages are always queued
+	h.Push(mc.InboundPut(chainID, 3, ids.GenerateTestID(), []byte{1}, nodeID))
+	h.Push(message.NewInternalBuilder().InternalFailedRequest(message.QueryFailed, nodeID, chainID, 4))
+	assert.Len(inner.queued, 3)
+}
+
+func TestQuotaHandlerUnbounded(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	m := &manager{
+		ManagerConfig: ManagerConfig{
+			SubnetConfigs: map[ids.ID]SubnetConfig{
+				// The primary network is never bounded
+				constants.PrimaryNetworkID: {ResourceQuotas: ResourceQuotas{MaxPendingMessages: 1}},
+			},
+		},
+	}
+	inner := &queueHandler{ctx: ctx}
+	h, err := m.boundHandler(ctx, inner)
+	assert.NoError(err)
+	assert.Equal(inner, h)
+}
+
+func TestResourceQuotasVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	quotas := ResourceQuotas{}
+	assert.NoError(quotas.Verify())
+
+	quotas.MaxPendingMessages = -1
+	assert.ErrorIs(quotas.Verify(), errNegativeQuota)
+}
@@ -0,0 +1,204 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/replay"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
+
+	dbManager "github.com/ava-labs/avalanchego/database/manager"
+)
+
+var (
+	replayDBPrefix = []byte("replay")
+
+	_ common.AppSender = noOpAppSender{}
+)
+
+// replayTarget is the second instance of the VM of the chain being replayed
+type replayTarget struct {
+	vm  block.ChainVM
+	fxs []*common.Fx
+}
+
+// isReplayChain returns true if [chainID] is the chain being replayed
+func (m *manager) isReplayChain(chainID ids.ID) bool {
+	if m.ReplayChain == "" {
+		return false
+	}
+	replayChainID, err := m.Lookup(m.ReplayChain)
+	return err == nil && replayChainID == chainID
+}
+
+func (m *manager) newReplayTarget(ctx *snow.Context, vmFactory vms.Factory, fxAliases []string) (*replayTarget, error) {
+	vmIntf, err := vmFactory.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vm, ok := vmIntf.(block.ChainVM)
+	if !ok {
+		// Only snowman chains can be replayed. The caller warns about it.
+		return &replayTarget{}, nil
+	}
+	fxs, err := m.createFxs(ctx, fxAliases)
+	if err != nil {
+		return nil, err
+	}
+	return &replayTarget{
+		vm:  vm,
+		fxs: fxs,
+	}, nil
+}
+
+// replay re-executes the accepted blocks of [source] with [target] and logs
+// the outcome. Failing to replay the chain doesn't prevent it from running.
+// Assumes [ctx.Lock] is held
+func (m *manager) replay(
+	ctx *snow.ConsensusContext,
+	target *replayTarget,
+	source *proposervm.VM,
+	chainDBManager dbManager.Manager,
+	genesisData []byte,
+	chainConfig ChainConfig,
+) {
+	if target.vm == nil {
+		return
+	}
+	report, err := m.runReplay(ctx, target, source, chainDBManager, genesisData, chainConfig)
+	switch {
+	case err != nil && report == nil:
+		ctx.Log.Error("couldn't replay the chain: %s", err)
+	case err != nil:
+		ctx.Log.Error("stopped replaying the chain after %d blocks: %s", report.NumReplayed, err)
+	case report.Divergence != nil:
+		ctx.Log.Error("replay diverged after %d blocks at %s", report.NumReplayed, report.Divergence)
+	default:
+		ctx.Log.Info("replayed %d blocks up to height %d without divergence", report.NumReplayed, report.ToHeight)
+	}
+}
+
+// runReplay initializes [target] from the genesis, isolated from the rest of
+// the node, and replays the accepted blocks of [source] with it. The state of
+// [target] is written under its own prefix of [chainDBManager] and deleted
+// once the replay is over.
+func (m *manager) runReplay(
+	ctx *snow.ConsensusContext,
+	target *replayTarget,
+	source *proposervm.VM,
+	chainDBManager dbManager.Manager,
+	genesisData []byte,
+	chainConfig ChainConfig,
+) (*replay.Report, error) {
+	replayDBManager := chainDBManager.NewPrefixDBManager(replayDBPrefix)
+	replayDB := replayDBManager.Current().Database
+	// A previous replay may have been interrupted before its state was deleted
+	if err := database.Clear(replayDB, replayDB); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := database.Clear(replayDB, replayDB); err != nil {
+			ctx.Log.Warn("couldn't delete the state of the replay: %s", err)
+		}
+	}()
+
+	// The replay has its own shared memory, so that it can't change the other
+	// chains. Blocks that import atomic UTXOs can't be replayed.
+	sharedMemory := &atomic.Memory{}
+	if err := sharedMemory.Initialize(ctx.Log, memdb.New()); err != nil {
+		return nil, err
+	}
+	replayCtx := &snow.Context{
+		NetworkID: ctx.NetworkID,
+		SubnetID:  ctx.SubnetID,
+		ChainID:   ctx.ChainID,
+		NodeID:    ctx.NodeID,
+
+		XChainID:    ctx.XChainID,
+		AVAXAssetID: ctx.AVAXAssetID,
+
+		Log:          ctx.Log,
+		Keystore:     ctx.Keystore,
+		SharedMemory: sharedMemory.NewSharedMemory(ctx.ChainID),
+		BCLookup:     ctx.BCLookup,
+		SNLookup:     ctx.SNLookup,
+		Metrics:      metrics.NewOptionalGatherer(),
+
+		ValidatorState:    ctx.ValidatorState,
+		StakingCertLeaf:   ctx.StakingCertLeaf,
+		StakingLeafSigner: ctx.StakingLeafSigner,
+	}
+	replayCtx.Lock.Lock()
+	defer replayCtx.Lock.Unlock()
+
+	// Messages from the VM to the engine are dropped
+	toEngine := make(chan common.Message, defaultChannelSize)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-toEngine:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	vm := proposervm.New(
+		target.vm,
+		m.Upgrades.ActivationTime(version.ApricotPhase4),
+		m.ApricotPhase4MinPChainHeight,
+		false,
+		false,
+		proposervm.ColdStorageConfig{},
+		nil,
+	)
+	if err := vm.Initialize(
+		replayCtx,
+		replayDBManager,
+		genesisData,
+		chainConfig.Upgrade,
+		chainConfig.Config,
+		toEngine,
+		target.fxs,
+		noOpAppSender{},
+	); err != nil {
+		return nil, fmt.Errorf("couldn't initialize the replay vm: %w", err)
+	}
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			ctx.Log.Warn("couldn't shut down the replay vm: %s", err)
+		}
+	}()
+
+	// The proposer windows and signatures are only verified once the VM is
+	// done bootstrapping
+	if err := vm.SetState(snow.Bootstrapping); err != nil {
+		return nil, err
+	}
+	if err := vm.SetState(snow.NormalOp); err != nil {
+		return nil, err
+	}
+	return replay.Replay(ctx.Log, source, vm, m.ReplayFromHeight)
+}
+
+// noOpAppSender drops the messages the replay vm sends to other nodes
+type noOpAppSender struct{}
+
+func (noOpAppSender) SendAppRequest(ids.ShortSet, uint32, []byte) error { return nil }
+func (noOpAppSender) SendAppResponse(ids.ShortID, uint32, []byte) error { return nil }
+func (noOpAppSender) SendAppGossip([]byte) error                        { return nil }
+func (noOpAppSender) SendAppGossipSpecific(ids.ShortSet, []byte) error  { return nil }
@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/pprof"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+// StallDiagnostics is the diagnostics bundle captured when a snowman chain
+// stops accepting blocks
+type StallDiagnostics struct {
+	ChainID    ids.ID    `json:"chainID"`
+	DetectedAt time.Time `json:"detectedAt"`
+	// Last time the accepted height was seen advancing, or the chain was seen
+	// without processing blocks
+	LastProgressAt time.Time         `json:"lastProgressAt"`
+	Engine         smeng.Diagnostics `json:"engine"`
+	// Nil if the proposer window couldn't be computed
+	ProposerWindow *proposervm.ProposerWindow `json:"proposerWindow,omitempty"`
+	// Stack traces of all the goroutines of the node
+	Goroutines string `json:"goroutines"`
+}
+
+// stallWatchdog detects when a snowman chain has blocks processing but its
+// accepted height doesn't advance. A chain without processing blocks isn't
+// stalled, it just has nothing to accept.
+// The watchdog is run by the chain's finality health check, so it's only
+// checked as often as the health checks are.
+type stallWatchdog struct {
+	chainID    ids.ID
+	timeout    time.Duration
+	engine     smeng.Engine
+	proposerVM *proposervm.VM
+	log        logging.Logger
+	clock      mockable.Clock
+
+	lastHeight     uint64
+	lastProgressAt time.Time
+	stalled        bool
+	// The bundle captured when the chain last stalled. Nil if the chain never
+	// stalled.
+	diagnostics *StallDiagnostics
+}
+
+func newStallWatchdog(
+	chainID ids.ID,
+	timeout time.Duration,
+	engine smeng.Engine,
+	proposerVM *proposervm.VM,
+	log logging.Logger,
+) *stallWatchdog {
+	return &stallWatchdog{
+		chainID:    chainID,
+		timeout:    timeout,
+		engine:     engine,
+		proposerVM: proposerVM,
+		log:        log,
+	}
+}
+
+// HealthCheck returns an error if the chain is stalled. A diagnostics bundle is
+// captured when a stall is first detected.
+// Assumes the context lock is held and the engine has been started.
+func (w *stallWatchdog) HealthCheck() (interface{}, error) {
+	engineDiagnostics, err := w.engine.Diagnostics()
+	if err != nil {
+		return nil, err
+	}
+
+	now := w.clock.Time()
+	height := uint64(engineDiagnostics.LastAcceptedHeight)
+	numProcessing := len(engineDiagnostics.ProcessingBlocks)
+	if w.lastProgressAt.IsZero() || height != w.lastHeight || numProcessing == 0 {
+		w.lastHeight = height
+		w.lastProgressAt = now
+		w.stalled = false
+	}
+
+	details := map[string]interface{}{
+		"lastAcceptedHeight": height,
+		"lastProgressAt":     w.lastProgressAt,
+		"processingBlocks":   numProcessing,
+	}
+	stalledFor := now.Sub(w.lastProgressAt)
+	if stalledFor < w.timeout {
+		return details, nil
+	}
+
+	if !w.stalled {
+		w.stalled = true
+		w.capture(engineDiagnostics, now)
+		w.log.Warn("chain %s hasn't accepted a block for %s with %d blocks processing. Captured diagnostics are available with admin.getStallDiagnostics",
+			w.chainID, stalledFor, numProcessing)
+	}
+	return details, fmt.Errorf("accepted height %d hasn't advanced for %s with %d blocks processing",
+		height, stalledFor, numProcessing)
+}
+
+// Diagnostics returns the bundle captured when the chain last stalled, or nil
+// if it never stalled.
+// Assumes the context lock is held.
+func (w *stallWatchdog) Diagnostics() *StallDiagnostics { return w.diagnostics }
+
+func (w *stallWatchdog) capture(engineDiagnostics smeng.Diagnostics, now time.Time) {
+	diagnostics := &StallDiagnostics{
+		ChainID:        w.chainID,
+		DetectedAt:     now,
+		LastProgressAt: w.lastProgressAt,
+		Engine:         engineDiagnostics,
+	}
+	goroutines := &bytes.Buffer{}
+	if err := pprof.Lookup("goroutine").WriteTo(goroutines, 2); err != nil {
+		w.log.Debug("couldn't dump the goroutines: %s", err)
+	}
+	diagnostics.Goroutines = goroutines.String()
+
+	if w.proposerVM != nil {
+		window, err := w.proposerVM.ProposerWindow()
+		if err != nil {
+			w.log.Debug("couldn't get the proposer window of chain %s: %s", w.chainID, err)
+		} else {
+			diagnostics.ProposerWindow = &window
+		}
+	}
+	w.diagnostics = diagnostics
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+func TestStallWatchdog(t *testing.T) {
+	assert := assert.New(t)
+
+	engineDiagnostics := smeng.Diagnostics{
+		LastAcceptedHeight: 10,
+		ProcessingBlocks: []smeng.ProcessingBlock{{
+			BlockID: ids.GenerateTestID(),
+			Height:  11,
+		}},
+	}
+	engine := &smeng.EngineTest{}
+	engine.DiagnosticsF = func() (smeng.Diagnostics, error) { return engineDiagnostics, nil }
+
+	timeout := time.Minute
+	chainID := ids.GenerateTestID()
+	watchdog := newStallWatchdog(chainID, timeout, engine, nil, logging.NoLog{})
+	now := time.Unix(1_000_000, 0)
+	watchdog.clock.Set(now)
+
+	_, err := watchdog.HealthCheck()
+	assert.NoError(err)
+
+	// The accepted height advancing resets the watchdog
+	now = now.Add(timeout - time.Second)
+	watchdog.clock.Set(now)
+	engineDiagnostics.LastAcceptedHeight++
+	_, err = watchdog.HealthCheck()
+	assert.NoError(err)
+
+	// So does the chain not having blocks to accept
+	now = now.Add(timeout - time.Second)
+	watchdog.clock.Set(now)
+	processing := engineDiagnostics.ProcessingBlocks
+	engineDiagnostics.ProcessingBlocks = nil
+	_, err = watchdog.HealthCheck()
+	assert.NoError(err)
+	engineDiagnostics.ProcessingBlocks = processing
+	assert.Nil(watchdog.Diagnostics())
+
+	// Once the height doesn't advance for [timeout] with blocks processing,
+	// the chain is stalled
+	stalledAt := now.Add(timeout)
+	watchdog.clock.Set(stalledAt)
+	_, err = watchdog.HealthCheck()
+	assert.Error(err)
+
+	diagnostics := watchdog.Diagnostics()
+	assert.NotNil(diagnostics)
+	assert.Equal(chainID, diagnostics.ChainID)
+	assert.Equal(stalledAt, diagnostics.DetectedAt)
+	assert.Equal(now, diagnostics.LastProgressAt)
+	assert.Equal(engineDiagnostics, diagnostics.Engine)
+	assert.Nil(diagnostics.ProposerWindow)
+	assert.Contains(diagnostics.Goroutines, "TestStallWatchdog")
+
+	// The bundle is only captured when the stall is detected
+	watchdog.clock.Set(stalledAt.Add(time.Second))
+	_, err = watchdog.HealthCheck()
+	assert.Error(err)
+	assert.Same(diagnostics, watchdog.Diagnostics())
+
+	// The chain recovers once it accepts a block, but the bundle is kept
+	engineDiagnostics.LastAcceptedHeight++
+	_, err = watchdog.HealthCheck()
+	assert.NoError(err)
+	assert.Same(diagnostics, watchdog.Diagnostics())
+}
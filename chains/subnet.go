@@ -32,6 +32,7 @@ type SubnetConfig struct {
 	// ValidatorOnly indicates that this Subnet's Chains are available to only subnet validators.
 	ValidatorOnly       bool                 `json:"validatorOnly"`
 	ConsensusParameters avalanche.Parameters `json:"consensusParameters"`
+	ResourceQuotas      ResourceQuotas       `json:"resourceQuotas"`
 }
 
 type subnet struct {
@@ -5,6 +5,7 @@ package config
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -18,19 +19,27 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ava-labs/avalanchego/alerts"
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/app/runner"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/tieredb"
+	"github.com/ava-labs/avalanchego/failover"
+	"github.com/ava-labs/avalanchego/faults"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/lightclient"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/staking"
@@ -42,7 +51,10 @@ import (
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/storage"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/throttlevm"
 )
 
 const (
@@ -58,6 +70,7 @@ var (
 	errInvalidStakerWeights          = errors.New("staking weights must be positive")
 	errStakingDisableOnPublicNetwork = errors.New("staking disabled on public network")
 	errAuthPasswordTooWeak           = errors.New("API auth password is not strong enough")
+	errNoClientCAs                   = errors.New("no CA certificates found")
 	errInvalidUptimeRequirement      = errors.New("uptime requirement must be in the range [0, 1]")
 	errMinValidatorStakeAboveMax     = errors.New("minimum validator stake can't be greater than maximum validator stake")
 	errInvalidDelegationFee          = errors.New("delegation fee must be in the range [0, 1,000,000]")
@@ -193,11 +206,121 @@ func getIPCConfig(v *viper.Viper) node.IPCConfig {
 	return config
 }
 
+func getFailoverConfig(v *viper.Viper) (failover.Config, error) {
+	config := failover.Config{
+		Enabled:            v.GetBool(FailoverEnabledKey),
+		Primary:            v.GetBool(FailoverPrimaryKey),
+		PeerURI:            strings.TrimSuffix(v.GetString(FailoverPeerURIKey), "/"),
+		HeartbeatFrequency: v.GetDuration(FailoverHeartbeatFrequencyKey),
+		LeaseDuration:      v.GetDuration(FailoverLeaseDurationKey),
+	}
+	if !config.Enabled {
+		return config, nil
+	}
+	switch {
+	case config.PeerURI == "":
+		return failover.Config{}, fmt.Errorf("%s must be set if %s is true", FailoverPeerURIKey, FailoverEnabledKey)
+	case config.HeartbeatFrequency <= 0:
+		return failover.Config{}, fmt.Errorf("%s must be > 0", FailoverHeartbeatFrequencyKey)
+	case config.LeaseDuration <= config.HeartbeatFrequency:
+		return failover.Config{}, fmt.Errorf("%s must be > %s", FailoverLeaseDurationKey, FailoverHeartbeatFrequencyKey)
+	}
+	return config, nil
+}
+
+func getMetricsPushConfig(v *viper.Viper) (metrics.PushConfig, error) {
+	config := metrics.PushConfig{
+		URL:      v.GetString(MetricsPushURLKey),
+		Protocol: v.GetString(MetricsPushProtocolKey),
+		Interval: v.GetDuration(MetricsPushIntervalKey),
+		Job:      v.GetString(MetricsPushJobKey),
+		Labels:   map[string]string{},
+	}
+	if config.URL == "" {
+		return config, nil
+	}
+	if err := json.Unmarshal([]byte(v.GetString(MetricsPushLabelsKey)), &config.Labels); err != nil {
+		return metrics.PushConfig{}, fmt.Errorf("couldn't parse %s: %w", MetricsPushLabelsKey, err)
+	}
+	switch {
+	case config.Protocol != metrics.PushGatewayProtocol && config.Protocol != metrics.RemoteWriteProtocol:
+		return metrics.PushConfig{}, fmt.Errorf("%s must be %q or %q", MetricsPushProtocolKey, metrics.PushGatewayProtocol, metrics.RemoteWriteProtocol)
+	case config.Interval <= 0:
+		return metrics.PushConfig{}, fmt.Errorf("%s must be > 0", MetricsPushIntervalKey)
+	case config.Job == "":
+		return metrics.PushConfig{}, fmt.Errorf("%s must be set if %s is set", MetricsPushJobKey, MetricsPushURLKey)
+	}
+	return config, nil
+}
+
+func getAlertsConfig(v *viper.Viper, diskPath string) (alerts.Config, error) {
+	config := alerts.Config{
+		WebhookURL:          v.GetString(AlertsWebhookURLKey),
+		Format:              v.GetString(AlertsWebhookFormatKey),
+		PagerDutyRoutingKey: v.GetString(AlertsPagerDutyRoutingKeyKey),
+		CheckFrequency:      v.GetDuration(AlertsCheckFrequencyKey),
+		Cooldown:            v.GetDuration(AlertsCooldownKey),
+		MinDiskSpace:        v.GetUint64(AlertsMinDiskSpaceKey),
+		DiskPath:            diskPath,
+	}
+	if config.WebhookURL == "" {
+		return config, nil
+	}
+	if err := json.Unmarshal([]byte(v.GetString(AlertsMetricRulesKey)), &config.MetricRules); err != nil {
+		return alerts.Config{}, fmt.Errorf("couldn't parse %s: %w", AlertsMetricRulesKey, err)
+	}
+	for _, rule := range config.MetricRules {
+		if rule.Name == "" {
+			return alerts.Config{}, fmt.Errorf("%s must only contain rules with a name", AlertsMetricRulesKey)
+		}
+	}
+	switch {
+	case config.Format != alerts.SlackFormat && config.Format != alerts.PagerDutyFormat:
+		return alerts.Config{}, fmt.Errorf("%s must be %q or %q", AlertsWebhookFormatKey, alerts.SlackFormat, alerts.PagerDutyFormat)
+	case config.Format == alerts.PagerDutyFormat && config.PagerDutyRoutingKey == "":
+		return alerts.Config{}, fmt.Errorf("%s must be set if %s is %q", AlertsPagerDutyRoutingKeyKey, AlertsWebhookFormatKey, alerts.PagerDutyFormat)
+	case config.CheckFrequency <= 0:
+		return alerts.Config{}, fmt.Errorf("%s must be > 0", AlertsCheckFrequencyKey)
+	case config.Cooldown < 0:
+		return alerts.Config{}, fmt.Errorf("%s must be >= 0", AlertsCooldownKey)
+	}
+	return config, nil
+}
+
+func getColdStorageConfig(v *viper.Viper) (node.ColdStorageConfig, error) {
+	config := node.ColdStorageConfig{
+		S3: tieredb.S3Config{
+			Endpoint:        v.GetString(ColdStorageS3EndpointKey),
+			Region:          v.GetString(ColdStorageS3RegionKey),
+			Bucket:          v.GetString(ColdStorageS3BucketKey),
+			Prefix:          v.GetString(ColdStorageS3PrefixKey),
+			AccessKeyID:     v.GetString(ColdStorageS3AccessKeyIDKey),
+			SecretAccessKey: v.GetString(ColdStorageS3SecretAccessKeyKey),
+		},
+		Depth:     v.GetUint64(ColdStorageDepthKey),
+		CacheSize: v.GetInt(ColdStorageCacheSizeKey),
+	}
+	if config.S3.Endpoint == "" {
+		return config, nil
+	}
+	switch {
+	case config.S3.Bucket == "":
+		return node.ColdStorageConfig{}, fmt.Errorf("%s must be set if %s is set", ColdStorageS3BucketKey, ColdStorageS3EndpointKey)
+	case config.Depth == 0:
+		return node.ColdStorageConfig{}, fmt.Errorf("%s must be > 0", ColdStorageDepthKey)
+	case config.CacheSize <= 0:
+		return node.ColdStorageConfig{}, fmt.Errorf("%s must be > 0", ColdStorageCacheSizeKey)
+	}
+	return config, nil
+}
+
 func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 	var (
-		httpsKey  []byte
-		httpsCert []byte
-		err       error
+		httpsKey      []byte
+		httpsCert     []byte
+		httpsKeyFile  string
+		httpsCertFile string
+		err           error
 	)
 	switch {
 	case v.IsSet(HTTPSKeyContentKey):
@@ -207,8 +330,8 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			return node.HTTPConfig{}, fmt.Errorf("unable to decode base64 content: %w", err)
 		}
 	case v.IsSet(HTTPSKeyFileKey):
-		httpsKeyFilepath := os.ExpandEnv(v.GetString(HTTPSKeyFileKey))
-		if httpsKey, err = os.ReadFile(filepath.Clean(httpsKeyFilepath)); err != nil {
+		httpsKeyFile = filepath.Clean(os.ExpandEnv(v.GetString(HTTPSKeyFileKey)))
+		if httpsKey, err = os.ReadFile(httpsKeyFile); err != nil {
 			return node.HTTPConfig{}, err
 		}
 	}
@@ -221,43 +344,108 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			return node.HTTPConfig{}, fmt.Errorf("unable to decode base64 content: %w", err)
 		}
 	case v.IsSet(HTTPSCertFileKey):
-		httpsCertFilepath := os.ExpandEnv(v.GetString(HTTPSCertFileKey))
-		if httpsCert, err = os.ReadFile(filepath.Clean(httpsCertFilepath)); err != nil {
+		httpsCertFile = filepath.Clean(os.ExpandEnv(v.GetString(HTTPSCertFileKey)))
+		if httpsCert, err = os.ReadFile(httpsCertFile); err != nil {
 			return node.HTTPConfig{}, err
 		}
 	}
 
+	// The certificate can only be reloaded if both it and its key were
+	// provided as files.
+	if httpsKeyFile == "" || httpsCertFile == "" {
+		httpsKeyFile = ""
+		httpsCertFile = ""
+	}
+
+	endpointAllowedOrigins := map[string][]string{}
+	if err := json.Unmarshal([]byte(v.GetString(HTTPEndpointAllowedOriginsKey)), &endpointAllowedOrigins); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("couldn't parse %s: %w", HTTPEndpointAllowedOriginsKey, err)
+	}
+
+	trustedProxies := v.GetStringSlice(HTTPTrustedProxiesKey)
+	if _, err := server.ParseTrustedProxies(trustedProxies); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("couldn't parse %s: %w", HTTPTrustedProxiesKey, err)
+	}
+
 	config := node.HTTPConfig{
 		APIConfig: node.APIConfig{
 			APIIndexerConfig: node.APIIndexerConfig{
 				IndexAPIEnabled:      v.GetBool(IndexEnabledKey),
 				IndexAllowIncomplete: v.GetBool(IndexAllowIncompleteKey),
 			},
-			AdminAPIEnabled:    v.GetBool(AdminAPIEnabledKey),
-			InfoAPIEnabled:     v.GetBool(InfoAPIEnabledKey),
-			KeystoreAPIEnabled: v.GetBool(KeystoreAPIEnabledKey),
-			MetricsAPIEnabled:  v.GetBool(MetricsAPIEnabledKey),
-			HealthAPIEnabled:   v.GetBool(HealthAPIEnabledKey),
+			AdminAPIEnabled:      v.GetBool(AdminAPIEnabledKey),
+			InfoAPIEnabled:       v.GetBool(InfoAPIEnabledKey),
+			KeystoreAPIEnabled:   v.GetBool(KeystoreAPIEnabledKey),
+			MetricsAPIEnabled:    v.GetBool(MetricsAPIEnabledKey),
+			HealthAPIEnabled:     v.GetBool(HealthAPIEnabledKey),
+			EvidenceAPIEnabled:   v.GetBool(EvidenceAPIEnabledKey),
+			NetworkingAPIEnabled: v.GetBool(NetworkingAPIEnabledKey),
+			WalletAPIEnabled:     v.GetBool(WalletAPIEnabledKey),
+			DocAPIEnabled:        v.GetBool(DocAPIEnabledKey),
+
+			WalletScanFrequency: v.GetDuration(WalletScanFrequencyKey),
 		},
 		HTTPHost:          v.GetString(HTTPHostKey),
 		HTTPPort:          uint16(v.GetUint(HTTPPortKey)),
 		HTTPSEnabled:      v.GetBool(HTTPSEnabledKey),
 		HTTPSKey:          httpsKey,
 		HTTPSCert:         httpsCert,
+		HTTPSKeyFile:      httpsKeyFile,
+		HTTPSCertFile:     httpsCertFile,
 		APIAllowedOrigins: v.GetStringSlice(HTTPAllowedOrigins),
 
+		APIEndpointAllowedOrigins: endpointAllowedOrigins,
+		HTTPTrustedProxies:        trustedProxies,
+
+		SlowRequestThreshold: v.GetDuration(HTTPSlowRequestThresholdKey),
+
 		ShutdownTimeout: v.GetDuration(HTTPShutdownTimeoutKey),
 		ShutdownWait:    v.GetDuration(HTTPShutdownWaitKey),
 	}
+	if config.SlowRequestThreshold < 0 {
+		return node.HTTPConfig{}, fmt.Errorf("%s must be >= 0", HTTPSlowRequestThresholdKey)
+	}
+	if config.WalletScanFrequency <= 0 {
+		return node.HTTPConfig{}, fmt.Errorf("%s must be > 0", WalletScanFrequencyKey)
+	}
 
 	config.APIAuthConfig, err = getAPIAuthConfig(v)
 	if err != nil {
 		return node.HTTPConfig{}, err
 	}
+	if err := getHTTPSClientAuthConfig(v, &config); err != nil {
+		return node.HTTPConfig{}, err
+	}
 	config.IPCConfig = getIPCConfig(v)
 	return config, nil
 }
 
+func getHTTPSClientAuthConfig(v *viper.Viper, config *node.HTTPConfig) error {
+	if err := json.Unmarshal([]byte(v.GetString(HTTPSClientScopesKey)), &config.HTTPSClientScopes); err != nil {
+		return fmt.Errorf("couldn't parse %s: %w", HTTPSClientScopesKey, err)
+	}
+	if !v.IsSet(HTTPSClientCAFileKey) {
+		if len(config.HTTPSClientScopes) > 0 {
+			return fmt.Errorf("%s requires %s", HTTPSClientScopesKey, HTTPSClientCAFileKey)
+		}
+		return nil
+	}
+	if !config.HTTPSEnabled {
+		return fmt.Errorf("%s requires %s", HTTPSClientCAFileKey, HTTPSEnabledKey)
+	}
+
+	config.HTTPSClientCAFile = filepath.Clean(os.ExpandEnv(v.GetString(HTTPSClientCAFileKey)))
+	caBytes, err := os.ReadFile(config.HTTPSClientCAFile)
+	if err != nil {
+		return err
+	}
+	config.HTTPSClientCAs = x509.NewCertPool()
+	if !config.HTTPSClientCAs.AppendCertsFromPEM(caBytes) {
+		return fmt.Errorf("couldn't parse %s: %w", HTTPSClientCAFileKey, errNoClientCAs)
+	}
+	return nil
+}
+
 func getRouterHealthConfig(v *viper.Viper, halflife time.Duration) (router.HealthConfig, error) {
 	config := router.HealthConfig{
 		MaxDropRate:            v.GetFloat64(RouterHealthMaxDropRateKey),
@@ -363,10 +551,12 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		DialerConfig: dialer.Config{
 			ThrottleRps:       v.GetUint32(OutboundConnectionThrottlingRps),
 			ConnectionTimeout: v.GetDuration(OutboundConnectionTimeout),
+			KeepAlivePeriod:   v.GetDuration(NetworkTCPKeepAlivePeriodKey),
 		},
 
 		TimeoutConfig: network.TimeoutConfig{
 			PingPongTimeout:      v.GetDuration(NetworkPingTimeoutKey),
+			DeadPeerTimeout:      v.GetDuration(NetworkDeadPeerTimeoutKey),
 			ReadHandshakeTimeout: v.GetDuration(NetworkReadHandshakeTimeoutKey),
 		},
 
@@ -376,6 +566,7 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 			PeerListNonValidatorGossipSize: v.GetUint32(NetworkPeerListNonValidatorGossipSizeKey),
 			PeerListPeersGossipSize:        v.GetUint32(NetworkPeerListPeersGossipSizeKey),
 			PeerListGossipFreq:             v.GetDuration(NetworkPeerListGossipFreqKey),
+			ValidatorSnapshotEpoch:         v.GetDuration(NetworkValidatorSnapshotEpochKey),
 		},
 
 		DelayConfig: network.DelayConfig{
@@ -393,6 +584,24 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		RequireValidatorToConnect: v.GetBool(NetworkRequireValidatorToConnectKey),
 		PeerReadBufferSize:        int(v.GetUint(NetworkPeerReadBufferSizeKey)),
 		PeerWriteBufferSize:       int(v.GetUint(NetworkPeerWriteBufferSizeKey)),
+		MaxPersistedPeers:         int(v.GetUint(NetworkMaxPersistedPeersKey)),
+
+		LightClientConfig: lightclient.Config{
+			Enabled:              v.GetBool(NetworkLightClientEnabledKey),
+			MaxHeadersPerRequest: v.GetUint32(NetworkLightClientMaxHeadersKey),
+			HeaderRequestsPerSec: v.GetFloat64(NetworkLightClientRequestsPerSecKey),
+		},
+	}
+
+	peerPolicy, err := getPeerPolicy(v)
+	if err != nil {
+		return network.Config{}, err
+	}
+	config.PeerPolicy = peerPolicy
+
+	config.AllowlistConfig, err = getAllowlistConfig(v)
+	if err != nil {
+		return network.Config{}, err
 	}
 
 	switch {
@@ -406,8 +615,12 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be in [0,1]", NetworkHealthMaxPortionSendQueueFillKey)
 	case config.DialerConfig.ConnectionTimeout < 0:
 		return network.Config{}, fmt.Errorf("%q must be >= 0", OutboundConnectionTimeout)
+	case config.DialerConfig.KeepAlivePeriod < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkTCPKeepAlivePeriodKey)
 	case config.PeerListGossipFreq < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkPeerListGossipFreqKey)
+	case config.ValidatorSnapshotEpoch < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkValidatorSnapshotEpochKey)
 	case config.MaxReconnectDelay < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkMaxReconnectDelayKey)
 	case config.InitialReconnectDelay < 0:
@@ -420,15 +633,74 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkPingFrequencyKey)
 	case config.PingPongTimeout <= config.PingFrequency:
 		return network.Config{}, fmt.Errorf("%s must be > %s", NetworkPingTimeoutKey, NetworkPingFrequencyKey)
+	case config.DeadPeerTimeout < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkDeadPeerTimeoutKey)
 	case config.ReadHandshakeTimeout < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkReadHandshakeTimeoutKey)
 	case config.MaxClockDifference < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkMaxClockDifferenceKey)
+	case config.LightClientConfig.MaxHeadersPerRequest == 0:
+		return network.Config{}, fmt.Errorf("%s must be > 0", NetworkLightClientMaxHeadersKey)
+	case config.LightClientConfig.HeaderRequestsPerSec < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkLightClientRequestsPerSecKey)
 	}
 
 	return config, nil
 }
 
+func getAllowlistConfig(v *viper.Viper) (network.AllowlistConfig, error) {
+	config := network.AllowlistConfig{
+		Enabled:         v.GetBool(NetworkAllowlistEnabledKey),
+		AllowValidators: v.GetBool(NetworkAllowlistValidatorsKey),
+	}
+	for _, id := range strings.Split(v.GetString(NetworkAllowlistNodeIDsKey), ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return network.AllowlistConfig{}, fmt.Errorf("couldn't parse %s: %w", NetworkAllowlistNodeIDsKey, err)
+		}
+		config.NodeIDs.Add(nodeID)
+	}
+	return config, nil
+}
+
+func getPeerPolicy(v *viper.Viper) (*version.PeerPolicy, error) {
+	parser := version.NewDefaultApplicationParser()
+	policy := version.NewDefaultPeerPolicy()
+	if minVersion := v.GetString(NetworkPeerMinVersionKey); minVersion != "" {
+		parsed, err := parser.Parse(minVersion)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", NetworkPeerMinVersionKey, err)
+		}
+		policy.MinVersion = parsed
+	}
+	if maxVersion := v.GetString(NetworkPeerMaxVersionKey); maxVersion != "" {
+		parsed, err := parser.Parse(maxVersion)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", NetworkPeerMaxVersionKey, err)
+		}
+		policy.MaxVersion = parsed
+	}
+	if policy.MinVersion != nil && policy.MaxVersion != nil && policy.MaxVersion.Before(policy.MinVersion) {
+		return nil, fmt.Errorf("%s must be >= %s", NetworkPeerMaxVersionKey, NetworkPeerMinVersionKey)
+	}
+
+	featureVersions := map[version.Feature]string{}
+	if err := json.Unmarshal([]byte(v.GetString(NetworkPeerFeatureVersionsKey)), &featureVersions); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", NetworkPeerFeatureVersionsKey, err)
+	}
+	for feature, featureVersion := range featureVersions {
+		parsed, err := parser.Parse(featureVersion)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s version of %s: %w", feature, NetworkPeerFeatureVersionsKey, err)
+		}
+		policy.FeatureVersions[feature] = parsed
+	}
+	return policy, nil
+}
+
 func getBenchlistConfig(v *viper.Viper, alpha, k int) (benchlist.Config, error) {
 	config := benchlist.Config{
 		Threshold:              v.GetInt(BenchlistFailThresholdKey),
@@ -449,10 +721,18 @@ func getBootstrapConfig(v *viper.Viper, networkID uint32) (node.BootstrapConfig,
 	config := node.BootstrapConfig{
 		RetryBootstrap:                          v.GetBool(RetryBootstrapKey),
 		RetryBootstrapWarnFrequency:             v.GetInt(RetryBootstrapWarnFrequencyKey),
+		BootstrapOnly:                           v.GetBool(BootstrapOnlyKey),
+		BootstrapFrontierQuorum:                 v.GetFloat64(BootstrapFrontierQuorumKey),
 		BootstrapBeaconConnectionTimeout:        v.GetDuration(BootstrapBeaconConnectionTimeoutKey),
 		BootstrapMaxTimeGetAncestors:            v.GetDuration(BootstrapMaxTimeGetAncestorsKey),
 		BootstrapAncestorsMaxContainersSent:     int(v.GetUint(BootstrapAncestorsMaxContainersSentKey)),
 		BootstrapAncestorsMaxContainersReceived: int(v.GetUint(BootstrapAncestorsMaxContainersReceivedKey)),
+		BootstrapEraImportDir:                   os.ExpandEnv(v.GetString(BootstrapEraImportDirKey)),
+		BootstrapJobsMemoryBudget:               v.GetUint64(BootstrapJobsMemoryBudgetKey),
+	}
+
+	if config.BootstrapFrontierQuorum < .5 || config.BootstrapFrontierQuorum >= 1 {
+		return node.BootstrapConfig{}, fmt.Errorf("%q must be in [0.5, 1)", BootstrapFrontierQuorumKey)
 	}
 
 	ipsSet := v.IsSet(BootstrapIPsKey)
@@ -660,6 +940,17 @@ func getStakingConfig(v *viper.Viper, networkID uint32) (node.StakingConfig, err
 		config.RewardConfig.MinConsumptionRate = v.GetUint64(StakeMinConsumptionRateKey)
 		config.RewardConfig.MintingPeriod = v.GetDuration(StakeMintingPeriodKey)
 		config.RewardConfig.SupplyCap = v.GetUint64(StakeSupplyCapKey)
+		// A reward function defined by the genesis of the network takes
+		// precedence over the flags
+		genesisRewardConfig, err := getGenesisRewardConfig(v)
+		if err != nil {
+			return node.StakingConfig{}, fmt.Errorf("unable to load genesis file: %w", err)
+		}
+		if genesisRewardConfig != nil {
+			config.RewardConfig = *genesisRewardConfig
+		}
+		config.ChurnConfig.Period = v.GetDuration(StakeChurnPeriodKey)
+		config.ChurnConfig.MaxRate = v.GetUint64(StakeMaxChurnRateKey)
 		config.MinDelegationFee = v.GetUint32(MinDelegatorFeeKey)
 		switch {
 		case config.UptimeRequirement < 0 || config.UptimeRequirement > 1:
@@ -677,12 +968,39 @@ func getStakingConfig(v *viper.Viper, networkID uint32) (node.StakingConfig, err
 		case config.RewardConfig.MintingPeriod < config.MaxStakeDuration:
 			return node.StakingConfig{}, errStakeMintingPeriodBelowMin
 		}
+		if err := config.RewardConfig.Verify(); err != nil {
+			return node.StakingConfig{}, fmt.Errorf("invalid reward config: %w", err)
+		}
+		if err := config.ChurnConfig.Verify(); err != nil {
+			return node.StakingConfig{}, fmt.Errorf("invalid churn config: %w", err)
+		}
 	} else {
 		config.StakingConfig = genesis.GetStakingConfig(networkID)
 	}
 	return config, nil
 }
 
+// getGenesisRewardConfig returns the reward config of the custom genesis of
+// the network, or nil if the network's genesis doesn't define one
+func getGenesisRewardConfig(v *viper.Viper) (*reward.Config, error) {
+	var (
+		genesisConfig *genesis.Config
+		err           error
+	)
+	switch {
+	case v.IsSet(GenesisConfigContentKey):
+		genesisConfig, err = genesis.GetConfigContent(v.GetString(GenesisConfigContentKey))
+	case v.IsSet(GenesisConfigFileKey):
+		genesisConfig, err = genesis.GetConfigFile(os.ExpandEnv(v.GetString(GenesisConfigFileKey)))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return genesisConfig.RewardConfig, nil
+}
+
 func getTxFeeConfig(v *viper.Viper, networkID uint32) genesis.TxFeeConfig {
 	if networkID != constants.MainnetID && networkID != constants.FujiID {
 		return genesis.TxFeeConfig{
@@ -796,6 +1114,28 @@ func getVMAliases(v *viper.Viper) (map[ids.ID][]string, error) {
 	return vmAliasMap, nil
 }
 
+// getFaultInjectionScript returns the script of the faults to inject, or nil
+// if faults aren't injected
+func getFaultInjectionScript(v *viper.Viper) (*faults.Script, error) {
+	path := v.GetString(FaultInjectionScriptFileKey)
+	if path == "" {
+		return nil, nil
+	}
+	if !faults.Enabled {
+		return nil, fmt.Errorf("%s requires a build with the faultinjection tag", FaultInjectionScriptFileKey)
+	}
+
+	scriptBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	script := &faults.Script{}
+	if err := json.Unmarshal(scriptBytes, script); err != nil {
+		return nil, fmt.Errorf("problem unmarshaling the fault injection script: %w", err)
+	}
+	return script, nil
+}
+
 func getVMManager(v *viper.Viper) (vms.Manager, error) {
 	vmAliases, err := getVMAliases(v)
 	if err != nil {
@@ -931,6 +1271,9 @@ func getSubnetConfigsFromFlags(v *viper.Viper, subnetIDs []ids.ID) (map[ids.ID]c
 			if err := subnetConfig.ConsensusParameters.Valid(); err != nil {
 				return nil, err
 			}
+			if err := subnetConfig.ResourceQuotas.Verify(); err != nil {
+				return nil, err
+			}
 			res[subnetID] = subnetConfig
 		}
 	}
@@ -991,6 +1334,9 @@ func readSubnetConfigs(subnetConfigPath string, subnetIDs []ids.ID, defaultSubne
 		if err := configData.ConsensusParameters.Valid(); err != nil {
 			return nil, err
 		}
+		if err := configData.ResourceQuotas.Verify(); err != nil {
+			return nil, err
+		}
 		subnetConfigs[subnetID] = configData
 	}
 
@@ -1016,16 +1362,23 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if err := nodeConfig.ConsensusParams.Valid(); err != nil {
 		return node.Config{}, err
 	}
+	nodeConfig.ReadOnlyReplica = v.GetBool(ReadOnlyReplicaEnabledKey)
 	nodeConfig.ConsensusShutdownTimeout = v.GetDuration(ConsensusShutdownTimeoutKey)
 	if nodeConfig.ConsensusShutdownTimeout < 0 {
 		return node.Config{}, fmt.Errorf("%q must be >= 0", ConsensusShutdownTimeoutKey)
 	}
+	nodeConfig.RouterMessagePolicyFile = os.ExpandEnv(v.GetString(RouterMessagePolicyFileKey))
 
 	// Gossiping
 	nodeConfig.ConsensusGossipFrequency = v.GetDuration(ConsensusGossipFrequencyKey)
 	if nodeConfig.ConsensusGossipFrequency < 0 {
 		return node.Config{}, fmt.Errorf("%s must be >= 0", ConsensusGossipFrequencyKey)
 	}
+	nodeConfig.ConsensusBatchQueriesEnabled = v.GetBool(ConsensusBatchQueriesEnabledKey)
+	nodeConfig.ConsensusFinalityStallTimeout = v.GetDuration(ConsensusFinalityStallTimeoutKey)
+	if nodeConfig.ConsensusFinalityStallTimeout < 0 {
+		return node.Config{}, fmt.Errorf("%s must be >= 0", ConsensusFinalityStallTimeoutKey)
+	}
 
 	var err error
 	// Logging
@@ -1046,6 +1399,12 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Cold storage
+	nodeConfig.ColdStorageConfig, err = getColdStorageConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// IP configuration
 	nodeConfig.IPConfig, err = getIPConfig(v)
 	if err != nil {
@@ -1087,9 +1446,53 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if err != nil {
 		return node.Config{}, err
 	}
+	nodeConfig.DroppedMessagesConfig = drops.Config{
+		HistorySize:  v.GetInt(RouterDroppedMessagesHistorySizeKey),
+		LogFrequency: v.GetDuration(RouterDroppedMessagesLogFrequencyKey),
+	}
+	if nodeConfig.DroppedMessagesConfig.HistorySize < 0 {
+		return node.Config{}, fmt.Errorf("%s must be >= 0", RouterDroppedMessagesHistorySizeKey)
+	}
+	if nodeConfig.DroppedMessagesConfig.LogFrequency < 0 {
+		return node.Config{}, fmt.Errorf("%s must be >= 0", RouterDroppedMessagesLogFrequencyKey)
+	}
+
+	// VM CPU throttling
+	nodeConfig.VMThrottlerConfig = throttlevm.Config{
+		CPUPortion: v.GetFloat64(VMCPUThrottlerPortionKey),
+		MaxBurst:   v.GetDuration(VMCPUThrottlerMaxBurstKey),
+	}
+	switch {
+	case nodeConfig.VMThrottlerConfig.CPUPortion < 0:
+		return node.Config{}, fmt.Errorf("%s must be >= 0", VMCPUThrottlerPortionKey)
+	case nodeConfig.VMThrottlerConfig.Enabled() && nodeConfig.VMThrottlerConfig.MaxBurst <= 0:
+		return node.Config{}, fmt.Errorf("%s must be > 0", VMCPUThrottlerMaxBurstKey)
+	}
 
 	// Metrics
 	nodeConfig.MeterVMEnabled = v.GetBool(MeterVMsEnabledKey)
+	nodeConfig.MetricsPushConfig, err = getMetricsPushConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Alerts
+	nodeConfig.AlertsConfig, err = getAlertsConfig(v, nodeConfig.DatabaseConfig.Path)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Decided block cache
+	nodeConfig.DecidedBlockCacheSize = v.GetInt(DecidedBlockCacheSizeKey)
+	if nodeConfig.DecidedBlockCacheSize < 0 {
+		return node.Config{}, fmt.Errorf("%q must be >= 0", DecidedBlockCacheSizeKey)
+	}
+
+	// Failover
+	nodeConfig.FailoverConfig, err = getFailoverConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
 
 	// Adaptive Timeout Config
 	nodeConfig.AdaptiveTimeoutConfig, err = getAdaptiveTimeoutConfig(v)
@@ -1163,5 +1566,24 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	// reset proposerVM height index
 	nodeConfig.ResetProposerVMHeightIndex = v.GetBool(ResetProposerVMHeightIndexKey)
 
+	// duplicate identity detection
+	nodeConfig.StopProposingOnDuplicateIdentity = v.GetBool(StopProposingOnDuplicateIdentityKey)
+
+	// block timestamps
+	nodeConfig.ProposerVMUsePeerTime = v.GetBool(ProposerVMUsePeerTimeKey)
+
+	// invariant checks
+	nodeConfig.InvariantChecks = v.GetBool(InvariantChecksKey)
+
+	// replay
+	nodeConfig.ReplayChain = v.GetString(ReplayChainKey)
+	nodeConfig.ReplayFromHeight = v.GetUint64(ReplayFromHeightKey)
+
+	// fault injection
+	nodeConfig.FaultInjectionScript, err = getFaultInjectionScript(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	return nodeConfig, nil
 }
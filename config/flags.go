@@ -13,6 +13,8 @@ import (
 
 	"github.com/kardianos/osext"
 
+	"github.com/ava-labs/avalanchego/alerts"
+	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/database/rocksdb"
@@ -100,6 +102,16 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(DBConfigFileKey, "", fmt.Sprintf("Path to database config file. Ignored if %s is specified", DBConfigContentKey))
 	fs.String(DBConfigContentKey, "", "Specifies base64 encoded database config content")
 
+	// Cold storage
+	fs.String(ColdStorageS3EndpointKey, "", "If set, URL of the S3-compatible object storage that the accepted blocks of the snowman chains are offloaded to once they're deep enough in the chain. Example: https://s3.us-east-1.amazonaws.com")
+	fs.String(ColdStorageS3RegionKey, "us-east-1", "Region of the cold storage bucket")
+	fs.String(ColdStorageS3BucketKey, "", fmt.Sprintf("Name of the cold storage bucket. Required if %s is set", ColdStorageS3EndpointKey))
+	fs.String(ColdStorageS3PrefixKey, "", "Prefix of the names of the offloaded blocks. The blocks of each chain are stored under <prefix><chain ID>/")
+	fs.String(ColdStorageS3AccessKeyIDKey, "", "Access key ID used to authenticate to the cold storage")
+	fs.String(ColdStorageS3SecretAccessKeyKey, "", "Secret access key used to authenticate to the cold storage")
+	fs.Uint64(ColdStorageDepthKey, 65536, "Number of accepted blocks of each chain kept in the local database below the last accepted block")
+	fs.Int(ColdStorageCacheSizeKey, 1024, "Number of offloaded blocks of each chain kept in memory once they were read")
+
 	// Logging
 	fs.String(LogsDirKey, "", "Logging directory for Avalanche")
 	fs.String(LogLevelKey, "info", "The log level. Should be one of {verbo, debug, trace, info, warn, error, fatal, off}")
@@ -127,6 +139,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Uint(NetworkPeerListNonValidatorGossipSizeKey, 25, gossipHelpMsg)
 	fs.Uint(NetworkPeerListPeersGossipSizeKey, 0, gossipHelpMsg)
 	fs.Duration(NetworkPeerListGossipFreqKey, time.Minute, gossipHelpMsg)
+	fs.Duration(NetworkValidatorSnapshotEpochKey, 30*time.Second, "Maximum amount of time the validators of a subnet are cached to sample the peers to gossip to. The cache is also refreshed when a validator is added or removed. If 0, the validators are looked up on every gossip")
 
 	// Public IP Resolution
 	fs.String(PublicIPKey, "", "Public IP of this node for P2P communication. If empty, try to discover with NAT. Ignored if dynamic-public-ip is non-empty")
@@ -139,6 +152,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// Outbound Connection Throttling
 	fs.Uint(OutboundConnectionThrottlingRps, 50, "Make at most this number of outgoing peer connection attempts per second")
 	fs.Duration(OutboundConnectionTimeout, 30*time.Second, "Timeout when dialing a peer")
+	fs.Duration(NetworkTCPKeepAlivePeriodKey, 15*time.Second, "Period between TCP keep-alive probes on peer connections. If 0, keep-alive probes aren't sent")
 	// Timeouts
 	fs.Duration(NetworkInitialTimeoutKey, 5*time.Second, "Initial timeout value of the adaptive timeout manager")
 	fs.Duration(NetworkMinimumTimeoutKey, 2*time.Second, "Minimum timeout value of the adaptive timeout manager")
@@ -149,13 +163,24 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(NetworkReadHandshakeTimeoutKey, 15*time.Second, "Timeout value for reading handshake messages")
 	fs.Duration(NetworkPingTimeoutKey, constants.DefaultPingPongTimeout, "Timeout value for Ping-Pong with a peer")
 	fs.Duration(NetworkPingFrequencyKey, constants.DefaultPingFrequency, "Frequency of pinging other peers")
+	fs.Uint(NetworkMaxPersistedPeersKey, 256, "Maximum number of validator IPs to remember across restarts. They are dialed along with the beacons when the node starts. If 0, no IPs are remembered")
+	fs.Duration(NetworkDeadPeerTimeoutKey, 10*time.Second, "Time a peer has to answer a ping before it's disconnected from, even if other messages are still received from it. If 0, only the ping timeout is enforced")
 
 	fs.Bool(NetworkCompressionEnabledKey, true, "If true, compress certain outbound messages. This node will be able to parse compressed inbound messages regardless of this flag's value")
 	fs.Duration(NetworkMaxClockDifferenceKey, time.Minute, "Max allowed clock difference value between this node and peers")
 	fs.Bool(NetworkAllowPrivateIPsKey, true, "Allows the node to initiate outbound connection attempts to peers with private IPs")
 	fs.Bool(NetworkRequireValidatorToConnectKey, false, "If true, this node will only maintain a connection with another node if this node is a validator, the other node is a validator, or the other node is a beacon")
+	fs.Bool(NetworkAllowlistEnabledKey, false, "If true, this node refuses the TLS handshake with any node that isn't a beacon or allowed by --"+NetworkAllowlistNodeIDsKey+" or --"+NetworkAllowlistValidatorsKey+". Meant for private networks")
+	fs.String(NetworkAllowlistNodeIDsKey, "", "Comma separated list of the node IDs allowed to connect when the allowlist is enabled")
+	fs.Bool(NetworkAllowlistValidatorsKey, false, "If true, the current primary network validators are allowed to connect when the allowlist is enabled")
 	fs.Uint(NetworkPeerReadBufferSizeKey, 8*units.KiB, "Size, in bytes, of the buffer that we read peer messages into (there is one buffer per peer)")
 	fs.Uint(NetworkPeerWriteBufferSizeKey, 8*units.KiB, "Size, in bytes, of the buffer that we write peer messages into (there is one buffer per peer)")
+	fs.String(NetworkPeerMinVersionKey, "", "If non-empty, disconnect from peers running a version older than this one, e.g. avalanche/1.7.5")
+	fs.String(NetworkPeerMaxVersionKey, "", "If non-empty, disconnect from peers running a version newer than this one, e.g. avalanche/1.7.10")
+	fs.String(NetworkPeerFeatureVersionsKey, "{}", "JSON map from a feature to the first peer version that supports it, overriding the defaults. Messages that need a feature are only sent to peers that support it. e.g. {\"compression\":\"avalanche/1.7.5\"}")
+	fs.Bool(NetworkLightClientEnabledKey, true, "If true, serve chain headers and validator set proofs to light clients")
+	fs.Uint(NetworkLightClientMaxHeadersKey, 64, "Maximum number of headers sent in response to a single light client request")
+	fs.Float64(NetworkLightClientRequestsPerSecKey, 2, "Number of light client header requests each peer may send per second. Requests that exceed this rate are dropped")
 
 	// Benchlist
 	fs.Int(BenchlistFailThresholdKey, 10, "Number of consecutive failed queries before benchlisting a node")
@@ -165,12 +190,17 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// Router
 	fs.Duration(ConsensusGossipFrequencyKey, 10*time.Second, "Frequency of gossiping accepted frontiers")
 	fs.Duration(ConsensusShutdownTimeoutKey, 30*time.Second, "Timeout before killing an unresponsive chain")
+	fs.String(RouterMessagePolicyFileKey, "", "Path to a JSON file mapping subnet IDs to the policy used to drop or deprioritize messages sent to the subnet's chains. Can be reloaded with the Admin API")
+	fs.Int(RouterDroppedMessagesHistorySizeKey, 256, "Number of the most recently dropped messages reported by the Networking API")
+	fs.Duration(RouterDroppedMessagesLogFrequencyKey, 0, "If non-zero, a dropped message is logged at most once per this duration")
 	fs.Uint(ConsensusGossipAcceptedFrontierValidatorSizeKey, 0, "Number of validators to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipAcceptedFrontierNonValidatorSizeKey, 0, "Number of non-validators to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipAcceptedFrontierPeerSizeKey, 35, "Number of peers to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipOnAcceptValidatorSizeKey, 0, "Number of validators to gossip to each accepted container to")
 	fs.Uint(ConsensusGossipOnAcceptNonValidatorSizeKey, 0, "Number of non-validators to gossip to each accepted container to")
 	fs.Uint(ConsensusGossipOnAcceptPeerSizeKey, 20, "Number of peers to gossip to each accepted container to")
+	fs.Bool(ConsensusBatchQueriesEnabledKey, true, "If true, snowman chains send the queries for several blocks issued at once to the same validator in one message, to validators that support it")
+	fs.Duration(ConsensusFinalityStallTimeoutKey, 5*time.Minute, "If non-zero, a snowman chain that has blocks processing but hasn't accepted a block for this long is reported unhealthy, and a diagnostics bundle is captured")
 	fs.Uint(AppGossipValidatorSizeKey, 10, "Number of validators to gossip an AppGossip message to")
 	fs.Uint(AppGossipNonValidatorSizeKey, 0, "Number of non-validators to gossip an AppGossip message to")
 	fs.Uint(AppGossipPeerSizeKey, 0, "Number of peers (which may be validators or non-validators) to gossip an AppGossip message to")
@@ -192,11 +222,16 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(HTTPHostKey, "127.0.0.1", "Address of the HTTP server")
 	fs.Uint(HTTPPortKey, 9650, "Port of the HTTP server")
 	fs.Bool(HTTPSEnabledKey, false, "Upgrade the HTTP server to HTTPs")
-	fs.String(HTTPSKeyFileKey, "", fmt.Sprintf("TLS private key file for the HTTPs server. Ignored if %s is specified", HTTPSKeyContentKey))
+	fs.String(HTTPSKeyFileKey, "", fmt.Sprintf("TLS private key file for the HTTPs server. Ignored if %s is specified. The key is reloaded when the file changes", HTTPSKeyContentKey))
 	fs.String(HTTPSKeyContentKey, "", "Specifies base64 encoded TLS private key for the HTTPs server")
-	fs.String(HTTPSCertFileKey, "", fmt.Sprintf("TLS certificate file for the HTTPs server. Ignored if %s is specified", HTTPSCertContentKey))
+	fs.String(HTTPSCertFileKey, "", fmt.Sprintf("TLS certificate file for the HTTPs server. Ignored if %s is specified. The certificate is reloaded when the file changes", HTTPSCertContentKey))
 	fs.String(HTTPSCertContentKey, "", "Specifies base64 encoded TLS certificate for the HTTPs server")
+	fs.String(HTTPSClientCAFileKey, "", fmt.Sprintf("File of PEM encoded CA certificates. If specified, clients of the HTTPs server must present a TLS certificate signed by one of them. Requires %s", HTTPSEnabledKey))
+	fs.String(HTTPSClientScopesKey, "{}", fmt.Sprintf("JSON map from the common name of client TLS certificates to the endpoints they give access to, e.g. {\"ops\":[\"*\"],\"wallet\":[\"/ext/bc/X\"]}. If empty, every certificate signed by %s gives access to all endpoints", HTTPSClientCAFileKey))
 	fs.String(HTTPAllowedOrigins, "*", "Origins to allow on the HTTP port. Defaults to * which allows all origins. Example: https://*.avax.network https://*.avax-test.network")
+	fs.String(HTTPEndpointAllowedOriginsKey, "{}", fmt.Sprintf("JSON map from an endpoint group to the origins to allow on it, overriding %s. Groups are named by their path under /ext. e.g. {\"info\":[\"*\"],\"bc/X\":[\"https://*.avax.network\"]}", HTTPAllowedOrigins))
+	fs.String(HTTPTrustedProxiesKey, "", "IPs and CIDR ranges of reverse proxies whose X-Forwarded-For header is trusted to report the address of the client. Example: 10.0.0.0/8 127.0.0.1")
+	fs.Duration(HTTPSlowRequestThresholdKey, 5*time.Second, "API requests that take at least this long are written to the slow request log. If 0, no requests are logged as slow")
 	fs.Duration(HTTPShutdownWaitKey, 0, "Duration to wait after receiving SIGTERM or SIGINT before initiating shutdown. The /health endpoint will return unhealthy during this duration")
 	fs.Duration(HTTPShutdownTimeoutKey, 10*time.Second, "Maximum duration to wait for existing connections to complete during node shutdown")
 	fs.Bool(APIAuthRequiredKey, false, "Require authorization token to call HTTP APIs")
@@ -211,7 +246,12 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Bool(KeystoreAPIEnabledKey, true, "If true, this node exposes the Keystore API")
 	fs.Bool(MetricsAPIEnabledKey, true, "If true, this node exposes the Metrics API")
 	fs.Bool(HealthAPIEnabledKey, true, "If true, this node exposes the Health API")
+	fs.Bool(EvidenceAPIEnabledKey, false, "If true, this node exposes the Evidence API")
+	fs.Bool(NetworkingAPIEnabledKey, false, "If true, this node exposes the Networking API")
+	fs.Bool(WalletAPIEnabledKey, false, "If true, this node exposes the Wallet API, which issues txs on the P-chain and X-chain for keystore users")
+	fs.Duration(WalletScanFrequencyKey, 30*time.Second, "Frequency at which the Wallet API rescans the UTXOs of the keystore users it tracks")
 	fs.Bool(IpcAPIEnabledKey, false, "If true, IPCs can be opened")
+	fs.Bool(DocAPIEnabledKey, true, "If true, this node serves OpenRPC descriptions of its APIs at /ext/doc")
 
 	// Health Checks
 	fs.Duration(HealthCheckFreqKey, 30*time.Second, "Time between health checks")
@@ -249,11 +289,14 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(MinStakeDurationKey, genesis.LocalParams.MinStakeDuration, "Minimum staking duration")
 	// Maximum Stake Duration
 	fs.Duration(MaxStakeDurationKey, genesis.LocalParams.MaxStakeDuration, "Maximum staking duration")
-	// Stake Reward Configs
+	// Stake Reward Configs. Overridden by the rewardConfig of a custom genesis.
 	fs.Uint64(StakeMaxConsumptionRateKey, genesis.LocalParams.RewardConfig.MaxConsumptionRate, "Maximum consumption rate of the remaining tokens to mint in the staking function")
 	fs.Uint64(StakeMinConsumptionRateKey, genesis.LocalParams.RewardConfig.MinConsumptionRate, "Minimum consumption rate of the remaining tokens to mint in the staking function")
 	fs.Duration(StakeMintingPeriodKey, genesis.LocalParams.RewardConfig.MintingPeriod, "Consumption period of the staking function")
 	fs.Uint64(StakeSupplyCapKey, genesis.LocalParams.RewardConfig.SupplyCap, "Supply cap of the staking function")
+	// Stake Churn Configs
+	fs.Duration(StakeChurnPeriodKey, time.Hour, fmt.Sprintf("Duration of the periods over which [%s] is enforced", StakeMaxChurnRateKey))
+	fs.Uint64(StakeMaxChurnRateKey, genesis.LocalParams.ChurnConfig.MaxRate, "Maximum portion, in the range [0, 1000000], of the current stake of a subnet that may start, or stop, staking in a period. The first staker of a period is always allowed. If 0, the churn isn't limited")
 	// Subnets
 	fs.String(WhitelistedSubnetsKey, "", "Whitelist of subnets to validate")
 
@@ -262,10 +305,14 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(BootstrapIDsKey, "", "Comma separated list of bootstrap peer ids to connect to. Example: NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET,NodeID-8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
 	fs.Bool(RetryBootstrapKey, true, "Specifies whether bootstrap should be retried")
 	fs.Int(RetryBootstrapWarnFrequencyKey, 50, "Specifies how many times bootstrap should be retried before warning the operator")
+	fs.Bool(BootstrapOnlyKey, false, "If true, this node is a seed node. Chains are kept synced and serve bootstrapping requests, but never finish bootstrapping, so consensus isn't run and chain APIs aren't served")
+	fs.Float64(BootstrapFrontierQuorumKey, 0.5, "Fraction of the beacons' stake that must report a container as accepted for bootstrapping to sync to it. Must be in [0.5, 1)")
 	fs.Duration(BootstrapBeaconConnectionTimeoutKey, time.Minute, "Timeout when attempting to connect to bootstrapping beacons")
 	fs.Duration(BootstrapMaxTimeGetAncestorsKey, 50*time.Millisecond, "Max Time to spend fetching a container and its ancestors when responding to a GetAncestors")
 	fs.Uint(BootstrapAncestorsMaxContainersSentKey, 2000, "Max number of containers in an Ancestors message sent by this node")
 	fs.Uint(BootstrapAncestorsMaxContainersReceivedKey, 2000, "This node reads at most this many containers from an incoming Ancestors message")
+	fs.Uint64(BootstrapJobsMemoryBudgetKey, 256*units.MiB, "Size, in bytes, of the jobs that each bootstrapping job queue holds in memory. Jobs beyond the budget are spilled to disk")
+	fs.String(BootstrapEraImportDirKey, "", "If set, before bootstrapping, each snowman chain accepts the blocks of the era archive in the subdirectory of this directory named after its chain ID. See the export-era command")
 
 	// Consensus
 	fs.Int(SnowSampleSizeKey, 20, "Number of nodes to query for each network poll")
@@ -278,9 +325,37 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Int(SnowOptimalProcessingKey, 50, "Optimal number of processing vertices in consensus")
 	fs.Int(SnowMaxProcessingKey, 1024, "Maximum number of processing items to be considered healthy")
 	fs.Duration(SnowMaxTimeProcessingKey, 2*time.Minute, "Maximum amount of time an item should be processing and still be healthy")
+	fs.Bool(ReadOnlyReplicaEnabledKey, false, "If true, chains follow consensus and serve APIs without ever building blocks or voting on undecided blocks. Intended for RPC nodes that shouldn't affect consensus")
+
+	// Failover
+	fs.Bool(FailoverEnabledKey, false, "If true, this node is one of an active/standby pair that share a staking key. Only the active node builds blocks and votes")
+	fs.Bool(FailoverPrimaryKey, false, "If true, this node is the primary of its failover pair, which is only active while it holds a lease granted by the standby. Otherwise, it's the standby, which only becomes active once the lease it granted expired")
+	fs.String(FailoverPeerURIKey, "", "URI of the API of the other node of the failover pair. e.g. http://10.0.0.2:9650")
+	fs.Duration(FailoverHeartbeatFrequencyKey, 2*time.Second, "Frequency of the failover primary renewing its lease")
+	fs.Duration(FailoverLeaseDurationKey, 30*time.Second, "Duration of the lease held by the failover primary. The standby becomes active once the primary hasn't renewed its lease for this long")
+
+	// VM CPU throttling
+	fs.Float64(VMCPUThrottlerPortionKey, 0, "Portion of a CPU core each snowman VM may spend building, parsing and verifying blocks on average. The calls of a VM that used up its share are delayed, so that it can't starve the other chains. If 0, VMs aren't throttled")
+	fs.Duration(VMCPUThrottlerMaxBurstKey, 5*time.Second, "CPU time each snowman VM may spend building, parsing and verifying blocks at once after it was idle")
 
 	// Metrics
 	fs.Bool(MeterVMsEnabledKey, true, "Enable Meter VMs to track VM performance with more granularity")
+	fs.String(MetricsPushURLKey, "", "If set, this node's metrics are periodically pushed to this URL, for nodes that can't be scraped")
+	fs.String(MetricsPushProtocolKey, metrics.PushGatewayProtocol, fmt.Sprintf("Protocol used to push metrics. Must be %q or %q", metrics.PushGatewayProtocol, metrics.RemoteWriteProtocol))
+	fs.Duration(MetricsPushIntervalKey, 30*time.Second, "Time between metrics pushes")
+	fs.String(MetricsPushJobKey, constants.AppName, "Value of the job label of the pushed metrics")
+	fs.String(MetricsPushLabelsKey, "{}", "JSON map of the labels added to the pushed metrics. e.g. {\"instance\":\"validator-1\"}")
+
+	// Alerts
+	fs.String(AlertsWebhookURLKey, "", "If set, this node notifies this webhook when its health checks fail, its disk space runs low or its metric rules fire")
+	fs.String(AlertsWebhookFormatKey, alerts.SlackFormat, fmt.Sprintf("Format of the alert notifications. Must be %q or %q", alerts.SlackFormat, alerts.PagerDutyFormat))
+	fs.String(AlertsPagerDutyRoutingKeyKey, "", fmt.Sprintf("Integration key of the PagerDuty service alerts are sent to. Required if %s is %q", AlertsWebhookFormatKey, alerts.PagerDutyFormat))
+	fs.Duration(AlertsCheckFrequencyKey, 30*time.Second, "Time between evaluations of the alerts")
+	fs.Duration(AlertsCooldownKey, 15*time.Minute, "Minimum time between two notifications that the same alert is still firing")
+	fs.Uint64(AlertsMinDiskSpaceKey, 10*units.GiB, "Number of bytes available in the database directory below which an alert fires. If 0, the disk space isn't watched")
+	fs.String(AlertsMetricRulesKey, `[{"name":"proposer_windows_missed","threshold":0,"increase":true},{"name":"benched_num","threshold":0}]`, "JSON list of metric rules. An alert fires for each series of the metric whose value, or increase since the previous evaluation if increase is true, is above the threshold")
+
+	fs.Int(DecidedBlockCacheSizeKey, 2048, "Number of recently accepted blocks, over all snowman chains, cached in front of the VMs. If 0, the cache is disabled")
 	fs.Duration(UptimeMetricFreqKey, 30*time.Second, "Frequency of renewing this node's average uptime metric")
 
 	// IPC
@@ -289,9 +364,21 @@ func addNodeFlags(fs *flag.FlagSet) {
 
 	// Indexer
 	fs.Bool(ResetProposerVMHeightIndexKey, false, "if true, proposervm height index is wiped on startup")
+	fs.Bool(StopProposingOnDuplicateIdentityKey, false, "If true, this node stops building blocks once another node is detected proposing blocks with this node's staking key")
+	fs.Bool(ProposerVMUsePeerTimeKey, false, "If true, blocks built by this node are timestamped with the stake-weighted median of the times recently reported by primary network validators rather than with the local time")
 	fs.Bool(IndexEnabledKey, false, "If true, index all accepted containers and transactions and expose them via an API")
 	fs.Bool(IndexAllowIncompleteKey, false, "If true, allow running the node in such a way that could cause an index to miss transactions. Ignored if index is disabled")
 
+	// Invariant checks
+	fs.Bool(InvariantChecksKey, false, "If true, expensive runtime assertions are made in consensus, the proposervm and the chain databases, and violations fail the operation that caused them. Meant for canary nodes")
+
+	// Replay
+	fs.String(ReplayChainKey, "", "If set, ID or alias of a snowman chain whose accepted blocks are re-executed from its genesis by a second, isolated instance of its VM when the node starts. The first block that doesn't replay as it was accepted is logged")
+	fs.Uint64(ReplayFromHeightKey, 0, fmt.Sprintf("Height from which the blocks replayed because of %s are compared in detail with the stored blocks. The blocks below it are only required to be valid and to have the same IDs", ReplayChainKey))
+
+	// Fault injection
+	fs.String(FaultInjectionScriptFileKey, "", "If set, path to a JSON script of the inbound messages to drop, delay or duplicate and of the database operations to fail. Only supported by builds with the faultinjection tag")
+
 	// Config Directories
 	fs.String(ChainConfigDirKey, defaultChainConfigDir, fmt.Sprintf("Chain specific configurations parent directory. Ignored if %s is specified", ChainConfigContentKey))
 	fs.String(ChainConfigContentKey, "", "Specifies base64 encoded chains configurations")
@@ -27,12 +27,22 @@ const (
 	StakeMinConsumptionRateKey                         = "stake-min-consumption-rate"
 	StakeMintingPeriodKey                              = "stake-minting-period"
 	StakeSupplyCapKey                                  = "stake-supply-cap"
+	StakeChurnPeriodKey                                = "stake-churn-period"
+	StakeMaxChurnRateKey                               = "stake-max-churn-rate"
 	AssertionsEnabledKey                               = "assertions-enabled"
 	SignatureVerificationEnabledKey                    = "signature-verification-enabled"
 	DBTypeKey                                          = "db-type"
 	DBPathKey                                          = "db-dir"
 	DBConfigFileKey                                    = "db-config-file"
 	DBConfigContentKey                                 = "db-config-file-content"
+	ColdStorageS3EndpointKey                           = "cold-storage-s3-endpoint"
+	ColdStorageS3RegionKey                             = "cold-storage-s3-region"
+	ColdStorageS3BucketKey                             = "cold-storage-s3-bucket"
+	ColdStorageS3PrefixKey                             = "cold-storage-s3-prefix"
+	ColdStorageS3AccessKeyIDKey                        = "cold-storage-s3-access-key-id"
+	ColdStorageS3SecretAccessKeyKey                    = "cold-storage-s3-secret-access-key"
+	ColdStorageDepthKey                                = "cold-storage-depth"
+	ColdStorageCacheSizeKey                            = "cold-storage-cache-size"
 	PublicIPKey                                        = "public-ip"
 	DynamicUpdateDurationKey                           = "dynamic-update-duration"
 	DynamicPublicIPResolverKey                         = "dynamic-public-ip"
@@ -40,6 +50,7 @@ const (
 	InboundThrottlerMaxConnsPerSecKey                  = "inbound-connection-throttling-max-conns-per-sec"
 	OutboundConnectionThrottlingRps                    = "outbound-connection-throttling-rps"
 	OutboundConnectionTimeout                          = "outbound-connection-timeout"
+	NetworkTCPKeepAlivePeriodKey                       = "network-tcp-keepalive-period"
 	HTTPHostKey                                        = "http-host"
 	HTTPPortKey                                        = "http-port"
 	HTTPSEnabledKey                                    = "http-tls-enabled"
@@ -47,7 +58,12 @@ const (
 	HTTPSKeyContentKey                                 = "http-tls-key-file-content"
 	HTTPSCertFileKey                                   = "http-tls-cert-file"
 	HTTPSCertContentKey                                = "http-tls-cert-file-content"
+	HTTPSClientCAFileKey                               = "http-tls-client-ca-file"
+	HTTPSClientScopesKey                               = "http-tls-client-scopes"
 	HTTPAllowedOrigins                                 = "http-allowed-origins"
+	HTTPEndpointAllowedOriginsKey                      = "http-endpoint-allowed-origins"
+	HTTPTrustedProxiesKey                              = "http-trusted-proxies"
+	HTTPSlowRequestThresholdKey                        = "http-slow-request-threshold"
 	HTTPShutdownTimeoutKey                             = "http-shutdown-timeout"
 	HTTPShutdownWaitKey                                = "http-shutdown-wait"
 	APIAuthRequiredKey                                 = "api-auth-required"
@@ -80,17 +96,29 @@ const (
 	NetworkPeerListNonValidatorGossipSizeKey           = "network-peer-list-non-validator-gossip-size"
 	NetworkPeerListPeersGossipSizeKey                  = "network-peer-list-peers-gossip-size"
 	NetworkPeerListGossipFreqKey                       = "network-peer-list-gossip-frequency"
+	NetworkValidatorSnapshotEpochKey                   = "network-validator-snapshot-epoch"
 	NetworkInitialReconnectDelayKey                    = "network-initial-reconnect-delay"
 	NetworkReadHandshakeTimeoutKey                     = "network-read-handshake-timeout"
 	NetworkPingTimeoutKey                              = "network-ping-timeout"
 	NetworkPingFrequencyKey                            = "network-ping-frequency"
+	NetworkDeadPeerTimeoutKey                          = "network-dead-peer-timeout"
+	NetworkMaxPersistedPeersKey                        = "network-max-persisted-peers"
 	NetworkMaxReconnectDelayKey                        = "network-max-reconnect-delay"
 	NetworkCompressionEnabledKey                       = "network-compression-enabled"
 	NetworkMaxClockDifferenceKey                       = "network-max-clock-difference"
 	NetworkAllowPrivateIPsKey                          = "network-allow-private-ips"
 	NetworkRequireValidatorToConnectKey                = "network-require-validator-to-connect"
+	NetworkAllowlistEnabledKey                         = "network-allowlist-enabled"
+	NetworkAllowlistNodeIDsKey                         = "network-allowlist-node-ids"
+	NetworkAllowlistValidatorsKey                      = "network-allowlist-validators"
 	NetworkPeerReadBufferSizeKey                       = "network-peer-read-buffer-size"
 	NetworkPeerWriteBufferSizeKey                      = "network-peer-write-buffer-size"
+	NetworkPeerMinVersionKey                           = "network-peer-min-version"
+	NetworkPeerMaxVersionKey                           = "network-peer-max-version"
+	NetworkPeerFeatureVersionsKey                      = "network-peer-feature-versions"
+	NetworkLightClientEnabledKey                       = "network-light-client-enabled"
+	NetworkLightClientMaxHeadersKey                    = "network-light-client-max-headers"
+	NetworkLightClientRequestsPerSecKey                = "network-light-client-requests-per-sec"
 	BenchlistFailThresholdKey                          = "benchlist-fail-threshold"
 	BenchlistDurationKey                               = "benchlist-duration"
 	BenchlistMinFailingDurationKey                     = "benchlist-min-failing-duration"
@@ -110,16 +138,42 @@ const (
 	SnowOptimalProcessingKey                           = "snow-optimal-processing"
 	SnowMaxProcessingKey                               = "snow-max-processing"
 	SnowMaxTimeProcessingKey                           = "snow-max-time-processing"
+	ReadOnlyReplicaEnabledKey                          = "read-only-replica-enabled"
+	FailoverEnabledKey                                 = "failover-enabled"
+	FailoverPrimaryKey                                 = "failover-primary"
+	FailoverPeerURIKey                                 = "failover-peer-uri"
+	FailoverHeartbeatFrequencyKey                      = "failover-heartbeat-frequency"
+	FailoverLeaseDurationKey                           = "failover-lease-duration"
 	WhitelistedSubnetsKey                              = "whitelisted-subnets"
 	AdminAPIEnabledKey                                 = "api-admin-enabled"
 	InfoAPIEnabledKey                                  = "api-info-enabled"
 	KeystoreAPIEnabledKey                              = "api-keystore-enabled"
 	MetricsAPIEnabledKey                               = "api-metrics-enabled"
 	HealthAPIEnabledKey                                = "api-health-enabled"
+	EvidenceAPIEnabledKey                              = "api-evidence-enabled"
+	NetworkingAPIEnabledKey                            = "api-networking-enabled"
+	WalletAPIEnabledKey                                = "api-wallet-enabled"
+	WalletScanFrequencyKey                             = "wallet-scan-frequency"
 	IpcAPIEnabledKey                                   = "api-ipcs-enabled"
+	DocAPIEnabledKey                                   = "api-doc-enabled"
 	IpcsChainIDsKey                                    = "ipcs-chain-ids"
 	IpcsPathKey                                        = "ipcs-path"
 	MeterVMsEnabledKey                                 = "meter-vms-enabled"
+	VMCPUThrottlerPortionKey                           = "vm-cpu-throttler-portion"
+	VMCPUThrottlerMaxBurstKey                          = "vm-cpu-throttler-max-burst"
+	MetricsPushURLKey                                  = "metrics-push-url"
+	MetricsPushProtocolKey                             = "metrics-push-protocol"
+	MetricsPushIntervalKey                             = "metrics-push-interval"
+	MetricsPushJobKey                                  = "metrics-push-job"
+	MetricsPushLabelsKey                               = "metrics-push-labels"
+	AlertsWebhookURLKey                                = "alerts-webhook-url"
+	AlertsWebhookFormatKey                             = "alerts-webhook-format"
+	AlertsPagerDutyRoutingKeyKey                       = "alerts-pagerduty-routing-key"
+	AlertsCheckFrequencyKey                            = "alerts-check-frequency"
+	AlertsCooldownKey                                  = "alerts-cooldown"
+	AlertsMinDiskSpaceKey                              = "alerts-min-disk-space"
+	AlertsMetricRulesKey                               = "alerts-metric-rules"
+	DecidedBlockCacheSizeKey                           = "decided-block-cache-size"
 	ConsensusGossipFrequencyKey                        = "consensus-gossip-frequency"
 	ConsensusGossipAcceptedFrontierValidatorSizeKey    = "consensus-accepted-frontier-gossip-validator-size"
 	ConsensusGossipAcceptedFrontierNonValidatorSizeKey = "consensus-accepted-frontier-gossip-non-validator-size"
@@ -131,21 +185,36 @@ const (
 	AppGossipNonValidatorSizeKey                       = "consensus-app-gossip-non-validator-size"
 	AppGossipPeerSizeKey                               = "consensus-app-gossip-peer-size"
 	ConsensusShutdownTimeoutKey                        = "consensus-shutdown-timeout"
+	ConsensusBatchQueriesEnabledKey                    = "consensus-batch-queries-enabled"
+	ConsensusFinalityStallTimeoutKey                   = "consensus-finality-stall-timeout"
 	FdLimitKey                                         = "fd-limit"
 	IndexEnabledKey                                    = "index-enabled"
 	IndexAllowIncompleteKey                            = "index-allow-incomplete"
 	ResetProposerVMHeightIndexKey                      = "reset-proposervm-height-index"
+	StopProposingOnDuplicateIdentityKey                = "stop-proposing-on-duplicate-identity"
+	ProposerVMUsePeerTimeKey                           = "proposervm-use-peer-time"
+	InvariantChecksKey                                 = "invariant-checks"
+	ReplayChainKey                                     = "replay-chain"
+	ReplayFromHeightKey                                = "replay-from-height"
+	FaultInjectionScriptFileKey                        = "fault-injection-script-file"
 	RouterHealthMaxDropRateKey                         = "router-health-max-drop-rate"
 	RouterHealthMaxOutstandingRequestsKey              = "router-health-max-outstanding-requests"
+	RouterMessagePolicyFileKey                         = "router-message-policy-file"
+	RouterDroppedMessagesHistorySizeKey                = "router-dropped-messages-history-size"
+	RouterDroppedMessagesLogFrequencyKey               = "router-dropped-messages-log-frequency"
 	HealthCheckFreqKey                                 = "health-check-frequency"
 	HealthCheckAveragerHalflifeKey                     = "health-check-averager-halflife"
 	RetryBootstrapKey                                  = "bootstrap-retry-enabled"
 	RetryBootstrapWarnFrequencyKey                     = "bootstrap-retry-warn-frequency"
+	BootstrapOnlyKey                                   = "bootstrap-only"
+	BootstrapFrontierQuorumKey                         = "bootstrap-frontier-quorum"
 	PluginModeKey                                      = "plugin-mode-enabled"
 	BootstrapBeaconConnectionTimeoutKey                = "bootstrap-beacon-connection-timeout"
 	BootstrapMaxTimeGetAncestorsKey                    = "boostrap-max-time-get-ancestors"
 	BootstrapAncestorsMaxContainersSentKey             = "bootstrap-ancestors-max-containers-sent"
 	BootstrapAncestorsMaxContainersReceivedKey         = "bootstrap-ancestors-max-containers-received"
+	BootstrapEraImportDirKey                           = "bootstrap-era-import-dir"
+	BootstrapJobsMemoryBudgetKey                       = "bootstrap-jobs-memory-budget"
 	ChainConfigDirKey                                  = "chain-config-dir"
 	ChainConfigContentKey                              = "chain-config-content"
 	SubnetConfigDirKey                                 = "subnet-config-dir"
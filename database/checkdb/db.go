@@ -0,0 +1,154 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package checkdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var (
+	ErrInvariantViolated = errors.New("database invariant violated")
+
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+)
+
+// Database reads back every write it makes: a key that was put must be read
+// with the value it was put with, and a key that was deleted must no longer be
+// found. A write that isn't read back fails with ErrInvariantViolated.
+//
+// The writes are serialized, so that a write can't be overwritten before it's
+// read back. Writing to a Database is much slower than writing to the database
+// it wraps, so it's only meant to catch bugs in the database layers.
+type Database struct {
+	database.Database
+
+	lock sync.Mutex
+}
+
+// New returns [db] with its writes read back
+func New(db database.Database) *Database {
+	return &Database{Database: db}
+}
+
+func (db *Database) Put(key, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if err := db.Database.Put(key, value); err != nil {
+		return err
+	}
+	return db.check(keyValue{key: key, value: value})
+}
+
+func (db *Database) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if err := db.Database.Delete(key); err != nil {
+		return err
+	}
+	return db.check(keyValue{key: key, delete: true})
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+// check returns an error if [kv] isn't read back from the database.
+// Assumes [db.lock] is held
+func (db *Database) check(kv keyValue) error {
+	value, err := db.Database.Get(kv.key)
+	switch {
+	case err == database.ErrNotFound && kv.delete:
+		return nil
+	case err == database.ErrNotFound:
+		return fmt.Errorf("%w: key 0x%x was put but isn't found", ErrInvariantViolated, kv.key)
+	case err != nil:
+		return err
+	case kv.delete:
+		return fmt.Errorf("%w: key 0x%x was deleted but is still found", ErrInvariantViolated, kv.key)
+	case !bytes.Equal(value, kv.value):
+		return fmt.Errorf("%w: key 0x%x was put with value 0x%x but is read with value 0x%x", ErrInvariantViolated, kv.key, kv.value, value)
+	default:
+		return nil
+	}
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch records its writes so that they can be read back once it's written
+type batch struct {
+	database.Batch
+	db     *Database
+	writes []keyValue
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), utils.CopyBytes(value), false})
+	return b.Batch.Put(key, value)
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), nil, true})
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+
+	// The last write of a key determines what is read back
+	last := make(map[string]keyValue, len(b.writes))
+	for _, kv := range b.writes {
+		last[string(kv.key)] = kv
+	}
+	for _, kv := range last {
+		if err := b.db.check(kv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *batch) Reset() {
+	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
+		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
+	} else {
+		b.writes = b.writes[:0]
+	}
+	b.Batch.Reset()
+}
+
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *batch) Inner() database.Batch { return b }
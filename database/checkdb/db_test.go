@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package checkdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		test(t, New(memdb.New()))
+	}
+}
+
+// lossyDB drops the puts of [lostKey]
+type lossyDB struct {
+	database.Database
+	lostKey string
+}
+
+func (db *lossyDB) Put(key, value []byte) error {
+	if string(key) == db.lostKey {
+		return nil
+	}
+	return db.Database.Put(key, value)
+}
+
+func (db *lossyDB) NewBatch() database.Batch {
+	return &lossyBatch{Batch: db.Database.NewBatch(), db: db}
+}
+
+type lossyBatch struct {
+	database.Batch
+	db *lossyDB
+}
+
+func (b *lossyBatch) Put(key, value []byte) error {
+	if string(key) == b.db.lostKey {
+		return nil
+	}
+	return b.Batch.Put(key, value)
+}
+
+func TestInvariantViolated(t *testing.T) {
+	assert := assert.New(t)
+
+	db := New(&lossyDB{Database: memdb.New(), lostKey: "lost"})
+	assert.NoError(db.Put([]byte("kept"), []byte{1}))
+	err := db.Put([]byte("lost"), []byte{1})
+	assert.True(errors.Is(err, ErrInvariantViolated))
+
+	// Only the last write of a key in a batch has to be read back
+	batch := db.NewBatch()
+	assert.NoError(batch.Put([]byte("kept"), []byte{2}))
+	assert.NoError(batch.Delete([]byte("kept")))
+	assert.NoError(batch.Write())
+	has, err := db.Has([]byte("kept"))
+	assert.NoError(err)
+	assert.False(has)
+
+	batch.Reset()
+	assert.NoError(batch.Put([]byte("kept"), []byte{3}))
+	assert.NoError(batch.Put([]byte("lost"), []byte{3}))
+	err = batch.Write()
+	assert.True(errors.Is(err, ErrInvariantViolated))
+}
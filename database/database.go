@@ -71,6 +71,26 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// RangeDeleter wraps the DeleteRange method of a backing data store.
+type RangeDeleter interface {
+	// DeleteRange removes all keys in the range [start, end).
+	//
+	// A nil start is treated as a key before all keys in the DB.
+	// And a nil end is treated as a key after all keys in the DB.
+	DeleteRange(start []byte, end []byte) error
+}
+
+// SizeEstimator wraps the EstimateSize method of a backing data store.
+type SizeEstimator interface {
+	// EstimateSize returns the approximate number of bytes used to store the
+	// keys in the range [start, end). The estimate may not include recent
+	// writes that haven't been flushed to disk.
+	//
+	// A nil start is treated as a key before all keys in the DB.
+	// And a nil end is treated as a key after all keys in the DB.
+	EstimateSize(start []byte, end []byte) (uint64, error)
+}
+
 // Database contains all the methods required to allow handling different
 // key-value data stores backing the database.
 type Database interface {
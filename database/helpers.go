@@ -4,12 +4,14 @@
 package database
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
 )
 
 var errWrongSize = errors.New("value has unexpected size")
@@ -17,6 +19,10 @@ var errWrongSize = errors.New("value has unexpected size")
 const (
 	// kvPairOverhead is an estimated overhead for a kv pair in a database.
 	kvPairOverhead = 8 // bytes
+
+	// MaxRangeDeleteBatchSize is the number of bytes of deletions that are
+	// buffered before being written when deleting a range of keys.
+	MaxRangeDeleteBatchSize = 128 * units.KiB
 )
 
 func PutID(db KeyValueWriter, key []byte, val ids.ID) error {
@@ -169,3 +175,58 @@ func ClearPrefix(readerDB Iteratee, deleterDB KeyValueDeleter, prefix []byte) er
 	}
 	return iterator.Error()
 }
+
+// DeleteRange removes all keys of [db] in the range [start, end). If [db]
+// doesn't support range deletion, the keys are deleted in batches.
+func DeleteRange(db Database, start, end []byte) error {
+	if deleter, ok := db.(RangeDeleter); ok {
+		return deleter.DeleteRange(start, end)
+	}
+
+	iterator := db.NewIteratorWithStart(start)
+	defer iterator.Release()
+
+	batch := db.NewBatch()
+	for iterator.Next() {
+		key := iterator.Key()
+		if end != nil && bytes.Compare(key, end) >= 0 {
+			break
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		if batch.Size() < MaxRangeDeleteBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := iterator.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// EstimateSize returns the approximate number of bytes used to store the keys
+// of [db] in the range [start, end). If [db] doesn't support size estimation,
+// the keys in the range are iterated over.
+func EstimateSize(db Database, start, end []byte) (uint64, error) {
+	if estimator, ok := db.(SizeEstimator); ok {
+		return estimator.EstimateSize(start, end)
+	}
+
+	iterator := db.NewIteratorWithStart(start)
+	defer iterator.Release()
+
+	size := uint64(0)
+	for iterator.Next() {
+		key := iterator.Key()
+		if end != nil && bytes.Compare(key, end) >= 0 {
+			break
+		}
+		size += uint64(len(key) + len(iterator.Value()) + kvPairOverhead)
+	}
+	return size, iterator.Error()
+}
@@ -43,9 +43,11 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iter{}
+	_ database.Database      = &Database{}
+	_ database.RangeDeleter  = &Database{}
+	_ database.SizeEstimator = &Database{}
+	_ database.Batch         = &batch{}
+	_ database.Iterator      = &iter{}
 )
 
 // Database is a persistent key-value store. Apart from basic data storage
@@ -273,6 +275,42 @@ func (db *Database) Compact(start []byte, limit []byte) error {
 	return updateError(db.DB.CompactRange(util.Range{Start: start, Limit: limit}))
 }
 
+// DeleteRange removes all keys in the range [start, end). The underlying
+// levelDB doesn't support range tombstones, so the keys are deleted with as few
+// batch writes as possible.
+func (db *Database) DeleteRange(start []byte, end []byte) error {
+	it := db.DB.NewIterator(&util.Range{Start: start, Limit: end}, nil)
+	defer it.Release()
+
+	b := batch{db: db}
+	for it.Next() {
+		if err := b.Delete(it.Key()); err != nil {
+			return err
+		}
+		if b.Size() < database.MaxRangeDeleteBatchSize {
+			continue
+		}
+		if err := b.Write(); err != nil {
+			return err
+		}
+		b.Reset()
+	}
+	if err := it.Error(); err != nil {
+		return updateError(err)
+	}
+	return b.Write()
+}
+
+// EstimateSize returns the approximate file system space used by the keys in
+// the range [start, end). Keys that are only in the memtable aren't included.
+func (db *Database) EstimateSize(start []byte, end []byte) (uint64, error) {
+	sizes, err := db.DB.SizeOf([]util.Range{{Start: start, Limit: end}})
+	if err != nil {
+		return 0, updateError(err)
+	}
+	return uint64(sizes.Sum()), nil
+}
+
 func (db *Database) Close() error {
 	db.closed.SetValue(true)
 	return updateError(db.DB.Close())
@@ -22,9 +22,11 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iterator{}
+	_ database.Database      = &Database{}
+	_ database.RangeDeleter  = &Database{}
+	_ database.SizeEstimator = &Database{}
+	_ database.Batch         = &batch{}
+	_ database.Iterator      = &iterator{}
 )
 
 // Database is an ephemeral key-value store that implements the Database
@@ -147,6 +149,45 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
+func (db *Database) DeleteRange(start, end []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	for key := range db.db {
+		if inRange(key, start, end) {
+			delete(db.db, key)
+		}
+	}
+	return nil
+}
+
+// EstimateSize returns the number of bytes of the keys and values in the range
+// [start, end)
+func (db *Database) EstimateSize(start, end []byte) (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+	size := uint64(0)
+	for key, value := range db.db {
+		if inRange(key, start, end) {
+			size += uint64(len(key) + len(value))
+		}
+	}
+	return size, nil
+}
+
+// inRange returns true if [key] is in the range [start, end). A nil [end] is
+// treated as a key after all keys.
+func inRange(key string, start, end []byte) bool {
+	return key >= string(start) && (end == nil || key < string(end))
+}
+
 func (db *Database) Stat(property string) (string, error) { return "", database.ErrNotFound }
 
 func (db *Database) Compact(start []byte, limit []byte) error {
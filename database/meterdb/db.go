@@ -11,9 +11,11 @@ import (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iterator{}
+	_ database.Database      = &Database{}
+	_ database.RangeDeleter  = &Database{}
+	_ database.SizeEstimator = &Database{}
+	_ database.Batch         = &batch{}
+	_ database.Iterator      = &iterator{}
 )
 
 // Database tracks the amount of time each operation takes and how many bytes
@@ -127,6 +129,22 @@ func (db *Database) Compact(start, limit []byte) error {
 	return err
 }
 
+func (db *Database) DeleteRange(start, end []byte) error {
+	startTime := db.clock.Time()
+	err := database.DeleteRange(db.db, start, end)
+	endTime := db.clock.Time()
+	db.deleteRange.Observe(float64(endTime.Sub(startTime)))
+	return err
+}
+
+func (db *Database) EstimateSize(start, end []byte) (uint64, error) {
+	startTime := db.clock.Time()
+	size, err := database.EstimateSize(db.db, start, end)
+	endTime := db.clock.Time()
+	db.estimateSize.Observe(float64(endTime.Sub(startTime)))
+	return size, err
+}
+
 func (db *Database) Close() error {
 	start := db.clock.Time()
 	err := db.db.Close()
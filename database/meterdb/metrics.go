@@ -47,6 +47,8 @@ type metrics struct {
 	newIterator,
 	stat,
 	compact,
+	deleteRange,
+	estimateSize,
 	close,
 	bPut,
 	bPutSize,
@@ -85,6 +87,8 @@ func (m *metrics) Initialize(
 	m.newIterator = newTimeMetric(namespace, "new_iterator", reg, &errs)
 	m.stat = newTimeMetric(namespace, "stat", reg, &errs)
 	m.compact = newTimeMetric(namespace, "compact", reg, &errs)
+	m.deleteRange = newTimeMetric(namespace, "delete_range", reg, &errs)
+	m.estimateSize = newTimeMetric(namespace, "estimate_size", reg, &errs)
 	m.close = newTimeMetric(namespace, "close", reg, &errs)
 	m.bPut = newTimeMetric(namespace, "batch_put", reg, &errs)
 	m.bPutSize = newSizeMetric(namespace, "batch_put", reg, &errs)
@@ -0,0 +1,192 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	ErrNewerVersion = errors.New("database was written by a newer version of the node")
+
+	errDuplicateMigration = errors.New("migration was already registered")
+	errNonSequential      = errors.New("migration versions must be sequential")
+
+	versionKey = []byte("version")
+	markerKey  = []byte("marker")
+
+	_ Manager  = &manager{}
+	_ Progress = &progress{}
+)
+
+// Migration upgrades a schema from [Version]-1 to [Version].
+type Migration struct {
+	// Version of the schema once this migration has run. The first migration
+	// of a schema has version 1.
+	Version uint32
+	// Name is used to report the progress of the migration.
+	Name string
+	// Migrate upgrades the data in [db]. If the node stops before Migrate
+	// returns, Migrate is called again on startup with the marker of the last
+	// checkpoint, so writes made after that checkpoint may be repeated.
+	Migrate func(db database.Database, progress Progress) error
+}
+
+// Progress allows a migration to be resumed if it's interrupted.
+type Progress interface {
+	// Marker returns the marker of the last checkpoint of the migration, or
+	// nil if the migration hasn't made a checkpoint.
+	Marker() []byte
+
+	// Checkpoint persists [marker], so that the migration can be resumed from
+	// it, and reports that [done] out of [total] units of work are complete.
+	Checkpoint(marker []byte, done, total uint64) error
+}
+
+// Manager tracks the version of each schema and runs the migrations that
+// upgrade them to the version this node expects.
+type Manager interface {
+	// Register adds [migration] to [schema]. Migrations of a schema must be
+	// registered in order of their versions.
+	Register(schema string, migration Migration) error
+
+	// Version returns the version [schema] is stored at. A schema that was
+	// never migrated is at version 0.
+	Version(schema string) (uint32, error)
+
+	// Migrate runs the migrations of [schema] on [db] that haven't run yet.
+	// [db] is expected to be new if no version of [schema] was recorded.
+	// Returns ErrNewerVersion if [schema] is stored at a version that this
+	// node doesn't have a migration for.
+	Migrate(schema string, db database.Database) error
+}
+
+type manager struct {
+	lock sync.Mutex
+	log  logging.Logger
+	// db stores the version and progress of each schema
+	db database.Database
+	// schema -> migrations, ordered by version
+	migrations map[string][]Migration
+}
+
+// NewManager returns a manager that records the versions of the schemas it
+// migrates in [db].
+func NewManager(log logging.Logger, db database.Database) Manager {
+	return &manager{
+		log:        log,
+		db:         db,
+		migrations: make(map[string][]Migration),
+	}
+}
+
+func (m *manager) Register(schema string, migration Migration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	migrations := m.migrations[schema]
+	expectedVersion := uint32(len(migrations)) + 1
+	switch {
+	case migration.Version < expectedVersion:
+		return fmt.Errorf("%w: %s version %d", errDuplicateMigration, schema, migration.Version)
+	case migration.Version > expectedVersion:
+		return fmt.Errorf("%w: expected %s version %d but got %d", errNonSequential, schema, expectedVersion, migration.Version)
+	}
+	m.migrations[schema] = append(migrations, migration)
+	return nil
+}
+
+func (m *manager) Version(schema string) (uint32, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.version(m.schemaDB(schema))
+}
+
+func (m *manager) Migrate(schema string, db database.Database) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	schemaDB := m.schemaDB(schema)
+	currentVersion, err := m.version(schemaDB)
+	if err != nil {
+		return err
+	}
+
+	migrations := m.migrations[schema]
+	latestVersion := uint32(len(migrations))
+	if currentVersion > latestVersion {
+		return fmt.Errorf("%w: %s is at version %d but this node supports up to version %d",
+			ErrNewerVersion, schema, currentVersion, latestVersion)
+	}
+
+	for _, migration := range migrations[currentVersion:] {
+		marker, err := schemaDB.Get(markerKey)
+		if err == database.ErrNotFound {
+			m.log.Info("running migration %q of %s to version %d", migration.Name, schema, migration.Version)
+		} else if err != nil {
+			return err
+		} else {
+			m.log.Info("resuming migration %q of %s to version %d", migration.Name, schema, migration.Version)
+		}
+
+		progress := &progress{
+			log:       m.log,
+			db:        schemaDB,
+			schema:    schema,
+			migration: migration,
+			marker:    marker,
+		}
+		if err := migration.Migrate(db, progress); err != nil {
+			return fmt.Errorf("migration %q of %s to version %d failed: %w", migration.Name, schema, migration.Version, err)
+		}
+
+		if err := database.PutUInt32(schemaDB, versionKey, migration.Version); err != nil {
+			return err
+		}
+		if err := schemaDB.Delete(markerKey); err != nil {
+			return err
+		}
+		m.log.Info("finished migration %q of %s to version %d", migration.Name, schema, migration.Version)
+	}
+	return nil
+}
+
+func (m *manager) schemaDB(schema string) database.Database {
+	return prefixdb.New([]byte(schema), m.db)
+}
+
+func (m *manager) version(schemaDB database.Database) (uint32, error) {
+	version, err := database.GetUInt32(schemaDB, versionKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return version, err
+}
+
+type progress struct {
+	log       logging.Logger
+	db        database.Database
+	schema    string
+	migration Migration
+	marker    []byte
+}
+
+func (p *progress) Marker() []byte { return p.marker }
+
+func (p *progress) Checkpoint(marker []byte, done, total uint64) error {
+	if err := p.db.Put(markerKey, marker); err != nil {
+		return err
+	}
+	p.marker = marker
+	p.log.Info("migration %q of %s to version %d has completed %d of %d",
+		p.migration.Name, p.schema, p.migration.Version, done, total)
+	return nil
+}
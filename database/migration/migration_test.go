@@ -0,0 +1,131 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestRegister(t *testing.T) {
+	assert := assert.New(t)
+
+	m := NewManager(logging.NoLog{}, memdb.New())
+	assert.ErrorIs(m.Register("schema", Migration{Version: 2}), errNonSequential)
+	assert.NoError(m.Register("schema", Migration{Version: 1}))
+	assert.ErrorIs(m.Register("schema", Migration{Version: 1}), errDuplicateMigration)
+	assert.NoError(m.Register("schema", Migration{Version: 2}))
+
+	// Schemas are versioned independently
+	assert.NoError(m.Register("other", Migration{Version: 1}))
+}
+
+func TestMigrate(t *testing.T) {
+	assert := assert.New(t)
+
+	metaDB := memdb.New()
+	db := memdb.New()
+	key := []byte("key")
+
+	var ran []uint32
+	newManager := func(numMigrations int) Manager {
+		m := NewManager(logging.NoLog{}, metaDB)
+		for i := 1; i <= numMigrations; i++ {
+			version := uint32(i)
+			assert.NoError(m.Register("schema", Migration{
+				Version: version,
+				Name:    "test",
+				Migrate: func(db database.Database, _ Progress) error {
+					ran = append(ran, version)
+					return database.PutUInt32(db, key, version)
+				},
+			}))
+		}
+		return m
+	}
+
+	m := newManager(2)
+	version, err := m.Version("schema")
+	assert.NoError(err)
+	assert.EqualValues(0, version)
+
+	assert.NoError(m.Migrate("schema", db))
+	assert.Equal([]uint32{1, 2}, ran)
+	version, err = m.Version("schema")
+	assert.NoError(err)
+	assert.EqualValues(2, version)
+
+	// Migrations only run once
+	assert.NoError(m.Migrate("schema", db))
+	assert.Equal([]uint32{1, 2}, ran)
+
+	// Only the new migrations run after an upgrade
+	m = newManager(3)
+	assert.NoError(m.Migrate("schema", db))
+	assert.Equal([]uint32{1, 2, 3}, ran)
+	value, err := database.GetUInt32(db, key)
+	assert.NoError(err)
+	assert.EqualValues(3, value)
+
+	// Older nodes refuse to run on the migrated database
+	m = newManager(2)
+	assert.ErrorIs(m.Migrate("schema", db), ErrNewerVersion)
+	assert.Equal([]uint32{1, 2, 3}, ran)
+}
+
+func TestMigrateResumes(t *testing.T) {
+	assert := assert.New(t)
+
+	metaDB := memdb.New()
+	db := memdb.New()
+	errInterrupted := errors.New("interrupted")
+
+	var markers [][]byte
+	interrupt := true
+	newManager := func() Manager {
+		m := NewManager(logging.NoLog{}, metaDB)
+		assert.NoError(m.Register("schema", Migration{
+			Version: 1,
+			Name:    "test",
+			Migrate: func(db database.Database, progress Progress) error {
+				markers = append(markers, progress.Marker())
+				if progress.Marker() == nil {
+					if err := progress.Checkpoint([]byte{1}, 1, 2); err != nil {
+						return err
+					}
+				}
+				if interrupt {
+					return errInterrupted
+				}
+				return progress.Checkpoint([]byte{2}, 2, 2)
+			},
+		}))
+		return m
+	}
+
+	assert.ErrorIs(newManager().Migrate("schema", db), errInterrupted)
+	version, err := newManager().Version("schema")
+	assert.NoError(err)
+	assert.EqualValues(0, version)
+
+	// The migration is resumed from its last checkpoint
+	interrupt = false
+	assert.NoError(newManager().Migrate("schema", db))
+	assert.Equal([][]byte{nil, {1}}, markers)
+	version, err = newManager().Version("schema")
+	assert.NoError(err)
+	assert.EqualValues(1, version)
+
+	// The marker is cleared once the migration finishes
+	has, err := prefixdb.New([]byte("schema"), metaDB).Has(markerKey)
+	assert.NoError(err)
+	assert.False(has)
+}
@@ -16,9 +16,11 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iterator{}
+	_ database.Database      = &Database{}
+	_ database.RangeDeleter  = &Database{}
+	_ database.SizeEstimator = &Database{}
+	_ database.Batch         = &batch{}
+	_ database.Iterator      = &iterator{}
 )
 
 // Database partitions a database into a sub-database by prefixing all keys with
@@ -182,7 +184,49 @@ func (db *Database) Compact(start, limit []byte) error {
 	if db.db == nil {
 		return database.ErrClosed
 	}
-	return db.db.Compact(db.prefix(start), db.prefix(limit))
+	// A nil [limit] compacts every key after [start], so the limit is the end
+	// of the prefix rather than the prefix itself.
+	prefixedStart := db.prefix(start)
+	prefixedLimit := db.prefixEnd(limit)
+	err := db.db.Compact(prefixedStart, prefixedLimit)
+	db.bufferPool.Put(prefixedStart)
+	db.bufferPool.Put(prefixedLimit)
+	return err
+}
+
+// DeleteRange removes all keys in the range [start, end) of this database. If
+// the underlying database doesn't support range deletion, the keys are deleted
+// in batches.
+func (db *Database) DeleteRange(start, end []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	prefixedStart := db.prefix(start)
+	prefixedEnd := db.prefixEnd(end)
+	err := database.DeleteRange(db.db, prefixedStart, prefixedEnd)
+	db.bufferPool.Put(prefixedStart)
+	db.bufferPool.Put(prefixedEnd)
+	return err
+}
+
+// EstimateSize returns the approximate number of bytes used to store the keys
+// in the range [start, end) of this database
+func (db *Database) EstimateSize(start, end []byte) (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+	prefixedStart := db.prefix(start)
+	prefixedEnd := db.prefixEnd(end)
+	size, err := database.EstimateSize(db.db, prefixedStart, prefixedEnd)
+	db.bufferPool.Put(prefixedStart)
+	db.bufferPool.Put(prefixedEnd)
+	return size, err
 }
 
 func (db *Database) Close() error {
@@ -203,6 +247,27 @@ func (db *Database) isClosed() bool {
 	return db.db == nil
 }
 
+// prefixEnd returns the prefixed version of the exclusive range end [end]. A nil
+// [end] is mapped to the first key after all keys with this db's prefix.
+// The returned slice should be put back in the pool
+// when it's done being used.
+func (db *Database) prefixEnd(end []byte) []byte {
+	if end != nil {
+		return db.prefix(end)
+	}
+
+	prefixedEnd := db.prefix(nil)
+	for i := len(prefixedEnd) - 1; i >= 0; i-- {
+		prefixedEnd[i]++
+		if prefixedEnd[i] != 0 {
+			return prefixedEnd[:i+1]
+		}
+	}
+	// The prefix is all 0xff bytes, so there is no key after all the keys with
+	// this db's prefix.
+	return nil
+}
+
 // Return a copy of [key], prepended with this db's prefix.
 // The returned slice should be put back in the pool
 // when it's done being used.
@@ -4,8 +4,11 @@
 package prefixdb
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 )
@@ -22,6 +25,75 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestDeleteRangeIsolation(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := memdb.New()
+	db0 := New([]byte("hello"), baseDB)
+	db1 := New([]byte("world"), baseDB)
+
+	key := []byte("key")
+	value := []byte("value")
+	assert.NoError(db0.Put(key, value))
+	assert.NoError(db1.Put(key, value))
+
+	size0, err := db0.EstimateSize(nil, nil)
+	assert.NoError(err)
+	totalSize, err := baseDB.EstimateSize(nil, nil)
+	assert.NoError(err)
+	assert.Equal(2*size0, totalSize)
+
+	// Deleting all the keys of one database doesn't modify the other
+	assert.NoError(db0.DeleteRange(nil, nil))
+
+	has, err := db0.Has(key)
+	assert.NoError(err)
+	assert.False(has)
+
+	has, err = db1.Has(key)
+	assert.NoError(err)
+	assert.True(has)
+
+	size0, err = db0.EstimateSize(nil, nil)
+	assert.NoError(err)
+	assert.Zero(size0)
+}
+
+// compactRecorder records the ranges it's asked to compact
+type compactRecorder struct {
+	database.Database
+	ranges [][2][]byte
+}
+
+func (db *compactRecorder) Compact(start, limit []byte) error {
+	db.ranges = append(db.ranges, [2][]byte{
+		append([]byte(nil), start...),
+		append([]byte(nil), limit...),
+	})
+	return nil
+}
+
+func TestCompactRange(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := &compactRecorder{Database: memdb.New()}
+	db := New([]byte("hello"), baseDB)
+
+	// Compacting the whole database compacts every key with its prefix, up to
+	// the first key after them
+	assert.NoError(db.Compact(nil, nil))
+	assert.Len(baseDB.ranges, 1)
+	start, limit := baseDB.ranges[0][0], baseDB.ranges[0][1]
+	assert.Equal(db.dbPrefix, start)
+	assert.Equal(1, bytes.Compare(limit, start))
+	assert.False(bytes.HasPrefix(limit, db.dbPrefix))
+
+	assert.NoError(db.Compact([]byte("a"), []byte("b")))
+	assert.Len(baseDB.ranges, 2)
+	assert.Equal(append(append([]byte(nil), db.dbPrefix...), 'a'), baseDB.ranges[1][0])
+	assert.Equal(append(append([]byte(nil), db.dbPrefix...), 'b'), baseDB.ranges[1][1])
+}
+
 func BenchmarkInterface(b *testing.B) {
 	for _, size := range database.BenchmarkSizes {
 		keys, values := database.SetupBenchmark(b, size[0], size[1], size[2])
@@ -0,0 +1,227 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quotadb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("database quota exceeded")
+
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+)
+
+// Quota is the number of bytes of keys and values that the databases sharing
+// it may store
+type Quota struct {
+	// lock is held while a database of the quota writes, so that the usage
+	// can't change between the check of a write and the write
+	lock    sync.Mutex
+	maxSize uint64
+	size    uint64
+}
+
+// NewQuota returns a quota of [maxSize] bytes
+func NewQuota(maxSize uint64) *Quota {
+	return &Quota{maxSize: maxSize}
+}
+
+// Size returns the number of bytes stored by the databases of the quota
+func (q *Quota) Size() uint64 {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.size
+}
+
+// Database refuses the writes that would store more bytes than its quota
+// allows. Writes that don't grow the stored bytes, and deletions, are always
+// allowed, so that a database over its quota can still be cleaned up.
+//
+// The size of the previous value of a key is read before it's written, so
+// writing to a Database is slower than writing to the database it wraps.
+type Database struct {
+	database.Database
+	quota *Quota
+}
+
+// New returns [db] with its writes counted against [quota]. The keys and
+// values already in [db] are iterated over to add their size to [quota].
+func New(quota *Quota, db database.Database) (*Database, error) {
+	it := db.NewIterator()
+	defer it.Release()
+
+	size := uint64(0)
+	for it.Next() {
+		size += uint64(len(it.Key()) + len(it.Value()))
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("couldn't compute the size of the database: %w", err)
+	}
+
+	quota.lock.Lock()
+	quota.size += size
+	quota.lock.Unlock()
+	return &Database{
+		Database: db,
+		quota:    quota,
+	}, nil
+}
+
+func (db *Database) Put(key, value []byte) error {
+	db.quota.lock.Lock()
+	defer db.quota.lock.Unlock()
+
+	oldSize, err := db.size(key)
+	if err != nil {
+		return err
+	}
+	newSize := uint64(len(key) + len(value))
+	if err := db.check(oldSize, newSize); err != nil {
+		return err
+	}
+	if err := db.Database.Put(key, value); err != nil {
+		return err
+	}
+	db.quota.size = db.quota.size - oldSize + newSize
+	return nil
+}
+
+func (db *Database) Delete(key []byte) error {
+	db.quota.lock.Lock()
+	defer db.quota.lock.Unlock()
+
+	oldSize, err := db.size(key)
+	if err != nil {
+		return err
+	}
+	if err := db.Database.Delete(key); err != nil {
+		return err
+	}
+	db.quota.size -= oldSize
+	return nil
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+// size returns the number of bytes that [key] and its value take, or 0 if
+// [key] isn't in the database.
+// Assumes [db.quota.lock] is held
+func (db *Database) size(key []byte) (uint64, error) {
+	value, err := db.Database.Get(key)
+	switch err {
+	case nil:
+		return uint64(len(key) + len(value)), nil
+	case database.ErrNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// check returns an error if replacing [oldSize] bytes by [newSize] bytes
+// grows the quota's usage over its maximum.
+// Assumes [db.quota.lock] is held
+func (db *Database) check(oldSize, newSize uint64) error {
+	if newSize <= oldSize {
+		return nil
+	}
+	if size := db.quota.size - oldSize + newSize; size > db.quota.maxSize {
+		return fmt.Errorf("%w: writing would store %d bytes, more than the quota of %d bytes", ErrQuotaExceeded, size, db.quota.maxSize)
+	}
+	return nil
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch records its writes so that its effect on the quota can be checked
+// when it's written
+type batch struct {
+	database.Batch
+	db     *Database
+	writes []keyValue
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), value, false})
+	return b.Batch.Put(key, value)
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), nil, true})
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	b.db.quota.lock.Lock()
+	defer b.db.quota.lock.Unlock()
+
+	// The last write of a key determines its new size
+	newSizes := make(map[string]uint64, len(b.writes))
+	for _, kv := range b.writes {
+		size := uint64(0)
+		if !kv.delete {
+			size = uint64(len(kv.key) + len(kv.value))
+		}
+		newSizes[string(kv.key)] = size
+	}
+
+	var oldSize, newSize uint64
+	for key, size := range newSizes {
+		keySize, err := b.db.size([]byte(key))
+		if err != nil {
+			return err
+		}
+		oldSize += keySize
+		newSize += size
+	}
+	if err := b.db.check(oldSize, newSize); err != nil {
+		return err
+	}
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+	b.db.quota.size = b.db.quota.size - oldSize + newSize
+	return nil
+}
+
+func (b *batch) Reset() {
+	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
+		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
+	} else {
+		b.writes = b.writes[:0]
+	}
+	b.Batch.Reset()
+}
+
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *batch) Inner() database.Batch { return b }
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quotadb
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		db, err := New(NewQuota(math.MaxUint64), memdb.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test(t, db)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := memdb.New()
+	assert.NoError(baseDB.Put([]byte{1}, []byte{1, 2, 3}))
+
+	quota := NewQuota(10)
+	db, err := New(quota, baseDB)
+	assert.NoError(err)
+	assert.EqualValues(4, quota.Size())
+
+	// Databases sharing a quota count against the same usage
+	otherDB, err := New(quota, memdb.New())
+	assert.NoError(err)
+	assert.NoError(otherDB.Put([]byte{2}, []byte{1, 2}))
+	assert.EqualValues(7, quota.Size())
+
+	err = db.Put([]byte{3}, []byte{1, 2, 3})
+	assert.True(errors.Is(err, ErrQuotaExceeded))
+	assert.EqualValues(7, quota.Size())
+	has, err := db.Has([]byte{3})
+	assert.NoError(err)
+	assert.False(has)
+
+	// Shrinking writes and deletions are allowed
+	assert.NoError(db.Put([]byte{1}, []byte{1}))
+	assert.EqualValues(5, quota.Size())
+	assert.NoError(otherDB.Delete([]byte{2}))
+	assert.EqualValues(2, quota.Size())
+	assert.NoError(db.Delete([]byte{4}))
+	assert.EqualValues(2, quota.Size())
+}
+
+func TestQuotaBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	quota := NewQuota(10)
+	db, err := New(quota, memdb.New())
+	assert.NoError(err)
+
+	batch := db.NewBatch()
+	assert.NoError(batch.Put([]byte{1}, []byte{1, 2, 3, 4, 5}))
+	assert.NoError(batch.Put([]byte{2}, []byte{1, 2, 3, 4, 5}))
+	err = batch.Write()
+	assert.True(errors.Is(err, ErrQuotaExceeded))
+	assert.EqualValues(0, quota.Size())
+
+	// Only the last write of a key counts
+	assert.NoError(batch.Delete([]byte{2}))
+	assert.NoError(batch.Write())
+	assert.EqualValues(6, quota.Size())
+	has, err := db.Has([]byte{2})
+	assert.NoError(err)
+	assert.False(has)
+
+	batch.Reset()
+	assert.NoError(batch.Put([]byte{1}, []byte{1}))
+	assert.NoError(batch.Put([]byte{2}, []byte{1, 2}))
+	assert.NoError(batch.Write())
+	assert.EqualValues(5, quota.Size())
+}
@@ -41,6 +41,8 @@ var Tests = []func(t *testing.T, db Database){
 	TestMemorySafetyBatch,
 	TestClear,
 	TestClearPrefix,
+	TestDeleteRange,
+	TestEstimateSizeNoPanic,
 }
 
 // TestSimpleKeyValue tests to make sure that simple Put + Get + Delete + Has
@@ -1202,3 +1204,48 @@ func TestClearPrefix(t *testing.T, db Database) {
 	err = db.Close()
 	assert.NoError(err)
 }
+
+// TestDeleteRange tests to make sure range deletion works as expected.
+func TestDeleteRange(t *testing.T, db Database) {
+	assert := assert.New(t)
+
+	keys := [][]byte{
+		[]byte("a"),
+		[]byte("b"),
+		[]byte("b1"),
+		[]byte("c"),
+		[]byte("d"),
+	}
+	for _, key := range keys {
+		assert.NoError(db.Put(key, key))
+	}
+
+	assert.NoError(DeleteRange(db, []byte("b"), []byte("c")))
+	for i, expected := range []bool{true, false, false, true, true} {
+		has, err := db.Has(keys[i])
+		assert.NoError(err)
+		assert.Equal(expected, has, "unexpected result for key %s", keys[i])
+	}
+
+	assert.NoError(DeleteRange(db, []byte("c"), nil))
+	count, err := Count(db)
+	assert.NoError(err)
+	assert.Equal(1, count)
+
+	assert.NoError(DeleteRange(db, nil, nil))
+	count, err = Count(db)
+	assert.NoError(err)
+	assert.Equal(0, count)
+}
+
+// TestEstimateSizeNoPanic tests to make sure size estimation doesn't fail on
+// an open database.
+func TestEstimateSizeNoPanic(t *testing.T, db Database) {
+	assert := assert.New(t)
+
+	assert.NoError(db.Put([]byte("hello"), []byte("world")))
+	_, err := EstimateSize(db, nil, nil)
+	assert.NoError(err)
+	_, err = EstimateSize(db, []byte("a"), []byte("z"))
+	assert.NoError(err)
+}
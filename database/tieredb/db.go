@@ -0,0 +1,301 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tieredb
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+	_ database.Iterator = &iterator{}
+)
+
+// Database keeps its values in a local hot database until they are offloaded
+// to an object store. Offloaded values are still returned by reads, which
+// fetch them from the object store and keep the most recently read ones in
+// memory.
+type Database struct {
+	// hot contains the values that haven't been offloaded
+	hot database.Database
+	// offloaded contains the keys whose value is in [store], with empty values
+	offloaded database.Database
+	store     ObjectStore
+
+	// Caches key -> value for offloaded values
+	coldCache cache.Cacher
+}
+
+// New returns a Database whose values are kept in [hot] until they're
+// offloaded to [store]. [offloaded] records which keys were offloaded and must
+// not overlap with [hot]. [cacheSize] is the number of offloaded values kept
+// in memory once read.
+func New(hot, offloaded database.Database, store ObjectStore, cacheSize int) *Database {
+	return &Database{
+		hot:       hot,
+		offloaded: offloaded,
+		store:     store,
+		coldCache: &cache.LRU{Size: cacheSize},
+	}
+}
+
+// Offload moves the value of [key] from the hot database to the object store.
+// Offloading a key that isn't in the hot database is a no-op.
+func (db *Database) Offload(key []byte) error {
+	value, err := db.hot.Get(key)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The value is uploaded before the key is removed from the hot database,
+	// so that the key can be read at any point in time.
+	if err := db.store.Put(objectName(key), value); err != nil {
+		return err
+	}
+	if err := db.offloaded.Put(key, nil); err != nil {
+		return err
+	}
+	return db.hot.Delete(key)
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	has, err := db.hot.Has(key)
+	if err != nil || has {
+		return has, err
+	}
+	return db.offloaded.Has(key)
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	value, err := db.hot.Get(key)
+	if err != database.ErrNotFound {
+		return value, err
+	}
+	return db.getCold(key)
+}
+
+func (db *Database) Put(key []byte, value []byte) error {
+	if err := db.hot.Put(key, value); err != nil {
+		return err
+	}
+	return db.forget(key)
+}
+
+func (db *Database) Delete(key []byte) error {
+	if err := db.hot.Delete(key); err != nil {
+		return err
+	}
+	return db.forget(key)
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.hot.NewBatch(),
+		db:    db,
+	}
+}
+
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &iterator{
+		db:        db,
+		hot:       db.hot.NewIteratorWithStartAndPrefix(start, prefix),
+		offloaded: db.offloaded.NewIteratorWithStartAndPrefix(start, prefix),
+	}
+}
+
+func (db *Database) Stat(property string) (string, error) { return db.hot.Stat(property) }
+
+func (db *Database) Compact(start []byte, limit []byte) error { return db.hot.Compact(start, limit) }
+
+func (db *Database) Close() error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		db.hot.Close(),
+		db.offloaded.Close(),
+	)
+	return errs.Err
+}
+
+// getCold returns the offloaded value of [key]
+func (db *Database) getCold(key []byte) ([]byte, error) {
+	if value, ok := db.coldCache.Get(string(key)); ok {
+		return utils.CopyBytes(value.([]byte)), nil
+	}
+
+	has, err := db.offloaded.Has(key)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, database.ErrNotFound
+	}
+
+	value, err := db.store.Get(objectName(key))
+	if err != nil {
+		return nil, err
+	}
+	db.coldCache.Put(string(key), value)
+	return utils.CopyBytes(value), nil
+}
+
+// forget removes the offloaded value of [key], if there is one, once [key] was
+// written to the hot database
+func (db *Database) forget(key []byte) error {
+	has, err := db.offloaded.Has(key)
+	if err != nil || !has {
+		return err
+	}
+	db.coldCache.Evict(string(key))
+	if err := db.offloaded.Delete(key); err != nil {
+		return err
+	}
+	return db.store.Delete(objectName(key))
+}
+
+// objectName returns the name of the object [key] is offloaded to
+func objectName(key []byte) string { return hex.EncodeToString(key) }
+
+// batch writes to the hot database and removes the offloaded values of the
+// keys it writes
+type batch struct {
+	database.Batch
+	db *Database
+
+	keys [][]byte
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.keys = append(b.keys, utils.CopyBytes(key))
+	return b.Batch.Put(key, value)
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.keys = append(b.keys, utils.CopyBytes(key))
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+	for _, key := range b.keys {
+		if err := b.db.forget(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *batch) Reset() {
+	b.keys = b.keys[:0]
+	b.Batch.Reset()
+}
+
+// iterator merges the keys of the hot database with the offloaded keys. The
+// offloaded values are fetched as the iterator reaches them.
+type iterator struct {
+	db *Database
+
+	hot, offloaded database.Iterator
+	// hotValid and offloadedValid report whether [hot] and [offloaded] are at
+	// a key that hasn't been returned yet
+	hotValid, offloadedValid bool
+	// advanceHot and advanceOffloaded report whether [hot] and [offloaded]
+	// must be moved to their next key before being compared
+	advanceHot, advanceOffloaded bool
+	initialized                  bool
+
+	key, value []byte
+	err        error
+}
+
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.initialized {
+		it.advanceHot = true
+		it.advanceOffloaded = true
+		it.initialized = true
+	}
+	if it.advanceHot {
+		it.hotValid = it.hot.Next()
+		it.advanceHot = false
+	}
+	if it.advanceOffloaded {
+		it.offloadedValid = it.offloaded.Next()
+		it.advanceOffloaded = false
+	}
+
+	switch {
+	case it.hotValid && (!it.offloadedValid || bytes.Compare(it.hot.Key(), it.offloaded.Key()) <= 0):
+		// A key can't be in both databases, but if it was written while the
+		// iterator was open, the hot value is the current one.
+		if it.offloadedValid && bytes.Equal(it.hot.Key(), it.offloaded.Key()) {
+			it.advanceOffloaded = true
+		}
+		it.key = it.hot.Key()
+		it.value = it.hot.Value()
+		it.advanceHot = true
+		return true
+	case it.offloadedValid:
+		key := utils.CopyBytes(it.offloaded.Key())
+		value, err := it.db.getCold(key)
+		if err != nil {
+			it.key = nil
+			it.value = nil
+			it.err = err
+			return false
+		}
+		it.key = key
+		it.value = value
+		it.advanceOffloaded = true
+		return true
+	default:
+		it.key = nil
+		it.value = nil
+		return false
+	}
+}
+
+func (it *iterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	if err := it.hot.Error(); err != nil {
+		return err
+	}
+	return it.offloaded.Error()
+}
+
+func (it *iterator) Key() []byte { return it.key }
+
+func (it *iterator) Value() []byte { return it.value }
+
+func (it *iterator) Release() {
+	it.hot.Release()
+	it.offloaded.Release()
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tieredb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+)
+
+func newTestDatabase() (*Database, *MemoryStore) {
+	baseDB := memdb.New()
+	store := NewMemoryStore()
+	db := New(
+		prefixdb.New([]byte("hot"), baseDB),
+		prefixdb.New([]byte("offloaded"), baseDB),
+		store,
+		2,
+	)
+	return db, store
+}
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		db, _ := newTestDatabase()
+		test(t, db)
+	}
+}
+
+func TestOffload(t *testing.T) {
+	assert := assert.New(t)
+
+	db, store := newTestDatabase()
+	key := []byte("key")
+	value := []byte("value")
+	assert.NoError(db.Put(key, value))
+	assert.NoError(db.Offload(key))
+	assert.Equal(1, store.Len())
+
+	has, err := db.hot.Has(key)
+	assert.NoError(err)
+	assert.False(has)
+
+	has, err = db.Has(key)
+	assert.NoError(err)
+	assert.True(has)
+
+	// The first read fetches the value from the store, the second one from
+	// the cache
+	for i := 0; i < 2; i++ {
+		got, err := db.Get(key)
+		assert.NoError(err)
+		assert.Equal(value, got)
+		got[0]++
+	}
+
+	// Offloading a key that isn't in the hot database does nothing
+	assert.NoError(db.Offload(key))
+	assert.NoError(db.Offload([]byte("missing")))
+	assert.Equal(1, store.Len())
+}
+
+func TestOffloadedOverwrite(t *testing.T) {
+	assert := assert.New(t)
+
+	db, store := newTestDatabase()
+	key := []byte("key")
+	assert.NoError(db.Put(key, []byte("old")))
+	assert.NoError(db.Offload(key))
+	_, err := db.Get(key)
+	assert.NoError(err)
+
+	assert.NoError(db.Put(key, []byte("new")))
+	assert.Equal(0, store.Len())
+	got, err := db.Get(key)
+	assert.NoError(err)
+	assert.Equal([]byte("new"), got)
+
+	assert.NoError(db.Offload(key))
+	batch := db.NewBatch()
+	assert.NoError(batch.Delete(key))
+	assert.NoError(batch.Write())
+	assert.Equal(0, store.Len())
+
+	has, err := db.Has(key)
+	assert.NoError(err)
+	assert.False(has)
+	_, err = db.Get(key)
+	assert.Equal(database.ErrNotFound, err)
+}
+
+func TestIteratorMergesOffloaded(t *testing.T) {
+	assert := assert.New(t)
+
+	db, _ := newTestDatabase()
+	keys := [][]byte{
+		[]byte("a"),
+		[]byte("b"),
+		[]byte("c"),
+		[]byte("d"),
+	}
+	for _, key := range keys {
+		assert.NoError(db.Put(key, key))
+	}
+	assert.NoError(db.Offload(keys[0]))
+	assert.NoError(db.Offload(keys[2]))
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	for _, key := range keys {
+		assert.True(it.Next())
+		assert.Equal(key, it.Key())
+		assert.Equal(key, it.Value())
+	}
+	assert.False(it.Next())
+	assert.NoError(it.Error())
+
+	count, err := database.Count(db)
+	assert.NoError(err)
+	assert.Equal(len(keys), count)
+}
+
+func TestIteratorMissingObject(t *testing.T) {
+	assert := assert.New(t)
+
+	db, store := newTestDatabase()
+	key := []byte("key")
+	assert.NoError(db.Put(key, key))
+	assert.NoError(db.Offload(key))
+	assert.NoError(store.Delete(objectName(key)))
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	assert.False(it.Next())
+	assert.Equal(database.ErrNotFound, it.Error())
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tieredb
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var _ ObjectStore = &MemoryStore{}
+
+// ObjectStore stores the values offloaded from a Database
+type ObjectStore interface {
+	// Put uploads [value] as the object [name], replacing it if it exists
+	Put(name string, value []byte) error
+
+	// Get returns the object [name], or database.ErrNotFound if it doesn't
+	// exist
+	Get(name string) ([]byte, error)
+
+	// Delete removes the object [name]. Deleting an object that doesn't exist
+	// isn't an error.
+	Delete(name string) error
+}
+
+// MemoryStore is an ObjectStore that keeps the objects in memory
+type MemoryStore struct {
+	lock    sync.RWMutex
+	objects map[string][]byte
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Put(name string, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.objects[name] = utils.CopyBytes(value)
+	return nil
+}
+
+func (s *MemoryStore) Get(name string) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	value, ok := s.objects[name]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return utils.CopyBytes(value), nil
+}
+
+func (s *MemoryStore) Delete(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.objects, name)
+	return nil
+}
+
+// Len returns the number of objects in the store
+func (s *MemoryStore) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.objects)
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tieredb
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+// The example of the AWS documentation on deriving a signing key
+func TestSigningKey(t *testing.T) {
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
+
+func TestS3Store(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		lock    sync.Mutex
+		objects = make(map[string][]byte)
+		paths   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/20220301/us-east-1/s3/aws4_request, ") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("X-Amz-Date") != "20220301T120000Z" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		paths = append(paths, r.URL.Path)
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("X-Amz-Content-Sha256") != hashHex(body) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(body)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "bucket",
+		Prefix:          "chain/",
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+	})
+	assert.NoError(err)
+	store.now = func() time.Time {
+		return time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	_, err = store.Get("object")
+	assert.Equal(database.ErrNotFound, err)
+
+	assert.NoError(store.Put("object", []byte("value")))
+	assert.Equal([]string{"/bucket/chain/object", "/bucket/chain/object"}, paths)
+
+	value, err := store.Get("object")
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+
+	assert.NoError(store.Delete("object"))
+	_, err = store.Get("object")
+	assert.Equal(database.ErrNotFound, err)
+
+	// Errors other than missing objects are reported
+	store.config.AccessKeyID = "other"
+	assert.Error(store.Put("object", []byte("value")))
+	_, err = store.Get("object")
+	assert.Error(err)
+	assert.NotEqual(database.ErrNotFound, err)
+}
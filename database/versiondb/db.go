@@ -40,6 +40,8 @@ type Database struct {
 	mem   map[string]valueDelete
 	db    database.Database
 	batch database.Batch
+	// Snapshots that haven't been released yet
+	snapshots map[*Snapshot]struct{}
 }
 
 type valueDelete struct {
@@ -50,9 +52,10 @@ type valueDelete struct {
 // New returns a new versioned database
 func New(db database.Database) *Database {
 	return &Database{
-		mem:   make(map[string]valueDelete, memdb.DefaultSize),
-		db:    db,
-		batch: db.NewBatch(),
+		mem:       make(map[string]valueDelete, memdb.DefaultSize),
+		db:        db,
+		batch:     db.NewBatch(),
+		snapshots: make(map[*Snapshot]struct{}),
 	}
 }
 
@@ -171,7 +174,8 @@ func (db *Database) Compact(start, limit []byte) error {
 	return db.db.Compact(start, limit)
 }
 
-// SetDatabase changes the underlying database to the specified database
+// SetDatabase changes the underlying database to the specified database.
+// Snapshots created before the change read from the new database.
 func (db *Database) SetDatabase(newDB database.Database) error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -247,6 +251,9 @@ func (db *Database) commitBatch() (database.Batch, error) {
 	if db.mem == nil {
 		return nil, database.ErrClosed
 	}
+	if err := db.preserve(); err != nil {
+		return nil, err
+	}
 
 	db.batch.Reset()
 	for key, value := range db.mem {
@@ -272,6 +279,7 @@ func (db *Database) Close() error {
 	db.batch = nil
 	db.mem = nil
 	db.db = nil
+	db.snapshots = nil
 	return nil
 }
 
@@ -0,0 +1,300 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package versiondb
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var (
+	_ database.KeyValueReader = &Snapshot{}
+	_ database.Iteratee       = &Snapshot{}
+	_ database.Iterator       = &snapshotIterator{}
+)
+
+// Snapshot is a read-only view of a Database, including its uncommitted
+// changes, as of the time the snapshot was created. Later writes, commits and
+// aborts of the Database aren't visible through the snapshot.
+//
+// The snapshot assumes that the underlying database is only modified through
+// the Database it was created from. The snapshot keeps the previous values of
+// committed keys in memory, so it should be released once it is no longer
+// needed.
+type Snapshot struct {
+	db *Database
+
+	// Values that differ from the underlying database. Contains the
+	// uncommitted changes of [db] as of the time the snapshot was created and
+	// the previous values of keys that were committed since then.
+	overlay map[string]valueDelete
+	// Incremented every time keys are added to [overlay]
+	version  uint64
+	released bool
+}
+
+// NewSnapshot returns a snapshot of the current state of this database
+func (db *Database) NewSnapshot() (*Snapshot, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.mem == nil {
+		return nil, database.ErrClosed
+	}
+
+	s := &Snapshot{
+		db:      db,
+		overlay: make(map[string]valueDelete, len(db.mem)),
+	}
+	for key, value := range db.mem {
+		s.overlay[key] = value
+	}
+	db.snapshots[s] = struct{}{}
+	return s, nil
+}
+
+// preserve records, in every open snapshot, the current value of each key in
+// [db.mem] before they are written to the underlying database.
+//
+// Assumes [db.lock] is held.
+func (db *Database) preserve() error {
+	if len(db.snapshots) == 0 {
+		return nil
+	}
+
+	for key := range db.mem {
+		var (
+			previous valueDelete
+			read     bool
+		)
+		for s := range db.snapshots {
+			if _, ok := s.overlay[key]; ok {
+				continue
+			}
+
+			if !read {
+				value, err := db.db.Get([]byte(key))
+				switch err {
+				case nil:
+					previous = valueDelete{value: value}
+				case database.ErrNotFound:
+					previous = valueDelete{delete: true}
+				default:
+					return err
+				}
+				read = true
+			}
+			s.overlay[key] = previous
+			s.version++
+		}
+	}
+	return nil
+}
+
+func (s *Snapshot) Has(key []byte) (bool, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.released || s.db.mem == nil {
+		return false, database.ErrClosed
+	}
+	if val, has := s.overlay[string(key)]; has {
+		return !val.delete, nil
+	}
+	return s.db.db.Has(key)
+}
+
+func (s *Snapshot) Get(key []byte) ([]byte, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.released || s.db.mem == nil {
+		return nil, database.ErrClosed
+	}
+	if val, has := s.overlay[string(key)]; has {
+		if val.delete {
+			return nil, database.ErrNotFound
+		}
+		return utils.CopyBytes(val.value), nil
+	}
+	return s.db.db.Get(key)
+}
+
+func (s *Snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *Snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *Snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *Snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.released || s.db.mem == nil {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+
+	return &snapshotIterator{
+		snapshot: s,
+		Iterator: s.db.db.NewIteratorWithStartAndPrefix(start, prefix),
+		start:    string(start),
+		prefix:   string(prefix),
+		// Ensure that the overlay keys are loaded on the first call to Next
+		version: s.version - 1,
+	}
+}
+
+// Release the snapshot. Reads from the snapshot fail after it is released.
+func (s *Snapshot) Release() {
+	s.db.lock.Lock()
+	defer s.db.lock.Unlock()
+
+	s.released = true
+	s.overlay = nil
+	delete(s.db.snapshots, s)
+}
+
+// snapshotIterator walks over both the snapshot overlay and the underlying
+// database at the same time. Because keys may be added to the overlay while
+// iterating, the overlay keys are reloaded whenever the overlay changes and
+// keys of the underlying database are skipped if they are in the overlay.
+type snapshotIterator struct {
+	snapshot *Snapshot
+	database.Iterator
+
+	start, prefix string
+
+	key, value []byte
+	err        error
+
+	// Sorted keys of the overlay that haven't been iterated over yet
+	keys    []string
+	version uint64
+
+	// The next key/value pair of the underlying database
+	dbKey, dbValue         []byte
+	initialized, exhausted bool
+	// Set once Next has returned false because the iterator was exhausted
+	done bool
+}
+
+func (it *snapshotIterator) Next() bool {
+	s := it.snapshot
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	// Short-circuit and set an error if the snapshot has been released or the
+	// database has been closed.
+	if s.released || s.db.mem == nil {
+		it.key = nil
+		it.value = nil
+		it.err = database.ErrClosed
+		return false
+	}
+
+	if it.done {
+		return false
+	}
+	if !it.initialized {
+		it.advanceDB()
+		it.initialized = true
+	}
+	if it.version != s.version {
+		it.loadKeys()
+	}
+
+	for {
+		// Skip keys of the underlying database that are overridden by the
+		// overlay
+		for !it.exhausted {
+			if _, ok := s.overlay[string(it.dbKey)]; !ok {
+				break
+			}
+			it.advanceDB()
+		}
+
+		switch {
+		case it.exhausted && len(it.keys) == 0:
+			it.key = nil
+			it.value = nil
+			it.done = true
+			return false
+		case len(it.keys) == 0 || (!it.exhausted && string(it.dbKey) < it.keys[0]):
+			it.key = it.dbKey
+			it.value = it.dbValue
+			it.advanceDB()
+			return true
+		default:
+			nextKey := it.keys[0]
+			it.keys = it.keys[1:]
+
+			nextValue := s.overlay[nextKey]
+			if !nextValue.delete {
+				it.key = []byte(nextKey)
+				it.value = utils.CopyBytes(nextValue.value)
+				return true
+			}
+		}
+	}
+}
+
+// advanceDB moves the underlying iterator to its next key/value pair
+func (it *snapshotIterator) advanceDB() {
+	it.exhausted = !it.Iterator.Next()
+	if it.exhausted {
+		it.dbKey = nil
+		it.dbValue = nil
+		return
+	}
+	it.dbKey = utils.CopyBytes(it.Iterator.Key())
+	it.dbValue = utils.CopyBytes(it.Iterator.Value())
+}
+
+// loadKeys loads the sorted keys of the overlay that come after the last
+// returned key.
+//
+// Assumes [it.snapshot.db.lock] is held.
+func (it *snapshotIterator) loadKeys() {
+	s := it.snapshot
+	it.keys = it.keys[:0]
+	for key := range s.overlay {
+		if !strings.HasPrefix(key, it.prefix) || key < it.start {
+			continue
+		}
+		if it.key != nil && key <= string(it.key) {
+			continue
+		}
+		it.keys = append(it.keys, key)
+	}
+	sort.Strings(it.keys) // Keys need to be in sorted order
+	it.version = s.version
+}
+
+func (it *snapshotIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+func (it *snapshotIterator) Key() []byte { return it.key }
+
+func (it *snapshotIterator) Value() []byte { return it.value }
+
+func (it *snapshotIterator) Release() {
+	it.key = nil
+	it.value = nil
+	it.keys = nil
+	it.Iterator.Release()
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package versiondb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func iterateAll(t *testing.T, it database.Iterator) map[string]string {
+	defer it.Release()
+
+	pairs := make(map[string]string)
+	var lastKey string
+	for it.Next() {
+		key := string(it.Key())
+		assert.Greater(t, key, lastKey)
+		pairs[key] = string(it.Value())
+		lastKey = key
+	}
+	assert.NoError(t, it.Error())
+	return pairs
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := memdb.New()
+	assert.NoError(baseDB.Put([]byte("a"), []byte("a0")))
+	assert.NoError(baseDB.Put([]byte("b"), []byte("b0")))
+
+	db := New(baseDB)
+	assert.NoError(db.Put([]byte("c"), []byte("c0")))
+	assert.NoError(db.Delete([]byte("a")))
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(err)
+	defer snapshot.Release()
+
+	assert.NoError(db.Put([]byte("b"), []byte("b1")))
+	assert.NoError(db.Put([]byte("d"), []byte("d1")))
+	assert.NoError(db.Delete([]byte("c")))
+	assert.NoError(db.Commit())
+
+	// The database sees the new state
+	assert.Equal(
+		map[string]string{"b": "b1", "d": "d1"},
+		iterateAll(t, db.NewIterator()),
+	)
+
+	// The snapshot sees the state, including the uncommitted changes, at the
+	// time it was created
+	expected := map[string]string{"b": "b0", "c": "c0"}
+	assert.Equal(expected, iterateAll(t, snapshot.NewIterator()))
+
+	has, err := snapshot.Has([]byte("a"))
+	assert.NoError(err)
+	assert.False(has)
+
+	value, err := snapshot.Get([]byte("b"))
+	assert.NoError(err)
+	assert.Equal([]byte("b0"), value)
+
+	_, err = snapshot.Get([]byte("d"))
+	assert.Equal(database.ErrNotFound, err)
+
+	// Aborting the database doesn't affect the snapshot
+	assert.NoError(db.Put([]byte("e"), []byte("e2")))
+	db.Abort()
+	assert.Equal(expected, iterateAll(t, snapshot.NewIterator()))
+
+	assert.Equal(
+		map[string]string{"c": "c0"},
+		iterateAll(t, snapshot.NewIteratorWithStartAndPrefix([]byte("c"), []byte("c"))),
+	)
+}
+
+func TestSnapshotIteratorConcurrentCommit(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := memdb.New()
+	db := New(baseDB)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		assert.NoError(db.Put([]byte(key), []byte(key)))
+	}
+	assert.NoError(db.Commit())
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(err)
+	defer snapshot.Release()
+
+	it := snapshot.NewIterator()
+	defer it.Release()
+
+	assert.True(it.Next())
+	assert.Equal([]byte("a"), it.Key())
+
+	// Commit changes while the iterator is in use
+	assert.NoError(db.Delete([]byte("c")))
+	assert.NoError(db.Put([]byte("b"), []byte("new")))
+	assert.NoError(db.Put([]byte("bb"), []byte("new")))
+	assert.NoError(db.Commit())
+
+	var keys, values []string
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+		values = append(values, string(it.Value()))
+	}
+	assert.NoError(it.Error())
+	assert.Equal([]string{"b", "c", "d"}, keys)
+	assert.Equal([]string{"b", "c", "d"}, values)
+}
+
+func TestSnapshotCommitBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(err)
+	defer snapshot.Release()
+
+	assert.NoError(db.Put([]byte("a"), []byte("a")))
+	batch, err := db.CommitBatch()
+	assert.NoError(err)
+	assert.NoError(batch.Write())
+	db.Abort()
+
+	has, err := baseDB.Has([]byte("a"))
+	assert.NoError(err)
+	assert.True(has)
+
+	has, err = snapshot.Has([]byte("a"))
+	assert.NoError(err)
+	assert.False(has)
+}
+
+func TestSnapshotReleased(t *testing.T) {
+	assert := assert.New(t)
+
+	db := New(memdb.New())
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(err)
+
+	snapshot.Release()
+	assert.Empty(db.snapshots)
+
+	_, err = snapshot.Get([]byte("a"))
+	assert.Equal(database.ErrClosed, err)
+
+	it := snapshot.NewIterator()
+	assert.False(it.Next())
+	assert.Equal(database.ErrClosed, it.Error())
+	it.Release()
+
+	assert.NoError(db.Close())
+	_, err = db.NewSnapshot()
+	assert.Equal(database.ErrClosed, err)
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wal
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var _ Log = &log{}
+
+// Intent is an operation that was recorded before it was performed. [Data] is
+// opaque to the log and is interpreted by the subsystem that recorded it.
+type Intent struct {
+	ID   uint64
+	Data []byte
+}
+
+// Log is a write-ahead log of intents. It allows a subsystem that updates
+// multiple stores that can't be written atomically to record what it is about
+// to do, so that after a crash the operation can be replayed or rolled back.
+//
+// The expected usage is:
+//  1. Record the intent
+//  2. Write to each store
+//  3. Complete the intent
+//
+// On startup, any intent that is still pending describes an operation that may
+// have been partially applied.
+type Log interface {
+	// Record persists [data] as a pending intent and returns its ID. IDs are
+	// increasing in the order intents were recorded.
+	Record(data []byte) (uint64, error)
+
+	// Complete removes the pending intent [id]. Completing an intent that isn't
+	// pending is a no-op.
+	Complete(id uint64) error
+
+	// Pending returns all the intents that haven't been completed, in the
+	// order they were recorded.
+	Pending() ([]Intent, error)
+
+	// Recover calls [handler] with each pending intent, in the order they were
+	// recorded. Intents that are handled successfully are completed. If
+	// [handler] returns an error, recovery stops and the remaining intents are
+	// left pending.
+	Recover(handler func(Intent) error) error
+}
+
+type log struct {
+	lock sync.Mutex
+	// db stores intent ID -> intent data
+	db database.Database
+	// ID that will be assigned to the next recorded intent
+	nextID uint64
+}
+
+// New returns a log that stores its intents in [db]. [db] should not be used
+// for anything else.
+func New(db database.Database) (Log, error) {
+	l := &log{db: db}
+
+	// Resume numbering after the most recent pending intent so that IDs
+	// remain ordered across restarts.
+	pending, err := l.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if numPending := len(pending); numPending > 0 {
+		l.nextID = pending[numPending-1].ID + 1
+	}
+	return l, nil
+}
+
+func (l *log) Record(data []byte) (uint64, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	id := l.nextID
+	if err := l.db.Put(database.PackUInt64(id), data); err != nil {
+		return 0, fmt.Errorf("failed to record intent %d: %w", id, err)
+	}
+	l.nextID++
+	return id, nil
+}
+
+func (l *log) Complete(id uint64) error {
+	if err := l.db.Delete(database.PackUInt64(id)); err != nil {
+		return fmt.Errorf("failed to complete intent %d: %w", id, err)
+	}
+	return nil
+}
+
+func (l *log) Pending() ([]Intent, error) {
+	// Keys are big endian, so iterating in key order returns the intents in
+	// the order they were recorded.
+	it := l.db.NewIterator()
+	defer it.Release()
+
+	var intents []Intent
+	for it.Next() {
+		id, err := database.ParseUInt64(it.Key())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse intent ID: %w", err)
+		}
+		intents = append(intents, Intent{
+			ID:   id,
+			Data: utils.CopyBytes(it.Value()),
+		})
+	}
+	return intents, it.Error()
+}
+
+func (l *log) Recover(handler func(Intent) error) error {
+	pending, err := l.Pending()
+	if err != nil {
+		return err
+	}
+	for _, intent := range pending {
+		if err := handler(intent); err != nil {
+			return fmt.Errorf("failed to recover intent %d: %w", intent.ID, err)
+		}
+		if err := l.Complete(intent.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
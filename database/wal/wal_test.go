@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestLogRecordComplete(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	l, err := New(db)
+	assert.NoError(err)
+
+	id0, err := l.Record([]byte{0})
+	assert.NoError(err)
+	id1, err := l.Record([]byte{1})
+	assert.NoError(err)
+	assert.Less(id0, id1)
+
+	assert.NoError(l.Complete(id0))
+	// Completing an intent twice is a no-op
+	assert.NoError(l.Complete(id0))
+
+	pending, err := l.Pending()
+	assert.NoError(err)
+	assert.Equal([]Intent{{ID: id1, Data: []byte{1}}}, pending)
+
+	// IDs keep increasing after a restart
+	l, err = New(db)
+	assert.NoError(err)
+	id2, err := l.Record([]byte{2})
+	assert.NoError(err)
+	assert.Less(id1, id2)
+}
+
+func TestLogRecover(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	l, err := New(db)
+	assert.NoError(err)
+
+	for i := byte(0); i < 3; i++ {
+		_, err := l.Record([]byte{i})
+		assert.NoError(err)
+	}
+
+	// Simulate a crash by re-opening the log
+	l, err = New(db)
+	assert.NoError(err)
+
+	errFailed := errors.New("failed")
+	var handled []byte
+	err = l.Recover(func(intent Intent) error {
+		if intent.Data[0] == 1 {
+			return errFailed
+		}
+		handled = append(handled, intent.Data[0])
+		return nil
+	})
+	assert.ErrorIs(err, errFailed)
+	assert.Equal([]byte{0}, handled)
+
+	// The failed intent and the intents after it are still pending
+	pending, err := l.Pending()
+	assert.NoError(err)
+	assert.Len(pending, 2)
+
+	handled = nil
+	assert.NoError(l.Recover(func(intent Intent) error {
+		handled = append(handled, intent.Data[0])
+		return nil
+	}))
+	assert.Equal([]byte{1, 2}, handled)
+
+	pending, err = l.Pending()
+	assert.NoError(err)
+	assert.Empty(pending)
+}
@@ -0,0 +1,258 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package era
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Extension of the segment files
+const Extension = ".era"
+
+var (
+	errGap              = errors.New("segments aren't contiguous")
+	errEmptySegment     = errors.New("segment has no containers")
+	errUnexpectedChain  = errors.New("segment belongs to another chain")
+	errUnexpectedRange  = errors.New("segment doesn't hold the containers its file name says")
+	errIndexOutOfBounds = errors.New("index isn't in the archive")
+)
+
+type segmentFile struct {
+	path       string
+	firstIndex uint64
+	lastIndex  uint64
+}
+
+func segmentName(firstIndex, lastIndex uint64) string {
+	return fmt.Sprintf("%020d-%020d%s", firstIndex, lastIndex, Extension)
+}
+
+// Archive is a directory holding the segments of a chain. Each segment is
+// named after the range of containers it holds, so that the containers of the
+// archive are known without reading the segments. Segments are only ever
+// appended to the archive.
+//
+// Archive isn't thread-safe.
+type Archive struct {
+	dir     string
+	chainID ids.ID
+	// sorted by first index, without gaps between them
+	segments []segmentFile
+}
+
+// OpenArchive opens the archive of [chainID] in [dir], creating the directory
+// if it doesn't exist
+func OpenArchive(dir string, chainID ids.ID) (*Archive, error) {
+	if err := os.MkdirAll(dir, perms.ReadWriteExecute); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Archive{
+		dir:     dir,
+		chainID: chainID,
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, Extension) {
+			continue
+		}
+		s := segmentFile{path: filepath.Join(dir, name)}
+		if _, err := fmt.Sscanf(name, "%d-%d"+Extension, &s.firstIndex, &s.lastIndex); err != nil {
+			return nil, fmt.Errorf("couldn't parse segment name %q: %w", name, err)
+		}
+		if s.lastIndex < s.firstIndex {
+			return nil, fmt.Errorf("%w: %q", errUnexpectedRange, name)
+		}
+		a.segments = append(a.segments, s)
+	}
+	sort.Slice(a.segments, func(i, j int) bool {
+		return a.segments[i].firstIndex < a.segments[j].firstIndex
+	})
+	for i := 1; i < len(a.segments); i++ {
+		if a.segments[i].firstIndex != a.segments[i-1].lastIndex+1 {
+			return nil, fmt.Errorf("%w: %s follows %s", errGap, a.segments[i].path, a.segments[i-1].path)
+		}
+	}
+	return a, nil
+}
+
+// NumSegments returns the number of segments in the archive
+func (a *Archive) NumSegments() int {
+	return len(a.segments)
+}
+
+// FirstIndex returns the index of the first container of the archive
+func (a *Archive) FirstIndex() uint64 {
+	if len(a.segments) == 0 {
+		return 0
+	}
+	return a.segments[0].firstIndex
+}
+
+// NextIndex returns the index of the container that follows the last
+// container of the archive
+func (a *Archive) NextIndex() uint64 {
+	if len(a.segments) == 0 {
+		return 0
+	}
+	return a.segments[len(a.segments)-1].lastIndex + 1
+}
+
+// Append writes [containers] as a new segment that follows the last segment
+// of the archive. If the archive is empty, [firstIndex] is the index of
+// containers[0]. Otherwise, it must be NextIndex.
+func (a *Archive) Append(firstIndex uint64, containers []Container) error {
+	if len(containers) == 0 {
+		return errEmptySegment
+	}
+	if len(a.segments) != 0 && firstIndex != a.NextIndex() {
+		return fmt.Errorf("%w: expected index %d but got %d", errGap, a.NextIndex(), firstIndex)
+	}
+
+	segment := &Segment{
+		ChainID:    a.chainID,
+		FirstIndex: firstIndex,
+		Containers: containers,
+	}
+
+	// Write to a temporary file first, so that a partially written segment is
+	// never mistaken for a complete one
+	s := segmentFile{
+		path:       filepath.Join(a.dir, segmentName(segment.FirstIndex, segment.LastIndex())),
+		firstIndex: segment.FirstIndex,
+		lastIndex:  segment.LastIndex(),
+	}
+	tmpPath := s.path + ".tmp"
+	if err := writeSegment(tmpPath, segment); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+	a.segments = append(a.segments, s)
+	return nil
+}
+
+// writeSegment streams [segment] to the file at [path]
+func writeSegment(path string, segment *Segment) error {
+	f, err := perms.Create(path, perms.ReadWrite)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if _, err := segment.WriteTo(w); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadSegment returns the [i]th segment of the archive, after verifying it
+func (a *Archive) ReadSegment(i int) (*Segment, error) {
+	s := a.segments[i]
+	segmentBytes, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	segment, err := ParseSegment(segmentBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", s.path, err)
+	}
+	switch {
+	case segment.ChainID != a.chainID:
+		return nil, fmt.Errorf("%w: %s belongs to %s", errUnexpectedChain, s.path, segment.ChainID)
+	case len(segment.Containers) == 0 || segment.FirstIndex != s.firstIndex || segment.LastIndex() != s.lastIndex:
+		return nil, fmt.Errorf("%w: %s", errUnexpectedRange, s.path)
+	}
+	return segment, nil
+}
+
+// Get returns the container accepted at [index]. Only the container is read
+// from its segment, using the index of the segment, so the checksum of the
+// segment isn't verified.
+func (a *Archive) Get(index uint64) (Container, error) {
+	i := sort.Search(len(a.segments), func(i int) bool {
+		return a.segments[i].lastIndex >= index
+	})
+	if i == len(a.segments) || a.segments[i].firstIndex > index {
+		return Container{}, fmt.Errorf("%w: %d", errIndexOutOfBounds, index)
+	}
+	s := a.segments[i]
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return Container{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return Container{}, err
+	}
+
+	header := make([]byte, headerLen)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return Container{}, err
+	}
+	segment := &Segment{}
+	numContainers, err := parseHeader(&wrappers.Packer{Bytes: header}, segment)
+	if err != nil {
+		return Container{}, fmt.Errorf("couldn't parse %s: %w", s.path, err)
+	}
+	if segment.ChainID != a.chainID {
+		return Container{}, fmt.Errorf("%w: %s belongs to %s", errUnexpectedChain, s.path, segment.ChainID)
+	}
+	if segment.FirstIndex != s.firstIndex || numContainers != s.lastIndex-s.firstIndex+1 {
+		return Container{}, fmt.Errorf("%w: %s", errUnexpectedRange, s.path)
+	}
+	indexOffset, err := indexOffset(int(info.Size())-footerLen, numContainers)
+	if err != nil {
+		return Container{}, err
+	}
+
+	entry := make([]byte, wrappers.LongLen)
+	if _, err := f.ReadAt(entry, int64(indexOffset)+int64(index-s.firstIndex)*wrappers.LongLen); err != nil {
+		return Container{}, err
+	}
+	offset := indexEntry(entry, 0, 0)
+	if offset < headerLen || offset+recordHeaderLen > uint64(indexOffset) {
+		return Container{}, fmt.Errorf("%w: container %d at %d", errInvalidOffset, index, offset)
+	}
+
+	recordHeader := make([]byte, recordHeaderLen)
+	if _, err := f.ReadAt(recordHeader, int64(offset)); err != nil {
+		return Container{}, err
+	}
+	p := wrappers.Packer{Bytes: recordHeader}
+	container := Container{}
+	copy(container.ID[:], p.UnpackFixedBytes(len(container.ID)))
+	container.Timestamp = int64(p.UnpackLong())
+	size := uint64(p.UnpackInt())
+	if offset+recordHeaderLen+size > uint64(indexOffset) {
+		return Container{}, fmt.Errorf("%w: container %d at %d", errInvalidOffset, index, offset)
+	}
+
+	container.Bytes = make([]byte, size)
+	if _, err := f.ReadAt(container.Bytes, int64(offset+recordHeaderLen)); err != nil {
+		return Container{}, err
+	}
+	return container, nil
+}
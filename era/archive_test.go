@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package era
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func testContainers(n int) []Container {
+	containers := make([]Container, n)
+	for i := range containers {
+		containers[i] = Container{
+			ID:        ids.GenerateTestID(),
+			Timestamp: int64(i),
+			Bytes:     make([]byte, i),
+		}
+	}
+	return containers
+}
+
+func TestSegment(t *testing.T) {
+	assert := assert.New(t)
+
+	segment := &Segment{
+		ChainID:    ids.GenerateTestID(),
+		FirstIndex: 5,
+		Containers: testContainers(3),
+	}
+	segmentBytes, err := segment.Bytes()
+	assert.NoError(err)
+
+	parsed, err := ParseSegment(segmentBytes)
+	assert.NoError(err)
+	assert.Equal(segment, parsed)
+	assert.EqualValues(7, parsed.LastIndex())
+
+	// Streaming the segment writes the same encoding
+	buf := &bytes.Buffer{}
+	n, err := segment.WriteTo(buf)
+	assert.NoError(err)
+	assert.EqualValues(len(segmentBytes), n)
+	assert.Equal(segmentBytes, buf.Bytes())
+
+	// Any corruption is detected by the checksum
+	segmentBytes[headerLen] ^= 1
+	_, err = ParseSegment(segmentBytes)
+	assert.ErrorIs(err, errInvalidChecksum)
+
+	_, err = ParseSegment(segmentBytes[:headerLen])
+	assert.ErrorIs(err, errInvalidSegmentLength)
+}
+
+func TestArchive(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	chainID := ids.GenerateTestID()
+	archive, err := OpenArchive(dir, chainID)
+	assert.NoError(err)
+	assert.Zero(archive.NumSegments())
+	assert.Zero(archive.NextIndex())
+
+	containers := testContainers(5)
+	assert.NoError(archive.Append(0, containers[:3]))
+	assert.ErrorIs(archive.Append(2, containers[3:]), errGap)
+	assert.NoError(archive.Append(3, containers[3:]))
+	assert.ErrorIs(archive.Append(5, nil), errEmptySegment)
+
+	// The segments are found again once the archive is reopened
+	archive, err = OpenArchive(dir, chainID)
+	assert.NoError(err)
+	assert.Equal(2, archive.NumSegments())
+	assert.Zero(archive.FirstIndex())
+	assert.EqualValues(5, archive.NextIndex())
+
+	segment, err := archive.ReadSegment(1)
+	assert.NoError(err)
+	assert.EqualValues(3, segment.FirstIndex)
+	assert.Equal(containers[3:], segment.Containers)
+
+	for i, expected := range containers {
+		container, err := archive.Get(uint64(i))
+		assert.NoError(err)
+		assert.Equal(expected, container)
+	}
+	_, err = archive.Get(5)
+	assert.ErrorIs(err, errIndexOutOfBounds)
+
+	// The archive of another chain isn't read
+	otherArchive, err := OpenArchive(dir, ids.GenerateTestID())
+	assert.NoError(err)
+	_, err = otherArchive.ReadSegment(0)
+	assert.ErrorIs(err, errUnexpectedChain)
+	_, err = otherArchive.Get(0)
+	assert.ErrorIs(err, errUnexpectedChain)
+}
+
+func TestArchiveGap(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	chainID := ids.GenerateTestID()
+	archive, err := OpenArchive(dir, chainID)
+	assert.NoError(err)
+	assert.NoError(archive.Append(0, testContainers(2)))
+	assert.NoError(archive.Append(2, testContainers(2)))
+	assert.NoError(archive.Append(4, testContainers(2)))
+
+	assert.NoError(os.Remove(filepath.Join(dir, segmentName(2, 3))))
+	_, err = OpenArchive(dir, chainID)
+	assert.ErrorIs(err, errGap)
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package era
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var (
+	errUnexpectedID     = errors.New("block doesn't have the ID recorded in the segment")
+	errUnexpectedParent = errors.New("block doesn't extend the last accepted block")
+)
+
+// Import accepts the blocks of [archive] that follow the last accepted block
+// of [vm], in order, like bootstrapping would, and returns the number of
+// blocks accepted. Blocks the VM already accepted are skipped.
+//
+// Each block is verified by the VM before it's accepted, so an archive can't
+// make the chain accept an invalid block. Importing fails at the first block
+// that doesn't extend the last accepted block. The blocks accepted until then
+// stay accepted, and bootstrapping fetches the remaining blocks from the
+// network.
+//
+// Assumes [ctx.Lock] is held.
+func Import(ctx *snow.ConsensusContext, vm block.ChainVM, archive *Archive) (int, error) {
+	lastAcceptedID, err := vm.LastAccepted()
+	if err != nil {
+		return 0, err
+	}
+	lastAccepted, err := vm.GetBlock(lastAcceptedID)
+	if err != nil {
+		return 0, err
+	}
+	height := lastAccepted.Height()
+
+	numAccepted := 0
+	for i := 0; i < archive.NumSegments(); i++ {
+		segment, err := archive.ReadSegment(i)
+		if err != nil {
+			return numAccepted, err
+		}
+		for j, container := range segment.Containers {
+			blk, err := vm.ParseBlock(container.Bytes)
+			if err != nil {
+				return numAccepted, fmt.Errorf("couldn't parse block %d: %w", segment.FirstIndex+uint64(j), err)
+			}
+			blkID := blk.ID()
+			if blkID != container.ID {
+				return numAccepted, fmt.Errorf("%w: %s != %s", errUnexpectedID, blkID, container.ID)
+			}
+			if blk.Height() <= height {
+				continue
+			}
+			if parentID := blk.Parent(); parentID != lastAcceptedID {
+				return numAccepted, fmt.Errorf("%w: block %s has parent %s instead of %s", errUnexpectedParent, blkID, parentID, lastAcceptedID)
+			}
+
+			if err := blk.Verify(); err != nil {
+				return numAccepted, fmt.Errorf("block %s failed verification: %w", blkID, err)
+			}
+			// Note that the dispatchers must be notified before the block is
+			// accepted to honor EventDispatcher.Accept's invariant
+			if err := ctx.ConsensusDispatcher.Accept(ctx, blkID, container.Bytes); err != nil {
+				return numAccepted, err
+			}
+			if err := ctx.DecisionDispatcher.Accept(ctx, blkID, container.Bytes); err != nil {
+				return numAccepted, err
+			}
+			if err := blk.Accept(); err != nil {
+				return numAccepted, fmt.Errorf("couldn't accept block %s: %w", blkID, err)
+			}
+
+			numAccepted++
+			lastAcceptedID = blkID
+			height = blk.Height()
+		}
+		ctx.Log.Info("imported the blocks of segment %d of %d, up to height %d", i+1, archive.NumSegments(), height)
+	}
+	return numAccepted, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package era
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var errUnknownBlock = errors.New("unknown block")
+
+// testChain returns [n] blocks, each the child of the previous one. The first
+// block is accepted.
+func testChain(n int) []*snowman.TestBlock {
+	blks := make([]*snowman.TestBlock, n)
+	for i := range blks {
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			HeightV: uint64(i),
+			BytesV:  []byte{byte(i)},
+		}
+		if i > 0 {
+			blks[i].ParentV = blks[i-1].ID()
+		}
+	}
+	blks[0].StatusV = choices.Accepted
+	return blks
+}
+
+func testVM(t *testing.T, blks []*snowman.TestBlock) *block.TestVM {
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.LastAcceptedF = func() (ids.ID, error) {
+		lastAccepted := blks[0]
+		for _, blk := range blks {
+			if blk.Status() == choices.Accepted {
+				lastAccepted = blk
+			}
+		}
+		return lastAccepted.ID(), nil
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if bytes.Equal(blk.Bytes(), b) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	return vm
+}
+
+func toContainers(blks []*snowman.TestBlock) []Container {
+	containers := make([]Container, len(blks))
+	for i, blk := range blks {
+		containers[i] = Container{
+			ID:    blk.ID(),
+			Bytes: blk.Bytes(),
+		}
+	}
+	return containers
+}
+
+func TestImport(t *testing.T) {
+	assert := assert.New(t)
+
+	blks := testChain(6)
+	// [blks][1] was already accepted, so it's skipped
+	blks[1].StatusV = choices.Accepted
+	vm := testVM(t, blks)
+
+	archive, err := OpenArchive(t.TempDir(), ids.GenerateTestID())
+	assert.NoError(err)
+	containers := toContainers(blks[1:])
+	assert.NoError(archive.Append(0, containers[:2]))
+	assert.NoError(archive.Append(2, containers[2:]))
+
+	ctx := snow.DefaultConsensusContextTest()
+	dispatcher := snow.NewEventDispatcherTracker()
+	ctx.ConsensusDispatcher = dispatcher
+
+	numAccepted, err := Import(ctx, vm, archive)
+	assert.NoError(err)
+	assert.Equal(4, numAccepted)
+	for _, blk := range blks[2:] {
+		assert.Equal(choices.Accepted, blk.Status())
+		count, _ := dispatcher.IsAccepted(blk.ID())
+		assert.Equal(1, count)
+	}
+
+	// Importing again is a no-op
+	numAccepted, err = Import(ctx, vm, archive)
+	assert.NoError(err)
+	assert.Zero(numAccepted)
+}
+
+func TestImportStopsAtInvalidBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	blks := testChain(4)
+	blks[2].VerifyV = errors.New("invalid block")
+	vm := testVM(t, blks)
+
+	archive, err := OpenArchive(t.TempDir(), ids.GenerateTestID())
+	assert.NoError(err)
+	assert.NoError(archive.Append(0, toContainers(blks[1:])))
+
+	numAccepted, err := Import(snow.DefaultConsensusContextTest(), vm, archive)
+	assert.Error(err)
+	assert.Equal(1, numAccepted)
+	assert.Equal(choices.Accepted, blks[1].Status())
+	assert.Equal(choices.Processing, blks[2].Status())
+	assert.Equal(choices.Processing, blks[3].Status())
+}
+
+func TestImportUnexpectedParent(t *testing.T) {
+	assert := assert.New(t)
+
+	blks := testChain(3)
+	vm := testVM(t, blks)
+
+	// [blks][1] is missing from the archive
+	archive, err := OpenArchive(t.TempDir(), ids.GenerateTestID())
+	assert.NoError(err)
+	assert.NoError(archive.Append(1, toContainers(blks[2:])))
+
+	_, err = Import(snow.DefaultConsensusContextTest(), vm, archive)
+	assert.ErrorIs(err, errUnexpectedParent)
+	assert.Equal(choices.Processing, blks[2].Status())
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package era
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	codecVersion uint16 = 0
+
+	// magic (4 bytes) + codec version + chain ID + first index + number of
+	// containers
+	headerLen = 4 + wrappers.ShortLen + hashing.HashLen + 2*wrappers.LongLen
+	// ID + timestamp + length of the container bytes
+	recordHeaderLen = hashing.HashLen + wrappers.LongLen + wrappers.IntLen
+	// checksum of the rest of the segment
+	footerLen = hashing.HashLen
+)
+
+var (
+	magic = []byte("aera")
+
+	errInvalidMagic         = errors.New("not an era segment")
+	errUnknownCodecVersion  = errors.New("unknown codec version")
+	errInvalidChecksum      = errors.New("invalid segment checksum")
+	errInvalidSegmentLength = errors.New("invalid segment length")
+	errInvalidOffset        = errors.New("invalid container offset")
+)
+
+// Container is an accepted container, such as a block, in the order it was
+// accepted
+type Container struct {
+	ID ids.ID
+	// Timestamp is the unix time, in seconds, at which the container was
+	// accepted by the node that exported it
+	Timestamp int64
+	Bytes     []byte
+}
+
+// Segment is a range of the accepted containers of a chain. Segments are
+// written once and never modified.
+//
+// A segment is laid out as:
+//   - a header with the chain ID, the index of acceptance of the first
+//     container and the number of containers
+//   - the containers, each as its ID, timestamp, length and bytes
+//   - the index: the offset of each container in the segment
+//   - the checksum of everything before it
+type Segment struct {
+	ChainID ids.ID
+	// FirstIndex is the index of acceptance of Containers[0]
+	FirstIndex uint64
+	Containers []Container
+}
+
+// LastIndex returns the index of acceptance of the last container of the
+// segment. Assumes the segment isn't empty.
+func (s *Segment) LastIndex() uint64 {
+	return s.FirstIndex + uint64(len(s.Containers)) - 1
+}
+
+// Bytes returns the encoding of the segment
+func (s *Segment) Bytes() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, s.size()))
+	_, err := s.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// WriteTo writes the encoding of the segment to [w]. The containers are written
+// as they are, and the checksum is computed as the segment is written, so the
+// encoding of the segment is never held in memory.
+func (s *Segment) WriteTo(w io.Writer) (int64, error) {
+	hw := hashing.NewHash256Writer(w)
+
+	p := wrappers.Packer{Bytes: make([]byte, headerLen)}
+	p.PackFixedBytes(magic)
+	p.PackShort(codecVersion)
+	p.PackFixedBytes(s.ChainID[:])
+	p.PackLong(s.FirstIndex)
+	p.PackLong(uint64(len(s.Containers)))
+	if _, err := hw.Write(p.Bytes); err != nil {
+		return hw.Written(), err
+	}
+
+	offsets := wrappers.Packer{Bytes: make([]byte, len(s.Containers)*wrappers.LongLen)}
+	recordHeader := make([]byte, recordHeaderLen)
+	for _, container := range s.Containers {
+		offsets.PackLong(uint64(hw.Written()))
+
+		p := wrappers.Packer{Bytes: recordHeader}
+		p.PackFixedBytes(container.ID[:])
+		p.PackLong(uint64(container.Timestamp))
+		p.PackInt(uint32(len(container.Bytes)))
+		if _, err := hw.Write(p.Bytes); err != nil {
+			return hw.Written(), err
+		}
+		if _, err := hw.Write(container.Bytes); err != nil {
+			return hw.Written(), err
+		}
+	}
+	if _, err := hw.Write(offsets.Bytes); err != nil {
+		return hw.Written(), err
+	}
+
+	// The checksum isn't part of what it's the checksum of
+	checksum := hw.Sum256()
+	n, err := w.Write(checksum[:])
+	return hw.Written() + int64(n), err
+}
+
+// size returns the length of the encoding of the segment
+func (s *Segment) size() int {
+	size := headerLen + footerLen + len(s.Containers)*(recordHeaderLen+wrappers.LongLen)
+	for _, container := range s.Containers {
+		size += len(container.Bytes)
+	}
+	return size
+}
+
+// ParseSegment parses and verifies the segment encoded in [b]
+func ParseSegment(b []byte) (*Segment, error) {
+	if len(b) < headerLen+footerLen {
+		return nil, errInvalidSegmentLength
+	}
+	checksumOffset := len(b) - footerLen
+	if !bytes.Equal(hashing.ComputeHash256(b[:checksumOffset]), b[checksumOffset:]) {
+		return nil, errInvalidChecksum
+	}
+
+	s := &Segment{}
+	p := wrappers.Packer{Bytes: b[:checksumOffset]}
+	numContainers, err := parseHeader(&p, s)
+	if err != nil {
+		return nil, err
+	}
+	indexOffset, err := indexOffset(checksumOffset, numContainers)
+	if err != nil {
+		return nil, err
+	}
+	// Each container takes at least [recordHeaderLen] bytes
+	if numContainers > uint64(indexOffset-headerLen)/recordHeaderLen {
+		return nil, errInvalidSegmentLength
+	}
+
+	s.Containers = make([]Container, numContainers)
+	for i := range s.Containers {
+		if offset := uint64(p.Offset); offset != indexEntry(b, indexOffset, i) {
+			return nil, fmt.Errorf("%w: container %d at %d", errInvalidOffset, i, offset)
+		}
+		s.Containers[i] = unpackContainer(&p)
+	}
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	if p.Offset != indexOffset {
+		return nil, errInvalidSegmentLength
+	}
+	return s, nil
+}
+
+// parseHeader parses the header of [s] and returns its number of containers
+func parseHeader(p *wrappers.Packer, s *Segment) (uint64, error) {
+	if !bytes.Equal(p.UnpackFixedBytes(len(magic)), magic) {
+		return 0, errInvalidMagic
+	}
+	if version := p.UnpackShort(); version != codecVersion {
+		return 0, fmt.Errorf("%w: %d", errUnknownCodecVersion, version)
+	}
+	copy(s.ChainID[:], p.UnpackFixedBytes(hashing.HashLen))
+	s.FirstIndex = p.UnpackLong()
+	numContainers := p.UnpackLong()
+	return numContainers, p.Err
+}
+
+// indexOffset returns the offset of the index of a segment with
+// [numContainers] containers whose checksum is at [checksumOffset]
+func indexOffset(checksumOffset int, numContainers uint64) (int, error) {
+	if checksumOffset < headerLen || numContainers > uint64(checksumOffset-headerLen)/wrappers.LongLen {
+		return 0, errInvalidSegmentLength
+	}
+	return checksumOffset - int(numContainers)*wrappers.LongLen, nil
+}
+
+func indexEntry(b []byte, indexOffset int, i int) uint64 {
+	p := wrappers.Packer{
+		Bytes:  b,
+		Offset: indexOffset + i*wrappers.LongLen,
+	}
+	return p.UnpackLong()
+}
+
+func unpackContainer(p *wrappers.Packer) Container {
+	container := Container{}
+	copy(container.ID[:], p.UnpackFixedBytes(hashing.HashLen))
+	container.Timestamp = int64(p.UnpackLong())
+	container.Bytes = p.UnpackBytes()
+	return container
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package failover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+// Endpoint is the API endpoint, under /ext, that the primary requests leases
+// from
+const Endpoint = "failover"
+
+var (
+	errUnexpectedNodeID = errors.New("peer has a different node ID")
+	errLeaseRefused     = errors.New("peer refused to grant a lease")
+
+	_ Coordinator = &coordinator{}
+)
+
+// Config describes how a node coordinates with the other node of an
+// active/standby pair that share a staking key
+type Config struct {
+	// Enabled is true if this node is part of an active/standby pair
+	Enabled bool `json:"enabled"`
+
+	// Primary is true if this node is the primary of the pair. Exactly one
+	// node of the pair must be the primary.
+	Primary bool `json:"primary"`
+
+	// PeerURI is the URI of the other node's API, e.g. http://10.0.0.2:9650
+	PeerURI string `json:"peerURI"`
+
+	// HeartbeatFrequency is how often the primary renews its lease
+	HeartbeatFrequency time.Duration `json:"heartbeatFrequency"`
+
+	// LeaseDuration is how long a lease lasts for after it was requested
+	LeaseDuration time.Duration `json:"leaseDuration"`
+}
+
+// Lease is the response to a request for a lease
+type Lease struct {
+	NodeID string `json:"nodeID"`
+	// Granted is true if the responding node promised not to become active
+	// until [LeaseDuration] after it received the request
+	Granted bool `json:"granted"`
+}
+
+// Coordinator decides whether this node is the active node of its pair. Only
+// the active node may build blocks or vote, so that a staking key is never
+// used by both nodes at once.
+//
+// The primary is only active while it holds a lease granted by the standby.
+// It requests a lease every [HeartbeatFrequency], and each lease lasts for
+// [LeaseDuration] after the request was sent. The standby becomes active once
+// the last lease it granted expired, measured from when it received the
+// request, so the primary's lease always expires first. Once the standby is
+// active it refuses to grant leases, so the primary can't become active again
+// until the standby is restarted.
+//
+// If the nodes can't reach each other, the primary becomes inactive when its
+// lease expires. The standby only becomes active after that, so the nodes are
+// never active at the same time. A node that isn't active stays synced, but
+// doesn't affect consensus.
+type Coordinator interface {
+	http.Handler
+
+	// IsActive returns true if this node may currently affect consensus
+	IsActive() bool
+
+	// Dispatch renews or waits for leases until Stop is called
+	Dispatch()
+
+	// Stop stops renewing or waiting for leases. The primary becomes
+	// inactive when its lease expires.
+	Stop()
+}
+
+type coordinator struct {
+	log    logging.Logger
+	config Config
+	nodeID ids.ShortID
+	client *http.Client
+	clock  mockable.Clock
+
+	lock sync.RWMutex
+	// takenOver is true if this node is the standby and became active
+	takenOver bool
+	// leaseExpiry is when the lease held by the primary expires. On the
+	// primary, it's measured from when the lease was requested. On the
+	// standby, it's measured from when the lease was granted.
+	leaseExpiry time.Time
+
+	closer sync.Once
+	closed chan struct{}
+}
+
+// New returns a coordinator for the node with [nodeID]. The node starts
+// inactive. A standby assumes that the primary holds a lease when it starts,
+// so it won't become active until [LeaseDuration] after it was created.
+func New(log logging.Logger, config Config, nodeID ids.ShortID) Coordinator {
+	c := &coordinator{
+		log:    log,
+		config: config,
+		nodeID: nodeID,
+		client: &http.Client{Timeout: config.HeartbeatFrequency},
+		closed: make(chan struct{}),
+	}
+	if !config.Primary {
+		c.leaseExpiry = c.clock.Time().Add(config.LeaseDuration)
+	}
+	return c
+}
+
+func (c *coordinator) IsActive() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.config.Primary {
+		return c.clock.Time().Before(c.leaseExpiry)
+	}
+	return c.takenOver
+}
+
+// ServeHTTP grants a lease to the primary, unless this node is the primary or
+// already became active
+func (c *coordinator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	granted := c.grantLease()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(Lease{
+		NodeID:  c.nodeID.PrefixedString(constants.NodeIDPrefix),
+		Granted: granted,
+	})
+}
+
+func (c *coordinator) grantLease() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.config.Primary || c.takenOver {
+		return false
+	}
+	c.leaseExpiry = c.clock.Time().Add(c.config.LeaseDuration)
+	return true
+}
+
+func (c *coordinator) Dispatch() {
+	ticker := time.NewTicker(c.config.HeartbeatFrequency)
+	defer ticker.Stop()
+
+	for {
+		c.heartbeat()
+
+		select {
+		case <-ticker.C:
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+func (c *coordinator) Stop() {
+	c.closer.Do(func() {
+		close(c.closed)
+	})
+}
+
+// heartbeat renews the lease if this node is the primary. Otherwise, it
+// becomes active if the lease it granted expired.
+func (c *coordinator) heartbeat() {
+	if c.config.Primary {
+		c.renewLease()
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if now := c.clock.Time(); !c.takenOver && !now.Before(c.leaseExpiry) {
+		c.log.Warn("failover primary's lease expired at %s, becoming active", c.leaseExpiry)
+		c.takenOver = true
+	}
+}
+
+// renewLease requests a lease from the standby, which is held until
+// [LeaseDuration] after the request was sent
+func (c *coordinator) renewLease() {
+	requested := c.clock.Time()
+	err := c.requestLease()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err != nil {
+		c.log.Warn("couldn't renew lease from failover standby, which expires at %s: %s", c.leaseExpiry, err)
+		return
+	}
+	if !c.clock.Time().Before(c.leaseExpiry) {
+		c.log.Info("failover standby granted a lease, becoming active")
+	}
+	c.leaseExpiry = requested.Add(c.config.LeaseDuration)
+}
+
+// requestLease returns nil iff the peer granted a lease
+func (c *coordinator) requestLease() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.HeartbeatFrequency)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("%s/ext/%s", c.config.PeerURI, Endpoint),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+	lease := Lease{}
+	if err := json.Unmarshal(body, &lease); err != nil {
+		return err
+	}
+	if nodeID := c.nodeID.PrefixedString(constants.NodeIDPrefix); lease.NodeID != nodeID {
+		return fmt.Errorf("%w: %s", errUnexpectedNodeID, lease.NodeID)
+	}
+	if !lease.Granted {
+		return errLeaseRefused
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package failover
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTestCoordinator(primary bool, peerURI string, nodeID ids.ShortID, now time.Time) *coordinator {
+	c := New(logging.NoLog{}, Config{
+		Enabled:            true,
+		Primary:            primary,
+		PeerURI:            peerURI,
+		HeartbeatFrequency: time.Second,
+		LeaseDuration:      10 * time.Second,
+	}, nodeID).(*coordinator)
+	c.clock.Set(now)
+	if !primary {
+		c.leaseExpiry = now.Add(c.config.LeaseDuration)
+	}
+	return c
+}
+
+// newTestPair returns a primary and a standby that can reach each other,
+// along with the server of the standby
+func newTestPair(t *testing.T, now time.Time) (*coordinator, *coordinator, *httptest.Server) {
+	nodeID := ids.GenerateTestShortID()
+
+	standbyServer := httptest.NewServer(nil)
+	t.Cleanup(standbyServer.Close)
+
+	primary := newTestCoordinator(true, standbyServer.URL, nodeID, now)
+	standby := newTestCoordinator(false, "", nodeID, now)
+	standbyServer.Config.Handler = standby
+	return primary, standby, standbyServer
+}
+
+// setTime sets the clocks of [coordinators] to [now]
+func setTime(now time.Time, coordinators ...*coordinator) {
+	for _, c := range coordinators {
+		c.clock.Set(now)
+	}
+}
+
+func TestCoordinatorPrimaryHoldsLease(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	primary, standby, _ := newTestPair(t, now)
+	assert.False(primary.IsActive())
+	assert.False(standby.IsActive())
+
+	// The primary is active once the standby grants it a lease
+	primary.heartbeat()
+	assert.True(primary.IsActive())
+
+	// The standby doesn't take over while the primary renews its lease
+	for i := 0; i < 30; i++ {
+		now = now.Add(primary.config.HeartbeatFrequency)
+		setTime(now, primary, standby)
+		primary.heartbeat()
+		standby.heartbeat()
+		assert.True(primary.IsActive())
+		assert.False(standby.IsActive())
+	}
+}
+
+func TestCoordinatorPartition(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	primary, standby, standbyServer := newTestPair(t, now)
+	primary.heartbeat()
+	assert.True(primary.IsActive())
+
+	// The nodes can no longer reach each other
+	standbyServer.Close()
+	leaseExpiry := now.Add(primary.config.LeaseDuration)
+	for now.Before(leaseExpiry) {
+		setTime(now, primary, standby)
+		primary.heartbeat()
+		standby.heartbeat()
+		assert.True(primary.IsActive())
+		assert.False(standby.IsActive())
+		now = now.Add(primary.config.HeartbeatFrequency)
+	}
+
+	// The primary stops once its lease expires, which is no later than the
+	// standby takes over
+	setTime(leaseExpiry, primary, standby)
+	primary.heartbeat()
+	assert.False(primary.IsActive())
+	standby.heartbeat()
+	assert.True(standby.IsActive())
+}
+
+func TestCoordinatorStandbyTakesOverAfterLease(t *testing.T) {
+	assert := assert.New(t)
+
+	primaryServer := httptest.NewServer(nil)
+	primaryServer.Close()
+
+	// The standby assumes the primary holds a lease when it starts
+	now := time.Now()
+	standby := newTestCoordinator(false, primaryServer.URL, ids.GenerateTestShortID(), now)
+	standby.clock.Set(now.Add(standby.config.LeaseDuration - 1))
+	standby.heartbeat()
+	assert.False(standby.IsActive())
+
+	standby.clock.Set(now.Add(standby.config.LeaseDuration))
+	standby.heartbeat()
+	assert.True(standby.IsActive())
+}
+
+func TestCoordinatorActiveStandbyRefusesLeases(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	primary, standby, _ := newTestPair(t, now)
+
+	// A restarted primary doesn't take over from an active standby
+	setTime(now.Add(standby.config.LeaseDuration), primary, standby)
+	standby.heartbeat()
+	assert.True(standby.IsActive())
+	assert.ErrorIs(primary.requestLease(), errLeaseRefused)
+	primary.heartbeat()
+	assert.False(primary.IsActive())
+	assert.True(standby.IsActive())
+}
+
+func TestCoordinatorInvalidLease(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	nodeID := ids.GenerateTestShortID()
+
+	// A peer that doesn't grant a lease doesn't make the primary active
+	peerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer peerServer.Close()
+	primary := newTestCoordinator(true, peerServer.URL, nodeID, now)
+	primary.heartbeat()
+	assert.False(primary.IsActive())
+
+	// A peer with a different node ID isn't part of this pair
+	other := newTestCoordinator(false, "", ids.GenerateTestShortID(), now)
+	otherServer := httptest.NewServer(other)
+	defer otherServer.Close()
+	primary.config.PeerURI = otherServer.URL
+	assert.ErrorIs(primary.requestLease(), errUnexpectedNodeID)
+	assert.False(primary.IsActive())
+
+	// Two primaries never grant each other leases
+	otherPrimary := newTestCoordinator(true, "", nodeID, now)
+	otherPrimaryServer := httptest.NewServer(otherPrimary)
+	defer otherPrimaryServer.Close()
+	primary.config.PeerURI = otherPrimaryServer.URL
+	assert.ErrorIs(primary.requestLease(), errLeaseRefused)
+	assert.False(primary.IsActive())
+}
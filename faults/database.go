@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faults
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var (
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+)
+
+// Database fails the operations matched by the rules of its injector. The
+// operations that aren't matched are passed to the wrapped database.
+type Database struct {
+	database.Database
+	injector *Injector
+}
+
+// NewDatabase returns [db] with the database faults of [injector] injected
+func NewDatabase(db database.Database, injector *Injector) *Database {
+	return &Database{
+		Database: db,
+		injector: injector,
+	}
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	if err := db.injector.databaseError(Has, key); err != nil {
+		return false, err
+	}
+	return db.Database.Has(key)
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	if err := db.injector.databaseError(Get, key); err != nil {
+		return nil, err
+	}
+	return db.Database.Get(key)
+}
+
+func (db *Database) Put(key []byte, value []byte) error {
+	if err := db.injector.databaseError(Put, key); err != nil {
+		return err
+	}
+	return db.Database.Put(key, value)
+}
+
+func (db *Database) Delete(key []byte) error {
+	if err := db.injector.databaseError(Delete, key); err != nil {
+		return err
+	}
+	return db.Database.Delete(key)
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix returns an iterator that fails immediately if
+// an iterate rule matches [prefix]
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	if err := db.injector.databaseError(Iterate, prefix); err != nil {
+		return &nodb.Iterator{Err: err}
+	}
+	return db.Database.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// batch tracks the keys it writes so that batch write rules can match them
+type batch struct {
+	database.Batch
+	db *Database
+
+	keys [][]byte
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.keys = append(b.keys, utils.CopyBytes(key))
+	return b.Batch.Put(key, value)
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.keys = append(b.keys, utils.CopyBytes(key))
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	if err := b.db.injector.databaseError(BatchWrite, b.keys...); err != nil {
+		return err
+	}
+	return b.Batch.Write()
+}
+
+func (b *batch) Reset() {
+	b.keys = b.keys[:0]
+	b.Batch.Reset()
+}
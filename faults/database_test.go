@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		injector, err := NewInjector(Script{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		test(t, NewDatabase(memdb.New(), injector))
+	}
+}
+
+func TestDatabaseFailsMatchingOps(t *testing.T) {
+	assert := assert.New(t)
+
+	injector, err := NewInjector(Script{
+		Database: []DatabaseRule{
+			{
+				Op:        Put,
+				KeyPrefix: []byte("block"),
+				Error:     "disk full",
+			},
+			{
+				Op:    Get,
+				Limit: 1,
+			},
+		},
+	})
+	assert.NoError(err)
+	db := NewDatabase(memdb.New(), injector)
+
+	err = db.Put([]byte("block1"), []byte("value"))
+	assert.EqualError(err, "disk full")
+	assert.NoError(db.Put([]byte("other"), []byte("value")))
+
+	_, err = db.Get([]byte("other"))
+	assert.Error(err)
+	value, err := db.Get([]byte("other"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+}
+
+func TestDatabaseFailsBatchWrites(t *testing.T) {
+	assert := assert.New(t)
+
+	injector, err := NewInjector(Script{
+		Database: []DatabaseRule{{
+			Op:        BatchWrite,
+			KeyPrefix: []byte("block"),
+		}},
+	})
+	assert.NoError(err)
+	db := NewDatabase(memdb.New(), injector)
+
+	batch := db.NewBatch()
+	assert.NoError(batch.Put([]byte("other"), []byte("value")))
+	assert.NoError(batch.Put([]byte("block1"), []byte("value")))
+	assert.Error(batch.Write())
+
+	has, err := db.Has([]byte("other"))
+	assert.NoError(err)
+	assert.False(has)
+
+	batch.Reset()
+	assert.NoError(batch.Put([]byte("other"), []byte("value")))
+	assert.NoError(batch.Write())
+}
+
+func TestDatabaseFailsIterators(t *testing.T) {
+	assert := assert.New(t)
+
+	injector, err := NewInjector(Script{})
+	assert.NoError(err)
+	db := NewDatabase(memdb.New(), injector)
+	assert.NoError(db.Put([]byte("block1"), []byte("value")))
+
+	// Faults can be injected once the database is in use
+	assert.NoError(injector.SetScript(Script{
+		Database: []DatabaseRule{{
+			Op:        Iterate,
+			KeyPrefix: []byte("block"),
+		}},
+	}))
+
+	it := db.NewIteratorWithPrefix([]byte("block"))
+	assert.False(it.Next())
+	assert.Error(it.Error())
+	it.Release()
+
+	it = db.NewIterator()
+	assert.True(it.Next())
+	assert.NoError(it.Error())
+	it.Release()
+}
@@ -0,0 +1,11 @@
+//go:build !faultinjection
+// +build !faultinjection
+
+// ^ Only build this file if fault injection is not allowed
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faults
+
+// Enabled is true if this build allows faults to be injected into the node
+const Enabled = false
@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faults
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+)
+
+var (
+	_ router.Router          = &Router{}
+	_ message.InboundMessage = &sharedMessage{}
+)
+
+// Router drops, delays or duplicates the inbound messages matched by the rules
+// of its injector before they reach the wrapped router. Messages that aren't
+// matched are routed unchanged.
+type Router struct {
+	router.Router
+	injector *Injector
+}
+
+// NewRouter returns [r] with the message faults of [injector] injected
+func NewRouter(r router.Router, injector *Injector) *Router {
+	return &Router{
+		Router:   r,
+		injector: injector,
+	}
+}
+
+func (r *Router) HandleInbound(msg message.InboundMessage) {
+	var chainID ids.ID
+	if chainIDBytes, ok := msg.Get(message.ChainID).([]byte); ok {
+		chainID, _ = ids.ToID(chainIDBytes)
+	}
+
+	rule, ok := r.injector.messageRule(msg.Op(), chainID)
+	if !ok {
+		r.Router.HandleInbound(msg)
+		return
+	}
+
+	switch rule.Action {
+	case Drop:
+		// The message is released so that it doesn't count against the
+		// throttlers forever
+		msg.OnFinishedHandling()
+	case Delay:
+		time.AfterFunc(rule.Delay, func() {
+			r.Router.HandleInbound(msg)
+		})
+	case Duplicate:
+		remaining := int64(2)
+		r.Router.HandleInbound(&sharedMessage{
+			InboundMessage: msg,
+			remaining:      &remaining,
+		})
+		r.Router.HandleInbound(&sharedMessage{
+			InboundMessage: msg,
+			remaining:      &remaining,
+		})
+	}
+}
+
+// sharedMessage is a copy of a duplicated message. The message is only
+// released once every copy was handled.
+type sharedMessage struct {
+	message.InboundMessage
+	remaining *int64
+}
+
+func (m *sharedMessage) OnFinishedHandling() {
+	if atomic.AddInt64(m.remaining, -1) == 0 {
+		m.InboundMessage.OnFinishedHandling()
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faults
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+)
+
+// recordingRouter records the messages it's asked to handle
+type recordingRouter struct {
+	router.Router
+
+	lock    sync.Mutex
+	handled []message.InboundMessage
+}
+
+func (r *recordingRouter) HandleInbound(msg message.InboundMessage) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.handled = append(r.handled, msg)
+}
+
+func (r *recordingRouter) numHandled() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return len(r.handled)
+}
+
+// releaseCounter counts the times a message was released
+type releaseCounter struct {
+	message.InboundMessage
+	released int
+}
+
+func (m *releaseCounter) OnFinishedHandling() { m.released++ }
+
+func newTestMessages(t *testing.T) message.Creator {
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mc
+}
+
+func TestRouterPassesUnmatchedMessages(t *testing.T) {
+	assert := assert.New(t)
+	mc := newTestMessages(t)
+
+	injector, err := NewInjector(Script{
+		Messages: []MessageRule{{
+			Op:     message.Chits.String(),
+			Action: Drop,
+		}},
+	})
+	assert.NoError(err)
+	inner := &recordingRouter{}
+	r := NewRouter(inner, injector)
+
+	msg := &releaseCounter{InboundMessage: mc.InboundPut(ids.GenerateTestID(), 0, ids.GenerateTestID(), nil, ids.GenerateTestShortID())}
+	r.HandleInbound(msg)
+	assert.Equal(1, inner.numHandled())
+	assert.Zero(msg.released)
+}
+
+func TestRouterDrop(t *testing.T) {
+	assert := assert.New(t)
+	mc := newTestMessages(t)
+
+	chainID := ids.GenerateTestID()
+	injector, err := NewInjector(Script{
+		Messages: []MessageRule{{
+			Op:      message.Chits.String(),
+			ChainID: chainID,
+			Action:  Drop,
+			Limit:   1,
+		}},
+	})
+	assert.NoError(err)
+	inner := &recordingRouter{}
+	r := NewRouter(inner, injector)
+
+	// Only the messages of [chainID] are dropped
+	otherChainMsg := &releaseCounter{InboundMessage: mc.InboundChits(ids.GenerateTestID(), 0, nil, ids.GenerateTestShortID())}
+	r.HandleInbound(otherChainMsg)
+	assert.Equal(1, inner.numHandled())
+
+	dropped := &releaseCounter{InboundMessage: mc.InboundChits(chainID, 0, nil, ids.GenerateTestShortID())}
+	r.HandleInbound(dropped)
+	assert.Equal(1, inner.numHandled())
+	assert.Equal(1, dropped.released)
+
+	// The rule expired
+	passed := &releaseCounter{InboundMessage: mc.InboundChits(chainID, 1, nil, ids.GenerateTestShortID())}
+	r.HandleInbound(passed)
+	assert.Equal(2, inner.numHandled())
+	assert.Zero(passed.released)
+}
+
+func TestRouterDelay(t *testing.T) {
+	assert := assert.New(t)
+	mc := newTestMessages(t)
+
+	injector, err := NewInjector(Script{
+		Messages: []MessageRule{{
+			Op:     message.PushQuery.String(),
+			Action: Delay,
+			Delay:  10 * time.Millisecond,
+		}},
+	})
+	assert.NoError(err)
+	inner := &recordingRouter{}
+	r := NewRouter(inner, injector)
+
+	r.HandleInbound(mc.InboundPushQuery(ids.GenerateTestID(), 0, time.Second, ids.GenerateTestID(), nil, ids.GenerateTestShortID()))
+	assert.Zero(inner.numHandled())
+	assert.Eventually(func() bool { return inner.numHandled() == 1 }, time.Second, time.Millisecond)
+}
+
+func TestRouterDuplicate(t *testing.T) {
+	assert := assert.New(t)
+	mc := newTestMessages(t)
+
+	injector, err := NewInjector(Script{
+		Messages: []MessageRule{{
+			Op:     message.Put.String(),
+			Action: Duplicate,
+		}},
+	})
+	assert.NoError(err)
+	inner := &recordingRouter{}
+	r := NewRouter(inner, injector)
+
+	msg := &releaseCounter{InboundMessage: mc.InboundPut(ids.GenerateTestID(), 0, ids.GenerateTestID(), nil, ids.GenerateTestShortID())}
+	r.HandleInbound(msg)
+	assert.Equal(2, inner.numHandled())
+
+	// The message is released once both copies are handled
+	inner.handled[0].OnFinishedHandling()
+	assert.Zero(msg.released)
+	inner.handled[1].OnFinishedHandling()
+	assert.Equal(1, msg.released)
+}
+
+func TestRouterProbability(t *testing.T) {
+	assert := assert.New(t)
+	mc := newTestMessages(t)
+
+	script := Script{
+		Seed: 1,
+		Messages: []MessageRule{{
+			Op:          message.Put.String(),
+			Action:      Drop,
+			Probability: 0.5,
+		}},
+	}
+	run := func() int {
+		injector, err := NewInjector(script)
+		assert.NoError(err)
+		inner := &recordingRouter{}
+		r := NewRouter(inner, injector)
+		for i := 0; i < 100; i++ {
+			r.HandleInbound(mc.InboundPut(ids.GenerateTestID(), 0, ids.GenerateTestID(), nil, ids.GenerateTestShortID()))
+		}
+		return inner.numHandled()
+	}
+
+	handled := run()
+	assert.Greater(handled, 0)
+	assert.Less(handled, 100)
+	// The same seed drops the same messages
+	assert.Equal(handled, run())
+}
+
+func TestInvalidScript(t *testing.T) {
+	tests := map[string]Script{
+		"unknown op": {
+			Messages: []MessageRule{{Op: "unknown", Action: Drop}},
+		},
+		"unknown action": {
+			Messages: []MessageRule{{Op: message.Put.String(), Action: "unknown"}},
+		},
+		"missing delay": {
+			Messages: []MessageRule{{Op: message.Put.String(), Action: Delay}},
+		},
+		"invalid probability": {
+			Messages: []MessageRule{{Op: message.Put.String(), Action: Drop, Probability: 2}},
+		},
+		"unknown database op": {
+			Database: []DatabaseRule{{Op: "unknown"}},
+		},
+		"negative limit": {
+			Database: []DatabaseRule{{Op: Put, Limit: -1}},
+		},
+	}
+	for name, script := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewInjector(script)
+			assert.Error(t, err)
+		})
+	}
+}
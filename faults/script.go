@@ -0,0 +1,263 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faults
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+)
+
+// Action is what is done to an inbound message matched by a MessageRule
+type Action string
+
+const (
+	// Drop discards the message as if it was never received
+	Drop Action = "drop"
+	// Delay handles the message once [MessageRule.Delay] elapsed
+	Delay Action = "delay"
+	// Duplicate handles the message twice
+	Duplicate Action = "duplicate"
+)
+
+// DatabaseOp is a database operation that a DatabaseRule can fail
+type DatabaseOp string
+
+const (
+	Has        DatabaseOp = "has"
+	Get        DatabaseOp = "get"
+	Put        DatabaseOp = "put"
+	Delete     DatabaseOp = "delete"
+	BatchWrite DatabaseOp = "batchWrite"
+	Iterate    DatabaseOp = "iterate"
+)
+
+var (
+	errUnknownOp         = errors.New("unknown message op")
+	errUnknownAction     = errors.New("unknown action")
+	errUnknownDatabaseOp = errors.New("unknown database op")
+	errInvalidDelay      = errors.New("delay must be positive")
+	errInvalidProb       = errors.New("probability must be in [0, 1]")
+	errInvalidLimit      = errors.New("limit can't be negative")
+)
+
+// Script describes the faults injected into a node
+type Script struct {
+	// Seed of the randomness used to apply the rules with a probability, so
+	// that a run can be reproduced
+	Seed int64 `json:"seed"`
+
+	Messages []MessageRule  `json:"messages"`
+	Database []DatabaseRule `json:"database"`
+}
+
+// MessageRule applies [Action] to the inbound messages of type [Op]. If
+// several rules match a message, the first one applies.
+type MessageRule struct {
+	// Op is the name of the message type, such as "chits"
+	Op string `json:"op"`
+	// ChainID restricts the rule to the messages of a chain. If empty, the
+	// rule applies to every chain.
+	ChainID ids.ID `json:"chainID"`
+
+	Action Action        `json:"action"`
+	Delay  time.Duration `json:"delay"`
+
+	// Probability that a matching message is affected. If 0, every matching
+	// message is.
+	Probability float64 `json:"probability"`
+	// Limit is the number of messages affected before the rule expires. If
+	// 0, the rule never expires.
+	Limit int `json:"limit"`
+}
+
+// DatabaseRule fails the database operations [Op] on the keys starting with
+// [KeyPrefix] with the error [Error]. A batch write fails if it writes one of
+// these keys, and an iterator fails if the prefix it iterates over starts with
+// [KeyPrefix].
+type DatabaseRule struct {
+	Op        DatabaseOp `json:"op"`
+	KeyPrefix []byte     `json:"keyPrefix"`
+	Error     string     `json:"error"`
+
+	// Probability that a matching operation fails. If 0, every matching
+	// operation does.
+	Probability float64 `json:"probability"`
+	// Limit is the number of operations failed before the rule expires. If 0,
+	// the rule never expires.
+	Limit int `json:"limit"`
+}
+
+type messageRule struct {
+	MessageRule
+	op        message.Op
+	remaining int
+}
+
+type databaseRule struct {
+	DatabaseRule
+	err       error
+	remaining int
+}
+
+// Injector decides which messages and database operations are faulted. The
+// script can be replaced at any time, so that a test can inject faults at
+// specific points of its run.
+type Injector struct {
+	lock          sync.Mutex
+	rng           *rand.Rand
+	messageRules  []*messageRule
+	databaseRules []*databaseRule
+}
+
+// NewInjector returns an Injector that follows [script]
+func NewInjector(script Script) (*Injector, error) {
+	i := &Injector{}
+	return i, i.SetScript(script)
+}
+
+// SetScript replaces the rules of the injector with the rules of [script]
+func (i *Injector) SetScript(script Script) error {
+	messageRules := make([]*messageRule, len(script.Messages))
+	for j, rule := range script.Messages {
+		op, ok := parseOp(rule.Op)
+		if !ok {
+			return fmt.Errorf("message rule %d: %w %q", j, errUnknownOp, rule.Op)
+		}
+		switch rule.Action {
+		case Drop, Duplicate:
+		case Delay:
+			if rule.Delay <= 0 {
+				return fmt.Errorf("message rule %d: %w", j, errInvalidDelay)
+			}
+		default:
+			return fmt.Errorf("message rule %d: %w %q", j, errUnknownAction, rule.Action)
+		}
+		if err := verifyLimits(rule.Probability, rule.Limit); err != nil {
+			return fmt.Errorf("message rule %d: %w", j, err)
+		}
+		messageRules[j] = &messageRule{
+			MessageRule: rule,
+			op:          op,
+			remaining:   rule.Limit,
+		}
+	}
+
+	databaseRules := make([]*databaseRule, len(script.Database))
+	for j, rule := range script.Database {
+		switch rule.Op {
+		case Has, Get, Put, Delete, BatchWrite, Iterate:
+		default:
+			return fmt.Errorf("database rule %d: %w %q", j, errUnknownDatabaseOp, rule.Op)
+		}
+		if err := verifyLimits(rule.Probability, rule.Limit); err != nil {
+			return fmt.Errorf("database rule %d: %w", j, err)
+		}
+		msg := rule.Error
+		if msg == "" {
+			msg = fmt.Sprintf("injected %s failure", rule.Op)
+		}
+		databaseRules[j] = &databaseRule{
+			DatabaseRule: rule,
+			err:          errors.New(msg),
+			remaining:    rule.Limit,
+		}
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.rng = rand.New(rand.NewSource(script.Seed)) // #nosec G404
+	i.messageRules = messageRules
+	i.databaseRules = databaseRules
+	return nil
+}
+
+// messageRule returns the rule that applies to the message [op] of the chain
+// [chainID], if any
+func (i *Injector) messageRule(op message.Op, chainID ids.ID) (MessageRule, bool) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for _, rule := range i.messageRules {
+		if rule.op != op || (rule.ChainID != ids.Empty && rule.ChainID != chainID) {
+			continue
+		}
+		if i.trigger(rule.Probability, rule.Limit, &rule.remaining) {
+			return rule.MessageRule, true
+		}
+	}
+	return MessageRule{}, false
+}
+
+// databaseError returns the error that the operation [op] on [keys] fails
+// with, if any
+func (i *Injector) databaseError(op DatabaseOp, keys ...[]byte) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for _, rule := range i.databaseRules {
+		if rule.Op != op || !matchesAny(rule.KeyPrefix, keys) {
+			continue
+		}
+		if i.trigger(rule.Probability, rule.Limit, &rule.remaining) {
+			return rule.err
+		}
+	}
+	return nil
+}
+
+// trigger returns true if a matching rule applies, consuming one of its
+// [remaining] uses. Assumes [i.lock] is held.
+func (i *Injector) trigger(probability float64, limit int, remaining *int) bool {
+	if limit > 0 && *remaining == 0 {
+		return false
+	}
+	if probability > 0 && i.rng.Float64() >= probability {
+		return false
+	}
+	if limit > 0 {
+		*remaining--
+	}
+	return true
+}
+
+func matchesAny(prefix []byte, keys [][]byte) bool {
+	if len(prefix) == 0 {
+		return true
+	}
+	for _, key := range keys {
+		if bytes.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyLimits(probability float64, limit int) error {
+	switch {
+	case probability < 0 || probability > 1:
+		return errInvalidProb
+	case limit < 0:
+		return errInvalidLimit
+	default:
+		return nil
+	}
+}
+
+// parseOp returns the external message op named [name]
+func parseOp(name string) (message.Op, bool) {
+	for _, op := range message.ExternalOps {
+		if op.String() == name {
+			return op, true
+		}
+	}
+	return 0, false
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 
 	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
@@ -80,6 +81,10 @@ type Config struct {
 	CChainGenesis string `json:"cChainGenesis"`
 
 	Message string `json:"message"`
+
+	// RewardConfig defines the reward function of the network. If nil, the
+	// reward function is defined by the staking config of the node.
+	RewardConfig *reward.Config `json:"rewardConfig,omitempty"`
 }
 
 func (c Config) Unparse() (UnparsedConfig, error) {
@@ -93,6 +98,7 @@ func (c Config) Unparse() (UnparsedConfig, error) {
 		InitialStakers:             make([]UnparsedStaker, len(c.InitialStakers)),
 		CChainGenesis:              c.CChainGenesis,
 		Message:                    c.Message,
+		RewardConfig:               c.RewardConfig,
 	}
 	for i, a := range c.Allocations {
 		ua, err := a.Unparse(uc.NetworkID)
@@ -160,6 +160,19 @@ func validateConfig(networkID uint32, config *Config) error {
 		return errNoCChainGenesis
 	}
 
+	if config.RewardConfig != nil {
+		if err := config.RewardConfig.Verify(); err != nil {
+			return fmt.Errorf("invalid reward config: %w", err)
+		}
+		if config.RewardConfig.SupplyCap < initialSupply {
+			return fmt.Errorf(
+				"supply cap %d is below the initial supply %d",
+				config.RewardConfig.SupplyCap,
+				initialSupply,
+			)
+		}
+	}
+
 	return nil
 }
 
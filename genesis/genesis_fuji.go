@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm/churn"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 )
 
@@ -219,6 +220,10 @@ var (
 				MintingPeriod:      365 * 24 * time.Hour,
 				SupplyCap:          720 * units.MegaAvax,
 			},
+			ChurnConfig: churn.Config{
+				Period:  time.Hour,
+				MaxRate: .10 * reward.PercentDenominator, // 10%
+			},
 		},
 	}
 )
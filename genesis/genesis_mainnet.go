@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm/churn"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 )
 
@@ -175792,6 +175793,10 @@ var (
 				MintingPeriod:      365 * 24 * time.Hour,
 				SupplyCap:          720 * units.MegaAvax,
 			},
+			ChurnConfig: churn.Config{
+				Period:  24 * time.Hour,
+				MaxRate: .05 * reward.PercentDenominator, // 5%
+			},
 		},
 	}
 )
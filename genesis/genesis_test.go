@@ -131,6 +131,38 @@ func TestValidateConfig(t *testing.T) {
 				return &thisConfig
 			}(),
 		},
+		"custom reward config": {
+			networkID: 12345,
+			config: func() *Config {
+				thisConfig := LocalConfig
+				rewardConfig := LocalParams.RewardConfig
+				rewardConfig.MaxConsumptionRate = rewardConfig.MinConsumptionRate
+				thisConfig.RewardConfig = &rewardConfig
+				return &thisConfig
+			}(),
+		},
+		"invalid reward config": {
+			networkID: 12345,
+			config: func() *Config {
+				thisConfig := LocalConfig
+				rewardConfig := LocalParams.RewardConfig
+				rewardConfig.MintingPeriod = 0
+				thisConfig.RewardConfig = &rewardConfig
+				return &thisConfig
+			}(),
+			err: "invalid reward config",
+		},
+		"supply cap below initial supply": {
+			networkID: 12345,
+			config: func() *Config {
+				thisConfig := LocalConfig
+				rewardConfig := LocalParams.RewardConfig
+				rewardConfig.SupplyCap = 1
+				thisConfig.RewardConfig = &rewardConfig
+				return &thisConfig
+			}(),
+			err: "is below the initial supply",
+		},
 	}
 
 	for name, test := range tests {
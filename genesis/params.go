@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm/churn"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 )
 
@@ -31,6 +32,8 @@ type StakingConfig struct {
 	MaxStakeDuration time.Duration `json:"maxStakeDuration"`
 	// RewardConfig is the config for the reward function.
 	RewardConfig reward.Config `json:"rewardConfig"`
+	// ChurnConfig limits how quickly the stake of a subnet can change.
+	ChurnConfig churn.Config `json:"churnConfig"`
 }
 
 type TxFeeConfig struct {
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 )
 
 var errInvalidETHAddress = errors.New("invalid eth address")
@@ -98,6 +99,8 @@ type UnparsedConfig struct {
 	CChainGenesis string `json:"cChainGenesis"`
 
 	Message string `json:"message"`
+
+	RewardConfig *reward.Config `json:"rewardConfig,omitempty"`
 }
 
 func (uc UnparsedConfig) Parse() (Config, error) {
@@ -111,6 +114,7 @@ func (uc UnparsedConfig) Parse() (Config, error) {
 		InitialStakers:             make([]Staker, len(uc.InitialStakers)),
 		CChainGenesis:              uc.CChainGenesis,
 		Message:                    uc.Message,
+		RewardConfig:               uc.RewardConfig,
 	}
 	for i, ua := range uc.Allocations {
 		a, err := ua.Parse()
@@ -15,6 +15,7 @@ var AliasTests = []func(assert *assert.Assertions, r AliaserReader, w AliaserWri
 	AliaserPrimaryAliasTest,
 	AliaserAliasClashTest,
 	AliaserRemoveAliasTest,
+	AliaserRemoveSingleAliasTest,
 }
 
 func AliaserLookupErrorTest(assert *assert.Assertions, r AliaserReader, w AliaserWriter) {
@@ -106,3 +107,36 @@ func AliaserRemoveAliasTest(assert *assert.Assertions, r AliaserReader, w Aliase
 	err = w.Alias(id1, "Dark Night Rises")
 	assert.NoError(err)
 }
+
+func AliaserRemoveSingleAliasTest(assert *assert.Assertions, r AliaserReader, w AliaserWriter) {
+	id1 := ID{'B', 'r', 'u', 'c', 'e', ' ', 'W', 'a', 'y', 'n', 'e'}
+	id2 := ID{'J', 'a', 'm', 'e', 's', ' ', 'G', 'o', 'r', 'd', 'o', 'n'}
+	err := w.Alias(id1, "Batman")
+	assert.NoError(err)
+
+	err = w.Alias(id1, "Dark Knight")
+	assert.NoError(err)
+
+	err = w.RemoveAlias("Batman")
+	assert.NoError(err)
+
+	err = w.RemoveAlias("Batman")
+	assert.Error(err, "expected an error due to missing alias")
+
+	_, err = r.Lookup("Batman")
+	assert.Error(err)
+
+	res, err := r.PrimaryAlias(id1)
+	assert.NoError(err)
+	assert.Equal("Dark Knight", res)
+
+	// The removed alias can be given to another ID
+	err = w.Alias(id2, "Batman")
+	assert.NoError(err)
+
+	err = w.RemoveAlias("Dark Knight")
+	assert.NoError(err)
+
+	_, err = r.PrimaryAlias(id1)
+	assert.Error(err)
+}
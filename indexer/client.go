@@ -31,11 +31,18 @@ type Client interface {
 	IsAccepted(context.Context, *GetIndexArgs, ...rpc.Option) (bool, error)
 	// Get a container by its index
 	GetContainerByID(context.Context, *GetIndexArgs, ...rpc.Option) (Container, error)
+	// Returns how many containers were accepted, and how long ago, since the
+	// given container was accepted
+	GetFinality(context.Context, *GetIndexArgs, ...rpc.Option) (*GetFinalityResponse, error)
+	// Calls the provided function with each container from the provided index
+	// to the last accepted container, which are streamed in a single response
+	StreamContainers(ctx context.Context, startIndex uint64, f func(index uint64, container Container) error) error
 }
 
 // Client implementation for Avalanche Indexer API Endpoint
 type client struct {
 	requester rpc.EndpointRequester
+	streamURI string
 }
 
 // NewClient creates a client that can interact with an index via HTTP API calls.
@@ -44,6 +51,7 @@ type client struct {
 func NewClient(host, endpoint string) Client {
 	return &client{
 		requester: rpc.NewEndpointRequester(host, endpoint, "index"),
+		streamURI: host + endpoint + streamEndpoint,
 	}
 }
 
@@ -111,6 +119,12 @@ func (c *client) IsAccepted(ctx context.Context, args *GetIndexArgs, options ...
 	return isAccepted, err
 }
 
+func (c *client) GetFinality(ctx context.Context, args *GetIndexArgs, options ...rpc.Option) (*GetFinalityResponse, error) {
+	res := &GetFinalityResponse{}
+	err := c.requester.SendRequest(ctx, "getFinality", args, res, options...)
+	return res, err
+}
+
 func (c *client) GetContainerByID(ctx context.Context, args *GetIndexArgs, options ...rpc.Option) (Container, error) {
 	var fc FormattedContainer
 	if err := c.requester.SendRequest(ctx, "getContainerByID", args, &fc, options...); err != nil {
@@ -101,4 +101,19 @@ func TestIndexClient(t *testing.T) {
 		assert.NoError(err)
 		assert.EqualValues(id, container.ID)
 	}
+	{
+		// Test GetFinality
+		client.requester = &mockClient{
+			assert:         assert,
+			expectedMethod: "getFinality",
+			onSendRequestF: func(reply interface{}) error {
+				*(reply.(*GetFinalityResponse)) = GetFinalityResponse{Index: 1, Depth: 2}
+				return nil
+			},
+		}
+		finality, err := client.GetFinality(context.Background(), &GetIndexArgs{ContainerID: ids.Empty})
+		assert.NoError(err)
+		assert.EqualValues(1, finality.Index)
+		assert.EqualValues(2, finality.Depth)
+	}
 }
@@ -32,6 +32,7 @@ var (
 	nextAcceptedIndexKey   = []byte{0x00}
 	indexToContainerPrefix = []byte{0x01}
 	containerToIDPrefix    = []byte{0x02}
+	conflictsPrefix        = []byte{0x03}
 	errNoneAccepted        = errors.New("no containers have been accepted")
 	errNumToFetchZero      = fmt.Errorf("numToFetch must be in [1,%d]", MaxFetchedByRange)
 
@@ -44,11 +45,17 @@ var (
 // database of the VM that the container exists in.
 type Index interface {
 	snow.Acceptor
+	snow.Rejector
 	GetContainerByIndex(index uint64) (Container, error)
 	GetContainerRange(startIndex uint64, numToFetch uint64) ([]Container, error)
 	GetLastAccepted() (Container, error)
 	GetIndex(containerID ids.ID) (uint64, error)
 	GetContainerByID(containerID ids.ID) (Container, error)
+	// GetNumConflicts returns the number of containers that were rejected
+	// after [containerID] was accepted and before the next container was
+	// accepted. These are the containers that conflicted with [containerID],
+	// such as the blocks competing with it at the same height.
+	GetNumConflicts(containerID ids.ID) (uint64, error)
 	io.Closer
 }
 
@@ -67,7 +74,9 @@ type index struct {
 	indexToContainer database.Database
 	// Container ID --> Index
 	containerToIndex database.Database
-	log              logging.Logger
+	// Container ID --> Number of conflicting containers rejected
+	conflicts database.Database
+	log       logging.Logger
 }
 
 // Returns a new, thread-safe Index.
@@ -81,6 +90,7 @@ func newIndex(
 	vDB := versiondb.New(baseDB)
 	indexToContainer := prefixdb.New(indexToContainerPrefix, vDB)
 	containerToIndex := prefixdb.New(containerToIDPrefix, vDB)
+	conflicts := prefixdb.New(conflictsPrefix, vDB)
 
 	i := &index{
 		clock:            clock,
@@ -89,6 +99,7 @@ func newIndex(
 		vDB:              vDB,
 		indexToContainer: indexToContainer,
 		containerToIndex: containerToIndex,
+		conflicts:        conflicts,
 		log:              log,
 	}
 
@@ -113,6 +124,7 @@ func (i *index) Close() error {
 	errs.Add(
 		i.indexToContainer.Close(),
 		i.containerToIndex.Close(),
+		i.conflicts.Close(),
 		i.vDB.Close(),
 		i.baseDB.Close(),
 	)
@@ -169,6 +181,33 @@ func (i *index) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container
 	return i.vDB.Commit()
 }
 
+// Reject counts [containerID] as a conflict of the last accepted container.
+// Rejections are dispatched right after the container they conflict with is
+// accepted.
+func (i *index) Reject(ctx *snow.ConsensusContext, containerID ids.ID, _ []byte) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	lastAcceptedIndex, ok := i.lastAcceptedIndex()
+	if !ok {
+		return nil
+	}
+	lastAccepted, err := i.getContainerByIndex(lastAcceptedIndex)
+	if err != nil {
+		return err
+	}
+
+	numConflicts, err := database.GetUInt64(i.conflicts, lastAccepted.ID[:])
+	if err != nil && err != database.ErrNotFound {
+		return fmt.Errorf("couldn't get conflicts of %s: %w", lastAccepted.ID, err)
+	}
+	ctx.Log.Debug("indexing rejected container %s as a conflict of %s", containerID, lastAccepted.ID)
+	if err := database.PutUInt64(i.conflicts, lastAccepted.ID[:], numConflicts+1); err != nil {
+		return fmt.Errorf("couldn't put conflicts of %s: %w", lastAccepted.ID, err)
+	}
+	return i.vDB.Commit()
+}
+
 // Returns the ID of the [index]th accepted container and the container itself.
 // For example, if [index] == 0, returns the first accepted container.
 // If [index] == 1, returns the second accepted container, etc.
@@ -253,6 +292,21 @@ func (i *index) GetIndex(containerID ids.ID) (uint64, error) {
 	return database.GetUInt64(i.containerToIndex, containerID[:])
 }
 
+// Returns database.ErrNotFound if the container is not indexed as accepted
+func (i *index) GetNumConflicts(containerID ids.ID) (uint64, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if _, err := i.containerToIndex.Get(containerID[:]); err != nil {
+		return 0, err
+	}
+	numConflicts, err := database.GetUInt64(i.conflicts, containerID[:])
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return numConflicts, err
+}
+
 func (i *index) GetContainerByID(containerID ids.ID) (Container, error) {
 	i.lock.RLock()
 	defer i.lock.RUnlock()
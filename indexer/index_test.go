@@ -8,6 +8,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
@@ -173,3 +174,34 @@ func TestDontIndexSameContainerTwice(t *testing.T) {
 	assert.NoError(err)
 	assert.EqualValues(gotContainer.Bytes, []byte{1, 2, 3}, "should not have accepted same container twice")
 }
+
+func TestIndexConflicts(t *testing.T) {
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	assert.NoError(err)
+	db := memdb.New()
+	ctx := snow.DefaultConsensusContextTest()
+	idx, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{})
+	assert.NoError(err)
+
+	// Rejections before anything is accepted aren't attributed to a container
+	assert.NoError(idx.Reject(ctx, ids.GenerateTestID(), nil))
+
+	accepted0 := ids.GenerateTestID()
+	accepted1 := ids.GenerateTestID()
+	assert.NoError(idx.Accept(ctx, accepted0, []byte{0}))
+	assert.NoError(idx.Accept(ctx, accepted1, []byte{1}))
+	assert.NoError(idx.Reject(ctx, ids.GenerateTestID(), nil))
+	assert.NoError(idx.Reject(ctx, ids.GenerateTestID(), nil))
+
+	numConflicts, err := idx.GetNumConflicts(accepted0)
+	assert.NoError(err)
+	assert.EqualValues(0, numConflicts)
+	numConflicts, err = idx.GetNumConflicts(accepted1)
+	assert.NoError(err)
+	assert.EqualValues(2, numConflicts)
+
+	_, err = idx.GetNumConflicts(ids.GenerateTestID())
+	assert.ErrorIs(err, database.ErrNotFound)
+}
@@ -9,6 +9,7 @@ import (
 	"math"
 	"sync"
 
+	"github.com/ava-labs/avalanchego/api/doc"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -27,7 +28,6 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/snowman"
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/utils/logging"
-	"github.com/gorilla/rpc/v2"
 )
 
 const (
@@ -285,11 +285,11 @@ func (i *indexer) registerChainHelper(
 	}
 
 	// Create an API endpoint for this index
-	apiServer := rpc.NewServer()
+	apiServer := doc.NewServer()
 	codec := json.NewCodec()
 	apiServer.RegisterCodec(codec, "application/json")
 	apiServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := apiServer.RegisterService(&service{Index: index}, "index"); err != nil {
+	if err := apiServer.RegisterService(&service{Index: index, clock: i.clock}, "index"); err != nil {
 		_ = index.Close()
 		return nil, err
 	}
@@ -298,6 +298,16 @@ func (i *indexer) registerChainHelper(
 		_ = index.Close()
 		return nil, err
 	}
+
+	// Create an endpoint that streams the containers of this index
+	streamHandler := &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     &streamHandler{index: index, log: i.log},
+	}
+	if err := i.pathAdder.AddRoute(streamHandler, &sync.RWMutex{}, "index/"+name, "/"+endpoint+streamEndpoint); err != nil {
+		_ = index.Close()
+		return nil, err
+	}
 	return index, nil
 }
 
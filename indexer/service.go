@@ -12,10 +12,12 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 )
 
 type service struct {
 	Index
+	clock mockable.Clock
 }
 
 type FormattedContainer struct {
@@ -155,3 +157,56 @@ func (s *service) GetContainerByID(r *http.Request, args *GetIndexArgs, reply *F
 	*reply, err = newFormattedContainer(container, index, args.Encoding)
 	return err
 }
+
+type GetFinalityResponse struct {
+	// Index of the container
+	Index json.Uint64 `json:"index"`
+	// Number of containers accepted after the container
+	Depth json.Uint64 `json:"depth"`
+	// Time the container was accepted by this node
+	AcceptedAt time.Time `json:"acceptedAt"`
+	// Number of seconds since the container was accepted by this node
+	SecondsSinceAccepted json.Uint64 `json:"secondsSinceAccepted"`
+	// Number of conflicting containers that were rejected when the container
+	// was accepted. If 0, no competing containers were observed.
+	NumConflicts json.Uint64 `json:"numConflicts"`
+}
+
+// GetFinality returns how long ago, in containers and in time, the container
+// was accepted, so that clients can decide how many confirmations to wait for.
+// Returns an error if the container isn't accepted.
+func (s *service) GetFinality(r *http.Request, args *GetIndexArgs, reply *GetFinalityResponse) error {
+	container, err := s.Index.GetContainerByID(args.ContainerID)
+	if err != nil {
+		return err
+	}
+	index, err := s.Index.GetIndex(container.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get index: %w", err)
+	}
+	lastAccepted, err := s.Index.GetLastAccepted()
+	if err != nil {
+		return err
+	}
+	lastAcceptedIndex, err := s.Index.GetIndex(lastAccepted.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get index: %w", err)
+	}
+	numConflicts, err := s.Index.GetNumConflicts(container.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get conflicts: %w", err)
+	}
+
+	acceptedAt := time.Unix(0, container.Timestamp)
+	sinceAccepted := s.clock.Time().Sub(acceptedAt)
+	if sinceAccepted < 0 {
+		sinceAccepted = 0
+	}
+
+	reply.Index = json.Uint64(index)
+	reply.Depth = json.Uint64(lastAcceptedIndex - index)
+	reply.AcceptedAt = acceptedAt
+	reply.SecondsSinceAccepted = json.Uint64(sinceAccepted / time.Second)
+	reply.NumConflicts = json.Uint64(numConflicts)
+	return nil
+}
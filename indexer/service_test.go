@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+func TestGetFinality(t *testing.T) {
+	assert := assert.New(t)
+
+	codec := codec.NewDefaultManager()
+	assert.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+
+	clock := mockable.Clock{}
+	acceptedAt := time.Unix(1000, 0)
+	clock.Set(acceptedAt)
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, codec, clock)
+	assert.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	containerID := ids.GenerateTestID()
+	assert.NoError(idx.Accept(ctx, containerID, []byte{0}))
+	assert.NoError(idx.Reject(ctx, ids.GenerateTestID(), nil))
+	for i := byte(1); i <= 3; i++ {
+		assert.NoError(idx.Accept(ctx, ids.GenerateTestID(), []byte{i}))
+	}
+
+	s := &service{Index: idx}
+	s.clock.Set(acceptedAt.Add(90 * time.Second))
+
+	reply := GetFinalityResponse{}
+	assert.NoError(s.GetFinality(nil, &GetIndexArgs{ContainerID: containerID}, &reply))
+	assert.EqualValues(0, reply.Index)
+	assert.EqualValues(3, reply.Depth)
+	assert.Equal(acceptedAt, reply.AcceptedAt)
+	assert.EqualValues(90, reply.SecondsSinceAccepted)
+	assert.EqualValues(1, reply.NumConflicts)
+
+	err = s.GetFinality(nil, &GetIndexArgs{ContainerID: ids.GenerateTestID()}, &reply)
+	assert.ErrorIs(err, database.ErrNotFound)
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// streamEndpoint is appended to the endpoint of an index to get the
+	// endpoint that streams its containers
+	streamEndpoint = "/stream"
+
+	// startIndexParam is the query parameter of the first index to stream
+	startIndexParam = "startIndex"
+
+	// Each streamed container is prefixed by its index, ID, acceptance time
+	// and the length of its bytes
+	streamedContainerHeaderLen = wrappers.LongLen + hashing.HashLen + wrappers.LongLen + wrappers.IntLen
+)
+
+var errStreamedContainerTooLarge = errors.New("streamed container is too large")
+
+// streamHandler writes the containers of an index, in order of acceptance,
+// to the body of the response. The stream starts at the index given by the
+// [startIndexParam] query parameter and ends once the last accepted
+// container has been written.
+//
+// Containers are read in pages of [MaxFetchedByRange], so the index is only
+// locked while a page is read. Writes block while the client isn't reading, so
+// a slow client slows down the stream rather than growing buffers on the node.
+type streamHandler struct {
+	index Index
+	log   logging.Logger
+}
+
+func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var startIndex uint64
+	if startIndexStr := r.URL.Query().Get(startIndexParam); startIndexStr != "" {
+		var err error
+		startIndex, err = strconv.ParseUint(startIndexStr, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("couldn't parse %s: %s", startIndexParam, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	flusher, _ := w.(http.Flusher)
+	writer := bufio.NewWriter(w)
+	p := wrappers.Packer{Bytes: make([]byte, streamedContainerHeaderLen)}
+	for {
+		if err := r.Context().Err(); err != nil {
+			return
+		}
+
+		lastAccepted, err := h.index.GetLastAccepted()
+		if err == errNoneAccepted {
+			return
+		}
+		if err != nil {
+			h.log.Debug("couldn't stream containers: %s", err)
+			return
+		}
+		lastAcceptedIndex, err := h.index.GetIndex(lastAccepted.ID)
+		if err != nil {
+			h.log.Debug("couldn't stream containers: %s", err)
+			return
+		}
+		if startIndex > lastAcceptedIndex {
+			return
+		}
+
+		containers, err := h.index.GetContainerRange(startIndex, MaxFetchedByRange)
+		if err != nil {
+			h.log.Debug("couldn't stream containers from index %d: %s", startIndex, err)
+			return
+		}
+		for _, container := range containers {
+			p.Offset = 0
+			p.PackLong(startIndex)
+			p.PackFixedBytes(container.ID[:])
+			p.PackLong(uint64(container.Timestamp))
+			p.PackInt(uint32(len(container.Bytes)))
+			if _, err := writer.Write(p.Bytes); err != nil {
+				return
+			}
+			if _, err := writer.Write(container.Bytes); err != nil {
+				return
+			}
+			startIndex++
+		}
+		if err := writer.Flush(); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// StreamContainers calls [f] with each container of the index, in order of
+// acceptance, from [startIndex] to the last accepted container. Unlike
+// GetContainerRange, the containers are sent in a single response, so this is
+// suitable for fetching the entire index. The stream stops early if [f]
+// returns an error.
+func (c *client) StreamContainers(ctx context.Context, startIndex uint64, f func(index uint64, container Container) error) error {
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s?%s=%d", c.streamURI, startIndexParam, startIndex),
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("couldn't issue request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("received status code %d: %s", resp.StatusCode, body)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	header := make([]byte, streamedContainerHeaderLen)
+	for {
+		if _, err := io.ReadFull(reader, header); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("couldn't read container: %w", err)
+		}
+
+		p := wrappers.Packer{Bytes: header}
+		index := p.UnpackLong()
+		containerID, err := ids.ToID(p.UnpackFixedBytes(hashing.HashLen))
+		if err != nil {
+			return err
+		}
+		timestamp := p.UnpackLong()
+		numBytes := p.UnpackInt()
+		if numBytes > constants.DefaultMaxMessageSize {
+			return fmt.Errorf("%w: %d bytes", errStreamedContainerTooLarge, numBytes)
+		}
+
+		containerBytes := make([]byte, numBytes)
+		if _, err := io.ReadFull(reader, containerBytes); err != nil {
+			return fmt.Errorf("couldn't read container %s: %w", containerID, err)
+		}
+		if err := f(index, Container{
+			ID:        containerID,
+			Bytes:     containerBytes,
+			Timestamp: int64(timestamp),
+		}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+func TestStreamContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	codec := codec.NewDefaultManager()
+	assert.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, codec, mockable.Clock{})
+	assert.NoError(err)
+
+	server := httptest.NewServer(&streamHandler{index: idx, log: logging.NoLog{}})
+	defer server.Close()
+	c := &client{streamURI: server.URL}
+
+	collect := func(startIndex uint64) ([]uint64, []Container, error) {
+		var (
+			indices    []uint64
+			containers []Container
+		)
+		err := c.StreamContainers(context.Background(), startIndex, func(index uint64, container Container) error {
+			indices = append(indices, index)
+			containers = append(containers, container)
+			return nil
+		})
+		return indices, containers, err
+	}
+
+	// Nothing is streamed before any container is accepted
+	indices, _, err := collect(0)
+	assert.NoError(err)
+	assert.Empty(indices)
+
+	// Accept enough containers that they're streamed in multiple pages
+	ctx := snow.DefaultConsensusContextTest()
+	numContainers := MaxFetchedByRange + MaxFetchedByRange/2
+	accepted := make([]ids.ID, numContainers)
+	for i := range accepted {
+		accepted[i] = ids.GenerateTestID()
+		assert.NoError(idx.Accept(ctx, accepted[i], utils.RandomBytes(32)))
+	}
+
+	for _, startIndex := range []uint64{0, 1, MaxFetchedByRange, uint64(numContainers - 1)} {
+		indices, containers, err := collect(startIndex)
+		assert.NoError(err)
+		assert.Len(indices, numContainers-int(startIndex))
+		for i, index := range indices {
+			assert.Equal(startIndex+uint64(i), index)
+
+			expected, err := idx.GetContainerByIndex(index)
+			assert.NoError(err)
+			assert.Equal(expected, containers[i])
+		}
+	}
+
+	// Streaming past the last accepted container returns nothing
+	indices, _, err = collect(uint64(numContainers))
+	assert.NoError(err)
+	assert.Empty(indices)
+
+	// The stream stops if the callback fails
+	errTest := errors.New("non-nil error")
+	numCalls := 0
+	err = c.StreamContainers(context.Background(), 0, func(uint64, Container) error {
+		numCalls++
+		return errTest
+	})
+	assert.ErrorIs(err, errTest)
+	assert.Equal(1, numCalls)
+}
+
+func TestStreamContainersInvalidStartIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := &streamHandler{log: logging.NoLog{}}
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/stream?startIndex=-1", nil)
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(http.StatusBadRequest, recorder.Code)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ava-labs/avalanchego/app/cli"
 	"github.com/ava-labs/avalanchego/app/runner"
 	"github.com/ava-labs/avalanchego/config"
 	"github.com/ava-labs/avalanchego/version"
@@ -15,6 +16,17 @@ import (
 )
 
 func main() {
+	// Subcommands call the APIs of a running node instead of running one
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		if err := cli.Run(os.Args[1:], os.Stdout); err != nil {
+			if !errors.Is(err, pflag.ErrHelp) {
+				fmt.Printf("%s: %s\n", os.Args[1], err)
+				os.Exit(1)
+			}
+		}
+		os.Exit(0)
+	}
+
 	fs := config.BuildFlagSet()
 	v, err := config.BuildViper(fs, os.Args[1:])
 
@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// The smallest buffer held by a bufferPool is 2^minBufferSizeLog2 bytes.
+// Smaller messages share the buffers of the smallest size class.
+const minBufferSizeLog2 = 9
+
+// numLeakedBuffers is the number of buffers that were garbage collected
+// without being released. It's only tracked in debug builds.
+var numLeakedBuffers uint64
+
+// LeakedBuffers returns the number of pooled message buffers that were garbage
+// collected without being released. Leaks are only detected in builds with the
+// debug tag, so this is always 0 otherwise.
+func LeakedBuffers() uint64 { return atomic.LoadUint64(&numLeakedBuffers) }
+
+// Buffer holds the bytes of an inbound message. Buffers are taken from a pool,
+// so that reading a message doesn't allocate, and are returned to the pool once
+// the message parsed from them has been handled.
+type Buffer struct {
+	// Bytes that the message is read into
+	Bytes []byte
+
+	// Nil if the buffer is too large to be pooled
+	pool *sync.Pool
+	// 1 once the buffer was released
+	released uint32
+
+	// Where the buffer was taken from the pool. Only set in debug builds.
+	stack []byte
+}
+
+// Release returns the buffer to its pool. [b.Bytes] must no longer be used,
+// including any slices of it. Releasing a buffer more than once is a no-op,
+// but is reported in debug builds.
+func (b *Buffer) Release() {
+	if !atomic.CompareAndSwapUint32(&b.released, 0, 1) {
+		releasedTwice(b)
+		return
+	}
+	untrackBuffer(b)
+	if b.pool != nil {
+		b.pool.Put(b)
+	}
+}
+
+// detach releases the buffer without returning it to its pool, so that its
+// bytes may be used for as long as they're referenced.
+func (b *Buffer) detach() {
+	if !atomic.CompareAndSwapUint32(&b.released, 0, 1) {
+		releasedTwice(b)
+		return
+	}
+	untrackBuffer(b)
+}
+
+// bufferPool holds buffers in power of 2 size classes, from
+// 2^minBufferSizeLog2 bytes up to the maximum message size.
+type bufferPool struct {
+	pools []sync.Pool
+}
+
+func newBufferPool(maxSize int) *bufferPool {
+	numClasses := sizeClass(maxSize) + 1
+	p := &bufferPool{
+		pools: make([]sync.Pool, numClasses),
+	}
+	for i := range p.pools {
+		size := 1 << (i + minBufferSizeLog2)
+		pool := &p.pools[i]
+		pool.New = func() interface{} {
+			return &Buffer{
+				Bytes: make([]byte, size),
+				pool:  pool,
+			}
+		}
+	}
+	return p
+}
+
+// Get returns a buffer of [size] bytes. Buffers larger than the maximum size
+// aren't pooled.
+func (p *bufferPool) Get(size int) *Buffer {
+	class := sizeClass(size)
+	if class >= len(p.pools) {
+		return &Buffer{Bytes: make([]byte, size)}
+	}
+
+	b := p.pools[class].Get().(*Buffer)
+	b.Bytes = b.Bytes[:size]
+	atomic.StoreUint32(&b.released, 0)
+	trackBuffer(b)
+	return b
+}
+
+// sizeClass returns the index of the smallest size class that holds [size]
+// bytes
+func sizeClass(size int) int {
+	if size <= 1<<minBufferSizeLog2 {
+		return 0
+	}
+	return bits.Len(uint(size-1)) - minBufferSizeLog2
+}
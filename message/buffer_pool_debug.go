@@ -0,0 +1,36 @@
+//go:build debug
+// +build debug
+
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// trackBuffer records where [b] was taken from the pool, and reports [b] if
+// it's garbage collected before it's released
+func trackBuffer(b *Buffer) {
+	b.stack = debug.Stack()
+	runtime.SetFinalizer(b, reportLeak)
+}
+
+func untrackBuffer(b *Buffer) {
+	runtime.SetFinalizer(b, nil)
+	b.stack = nil
+}
+
+func reportLeak(b *Buffer) {
+	atomic.AddUint64(&numLeakedBuffers, 1)
+	fmt.Fprintf(os.Stderr, "message buffer of %d bytes was never released, it was taken from the pool at:\n%s\n", len(b.Bytes), b.stack)
+}
+
+func releasedTwice(*Buffer) {
+	panic(fmt.Sprintf("message buffer was released twice, the second time at:\n%s", debug.Stack()))
+}
@@ -0,0 +1,43 @@
+//go:build debug
+// +build debug
+
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestBufferLeakDetection(t *testing.T) {
+	assert := assert.New(t)
+
+	p := newBufferPool(units.KiB)
+	leaked := LeakedBuffers()
+
+	released := p.Get(10)
+	released.Release()
+	_ = p.Get(10) // Never released
+
+	// Finalizers are run after the garbage collection that found the buffer
+	// unreachable, so it may take a few collections to report the leak.
+	for i := 0; i < 100 && LeakedBuffers() == leaked; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(leaked+1, LeakedBuffers())
+}
+
+func TestBufferReleaseTwicePanics(t *testing.T) {
+	p := newBufferPool(units.KiB)
+	b := p.Get(10)
+	b.Release()
+	assert.Panics(t, b.Release)
+}
@@ -0,0 +1,13 @@
+//go:build !debug
+// +build !debug
+
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+func trackBuffer(*Buffer) {}
+
+func untrackBuffer(*Buffer) {}
+
+func releasedTwice(*Buffer) {}
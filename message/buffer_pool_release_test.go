@@ -0,0 +1,31 @@
+//go:build !debug
+// +build !debug
+
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestBufferReleaseTwice(t *testing.T) {
+	assert := assert.New(t)
+
+	p := newBufferPool(units.KiB)
+	b := p.Get(10)
+	b.Release()
+	assert.EqualValues(1, b.released)
+
+	// The second release mustn't return the buffer to the pool again
+	b.Release()
+	assert.EqualValues(1, b.released)
+
+	b = p.Get(10)
+	assert.EqualValues(0, b.released)
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestBufferPoolSizeClasses(t *testing.T) {
+	assert := assert.New(t)
+
+	p := newBufferPool(2 * units.MiB)
+	assert.Len(p.pools, 13)
+
+	tests := []struct {
+		size        int
+		expectedCap int
+	}{
+		{size: 0, expectedCap: 512},
+		{size: 1, expectedCap: 512},
+		{size: 512, expectedCap: 512},
+		{size: 513, expectedCap: 1024},
+		{size: 2 * units.MiB, expectedCap: 2 * units.MiB},
+	}
+	for _, test := range tests {
+		b := p.Get(test.size)
+		assert.Len(b.Bytes, test.size)
+		assert.Equal(test.expectedCap, cap(b.Bytes))
+		assert.NotNil(b.pool)
+		b.Release()
+	}
+}
+
+func TestBufferPoolOversized(t *testing.T) {
+	assert := assert.New(t)
+
+	p := newBufferPool(units.KiB)
+	b := p.Get(units.KiB + 1)
+	assert.Len(b.Bytes, units.KiB+1)
+	assert.Nil(b.pool)
+	b.Release()
+}
@@ -55,6 +55,7 @@ func TestBuildVersion(t *testing.T) {
 		myVersionTime,
 		sig,
 		[]ids.ID{subnetID},
+		nil,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, msg)
@@ -72,6 +73,25 @@ func TestBuildVersion(t *testing.T) {
 	assert.EqualValues(t, myVersionTime, parsedMsg.Get(VersionTime))
 	assert.EqualValues(t, sig, parsedMsg.Get(SigBytes))
 	assert.EqualValues(t, subnetIDs, parsedMsg.Get(TrackedSubnets))
+	assert.Nil(t, parsedMsg.Get(Features))
+
+	features := version.NewFeatureBits(version.CompressionFeature, version.StateSyncFeature)
+	msg, err = UncompressingBuilder.Version(
+		networkID,
+		myTime,
+		ip,
+		myVersion,
+		myVersionTime,
+		sig,
+		[]ids.ID{subnetID},
+		&features,
+	)
+	assert.NoError(t, err)
+
+	parsedMsg, err = TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.EqualValues(t, subnetIDs, parsedMsg.Get(TrackedSubnets))
+	assert.EqualValues(t, features, parsedMsg.Get(Features))
 }
 
 func TestBuildGetAcceptedFrontier(t *testing.T) {
@@ -267,6 +287,55 @@ func TestBuildChits(t *testing.T) {
 	assert.Equal(t, containerIDs, parsedMsg.Get(ContainerIDs))
 }
 
+func TestBuildPushQueryBatch(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestIDs := []uint32{5, 6}
+	deadline := uint64(15)
+	containerID := ids.Empty.Prefix(1)
+	containerID2 := ids.Empty.Prefix(2)
+	containerIDs := [][]byte{containerID[:], containerID2[:]}
+	containers := [][]byte{{3}, {4}}
+
+	for _, compress := range []bool{false, true} {
+		builder := NewOutboundBuilder(TestCodec, compress)
+		msg, err := builder.PushQueryBatch(chainID, requestIDs, time.Duration(deadline), []ids.ID{containerID, containerID2}, containers)
+		assert.NoError(t, err)
+		assert.NotNil(t, msg)
+		assert.Equal(t, PushQueryBatch, msg.Op())
+
+		parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+		assert.NoError(t, err)
+		assert.NotNil(t, parsedMsg)
+		assert.Equal(t, PushQueryBatch, parsedMsg.Op())
+		assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+		assert.Equal(t, requestIDs, parsedMsg.Get(RequestIDs))
+		assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+		assert.Equal(t, containerIDs, parsedMsg.Get(ContainerIDs))
+		assert.Equal(t, containers, parsedMsg.Get(MultiContainerBytes))
+	}
+}
+
+func TestBuildChitsBatch(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestIDs := []uint32{5, 6}
+	vote := ids.Empty.Prefix(1)
+	vote2 := ids.Empty.Prefix(2)
+	votes := [][]byte{vote[:], vote2[:]}
+
+	msg, err := UncompressingBuilder.ChitsBatch(chainID, requestIDs, []ids.ID{vote, vote2})
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, ChitsBatch, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, ChitsBatch, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestIDs, parsedMsg.Get(RequestIDs))
+	assert.Equal(t, votes, parsedMsg.Get(ContainerIDs))
+}
+
 func TestBuildAncestors(t *testing.T) {
 	chainID := ids.Empty.Prefix(0)
 	requestID := uint32(5)
@@ -356,3 +425,48 @@ func TestBuildAppGossipMsg(t *testing.T) {
 		assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
 	}
 }
+
+func TestBuildGetHeaders(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	startHeight := uint64(100)
+	numHeaders := uint32(10)
+
+	msg, err := UncompressingBuilder.GetHeaders(chainID, requestID, startHeight, numHeaders)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetHeaders, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetHeaders, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, startHeight, parsedMsg.Get(StartHeight))
+	assert.Equal(t, numHeaders, parsedMsg.Get(NumHeaders))
+}
+
+func TestBuildHeaders(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	header := ids.Empty.Prefix(1)
+	header2 := ids.Empty.Prefix(2)
+	headers := [][]byte{header[:], header2[:]}
+
+	for _, compress := range []bool{false, true} {
+		builder := NewOutboundBuilder(TestCodec, compress)
+		msg, err := builder.Headers(chainID, requestID, headers)
+		assert.NoError(t, err)
+		assert.NotNil(t, msg)
+		assert.Equal(t, Headers, msg.Op())
+
+		parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+		assert.NoError(t, err)
+		assert.NotNil(t, parsedMsg)
+		assert.Equal(t, Headers, parsedMsg.Op())
+		assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+		assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+		assert.Equal(t, headers, parsedMsg.Get(HeaderBytes))
+	}
+}
@@ -42,6 +42,16 @@ type Parser interface {
 	// Parse reads given bytes as InboundMessage
 	// Overrides client specified deadline in a message to maxDeadlineDuration
 	Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error)
+
+	// NewBuffer returns a pooled buffer of [size] bytes to read an inbound
+	// message into
+	NewBuffer(size int) *Buffer
+
+	// ParseBuffer is Parse for a message that was read into [buffer]. If the
+	// message is parsed, it takes ownership of [buffer], which is released
+	// once the message has been handled. Otherwise, the caller must release
+	// [buffer].
+	ParseBuffer(buffer *Buffer, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error)
 }
 
 type Codec interface {
@@ -56,12 +66,18 @@ type codec struct {
 	// Can be accessed by multiple goroutines concurrently.
 	byteSlicePool sync.Pool
 
+	// Holds the buffers inbound messages are read into
+	bufferPool *bufferPool
+
 	clock mockable.Clock
 
 	compressTimeMetrics   map[Op]metric.Averager
 	decompressTimeMetrics map[Op]metric.Averager
 	compressor            compression.Compressor
 	maxMessageTimeout     time.Duration
+
+	// De-duplicates the containers of parsed messages
+	containers *containerStore
 }
 
 func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, maxMessageSize int64, maxMessageTimeout time.Duration) (Codec, error) {
@@ -71,6 +87,7 @@ func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, max
 				return make([]byte, 0, constants.DefaultByteSliceCap)
 			},
 		},
+		bufferPool:            newBufferPool(int(maxMessageSize)),
 		compressTimeMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
 		decompressTimeMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
 		compressor:            compression.NewGzipCompressor(maxMessageSize),
@@ -98,6 +115,10 @@ func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, max
 			&errs,
 		)
 	}
+
+	containers, err := newContainerStore(namespace, metrics, defaultMaxUnreferencedContainerBytes)
+	errs.Add(err)
+	c.containers = containers
 	return c, errs.Err
 }
 
@@ -137,10 +158,16 @@ func (c *codec) Pack(
 	}
 
 	// Pack the uncompressed payload
-	for _, field := range msgFields {
+	numRequiredFields := len(msgFields) - optionalFields[op]
+	for i, field := range msgFields {
 		data, ok := fieldValues[field]
 		if !ok {
-			return nil, errMissingField
+			if i < numRequiredFields {
+				return nil, errMissingField
+			}
+			// Optional fields can only be omitted from the end of the
+			// message, so the following fields are omitted as well
+			break
 		}
 		field.Packer()(&p, data)
 	}
@@ -176,10 +203,23 @@ func (c *codec) Pack(
 	return msg, nil
 }
 
+func (c *codec) NewBuffer(size int) *Buffer { return c.bufferPool.Get(size) }
+
 // Parse attempts to convert bytes into a message.
 // The first byte of the message is the opcode of the message.
 // Overrides client specified deadline in a message to maxDeadlineDuration
 func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error) {
+	return c.parse(bytes, nil, nodeID, onFinishedHandling)
+}
+
+func (c *codec) ParseBuffer(buffer *Buffer, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error) {
+	return c.parse(buffer.Bytes, buffer, nodeID, onFinishedHandling)
+}
+
+// parse [bytes] into a message. The unpacked byte slices are views into
+// [bytes], rather than copies. If [buffer] isn't nil, it holds [bytes], and
+// it's released once no field of the message refers to it.
+func (c *codec) parse(bytes []byte, buffer *Buffer, nodeID ids.ShortID, onFinishedHandling func()) (InboundMessage, error) {
 	p := wrappers.Packer{Bytes: bytes}
 
 	// Unpack the op code (message type)
@@ -211,27 +251,79 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 			return nil, fmt.Errorf("couldn't decompress payload of %s message: %w", op, err)
 		}
 		c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
-		// Replace the compressed payload with the decompressed payload.
-		// Remove the compressed payload and isCompressed; keep just the message type
-		p.Bytes = p.Bytes[:wrappers.ByteLen]
-		// Rewind offset by 1 because we removed the bool flag
-		// since the data now is uncompressed
-		p.Offset -= wrappers.BoolLen
-		// Attach the decompressed payload.
-		p.Bytes = append(p.Bytes, payloadBytes...)
 		bytesSaved = len(payloadBytes) - len(compressedPayloadBytes)
+
+		// Unpack the fields from the decompressed payload, rather than copying
+		// it after the op code
+		p = wrappers.Packer{Bytes: payloadBytes}
 	}
 
 	// Parse each field of the payload
 	fieldValues := make(map[Field]interface{}, len(msgFields))
-	for _, field := range msgFields {
+	numRequiredFields := len(msgFields) - optionalFields[op]
+	for i, field := range msgFields {
+		if i >= numRequiredFields && p.Offset == len(p.Bytes) {
+			// The remaining optional fields were omitted
+			break
+		}
 		fieldValues[field] = field.Unpacker()(&p)
 	}
 
+	if p.Err != nil {
+		return nil, p.Err
+	}
 	if p.Offset != len(p.Bytes) {
 		return nil, fmt.Errorf("expected length %d but got %d", len(p.Bytes), p.Offset)
 	}
 
+	// Replace the containers with their de-duplicated copies, which are
+	// released once the message has been handled. VMs keep the bytes of the
+	// containers they parse, so they must never refer to [buffer], which is
+	// reused once the message has been handled.
+	var containerIDs []ids.ID
+	if container, ok := fieldValues[ContainerBytes].([]byte); ok {
+		var containerID ids.ID
+		fieldValues[ContainerBytes], containerID = c.containers.Add(container)
+		containerIDs = append(containerIDs, containerID)
+	}
+	if containers, ok := fieldValues[MultiContainerBytes].([][]byte); ok {
+		for i, container := range containers {
+			var containerID ids.ID
+			containers[i], containerID = c.containers.Add(container)
+			containerIDs = append(containerIDs, containerID)
+		}
+	}
+	if len(containerIDs) > 0 {
+		finished := onFinishedHandling
+		onFinishedHandling = func() {
+			c.containers.Release(containerIDs...)
+			if finished != nil {
+				finished()
+			}
+		}
+	}
+
+	// If the fields were unpacked from a decompressed payload, the buffer is
+	// no longer needed. If the receiver may keep fields that refer to the
+	// buffer, the buffer is left to the garbage collector. Otherwise, it's
+	// returned to the pool once the message has been handled.
+	if buffer != nil {
+		switch {
+		case compressed:
+			buffer.Release()
+		case retainable(msgFields):
+			buffer.detach()
+		default:
+			finished := onFinishedHandling
+			onFinishedHandling = func() {
+				buffer.Release()
+				if finished != nil {
+					finished()
+				}
+			}
+		}
+	}
+
 	var expirationTime time.Time
 	if deadline, hasDeadline := fieldValues[Deadline]; hasDeadline {
 		deadlineDuration := time.Duration(deadline.(uint64))
@@ -248,5 +340,16 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 		nodeID:                nodeID,
 		expirationTime:        expirationTime,
 		onFinishedHandling:    onFinishedHandling,
-	}, p.Err
+	}, nil
+}
+
+// retainable returns true if any of [fields] may be kept after the message
+// they're parsed from has been handled
+func retainable(fields []Field) bool {
+	for _, field := range fields {
+		if field.Retainable() {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,133 @@
+//go:build go1.18
+// +build go1.18
+
+// ^ Only build this file if native fuzzing is supported
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+// FuzzParseMessage checks that the codec rejects, rather than panics on, any
+// bytes received from a peer
+func FuzzParseMessage(f *testing.F) {
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	if err != nil {
+		f.Fatal(err)
+	}
+	tlsCert, err := staking.NewTLSCert()
+	if err != nil {
+		f.Fatal(err)
+	}
+	id := ids.GenerateTestID()
+	now := uint64(time.Now().Unix())
+
+	seeds := []inboundMessage{
+		{
+			op: Version,
+			fields: map[Field]interface{}{
+				NetworkID:      uint32(1),
+				NodeID:         uint32(1337),
+				MyTime:         now,
+				IP:             utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651},
+				VersionStr:     "avalanche/1.7.11",
+				VersionTime:    now,
+				SigBytes:       make([]byte, 256),
+				TrackedSubnets: [][]byte{id[:]},
+			},
+		},
+		{
+			op: PeerList,
+			fields: map[Field]interface{}{
+				Peers: []utils.IPCertDesc{{
+					Cert:      tlsCert.Leaf,
+					IPDesc:    utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651},
+					Time:      now,
+					Signature: make([]byte, 256),
+				}},
+			},
+		},
+		{
+			op:     Ping,
+			fields: map[Field]interface{}{},
+		},
+		{
+			op: Pong,
+			fields: map[Field]interface{}{
+				Uptime: uint8(80),
+			},
+		},
+		{
+			op: GetAccepted,
+			fields: map[Field]interface{}{
+				ChainID:      id[:],
+				RequestID:    uint32(1337),
+				Deadline:     uint64(time.Second),
+				ContainerIDs: [][]byte{id[:]},
+			},
+		},
+		{
+			op: Ancestors,
+			fields: map[Field]interface{}{
+				ChainID:             id[:],
+				RequestID:           uint32(1337),
+				MultiContainerBytes: [][]byte{make([]byte, 512), make([]byte, 512)},
+			},
+		},
+		{
+			op: PushQuery,
+			fields: map[Field]interface{}{
+				ChainID:        id[:],
+				RequestID:      uint32(1337),
+				Deadline:       uint64(time.Second),
+				ContainerID:    id[:],
+				ContainerBytes: make([]byte, 1024),
+			},
+		},
+		{
+			op: Chits,
+			fields: map[Field]interface{}{
+				ChainID:      id[:],
+				RequestID:    uint32(1337),
+				ContainerIDs: [][]byte{id[:]},
+			},
+		},
+		{
+			op: AppGossip,
+			fields: map[Field]interface{}{
+				ChainID:  id[:],
+				AppBytes: make([]byte, 64),
+			},
+		},
+	}
+	for _, m := range seeds {
+		for _, compress := range []bool{false, m.op.Compressible()} {
+			msg, err := c.Pack(m.op, m.fields, compress, false)
+			if err != nil {
+				f.Fatalf("failed to pack %s: %s", m.op, err)
+			}
+			f.Add(msg.Bytes())
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, msgBytes []byte) {
+		msg, err := c.Parse(msgBytes, dummyNodeID, dummyOnFinishedHandling)
+		if err != nil {
+			return
+		}
+		_ = msg.String()
+		msg.OnFinishedHandling()
+	})
+}
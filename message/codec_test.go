@@ -234,3 +234,150 @@ func TestCodecPackParseGzip(t *testing.T) {
 		assert.EqualValues(t, len(m.fields), len(unpacked.fields))
 	}
 }
+
+func TestCodecParseBuffer(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	assert.NoError(err)
+	id := ids.GenerateTestID()
+
+	tests := []struct {
+		name             string
+		op               Op
+		fields           map[Field]interface{}
+		compress         bool
+		releasedOnParse  bool
+		releasedOnHandle bool
+	}{
+		{
+			name: "released once handled",
+			op:   Get,
+			fields: map[Field]interface{}{
+				ChainID:     id[:],
+				RequestID:   uint32(1337),
+				Deadline:    uint64(time.Second),
+				ContainerID: id[:],
+			},
+			releasedOnParse:  false,
+			releasedOnHandle: true,
+		},
+		{
+			name: "released once decompressed",
+			op:   Put,
+			fields: map[Field]interface{}{
+				ChainID:        id[:],
+				RequestID:      uint32(1337),
+				ContainerID:    id[:],
+				ContainerBytes: make([]byte, 1024),
+			},
+			compress:         true,
+			releasedOnParse:  true,
+			releasedOnHandle: true,
+		},
+		{
+			name: "left to the garbage collector if retainable",
+			op:   AppGossip,
+			fields: map[Field]interface{}{
+				ChainID:  id[:],
+				AppBytes: []byte{1, 2, 3},
+			},
+			releasedOnParse:  true,
+			releasedOnHandle: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outMsg, err := c.Pack(test.op, test.fields, test.compress, false)
+			assert.NoError(err)
+
+			buffer := c.NewBuffer(len(outMsg.Bytes()))
+			copy(buffer.Bytes, outMsg.Bytes())
+
+			finished := false
+			inMsg, err := c.ParseBuffer(buffer, dummyNodeID, func() { finished = true })
+			assert.NoError(err)
+			for field, value := range test.fields {
+				assert.Equal(value, inMsg.Get(field))
+			}
+			assert.Equal(test.releasedOnParse, buffer.released == 1)
+
+			inMsg.OnFinishedHandling()
+			assert.True(finished)
+			assert.Equal(test.releasedOnHandle, buffer.released == 1)
+		})
+	}
+}
+
+func TestCodecParseBufferInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	assert.NoError(err)
+
+	buffer := c.NewBuffer(3)
+	copy(buffer.Bytes, []byte{byte(Ping), 0x00, 0x00})
+
+	// The caller keeps ownership of the buffer if it can't be parsed
+	_, err = c.ParseBuffer(buffer, dummyNodeID, dummyOnFinishedHandling)
+	assert.Error(err)
+	assert.EqualValues(0, buffer.released)
+	buffer.Release()
+}
+
+// Containers are kept by VMs after the messages they're parsed from have been
+// handled, so they must not be overwritten when the buffers of the messages
+// are reused.
+func TestCodecParseBufferRetainsContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	assert.NoError(err)
+	chainID := ids.GenerateTestID()
+
+	parse := func(op Op, fields map[Field]interface{}) InboundMessage {
+		outMsg, err := c.Pack(op, fields, false, false)
+		assert.NoError(err)
+
+		buffer := c.NewBuffer(len(outMsg.Bytes()))
+		copy(buffer.Bytes, outMsg.Bytes())
+		inMsg, err := c.ParseBuffer(buffer, dummyNodeID, nil)
+		assert.NoError(err)
+		return inMsg
+	}
+	put := func(container []byte) InboundMessage {
+		return parse(Put, map[Field]interface{}{
+			ChainID:        chainID[:],
+			RequestID:      uint32(1),
+			ContainerID:    ids.Empty[:],
+			ContainerBytes: container,
+		})
+	}
+	ancestors := func(containers [][]byte) InboundMessage {
+		return parse(Ancestors, map[Field]interface{}{
+			ChainID:             chainID[:],
+			RequestID:           uint32(1),
+			MultiContainerBytes: containers,
+		})
+	}
+
+	firstBlk := []byte{1, 2, 3, 4}
+	firstMsg := put(utils.CopyBytes(firstBlk))
+	retained := firstMsg.Get(ContainerBytes).([]byte)
+	firstMsg.OnFinishedHandling()
+
+	// The second message is read into the buffer that the first one released
+	secondMsg := put([]byte{5, 6, 7, 8})
+	assert.Equal(firstBlk, retained)
+	assert.Equal([]byte{5, 6, 7, 8}, secondMsg.Get(ContainerBytes))
+	secondMsg.OnFinishedHandling()
+
+	firstBlks := [][]byte{{1, 2}, {3, 4}}
+	firstMsg = ancestors([][]byte{{1, 2}, {3, 4}})
+	retainedBlks := firstMsg.Get(MultiContainerBytes).([][]byte)
+	firstMsg.OnFinishedHandling()
+
+	secondMsg = ancestors([][]byte{{5, 6}, {7, 8}})
+	assert.Equal(firstBlks, retainedBlks)
+	secondMsg.OnFinishedHandling()
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// The number of bytes of containers that are no longer referenced by any
+// message that are kept, so that containers received again shortly after can
+// still be de-duplicated.
+const defaultMaxUnreferencedContainerBytes = 16 * units.MiB
+
+type containerEntry struct {
+	bytes []byte
+	refs  int
+}
+
+// containerStore de-duplicates the container bytes of parsed messages. When the
+// same container is received from many peers, it is only held in memory once
+// and all messages reference the same copy.
+type containerStore struct {
+	lock sync.Mutex
+
+	// Container ID --> Container
+	containers map[ids.ID]*containerEntry
+
+	// IDs of the containers that aren't referenced by any message, from least
+	// to most recently released
+	unreferenced         linkedhashmap.LinkedHashmap
+	unreferencedBytes    int
+	maxUnreferencedBytes int
+
+	numBytes         prometheus.Gauge
+	numHits, numMiss prometheus.Counter
+}
+
+func newContainerStore(namespace string, registerer prometheus.Registerer, maxUnreferencedBytes int) (*containerStore, error) {
+	s := &containerStore{
+		containers:           make(map[ids.ID]*containerEntry),
+		unreferenced:         linkedhashmap.New(),
+		maxUnreferencedBytes: maxUnreferencedBytes,
+		numBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "container_store_bytes",
+			Help:      "Number of bytes of de-duplicated containers held in memory",
+		}),
+		numHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "container_store_hits",
+			Help:      "Number of parsed containers that were already held in memory",
+		}),
+		numMiss: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "container_store_misses",
+			Help:      "Number of parsed containers that weren't already held in memory",
+		}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(s.numBytes),
+		registerer.Register(s.numHits),
+		registerer.Register(s.numMiss),
+	)
+	return s, errs.Err
+}
+
+// Add returns the stored copy of [container], storing a copy of it if it
+// wasn't already stored, and the ID that must later be passed to Release.
+func (s *containerStore) Add(container []byte) ([]byte, ids.ID) {
+	containerID := hashing.ComputeHash256Array(container)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if entry, ok := s.containers[containerID]; ok {
+		if entry.refs == 0 {
+			s.unreferenced.Delete(containerID)
+			s.unreferencedBytes -= len(entry.bytes)
+		}
+		entry.refs++
+		s.numHits.Inc()
+		return entry.bytes, containerID
+	}
+
+	// Copy the container so that the buffer of the message it was parsed from
+	// isn't kept alive.
+	entry := &containerEntry{
+		bytes: make([]byte, len(container)),
+		refs:  1,
+	}
+	copy(entry.bytes, container)
+	s.containers[containerID] = entry
+	s.numMiss.Inc()
+	s.numBytes.Add(float64(len(container)))
+	return entry.bytes, containerID
+}
+
+// Release removes a reference to each of the containers. Containers that are no
+// longer referenced are evicted once too many unreferenced bytes are stored.
+func (s *containerStore) Release(containerIDs ...ids.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, containerID := range containerIDs {
+		entry, ok := s.containers[containerID]
+		if !ok || entry.refs == 0 {
+			continue
+		}
+		entry.refs--
+		if entry.refs == 0 {
+			s.unreferenced.Put(containerID, nil)
+			s.unreferencedBytes += len(entry.bytes)
+		}
+	}
+
+	for s.unreferencedBytes > s.maxUnreferencedBytes {
+		oldestIntf, _, ok := s.unreferenced.Oldest()
+		if !ok {
+			break
+		}
+		oldest := oldestIntf.(ids.ID)
+		numBytes := len(s.containers[oldest].bytes)
+		s.unreferenced.Delete(oldest)
+		delete(s.containers, oldest)
+		s.unreferencedBytes -= numBytes
+		s.numBytes.Sub(float64(numBytes))
+	}
+}
+
+// Len returns the number of stored containers
+func (s *containerStore) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.containers)
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestContainerStoreDeduplicates(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := newContainerStore("", prometheus.NewRegistry(), 0)
+	assert.NoError(err)
+
+	container := []byte{1, 2, 3}
+	stored0, id0 := s.Add(container)
+	stored1, id1 := s.Add([]byte{1, 2, 3})
+	assert.Equal(id0, id1)
+	assert.Equal(container, stored0)
+	assert.Equal(1, s.Len())
+
+	// Both messages reference the same copy, which doesn't alias the input
+	assert.Same(&stored0[0], &stored1[0])
+	assert.NotSame(&container[0], &stored0[0])
+
+	// The container is kept while it is still referenced
+	s.Release(id0)
+	assert.Equal(1, s.Len())
+
+	s.Release(id1)
+	assert.Equal(0, s.Len())
+
+	// Releasing an unknown container is a no-op
+	s.Release(id1)
+	assert.Equal(0, s.Len())
+}
+
+func TestContainerStoreEviction(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := newContainerStore("", prometheus.NewRegistry(), 4)
+	assert.NoError(err)
+
+	_, id0 := s.Add([]byte{0, 0, 0})
+	_, id1 := s.Add([]byte{1, 1, 1})
+	s.Release(id0)
+	assert.Equal(2, s.Len())
+
+	// Keeping both unreferenced containers would exceed the limit, so the
+	// least recently released one is evicted
+	s.Release(id1)
+	assert.Equal(1, s.Len())
+
+	// The remaining container is reused
+	_, id := s.Add([]byte{1, 1, 1})
+	assert.Equal(id1, id)
+	assert.Equal(1, s.Len())
+	s.Release(id)
+
+	_, id = s.Add([]byte{0, 0, 0})
+	assert.Equal(id0, id)
+	assert.Equal(2, s.Len())
+}
+
+func TestParseSharesContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	assert.NoError(err)
+
+	container := []byte{4, 5, 6}
+	msg, err := mc.Pack(
+		Put,
+		map[Field]interface{}{
+			ChainID:        ids.Empty[:],
+			RequestID:      uint32(1),
+			ContainerID:    ids.Empty[:],
+			ContainerBytes: container,
+		},
+		false,
+		false,
+	)
+	assert.NoError(err)
+
+	c := mc.(*codec)
+	finished := 0
+	onFinished := func() { finished++ }
+
+	parsed0, err := mc.Parse(msg.Bytes(), ids.ShortEmpty, onFinished)
+	assert.NoError(err)
+	parsed1, err := mc.Parse(msg.Bytes(), ids.ShortEmpty, onFinished)
+	assert.NoError(err)
+
+	bytes0 := parsed0.Get(ContainerBytes).([]byte)
+	bytes1 := parsed1.Get(ContainerBytes).([]byte)
+	assert.Equal(container, bytes0)
+	assert.Same(&bytes0[0], &bytes1[0])
+	assert.Equal(1, c.containers.Len())
+
+	parsed0.OnFinishedHandling()
+	parsed1.OnFinishedHandling()
+	assert.Equal(2, finished)
+	assert.Equal(1, c.containers.Len())
+	assert.Equal(len(container), c.containers.unreferencedBytes)
+}
@@ -32,6 +32,11 @@ const (
 	VMMessage                        // Used internally
 	Uptime                           // Used for Pong
 	VersionStruct                    // Used internally
+	StartHeight                      // Used in light client header requests
+	NumHeaders                       // Used in light client header requests
+	HeaderBytes                      // Used in light client header responses
+	RequestIDs                       // Used in batched queries
+	Features                         // Used in handshake
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -73,6 +78,16 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackHashes
 	case Uptime:
 		return wrappers.TryPackByte
+	case StartHeight:
+		return wrappers.TryPackLong
+	case NumHeaders:
+		return wrappers.TryPackInt
+	case HeaderBytes:
+		return wrappers.TryPack2DBytes
+	case RequestIDs:
+		return wrappers.TryPackInts
+	case Features:
+		return wrappers.TryPackLong
 	default:
 		return nil
 	}
@@ -117,11 +132,37 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackHashes
 	case Uptime:
 		return wrappers.TryUnpackByte
+	case StartHeight:
+		return wrappers.TryUnpackLong
+	case NumHeaders:
+		return wrappers.TryUnpackInt
+	case HeaderBytes:
+		return wrappers.TryUnpack2DBytes
+	case RequestIDs:
+		return wrappers.TryUnpackInts
+	case Features:
+		return wrappers.TryUnpackLong
 	default:
 		return nil
 	}
 }
 
+// Retainable returns true if the unpacked value of this field refers to the
+// bytes of the message it was parsed from, and may be kept by the receiver of
+// the message after the message has been handled.
+//
+// ContainerBytes and MultiContainerBytes are kept by VMs, but they're replaced
+// by copies from the codec's container store before the message is handled, so
+// they never refer to the bytes of the message.
+func (f Field) Retainable() bool {
+	switch f {
+	case IP, SigBytes, Peers, AppBytes, HeaderBytes:
+		return true
+	default:
+		return false
+	}
+}
+
 func (f Field) String() string {
 	switch f {
 	case VersionStr:
@@ -164,6 +205,16 @@ func (f Field) String() string {
 		return "Uptime"
 	case VersionStruct:
 		return "VersionStruct"
+	case StartHeight:
+		return "StartHeight"
+	case NumHeaders:
+		return "NumHeaders"
+	case HeaderBytes:
+		return "HeaderBytes"
+	case RequestIDs:
+		return "RequestIDs"
+	case Features:
+		return "Features"
 	default:
 		return "Unknown Field"
 	}
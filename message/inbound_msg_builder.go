@@ -75,6 +75,22 @@ type InboundMsgBuilder interface {
 		nodeID ids.ShortID,
 	) InboundMessage
 
+	InboundPushQueryBatch(
+		chainID ids.ID,
+		requestIDs []uint32,
+		deadline time.Duration,
+		containerIDs []ids.ID,
+		containers [][]byte,
+		nodeID ids.ShortID,
+	) InboundMessage // used in UTs only
+
+	InboundChitsBatch(
+		chainID ids.ID,
+		requestIDs []uint32,
+		votes []ids.ID,
+		nodeID ids.ShortID,
+	) InboundMessage // used in UTs only
+
 	InboundAppRequest(
 		chainID ids.ID,
 		requestID uint32,
@@ -266,6 +282,50 @@ func (b *inMsgBuilder) InboundChits(
 	}
 }
 
+func (b *inMsgBuilder) InboundPushQueryBatch(
+	chainID ids.ID,
+	requestIDs []uint32,
+	deadline time.Duration,
+	containerIDs []ids.ID,
+	containers [][]byte,
+	nodeID ids.ShortID,
+) InboundMessage {
+	received := b.clock.Time()
+	containerIDBytes := make([][]byte, len(containerIDs))
+	encodeContainerIDs(containerIDs, containerIDBytes)
+	return &inboundMessage{
+		op: PushQueryBatch,
+		fields: map[Field]interface{}{
+			ChainID:             chainID[:],
+			RequestIDs:          requestIDs,
+			Deadline:            uint64(deadline),
+			ContainerIDs:        containerIDBytes,
+			MultiContainerBytes: containers,
+		},
+		nodeID:         nodeID,
+		expirationTime: received.Add(deadline),
+	}
+}
+
+func (b *inMsgBuilder) InboundChitsBatch(
+	chainID ids.ID,
+	requestIDs []uint32,
+	votes []ids.ID,
+	nodeID ids.ShortID,
+) InboundMessage {
+	voteBytes := make([][]byte, len(votes))
+	encodeContainerIDs(votes, voteBytes)
+	return &inboundMessage{
+		op: ChitsBatch,
+		fields: map[Field]interface{}{
+			ChainID:      chainID[:],
+			RequestIDs:   requestIDs,
+			ContainerIDs: voteBytes,
+		},
+		nodeID: nodeID,
+	}
+}
+
 func (b *inMsgBuilder) InboundAppRequest(
 	chainID ids.ID,
 	requestID uint32,
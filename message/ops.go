@@ -42,6 +42,12 @@ const (
 	AppRequest
 	AppResponse
 	AppGossip
+	// Light client:
+	GetHeaders
+	Headers
+	// Batched consensus:
+	PushQueryBatch
+	ChitsBatch
 
 	// Internal messages (External messages should be added above these):
 	GetAcceptedFrontierFailed
@@ -83,13 +89,22 @@ var (
 		Chits,
 		AppResponse,
 	}
+	// Messages that carry several queries, or the responses to several
+	// queries, sent to the same node at once
+	ConsensusBatchOps = []Op{
+		PushQueryBatch,
+		ChitsBatch,
+	}
 	// AppGossip is the only message that is sent unrequested without the
 	// expectation of a response
 	ConsensusExternalOps = append(
 		ConsensusRequestOps,
 		append(
 			ConsensusResponseOps,
-			AppGossip,
+			append(
+				ConsensusBatchOps,
+				AppGossip,
+			)...,
 		)...,
 	)
 	ConsensusInternalOps = []Op{
@@ -107,7 +122,20 @@ var (
 	}
 	ConsensusOps = append(ConsensusExternalOps, ConsensusInternalOps...)
 
-	ExternalOps = append(ConsensusExternalOps, HandshakeOps...)
+	// Messages that are handled by the network rather than routed to a chain,
+	// so that light clients can follow chains they aren't running
+	LightClientOps = []Op{
+		GetHeaders,
+		Headers,
+	}
+
+	ExternalOps = append(
+		ConsensusExternalOps,
+		append(
+			HandshakeOps,
+			LightClientOps...,
+		)...,
+	)
 
 	SynchronousOps = []Op{
 		GetAcceptedFrontier,
@@ -121,6 +149,7 @@ var (
 		PushQuery,
 		PullQuery,
 		Chits,
+		PushQueryBatch,
 		GetAcceptedFrontierFailed,
 		GetAcceptedFailed,
 		GetFailed,
@@ -169,6 +198,7 @@ var (
 		Get:                 {},
 		PushQuery:           {},
 		PullQuery:           {},
+		PushQueryBatch:      {},
 		AppRequest:          {},
 		AppGossip:           {},
 	}
@@ -177,7 +207,7 @@ var (
 	messages = map[Op][]Field{
 		// Handshake:
 		// TODO: remove NodeID from the Version message
-		Version:  {NetworkID, NodeID, MyTime, IP, VersionStr, VersionTime, SigBytes, TrackedSubnets},
+		Version:  {NetworkID, NodeID, MyTime, IP, VersionStr, VersionTime, SigBytes, TrackedSubnets, Features},
 		PeerList: {Peers},
 		Ping:     {},
 		Pong:     {Uptime},
@@ -198,12 +228,26 @@ var (
 		AppRequest:  {ChainID, RequestID, Deadline, AppBytes},
 		AppResponse: {ChainID, RequestID, AppBytes},
 		AppGossip:   {ChainID, AppBytes},
+		// Light client:
+		GetHeaders: {ChainID, RequestID, StartHeight, NumHeaders},
+		Headers:    {ChainID, RequestID, HeaderBytes},
+		// Batched consensus:
+		PushQueryBatch: {ChainID, RequestIDs, Deadline, ContainerIDs, MultiContainerBytes},
+		ChitsBatch:     {ChainID, RequestIDs, ContainerIDs},
+	}
+
+	// Defines the number of fields, at the end of a message, that were added
+	// after the message was introduced. They may be omitted, by older peers
+	// that don't know about them, in which case they're missing from the
+	// parsed message.
+	optionalFields = map[Op]int{
+		Version: 1, // Features
 	}
 )
 
 func (op Op) Compressible() bool {
 	switch op {
-	case PeerList, Put, Ancestors, PushQuery, AppRequest, AppResponse, AppGossip:
+	case PeerList, Put, Ancestors, PushQuery, AppRequest, AppResponse, AppGossip, Headers, PushQueryBatch:
 		return true
 	default:
 		return false
@@ -248,6 +292,14 @@ func (op Op) String() string {
 		return "app_response"
 	case AppGossip:
 		return "app_gossip"
+	case GetHeaders:
+		return "get_headers"
+	case Headers:
+		return "headers"
+	case PushQueryBatch:
+		return "push_query_batch"
+	case ChitsBatch:
+		return "chits_batch"
 
 	case GetAcceptedFrontierFailed:
 		return "get_accepted_frontier_failed"
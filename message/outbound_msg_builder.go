@@ -8,6 +8,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 var _ OutboundMsgBuilder = &outMsgBuilder{}
@@ -24,6 +25,7 @@ type OutboundMsgBuilder interface {
 		myVersionTime uint64,
 		sig []byte,
 		trackedSubnets []ids.ID,
+		myFeatures *version.FeatureBits,
 	) (OutboundMessage, error)
 
 	PeerList(
@@ -108,6 +110,20 @@ type OutboundMsgBuilder interface {
 		containerIDs []ids.ID,
 	) (OutboundMessage, error)
 
+	PushQueryBatch(
+		chainID ids.ID,
+		requestIDs []uint32,
+		deadline time.Duration,
+		containerIDs []ids.ID,
+		containers [][]byte,
+	) (OutboundMessage, error)
+
+	ChitsBatch(
+		chainID ids.ID,
+		requestIDs []uint32,
+		votes []ids.ID,
+	) (OutboundMessage, error)
+
 	AppRequest(
 		chainID ids.ID,
 		requestID uint32,
@@ -125,6 +141,19 @@ type OutboundMsgBuilder interface {
 		chainID ids.ID,
 		msg []byte,
 	) (OutboundMessage, error)
+
+	GetHeaders(
+		chainID ids.ID,
+		requestID uint32,
+		startHeight uint64,
+		numHeaders uint32,
+	) (OutboundMessage, error)
+
+	Headers(
+		chainID ids.ID,
+		requestID uint32,
+		headers [][]byte,
+	) (OutboundMessage, error)
 }
 
 type outMsgBuilder struct {
@@ -147,24 +176,32 @@ func (b *outMsgBuilder) Version(
 	myVersionTime uint64,
 	sig []byte,
 	trackedSubnets []ids.ID,
+	myFeatures *version.FeatureBits,
 ) (OutboundMessage, error) {
 	subnetIDBytes := make([][]byte, len(trackedSubnets))
 	for i, containerID := range trackedSubnets {
 		copy := containerID
 		subnetIDBytes[i] = copy[:]
 	}
+	fields := map[Field]interface{}{
+		NetworkID:      networkID,
+		NodeID:         uint32(0),
+		MyTime:         myTime,
+		IP:             ip,
+		VersionStr:     myVersion,
+		VersionTime:    myVersionTime,
+		SigBytes:       sig,
+		TrackedSubnets: subnetIDBytes,
+	}
+	// [myFeatures] is nil while the features can't be advertised yet, as peers
+	// that don't know about feature bits reject Version messages including
+	// them
+	if myFeatures != nil {
+		fields[Features] = uint64(*myFeatures)
+	}
 	return b.c.Pack(
 		Version,
-		map[Field]interface{}{
-			NetworkID:      networkID,
-			NodeID:         uint32(0),
-			MyTime:         myTime,
-			IP:             ip,
-			VersionStr:     myVersion,
-			VersionTime:    myVersionTime,
-			SigBytes:       sig,
-			TrackedSubnets: subnetIDBytes,
-		},
+		fields,
 		Version.Compressible(), // Version Messages can't be compressed
 		true,
 	)
@@ -422,6 +459,52 @@ func (b *outMsgBuilder) Chits(
 	)
 }
 
+// PushQuery of several containers at once. The query for [containers[i]] has
+// request ID [requestIDs[i]].
+func (b *outMsgBuilder) PushQueryBatch(
+	chainID ids.ID,
+	requestIDs []uint32,
+	deadline time.Duration,
+	containerIDs []ids.ID,
+	containers [][]byte,
+) (OutboundMessage, error) {
+	containerIDBytes := make([][]byte, len(containerIDs))
+	encodeContainerIDs(containerIDs, containerIDBytes)
+	return b.c.Pack(
+		PushQueryBatch,
+		map[Field]interface{}{
+			ChainID:             chainID[:],
+			RequestIDs:          requestIDs,
+			Deadline:            uint64(deadline),
+			ContainerIDs:        containerIDBytes,
+			MultiContainerBytes: containers,
+		},
+		b.compress && PushQueryBatch.Compressible(), // PushQueryBatch messages may be compressed
+		false,
+	)
+}
+
+// Chits answering several queries at once. The request with ID [requestIDs[i]]
+// is answered with a single vote, [votes[i]].
+func (b *outMsgBuilder) ChitsBatch(
+	chainID ids.ID,
+	requestIDs []uint32,
+	votes []ids.ID,
+) (OutboundMessage, error) {
+	voteBytes := make([][]byte, len(votes))
+	encodeContainerIDs(votes, voteBytes)
+	return b.c.Pack(
+		ChitsBatch,
+		map[Field]interface{}{
+			ChainID:      chainID[:],
+			RequestIDs:   requestIDs,
+			ContainerIDs: voteBytes,
+		},
+		ChitsBatch.Compressible(), // ChitsBatch messages can't be compressed
+		false,
+	)
+}
+
 // Application level request
 func (b *outMsgBuilder) AppRequest(
 	chainID ids.ID,
@@ -468,3 +551,41 @@ func (b *outMsgBuilder) AppGossip(chainID ids.ID, msg []byte) (OutboundMessage,
 		false,
 	)
 }
+
+// Light client request for the headers of a chain
+func (b *outMsgBuilder) GetHeaders(
+	chainID ids.ID,
+	requestID uint32,
+	startHeight uint64,
+	numHeaders uint32,
+) (OutboundMessage, error) {
+	return b.c.Pack(
+		GetHeaders,
+		map[Field]interface{}{
+			ChainID:     chainID[:],
+			RequestID:   requestID,
+			StartHeight: startHeight,
+			NumHeaders:  numHeaders,
+		},
+		GetHeaders.Compressible(), // GetHeaders messages can't be compressed
+		false,
+	)
+}
+
+// Response to a light client's request for headers
+func (b *outMsgBuilder) Headers(
+	chainID ids.ID,
+	requestID uint32,
+	headers [][]byte,
+) (OutboundMessage, error) {
+	return b.c.Pack(
+		Headers,
+		map[Field]interface{}{
+			ChainID:     chainID[:],
+			RequestID:   requestID,
+			HeaderBytes: headers,
+		},
+		b.compress && Headers.Compressible(), // Headers messages may be compressed
+		false,
+	)
+}
@@ -0,0 +1,230 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Persisted bans are keyed by a prefix byte followed by the banned node ID or
+// the 16 byte form of the banned IP. The value is the unix time of the expiry.
+const (
+	nodeIDBanPrefix byte = iota
+	ipBanPrefix
+)
+
+var (
+	errNoBanTarget     = errors.New("a ban needs either a node ID or an IP")
+	errBanTwoTargets   = errors.New("a ban can't have both a node ID and an IP")
+	errInvalidBanKey   = errors.New("invalid persisted ban key")
+	errInvalidBanValue = errors.New("invalid persisted ban value")
+)
+
+// BanTarget identifies the peers a ban applies to. Exactly one of the fields
+// is set: either the peer with NodeID is banned, or the peers at IP are.
+type BanTarget struct {
+	NodeID ids.ShortID
+	IP     net.IP
+}
+
+func (t BanTarget) verify() error {
+	switch {
+	case t.NodeID == ids.ShortEmpty && t.IP == nil:
+		return errNoBanTarget
+	case t.NodeID != ids.ShortEmpty && t.IP != nil:
+		return errBanTwoTargets
+	default:
+		return nil
+	}
+}
+
+func (t BanTarget) key() []byte {
+	if t.IP != nil {
+		return append([]byte{ipBanPrefix}, t.IP.To16()...)
+	}
+	return append([]byte{nodeIDBanPrefix}, t.NodeID[:]...)
+}
+
+func parseBanTarget(key []byte) (BanTarget, error) {
+	switch {
+	case len(key) == 1+len(ids.ShortEmpty) && key[0] == nodeIDBanPrefix:
+		nodeID, err := ids.ToShortID(key[1:])
+		return BanTarget{NodeID: nodeID}, err
+	case len(key) == 1+net.IPv6len && key[0] == ipBanPrefix:
+		return BanTarget{IP: net.IP(key[1:])}, nil
+	default:
+		return BanTarget{}, errInvalidBanKey
+	}
+}
+
+// Ban is a ban this node enforces
+type Ban struct {
+	BanTarget
+	// Expiry is the time the ban is lifted at
+	Expiry time.Time
+}
+
+// banList keeps the bans the operator placed on peers. Bans are persisted, so
+// that they're still enforced after the node restarts, and are forgotten once
+// they expire.
+type banList struct {
+	// target key -> expiry. It's nil if the bans aren't persisted.
+	db database.Database
+
+	lock sync.Mutex
+	// target key -> ban
+	bans map[string]Ban
+}
+
+// newBanList returns the bans persisted in [db], without the bans that expired
+// before [now]. [db] may be nil, in which case bans are only kept in memory.
+func newBanList(db database.Database, now time.Time) (*banList, error) {
+	b := &banList{
+		db:   db,
+		bans: make(map[string]Ban),
+	}
+	if db == nil {
+		return b, nil
+	}
+
+	expired, err := b.load(now)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range expired {
+		if err := db.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// load reads the persisted bans that are in effect at [now] and returns the
+// keys of the expired ones
+func (b *banList) load(now time.Time) ([][]byte, error) {
+	it := b.db.NewIterator()
+	defer it.Release()
+
+	expired := [][]byte(nil)
+	for it.Next() {
+		key := utils.CopyBytes(it.Key())
+		target, err := parseBanTarget(key)
+		if err != nil {
+			return nil, err
+		}
+		value := it.Value()
+		if len(value) != wrappers.LongLen {
+			return nil, errInvalidBanValue
+		}
+		packer := wrappers.Packer{Bytes: value}
+		ban := Ban{
+			BanTarget: target,
+			Expiry:    time.Unix(int64(packer.UnpackLong()), 0),
+		}
+		if !now.Before(ban.Expiry) {
+			expired = append(expired, key)
+			continue
+		}
+		b.bans[string(key)] = ban
+	}
+	return expired, it.Error()
+}
+
+// ban bans [ban.BanTarget] until [ban.Expiry], replacing any previous ban of
+// the same target
+func (b *banList) ban(ban Ban) error {
+	if err := ban.verify(); err != nil {
+		return err
+	}
+	key := ban.key()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.db != nil {
+		packer := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+		packer.PackLong(uint64(ban.Expiry.Unix()))
+		if err := b.db.Put(key, packer.Bytes); err != nil {
+			return err
+		}
+	}
+	b.bans[string(key)] = ban
+	return nil
+}
+
+// unban lifts the ban of [target]. Returns false if [target] wasn't banned.
+func (b *banList) unban(target BanTarget) (bool, error) {
+	if err := target.verify(); err != nil {
+		return false, err
+	}
+	key := target.key()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	_, banned := b.bans[string(key)]
+	return banned, b.remove(key)
+}
+
+// banned returns true if [target] is banned at [now]. Expired bans found along
+// the way are removed.
+func (b *banList) banned(target BanTarget, now time.Time) bool {
+	key := target.key()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	ban, ok := b.bans[string(key)]
+	if !ok {
+		return false
+	}
+	if now.Before(ban.Expiry) {
+		return true
+	}
+	// Failing to remove the ban from the database only means it will be
+	// removed on restart instead.
+	_ = b.remove(key)
+	return false
+}
+
+// nodeBanned returns true if the peer with [nodeID] at [ip] is banned at [now]
+func (b *banList) nodeBanned(nodeID ids.ShortID, ip net.IP, now time.Time) bool {
+	return b.banned(BanTarget{NodeID: nodeID}, now) ||
+		(ip != nil && b.banned(BanTarget{IP: ip}, now))
+}
+
+// active returns the bans in effect at [now], sorted by expiry
+func (b *banList) active(now time.Time) []Ban {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	bans := make([]Ban, 0, len(b.bans))
+	for key, ban := range b.bans {
+		if !now.Before(ban.Expiry) {
+			_ = b.remove([]byte(key))
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].Expiry.Before(bans[j].Expiry) })
+	return bans
+}
+
+// remove assumes [b.lock] is held
+func (b *banList) remove(key []byte) error {
+	delete(b.bans, string(key))
+	if b.db == nil {
+		return nil
+	}
+	return b.db.Delete(key)
+}
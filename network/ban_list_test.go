@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestBanList(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	now := time.Unix(1000, 0)
+	b, err := newBanList(db, now)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	ip := net.IPv4(1, 2, 3, 4)
+	assert.NoError(b.ban(Ban{
+		BanTarget: BanTarget{NodeID: nodeID},
+		Expiry:    now.Add(time.Minute),
+	}))
+	assert.NoError(b.ban(Ban{
+		BanTarget: BanTarget{IP: ip},
+		Expiry:    now.Add(time.Hour),
+	}))
+	assert.ErrorIs(b.ban(Ban{Expiry: now.Add(time.Hour)}), errNoBanTarget)
+	assert.ErrorIs(b.ban(Ban{
+		BanTarget: BanTarget{NodeID: nodeID, IP: ip},
+		Expiry:    now.Add(time.Hour),
+	}), errBanTwoTargets)
+
+	assert.True(b.nodeBanned(nodeID, nil, now))
+	assert.True(b.nodeBanned(ids.GenerateTestShortID(), ip.To16(), now))
+	assert.False(b.nodeBanned(ids.GenerateTestShortID(), net.IPv4(1, 2, 3, 5), now))
+	assert.Len(b.active(now), 2)
+
+	// Bans are kept across restarts, without the expired ones
+	b, err = newBanList(db, now.Add(2*time.Minute))
+	assert.NoError(err)
+	bans := b.active(now.Add(2 * time.Minute))
+	assert.Len(bans, 1)
+	assert.True(ip.Equal(bans[0].IP))
+	assert.False(b.nodeBanned(nodeID, nil, now.Add(2*time.Minute)))
+
+	// Expired bans are removed once they're checked
+	assert.False(b.banned(BanTarget{IP: ip}, now.Add(time.Hour)))
+	b, err = newBanList(db, now)
+	assert.NoError(err)
+	assert.Empty(b.active(now))
+
+	assert.NoError(b.ban(Ban{
+		BanTarget: BanTarget{NodeID: nodeID},
+		Expiry:    now.Add(time.Minute),
+	}))
+	unbanned, err := b.unban(BanTarget{NodeID: nodeID})
+	assert.NoError(err)
+	assert.True(unbanned)
+	unbanned, err = b.unban(BanTarget{NodeID: nodeID})
+	assert.NoError(err)
+	assert.False(unbanned)
+	b, err = newBanList(db, now)
+	assert.NoError(err)
+	assert.Empty(b.active(now))
+}
@@ -8,12 +8,17 @@ import (
 	"crypto/tls"
 	"time"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/lightclient"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/uptime"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 // HealthConfig describes parameters for network layer health checks.
@@ -66,6 +71,12 @@ type PeerListGossipConfig struct {
 	// PeerListGossipFreq is the frequency that this node will attempt to gossip
 	// signed IPs to its peers.
 	PeerListGossipFreq time.Duration `json:"peerListGossipFreq"`
+
+	// ValidatorSnapshotEpoch is the maximum amount of time the validator set
+	// of a subnet is reused to sample the peers to gossip to. The set is also
+	// retaken as soon as a validator is added or removed. If 0, the set is
+	// retaken on every gossip.
+	ValidatorSnapshotEpoch time.Duration `json:"validatorSnapshotEpoch"`
 }
 
 type TimeoutConfig struct {
@@ -73,6 +84,11 @@ type TimeoutConfig struct {
 	// from a peer we sent a Ping to.
 	PingPongTimeout time.Duration `json:"pingPongTimeout"`
 
+	// DeadPeerTimeout is the maximum amount of time to wait for a Pong response
+	// before disconnecting from the peer, even if other messages are still
+	// being received from it. If 0, only [PingPongTimeout] is enforced.
+	DeadPeerTimeout time.Duration `json:"deadPeerTimeout"`
+
 	// ReadHandshakeTimeout is the maximum amount of time to wait for the peer's
 	// connection upgrade to finish before starting the p2p handshake.
 	ReadHandshakeTimeout time.Duration `json:"readHandshakeTimeout"`
@@ -96,15 +112,31 @@ type ThrottlerConfig struct {
 	MaxInboundConnsPerSec             float64                                      `json:"maxInboundConnsPerSec"`
 }
 
+type AllowlistConfig struct {
+	// Enabled restricts the nodes this node connects to. The TLS handshake
+	// with any node that isn't allowed is refused. Beacons are always allowed,
+	// so that the node can bootstrap.
+	Enabled bool `json:"enabled"`
+
+	// NodeIDs are the nodes allowed to connect
+	NodeIDs ids.ShortSet `json:"nodeIDs"`
+
+	// AllowValidators allows the current validators of the primary network,
+	// as registered on the P-chain, to connect
+	AllowValidators bool `json:"allowValidators"`
+}
+
 type Config struct {
 	HealthConfig         `json:"healthConfig"`
 	PeerListGossipConfig `json:"peerListGossipConfig"`
 	TimeoutConfig        `json:"timeoutConfigs"`
 	DelayConfig          `json:"delayConfig"`
 	ThrottlerConfig      ThrottlerConfig `json:"throttlerConfig"`
+	AllowlistConfig      AllowlistConfig `json:"allowlistConfig"`
 
-	DialerConfig dialer.Config `json:"dialerConfig"`
-	TLSConfig    *tls.Config   `json:"-"`
+	DialerConfig      dialer.Config      `json:"dialerConfig"`
+	LightClientConfig lightclient.Config `json:"lightClientConfig"`
+	TLSConfig         *tls.Config        `json:"-"`
 
 	Namespace          string              `json:"namespace"`
 	MyNodeID           ids.ShortID         `json:"myNodeID"`
@@ -118,6 +150,10 @@ type Config struct {
 	// true.
 	CompressionEnabled bool `json:"compressionEnabled"`
 
+	// PeerPolicy restricts the versions of the peers this node connects to and
+	// describes which features each version supports.
+	PeerPolicy *version.PeerPolicy `json:"-"`
+
 	// TLSKey is this node's TLS key that is used to sign IPs.
 	TLSKey crypto.Signer `json:"-"`
 
@@ -130,6 +166,21 @@ type Config struct {
 
 	UptimeCalculator uptime.Calculator `json:"-"`
 
+	// LightClientServer serves headers to light clients. It's nil if
+	// [LightClientConfig] isn't enabled.
+	LightClientServer lightclient.Server `json:"-"`
+
+	// PeerTime records the times reported by peers. It's nil if blocks aren't
+	// timestamped with the time reported by peers.
+	PeerTime *timer.PeerTime `json:"-"`
+
+	// Clock tells the time. If nil, the local clock is used.
+	Clock *mockable.Clock `json:"-"`
+
+	// Upgrades are the activation times of the network upgrades. If nil, the
+	// upgrade schedule of [NetworkID] is used.
+	Upgrades *version.UpgradeSchedule `json:"-"`
+
 	// UptimeMetricFreq marks how frequently this node will recalculate the
 	// observed average uptime metrics.
 	UptimeMetricFreq time.Duration `json:"uptimeMetricFreq"`
@@ -156,4 +207,14 @@ type Config struct {
 	// Size, in bytes, of the buffer that we write peer messages into
 	// (there is one buffer per peer)
 	PeerWriteBufferSize int `json:"peerWriteBufferSize"`
+
+	// PeerDB persists the IPs of the validators this node connects to. On
+	// restart, the best [MaxPersistedPeers] of them are dialed along with the
+	// beacons. If nil, or if [MaxPersistedPeers] is 0, nothing is persisted.
+	PeerDB            database.Database `json:"-"`
+	MaxPersistedPeers int               `json:"maxPersistedPeers"`
+
+	// BanDB persists the bans placed on peers, so that they're enforced across
+	// restarts. If nil, bans are only kept in memory.
+	BanDB database.Database `json:"-"`
 }
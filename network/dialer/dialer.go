@@ -33,12 +33,27 @@ type dialer struct {
 type Config struct {
 	ThrottleRps       uint32        `json:"throttleRps"`
 	ConnectionTimeout time.Duration `json:"connectionTimeout"`
+	// KeepAlivePeriod is the period between TCP keep-alive probes on
+	// connections to peers, including the ones accepted by this node. If 0,
+	// keep-alive probes aren't sent.
+	KeepAlivePeriod time.Duration `json:"keepAlivePeriod"`
+}
+
+// KeepAlive returns the value to set as the KeepAlive of a net.Dialer or
+// net.ListenConfig to send probes every [period]. Unlike the net package,
+// where 0 enables the default period, a [period] of 0 disables keep-alives.
+func KeepAlive(period time.Duration) time.Duration {
+	if period <= 0 {
+		return -1
+	}
+	return period
 }
 
 // NewDialer returns a new Dialer that calls net.Dial with the provided network.
 // [network] is the network passed into Dial. Should probably be "TCP".
 // [dialerConfig.connectionTimeout] gives the timeout when dialing an IP.
 // [dialerConfig.throttleRps] gives the max number of outgoing connection attempts/second.
+// [dialerConfig.keepAlivePeriod] gives the period between TCP keep-alive probes.
 // If [dialerConfig.throttleRps] == 0, outgoing connections aren't rate-limited.
 func NewDialer(network string, dialerConfig Config, log logging.Logger) Dialer {
 	var throttler throttling.DialThrottler
@@ -48,12 +63,16 @@ func NewDialer(network string, dialerConfig Config, log logging.Logger) Dialer {
 		throttler = throttling.NewDialThrottler(int(dialerConfig.ThrottleRps))
 	}
 	log.Debug(
-		"dialer has outgoing connection limit of %d/second and dial timeout %s",
+		"dialer has outgoing connection limit of %d/second, dial timeout %s and keep-alive period %s",
 		dialerConfig.ThrottleRps,
 		dialerConfig.ConnectionTimeout,
+		dialerConfig.KeepAlivePeriod,
 	)
 	return &dialer{
-		dialer:    net.Dialer{Timeout: dialerConfig.ConnectionTimeout},
+		dialer: net.Dialer{
+			Timeout:   dialerConfig.ConnectionTimeout,
+			KeepAlive: KeepAlive(dialerConfig.KeepAlivePeriod),
+		},
 		log:       log,
 		network:   network,
 		throttler: throttler,
@@ -0,0 +1,26 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lightclient
+
+import (
+	"math"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+)
+
+// CodecVersion is the version of [Codec] used to marshal headers
+const CodecVersion = 0
+
+// Codec marshals the headers that are sent to light clients
+var Codec codec.Manager
+
+func init() {
+	lc := linearcodec.NewCustomMaxLength(math.MaxUint32)
+	Codec = codec.NewManager(math.MaxInt32)
+
+	if err := Codec.RegisterCodec(CodecVersion, lc); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lightclient
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+)
+
+var (
+	errNoProposer          = errors.New("header has no proposer")
+	errUnexpectedProof     = errors.New("unsigned header has a validator set proof")
+	errWrongProofKey       = errors.New("validator set proof is for a different node ID")
+	errWrongProofWeight    = errors.New("validator set proof is for a different weight")
+	errZeroProposerWeight  = errors.New("proposer wasn't a validator")
+	errUnexpectedParent    = errors.New("header doesn't build on the previous header")
+	errNonSequentialHeight = errors.New("header heights aren't sequential")
+)
+
+// Header is the part of an accepted ProposerVM block that a light client needs
+// to follow the progress of a chain without downloading the chain's blocks.
+//
+// The proposer signs [BlockID] and [ParentID], so the signature links each
+// header to its parent. [Timestamp] and [PChainHeight] are committed to by
+// [BlockID] but can only be checked against it by fetching the full block.
+//
+// Blocks that weren't signed by a proposer, such as the first post-fork block,
+// options and blocks issued after every proposer window passed, have unsigned
+// headers. Unsigned headers have no certificate, signature or validator set
+// proof, so they're only linked to their parents by ID. Options have no
+// [Timestamp] or [PChainHeight].
+type Header struct {
+	BlockID      ids.ID `serialize:"true"`
+	ParentID     ids.ID `serialize:"true"`
+	Height       uint64 `serialize:"true"`
+	Timestamp    int64  `serialize:"true"`
+	PChainHeight uint64 `serialize:"true"`
+	Certificate  []byte `serialize:"true"`
+	Signature    []byte `serialize:"true"`
+
+	// Merkle root of the validator set of the chain's subnet at
+	// [PChainHeight], and a proof of the proposer's weight in it. The
+	// validator set is encoded as the P-chain encodes it, mapping each node ID
+	// to its big endian weight.
+	ValidatorSetRoot ids.ID         `serialize:"true"`
+	ProposerWeight   uint64         `serialize:"true"`
+	ProposerProof    merkledb.Proof `serialize:"true"`
+}
+
+// ParseHeader parses the bytes of a header sent in a Headers message
+func ParseHeader(b []byte) (*Header, error) {
+	header := &Header{}
+	_, err := Codec.Unmarshal(b, header)
+	return header, err
+}
+
+// Bytes returns the bytes of this header that are sent in a Headers message
+func (h *Header) Bytes() ([]byte, error) {
+	return Codec.Marshal(CodecVersion, h)
+}
+
+// Signed returns true iff this header was signed by a proposer
+func (h *Header) Signed() bool {
+	return len(h.Certificate) != 0
+}
+
+// Proposer returns the node ID of the validator that signed this header
+func (h *Header) Proposer() (ids.ShortID, error) {
+	if !h.Signed() {
+		return ids.ShortEmpty, errNoProposer
+	}
+	cert, err := x509.ParseCertificate(h.Certificate)
+	if err != nil {
+		return ids.ShortEmpty, err
+	}
+	return hashing.ComputeHash160Array(hashing.ComputeHash256(cert.Raw)), nil
+}
+
+// Verify returns nil iff this header was signed by its proposer for
+// [chainID], and the proposer had a non-zero weight in the validator set with
+// root [ValidatorSetRoot]. Unsigned headers are valid iff they don't claim a
+// signature or a proposer's weight.
+//
+// [ValidatorSetRoot] is the root that the serving node computed for the
+// validator set at [PChainHeight]. It isn't committed to by the P-chain, so
+// light clients should check it against roots they trust, for example ones
+// that several nodes agree on in platform.getValidatorSetProof.
+func (h *Header) Verify(chainID ids.ID) error {
+	if !h.Signed() {
+		if len(h.Signature) != 0 || h.ValidatorSetRoot != ids.Empty || h.ProposerWeight != 0 ||
+			len(h.ProposerProof.Key) != 0 || h.ProposerProof.HasValue || len(h.ProposerProof.Path) != 0 {
+			return errUnexpectedProof
+		}
+		return nil
+	}
+	cert, err := x509.ParseCertificate(h.Certificate)
+	if err != nil {
+		return err
+	}
+
+	signedHeader, err := block.BuildHeader(chainID, h.ParentID, h.BlockID)
+	if err != nil {
+		return err
+	}
+	if err := cert.CheckSignature(cert.SignatureAlgorithm, signedHeader.Bytes(), h.Signature); err != nil {
+		return err
+	}
+
+	proposer := hashing.ComputeHash160Array(hashing.ComputeHash256(cert.Raw))
+	if !bytes.Equal(h.ProposerProof.Key, proposer[:]) {
+		return errWrongProofKey
+	}
+	if h.ProposerWeight == 0 {
+		return errZeroProposerWeight
+	}
+	if !h.ProposerProof.HasValue || !bytes.Equal(h.ProposerProof.Value, database.PackUInt64(h.ProposerWeight)) {
+		return errWrongProofWeight
+	}
+	return h.ProposerProof.Verify(h.ValidatorSetRoot)
+}
+
+// VerifyChain verifies each of [headers] and that they form a chain starting
+// from the accepted block [parentID] at height [parentHeight].
+func VerifyChain(chainID ids.ID, parentID ids.ID, parentHeight uint64, headers []*Header) error {
+	for _, header := range headers {
+		if header.ParentID != parentID {
+			return fmt.Errorf("%w at height %d", errUnexpectedParent, header.Height)
+		}
+		if header.Height != parentHeight+1 {
+			return fmt.Errorf("%w: expected %d but got %d", errNonSequentialHeight, parentHeight+1, header.Height)
+		}
+		if err := header.Verify(chainID); err != nil {
+			return fmt.Errorf("invalid header at height %d: %w", header.Height, err)
+		}
+		parentID = header.BlockID
+		parentHeight = header.Height
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lightclient
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+)
+
+const (
+	// maxConcurrentRequests is the number of GetHeaders messages that are
+	// served at once. Requests received while this many are being served are
+	// dropped.
+	maxConcurrentRequests = 4
+
+	// maxValidatorSetsPerRequest is the number of validator sets that headers
+	// are proven against while serving a single request. Proving against a
+	// validator set may require the P-chain to build its trie, so a request
+	// stops early once the headers were signed at too many P-chain heights.
+	maxValidatorSetsPerRequest = 4
+)
+
+var (
+	errUnknownChain         = errors.New("chain doesn't serve headers")
+	errNoValidatorSetProver = errors.New("no validator set prover was registered")
+	errDuplicateChain       = errors.New("chain was already registered")
+	errDuplicateProver      = errors.New("validator set prover was already registered")
+	errTooManyValidatorSets = errors.New("headers were signed at too many P-chain heights")
+
+	_ Server = &server{}
+)
+
+// Config describes how headers are served to light clients
+type Config struct {
+	// Enabled is true if headers are served to light clients
+	Enabled bool `json:"enabled"`
+
+	// MaxHeadersPerRequest is the maximum number of headers sent in response
+	// to a single GetHeaders message.
+	MaxHeadersPerRequest uint32 `json:"maxHeadersPerRequest"`
+
+	// HeaderRequestsPerSec is the number of GetHeaders messages each peer may
+	// send per second. Requests that exceed this rate are dropped.
+	HeaderRequestsPerSec float64 `json:"headerRequestsPerSec"`
+}
+
+// HeaderSource provides the accepted blocks of a chain. It's implemented by
+// the ProposerVM.
+type HeaderSource interface {
+	GetPostForkBlockAtHeight(height uint64) (block.Block, error)
+}
+
+// ValidatorSetProver proves the weights of validators. It's implemented by the
+// P-chain.
+type ValidatorSetProver interface {
+	GetValidatorSetProof(height uint64, subnetID ids.ID, nodeID ids.ShortID) (ids.ID, uint64, *merkledb.Proof, error)
+}
+
+type lockedValidatorSetProver struct {
+	lock   sync.Locker
+	prover ValidatorSetProver
+}
+
+func NewLockedValidatorSetProver(lock sync.Locker, prover ValidatorSetProver) ValidatorSetProver {
+	return &lockedValidatorSetProver{
+		lock:   lock,
+		prover: prover,
+	}
+}
+
+func (p *lockedValidatorSetProver) GetValidatorSetProof(height uint64, subnetID ids.ID, nodeID ids.ShortID) (ids.ID, uint64, *merkledb.Proof, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.prover.GetValidatorSetProof(height, subnetID, nodeID)
+}
+
+// Server serves the headers of the registered chains to light clients
+type Server interface {
+	// RegisterChain serves the headers of [chainID], which is validated by
+	// [subnetID], from [source].
+	RegisterChain(chainID ids.ID, subnetID ids.ID, source HeaderSource) error
+
+	// DeregisterChain stops serving the headers of [chainID].
+	DeregisterChain(chainID ids.ID)
+
+	// SetValidatorSetProver sets the prover of the validator sets that the
+	// headers are signed by.
+	SetValidatorSetProver(prover ValidatorSetProver) error
+
+	// GetHeaders returns the bytes of up to [numHeaders] sequential headers of
+	// [chainID], starting at [startHeight]. Fewer headers are returned if the
+	// chain hasn't accepted a post-fork block at one of the heights, or if the
+	// headers were signed at too many P-chain heights.
+	GetHeaders(chainID ids.ID, startHeight uint64, numHeaders uint32) ([][]byte, error)
+
+	// ServeHeaders calls GetHeaders on another goroutine and passes its
+	// results to [onHeaders]. Returns false, without calling [onHeaders], if
+	// too many requests are already being served.
+	ServeHeaders(chainID ids.ID, startHeight uint64, numHeaders uint32, onHeaders func([][]byte, error)) bool
+}
+
+type chain struct {
+	subnetID ids.ID
+	source   HeaderSource
+}
+
+type server struct {
+	log                  logging.Logger
+	maxHeadersPerRequest uint32
+
+	// Each request that is being served holds a slot
+	slots chan struct{}
+
+	lock   sync.RWMutex
+	chains map[ids.ID]chain
+	prover ValidatorSetProver
+}
+
+func NewServer(log logging.Logger, maxHeadersPerRequest uint32) Server {
+	return &server{
+		log:                  log,
+		maxHeadersPerRequest: maxHeadersPerRequest,
+		slots:                make(chan struct{}, maxConcurrentRequests),
+		chains:               make(map[ids.ID]chain),
+	}
+}
+
+func (s *server) RegisterChain(chainID ids.ID, subnetID ids.ID, source HeaderSource) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.chains[chainID]; exists {
+		return errDuplicateChain
+	}
+	s.chains[chainID] = chain{
+		subnetID: subnetID,
+		source:   source,
+	}
+	return nil
+}
+
+func (s *server) DeregisterChain(chainID ids.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.chains, chainID)
+}
+
+func (s *server) SetValidatorSetProver(prover ValidatorSetProver) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.prover != nil {
+		return errDuplicateProver
+	}
+	s.prover = prover
+	return nil
+}
+
+func (s *server) GetHeaders(chainID ids.ID, startHeight uint64, numHeaders uint32) ([][]byte, error) {
+	s.lock.RLock()
+	chain, ok := s.chains[chainID]
+	prover := s.prover
+	s.lock.RUnlock()
+
+	if !ok {
+		return nil, errUnknownChain
+	}
+	if prover == nil {
+		return nil, errNoValidatorSetProver
+	}
+
+	if numHeaders > s.maxHeadersPerRequest {
+		numHeaders = s.maxHeadersPerRequest
+	}
+	r := request{
+		chain:         chain,
+		prover:        prover,
+		proofs:        make(map[proofKey]*validatorSetProof),
+		pChainHeights: make(map[uint64]struct{}),
+	}
+	headers := make([][]byte, 0, numHeaders)
+	for i := uint64(0); i < uint64(numHeaders); i++ {
+		height := startHeight + i
+		headerBytes, err := r.getHeader(height)
+		if err != nil {
+			s.log.Debug("stopped serving headers of %s at height %d: %s", chainID, height, err)
+			break
+		}
+		headers = append(headers, headerBytes)
+	}
+	return headers, nil
+}
+
+func (s *server) ServeHeaders(chainID ids.ID, startHeight uint64, numHeaders uint32, onHeaders func([][]byte, error)) bool {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		return false
+	}
+
+	go func() {
+		defer func() { <-s.slots }()
+
+		onHeaders(s.GetHeaders(chainID, startHeight, numHeaders))
+	}()
+	return true
+}
+
+type proofKey struct {
+	pChainHeight uint64
+	nodeID       ids.ShortID
+}
+
+type validatorSetProof struct {
+	root   ids.ID
+	weight uint64
+	proof  *merkledb.Proof
+}
+
+// request holds the state of serving a single GetHeaders message
+type request struct {
+	chain  chain
+	prover ValidatorSetProver
+
+	// Consecutive headers are usually signed by the same proposers at the same
+	// P-chain height, so their proofs are only fetched once.
+	proofs map[proofKey]*validatorSetProof
+	// P-chain heights whose validator sets were proven against
+	pChainHeights map[uint64]struct{}
+}
+
+func (r *request) getHeader(height uint64) ([]byte, error) {
+	blk, err := r.chain.source.GetPostForkBlockAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	header := Header{
+		BlockID:  blk.ID(),
+		ParentID: blk.ParentID(),
+		Height:   height,
+	}
+	signedBlk, ok := blk.(block.SignedBlock)
+	if !ok {
+		// Options are never signed
+		return header.Bytes()
+	}
+	header.Timestamp = signedBlk.Timestamp().Unix()
+	header.PChainHeight = signedBlk.PChainHeight()
+	if signedBlk.Proposer() == ids.ShortEmpty {
+		return header.Bytes()
+	}
+
+	proof, err := r.getProof(signedBlk.PChainHeight(), signedBlk.Proposer())
+	if err != nil {
+		return nil, err
+	}
+	header.Certificate = signedBlk.Certificate()
+	header.Signature = signedBlk.SignatureBytes()
+	header.ValidatorSetRoot = proof.root
+	header.ProposerWeight = proof.weight
+	header.ProposerProof = *proof.proof
+	return header.Bytes()
+}
+
+func (r *request) getProof(pChainHeight uint64, nodeID ids.ShortID) (*validatorSetProof, error) {
+	key := proofKey{
+		pChainHeight: pChainHeight,
+		nodeID:       nodeID,
+	}
+	if proof, ok := r.proofs[key]; ok {
+		return proof, nil
+	}
+
+	if _, ok := r.pChainHeights[pChainHeight]; !ok && len(r.pChainHeights) >= maxValidatorSetsPerRequest {
+		return nil, errTooManyValidatorSets
+	}
+	r.pChainHeights[pChainHeight] = struct{}{}
+
+	root, weight, proof, err := r.prover.GetValidatorSetProof(pChainHeight, r.chain.subnetID, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	validatorSetProof := &validatorSetProof{
+		root:   root,
+		weight: weight,
+		proof:  proof,
+	}
+	r.proofs[key] = validatorSetProof
+	return validatorSetProof, nil
+}
@@ -0,0 +1,399 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lightclient
+
+import (
+	"crypto"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+)
+
+type testHeaderSource map[uint64]block.Block
+
+func (s testHeaderSource) GetPostForkBlockAtHeight(height uint64) (block.Block, error) {
+	blk, ok := s[height]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return blk, nil
+}
+
+type testValidatorSetProver struct {
+	trie merkledb.Trie
+	// Number of proofs that were requested
+	calls int
+}
+
+func (p *testValidatorSetProver) GetValidatorSetProof(_ uint64, _ ids.ID, nodeID ids.ShortID) (ids.ID, uint64, *merkledb.Proof, error) {
+	p.calls++
+	proof, err := p.trie.GetProof(nodeID[:])
+	if err != nil {
+		return ids.Empty, 0, nil, err
+	}
+	var weight uint64
+	if proof.HasValue {
+		weight, err = database.ParseUInt64(proof.Value)
+		if err != nil {
+			return ids.Empty, 0, nil, err
+		}
+	}
+	return p.trie.GetMerkleRoot(), weight, proof, nil
+}
+
+// newTestChain returns a source of [numBlocks] blocks built on [genesisID]
+// with heights starting at 1, signed by the validator with [proposerWeight] at
+// P-chain height 10, and a prover of the validator set containing that
+// validator.
+func newTestChain(t *testing.T, chainID ids.ID, genesisID ids.ID, numBlocks int, proposerWeight uint64) (testHeaderSource, *testValidatorSetProver) {
+	return newTestChainWithPChainHeights(t, chainID, genesisID, numBlocks, proposerWeight, func(uint64) uint64 { return 10 })
+}
+
+// newTestChainWithPChainHeights is newTestChain, except that the block at each
+// height is signed at the P-chain height returned by [pChainHeight].
+func newTestChainWithPChainHeights(
+	t *testing.T,
+	chainID ids.ID,
+	genesisID ids.ID,
+	numBlocks int,
+	proposerWeight uint64,
+	pChainHeight func(height uint64) uint64,
+) (testHeaderSource, *testValidatorSetProver) {
+	assert := assert.New(t)
+
+	tlsCert, err := staking.NewTLSCert()
+	assert.NoError(err)
+	cert := tlsCert.Leaf
+	key := tlsCert.PrivateKey.(crypto.Signer)
+	proposer := hashing.ComputeHash160Array(hashing.ComputeHash256(cert.Raw))
+
+	trie, err := merkledb.New(memdb.New())
+	assert.NoError(err)
+	if proposerWeight > 0 {
+		assert.NoError(trie.Put(proposer[:], database.PackUInt64(proposerWeight)))
+	}
+	otherValidator := ids.GenerateTestShortID()
+	assert.NoError(trie.Put(otherValidator[:], database.PackUInt64(1)))
+
+	source := make(testHeaderSource)
+	parentID := genesisID
+	for height := uint64(1); height <= uint64(numBlocks); height++ {
+		blk, err := block.Build(
+			parentID,
+			time.Unix(int64(height), 0),
+			pChainHeight(height),
+			cert,
+			[]byte{byte(height)},
+			chainID,
+			key,
+		)
+		assert.NoError(err)
+		source[height] = blk
+		parentID = blk.ID()
+	}
+	return source, &testValidatorSetProver{trie: trie}
+}
+
+func parseHeaders(t *testing.T, headersBytes [][]byte) []*Header {
+	headers := make([]*Header, len(headersBytes))
+	for i, headerBytes := range headersBytes {
+		header, err := ParseHeader(headerBytes)
+		assert.NoError(t, err)
+		headers[i] = header
+	}
+	return headers
+}
+
+func TestServerGetHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	genesisID := ids.GenerateTestID()
+	source, prover := newTestChain(t, chainID, genesisID, 10, 5)
+
+	s := NewServer(logging.NoLog{}, 4)
+	_, err := s.GetHeaders(chainID, 1, 4)
+	assert.ErrorIs(err, errUnknownChain)
+
+	assert.NoError(s.RegisterChain(chainID, subnetID, source))
+	assert.ErrorIs(s.RegisterChain(chainID, subnetID, source), errDuplicateChain)
+	s.DeregisterChain(chainID)
+	_, err = s.GetHeaders(chainID, 1, 4)
+	assert.ErrorIs(err, errUnknownChain)
+	assert.NoError(s.RegisterChain(chainID, subnetID, source))
+	_, err = s.GetHeaders(chainID, 1, 4)
+	assert.ErrorIs(err, errNoValidatorSetProver)
+
+	assert.NoError(s.SetValidatorSetProver(prover))
+	assert.ErrorIs(s.SetValidatorSetProver(prover), errDuplicateProver)
+
+	// Requests are capped at the maximum number of headers
+	headersBytes, err := s.GetHeaders(chainID, 1, 100)
+	assert.NoError(err)
+	assert.Len(headersBytes, 4)
+	headers := parseHeaders(t, headersBytes)
+	assert.NoError(VerifyChain(chainID, genesisID, 0, headers))
+	for i, header := range headers {
+		blk := source[uint64(i+1)].(block.SignedBlock)
+		assert.Equal(blk.ID(), header.BlockID)
+		assert.Equal(uint64(i+1), header.Height)
+		assert.Equal(blk.PChainHeight(), header.PChainHeight)
+		assert.Equal(blk.Timestamp().Unix(), header.Timestamp)
+		assert.Equal(uint64(5), header.ProposerWeight)
+	}
+
+	// The headers can be followed from the last header of the previous
+	// request
+	last := headers[len(headers)-1]
+	headersBytes, err = s.GetHeaders(chainID, last.Height+1, 4)
+	assert.NoError(err)
+	assert.NoError(VerifyChain(chainID, last.BlockID, last.Height, parseHeaders(t, headersBytes)))
+
+	// Headers stop at the last accepted height
+	headersBytes, err = s.GetHeaders(chainID, 9, 4)
+	assert.NoError(err)
+	assert.Len(headersBytes, 2)
+	headersBytes, err = s.GetHeaders(chainID, 11, 4)
+	assert.NoError(err)
+	assert.Empty(headersBytes)
+
+	// Headers must be sequential
+	headersBytes, err = s.GetHeaders(chainID, 1, 3)
+	assert.NoError(err)
+	headers = parseHeaders(t, headersBytes)
+	assert.ErrorIs(VerifyChain(chainID, genesisID, 0, []*Header{headers[0], headers[2]}), errUnexpectedParent)
+	assert.ErrorIs(VerifyChain(chainID, ids.GenerateTestID(), 0, headers), errUnexpectedParent)
+	assert.ErrorIs(VerifyChain(chainID, genesisID, 1, headers), errNonSequentialHeight)
+}
+
+func TestHeaderVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	source, prover := newTestChain(t, chainID, ids.GenerateTestID(), 1, 5)
+
+	s := NewServer(logging.NoLog{}, 1)
+	assert.NoError(s.RegisterChain(chainID, ids.GenerateTestID(), source))
+	assert.NoError(s.SetValidatorSetProver(prover))
+
+	headersBytes, err := s.GetHeaders(chainID, 1, 1)
+	assert.NoError(err)
+	assert.Len(headersBytes, 1)
+
+	header, err := ParseHeader(headersBytes[0])
+	assert.NoError(err)
+	assert.NoError(header.Verify(chainID))
+
+	proposer, err := header.Proposer()
+	assert.NoError(err)
+	assert.Equal(source[1].(block.SignedBlock).Proposer(), proposer)
+
+	// Signatures are only valid for the chain they were made for
+	assert.Error(header.Verify(ids.GenerateTestID()))
+
+	// The signature covers the block ID
+	tampered := *header
+	tampered.BlockID = ids.GenerateTestID()
+	assert.Error(tampered.Verify(chainID))
+
+	// The proposer's weight must match the proof
+	tampered = *header
+	tampered.ProposerWeight++
+	assert.ErrorIs(tampered.Verify(chainID), errWrongProofWeight)
+
+	// The proof must be against the validator set root
+	tampered = *header
+	tampered.ValidatorSetRoot = ids.GenerateTestID()
+	assert.Error(tampered.Verify(chainID))
+
+	// Unsigned headers can't claim a proof
+	tampered = *header
+	tampered.Certificate = nil
+	assert.ErrorIs(tampered.Verify(chainID), errUnexpectedProof)
+}
+
+func TestServerGetHeadersAcrossUnsignedBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	genesisID := ids.GenerateTestID()
+	tlsCert, err := staking.NewTLSCert()
+	assert.NoError(err)
+	proposer := hashing.ComputeHash160Array(hashing.ComputeHash256(tlsCert.Leaf.Raw))
+	trie, err := merkledb.New(memdb.New())
+	assert.NoError(err)
+	assert.NoError(trie.Put(proposer[:], database.PackUInt64(5)))
+	prover := &testValidatorSetProver{trie: trie}
+
+	// The first post-fork block isn't signed
+	forkBlk, err := block.BuildUnsigned(genesisID, time.Unix(1, 0), 10, []byte{1})
+	assert.NoError(err)
+	signedBlk, err := block.Build(
+		forkBlk.ID(),
+		time.Unix(2, 0),
+		10,
+		tlsCert.Leaf,
+		[]byte{2},
+		chainID,
+		tlsCert.PrivateKey.(crypto.Signer),
+	)
+	assert.NoError(err)
+	// Options are never signed
+	option, err := block.BuildOption(signedBlk.ID(), []byte{3})
+	assert.NoError(err)
+	// Blocks issued after every proposer window passed aren't signed
+	lateBlk, err := block.BuildUnsigned(option.ID(), time.Unix(4, 0), 10, []byte{4})
+	assert.NoError(err)
+	source := testHeaderSource{
+		1: forkBlk,
+		2: signedBlk,
+		3: option,
+		4: lateBlk,
+	}
+
+	s := NewServer(logging.NoLog{}, 4)
+	assert.NoError(s.RegisterChain(chainID, ids.GenerateTestID(), source))
+	assert.NoError(s.SetValidatorSetProver(prover))
+
+	headersBytes, err := s.GetHeaders(chainID, 1, 4)
+	assert.NoError(err)
+	assert.Len(headersBytes, 4)
+	headers := parseHeaders(t, headersBytes)
+	assert.NoError(VerifyChain(chainID, genesisID, 0, headers))
+	assert.Equal([]bool{false, true, false, false}, []bool{
+		headers[0].Signed(),
+		headers[1].Signed(),
+		headers[2].Signed(),
+		headers[3].Signed(),
+	})
+	assert.Equal(forkBlk.PChainHeight(), headers[0].PChainHeight)
+	assert.Equal(lateBlk.Timestamp().Unix(), headers[3].Timestamp)
+	assert.Equal(1, prover.calls)
+
+	// Unsigned headers are still linked to their parents
+	assert.ErrorIs(VerifyChain(chainID, genesisID, 0, headers[1:]), errUnexpectedParent)
+	assert.ErrorIs(VerifyChain(chainID, genesisID, 0, []*Header{headers[0], headers[2]}), errUnexpectedParent)
+
+	// Unsigned headers can't claim a proposer's weight
+	tampered := *headers[0]
+	tampered.ProposerWeight = 5
+	assert.ErrorIs(tampered.Verify(chainID), errUnexpectedProof)
+	tampered = *headers[0]
+	tampered.ValidatorSetRoot = headers[1].ValidatorSetRoot
+	assert.ErrorIs(tampered.Verify(chainID), errUnexpectedProof)
+}
+
+func TestHeaderVerifyNonValidatorProposer(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	source, prover := newTestChain(t, chainID, ids.GenerateTestID(), 1, 0)
+
+	s := NewServer(logging.NoLog{}, 1)
+	assert.NoError(s.RegisterChain(chainID, ids.GenerateTestID(), source))
+	assert.NoError(s.SetValidatorSetProver(prover))
+
+	headersBytes, err := s.GetHeaders(chainID, 1, 1)
+	assert.NoError(err)
+	assert.Len(headersBytes, 1)
+
+	header, err := ParseHeader(headersBytes[0])
+	assert.NoError(err)
+	assert.ErrorIs(header.Verify(chainID), errZeroProposerWeight)
+}
+
+func TestServerProvesEachValidatorSetOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	genesisID := ids.GenerateTestID()
+	source, prover := newTestChain(t, chainID, genesisID, 4, 5)
+
+	s := NewServer(logging.NoLog{}, 4)
+	assert.NoError(s.RegisterChain(chainID, ids.GenerateTestID(), source))
+	assert.NoError(s.SetValidatorSetProver(prover))
+
+	// Every header is signed by the same proposer at the same P-chain height
+	headersBytes, err := s.GetHeaders(chainID, 1, 4)
+	assert.NoError(err)
+	assert.Len(headersBytes, 4)
+	assert.NoError(VerifyChain(chainID, genesisID, 0, parseHeaders(t, headersBytes)))
+	assert.Equal(1, prover.calls)
+}
+
+func TestServerCapsValidatorSetsPerRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	genesisID := ids.GenerateTestID()
+	// Every block is signed at a different P-chain height
+	source, prover := newTestChainWithPChainHeights(t, chainID, genesisID, 10, 5, func(height uint64) uint64 { return height })
+
+	s := NewServer(logging.NoLog{}, 10)
+	assert.NoError(s.RegisterChain(chainID, ids.GenerateTestID(), source))
+	assert.NoError(s.SetValidatorSetProver(prover))
+
+	headersBytes, err := s.GetHeaders(chainID, 1, 10)
+	assert.NoError(err)
+	assert.Len(headersBytes, maxValidatorSetsPerRequest)
+	assert.Equal(maxValidatorSetsPerRequest, prover.calls)
+
+	// The rest of the headers can be requested afterwards
+	headers := parseHeaders(t, headersBytes)
+	last := headers[len(headers)-1]
+	headersBytes, err = s.GetHeaders(chainID, last.Height+1, 10)
+	assert.NoError(err)
+	assert.NoError(VerifyChain(chainID, last.BlockID, last.Height, parseHeaders(t, headersBytes)))
+}
+
+func TestServerServeHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	genesisID := ids.GenerateTestID()
+	source, prover := newTestChain(t, chainID, genesisID, 4, 5)
+
+	s := NewServer(logging.NoLog{}, 4)
+	assert.NoError(s.RegisterChain(chainID, ids.GenerateTestID(), source))
+	// Requests are served concurrently, so the prover is locked as it is by
+	// the node
+	assert.NoError(s.SetValidatorSetProver(NewLockedValidatorSetProver(&sync.Mutex{}, prover)))
+
+	served := make(chan [][]byte, 1)
+	assert.True(s.ServeHeaders(chainID, 1, 4, func(headers [][]byte, err error) {
+		assert.NoError(err)
+		served <- headers
+	}))
+	headersBytes := <-served
+	assert.Len(headersBytes, 4)
+	assert.NoError(VerifyChain(chainID, genesisID, 0, parseHeaders(t, headersBytes)))
+
+	// Requests are dropped while too many are being served
+	blocked := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < maxConcurrentRequests; i++ {
+		wg.Add(1)
+		assert.True(s.ServeHeaders(chainID, 1, 4, func([][]byte, error) {
+			defer wg.Done()
+			<-blocked
+		}))
+	}
+	assert.False(s.ServeHeaders(chainID, 1, 4, func([][]byte, error) {
+		t.Fatal("should have dropped the request")
+	}))
+	close(blocked)
+	wg.Wait()
+}
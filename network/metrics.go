@@ -4,6 +4,8 @@
 package network
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -18,6 +20,7 @@ type metrics struct {
 	numSubnetPeers            *prometheus.GaugeVec
 	timeSinceLastMsgSent      prometheus.Gauge
 	timeSinceLastMsgReceived  prometheus.Gauge
+	peerTimeSinceLastReceived *prometheus.GaugeVec
 	sendQueuePortionFull      prometheus.Gauge
 	sendFailRate              prometheus.Gauge
 	connected                 prometheus.Counter
@@ -26,6 +29,11 @@ type metrics struct {
 	inboundConnAllowed        prometheus.Counter
 	nodeUptimeWeightedAverage prometheus.Gauge
 	nodeUptimeRewardingStake  prometheus.Gauge
+	duplicateIdentity         prometheus.Counter
+	staleIPsRejected          prometheus.Counter
+	futureIPsRejected         prometheus.Counter
+	connsBanned               prometheus.Counter
+	connsNotAllowlisted       prometheus.Counter
 }
 
 func newMetrics(namespace string, registerer prometheus.Registerer, initialSubnetIDs ids.Set) (*metrics, error) {
@@ -53,6 +61,14 @@ func newMetrics(namespace string, registerer prometheus.Registerer, initialSubne
 			Name:      "time_since_last_msg_received",
 			Help:      "Time (in ns) since the last msg was received",
 		}),
+		peerTimeSinceLastReceived: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "peer_time_since_last_msg_received",
+				Help:      "Time (in ns) since the last msg was received from a connected peer",
+			},
+			[]string{"nodeID"},
+		),
 		timeSinceLastMsgSent: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "time_since_last_msg_sent",
@@ -98,6 +114,31 @@ func newMetrics(namespace string, registerer prometheus.Registerer, initialSubne
 			Name:      "node_uptime_rewarding_stake",
 			Help:      "The percentage of total stake which thinks this node is eligible for rewards",
 		}),
+		duplicateIdentity: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "duplicate_identity_detected",
+			Help:      "Times this node observed an IP signed with its own staking key that it didn't sign",
+		}),
+		staleIPsRejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stale_ips_rejected",
+			Help:      "Times this node rejected a gossiped IP that was older than the latest IP signed by the same node",
+		}),
+		futureIPsRejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "future_ips_rejected",
+			Help:      "Times this node rejected a gossiped IP that was signed too far in the future",
+		}),
+		connsBanned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "conns_banned",
+			Help:      "Times this node refused to dial, or dropped a connection with, a banned peer",
+		}),
+		connsNotAllowlisted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "conns_not_allowlisted",
+			Help:      "Times this node refused the TLS handshake with a node that isn't allowlisted",
+		}),
 	}
 
 	errs := wrappers.Errs{}
@@ -106,6 +147,7 @@ func newMetrics(namespace string, registerer prometheus.Registerer, initialSubne
 		registerer.Register(m.numPeers),
 		registerer.Register(m.numSubnetPeers),
 		registerer.Register(m.timeSinceLastMsgReceived),
+		registerer.Register(m.peerTimeSinceLastReceived),
 		registerer.Register(m.timeSinceLastMsgSent),
 		registerer.Register(m.sendQueuePortionFull),
 		registerer.Register(m.sendFailRate),
@@ -115,6 +157,11 @@ func newMetrics(namespace string, registerer prometheus.Registerer, initialSubne
 		registerer.Register(m.inboundConnRateLimited),
 		registerer.Register(m.nodeUptimeWeightedAverage),
 		registerer.Register(m.nodeUptimeRewardingStake),
+		registerer.Register(m.duplicateIdentity),
+		registerer.Register(m.staleIPsRejected),
+		registerer.Register(m.futureIPsRejected),
+		registerer.Register(m.connsBanned),
+		registerer.Register(m.connsNotAllowlisted),
 	)
 
 	// init subnet tracker metrics with whitelisted subnets
@@ -146,6 +193,7 @@ func (m *metrics) markConnected(peer peer.Peer) {
 func (m *metrics) markDisconnected(peer peer.Peer) {
 	m.numPeers.Dec()
 	m.disconnected.Inc()
+	m.peerTimeSinceLastReceived.DeleteLabelValues(peer.ID().PrefixedString(constants.NodeIDPrefix))
 
 	trackedSubnets := peer.TrackedSubnets()
 	for subnetID := range trackedSubnets {
@@ -156,3 +204,8 @@ func (m *metrics) markDisconnected(peer peer.Peer) {
 		m.numSubnetPeers.WithLabelValues(subnetID.String()).Dec()
 	}
 }
+
+func (m *metrics) markLastReceived(peer peer.Peer, now time.Time) {
+	timeSinceLastReceived := now.Sub(peer.LastReceived())
+	m.peerTimeSinceLastReceived.WithLabelValues(peer.ID().PrefixedString(constants.NodeIDPrefix)).Set(float64(timeSinceLastReceived))
+}
@@ -5,6 +5,7 @@ package network
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
@@ -30,6 +31,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 )
@@ -39,12 +41,15 @@ const (
 	TimeSinceLastMsgReceivedKey = "timeSinceLastMsgReceived"
 	TimeSinceLastMsgSentKey     = "timeSinceLastMsgSent"
 	SendFailRateKey             = "sendFailRate"
+	DuplicateIdentityIPKey      = "duplicateIdentityIP"
 )
 
 var (
 	_                      sender.ExternalSender = &network{}
 	_                      Network               = &network{}
 	errNoPrimaryValidators                       = errors.New("no default subnet validators")
+	errNonPositiveBan                            = errors.New("ban duration must be positive")
+	errNotAllowlisted                            = errors.New("node isn't allowlisted")
 )
 
 // Network defines the functionality of the networking library.
@@ -81,6 +86,18 @@ type Network interface {
 	// info about the peers in [nodeIDs] that have finished the handshake.
 	PeerInfo(nodeIDs []ids.ShortID) []peer.Info
 
+	// Ban disconnects from the peers matching [target] and refuses any
+	// connection with them for [duration], including after a restart. Banning
+	// a target again replaces its previous ban.
+	Ban(target BanTarget, duration time.Duration) error
+
+	// Unban lifts the ban of [target]. Returns false if [target] wasn't
+	// banned.
+	Unban(target BanTarget) (bool, error)
+
+	// Bans returns the bans in effect, sorted by expiry
+	Bans() []Ban
+
 	NodeUptime() (UptimeResult, bool)
 }
 
@@ -96,6 +113,10 @@ type network struct {
 	// Signs my IP so I can send my signed IP address to other nodes in Version
 	// messages
 	ipSigner *ipSigner
+	// Features this node advertises in Version messages
+	myFeatures version.FeatureBits
+	// Activation times of the network upgrades
+	upgrades *version.UpgradeSchedule
 
 	// Limits the number of connection attempts based on IP.
 	inboundConnUpgradeThrottler throttling.InboundConnUpgradeThrottler
@@ -117,16 +138,37 @@ type network struct {
 
 	sendFailRateCalculator math.Averager
 
+	// duplicateIdentityIP is set to the most recently observed IP that was
+	// signed with this node's staking key, but wasn't signed by this node. If
+	// set, another node is running with this node's identity.
+	duplicateIdentityIP utils.AtomicInterface
+
 	peersLock sync.RWMutex
 	// trackedIPs contains the set of IPs that we are currently attempting to
 	// connect to. An entry is added to this set when we first start attempting
 	// to connect to the peer. An entry is deleted from this set once we have
 	// finished the handshake.
-	trackedIPs      map[ids.ShortID]*trackedIP
+	trackedIPs map[ids.ShortID]*trackedIP
+	// latestIPs contains the most recent signed IP that was verified for each
+	// node. Signed IPs that are older are stale and are rejected, so that a
+	// node's old signatures can't be replayed to redirect connections to it.
+	latestIPs       map[ids.ShortID]peer.UnsignedIP
 	connectingPeers peer.Set
 	connectedPeers  peer.Set
 	closing         bool
 
+	// peerStore persists the IPs of the peers this node connects to, to dial
+	// them when the node restarts. It's nil if persistence is disabled.
+	peerStore *peerStore
+
+	// bans are the peers the operator banned. They're never dialed and their
+	// connections are refused.
+	bans *banList
+
+	// validatorSnapshots are the validator sets that gossip samples peers
+	// from
+	validatorSnapshots *validatorSnapshots
+
 	// router is notified about all peer [Connected] and [Disconnected] events
 	// as well as all non-handshake peer messages.
 	//
@@ -191,9 +233,20 @@ func NewNetwork(
 		return nil, fmt.Errorf("initializing network metrics failed with: %w", err)
 	}
 
+	if config.PeerPolicy == nil {
+		config.PeerPolicy = version.NewDefaultPeerPolicy()
+	}
+	if config.Clock == nil {
+		config.Clock = &mockable.Clock{}
+	}
+	if config.Upgrades == nil {
+		config.Upgrades = version.GetUpgradeSchedule(config.NetworkID)
+	}
+
 	peerConfig := &peer.Config{
 		ReadBufferSize:       config.PeerReadBufferSize,
 		WriteBufferSize:      config.PeerWriteBufferSize,
+		Clock:                config.Clock,
 		Metrics:              peerMetrics,
 		MessageCreator:       msgCreator,
 		Log:                  log,
@@ -203,25 +256,35 @@ func NewNetwork(
 		Router:               router,
 		VersionCompatibility: version.GetCompatibility(config.NetworkID),
 		VersionParser:        version.NewDefaultApplicationParser(),
+		PeerPolicy:           config.PeerPolicy,
 		MySubnets:            config.WhitelistedSubnets,
 		Beacons:              config.Beacons,
 		NetworkID:            config.NetworkID,
 		PingFrequency:        config.PingFrequency,
 		PongTimeout:          config.PingPongTimeout,
+		DeadPeerTimeout:      config.DeadPeerTimeout,
 		MaxClockDifference:   config.MaxClockDifference,
+		LightClient:          config.LightClientServer,
+		HeaderRequestsPerSec: config.LightClientConfig.HeaderRequestsPerSec,
+		PeerTime:             config.PeerTime,
 	}
+	bans, err := newBanList(config.BanDB, peerConfig.Clock.Time())
+	if err != nil {
+		return nil, fmt.Errorf("loading the peer bans failed with: %w", err)
+	}
+
 	onCloseCtx, cancel := context.WithCancel(context.Background())
 	n := &network{
 		config:     config,
 		peerConfig: peerConfig,
 		metrics:    metrics,
-		ipSigner:   newIPSigner(&config.MyIP, &peerConfig.Clock, config.TLSKey),
+		ipSigner:   newIPSigner(&config.MyIP, config.Clock, config.TLSKey),
+		myFeatures: myFeatures(config),
+		upgrades:   config.Upgrades,
 
 		inboundConnUpgradeThrottler: throttling.NewInboundConnUpgradeThrottler(log, config.ThrottlerConfig.InboundConnUpgradeThrottlerConfig),
 		listener:                    listener,
 		dialer:                      dialer,
-		serverUpgrader:              peer.NewTLSServerUpgrader(config.TLSConfig),
-		clientUpgrader:              peer.NewTLSClientUpgrader(config.TLSConfig),
 
 		onCloseCtx:       onCloseCtx,
 		onCloseCtxCancel: cancel,
@@ -229,20 +292,34 @@ func NewNetwork(
 		sendFailRateCalculator: math.NewSyncAverager(math.NewAverager(
 			0,
 			config.SendFailRateHalflife,
-			time.Now(),
+			config.Clock.Time(),
 		)),
 
 		trackedIPs:      make(map[ids.ShortID]*trackedIP),
+		latestIPs:       make(map[ids.ShortID]peer.UnsignedIP),
 		connectingPeers: peer.NewSet(),
 		connectedPeers:  peer.NewSet(),
+		bans:            bans,
 		router:          router,
 	}
+	n.validatorSnapshots = newValidatorSnapshots(config.Validators, config.ValidatorSnapshotEpoch, config.Clock)
+
+	tlsConfig := config.TLSConfig
+	if config.AllowlistConfig.Enabled {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.VerifyPeerCertificate = n.verifyAllowlisted
+	}
+	n.serverUpgrader = peer.NewTLSServerUpgrader(tlsConfig)
+	n.clientUpgrader = peer.NewTLSClientUpgrader(tlsConfig)
 	n.peerConfig.Network = n
+	if config.PeerDB != nil && config.MaxPersistedPeers > 0 {
+		n.peerStore = newPeerStore(config.PeerDB, config.MaxPersistedPeers)
+	}
 	return n, nil
 }
 
 func (n *network) Send(msg message.OutboundMessage, nodeIDs ids.ShortSet, subnetID ids.ID, validatorOnly bool) ids.ShortSet {
-	peers := n.getPeers(nodeIDs, subnetID, validatorOnly)
+	peers := n.getPeers(nodeIDs, subnetID, validatorOnly, n.requiredFeatures(msg.Op()))
 	n.peerConfig.Metrics.MultipleSendsFailed(
 		msg.Op(),
 		nodeIDs.Len()-len(peers),
@@ -258,7 +335,7 @@ func (n *network) Gossip(
 	numNonValidatorsToSend int,
 	numPeersToSend int,
 ) ids.ShortSet {
-	peers := n.samplePeers(subnetID, validatorOnly, numValidatorsToSend, numNonValidatorsToSend, numPeersToSend)
+	peers := n.samplePeers(subnetID, validatorOnly, n.requiredFeatures(msg.Op()), numValidatorsToSend, numNonValidatorsToSend, numPeersToSend)
 	return n.send(msg, peers)
 }
 
@@ -303,6 +380,13 @@ func (n *network) HealthCheck() (interface{}, error) {
 	details[SendFailRateKey] = sendFailRate
 	n.metrics.sendFailRate.Set(sendFailRate)
 
+	// Make sure no other node is using our identity
+	duplicateIP, hasDuplicateIdentity := n.duplicateIdentityIP.GetValue().(utils.IPDesc)
+	healthy = healthy && !hasDuplicateIdentity
+	if hasDuplicateIdentity {
+		details[DuplicateIdentityIPKey] = duplicateIP.String()
+	}
+
 	// Network layer is unhealthy
 	if !healthy {
 		var errorReasons []string
@@ -318,6 +402,9 @@ func (n *network) HealthCheck() (interface{}, error) {
 		if !isMsgFailRate {
 			errorReasons = append(errorReasons, fmt.Sprintf("messages failure send rate %g > %g", sendFailRate, n.config.HealthConfig.MaxSendFailRate))
 		}
+		if hasDuplicateIdentity {
+			errorReasons = append(errorReasons, fmt.Sprintf("another node at %s is using this node's staking key", duplicateIP))
+		}
 
 		return details, fmt.Errorf("network layer is unhealthy reason: %s", strings.Join(errorReasons, ", "))
 	}
@@ -345,10 +432,22 @@ func (n *network) Connected(nodeID ids.ShortID) {
 	}
 	n.connectingPeers.Remove(nodeID)
 	n.connectedPeers.Add(peer)
+	n.updateLatestIP(nodeID, peer.IP().IP)
 	n.peersLock.Unlock()
 
 	n.metrics.markConnected(peer)
 
+	if n.peerStore != nil && n.WantsConnection(nodeID) {
+		err := n.peerStore.connected(nodeID, peer.IP().IP.IP, n.peerConfig.Clock.Unix())
+		if err != nil {
+			n.peerConfig.Log.Warn(
+				"failed to persist the connection to %s%s: %s",
+				constants.NodeIDPrefix, nodeID,
+				err,
+			)
+		}
+	}
+
 	peerVersion := peer.Version()
 	n.router.Connected(nodeID, peerVersion)
 }
@@ -365,6 +464,22 @@ func (n *network) AllowConnection(nodeID ids.ShortID) bool {
 
 func (n *network) Track(ip utils.IPCertDesc) {
 	nodeID := peer.CertToID(ip.Cert)
+	if nodeID == n.config.MyNodeID {
+		n.checkDuplicateIdentity(ip)
+		return
+	}
+
+	// IPs signed too far in the future would prevent any later IP of the node
+	// from being tracked.
+	maxTimestamp := n.peerConfig.Clock.Unix() + uint64(n.peerConfig.MaxClockDifference.Seconds())
+	if ip.Time > maxTimestamp {
+		n.metrics.futureIPsRejected.Inc()
+		n.peerConfig.Log.Debug("dropping IP of %s%s signed at %d, which is too far in the future",
+			constants.NodeIDPrefix, nodeID,
+			ip.Time,
+		)
+		return
+	}
 
 	// Verify that we do want to attempt to make a connection to this peer
 	// before verifying that the IP has been correctly signed.
@@ -402,6 +517,15 @@ func (n *network) Track(ip utils.IPCertDesc) {
 		return
 	}
 
+	// The latest IP may have been updated since [shouldTrack] was called
+	if n.isStaleIP(nodeID, ip) {
+		return
+	}
+	n.updateLatestIP(nodeID, peer.UnsignedIP{
+		IP:        ip.IPDesc,
+		Timestamp: ip.Time,
+	})
+
 	tracked, isTracked := n.trackedIPs[nodeID]
 	if isTracked {
 		if tracked.ip.Timestamp < ip.Time {
@@ -423,6 +547,44 @@ func (n *network) Track(ip utils.IPCertDesc) {
 	}
 }
 
+// checkDuplicateIdentity is called with IPs that were gossiped to this node
+// claiming to be this node's IP. If the IP was correctly signed with this
+// node's staking key but not by this node, then another node must be running
+// with this node's staking key.
+func (n *network) checkDuplicateIdentity(ip utils.IPCertDesc) {
+	mySignedIP, err := n.ipSigner.getSignedIP()
+	if err != nil {
+		return
+	}
+
+	// Our own IP being gossiped back to us, or an IP we signed in the past, is
+	// expected.
+	if ip.Time < mySignedIP.IP.Timestamp ||
+		(ip.Time == mySignedIP.IP.Timestamp && ip.IPDesc.Equal(mySignedIP.IP.IP)) {
+		return
+	}
+
+	signedIP := peer.SignedIP{
+		IP: peer.UnsignedIP{
+			IP:        ip.IPDesc,
+			Timestamp: ip.Time,
+		},
+		Signature: ip.Signature,
+	}
+	if err := signedIP.Verify(ip.Cert); err != nil {
+		n.peerConfig.Log.Debug("signature verification failed for our own IP claim: %s", err)
+		return
+	}
+
+	n.duplicateIdentityIP.SetValue(ip.IPDesc)
+	n.metrics.duplicateIdentity.Inc()
+	n.peerConfig.Log.Error(
+		"detected another node at %s using this node's identity %s%s. The staking key may have been copied",
+		ip.IPDesc,
+		constants.NodeIDPrefix, n.config.MyNodeID,
+	)
+}
+
 // Disconnected is called after the peer's handling has been shutdown.
 // It is not guaranteed that [Connected] was previously called with [nodeID].
 // It is guaranteed that [Connected] will not be called with [nodeID] after this
@@ -455,9 +617,34 @@ func (n *network) Version() (message.OutboundMessage, error) {
 		mySignedIP.IP.Timestamp,
 		mySignedIP.Signature,
 		n.peerConfig.MySubnets.List(),
+		n.advertisedFeatures(),
 	)
 }
 
+// advertisedFeatures returns the features to include in the Version message,
+// or nil if they can't be advertised yet. Until apricot phase 6, peers may be
+// running a version that rejects Version messages including features.
+func (n *network) advertisedFeatures() *version.FeatureBits {
+	if !n.upgrades.IsActivated(version.ApricotPhase6, n.peerConfig.Clock.Time()) {
+		return nil
+	}
+	myFeatures := n.myFeatures
+	return &myFeatures
+}
+
+// myFeatures returns the features a node running with [config] supports
+func myFeatures(config *Config) version.FeatureBits {
+	features := []version.Feature{
+		version.CompressionFeature,
+		version.AppMessagesFeature,
+		version.BatchedQueriesFeature,
+	}
+	if config.LightClientServer != nil {
+		features = append(features, version.HeaderSyncFeature)
+	}
+	return version.NewFeatureBits(features...)
+}
+
 func (n *network) Peers() (message.OutboundMessage, error) {
 	peers := n.sampleValidatorIPs()
 	return n.peerConfig.MessageCreator.PeerList(peers, true)
@@ -478,6 +665,7 @@ func (n *network) Pong(nodeID ids.ShortID) (message.OutboundMessage, error) {
 func (n *network) Dispatch() error {
 	go n.runTimers() // Periodically perform operations
 	go n.inboundConnUpgradeThrottler.Dispatch()
+	n.dialPersistedPeers()
 	errs := wrappers.Errs{}
 	for { // Continuously accept new connections
 		conn, err := n.listener.Accept() // Returns error when n.Close() is called
@@ -507,6 +695,13 @@ func (n *network) Dispatch() error {
 			break
 		}
 
+		if n.bans.banned(BanTarget{IP: ip.IP}, n.peerConfig.Clock.Time()) {
+			n.peerConfig.Log.Debug("refusing connection from banned IP %s", ip)
+			n.metrics.connsBanned.Inc()
+			_ = conn.Close()
+			continue
+		}
+
 		if !n.inboundConnUpgradeThrottler.ShouldUpgrade(ip) {
 			n.peerConfig.Log.Debug(
 				"not upgrading connection to %s due to rate-limiting",
@@ -538,6 +733,55 @@ func (n *network) Dispatch() error {
 	return errs.Err
 }
 
+// dialPersistedPeers starts connecting to the peers this node was connected to
+// before it was restarted. The peers that are no longer validators are dropped
+// once they're dialed.
+func (n *network) dialPersistedPeers() {
+	if n.peerStore == nil {
+		return
+	}
+
+	peers, err := n.peerStore.load()
+	if err != nil {
+		n.peerConfig.Log.Warn("failed to load the persisted peers: %s", err)
+		return
+	}
+
+	n.peerConfig.Log.Info("dialing %d persisted peers", len(peers))
+	for _, p := range peers {
+		n.ManuallyTrack(p.NodeID, p.IP)
+	}
+}
+
+// allowlisted returns true if [nodeID] may connect to this node. Unless the
+// allowlist is enabled, every node may.
+func (n *network) allowlisted(nodeID ids.ShortID) bool {
+	allowlist := n.config.AllowlistConfig
+	return !allowlist.Enabled ||
+		allowlist.NodeIDs.Contains(nodeID) ||
+		n.config.Beacons.Contains(nodeID) ||
+		(allowlist.AllowValidators && n.config.Validators.Contains(constants.PrimaryNetworkID, nodeID))
+}
+
+// verifyAllowlisted is called during the TLS handshake, once the peer sent its
+// certificate, to refuse the handshake with the nodes that aren't allowlisted
+func (n *network) verifyAllowlisted(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		// The upgrader drops connections that didn't provide a certificate
+		return nil
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return err
+	}
+	nodeID := peer.CertToID(cert)
+	if !n.allowlisted(nodeID) {
+		n.metrics.connsNotAllowlisted.Inc()
+		return fmt.Errorf("%w: %s%s", errNotAllowlisted, constants.NodeIDPrefix, nodeID)
+	}
+	return nil
+}
+
 func (n *network) WantsConnection(nodeID ids.ShortID) bool {
 	return n.config.Validators.Contains(constants.PrimaryNetworkID, nodeID) ||
 		n.config.Beacons.Contains(nodeID)
@@ -567,12 +811,14 @@ func (n *network) ManuallyTrack(nodeID ids.ShortID, ip utils.IPDesc) {
 }
 
 func (n *network) sampleValidatorIPs() []utils.IPCertDesc {
+	primaryValidators := n.validatorSnapshots.get(constants.PrimaryNetworkID)
+
 	n.peersLock.RLock()
 	peers := n.connectedPeers.Sample(
 		int(n.config.PeerListNumValidatorIPs),
 		func(p peer.Peer) bool {
 			// Only sample validators
-			return n.config.Validators.Contains(constants.PrimaryNetworkID, p.ID())
+			return primaryValidators.Contains(p.ID())
 		},
 	)
 	n.peersLock.RUnlock()
@@ -598,10 +844,12 @@ func (n *network) sampleValidatorIPs() []utils.IPCertDesc {
 //   [validatorOnly] is set to true.
 // - [validatorOnly] is the flag to drop any nodes from [nodeIDs] that are not
 //   validators in [subnetID].
+// - [features] are the features that the returned peers must support.
 func (n *network) getPeers(
 	nodeIDs ids.ShortSet,
 	subnetID ids.ID,
 	validatorOnly bool,
+	features []version.Feature,
 ) []peer.Peer {
 	peers := make([]peer.Peer, 0, nodeIDs.Len())
 
@@ -623,6 +871,10 @@ func (n *network) getPeers(
 			continue
 		}
 
+		if !n.supports(peer, features) {
+			continue
+		}
+
 		peers = append(peers, peer)
 	}
 
@@ -632,6 +884,7 @@ func (n *network) getPeers(
 func (n *network) samplePeers(
 	subnetID ids.ID,
 	validatorOnly bool,
+	features []version.Feature,
 	numValidatorsToSample,
 	numNonValidatorsToSample int,
 	numPeersToSample int,
@@ -642,6 +895,8 @@ func (n *network) samplePeers(
 		numPeersToSample = 0
 	}
 
+	subnetValidators := n.validatorSnapshots.get(subnetID)
+
 	n.peersLock.RLock()
 	defer n.peersLock.RUnlock()
 
@@ -654,12 +909,16 @@ func (n *network) samplePeers(
 				return false
 			}
 
+			if !n.supports(p, features) {
+				return false
+			}
+
 			if numPeersToSample > 0 {
 				numPeersToSample--
 				return true
 			}
 
-			if n.config.Validators.Contains(subnetID, p.ID()) {
+			if subnetValidators.Contains(p.ID()) {
 				numValidatorsToSample--
 				return numValidatorsToSample >= 0
 			}
@@ -670,6 +929,36 @@ func (n *network) samplePeers(
 	)
 }
 
+// requiredFeatures returns the features a peer must support to be sent a
+// message of type [op].
+func (n *network) requiredFeatures(op message.Op) []version.Feature {
+	var features []version.Feature
+	switch op {
+	case message.AppRequest, message.AppResponse, message.AppGossip:
+		features = append(features, version.AppMessagesFeature)
+	case message.PushQueryBatch, message.ChitsBatch:
+		features = append(features, version.BatchedQueriesFeature)
+	case message.GetHeaders:
+		features = append(features, version.HeaderSyncFeature)
+	}
+	if n.config.CompressionEnabled && op.Compressible() {
+		features = append(features, version.CompressionFeature)
+	}
+	return features
+}
+
+// supports returns true if [p] supports all of [features].
+func (n *network) supports(p peer.Peer, features []version.Feature) bool {
+	peerVersion := p.Version()
+	peerFeatures := p.AdvertisedFeatures()
+	for _, feature := range features {
+		if !n.config.PeerPolicy.PeerSupports(peerVersion, peerFeatures, feature) {
+			return false
+		}
+	}
+	return true
+}
+
 // send the message to the provided peers.
 //
 // send takes ownership of the provided message reference. So, the provided
@@ -727,6 +1016,16 @@ func (n *network) disconnectedFromConnecting(nodeID ids.ShortID) {
 func (n *network) disconnectedFromConnected(peer peer.Peer, nodeID ids.ShortID) {
 	n.router.Disconnected(nodeID)
 
+	if n.peerStore != nil {
+		if err := n.peerStore.disconnected(nodeID, n.peerConfig.Clock.Unix()); err != nil {
+			n.peerConfig.Log.Warn(
+				"failed to persist the disconnection from %s%s: %s",
+				constants.NodeIDPrefix, nodeID,
+				err,
+			)
+		}
+	}
+
 	n.peersLock.Lock()
 	defer n.peersLock.Unlock()
 
@@ -739,6 +1038,10 @@ func (n *network) disconnectedFromConnected(peer peer.Peer, nodeID ids.ShortID)
 		n.dial(n.onCloseCtx, nodeID, tracked)
 	} else {
 		delete(n.trackedIPs, nodeID)
+		// Only the IPs of nodes this node wants to connect to are remembered,
+		// so that peers can't grow [latestIPs] by connecting with new
+		// identities.
+		delete(n.latestIPs, nodeID)
 	}
 
 	n.metrics.markDisconnected(peer)
@@ -765,6 +1068,16 @@ func (n *network) shouldTrack(nodeID ids.ShortID, ip utils.IPCertDesc) bool {
 		return false
 	}
 
+	if n.isStaleIP(nodeID, ip) {
+		n.metrics.staleIPsRejected.Inc()
+		n.peerConfig.Log.Verbo(
+			"dropping stale IP of %s%s signed at %d",
+			constants.NodeIDPrefix, nodeID,
+			ip.Time,
+		)
+		return false
+	}
+
 	tracked, isTracked := n.trackedIPs[nodeID]
 	if isTracked {
 		return tracked.ip.Timestamp < ip.Time
@@ -772,6 +1085,26 @@ func (n *network) shouldTrack(nodeID ids.ShortID, ip utils.IPCertDesc) bool {
 	return n.WantsConnection(nodeID)
 }
 
+// isStaleIP returns true if a more recent signed IP of [nodeID] was already
+// verified, or if [ip] claims the timestamp of the latest IP for a different
+// address. Assumes [n.peersLock] is held.
+func (n *network) isStaleIP(nodeID ids.ShortID, ip utils.IPCertDesc) bool {
+	latest, ok := n.latestIPs[nodeID]
+	if !ok {
+		return false
+	}
+	return ip.Time < latest.Timestamp ||
+		(ip.Time == latest.Timestamp && !ip.IPDesc.Equal(latest.IP))
+}
+
+// updateLatestIP records [ip] as the latest verified IP of [nodeID] if it's
+// more recent than the current one. Assumes [n.peersLock] is held.
+func (n *network) updateLatestIP(nodeID ids.ShortID, ip peer.UnsignedIP) {
+	if latest, ok := n.latestIPs[nodeID]; !ok || latest.Timestamp < ip.Timestamp {
+		n.latestIPs[nodeID] = ip
+	}
+}
+
 // dial will spin up a new goroutine and attempt to establish a connection with
 // [nodeID] at [ip].
 //
@@ -807,7 +1140,7 @@ func (n *network) dial(ctx context.Context, nodeID ids.ShortID, ip *trackedIP) {
 			}
 
 			n.peersLock.Lock()
-			if !n.WantsConnection(nodeID) {
+			if !n.WantsConnection(nodeID) || !n.allowlisted(nodeID) {
 				// Typically [n.trackedIPs[nodeID]] will already equal [ip], but
 				// the reference to [ip] is refreshed to avoid any potential
 				// race conditions before removing the entry.
@@ -818,6 +1151,20 @@ func (n *network) dial(ctx context.Context, nodeID ids.ShortID, ip *trackedIP) {
 				n.peersLock.Unlock()
 				return
 			}
+			if n.bans.nodeBanned(nodeID, ip.ip.IP.IP, n.peerConfig.Clock.Time()) {
+				if ip, exists := n.trackedIPs[nodeID]; exists {
+					ip.stopTracking()
+					delete(n.trackedIPs, nodeID)
+				}
+				n.peersLock.Unlock()
+				n.peerConfig.Log.Debug(
+					"not dialing banned peer %s%s at %s",
+					constants.NodeIDPrefix, nodeID,
+					ip.ip,
+				)
+				n.metrics.connsBanned.Inc()
+				return
+			}
 			_, connecting := n.connectingPeers.GetByID(nodeID)
 			_, connected := n.connectedPeers.GetByID(nodeID)
 			n.peersLock.Unlock()
@@ -913,6 +1260,16 @@ func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader) error {
 		return nil
 	}
 
+	if n.bans.banned(BanTarget{NodeID: nodeID}, n.peerConfig.Clock.Time()) {
+		_ = tlsConn.Close()
+		n.peerConfig.Log.Debug(
+			"dropping connection to banned peer %s%s",
+			constants.NodeIDPrefix, nodeID,
+		)
+		n.metrics.connsBanned.Inc()
+		return nil
+	}
+
 	if !n.AllowConnection(nodeID) {
 		_ = tlsConn.Close()
 		n.peerConfig.Log.Verbo(
@@ -972,6 +1329,55 @@ func (n *network) PeerInfo(nodeIDs []ids.ShortID) []peer.Info {
 	return n.connectedPeers.Info(nodeIDs)
 }
 
+func (n *network) Ban(target BanTarget, duration time.Duration) error {
+	if duration <= 0 {
+		return errNonPositiveBan
+	}
+	err := n.bans.ban(Ban{
+		BanTarget: target,
+		Expiry:    n.peerConfig.Clock.Time().Add(duration),
+	})
+	if err != nil {
+		return err
+	}
+
+	n.peersLock.RLock()
+	peers := append(
+		n.connectingPeers.Sample(n.connectingPeers.Len(), peer.NoPrecondition),
+		n.connectedPeers.Sample(n.connectedPeers.Len(), peer.NoPrecondition)...,
+	)
+	n.peersLock.RUnlock()
+
+	for _, p := range peers {
+		if matchesBan(p, target) {
+			n.peerConfig.Log.Info(
+				"disconnecting from banned peer %s%s",
+				constants.NodeIDPrefix, p.ID(),
+			)
+			p.StartClose()
+		}
+	}
+	return nil
+}
+
+// matchesBan returns true if [p] is a peer [target] applies to. The IP of a
+// peer is only known once it sent its Version message.
+func matchesBan(p peer.Peer, target BanTarget) bool {
+	if target.IP == nil {
+		return p.ID() == target.NodeID
+	}
+	ip := p.IP()
+	return ip != nil && ip.IP.IP.IP.Equal(target.IP)
+}
+
+func (n *network) Unban(target BanTarget) (bool, error) {
+	return n.bans.unban(target)
+}
+
+func (n *network) Bans() []Ban {
+	return n.bans.active(n.peerConfig.Clock.Time())
+}
+
 func (n *network) StartClose() {
 	n.closeOnce.Do(func() {
 		n.peerConfig.Log.Info("shutting down the p2p networking")
@@ -1053,9 +1459,11 @@ func (n *network) NodeUptime() (UptimeResult, bool) {
 func (n *network) runTimers() {
 	gossipPeerlists := time.NewTicker(n.config.PeerListGossipFreq)
 	updateUptimes := time.NewTicker(n.config.UptimeMetricFreq)
+	updateLastReceived := time.NewTicker(n.config.PingFrequency)
 	defer func() {
 		gossipPeerlists.Stop()
 		updateUptimes.Stop()
+		updateLastReceived.Stop()
 	}()
 
 	for {
@@ -1093,6 +1501,16 @@ func (n *network) runTimers() {
 			result, _ := n.NodeUptime()
 			n.metrics.nodeUptimeWeightedAverage.Set(result.WeightedAveragePercentage)
 			n.metrics.nodeUptimeRewardingStake.Set(result.RewardingStakePercentage)
+
+		case <-updateLastReceived.C:
+			now := n.peerConfig.Clock.Time()
+
+			n.peersLock.RLock()
+			for i := 0; i < n.connectedPeers.Len(); i++ {
+				peer, _ := n.connectedPeers.GetByIndex(i)
+				n.metrics.markLastReceived(peer, now)
+			}
+			n.peersLock.RUnlock()
 		}
 	}
 }
@@ -5,6 +5,7 @@ package network
 
 import (
 	"crypto"
+	"io"
 	"net"
 	"sync"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
@@ -288,6 +290,147 @@ func TestSend(t *testing.T) {
 	wg.Wait()
 }
 
+func TestSendOnlyToCapablePeers(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeIDs, networks, wg := newFullyConnectedTestNetwork(
+		t,
+		[]router.InboundHandler{
+			router.InboundHandlerFunc(func(message.InboundMessage) {
+				t.Fatal("unexpected message received")
+			}),
+			router.InboundHandlerFunc(func(message.InboundMessage) {
+				t.Fatal("unexpected message received")
+			}),
+		},
+	)
+
+	// Pretend that no released version supports app messages
+	net0 := networks[0].(*network)
+	net0.config.PeerPolicy = &version.PeerPolicy{
+		FeatureVersions: map[version.Feature]version.Application{
+			version.AppMessagesFeature: version.NewDefaultApplication(constants.PlatformName, 1000, 0, 0),
+		},
+	}
+
+	mc := newMessageCreator(t)
+	outboundGossipMsg, err := mc.AppGossip(ids.Empty, []byte{1})
+	assert.NoError(err)
+
+	toSend := ids.ShortSet{}
+	toSend.Add(nodeIDs[1])
+	sentTo := net0.Send(outboundGossipMsg, toSend, constants.PrimaryNetworkID, false)
+	assert.Zero(sentTo.Len())
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestBanDisconnectsPeer(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeIDs, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil, nil})
+
+	net1 := networks[1].(*network)
+	assert.ErrorIs(net1.Ban(BanTarget{NodeID: nodeIDs[0]}, 0), errNonPositiveBan)
+	assert.NoError(net1.Ban(BanTarget{NodeID: nodeIDs[0]}, time.Hour))
+
+	// The banned peer is disconnected, and isn't dialed again
+	assert.Eventually(func() bool {
+		net1.peersLock.RLock()
+		defer net1.peersLock.RUnlock()
+
+		_, connected := net1.connectedPeers.GetByID(nodeIDs[0])
+		_, tracked := net1.trackedIPs[nodeIDs[0]]
+		return !connected && !tracked
+	}, 5*time.Second, 10*time.Millisecond)
+
+	bans := net1.Bans()
+	assert.Len(bans, 1)
+	assert.Equal(nodeIDs[0], bans[0].NodeID)
+
+	unbanned, err := net1.Unban(BanTarget{NodeID: nodeIDs[0]})
+	assert.NoError(err)
+	assert.True(unbanned)
+	assert.Empty(net1.Bans())
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestAllowlistRefusesHandshake(t *testing.T) {
+	assert := assert.New(t)
+
+	_, listeners, nodeIDs, configs := newTestNetwork(t, 1)
+	config := configs[0]
+	config.Beacons = validators.NewSet()
+	config.Validators = validators.NewManager()
+	assert.NoError(config.Validators.AddWeight(constants.PrimaryNetworkID, nodeIDs[0], 1))
+	config.AllowlistConfig.Enabled = true
+
+	netIntf, err := NewNetwork(
+		config,
+		newMessageCreator(t),
+		prometheus.NewRegistry(),
+		logging.NoLog{},
+		listeners[0],
+		newTestDialer(),
+		&testHandler{},
+		benchlist.NewManager(&benchlist.Config{}),
+	)
+	assert.NoError(err)
+	n := netIntf.(*network)
+
+	nodeID, _, tlsConfig := getTLS(t, 1)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	defer listener.Close()
+
+	// handshake returns the error of the server side of the TLS handshake
+	handshake := func() error {
+		clientConn, err := net.Dial("tcp", listener.Addr().String())
+		assert.NoError(err)
+		serverConn, err := listener.Accept()
+		assert.NoError(err)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			_, conn, _, err := peer.NewTLSClientUpgrader(tlsConfig).Upgrade(clientConn)
+			if err == nil {
+				// The server only verifies the certificate of the client
+				// once the client considers the handshake done
+				_, _ = io.Copy(io.Discard, conn)
+			}
+			_ = clientConn.Close()
+		}()
+		_, _, _, err = n.serverUpgrader.Upgrade(serverConn)
+		_ = serverConn.Close()
+		<-done
+		return err
+	}
+
+	assert.ErrorIs(handshake(), errNotAllowlisted)
+
+	n.config.AllowlistConfig.NodeIDs.Add(nodeID)
+	assert.NoError(handshake())
+
+	n.config.AllowlistConfig.NodeIDs.Remove(nodeID)
+	n.config.AllowlistConfig.AllowValidators = true
+	assert.ErrorIs(handshake(), errNotAllowlisted)
+	assert.NoError(config.Validators.AddWeight(constants.PrimaryNetworkID, nodeID, 1))
+	assert.NoError(handshake())
+
+	n.config.AllowlistConfig.AllowValidators = false
+	assert.NoError(config.Beacons.AddWeight(nodeID, 1))
+	assert.NoError(handshake())
+}
+
 func TestTrackVerifiesSignatures(t *testing.T) {
 	assert := assert.New(t)
 
@@ -317,3 +460,160 @@ func TestTrackVerifiesSignatures(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestTrackDetectsDuplicateIdentity(t *testing.T) {
+	assert := assert.New(t)
+
+	_, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil})
+
+	network := networks[0].(*network)
+	_, tlsCert, _ := getTLS(t, 0)
+
+	mySignedIP, err := network.ipSigner.getSignedIP()
+	assert.NoError(err)
+
+	// Our own IP being gossiped back to us isn't a duplicate identity.
+	network.Track(utils.IPCertDesc{
+		Cert:      tlsCert.Leaf,
+		IPDesc:    mySignedIP.IP.IP,
+		Time:      mySignedIP.IP.Timestamp,
+		Signature: mySignedIP.Signature,
+	})
+	_, detected := network.duplicateIdentityIP.GetValue().(utils.IPDesc)
+	assert.False(detected)
+
+	otherIP := peer.UnsignedIP{
+		IP: utils.IPDesc{
+			IP:   net.IPv4(123, 132, 123, 123),
+			Port: 10000,
+		},
+		Timestamp: mySignedIP.IP.Timestamp + 1,
+	}
+	otherSignedIP, err := otherIP.Sign(network.config.TLSKey)
+	assert.NoError(err)
+
+	network.Track(utils.IPCertDesc{
+		Cert:      tlsCert.Leaf,
+		IPDesc:    otherSignedIP.IP.IP,
+		Time:      otherSignedIP.IP.Timestamp,
+		Signature: otherSignedIP.Signature,
+	})
+	duplicateIP, detected := network.duplicateIdentityIP.GetValue().(utils.IPDesc)
+	assert.True(detected)
+	assert.Equal(otherIP.IP, duplicateIP)
+
+	network.peersLock.RLock()
+	assert.Empty(network.trackedIPs)
+	network.peersLock.RUnlock()
+
+	details, err := network.HealthCheck()
+	assert.Error(err)
+	assert.Contains(details, DuplicateIdentityIPKey)
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestTrackRejectsStaleIPs(t *testing.T) {
+	assert := assert.New(t)
+
+	_, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil})
+
+	network := networks[0].(*network)
+	nodeID, tlsCert, _ := getTLS(t, 1)
+	err := network.config.Validators.AddWeight(constants.PrimaryNetworkID, nodeID, 1)
+	assert.NoError(err)
+
+	signIP := func(ip net.IP, timestamp uint64) utils.IPCertDesc {
+		unsignedIP := peer.UnsignedIP{
+			IP: utils.IPDesc{
+				IP:   ip,
+				Port: 10000,
+			},
+			Timestamp: timestamp,
+		}
+		signedIP, err := unsignedIP.Sign(tlsCert.PrivateKey.(crypto.Signer))
+		assert.NoError(err)
+		return utils.IPCertDesc{
+			Cert:      tlsCert.Leaf,
+			IPDesc:    signedIP.IP.IP,
+			Time:      signedIP.IP.Timestamp,
+			Signature: signedIP.Signature,
+		}
+	}
+	trackedIP := func() utils.IPDesc {
+		network.peersLock.RLock()
+		defer network.peersLock.RUnlock()
+
+		return network.trackedIPs[nodeID].ip.IP
+	}
+
+	// The clock is stopped so that the bounds on the signing times don't move
+	network.peerConfig.Clock.Set(time.Now())
+	now := network.peerConfig.Clock.Unix()
+	latestIP := signIP(net.IPv4(123, 132, 123, 123), now)
+	network.Track(latestIP)
+	assert.Equal(latestIP.IPDesc, trackedIP())
+
+	// Older signatures, and other IPs signed at the same time, are stale
+	network.Track(signIP(net.IPv4(123, 132, 123, 124), now-1))
+	network.Track(signIP(net.IPv4(123, 132, 123, 125), now))
+	assert.Equal(latestIP.IPDesc, trackedIP())
+
+	// The latest IP is remembered once it's no longer tracked
+	network.peersLock.Lock()
+	network.trackedIPs[nodeID].stopTracking()
+	delete(network.trackedIPs, nodeID)
+	network.peersLock.Unlock()
+	network.Track(signIP(net.IPv4(123, 132, 123, 124), now-1))
+	network.peersLock.RLock()
+	assert.Empty(network.trackedIPs)
+	network.peersLock.RUnlock()
+
+	// IPs signed too far in the future are rejected
+	network.Track(signIP(net.IPv4(123, 132, 123, 126), now+uint64(network.peerConfig.MaxClockDifference.Seconds())+1))
+	network.peersLock.RLock()
+	assert.Empty(network.trackedIPs)
+	network.peersLock.RUnlock()
+
+	newIP := signIP(net.IPv4(123, 132, 123, 127), now+1)
+	network.Track(newIP)
+	assert.Equal(newIP.IPDesc, trackedIP())
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestAdvertisedFeaturesAfterApricotPhase6(t *testing.T) {
+	assert := assert.New(t)
+
+	_, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil})
+
+	network := networks[0].(*network)
+	network.peerConfig.Clock.Set(time.Now())
+	now := network.peerConfig.Clock.Time()
+
+	// Features aren't advertised before apricot phase 6
+	network.upgrades = version.NewUpgradeSchedule(version.Upgrade{
+		Name: version.ApricotPhase6,
+		Time: now.Add(time.Second),
+	})
+	assert.Nil(network.advertisedFeatures())
+
+	network.upgrades = version.NewUpgradeSchedule(version.Upgrade{
+		Name: version.ApricotPhase6,
+		Time: now,
+	})
+	features := network.advertisedFeatures()
+	assert.NotNil(features)
+	assert.Equal(network.myFeatures, *features)
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
@@ -8,10 +8,12 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/lightclient"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/version"
 )
@@ -20,8 +22,9 @@ type Config struct {
 	// Size, in bytes, of the buffer this peer reads messages into
 	ReadBufferSize int
 	// Size, in bytes, of the buffer this peer writes messages into
-	WriteBufferSize      int
-	Clock                mockable.Clock
+	WriteBufferSize int
+	// Clock tells the time. It may be shared with the rest of the node.
+	Clock                *mockable.Clock
 	Metrics              *Metrics
 	MessageCreator       message.Creator
 	Log                  logging.Logger
@@ -31,6 +34,7 @@ type Config struct {
 	Router               router.InboundHandler
 	VersionCompatibility version.Compatibility
 	VersionParser        version.ApplicationParser
+	PeerPolicy           *version.PeerPolicy
 	MySubnets            ids.Set
 	Beacons              validators.Set
 	NetworkID            uint32
@@ -38,6 +42,22 @@ type Config struct {
 	PongTimeout          time.Duration
 	MaxClockDifference   time.Duration
 
+	// DeadPeerTimeout is the amount of time the peer has to answer a ping
+	// before it's disconnected from. If 0, the peer is only disconnected from
+	// once nothing has been received from it for [PongTimeout].
+	DeadPeerTimeout time.Duration
+
+	// LightClient serves the headers requested by light clients. If nil,
+	// GetHeaders messages are dropped.
+	LightClient lightclient.Server
+	// HeaderRequestsPerSec is the number of GetHeaders messages each peer may
+	// send per second
+	HeaderRequestsPerSec float64
+
+	// PeerTime records the times reported by peers. If nil, they aren't
+	// recorded.
+	PeerTime *timer.PeerTime
+
 	// Unix time of the last message sent and received respectively
 	// Must only be accessed atomically
 	LastSent, LastReceived int64
@@ -8,15 +8,17 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 type Info struct {
-	IP             string     `json:"ip"`
-	PublicIP       string     `json:"publicIP,omitempty"`
-	ID             string     `json:"nodeID"`
-	Version        string     `json:"version"`
-	LastSent       time.Time  `json:"lastSent"`
-	LastReceived   time.Time  `json:"lastReceived"`
-	ObservedUptime json.Uint8 `json:"observedUptime"`
-	TrackedSubnets []ids.ID   `json:"trackedSubnets"`
+	IP             string            `json:"ip"`
+	PublicIP       string            `json:"publicIP,omitempty"`
+	ID             string            `json:"nodeID"`
+	Version        string            `json:"version"`
+	LastSent       time.Time         `json:"lastSent"`
+	LastReceived   time.Time         `json:"lastReceived"`
+	ObservedUptime json.Uint8        `json:"observedUptime"`
+	TrackedSubnets []ids.ID          `json:"trackedSubnets"`
+	Features       []version.Feature `json:"features"`
 }
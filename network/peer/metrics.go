@@ -85,6 +85,7 @@ func NewMessageMetrics(
 type Metrics struct {
 	Log            logging.Logger
 	FailedToParse  prometheus.Counter
+	DeadPeers      prometheus.Counter
 	MessageMetrics map[message.Op]*MessageMetrics
 }
 
@@ -100,11 +101,19 @@ func NewMetrics(
 			Name:      "msgs_failed_to_parse",
 			Help:      "Number of messages that could not be parsed or were invalidly formed",
 		}),
+		DeadPeers: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dead_peers",
+			Help:      "Number of peers that were disconnected from for not answering a ping in time",
+		}),
 		MessageMetrics: make(map[message.Op]*MessageMetrics, len(message.ExternalOps)),
 	}
 
 	errs := wrappers.Errs{}
-	errs.Add(registerer.Register(m.FailedToParse))
+	errs.Add(
+		registerer.Register(m.FailedToParse),
+		registerer.Register(m.DeadPeers),
+	)
 	for _, op := range message.ExternalOps {
 		m.MessageMetrics[op] = NewMessageMetrics(op, namespace, registerer, &errs)
 	}
@@ -16,6 +16,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/utils"
@@ -73,6 +75,11 @@ type Peer interface {
 	// be called after [Ready] returns true.
 	TrackedSubnets() ids.Set
 
+	// AdvertisedFeatures returns the features this peer advertised during the
+	// handshake, or nil if it didn't advertise any. It should only be called
+	// after [Ready] returns true.
+	AdvertisedFeatures() *version.FeatureBits
+
 	// ObservedUptime returns the local node's uptime according to the peer. The
 	// value ranges from [0, 100]. It should only be called after [Ready]
 	// returns true.
@@ -117,11 +124,17 @@ type peer struct {
 	// trackedSubnets is the subset of subnetIDs the peer sent us in the Version
 	// message that we are also tracking.
 	trackedSubnets ids.Set
+	// features is the set of features the peer advertised in the Version
+	// message, or nil if the peer didn't advertise any.
+	features *version.FeatureBits
 
 	observedUptimeLock sync.RWMutex
 	// [observedUptimeLock] must be held while accessing [observedUptime]
 	observedUptime uint8
 
+	// Limits the rate that this peer's GetHeaders messages are served at
+	headerRequestLimiter *rate.Limiter
+
 	// True if this peer has sent us a valid Version message and
 	// is running a compatible version.
 	// Only modified on the connection's reader routine.
@@ -165,6 +178,10 @@ type peer struct {
 	// Unix time of the last message sent and received respectively
 	// Must only be accessed atomically
 	lastSent, lastReceived int64
+
+	// Unix time, in nanoseconds, of the last pong received
+	// Must only be accessed atomically
+	lastPongReceived int64
 }
 
 func Start(
@@ -184,6 +201,10 @@ func Start(
 		onClosed:          make(chan struct{}),
 		sendQueueCond:     sync.NewCond(&sync.Mutex{}),
 		canSend:           true,
+		headerRequestLimiter: rate.NewLimiter(
+			rate.Limit(config.HeaderRequestsPerSec),
+			int(config.HeaderRequestsPerSec)+1,
+		),
 	}
 
 	p.trackedSubnets.Add(constants.PrimaryNetworkID)
@@ -245,6 +266,7 @@ func (p *peer) Info() Info {
 		LastReceived:   time.Unix(atomic.LoadInt64(&p.lastReceived), 0),
 		ObservedUptime: json.Uint8(p.ObservedUptime()),
 		TrackedSubnets: p.trackedSubnets.List(),
+		Features:       p.PeerPolicy.PeerFeatures(p.version, p.features),
 	}
 }
 
@@ -254,6 +276,8 @@ func (p *peer) Version() version.Application { return p.version }
 
 func (p *peer) TrackedSubnets() ids.Set { return p.trackedSubnets }
 
+func (p *peer) AdvertisedFeatures() *version.FeatureBits { return p.features }
+
 func (p *peer) ObservedUptime() uint8 {
 	p.observedUptimeLock.RLock()
 	uptime := p.observedUptime
@@ -420,14 +444,16 @@ func (p *peer) readMessages() {
 			return
 		}
 
-		// Read the message
-		msgBytes := make([]byte, msgLen)
-		if _, err := io.ReadFull(reader, msgBytes); err != nil {
+		// Read the message into a pooled buffer. Once the message is parsed,
+		// the buffer is released when the message has been handled.
+		buffer := p.MessageCreator.NewBuffer(int(msgLen))
+		if _, err := io.ReadFull(reader, buffer.Bytes); err != nil {
 			p.Log.Verbo(
 				"error reading from %s%s: %s",
 				constants.NodeIDPrefix, p.id,
 				err,
 			)
+			buffer.Release()
 			onFinishedHandling()
 			return
 		}
@@ -435,22 +461,23 @@ func (p *peer) readMessages() {
 		p.Log.Verbo(
 			"parsing message from %s%s:\n%s",
 			constants.NodeIDPrefix, p.id,
-			formatting.DumpBytes(msgBytes),
+			formatting.DumpBytes(buffer.Bytes),
 		)
 
 		// Parse the message
-		msg, err := p.MessageCreator.Parse(msgBytes, p.id, onFinishedHandling)
+		msg, err := p.MessageCreator.ParseBuffer(buffer, p.id, onFinishedHandling)
 		if err != nil {
 			p.Log.Verbo(
 				"failed to parse message from %s%s: %s\n%s",
 				constants.NodeIDPrefix, p.id,
 				err,
-				formatting.DumpBytes(msgBytes),
+				formatting.DumpBytes(buffer.Bytes),
 			)
 
 			p.Metrics.FailedToParse.Inc()
 
 			// Couldn't parse the message. Read the next one.
+			buffer.Release()
 			onFinishedHandling()
 			continue
 		}
@@ -591,8 +618,19 @@ func (p *peer) nextMessageWithBlocking() (message.OutboundMessage, bool) {
 
 func (p *peer) sendPings() {
 	sendPingsTicker := time.NewTicker(p.PingFrequency)
+
+	// If [p.DeadPeerTimeout] > 0, [pongDeadline] fires [p.DeadPeerTimeout]
+	// after the ping sent at [pingSentAt]. It's nil when no ping is awaiting a
+	// pong.
+	var (
+		pongDeadline *time.Timer
+		pingSentAt   time.Time
+	)
 	defer func() {
 		sendPingsTicker.Stop()
+		if pongDeadline != nil {
+			pongDeadline.Stop()
+		}
 
 		p.StartClose()
 		p.close()
@@ -624,12 +662,39 @@ func (p *peer) sendPings() {
 			msg, err := p.MessageCreator.Ping()
 			p.Log.AssertNoError(err)
 			p.Send(msg)
+
+			if p.DeadPeerTimeout > 0 && pongDeadline == nil {
+				pingSentAt = p.Clock.Time()
+				pongDeadline = time.NewTimer(p.DeadPeerTimeout)
+			}
+		case <-timerC(pongDeadline):
+			pongDeadline = nil
+
+			lastPongReceived := time.Unix(0, atomic.LoadInt64(&p.lastPongReceived))
+			if lastPongReceived.Before(pingSentAt) {
+				p.Log.Debug(
+					"disconnecting from peer %s%s because it didn't answer a ping within %s",
+					constants.NodeIDPrefix, p.id,
+					p.DeadPeerTimeout,
+				)
+				p.Metrics.DeadPeers.Inc()
+				return
+			}
 		case <-p.onClosing:
 			return
 		}
 	}
 }
 
+// timerC returns the channel of [timer], or nil if [timer] is nil, so that
+// selecting on it blocks forever.
+func timerC(timer *time.Timer) <-chan time.Time {
+	if timer == nil {
+		return nil
+	}
+	return timer.C
+}
+
 func (p *peer) handle(msg message.InboundMessage) {
 	op := msg.Op()
 	switch op { // Network-related message types
@@ -660,6 +725,22 @@ func (p *peer) handle(msg message.InboundMessage) {
 		return
 	}
 
+	switch op { // Light client message types
+	case message.GetHeaders:
+		p.handleGetHeaders(msg)
+		msg.OnFinishedHandling()
+		return
+	case message.Headers:
+		// This node never requests headers
+		p.Log.Debug(
+			"dropping unrequested %s from %s%s",
+			op,
+			constants.NodeIDPrefix, p.id,
+		)
+		msg.OnFinishedHandling()
+		return
+	}
+
 	// Consensus and app-level messages
 	p.Router.HandleInbound(msg)
 }
@@ -671,6 +752,8 @@ func (p *peer) handlePing(_ message.InboundMessage) {
 }
 
 func (p *peer) handlePong(msg message.InboundMessage) {
+	atomic.StoreInt64(&p.lastPongReceived, p.Clock.Time().UnixNano())
+
 	uptime := msg.Get(message.Uptime).(uint8)
 	if uptime > 100 {
 		return
@@ -722,6 +805,9 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 		p.StartClose()
 		return
 	}
+	if p.PeerTime != nil {
+		p.PeerTime.Observe(p.id, time.Unix(int64(peerTime), 0))
+	}
 
 	peerVersionStr := msg.Get(message.VersionStr).(string)
 	peerVersion, err := p.VersionParser.Parse(peerVersionStr)
@@ -762,6 +848,16 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 		return
 	}
 
+	if err := p.PeerPolicy.Acceptable(peerVersion); err != nil {
+		p.Log.Verbo("peer %s%s version (%s) not acceptable: %s",
+			constants.NodeIDPrefix, p.id,
+			peerVersion,
+			err,
+		)
+		p.StartClose()
+		return
+	}
+
 	// Note that it is expected that the [versionTime] can be in the past. We
 	// are just verifying that the claimed signing time isn't too far in the
 	// future here.
@@ -797,6 +893,13 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 		}
 	}
 
+	// Peers running a version from before the feature bits were introduced
+	// don't advertise their features
+	if features, ok := msg.Get(message.Features).(uint64); ok {
+		featureBits := version.FeatureBits(features)
+		p.features = &featureBits
+	}
+
 	p.ip = &SignedIP{
 		IP: UnsignedIP{
 			IP:        peerIP,
@@ -837,6 +940,61 @@ func (p *peer) handlePeerList(msg message.InboundMessage) {
 	}
 }
 
+func (p *peer) handleGetHeaders(msg message.InboundMessage) {
+	if p.LightClient == nil {
+		p.Log.Verbo(
+			"dropping get_headers from %s%s because headers aren't served",
+			constants.NodeIDPrefix, p.id,
+		)
+		return
+	}
+	if !p.headerRequestLimiter.Allow() {
+		p.Log.Debug(
+			"dropping get_headers from %s%s because it exceeded its rate limit",
+			constants.NodeIDPrefix, p.id,
+		)
+		return
+	}
+
+	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
+	p.Log.AssertNoError(err)
+	requestID := msg.Get(message.RequestID).(uint32)
+	startHeight := msg.Get(message.StartHeight).(uint64)
+	numHeaders := msg.Get(message.NumHeaders).(uint32)
+
+	// Serving headers may wait for the chain and the P-chain, so it's done off
+	// of this peer's read goroutine.
+	served := p.LightClient.ServeHeaders(chainID, startHeight, numHeaders, func(headers [][]byte, err error) {
+		if err != nil {
+			p.Log.Debug(
+				"failed to get headers of %s for %s%s: %s",
+				chainID,
+				constants.NodeIDPrefix, p.id,
+				err,
+			)
+			return
+		}
+
+		headersMsg, err := p.MessageCreator.Headers(chainID, requestID, headers)
+		if err != nil {
+			p.Log.Debug(
+				"failed to build headers message of %s for %s%s: %s",
+				chainID,
+				constants.NodeIDPrefix, p.id,
+				err,
+			)
+			return
+		}
+		p.Send(headersMsg)
+	})
+	if !served {
+		p.Log.Debug(
+			"dropping get_headers from %s%s because too many requests are being served",
+			constants.NodeIDPrefix, p.id,
+		)
+	}
+}
+
 func (p *peer) nextTimeout() time.Time {
 	return p.Clock.Time().Add(p.PongTimeout)
 }
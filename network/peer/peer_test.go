@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/x509"
+	"io"
 	"net"
 	"testing"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -77,12 +79,14 @@ func makeRawTestPeers(t *testing.T) (*rawTestPeer, *rawTestPeer) {
 	assert.NoError(err)
 
 	sharedConfig := Config{
+		Clock:                &mockable.Clock{},
 		Metrics:              metrics,
 		MessageCreator:       mc,
 		Log:                  logging.NoLog{},
 		InboundMsgThrottler:  throttling.NewNoInboundThrottler(),
 		OutboundMsgThrottler: throttling.NewNoOutboundThrottler(),
 		VersionCompatibility: version.GetCompatibility(constants.LocalID),
+		PeerPolicy:           version.NewDefaultPeerPolicy(),
 		VersionParser:        version.NewDefaultApplicationParser(),
 		MySubnets:            ids.Set{},
 		Beacons:              validators.NewSet(),
@@ -252,3 +256,91 @@ func TestSend(t *testing.T) {
 	err = peer1.AwaitClosed(context.Background())
 	assert.NoError(err)
 }
+
+func TestAdvertisedFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	features := version.NewFeatureBits(version.CompressionFeature, version.HeaderSyncFeature)
+	rawPeer0.config.Network.(*testNetwork).features = &features
+
+	peer0 := Start(rawPeer0.config, rawPeer0.conn, rawPeer1.cert, rawPeer1.nodeID)
+	peer1 := Start(rawPeer1.config, rawPeer1.conn, rawPeer0.cert, rawPeer0.nodeID)
+	assert.NoError(peer0.AwaitReady(context.Background()))
+	assert.NoError(peer1.AwaitReady(context.Background()))
+
+	// peer1 didn't advertise any features, so its support is inferred from its
+	// version
+	assert.Nil(peer0.AdvertisedFeatures())
+	assert.Contains(peer0.Info().Features, version.AppMessagesFeature)
+	assert.NotContains(peer0.Info().Features, version.HeaderSyncFeature)
+
+	assert.Equal(&features, peer1.AdvertisedFeatures())
+	assert.Equal(
+		[]version.Feature{version.CompressionFeature, version.HeaderSyncFeature},
+		peer1.Info().Features,
+	)
+
+	peer0.StartClose()
+	assert.NoError(peer0.AwaitClosed(context.Background()))
+	assert.NoError(peer1.AwaitClosed(context.Background()))
+}
+
+func TestDeadPeerTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	rawPeer0.config.PingFrequency = 10 * time.Millisecond
+	rawPeer0.config.DeadPeerTimeout = 50 * time.Millisecond
+
+	peer0 := Start(
+		rawPeer0.config,
+		rawPeer0.conn,
+		rawPeer1.cert,
+		rawPeer1.nodeID,
+	)
+
+	// The other end reads everything, so the connection's writes don't block,
+	// but never answers the pings
+	go func() {
+		_, _ = io.Copy(io.Discard, rawPeer1.conn)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := peer0.AwaitClosed(ctx)
+	assert.NoError(err)
+}
+
+func TestDeadPeerTimeoutAnsweredPings(t *testing.T) {
+	assert := assert.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	rawPeer0.config.PingFrequency = 10 * time.Millisecond
+	rawPeer0.config.DeadPeerTimeout = 250 * time.Millisecond
+
+	peer0 := Start(
+		rawPeer0.config,
+		rawPeer0.conn,
+		rawPeer1.cert,
+		rawPeer1.nodeID,
+	)
+	peer1 := Start(
+		rawPeer1.config,
+		rawPeer1.conn,
+		rawPeer0.cert,
+		rawPeer0.nodeID,
+	)
+
+	// Many pings are answered before the peer would be considered dead
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := peer0.AwaitClosed(ctx)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	peer1.StartClose()
+	err = peer0.AwaitClosed(context.Background())
+	assert.NoError(err)
+	err = peer1.AwaitClosed(context.Background())
+	assert.NoError(err)
+}
@@ -24,6 +24,7 @@ type testNetwork struct {
 	version   version.Application
 	signer    crypto.Signer
 	subnets   ids.Set
+	features  *version.FeatureBits
 
 	uptime uint8
 }
@@ -75,6 +76,7 @@ func (n *testNetwork) Version() (message.OutboundMessage, error) {
 		now,
 		signedIP.Signature,
 		n.subnets.List(),
+		n.features,
 	)
 }
 
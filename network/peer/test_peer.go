@@ -20,6 +20,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -87,6 +88,7 @@ func StartTestPeer(
 	}
 	peer := Start(
 		&Config{
+			Clock:                &mockable.Clock{},
 			Metrics:              metrics,
 			MessageCreator:       mc,
 			Log:                  logging.NoLog{},
@@ -103,6 +105,7 @@ func StartTestPeer(
 			),
 			Router:               router,
 			VersionCompatibility: version.GetCompatibility(networkID),
+			PeerPolicy:           version.NewDefaultPeerPolicy(),
 			VersionParser:        version.NewDefaultApplicationParser(),
 			MySubnets:            ids.Set{},
 			Beacons:              validators.NewSet(),
@@ -0,0 +1,152 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sort"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// IP (16 bytes) + port (2 bytes) + last connected (8 bytes) + score (8 bytes)
+const persistedPeerLen = 16 + wrappers.ShortLen + 2*wrappers.LongLen
+
+// persistedPeer is a peer this node was connected to before it was restarted
+type persistedPeer struct {
+	NodeID ids.ShortID
+	IP     utils.IPDesc
+	// LastConnected is the unix time this node last connected to the peer
+	LastConnected uint64
+	// Score rates how long the connections to the peer lasted. Each time this
+	// node disconnects from the peer, the score is halved and the number of
+	// seconds the connection lasted is added to it. This way the recent
+	// connections weigh the most.
+	Score uint64
+}
+
+func (p *persistedPeer) bytes() []byte {
+	packer := wrappers.Packer{Bytes: make([]byte, persistedPeerLen)}
+	packer.PackIP(p.IP)
+	packer.PackLong(p.LastConnected)
+	packer.PackLong(p.Score)
+	return packer.Bytes
+}
+
+func parsePersistedPeer(nodeIDBytes []byte, peerBytes []byte) (*persistedPeer, error) {
+	nodeID, err := ids.ToShortID(nodeIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	packer := wrappers.Packer{Bytes: peerBytes}
+	p := &persistedPeer{
+		NodeID:        nodeID,
+		IP:            packer.UnpackIP(),
+		LastConnected: packer.UnpackLong(),
+		Score:         packer.UnpackLong(),
+	}
+	return p, packer.Err
+}
+
+// peerStore persists the IPs of the peers this node connects to, so that they
+// can be dialed as soon as the node is restarted, instead of waiting for the
+// beacons to gossip them.
+type peerStore struct {
+	// nodeID -> persistedPeer
+	db database.Database
+	// maxPeers is the maximum number of peers kept across restarts
+	maxPeers int
+}
+
+func newPeerStore(db database.Database, maxPeers int) *peerStore {
+	return &peerStore{
+		db:       db,
+		maxPeers: maxPeers,
+	}
+}
+
+func (s *peerStore) get(nodeID ids.ShortID) (*persistedPeer, error) {
+	peerBytes, err := s.db.Get(nodeID[:])
+	if err != nil {
+		return nil, err
+	}
+	return parsePersistedPeer(nodeID[:], peerBytes)
+}
+
+// connected records that this node connected to [nodeID] at [ip] at the unix
+// time [now]
+func (s *peerStore) connected(nodeID ids.ShortID, ip utils.IPDesc, now uint64) error {
+	p, err := s.get(nodeID)
+	switch err {
+	case nil:
+	case database.ErrNotFound:
+		p = &persistedPeer{NodeID: nodeID}
+	default:
+		return err
+	}
+	p.IP = ip
+	p.LastConnected = now
+	return s.db.Put(nodeID[:], p.bytes())
+}
+
+// disconnected records that this node disconnected from [nodeID] at the unix
+// time [now]
+func (s *peerStore) disconnected(nodeID ids.ShortID, now uint64) error {
+	p, err := s.get(nodeID)
+	switch err {
+	case nil:
+	case database.ErrNotFound:
+		return nil
+	default:
+		return err
+	}
+	p.Score /= 2
+	if now > p.LastConnected {
+		p.Score += now - p.LastConnected
+	}
+	return s.db.Put(nodeID[:], p.bytes())
+}
+
+func (s *peerStore) getAll() ([]*persistedPeer, error) {
+	it := s.db.NewIterator()
+	defer it.Release()
+
+	peers := []*persistedPeer(nil)
+	for it.Next() {
+		p, err := parsePersistedPeer(it.Key(), it.Value())
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, p)
+	}
+	return peers, it.Error()
+}
+
+// load returns the best [maxPeers] persisted peers, sorted by decreasing
+// score, and forgets about the others.
+func (s *peerStore) load() ([]*persistedPeer, error) {
+	peers, err := s.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(peers, func(i, j int) bool {
+		if peers[i].Score != peers[j].Score {
+			return peers[i].Score > peers[j].Score
+		}
+		return peers[i].LastConnected > peers[j].LastConnected
+	})
+	if len(peers) <= s.maxPeers {
+		return peers, nil
+	}
+
+	for _, p := range peers[s.maxPeers:] {
+		if err := s.db.Delete(p.NodeID[:]); err != nil {
+			return nil, err
+		}
+	}
+	return peers[:s.maxPeers], nil
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+func TestPeerStore(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newPeerStore(memdb.New(), 2)
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	nodeID2 := ids.GenerateTestShortID()
+	ip0 := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	ip1 := utils.IPDesc{IP: net.IPv4(1, 2, 3, 5), Port: 9651}
+	ip2 := utils.IPDesc{IP: net.IPv4(1, 2, 3, 6), Port: 9651}
+
+	// Disconnecting from an unknown peer doesn't persist it
+	assert.NoError(s.disconnected(nodeID0, 10))
+	peers, err := s.load()
+	assert.NoError(err)
+	assert.Empty(peers)
+
+	assert.NoError(s.connected(nodeID0, ip0, 10))
+	assert.NoError(s.disconnected(nodeID0, 110))
+	assert.NoError(s.connected(nodeID0, ip1, 200))
+	assert.NoError(s.disconnected(nodeID0, 210))
+
+	p, err := s.get(nodeID0)
+	assert.NoError(err)
+	assert.Equal(ip1.String(), p.IP.String())
+	assert.EqualValues(200, p.LastConnected)
+	assert.EqualValues(100/2+10, p.Score)
+
+	assert.NoError(s.connected(nodeID1, ip1, 300))
+	assert.NoError(s.disconnected(nodeID1, 400))
+	assert.NoError(s.connected(nodeID2, ip2, 500))
+
+	// Only the best peers are kept
+	peers, err = s.load()
+	assert.NoError(err)
+	assert.Len(peers, 2)
+	assert.Equal(nodeID1, peers[0].NodeID)
+	assert.Equal(nodeID0, peers[1].NodeID)
+
+	peers, err = s.getAll()
+	assert.NoError(err)
+	assert.Len(peers, 2)
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var _ validators.SetCallbackListener = &validatorSnapshot{}
+
+// validatorSnapshots caches the validator IDs of the subnets that peers are
+// sampled from. Sampling the peers of a gossip round checks the membership of
+// every connected peer, so reading the snapshot rather than the validator
+// manager avoids taking the manager's and the set's locks once per peer.
+//
+// A snapshot is retaken once its epoch ends, or as soon as its validator set
+// reports a change.
+type validatorSnapshots struct {
+	validators validators.Manager
+	epoch      time.Duration
+	clock      *mockable.Clock
+
+	lock      sync.Mutex
+	snapshots map[ids.ID]*validatorSnapshot
+}
+
+type validatorSnapshot struct {
+	// vdrs is the set the snapshot was taken of. If the manager replaces the
+	// set of the subnet, a new snapshot is taken of the new set.
+	vdrs    validators.Set
+	takenAt time.Time
+	// stale is set by the listener of [vdrs]. It's atomic because the
+	// listener is called while [vdrs] is locked, which [get] also locks.
+	stale utils.AtomicBool
+	// nodeIDs must not be modified once the snapshot is taken, as it's shared
+	// with the callers of [get]
+	nodeIDs ids.ShortSet
+}
+
+func newValidatorSnapshots(vdrs validators.Manager, epoch time.Duration, clock *mockable.Clock) *validatorSnapshots {
+	return &validatorSnapshots{
+		validators: vdrs,
+		epoch:      epoch,
+		clock:      clock,
+		snapshots:  make(map[ids.ID]*validatorSnapshot),
+	}
+}
+
+// get returns the IDs of the validators of [subnetID]. The returned set must
+// not be modified.
+func (s *validatorSnapshots) get(subnetID ids.ID) ids.ShortSet {
+	vdrs, ok := s.validators.GetValidators(subnetID)
+	if !ok {
+		return nil
+	}
+	now := s.clock.Time()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot, ok := s.snapshots[subnetID]
+	if !ok || snapshot.vdrs != vdrs {
+		snapshot = &validatorSnapshot{vdrs: vdrs}
+		vdrs.RegisterCallbackListener(snapshot)
+		s.snapshots[subnetID] = snapshot
+	} else if !snapshot.stale.GetValue() && now.Sub(snapshot.takenAt) < s.epoch {
+		return snapshot.nodeIDs
+	}
+
+	// The flag is cleared before the validators are listed, so that a change
+	// made after they're listed marks the new snapshot as stale.
+	snapshot.stale.SetValue(false)
+	vdrList := vdrs.List()
+	snapshot.takenAt = now
+	snapshot.nodeIDs = ids.NewShortSet(len(vdrList))
+	for _, vdr := range vdrList {
+		snapshot.nodeIDs.Add(vdr.ID())
+	}
+	return snapshot.nodeIDs
+}
+
+func (s *validatorSnapshot) OnValidatorAdded(ids.ShortID, uint64) { s.stale.SetValue(true) }
+
+func (s *validatorSnapshot) OnValidatorRemoved(ids.ShortID, uint64) { s.stale.SetValue(true) }
+
+func (s *validatorSnapshot) OnValidatorWeightChanged(ids.ShortID, uint64, uint64) {
+	// Weights aren't part of the snapshot
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+func TestValidatorSnapshots(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.GenerateTestID()
+	vdrs := validators.NewManager()
+	assert.NoError(vdrs.AddWeight(subnetID, ids.ShortID{1}, 1))
+	assert.NoError(vdrs.AddWeight(subnetID, ids.ShortID{2}, 1))
+
+	clock := &mockable.Clock{}
+	clock.Set(time.Unix(1, 0))
+	snapshots := newValidatorSnapshots(vdrs, time.Minute, clock)
+
+	assert.Nil(snapshots.get(ids.GenerateTestID()))
+
+	snapshot := snapshots.get(subnetID)
+	assert.Equal(2, snapshot.Len())
+	assert.True(snapshot.Contains(ids.ShortID{1}))
+	assert.True(snapshot.Contains(ids.ShortID{2}))
+
+	// Changes of the validators are seen before the epoch ends
+	assert.NoError(vdrs.AddWeight(subnetID, ids.ShortID{3}, 1))
+	snapshot = snapshots.get(subnetID)
+	assert.True(snapshot.Contains(ids.ShortID{3}))
+
+	assert.NoError(vdrs.RemoveWeight(subnetID, ids.ShortID{3}, 1))
+	assert.NoError(vdrs.AddWeight(subnetID, ids.ShortID{4}, 1))
+	snapshot = snapshots.get(subnetID)
+	assert.False(snapshot.Contains(ids.ShortID{3}))
+	assert.True(snapshot.Contains(ids.ShortID{4}))
+
+	// Weight changes don't change the snapshot
+	assert.NoError(vdrs.AddWeight(subnetID, ids.ShortID{4}, 1))
+	assert.Equal(snapshot, snapshots.get(subnetID))
+
+	// Replacing the set of the subnet replaces the snapshot
+	newVdrs := validators.NewSet()
+	assert.NoError(newVdrs.AddWeight(ids.ShortID{5}, 1))
+	assert.NoError(vdrs.Set(subnetID, newVdrs))
+	snapshot = snapshots.get(subnetID)
+	assert.Equal(1, snapshot.Len())
+	assert.True(snapshot.Contains(ids.ShortID{5}))
+
+	clock.Set(clock.Time().Add(time.Minute))
+	snapshot = snapshots.get(subnetID)
+	assert.True(snapshot.Contains(ids.ShortID{5}))
+}
+
+func TestValidatorSnapshotsNoEpoch(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.GenerateTestID()
+	vdrs := validators.NewManager()
+	assert.NoError(vdrs.AddWeight(subnetID, ids.ShortID{1}, 1))
+
+	clock := &mockable.Clock{}
+	clock.Set(time.Unix(1, 0))
+	snapshots := newValidatorSnapshots(vdrs, 0, clock)
+
+	snapshot := snapshots.get(subnetID)
+	assert.True(snapshot.Contains(ids.ShortID{1}))
+
+	assert.NoError(vdrs.RemoveWeight(subnetID, ids.ShortID{1}, 1))
+	assert.NoError(vdrs.AddWeight(subnetID, ids.ShortID{2}, 1))
+	snapshot = snapshots.get(subnetID)
+	assert.False(snapshot.Contains(ids.ShortID{1}))
+	assert.True(snapshot.Contains(ids.ShortID{2}))
+}
+
+// BenchmarkValidatorSnapshots compares looking up the membership of the
+// connected peers in the snapshot with looking it up in the manager
+func BenchmarkValidatorSnapshots(b *testing.B) {
+	const numPeers = 1000
+
+	subnetID := ids.GenerateTestID()
+	vdrs := validators.NewManager()
+	nodeIDs := make([]ids.ShortID, numPeers)
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.GenerateTestShortID()
+		if i%2 == 0 {
+			if err := vdrs.AddWeight(subnetID, nodeIDs[i], 1); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("manager", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, nodeID := range nodeIDs {
+				vdrs.Contains(subnetID, nodeID)
+			}
+		}
+	})
+	b.Run("snapshot", func(b *testing.B) {
+		snapshots := newValidatorSnapshots(vdrs, time.Minute, &mockable.Clock{})
+		for n := 0; n < b.N; n++ {
+			snapshot := snapshots.get(subnetID)
+			for _, nodeID := range nodeIDs {
+				snapshot.Contains(nodeID)
+			}
+		}
+	})
+}
@@ -5,15 +5,22 @@ package node
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"time"
 
+	"github.com/ava-labs/avalanchego/alerts"
+	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/tieredb"
+	"github.com/ava-labs/avalanchego/failover"
+	"github.com/ava-labs/avalanchego/faults"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/utils"
@@ -21,7 +28,9 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/throttlevm"
 )
 
 type IPCConfig struct {
@@ -49,7 +58,32 @@ type HTTPConfig struct {
 	HTTPSKey     []byte `json:"-"`
 	HTTPSCert    []byte `json:"-"`
 
+	// HTTPSKeyFile and HTTPSCertFile are set if the HTTPS certificate was
+	// loaded from files, in which case it's reloaded when the files change.
+	HTTPSKeyFile  string `json:"httpsKeyFile"`
+	HTTPSCertFile string `json:"httpsCertFile"`
+
+	// HTTPSClientCAs are the CAs that must have signed the TLS certificates of
+	// the clients of the HTTPS server. If nil, clients aren't authenticated.
+	HTTPSClientCAs    *x509.CertPool `json:"-"`
+	HTTPSClientCAFile string         `json:"httpsClientCAFile"`
+	// HTTPSClientScopes maps the common name of client certificates to the
+	// endpoints they give access to. If empty, every client certificate signed
+	// by [HTTPSClientCAs] gives access to all endpoints.
+	HTTPSClientScopes map[string][]string `json:"httpsClientScopes"`
+
 	APIAllowedOrigins []string `json:"apiAllowedOrigins"`
+	// APIEndpointAllowedOrigins maps the base of a group of endpoints to the
+	// origins allowed on it, overriding [APIAllowedOrigins].
+	APIEndpointAllowedOrigins map[string][]string `json:"apiEndpointAllowedOrigins"`
+
+	// HTTPTrustedProxies are the IPs and CIDR ranges of the reverse proxies
+	// whose X-Forwarded-For header is trusted.
+	HTTPTrustedProxies []string `json:"httpTrustedProxies"`
+
+	// SlowRequestThreshold is the minimum duration of API requests that are
+	// written to the slow request log. If 0, no requests are logged as slow.
+	SlowRequestThreshold time.Duration `json:"slowRequestThreshold"`
 
 	ShutdownTimeout time.Duration `json:"shutdownTimeout"`
 	ShutdownWait    time.Duration `json:"shutdownWait"`
@@ -61,11 +95,19 @@ type APIConfig struct {
 	IPCConfig        `json:"ipcConfig"`
 
 	// Enable/Disable APIs
-	AdminAPIEnabled    bool `json:"adminAPIEnabled"`
-	InfoAPIEnabled     bool `json:"infoAPIEnabled"`
-	KeystoreAPIEnabled bool `json:"keystoreAPIEnabled"`
-	MetricsAPIEnabled  bool `json:"metricsAPIEnabled"`
-	HealthAPIEnabled   bool `json:"healthAPIEnabled"`
+	AdminAPIEnabled      bool `json:"adminAPIEnabled"`
+	InfoAPIEnabled       bool `json:"infoAPIEnabled"`
+	KeystoreAPIEnabled   bool `json:"keystoreAPIEnabled"`
+	MetricsAPIEnabled    bool `json:"metricsAPIEnabled"`
+	HealthAPIEnabled     bool `json:"healthAPIEnabled"`
+	EvidenceAPIEnabled   bool `json:"evidenceAPIEnabled"`
+	NetworkingAPIEnabled bool `json:"networkingAPIEnabled"`
+	WalletAPIEnabled     bool `json:"walletAPIEnabled"`
+	DocAPIEnabled        bool `json:"docAPIEnabled"`
+
+	// WalletScanFrequency is the time between the wallet's scans of the UTXOs
+	// of the keystore users it tracks
+	WalletScanFrequency time.Duration `json:"walletScanFrequency"`
 }
 
 type IPConfig struct {
@@ -96,6 +138,14 @@ type BootstrapConfig struct {
 	// Max number of times to retry bootstrap before warning the node operator
 	RetryBootstrapWarnFrequency int `json:"retryBootstrapWarnFrequency"`
 
+	// If true, chains never finish bootstrapping, so this node only serves
+	// bootstrapping requests and peer lists
+	BootstrapOnly bool `json:"bootstrapOnly"`
+
+	// Fraction of the beacons' stake that must have accepted a container in
+	// the accepted frontier for bootstrapping to sync to it
+	BootstrapFrontierQuorum float64 `json:"bootstrapFrontierQuorum"`
+
 	// Timeout when connecting to bootstrapping beacons
 	BootstrapBeaconConnectionTimeout time.Duration `json:"bootstrapBeaconConnectionTimeout"`
 
@@ -110,6 +160,13 @@ type BootstrapConfig struct {
 	// ancestors while responding to a GetAncestors message
 	BootstrapMaxTimeGetAncestors time.Duration `json:"bootstrapMaxTimeGetAncestors"`
 
+	// If non-empty, snowman chains import the era archives in this directory
+	// before bootstrapping
+	BootstrapEraImportDir string `json:"bootstrapEraImportDir"`
+
+	// Bytes of jobs that each bootstrapping job queue holds in memory
+	BootstrapJobsMemoryBudget uint64 `json:"bootstrapJobsMemoryBudget"`
+
 	BootstrapIDs []ids.ShortID  `json:"bootstrapIDs"`
 	BootstrapIPs []utils.IPDesc `json:"bootstrapIPs"`
 }
@@ -125,6 +182,22 @@ type DatabaseConfig struct {
 	Config []byte `json:"-"`
 }
 
+// ColdStorageConfig describes when the accepted blocks of the snowman chains
+// are offloaded to an object storage
+type ColdStorageConfig struct {
+	// S3 is the bucket the blocks are offloaded to. If its endpoint is empty,
+	// blocks are never offloaded.
+	S3 tieredb.S3Config `json:"s3"`
+
+	// Depth is the number of accepted blocks of each chain kept locally below
+	// its last accepted block
+	Depth uint64 `json:"depth"`
+
+	// CacheSize is the number of offloaded blocks of each chain kept in
+	// memory once read
+	CacheSize int `json:"cacheSize"`
+}
+
 // Config contains all of the configurations of an Avalanche node.
 type Config struct {
 	HTTPConfig          `json:"httpConfig"`
@@ -134,6 +207,8 @@ type Config struct {
 	BootstrapConfig     `json:"bootstrapConfig"`
 	DatabaseConfig      `json:"databaseConfig"`
 
+	ColdStorageConfig ColdStorageConfig `json:"coldStorageConfig"`
+
 	// Genesis information
 	GenesisBytes []byte `json:"-"`
 	AvaxAssetID  ids.ID `json:"avaxAssetID"`
@@ -175,15 +250,47 @@ type Config struct {
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters `json:"consensusParams"`
 
+	// ReadOnlyReplica is true if this node follows consensus without ever
+	// building blocks or voting on blocks that haven't been decided
+	ReadOnlyReplica bool `json:"readOnlyReplica"`
+
+	// FailoverConfig describes the active/standby pair this node is part of
+	FailoverConfig failover.Config `json:"failoverConfig"`
+
+	// VMThrottlerConfig limits the CPU time each snowman VM spends in block
+	// building, parsing and verification
+	VMThrottlerConfig throttlevm.Config `json:"vmThrottlerConfig"`
+
 	// Metrics
 	MeterVMEnabled bool `json:"meterVMEnabled"`
 
+	// MetricsPushConfig describes where this node's metrics are pushed to
+	MetricsPushConfig metrics.PushConfig `json:"metricsPushConfig"`
+
+	// AlertsConfig describes what this node alerts about and where the alerts
+	// are sent
+	AlertsConfig alerts.Config `json:"alertsConfig"`
+
+	// Number of accepted blocks cached in front of the snowman VMs
+	DecidedBlockCacheSize int `json:"decidedBlockCacheSize"`
+
 	// Router that is used to handle incoming consensus messages
 	ConsensusRouter          router.Router       `json:"-"`
 	RouterHealthConfig       router.HealthConfig `json:"routerHealthConfig"`
 	ConsensusShutdownTimeout time.Duration       `json:"consensusShutdownTimeout"`
+	// Path to the file containing the per-subnet message policies. If empty,
+	// no policies are applied.
+	RouterMessagePolicyFile string `json:"routerMessagePolicyFile"`
+	// Configures how the messages dropped by the router, the chain handlers
+	// and the chain senders are reported
+	DroppedMessagesConfig drops.Config `json:"droppedMessagesConfig"`
 	// Gossip a container in the accepted frontier every [ConsensusGossipFrequency]
 	ConsensusGossipFrequency time.Duration `json:"consensusGossipFreq"`
+	// Batch the queries sent to the same validator by snowman chains
+	ConsensusBatchQueriesEnabled bool `json:"consensusBatchQueriesEnabled"`
+	// If non-zero, snowman chains that have blocks processing but haven't
+	// accepted a block for this long are reported unhealthy
+	ConsensusFinalityStallTimeout time.Duration `json:"consensusFinalityStallTimeout"`
 
 	// Subnet Whitelist
 	WhitelistedSubnets ids.Set `json:"whitelistedSubnets"`
@@ -199,4 +306,30 @@ type Config struct {
 
 	// Reset proposerVM height index
 	ResetProposerVMHeightIndex bool `json:"resetProposerVMHeightIndex"`
+
+	// Stop building blocks if another node is detected proposing blocks with
+	// this node's staking key
+	StopProposingOnDuplicateIdentity bool `json:"stopProposingOnDuplicateIdentity"`
+
+	// Timestamp built blocks with the median of the times recently reported
+	// by peers rather than with the local time
+	ProposerVMUsePeerTime bool `json:"proposerVMUsePeerTime"`
+
+	// Make expensive runtime assertions in consensus, the proposervm and the
+	// chain databases
+	InvariantChecks bool `json:"invariantChecks"`
+
+	// Clock tells the time of the node. The network, the request timeouts and
+	// the snowman chains tell time with it. If nil, the local clock is used.
+	// Tests set it to control time-dependent behavior.
+	Clock *mockable.Clock `json:"-"`
+
+	// ID or alias of the chain whose accepted blocks are replayed when the
+	// node starts, and height from which they're compared in detail
+	ReplayChain      string `json:"replayChain"`
+	ReplayFromHeight uint64 `json:"replayFromHeight"`
+
+	// Faults injected into the node. Only supported by builds with the
+	// faultinjection tag.
+	FaultInjectionScript *faults.Script `json:"faultInjectionScript"`
 }
@@ -4,12 +4,16 @@
 package node
 
 import (
+	"context"
 	"crypto"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +24,7 @@ import (
 
 	coreth "github.com/ava-labs/coreth/plugin/evm"
 
+	"github.com/ava-labs/avalanchego/alerts"
 	"github.com/ava-labs/avalanchego/api/admin"
 	"github.com/ava-labs/avalanchego/api/auth"
 	"github.com/ava-labs/avalanchego/api/health"
@@ -32,6 +37,8 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/failover"
+	"github.com/ava-labs/avalanchego/faults"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/indexer"
@@ -39,10 +46,13 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/lightclient"
 	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/evidence"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/triggers"
@@ -56,21 +66,41 @@ import (
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/cachevm"
+	"github.com/ava-labs/avalanchego/vms/ed25519fx"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/avalanchego/vms/propertyfx"
 	"github.com/ava-labs/avalanchego/vms/registry"
+	"github.com/ava-labs/avalanchego/vms/regulatedfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/vms/vestingfx"
 
+	evidenceapi "github.com/ava-labs/avalanchego/api/evidence"
 	ipcsapi "github.com/ava-labs/avalanchego/api/ipcs"
+	networkingapi "github.com/ava-labs/avalanchego/api/networking"
+	walletapi "github.com/ava-labs/avalanchego/api/wallet"
+)
+
+const (
+	// The stake-weighted median time reported by primary network validators
+	// is only used once at least [peerTimeMinPeers] validators reported their
+	// time. Only the times reported by the [peerTimeMaxPeers] validators that
+	// reported most recently are considered.
+	peerTimeMinPeers = 5
+	peerTimeMaxPeers = 256
 )
 
 var (
 	genesisHashKey  = []byte("genesisID")
 	indexerDBPrefix = []byte{0x00}
+	peersDBPrefix   = []byte("peers")
+	bansDBPrefix    = []byte("bans")
+	aliasesDBPrefix = []byte("chainAliases")
 
 	errInvalidTLSKey = errors.New("invalid TLS key")
 	errShuttingDown  = errors.New("server shutting down")
@@ -98,9 +128,22 @@ type Node struct {
 	// Handles calls to Keystore API
 	keystore keystore.Keystore
 
+	// Builds and issues txs for keystore users
+	wallet walletapi.Wallet
+
 	// Manages shared memory
 	sharedMemory atomic.Memory
 
+	// Stores evidence of misbehavior by other nodes
+	evidence evidence.Store
+
+	// Tracks the messages dropped by the router, the chain handlers and the
+	// chain senders
+	drops drops.Tracker
+
+	// Activation times of the network upgrades of this node's network
+	upgrades *version.UpgradeSchedule
+
 	// Monitors node health and runs health checks
 	health health.Health
 
@@ -110,6 +153,9 @@ type Node struct {
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
+	// Chain aliases given by the operator, which are restored on restart
+	persistedAliases *chains.PersistedAliases
+
 	// Manages validator benching
 	benchlistManager benchlist.Manager
 
@@ -125,6 +171,24 @@ type Node struct {
 	networkNamespace string
 	Net              network.Network
 
+	// Serves chain headers to light clients. Nil if disabled.
+	lightClientServer lightclient.Server
+
+	// Records the times reported by peers. Nil if blocks aren't timestamped
+	// with the time reported by peers.
+	peerTime *timer.PeerTime
+
+	// Decides whether this node is the active node of its failover pair. Nil
+	// if failover is disabled.
+	failover failover.Coordinator
+
+	// Pushes this node's metrics. Nil if metrics aren't pushed.
+	metricsPusher metrics.Pusher
+
+	// Notifies a webhook about this node's alerts. Nil if alerting is
+	// disabled.
+	alerter alerts.Alerter
+
 	// this node's initial connections to the network
 	beacons validators.Set
 
@@ -165,7 +229,10 @@ type Node struct {
  */
 
 func (n *Node) initNetworking() error {
-	listener, err := net.Listen(constants.NetworkType, fmt.Sprintf(":%d", n.Config.IP.Port))
+	listenConfig := net.ListenConfig{
+		KeepAlive: dialer.KeepAlive(n.Config.NetworkConfig.DialerConfig.KeepAlivePeriod),
+	}
+	listener, err := listenConfig.Listen(context.Background(), constants.NetworkType, fmt.Sprintf(":%d", n.Config.IP.Port))
 	if err != nil {
 		return err
 	}
@@ -256,6 +323,20 @@ func (n *Node) initNetworking() error {
 	n.Config.NetworkConfig.WhitelistedSubnets = n.Config.WhitelistedSubnets
 	n.Config.NetworkConfig.UptimeCalculator = n.uptimeCalculator
 	n.Config.NetworkConfig.UptimeRequirement = n.Config.UptimeRequirement
+	n.Config.NetworkConfig.PeerDB = prefixdb.New(peersDBPrefix, n.DB)
+	n.Config.NetworkConfig.BanDB = prefixdb.New(bansDBPrefix, n.DB)
+	n.Config.NetworkConfig.Clock = n.Config.Clock
+	n.Config.NetworkConfig.Upgrades = n.upgrades
+
+	if n.Config.ProposerVMUsePeerTime {
+		n.peerTime = timer.NewPeerTime(primaryNetworkValidators, peerTimeMinPeers, peerTimeMaxPeers, n.Config.Clock)
+		n.Config.NetworkConfig.PeerTime = n.peerTime
+	}
+
+	if n.Config.NetworkConfig.LightClientConfig.Enabled {
+		n.lightClientServer = lightclient.NewServer(n.Log, n.Config.NetworkConfig.LightClientConfig.MaxHeadersPerRequest)
+		n.Config.NetworkConfig.LightClientServer = n.lightClientServer
+	}
 
 	n.Net, err = network.NewNetwork(
 		&n.Config.NetworkConfig,
@@ -336,10 +417,14 @@ func (n *Node) Dispatch() error {
 	// Start the HTTP API server
 	go n.Log.RecoverAndPanic(func() {
 		var err error
-		if n.Config.HTTPSEnabled {
+		switch {
+		case n.Config.HTTPSEnabled && n.Config.HTTPSCertFile != "":
+			n.Log.Debug("initializing API server with TLS from files")
+			err = n.APIServer.DispatchTLSFromFiles(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile, n.Config.HTTPSClientCAs)
+		case n.Config.HTTPSEnabled:
 			n.Log.Debug("initializing API server with TLS")
-			err = n.APIServer.DispatchTLS(n.Config.HTTPSCert, n.Config.HTTPSKey)
-		} else {
+			err = n.APIServer.DispatchTLS(n.Config.HTTPSCert, n.Config.HTTPSKey, n.Config.HTTPSClientCAs)
+		default:
 			n.Log.Debug("initializing API server without TLS")
 			err = n.APIServer.Dispatch()
 		}
@@ -354,6 +439,18 @@ func (n *Node) Dispatch() error {
 		n.Shutdown(1)
 	})
 
+	if n.failover != nil {
+		go n.Log.RecoverAndPanic(n.failover.Dispatch)
+	}
+
+	if n.metricsPusher != nil {
+		go n.Log.RecoverAndPanic(n.metricsPusher.Dispatch)
+	}
+
+	if n.alerter != nil {
+		go n.Log.RecoverAndPanic(n.alerter.Dispatch)
+	}
+
 	// Add bootstrap nodes to the peer network
 	for i, peerIP := range n.Config.BootstrapIPs {
 		n.Net.ManuallyTrack(n.Config.BootstrapIDs[i], peerIP)
@@ -486,6 +583,33 @@ func (n *Node) initAPIServer() error {
 	n.Log.Info("initializing API server")
 	n.APIServer = server.New()
 
+	trustedProxies, err := server.ParseTrustedProxies(n.Config.HTTPTrustedProxies)
+	if err != nil {
+		return err
+	}
+	// The client's address must be known before any other wrapper sees the
+	// request, so this wrapper is applied last.
+	forwardedFor := server.NewForwardedForWrapper(trustedProxies)
+
+	slowLog, err := n.LogFactory.Make("api-slow")
+	if err != nil {
+		return fmt.Errorf("couldn't create slow API request log: %w", err)
+	}
+	tracer := server.NewRequestTracer(n.Log, slowLog, n.Config.SlowRequestThreshold)
+
+	apiMetrics, err := server.NewMetricsWrapper("api", n.MetricsRegisterer)
+	if err != nil {
+		return fmt.Errorf("couldn't create API metrics: %w", err)
+	}
+
+	// Each wrapper wraps the previous ones, so the client certificate is
+	// checked before the auth token
+	wrappers := []server.Wrapper{apiMetrics, tracer, forwardedFor}
+	if len(n.Config.HTTPSClientScopes) > 0 {
+		n.Log.Info("API client certificates are mapped to the endpoints they give access to")
+		wrappers = append([]server.Wrapper{auth.NewClientCertWrapper(n.Config.HTTPSClientScopes)}, wrappers...)
+	}
+
 	if !n.Config.APIRequireAuthToken {
 		n.APIServer.Initialize(
 			n.Log,
@@ -493,8 +617,10 @@ func (n *Node) initAPIServer() error {
 			n.Config.HTTPHost,
 			n.Config.HTTPPort,
 			n.Config.APIAllowedOrigins,
+			n.Config.APIEndpointAllowedOrigins,
 			n.Config.ShutdownTimeout,
 			n.ID,
+			wrappers...,
 		)
 		return nil
 	}
@@ -510,9 +636,10 @@ func (n *Node) initAPIServer() error {
 		n.Config.HTTPHost,
 		n.Config.HTTPPort,
 		n.Config.APIAllowedOrigins,
+		n.Config.APIEndpointAllowedOrigins,
 		n.Config.ShutdownTimeout,
 		n.ID,
-		a,
+		append([]server.Wrapper{a}, wrappers...)...,
 	)
 
 	// only create auth service if token authorization is required
@@ -568,6 +695,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	)
 
 	// Manages network timeouts
+	n.Config.AdaptiveTimeoutConfig.Clock = n.Config.Clock
 	timeoutManager, err := timeout.NewManager(
 		&n.Config.AdaptiveTimeoutConfig,
 		n.benchlistManager,
@@ -589,12 +717,25 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		criticalChains,
 		n.Shutdown,
 		n.Config.RouterHealthConfig,
+		n.drops,
 		"requests",
 		n.MetricsRegisterer,
 	)
 	if err != nil {
 		return fmt.Errorf("couldn't initialize chain router: %w", err)
 	}
+	if err := n.loadMessagePolicies(); err != nil {
+		return err
+	}
+
+	// Caches accepted blocks in front of the snowman VMs
+	var decidedBlocks *cachevm.DecidedBlocks
+	if n.Config.DecidedBlockCacheSize > 0 {
+		decidedBlocks, err = cachevm.NewDecidedBlocks(n.Config.DecidedBlockCacheSize, "decided_block_cache", n.MetricsRegisterer)
+		if err != nil {
+			return err
+		}
+	}
 
 	n.chainManager = chains.New(&chains.ManagerConfig{
 		StakingEnabled:                          n.Config.EnableStaking,
@@ -615,6 +756,8 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		Server:                                  n.APIServer,
 		Keystore:                                n.keystore,
 		AtomicMemory:                            &n.sharedMemory,
+		Evidence:                                n.evidence,
+		Drops:                                   n.drops,
 		AVAXAssetID:                             avaxAssetID,
 		XChainID:                                xChainID,
 		CriticalChains:                          criticalChains,
@@ -623,23 +766,44 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		WhitelistedSubnets:                      n.Config.WhitelistedSubnets,
 		RetryBootstrap:                          n.Config.RetryBootstrap,
 		RetryBootstrapWarnFrequency:             n.Config.RetryBootstrapWarnFrequency,
+		BootstrapOnly:                           n.Config.BootstrapOnly,
+		BootstrapFrontierQuorum:                 n.Config.BootstrapFrontierQuorum,
 		ShutdownNodeFunc:                        n.Shutdown,
 		MeterVMEnabled:                          n.Config.MeterVMEnabled,
+		VMThrottlerConfig:                       n.Config.VMThrottlerConfig,
+		DecidedBlocks:                           decidedBlocks,
+		LightClientServer:                       n.lightClientServer,
+		IsReadOnly:                              n.isReadOnly,
 		Metrics:                                 n.MetricsGatherer,
 		SubnetConfigs:                           n.Config.SubnetConfigs,
 		ChainConfigs:                            n.Config.ChainConfigs,
 		ConsensusGossipFrequency:                n.Config.ConsensusGossipFrequency,
+		ConsensusBatchQueriesEnabled:            n.Config.ConsensusBatchQueriesEnabled,
+		FinalityStallTimeout:                    n.Config.ConsensusFinalityStallTimeout,
 		GossipConfig:                            n.Config.GossipConfig,
 		BootstrapMaxTimeGetAncestors:            n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapAncestorsMaxContainersSent:     n.Config.BootstrapAncestorsMaxContainersSent,
 		BootstrapAncestorsMaxContainersReceived: n.Config.BootstrapAncestorsMaxContainersReceived,
-		ApricotPhase4Time:                       version.GetApricotPhase4Time(n.Config.NetworkID),
+		Upgrades:                                n.upgrades,
 		ApricotPhase4MinPChainHeight:            version.GetApricotPhase4MinPChainHeight(n.Config.NetworkID),
 		ResetProposerVMHeightIndex:              n.Config.ResetProposerVMHeightIndex,
+		StopProposingOnDuplicateIdentity:        n.Config.StopProposingOnDuplicateIdentity,
+		PeerTime:                                n.peerTime,
+		EraImportDir:                            n.Config.BootstrapEraImportDir,
+		BootstrapJobsMemoryBudget:               int(n.Config.BootstrapJobsMemoryBudget),
+		InvariantChecks:                         n.Config.InvariantChecks,
+		Clock:                                   n.Config.Clock,
+		ReplayChain:                             n.Config.ReplayChain,
+		ReplayFromHeight:                        n.Config.ReplayFromHeight,
+		ColdStorageS3:                           n.Config.ColdStorageConfig.S3,
+		ColdStorageDepth:                        n.Config.ColdStorageConfig.Depth,
+		ColdStorageCacheSize:                    n.Config.ColdStorageConfig.CacheSize,
 	})
 
 	// Notify the API server when new chains are created
 	n.chainManager.AddRegistrant(n.APIServer)
+	n.APIServer.SetChainAliases(n.chainManager)
+	n.persistedAliases = chains.NewPersistedAliases(n.chainManager, prefixdb.New(aliasesDBPrefix, n.DB))
 	return nil
 }
 
@@ -683,9 +847,11 @@ func (n *Node) initVMs() error {
 			MinStakeDuration:       n.Config.MinStakeDuration,
 			MaxStakeDuration:       n.Config.MaxStakeDuration,
 			RewardConfig:           n.Config.RewardConfig,
-			ApricotPhase3Time:      version.GetApricotPhase3Time(n.Config.NetworkID),
-			ApricotPhase4Time:      version.GetApricotPhase4Time(n.Config.NetworkID),
-			ApricotPhase5Time:      version.GetApricotPhase5Time(n.Config.NetworkID),
+			ChurnConfig:            n.Config.ChurnConfig,
+			ApricotPhase3Time:      n.upgrades.ActivationTime(version.ApricotPhase3),
+			ApricotPhase4Time:      n.upgrades.ActivationTime(version.ApricotPhase4),
+			ApricotPhase5Time:      n.upgrades.ActivationTime(version.ApricotPhase5),
+			ApricotPhase6Time:      n.upgrades.ActivationTime(version.ApricotPhase6),
 		}),
 		vmRegisterer.Register(constants.AVMID, &avm.Factory{
 			TxFee:            n.Config.TxFee,
@@ -695,6 +861,9 @@ func (n *Node) initVMs() error {
 		n.Config.VMManager.RegisterFactory(secp256k1fx.ID, &secp256k1fx.Factory{}),
 		n.Config.VMManager.RegisterFactory(nftfx.ID, &nftfx.Factory{}),
 		n.Config.VMManager.RegisterFactory(propertyfx.ID, &propertyfx.Factory{}),
+		n.Config.VMManager.RegisterFactory(regulatedfx.ID, &regulatedfx.Factory{}),
+		n.Config.VMManager.RegisterFactory(vestingfx.ID, &vestingfx.Factory{}),
+		n.Config.VMManager.RegisterFactory(ed25519fx.ID, &ed25519fx.Factory{}),
 	)
 	if errs.Errored() {
 		return errs.Err
@@ -725,6 +894,117 @@ func (n *Node) initSharedMemory() error {
 	return n.sharedMemory.Initialize(n.Log, sharedMemoryDB)
 }
 
+// initFaultInjection wraps the consensus router and the current database so
+// that they inject the faults of the configured script, if there is one.
+// Assumes n.DBManager is already set
+func (n *Node) initFaultInjection() error {
+	if n.Config.FaultInjectionScript == nil {
+		return nil
+	}
+	n.Log.Warn("injecting faults into the node, which must only be done in tests")
+	injector, err := faults.NewInjector(*n.Config.FaultInjectionScript)
+	if err != nil {
+		return err
+	}
+	n.Config.ConsensusRouter = faults.NewRouter(n.Config.ConsensusRouter, injector)
+
+	dbs := n.DBManager.GetDatabases()
+	faultDBs := make([]*manager.VersionedDatabase, len(dbs))
+	copy(faultDBs, dbs)
+	faultDBs[0] = &manager.VersionedDatabase{
+		Database: faults.NewDatabase(dbs[0].Database, injector),
+		Version:  dbs[0].Version,
+	}
+	n.DBManager, err = manager.NewManagerFromDBs(faultDBs)
+	if err != nil {
+		return err
+	}
+	n.DB = n.DBManager.Current().Database
+	return nil
+}
+
+// initFailover initializes the coordination with the other node of this
+// node's failover pair, if there is one.
+// Assumes n.APIServer is already set
+func (n *Node) initFailover() error {
+	if !n.Config.FailoverConfig.Enabled {
+		return nil
+	}
+	n.Log.Info("initializing failover with peer %s", n.Config.FailoverConfig.PeerURI)
+	n.failover = failover.New(n.Log, n.Config.FailoverConfig, n.ID)
+	handler := &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     n.failover,
+	}
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, failover.Endpoint, "")
+}
+
+// isReadOnly returns true if this node shouldn't currently affect consensus
+func (n *Node) isReadOnly() bool {
+	return n.Config.ReadOnlyReplica || (n.failover != nil && !n.failover.IsActive())
+}
+
+// initEvidenceAPI initializes the evidence store and, if enabled, the API
+// used to query it.
+// Assumes n.APIServer is already set
+func (n *Node) initEvidenceAPI() error {
+	n.Log.Info("initializing evidence store")
+	evidenceStore, err := evidence.NewStore(prefixdb.New([]byte("evidence"), n.DB))
+	if err != nil {
+		return err
+	}
+	n.evidence = evidenceStore
+	if !n.Config.EvidenceAPIEnabled {
+		n.Log.Info("skipping evidence API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing evidence API")
+	handler, err := evidenceapi.NewService(n.Log, n.evidence)
+	if err != nil {
+		return err
+	}
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "evidence", "")
+}
+
+// initDocAPI serves the descriptions of the JSON-RPC services added to the API
+// server. Services added later, such as those of chains, are described once
+// they're added.
+// Assumes n.APIServer is already set
+func (n *Node) initDocAPI() error {
+	if !n.Config.DocAPIEnabled {
+		n.Log.Info("skipping doc API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing doc API")
+	handler := &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     n.APIServer.Documentation(),
+	}
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "doc", "")
+}
+
+// initNetworkingAPI initializes the tracker of dropped messages and, if
+// enabled, the API used to query it.
+// Assumes n.APIServer is already set
+func (n *Node) initNetworkingAPI() error {
+	n.Log.Info("initializing dropped messages tracker")
+	var err error
+	n.drops, err = drops.NewTracker(n.Log, n.Config.DroppedMessagesConfig, "router", n.MetricsRegisterer)
+	if err != nil {
+		return err
+	}
+	if !n.Config.NetworkingAPIEnabled {
+		n.Log.Info("skipping networking API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing networking API")
+	handler, err := networkingapi.NewService(n.Log, n.drops)
+	if err != nil {
+		return err
+	}
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "networking", "")
+}
+
 // initKeystoreAPI initializes the keystore service, which is an on-node wallet.
 // Assumes n.APIServer is already set
 func (n *Node) initKeystoreAPI() error {
@@ -747,13 +1027,60 @@ func (n *Node) initKeystoreAPI() error {
 	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "keystore", "")
 }
 
-// initMetricsAPI initializes the Metrics API
-// Assumes n.APIServer is already set
-func (n *Node) initMetricsAPI() error {
+// initWalletAPI initializes the wallet service, which builds and issues txs on
+// the P-chain and X-chain for keystore users.
+// Assumes n.keystore and n.APIServer are already set
+func (n *Node) initWalletAPI() error {
+	if !n.Config.WalletAPIEnabled {
+		n.Log.Info("skipping wallet API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing wallet API")
+
+	createAVMTx, err := genesis.VMGenesis(n.Config.GenesisBytes, constants.AVMID)
+	if err != nil {
+		return err
+	}
+	xChainID := createAVMTx.ID()
+
+	// Txs are issued through this node's API server
+	scheme := "http"
+	if n.Config.HTTPSEnabled {
+		scheme = "https"
+	}
+	host := n.Config.HTTPHost
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	uri := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(int(n.Config.HTTPPort))))
+
+	n.wallet = walletapi.New(walletapi.Config{
+		Log:           n.Log,
+		Keystore:      n.keystore,
+		XChainID:      xChainID,
+		URI:           uri,
+		ScanFrequency: n.Config.WalletScanFrequency,
+	})
+	handler, err := walletapi.NewService(n.Log, n.wallet, xChainID)
+	if err != nil {
+		return err
+	}
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "wallet", "")
+}
+
+// initMetrics initializes the registry that the node's metrics are
+// registered with
+func (n *Node) initMetrics() {
 	n.MetricsRegisterer = prometheus.NewRegistry()
 	n.MetricsGatherer = metrics.NewMultiGatherer()
+}
 
-	if !n.Config.MetricsAPIEnabled {
+// initMetricsAPI initializes the Metrics API
+// Assumes n.APIServer and n.MetricsRegisterer are already set
+func (n *Node) initMetricsAPI() error {
+	pushEnabled := n.Config.MetricsPushConfig.URL != ""
+	alertsEnabled := n.Config.AlertsConfig.WebhookURL != ""
+	if !n.Config.MetricsAPIEnabled && !pushEnabled && !alertsEnabled {
 		n.Log.Info("skipping metrics API initialization because it has been disabled")
 		return nil
 	}
@@ -762,14 +1089,27 @@ func (n *Node) initMetricsAPI() error {
 		return err
 	}
 
-	n.Log.Info("initializing metrics API")
-
 	meterDBManager, err := n.DBManager.NewMeterDBManager("db", n.MetricsRegisterer)
 	if err != nil {
 		return err
 	}
 	n.DBManager = meterDBManager
 
+	if pushEnabled {
+		n.Log.Info("pushing metrics to %s every %s", n.Config.MetricsPushConfig.URL, n.Config.MetricsPushConfig.Interval)
+		n.metricsPusher, err = metrics.NewPusher(n.Log, n.Config.MetricsPushConfig, n.MetricsGatherer)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !n.Config.MetricsAPIEnabled {
+		n.Log.Info("skipping metrics API initialization because it has been disabled")
+		return nil
+	}
+
+	n.Log.Info("initializing metrics API")
+
 	return n.APIServer.AddRoute(
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
@@ -802,12 +1142,17 @@ func (n *Node) initAdminAPI() error {
 		admin.Config{
 			Log:          n.Log,
 			ChainManager: n.chainManager,
+			ChainAliases: n.persistedAliases,
+			AtomicMemory: &n.sharedMemory,
 			HTTPServer:   n.APIServer,
 			ProfileDir:   n.Config.ProfilerConfig.Dir,
 			LogFactory:   n.LogFactory,
 			NodeConfig:   n.Config,
 			VMManager:    n.Config.VMManager,
 			VMRegistry:   n.VMRegistry,
+			Network:      n.Net,
+
+			MessagePolicyReloader: n.loadMessagePolicies,
 		},
 	)
 	if err != nil {
@@ -816,6 +1161,28 @@ func (n *Node) initAdminAPI() error {
 	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "")
 }
 
+// loadMessagePolicies reads the per-subnet message policies from
+// [n.Config.RouterMessagePolicyFile] and replaces the router's policies with
+// them.
+func (n *Node) loadMessagePolicies() error {
+	if n.Config.RouterMessagePolicyFile == "" {
+		return nil
+	}
+	policyBytes, err := os.ReadFile(filepath.Clean(n.Config.RouterMessagePolicyFile))
+	if err != nil {
+		return fmt.Errorf("couldn't read message policy file: %w", err)
+	}
+	policies := map[ids.ID]router.MessagePolicy{}
+	if err := json.Unmarshal(policyBytes, &policies); err != nil {
+		return fmt.Errorf("couldn't parse message policy file: %w", err)
+	}
+	if err := n.Config.ConsensusRouter.SetMessagePolicies(policies); err != nil {
+		return err
+	}
+	n.Log.Info("loaded message policies for %d subnets", len(policies))
+	return nil
+}
+
 // initProfiler initializes the continuous profiling
 func (n *Node) initProfiler() {
 	if !n.Config.ProfilerConfig.Enabled {
@@ -857,6 +1224,8 @@ func (n *Node) initInfoAPI() error {
 			CreateSubnetTxFee:     n.Config.CreateSubnetTxFee,
 			CreateBlockchainTxFee: n.Config.CreateBlockchainTxFee,
 			VMManager:             n.Config.VMManager,
+			Upgrades:              n.upgrades,
+			PeerPolicy:            n.Config.NetworkConfig.PeerPolicy,
 		},
 		n.Log,
 		n.chainManager,
@@ -875,6 +1244,28 @@ func (n *Node) initInfoAPI() error {
 
 // initHealthAPI initializes the Health API service
 // Assumes n.Log, n.Net, n.APIServer, n.HTTPLog already initialized
+// initAlerter creates the alerter if a webhook is configured
+// Assumes n.health and n.MetricsGatherer are already set
+func (n *Node) initAlerter() error {
+	if n.Config.AlertsConfig.WebhookURL == "" {
+		return nil
+	}
+
+	n.Log.Info("sending %s alerts every %s", n.Config.AlertsConfig.Format, n.Config.AlertsConfig.CheckFrequency)
+	alerter, err := alerts.New(
+		n.Log,
+		n.Config.AlertsConfig,
+		n.ID,
+		n.health,
+		n.MetricsGatherer,
+	)
+	if err != nil {
+		return err
+	}
+	n.alerter = alerter
+	return nil
+}
+
 func (n *Node) initHealthAPI() error {
 	healthChecker, err := health.New(n.MetricsRegisterer)
 	if err != nil {
@@ -882,12 +1273,13 @@ func (n *Node) initHealthAPI() error {
 	}
 	n.health = healthChecker
 
-	if !n.Config.HealthAPIEnabled {
+	// The alerter evaluates the health checks even if the API is disabled
+	alertsEnabled := n.Config.AlertsConfig.WebhookURL != ""
+	if !n.Config.HealthAPIEnabled && !alertsEnabled {
 		n.Log.Info("skipping health API initialization because it has been disabled")
 		return nil
 	}
 
-	n.Log.Info("initializing Health API")
 	err = healthChecker.RegisterHealthCheck("network", n.Net)
 	if err != nil {
 		return fmt.Errorf("couldn't register network health check: %w", err)
@@ -898,6 +1290,13 @@ func (n *Node) initHealthAPI() error {
 		return fmt.Errorf("couldn't register router health check: %w", err)
 	}
 
+	if !n.Config.HealthAPIEnabled {
+		n.Log.Info("skipping health API initialization because it has been disabled")
+		return nil
+	}
+
+	n.Log.Info("initializing Health API")
+
 	handler, err := health.NewGetAndPostHandler(n.Log, healthChecker)
 	if err != nil {
 		return err
@@ -983,7 +1382,8 @@ func (n *Node) initChainAliases(genesisBytes []byte) error {
 			}
 		}
 	}
-	return nil
+	// The genesis aliases take precedence over the persisted ones
+	return n.persistedAliases.Restore(n.Log)
 }
 
 // APIs aliases as specified by the genesis information
@@ -995,6 +1395,9 @@ func (n *Node) initAPIAliases(genesisBytes []byte) error {
 	}
 
 	for url, aliases := range apiAliases {
+		// Aliases under bc/ are resolved from the chain manager's aliases when
+		// a request is routed, so they follow changes made at runtime.
+		aliases = filterChainAliases(aliases)
 		if err := n.APIServer.AddAliases(url, aliases...); err != nil {
 			return err
 		}
@@ -1002,6 +1405,16 @@ func (n *Node) initAPIAliases(genesisBytes []byte) error {
 	return nil
 }
 
+func filterChainAliases(aliases []string) []string {
+	filtered := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		if !strings.HasPrefix(alias, constants.ChainAliasPrefix) {
+			filtered = append(filtered, alias)
+		}
+	}
+	return filtered
+}
+
 // Initialize this node
 func (n *Node) Initialize(
 	config *Config,
@@ -1011,9 +1424,13 @@ func (n *Node) Initialize(
 ) error {
 	n.Log = logger
 	n.Config = config
+	if n.Config.Clock == nil {
+		n.Config.Clock = &mockable.Clock{}
+	}
 	var err error
 	n.ID = peer.CertToID(n.Config.StakingTLSCert.Leaf)
 	n.LogFactory = logFactory
+	n.upgrades = version.GetUpgradeSchedule(n.Config.NetworkID)
 	n.DoneShuttingDown.Add(1)
 	n.Log.Info("node version is: %s", version.CurrentApp)
 	n.Log.Info("node ID is: %s", n.ID.PrefixedString(constants.NodeIDPrefix))
@@ -1022,10 +1439,15 @@ func (n *Node) Initialize(
 	if err := n.initDatabase(dbManager); err != nil { // Set up the node's database
 		return fmt.Errorf("problem initializing database: %w", err)
 	}
+	if err := n.initFaultInjection(); err != nil {
+		return fmt.Errorf("couldn't initialize fault injection: %w", err)
+	}
 
 	if err = n.initBeacons(); err != nil { // Configure the beacons
 		return fmt.Errorf("problem initializing node beacons: %w", err)
 	}
+	n.initMetrics()
+
 	// Start HTTP APIs
 	if err := n.initAPIServer(); err != nil { // Start the API Server
 		return fmt.Errorf("couldn't initialize API server: %w", err)
@@ -1040,6 +1462,15 @@ func (n *Node) Initialize(
 	if err := n.initSharedMemory(); err != nil { // Initialize shared memory
 		return fmt.Errorf("problem initializing shared memory: %w", err)
 	}
+	if err := n.initEvidenceAPI(); err != nil { // Start the Evidence API
+		return fmt.Errorf("couldn't initialize evidence API: %w", err)
+	}
+	if err := n.initNetworkingAPI(); err != nil { // Start the Networking API
+		return fmt.Errorf("couldn't initialize networking API: %w", err)
+	}
+	if err := n.initFailover(); err != nil {
+		return fmt.Errorf("couldn't initialize failover: %w", err)
+	}
 
 	// message.Creator is shared between networking, chainManager and the engine.
 	// It must be initiated before networking (initNetworking), chain manager (initChainManager)
@@ -1067,6 +1498,9 @@ func (n *Node) Initialize(
 	if err := n.initHealthAPI(); err != nil {
 		return fmt.Errorf("couldn't initialize health API: %w", err)
 	}
+	if err := n.initAlerter(); err != nil {
+		return fmt.Errorf("couldn't initialize alerter: %w", err)
+	}
 	if err := n.addDefaultVMAliases(); err != nil {
 		return fmt.Errorf("couldn't initialize API aliases: %w", err)
 	}
@@ -1076,12 +1510,18 @@ func (n *Node) Initialize(
 	if err := n.initVMs(); err != nil { // Initialize the VM registry.
 		return fmt.Errorf("couldn't initialize VM registry: %w", err)
 	}
+	if err := n.initWalletAPI(); err != nil { // Start the Wallet API
+		return fmt.Errorf("couldn't initialize wallet API: %w", err)
+	}
 	if err := n.initAdminAPI(); err != nil { // Start the Admin API
 		return fmt.Errorf("couldn't initialize admin API: %w", err)
 	}
 	if err := n.initInfoAPI(); err != nil { // Start the Info API
 		return fmt.Errorf("couldn't initialize info API: %w", err)
 	}
+	if err := n.initDocAPI(); err != nil { // Start the Doc API
+		return fmt.Errorf("couldn't initialize doc API: %w", err)
+	}
 	if err := n.initIPCs(); err != nil { // Start the IPCs
 		return fmt.Errorf("couldn't initialize IPCs: %w", err)
 	}
@@ -1140,6 +1580,18 @@ func (n *Node) shutdown() {
 			n.Log.Debug("error during IPC shutdown: %s", err)
 		}
 	}
+	if n.failover != nil {
+		n.failover.Stop()
+	}
+	if n.metricsPusher != nil {
+		n.metricsPusher.Stop()
+	}
+	if n.alerter != nil {
+		n.alerter.Stop()
+	}
+	if n.wallet != nil {
+		n.wallet.Shutdown()
+	}
 	if n.chainManager != nil {
 		n.chainManager.Shutdown()
 	}
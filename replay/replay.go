@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// logFrequency is the number of blocks replayed between two progress logs
+const logFrequency = 4096
+
+var (
+	errUnexpectedID           = errors.New("replayed block has a different ID")
+	errUnexpectedParent       = errors.New("replayed block doesn't extend the previously replayed block")
+	errUnexpectedHeight       = errors.New("replayed block has a different height")
+	errUnexpectedTimestamp    = errors.New("replayed block has a different timestamp")
+	errUnexpectedLastAccepted = errors.New("replayed block isn't the last accepted block once accepted")
+	errUnexpectedBytes        = errors.New("replayed block is stored with different bytes")
+	errUnexpectedStatus       = errors.New("replayed block isn't stored as accepted")
+)
+
+// Source is a chain whose accepted blocks can be read by height
+type Source interface {
+	block.Getter
+	block.HeightIndexedChainVM
+
+	LastAccepted() (ids.ID, error)
+}
+
+// Divergence is the first accepted block of a chain whose replay didn't match
+// what the chain stored
+type Divergence struct {
+	Height  uint64
+	BlockID ids.ID
+	Err     error
+}
+
+func (d *Divergence) String() string {
+	return fmt.Sprintf("block %s at height %d: %s", d.BlockID, d.Height, d.Err)
+}
+
+// Report is the outcome of a replay
+type Report struct {
+	// FromHeight is the first height whose block was checked in detail
+	FromHeight uint64
+	// ToHeight is the height of the last accepted block of the source when
+	// the replay started
+	ToHeight uint64
+	// NumReplayed is the number of blocks the target accepted
+	NumReplayed uint64
+	// Divergence is nil if every block was replayed as it was stored
+	Divergence *Divergence
+}
+
+// Replay re-executes the accepted blocks of [source] against [target], which
+// must be a separate instance of the same VM whose last accepted block is an
+// accepted block of [source], usually the genesis. Each block is parsed,
+// verified and accepted by [target] in order, and the replay stops at the
+// first block that doesn't behave like it did when [source] accepted it.
+//
+// The blocks below [fromHeight] only rebuild the state of [target], so they
+// are just required to have the same ID and to be valid. From [fromHeight] on,
+// the block that [target] stores is also compared with the block that
+// [source] stored.
+//
+// An error is returned if the replay couldn't run, in which case the report
+// covers the blocks replayed until then.
+func Replay(log logging.Logger, source Source, target block.ChainVM, fromHeight uint64) (*Report, error) {
+	sourceLastAcceptedID, err := source.LastAccepted()
+	if err != nil {
+		return nil, err
+	}
+	sourceLastAccepted, err := source.GetBlock(sourceLastAcceptedID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastAcceptedID, err := target.LastAccepted()
+	if err != nil {
+		return nil, err
+	}
+	lastAccepted, err := target.GetBlock(lastAcceptedID)
+	if err != nil {
+		return nil, err
+	}
+	height := lastAccepted.Height()
+	if fromHeight <= height {
+		fromHeight = height + 1
+	}
+
+	report := &Report{
+		FromHeight: fromHeight,
+		ToHeight:   sourceLastAccepted.Height(),
+	}
+	sourceID, err := source.GetBlockIDAtHeight(height)
+	if err != nil {
+		return report, fmt.Errorf("couldn't get the ID of the block at height %d: %w", height, err)
+	}
+	if sourceID != lastAcceptedID {
+		report.Divergence = &Divergence{
+			Height:  height,
+			BlockID: sourceID,
+			Err:     fmt.Errorf("%w: the replay starts from %s", errUnexpectedID, lastAcceptedID),
+		}
+		return report, nil
+	}
+
+	log.Info("replaying the blocks from height %d to %d, checking them in detail from height %d",
+		height+1, report.ToHeight, fromHeight)
+	for height++; height <= report.ToHeight; height++ {
+		blkID, err := source.GetBlockIDAtHeight(height)
+		if err != nil {
+			return report, fmt.Errorf("couldn't get the ID of the block at height %d: %w", height, err)
+		}
+		stored, err := source.GetBlock(blkID)
+		if err != nil {
+			return report, fmt.Errorf("couldn't get block %s: %w", blkID, err)
+		}
+
+		if err := replayBlock(target, stored, lastAcceptedID, height >= fromHeight); err != nil {
+			report.Divergence = &Divergence{
+				Height:  height,
+				BlockID: blkID,
+				Err:     err,
+			}
+			return report, nil
+		}
+		lastAcceptedID = blkID
+		report.NumReplayed++
+
+		if report.NumReplayed%logFrequency == 0 {
+			log.Info("replayed %d blocks, up to height %d", report.NumReplayed, height)
+		}
+	}
+	return report, nil
+}
+
+// replayBlock parses, verifies and accepts [stored] with [target]. If [check]
+// is true, the accepted block is compared with [stored].
+func replayBlock(target block.ChainVM, stored snowman.Block, parentID ids.ID, check bool) error {
+	blkID := stored.ID()
+	blk, err := target.ParseBlock(stored.Bytes())
+	if err != nil {
+		return fmt.Errorf("couldn't parse block: %w", err)
+	}
+	if replayedID := blk.ID(); replayedID != blkID {
+		return fmt.Errorf("%w: %s", errUnexpectedID, replayedID)
+	}
+	if replayedParentID := blk.Parent(); replayedParentID != parentID {
+		return fmt.Errorf("%w: parent %s instead of %s", errUnexpectedParent, replayedParentID, parentID)
+	}
+	if check {
+		if replayedHeight, height := blk.Height(), stored.Height(); replayedHeight != height {
+			return fmt.Errorf("%w: %d instead of %d", errUnexpectedHeight, replayedHeight, height)
+		}
+		if replayedTimestamp, timestamp := blk.Timestamp(), stored.Timestamp(); !replayedTimestamp.Equal(timestamp) {
+			return fmt.Errorf("%w: %s instead of %s", errUnexpectedTimestamp, replayedTimestamp, timestamp)
+		}
+	}
+
+	if err := blk.Verify(); err != nil {
+		return fmt.Errorf("failed verification: %w", err)
+	}
+	if err := blk.Accept(); err != nil {
+		return fmt.Errorf("couldn't be accepted: %w", err)
+	}
+	if !check {
+		return nil
+	}
+
+	lastAcceptedID, err := target.LastAccepted()
+	if err != nil {
+		return err
+	}
+	if lastAcceptedID != blkID {
+		return fmt.Errorf("%w: last accepted block is %s", errUnexpectedLastAccepted, lastAcceptedID)
+	}
+	replayed, err := target.GetBlock(blkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the replayed block: %w", err)
+	}
+	if !bytes.Equal(replayed.Bytes(), stored.Bytes()) {
+		return errUnexpectedBytes
+	}
+	if status := replayed.Status(); status != choices.Accepted {
+		return fmt.Errorf("%w: status is %s", errUnexpectedStatus, status)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	errUnknownBlock = errors.New("unknown block")
+	errInvalidBlock = errors.New("invalid block")
+)
+
+type testSource struct {
+	*block.TestVM
+	*block.TestHeightIndexedVM
+}
+
+// testChain returns [n] accepted blocks, each the child of the previous one
+func testChain(n int) []*snowman.TestBlock {
+	blks := make([]*snowman.TestBlock, n)
+	for i := range blks {
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Accepted,
+			},
+			HeightV:    uint64(i),
+			TimestampV: time.Unix(int64(i), 0),
+			BytesV:     []byte{byte(i)},
+		}
+		if i > 0 {
+			blks[i].ParentV = blks[i-1].ID()
+		}
+	}
+	return blks
+}
+
+func testSourceVM(t *testing.T, blks []*snowman.TestBlock) *testSource {
+	vm := &testSource{
+		TestVM:              &block.TestVM{},
+		TestHeightIndexedVM: &block.TestHeightIndexedVM{T: t},
+	}
+	vm.TestVM.T = t
+	vm.LastAcceptedF = func() (ids.ID, error) { return blks[len(blks)-1].ID(), nil }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	vm.GetBlockIDAtHeightF = func(height uint64) (ids.ID, error) {
+		if height >= uint64(len(blks)) {
+			return ids.Empty, errUnknownBlock
+		}
+		return blks[height].ID(), nil
+	}
+	return vm
+}
+
+// testTargetVM returns a VM that replays copies of [blks], starting from the
+// genesis
+func testTargetVM(t *testing.T, blks []*snowman.TestBlock) (*block.TestVM, []*snowman.TestBlock) {
+	replayed := make([]*snowman.TestBlock, len(blks))
+	for i, blk := range blks {
+		blkCopy := *blk
+		if i > 0 {
+			blkCopy.StatusV = choices.Processing
+		}
+		replayed[i] = &blkCopy
+	}
+
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.LastAcceptedF = func() (ids.ID, error) {
+		lastAccepted := replayed[0]
+		for _, blk := range replayed {
+			if blk.Status() == choices.Accepted {
+				lastAccepted = blk
+			}
+		}
+		return lastAccepted.ID(), nil
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range replayed {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range replayed {
+			if bytes.Equal(blk.Bytes(), b) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	return vm, replayed
+}
+
+func TestReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	blks := testChain(5)
+	source := testSourceVM(t, blks)
+	target, replayed := testTargetVM(t, blks)
+
+	report, err := Replay(logging.NoLog{}, source, target, 2)
+	assert.NoError(err)
+	assert.Nil(report.Divergence)
+	assert.EqualValues(2, report.FromHeight)
+	assert.EqualValues(4, report.ToHeight)
+	assert.EqualValues(4, report.NumReplayed)
+	for _, blk := range replayed {
+		assert.Equal(choices.Accepted, blk.Status())
+	}
+}
+
+func TestReplayVerificationDivergence(t *testing.T) {
+	assert := assert.New(t)
+
+	blks := testChain(5)
+	source := testSourceVM(t, blks)
+	target, replayed := testTargetVM(t, blks)
+	replayed[3].VerifyV = errInvalidBlock
+
+	report, err := Replay(logging.NoLog{}, source, target, 0)
+	assert.NoError(err)
+	assert.EqualValues(1, report.FromHeight)
+	assert.EqualValues(2, report.NumReplayed)
+	assert.NotNil(report.Divergence)
+	assert.EqualValues(3, report.Divergence.Height)
+	assert.Equal(blks[3].ID(), report.Divergence.BlockID)
+	assert.ErrorIs(report.Divergence.Err, errInvalidBlock)
+	assert.Equal(choices.Processing, replayed[3].Status())
+}
+
+func TestReplayTimestampDivergence(t *testing.T) {
+	assert := assert.New(t)
+
+	blks := testChain(5)
+	source := testSourceVM(t, blks)
+	target, replayed := testTargetVM(t, blks)
+	replayed[1].TimestampV = time.Unix(100, 0)
+	replayed[3].TimestampV = time.Unix(100, 0)
+
+	// The timestamp of the blocks below the first checked height isn't
+	// compared
+	report, err := Replay(logging.NoLog{}, source, target, 3)
+	assert.NoError(err)
+	assert.EqualValues(2, report.NumReplayed)
+	assert.NotNil(report.Divergence)
+	assert.EqualValues(3, report.Divergence.Height)
+	assert.ErrorIs(report.Divergence.Err, errUnexpectedTimestamp)
+}
+
+func TestReplayDifferentGenesis(t *testing.T) {
+	assert := assert.New(t)
+
+	blks := testChain(3)
+	source := testSourceVM(t, blks)
+	target, _ := testTargetVM(t, testChain(3))
+
+	report, err := Replay(logging.NoLog{}, source, target, 0)
+	assert.NoError(err)
+	assert.Zero(report.NumReplayed)
+	assert.NotNil(report.Divergence)
+	assert.Zero(report.Divergence.Height)
+	assert.ErrorIs(report.Divergence.Err, errUnexpectedID)
+}
+
+func TestReplaySourceError(t *testing.T) {
+	assert := assert.New(t)
+
+	blks := testChain(3)
+	source := testSourceVM(t, blks)
+	target, _ := testTargetVM(t, blks)
+	source.GetBlockIDAtHeightF = func(height uint64) (ids.ID, error) {
+		if height == 2 {
+			return ids.Empty, block.ErrIndexIncomplete
+		}
+		return blks[height].ID(), nil
+	}
+
+	report, err := Replay(logging.NoLog{}, source, target, 0)
+	assert.ErrorIs(err, block.ErrIndexIncomplete)
+	assert.EqualValues(1, report.NumReplayed)
+	assert.Nil(report.Divergence)
+}
@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# Runs each fuzz target for FUZZ_TIME (1 minute by default). Native fuzzing
+# requires go1.18 or later.
+# Usage: ./scripts/fuzz.sh [fuzz_time]
+fuzz_time=${1:-${FUZZ_TIME:-1m}}
+
+targets=(
+  "FuzzParseMessage ./message"
+  "FuzzParseProposerBlock ./vms/proposervm/block"
+  "FuzzParseTx ./vms/platformvm"
+)
+
+for target in "${targets[@]}"; do
+  read -r name pkg <<< "$target"
+  echo "fuzzing $name in $pkg for $fuzz_time"
+  go test -run "^$name\$" -fuzz "^$name\$" -fuzztime "$fuzz_time" "$pkg"
+done
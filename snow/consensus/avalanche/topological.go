@@ -266,6 +266,8 @@ func (ta *Topological) Quiesce() bool { return ta.virtuousVoting.Len() == 0 }
 
 func (ta *Topological) Finalized() bool { return ta.cg.Finalized() }
 
+func (ta *Topological) ConflictGraph() snowstorm.ConflictGraph { return ta.cg.ConflictGraph() }
+
 // HealthCheck returns information about the consensus health.
 func (ta *Topological) HealthCheck() (interface{}, error) {
 	numOutstandingVtx := ta.Latency.NumProcessing()
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+var (
+	genesisID     = ids.Empty.Prefix(0)
+	conflictInput = ids.Empty.Prefix(1)
+
+	_ instance = &snowmanInstance{}
+	_ instance = &snowstormInstance{}
+)
+
+// instance is the consensus run by an honest node to decide between the
+// conflicting choices
+type instance interface {
+	// preference is the choice the node votes for
+	preference() ids.ID
+	recordPoll(votes ids.Bag) error
+	finalized() bool
+	// decision is the accepted choice, or ids.Empty if none was accepted
+	decision() ids.ID
+}
+
+// snowmanInstance decides between conflicting blocks that extend the genesis
+type snowmanInstance struct {
+	consensus snowman.Consensus
+	blocks    []*snowman.TestBlock
+}
+
+// newSnowmanInstance returns an instance that initially prefers the choice
+// [first]
+func newSnowmanInstance(params snowball.Parameters, choiceIDs []ids.ID, first int) (*snowmanInstance, error) {
+	consensus := &snowman.Topological{}
+	if err := consensus.Initialize(snow.DefaultConsensusContextTest(), params, genesisID, 0); err != nil {
+		return nil, err
+	}
+	i := &snowmanInstance{consensus: consensus}
+	for j := range choiceIDs {
+		// The first block added is preferred
+		blk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     choiceIDs[(first+j)%len(choiceIDs)],
+				StatusV: choices.Processing,
+			},
+			ParentV: genesisID,
+			HeightV: 1,
+		}
+		if err := consensus.Add(blk); err != nil {
+			return nil, err
+		}
+		i.blocks = append(i.blocks, blk)
+	}
+	return i, nil
+}
+
+func (i *snowmanInstance) preference() ids.ID             { return i.consensus.Preference() }
+func (i *snowmanInstance) recordPoll(votes ids.Bag) error { return i.consensus.RecordPoll(votes) }
+func (i *snowmanInstance) finalized() bool                { return i.consensus.Finalized() }
+
+func (i *snowmanInstance) decision() ids.ID {
+	for _, blk := range i.blocks {
+		if blk.Status() == choices.Accepted {
+			return blk.ID()
+		}
+	}
+	return ids.Empty
+}
+
+// snowstormInstance decides between conflicting txs, like the txs of the
+// vertices of an avalanche chain
+type snowstormInstance struct {
+	consensus snowstorm.Consensus
+	txs       []*snowstorm.TestTx
+}
+
+// newSnowstormInstance returns an instance that initially prefers the choice
+// [first]
+func newSnowstormInstance(params snowball.Parameters, choiceIDs []ids.ID, first int) (*snowstormInstance, error) {
+	consensus := &snowstorm.Directed{}
+	if err := consensus.Initialize(snow.DefaultConsensusContextTest(), params); err != nil {
+		return nil, err
+	}
+	i := &snowstormInstance{consensus: consensus}
+	for j := range choiceIDs {
+		// The first tx added is preferred
+		tx := &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     choiceIDs[(first+j)%len(choiceIDs)],
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{conflictInput},
+		}
+		if err := consensus.Add(tx); err != nil {
+			return nil, err
+		}
+		i.txs = append(i.txs, tx)
+	}
+	return i, nil
+}
+
+func (i *snowstormInstance) preference() ids.ID {
+	if preferences := i.consensus.Preferences(); preferences.Len() > 0 {
+		return preferences.List()[0]
+	}
+	return i.decision()
+}
+
+func (i *snowstormInstance) recordPoll(votes ids.Bag) error {
+	_, err := i.consensus.RecordPoll(votes)
+	return err
+}
+
+func (i *snowstormInstance) finalized() bool { return i.consensus.Finalized() }
+
+func (i *snowstormInstance) decision() ids.ID {
+	for _, tx := range i.txs {
+		if tx.Status() == choices.Accepted {
+			return tx.ID()
+		}
+	}
+	return ids.Empty
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Distribution of the one-way latency of the messages between two nodes
+type Distribution string
+
+const (
+	// Constant latencies are always [Latency.Mean]
+	Constant Distribution = "constant"
+	// Uniform latencies are in [Mean-Deviation, Mean+Deviation]
+	Uniform Distribution = "uniform"
+	// Normal latencies have a mean of [Mean] and a standard deviation of
+	// [Deviation]. Negative samples are rounded up to 0.
+	Normal Distribution = "normal"
+	// Exponential latencies have a mean of [Mean], so that a few messages
+	// take much longer than the others
+	Exponential Distribution = "exponential"
+)
+
+var (
+	errUnknownDistribution = errors.New("unknown latency distribution")
+	errNegativeLatency     = errors.New("latency can't be negative")
+)
+
+// Latency describes how long the messages between nodes take to arrive
+type Latency struct {
+	Distribution Distribution  `json:"distribution"`
+	Mean         time.Duration `json:"mean"`
+	Deviation    time.Duration `json:"deviation"`
+}
+
+func (l Latency) verify() error {
+	switch l.Distribution {
+	case Constant, Normal, Exponential:
+	case Uniform:
+		if l.Deviation > l.Mean {
+			return fmt.Errorf("%w: uniform deviation %s exceeds the mean %s", errNegativeLatency, l.Deviation, l.Mean)
+		}
+	default:
+		return fmt.Errorf("%w %q", errUnknownDistribution, l.Distribution)
+	}
+	if l.Mean < 0 || l.Deviation < 0 {
+		return errNegativeLatency
+	}
+	return nil
+}
+
+// sample returns the latency of a message
+func (l Latency) sample(rng *rand.Rand) time.Duration {
+	var latency time.Duration
+	switch l.Distribution {
+	case Uniform:
+		latency = l.Mean - l.Deviation + time.Duration(rng.Int63n(int64(2*l.Deviation)+1))
+	case Normal:
+		latency = l.Mean + time.Duration(rng.NormFloat64()*float64(l.Deviation))
+	case Exponential:
+		latency = time.Duration(rng.ExpFloat64() * float64(l.Mean))
+	default:
+		latency = l.Mean
+	}
+	if latency < 0 {
+		return 0
+	}
+	return latency
+}
@@ -0,0 +1,221 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package simulator evaluates consensus parameters by running the consensus of
+// a simulated network of nodes, so that the safety and liveness of a change to
+// the parameters of a subnet can be compared with its current parameters
+// before it's deployed.
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+// Consensus is the consensus run by the simulated nodes
+type Consensus string
+
+const (
+	// Snowman nodes decide between conflicting blocks
+	Snowman Consensus = "snowman"
+	// Avalanche nodes decide between conflicting txs. The vertices that carry
+	// the txs aren't simulated.
+	Avalanche Consensus = "avalanche"
+)
+
+var (
+	errUnknownConsensus  = errors.New("unknown consensus")
+	errInvalidNodes      = errors.New("there must be at least one honest node")
+	errInvalidByzantine  = errors.New("the number of byzantine nodes can't be negative")
+	errSampleTooLarge    = errors.New("k exceeds the number of nodes")
+	errInvalidChoices    = errors.New("there must be at least one choice")
+	errInvalidPacketLoss = errors.New("packet loss must be in [0, 1)")
+	errInvalidTimeout    = errors.New("query timeout must be positive")
+	errInvalidMaxTime    = errors.New("max time must be positive")
+	errInvalidTrials     = errors.New("there must be at least one trial")
+)
+
+// Config describes the simulated network and the consensus parameters of its
+// nodes
+type Config struct {
+	Consensus Consensus           `json:"consensus"`
+	Params    snowball.Parameters `json:"params"`
+
+	// NumNodes is the number of nodes, including the byzantine ones. Every
+	// node has the same weight.
+	NumNodes int `json:"numNodes"`
+	// NumByzantine nodes don't run consensus. Each of their votes is for a
+	// choice that the querying node doesn't prefer.
+	NumByzantine int `json:"numByzantine"`
+	// NumChoices is the number of conflicting choices. The honest nodes
+	// initially prefer each of the choices evenly.
+	NumChoices int `json:"numChoices"`
+
+	Latency Latency `json:"latency"`
+	// PacketLoss is the probability that a message is lost
+	PacketLoss float64 `json:"packetLoss"`
+	// QueryTimeout is the time after which the votes that weren't received
+	// are dropped from a poll
+	QueryTimeout time.Duration `json:"queryTimeout"`
+	// MaxTime is the simulated time after which a trial is stopped. The
+	// honest nodes that haven't finalized by then are liveness failures.
+	MaxTime time.Duration `json:"maxTime"`
+
+	// Trials is the number of times the network is simulated. Trial i uses
+	// the seed [Seed]+i, so that a run can be reproduced.
+	Trials int   `json:"trials"`
+	Seed   int64 `json:"seed"`
+}
+
+// DefaultConfig returns the consensus parameters of the primary network in a
+// network of 100 nodes with a 2-way conflict
+func DefaultConfig() Config {
+	return Config{
+		Consensus: Snowman,
+		Params: snowball.Parameters{
+			K:                     20,
+			Alpha:                 15,
+			BetaVirtuous:          15,
+			BetaRogue:             20,
+			ConcurrentRepolls:     4,
+			OptimalProcessing:     50,
+			MaxOutstandingItems:   1024,
+			MaxItemProcessingTime: 2 * time.Minute,
+		},
+		NumNodes:   100,
+		NumChoices: 2,
+		Latency: Latency{
+			Distribution: Normal,
+			Mean:         50 * time.Millisecond,
+			Deviation:    20 * time.Millisecond,
+		},
+		QueryTimeout: 5 * time.Second,
+		MaxTime:      2 * time.Minute,
+		Trials:       20,
+	}
+}
+
+// Verify returns nil if [c] describes a network that can be simulated
+func (c *Config) Verify() error {
+	switch c.Consensus {
+	case Snowman, Avalanche:
+	default:
+		return fmt.Errorf("%w %q", errUnknownConsensus, c.Consensus)
+	}
+	if err := c.Params.Verify(); err != nil {
+		return err
+	}
+	if err := c.Latency.verify(); err != nil {
+		return err
+	}
+	switch {
+	case c.NumByzantine < 0:
+		return errInvalidByzantine
+	case c.NumNodes <= c.NumByzantine:
+		return errInvalidNodes
+	case c.Params.K > c.NumNodes:
+		return fmt.Errorf("%w: k = %d, %d nodes", errSampleTooLarge, c.Params.K, c.NumNodes)
+	case c.NumChoices <= 0:
+		return errInvalidChoices
+	case c.PacketLoss < 0 || c.PacketLoss >= 1:
+		return errInvalidPacketLoss
+	case c.QueryTimeout <= 0:
+		return errInvalidTimeout
+	case c.MaxTime <= 0:
+		return errInvalidMaxTime
+	case c.Trials <= 0:
+		return errInvalidTrials
+	default:
+		return nil
+	}
+}
+
+// Summary is the outcome of the trials of a config
+type Summary struct {
+	Trials int `json:"trials"`
+	// SafetyViolations is the number of trials in which two honest nodes
+	// accepted different choices
+	SafetyViolations int `json:"safetyViolations"`
+	// LivenessFailures is the number of trials in which an honest node
+	// didn't finalize before the max time
+	LivenessFailures int `json:"livenessFailures"`
+
+	// Times honest nodes took to finalize, over every trial
+	MedianFinalization time.Duration `json:"medianFinalization"`
+	P99Finalization    time.Duration `json:"p99Finalization"`
+	MaxFinalization    time.Duration `json:"maxFinalization"`
+
+	// QueriesPerNode is the average number of queries sent by an honest node
+	// until it finalized
+	QueriesPerNode float64 `json:"queriesPerNode"`
+}
+
+// Run simulates the trials of [config]. The outcome only depends on [config].
+func Run(config Config) (*Summary, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{Trials: config.Trials}
+	var (
+		finalizationTimes []time.Duration
+		numQueries        int
+	)
+	for i := 0; i < config.Trials; i++ {
+		result, err := runTrial(&config, config.Seed+int64(i))
+		if err != nil {
+			return nil, fmt.Errorf("trial %d failed: %w", i, err)
+		}
+		if result.safetyViolated {
+			summary.SafetyViolations++
+		}
+		if len(result.finalizationTimes) < config.NumNodes-config.NumByzantine {
+			summary.LivenessFailures++
+		}
+		finalizationTimes = append(finalizationTimes, result.finalizationTimes...)
+		numQueries += result.numQueries
+	}
+
+	sort.Slice(finalizationTimes, func(i, j int) bool { return finalizationTimes[i] < finalizationTimes[j] })
+	summary.MedianFinalization = percentile(finalizationTimes, 0.5)
+	summary.P99Finalization = percentile(finalizationTimes, 0.99)
+	summary.MaxFinalization = percentile(finalizationTimes, 1)
+	summary.QueriesPerNode = float64(numQueries) / float64(config.Trials*(config.NumNodes-config.NumByzantine))
+	return summary, nil
+}
+
+// Compare simulates [baseline] and [candidate], which usually only differ by
+// their consensus parameters
+func Compare(baseline, candidate Config) (*Summary, *Summary, error) {
+	baselineSummary, err := Run(baseline)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baseline: %w", err)
+	}
+	candidateSummary, err := Run(candidate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("candidate: %w", err)
+	}
+	return baselineSummary, candidateSummary, nil
+}
+
+// percentile returns the [p]th percentile of [durations], which must be
+// sorted
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	// Nearest-rank method
+	index := int(math.Ceil(p*float64(len(durations)))) - 1
+	switch {
+	case index < 0:
+		index = 0
+	case index >= len(durations):
+		index = len(durations) - 1
+	}
+	return durations[index]
+}
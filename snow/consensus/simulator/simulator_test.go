@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.NumNodes = 30
+	config.Params.K = 10
+	config.Params.Alpha = 7
+	config.Params.BetaVirtuous = 5
+	config.Params.BetaRogue = 8
+	config.Trials = 3
+	return config
+}
+
+func TestRunFinalizes(t *testing.T) {
+	for _, consensus := range []Consensus{Snowman, Avalanche} {
+		t.Run(string(consensus), func(t *testing.T) {
+			assert := assert.New(t)
+
+			config := testConfig()
+			config.Consensus = consensus
+			summary, err := Run(config)
+			assert.NoError(err)
+			assert.Equal(config.Trials, summary.Trials)
+			assert.Zero(summary.SafetyViolations)
+			assert.Zero(summary.LivenessFailures)
+			assert.Greater(summary.MedianFinalization, time.Duration(0))
+			assert.LessOrEqual(summary.MedianFinalization, summary.P99Finalization)
+			assert.LessOrEqual(summary.P99Finalization, summary.MaxFinalization)
+			// Each honest node needs at least [BetaRogue] successful polls
+			assert.GreaterOrEqual(summary.QueriesPerNode, float64(config.Params.K*config.Params.BetaRogue))
+		})
+	}
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig()
+	config.PacketLoss = 0.01
+	first, err := Run(config)
+	assert.NoError(err)
+	second, err := Run(config)
+	assert.NoError(err)
+	assert.Equal(first, second)
+
+	config.Seed++
+	third, err := Run(config)
+	assert.NoError(err)
+	assert.NotEqual(first, third)
+}
+
+func TestRunByzantineLiveness(t *testing.T) {
+	assert := assert.New(t)
+
+	config := testConfig()
+	config.Trials = 1
+	config.MaxTime = 30 * time.Second
+	// Too few honest nodes are left for a poll to reach alpha votes
+	config.NumByzantine = 15
+	summary, err := Run(config)
+	assert.NoError(err)
+	assert.Equal(1, summary.LivenessFailures)
+}
+
+func TestCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	baseline := testConfig()
+	candidate := baseline
+	candidate.Params.BetaVirtuous = 10
+	candidate.Params.BetaRogue = 16
+
+	baselineSummary, candidateSummary, err := Compare(baseline, candidate)
+	assert.NoError(err)
+	// Doubling beta requires more polls to finalize
+	assert.Greater(candidateSummary.QueriesPerNode, baselineSummary.QueriesPerNode)
+	assert.Greater(candidateSummary.MedianFinalization, baselineSummary.MedianFinalization)
+
+	candidate.Params.Alpha = 2
+	_, _, err = Compare(baseline, candidate)
+	assert.Error(err)
+}
+
+func TestConfigVerify(t *testing.T) {
+	tests := map[string]func(*Config){
+		"unknown consensus":    func(c *Config) { c.Consensus = "unknown" },
+		"invalid params":       func(c *Config) { c.Params.Alpha = 1 },
+		"unknown distribution": func(c *Config) { c.Latency.Distribution = "unknown" },
+		"negative latency":     func(c *Config) { c.Latency.Mean = -1 },
+		"no honest nodes":      func(c *Config) { c.NumByzantine = c.NumNodes },
+		"k too large":          func(c *Config) { c.NumNodes = c.Params.K - 1 },
+		"no choices":           func(c *Config) { c.NumChoices = 0 },
+		"invalid packet loss":  func(c *Config) { c.PacketLoss = 1 },
+		"no query timeout":     func(c *Config) { c.QueryTimeout = 0 },
+		"no max time":          func(c *Config) { c.MaxTime = 0 },
+		"no trials":            func(c *Config) { c.Trials = 0 },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultConfig()
+			mutate(&config)
+			assert.Error(t, config.Verify())
+		})
+	}
+
+	config := DefaultConfig()
+	assert.NoError(t, config.Verify())
+}
+
+func TestLatencySample(t *testing.T) {
+	assert := assert.New(t)
+
+	rng := rand.New(rand.NewSource(0)) // #nosec G404
+	for _, distribution := range []Distribution{Constant, Uniform, Normal, Exponential} {
+		latency := Latency{
+			Distribution: distribution,
+			Mean:         50 * time.Millisecond,
+			Deviation:    40 * time.Millisecond,
+		}
+		assert.NoError(latency.verify())
+
+		var total time.Duration
+		for i := 0; i < 1000; i++ {
+			sample := latency.sample(rng)
+			assert.GreaterOrEqual(sample, time.Duration(0))
+			total += sample
+		}
+		assert.InDelta(float64(latency.Mean), float64(total/1000), float64(10*time.Millisecond), distribution)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	durations := []time.Duration{1, 2, 3, 4}
+	assert.Equal(time.Duration(2), percentile(durations, 0.5))
+	assert.Equal(time.Duration(4), percentile(durations, 0.99))
+	assert.Equal(time.Duration(4), percentile(durations, 1))
+	assert.Zero(percentile(nil, 0.5))
+}
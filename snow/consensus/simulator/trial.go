@@ -0,0 +1,240 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"container/heap"
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// trialResult is the outcome of one simulation of the network
+type trialResult struct {
+	safetyViolated bool
+	// finalizationTimes of the honest nodes that finalized
+	finalizationTimes []time.Duration
+	numQueries        int
+}
+
+type node struct {
+	// consensus is nil for byzantine nodes
+	consensus instance
+	finalized bool
+}
+
+// poll is an outstanding poll of a node
+type poll struct {
+	node        *node
+	votes       ids.Bag
+	outstanding int
+	done        bool
+}
+
+// trial is a discrete-event simulation of the network. Events at the same
+// time run in the order they were scheduled, so that a trial only depends on
+// its seed.
+type trial struct {
+	config *Config
+	rng    *rand.Rand
+	now    time.Duration
+	events eventHeap
+	numSeq uint64
+
+	nodes       []*node
+	choiceIDs   []ids.ID
+	choiceIndex map[ids.ID]int
+	// indices is a scratch space used to sample the nodes of the polls
+	indices []int
+
+	numFinalized int
+	result       trialResult
+}
+
+func runTrial(config *Config, seed int64) (*trialResult, error) {
+	t := &trial{
+		config:      config,
+		rng:         rand.New(rand.NewSource(seed)), // #nosec G404
+		nodes:       make([]*node, config.NumNodes),
+		choiceIDs:   make([]ids.ID, config.NumChoices),
+		choiceIndex: make(map[ids.ID]int, config.NumChoices),
+		indices:     make([]int, config.NumNodes),
+	}
+	for i := range t.choiceIDs {
+		choiceID := ids.Empty.Prefix(uint64(i) + 2)
+		t.choiceIDs[i] = choiceID
+		t.choiceIndex[choiceID] = i
+	}
+
+	numHonest := config.NumNodes - config.NumByzantine
+	for i := range t.nodes {
+		n := &node{}
+		if i < numHonest {
+			var err error
+			switch config.Consensus {
+			case Avalanche:
+				n.consensus, err = newSnowstormInstance(config.Params, t.choiceIDs, i%config.NumChoices)
+			default:
+				n.consensus, err = newSnowmanInstance(config.Params, t.choiceIDs, i%config.NumChoices)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		t.nodes[i] = n
+		t.indices[i] = i
+	}
+
+	for _, n := range t.nodes[:numHonest] {
+		for i := 0; i < config.Params.ConcurrentRepolls; i++ {
+			t.startPoll(n)
+		}
+	}
+	for t.events.Len() > 0 && t.numFinalized < numHonest {
+		e := heap.Pop(&t.events).(*event)
+		if e.time > config.MaxTime {
+			break
+		}
+		t.now = e.time
+		if err := e.run(); err != nil {
+			return nil, err
+		}
+	}
+
+	decisions := ids.Set{}
+	for _, n := range t.nodes[:numHonest] {
+		if n.finalized {
+			decisions.Add(n.consensus.decision())
+		}
+	}
+	t.result.safetyViolated = decisions.Len() > 1
+	return &t.result, nil
+}
+
+// startPoll sends a query to [k] nodes sampled uniformly
+func (t *trial) startPoll(n *node) {
+	k := t.config.Params.K
+	p := &poll{
+		node:        n,
+		outstanding: k,
+	}
+	t.result.numQueries += k
+
+	// Partial Fisher–Yates shuffle of the node indices
+	for i := 0; i < k; i++ {
+		j := i + t.rng.Intn(len(t.indices)-i)
+		t.indices[i], t.indices[j] = t.indices[j], t.indices[i]
+	}
+	for _, index := range t.indices[:k] {
+		peer := t.nodes[index]
+		if t.lost() {
+			continue
+		}
+		t.schedule(t.config.Latency.sample(t.rng), func() error {
+			vote := t.vote(peer, n)
+			if t.lost() {
+				return nil
+			}
+			t.schedule(t.config.Latency.sample(t.rng), func() error {
+				return t.receive(p, vote)
+			})
+			return nil
+		})
+	}
+	t.schedule(t.config.QueryTimeout, func() error {
+		return t.finish(p)
+	})
+}
+
+// vote returns the vote of [peer] for a query of [querier]
+func (t *trial) vote(peer, querier *node) ids.ID {
+	if peer.consensus != nil {
+		return peer.consensus.preference()
+	}
+	index := t.choiceIndex[querier.consensus.preference()]
+	return t.choiceIDs[(index+1)%len(t.choiceIDs)]
+}
+
+func (t *trial) receive(p *poll, vote ids.ID) error {
+	if p.done {
+		return nil
+	}
+	p.votes.Add(vote)
+	p.outstanding--
+	if p.outstanding > 0 {
+		return nil
+	}
+	return t.finish(p)
+}
+
+// finish records the votes of [p] and starts the next poll of its node
+func (t *trial) finish(p *poll) error {
+	if p.done {
+		return nil
+	}
+	p.done = true
+
+	n := p.node
+	if n.finalized {
+		return nil
+	}
+	if err := n.consensus.recordPoll(p.votes); err != nil {
+		return err
+	}
+	if n.consensus.finalized() {
+		n.finalized = true
+		t.numFinalized++
+		t.result.finalizationTimes = append(t.result.finalizationTimes, t.now)
+		return nil
+	}
+	t.startPoll(n)
+	return nil
+}
+
+func (t *trial) lost() bool {
+	return t.config.PacketLoss > 0 && t.rng.Float64() < t.config.PacketLoss
+}
+
+// schedule runs [f] once [delay] elapsed
+func (t *trial) schedule(delay time.Duration, f func() error) {
+	t.numSeq++
+	heap.Push(&t.events, &event{
+		time: t.now + delay,
+		seq:  t.numSeq,
+		run:  f,
+	})
+}
+
+type event struct {
+	time time.Duration
+	// seq orders the events scheduled at the same time
+	seq uint64
+	run func() error
+}
+
+// eventHeap is a min-heap of events ordered by time
+type eventHeap []*event
+
+func (h eventHeap) Len() int { return len(h) }
+
+func (h eventHeap) Less(i, j int) bool {
+	if h[i].time != h[j].time {
+		return h[i].time < h[j].time
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h eventHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *eventHeap) Push(x interface{}) { *h = append(*h, x.(*event)) }
+
+func (h *eventHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
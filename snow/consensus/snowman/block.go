@@ -4,12 +4,18 @@
 package snowman
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 )
 
+// ErrMissingParent is returned by Verify if the block can't be verified yet
+// because the VM doesn't know its parent. The engine will retry verification
+// once the parent arrives.
+var ErrMissingParent = errors.New("parent block is unknown")
+
 // Block is a possible decision that dictates the next canonical block.
 //
 // Blocks are guaranteed to be Verified, Accepted, and Rejected in topological
@@ -30,7 +36,9 @@ type Block interface {
 	// valid. If the state transition is invalid, a non-nil error should be
 	// returned.
 	//
-	// It is guaranteed that the Parent has been successfully verified.
+	// It is guaranteed that the Parent has been successfully verified. If the
+	// VM doesn't know the Parent, an error wrapping ErrMissingParent should be
+	// returned.
 	Verify() error
 
 	// Bytes returns the binary representation of this block.
@@ -142,6 +142,9 @@ func NumProcessingTest(t *testing.T, factory Factory) {
 	if numProcessing := sm.NumProcessing(); numProcessing != 1 {
 		t.Fatalf("expected %d blocks to be processing but returned %d", 1, numProcessing)
 	}
+	if processing := sm.ProcessingBlocks(); len(processing) != 1 || processing[0].ID() != block.ID() {
+		t.Fatalf("expected %s to be the only processing block but returned %v", block.ID(), processing)
+	}
 
 	votes := ids.Bag{}
 	votes.Add(block.ID())
@@ -152,6 +155,9 @@ func NumProcessingTest(t *testing.T, factory Factory) {
 	if numProcessing := sm.NumProcessing(); numProcessing != 0 {
 		t.Fatalf("expected %d blocks to be processing but returned %d", 0, numProcessing)
 	}
+	if processing := sm.ProcessingBlocks(); len(processing) != 0 {
+		t.Fatalf("expected no blocks to be processing but returned %v", processing)
+	}
 }
 
 // Make sure that adding a block to the tail updates the preference
@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/snow/choices"
+)
+
+var errInvariantViolated = errors.New("consensus invariant violated")
+
+// checkInvariants returns an error if the tree of blocks is inconsistent. It's
+// a no-op unless invariant checks are enabled, as it iterates over every
+// processing block.
+func (ts *Topological) checkInvariants() error {
+	if !ts.ctx.InvariantChecks {
+		return nil
+	}
+
+	head, ok := ts.blocks[ts.head]
+	if !ok {
+		return fmt.Errorf("%w: last accepted block %s isn't tracked", errInvariantViolated, ts.head)
+	}
+	if !head.Accepted() {
+		return fmt.Errorf("%w: last accepted block %s isn't accepted", errInvariantViolated, ts.head)
+	}
+
+	for blkID, n := range ts.blocks {
+		if blkID == ts.head {
+			continue
+		}
+		if status := n.blk.Status(); status != choices.Processing {
+			return fmt.Errorf("%w: tracked block %s has status %s", errInvariantViolated, blkID, status)
+		}
+		parentID := n.blk.Parent()
+		parent, ok := ts.blocks[parentID]
+		if !ok {
+			return fmt.Errorf("%w: parent %s of block %s isn't tracked", errInvariantViolated, parentID, blkID)
+		}
+		if _, ok := parent.children[blkID]; !ok {
+			return fmt.Errorf("%w: block %s isn't a child of its parent %s", errInvariantViolated, blkID, parentID)
+		}
+	}
+
+	tail, ok := ts.blocks[ts.tail]
+	if !ok {
+		return fmt.Errorf("%w: preferred block %s isn't tracked", errInvariantViolated, ts.tail)
+	}
+	if tail.sb != nil {
+		return fmt.Errorf("%w: preferred block %s has children", errInvariantViolated, ts.tail)
+	}
+
+	// The preferred blocks are the processing blocks from the tail to the head
+	numPreferred := 0
+	for n := tail; !n.Accepted(); n = ts.blocks[n.blk.Parent()] {
+		blkID := n.blk.ID()
+		if !ts.preferredIDs.Contains(blkID) {
+			return fmt.Errorf("%w: block %s is an ancestor of the preferred block %s but isn't preferred", errInvariantViolated, blkID, ts.tail)
+		}
+		numPreferred++
+	}
+	if numPreferred != ts.preferredIDs.Len() {
+		return fmt.Errorf("%w: %d blocks are preferred but the preferred branch has %d blocks", errInvariantViolated, ts.preferredIDs.Len(), numPreferred)
+	}
+	return nil
+}
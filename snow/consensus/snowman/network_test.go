@@ -58,7 +58,9 @@ func (n *Network) Initialize(params snowball.Parameters, numColors int) {
 }
 
 func (n *Network) AddNode(sm Consensus) error {
-	if err := sm.Initialize(snow.DefaultConsensusContextTest(), n.params, Genesis.ID(), Genesis.Height()); err != nil {
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.InvariantChecks = true
+	if err := sm.Initialize(ctx, n.params, Genesis.ID(), Genesis.Height()); err != nil {
 		return err
 	}
 
@@ -5,6 +5,7 @@ package snowman
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -130,7 +131,28 @@ func (ts *Topological) Parameters() snowball.Parameters { return ts.params }
 
 func (ts *Topological) NumProcessing() int { return len(ts.blocks) - 1 }
 
+func (ts *Topological) ProcessingBlocks() []Block {
+	blks := make([]Block, 0, len(ts.blocks)-1)
+	for blkID, node := range ts.blocks {
+		if blkID == ts.head {
+			continue
+		}
+		blks = append(blks, node.blk)
+	}
+	sort.Slice(blks, func(i, j int) bool {
+		return blks[i].Height() < blks[j].Height()
+	})
+	return blks
+}
+
 func (ts *Topological) Add(blk Block) error {
+	if err := ts.add(blk); err != nil {
+		return err
+	}
+	return ts.checkInvariants()
+}
+
+func (ts *Topological) add(blk Block) error {
 	parentID := blk.Parent()
 
 	blkID := blk.ID()
@@ -234,6 +256,13 @@ func (ts *Topological) Preference() ids.ID { return ts.tail }
 // - Runtime = 3 * |live set| + |votes|
 // - Space = 2 * |live set| + |votes|
 func (ts *Topological) RecordPoll(voteBag ids.Bag) error {
+	if err := ts.recordPoll(voteBag); err != nil {
+		return err
+	}
+	return ts.checkInvariants()
+}
+
+func (ts *Topological) recordPoll(voteBag ids.Bag) error {
 	// Register a new poll call
 	ts.pollNumber++
 
@@ -4,7 +4,67 @@
 package snowman
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 )
 
 func TestTopological(t *testing.T) { runConsensusTests(t, TopologicalFactory{}) }
+
+func TestTopologicalInvariants(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.InvariantChecks = true
+	params := snowball.Parameters{
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             2,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	ts := &Topological{}
+	assert.NoError(ts.Initialize(ctx, params, GenesisID, GenesisHeight))
+
+	block0 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: GenesisID,
+		HeightV: GenesisHeight + 1,
+	}
+	block1 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: block0.IDV,
+		HeightV: block0.HeightV + 1,
+	}
+	assert.NoError(ts.Add(block0))
+	assert.NoError(ts.Add(block1))
+
+	votes := ids.Bag{}
+	votes.Add(block0.IDV)
+	assert.NoError(ts.RecordPoll(votes))
+	assert.Equal(choices.Accepted, block0.Status())
+
+	// A processing block that was decided outside of consensus is caught
+	block1.StatusV = choices.Rejected
+	err := ts.RecordPoll(ids.Bag{})
+	assert.True(errors.Is(err, errInvariantViolated))
+
+	// The checks are skipped unless they're enabled
+	ctx.InvariantChecks = false
+	assert.NoError(ts.RecordPoll(ids.Bag{}))
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ConflictGraph is a snapshot of the transactions currently being decided by a
+// snowstorm instance. It is intended to help diagnose transactions that aren't
+// being finalized.
+type ConflictGraph struct {
+	// Number of polls that have been recorded by the instance
+	PollNumber uint64 `json:"pollNumber"`
+	// Snowball parameters the transactions are decided with
+	BetaVirtuous int `json:"betaVirtuous"`
+	BetaRogue    int `json:"betaRogue"`
+	// Processing transactions, sorted by ID
+	Txs []TxState `json:"txs"`
+	// Inputs consumed by more than one processing transaction, sorted by
+	// input ID
+	ConflictSets []ConflictSet `json:"conflictSets"`
+}
+
+// TxState describes a processing transaction
+type TxState struct {
+	TxID ids.ID `json:"txID"`
+	// True if no processing transaction conflicts with this transaction
+	Virtuous bool `json:"virtuous"`
+	// True if a conflict with this transaction has ever been issued. Rogue
+	// transactions require [BetaRogue] consecutive successful polls to be
+	// accepted rather than [BetaVirtuous].
+	Rogue bool `json:"rogue"`
+	// True if this transaction is preferred over all of its conflicts
+	Preferred bool `json:"preferred"`
+	// Number of consecutive successful polls as of the last poll
+	Confidence int `json:"confidence"`
+	// Total number of successful polls
+	NumSuccessfulPolls int `json:"numSuccessfulPolls"`
+	// True if this transaction has been decided but is waiting for its
+	// dependencies to be accepted
+	PendingAccept bool `json:"pendingAccept"`
+	// Processing transactions that conflict with this transaction, sorted by
+	// ID
+	Conflicts []ids.ID `json:"conflicts"`
+}
+
+// ConflictSet is a set of processing transactions consuming the same input
+type ConflictSet struct {
+	InputID ids.ID   `json:"inputID"`
+	TxIDs   []ids.ID `json:"txIDs"`
+}
+
+func (dg *Directed) ConflictGraph() ConflictGraph {
+	graph := ConflictGraph{
+		PollNumber:   dg.pollNumber,
+		BetaVirtuous: dg.params.BetaVirtuous,
+		BetaRogue:    dg.params.BetaRogue,
+		Txs:          make([]TxState, 0, len(dg.txs)),
+		ConflictSets: []ConflictSet{},
+	}
+	for txID, txNode := range dg.txs {
+		conflicts := make([]ids.ID, 0, txNode.ins.Len()+txNode.outs.Len())
+		conflicts = append(conflicts, txNode.ins.List()...)
+		conflicts = append(conflicts, txNode.outs.List()...)
+		ids.SortIDs(conflicts)
+
+		graph.Txs = append(graph.Txs, TxState{
+			TxID:               txID,
+			Virtuous:           dg.virtuous.Contains(txID),
+			Rogue:              txNode.rogue,
+			Preferred:          dg.preferences.Contains(txID),
+			Confidence:         txNode.Confidence(dg.pollNumber),
+			NumSuccessfulPolls: txNode.numSuccessfulPolls,
+			PendingAccept:      txNode.pendingAccept,
+			Conflicts:          conflicts,
+		})
+	}
+	sort.Slice(graph.Txs, func(i, j int) bool {
+		return bytes.Compare(graph.Txs[i].TxID[:], graph.Txs[j].TxID[:]) == -1
+	})
+
+	for inputID, spenders := range dg.utxos {
+		if spenders.Len() < 2 {
+			continue
+		}
+		txIDs := spenders.List()
+		ids.SortIDs(txIDs)
+		graph.ConflictSets = append(graph.ConflictSets, ConflictSet{
+			InputID: inputID,
+			TxIDs:   txIDs,
+		})
+	}
+	sort.Slice(graph.ConflictSets, func(i, j int) bool {
+		return bytes.Compare(graph.ConflictSets[i].InputID[:], graph.ConflictSets[j].InputID[:]) == -1
+	})
+	return graph
+}
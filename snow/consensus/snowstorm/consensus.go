@@ -69,4 +69,8 @@ type Consensus interface {
 
 	// HealthCheck returns information about the consensus health.
 	HealthCheck() (interface{}, error)
+
+	// ConflictGraph returns a snapshot of the processing transactions and the
+	// conflicts between them.
+	ConflictGraph() ConflictGraph
 }
@@ -53,6 +53,7 @@ var (
 		ErrorOnRejectingHigherConfidenceConflictTest,
 		UTXOCleanupTest,
 		RemoveVirtuousTest,
+		ConflictGraphTest,
 	}
 
 	Red, Green, Blue, Alpha *TestTx
@@ -1695,6 +1696,75 @@ func RemoveVirtuousTest(t *testing.T, factory Factory) {
 	assert.Empty(t, virtuous, "removal of a virtuous transaction should have emptied the virtuous set")
 }
 
+func ConflictGraphTest(t *testing.T, factory Factory) {
+	assert := assert.New(t)
+
+	graph := factory.New()
+
+	params := sbcon.Parameters{
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          2,
+		BetaRogue:             3,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	err := graph.Initialize(snow.DefaultConsensusContextTest(), params)
+	assert.NoError(err)
+
+	assert.NoError(graph.Add(Red))
+	assert.NoError(graph.Add(Green))
+	assert.NoError(graph.Add(Alpha))
+
+	votes := ids.Bag{}
+	votes.Add(Green.ID())
+	changed, err := graph.RecordPoll(votes)
+	assert.NoError(err)
+	assert.True(changed, "green should now be preferred")
+
+	conflictGraph := graph.ConflictGraph()
+	assert.Equal(uint64(1), conflictGraph.PollNumber)
+	assert.Equal(params.BetaVirtuous, conflictGraph.BetaVirtuous)
+	assert.Equal(params.BetaRogue, conflictGraph.BetaRogue)
+	assert.Len(conflictGraph.Txs, 3)
+
+	txs := make(map[ids.ID]TxState, len(conflictGraph.Txs))
+	for _, tx := range conflictGraph.Txs {
+		txs[tx.TxID] = tx
+	}
+
+	red := txs[Red.ID()]
+	assert.False(red.Virtuous)
+	assert.True(red.Rogue)
+	assert.False(red.Preferred)
+	assert.Zero(red.Confidence)
+	assert.Equal([]ids.ID{Green.ID()}, red.Conflicts)
+
+	green := txs[Green.ID()]
+	assert.False(green.Virtuous)
+	assert.True(green.Rogue)
+	assert.True(green.Preferred)
+	assert.Equal(1, green.Confidence)
+	assert.Equal(1, green.NumSuccessfulPolls)
+	assert.Equal([]ids.ID{Red.ID()}, green.Conflicts)
+
+	alpha := txs[Alpha.ID()]
+	assert.True(alpha.Virtuous)
+	assert.False(alpha.Rogue)
+	assert.True(alpha.Preferred)
+	assert.Zero(alpha.Confidence)
+	assert.Empty(alpha.Conflicts)
+
+	conflicting := []ids.ID{Red.ID(), Green.ID()}
+	ids.SortIDs(conflicting)
+	assert.Equal([]ConflictSet{{
+		InputID: Red.InputIDs()[0],
+		TxIDs:   conflicting,
+	}}, conflictGraph.ConflictSets)
+}
+
 func StringTest(t *testing.T, factory Factory, prefix string) {
 	graph := factory.New()
 
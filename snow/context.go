@@ -14,9 +14,12 @@ import (
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/evidence"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 )
 
 type EventDispatcher interface {
@@ -52,6 +55,27 @@ type Context struct {
 	XChainID    ids.ID
 	AVAXAssetID ids.ID
 
+	// FeeAssetID is the asset the subnet of the chain declared for the fees of
+	// its chains. It's empty if the subnet didn't declare one, in which case
+	// the VM picks the asset its fees are paid in.
+	FeeAssetID ids.ID
+	// FeeCollector is the address the subnet of the chain declared to receive
+	// the fees of its chains. It's empty if the fees are burned.
+	FeeCollector ids.ShortID
+
+	// SubnetVMConfig is the latest config the subnet of the chain published on
+	// the P-chain for the VMs of its chains. It's empty if the subnet didn't
+	// publish one. VMs that implement common.SubnetVMConfigurable are given
+	// the configs published afterwards.
+	SubnetVMConfig []byte
+
+	// InvariantChecks is true if expensive runtime assertions should be made.
+	// A violated invariant fails the operation that caused it.
+	InvariantChecks bool
+
+	// Clock tells the time of the node. If nil, the local clock is used.
+	Clock *mockable.Clock
+
 	Log          logging.Logger
 	Lock         sync.RWMutex
 	Keystore     keystore.BlockchainKeystore
@@ -59,6 +83,8 @@ type Context struct {
 	BCLookup     ids.AliaserReader
 	SNLookup     SubnetLookup
 	Metrics      metrics.OptionalGatherer
+	Evidence     evidence.Recorder
+	Drops        drops.Recorder
 
 	// snowman++ attributes
 	ValidatorState    validators.State  // interface for P-Chain validators
@@ -123,6 +149,8 @@ func DefaultContextTest() *Context {
 		Log:       logging.NoLog{},
 		BCLookup:  ids.NewAliaser(),
 		Metrics:   metrics.NewOptionalGatherer(),
+		Evidence:  evidence.NewNoOpRecorder(),
+		Drops:     drops.NewNoOpRecorder(),
 	}
 }
 
@@ -75,8 +75,9 @@ func New(config Config, onFinished func(lastReqID uint32) error) (common.Bootstr
 	}
 
 	config.Config.Bootstrapable = b
-	b.Bootstrapper = common.NewCommonBootstrapper(config.Config)
-	return b, nil
+	var err error
+	b.Bootstrapper, err = common.NewCommonBootstrapper(config.Config)
+	return b, err
 }
 
 type bootstrapper struct {
@@ -119,6 +120,20 @@ func (b *bootstrapper) Clear() error {
 	return b.TxBlocked.Commit()
 }
 
+// compactJobs removes the jobs left in the queues, which can no longer be
+// executed once the chain has synced, and compacts the queues' databases, so
+// that the space of the jobs is reclaimed after bootstrapping.
+func (b *bootstrapper) compactJobs() error {
+	if err := b.Clear(); err != nil {
+		return err
+	}
+	b.Ctx.Log.Debug("compacting the bootstrapping job queues")
+	if err := b.VtxBlocked.Compact(); err != nil {
+		return err
+	}
+	return b.TxBlocked.Compact()
+}
+
 // Ancestors handles the receipt of multiple containers. Should be received in response to a GetAncestors message to [vdr]
 // with request ID [requestID]. Expects vtxs[0] to be the vertex requested in the corresponding GetAncestors.
 func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, vtxs [][]byte) error {
@@ -241,7 +256,7 @@ func (b *bootstrapper) Timeout() error {
 	}
 	b.awaitingTimeout = false
 
-	if !b.Config.Subnet.IsBootstrapped() {
+	if b.Config.BootstrapOnly || !b.Config.Subnet.IsBootstrapped() {
 		return b.Restart(true)
 	}
 	return b.finish()
@@ -505,6 +520,18 @@ func (b *bootstrapper) checkFinish() error {
 	b.Config.Subnet.Bootstrapped(b.Ctx.ChainID)
 	b.processedCache.Flush()
 
+	// If this chain only serves bootstrapping, it should remain syncing.
+	if b.Config.BootstrapOnly {
+		if !b.Config.SharedCfg.Restarted {
+			b.Ctx.Log.Info("finished syncing, serving bootstrapping without running consensus")
+		}
+		// Restart bootstrapping after [bootstrappingDelay] to keep up to date
+		// on the latest tip.
+		b.Config.Timer.RegisterTimeout(bootstrappingDelay)
+		b.awaitingTimeout = true
+		return nil
+	}
+
 	// If the subnet hasn't finished bootstrapping, this chain should remain
 	// syncing.
 	if !b.Config.Subnet.IsBootstrapped() {
@@ -525,6 +552,9 @@ func (b *bootstrapper) checkFinish() error {
 
 // Finish bootstrapping
 func (b *bootstrapper) finish() error {
+	if err := b.compactJobs(); err != nil {
+		return err
+	}
 	if err := b.VM.SetState(snow.NormalOp); err != nil {
 		return fmt.Errorf("failed to notify VM that bootstrapping has finished: %w",
 			err)
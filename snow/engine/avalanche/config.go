@@ -22,4 +22,14 @@ type Config struct {
 
 	Params    avalanche.Parameters
 	Consensus avalanche.Consensus
+
+	// IsReadOnly returns true while this engine should follow consensus
+	// without affecting it. A read only engine never issues vertices and
+	// responds to queries with its accepted frontier, rather than its
+	// preferences. If nil, the engine is never read only.
+	IsReadOnly func() bool
+}
+
+func (c *Config) readOnly() bool {
+	return c.IsReadOnly != nil && c.IsReadOnly()
 }
@@ -6,6 +6,7 @@ package avalanche
 import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 )
 
@@ -16,4 +17,9 @@ type Engine interface {
 	// GetVtx returns a vertex by its ID.
 	// Returns an error if unknown.
 	GetVtx(vtxID ids.ID) (avalanche.Vertex, error)
+
+	// ConflictGraph returns a snapshot of the transactions currently being
+	// decided by consensus.
+	// Assumes consensus has been started.
+	ConflictGraph() snowstorm.ConflictGraph
 }
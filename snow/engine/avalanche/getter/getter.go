@@ -68,10 +68,16 @@ func (gh *getter) GetAncestors(validatorID ids.ShortID, requestID uint32, vtxID
 	startTime := time.Now()
 	gh.log.Verbo("GetAncestors(%s, %d, %s) called", validatorID, requestID, vtxID)
 	vertex, err := gh.storage.GetVtx(vtxID)
-	if err != nil || vertex.Status() == choices.Unknown {
+	if err != nil {
 		gh.log.Verbo("dropping getAncestors")
 		return nil // Don't have the requested vertex. Drop message.
 	}
+	if status := vertex.Status(); status == choices.Unknown || status == choices.Rejected {
+		// Rejected vertices are pruned from the database, so they can't be
+		// served
+		gh.log.Verbo("dropping getAncestors for vertex with status %s", status)
+		return nil
+	}
 
 	queue := make([]avalanche.Vertex, 1, gh.cfg.AncestorsMaxContainersSent) // for BFS
 	queue[0] = vertex
@@ -114,7 +120,7 @@ func (gh *getter) GetAncestors(validatorID ids.ShortID, requestID uint32, vtxID
 
 func (gh *getter) Get(validatorID ids.ShortID, requestID uint32, vtxID ids.ID) error {
 	// If this engine has access to the requested vertex, provide it
-	if vtx, err := gh.storage.GetVtx(vtxID); err == nil {
+	if vtx, err := gh.storage.GetVtx(vtxID); err == nil && vtx.Status() != choices.Rejected {
 		gh.sender.SendPut(validatorID, requestID, vtxID, vtx.Bytes())
 	}
 	return nil
@@ -168,3 +168,39 @@ func TestFilterAccepted(t *testing.T) {
 		t.Fatalf("Vtx shouldn't be accepted")
 	}
 }
+
+func TestGetRejected(t *testing.T) {
+	manager, _, config := testSetup(t)
+
+	vtxID := ids.GenerateTestID()
+	vtx := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     vtxID,
+			StatusV: choices.Rejected,
+		},
+	}
+
+	bsIntf, err := New(manager, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, ok := bsIntf.(*getter)
+	if !ok {
+		t.Fatal("Unexpected get handler")
+	}
+
+	manager.GetVtxF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id != vtxID {
+			t.Fatal(errUnknownVertex)
+		}
+		return vtx, nil
+	}
+
+	// The sender fails the test if the pruned vertex is sent
+	if err := bs.Get(ids.ShortEmpty, 0, vtxID); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.GetAncestors(ids.ShortEmpty, 0, vtxID); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -12,6 +12,8 @@ import (
 
 	snow "github.com/ava-labs/avalanchego/snow"
 
+	snowstorm "github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+
 	time "time"
 
 	version "github.com/ava-labs/avalanchego/version"
@@ -134,6 +136,20 @@ func (_m *Engine) Chits(validatorID ids.ShortID, requestID uint32, containerIDs
 	return r0
 }
 
+// ConflictGraph provides a mock function with given fields:
+func (_m *Engine) ConflictGraph() snowstorm.ConflictGraph {
+	ret := _m.Called()
+
+	var r0 snowstorm.ConflictGraph
+	if rf, ok := ret.Get(0).(func() snowstorm.ConflictGraph); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(snowstorm.ConflictGraph)
+	}
+
+	return r0
+}
+
 // Connected provides a mock function with given fields: id, nodeVersion
 func (_m *Engine) Connected(id ids.ShortID, nodeVersion version.Application) error {
 	ret := _m.Called(id, nodeVersion)
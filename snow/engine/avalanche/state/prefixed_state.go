@@ -63,6 +63,20 @@ func (s *prefixedState) SetVertex(vtx vertex.StatelessVertex) error {
 	return s.state.SetVertex(vID, vtx)
 }
 
+// DeleteVertex removes the vertex with ID [id] from the database, without
+// removing its status
+func (s *prefixedState) DeleteVertex(id ids.ID) error {
+	var vID ids.ID
+	if cachedVtxIDIntf, found := s.vtx.Get(id); found {
+		vID = cachedVtxIDIntf.(ids.ID)
+	} else {
+		vID = id.Prefix(vtxID)
+		s.vtx.Put(id, vID)
+	}
+
+	return s.state.SetVertex(vID, nil)
+}
+
 func (s *prefixedState) Status(id ids.ID) choices.Status {
 	var sID ids.ID
 	if cachedStatusIDIntf, found := s.status.Get(id); found {
@@ -22,8 +22,9 @@ import (
 )
 
 const (
-	dbCacheSize = 10000
-	idCacheSize = 1000
+	dbCacheSize      = 10000
+	idCacheSize      = 1000
+	closureCacheSize = 64
 )
 
 var (
@@ -39,6 +40,10 @@ type Serializer struct {
 	versionDB *versiondb.Database
 	state     *prefixedState
 	edge      ids.Set
+
+	// closures caches the transitive closures of the vertices whose closure
+	// was computed since the last vertex was accepted
+	closures cache.Cacher // vtxID -> *transitiveClosure
 }
 
 type SerializerConfig struct {
@@ -55,6 +60,7 @@ func NewSerializer(config SerializerConfig) vertex.Manager {
 	s := Serializer{
 		SerializerConfig: config,
 		versionDB:        versionDB,
+		closures:         &cache.LRU{Size: closureCacheSize},
 	}
 
 	rawState := &state{
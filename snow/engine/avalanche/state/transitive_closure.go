@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+// transitiveClosure is the part of the DAG that a vertex transitively
+// references, up to the accepted frontier. Cached closures are flushed whenever
+// a vertex is accepted, as they may then include vertices that are no longer
+// processing.
+type transitiveClosure struct {
+	// vertices are the IDs of the non-accepted vertices in the closure,
+	// including the vertex itself
+	vertices ids.Set
+	// txs are the IDs of the txs in [vertices]
+	txs ids.Set
+	// acceptedFrontier are the IDs of the accepted vertices the closure
+	// reaches
+	acceptedFrontier ids.Set
+	// dependencies are the dependencies of [txs] that weren't accepted when
+	// the closure was computed
+	dependencies map[ids.ID]snowstorm.Tx
+}
+
+func (c *transitiveClosure) union(other *transitiveClosure) {
+	c.vertices.Union(other.vertices)
+	c.txs.Union(other.txs)
+	c.acceptedFrontier.Union(other.acceptedFrontier)
+	for depID, dep := range other.dependencies {
+		c.dependencies[depID] = dep
+	}
+}
+
+// getTransitiveClosure returns the transitive closure of [vtx]. The traversal
+// stops at the accepted vertices, and at the vertices whose closure is already
+// cached.
+func (s *Serializer) getTransitiveClosure(vtx avalanche.Vertex) (*transitiveClosure, error) {
+	vtxID := vtx.ID()
+	if closure, ok := s.cachedTransitiveClosure(vtxID); ok {
+		return closure, nil
+	}
+
+	closure := &transitiveClosure{
+		vertices:         ids.NewSet(0),
+		txs:              ids.NewSet(0),
+		acceptedFrontier: ids.NewSet(0),
+		dependencies:     make(map[ids.ID]snowstorm.Tx),
+	}
+	queue := []avalanche.Vertex{vtx}
+	for len(queue) > 0 { // perform BFS
+		cur := queue[0]
+		queue = queue[1:]
+
+		curID := cur.ID()
+		if cur.Status() == choices.Accepted {
+			// have reached the accepted frontier on the transitive closure
+			// no need to continue the search on this path
+			closure.acceptedFrontier.Add(curID)
+			continue
+		}
+		if closure.vertices.Contains(curID) {
+			continue
+		}
+		if cached, ok := s.cachedTransitiveClosure(curID); ok {
+			closure.union(cached)
+			continue
+		}
+		closure.vertices.Add(curID)
+
+		txs, err := cur.Txs()
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			closure.txs.Add(tx.ID())
+			deps, err := tx.Dependencies()
+			if err != nil {
+				return nil, err
+			}
+			for _, dep := range deps {
+				// only add non-accepted dependencies
+				if dep.Status() != choices.Accepted {
+					closure.dependencies[dep.ID()] = dep
+				}
+			}
+		}
+
+		parents, err := cur.Parents()
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, parents...)
+	}
+
+	s.closures.Put(vtxID, closure)
+	return closure, nil
+}
+
+func (s *Serializer) cachedTransitiveClosure(vtxID ids.ID) (*transitiveClosure, bool) {
+	closureIntf, ok := s.closures.Get(vtxID)
+	if !ok {
+		return nil, false
+	}
+	return closureIntf.(*transitiveClosure), true
+}
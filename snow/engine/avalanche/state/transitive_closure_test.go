@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+)
+
+func TestTransitiveClosureCache(t *testing.T) {
+	t.Parallel()
+
+	txs, parseTx := generateTestTxs('a', 'b', 'c', 'd')
+	ts := newTestSerializer(t, parseTx)
+
+	// (accepted)
+	//   vtx_1 <- vtx_2 <- vtx_3 <- stop_vertex_4
+	//  [tx_a]   [tx_b]   [tx_c]
+	uvtx1 := newTestUniqueVertex(t, ts, nil, [][]byte{{'a'}}, false)
+	if err := uvtx1.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	uvtx2 := newTestUniqueVertex(t, ts, []ids.ID{uvtx1.id}, [][]byte{{'b'}}, false)
+	uvtx3 := newTestUniqueVertex(t, ts, []ids.ID{uvtx2.id}, [][]byte{{'c'}}, false)
+	svtx4 := newTestUniqueVertex(t, ts, []ids.ID{uvtx3.id}, nil, true)
+
+	closure3, err := ts.getTransitiveClosure(uvtx3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ts.cachedTransitiveClosure(uvtx3.id); !ok {
+		t.Fatal("closure should have been cached")
+	}
+
+	// The closure of the stop vertex stops traversing at the cached closure of
+	// its parent, so modifying the cached closure is observable
+	closure3.txs.Add(txs[3].ID())
+	whitelist, err := svtx4.Whitelist()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedWhitelist := []ids.ID{
+		txs[1].ID(),
+		txs[2].ID(),
+		txs[3].ID(),
+		uvtx2.ID(),
+		uvtx3.ID(),
+		svtx4.ID(),
+	}
+	if !ids.UnsortedEquals(whitelist.List(), expectedWhitelist) {
+		t.Fatalf("whitelist expected %v, got %v", expectedWhitelist, whitelist)
+	}
+
+	// Accepting a vertex flushes the cached closures
+	if err := uvtx2.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ts.cachedTransitiveClosure(uvtx3.id); ok {
+		t.Fatal("closure should have been flushed")
+	}
+	if _, ok := ts.cachedTransitiveClosure(svtx4.id); ok {
+		t.Fatal("closure should have been flushed")
+	}
+
+	whitelist, err = svtx4.Whitelist()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedWhitelist = []ids.ID{
+		txs[2].ID(),
+		uvtx3.ID(),
+		svtx4.ID(),
+	}
+	if !ids.UnsortedEquals(whitelist.List(), expectedWhitelist) {
+		t.Fatalf("whitelist expected %v, got %v", expectedWhitelist, whitelist)
+	}
+	if err := svtx4.Verify(); err != nil {
+		t.Fatalf("stop vertex 'Verify' expected nil, got %v", err)
+	}
+}
+
+func TestRejectedVertexPruned(t *testing.T) {
+	t.Parallel()
+
+	_, parseTx := generateTestTxs('a')
+	ts := newTestSerializer(t, parseTx)
+
+	uvtx := newTestUniqueVertex(t, ts, nil, [][]byte{{'a'}}, false)
+	vtxBytes := uvtx.Bytes()
+	if err := uvtx.Reject(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The in-memory vertex can still be reported
+	if string(uvtx.Bytes()) != string(vtxBytes) {
+		t.Fatal("rejected vertex should still have its bytes in memory")
+	}
+
+	// A node restarting on the same database only knows the vertex's status
+	restarted := NewSerializer(ts.SerializerConfig).(*Serializer)
+	vtx, err := restarted.GetVtx(uvtx.id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := vtx.Status(); status != choices.Rejected {
+		t.Fatalf("expected status %s, got %s", choices.Rejected, status)
+	}
+	if b := vtx.Bytes(); len(b) != 0 {
+		t.Fatal("rejected vertex should have been pruned from the database")
+	}
+	if _, err := vtx.Parents(); err == nil {
+		t.Fatal("parents of a pruned vertex should have produced an error")
+	}
+
+	// The vertex can be parsed again if it's received from a peer, without
+	// changing its status
+	reparsed, err := restarted.ParseVtx(vtxBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := reparsed.Status(); status != choices.Rejected {
+		t.Fatalf("expected status %s, got %s", choices.Rejected, status)
+	}
+}
@@ -187,10 +187,11 @@ func (vtx *uniqueVertex) Accept() error {
 		return fmt.Errorf("failed to set edge while accepting vertex %s due to %w", vtx.id, err)
 	}
 
-	// Should never traverse into parents of a decided vertex. Allows for the
-	// parents to be garbage collected
-	vtx.v.parents = nil
+	// The cached closures may traverse through this vertex, which is now part
+	// of the accepted frontier
+	vtx.serializer.closures.Flush()
 
+	vtx.prune()
 	return vtx.serializer.versionDB.Commit()
 }
 
@@ -199,13 +200,26 @@ func (vtx *uniqueVertex) Reject() error {
 		return err
 	}
 
-	// Should never traverse into parents of a decided vertex. Allows for the
-	// parents to be garbage collected
-	vtx.v.parents = nil
+	// A rejected vertex is never served to peers or traversed again, so only
+	// its status needs to be kept on disk. The in-memory vertex is kept so
+	// that it can still be reported to the consensus dispatcher.
+	if err := vtx.serializer.state.DeleteVertex(vtx.id); err != nil {
+		return fmt.Errorf("failed to prune rejected vertex %s due to %w", vtx.id, err)
+	}
 
+	vtx.prune()
 	return vtx.serializer.versionDB.Commit()
 }
 
+// prune releases the references of a decided vertex to other vertices and
+// txs, as consensus never traverses a decided vertex.
+func (vtx *uniqueVertex) prune() {
+	// Allows for the parents and the txs to be garbage collected. They are
+	// re-loaded if they are requested again.
+	vtx.v.parents = nil
+	vtx.v.txs = nil
+}
+
 // TODO: run performance test to see if shallow refreshing
 // (which will mean that refresh must be called in Bytes and Verify)
 // improves performance
@@ -243,6 +257,11 @@ var (
 // is stop vertex or not. Called before issuing the vertex to the consensus.
 // No vertex should ever be able to refer to a stop vertex in its transitive closure.
 func (vtx *uniqueVertex) Verify() error {
+	vtx.refresh()
+	if vtx.v.vtx == nil {
+		return fmt.Errorf("failed to verify vertex with status: %s", vtx.v.status)
+	}
+
 	// first verify the underlying stateless vertex
 	if err := vtx.v.vtx.Verify(); err != nil {
 		return err
@@ -299,68 +318,26 @@ func (vtx *uniqueVertex) Verify() error {
 	// To make sure such transitive paths of the stop vertex reach all accepted frontier:
 	// 1. check the edge of the transitive paths refers to the accepted frontier
 	// 2. check dependencies of all txs must be subset of transitive paths
-	queue := []avalanche.Vertex{vtx}
-	visitedVtx := ids.NewSet(0)
-
-	acceptedFrontier := ids.NewSet(0)
-	transitivePaths := ids.NewSet(0)
-	dependencies := ids.NewSet(0)
-	for len(queue) > 0 { // perform BFS
-		cur := queue[0]
-		queue = queue[1:]
-
-		curID := cur.ID()
-		if cur.Status() == choices.Accepted {
-			// 1. check the edge of the transitive paths refers to the accepted frontier
-			acceptedFrontier.Add(curID)
-
-			// have reached the accepted frontier on the transitive closure
-			// no need to continue the search on this path
-			continue
-		}
-
-		if visitedVtx.Contains(curID) {
-			continue
-		}
-		visitedVtx.Add(curID)
-		transitivePaths.Add(curID)
-
-		txs, err := cur.Txs()
-		if err != nil {
-			return err
-		}
-		for _, tx := range txs {
-			transitivePaths.Add(tx.ID())
-			deps, err := tx.Dependencies()
-			if err != nil {
-				return err
-			}
-			for _, dep := range deps {
-				// only add non-accepted dependencies
-				if dep.Status() != choices.Accepted {
-					dependencies.Add(dep.ID())
-				}
-			}
-		}
-
-		parents, err := cur.Parents()
-		if err != nil {
-			return err
-		}
-		queue = append(queue, parents...)
+	closure, err := vtx.serializer.getTransitiveClosure(vtx)
+	if err != nil {
+		return err
 	}
 
 	// stop vertex should be able to reach all IDs
 	// that are returned by the "Edge"
-	if !acceptedFrontier.Equals(acceptedEdges) {
+	if !closure.acceptedFrontier.Equals(acceptedEdges) {
 		return errUnexpectedEdges
 	}
 
 	// 2. check dependencies of all txs must be subset of transitive paths
-	prev := transitivePaths.Len()
-	transitivePaths.Union(dependencies)
-	if prev != transitivePaths.Len() {
-		return errUnexpectedDependencyStopVtx
+	for depID, dep := range closure.dependencies {
+		// the dependency may have been accepted since the closure was cached
+		if dep.Status() == choices.Accepted {
+			continue
+		}
+		if !closure.vertices.Contains(depID) && !closure.txs.Contains(depID) {
+			return errUnexpectedDependencyStopVtx
+		}
 	}
 
 	return nil
@@ -377,43 +354,16 @@ func (vtx *uniqueVertex) Whitelist() (ids.Set, error) {
 		return nil, nil
 	}
 
-	// perform BFS on transitive paths until reaching the accepted frontier
 	// represents all processing transaction IDs transitively referenced by the
 	// vertex
-	queue := []avalanche.Vertex{vtx}
-	whitlist := ids.NewSet(0)
-	visitedVtx := ids.NewSet(0)
-	for len(queue) > 0 {
-		cur := queue[0]
-		queue = queue[1:]
-
-		if cur.Status() == choices.Accepted {
-			// have reached the accepted frontier on the transitive closure
-			// no need to continue the search on this path
-			continue
-		}
-		curID := cur.ID()
-		if visitedVtx.Contains(curID) {
-			continue
-		}
-		visitedVtx.Add(curID)
-
-		txs, err := cur.Txs()
-		if err != nil {
-			return nil, err
-		}
-		for _, tx := range txs {
-			whitlist.Add(tx.ID())
-		}
-		whitlist.Add(curID)
-
-		parents, err := cur.Parents()
-		if err != nil {
-			return nil, err
-		}
-		queue = append(queue, parents...)
+	closure, err := vtx.serializer.getTransitiveClosure(vtx)
+	if err != nil {
+		return nil, err
 	}
-	return whitlist, nil
+	whitelist := ids.NewSet(closure.vertices.Len() + closure.txs.Len())
+	whitelist.Union(closure.vertices)
+	whitelist.Union(closure.txs)
+	return whitelist, nil
 }
 
 func (vtx *uniqueVertex) Height() (uint64, error) {
@@ -458,7 +408,15 @@ func (vtx *uniqueVertex) Txs() ([]snowstorm.Tx, error) {
 	return vtx.v.txs, nil
 }
 
-func (vtx *uniqueVertex) Bytes() []byte { return vtx.v.vtx.Bytes() }
+func (vtx *uniqueVertex) Bytes() []byte {
+	vtx.refresh()
+
+	if vtx.v.vtx == nil {
+		// The vertex was rejected and pruned from the database
+		return nil
+	}
+	return vtx.v.vtx.Bytes()
+}
 
 func (vtx *uniqueVertex) String() string {
 	sb := strings.Builder{}
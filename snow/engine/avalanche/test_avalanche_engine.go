@@ -8,6 +8,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 )
 
@@ -21,13 +22,16 @@ var (
 type EngineTest struct {
 	common.EngineTest
 
-	CantGetVtx bool
-	GetVtxF    func(vtxID ids.ID) (avalanche.Vertex, error)
+	CantGetVtx, CantConflictGraph bool
+
+	GetVtxF        func(vtxID ids.ID) (avalanche.Vertex, error)
+	ConflictGraphF func() snowstorm.ConflictGraph
 }
 
 func (e *EngineTest) Default(cant bool) {
 	e.EngineTest.Default(cant)
 	e.CantGetVtx = false
+	e.CantConflictGraph = false
 }
 
 func (e *EngineTest) GetVtx(vtxID ids.ID) (avalanche.Vertex, error) {
@@ -39,3 +43,13 @@ func (e *EngineTest) GetVtx(vtxID ids.ID) (avalanche.Vertex, error) {
 	}
 	return nil, errGetVtx
 }
+
+func (e *EngineTest) ConflictGraph() snowstorm.ConflictGraph {
+	if e.ConflictGraphF != nil {
+		return e.ConflictGraphF()
+	}
+	if e.CantConflictGraph && e.T != nil {
+		e.T.Fatalf("Unexpectedly called ConflictGraph")
+	}
+	return snowstorm.ConflictGraph{}
+}
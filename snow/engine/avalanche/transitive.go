@@ -131,6 +131,11 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32) error {
 }
 
 func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID) error {
+	if t.readOnly() {
+		t.Sender.SendChits(vdr, requestID, t.Manager.Edge())
+		return nil
+	}
+
 	// Will send chits to [vdr] once we have [vtxID] and its dependencies
 	c := &convincer{
 		consensus: t.Consensus,
@@ -159,6 +164,11 @@ func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID)
 }
 
 func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, vtxBytes []byte) error {
+	if t.readOnly() {
+		t.Sender.SendChits(vdr, requestID, t.Manager.Edge())
+		return nil
+	}
+
 	vtx, err := t.Manager.ParseVtx(vtxBytes)
 	if err != nil {
 		t.Ctx.Log.Debug("failed to parse vertex due to: %s", err)
@@ -267,11 +277,20 @@ func (t *Transitive) Shutdown() error {
 func (t *Transitive) Notify(msg common.Message) error {
 	switch msg {
 	case common.PendingTxs:
-		t.pendingTxs = append(t.pendingTxs, t.VM.PendingTxs()...)
+		txs := t.VM.PendingTxs()
+		if t.readOnly() {
+			t.Ctx.Log.Verbo("dropping %d txs because the engine is read only", len(txs))
+			return nil
+		}
+		t.pendingTxs = append(t.pendingTxs, txs...)
 		t.metrics.pendingTxs.Set(float64(len(t.pendingTxs)))
 		return t.attemptToIssueTxs()
 
 	case common.StopVertex:
+		if t.readOnly() {
+			t.Ctx.Log.Verbo("dropping request to issue a stop vertex because the engine is read only")
+			return nil
+		}
 		// stop vertex doesn't have any txs, issue directly!
 		return t.issueStopVtx()
 
@@ -331,6 +350,10 @@ func (t *Transitive) GetVtx(vtxID ids.ID) (avalanche.Vertex, error) {
 	return t.Manager.GetVtx(vtxID)
 }
 
+func (t *Transitive) ConflictGraph() snowstorm.ConflictGraph {
+	return t.Consensus.ConflictGraph()
+}
+
 func (t *Transitive) attemptToIssueTxs() error {
 	err := t.errs.Err
 	if err != nil {
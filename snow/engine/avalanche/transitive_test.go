@@ -4654,3 +4654,94 @@ func TestAbandonTx(t *testing.T) {
 	// sanity check that there is indeed an outstanding vertex request
 	assert.True(te.outstandingVtxReqs.Len() == 1)
 }
+
+func TestEngineReadOnly(t *testing.T) {
+	_, bootCfg, engCfg := DefaultConfig()
+	engCfg.IsReadOnly = func() bool { return true }
+
+	sender := &common.SenderTest{T: t}
+	bootCfg.Sender = sender
+	engCfg.Sender = sender
+	sender.Default(true)
+	sender.CantSendGetAcceptedFrontier = false
+
+	vals := validators.NewSet()
+	wt := tracker.NewWeightTracker(vals, bootCfg.StartupAlpha)
+	bootCfg.Validators = vals
+	bootCfg.WeightTracker = wt
+	engCfg.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(vdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := vertex.NewTestManager(t)
+	engCfg.Manager = manager
+	manager.Default(true)
+
+	vm := &vertex.TestVM{TestVM: common.TestVM{T: t}}
+	bootCfg.VM = vm
+	engCfg.VM = vm
+	vm.Default(true)
+
+	edge := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	manager.EdgeF = func() []ids.ID { return edge }
+	manager.GetVtxF = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		return &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+			IDV:     vtxID,
+			StatusV: choices.Accepted,
+		}}, nil
+	}
+
+	vm.CantSetState = false
+	te, err := newTransitive(engCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Start(0); err != nil {
+		t.Fatal(err)
+	}
+	vm.CantSetState = true
+
+	// Pending txs are dropped rather than issued into a vertex
+	tx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	vm.PendingTxsF = func() []snowstorm.Tx { return []snowstorm.Tx{tx} }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Notify(common.StopVertex); err != nil {
+		t.Fatal(err)
+	}
+
+	// Queries are answered with the accepted frontier without issuing the
+	// queried vertex
+	numChits := 0
+	sender.SendChitsF = func(inVdr ids.ShortID, requestID uint32, votes []ids.ID) {
+		numChits++
+		if inVdr != vdr {
+			t.Fatalf("sent chits to the wrong validator")
+		}
+		if requestID != uint32(numChits) {
+			t.Fatalf("sent chits with the wrong request ID")
+		}
+		if len(votes) != len(edge) || votes[0] != edge[0] || votes[1] != edge[1] {
+			t.Fatalf("voted for %v rather than the accepted frontier", votes)
+		}
+	}
+	if err := te.PullQuery(vdr, 1, ids.GenerateTestID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.PushQuery(vdr, 2, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if numChits != 2 {
+		t.Fatalf("sent %d chits, expected 2", numChits)
+	}
+	if te.Consensus.NumProcessing() != 0 {
+		t.Fatalf("read only engine issued a vertex")
+	}
+}
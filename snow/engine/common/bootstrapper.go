@@ -8,9 +8,12 @@ import (
 
 	stdmath "math"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 const (
@@ -74,12 +77,36 @@ type bootstrapper struct {
 
 	// number of times the bootstrap has been attempted
 	bootstrapAttempts int
+
+	// Number of containers in the accepted frontier that weren't accepted by
+	// a quorum of the beacons' stake
+	numBelowQuorum prometheus.Counter
+	// Number of times bootstrapping was restarted because beacons with too
+	// little stake responded
+	numInsufficientWeight prometheus.Counter
 }
 
-func NewCommonBootstrapper(config Config) Bootstrapper {
-	return &bootstrapper{
+func NewCommonBootstrapper(config Config) (Bootstrapper, error) {
+	b := &bootstrapper{
 		Config: config,
+		numBelowQuorum: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "bs",
+			Name:      "frontier_below_quorum",
+			Help:      "Number of containers in the accepted frontier that weren't accepted by a quorum of the beacons' stake",
+		}),
+		numInsufficientWeight: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "bs",
+			Name:      "insufficient_weight_restarts",
+			Help:      "Number of times bootstrapping was restarted because beacons with too little stake responded",
+		}),
 	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		config.Ctx.Registerer.Register(b.numBelowQuorum),
+		config.Ctx.Registerer.Register(b.numInsufficientWeight),
+	)
+	return b, errs.Err
 }
 
 func (b *bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs []ids.ID) error {
@@ -127,9 +154,16 @@ func (b *bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint3
 		return err
 	}
 
+	b.Ctx.Log.Debug("Received accepted frontiers with %d containers from beacons with %d of %d sampled stake",
+		b.acceptedFrontierSet.Len(),
+		b.sampledBeacons.Weight()-failedBeaconWeight,
+		b.sampledBeacons.Weight(),
+	)
+
 	// fail the bootstrap if the weight is not enough to bootstrap
 	if float64(b.sampledBeacons.Weight())-newAlpha < float64(failedBeaconWeight) {
 		if b.Config.RetryBootstrap {
+			b.numInsufficientWeight.Inc()
 			b.Ctx.Log.Debug("Not enough frontiers received, restarting bootstrap... - Beacons: %d - Failed Bootstrappers: %d "+
 				"- bootstrap attempt: %d", b.Beacons.Len(), b.failedAcceptedFrontier.Len(), b.bootstrapAttempts)
 			return b.Restart(false)
@@ -210,6 +244,19 @@ func (b *bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 		}
 	}
 
+	// When beacons disagree about the accepted frontier, the containers that
+	// only a minority of the stake accepted are dropped
+	for _, containerID := range b.acceptedFrontier {
+		if weight := b.acceptedVotes[containerID]; weight < b.Alpha {
+			b.numBelowQuorum.Inc()
+			b.Ctx.Log.Debug("Dropping %s from the accepted frontier as it was accepted by %d stake, which is below the quorum of %d",
+				containerID,
+				weight,
+				b.Alpha,
+			)
+		}
+	}
+
 	// if we don't have enough weight for the bootstrap to be accepted then retry or fail the bootstrap
 	size := len(accepted)
 	if size == 0 && b.Beacons.Len() > 0 {
@@ -221,6 +268,7 @@ func (b *bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 
 		// in a zero network there will be no accepted votes but the voting weight will be greater than the failed weight
 		if b.Config.RetryBootstrap && b.Beacons.Weight()-b.Alpha < failedBeaconWeight {
+			b.numInsufficientWeight.Inc()
 			b.Ctx.Log.Debug("Not enough votes received, restarting bootstrap... - Beacons: %d - Failed Bootstrappers: %d "+
 				"- bootstrap attempt: %d", b.Beacons.Len(), b.failedAccepted.Len(), b.bootstrapAttempts)
 			return b.Restart(false)
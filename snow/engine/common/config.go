@@ -31,6 +31,12 @@ type Config struct {
 	// Max number of times to retry bootstrap before warning the node operator
 	RetryBootstrapWarnFrequency int
 
+	// BootstrapOnly is true if this chain should never finish bootstrapping.
+	// Instead, bootstrapping is periodically restarted to keep up with the
+	// network, so the chain serves bootstrapping requests without running
+	// consensus.
+	BootstrapOnly bool
+
 	// Max time to spend fetching a container and its ancestors when responding
 	// to a GetAncestors
 	MaxTimeGetAncestors time.Duration
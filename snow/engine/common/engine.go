@@ -284,6 +284,23 @@ type QueryHandler interface {
 	) error
 }
 
+// QueryBatchHandler is implemented by engines that answer the queries of a
+// PushQueryBatch message together. Engines that don't implement it are passed
+// each query of the batch as a PushQuery.
+type QueryBatchHandler interface {
+	// Notify this engine of several requests for our preferences at once. The
+	// query for [containers[i]] has request ID [requestIDs[i]].
+	//
+	// This function is meant to behave the same way as calling PushQuery for
+	// each of the queries, except that the queries that can be answered right
+	// away should be answered in a single ChitsBatch message.
+	PushQueryBatch(
+		validatorID ids.ShortID,
+		requestIDs []uint32,
+		containers [][]byte,
+	) error
+}
+
 // ChitsHandler defines how a consensus engine reacts to query response messages
 // from other validators. Functions only return fatal errors.
 type ChitsHandler interface {
@@ -394,3 +411,12 @@ type InternalHandler interface {
 	// Notify this engine of a message from the virtual machine.
 	Notify(Message) error
 }
+
+// ContainerPreparer is implemented by engines that can do part of the work of
+// handling containers before the chain's lock is held.
+type ContainerPreparer interface {
+	// PrepareContainers prepares [containers], which were received from a
+	// peer, to be handled. It may be called concurrently with any other
+	// method, including itself, so it must not touch the state of the engine.
+	PrepareContainers(containers [][]byte)
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var ErrUnknownTxType = errors.New("unknown tx type")
+
+// FeeEstimator is implemented by VMs that can report the fees of the txs
+// issued to their chain.
+type FeeEstimator interface {
+	// EstimateFees is called, with the context lock held, to estimate the fee
+	// that a tx of [txType] and [size] bytes issued now must pay. If [txType]
+	// is empty, the fees of all the tx types users can issue are estimated.
+	// ErrUnknownTxType is returned if [txType] isn't issued to the chain.
+	EstimateFees(txType string, size uint64) (FeeEstimate, error)
+}
+
+// FeeEstimate is the fees of txs issued to a chain
+type FeeEstimate struct {
+	// ID of the asset the fees are paid in
+	FeeAssetID ids.ID
+	// Maps a tx type to its fee
+	Fees map[string]uint64
+	// BaseFee is the current base fee of chains whose fees change with their
+	// load. Nil if the fees are static.
+	BaseFee *uint64
+}
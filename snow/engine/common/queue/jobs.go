@@ -29,6 +29,9 @@ type Jobs struct {
 	db *versiondb.Database
 	// state writes the job queue to [db].
 	state *state
+	// The number of bytes of pushed jobs that can be held in memory before
+	// they should be committed
+	maxUncommittedBytes int
 }
 
 // New attempts to create a new job queue from the provided database.
@@ -44,14 +47,30 @@ func New(
 	}
 
 	return &Jobs{
-		db:    vdb,
-		state: state,
+		db:                  vdb,
+		state:               state,
+		maxUncommittedBytes: DefaultMemoryBudget / 2,
 	}, nil
 }
 
 // SetParser tells this job queue how to parse jobs from the database.
 func (j *Jobs) SetParser(parser Parser) error { j.state.parser = parser; return nil }
 
+// SetMemoryBudget bounds the bytes of jobs held in memory by this job queue to
+// about [budget]. Half of it is used to cache parsed jobs, and the other half
+// to hold pushed jobs until they're committed.
+func (j *Jobs) SetMemoryBudget(budget int) {
+	j.state.jobsLRU.MaxSize = budget / 2
+	j.maxUncommittedBytes = budget / 2
+}
+
+// ShouldCommit returns true if the pushed jobs that aren't committed exceed the
+// memory budget. They should then be committed, so that they're spilled to
+// disk rather than held in memory, before more jobs are pushed.
+func (j *Jobs) ShouldCommit() bool {
+	return j.state.uncommittedBytes > j.maxUncommittedBytes
+}
+
 func (j *Jobs) Has(jobID ids.ID) (bool, error) { return j.state.HasJob(jobID) }
 
 // Returns how many pending jobs are waiting in the queue.
@@ -191,7 +210,18 @@ func (j *Jobs) Clear() error {
 
 // Commit the versionDB to the underlying database.
 func (j *Jobs) Commit() error {
-	return j.db.Commit()
+	if err := j.db.Commit(); err != nil {
+		return err
+	}
+	j.state.uncommittedBytes = 0
+	j.state.updateMetrics()
+	return nil
+}
+
+// Compact the database the jobs are stored in, so that the space of the jobs
+// that were executed or cleared is reclaimed.
+func (j *Jobs) Compact() error {
+	return j.db.Compact(nil, nil)
 }
 
 type JobsWithMissing struct {
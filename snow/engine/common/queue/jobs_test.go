@@ -16,9 +16,9 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-// Magic value that comes from the size in bytes of a serialized key-value bootstrap checkpoint in a database +
-// the overhead of the key-value storage.
-const bootstrapProgressCheckpointSize = 55
+// Magic value that comes from the size in bytes of the serialized key-value bootstrap checkpoints, of the number
+// of jobs and of their bytes, in a database + the overhead of the key-value storage.
+const bootstrapProgressCheckpointSize = 111
 
 func testJob(t *testing.T, jobID ids.ID, executed *bool, parentID ids.ID, parentExecuted *bool) *TestJob {
 	return &TestJob{
@@ -517,3 +517,89 @@ func TestClearAll(t *testing.T) {
 	assert.NoError(err)
 	assert.False(hasJob1)
 }
+
+// Test that the pushed jobs are held in memory up to the memory budget of the
+// queue, and that they're no longer held once they're committed.
+func TestMemoryBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := &TestParser{T: t}
+	db := memdb.New()
+
+	jobs, err := New(db, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+	jobs.SetMemoryBudget(100)
+
+	newJob := func(jobID ids.ID) *TestJob {
+		job := testJob(t, jobID, nil, ids.Empty, nil)
+		job.BytesF = func() []byte { return make([]byte, 30) }
+		return job
+	}
+
+	pushed, err := jobs.Push(newJob(ids.GenerateTestID()))
+	assert.NoError(err)
+	assert.True(pushed)
+	assert.False(jobs.ShouldCommit())
+
+	pushed, err = jobs.Push(newJob(ids.GenerateTestID()))
+	assert.NoError(err)
+	assert.True(pushed)
+	assert.True(jobs.ShouldCommit())
+
+	// The cached jobs are bounded by the other half of the budget
+	assert.LessOrEqual(jobs.state.jobsLRU.CurrentSize(), 50)
+
+	assert.NoError(jobs.Commit())
+	assert.False(jobs.ShouldCommit())
+	assert.EqualValues(2, jobs.PendingJobs())
+}
+
+// Test that the metrics track the jobs and bytes in the queue, including after
+// the queue is reloaded from the database.
+func TestMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := &TestParser{T: t}
+	db := memdb.New()
+
+	gauges := func(registry *prometheus.Registry) map[string]float64 {
+		families, err := registry.Gather()
+		assert.NoError(err)
+
+		values := map[string]float64{}
+		for _, family := range families {
+			if metrics := family.GetMetric(); len(metrics) == 1 && metrics[0].GetGauge() != nil {
+				values[family.GetName()] = metrics[0].GetGauge().GetValue()
+			}
+		}
+		return values
+	}
+
+	registry := prometheus.NewRegistry()
+	jobs, err := New(db, "block", registry)
+	assert.NoError(err)
+	assert.NoError(jobs.SetParser(parser))
+
+	job := testJob(t, ids.GenerateTestID(), nil, ids.Empty, nil)
+	job.BytesF = func() []byte { return make([]byte, 30) }
+	pushed, err := jobs.Push(job)
+	assert.NoError(err)
+	assert.True(pushed)
+
+	values := gauges(registry)
+	assert.Equal(float64(1), values["block_pending_jobs"])
+	assert.Equal(float64(30), values["block_pending_jobs_bytes"])
+	assert.Equal(float64(30), values["block_uncommitted_jobs_bytes"])
+
+	assert.NoError(jobs.Commit())
+	assert.Equal(float64(0), gauges(registry)["block_uncommitted_jobs_bytes"])
+
+	registry = prometheus.NewRegistry()
+	jobs, err = New(db, "block", registry)
+	assert.NoError(err)
+
+	values = gauges(registry)
+	assert.Equal(float64(1), values["block_pending_jobs"])
+	assert.Equal(float64(30), values["block_pending_jobs_bytes"])
+}
@@ -12,13 +12,17 @@ import (
 	"github.com/ava-labs/avalanchego/database/linkeddb"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
 	dependentsCacheSize = 1024
-	jobsCacheSize       = 2048
+
+	// DefaultMemoryBudget is the number of bytes of jobs a queue holds in
+	// memory, unless it's given another budget
+	DefaultMemoryBudget = 256 * units.MiB
 )
 
 var (
@@ -28,6 +32,7 @@ var (
 	missingJobIDsPrefix  = []byte("missing job IDs")
 	metadataPrefix       = []byte("metadata")
 	numJobsKey           = []byte("numJobs")
+	numBytesKey          = []byte("numBytes")
 )
 
 type state struct {
@@ -35,7 +40,13 @@ type state struct {
 	runnableJobIDs linkeddb.LinkedDB
 	cachingEnabled bool
 	jobsCache      cache.Cacher
-	jobsDB         database.Database
+	// The parsed jobs in [jobsCache], which are bounded by the size of their
+	// bytes
+	jobsLRU *cache.SizedLRU
+	jobsDB  database.Database
+	// The number of bytes of jobs that were put since the last commit, which
+	// are held in memory until they're committed
+	uncommittedBytes int
 	// Should be prefixed with the jobID that we are attempting to find the
 	// dependencies of. This prefixdb.Database should then be wrapped in a
 	// linkeddb.LinkedDB to read the dependencies.
@@ -51,6 +62,11 @@ type state struct {
 	// This caches the number of jobs that are currently in the queue to
 	// execute.
 	numJobs uint64
+	// This caches the number of bytes of the jobs that are currently in the
+	// queue to execute.
+	numBytes uint64
+
+	numJobsMetric, numBytesMetric, uncommittedBytesMetric prometheus.Gauge
 }
 
 func newState(
@@ -59,7 +75,13 @@ func newState(
 	metricsRegisterer prometheus.Registerer,
 ) (*state, error) {
 	jobsCacheMetricsNamespace := fmt.Sprintf("%s_jobs_cache", metricsNamespace)
-	jobsCache, err := metercacher.New(jobsCacheMetricsNamespace, metricsRegisterer, &cache.LRU{Size: jobsCacheSize})
+	jobsLRU := &cache.SizedLRU{
+		MaxSize: DefaultMemoryBudget / 2,
+		Size: func(_, job interface{}) int {
+			return len(job.(Job).Bytes())
+		},
+	}
+	jobsCache, err := metercacher.New(jobsCacheMetricsNamespace, metricsRegisterer, jobsLRU)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create metered cache: %w", err)
 	}
@@ -70,17 +92,47 @@ func newState(
 	if err != nil {
 		return nil, fmt.Errorf("couldn't initialize pending jobs: %w", err)
 	}
-	return &state{
+	numBytes, err := getNumBytes(metadataDB, jobs)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize pending bytes: %w", err)
+	}
+	s := &state{
 		runnableJobIDs:  linkeddb.NewDefault(prefixdb.New(runnableJobIDsPrefix, db)),
 		cachingEnabled:  true,
 		jobsCache:       jobsCache,
+		jobsLRU:         jobsLRU,
 		jobsDB:          jobs,
 		dependenciesDB:  prefixdb.New(dependenciesPrefix, db),
 		dependentsCache: &cache.LRU{Size: dependentsCacheSize},
 		missingJobIDs:   linkeddb.NewDefault(prefixdb.New(missingJobIDsPrefix, db)),
 		metadataDB:      metadataDB,
 		numJobs:         numJobs,
-	}, nil
+		numBytes:        numBytes,
+		numJobsMetric: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "pending_jobs",
+			Help:      "Number of jobs in the queue to execute",
+		}),
+		numBytesMetric: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "pending_jobs_bytes",
+			Help:      "Number of bytes of the jobs in the queue to execute",
+		}),
+		uncommittedBytesMetric: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "uncommitted_jobs_bytes",
+			Help:      "Number of bytes of the pushed jobs that are held in memory until they're committed",
+		}),
+	}
+	s.updateMetrics()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		metricsRegisterer.Register(s.numJobsMetric),
+		metricsRegisterer.Register(s.numBytesMetric),
+		metricsRegisterer.Register(s.uncommittedBytesMetric),
+	)
+	return s, errs.Err
 }
 
 func getNumJobs(d database.Database, jobs database.Iteratee) (uint64, error) {
@@ -93,6 +145,37 @@ func getNumJobs(d database.Database, jobs database.Iteratee) (uint64, error) {
 	return numJobs, err
 }
 
+func getNumBytes(d database.Database, jobs database.Iteratee) (uint64, error) {
+	numBytes, err := database.GetUInt64(d, numBytesKey)
+	if err != database.ErrNotFound {
+		return numBytes, err
+	}
+
+	// If we don't have a checkpoint, we need to initialize it.
+	iterator := jobs.NewIterator()
+	defer iterator.Release()
+
+	for iterator.Next() {
+		numBytes += uint64(len(iterator.Value()))
+	}
+	return numBytes, iterator.Error()
+}
+
+// putCounts writes the number of jobs and bytes in the queue
+func (s *state) putCounts() error {
+	s.updateMetrics()
+	if err := database.PutUInt64(s.metadataDB, numJobsKey, s.numJobs); err != nil {
+		return err
+	}
+	return database.PutUInt64(s.metadataDB, numBytesKey, s.numBytes)
+}
+
+func (s *state) updateMetrics() {
+	s.numJobsMetric.Set(float64(s.numJobs))
+	s.numBytesMetric.Set(float64(s.numBytes))
+	s.uncommittedBytesMetric.Set(float64(s.uncommittedBytes))
+}
+
 func (s *state) Clear() error {
 	var (
 		runJobsIter  = s.runnableJobIDs.NewIterator()
@@ -139,7 +222,8 @@ func (s *state) Clear() error {
 
 	// clear number of pending jobs
 	s.numJobs = 0
-	if err := database.PutUInt64(s.metadataDB, numJobsKey, s.numJobs); err != nil {
+	s.numBytes = 0
+	if err := s.putCounts(); err != nil {
 		return err
 	}
 
@@ -192,8 +276,13 @@ func (s *state) RemoveRunnableJob() (Job, error) {
 		return job, nil
 	}
 	s.numJobs--
+	if numBytes := uint64(len(job.Bytes())); numBytes <= s.numBytes {
+		s.numBytes -= numBytes
+	} else {
+		s.numBytes = 0
+	}
 
-	return job, database.PutUInt64(s.metadataDB, numJobsKey, s.numJobs)
+	return job, s.putCounts()
 }
 
 // PutJob adds the job to the queue
@@ -203,12 +292,15 @@ func (s *state) PutJob(job Job) error {
 		s.jobsCache.Put(id, job)
 	}
 
-	if err := s.jobsDB.Put(id[:], job.Bytes()); err != nil {
+	jobBytes := job.Bytes()
+	if err := s.jobsDB.Put(id[:], jobBytes); err != nil {
 		return err
 	}
+	s.uncommittedBytes += len(jobBytes)
 
 	s.numJobs++
-	return database.PutUInt64(s.metadataDB, numJobsKey, s.numJobs)
+	s.numBytes += uint64(len(jobBytes))
+	return s.putCounts()
 }
 
 // HasJob returns true if the job [id] is in the queue
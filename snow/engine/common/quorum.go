@@ -0,0 +1,25 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// QuorumWeight returns the minimum weight that is more than [quorum] of
+// [totalWeight]. [quorum] is expected to be in [0, 1).
+//
+// The weight is calculated exactly using the decimal representation of
+// [quorum], so a quorum of 0.5 always requires a strict majority of
+// [totalWeight] and a quorum of 0.99 requires more than 99% of it.
+func QuorumWeight(totalWeight uint64, quorum float64) uint64 {
+	quorumRat, _ := new(big.Rat).SetString(strconv.FormatFloat(quorum, 'f', -1, 64))
+	weight := new(big.Rat).SetUint64(totalWeight)
+	weight.Mul(weight, quorumRat)
+
+	// Round down to the largest weight that is not more than the quorum
+	floor := new(big.Int).Quo(weight.Num(), weight.Denom())
+	return floor.Uint64() + 1
+}
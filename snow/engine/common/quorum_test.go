@@ -0,0 +1,30 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuorumWeight(t *testing.T) {
+	tests := []struct {
+		totalWeight uint64
+		quorum      float64
+		expected    uint64
+	}{
+		{totalWeight: 0, quorum: 0.5, expected: 1},
+		{totalWeight: 1, quorum: 0.5, expected: 1},
+		{totalWeight: 4, quorum: 0.5, expected: 3},
+		{totalWeight: 5, quorum: 0.5, expected: 3},
+		{totalWeight: 100, quorum: 0.67, expected: 68},
+		{totalWeight: 100, quorum: 0.99, expected: 100},
+		{totalWeight: math.MaxUint64, quorum: 0.5, expected: math.MaxUint64/2 + 1},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, QuorumWeight(test.totalWeight, test.quorum), "weight %d quorum %f", test.totalWeight, test.quorum)
+	}
+}
@@ -96,6 +96,22 @@ type QuerySender interface {
 
 	// Send chits to the specified node
 	SendChits(nodeID ids.ShortID, requestID uint32, votes []ids.ID)
+
+	// Request from the specified node its preferred frontier, given the
+	// existence of each of [containers]. The query for [containers[i]] has
+	// request ID [requestIDs[i]]. If the node doesn't support batched queries,
+	// it is sent one PushQuery per container instead.
+	SendPushQueryBatch(
+		nodeID ids.ShortID,
+		requestIDs []uint32,
+		containerIDs []ids.ID,
+		containers [][]byte,
+	)
+
+	// Send chits answering several queries of the specified node at once. The
+	// request with ID [requestIDs[i]] is answered with the single vote
+	// [votes[i]].
+	SendChitsBatch(nodeID ids.ShortID, requestIDs []uint32, votes []ids.ID)
 }
 
 // Gossiper defines how a consensus engine gossips a container on the accepted
@@ -124,5 +140,7 @@ type AppSender interface {
 	// Gossip an application-level message.
 	// A non-nil error should be considered fatal.
 	SendAppGossip(appGossipBytes []byte) error
+	// Gossip an application-level message to the nodes in [nodeIDs] only.
+	// A non-nil error should be considered fatal.
 	SendAppGossipSpecific(nodeIDs ids.ShortSet, appGossipBytes []byte) error
 }
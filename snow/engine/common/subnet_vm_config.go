@@ -0,0 +1,13 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// SubnetVMConfigurable is implemented by VMs that can apply a new config
+// published by the subnet of their chain without being restarted. The config a
+// VM is initialized with is in snow.Context.SubnetVMConfig.
+type SubnetVMConfigurable interface {
+	// SetSubnetVMConfig is called, with the context lock held, once the
+	// subnet of the chain published a new config on the P-chain.
+	SetSubnetVMConfig(config []byte) error
+}
@@ -25,6 +25,7 @@ type SenderTest struct {
 	CantSendGetAccepted, CantSendAccepted,
 	CantSendGet, CantSendGetAncestors, CantSendPut, CantSendAncestors,
 	CantSendPullQuery, CantSendPushQuery, CantSendChits,
+	CantSendPushQueryBatch, CantSendChitsBatch,
 	CantSendGossip,
 	CantSendAppRequest, CantSendAppResponse, CantSendAppGossip, CantSendAppGossipSpecific bool
 
@@ -39,6 +40,8 @@ type SenderTest struct {
 	SendPushQueryF           func(ids.ShortSet, uint32, ids.ID, []byte)
 	SendPullQueryF           func(ids.ShortSet, uint32, ids.ID)
 	SendChitsF               func(ids.ShortID, uint32, []ids.ID)
+	SendPushQueryBatchF      func(ids.ShortID, []uint32, []ids.ID, [][]byte)
+	SendChitsBatchF          func(ids.ShortID, []uint32, []ids.ID)
 	SendGossipF              func(ids.ID, []byte)
 	SendAppRequestF          func(ids.ShortSet, uint32, []byte) error
 	SendAppResponseF         func(ids.ShortID, uint32, []byte) error
@@ -59,6 +62,8 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantSendPullQuery = cant
 	s.CantSendPushQuery = cant
 	s.CantSendChits = cant
+	s.CantSendPushQueryBatch = cant
+	s.CantSendChitsBatch = cant
 	s.CantSendGossip = cant
 	s.CantSendAppRequest = cant
 	s.CantSendAppResponse = cant
@@ -187,6 +192,28 @@ func (s *SenderTest) SendChits(vdr ids.ShortID, requestID uint32, votes []ids.ID
 	}
 }
 
+// SendPushQueryBatch calls SendPushQueryBatchF if it was initialized. If it
+// wasn't initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) SendPushQueryBatch(vdr ids.ShortID, requestIDs []uint32, containerIDs []ids.ID, containers [][]byte) {
+	if s.SendPushQueryBatchF != nil {
+		s.SendPushQueryBatchF(vdr, requestIDs, containerIDs, containers)
+	} else if s.CantSendPushQueryBatch && s.T != nil {
+		s.T.Fatalf("Unexpectedly called SendPushQueryBatch")
+	}
+}
+
+// SendChitsBatch calls SendChitsBatchF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) SendChitsBatch(vdr ids.ShortID, requestIDs []uint32, votes []ids.ID) {
+	if s.SendChitsBatchF != nil {
+		s.SendChitsBatchF(vdr, requestIDs, votes)
+	} else if s.CantSendChitsBatch && s.T != nil {
+		s.T.Fatalf("Unexpectedly called SendChitsBatch")
+	}
+}
+
 // SendGossip calls SendGossipF if it was initialized. If it wasn't initialized
 // and this function shouldn't be called and testing was initialized, then
 // testing will fail.
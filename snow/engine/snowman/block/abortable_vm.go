@@ -0,0 +1,37 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+var ErrAbortableVMNotImplemented = errors.New("vm does not implement AbortableChainVM interface")
+
+// AbortableChainVM extends ChainVM to allow aborting a block that takes too
+// long to build.
+type AbortableChainVM interface {
+	// BuildBlockWithContext attempts to build a block like BuildBlock. Once
+	// [ctx] is done, the build should be aborted and ctx.Err() returned.
+	//
+	// When the build is aborted, the transactions that would have been
+	// included in the block must be kept pending, so that they're included in
+	// a later block.
+	BuildBlockWithContext(ctx context.Context) (snowman.Block, error)
+}
+
+// BuildBlock builds a block with [vm], aborting the build once [ctx] is done
+// if [vm] supports it. Otherwise, [ctx] is ignored.
+func BuildBlock(ctx context.Context, vm ChainVM) (snowman.Block, error) {
+	if vm, ok := vm.(AbortableChainVM); ok {
+		blk, err := vm.BuildBlockWithContext(ctx)
+		if err != ErrAbortableVMNotImplemented {
+			return blk, err
+		}
+	}
+	return vm.BuildBlock()
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+var ErrChainVerifierVMNotImplemented = errors.New("vm does not implement ChainVerifierVM interface")
+
+// ChainVerifierVM extends ChainVM to allow verifying a batch of sequentially
+// dependent blocks at once, sharing the work the blocks have in common.
+type ChainVerifierVM interface {
+	// VerifyChain verifies [blks], which are ordered by increasing height
+	// with each block being the parent of the next one. The first block's
+	// parent may not be known yet.
+	//
+	// VerifyChain allows rejecting an invalid batch early. It doesn't replace
+	// Verify, which must still be called on each block before it's accepted.
+	VerifyChain(blks []snowman.Block) error
+}
+
+// VerifyChain verifies [blks] with [vm] if [vm] supports it. Otherwise, nil is
+// returned.
+func VerifyChain(vm ChainVM, blks []snowman.Block) error {
+	if vm, ok := vm.(ChainVerifierVM); ok {
+		if err := vm.VerifyChain(blks); err != ErrChainVerifierVMNotImplemented {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+// PreparingChainVM extends ChainVM for VMs that can do the part of parsing a
+// block that doesn't depend on their state ahead of time, without the chain's
+// lock held.
+type PreparingChainVM interface {
+	// PrepareBlock prepares [blkBytes] to be parsed, so that a later
+	// ParseBlock of the same bytes is cheaper. It may be called concurrently
+	// with any other method, including itself.
+	PrepareBlock(blkBytes []byte)
+}
+
+// PrepareBlock prepares [blkBytes] to be parsed by [vm], if [vm] supports it
+func PrepareBlock(vm ChainVM, blkBytes []byte) {
+	if vm, ok := vm.(PreparingChainVM); ok {
+		vm.PrepareBlock(blkBytes)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ScheduledChainVM extends ChainVM to be notified of when this node may next
+// propose a block. This allows the VM to prepare the block ahead of time, so
+// that it can be built as soon as the node's proposer window starts.
+type ScheduledChainVM interface {
+	// SetProposerSlot is called when the preferred block changes, with the ID
+	// of the preferred block and the earliest time this node may propose a
+	// child of it.
+	//
+	// The slot is a hint: the VM may still be asked to build a block at any
+	// time, and the slot may be replaced before it starts.
+	SetProposerSlot(parentID ids.ID, slotStart time.Time) error
+}
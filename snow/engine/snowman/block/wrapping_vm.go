@@ -0,0 +1,36 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+var ErrWrappingVMNotImplemented = errors.New("vm does not implement WrappingChainVM interface")
+
+// WrappingChainVM extends ChainVM for VMs whose blocks wrap the blocks of
+// another VM. Peers may request a wrapping block by the ID of the block it
+// wraps.
+type WrappingChainVM interface {
+	// GetWrappingBlock returns the block with [blkID]. If [blkID] is the ID of
+	// a wrapped block, the block that wraps it is returned instead, so the
+	// returned block's ID may differ from [blkID].
+	GetWrappingBlock(blkID ids.ID) (snowman.Block, error)
+}
+
+// GetWrappingBlock returns the block that [vm] serves to peers requesting
+// [blkID]. If [vm] doesn't wrap the blocks of another VM, this is the block
+// with [blkID].
+func GetWrappingBlock(vm Getter, blkID ids.ID) (snowman.Block, error) {
+	if vm, ok := vm.(WrappingChainVM); ok {
+		blk, err := vm.GetWrappingBlock(blkID)
+		if err != ErrWrappingVMNotImplemented {
+			return blk, err
+		}
+	}
+	return vm.GetBlock(blkID)
+}
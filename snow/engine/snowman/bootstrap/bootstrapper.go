@@ -24,6 +24,7 @@ const bootstrappingDelay = 10 * time.Second
 
 var (
 	_ common.BootstrapableEngine = &bootstrapper{}
+	_ common.ContainerPreparer   = &bootstrapper{}
 
 	errUnexpectedTimeout = errors.New("unexpected timeout fired")
 )
@@ -69,8 +70,8 @@ func New(config Config, onFinished func(lastReqID uint32) error) (common.Bootstr
 	}
 
 	config.Bootstrapable = b
-	b.Bootstrapper = common.NewCommonBootstrapper(config.Config)
-	return b, nil
+	b.Bootstrapper, err = common.NewCommonBootstrapper(config.Config)
+	return b, err
 }
 
 type bootstrapper struct {
@@ -107,6 +108,17 @@ type bootstrapper struct {
 	awaitingTimeout bool
 }
 
+// PrepareContainers prepares the blocks in an Ancestors message to be parsed.
+// It only touches the VM, so it's safe to call without the context lock held.
+func (b *bootstrapper) PrepareContainers(blks [][]byte) {
+	if len(blks) > b.Config.AncestorsMaxContainersReceived {
+		blks = blks[:b.Config.AncestorsMaxContainersReceived]
+	}
+	for _, blkBytes := range blks {
+		block.PrepareBlock(b.VM, blkBytes)
+	}
+}
+
 // Ancestors handles the receipt of multiple containers. Should be received in response to a GetAncestors message to [vdr]
 // with request ID [requestID]
 func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, blks [][]byte) error {
@@ -146,6 +158,17 @@ func (b *bootstrapper) Ancestors(vdr ids.ShortID, requestID uint32, blks [][]byt
 		return b.fetch(wantedBlkID)
 	}
 
+	// The blocks are ordered by decreasing height, each being the parent of
+	// the previous one, so they're verified in reverse
+	chain := make([]snowman.Block, len(blocks))
+	for i, blk := range blocks {
+		chain[len(blocks)-1-i] = blk
+	}
+	if err := block.VerifyChain(b.VM, chain); err != nil {
+		b.Ctx.Log.Debug("failed to verify blocks in Ancestors from %s with ID %d: %s", vdr, requestID, err)
+		return b.fetch(wantedBlkID)
+	}
+
 	blockSet := make(map[ids.ID]snowman.Block, len(blocks))
 	for _, block := range blocks[1:] {
 		blockSet[block.ID()] = block
@@ -195,7 +218,7 @@ func (b *bootstrapper) Timeout() error {
 	}
 	b.awaitingTimeout = false
 
-	if !b.Config.Subnet.IsBootstrapped() {
+	if b.Config.BootstrapOnly || !b.Config.Subnet.IsBootstrapped() {
 		return b.Restart(true)
 	}
 	return b.finish()
@@ -312,6 +335,17 @@ func (b *bootstrapper) Clear() error {
 	return b.Config.Blocked.Commit()
 }
 
+// compactJobs removes the jobs left in the queue, which can no longer be
+// executed once the chain has synced, and compacts the queue's database, so
+// that the space of the jobs is reclaimed after bootstrapping.
+func (b *bootstrapper) compactJobs() error {
+	if err := b.Clear(); err != nil {
+		return err
+	}
+	b.Ctx.Log.Debug("compacting the bootstrapping job queue")
+	return b.Blocked.Compact()
+}
+
 // process a block
 func (b *bootstrapper) process(blk snowman.Block, processingBlocks map[ids.ID]snowman.Block) error {
 	status := blk.Status()
@@ -380,6 +414,16 @@ func (b *bootstrapper) process(blk snowman.Block, processingBlocks map[ids.ID]sn
 				b.Ctx.Log.Debug("fetched %d of %d blocks. ETA = %s", blocksFetchedSoFar, totalBlocksToFetch, eta)
 			}
 		}
+
+		// Spill the pushed blocks to disk once they exceed the memory budget
+		// of the queue. [blkID] is marked as missing so that the traversal is
+		// resumed from it if the node restarts before it's pushed.
+		if status == choices.Processing && b.Blocked.ShouldCommit() {
+			b.Blocked.AddMissingID(blkID)
+			if err := b.Blocked.Commit(); err != nil {
+				return err
+			}
+		}
 	}
 
 	switch status {
@@ -446,6 +490,18 @@ func (b *bootstrapper) checkFinish() error {
 	// Notify the subnet that this chain is synced
 	b.Config.Subnet.Bootstrapped(b.Ctx.ChainID)
 
+	// If this chain only serves bootstrapping, it should remain syncing.
+	if b.Config.BootstrapOnly {
+		if !b.Config.SharedCfg.Restarted {
+			b.Ctx.Log.Info("finished syncing, serving bootstrapping without running consensus")
+		}
+		// Restart bootstrapping after [bootstrappingDelay] to keep up to date
+		// on the latest tip.
+		b.Config.Timer.RegisterTimeout(bootstrappingDelay)
+		b.awaitingTimeout = true
+		return nil
+	}
+
 	// If the subnet hasn't finished bootstrapping, this chain should remain
 	// syncing.
 	if !b.Config.Subnet.IsBootstrapped() {
@@ -465,6 +521,9 @@ func (b *bootstrapper) checkFinish() error {
 }
 
 func (b *bootstrapper) finish() error {
+	if err := b.compactJobs(); err != nil {
+		return err
+	}
 	if err := b.VM.SetState(snow.NormalOp); err != nil {
 		return fmt.Errorf("failed to notify VM that bootstrapping has finished: %w",
 			err)
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"gotest.tools/assert"
@@ -168,6 +169,105 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	}
 }
 
+func TestBootstrapperBootstrapOnly(t *testing.T) {
+	config, _, _, vm := newConfig(t)
+	config.BootstrapOnly = true
+
+	numTimeouts := 0
+	config.Timer = &common.TimerTest{
+		RegisterTimeoutF: func(time.Duration) { numTimeouts++ },
+	}
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(0),
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+		BytesV:  []byte{0},
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0.IDV,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.CantLastAccepted = false
+	vm.LastAcceptedF = func() (ids.ID, error) { return blk0.ID(), nil }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case blk0.ID():
+			return blk0, nil
+		case blk1.ID():
+			return blk1, nil
+		default:
+			t.Fatal(errUnknownBlock)
+			panic(errUnknownBlock)
+		}
+	}
+
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(blkBytes, blk0.Bytes()):
+			return blk0, nil
+		case bytes.Equal(blkBytes, blk1.Bytes()):
+			return blk1, nil
+		default:
+			t.Fatal(errUnknownBlock)
+			return nil, errUnknownBlock
+		}
+	}
+	vm.SetStateF = func(state snow.State) error {
+		if state == snow.NormalOp {
+			t.Fatal("bootstrapping shouldn't finish")
+		}
+		return nil
+	}
+
+	bs, err := New(
+		config,
+		func(uint32) error {
+			t.Fatal("bootstrapping shouldn't finish")
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Start(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The accepted blocks are fetched, but consensus isn't started
+	if err := bs.ForceAccepted([]ids.ID{blk1.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if blk1.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+	if config.Ctx.GetState() == snow.NormalOp {
+		t.Fatalf("Bootstrapping shouldn't have finished")
+	}
+	if numTimeouts != 1 {
+		t.Fatalf("Bootstrapping should be restarted after a delay")
+	}
+
+	// Bootstrapping is restarted rather than finished
+	if err := bs.Timeout(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.ForceAccepted([]ids.ID{blk1.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if numTimeouts != 2 {
+		t.Fatalf("Bootstrapping should be restarted after a delay")
+	}
+}
+
 // Requests the unknown block and gets back a Ancestors with unexpected request ID.
 // Requests again and gets response from unexpected peer.
 // Requests again and gets an unexpected block.
@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+// Behavior is how a beacon responds to the requests of the bootstrapping node
+type Behavior int
+
+const (
+	// Honest beacons respond truthfully with the blocks they accepted
+	Honest Behavior = iota
+	// Silent beacons never respond, so that the requests sent to them time
+	// out
+	Silent
+	// Forger beacons advertise and vote for blocks that don't exist, and never
+	// send blocks
+	Forger
+	// Garbage beacons respond truthfully to the requests for the accepted
+	// frontier, but send blocks that can't be parsed
+	Garbage
+)
+
+// Period is a period of simulated time, from the start of the simulation
+type Period struct {
+	Start, End time.Duration
+}
+
+// Contains returns true if [t] is in the period
+func (p Period) Contains(t time.Duration) bool {
+	return p.Start <= t && t < p.End
+}
+
+// Latency bounds the time a message takes to be delivered. The latency of
+// each message is sampled uniformly in [Min, Max].
+type Latency struct {
+	Min, Max time.Duration
+}
+
+func (l Latency) sample(rng *rand.Rand) time.Duration {
+	if l.Max <= l.Min {
+		return l.Min
+	}
+	return l.Min + time.Duration(rng.Int63n(int64(l.Max-l.Min)+1))
+}
+
+// Beacon describes a peer the bootstrapping node bootstraps from
+type Beacon struct {
+	// Weight of the beacon. Defaults to 1.
+	Weight uint64
+	// Behavior of the beacon
+	Behavior Behavior
+	// Lag is the number of blocks of the simulated chain the beacon didn't
+	// accept yet. Beacons that lag behind advertise an older accepted
+	// frontier.
+	Lag uint64
+	// Periods during which the beacon is offline. The messages that reach the
+	// beacon while it's offline are dropped.
+	Offline []Period
+	// Latency of the messages sent to and by the beacon. Defaults to the
+	// latency of the network.
+	Latency *Latency
+}
+
+// beacon is the simulated state of a Beacon
+type beacon struct {
+	Beacon
+	nodeID ids.ShortID
+	chain  *chain
+	// height of the last block the beacon accepted
+	height uint64
+}
+
+func (b *beacon) online(t time.Duration) bool {
+	for _, period := range b.Offline {
+		if period.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptedFrontier returns the response of the beacon to a
+// GetAcceptedFrontier, or false if the beacon doesn't respond
+func (b *beacon) acceptedFrontier(rng *rand.Rand) ([]ids.ID, bool) {
+	switch b.Behavior {
+	case Honest, Garbage:
+		return []ids.ID{b.chain.blocks[b.height].ID()}, true
+	case Forger:
+		return []ids.ID{forgedID(rng)}, true
+	default:
+		return nil, false
+	}
+}
+
+// accepted returns the response of the beacon to a GetAccepted of
+// [containerIDs], or false if the beacon doesn't respond
+func (b *beacon) accepted(containerIDs []ids.ID) ([]ids.ID, bool) {
+	switch b.Behavior {
+	case Honest, Garbage:
+		accepted := []ids.ID{}
+		for _, containerID := range containerIDs {
+			if height, ok := b.chain.heights[containerID]; ok && height <= b.height {
+				accepted = append(accepted, containerID)
+			}
+		}
+		return accepted, true
+	case Forger:
+		return containerIDs, true
+	default:
+		return nil, false
+	}
+}
+
+// ancestors returns the response of the beacon to a GetAncestors of [blkID],
+// or false if the beacon doesn't respond
+func (b *beacon) ancestors(blkID ids.ID, maxContainers int, rng *rand.Rand) ([][]byte, bool) {
+	switch b.Behavior {
+	case Honest:
+		height, ok := b.chain.heights[blkID]
+		if !ok || height > b.height {
+			// Like a node that didn't accept the block, the beacon drops the
+			// request
+			return nil, false
+		}
+		blks := [][]byte{}
+		for i := int(height); i >= 0 && len(blks) < maxContainers; i-- {
+			blks = append(blks, b.chain.blocks[i].Bytes())
+		}
+		return blks, true
+	case Garbage:
+		garbage := make([]byte, 32)
+		_, _ = rng.Read(garbage)
+		return [][]byte{garbage}, true
+	default:
+		return nil, false
+	}
+}
+
+func forgedID(rng *rand.Rand) ids.ID {
+	id := ids.ID{}
+	_, _ = rng.Read(id[:])
+	return id
+}
+
+// chain is the chain of blocks accepted by the beacons
+type chain struct {
+	blocks  []snowman.Block
+	heights map[ids.ID]uint64
+}
+
+func newChain(blocks []snowman.Block) *chain {
+	c := &chain{
+		blocks:  blocks,
+		heights: make(map[ids.ID]uint64, len(blocks)),
+	}
+	for height, blk := range blocks {
+		c.heights[blk.ID()] = uint64(height)
+	}
+	return c
+}
@@ -0,0 +1,405 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package simulator runs the snowman bootstrapper against a simulated network
+// of beacons, with configurable latency, churn and adversarial beacons. The
+// simulation runs on a simulated clock, so that long timeouts don't slow it
+// down, and every random choice of the simulation is drawn from its seed.
+//
+// VM developers can bootstrap their own VM in the simulation by passing its
+// blocks and the VM in the Config.
+package simulator
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/queue"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracker"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+
+	snowgetter "github.com/ava-labs/avalanchego/snow/engine/snowman/getter"
+)
+
+const (
+	defaultRequestTimeout = 2 * time.Second
+	defaultMaxTime        = time.Hour
+	defaultMaxContainers  = 2000
+)
+
+var (
+	errNoBlocks      = errors.New("the simulated chain must contain at least its genesis block")
+	errNoBeacons     = errors.New("the simulated network must contain at least one beacon")
+	errLagAboveChain = errors.New("beacon's lag is above the height of the simulated chain")
+)
+
+// Config describes a simulated network
+type Config struct {
+	// Seed of the random choices of the simulation. The bootstrapper samples
+	// the beacons it fetches blocks from with the global sampler, which is
+	// seeded with it too.
+	Seed int64
+
+	// Blocks is the chain the beacons accepted. Blocks[0] must be the last
+	// block VM accepted, and each block is the parent of the next one.
+	Blocks []snowman.Block
+	// VM of the bootstrapping node. It must parse the blocks of [Blocks].
+	VM block.ChainVM
+
+	// Beacons the node bootstraps from
+	Beacons []Beacon
+	// Latency of the messages sent to and by the beacons that don't specify
+	// their own latency
+	Latency Latency
+	// Time after which a request is considered failed. Defaults to 2s.
+	RequestTimeout time.Duration
+	// Simulated time after which the simulation stops, if the node didn't
+	// finish bootstrapping. Defaults to 1h.
+	MaxTime time.Duration
+	// Maximum number of blocks sent in response to a GetAncestors. Defaults
+	// to 2000.
+	MaxContainers int
+
+	// RetryBootstrap makes the node restart bootstrapping when too few
+	// beacons respond, as set by the node's retry-bootstrap flag
+	RetryBootstrap bool
+}
+
+// Result describes how the bootstrapping node behaved in a simulation
+type Result struct {
+	// Finished is true if the node finished bootstrapping
+	Finished bool
+	// Time is the simulated time at which the simulation ended
+	Time time.Duration
+	// LastAccepted is the ID of the last block the node accepted
+	LastAccepted ids.ID
+	// NumRequests is the number of requests the node sent
+	NumRequests int
+	// NumTimeouts is the number of requests of the node that timed out
+	NumTimeouts int
+}
+
+// Run bootstraps [config.VM] in the network described by [config]
+func Run(config Config) (Result, error) {
+	if len(config.Blocks) == 0 {
+		return Result{}, errNoBlocks
+	}
+	if len(config.Beacons) == 0 {
+		return Result{}, errNoBeacons
+	}
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = defaultRequestTimeout
+	}
+	if config.MaxTime == 0 {
+		config.MaxTime = defaultMaxTime
+	}
+	if config.MaxContainers == 0 {
+		config.MaxContainers = defaultMaxContainers
+	}
+
+	n := &network{
+		config:   config,
+		rng:      rand.New(rand.NewSource(config.Seed)), // #nosec G404
+		beacons:  make(map[ids.ShortID]*beacon, len(config.Beacons)),
+		requests: make(map[request]bool),
+	}
+	sampler.Seed(config.Seed)
+	if err := n.initBootstrapper(); err != nil {
+		return Result{}, err
+	}
+
+	if err := n.bs.Start(0); err != nil {
+		return Result{}, err
+	}
+	for !n.finished && n.events.Len() > 0 {
+		e := heap.Pop(&n.events).(*event)
+		if e.time > n.config.MaxTime {
+			break
+		}
+		n.now = e.time
+		if err := e.run(); err != nil {
+			return Result{}, err
+		}
+	}
+
+	lastAccepted, err := config.VM.LastAccepted()
+	return Result{
+		Finished:     n.finished,
+		Time:         n.now,
+		LastAccepted: lastAccepted,
+		NumRequests:  n.numRequests,
+		NumTimeouts:  n.numTimeouts,
+	}, err
+}
+
+type network struct {
+	config Config
+	rng    *rand.Rand
+	chain  *chain
+
+	beacons      map[ids.ShortID]*beacon
+	beaconIDs    []ids.ShortID
+	bs           common.BootstrapableEngine
+	bootstrapped bool
+	finished     bool
+
+	now    time.Duration
+	events events
+	seq    uint64
+
+	// requests maps the requests of the node to whether they were answered
+	// or failed
+	requests    map[request]bool
+	numRequests int
+	numTimeouts int
+}
+
+type request struct {
+	op        string
+	nodeID    ids.ShortID
+	requestID uint32
+}
+
+func (n *network) initBootstrapper() error {
+	n.chain = newChain(n.config.Blocks)
+	maxHeight := uint64(len(n.config.Blocks) - 1)
+	vdrs := validators.NewSet()
+	for i, b := range n.config.Beacons {
+		nodeID := ids.ShortID{}
+		nodeID[0] = byte(i >> 8)
+		nodeID[1] = byte(i)
+		if b.Weight == 0 {
+			b.Weight = 1
+		}
+		if b.Lag > maxHeight {
+			return fmt.Errorf("%w: %d > %d", errLagAboveChain, b.Lag, maxHeight)
+		}
+		if b.Latency == nil {
+			b.Latency = &n.config.Latency
+		}
+		if err := vdrs.AddWeight(nodeID, b.Weight); err != nil {
+			return err
+		}
+		n.beacons[nodeID] = &beacon{
+			Beacon: b,
+			nodeID: nodeID,
+			chain:  n.chain,
+			height: maxHeight - b.Lag,
+		}
+		n.beaconIDs = append(n.beaconIDs, nodeID)
+	}
+
+	sender := &common.SenderTest{}
+	sender.Default(false)
+	sender.SendGetAcceptedFrontierF = func(nodeIDs ids.ShortSet, requestID uint32) {
+		n.sendAll("GetAcceptedFrontier", nodeIDs, requestID, func(b *beacon) func() error {
+			frontier, ok := b.acceptedFrontier(n.rng)
+			if !ok {
+				return nil
+			}
+			return func() error { return n.bs.AcceptedFrontier(b.nodeID, requestID, frontier) }
+		}, n.bsGetAcceptedFrontierFailed)
+	}
+	sender.SendGetAcceptedF = func(nodeIDs ids.ShortSet, requestID uint32, containerIDs []ids.ID) {
+		n.sendAll("GetAccepted", nodeIDs, requestID, func(b *beacon) func() error {
+			accepted, ok := b.accepted(containerIDs)
+			if !ok {
+				return nil
+			}
+			return func() error { return n.bs.Accepted(b.nodeID, requestID, accepted) }
+		}, n.bsGetAcceptedFailed)
+	}
+	sender.SendGetAncestorsF = func(nodeID ids.ShortID, requestID uint32, blkID ids.ID) {
+		n.send("GetAncestors", nodeID, requestID, func(b *beacon) func() error {
+			blks, ok := b.ancestors(blkID, n.config.MaxContainers, n.rng)
+			if !ok {
+				return nil
+			}
+			return func() error { return n.bs.Ancestors(b.nodeID, requestID, blks) }
+		}, n.bsGetAncestorsFailed)
+	}
+
+	subnet := &common.SubnetTest{
+		IsBootstrappedF: func() bool { return n.bootstrapped },
+		BootstrappedF:   func(ids.ID) { n.bootstrapped = true },
+	}
+	timer := &common.TimerTest{
+		RegisterTimeoutF: func(d time.Duration) {
+			n.schedule(d, n.bsTimeout)
+		},
+	}
+
+	commonConfig := common.Config{
+		Ctx:                            snow.DefaultConsensusContextTest(),
+		Validators:                     vdrs,
+		Beacons:                        vdrs,
+		SampleK:                        vdrs.Len(),
+		Alpha:                          vdrs.Weight()/2 + 1,
+		Sender:                         sender,
+		Subnet:                         subnet,
+		Timer:                          timer,
+		RetryBootstrap:                 n.config.RetryBootstrap,
+		RetryBootstrapWarnFrequency:    50,
+		AncestorsMaxContainersSent:     n.config.MaxContainers,
+		AncestorsMaxContainersReceived: n.config.MaxContainers,
+		SharedCfg:                      &common.SharedConfig{},
+	}
+	getter, err := snowgetter.New(n.config.VM, commonConfig)
+	if err != nil {
+		return err
+	}
+	blocked, err := queue.NewWithMissing(memdb.New(), "", prometheus.NewRegistry())
+	if err != nil {
+		return err
+	}
+	n.bs, err = bootstrap.New(
+		bootstrap.Config{
+			Config:        commonConfig,
+			AllGetsServer: getter,
+			Blocked:       blocked,
+			VM:            n.config.VM,
+			WeightTracker: tracker.NewWeightTracker(vdrs, commonConfig.StartupAlpha),
+		},
+		func(uint32) error {
+			n.finished = true
+			return nil
+		},
+	)
+	return err
+}
+
+// sendAll sends a request to each of [nodeIDs], in the order of their IDs so
+// that the simulation doesn't depend on the iteration order of the set
+func (n *network) sendAll(
+	op string,
+	nodeIDs ids.ShortSet,
+	requestID uint32,
+	respond func(*beacon) func() error,
+	failed func(ids.ShortID, uint32) error,
+) {
+	for _, nodeID := range n.beaconIDs {
+		if nodeIDs.Contains(nodeID) {
+			n.send(op, nodeID, requestID, respond, failed)
+		}
+	}
+}
+
+// send delivers a request to [nodeID] after its latency, and [nodeID]'s
+// response after another latency. If no response is delivered within the
+// request timeout, [failed] is called.
+func (n *network) send(
+	op string,
+	nodeID ids.ShortID,
+	requestID uint32,
+	respond func(*beacon) func() error,
+	failed func(ids.ShortID, uint32) error,
+) {
+	n.numRequests++
+	req := request{
+		op:        op,
+		nodeID:    nodeID,
+		requestID: requestID,
+	}
+	n.requests[req] = false
+	n.schedule(n.config.RequestTimeout, func() error {
+		if n.requests[req] {
+			return nil
+		}
+		n.requests[req] = true
+		n.numTimeouts++
+		return failed(nodeID, requestID)
+	})
+
+	b, ok := n.beacons[nodeID]
+	if !ok {
+		return
+	}
+	n.schedule(b.Latency.sample(n.rng), func() error {
+		if !b.online(n.now) {
+			return nil
+		}
+		response := respond(b)
+		if response == nil {
+			return nil
+		}
+		n.schedule(b.Latency.sample(n.rng), func() error {
+			if n.requests[req] {
+				// The request already timed out
+				return nil
+			}
+			n.requests[req] = true
+			return response()
+		})
+		return nil
+	})
+}
+
+func (n *network) bsGetAcceptedFrontierFailed(nodeID ids.ShortID, requestID uint32) error {
+	return n.bs.GetAcceptedFrontierFailed(nodeID, requestID)
+}
+
+func (n *network) bsGetAcceptedFailed(nodeID ids.ShortID, requestID uint32) error {
+	return n.bs.GetAcceptedFailed(nodeID, requestID)
+}
+
+func (n *network) bsGetAncestorsFailed(nodeID ids.ShortID, requestID uint32) error {
+	return n.bs.GetAncestorsFailed(nodeID, requestID)
+}
+
+func (n *network) bsTimeout() error {
+	return n.bs.Timeout()
+}
+
+// schedule runs [f] after [delay] of simulated time
+func (n *network) schedule(delay time.Duration, f func() error) {
+	n.seq++
+	heap.Push(&n.events, &event{
+		time: n.now + delay,
+		seq:  n.seq,
+		run:  f,
+	})
+}
+
+type event struct {
+	time time.Duration
+	// seq orders the events scheduled at the same time by when they were
+	// scheduled
+	seq uint64
+	run func() error
+}
+
+// events is a min-heap of events ordered by time
+type events []*event
+
+func (e events) Len() int { return len(e) }
+
+func (e events) Less(i, j int) bool {
+	if e[i].time != e[j].time {
+		return e[i].time < e[j].time
+	}
+	return e[i].seq < e[j].seq
+}
+
+func (e events) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+func (e *events) Push(x interface{}) { *e = append(*e, x.(*event)) }
+
+func (e *events) Pop() interface{} {
+	old := *e
+	last := old[len(old)-1]
+	*e = old[:len(old)-1]
+	return last
+}
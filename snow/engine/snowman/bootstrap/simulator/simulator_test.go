@@ -0,0 +1,169 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const chainLength = 5000
+
+var defaultLatency = Latency{
+	Min: 10 * time.Millisecond,
+	Max: 200 * time.Millisecond,
+}
+
+func honestBeacons(num int) []Beacon {
+	return make([]Beacon, num)
+}
+
+func TestBootstrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		beacons []Beacon
+		// lag of the block the node is expected to accept
+		expectedLag int
+	}{
+		{
+			name:    "honest beacons",
+			beacons: honestBeacons(5),
+		},
+		{
+			name: "lagging minority",
+			beacons: append(honestBeacons(3),
+				Beacon{Lag: 100},
+				Beacon{Lag: 4000},
+			),
+		},
+		{
+			name: "lagging majority",
+			beacons: append(honestBeacons(2),
+				Beacon{Lag: 100},
+				Beacon{Lag: 100},
+				Beacon{Lag: 100},
+			),
+			expectedLag: 100,
+		},
+		{
+			name: "silent minority",
+			beacons: append(honestBeacons(3),
+				Beacon{Behavior: Silent},
+				Beacon{Behavior: Silent},
+			),
+		},
+		{
+			name: "forger minority",
+			beacons: append(honestBeacons(3),
+				Beacon{Behavior: Forger},
+				Beacon{Behavior: Forger},
+			),
+		},
+		{
+			name: "garbage minority",
+			beacons: append(honestBeacons(3),
+				Beacon{Behavior: Garbage},
+				Beacon{Behavior: Garbage},
+			),
+		},
+		{
+			name: "heavy forger",
+			beacons: append(honestBeacons(3),
+				Beacon{Behavior: Forger, Weight: 2},
+			),
+		},
+		{
+			name: "slow beacon",
+			beacons: append(honestBeacons(3),
+				Beacon{Latency: &Latency{Min: 5 * time.Second, Max: 10 * time.Second}},
+			),
+		},
+		{
+			name: "churn",
+			beacons: []Beacon{
+				{Offline: []Period{{Start: 0, End: time.Minute}}},
+				{Offline: []Period{{Start: 30 * time.Second, End: 2 * time.Minute}}},
+				{Offline: []Period{{Start: time.Second, End: 5 * time.Second}, {Start: 10 * time.Second, End: time.Hour}}},
+				{},
+				{},
+			},
+		},
+	}
+	for _, test := range tests {
+		for seed := int64(0); seed < 3; seed++ {
+			t.Run(test.name, func(t *testing.T) {
+				assert := assert.New(t)
+
+				blocks, vm := NewTestChain(chainLength)
+				result, err := Run(Config{
+					Seed:           seed,
+					Blocks:         blocks,
+					VM:             vm,
+					Beacons:        test.beacons,
+					Latency:        defaultLatency,
+					MaxContainers:  500,
+					RetryBootstrap: true,
+				})
+				assert.NoError(err)
+				assert.True(result.Finished)
+				assert.Equal(blocks[chainLength-1-test.expectedLag].ID(), result.LastAccepted)
+			})
+		}
+	}
+}
+
+func TestBootstrapAllSilent(t *testing.T) {
+	assert := assert.New(t)
+
+	blocks, vm := NewTestChain(10)
+	result, err := Run(Config{
+		Blocks: blocks,
+		VM:     vm,
+		Beacons: []Beacon{
+			{Behavior: Silent},
+			{Behavior: Silent},
+		},
+		Latency:        defaultLatency,
+		MaxTime:        10 * time.Minute,
+		RetryBootstrap: true,
+	})
+	assert.NoError(err)
+	assert.False(result.Finished)
+	assert.Equal(blocks[0].ID(), result.LastAccepted)
+	assert.Equal(result.NumRequests, result.NumTimeouts+2)
+}
+
+func TestBootstrapDeterministicOutcome(t *testing.T) {
+	assert := assert.New(t)
+
+	run := func() Result {
+		blocks, vm := NewTestChain(100)
+		result, err := Run(Config{
+			Seed:    1,
+			Blocks:  blocks,
+			VM:      vm,
+			Beacons: honestBeacons(1),
+			Latency: defaultLatency,
+		})
+		assert.NoError(err)
+		return result
+	}
+	assert.Equal(run(), run())
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	blocks, vm := NewTestChain(10)
+	_, err := Run(Config{VM: vm, Beacons: honestBeacons(1)})
+	assert.ErrorIs(err, errNoBlocks)
+
+	_, err = Run(Config{Blocks: blocks, VM: vm})
+	assert.ErrorIs(err, errNoBeacons)
+
+	_, err = Run(Config{Blocks: blocks, VM: vm, Beacons: []Beacon{{Lag: 10}}})
+	assert.ErrorIs(err, errLagAboveChain)
+}
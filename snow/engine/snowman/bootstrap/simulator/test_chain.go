@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var (
+	errUnknownBlock = errors.New("unknown block")
+	errUnknownBytes = errors.New("bytes don't belong to a known block")
+)
+
+// NewTestChain returns a chain of [length] test blocks, starting with an
+// accepted genesis block, and a VM that has only accepted the genesis block.
+// The VM parses the bytes of the blocks of the chain.
+func NewTestChain(length int) ([]snowman.Block, block.ChainVM) {
+	blocks := make([]snowman.Block, length)
+	byBytes := make(map[string]*snowman.TestBlock, length)
+	byID := make(map[ids.ID]*snowman.TestBlock, length)
+	parentID := ids.Empty
+	for height := range blocks {
+		blkBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(blkBytes, uint64(height))
+		blk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(height)),
+				StatusV: choices.Processing,
+			},
+			ParentV: parentID,
+			HeightV: uint64(height),
+			BytesV:  blkBytes,
+		}
+		parentID = blk.IDV
+		blocks[height] = blk
+		byBytes[string(blkBytes)] = blk
+		byID[blk.IDV] = blk
+	}
+	if length > 0 {
+		blocks[0].(*snowman.TestBlock).StatusV = choices.Accepted
+	}
+
+	// The VM knows about the accepted blocks and the blocks it parsed
+	known := ids.Set{}
+	if length > 0 {
+		known.Add(blocks[0].ID())
+	}
+	vm := &block.TestVM{}
+	vm.LastAcceptedF = func() (ids.ID, error) {
+		lastAccepted := ids.Empty
+		for _, blk := range blocks {
+			if blk.Status() != choices.Accepted {
+				break
+			}
+			lastAccepted = blk.ID()
+		}
+		return lastAccepted, nil
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if !known.Contains(blkID) {
+			return nil, errUnknownBlock
+		}
+		return byID[blkID], nil
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		blk, ok := byBytes[string(blkBytes)]
+		if !ok {
+			return nil, errUnknownBytes
+		}
+		known.Add(blk.IDV)
+		return blk, nil
+	}
+	return blocks, vm
+}
@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
@@ -22,4 +23,30 @@ type Config struct {
 	Validators validators.Set
 	Params     snowball.Parameters
 	Consensus  snowman.Consensus
+
+	// IsReadOnly returns true while this engine should follow consensus
+	// without affecting it. A read only engine never builds blocks and
+	// responds to queries with its last accepted block, rather than its
+	// preference. If nil, the engine is never read only.
+	IsReadOnly func() bool
+
+	// ProcessingDB persists the blocks that are processing in consensus, so
+	// that they can be re-issued when the engine is restarted rather than
+	// being fetched from peers again. If nil, processing blocks aren't
+	// persisted.
+	ProcessingDB database.Database
+
+	// Timer schedules the retries of the blocks whose verification was
+	// deferred. If nil, they're only retried when another block is issued or
+	// accepted.
+	Timer common.Timer
+
+	// BatchQueries enables sending the push queries for the blocks issued
+	// while handling a single message to each validator in one message, rather
+	// than in one message per block.
+	BatchQueries bool
+}
+
+func (c *Config) readOnly() bool {
+	return c.IsReadOnly != nil && c.IsReadOnly()
 }
@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+// Diagnostics describes the state of a snowman engine, to help diagnose why it
+// isn't accepting blocks
+type Diagnostics struct {
+	LastAcceptedID     ids.ID      `json:"lastAcceptedID"`
+	LastAcceptedHeight json.Uint64 `json:"lastAcceptedHeight"`
+	Preference         ids.ID      `json:"preference"`
+	// Blocks issued to consensus that haven't been decided, sorted by height
+	ProcessingBlocks []ProcessingBlock `json:"processingBlocks"`
+	// Number of blocks waiting for their ancestors to be fetched before being
+	// issued to consensus
+	PendingBlocks int `json:"pendingBlocks"`
+	// Number of blocks requested from peers that haven't been received
+	OutstandingBlockRequests int `json:"outstandingBlockRequests"`
+	// Number of polls waiting for votes, and the votes they received so far
+	OutstandingPolls int    `json:"outstandingPolls"`
+	Polls            string `json:"polls"`
+}
+
+// ProcessingBlock describes a block that is being decided by consensus
+type ProcessingBlock struct {
+	BlockID  ids.ID      `json:"blockID"`
+	ParentID ids.ID      `json:"parentID"`
+	Height   json.Uint64 `json:"height"`
+	// True if the block is on the preferred chain
+	Preferred bool `json:"preferred"`
+}
+
+func (t *Transitive) Diagnostics() (Diagnostics, error) {
+	lastAcceptedID, err := t.VM.LastAccepted()
+	if err != nil {
+		return Diagnostics{}, err
+	}
+	lastAccepted, err := t.GetBlock(lastAcceptedID)
+	if err != nil {
+		return Diagnostics{}, err
+	}
+
+	processing := t.Consensus.ProcessingBlocks()
+	diagnostics := Diagnostics{
+		LastAcceptedID:           lastAcceptedID,
+		LastAcceptedHeight:       json.Uint64(lastAccepted.Height()),
+		Preference:               t.Consensus.Preference(),
+		ProcessingBlocks:         make([]ProcessingBlock, len(processing)),
+		PendingBlocks:            len(t.pending),
+		OutstandingBlockRequests: t.blkReqs.Len(),
+		OutstandingPolls:         t.polls.Len(),
+		Polls:                    t.polls.String(),
+	}
+	for i, blk := range processing {
+		diagnostics.ProcessingBlocks[i] = ProcessingBlock{
+			BlockID:   blk.ID(),
+			ParentID:  blk.Parent(),
+			Height:    json.Uint64(blk.Height()),
+			Preferred: t.Consensus.IsPreferred(blk),
+		}
+	}
+	return diagnostics, nil
+}
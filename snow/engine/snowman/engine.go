@@ -19,4 +19,9 @@ import (
 type Engine interface {
 	common.Engine
 	block.Getter
+
+	// Diagnostics returns the state of the engine, to help diagnose why it
+	// isn't accepting blocks.
+	// Assumes the engine has been started.
+	Diagnostics() (Diagnostics, error)
 }
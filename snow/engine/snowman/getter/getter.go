@@ -83,7 +83,8 @@ func (gh *getter) GetAncestors(validatorID ids.ShortID, requestID uint32, blkID
 }
 
 func (gh *getter) Get(validatorID ids.ShortID, requestID uint32, blkID ids.ID) error {
-	blk, err := gh.vm.GetBlock(blkID)
+	// Peers may request a block by the ID of a block it wraps
+	blk, err := block.GetWrappingBlock(gh.vm, blkID)
 	if err != nil {
 		// If we failed to get the block, that means either an unexpected error
 		// has occurred, [vdr] is not following the protocol, or the
@@ -93,6 +94,8 @@ func (gh *getter) Get(validatorID ids.ShortID, requestID uint32, blkID ids.ID) e
 	}
 
 	// Respond to the validator with the fetched block and the same requestID.
-	gh.sender.SendPut(validatorID, requestID, blkID, blk.Bytes())
+	// The VM may have resolved [blkID] to a block with a different ID, such as
+	// the block that wraps the requested block.
+	gh.sender.SendPut(validatorID, requestID, blk.ID(), blk.Bytes())
 	return nil
 }
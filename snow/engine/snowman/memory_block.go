@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 )
 
@@ -13,12 +14,19 @@ type memoryBlock struct {
 
 	tree    AncestorTree
 	metrics *metrics
+
+	// If non-nil, the database that the block was persisted to while it was
+	// processing
+	processingDB database.Database
 }
 
 // Accept accepts the underlying block & removes sibling subtrees
 func (mb *memoryBlock) Accept() error {
 	mb.tree.RemoveSubtree(mb.Parent())
 	mb.metrics.numNonVerifieds.Set(float64(mb.tree.Len()))
+	if err := mb.removeProcessing(); err != nil {
+		return err
+	}
 	return mb.Block.Accept()
 }
 
@@ -26,5 +34,16 @@ func (mb *memoryBlock) Accept() error {
 func (mb *memoryBlock) Reject() error {
 	mb.tree.RemoveSubtree(mb.ID())
 	mb.metrics.numNonVerifieds.Set(float64(mb.tree.Len()))
+	if err := mb.removeProcessing(); err != nil {
+		return err
+	}
 	return mb.Block.Reject()
 }
+
+func (mb *memoryBlock) removeProcessing() error {
+	if mb.processingDB == nil {
+		return nil
+	}
+	blkID := mb.ID()
+	return mb.processingDB.Delete(blkID[:])
+}
@@ -11,9 +11,9 @@ import (
 )
 
 type metrics struct {
-	bootstrapFinished, numRequests, numBlocked, numBlockers, numNonVerifieds prometheus.Gauge
-	numBuilt, numBuildsFailed, numUselessPutBytes, numUselessPushQueryBytes  prometheus.Counter
-	getAncestorsBlks                                                         metric.Averager
+	bootstrapFinished, numRequests, numBlocked, numBlockers, numNonVerifieds, numDeferred prometheus.Gauge
+	numBuilt, numBuildsFailed, numUselessPutBytes, numUselessPushQueryBytes, numRestored  prometheus.Counter
+	getAncestorsBlks                                                                      metric.Averager
 }
 
 // Initialize the metrics
@@ -66,11 +66,21 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		reg,
 		&errs,
 	)
+	m.numRestored = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "restored_blks",
+		Help:      "Number of processing blocks that were re-issued from disk on startup",
+	})
 	m.numNonVerifieds = prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Name:      "non_verified_blks",
 		Help:      "Number of non-verified blocks in the memory",
 	})
+	m.numDeferred = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "deferred_blks",
+		Help:      "Number of blocks waiting for their parent to be known before being verified",
+	})
 
 	errs.Add(
 		reg.Register(m.bootstrapFinished),
@@ -78,10 +88,12 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		reg.Register(m.numBlocked),
 		reg.Register(m.numBlockers),
 		reg.Register(m.numNonVerifieds),
+		reg.Register(m.numDeferred),
 		reg.Register(m.numBuilt),
 		reg.Register(m.numBuildsFailed),
 		reg.Register(m.numUselessPutBytes),
 		reg.Register(m.numUselessPushQueryBytes),
+		reg.Register(m.numRestored),
 	)
 	return errs.Err
 }
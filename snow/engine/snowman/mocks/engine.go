@@ -12,6 +12,8 @@ import (
 
 	snow "github.com/ava-labs/avalanchego/snow"
 
+	snowman "github.com/ava-labs/avalanchego/snow/engine/snowman"
+
 	time "time"
 
 	version "github.com/ava-labs/avalanchego/version"
@@ -164,6 +166,27 @@ func (_m *Engine) Context() *snow.ConsensusContext {
 	return r0
 }
 
+// Diagnostics provides a mock function with given fields:
+func (_m *Engine) Diagnostics() (snowman.Diagnostics, error) {
+	ret := _m.Called()
+
+	var r0 snowman.Diagnostics
+	if rf, ok := ret.Get(0).(func() snowman.Diagnostics); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(snowman.Diagnostics)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Disconnected provides a mock function with given fields: id
 func (_m *Engine) Disconnected(id ids.ShortID) error {
 	ret := _m.Called(id)
@@ -0,0 +1,19 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ common.ContainerPreparer = &Transitive{}
+
+// PrepareContainers only touches the VM, which must support being prepared
+// concurrently, so it's safe to call without the context lock held.
+func (t *Transitive) PrepareContainers(containers [][]byte) {
+	for _, blkBytes := range containers {
+		block.PrepareBlock(t.VM, blkBytes)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var _ common.QueryBatchHandler = &Transitive{}
+
+// queuedPushQuery is a push query whose poll is registered, but that hasn't
+// been sent yet
+type queuedPushQuery struct {
+	vdrs      ids.ShortSet
+	requestID uint32
+	blkID     ids.ID
+	blkBytes  []byte
+}
+
+// pushQueryBatch is the queries to send to a validator in one message
+type pushQueryBatch struct {
+	requestIDs []uint32
+	blkIDs     []ids.ID
+	blks       [][]byte
+}
+
+// sendPushQueries sends the queued push queries. A validator that is queried
+// about more than one block is sent all of its queries in one message.
+func (t *Transitive) sendPushQueries() {
+	if len(t.queuedPushQueries) == 0 {
+		return
+	}
+	queries := t.queuedPushQueries
+	t.queuedPushQueries = nil
+
+	numQueries := make(map[ids.ShortID]int)
+	for _, query := range queries {
+		for vdr := range query.vdrs {
+			numQueries[vdr]++
+		}
+	}
+
+	batches := make(map[ids.ShortID]*pushQueryBatch)
+	for _, query := range queries {
+		for vdr := range query.vdrs {
+			if numQueries[vdr] < 2 {
+				continue
+			}
+			query.vdrs.Remove(vdr)
+
+			batch, ok := batches[vdr]
+			if !ok {
+				batch = &pushQueryBatch{}
+				batches[vdr] = batch
+			}
+			batch.requestIDs = append(batch.requestIDs, query.requestID)
+			batch.blkIDs = append(batch.blkIDs, query.blkID)
+			batch.blks = append(batch.blks, query.blkBytes)
+		}
+		if query.vdrs.Len() > 0 {
+			t.Sender.SendPushQuery(query.vdrs, query.requestID, query.blkID, query.blkBytes)
+		}
+	}
+	for vdr, batch := range batches {
+		t.Sender.SendPushQueryBatch(vdr, batch.requestIDs, batch.blkIDs, batch.blks)
+	}
+}
+
+// PushQueryBatch handles each of the queries like PushQuery. The queries that
+// are answered before the whole batch is handled are answered together.
+func (t *Transitive) PushQueryBatch(vdr ids.ShortID, requestIDs []uint32, blks [][]byte) error {
+	sender := &chitsBatcher{
+		Sender: t.Sender,
+		vdr:    vdr,
+	}
+	for i, requestID := range requestIDs {
+		if err := t.pushQueryFrom(vdr, requestID, blks[i], sender); err != nil {
+			return err
+		}
+	}
+	sender.flush()
+	return nil
+}
+
+// chitsBatcher holds back the chits sent to [vdr] until it's flushed, so that
+// they can be sent in one message. Chits sent after it's flushed, for queries
+// whose blocks weren't issued yet, are sent right away.
+type chitsBatcher struct {
+	common.Sender
+
+	vdr        ids.ShortID
+	flushed    bool
+	requestIDs []uint32
+	votes      []ids.ID
+}
+
+func (b *chitsBatcher) SendChits(vdr ids.ShortID, requestID uint32, votes []ids.ID) {
+	if b.flushed || vdr != b.vdr || len(votes) != 1 {
+		b.Sender.SendChits(vdr, requestID, votes)
+		return
+	}
+	b.requestIDs = append(b.requestIDs, requestID)
+	b.votes = append(b.votes, votes[0])
+}
+
+func (b *chitsBatcher) flush() {
+	b.flushed = true
+	switch len(b.requestIDs) {
+	case 0:
+	case 1:
+		b.Sender.SendChits(b.vdr, b.requestIDs[0], b.votes)
+	default:
+		b.Sender.SendChitsBatch(b.vdr, b.requestIDs, b.votes)
+	}
+	b.requestIDs = nil
+	b.votes = nil
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+func TestSendPushQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+	vdr2 := ids.GenerateTestShortID()
+	blkID0 := ids.GenerateTestID()
+	blkID1 := ids.GenerateTestID()
+
+	sender := &common.SenderTest{T: t}
+	sender.Default(true)
+	te := &Transitive{
+		Config: Config{
+			Sender:       sender,
+			BatchQueries: true,
+		},
+	}
+
+	vdrs0 := ids.ShortSet{}
+	vdrs0.Add(vdr0, vdr1)
+	vdrs1 := ids.ShortSet{}
+	vdrs1.Add(vdr0, vdr2)
+	te.queuedPushQueries = []queuedPushQuery{
+		{
+			vdrs:      vdrs0,
+			requestID: 1,
+			blkID:     blkID0,
+			blkBytes:  []byte{0},
+		},
+		{
+			vdrs:      vdrs1,
+			requestID: 2,
+			blkID:     blkID1,
+			blkBytes:  []byte{1},
+		},
+	}
+
+	// Only [vdr0] is queried about both blocks
+	queried := map[uint32]ids.ShortSet{}
+	sender.SendPushQueryF = func(vdrs ids.ShortSet, requestID uint32, _ ids.ID, _ []byte) {
+		queried[requestID] = vdrs
+	}
+	batched := false
+	sender.SendPushQueryBatchF = func(vdr ids.ShortID, requestIDs []uint32, blkIDs []ids.ID, blks [][]byte) {
+		assert.False(batched)
+		batched = true
+		assert.Equal(vdr0, vdr)
+		assert.Equal([]uint32{1, 2}, requestIDs)
+		assert.Equal([]ids.ID{blkID0, blkID1}, blkIDs)
+		assert.Equal([][]byte{{0}, {1}}, blks)
+	}
+
+	te.sendPushQueries()
+	assert.True(batched)
+	assert.Len(queried, 2)
+	assert.Equal([]ids.ShortID{vdr1}, queried[1].List())
+	assert.Equal([]ids.ShortID{vdr2}, queried[2].List())
+	assert.Empty(te.queuedPushQueries)
+
+	// Nothing is left to send
+	te.sendPushQueries()
+	assert.Len(queried, 2)
+}
+
+func TestEngineBatchesPushQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr, _, sender, vm, te, gBlk := setup(t)
+	te.BatchQueries = true
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0.ID(),
+		HeightV: 2,
+		BytesV:  []byte{2},
+	}
+
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		if bytes.Equal(b, blk1.Bytes()) {
+			return blk1, nil
+		}
+		return nil, errUnknownBytes
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk0.ID():
+			return blk0, nil
+		case blk1.ID():
+			return blk1, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+
+	sender.SendChitsF = func(ids.ShortID, uint32, []ids.ID) {}
+	batched := false
+	sender.SendPushQueryBatchF = func(inVdr ids.ShortID, requestIDs []uint32, blkIDs []ids.ID, blks [][]byte) {
+		assert.False(batched)
+		batched = true
+		assert.Equal(vdr, inVdr)
+		assert.Len(requestIDs, 2)
+		assert.Equal([]ids.ID{blk0.ID(), blk1.ID()}, blkIDs)
+		assert.Equal([][]byte{blk0.Bytes(), blk1.Bytes()}, blks)
+	}
+
+	// Issuing [blk1] also issues its parent, so both blocks are queried
+	// while handling this message
+	assert.NoError(te.PushQuery(vdr, 20, blk1.Bytes()))
+	assert.True(batched)
+	assert.Equal(2, te.polls.Len())
+}
+
+func TestEnginePushQueryBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0.ID(),
+		HeightV: 2,
+		BytesV:  []byte{2},
+	}
+	blk2 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: ids.GenerateTestID(),
+		HeightV: 2,
+		BytesV:  []byte{3},
+	}
+
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range []*snowman.TestBlock{blk0, blk1, blk2} {
+			if bytes.Equal(b, blk.Bytes()) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBytes
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk0.ID():
+			return blk0, nil
+		case blk1.ID():
+			return blk1, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+
+	sender.SendPushQueryF = func(ids.ShortSet, uint32, ids.ID, []byte) {}
+	// The parent of [blk2] is fetched, so its query can't be answered yet
+	sender.SendGetF = func(ids.ShortID, uint32, ids.ID) {}
+	batched := false
+	sender.SendChitsBatchF = func(inVdr ids.ShortID, requestIDs []uint32, votes []ids.ID) {
+		assert.False(batched)
+		batched = true
+		assert.Equal(vdr, inVdr)
+		assert.Equal([]uint32{1, 2}, requestIDs)
+		// Each query is answered with the preference at the time it was
+		// handled
+		assert.Equal([]ids.ID{blk0.ID(), blk1.ID()}, votes)
+	}
+
+	assert.NoError(te.PushQueryBatch(vdr, []uint32{1, 2, 3}, [][]byte{blk0.Bytes(), blk1.Bytes(), blk2.Bytes()}))
+	assert.True(batched)
+}
+
+func TestEngineTimeoutSendsBatchedPushQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, sender, vm, te, gBlk := setup(t)
+	te.BatchQueries = true
+	te.Timer = &common.TimerTest{
+		T:                t,
+		RegisterTimeoutF: func(time.Duration) {},
+	}
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		VerifyV: fmt.Errorf("%w: %s", snowman.ErrMissingParent, gBlk.ID()),
+		BytesV:  []byte{1},
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == gBlk.ID() {
+			return gBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	assert.NoError(te.issue(blk))
+	assert.True(te.pendingContains(blk.ID()))
+
+	// The block issued by the retry is queried before Timeout returns
+	blk.VerifyV = nil
+	pushed := false
+	sender.SendPushQueryF = func(_ ids.ShortSet, _ uint32, blkID ids.ID, _ []byte) {
+		pushed = blkID == blk.ID()
+	}
+	assert.NoError(te.Timeout())
+	assert.True(te.Consensus.Processing(blk.ID()))
+	assert.True(pushed)
+	assert.Empty(te.queuedPushQueries)
+	assert.Equal(1, te.polls.Len())
+}
@@ -14,20 +14,24 @@ import (
 var (
 	_ Engine = &EngineTest{}
 
-	errGetBlock = errors.New("unexpectedly called GetBlock")
+	errGetBlock    = errors.New("unexpectedly called GetBlock")
+	errDiagnostics = errors.New("unexpectedly called Diagnostics")
 )
 
 // EngineTest is a test engine
 type EngineTest struct {
 	common.EngineTest
 
-	CantGetBlock bool
+	CantGetBlock, CantDiagnostics bool
+
 	GetBlockF    func(ids.ID) (snowman.Block, error)
+	DiagnosticsF func() (Diagnostics, error)
 }
 
 func (e *EngineTest) Default(cant bool) {
 	e.EngineTest.Default(cant)
 	e.CantGetBlock = false
+	e.CantDiagnostics = false
 }
 
 func (e *EngineTest) GetBlock(blkID ids.ID) (snowman.Block, error) {
@@ -39,3 +43,13 @@ func (e *EngineTest) GetBlock(blkID ids.ID) (snowman.Block, error) {
 	}
 	return nil, errGetBlock
 }
+
+func (e *EngineTest) Diagnostics() (Diagnostics, error) {
+	if e.DiagnosticsF != nil {
+		return e.DiagnosticsF()
+	}
+	if e.CantDiagnostics && e.T != nil {
+		e.T.Fatalf("Unexpectedly called Diagnostics")
+	}
+	return Diagnostics{}, errDiagnostics
+}
@@ -4,7 +4,9 @@
 package snowman
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -15,10 +17,27 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/events"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 )
 
+const (
+	// The maximum number of blocks that can be waiting for their parent to be
+	// known by the VM before being verified. Once this many blocks are waiting,
+	// blocks whose parent is unknown are dropped.
+	maxDeferredBlocks = 256
+
+	// The number of times a deferred block is verified again before it's
+	// dropped.
+	maxDeferredRetries = 10
+
+	// How often deferred blocks are verified again, if the engine has a timer.
+	// They're also verified again whenever another block is issued or
+	// accepted.
+	deferredRetryFrequency = time.Second
+)
+
 var _ Engine = &Transitive{}
 
 func New(config Config) (Engine, error) {
@@ -51,6 +70,19 @@ type Transitive struct {
 	// Block ID --> Parent ID
 	nonVerifieds AncestorTree
 
+	// blocks that failed verification because the VM didn't know their parent,
+	// or because of a transient error. They are verified again until they can
+	// be issued or have been retried [maxDeferredRetries] times.
+	// Block ID --> *deferredBlock
+	deferred linkedhashmap.LinkedHashmap
+	// true if a timeout is registered to retry the deferred blocks
+	deferredRetryScheduled bool
+	// true while the deferred blocks are being retried
+	retryingDeferred bool
+	// true if the deferred blocks should be retried again once the current
+	// retries finish
+	retryDeferredAgain bool
+
 	// operations that are blocked on a block being issued. This could be
 	// issuing another block, responding to a query, or applying votes to consensus
 	blocked events.Blocker
@@ -59,6 +91,10 @@ type Transitive struct {
 	// processing blocks has gone below the optimal number.
 	pendingBuildBlocks int
 
+	// push queries that are sent once the engine is done handling the current
+	// message. Only used if [BatchQueries] is set.
+	queuedPushQueries []queuedPushQuery
+
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
 }
@@ -74,6 +110,7 @@ func newTransitive(config Config) (*Transitive, error) {
 		AncestorsHandler:        common.NewNoOpAncestorsHandler(config.Ctx.Log),
 		pending:                 make(map[ids.ID]snowman.Block),
 		nonVerifieds:            NewAncestorTree(),
+		deferred:                linkedhashmap.New(),
 		polls: poll.NewSet(factory,
 			config.Ctx.Log,
 			"",
@@ -95,6 +132,17 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkBytes []byte) err
 		return t.GetFailed(vdr, requestID)
 	}
 
+	// The peer may have replied with a different block than the one that was
+	// requested, such as the block that wraps it. The requested block is then
+	// no longer expected to arrive.
+	if requestedID, ok := t.blkReqs.Remove(vdr, requestID); ok && requestedID != blk.ID() {
+		t.Ctx.Log.Debug("Put(%s, %d) replied with %s rather than the requested block %s",
+			vdr, requestID, blk.ID(), requestedID)
+		t.blocked.Abandon(requestedID)
+		t.metrics.numRequests.Set(float64(t.blkReqs.Len()))
+		t.metrics.numBlockers.Set(float64(t.blocked.Len()))
+	}
+
 	if t.wasIssued(blk) {
 		t.metrics.numUselessPutBytes.Add(float64(len(blkBytes)))
 	}
@@ -126,10 +174,20 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32) error {
 }
 
 func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID) error {
+	return t.pullQueryFrom(vdr, requestID, blkID, t.Sender)
+}
+
+// pullQueryFrom handles a PullQuery, sending the chits answering it with
+// [sender].
+func (t *Transitive) pullQueryFrom(vdr ids.ShortID, requestID uint32, blkID ids.ID, sender common.Sender) error {
+	if t.readOnly() {
+		return t.sendLastAcceptedChits(vdr, requestID, sender)
+	}
+
 	// Will send chits once we've issued block [blkID] into consensus
 	c := &convincer{
 		consensus: t.Consensus,
-		sender:    t.Sender,
+		sender:    sender,
 		vdr:       vdr,
 		requestID: requestID,
 		errs:      &t.errs,
@@ -153,6 +211,16 @@ func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID)
 }
 
 func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, blkBytes []byte) error {
+	return t.pushQueryFrom(vdr, requestID, blkBytes, t.Sender)
+}
+
+// pushQueryFrom handles a PushQuery, sending the chits answering it with
+// [sender].
+func (t *Transitive) pushQueryFrom(vdr ids.ShortID, requestID uint32, blkBytes []byte, sender common.Sender) error {
+	if t.readOnly() {
+		return t.sendLastAcceptedChits(vdr, requestID, sender)
+	}
+
 	blk, err := t.VM.ParseBlock(blkBytes)
 	// If parsing fails, we just drop the request, as we didn't ask for it
 	if err != nil {
@@ -175,7 +243,19 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, blkBytes []byt
 	}
 
 	// register the chit request
-	return t.PullQuery(vdr, requestID, blk.ID())
+	return t.pullQueryFrom(vdr, requestID, blk.ID(), sender)
+}
+
+// sendLastAcceptedChits responds to a query with the last accepted block. The
+// querier's poll requires a response, but a vote for a decided block doesn't
+// count towards any processing block.
+func (t *Transitive) sendLastAcceptedChits(vdr ids.ShortID, requestID uint32, sender common.Sender) error {
+	lastAcceptedID, err := t.VM.LastAccepted()
+	if err != nil {
+		return err
+	}
+	sender.SendChits(vdr, requestID, []ids.ID{lastAcceptedID})
+	return nil
 }
 
 func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) error {
@@ -251,7 +331,13 @@ func (t *Transitive) Disconnected(nodeID ids.ShortID) error {
 	return t.VM.Disconnected(nodeID)
 }
 
-func (t *Transitive) Timeout() error { return nil }
+func (t *Transitive) Timeout() error {
+	t.deferredRetryScheduled = false
+	if err := t.retryDeferred(); err != nil {
+		return err
+	}
+	return t.buildBlocks()
+}
 
 func (t *Transitive) Gossip() error {
 	blkID, err := t.VM.LastAccepted()
@@ -279,6 +365,10 @@ func (t *Transitive) Notify(msg common.Message) error {
 	t.Ctx.Log.Verbo("snowman engine notified of %s from the vm", msg)
 	switch msg {
 	case common.PendingTxs:
+		if t.readOnly() {
+			t.Ctx.Log.Verbo("dropping request to build a block because the engine is read only")
+			return nil
+		}
 		// the pending txs message means we should attempt to build a block.
 		t.pendingBuildBlocks++
 		return t.buildBlocks()
@@ -337,6 +427,78 @@ func (t *Transitive) Start(startReqID uint32) error {
 	t.Ctx.Log.Info("bootstrapping finished with %s as the last accepted block", lastAcceptedID)
 	t.metrics.bootstrapFinished.Set(1)
 	t.Ctx.SetState(snow.NormalOp)
+	if err := t.restoreProcessing(); err != nil {
+		return err
+	}
+	t.sendPushQueries()
+	return nil
+}
+
+// restoreProcessing re-issues the blocks that were processing when the engine
+// was last stopped. Blocks that were decided since then, for example while
+// bootstrapping, are removed from [ProcessingDB].
+func (t *Transitive) restoreProcessing() error {
+	if t.ProcessingDB == nil {
+		return nil
+	}
+
+	it := t.ProcessingDB.NewIterator()
+	defer it.Release()
+
+	var (
+		blks     []snowman.Block
+		obsolete [][]byte
+	)
+	for it.Next() {
+		blk, err := t.VM.ParseBlock(it.Value())
+		if err != nil {
+			t.Ctx.Log.Debug("dropping processing block that failed to parse: %s", err)
+			obsolete = append(obsolete, it.Key())
+			continue
+		}
+		if blk.Status().Decided() {
+			obsolete = append(obsolete, it.Key())
+			continue
+		}
+		blks = append(blks, blk)
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	for _, key := range obsolete {
+		if err := t.ProcessingDB.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	// Ancestors are issued first, so that each block's parent is processing
+	// by the time the block is issued.
+	sort.Slice(blks, func(i, j int) bool {
+		return blks[i].Height() < blks[j].Height()
+	})
+	numRestored := 0
+	for _, blk := range blks {
+		if _, err := t.issueWithAncestors(blk); err != nil {
+			return err
+		}
+
+		// Blocks that couldn't be added to consensus, because they are no
+		// longer valid or their parent is missing, won't be decided, so they
+		// are removed now.
+		blkID := blk.ID()
+		if !t.Consensus.Processing(blkID) {
+			if err := t.ProcessingDB.Delete(blkID[:]); err != nil {
+				return err
+			}
+			continue
+		}
+		numRestored++
+	}
+	if numRestored > 0 {
+		t.Ctx.Log.Info("re-issued %d processing blocks", numRestored)
+		t.metrics.numRestored.Add(float64(numRestored))
+	}
 	return nil
 }
 
@@ -380,7 +542,7 @@ func (t *Transitive) buildBlocks() error {
 		if err != nil {
 			t.Ctx.Log.Debug("VM.BuildBlock errored with: %s", err)
 			t.numBuildsFailed.Inc()
-			return nil
+			break
 		}
 		t.numBuilt.Inc()
 
@@ -409,6 +571,10 @@ func (t *Transitive) buildBlocks() error {
 			t.Ctx.Log.Warn("VM.BuildBlock returned a block with unissued ancestors")
 		}
 	}
+
+	// Every message handled by the engine ends by building blocks, so this is
+	// where the queries for the blocks issued while handling it are sent.
+	t.sendPushQueries()
 	return nil
 }
 
@@ -613,6 +779,15 @@ func (t *Transitive) pushQuery(blk snowman.Block) {
 		vdrSet := ids.NewShortSet(len(vdrList))
 		vdrSet.Add(vdrList...)
 
+		if t.BatchQueries {
+			t.queuedPushQueries = append(t.queuedPushQueries, queuedPushQuery{
+				vdrs:      vdrSet,
+				requestID: t.RequestID,
+				blkID:     blk.ID(),
+				blkBytes:  blk.Bytes(),
+			})
+			return
+		}
 		t.Sender.SendPushQuery(vdrSet, t.RequestID, blk.ID(), blk.Bytes())
 	}
 }
@@ -647,10 +822,28 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 
 	// make sure this block is valid
 	if err := blk.Verify(); err != nil {
-		t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
+		switch {
+		case errors.Is(err, snowman.ErrMissingParent) && t.deferVerification(blk):
+			t.Ctx.Log.Debug("deferring verification of block %s until its parent %s arrives", blkID, parentID)
+			return t.errs.Err
+		case wrappers.Classify(err) == wrappers.TransientError && t.deferVerification(blk):
+			t.Ctx.Log.Debug("deferring verification of block %s after transient error %s", blkID, err)
+			return t.errs.Err
+		case errors.Is(err, snowman.ErrMissingParent):
+			// The block isn't known to be invalid, so it isn't marked as such.
+			t.Ctx.Log.Debug("dropping block %s as its parent %s didn't arrive", blkID, parentID)
+		case wrappers.Classify(err) == wrappers.FatalError:
+			return fmt.Errorf("failed to verify block %s: %w", blkID, err)
+		case wrappers.Classify(err) == wrappers.TransientError:
+			// The block isn't known to be invalid, so it isn't marked as such.
+			// It can be issued again if it is received again.
+			t.Ctx.Log.Debug("block %s kept failing verification due to transient error %s, dropping block", blkID, err)
+		default:
+			t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
 
-		// if verify fails, then all descendants are also invalid
-		t.addToNonVerifieds(blk)
+			// if verify fails, then all descendants are also invalid
+			t.addToNonVerifieds(blk)
+		}
 		t.blocked.Abandon(blkID)
 		t.metrics.numBlocked.Set(float64(len(t.pending))) // Tracks performance statistics
 		t.metrics.numBlockers.Set(float64(t.blocked.Len()))
@@ -659,10 +852,18 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	t.nonVerifieds.Remove(blkID)
 	t.metrics.numNonVerifieds.Set(float64(t.nonVerifieds.Len()))
 	t.Ctx.Log.Verbo("adding block to consensus: %s", blkID)
+	// The block is persisted before it's added, as consensus may decide it
+	// immediately.
+	if t.ProcessingDB != nil {
+		if err := t.ProcessingDB.Put(blkID[:], blk.Bytes()); err != nil {
+			return err
+		}
+	}
 	wrappedBlk := &memoryBlock{
-		Block:   blk,
-		metrics: &t.metrics,
-		tree:    t.nonVerifieds,
+		Block:        blk,
+		metrics:      &t.metrics,
+		tree:         t.nonVerifieds,
+		processingDB: t.ProcessingDB,
 	}
 	if err := t.Consensus.Add(wrappedBlk); err != nil {
 		return err
@@ -682,10 +883,19 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 
 			for _, blk := range options {
 				if err := blk.Verify(); err != nil {
+					if wrappers.Classify(err) == wrappers.FatalError {
+						return fmt.Errorf("failed to verify option %s: %w", blk.ID(), err)
+					}
+					if wrappers.Classify(err) == wrappers.TransientError && t.deferVerification(blk) {
+						t.Ctx.Log.Debug("deferring verification of option %s after transient error %s", blk.ID(), err)
+						continue
+					}
 					t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
 					dropped = append(dropped, blk)
-					// block fails verification, hold this in memory for bubbling
-					t.addToNonVerifieds(blk)
+					if wrappers.Classify(err) != wrappers.TransientError {
+						// block fails verification, hold this in memory for bubbling
+						t.addToNonVerifieds(blk)
+					}
 				} else {
 					// correctly verified will be passed to consensus as processing block
 					// no need to keep it anymore
@@ -726,6 +936,10 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 		t.blocked.Fulfill(blkID)
 		t.blkReqs.RemoveAny(blkID)
 	}
+	// The VM may now know the parents of the deferred blocks
+	if err := t.retryDeferred(); err != nil {
+		return err
+	}
 	for _, blk := range dropped {
 		blkID := blk.ID()
 		t.removeFromPending(blk)
@@ -753,6 +967,84 @@ func (t *Transitive) removeFromPending(blk snowman.Block) {
 	delete(t.pending, blk.ID())
 }
 
+// deferredBlock is a block whose verification was deferred because the VM
+// didn't know its parent, or because of a transient error
+type deferredBlock struct {
+	blk     snowman.Block
+	retries int
+}
+
+// deferVerification holds [blk] until it can be verified again, rather than
+// dropping it, so that it doesn't need to be fetched again. Blocks depending on
+// [blk] remain blocked. Returns false if [blk] was already retried too many
+// times, or if too many blocks are already deferred.
+func (t *Transitive) deferVerification(blk snowman.Block) bool {
+	blkID := blk.ID()
+	if deferredIntf, ok := t.deferred.Get(blkID); ok {
+		deferred := deferredIntf.(*deferredBlock)
+		deferred.retries++
+		if deferred.retries > maxDeferredRetries {
+			t.deferred.Delete(blkID)
+			t.metrics.numDeferred.Set(float64(t.deferred.Len()))
+			return false
+		}
+	} else {
+		if t.deferred.Len() >= maxDeferredBlocks {
+			return false
+		}
+		t.deferred.Put(blkID, &deferredBlock{blk: blk})
+	}
+	t.pending[blkID] = blk
+
+	if t.Timer != nil && !t.deferredRetryScheduled {
+		t.deferredRetryScheduled = true
+		t.Timer.RegisterTimeout(deferredRetryFrequency)
+	}
+
+	t.metrics.numDeferred.Set(float64(t.deferred.Len()))
+	t.metrics.numBlocked.Set(float64(len(t.pending)))
+	return true
+}
+
+// retryDeferred attempts to issue the blocks whose verification was deferred.
+// Blocks that still can't be verified are deferred again, until they've been
+// retried too many times.
+func (t *Transitive) retryDeferred() error {
+	// Issuing a deferred block retries the deferred blocks again, which is done
+	// once the current retries finish, rather than recursively.
+	if t.retryingDeferred {
+		t.retryDeferredAgain = true
+		return nil
+	}
+	t.retryingDeferred = true
+	defer func() { t.retryingDeferred = false }()
+
+	for {
+		t.retryDeferredAgain = false
+
+		blks := make([]snowman.Block, 0, t.deferred.Len())
+		it := t.deferred.NewIterator()
+		for it.Next() {
+			blks = append(blks, it.Value().(*deferredBlock).blk)
+		}
+		for _, blk := range blks {
+			if err := t.deliver(blk); err != nil {
+				return err
+			}
+			// If the block wasn't deferred again, it was either issued or
+			// dropped
+			if blkID := blk.ID(); !t.pendingContains(blkID) {
+				t.deferred.Delete(blkID)
+			}
+		}
+		t.metrics.numDeferred.Set(float64(t.deferred.Len()))
+
+		if !t.retryDeferredAgain {
+			return nil
+		}
+	}
+}
+
 func (t *Transitive) addToNonVerifieds(blk snowman.Block) {
 	// don't add this blk if it's decided or processing.
 	blkID := blk.ID()
@@ -6,9 +6,13 @@ package snowman
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
@@ -729,6 +733,67 @@ func TestEngineAbandonResponse(t *testing.T) {
 	}
 }
 
+func TestEnginePutOfOtherBlockAbandonsRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr, _, sender, vm, te, gBlk := setup(t)
+	sender.Default(true)
+	sender.SendPushQueryF = func(ids.ShortSet, uint32, ids.ID, []byte) {}
+
+	missingID := ids.GenerateTestID()
+	child := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: missingID,
+		HeightV: 2,
+		BytesV:  []byte{2},
+	}
+	other := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{3},
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == gBlk.ID() {
+			return gBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		if bytes.Equal(b, other.Bytes()) {
+			return other, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	requestID := new(uint32)
+	sender.SendGetF = func(inVdr ids.ShortID, inRequestID uint32, blkID ids.ID) {
+		assert.Equal(vdr, inVdr)
+		assert.Equal(missingID, blkID)
+		*requestID = inRequestID
+	}
+	added, err := te.issueFrom(vdr, child)
+	assert.NoError(err)
+	assert.False(added)
+	assert.True(te.blkReqs.Contains(missingID))
+
+	// The peer replies with another block, such as the block that wraps the
+	// requested block
+	assert.NoError(te.Put(vdr, *requestID, other.Bytes()))
+	assert.True(te.Consensus.Processing(other.ID()))
+
+	// The requested block is no longer expected, so the blocks waiting on it
+	// are abandoned
+	assert.Zero(te.blkReqs.Len())
+	assert.False(te.pendingContains(child.ID()))
+}
+
 func TestEngineFetchBlock(t *testing.T) {
 	vdr, _, sender, vm, te, gBlk := setup(t)
 
@@ -3181,3 +3246,486 @@ func TestEngineBubbleVotesThroughInvalidChain(t *testing.T) {
 		t.Fatalf("Expected blk1 to be Accepted, but found status: %s", blk1.Status())
 	}
 }
+
+func TestEngineDeferMissingParentVerification(t *testing.T) {
+	_, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+
+	timeouts := 0
+	te.Timer = &common.TimerTest{
+		T: t,
+		RegisterTimeoutF: func(time.Duration) {
+			timeouts++
+		},
+	}
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		VerifyV: fmt.Errorf("%w: %s", snowman.ErrMissingParent, gBlk.ID()),
+		BytesV:  []byte{1},
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == gBlk.ID() {
+			return gBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	if err := te.issue(blk); err != nil {
+		t.Fatal(err)
+	}
+
+	assert := assert.New(t)
+	assert.False(te.Consensus.Processing(blk.ID()))
+	assert.True(te.pendingContains(blk.ID()))
+	assert.Equal(1, te.deferred.Len())
+	assert.Zero(te.nonVerifieds.Len())
+	assert.Equal(1, timeouts)
+
+	// While the parent is still unknown, the block stays deferred and the
+	// retry is scheduled again
+	if err := te.Timeout(); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(te.pendingContains(blk.ID()))
+	assert.Equal(1, te.deferred.Len())
+	assert.Equal(2, timeouts)
+
+	// Once the VM learns about the parent, the next retry verifies the block
+	blk.VerifyV = nil
+	pushed := false
+	sender.SendPushQueryF = func(_ ids.ShortSet, _ uint32, blkID ids.ID, _ []byte) {
+		pushed = blkID == blk.ID()
+	}
+	if err := te.Timeout(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(te.Consensus.Processing(blk.ID()))
+	assert.False(te.pendingContains(blk.ID()))
+	assert.Zero(te.deferred.Len())
+	assert.True(pushed)
+	assert.Equal(2, timeouts)
+}
+
+func TestEngineRetryDeferredOnIssue(t *testing.T) {
+	_, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+	sender.SendPushQueryF = func(ids.ShortSet, uint32, ids.ID, []byte) {}
+
+	deferredBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		VerifyV: fmt.Errorf("%w: %s", snowman.ErrMissingParent, gBlk.ID()),
+		BytesV:  []byte{1},
+	}
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{2},
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == gBlk.ID() {
+			return gBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	if err := te.issue(deferredBlk); err != nil {
+		t.Fatal(err)
+	}
+
+	assert := assert.New(t)
+	assert.True(te.pendingContains(deferredBlk.ID()))
+	assert.Equal(1, te.deferred.Len())
+
+	// Issuing another block verifies the deferred block again, without a timer
+	deferredBlk.VerifyV = nil
+	if err := te.issue(blk); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(te.Consensus.Processing(blk.ID()))
+	assert.True(te.Consensus.Processing(deferredBlk.ID()))
+	assert.False(te.pendingContains(deferredBlk.ID()))
+	assert.Zero(te.deferred.Len())
+}
+
+func TestEngineDropDeferredAfterMaxRetries(t *testing.T) {
+	_, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		VerifyV: fmt.Errorf("%w: %s", snowman.ErrMissingParent, gBlk.ID()),
+		BytesV:  []byte{1},
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == gBlk.ID() {
+			return gBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	if err := te.issue(blk); err != nil {
+		t.Fatal(err)
+	}
+
+	assert := assert.New(t)
+	for i := 0; i < maxDeferredRetries; i++ {
+		if err := te.Timeout(); err != nil {
+			t.Fatal(err)
+		}
+		assert.True(te.pendingContains(blk.ID()))
+	}
+
+	// Once the block has been retried too many times, it's dropped without
+	// being marked as invalid
+	if err := te.Timeout(); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(te.Consensus.Processing(blk.ID()))
+	assert.False(te.pendingContains(blk.ID()))
+	assert.Zero(te.deferred.Len())
+	assert.Zero(te.nonVerifieds.Len())
+}
+
+func TestEngineVerifyErrorClasses(t *testing.T) {
+	tests := map[string]struct {
+		err                 error
+		expectedErr         bool
+		expectedNonVerified bool
+		expectedDeferred    bool
+	}{
+		"unclassified": {
+			err:                 errors.New("unclassified"),
+			expectedNonVerified: true,
+		},
+		"invalid": {
+			err:                 wrappers.NewInvalid(errors.New("invalid")),
+			expectedNonVerified: true,
+		},
+		"transient": {
+			err:              wrappers.NewTransient(errors.New("transient")),
+			expectedDeferred: true,
+		},
+		"fatal": {
+			err:         wrappers.NewFatal(errors.New("fatal")),
+			expectedErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			_, _, sender, vm, te, gBlk := setup(t)
+			sender.Default(true)
+			sender.SendPushQueryF = func(ids.ShortSet, uint32, ids.ID, []byte) {}
+
+			parentBlk := &snowman.TestBlock{
+				TestDecidable: choices.TestDecidable{
+					IDV:     ids.GenerateTestID(),
+					StatusV: choices.Processing,
+				},
+				ParentV: gBlk.ID(),
+				HeightV: 1,
+				BytesV:  []byte{1},
+			}
+			calls := 0
+			blk := &snowman.TestBlock{
+				TestDecidable: choices.TestDecidable{
+					IDV:     ids.GenerateTestID(),
+					StatusV: choices.Processing,
+				},
+				ParentV: parentBlk.ID(),
+				HeightV: 2,
+				BytesV:  []byte{2},
+			}
+			vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+				switch blkID {
+				case gBlk.ID():
+					return gBlk, nil
+				case parentBlk.ID():
+					return parentBlk, nil
+				default:
+					return nil, errUnknownBlock
+				}
+			}
+
+			assert.NoError(te.issue(parentBlk))
+
+			verifyingBlk := &verifyCountingBlock{TestBlock: blk, calls: &calls, err: test.err}
+			err := te.issue(verifyingBlk)
+			if test.expectedErr {
+				assert.ErrorIs(err, test.err)
+			} else {
+				assert.NoError(err)
+			}
+			// Verify is never retried immediately
+			assert.Equal(1, calls)
+			assert.False(te.Consensus.Processing(blk.ID()))
+			assert.Equal(test.expectedNonVerified, te.nonVerifieds.Has(blk.ID()))
+			assert.Equal(test.expectedDeferred, te.pendingContains(blk.ID()))
+			if !test.expectedDeferred {
+				return
+			}
+
+			// Blocks deferred after a transient error are verified again later
+			verifyingBlk.err = nil
+			assert.NoError(te.Timeout())
+			assert.Equal(2, calls)
+			assert.True(te.Consensus.Processing(blk.ID()))
+			assert.False(te.pendingContains(blk.ID()))
+		})
+	}
+}
+
+type verifyCountingBlock struct {
+	*snowman.TestBlock
+	calls *int
+	err   error
+}
+
+func (b *verifyCountingBlock) Verify() error {
+	*b.calls++
+	return b.err
+}
+
+func TestEngineReadOnly(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+	te.IsReadOnly = func() bool { return true }
+
+	vm.LastAcceptedF = func() (ids.ID, error) { return gBlk.ID(), nil }
+
+	// Blocks are never built
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+
+	// Queries are answered with the last accepted block without issuing the
+	// queried block
+	numChits := 0
+	sender.SendChitsF = func(inVdr ids.ShortID, requestID uint32, votes []ids.ID) {
+		numChits++
+		if inVdr != vdr {
+			t.Fatalf("sent chits to the wrong validator")
+		}
+		if requestID != uint32(numChits) {
+			t.Fatalf("sent chits with the wrong request ID")
+		}
+		if len(votes) != 1 || votes[0] != gBlk.ID() {
+			t.Fatalf("voted for %v rather than the last accepted block", votes)
+		}
+	}
+	if err := te.PullQuery(vdr, 1, ids.GenerateTestID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.PushQuery(vdr, 2, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if numChits != 2 {
+		t.Fatalf("sent %d chits, expected 2", numChits)
+	}
+	if te.Consensus.NumProcessing() != 0 {
+		t.Fatalf("read only engine issued a block")
+	}
+}
+
+func TestEngineRestoreProcessing(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(false)
+
+	db := memdb.New()
+	te.ProcessingDB = db
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0.IDV,
+		HeightV: 2,
+		BytesV:  []byte{2},
+	}
+	orphan := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: ids.GenerateTestID(),
+		HeightV: 2,
+		BytesV:  []byte{3},
+	}
+	rejected := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Rejected,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{4},
+	}
+
+	vm.LastAcceptedF = func() (ids.ID, error) { return gBlk.ID(), nil }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk0.ID():
+			return blk0, nil
+		case blk1.ID():
+			return blk1, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range []*snowman.TestBlock{blk0, blk1, orphan, rejected} {
+			if bytes.Equal(b, blk.Bytes()) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBytes
+	}
+
+	if err := te.issue(blk1); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.issue(blk0); err != nil {
+		t.Fatal(err)
+	}
+	for _, blk := range []snowman.Block{blk0, blk1} {
+		blkID := blk.ID()
+		if has, err := db.Has(blkID[:]); err != nil {
+			t.Fatal(err)
+		} else if !has {
+			t.Fatalf("processing block %s wasn't persisted", blkID)
+		}
+	}
+
+	for _, blk := range []snowman.Block{orphan, rejected} {
+		blkID := blk.ID()
+		if err := db.Put(blkID[:], blk.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Restart the engine with the same database
+	engCfg := te.Config
+	engCfg.Ctx = snow.DefaultConsensusContextTest()
+	engCfg.Consensus = &snowman.Topological{}
+	te, err := newTransitive(engCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Start(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if !te.Consensus.Processing(blk0.ID()) || !te.Consensus.Processing(blk1.ID()) {
+		t.Fatalf("processing blocks weren't re-issued")
+	}
+	for _, blk := range []snowman.Block{orphan, rejected} {
+		blkID := blk.ID()
+		if has, err := db.Has(blkID[:]); err != nil {
+			t.Fatal(err)
+		} else if has {
+			t.Fatalf("block %s that can't be issued should have been removed", blkID)
+		}
+	}
+
+	// Decided blocks are removed
+	for requestID := uint32(1); requestID <= te.RequestID; requestID++ {
+		if err := te.Chits(vdr, requestID, []ids.ID{blk1.ID()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if blk1.Status() != choices.Accepted {
+		t.Fatalf("should have accepted blk1")
+	}
+	for _, blk := range []snowman.Block{blk0, blk1} {
+		blkID := blk.ID()
+		if has, err := db.Has(blkID[:]); err != nil {
+			t.Fatal(err)
+		} else if has {
+			t.Fatalf("decided block %s should have been removed", blkID)
+		}
+	}
+}
+
+func TestEngineDiagnostics(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, sender, vm, te, gBlk := setup(t)
+	sender.Default(true)
+	sender.SendPushQueryF = func(ids.ShortSet, uint32, ids.ID, []byte) {}
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	vm.LastAcceptedF = func() (ids.ID, error) { return gBlk.ID(), nil }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	assert.NoError(te.issue(blk))
+
+	diagnostics, err := te.Diagnostics()
+	assert.NoError(err)
+	assert.Equal(gBlk.ID(), diagnostics.LastAcceptedID)
+	assert.Zero(diagnostics.LastAcceptedHeight)
+	assert.Equal(blk.ID(), diagnostics.Preference)
+	assert.Equal([]ProcessingBlock{{
+		BlockID:   blk.ID(),
+		ParentID:  gBlk.ID(),
+		Height:    1,
+		Preferred: true,
+	}}, diagnostics.ProcessingBlocks)
+	assert.Zero(diagnostics.PendingBlocks)
+	assert.Equal(1, diagnostics.OutstandingPolls)
+	assert.NotEmpty(diagnostics.Polls)
+}
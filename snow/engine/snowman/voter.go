@@ -67,6 +67,12 @@ func (v *voter) Update() {
 		return
 	}
 
+	// The VM may now know the parents of the deferred blocks
+	if err := v.t.retryDeferred(); err != nil {
+		v.t.errs.Add(err)
+		return
+	}
+
 	if v.t.Consensus.Finalized() {
 		v.t.Ctx.Log.Debug("Snowman engine can quiesce")
 		return
@@ -0,0 +1,27 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evidence
+
+import (
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	maxPackerSize  = 16 * units.MiB // max size, in bytes, of an evidence entry
+	maxSliceLength = maxPackerSize
+
+	codecVersion = 0
+)
+
+var c codec.Manager
+
+func init() {
+	lc := linearcodec.NewCustomMaxLength(maxSliceLength)
+	c = codec.NewManager(maxPackerSize)
+	if err := c.RegisterCodec(codecVersion, lc); err != nil {
+		panic(err)
+	}
+}
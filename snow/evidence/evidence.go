@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evidence
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// Type describes the kind of misbehavior an Evidence entry proves
+type Type byte
+
+const (
+	// ConflictingBlocks is recorded when a proposer signs two different
+	// blocks on top of the same parent. The containers are the signed blocks.
+	ConflictingBlocks Type = iota
+)
+
+func (t Type) String() string {
+	switch t {
+	case ConflictingBlocks:
+		return "conflictingBlocks"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(t))
+	}
+}
+
+func (t Type) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// Evidence is a record of misbehavior by [NodeID] on [ChainID]
+type Evidence struct {
+	Type       Type        `serialize:"true"`
+	ChainID    ids.ID      `serialize:"true"`
+	NodeID     ids.ShortID `serialize:"true"`
+	Timestamp  int64       `serialize:"true"`
+	Containers [][]byte    `serialize:"true"`
+
+	id    ids.ID
+	bytes []byte
+}
+
+// misbehavior is the part of an Evidence entry that identifies it. The time
+// the misbehavior was observed, and the order its containers were observed in,
+// aren't included, so that observing the same misbehavior again doesn't
+// record it again.
+type misbehavior struct {
+	Type       Type        `serialize:"true"`
+	ChainID    ids.ID      `serialize:"true"`
+	NodeID     ids.ShortID `serialize:"true"`
+	Containers [][]byte    `serialize:"true"`
+}
+
+// ID returns the hash of the misbehavior that the evidence proves. It is only
+// populated after the evidence has been serialized or parsed.
+func (e *Evidence) ID() ids.ID { return e.id }
+
+// Bytes returns the serialized evidence
+func (e *Evidence) Bytes() []byte { return e.bytes }
+
+func (e *Evidence) initialize() error {
+	bytes, err := c.Marshal(codecVersion, e)
+	if err != nil {
+		return err
+	}
+	e.bytes = bytes
+	return e.initializeID()
+}
+
+func (e *Evidence) initializeID() error {
+	m := misbehavior{
+		Type:       e.Type,
+		ChainID:    e.ChainID,
+		NodeID:     e.NodeID,
+		Containers: make([][]byte, len(e.Containers)),
+	}
+	copy(m.Containers, e.Containers)
+	utils.Sort2DBytes(m.Containers)
+
+	bytes, err := c.Marshal(codecVersion, &m)
+	if err != nil {
+		return err
+	}
+	e.id = hashing.ComputeHash256Array(bytes)
+	return nil
+}
+
+// Parse deserializes [bytes] into an Evidence entry
+func Parse(bytes []byte) (*Evidence, error) {
+	e := &Evidence{}
+	if _, err := c.Unmarshal(bytes, e); err != nil {
+		return nil, err
+	}
+	e.bytes = bytes
+	if err := e.initializeID(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Recorder persists evidence of misbehavior
+type Recorder interface {
+	// Record stores [e]. Recording the same evidence twice is a no-op.
+	Record(e *Evidence) error
+}
+
+type noOpRecorder struct{}
+
+// NewNoOpRecorder returns a Recorder that drops all evidence
+func NewNoOpRecorder() Recorder { return noOpRecorder{} }
+
+func (noOpRecorder) Record(*Evidence) error { return nil }
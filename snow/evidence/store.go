@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evidence
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	// One proof of misbehavior is enough to act on, so further evidence
+	// against the same node only takes up space
+	maxEvidencePerNode = 16
+	maxEvidence        = 4096
+)
+
+var (
+	_ Store = &store{}
+
+	errNodeEvidenceLimit = errors.New("evidence limit of the node reached")
+	errEvidenceLimit     = errors.New("evidence limit reached")
+
+	evidencePrefix  = []byte("evidence")
+	nodeIndexPrefix = []byte("node")
+)
+
+// Store is a Recorder that can also be queried
+type Store interface {
+	Recorder
+
+	// Get returns the evidence with the given ID, or database.ErrNotFound
+	Get(evidenceID ids.ID) (*Evidence, error)
+	// GetByNodeID returns all evidence recorded against [nodeID]
+	GetByNodeID(nodeID ids.ShortID) ([]*Evidence, error)
+}
+
+type store struct {
+	// lock protects [count] and ensures that limits aren't exceeded by
+	// concurrent records
+	lock sync.Mutex
+	// number of evidence entries in [evidenceDB]
+	count int
+
+	evidenceDB database.Database
+	// nodeID + evidenceID -> nil
+	nodeIndexDB database.Database
+}
+
+// NewStore returns a Store persisted in [db]. At most [maxEvidencePerNode]
+// entries are recorded against a node and at most [maxEvidence] in total.
+func NewStore(db database.Database) (Store, error) {
+	s := &store{
+		evidenceDB:  prefixdb.New(evidencePrefix, db),
+		nodeIndexDB: prefixdb.New(nodeIndexPrefix, db),
+	}
+
+	it := s.evidenceDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		s.count++
+	}
+	return s, it.Error()
+}
+
+func (s *store) Record(e *Evidence) error {
+	if err := e.initialize(); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if has, err := s.evidenceDB.Has(e.id[:]); err != nil || has {
+		return err
+	}
+	if s.count >= maxEvidence {
+		return errEvidenceLimit
+	}
+	nodeCount, err := s.countByNodeID(e.NodeID)
+	if err != nil {
+		return err
+	}
+	if nodeCount >= maxEvidencePerNode {
+		return errNodeEvidenceLimit
+	}
+
+	if err := s.evidenceDB.Put(e.id[:], e.bytes); err != nil {
+		return err
+	}
+	key := make([]byte, hashing.AddrLen+hashing.HashLen)
+	copy(key, e.NodeID[:])
+	copy(key[hashing.AddrLen:], e.id[:])
+	if err := s.nodeIndexDB.Put(key, nil); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+func (s *store) Get(evidenceID ids.ID) (*Evidence, error) {
+	bytes, err := s.evidenceDB.Get(evidenceID[:])
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes)
+}
+
+func (s *store) GetByNodeID(nodeID ids.ShortID) ([]*Evidence, error) {
+	it := s.nodeIndexDB.NewIteratorWithPrefix(nodeID[:])
+	defer it.Release()
+
+	evidence := []*Evidence(nil)
+	for it.Next() {
+		key := it.Key()
+		evidenceID, err := ids.ToID(key[hashing.AddrLen:])
+		if err != nil {
+			return nil, err
+		}
+		e, err := s.Get(evidenceID)
+		if err != nil {
+			return nil, err
+		}
+		evidence = append(evidence, e)
+	}
+	return evidence, it.Error()
+}
+
+// countByNodeID returns the number of entries recorded against [nodeID]
+func (s *store) countByNodeID(nodeID ids.ShortID) (int, error) {
+	it := s.nodeIndexDB.NewIteratorWithPrefix(nodeID[:])
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	return count, it.Error()
+}
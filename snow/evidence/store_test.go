@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evidence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestStoreRecordAndGet(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := NewStore(memdb.New())
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	e := &Evidence{
+		Type:       ConflictingBlocks,
+		ChainID:    ids.GenerateTestID(),
+		NodeID:     nodeID,
+		Timestamp:  1,
+		Containers: [][]byte{{1}, {2}},
+	}
+	assert.NoError(s.Record(e))
+	// Recording the same evidence again must not duplicate the index entry
+	assert.NoError(s.Record(e))
+
+	fetched, err := s.Get(e.ID())
+	assert.NoError(err)
+	assert.Equal(e.Type, fetched.Type)
+	assert.Equal(e.ChainID, fetched.ChainID)
+	assert.Equal(e.NodeID, fetched.NodeID)
+	assert.Equal(e.Containers, fetched.Containers)
+	assert.Equal(e.ID(), fetched.ID())
+
+	// Observing the same misbehavior again, at another time or in another
+	// order, doesn't record it again
+	again := &Evidence{
+		Type:       e.Type,
+		ChainID:    e.ChainID,
+		NodeID:     nodeID,
+		Timestamp:  3,
+		Containers: [][]byte{{2}, {1}},
+	}
+	assert.NoError(s.Record(again))
+	assert.Equal(e.ID(), again.ID())
+
+	other := &Evidence{
+		Type:       ConflictingBlocks,
+		ChainID:    e.ChainID,
+		NodeID:     nodeID,
+		Timestamp:  2,
+		Containers: [][]byte{{3}, {4}},
+	}
+	assert.NoError(s.Record(other))
+	assert.NoError(s.Record(&Evidence{
+		Type:   ConflictingBlocks,
+		NodeID: ids.GenerateTestShortID(),
+	}))
+
+	byNode, err := s.GetByNodeID(nodeID)
+	assert.NoError(err)
+	assert.Len(byNode, 2)
+
+	_, err = s.Get(ids.GenerateTestID())
+	assert.Equal(database.ErrNotFound, err)
+}
+
+func TestStoreLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	s, err := NewStore(db)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	for i := 0; i < maxEvidencePerNode; i++ {
+		assert.NoError(s.Record(&Evidence{
+			NodeID:     nodeID,
+			Containers: [][]byte{{byte(i)}},
+		}))
+	}
+	err = s.Record(&Evidence{
+		NodeID:     nodeID,
+		Containers: [][]byte{{maxEvidencePerNode}},
+	})
+	assert.ErrorIs(err, errNodeEvidenceLimit)
+
+	// Recording evidence that was already recorded isn't limited
+	assert.NoError(s.Record(&Evidence{
+		NodeID:     nodeID,
+		Containers: [][]byte{{0}},
+	}))
+
+	byNode, err := s.GetByNodeID(nodeID)
+	assert.NoError(err)
+	assert.Len(byNode, maxEvidencePerNode)
+
+	for i := maxEvidencePerNode; i < maxEvidence; i++ {
+		assert.NoError(s.Record(&Evidence{
+			NodeID: ids.GenerateTestShortID(),
+		}))
+	}
+
+	// The total limit is enforced across restarts
+	s, err = NewStore(db)
+	assert.NoError(err)
+	err = s.Record(&Evidence{
+		NodeID: ids.GenerateTestShortID(),
+	})
+	assert.ErrorIs(err, errEvidenceLimit)
+}
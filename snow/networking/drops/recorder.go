@@ -0,0 +1,66 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package drops
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+)
+
+var _ Recorder = noOpRecorder{}
+
+// Reason describes why an inbound message was dropped, or why an outbound
+// request wasn't sent
+type Reason string
+
+const (
+	// UnknownChain drops are messages for a chain this node isn't running, or
+	// from a peer that isn't allowed to message the chain
+	UnknownChain Reason = "unknownChain"
+	// Policy drops are messages rejected by the message policy of the subnet
+	Policy Reason = "policy"
+	// Executing drops are messages received while the chain is executing a
+	// transaction
+	Executing Reason = "executing"
+	// Unrequested drops are responses to requests this node didn't send, or
+	// duplicated responses
+	Unrequested Reason = "unrequested"
+	// Invalid drops are messages that couldn't be handled because they're
+	// malformed
+	Invalid Reason = "invalid"
+	// Expired drops are messages whose deadline passed while they were queued
+	Expired Reason = "expired"
+	// Duplicate drops are queries identical to a query the same peer already
+	// has queued
+	Duplicate Reason = "duplicate"
+	// Shutdown drops are messages received after the chain stopped
+	Shutdown Reason = "shutdown"
+	// Benched drops are requests that weren't sent because the peer is benched
+	Benched Reason = "benched"
+)
+
+// Drop describes a dropped message
+type Drop struct {
+	Time    time.Time
+	NodeID  ids.ShortID
+	ChainID ids.ID
+	Op      message.Op
+	Reason  Reason
+}
+
+// Recorder is notified of every dropped message
+type Recorder interface {
+	// Record that a message of type [op] from, or to, [nodeID] about [chainID]
+	// was dropped because of [reason]
+	Record(nodeID ids.ShortID, chainID ids.ID, op message.Op, reason Reason)
+}
+
+type noOpRecorder struct{}
+
+// NewNoOpRecorder returns a Recorder that ignores all drops
+func NewNoOpRecorder() Recorder { return noOpRecorder{} }
+
+func (noOpRecorder) Record(ids.ShortID, ids.ID, message.Op, Reason) {}
@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package drops
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var _ Tracker = &tracker{}
+
+// Config of a Tracker
+type Config struct {
+	// Number of the most recent drops that are kept
+	HistorySize int `json:"historySize"`
+	// If non-zero, a drop is logged at most once per LogFrequency, along with
+	// the number of drops that weren't logged since the previous one
+	LogFrequency time.Duration `json:"logFrequency"`
+}
+
+// Tracker is a Recorder that counts the drops in labeled metrics and keeps
+// the most recent ones
+type Tracker interface {
+	Recorder
+
+	// Recent returns up to [limit] of the most recent drops, most recent first
+	Recent(limit int) []Drop
+	// Total returns the number of drops recorded so far
+	Total() uint64
+}
+
+type tracker struct {
+	log    logging.Logger
+	config Config
+	clock  mockable.Clock
+
+	dropped *prometheus.CounterVec
+
+	lock sync.Mutex
+	// Circular buffer of the most recent drops. [next] is the index the next
+	// drop is written at.
+	history []Drop
+	next    int
+	total   uint64
+	// Time of the last logged drop and the number of drops since then
+	lastLogged time.Time
+	notLogged  uint64
+}
+
+// NewTracker returns a Tracker that registers its metrics in [registerer]
+func NewTracker(
+	log logging.Logger,
+	config Config,
+	namespace string,
+	registerer prometheus.Registerer,
+) (Tracker, error) {
+	t := &tracker{
+		log:    log,
+		config: config,
+		dropped: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "dropped_messages",
+				Help:      "Number of messages dropped by the router and the chain handlers",
+			},
+			[]string{"op", "reason"},
+		),
+		history: make([]Drop, 0, config.HistorySize),
+	}
+	return t, registerer.Register(t.dropped)
+}
+
+func (t *tracker) Record(nodeID ids.ShortID, chainID ids.ID, op message.Op, reason Reason) {
+	t.dropped.WithLabelValues(op.String(), string(reason)).Inc()
+
+	drop := Drop{
+		Time:    t.clock.Time(),
+		NodeID:  nodeID,
+		ChainID: chainID,
+		Op:      op,
+		Reason:  reason,
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.total++
+	if t.config.HistorySize > 0 {
+		if len(t.history) < t.config.HistorySize {
+			t.history = append(t.history, drop)
+		} else {
+			t.history[t.next] = drop
+		}
+		t.next = (t.next + 1) % t.config.HistorySize
+	}
+
+	if t.config.LogFrequency == 0 {
+		return
+	}
+	if drop.Time.Sub(t.lastLogged) < t.config.LogFrequency {
+		t.notLogged++
+		return
+	}
+	t.log.Info(
+		"dropped %s from %s%s on chain %s: %s (%d drops since the previous log)",
+		op,
+		constants.NodeIDPrefix, nodeID,
+		chainID,
+		reason,
+		t.notLogged,
+	)
+	t.lastLogged = drop.Time
+	t.notLogged = 0
+}
+
+func (t *tracker) Recent(limit int) []Drop {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if limit > len(t.history) || limit <= 0 {
+		limit = len(t.history)
+	}
+	drops := make([]Drop, limit)
+	for i := range drops {
+		index := (t.next - 1 - i + len(t.history)) % len(t.history)
+		drops[i] = t.history[index]
+	}
+	return drops
+}
+
+func (t *tracker) Total() uint64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.total
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package drops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestTrackerRecent(t *testing.T) {
+	assert := assert.New(t)
+
+	trackerIntf, err := NewTracker(logging.NoLog{}, Config{HistorySize: 3}, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	tracker := trackerIntf.(*tracker)
+
+	assert.Empty(tracker.Recent(0))
+
+	chainID := ids.GenerateTestID()
+	nodeIDs := []ids.ShortID{}
+	for i := 0; i < 5; i++ {
+		nodeID := ids.GenerateTestShortID()
+		nodeIDs = append(nodeIDs, nodeID)
+		tracker.clock.Set(time.Unix(int64(i), 0))
+		tracker.Record(nodeID, chainID, message.PullQuery, Expired)
+	}
+	assert.EqualValues(5, tracker.Total())
+
+	// Only the 3 most recent drops are kept, most recent first
+	recent := tracker.Recent(0)
+	assert.Len(recent, 3)
+	for i, drop := range recent {
+		assert.Equal(nodeIDs[4-i], drop.NodeID)
+		assert.Equal(chainID, drop.ChainID)
+		assert.Equal(message.PullQuery, drop.Op)
+		assert.Equal(Expired, drop.Reason)
+		assert.Equal(time.Unix(int64(4-i), 0), drop.Time)
+	}
+
+	recent = tracker.Recent(2)
+	assert.Len(recent, 2)
+	assert.Equal(nodeIDs[4], recent[0].NodeID)
+	assert.Equal(nodeIDs[3], recent[1].NodeID)
+}
+
+func TestTrackerMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	tracker, err := NewTracker(logging.NoLog{}, Config{}, "", registry)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	tracker.Record(nodeID, ids.Empty, message.Get, Benched)
+	tracker.Record(nodeID, ids.Empty, message.Get, Benched)
+	tracker.Record(nodeID, ids.Empty, message.Put, Executing)
+	assert.Empty(tracker.Recent(0))
+
+	families, err := registry.Gather()
+	assert.NoError(err)
+	assert.Len(families, 1)
+
+	counts := map[string]float64{}
+	for _, metric := range families[0].GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		counts[labels["op"]+"/"+labels["reason"]] = metric.GetCounter().GetValue()
+	}
+	assert.Equal(map[string]float64{
+		"get/benched":   2,
+		"put/executing": 1,
+	}, counts)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/networking/worker"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -33,6 +34,9 @@ type Handler interface {
 	common.Timer
 	Context() *snow.ConsensusContext
 	IsValidator(nodeID ids.ShortID) bool
+	Validators() validators.Set
+	// Len returns the number of messages waiting to be handled
+	Len() int
 	SetBootstrapper(engine common.BootstrapableEngine)
 	Bootstrapper() common.BootstrapableEngine
 	SetConsensus(engine common.Engine)
@@ -49,6 +53,8 @@ type Handler interface {
 // (Actually, it receives the incoming messages from a ChainRouter, but same difference.)
 type handler struct {
 	metrics *metrics
+	// Notified of the messages this handler drops
+	drops drops.Recorder
 
 	// Useful for faking time in tests
 	clock mockable.Clock
@@ -76,6 +82,9 @@ type handler struct {
 	// Holds messages that [engine] hasn't processed yet.
 	// [unprocessedAsyncMsgsCond.L] must be held while accessing [asyncMessageQueue].
 	asyncMessageQueue MessageQueue
+	// Prepares synchronous consensus messages concurrently, before
+	// [ctx.Lock] is grabbed, and then handles them in order
+	syncMessagePipeline worker.Pipeline
 	// Worker pool for handling asynchronous consensus messages
 	asyncMessagePool worker.Pool
 	timeouts         chan struct{}
@@ -105,9 +114,10 @@ func New(
 		preemptTimeouts: preemptTimeouts,
 		gossipFrequency: gossipFrequency,
 
-		cpuTracker:       tracker.NewCPUTracker(uptime.ContinuousFactory{}, cpuHalflife),
-		asyncMessagePool: worker.NewPool(threadPoolSize),
-		timeouts:         make(chan struct{}, 1),
+		cpuTracker:          tracker.NewCPUTracker(uptime.ContinuousFactory{}, cpuHalflife),
+		syncMessagePipeline: worker.NewPipeline(threadPoolSize),
+		asyncMessagePool:    worker.NewPool(threadPoolSize),
+		timeouts:            make(chan struct{}, 1),
 
 		closingChan: make(chan struct{}),
 		closed:      make(chan struct{}),
@@ -118,11 +128,15 @@ func New(
 	if err != nil {
 		return nil, fmt.Errorf("initializing handler metrics errored with: %w", err)
 	}
-	h.syncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, h.cpuTracker, "handler", h.ctx.Registerer, message.SynchronousOps)
+	h.drops = ctx.Drops
+	if h.drops == nil {
+		h.drops = drops.NewNoOpRecorder()
+	}
+	h.syncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.ctx.ChainID, h.drops, h.validators, h.cpuTracker, "handler", h.ctx.Registerer, message.SynchronousOps)
 	if err != nil {
 		return nil, fmt.Errorf("initializing sync message queue errored with: %w", err)
 	}
-	h.asyncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, h.cpuTracker, "handler_async", h.ctx.Registerer, message.AsynchronousOps)
+	h.asyncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.ctx.ChainID, h.drops, h.validators, h.cpuTracker, "handler_async", h.ctx.Registerer, message.AsynchronousOps)
 	if err != nil {
 		return nil, fmt.Errorf("initializing async message queue errored with: %w", err)
 	}
@@ -137,6 +151,12 @@ func (h *handler) IsValidator(nodeID ids.ShortID) bool {
 		h.validators.Contains(nodeID)
 }
 
+func (h *handler) Validators() validators.Set { return h.validators }
+
+func (h *handler) Len() int {
+	return h.syncMessageQueue.Len() + h.asyncMessageQueue.Len()
+}
+
 func (h *handler) SetBootstrapper(engine common.BootstrapableEngine) { h.bootstrapper = engine }
 func (h *handler) Bootstrapper() common.BootstrapableEngine          { return h.bootstrapper }
 
@@ -222,7 +242,10 @@ func (h *handler) StopWithError(err error) {
 func (h *handler) Stopped() chan struct{} { return h.closed }
 
 func (h *handler) dispatchSync() {
-	defer h.closeDispatcher()
+	defer func() {
+		h.syncMessagePipeline.Shutdown()
+		h.closeDispatcher()
+	}()
 
 	// Handle sync messages from the router
 	for {
@@ -233,15 +256,30 @@ func (h *handler) dispatchSync() {
 			return
 		}
 
-		// If there is an error handling the message, shut down the chain
-		if err := h.handleSyncMsg(msg); err != nil {
-			h.StopWithError(fmt.Errorf(
-				"%w while processing sync message: %s",
-				err,
-				msg,
-			))
-			return
-		}
+		h.syncMessagePipeline.Send(
+			func() { h.prepareSyncMsg(msg) },
+			func() {
+				// If a previous message caused the chain to shut down, don't
+				// handle this one
+				select {
+				case <-h.closingChan:
+					h.drops.Record(msg.NodeID(), h.ctx.ChainID, msg.Op(), drops.Shutdown)
+					msg.OnFinishedHandling()
+					return
+				default:
+				}
+
+				// If there is an error handling the message, shut down the
+				// chain
+				if err := h.handleSyncMsg(msg); err != nil {
+					h.StopWithError(fmt.Errorf(
+						"%w while processing sync message: %s",
+						err,
+						msg,
+					))
+				}
+			},
+		)
 	}
 }
 
@@ -299,16 +337,46 @@ func (h *handler) dispatchChans() {
 	}
 }
 
+// prepareSyncMsg does the work of handling [msg] that doesn't require
+// [h.ctx.Lock]. It may be called concurrently with the handling of other
+// messages.
+func (h *handler) prepareSyncMsg(msg message.InboundMessage) {
+	var containers [][]byte
+	switch msg.Op() {
+	case message.Put, message.PushQuery:
+		containers = [][]byte{msg.Get(message.ContainerBytes).([]byte)}
+	case message.Ancestors, message.PushQueryBatch:
+		containers = msg.Get(message.MultiContainerBytes).([][]byte)
+	default:
+		return
+	}
+
+	engine, err := h.getEngine()
+	if err != nil {
+		return
+	}
+	preparer, ok := engine.(common.ContainerPreparer)
+	if !ok {
+		return
+	}
+
+	nodeID := msg.NodeID()
+	h.cpuTracker.StartCPU(nodeID, h.clock.Time())
+	preparer.PrepareContainers(containers)
+	h.cpuTracker.StopCPU(nodeID, h.clock.Time())
+}
+
 func (h *handler) handleSyncMsg(msg message.InboundMessage) error {
 	h.ctx.Log.Debug("Forwarding sync message to consensus: %s", msg)
 
 	var (
-		nodeID    = msg.NodeID()
-		op        = msg.Op()
-		startTime = h.clock.Time()
+		nodeID = msg.NodeID()
+		op     = msg.Op()
 	)
-	h.cpuTracker.StartCPU(nodeID, startTime)
+	// The time spent waiting for [h.ctx.Lock] isn't charged to [nodeID]
 	h.ctx.Lock.Lock()
+	startTime := h.clock.Time()
+	h.cpuTracker.StartCPU(nodeID, startTime)
 	defer func() {
 		h.ctx.Lock.Unlock()
 
@@ -423,6 +491,30 @@ func (h *handler) handleSyncMsg(msg message.InboundMessage) error {
 		container := msg.Get(message.ContainerBytes).([]byte)
 		return engine.PushQuery(nodeID, reqID, container)
 
+	case message.PushQueryBatch:
+		reqIDs := msg.Get(message.RequestIDs).([]uint32)
+		containers := msg.Get(message.MultiContainerBytes).([][]byte)
+		if len(reqIDs) != len(containers) {
+			h.ctx.Log.Debug(
+				"Malformed message %s from (%s%s): %d request IDs but %d containers",
+				op,
+				constants.NodeIDPrefix,
+				nodeID,
+				len(reqIDs),
+				len(containers),
+			)
+			return nil
+		}
+		if batchHandler, ok := engine.(common.QueryBatchHandler); ok {
+			return batchHandler.PushQueryBatch(nodeID, reqIDs, containers)
+		}
+		for i, reqID := range reqIDs {
+			if err := engine.PushQuery(nodeID, reqID, containers[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+
 	case message.PullQuery:
 		reqID := msg.Get(message.RequestID).(uint32)
 		containerID, err := ids.ToID(msg.Get(message.ContainerID).([]byte))
@@ -606,6 +698,7 @@ func (h *handler) popUnexpiredMsg(queue MessageQueue) (message.InboundMessage, b
 				msg,
 			)
 			h.metrics.expired.Inc()
+			h.drops.Record(msg.NodeID(), h.ctx.ChainID, msg.Op(), drops.Expired)
 			msg.OnFinishedHandling()
 			continue
 		}
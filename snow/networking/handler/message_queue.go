@@ -10,10 +10,13 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 var _ MessageQueue = &messageQueue{}
@@ -47,6 +50,10 @@ type messageQueue struct {
 	metrics messageQueueMetrics
 
 	log logging.Logger
+	// Chain associated with this queue
+	chainID ids.ID
+	// Notified of the messages removed from the queue without being handled
+	drops drops.Recorder
 	// Validator set for the chain associated with this
 	vdrs validators.Set
 	// Tracks CPU utilization of each node
@@ -58,10 +65,14 @@ type messageQueue struct {
 	nodeToUnprocessedMsgs map[ids.ShortID]int
 	// Unprocessed messages
 	msgs []message.InboundMessage
+	// Query key --> The query with that key in [msgs]
+	queuedQueries map[ids.ID]message.InboundMessage
 }
 
 func NewMessageQueue(
 	log logging.Logger,
+	chainID ids.ID,
+	dropRecorder drops.Recorder,
 	vdrs validators.Set,
 	cpuTracker tracker.TimeTracker,
 	metricsNamespace string,
@@ -70,10 +81,13 @@ func NewMessageQueue(
 ) (MessageQueue, error) {
 	m := &messageQueue{
 		log:                   log,
+		chainID:               chainID,
+		drops:                 dropRecorder,
 		vdrs:                  vdrs,
 		cpuTracker:            cpuTracker,
 		cond:                  sync.NewCond(&sync.Mutex{}),
 		nodeToUnprocessedMsgs: make(map[ids.ShortID]int),
+		queuedQueries:         make(map[ids.ID]message.InboundMessage),
 	}
 	return m, m.metrics.initialize(metricsNamespace, metricsRegisterer, ops)
 }
@@ -83,10 +97,40 @@ func (m *messageQueue) Push(msg message.InboundMessage) {
 	defer m.cond.L.Unlock()
 
 	if m.closed {
+		m.drops.Record(msg.NodeID(), m.chainID, msg.Op(), drops.Shutdown)
 		msg.OnFinishedHandling()
 		return
 	}
 
+	// If the sender already has an identical query waiting to be answered,
+	// only the queued query is answered. The newer query is dropped, rather
+	// than the queued one, so that the peer gets an answer to the request it
+	// has waited on the longest. If the queued query already expired, it
+	// would never be answered, so the newer query takes its place in the
+	// queue.
+	if key, ok := queryKey(msg); ok {
+		if queued, exists := m.queuedQueries[key]; exists {
+			m.metrics.numDuplicateQueries.Inc()
+			if expirationTime := queued.ExpirationTime(); expirationTime.IsZero() || !m.clock.Time().After(expirationTime) {
+				m.drops.Record(msg.NodeID(), m.chainID, msg.Op(), drops.Duplicate)
+				msg.OnFinishedHandling()
+				return
+			}
+
+			for i, queuedMsg := range m.msgs {
+				if queuedMsg == queued {
+					m.msgs[i] = msg
+					break
+				}
+			}
+			m.queuedQueries[key] = msg
+			m.drops.Record(queued.NodeID(), m.chainID, queued.Op(), drops.Expired)
+			queued.OnFinishedHandling()
+			return
+		}
+		m.queuedQueries[key] = msg
+	}
+
 	// Add the message to the queue
 	m.msgs = append(m.msgs, msg)
 	m.nodeToUnprocessedMsgs[msg.NodeID()]++
@@ -136,6 +180,9 @@ func (m *messageQueue) Pop() (message.InboundMessage, bool) {
 			if m.nodeToUnprocessedMsgs[nodeID] == 0 {
 				delete(m.nodeToUnprocessedMsgs, nodeID)
 			}
+			if key, ok := queryKey(msg); ok && m.queuedQueries[key] == msg {
+				delete(m.queuedQueries, key)
+			}
 			m.metrics.nodesWithMessages.Set(float64(len(m.nodeToUnprocessedMsgs)))
 			m.metrics.len.Dec()
 			m.metrics.ops[msg.Op()].Dec()
@@ -163,10 +210,12 @@ func (m *messageQueue) Shutdown() {
 
 	// Remove all the current messages from the queue
 	for _, msg := range m.msgs {
+		m.drops.Record(msg.NodeID(), m.chainID, msg.Op(), drops.Shutdown)
 		msg.OnFinishedHandling()
 	}
 	m.msgs = nil
 	m.nodeToUnprocessedMsgs = nil
+	m.queuedQueries = nil
 
 	// Update metrics
 	m.metrics.nodesWithMessages.Set(0)
@@ -209,3 +258,29 @@ func (m *messageQueue) canPop(msg message.InboundMessage) bool {
 	maxCPU := baseMaxCPU + (1.0-baseMaxCPU)*portionWeight
 	return recentCPUUtilized <= maxCPU
 }
+
+// queryKey returns a key identifying the sender and contents of [msg], if [msg]
+// is a query whose answer doesn't depend on anything else. Queries with the
+// same key can be answered by answering only one of them.
+func queryKey(msg message.InboundMessage) (ids.ID, bool) {
+	var content []byte
+	switch msg.Op() {
+	case message.GetAcceptedFrontier:
+	case message.GetAccepted:
+		containerIDs, _ := msg.Get(message.ContainerIDs).([][]byte)
+		for _, containerID := range containerIDs {
+			content = append(content, containerID...)
+		}
+	case message.Get, message.GetAncestors:
+		content, _ = msg.Get(message.ContainerID).([]byte)
+	default:
+		return ids.Empty, false
+	}
+
+	nodeID := msg.NodeID()
+	keyBytes := make([]byte, 0, hashing.AddrLen+wrappers.ByteLen+len(content))
+	keyBytes = append(keyBytes, nodeID[:]...)
+	keyBytes = append(keyBytes, byte(msg.Op()))
+	keyBytes = append(keyBytes, content...)
+	return hashing.ComputeHash256Array(keyBytes), true
+}
@@ -17,6 +17,8 @@ type messageQueueMetrics struct {
 	len               prometheus.Gauge
 	nodesWithMessages prometheus.Gauge
 	numExcessiveCPU   prometheus.Counter
+	// Queries replaced by an identical, more recent, query from the same node
+	numDuplicateQueries prometheus.Counter
 }
 
 func (m *messageQueueMetrics) initialize(
@@ -40,6 +42,11 @@ func (m *messageQueueMetrics) initialize(
 		Name:      "excessive_cpu",
 		Help:      "Times we deferred handling a message from a node because the node was using excessive CPU",
 	})
+	m.numDuplicateQueries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_queries",
+		Help:      "Times we received a query identical to a queued query from the same node",
+	})
 
 	errs := wrappers.Errs{}
 	m.ops = make(map[message.Op]prometheus.Gauge, len(ops))
@@ -59,6 +66,7 @@ func (m *messageQueueMetrics) initialize(
 		metricsRegisterer.Register(m.len),
 		metricsRegisterer.Register(m.nodesWithMessages),
 		metricsRegisterer.Register(m.numExcessiveCPU),
+		metricsRegisterer.Register(m.numDuplicateQueries),
 	)
 	return errs.Err
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -24,7 +25,7 @@ func TestQueue(t *testing.T) {
 	vdr1ID, vdr2ID := ids.GenerateTestShortID(), ids.GenerateTestShortID()
 	assert.NoError(vdrs.AddWeight(vdr1ID, 1))
 	assert.NoError(vdrs.AddWeight(vdr2ID, 1))
-	mIntf, err := NewMessageQueue(logging.NoLog{}, vdrs, cpuTracker, "", prometheus.NewRegistry(), message.SynchronousOps)
+	mIntf, err := NewMessageQueue(logging.NoLog{}, ids.Empty, drops.NewNoOpRecorder(), vdrs, cpuTracker, "", prometheus.NewRegistry(), message.SynchronousOps)
 	assert.NoError(err)
 	u := mIntf.(*messageQueue)
 	currentTime := time.Now()
@@ -138,3 +139,69 @@ func TestQueue(t *testing.T) {
 	assert.EqualValues(msg3, gotMsg3)
 	assert.EqualValues(0, u.Len())
 }
+
+func TestQueueDropsDuplicateQueries(t *testing.T) {
+	assert := assert.New(t)
+	cpuTracker := &tracker.MockTimeTracker{}
+	cpuTracker.On("Utilization", mock.Anything, mock.Anything).Return(0.0)
+	vdrs := validators.NewSet()
+	vdr1ID, vdr2ID := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	assert.NoError(vdrs.AddWeight(vdr1ID, 1))
+	assert.NoError(vdrs.AddWeight(vdr2ID, 1))
+	dropTracker, err := drops.NewTracker(logging.NoLog{}, drops.Config{HistorySize: 10}, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	mIntf, err := NewMessageQueue(logging.NoLog{}, ids.Empty, dropTracker, vdrs, cpuTracker, "", prometheus.NewRegistry(), message.SynchronousOps)
+	assert.NoError(err)
+	u := mIntf.(*messageQueue)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(err)
+
+	containerIDs := []ids.ID{ids.GenerateTestID()}
+	msg1 := mc.InboundGetAccepted(ids.Empty, 1, time.Second, containerIDs, vdr1ID)
+	msg2 := mc.InboundGetAcceptedFrontier(ids.Empty, 2, time.Second, vdr1ID)
+	msg3 := mc.InboundGetAccepted(ids.Empty, 3, time.Second, containerIDs, vdr2ID)
+	msg4 := mc.InboundGetAccepted(ids.Empty, 4, time.Second, containerIDs, vdr1ID)
+	msg5 := mc.InboundGetAccepted(ids.Empty, 5, time.Second, []ids.ID{ids.GenerateTestID()}, vdr1ID)
+
+	u.Push(msg1)
+	u.Push(msg2)
+	u.Push(msg3)
+	// [msg4] is identical to [msg1], so it should be dropped
+	u.Push(msg4)
+	u.Push(msg5)
+	assert.EqualValues(4, u.Len())
+	assert.EqualValues(3, u.nodeToUnprocessedMsgs[vdr1ID])
+
+	// The duplicate query is reported as dropped
+	recent := dropTracker.Recent(0)
+	assert.Len(recent, 1)
+	assert.Equal(vdr1ID, recent[0].NodeID)
+	assert.Equal(message.GetAccepted, recent[0].Op)
+	assert.Equal(drops.Duplicate, recent[0].Reason)
+
+	for _, expected := range []message.InboundMessage{msg1, msg2, msg3, msg5} {
+		gotMsg, ok := u.Pop()
+		assert.True(ok)
+		assert.Equal(expected, gotMsg)
+	}
+	assert.Empty(u.queuedQueries)
+
+	// Once answered, an identical query is queued again
+	u.Push(msg1)
+	assert.EqualValues(1, u.Len())
+
+	// An expired query is replaced by an identical query
+	u.clock.Set(time.Now().Add(2 * time.Second))
+	u.Push(msg4)
+	assert.EqualValues(1, u.Len())
+	recent = dropTracker.Recent(0)
+	assert.Len(recent, 2)
+	assert.Equal(drops.Expired, recent[0].Reason)
+
+	// Queued messages are dropped on shutdown
+	u.Shutdown()
+	recent = dropTracker.Recent(0)
+	assert.Len(recent, 3)
+	assert.Equal(drops.Shutdown, recent[0].Reason)
+}
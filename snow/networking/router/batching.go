@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// handleChitsBatch splits [msg] into one Chits message per answered request,
+// each of which is then routed as if it had been received on its own. This way
+// every request is cleared, timed and checked for equivocation individually.
+//
+// The batch is marked as handled once it's split, so the Chits it carried
+// aren't counted against the inbound throttler of [msg]'s sender.
+func (cr *ChainRouter) handleChitsBatch(chainID ids.ID, msg message.InboundMessage) {
+	defer msg.OnFinishedHandling()
+
+	nodeID := msg.NodeID()
+	requestIDs := msg.Get(message.RequestIDs).([]uint32)
+	votesBytes := msg.Get(message.ContainerIDs).([][]byte)
+	if len(requestIDs) != len(votesBytes) {
+		cr.log.Debug(
+			"dropping %s from %s%s with %d request IDs but %d votes",
+			message.ChitsBatch,
+			constants.NodeIDPrefix, nodeID,
+			len(requestIDs),
+			len(votesBytes),
+		)
+		cr.drops.Record(nodeID, chainID, message.ChitsBatch, drops.Invalid)
+		return
+	}
+
+	votes := make([]ids.ID, len(votesBytes))
+	for i, voteBytes := range votesBytes {
+		vote, err := ids.ToID(voteBytes)
+		if err != nil {
+			cr.log.Debug("dropping %s from %s%s due to invalid vote: %s", message.ChitsBatch, constants.NodeIDPrefix, nodeID, err)
+			cr.drops.Record(nodeID, chainID, message.ChitsBatch, drops.Invalid)
+			return
+		}
+		votes[i] = vote
+	}
+
+	for i, requestID := range requestIDs {
+		cr.HandleInbound(cr.msgCreator.InboundChits(chainID, requestID, []ids.ID{votes[i]}, nodeID))
+	}
+}
@@ -4,6 +4,7 @@
 package router
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -13,9 +14,12 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -27,6 +31,10 @@ import (
 	"github.com/ava-labs/avalanchego/version"
 )
 
+// The number of answered Chits remembered to detect peers that answer the same
+// query with conflicting votes.
+const answeredChitsCacheSize = 4096
+
 var (
 	errUnknownChain = errors.New("received message for unknown chain")
 
@@ -67,11 +75,22 @@ type ChainRouter struct {
 	metrics        *routerMetrics
 	// Parameters for doing health checks
 	healthConfig HealthConfig
+	// Notified of the messages this router drops
+	drops drops.Recorder
 	// aggregator of requests based on their time
 	timedRequests linkedhashmap.LinkedHashmap
 	// Must only be accessed in method [createRequestID].
 	// [lock] must be held when [requestIDBytes] is accessed.
 	requestIDBytes []byte
+	// unique request ID -> votes of the Chits that answered the request
+	answeredChits cache.LRU
+	// subnet ID -> policy applied to messages sent to the subnet's chains
+	policies map[ids.ID]*compiledPolicy
+	// coalescing key -> outstanding request that identical requests are
+	// coalesced into
+	coalescedRequests map[ids.ID]*coalescedRequest
+	// unique request ID of an outstanding request -> its coalescing key
+	coalescingLeaders map[ids.ID]ids.ID
 }
 
 // Initialize the router.
@@ -88,6 +107,7 @@ func (cr *ChainRouter) Initialize(
 	criticalChains ids.Set,
 	onFatal func(exitCode int),
 	healthConfig HealthConfig,
+	dropRecorder drops.Recorder,
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
 ) error {
@@ -103,6 +123,10 @@ func (cr *ChainRouter) Initialize(
 	cr.peers = make(map[ids.ShortID]version.Application)
 	cr.peers[nodeID] = version.CurrentApp
 	cr.healthConfig = healthConfig
+	cr.drops = dropRecorder
+	cr.answeredChits = cache.LRU{Size: answeredChitsCacheSize}
+	cr.coalescedRequests = make(map[ids.ID]*coalescedRequest)
+	cr.coalescingLeaders = make(map[ids.ID]ids.ID)
 	cr.requestIDBytes = make([]byte, hashing.AddrLen+hashing.HashLen+wrappers.IntLen+wrappers.ByteLen) // Validator ID, Chain ID, Request ID, Msg Type
 
 	// Register metrics
@@ -165,9 +189,18 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 	// Here we assign the requestID already in use for gossiped containers
 	// to allow a uniform handling of all messages
 	var requestID uint32
-	if op == message.AppGossip {
+	switch op {
+	case message.AppGossip:
 		requestID = constants.GossipMsgRequestID
-	} else {
+	case message.PushQueryBatch:
+		// Each query in the batch has its own request ID. The batch is handled
+		// as a whole by the chain, so that it can be answered with one
+		// ChitsBatch.
+	case message.ChitsBatch:
+		// Each of the answered requests is cleared on its own
+		cr.handleChitsBatch(chainID, msg)
+		return
+	default:
 		requestID = msg.Get(message.RequestID).(uint32)
 	}
 
@@ -184,6 +217,16 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 			chainID,
 			errUnknownChain,
 		)
+		cr.drops.Record(nodeID, chainID, op, drops.UnknownChain)
+
+		msg.OnFinishedHandling()
+		return
+	}
+
+	if !cr.allowedByPolicy(chain, msg) {
+		cr.log.Verbo("dropping %s from %s%s due to the message policy of chain %s", op, constants.NodeIDPrefix, nodeID, chainID)
+		cr.metrics.policyDropped.Inc()
+		cr.drops.Record(nodeID, chainID, op, drops.Policy)
 
 		msg.OnFinishedHandling()
 		return
@@ -196,6 +239,7 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 		if ctx.IsExecuting() {
 			cr.log.Debug("dropping %s and skipping queue since the chain is currently executing", op)
 			cr.metrics.droppedRequests.Inc()
+			cr.drops.Record(nodeID, chainID, op, drops.Executing)
 
 			msg.OnFinishedHandling()
 			return
@@ -217,6 +261,9 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 		// Tell the timeout manager we are no longer expecting a response
 		cr.timeoutManager.RemoveRequest(uniqueRequestID)
 
+		// Requests coalesced into this one will time out on their own
+		cr.removeCoalescedRequest(uniqueRequestID)
+
 		// Pass the failure to the chain
 		chain.Push(msg)
 		return
@@ -225,6 +272,7 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 	if ctx.IsExecuting() {
 		cr.log.Debug("dropping %s and skipping queue since the chain is currently executing", op)
 		cr.metrics.droppedRequests.Inc()
+		cr.drops.Record(nodeID, chainID, op, drops.Executing)
 
 		msg.OnFinishedHandling()
 		return
@@ -232,10 +280,17 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 
 	uniqueRequestID, req := cr.clearRequest(op, nodeID, chainID, requestID)
 	if req == nil {
-		// We didn't request this message.
+		// We didn't request this message, or this is a duplicated response.
+		if op == message.Chits {
+			cr.checkEquivocatingChits(ctx, nodeID, uniqueRequestID, msg)
+		}
+		cr.drops.Record(nodeID, chainID, op, drops.Unrequested)
 		msg.OnFinishedHandling()
 		return
 	}
+	if op == message.Chits {
+		cr.answeredChits.Put(uniqueRequestID, chitsVotes(msg))
+	}
 
 	// Calculate how long it took [nodeID] to reply
 	latency := cr.clock.Time().Sub(req.time)
@@ -243,6 +298,9 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 	// Tell the timeout manager we got a response
 	cr.timeoutManager.RegisterResponse(nodeID, chainID, uniqueRequestID, req.op, latency)
 
+	// Share the response with the requests that were coalesced into this one
+	cr.answerCoalescedRequests(chain, uniqueRequestID, msg)
+
 	// Pass the response to the chain
 	chain.Push(msg)
 }
@@ -281,7 +339,7 @@ func (cr *ChainRouter) AddChain(chain handler.Handler) {
 	chainID := chain.Context().ChainID
 	cr.log.Debug("registering chain %s with chain router", chainID)
 	chain.SetOnStopped(func() {
-		cr.removeChain(chainID)
+		cr.removeChain(chain)
 	})
 	cr.chains[chainID] = chain
 
@@ -423,10 +481,24 @@ func (cr *ChainRouter) HealthCheck() (interface{}, error) {
 
 // RemoveChain removes the specified chain so that incoming
 // messages can't be routed to it
-func (cr *ChainRouter) removeChain(chainID ids.ID) {
+func (cr *ChainRouter) RemoveChain(chainID ids.ID) {
 	cr.lock.Lock()
 	chain, exists := cr.chains[chainID]
+	cr.lock.Unlock()
 	if !exists {
+		cr.log.Debug("can't remove unknown chain %s", chainID)
+		return
+	}
+	cr.removeChain(chain)
+}
+
+// removeChain removes [chain] if it's still the handler messages to its chain
+// are routed to. A chain that was removed and then added again is unaffected
+// by the previous handler finishing its shutdown.
+func (cr *ChainRouter) removeChain(chain handler.Handler) {
+	chainID := chain.Context().ChainID
+	cr.lock.Lock()
+	if cr.chains[chainID] != chain {
 		cr.log.Debug("can't remove unknown chain %s", chainID)
 		cr.lock.Unlock()
 		return
@@ -471,6 +543,39 @@ func (cr *ChainRouter) clearRequest(
 	return uniqueRequestID, &request
 }
 
+// checkEquivocatingChits reports [nodeID] if [msg] answers a query that
+// [nodeID] already answered with different votes. Chits aren't signed, so
+// the conflict can't be proven to others and isn't recorded as evidence.
+// Assumes [cr.lock] is held.
+func (cr *ChainRouter) checkEquivocatingChits(
+	ctx *snow.ConsensusContext,
+	nodeID ids.ShortID,
+	uniqueRequestID ids.ID,
+	msg message.InboundMessage,
+) {
+	previousIntf, ok := cr.answeredChits.Get(uniqueRequestID)
+	if !ok {
+		return
+	}
+	if bytes.Equal(previousIntf.([]byte), chitsVotes(msg)) {
+		return
+	}
+
+	cr.metrics.equivocatingChits.Inc()
+	cr.log.Warn("%s%s sent conflicting chits for the same query on chain %s",
+		constants.NodeIDPrefix, nodeID, ctx.ChainID)
+}
+
+// chitsVotes returns the concatenated container IDs voted for in [msg]
+func chitsVotes(msg message.InboundMessage) []byte {
+	containerIDs, _ := msg.Get(message.ContainerIDs).([][]byte)
+	votes := make([]byte, 0, len(containerIDs)*hashing.HashLen)
+	for _, containerID := range containerIDs {
+		votes = append(votes, containerID...)
+	}
+	return votes
+}
+
 // Assumes [cr.lock] is held.
 // Assumes [message.Op] is an alias of byte.
 func (cr *ChainRouter) createRequestID(nodeID ids.ShortID, chainID ids.ID, requestID uint32, op message.Op) ids.ID {
@@ -13,6 +13,9 @@ type routerMetrics struct {
 	outstandingRequests   prometheus.Gauge
 	longestRunningRequest prometheus.Gauge
 	droppedRequests       prometheus.Counter
+	equivocatingChits     prometheus.Counter
+	policyDropped         prometheus.Counter
+	coalescedRequests     prometheus.Counter
 }
 
 func newRouterMetrics(namespace string, registerer prometheus.Registerer) (*routerMetrics, error) {
@@ -38,12 +41,36 @@ func newRouterMetrics(namespace string, registerer prometheus.Registerer) (*rout
 			Help:      "Number of dropped requests (all types)",
 		},
 	)
+	rMetrics.equivocatingChits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "equivocating_chits",
+			Help:      "Number of queries a peer answered with conflicting chits",
+		},
+	)
+	rMetrics.policyDropped = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "policy_dropped",
+			Help:      "Number of messages dropped due to a subnet's message policy",
+		},
+	)
+	rMetrics.coalescedRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "coalesced",
+			Help:      "Number of requests answered by the response to an identical outstanding request",
+		},
+	)
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		registerer.Register(rMetrics.outstandingRequests),
 		registerer.Register(rMetrics.longestRunningRequest),
 		registerer.Register(rMetrics.droppedRequests),
+		registerer.Register(rMetrics.equivocatingChits),
+		registerer.Register(rMetrics.policyDropped),
+		registerer.Register(rMetrics.coalescedRequests),
 	)
 	return rMetrics, errs.Err
 }
@@ -11,11 +11,14 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 
+	dto "github.com/prometheus/client_model/go"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -51,7 +54,7 @@ func TestShutdown(t *testing.T) {
 	mc, err := message.NewCreator(metrics, true, "dummyNamespace", 10*time.Second)
 	assert.NoError(t, err)
 
-	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
 	assert.NoError(t, err)
 
 	shutdownCalled := make(chan struct{}, 1)
@@ -149,6 +152,7 @@ func TestShutdownTimesOut(t *testing.T) {
 		ids.Set{},
 		nil,
 		HealthConfig{},
+		drops.NewNoOpRecorder(),
 		"",
 		metrics,
 	)
@@ -245,7 +249,7 @@ func TestRouterTimeout(t *testing.T) {
 	mc, err := message.NewCreator(metrics, true, "dummyNamespace", 10*time.Second)
 	assert.NoError(t, err)
 
-	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
 	assert.NoError(t, err)
 
 	// Create bootstrapper, engine and handler
@@ -365,7 +369,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.NoError(t, err)
-	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
 	assert.NoError(t, err)
 
 	// Create bootstrapper, engine and handler
@@ -472,7 +476,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 	mc, err := message.NewCreator(metrics, true, "dummyNamespace", 10*time.Second)
 	assert.NoError(t, err)
 
-	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
 	assert.NoError(t, err)
 
 	// Create bootstrapper, engine and handler
@@ -565,3 +569,360 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 	// the GetFailed message is sent
 	assert.Equal(t, 1, chainRouter.timedRequests.Len())
 }
+
+func TestRouterCountsEquivocatingChits(t *testing.T) {
+	assert := assert.New(t)
+
+	tm, err := timeout.NewManager(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     3 * time.Second,
+			MinimumTimeout:     3 * time.Second,
+			MaximumTimeout:     5 * time.Minute,
+			TimeoutCoefficient: 1,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist.NewNoBenchlist(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	go tm.Dispatch()
+
+	chainRouter := ChainRouter{}
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(err)
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+
+	vID := ids.GenerateTestShortID()
+	vdrs := validators.NewSet()
+	assert.NoError(vdrs.AddWeight(vID, 1))
+
+	handler, err := handler.New(
+		mc,
+		ctx,
+		vdrs,
+		nil,
+		nil,
+		time.Second,
+	)
+	assert.NoError(err)
+
+	bootstrapper := &common.BootstrapperTest{
+		BootstrapableTest: common.BootstrapableTest{
+			T: t,
+		},
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	bootstrapper.Default(false)
+	bootstrapper.ContextF = func() *snow.ConsensusContext { return ctx }
+	handler.SetBootstrapper(bootstrapper)
+
+	engine := &common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = func() *snow.ConsensusContext { return ctx }
+	handler.SetConsensus(engine)
+	ctx.SetState(snow.NormalOp) // assumed bootstrapping is done
+
+	chainRouter.AddChain(handler)
+	handler.Start(false)
+
+	chainRouter.RegisterRequest(vID, ctx.ChainID, 0, message.Chits)
+
+	vote0 := ids.GenerateTestID()
+	vote1 := ids.GenerateTestID()
+	chainRouter.HandleInbound(mc.InboundChits(ctx.ChainID, 0, []ids.ID{vote0}, vID))
+
+	// Repeating the same answer isn't an equivocation
+	chainRouter.HandleInbound(mc.InboundChits(ctx.ChainID, 0, []ids.ID{vote0}, vID))
+	metric := &dto.Metric{}
+	assert.NoError(chainRouter.metrics.equivocatingChits.Write(metric))
+	assert.Zero(metric.GetCounter().GetValue())
+
+	chainRouter.HandleInbound(mc.InboundChits(ctx.ChainID, 0, []ids.ID{vote1}, vID))
+	assert.NoError(chainRouter.metrics.equivocatingChits.Write(metric))
+	assert.Equal(1., metric.GetCounter().GetValue())
+}
+
+func TestRouterCoalescesIdenticalRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	tm, err := timeout.NewManager(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     3 * time.Second,
+			MinimumTimeout:     3 * time.Second,
+			MaximumTimeout:     5 * time.Minute,
+			TimeoutCoefficient: 1,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist.NewNoBenchlist(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	go tm.Dispatch()
+
+	chainRouter := ChainRouter{}
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(err)
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vID := ids.GenerateTestShortID()
+	vdrs := validators.NewSet()
+	assert.NoError(vdrs.AddWeight(vID, 1))
+
+	handler, err := handler.New(
+		mc,
+		ctx,
+		vdrs,
+		nil,
+		nil,
+		time.Second,
+	)
+	assert.NoError(err)
+
+	bootstrapper := &common.BootstrapperTest{
+		BootstrapableTest: common.BootstrapableTest{
+			T: t,
+		},
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	bootstrapper.Default(false)
+	bootstrapper.ContextF = func() *snow.ConsensusContext { return ctx }
+	handler.SetBootstrapper(bootstrapper)
+
+	answered := make(chan uint32, 2)
+	engine := &common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = func() *snow.ConsensusContext { return ctx }
+	engine.AcceptedFrontierF = func(nodeID ids.ShortID, requestID uint32, containerIDs []ids.ID) error {
+		answered <- requestID
+		return nil
+	}
+	handler.SetConsensus(engine)
+	ctx.SetState(snow.NormalOp) // assumed bootstrapping is done
+
+	chainRouter.AddChain(handler)
+	handler.Start(false)
+
+	assert.True(chainRouter.RegisterCoalescedRequest(vID, ctx.ChainID, 0, message.AcceptedFrontier, ids.Empty))
+	assert.False(chainRouter.RegisterCoalescedRequest(vID, ctx.ChainID, 1, message.AcceptedFrontier, ids.Empty))
+	// A request with different content isn't coalesced
+	assert.True(chainRouter.RegisterCoalescedRequest(vID, ctx.ChainID, 2, message.Accepted, ids.Empty))
+
+	chainRouter.HandleInbound(mc.InboundAcceptedFrontier(ctx.ChainID, 0, []ids.ID{ids.GenerateTestID()}, vID))
+
+	answeredIDs := map[uint32]struct{}{}
+	for i := 0; i < 2; i++ {
+		select {
+		case requestID := <-answered:
+			answeredIDs[requestID] = struct{}{}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for coalesced responses")
+		}
+	}
+	assert.Contains(answeredIDs, uint32(0))
+	assert.Contains(answeredIDs, uint32(1))
+
+	// Only the Accepted request is still outstanding
+	chainRouter.lock.Lock()
+	assert.Equal(1, chainRouter.timedRequests.Len())
+	assert.Len(chainRouter.coalescedRequests, 1)
+	chainRouter.lock.Unlock()
+}
+
+func TestRemoveChain(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := validators.NewSet()
+	err := vdrs.AddWeight(ids.GenerateTestShortID(), 1)
+	assert.NoError(err)
+	benchlist := benchlist.NewNoBenchlist()
+	tm, err := timeout.NewManager(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     time.Millisecond,
+			MinimumTimeout:     time.Millisecond,
+			MaximumTimeout:     10 * time.Second,
+			TimeoutCoefficient: 1.25,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	go tm.Dispatch()
+
+	chainRouter := ChainRouter{}
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(err)
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	// Each instance of the chain has its own context, as it would when a chain
+	// is restarted
+	newHandler := func() handler.Handler {
+		ctx := snow.DefaultConsensusContextTest()
+		ctx.SetState(snow.NormalOp)
+		h, err := handler.New(mc, ctx, vdrs, nil, nil, time.Second)
+		assert.NoError(err)
+
+		bootstrapper := &common.BootstrapperTest{
+			BootstrapableTest: common.BootstrapableTest{T: t},
+			EngineTest:        common.EngineTest{T: t},
+		}
+		bootstrapper.Default(false)
+		bootstrapper.ContextF = func() *snow.ConsensusContext { return ctx }
+		bootstrapper.HaltF = func() {}
+		h.SetBootstrapper(bootstrapper)
+
+		engine := &common.EngineTest{T: t}
+		engine.Default(false)
+		engine.ContextF = func() *snow.ConsensusContext { return ctx }
+		engine.ShutdownF = func() error { return nil }
+		h.SetConsensus(engine)
+		return h
+	}
+
+	handler1 := newHandler()
+	chainRouter.AddChain(handler1)
+	handler1.Start(false)
+
+	// The chain is shut down by the time RemoveChain returns
+	chainID := handler1.Context().ChainID
+	chainRouter.RemoveChain(chainID)
+	select {
+	case <-handler1.Stopped():
+	default:
+		t.Fatal("handler wasn't stopped")
+	}
+
+	// The chain can be added again, and isn't removed when its previous
+	// handler finishes shutting down
+	handler2 := newHandler()
+	chainRouter.AddChain(handler2)
+	handler2.Start(false)
+	time.Sleep(50 * time.Millisecond)
+
+	chainRouter.lock.Lock()
+	assert.Equal(handler2, chainRouter.chains[chainID])
+	chainRouter.lock.Unlock()
+
+	chainRouter.Shutdown()
+}
+
+func TestRouterSplitsBatches(t *testing.T) {
+	assert := assert.New(t)
+
+	tm, err := timeout.NewManager(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     3 * time.Second,
+			MinimumTimeout:     3 * time.Second,
+			MaximumTimeout:     5 * time.Minute,
+			TimeoutCoefficient: 1,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist.NewNoBenchlist(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	go tm.Dispatch()
+
+	chainRouter := ChainRouter{}
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(err)
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Millisecond, ids.Set{}, nil, HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vID := ids.GenerateTestShortID()
+	vdrs := validators.NewSet()
+	assert.NoError(vdrs.AddWeight(vID, 1))
+
+	handler, err := handler.New(
+		mc,
+		ctx,
+		vdrs,
+		nil,
+		nil,
+		time.Second,
+	)
+	assert.NoError(err)
+
+	bootstrapper := &common.BootstrapperTest{
+		BootstrapableTest: common.BootstrapableTest{
+			T: t,
+		},
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	bootstrapper.Default(false)
+	bootstrapper.ContextF = func() *snow.ConsensusContext { return ctx }
+	handler.SetBootstrapper(bootstrapper)
+
+	wg := sync.WaitGroup{}
+	wg.Add(4)
+	lock := sync.Mutex{}
+	chits := map[uint32]ids.ID{}
+	queries := map[uint32][]byte{}
+	engine := &common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = func() *snow.ConsensusContext { return ctx }
+	engine.ChitsF = func(nodeID ids.ShortID, requestID uint32, votes []ids.ID) error {
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(vID, nodeID)
+		assert.Len(votes, 1)
+		chits[requestID] = votes[0]
+		wg.Done()
+		return nil
+	}
+	engine.PushQueryF = func(nodeID ids.ShortID, requestID uint32, container []byte) error {
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(vID, nodeID)
+		queries[requestID] = container
+		wg.Done()
+		return nil
+	}
+	handler.SetConsensus(engine)
+	ctx.SetState(snow.NormalOp) // assumed bootstrapping is done
+
+	chainRouter.AddChain(handler)
+	handler.Start(false)
+
+	// Each request answered by the batch is cleared on its own, so the
+	// votes for requests that weren't sent are dropped
+	chainRouter.RegisterRequest(vID, ctx.ChainID, 1, message.Chits)
+	chainRouter.RegisterRequest(vID, ctx.ChainID, 2, message.Chits)
+	vote1 := ids.GenerateTestID()
+	vote2 := ids.GenerateTestID()
+	chainRouter.HandleInbound(mc.InboundChitsBatch(ctx.ChainID, []uint32{1, 2, 3}, []ids.ID{vote1, vote2, ids.GenerateTestID()}, vID))
+
+	// Engines that don't handle batches are passed each query on its own
+	chainRouter.HandleInbound(mc.InboundPushQueryBatch(
+		ctx.ChainID,
+		[]uint32{4, 5},
+		time.Minute,
+		[]ids.ID{ids.GenerateTestID(), ids.GenerateTestID()},
+		[][]byte{{4}, {5}},
+		vID,
+	))
+
+	wg.Wait()
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(map[uint32]ids.ID{1: vote1, 2: vote2}, chits)
+	assert.Equal(map[uint32][]byte{4: {4}, 5: {5}}, queries)
+}
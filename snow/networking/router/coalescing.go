@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
+	"github.com/ava-labs/avalanchego/snow/networking/handler"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// A request is only coalesced into an outstanding identical request if the
+// outstanding request was sent less than this long ago.
+const requestCoalescingWindow = 500 * time.Millisecond
+
+// coalescedRequest is an outstanding request whose response will also answer
+// the identical requests that were coalesced into it.
+type coalescedRequest struct {
+	// When the outstanding request was sent
+	time time.Time
+	// unique request ID of the outstanding request
+	leaderID ids.ID
+	// Request IDs of the requests that weren't sent
+	followers []uint32
+}
+
+// RegisterCoalescedRequest registers a request like RegisterRequest. If an
+// identical request, as identified by [op] and [contentID], was sent to
+// [nodeID] very recently and is still outstanding, then this request will be
+// answered with the response to that request and false is returned to signal
+// that this request shouldn't be sent.
+//
+// Only AcceptedFrontier and Accepted responses can be shared.
+func (cr *ChainRouter) RegisterCoalescedRequest(
+	nodeID ids.ShortID,
+	chainID ids.ID,
+	requestID uint32,
+	op message.Op,
+	contentID ids.ID,
+) bool {
+	cr.RegisterRequest(nodeID, chainID, requestID, op)
+	if op != message.AcceptedFrontier && op != message.Accepted {
+		return true
+	}
+
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	key := coalescingKey(nodeID, chainID, op, contentID)
+	now := cr.clock.Time()
+	if req, ok := cr.coalescedRequests[key]; ok {
+		if now.Sub(req.time) >= requestCoalescingWindow {
+			// Too old to coalesce into, but its followers still expect its
+			// response.
+			return true
+		}
+		req.followers = append(req.followers, requestID)
+		cr.metrics.coalescedRequests.Inc()
+		return false
+	}
+
+	leaderID := cr.createRequestID(nodeID, chainID, requestID, op)
+	cr.coalescedRequests[key] = &coalescedRequest{
+		time:     now,
+		leaderID: leaderID,
+	}
+	cr.coalescingLeaders[leaderID] = key
+	return true
+}
+
+// answerCoalescedRequests answers the requests coalesced into the request
+// with ID [uniqueRequestID] with the contents of [msg].
+// Assumes [cr.lock] is held.
+func (cr *ChainRouter) answerCoalescedRequests(
+	chain handler.Handler,
+	uniqueRequestID ids.ID,
+	msg message.InboundMessage,
+) {
+	req, ok := cr.removeCoalescedRequest(uniqueRequestID)
+	if !ok || len(req.followers) == 0 {
+		return
+	}
+
+	op := msg.Op()
+	nodeID := msg.NodeID()
+	chainID := chain.Context().ChainID
+	containerIDsBytes, _ := msg.Get(message.ContainerIDs).([][]byte)
+	containerIDs := make([]ids.ID, 0, len(containerIDsBytes))
+	for _, containerIDBytes := range containerIDsBytes {
+		containerID, err := ids.ToID(containerIDBytes)
+		if err != nil {
+			cr.log.Debug("dropping coalesced %s responses due to invalid container ID: %s", op, err)
+			cr.drops.Record(nodeID, chainID, op, drops.Invalid)
+			return
+		}
+		containerIDs = append(containerIDs, containerID)
+	}
+
+	for _, requestID := range req.followers {
+		followerID, followerReq := cr.clearRequest(op, nodeID, chainID, requestID)
+		if followerReq == nil {
+			// This request already failed
+			continue
+		}
+		cr.timeoutManager.RemoveRequest(followerID)
+
+		var followerMsg message.InboundMessage
+		if op == message.AcceptedFrontier {
+			followerMsg = cr.msgCreator.InboundAcceptedFrontier(chainID, requestID, containerIDs, nodeID)
+		} else {
+			followerMsg = cr.msgCreator.InboundAccepted(chainID, requestID, containerIDs, nodeID)
+		}
+		chain.Push(followerMsg)
+	}
+}
+
+// removeCoalescedRequest stops coalescing requests into the request with ID
+// [uniqueRequestID], if any were being coalesced into it.
+// Assumes [cr.lock] is held.
+func (cr *ChainRouter) removeCoalescedRequest(uniqueRequestID ids.ID) (*coalescedRequest, bool) {
+	key, ok := cr.coalescingLeaders[uniqueRequestID]
+	if !ok {
+		return nil, false
+	}
+	delete(cr.coalescingLeaders, uniqueRequestID)
+	req := cr.coalescedRequests[key]
+	delete(cr.coalescedRequests, key)
+	return req, true
+}
+
+func coalescingKey(nodeID ids.ShortID, chainID ids.ID, op message.Op, contentID ids.ID) ids.ID {
+	keyBytes := make([]byte, 0, hashing.AddrLen+2*hashing.HashLen+wrappers.ByteLen)
+	keyBytes = append(keyBytes, nodeID[:]...)
+	keyBytes = append(keyBytes, chainID[:]...)
+	keyBytes = append(keyBytes, byte(op))
+	keyBytes = append(keyBytes, contentID[:]...)
+	return hashing.ComputeHash256Array(keyBytes)
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/handler"
+)
+
+// The number of pending messages on a chain above which deprioritized messages
+// are dropped, if the policy doesn't specify it.
+const defaultDeprioritizedMaxPending = 64
+
+// PeerClass describes which peers a PolicyRule applies to
+type PeerClass string
+
+const (
+	AnyPeer       PeerClass = "any"
+	Validators    PeerClass = "validators"
+	NonValidators PeerClass = "nonValidators"
+)
+
+// PolicyAction describes what happens to a message matched by a PolicyRule
+type PolicyAction string
+
+const (
+	// Drop the message before it reaches the chain
+	Drop PolicyAction = "drop"
+	// Only deliver the message if the chain isn't busy
+	Deprioritize PolicyAction = "deprioritize"
+)
+
+// PolicyRule matches messages of the given types sent by the given class of
+// peers. Messages are matched against the validator set of the subnet the
+// destination chain belongs to.
+type PolicyRule struct {
+	// Names of the message types this rule applies to, e.g. "get". If empty,
+	// the rule applies to all requests and gossip sent by peers.
+	Ops    []string     `json:"ops"`
+	Peers  PeerClass    `json:"peers"`
+	Action PolicyAction `json:"action"`
+}
+
+// MessagePolicy is the set of rules applied to messages sent to the chains of
+// a subnet. The first matching rule is applied.
+type MessagePolicy struct {
+	Rules []PolicyRule `json:"rules"`
+	// Deprioritized messages are dropped while the destination chain has at
+	// least this many messages pending. Defaults to 64.
+	DeprioritizedMaxPending int `json:"deprioritizedMaxPending"`
+}
+
+type compiledRule struct {
+	ops    map[message.Op]struct{} // nil means all ops
+	peers  PeerClass
+	action PolicyAction
+}
+
+type compiledPolicy struct {
+	rules                   []compiledRule
+	deprioritizedMaxPending int
+}
+
+// Only requests and gossip sent by peers are subject to policies. Responses to
+// this node's requests must reach the chain, as dropping them would fail the
+// requests and count against the peers that answered them. Internal messages,
+// such as request failures, must also always reach the chain.
+var policyOps = message.UnrequestedOps
+
+func compilePolicy(policy MessagePolicy) (*compiledPolicy, error) {
+	opsByName := make(map[string]message.Op, len(policyOps))
+	for op := range policyOps {
+		opsByName[op.String()] = op
+	}
+
+	compiled := &compiledPolicy{
+		rules:                   make([]compiledRule, len(policy.Rules)),
+		deprioritizedMaxPending: policy.DeprioritizedMaxPending,
+	}
+	switch {
+	case compiled.deprioritizedMaxPending < 0:
+		return nil, fmt.Errorf("deprioritizedMaxPending must be non-negative but got %d", compiled.deprioritizedMaxPending)
+	case compiled.deprioritizedMaxPending == 0:
+		compiled.deprioritizedMaxPending = defaultDeprioritizedMaxPending
+	}
+
+	for i, rule := range policy.Rules {
+		switch rule.Peers {
+		case AnyPeer, Validators, NonValidators:
+		default:
+			return nil, fmt.Errorf("rule %d has unknown peer class %q", i, rule.Peers)
+		}
+		switch rule.Action {
+		case Drop, Deprioritize:
+		default:
+			return nil, fmt.Errorf("rule %d has unknown action %q", i, rule.Action)
+		}
+
+		compiledRule := compiledRule{
+			peers:  rule.Peers,
+			action: rule.Action,
+		}
+		if len(rule.Ops) > 0 {
+			compiledRule.ops = make(map[message.Op]struct{}, len(rule.Ops))
+			for _, name := range rule.Ops {
+				op, ok := opsByName[name]
+				if !ok {
+					return nil, fmt.Errorf("rule %d has unknown message type %q", i, name)
+				}
+				compiledRule.ops[op] = struct{}{}
+			}
+		}
+		compiled.rules[i] = compiledRule
+	}
+	return compiled, nil
+}
+
+// action returns the action of the first rule matching a message of type [op]
+// from a peer, and whether any rule matched.
+func (p *compiledPolicy) action(op message.Op, isValidator bool) (PolicyAction, bool) {
+	for _, rule := range p.rules {
+		if rule.ops != nil {
+			if _, ok := rule.ops[op]; !ok {
+				continue
+			}
+		}
+		switch rule.peers {
+		case Validators:
+			if !isValidator {
+				continue
+			}
+		case NonValidators:
+			if isValidator {
+				continue
+			}
+		}
+		return rule.action, true
+	}
+	return "", false
+}
+
+// SetMessagePolicies replaces the message policies of all subnets with
+// [policies]. Subnets that aren't in [policies] accept all messages. If any
+// policy is invalid, the existing policies are left unchanged.
+func (cr *ChainRouter) SetMessagePolicies(policies map[ids.ID]MessagePolicy) error {
+	compiled := make(map[ids.ID]*compiledPolicy, len(policies))
+	for subnetID, policy := range policies {
+		p, err := compilePolicy(policy)
+		if err != nil {
+			return fmt.Errorf("invalid message policy for subnet %s: %w", subnetID, err)
+		}
+		compiled[subnetID] = p
+	}
+
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	cr.policies = compiled
+	return nil
+}
+
+// allowedByPolicy returns false if the message policy of the subnet of
+// [chain] says that [msg] should be dropped.
+// Assumes [cr.lock] is held.
+func (cr *ChainRouter) allowedByPolicy(chain handler.Handler, msg message.InboundMessage) bool {
+	op := msg.Op()
+	if _, ok := policyOps[op]; !ok {
+		return true
+	}
+	policy, ok := cr.policies[chain.Context().SubnetID]
+	if !ok {
+		return true
+	}
+	action, matched := policy.action(op, chain.Validators().Contains(msg.NodeID()))
+	if !matched {
+		return true
+	}
+	switch action {
+	case Drop:
+		return false
+	case Deprioritize:
+		return chain.Len() < policy.deprioritizedMaxPending
+	default:
+		return true
+	}
+}
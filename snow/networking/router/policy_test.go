@@ -0,0 +1,153 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/networking/handler"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func TestCompilePolicyInvalid(t *testing.T) {
+	tests := map[string]MessagePolicy{
+		"unknown op": {Rules: []PolicyRule{{
+			Ops:    []string{"not_an_op"},
+			Peers:  AnyPeer,
+			Action: Drop,
+		}}},
+		"internal op": {Rules: []PolicyRule{{
+			Ops:    []string{message.GetFailed.String()},
+			Peers:  AnyPeer,
+			Action: Drop,
+		}}},
+		"response op": {Rules: []PolicyRule{{
+			Ops:    []string{message.Chits.String()},
+			Peers:  AnyPeer,
+			Action: Drop,
+		}}},
+		"unknown peer class": {Rules: []PolicyRule{{
+			Peers:  "friends",
+			Action: Drop,
+		}}},
+		"unknown action": {Rules: []PolicyRule{{
+			Peers:  AnyPeer,
+			Action: "ignore",
+		}}},
+		"negative max pending": {
+			DeprioritizedMaxPending: -1,
+		},
+	}
+	for name, policy := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := compilePolicy(policy)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPolicyAction(t *testing.T) {
+	assert := assert.New(t)
+
+	policy, err := compilePolicy(MessagePolicy{Rules: []PolicyRule{
+		{
+			Ops:    []string{message.Get.String(), message.GetAncestors.String()},
+			Peers:  NonValidators,
+			Action: Drop,
+		},
+		{
+			Ops:    []string{message.AppGossip.String()},
+			Peers:  AnyPeer,
+			Action: Deprioritize,
+		},
+	}})
+	assert.NoError(err)
+	assert.Equal(defaultDeprioritizedMaxPending, policy.deprioritizedMaxPending)
+
+	action, matched := policy.action(message.Get, false)
+	assert.True(matched)
+	assert.Equal(Drop, action)
+
+	_, matched = policy.action(message.Get, true)
+	assert.False(matched)
+
+	action, matched = policy.action(message.AppGossip, true)
+	assert.True(matched)
+	assert.Equal(Deprioritize, action)
+
+	_, matched = policy.action(message.PushQuery, false)
+	assert.False(matched)
+}
+
+func TestAllowedByPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.SubnetID = ids.GenerateTestID()
+
+	vdrID := ids.GenerateTestShortID()
+	vdrs := validators.NewSet()
+	assert.NoError(vdrs.AddWeight(vdrID, 1))
+
+	chain, err := handler.New(mc, ctx, vdrs, nil, nil, time.Second)
+	assert.NoError(err)
+
+	cr := &ChainRouter{}
+	err = cr.SetMessagePolicies(map[ids.ID]MessagePolicy{
+		ctx.SubnetID: {Rules: []PolicyRule{{
+			Ops:    []string{message.PullQuery.String()},
+			Peers:  NonValidators,
+			Action: Drop,
+		}}},
+	})
+	assert.NoError(err)
+
+	nonVdrID := ids.GenerateTestShortID()
+	containerID := ids.GenerateTestID()
+	assert.True(cr.allowedByPolicy(chain, mc.InboundPullQuery(ctx.ChainID, 0, time.Second, containerID, vdrID)))
+	assert.False(cr.allowedByPolicy(chain, mc.InboundPullQuery(ctx.ChainID, 0, time.Second, containerID, nonVdrID)))
+	assert.True(cr.allowedByPolicy(chain, mc.InboundChits(ctx.ChainID, 0, []ids.ID{containerID}, nonVdrID)))
+
+	// Rules that apply to all messages don't drop responses to this node's
+	// requests
+	err = cr.SetMessagePolicies(map[ids.ID]MessagePolicy{
+		ctx.SubnetID: {Rules: []PolicyRule{{
+			Peers:  AnyPeer,
+			Action: Drop,
+		}}},
+	})
+	assert.NoError(err)
+	assert.False(cr.allowedByPolicy(chain, mc.InboundPullQuery(ctx.ChainID, 0, time.Second, containerID, vdrID)))
+	assert.True(cr.allowedByPolicy(chain, mc.InboundChits(ctx.ChainID, 0, []ids.ID{containerID}, vdrID)))
+	assert.True(cr.allowedByPolicy(chain, mc.InboundPut(ctx.ChainID, 0, containerID, nil, vdrID)))
+
+	// An invalid policy leaves the previous policies in place
+	err = cr.SetMessagePolicies(map[ids.ID]MessagePolicy{
+		ctx.SubnetID: {Rules: []PolicyRule{{
+			Ops:    []string{message.PullQuery.String()},
+			Peers:  NonValidators,
+			Action: Drop,
+		}}},
+	})
+	assert.NoError(err)
+	err = cr.SetMessagePolicies(map[ids.ID]MessagePolicy{
+		ctx.SubnetID: {Rules: []PolicyRule{{Peers: AnyPeer, Action: "ignore"}}},
+	})
+	assert.Error(err)
+	assert.False(cr.allowedByPolicy(chain, mc.InboundPullQuery(ctx.ChainID, 0, time.Second, containerID, nonVdrID)))
+
+	// Removing the policy allows all messages
+	assert.NoError(cr.SetMessagePolicies(nil))
+	assert.True(cr.allowedByPolicy(chain, mc.InboundPullQuery(ctx.ChainID, 0, time.Second, containerID, nonVdrID)))
+}
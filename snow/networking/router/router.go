@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -32,11 +33,17 @@ type Router interface {
 		criticalChains ids.Set,
 		onFatal func(exitCode int),
 		healthConfig HealthConfig,
+		dropRecorder drops.Recorder,
 		metricsNamespace string,
 		metricsRegisterer prometheus.Registerer,
 	) error
 	Shutdown()
 	AddChain(chain handler.Handler)
+	// RemoveChain stops the specified chain and waits for it to shut down.
+	// Messages are no longer routed to the chain once this returns.
+	RemoveChain(chainID ids.ID)
+	// SetMessagePolicies replaces the per-subnet message policies
+	SetMessagePolicies(policies map[ids.ID]MessagePolicy) error
 	health.Checker
 }
 
@@ -50,4 +57,11 @@ type InternalHandler interface {
 		requestID uint32,
 		op message.Op,
 	)
+	RegisterCoalescedRequest(
+		nodeID ids.ShortID,
+		chainID ids.ID,
+		requestID uint32,
+		op message.Op,
+		contentID ids.ID,
+	) bool
 }
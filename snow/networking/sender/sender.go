@@ -12,10 +12,12 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 )
 
 var (
@@ -100,8 +102,13 @@ func (s *sender) SendGetAcceptedFrontier(nodeIDs ids.ShortSet, requestID uint32)
 	// We register timeouts for all nodes, regardless of whether we fail
 	// to send them a message, to avoid busy looping when disconnected from
 	// the internet.
+	// Nodes that were recently sent an identical request that is still
+	// outstanding aren't sent another one. The response to the outstanding
+	// request will also answer this one.
 	for nodeID := range nodeIDs {
-		s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.AcceptedFrontier)
+		if !s.router.RegisterCoalescedRequest(nodeID, s.ctx.ChainID, requestID, message.AcceptedFrontier, ids.Empty) {
+			nodeIDs.Remove(nodeID)
+		}
 	}
 
 	// Sending a message to myself. No need to send it over the network.
@@ -175,8 +182,17 @@ func (s *sender) SendGetAccepted(nodeIDs ids.ShortSet, requestID uint32, contain
 	// We register timeouts for all nodes, regardless of whether we fail
 	// to send them a message, to avoid busy looping when disconnected from
 	// the internet.
+	// Nodes that were recently sent an identical request that is still
+	// outstanding aren't sent another one.
+	containerIDBytes := make([]byte, 0, len(containerIDs)*hashing.HashLen)
+	for _, containerID := range containerIDs {
+		containerIDBytes = append(containerIDBytes, containerID[:]...)
+	}
+	contentID := hashing.ComputeHash256Array(containerIDBytes)
 	for nodeID := range nodeIDs {
-		s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.Accepted)
+		if !s.router.RegisterCoalescedRequest(nodeID, s.ctx.ChainID, requestID, message.Accepted, contentID) {
+			nodeIDs.Remove(nodeID)
+		}
 	}
 
 	// Sending a message to myself. No need to send it over the network.
@@ -272,7 +288,7 @@ func (s *sender) SendGetAncestors(nodeID ids.ShortID, requestID uint32, containe
 	// [nodeID] may be benched. That is, they've been unresponsive
 	// so we don't even bother sending requests to them. We just have them immediately fail.
 	if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
-		s.failedDueToBench[message.GetAncestors].Inc() // update metric
+		s.recordBenched(nodeID, message.GetAncestors)
 		s.timeouts.RegisterRequestToUnreachableValidator()
 		inMsg := s.msgCreator.InternalFailedRequest(message.GetAncestorsFailed, nodeID, s.ctx.ChainID, requestID)
 		go s.router.HandleInbound(inMsg)
@@ -364,7 +380,7 @@ func (s *sender) SendGet(nodeID ids.ShortID, requestID uint32, containerID ids.I
 	// [nodeID] may be benched. That is, they've been unresponsive
 	// so we don't even bother sending requests to them. We just have them immediately fail.
 	if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
-		s.failedDueToBench[message.Get].Inc() // update metric
+		s.recordBenched(nodeID, message.Get)
 		s.timeouts.RegisterRequestToUnreachableValidator()
 		inMsg := s.msgCreator.InternalFailedRequest(message.GetFailed, nodeID, s.ctx.ChainID, requestID)
 		go s.router.HandleInbound(inMsg)
@@ -474,7 +490,7 @@ func (s *sender) SendPushQuery(nodeIDs ids.ShortSet, requestID uint32, container
 	// so we don't even bother sending messages to them. We just have them immediately fail.
 	for nodeID := range nodeIDs {
 		if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
-			s.failedDueToBench[message.PushQuery].Inc() // update metric
+			s.recordBenched(nodeID, message.PushQuery)
 			nodeIDs.Remove(nodeID)
 			s.timeouts.RegisterRequestToUnreachableValidator()
 
@@ -558,7 +574,7 @@ func (s *sender) SendPullQuery(nodeIDs ids.ShortSet, requestID uint32, container
 	// so we don't even bother sending messages to them. We just have them immediately fail.
 	for nodeID := range nodeIDs {
 		if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
-			s.failedDueToBench[message.PullQuery].Inc() // update metric
+			s.recordBenched(nodeID, message.PullQuery)
 			nodeIDs.Remove(nodeID)
 			s.timeouts.RegisterRequestToUnreachableValidator()
 			// Immediately register a failure. Do so asynchronously to avoid deadlock.
@@ -636,6 +652,116 @@ func (s *sender) SendChits(nodeID ids.ShortID, requestID uint32, votes []ids.ID)
 	}
 }
 
+// SendPushQueryBatch sends a PushQueryBatch message to the consensus engine
+// running on the specified chain on the specified node. If the node doesn't
+// support batched queries, each query is sent as a PushQuery message instead.
+func (s *sender) SendPushQueryBatch(nodeID ids.ShortID, requestIDs []uint32, containerIDs []ids.ID, containers [][]byte) {
+	s.ctx.Log.Verbo(
+		"Sending PushQueryBatch to node %s. RequestIDs: %v. ContainerIDs: %s",
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+		requestIDs,
+		containerIDs,
+	)
+
+	// Queries to myself, and to benched nodes, never go over the network, so
+	// there is nothing to gain from batching them.
+	if nodeID == s.ctx.NodeID || s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
+		for i, requestID := range requestIDs {
+			nodeIDs := ids.NewShortSet(1)
+			nodeIDs.Add(nodeID)
+			s.SendPushQuery(nodeIDs, requestID, containerIDs[i], containers[i])
+		}
+		return
+	}
+
+	// Tell the router to expect a response message or a message notifying
+	// that we won't get a response for each of these requests.
+	for _, requestID := range requestIDs {
+		s.router.RegisterRequest(nodeID, s.ctx.ChainID, requestID, message.Chits)
+	}
+
+	// Note that this timeout duration won't exactly match the one that gets
+	// registered. That's OK.
+	deadline := s.timeouts.TimeoutDuration()
+
+	nodeIDs := ids.NewShortSet(1)
+	nodeIDs.Add(nodeID)
+	outMsg, err := s.msgCreator.PushQueryBatch(s.ctx.ChainID, requestIDs, deadline, containerIDs, containers)
+	if err == nil {
+		if sentTo := s.sender.Send(outMsg, nodeIDs, s.ctx.SubnetID, s.ctx.IsValidatorOnly()); sentTo.Len() != 0 {
+			return
+		}
+	} else {
+		s.ctx.Log.Error(
+			"failed to build PushQueryBatch(%s, %v, %s): %s",
+			s.ctx.ChainID,
+			requestIDs,
+			containerIDs,
+			err,
+		)
+	}
+
+	// The node may not support batched queries, so the queries are sent one at
+	// a time. Their requests are already registered.
+	for i, requestID := range requestIDs {
+		outMsg, err := s.msgCreator.PushQuery(s.ctx.ChainID, requestID, deadline, containerIDs[i], containers[i])
+		if err == nil {
+			if sentTo := s.sender.Send(outMsg, nodeIDs, s.ctx.SubnetID, s.ctx.IsValidatorOnly()); sentTo.Len() != 0 {
+				continue
+			}
+		}
+
+		s.ctx.Log.Debug(
+			"failed to send PushQuery(%s, %s, %d, %s)",
+			nodeID,
+			s.ctx.ChainID,
+			requestID,
+			containerIDs[i],
+		)
+
+		// Register a failure for the request we didn't send.
+		s.timeouts.RegisterRequestToUnreachableValidator()
+		inMsg := s.msgCreator.InternalFailedRequest(message.QueryFailed, nodeID, s.ctx.ChainID, requestID)
+		go s.router.HandleInbound(inMsg)
+	}
+}
+
+// SendChitsBatch sends a ChitsBatch message. If the node doesn't support
+// batched queries, each request is answered with a Chits message instead.
+func (s *sender) SendChitsBatch(nodeID ids.ShortID, requestIDs []uint32, votes []ids.ID) {
+	s.ctx.Log.Verbo(
+		"Sending ChitsBatch to node %s. RequestIDs: %v. Votes: %s",
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+		requestIDs,
+		votes,
+	)
+
+	// Chits to myself don't go over the network, so they're passed to my own
+	// router one at a time.
+	if nodeID != s.ctx.NodeID {
+		outMsg, err := s.msgCreator.ChitsBatch(s.ctx.ChainID, requestIDs, votes)
+		if err == nil {
+			nodeIDs := ids.NewShortSet(1)
+			nodeIDs.Add(nodeID)
+			if sentTo := s.sender.Send(outMsg, nodeIDs, s.ctx.SubnetID, s.ctx.IsValidatorOnly()); sentTo.Len() != 0 {
+				return
+			}
+		} else {
+			s.ctx.Log.Error(
+				"failed to build ChitsBatch(%s, %v, %s): %s",
+				s.ctx.ChainID,
+				requestIDs,
+				votes,
+				err,
+			)
+		}
+	}
+
+	for i, requestID := range requestIDs {
+		s.SendChits(nodeID, requestID, []ids.ID{votes[i]})
+	}
+}
+
 // SendAppRequest sends an application-level request to the given nodes.
 // The meaning of this request, and how it should be handled, is defined by the VM.
 func (s *sender) SendAppRequest(nodeIDs ids.ShortSet, requestID uint32, appRequestBytes []byte) error {
@@ -670,7 +796,7 @@ func (s *sender) SendAppRequest(nodeIDs ids.ShortSet, requestID uint32, appReque
 	// so we don't even bother sending messages to them. We just have them immediately fail.
 	for nodeID := range nodeIDs {
 		if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
-			s.failedDueToBench[message.AppRequest].Inc() // update metric
+			s.recordBenched(nodeID, message.AppRequest)
 			nodeIDs.Remove(nodeID)
 			s.timeouts.RegisterRequestToUnreachableValidator()
 
@@ -856,3 +982,12 @@ func (s *sender) Accept(ctx *snow.ConsensusContext, containerID ids.ID, containe
 	}
 	return nil
 }
+
+// recordBenched counts that a request of type [op] wasn't sent to [nodeID]
+// because it's benched
+func (s *sender) recordBenched(nodeID ids.ShortID, op message.Op) {
+	s.failedDueToBench[op].Inc()
+	if s.ctx.Drops != nil {
+		s.ctx.Drops.Record(nodeID, s.ctx.ChainID, op, drops.Benched)
+	}
+}
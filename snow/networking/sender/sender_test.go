@@ -19,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
@@ -84,7 +85,7 @@ func TestTimeout(t *testing.T) {
 	metrics := prometheus.NewRegistry()
 	mc, err := message.NewCreator(metrics, true, "dummyNamespace", 10*time.Second)
 	assert.NoError(t, err)
-	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, router.HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
 	assert.NoError(t, err)
 
 	context := snow.DefaultConsensusContextTest()
@@ -170,7 +171,7 @@ func TestReliableMessages(t *testing.T) {
 	metrics := prometheus.NewRegistry()
 	mc, err := message.NewCreator(metrics, true, "dummyNamespace", 10*time.Second)
 	assert.NoError(t, err)
-	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, router.HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
 	assert.NoError(t, err)
 
 	context := snow.DefaultConsensusContextTest()
@@ -262,7 +263,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 	metrics := prometheus.NewRegistry()
 	mc, err := message.NewCreator(metrics, true, "dummyNamespace", 10*time.Second)
 	assert.NoError(t, err)
-	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, router.HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
 	assert.NoError(t, err)
 
 	context := snow.DefaultConsensusContextTest()
@@ -326,3 +327,72 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		<-await
 	}
 }
+
+func TestSendBatchesFallBack(t *testing.T) {
+	assert := assert.New(t)
+
+	tm, err := timeout.NewManager(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     10 * time.Second,
+			MinimumTimeout:     10 * time.Second,
+			MaximumTimeout:     10 * time.Second,
+			TimeoutHalflife:    5 * time.Minute,
+			TimeoutCoefficient: 1.25,
+		},
+		benchlist.NewNoBenchlist(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	go tm.Dispatch()
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(err)
+	chainRouter := router.ChainRouter{}
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, nil, router.HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	// The peer doesn't support batched messages
+	var sentOps []message.Op
+	externalSender := &ExternalSenderTest{TB: t}
+	externalSender.SendF = func(msg message.OutboundMessage, nodeIDs ids.ShortSet, _ ids.ID, _ bool) ids.ShortSet {
+		sentOps = append(sentOps, msg.Op())
+		if msg.Op() == message.PushQueryBatch || msg.Op() == message.ChitsBatch {
+			return nil
+		}
+		return nodeIDs
+	}
+
+	ctx := snow.DefaultConsensusContextTest()
+	sender, err := New(ctx, mc, externalSender, &chainRouter, tm, defaultGossipConfig)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	sender.SendPushQueryBatch(
+		nodeID,
+		[]uint32{1, 2},
+		[]ids.ID{ids.GenerateTestID(), ids.GenerateTestID()},
+		[][]byte{{1}, {2}},
+	)
+	assert.Equal([]message.Op{message.PushQueryBatch, message.PushQuery, message.PushQuery}, sentOps)
+
+	sentOps = nil
+	sender.SendChitsBatch(nodeID, []uint32{1, 2}, []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()})
+	assert.Equal([]message.Op{message.ChitsBatch, message.Chits, message.Chits}, sentOps)
+
+	// The peer supports batched messages
+	externalSender.SendF = func(msg message.OutboundMessage, nodeIDs ids.ShortSet, _ ids.ID, _ bool) ids.ShortSet {
+		sentOps = append(sentOps, msg.Op())
+		return nodeIDs
+	}
+
+	sentOps = nil
+	sender.SendPushQueryBatch(
+		nodeID,
+		[]uint32{3, 4},
+		[]ids.ID{ids.GenerateTestID(), ids.GenerateTestID()},
+		[][]byte{{1}, {2}},
+	)
+	sender.SendChitsBatch(nodeID, []uint32{3, 4}, []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()})
+	assert.Equal([]message.Op{message.PushQueryBatch, message.ChitsBatch}, sentOps)
+}
@@ -31,6 +31,9 @@ type Manager interface {
 	// Must be called before any method calls that use the
 	// ID of the chain.
 	RegisterChain(ctx *snow.ConsensusContext) error
+	// DeregisterChain removes the metrics of the given chain so that it can
+	// be registered again.
+	DeregisterChain(chainID ids.ID)
 	// RegisterRequest notes that we expect a response of type [op] from
 	// [nodeID] for chain [chainID]. If we don't receive a response in
 	// time, [timeoutHandler] is executed.
@@ -103,6 +106,10 @@ func (m *manager) RegisterChain(ctx *snow.ConsensusContext) error {
 	return nil
 }
 
+func (m *manager) DeregisterChain(chainID ids.ID) {
+	m.metrics.DeregisterChain(chainID)
+}
+
 func (m *manager) RegisterRequest(
 	nodeID ids.ShortID,
 	chainID ids.ID,
@@ -45,6 +45,15 @@ func (m *metrics) RegisterChain(ctx *snow.ConsensusContext) error {
 	return nil
 }
 
+// DeregisterChain stops recording the metrics of [chainID]. The metrics were
+// registered with the chain's registerer, so they aren't unregistered here.
+func (m *metrics) DeregisterChain(chainID ids.ID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.chainToMetrics, chainID)
+}
+
 // Record that a response of type [op] took [latency]
 func (m *metrics) Observe(validatorID ids.ShortID, chainID ids.ID, op message.Op, latency time.Duration) {
 	m.lock.Lock()
@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package worker
+
+import (
+	"sync"
+)
+
+var _ Pipeline = &pipeline{}
+
+type Pipeline interface {
+	// Send the request to the pipeline.
+	//
+	// [prepare] is run on a worker pool, concurrently with the preparation of
+	// other requests. [handle] is run once [prepare] has returned, after the
+	// [handle] of every request that was sent before it.
+	//
+	// Send should never be called after [Shutdown] is called.
+	Send(prepare, handle Request)
+
+	// Shutdown the pipeline.
+	//
+	// This method will block until all requests that were sent have been
+	// handled.
+	//
+	// It is safe to call shutdown multiple times.
+	Shutdown()
+}
+
+type pendingRequest struct {
+	handle   Request
+	prepared chan struct{}
+}
+
+type pipeline struct {
+	pool    Pool
+	pending chan pendingRequest
+
+	shutdownOnce sync.Once
+	handlerDone  chan struct{}
+}
+
+// NewPipeline returns a pipeline that prepares up to [size] requests at a
+// time
+func NewPipeline(size int) Pipeline {
+	p := &pipeline{
+		pool:        NewPool(size),
+		pending:     make(chan pendingRequest, size),
+		handlerDone: make(chan struct{}),
+	}
+	go p.runHandler()
+	return p
+}
+
+func (p *pipeline) runHandler() {
+	defer close(p.handlerDone)
+
+	for request := range p.pending {
+		<-request.prepared
+		request.handle()
+	}
+}
+
+func (p *pipeline) Shutdown() {
+	p.shutdownOnce.Do(func() {
+		close(p.pending)
+	})
+	<-p.handlerDone
+	p.pool.Shutdown()
+}
+
+func (p *pipeline) Send(prepare, handle Request) {
+	prepared := make(chan struct{})
+	p.pending <- pendingRequest{
+		handle:   handle,
+		prepared: prepared,
+	}
+	p.pool.Send(func() {
+		defer close(prepared)
+		prepare()
+	})
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineHandlesInOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewPipeline(2)
+
+	// The first request can't finish preparing until the second one has
+	// started preparing, so they must be prepared concurrently
+	firstPrepared := make(chan struct{})
+	secondPreparing := make(chan struct{})
+	handled := []int{}
+	p.Send(
+		func() {
+			<-secondPreparing
+			close(firstPrepared)
+		},
+		func() { handled = append(handled, 0) },
+	)
+	p.Send(
+		func() { close(secondPreparing) },
+		func() {
+			// The second request isn't handled until the first one is
+			select {
+			case <-firstPrepared:
+			default:
+				t.Error("handled before the previous request was prepared")
+			}
+			handled = append(handled, 1)
+		},
+	)
+	for i := 2; i < 10; i++ {
+		i := i
+		p.Send(func() {}, func() { handled = append(handled, i) })
+	}
+	p.Shutdown()
+
+	assert.Equal([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, handled)
+}
@@ -12,6 +12,9 @@ var ErrUnknownState = errors.New("unknown node state")
 const (
 	Bootstrapping = iota + 1
 	NormalOp
+	// Stopped is the state of a chain that was shut down while the node kept
+	// running
+	Stopped
 )
 
 func (st State) String() string {
@@ -20,6 +23,8 @@ func (st State) String() string {
 		return "Bootstrapping state"
 	case NormalOp:
 		return "Normal operations state"
+	case Stopped:
+		return "Stopped state"
 	default:
 		// State.Unknown treated as default
 		return "Unknown state"
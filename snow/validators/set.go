@@ -72,6 +72,22 @@ type Set interface {
 	// RevealValidator ensures the named validator is not hidden from future
 	// samplings
 	RevealValidator(ids.ShortID) error
+
+	// RegisterCallbackListener notifies the listener of every change to the
+	// validators of the set. Before this returns, the current validators are
+	// reported to the listener as added. Masking a validator isn't reported.
+	//
+	// The listeners are called synchronously, while the set is locked, in the
+	// order the changes are made and then in the order the listeners were
+	// registered. Listeners must not call back into the set.
+	RegisterCallbackListener(SetCallbackListener)
+}
+
+// SetCallbackListener is notified of the changes to the validators of a Set
+type SetCallbackListener interface {
+	OnValidatorAdded(validatorID ids.ShortID, weight uint64)
+	OnValidatorRemoved(validatorID ids.ShortID, weight uint64)
+	OnValidatorWeightChanged(validatorID ids.ShortID, oldWeight, newWeight uint64)
 }
 
 // NewSet returns a new, empty set of validators.
@@ -103,6 +119,8 @@ type set struct {
 	sampler          sampler.WeightedWithoutReplacement
 	totalWeight      uint64
 	maskedVdrs       ids.ShortSet
+
+	callbackListeners []SetCallbackListener
 }
 
 func (s *set) Set(vdrs []Validator) error {
@@ -113,6 +131,21 @@ func (s *set) Set(vdrs []Validator) error {
 }
 
 func (s *set) set(vdrs []Validator) error {
+	// Weights of the replaced validators, to report the changes to the
+	// listeners
+	var (
+		oldVdrIDs  []ids.ShortID
+		oldWeights map[ids.ShortID]uint64
+	)
+	if len(s.callbackListeners) > 0 {
+		oldVdrIDs = make([]ids.ShortID, len(s.vdrSlice))
+		oldWeights = make(map[ids.ShortID]uint64, len(s.vdrSlice))
+		for i, vdr := range s.vdrSlice {
+			oldVdrIDs[i] = vdr.ID()
+			oldWeights[vdr.ID()] = s.vdrWeights[i]
+		}
+	}
+
 	lenVdrs := len(vdrs)
 	// If the underlying arrays are much larger than necessary, resize them to
 	// allow garbage collection of unused memory
@@ -163,6 +196,26 @@ func (s *set) set(vdrs []Validator) error {
 		}
 		s.totalWeight = newTotalWeight
 	}
+
+	if len(s.callbackListeners) == 0 {
+		return nil
+	}
+	for _, vdrID := range oldVdrIDs {
+		if !s.contains(vdrID) {
+			s.callValidatorRemovedCallbacks(vdrID, oldWeights[vdrID])
+		}
+	}
+	for i, vdr := range s.vdrSlice {
+		vdrID := vdr.ID()
+		newWeight := s.vdrWeights[i]
+		oldWeight, existed := oldWeights[vdrID]
+		switch {
+		case !existed:
+			s.callValidatorAddedCallbacks(vdrID, newWeight)
+		case oldWeight != newWeight:
+			s.callWeightChangeCallbacks(vdrID, oldWeight, newWeight)
+		}
+	}
 	return nil
 }
 
@@ -192,8 +245,14 @@ func (s *set) addWeight(vdrID ids.ShortID, weight uint64) error {
 		vdr = s.vdrSlice[i]
 	}
 
+	oldWeight := s.vdrWeights[i]
 	s.vdrWeights[i] += weight
 	vdr.addWeight(weight)
+	if ok {
+		s.callWeightChangeCallbacks(vdrID, oldWeight, s.vdrWeights[i])
+	} else {
+		s.callValidatorAddedCallbacks(vdrID, weight)
+	}
 
 	if s.maskedVdrs.Contains(vdrID) {
 		return nil
@@ -261,7 +320,8 @@ func (s *set) removeWeight(vdrID ids.ShortID, weight uint64) error {
 	// Validator exists
 	vdr := s.vdrSlice[i]
 
-	weight = safemath.Min64(s.vdrWeights[i], weight)
+	oldWeight := s.vdrWeights[i]
+	weight = safemath.Min64(oldWeight, weight)
 	s.vdrWeights[i] -= weight
 	vdr.removeWeight(weight)
 	if !s.maskedVdrs.Contains(vdrID) {
@@ -273,6 +333,9 @@ func (s *set) removeWeight(vdrID ids.ShortID, weight uint64) error {
 		if err := s.remove(vdrID); err != nil {
 			return err
 		}
+		s.callValidatorRemovedCallbacks(vdrID, oldWeight)
+	} else {
+		s.callWeightChangeCallbacks(vdrID, oldWeight, s.vdrWeights[i])
 	}
 	s.initialized = false
 	return nil
@@ -494,3 +557,34 @@ func (s *set) revealValidator(vdrID ids.ShortID) error {
 
 	return nil
 }
+
+func (s *set) RegisterCallbackListener(callbackListener SetCallbackListener) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.callbackListeners = append(s.callbackListeners, callbackListener)
+	for i, vdr := range s.vdrSlice {
+		callbackListener.OnValidatorAdded(vdr.ID(), s.vdrWeights[i])
+	}
+}
+
+// Assumes [s.lock] is held
+func (s *set) callWeightChangeCallbacks(vdrID ids.ShortID, oldWeight, newWeight uint64) {
+	for _, callbackListener := range s.callbackListeners {
+		callbackListener.OnValidatorWeightChanged(vdrID, oldWeight, newWeight)
+	}
+}
+
+// Assumes [s.lock] is held
+func (s *set) callValidatorAddedCallbacks(vdrID ids.ShortID, weight uint64) {
+	for _, callbackListener := range s.callbackListeners {
+		callbackListener.OnValidatorAdded(vdrID, weight)
+	}
+}
+
+// Assumes [s.lock] is held
+func (s *set) callValidatorRemovedCallbacks(vdrID ids.ShortID, weight uint64) {
+	for _, callbackListener := range s.callbackListeners {
+		callbackListener.OnValidatorRemoved(vdrID, weight)
+	}
+}
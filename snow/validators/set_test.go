@@ -255,3 +255,67 @@ func TestSamplerMasked(t *testing.T) {
 		assert.Equal(t, expected, result, "wrong string returned")
 	}
 }
+
+type change struct {
+	kind      string
+	vdrID     ids.ShortID
+	oldWeight uint64
+	newWeight uint64
+}
+
+type recordingListener struct {
+	changes []change
+}
+
+func (l *recordingListener) OnValidatorAdded(vdrID ids.ShortID, weight uint64) {
+	l.changes = append(l.changes, change{kind: "added", vdrID: vdrID, newWeight: weight})
+}
+
+func (l *recordingListener) OnValidatorRemoved(vdrID ids.ShortID, weight uint64) {
+	l.changes = append(l.changes, change{kind: "removed", vdrID: vdrID, oldWeight: weight})
+}
+
+func (l *recordingListener) OnValidatorWeightChanged(vdrID ids.ShortID, oldWeight, newWeight uint64) {
+	l.changes = append(l.changes, change{kind: "changed", vdrID: vdrID, oldWeight: oldWeight, newWeight: newWeight})
+}
+
+func TestSetCallbackListener(t *testing.T) {
+	assert := assert.New(t)
+
+	vdr0 := ids.ShortID{1}
+	vdr1 := ids.ShortID{2}
+	vdr2 := ids.ShortID{3}
+
+	s := NewSet()
+	assert.NoError(s.AddWeight(vdr0, 1))
+
+	listener := &recordingListener{}
+	s.RegisterCallbackListener(listener)
+	assert.Equal([]change{{kind: "added", vdrID: vdr0, newWeight: 1}}, listener.changes)
+
+	listener.changes = nil
+	assert.NoError(s.AddWeight(vdr1, 2))
+	assert.NoError(s.AddWeight(vdr0, 3))
+	assert.NoError(s.RemoveWeight(vdr0, 1))
+	assert.NoError(s.RemoveWeight(vdr1, 5))
+	// Masking isn't a change of the validators
+	assert.NoError(s.MaskValidator(vdr0))
+	assert.Equal([]change{
+		{kind: "added", vdrID: vdr1, newWeight: 2},
+		{kind: "changed", vdrID: vdr0, oldWeight: 1, newWeight: 4},
+		{kind: "changed", vdrID: vdr0, oldWeight: 4, newWeight: 3},
+		{kind: "removed", vdrID: vdr1, oldWeight: 2},
+	}, listener.changes)
+
+	listener.changes = nil
+	assert.NoError(s.AddWeight(vdr1, 1))
+	assert.NoError(s.Set([]Validator{
+		NewValidator(vdr2, 1),
+		NewValidator(vdr0, 3),
+	}))
+	assert.Equal([]change{
+		{kind: "added", vdrID: vdr1, newWeight: 1},
+		{kind: "removed", vdrID: vdr1, oldWeight: 1},
+		{kind: "added", vdrID: vdr2, newWeight: 1},
+	}, listener.changes)
+}
@@ -14,6 +14,13 @@ import (
 
 var errMaxBytes = errors.New("too large")
 
+// Filter and Serializable are implemented by filters created by New and
+// Parse
+var (
+	_ Filter       = &steakKnifeFilter{}
+	_ Serializable = &steakKnifeFilter{}
+)
+
 type Filter interface {
 	// Add adds to filter, assumed thread safe
 	Add(...[]byte)
@@ -22,7 +29,23 @@ type Filter interface {
 	Check([]byte) bool
 }
 
+// Serializable is a filter that can be sent to another party, which can check
+// it after parsing it with Parse
+type Serializable interface {
+	Filter
+
+	// Bytes returns the binary representation of the filter, including the
+	// keys it hashes elements with
+	Bytes() ([]byte, error)
+}
+
 func New(maxN uint64, p float64, maxBytes uint64) (Filter, error) {
+	return NewSerializable(maxN, p, maxBytes)
+}
+
+// NewSerializable returns a new filter sized to hold [maxN] elements with a
+// false positive rate of [p]
+func NewSerializable(maxN uint64, p float64, maxBytes uint64) (Serializable, error) {
 	neededBytes := bytesSteakKnifeFilter(maxN, p)
 	if neededBytes > maxBytes {
 		return nil, errMaxBytes
@@ -30,6 +53,15 @@ func New(maxN uint64, p float64, maxBytes uint64) (Filter, error) {
 	return newSteakKnifeFilter(maxN, p)
 }
 
+// Parse returns the filter whose binary representation is [b]
+func Parse(b []byte) (Serializable, error) {
+	filter := &streakKnife.Filter{}
+	if err := filter.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return &steakKnifeFilter{filter: filter}, nil
+}
+
 type steakKnifeFilter struct {
 	lock   sync.RWMutex
 	filter *streakKnife.Filter
@@ -48,12 +80,15 @@ func bytesSteakKnifeFilter(maxN uint64, p float64) uint64 {
 	return totalSize * 8 // 8 == sizeof(uint64))
 }
 
-func newSteakKnifeFilter(maxN uint64, p float64) (Filter, error) {
+func newSteakKnifeFilter(maxN uint64, p float64) (*steakKnifeFilter, error) {
 	m := streakKnife.OptimalM(maxN, p)
 	k := streakKnife.OptimalK(m, maxN)
 
 	filter, err := streakKnife.New(m, k)
-	return &steakKnifeFilter{filter: filter}, err
+	if err != nil {
+		return nil, err
+	}
+	return &steakKnifeFilter{filter: filter}, nil
 }
 
 func (f *steakKnifeFilter) Add(bl ...[]byte) {
@@ -75,3 +110,10 @@ func (f *steakKnifeFilter) Check(b []byte) bool {
 	_, _ = h.Write(b)
 	return f.filter.Contains(h)
 }
+
+func (f *steakKnifeFilter) Bytes() ([]byte, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.filter.MarshalBinary()
+}
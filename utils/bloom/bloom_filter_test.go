@@ -29,3 +29,32 @@ func TestNew(t *testing.T) {
 	checked = f.Check([]byte("bye"))
 	assert.False(checked, "shouldn't have contained the key")
 }
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	f, err := NewSerializable(100, 0.01, units.MiB)
+	assert.NoError(err)
+
+	f.Add([]byte("hello"))
+
+	b, err := f.Bytes()
+	assert.NoError(err)
+
+	parsed, err := Parse(b)
+	assert.NoError(err)
+	assert.True(parsed.Check([]byte("hello")), "should have contained the key")
+	assert.False(parsed.Check([]byte("bye")), "shouldn't have contained the key")
+
+	parsedBytes, err := parsed.Bytes()
+	assert.NoError(err)
+	assert.Equal(b, parsedBytes)
+
+	_, err = Parse(b[:len(b)-1])
+	assert.Error(err, "should have failed to parse a truncated filter")
+}
+
+func TestNewSerializableMaxBytes(t *testing.T) {
+	_, err := NewSerializable(1000000, 0.0001, units.KiB)
+	assert.ErrorIs(t, err, errMaxBytes)
+}
@@ -11,4 +11,8 @@ const (
 	// SecretKeyPrefix is used to denote secret keys rather than other byte
 	// arrays.
 	SecretKeyPrefix string = "PrivateKey-"
+
+	// Ed25519SecretKeyPrefix is used to denote ed25519 secret keys rather than
+	// secp256k1 secret keys.
+	Ed25519SecretKeyPrefix string = "Ed25519PrivateKey-"
 )
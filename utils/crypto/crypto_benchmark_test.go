@@ -6,6 +6,7 @@ package crypto
 import (
 	"testing"
 
+	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 )
@@ -102,3 +103,28 @@ func BenchmarkSECP256k1Verify(b *testing.B) {
 		verify(SECP256K1)
 	}
 }
+
+// BenchmarkSECP256k1Recover runs the benchmark with SECP256K1 public key
+// recovery, where a new factory recovers each key
+func BenchmarkSECP256k1Recover(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		recoverSECP256K1(b, &FactorySECP256K1R{})
+	}
+}
+
+// BenchmarkSECP256k1RecoverCached runs the benchmark with SECP256K1 public key
+// recovery, where the recovered keys are cached
+func BenchmarkSECP256k1RecoverCached(b *testing.B) {
+	f := &FactorySECP256K1R{Cache: cache.LRU{Size: NumVerifies}}
+	for n := 0; n < b.N; n++ {
+		recoverSECP256K1(b, f)
+	}
+}
+
+func recoverSECP256K1(b *testing.B, f *FactorySECP256K1R) {
+	for i := 0; i < NumVerifies; i++ {
+		if _, err := f.RecoverHashPublicKey(hashes[i], sigs[SECP256K1][i]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -12,6 +12,17 @@ import (
 	"github.com/ava-labs/avalanchego/utils/hashing"
 )
 
+const (
+	// ED25519SigLen is the number of bytes in an ed25519 signature
+	ED25519SigLen = ed25519.SignatureSize
+
+	// ED25519SKLen is the number of bytes in an ed25519 private key
+	ED25519SKLen = ed25519.PrivateKeySize
+
+	// ED25519PKLen is the number of bytes in an ed25519 public key
+	ED25519PKLen = ed25519.PublicKeySize
+)
+
 var (
 	errWrongPublicKeySize  = errors.New("wrong public key size")
 	errWrongPrivateKeySize = errors.New("wrong private key size")
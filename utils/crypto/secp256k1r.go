@@ -42,6 +42,9 @@ const (
 	compactSigMagicOffset = 27
 )
 
+// RecoverCacheSize is the number of public keys held by RecoverCache
+const RecoverCacheSize = 4096
+
 var (
 	errCompressed = errors.New("wasn't expecting a compressed key")
 
@@ -51,7 +54,21 @@ var (
 	_ PrivateKey         = &PrivateKeySECP256K1R{}
 )
 
-type FactorySECP256K1R struct{ Cache cache.LRU }
+// RecoverCache is a bounded cache of recovered public keys that factories may
+// share. The same credentials are verified when a tx is gossiped, when it's
+// added to a mempool and when its block is verified, so sharing the cache
+// across the chains of the node means a signature is only recovered once.
+var RecoverCache cache.Cacher = &cache.LRU{Size: RecoverCacheSize}
+
+// FactorySECP256K1R caches the public keys it recovers, keyed by the hash and
+// signature they were recovered from
+type FactorySECP256K1R struct {
+	Cache cache.LRU
+
+	// SharedCache, if non-nil, holds the recovered public keys in place of
+	// [Cache], so that they're shared with other factories
+	SharedCache cache.Cacher
+}
 
 func (*FactorySECP256K1R) NewPrivateKey() (PrivateKey, error) {
 	k, err := secp256k1.GeneratePrivateKey()
@@ -78,11 +95,16 @@ func (f *FactorySECP256K1R) RecoverPublicKey(msg, sig []byte) (PublicKey, error)
 }
 
 func (f *FactorySECP256K1R) RecoverHashPublicKey(hash, sig []byte) (PublicKey, error) {
+	var recoverCache cache.Cacher = &f.Cache
+	if f.SharedCache != nil {
+		recoverCache = f.SharedCache
+	}
+
 	cacheBytes := make([]byte, len(hash)+len(sig))
 	copy(cacheBytes, hash)
 	copy(cacheBytes[len(hash):], sig)
 	id := hashing.ComputeHash256Array(cacheBytes)
-	if cachedPublicKey, ok := f.Cache.Get(id); ok {
+	if cachedPublicKey, ok := recoverCache.Get(id); ok {
 		return cachedPublicKey.(*PublicKeySECP256K1R), nil
 	}
 
@@ -105,7 +127,10 @@ func (f *FactorySECP256K1R) RecoverHashPublicKey(hash, sig []byte) (PublicKey, e
 	}
 
 	pubkey := &PublicKeySECP256K1R{pk: rawPubkey}
-	f.Cache.Put(id, pubkey)
+	// The address and bytes of a cached key are computed before it's shared,
+	// so that it isn't modified while it's used concurrently
+	pubkey.Address()
+	recoverCache.Put(id, pubkey)
 	return pubkey, nil
 }
 
@@ -46,6 +46,35 @@ func TestCachedRecover(t *testing.T) {
 	}
 }
 
+func TestSharedCachedRecover(t *testing.T) {
+	assert := assert.New(t)
+
+	f := FactorySECP256K1R{SharedCache: RecoverCache}
+	otherF := FactorySECP256K1R{SharedCache: RecoverCache}
+	key, err := f.NewPrivateKey()
+	assert.NoError(err)
+
+	hash := hashing.ComputeHash256([]byte{1, 2, 3})
+	sig, err := key.SignHash(hash)
+	assert.NoError(err)
+
+	pub, err := f.RecoverHashPublicKey(hash, sig)
+	assert.NoError(err)
+	assert.Equal(key.PublicKey().Address(), pub.Address())
+
+	// Factories sharing the cache don't recover the key again
+	otherPub, err := otherF.RecoverHashPublicKey(hash, sig)
+	assert.NoError(err)
+	assert.True(pub == otherPub)
+
+	// A factory that doesn't share the cache recovers the key again
+	unshared := FactorySECP256K1R{}
+	unsharedPub, err := unshared.RecoverHashPublicKey(hash, sig)
+	assert.NoError(err)
+	assert.False(pub == unsharedPub)
+	assert.Equal(pub.Address(), unsharedPub.Address())
+}
+
 func TestExtensive(t *testing.T) {
 	f := FactorySECP256K1R{}
 
@@ -51,6 +51,27 @@ func ComputeHash256Ranges(buf []byte, ranges [][2]int) []byte {
 	return hashBuilder.Sum(nil)
 }
 
+// ComputeHash256Batch Compute a cryptographically strong 256 bit hash of the
+//                     concatenation of the input byte slices, without
+//                     concatenating them.
+// Example: ComputeHash256Batch({1, 2}, {3})
+//          is equivalent to ComputeHash256Array({1, 2, 3}).
+func ComputeHash256Batch(bufs ...[]byte) Hash256 {
+	w := NewHash256Writer(nil)
+	for _, buf := range bufs {
+		_, _ = w.Write(buf)
+	}
+	return w.Sum256()
+}
+
+// ComputeHash256Reader Compute a cryptographically strong 256 bit hash of
+//                      everything read from [r] until EOF.
+func ComputeHash256Reader(r io.Reader) (Hash256, error) {
+	w := NewHash256Writer(nil)
+	_, err := io.Copy(w, r)
+	return w.Sum256(), err
+}
+
 // ComputeHash160Array Compute a cryptographically strong 160 bit hash of the
 //                     input byte slice.
 func ComputeHash160Array(buf []byte) Hash160 {
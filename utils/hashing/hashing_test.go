@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hashing
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeHash256Batch(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(ComputeHash256Array([]byte{1, 2, 3}), ComputeHash256Batch([]byte{1, 2}, nil, []byte{3}))
+	assert.Equal(ComputeHash256Array(nil), ComputeHash256Batch())
+}
+
+func TestComputeHash256Reader(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := make([]byte, 100_000)
+	for i := range buf {
+		buf[i] = byte(i)
+	}
+	hash, err := ComputeHash256Reader(bytes.NewReader(buf))
+	assert.NoError(err)
+	assert.Equal(ComputeHash256Array(buf), hash)
+}
+
+func TestHash256Writer(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &bytes.Buffer{}
+	w := NewHash256Writer(out)
+	_, err := w.Write([]byte{1, 2})
+	assert.NoError(err)
+	assert.Equal(ComputeHash256Array([]byte{1, 2}), w.Sum256())
+
+	// Taking the sum doesn't end the hash
+	_, err = w.Write([]byte{3})
+	assert.NoError(err)
+	assert.Equal(ComputeHash256Array([]byte{1, 2, 3}), w.Sum256())
+	assert.Equal([]byte{1, 2, 3}, out.Bytes())
+	assert.EqualValues(3, w.Written())
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hashing
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io"
+)
+
+var _ io.Writer = &Hash256Writer{}
+
+// Hash256Writer computes the 256 bit hash of the bytes written to it while
+// passing them on to an underlying writer. This allows a large value to be
+// hashed as it's streamed out, rather than building it in memory first.
+type Hash256Writer struct {
+	w io.Writer
+	h hash.Hash
+	n int64
+}
+
+// NewHash256Writer returns a writer that writes to [w] and hashes what was
+// written. If [w] is nil, the bytes are only hashed.
+func NewHash256Writer(w io.Writer) *Hash256Writer {
+	return &Hash256Writer{
+		w: w,
+		h: sha256.New(),
+	}
+}
+
+// Write writes [b] to the underlying writer and hashes the bytes that were
+// written
+func (w *Hash256Writer) Write(b []byte) (int, error) {
+	n := len(b)
+	var err error
+	if w.w != nil {
+		n, err = w.w.Write(b)
+	}
+	// Writing to a hash.Hash never returns an error
+	_, _ = w.h.Write(b[:n])
+	w.n += int64(n)
+	return n, err
+}
+
+// Written returns the number of bytes written
+func (w *Hash256Writer) Written() int64 { return w.n }
+
+// Sum256 returns the hash of the bytes written so far. Further writes continue
+// the hash.
+func (w *Hash256Writer) Sum256() Hash256 {
+	hash := Hash256{}
+	w.h.Sum(hash[:0])
+	return hash
+}
@@ -72,13 +72,15 @@ type AdaptiveTimeoutConfig struct {
 	// Larger halflife --> less volatile timeout
 	// [timeoutHalfLife] must be positive
 	TimeoutHalflife time.Duration `json:"timeoutHalflife"`
+	// Clock tells the time. If nil, the local clock is used.
+	Clock *mockable.Clock `json:"-"`
 }
 
 // AdaptiveTimeoutManager is a manager for timeouts.
 type AdaptiveTimeoutManager struct {
 	lock sync.Mutex
 	// Tells the time. Can be faked for testing.
-	clock                            mockable.Clock
+	clock                            *mockable.Clock
 	networkTimeoutMetric, avgLatency prometheus.Gauge
 	numTimeouts                      prometheus.Counter
 	// Averages the response time from all peers
@@ -127,6 +129,10 @@ func (tm *AdaptiveTimeoutManager) Initialize(
 		return errNonPositiveHalflife
 	}
 
+	tm.clock = config.Clock
+	if tm.clock == nil {
+		tm.clock = &mockable.Clock{}
+	}
 	tm.timeoutCoefficient = config.TimeoutCoefficient
 	tm.averager = math.NewAverager(float64(config.InitialTimeout), config.TimeoutHalflife, tm.clock.Time())
 	tm.minimumTimeout = config.MinimumTimeout
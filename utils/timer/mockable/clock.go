@@ -4,28 +4,41 @@
 package mockable
 
 import (
+	"sync/atomic"
 	"time"
 )
 
 // MaxTime was taken from https://stackoverflow.com/questions/25065055/what-is-the-maximum-time-time-in-go/32620397#32620397
 var MaxTime = time.Unix(1<<63-62135596801, 0) // 0 is used because we drop the nano-seconds
 
-// Clock acts as a thin wrapper around global time that allows for easy testing
+// Clock acts as a thin wrapper around global time that allows for easy testing.
+// A Clock may be shared by goroutines, so that a test can move the time of
+// every component that tells time with it.
 type Clock struct {
+	// faked holds the fakedTime the clock was last set to. It's empty until
+	// the clock is first set.
+	faked atomic.Value
+}
+
+type fakedTime struct {
 	faked bool
 	time  time.Time
 }
 
 // Set the time on the clock
-func (c *Clock) Set(time time.Time) { c.faked = true; c.time = time }
+func (c *Clock) Set(time time.Time) { c.faked.Store(fakedTime{faked: true, time: time}) }
 
 // Sync this clock with global time
-func (c *Clock) Sync() { c.faked = false }
+func (c *Clock) Sync() { c.faked.Store(fakedTime{}) }
+
+// Advance the time on the clock by [d]. If the clock was synced with global
+// time, it's set to the current time advanced by [d].
+func (c *Clock) Advance(d time.Duration) { c.Set(c.Time().Add(d)) }
 
 // Time returns the time on this clock
 func (c *Clock) Time() time.Time {
-	if c.faked {
-		return c.time
+	if faked, ok := c.faked.Load().(fakedTime); ok && faked.faked {
+		return faked.time
 	}
 	return time.Now()
 }
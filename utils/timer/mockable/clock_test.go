@@ -11,7 +11,7 @@ import (
 func TestClockSet(t *testing.T) {
 	clock := Clock{}
 	clock.Set(time.Unix(1000000, 0))
-	if clock.faked == false {
+	if faked, _ := clock.faked.Load().(fakedTime); !faked.faked {
 		t.Error("Fake time was set, but .faked flag was not set")
 	}
 	if !clock.Time().Equal(time.Unix(1000000, 0)) {
@@ -20,9 +20,10 @@ func TestClockSet(t *testing.T) {
 }
 
 func TestClockSync(t *testing.T) {
-	clock := Clock{true, time.Unix(0, 0)}
+	clock := Clock{}
+	clock.Set(time.Unix(0, 0))
 	clock.Sync()
-	if clock.faked == true {
+	if faked, _ := clock.faked.Load().(fakedTime); faked.faked {
 		t.Error("Clock was synced, but .faked flag was set")
 	}
 	if clock.Time().Equal(time.Unix(0, 0)) {
@@ -31,10 +32,20 @@ func TestClockSync(t *testing.T) {
 }
 
 func TestClockUnix(t *testing.T) {
-	clock := Clock{true, time.Unix(-14159040, 0)}
+	clock := Clock{}
+	clock.Set(time.Unix(-14159040, 0))
 	actual := clock.Unix()
 	if actual != 0 {
 		// We are Unix of 1970s, Moon landings are irrelevant
 		t.Errorf("Expected time prior to Unix epoch to be clamped to 0, got %d", actual)
 	}
 }
+
+func TestClockAdvance(t *testing.T) {
+	clock := Clock{}
+	clock.Set(time.Unix(1000000, 0))
+	clock.Advance(time.Second)
+	if !clock.Time().Equal(time.Unix(1000001, 0)) {
+		t.Error("Clock was advanced, but didn't return the advanced time")
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+// Weights provides the stake of the nodes whose times are considered
+type Weights interface {
+	GetWeight(ids.ShortID) (uint64, bool)
+}
+
+// PeerTime estimates the time of the network as the local time adjusted by the
+// stake-weighted median offset of the times recently reported by validators.
+// This way, a node with a skewed clock still reports a time that the majority
+// of the stake agrees with, and peers that aren't validators can't move it.
+type PeerTime struct {
+	Clock *mockable.Clock
+
+	lock       sync.Mutex
+	validators Weights
+	minPeers   int
+	maxPeers   int
+	// Node ID --> offset of the time it reported from the local time. Ordered
+	// by when the times were reported.
+	offsets linkedhashmap.LinkedHashmap
+}
+
+// weightedOffset is the offset of the time reported by a validator, weighted
+// by its stake
+type weightedOffset struct {
+	offset time.Duration
+	weight uint64
+}
+
+// NewPeerTime returns a PeerTime that reports the local time until at least
+// [minPeers] validators in [validators] reported their time. Only the times
+// reported by the [maxPeers] most recently reporting validators are
+// considered. The local time is told by [clock].
+func NewPeerTime(validators Weights, minPeers, maxPeers int, clock *mockable.Clock) *PeerTime {
+	return &PeerTime{
+		Clock:      clock,
+		validators: validators,
+		minPeers:   minPeers,
+		maxPeers:   maxPeers,
+		offsets:    linkedhashmap.New(),
+	}
+}
+
+// Observe records that [nodeID] reported that it's currently [peerTime]. Only
+// the most recently reported time of each peer is considered, and only while
+// the peer is a validator.
+func (p *PeerTime) Observe(nodeID ids.ShortID, peerTime time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// Peers that aren't validators aren't recorded, so that they can't evict
+	// the times reported by validators
+	if weight, ok := p.validators.GetWeight(nodeID); !ok || weight == 0 {
+		return
+	}
+
+	p.offsets.Put(nodeID, peerTime.Sub(p.Clock.Time()))
+	for p.offsets.Len() > p.maxPeers {
+		oldestID, _, _ := p.offsets.Oldest()
+		p.offsets.Delete(oldestID)
+	}
+}
+
+// Offset returns the stake-weighted median offset of the times reported by
+// validators from the local time. If too few validators reported their time, 0
+// is returned.
+func (p *PeerTime) Offset() time.Duration {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	offsets := make([]weightedOffset, 0, p.offsets.Len())
+	totalWeight := uint64(0)
+	it := p.offsets.NewIterator()
+	for it.Next() {
+		// Stakes change over time, so the current stake of each validator is
+		// used
+		weight, ok := p.validators.GetWeight(it.Key().(ids.ShortID))
+		if !ok || weight == 0 {
+			continue
+		}
+		offsets = append(offsets, weightedOffset{
+			offset: it.Value().(time.Duration),
+			weight: weight,
+		})
+		totalWeight += weight
+	}
+	if len(offsets) == 0 || len(offsets) < p.minPeers {
+		return 0
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i].offset < offsets[j].offset })
+
+	// Return the smallest offset that at least half of the stake is at or
+	// below
+	cumulativeWeight := uint64(0)
+	for _, offset := range offsets {
+		cumulativeWeight += offset.weight
+		if cumulativeWeight >= totalWeight-cumulativeWeight {
+			return offset.offset
+		}
+	}
+	return offsets[len(offsets)-1].offset
+}
+
+// Time returns the local time adjusted by the stake-weighted median offset of
+// the times reported by validators
+func (p *PeerTime) Time() time.Time {
+	offset := p.Offset()
+	return p.Clock.Time().Add(offset)
+}
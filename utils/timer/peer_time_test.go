@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+type testWeights map[ids.ShortID]uint64
+
+func (w testWeights) GetWeight(nodeID ids.ShortID) (uint64, bool) {
+	weight, ok := w[nodeID]
+	return weight, ok
+}
+
+// newTestValidators returns [weights] along with a validator for each weight
+func newTestValidators(weights ...uint64) (testWeights, []ids.ShortID) {
+	validators := testWeights{}
+	nodeIDs := make([]ids.ShortID, len(weights))
+	for i, weight := range weights {
+		nodeIDs[i] = ids.GenerateTestShortID()
+		validators[nodeIDs[i]] = weight
+	}
+	return validators, nodeIDs
+}
+
+func TestPeerTimeMedian(t *testing.T) {
+	assert := assert.New(t)
+
+	validators, nodeIDs := newTestValidators(1, 1, 1, 1)
+	now := time.Unix(1000, 0)
+	p := NewPeerTime(validators, 3, 5, &mockable.Clock{})
+	p.Clock.Set(now)
+
+	// Too few validators reported their time
+	p.Observe(nodeIDs[0], now.Add(10*time.Second))
+	p.Observe(nodeIDs[1], now.Add(20*time.Second))
+	assert.Equal(now, p.Time())
+
+	p.Observe(nodeIDs[2], now.Add(-30*time.Second))
+	assert.Equal(10*time.Second, p.Offset())
+	assert.Equal(now.Add(10*time.Second), p.Time())
+
+	// With an even number of equally weighted validators, the lower median is
+	// used
+	p.Observe(nodeIDs[3], now.Add(40*time.Second))
+	assert.Equal(10*time.Second, p.Offset())
+}
+
+func TestPeerTimeStakeWeighted(t *testing.T) {
+	assert := assert.New(t)
+
+	validators, nodeIDs := newTestValidators(1, 1, 5)
+	now := time.Unix(1000, 0)
+	p := NewPeerTime(validators, 1, 5, &mockable.Clock{})
+	p.Clock.Set(now)
+
+	// The validator with the majority of the stake decides the time
+	p.Observe(nodeIDs[0], now.Add(-10*time.Second))
+	p.Observe(nodeIDs[1], now.Add(-20*time.Second))
+	p.Observe(nodeIDs[2], now.Add(30*time.Second))
+	assert.Equal(30*time.Second, p.Offset())
+
+	// Stake changes are taken into account
+	validators[nodeIDs[2]] = 1
+	assert.Equal(-10*time.Second, p.Offset())
+
+	// Validators that left the set are ignored
+	delete(validators, nodeIDs[0])
+	assert.Equal(-20*time.Second, p.Offset())
+}
+
+func TestPeerTimeIgnoresNonValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	validators, nodeIDs := newTestValidators(1, 0)
+	now := time.Unix(1000, 0)
+	p := NewPeerTime(validators, 1, 1, &mockable.Clock{})
+	p.Clock.Set(now)
+
+	p.Observe(nodeIDs[0], now.Add(10*time.Second))
+	assert.Equal(10*time.Second, p.Offset())
+
+	// Peers without stake can't evict the times reported by validators
+	p.Observe(nodeIDs[1], now.Add(time.Hour))
+	p.Observe(ids.GenerateTestShortID(), now.Add(time.Hour))
+	assert.Equal(10*time.Second, p.Offset())
+}
+
+func TestPeerTimeOnlyLatestObservation(t *testing.T) {
+	assert := assert.New(t)
+
+	validators, nodeIDs := newTestValidators(1, 1, 1)
+	now := time.Unix(1000, 0)
+	p := NewPeerTime(validators, 1, 2, &mockable.Clock{})
+	p.Clock.Set(now)
+
+	p.Observe(nodeIDs[0], now.Add(10*time.Second))
+	p.Observe(nodeIDs[0], now.Add(20*time.Second))
+	assert.Equal(20*time.Second, p.Offset())
+
+	// The validator that reported least recently is evicted
+	p.Observe(nodeIDs[1], now.Add(40*time.Second))
+	p.Observe(nodeIDs[2], now.Add(60*time.Second))
+	assert.Equal(40*time.Second, p.Offset())
+}
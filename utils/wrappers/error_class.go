@@ -0,0 +1,79 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappers
+
+import (
+	"errors"
+)
+
+var _ error = &classifiedError{}
+
+// ErrorClass describes how the caller should react to a failure
+type ErrorClass uint8
+
+const (
+	// UnclassifiedError is the class of errors that weren't tagged. Callers
+	// fall back to their default handling.
+	UnclassifiedError ErrorClass = iota
+	// FatalError means that the component can't continue operating
+	FatalError
+	// InvalidError means that the input is invalid and will never become valid
+	InvalidError
+	// TransientError means that the operation failed but may succeed if it is
+	// retried later
+	TransientError
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case UnclassifiedError:
+		return "unclassified"
+	case FatalError:
+		return "fatal"
+	case InvalidError:
+		return "invalid"
+	case TransientError:
+		return "transient"
+	default:
+		return "unknown"
+	}
+}
+
+type classifiedError struct {
+	class ErrorClass
+	err   error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// NewFatal tags [err] as a FatalError
+func NewFatal(err error) error { return classify(FatalError, err) }
+
+// NewInvalid tags [err] as an InvalidError
+func NewInvalid(err error) error { return classify(InvalidError, err) }
+
+// NewTransient tags [err] as a TransientError
+func NewTransient(err error) error { return classify(TransientError, err) }
+
+func classify(class ErrorClass, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{
+		class: class,
+		err:   err,
+	}
+}
+
+// Classify returns the class of the outermost tagged error in the chain of
+// [err], or UnclassifiedError if no error in the chain was tagged.
+func Classify(err error) ErrorClass {
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classified.class
+	}
+	return UnclassifiedError
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	assert := assert.New(t)
+
+	base := errors.New("base")
+	assert.Equal(UnclassifiedError, Classify(nil))
+	assert.Equal(UnclassifiedError, Classify(base))
+
+	transient := NewTransient(base)
+	assert.Equal(TransientError, Classify(transient))
+	assert.ErrorIs(transient, base)
+	assert.Equal(base.Error(), transient.Error())
+
+	// The class is preserved when the error is wrapped
+	wrapped := fmt.Errorf("failed to verify: %w", transient)
+	assert.Equal(TransientError, Classify(wrapped))
+
+	// The outermost class takes precedence
+	assert.Equal(FatalError, Classify(NewFatal(wrapped)))
+	assert.Equal(InvalidError, Classify(NewInvalid(base)))
+
+	assert.NoError(NewFatal(nil))
+}
@@ -38,7 +38,9 @@ var (
 	errBadBool        = errors.New("unexpected value when unpacking bool")
 )
 
-// Packer packs and unpacks a byte array from/to standard values
+// Packer packs and unpacks a byte array from/to standard values.
+// The byte slices it unpacks are views into the byte array rather than copies,
+// so they're only valid for as long as the byte array isn't modified.
 type Packer struct {
 	Errs
 
@@ -63,6 +65,24 @@ func (p *Packer) CheckSpace(bytes int) {
 	}
 }
 
+// sliceCap returns the capacity to allocate for a slice of [n] unpacked
+// elements that are each at least [size] bytes long. The capacity is bounded by
+// the number of elements that fit in the rest of the byte array, so that a
+// corrupt length can't cause a large allocation.
+func (p *Packer) sliceCap(n uint32, size int) int {
+	if size <= 0 {
+		return 0
+	}
+	remaining := (len(p.Bytes) - p.Offset) / size
+	if remaining <= 0 {
+		return 0
+	}
+	if uint64(n) < uint64(remaining) {
+		return int(n)
+	}
+	return remaining
+}
+
 // Expand ensures that there is [bytes] bytes left of space in the byte slice.
 // If this is not allowed due to the maximum size, an error is added to the packer
 // In order to understand this code, its important to understand the difference
@@ -152,6 +172,28 @@ func (p *Packer) UnpackInt() uint32 {
 	return val
 }
 
+// PackInts append an int slice to the byte array
+func (p *Packer) PackInts(vals []uint32) {
+	p.PackInt(uint32(len(vals)))
+	for _, val := range vals {
+		p.PackInt(val)
+	}
+}
+
+// UnpackInts returns an int slice from the byte array. The number of ints is
+// read from the byte array.
+func (p *Packer) UnpackInts() []uint32 {
+	sliceSize := p.UnpackInt()
+	vals := []uint32(nil)
+	if n := p.sliceCap(sliceSize, IntLen); n > 0 {
+		vals = make([]uint32, 0, n)
+	}
+	for i := uint32(0); i < sliceSize && !p.Errored(); i++ {
+		vals = append(vals, p.UnpackInt())
+	}
+	return vals
+}
+
 // PackLong append a long to the byte array
 func (p *Packer) PackLong(val uint64) {
 	p.Expand(LongLen)
@@ -249,6 +291,9 @@ func (p *Packer) PackFixedByteSlices(byteSlices [][]byte) {
 func (p *Packer) UnpackFixedByteSlices(size int) [][]byte {
 	sliceSize := p.UnpackInt()
 	bytes := [][]byte(nil)
+	if n := p.sliceCap(sliceSize, size); n > 0 {
+		bytes = make([][]byte, 0, n)
+	}
 	for i := uint32(0); i < sliceSize && !p.Errored(); i++ {
 		bytes = append(bytes, p.UnpackFixedBytes(size))
 	}
@@ -267,6 +312,9 @@ func (p *Packer) Pack2DByteSlice(byteSlices [][]byte) {
 func (p *Packer) Unpack2DByteSlice() [][]byte {
 	sliceSize := p.UnpackInt()
 	bytes := [][]byte(nil)
+	if n := p.sliceCap(sliceSize, IntLen); n > 0 {
+		bytes = make([][]byte, 0, n)
+	}
 	for i := uint32(0); i < sliceSize && !p.Errored(); i++ {
 		bytes = append(bytes, p.UnpackBytes())
 	}
@@ -352,6 +400,20 @@ func TryUnpackInt(packer *Packer) interface{} {
 	return packer.UnpackInt()
 }
 
+// TryPackInts attempts to pack the value as a list of ints
+func TryPackInts(packer *Packer, valIntf interface{}) {
+	if val, ok := valIntf.([]uint32); ok {
+		packer.PackInts(val)
+	} else {
+		packer.Add(errBadType)
+	}
+}
+
+// TryUnpackInts attempts to unpack the value as a list of ints
+func TryUnpackInts(packer *Packer) interface{} {
+	return packer.UnpackInts()
+}
+
 // TryPackLong attempts to pack the value as a long
 func TryPackLong(packer *Packer, valIntf interface{}) {
 	if val, ok := valIntf.(uint64); ok {
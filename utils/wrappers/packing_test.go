@@ -5,6 +5,7 @@ package wrappers
 
 import (
 	"bytes"
+	"math"
 	"net"
 	"reflect"
 	"testing"
@@ -530,6 +531,19 @@ func TestPackerUnpackBool(t *testing.T) {
 	}
 }
 
+func TestPackerUnpackSliceCorruptLength(t *testing.T) {
+	// The length claims far more elements than the byte array holds
+	p := Packer{Bytes: []byte("\xff\xff\xff\xffAvaxEvax")}
+	actual := p.UnpackFixedByteSlices(4)
+	if !p.Errored() {
+		t.Fatal("Packer.UnpackFixedByteSlices should have set error, due to attempted out of bounds read")
+	}
+	// The capacity is bounded by the size of the byte array, not by the length
+	if cap(actual) > 8 {
+		t.Fatalf("Packer.UnpackFixedByteSlices allocated capacity %d for a byte array of 12 bytes", cap(actual))
+	}
+}
+
 func TestPacker2DByteSlice(t *testing.T) {
 	// Case: empty array
 	p := Packer{MaxSize: 1024}
@@ -650,3 +664,27 @@ func TestPackIPCertList(t *testing.T) {
 	assert.Equal(t, ipCert.Signature, resolvedUnpackedIPCertList[0].Signature)
 	assert.Equal(t, ipCert.Time, resolvedUnpackedIPCertList[0].Time)
 }
+
+func TestPackerInts(t *testing.T) {
+	p := Packer{MaxSize: 1024}
+	p.PackInts(nil)
+	assert.NoError(t, p.Err)
+	p = Packer{Bytes: p.Bytes}
+	assert.Empty(t, p.UnpackInts())
+	assert.NoError(t, p.Err)
+
+	vals := []uint32{0, 1, math.MaxUint32}
+	p = Packer{MaxSize: 1024}
+	p.PackInts(vals)
+	assert.NoError(t, p.Err)
+	assert.Len(t, p.Bytes, IntLen*(len(vals)+1))
+
+	p = Packer{Bytes: p.Bytes}
+	assert.Equal(t, vals, p.UnpackInts())
+	assert.NoError(t, p.Err)
+
+	// The length prefix claims more ints than there are
+	p = Packer{Bytes: []byte{0, 0, 0, 2, 0, 0, 0, 1}}
+	p.UnpackInts()
+	assert.Error(t, p.Err)
+}
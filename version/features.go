@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"sort"
+)
+
+// featureRegistry assigns each feature the bit it's advertised with in the
+// handshake. Bits are part of the wire format, so a bit must never be
+// reassigned, even once the feature it was assigned to is retired.
+var featureRegistry = map[Feature]uint{
+	CompressionFeature:    0,
+	AppMessagesFeature:    1,
+	BatchedQueriesFeature: 2,
+	StateSyncFeature:      3,
+	HeaderSyncFeature:     4,
+}
+
+// FeatureBits is the set of registered features a node advertises in its
+// Version message
+type FeatureBits uint64
+
+// NewFeatureBits returns the bits advertising [features]. Features that aren't
+// registered are ignored.
+func NewFeatureBits(features ...Feature) FeatureBits {
+	bits := FeatureBits(0)
+	for _, feature := range features {
+		if bit, ok := featureRegistry[feature]; ok {
+			bits |= 1 << bit
+		}
+	}
+	return bits
+}
+
+// Registered returns true if [feature] can be advertised in the handshake
+func Registered(feature Feature) bool {
+	_, ok := featureRegistry[feature]
+	return ok
+}
+
+// Contains returns true if [feature] is advertised by [b]
+func (b FeatureBits) Contains(feature Feature) bool {
+	bit, ok := featureRegistry[feature]
+	return ok && b&(1<<bit) != 0
+}
+
+// Features returns the registered features advertised by [b], sorted by name.
+// Bits that aren't assigned to a feature, which newer peers may set, are
+// ignored.
+func (b FeatureBits) Features() []Feature {
+	features := []Feature{}
+	for feature, bit := range featureRegistry {
+		if b&(1<<bit) != 0 {
+			features = append(features, feature)
+		}
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
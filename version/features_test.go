@@ -0,0 +1,37 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureRegistryBitsUnique(t *testing.T) {
+	assert := assert.New(t)
+
+	bits := map[uint]Feature{}
+	for feature, bit := range featureRegistry {
+		assert.Less(bit, uint(64))
+		other, ok := bits[bit]
+		assert.False(ok, "%s and %s are both assigned bit %d", feature, other, bit)
+		bits[bit] = feature
+	}
+}
+
+func TestFeatureBits(t *testing.T) {
+	assert := assert.New(t)
+
+	bits := NewFeatureBits(StateSyncFeature, CompressionFeature, Feature("unregistered"))
+	assert.True(bits.Contains(CompressionFeature))
+	assert.True(bits.Contains(StateSyncFeature))
+	assert.False(bits.Contains(HeaderSyncFeature))
+	assert.False(bits.Contains(Feature("unregistered")))
+	assert.Equal([]Feature{CompressionFeature, StateSyncFeature}, bits.Features())
+
+	// Bits that aren't assigned yet are ignored
+	bits |= 1 << 63
+	assert.Equal([]Feature{CompressionFeature, StateSyncFeature}, bits.Features())
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+var (
+	errPeerVersionTooOld = errors.New("peer version is older than the minimum acceptable version")
+	errPeerVersionTooNew = errors.New("peer version is newer than the maximum acceptable version")
+)
+
+// Feature is a capability that is only supported by peers running a
+// sufficiently recent version, or by peers advertising it in their handshake
+type Feature string
+
+const (
+	// CompressionFeature is support for receiving compressed messages
+	CompressionFeature Feature = "compression"
+	// AppMessagesFeature is support for AppRequest, AppResponse and AppGossip
+	// messages
+	AppMessagesFeature Feature = "appMessages"
+	// BatchedQueriesFeature is support for PushQueryBatch and ChitsBatch
+	// messages
+	BatchedQueriesFeature Feature = "batchedQueries"
+	// StateSyncFeature is support for serving state sync requests
+	StateSyncFeature Feature = "stateSync"
+	// HeaderSyncFeature is support for serving GetHeaders messages
+	HeaderSyncFeature Feature = "headerSync"
+)
+
+// DefaultFeatureVersions returns the first version supporting each feature
+func DefaultFeatureVersions() map[Feature]Application {
+	return map[Feature]Application{
+		CompressionFeature:    NewDefaultApplication(constants.PlatformName, 1, 7, 0),
+		AppMessagesFeature:    NewDefaultApplication(constants.PlatformName, 1, 6, 0),
+		BatchedQueriesFeature: NewDefaultApplication(constants.PlatformName, 1, 7, 11),
+	}
+}
+
+// PeerPolicy describes which peer versions are acceptable, in addition to the
+// compatibility rules of the network, and which features peers support.
+type PeerPolicy struct {
+	// If non-nil, peers running a version before MinVersion are disconnected
+	MinVersion Application
+	// If non-nil, peers running a version after MaxVersion are disconnected
+	MaxVersion Application
+	// Maps a feature to the first version that supports it. Features that
+	// aren't in this map are assumed to be supported by all peers, unless
+	// they're registered to be advertised in the handshake, in which case
+	// they're only supported by the peers advertising them.
+	FeatureVersions map[Feature]Application
+}
+
+// NewDefaultPeerPolicy returns a policy that accepts all versions and uses the
+// default feature versions
+func NewDefaultPeerPolicy() *PeerPolicy {
+	return &PeerPolicy{
+		FeatureVersions: DefaultFeatureVersions(),
+	}
+}
+
+// Acceptable returns nil if a peer running [peer] should be connected to
+func (p *PeerPolicy) Acceptable(peer Application) error {
+	if p.MinVersion != nil && peer.Before(p.MinVersion) {
+		return fmt.Errorf("%w: %s < %s", errPeerVersionTooOld, peer, p.MinVersion)
+	}
+	if p.MaxVersion != nil && p.MaxVersion.Before(peer) {
+		return fmt.Errorf("%w: %s > %s", errPeerVersionTooNew, peer, p.MaxVersion)
+	}
+	return nil
+}
+
+// Supports returns true if a peer running [peer], that didn't advertise its
+// features, supports [feature]
+func (p *PeerPolicy) Supports(peer Application, feature Feature) bool {
+	return p.PeerSupports(peer, nil, feature)
+}
+
+// Features returns the configured features supported by a peer running [peer],
+// that didn't advertise its features, sorted by name
+func (p *PeerPolicy) Features(peer Application) []Feature {
+	return p.PeerFeatures(peer, nil)
+}
+
+// PeerSupports returns true if a peer running [peer] supports [feature].
+// [advertised] is the set of features the peer advertised in its handshake, or
+// nil if it didn't advertise any. Peers must both advertise a registered
+// feature and run a version at least as recent as the one configured for it.
+func (p *PeerPolicy) PeerSupports(peer Application, advertised *FeatureBits, feature Feature) bool {
+	minVersion, hasVersion := p.FeatureVersions[feature]
+	if hasVersion && peer.Before(minVersion) {
+		return false
+	}
+	if !Registered(feature) {
+		return true
+	}
+	if advertised == nil {
+		return hasVersion
+	}
+	return advertised.Contains(feature)
+}
+
+// PeerFeatures returns the features supported by a peer running [peer], that
+// advertised [advertised], sorted by name
+func (p *PeerPolicy) PeerFeatures(peer Application, advertised *FeatureBits) []Feature {
+	candidates := make(map[Feature]struct{}, len(p.FeatureVersions)+len(featureRegistry))
+	for feature := range p.FeatureVersions {
+		candidates[feature] = struct{}{}
+	}
+	if advertised != nil {
+		for _, feature := range advertised.Features() {
+			candidates[feature] = struct{}{}
+		}
+	}
+
+	features := make([]Feature, 0, len(candidates))
+	for feature := range candidates {
+		if p.PeerSupports(peer, advertised, feature) {
+			features = append(features, feature)
+		}
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestPeerPolicyAcceptable(t *testing.T) {
+	assert := assert.New(t)
+
+	v1_6_0 := NewDefaultApplication(constants.PlatformName, 1, 6, 0)
+	v1_7_0 := NewDefaultApplication(constants.PlatformName, 1, 7, 0)
+	v1_8_0 := NewDefaultApplication(constants.PlatformName, 1, 8, 0)
+
+	policy := NewDefaultPeerPolicy()
+	assert.NoError(policy.Acceptable(v1_6_0))
+	assert.NoError(policy.Acceptable(v1_8_0))
+
+	policy.MinVersion = v1_7_0
+	policy.MaxVersion = v1_7_0
+	assert.ErrorIs(policy.Acceptable(v1_6_0), errPeerVersionTooOld)
+	assert.NoError(policy.Acceptable(v1_7_0))
+	assert.ErrorIs(policy.Acceptable(v1_8_0), errPeerVersionTooNew)
+}
+
+func TestPeerPolicyFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	v1_6_0 := NewDefaultApplication(constants.PlatformName, 1, 6, 0)
+	v1_7_0 := NewDefaultApplication(constants.PlatformName, 1, 7, 0)
+	v1_7_11 := NewDefaultApplication(constants.PlatformName, 1, 7, 11)
+
+	policy := NewDefaultPeerPolicy()
+	assert.True(policy.Supports(v1_6_0, AppMessagesFeature))
+	assert.False(policy.Supports(v1_6_0, CompressionFeature))
+	assert.True(policy.Supports(v1_7_0, CompressionFeature))
+	assert.False(policy.Supports(v1_7_0, BatchedQueriesFeature))
+	assert.True(policy.Supports(v1_7_11, BatchedQueriesFeature))
+	assert.True(policy.Supports(v1_6_0, Feature("unconfigured")))
+
+	assert.Equal([]Feature{AppMessagesFeature}, policy.Features(v1_6_0))
+	assert.Equal([]Feature{AppMessagesFeature, CompressionFeature}, policy.Features(v1_7_0))
+	assert.Equal([]Feature{AppMessagesFeature, BatchedQueriesFeature, CompressionFeature}, policy.Features(v1_7_11))
+}
+
+func TestPeerPolicyAdvertisedFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	v1_6_0 := NewDefaultApplication(constants.PlatformName, 1, 6, 0)
+	v1_7_11 := NewDefaultApplication(constants.PlatformName, 1, 7, 11)
+
+	policy := NewDefaultPeerPolicy()
+
+	// Peers that don't advertise their features are never assumed to support
+	// registered features without a configured version
+	assert.False(policy.PeerSupports(v1_7_11, nil, HeaderSyncFeature))
+	assert.True(policy.PeerSupports(v1_7_11, nil, CompressionFeature))
+
+	// Advertised features take precedence over the version of the peer...
+	advertised := NewFeatureBits(HeaderSyncFeature, AppMessagesFeature)
+	assert.True(policy.PeerSupports(v1_7_11, &advertised, HeaderSyncFeature))
+	assert.False(policy.PeerSupports(v1_7_11, &advertised, CompressionFeature))
+	assert.True(policy.PeerSupports(v1_7_11, &advertised, Feature("unconfigured")))
+	assert.Equal([]Feature{AppMessagesFeature, HeaderSyncFeature}, policy.PeerFeatures(v1_7_11, &advertised))
+
+	// ...but the peer must still run a version at least as recent as the one
+	// configured for the feature
+	policy.FeatureVersions[HeaderSyncFeature] = v1_7_11
+	assert.False(policy.PeerSupports(v1_6_0, &advertised, HeaderSyncFeature))
+	assert.Equal([]Feature{AppMessagesFeature}, policy.PeerFeatures(v1_6_0, &advertised))
+}
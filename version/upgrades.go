@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"time"
+)
+
+// Names of the timestamp-activated network upgrades
+const (
+	ApricotPhase0   = "apricotPhase0"
+	ApricotPhase1   = "apricotPhase1"
+	ApricotPhase2   = "apricotPhase2"
+	ApricotPhase3   = "apricotPhase3"
+	ApricotPhase4   = "apricotPhase4"
+	ApricotPhase5   = "apricotPhase5"
+	XChainMigration = "xChainMigration"
+	ApricotPhase6   = "apricotPhase6"
+)
+
+// The activation time reported for upgrades that aren't scheduled
+var unscheduledUpgradeTime = time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Upgrade is a protocol change that activates at a network specific time
+type Upgrade struct {
+	Name        string
+	Description string
+	Time        time.Time
+}
+
+// UpgradeSchedule is the ordered set of upgrades of a network. Modules that
+// change their behavior on an upgrade should look up its activation time here
+// so that all modules agree on when the upgrade activates.
+type UpgradeSchedule struct {
+	upgrades []Upgrade
+	byName   map[string]int
+}
+
+// NewUpgradeSchedule returns a schedule containing [upgrades]. Upgrades are
+// expected to be provided in the order they were introduced.
+func NewUpgradeSchedule(upgrades ...Upgrade) *UpgradeSchedule {
+	s := &UpgradeSchedule{
+		upgrades: upgrades,
+		byName:   make(map[string]int, len(upgrades)),
+	}
+	for i, upgrade := range upgrades {
+		s.byName[upgrade.Name] = i
+	}
+	return s
+}
+
+// GetUpgradeSchedule returns the upgrade schedule of the network [networkID]
+func GetUpgradeSchedule(networkID uint32) *UpgradeSchedule {
+	return NewUpgradeSchedule(
+		Upgrade{
+			Name:        ApricotPhase0,
+			Description: "Stops connecting to peers running versions older than the minimum unmasked version",
+			Time:        GetApricotPhase0Time(networkID),
+		},
+		Upgrade{
+			Name:        ApricotPhase1,
+			Description: "C-chain fee and gas changes",
+			Time:        GetApricotPhase1Time(networkID),
+		},
+		Upgrade{
+			Name:        ApricotPhase2,
+			Description: "C-chain support for the Berlin upgrade",
+			Time:        GetApricotPhase2Time(networkID),
+		},
+		Upgrade{
+			Name:        ApricotPhase3,
+			Description: "Dynamic C-chain fees and P-chain subnet and blockchain creation fees",
+			Time:        GetApricotPhase3Time(networkID),
+		},
+		Upgrade{
+			Name:        ApricotPhase4,
+			Description: "Activates the proposervm and P-chain app gossip",
+			Time:        GetApricotPhase4Time(networkID),
+		},
+		Upgrade{
+			Name:        ApricotPhase5,
+			Description: "Raises the minimum compatible version and moves P-chain atomic transactions into standard blocks",
+			Time:        GetApricotPhase5Time(networkID),
+		},
+		Upgrade{
+			Name:        XChainMigration,
+			Description: "Allows the X-chain to issue the stop vertex",
+			Time:        GetXChainMigrationTime(networkID),
+		},
+		Upgrade{
+			Name:        ApricotPhase6,
+			Description: "Allows P-chain validators to schedule changes of their delegation fee, and subnets to declare the fee asset of their chains",
+			Time:        GetApricotPhase6Time(networkID),
+		},
+	)
+}
+
+// Upgrades returns the upgrades of this schedule in the order they were
+// introduced
+func (s *UpgradeSchedule) Upgrades() []Upgrade {
+	upgrades := make([]Upgrade, len(s.upgrades))
+	copy(upgrades, s.upgrades)
+	return upgrades
+}
+
+// Get returns the upgrade named [name], if it is part of this schedule
+func (s *UpgradeSchedule) Get(name string) (Upgrade, bool) {
+	i, ok := s.byName[name]
+	if !ok {
+		return Upgrade{}, false
+	}
+	return s.upgrades[i], true
+}
+
+// ActivationTime returns the time the upgrade named [name] activates. Unknown
+// upgrades never activate.
+func (s *UpgradeSchedule) ActivationTime(name string) time.Time {
+	upgrade, ok := s.Get(name)
+	if !ok {
+		return unscheduledUpgradeTime
+	}
+	return upgrade.Time
+}
+
+// IsActivated returns true if the upgrade named [name] is active at [timestamp]
+func (s *UpgradeSchedule) IsActivated(name string, timestamp time.Time) bool {
+	upgrade, ok := s.Get(name)
+	return ok && !timestamp.Before(upgrade.Time)
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestUpgradeSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule := GetUpgradeSchedule(constants.MainnetID)
+	upgrades := schedule.Upgrades()
+	assert.Len(upgrades, 8)
+	for i := 1; i < len(upgrades); i++ {
+		assert.NotEqual(upgrades[i-1].Name, upgrades[i].Name)
+	}
+
+	ap4Time := GetApricotPhase4Time(constants.MainnetID)
+	assert.Equal(ap4Time, schedule.ActivationTime(ApricotPhase4))
+	assert.False(schedule.IsActivated(ApricotPhase4, ap4Time.Add(-time.Second)))
+	assert.True(schedule.IsActivated(ApricotPhase4, ap4Time))
+
+	_, ok := schedule.Get("unknown")
+	assert.False(ok)
+	assert.False(schedule.IsActivated("unknown", time.Now()))
+	assert.True(schedule.ActivationTime("unknown").After(time.Now()))
+
+	// Modifying the returned upgrades doesn't modify the schedule
+	upgrades[0].Time = time.Time{}
+	assert.Equal(GetApricotPhase0Time(constants.MainnetID), schedule.ActivationTime(ApricotPhase0))
+}
@@ -32,8 +32,16 @@ type Client interface {
 	ConfirmTx(ctx context.Context, txID ids.ID, freq time.Duration, options ...rpc.Option) (choices.Status, error)
 	// GetTx returns the byte representation of [txID]
 	GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error)
+	// SimulateTx verifies [tx] against the current state without issuing it.
+	// If [tx] is invalid, the reason is returned in the reply.
+	SimulateTx(ctx context.Context, tx []byte, options ...rpc.Option) (*api.SimulateTxReply, error)
+	// DecodeTx decodes the signed [tx] into JSON without verifying it
+	DecodeTx(ctx context.Context, tx []byte, options ...rpc.Option) (*api.DecodeTxReply, error)
 	// IssueStopVertex issues a stop vertex.
 	IssueStopVertex(ctx context.Context, options ...rpc.Option) error
+	// GetImportStatus returns which outputs of [exportTxID], exported from
+	// [sourceChain], were imported into this chain, and by which txs
+	GetImportStatus(ctx context.Context, sourceChain string, exportTxID ids.ID, options ...rpc.Option) (*api.GetImportStatusReply, error)
 	// GetUTXOs returns the byte representation of the UTXOs controlled by [addrs]
 	GetUTXOs(
 		ctx context.Context,
@@ -54,6 +62,9 @@ type Client interface {
 		startUTXOID string,
 		options ...rpc.Option,
 	) ([][]byte, api.Index, error)
+	// GetTxFee returns the fees of the transactions issued to the chain, and
+	// the asset they're paid in
+	GetTxFee(ctx context.Context, options ...rpc.Option) (*GetTxFeeReply, error)
 	// GetAssetDescription returns a description of [assetID]
 	GetAssetDescription(ctx context.Context, assetID string, options ...rpc.Option) (*GetAssetDescriptionReply, error)
 	// GetBalance returns the balance of [assetID] held by [addr].
@@ -117,6 +128,43 @@ type Client interface {
 	ExportKey(ctx context.Context, user api.UserPass, addr string, options ...rpc.Option) (string, error)
 	// ImportKey imports [privateKey] to [user]
 	ImportKey(ctx context.Context, user api.UserPass, privateKey string, options ...rpc.Option) (string, error)
+	// CreateEd25519Address creates a new address controlled by a new ed25519
+	// key of [user]
+	CreateEd25519Address(ctx context.Context, user api.UserPass, options ...rpc.Option) (string, error)
+	// ListEd25519Addresses returns all addresses on this chain controlled by
+	// the ed25519 keys of [user]
+	ListEd25519Addresses(ctx context.Context, user api.UserPass, options ...rpc.Option) ([]string, error)
+	// ExportEd25519Key returns the ed25519 private key corresponding to [addr]
+	// controlled by [user]
+	ExportEd25519Key(ctx context.Context, user api.UserPass, addr string, options ...rpc.Option) (string, error)
+	// ImportEd25519Key imports the ed25519 [privateKey] to [user]
+	ImportEd25519Key(ctx context.Context, user api.UserPass, privateKey string, options ...rpc.Option) (string, error)
+	// CreateEd25519Asset creates a new fixed cap asset whose [holders] hold
+	// it with ed25519 keys, and returns its assetID
+	CreateEd25519Asset(
+		ctx context.Context,
+		user api.UserPass,
+		from []string,
+		changeAddr,
+		name,
+		symbol string,
+		denomination byte,
+		holders []*Holder,
+		options ...rpc.Option,
+	) (ids.ID, error)
+	// SendEd25519 sends [amount] of [assetID] from the ed25519 outputs of
+	// [user] to [to], and returns the ID of the newly created transaction
+	SendEd25519(
+		ctx context.Context,
+		user api.UserPass,
+		from []string,
+		changeAddr string,
+		amount uint64,
+		assetID,
+		to,
+		memo string,
+		options ...rpc.Option,
+	) (ids.ID, error)
 	// Mint [amount] of [assetID] to be owned by [to]
 	Mint(
 		ctx context.Context,
@@ -150,6 +198,16 @@ type Client interface {
 		to string,
 		options ...rpc.Option,
 	) (ids.ID, error)
+	// BatchOperations issues a transaction that performs all of [operations]
+	// and returns the ID of the newly created transaction
+	BatchOperations(
+		ctx context.Context,
+		user api.UserPass,
+		from []string,
+		changeAddr string,
+		operations []BatchOperation,
+		options ...rpc.Option,
+	) (ids.ID, error)
 	// Import sends an import transaction to import funds from [sourceChain] and
 	// returns the ID of the newly created transaction
 	Import(ctx context.Context, user api.UserPass, to, sourceChain string, options ...rpc.Option) (ids.ID, error) // Export sends an asset from this chain to the P/C-Chain.
@@ -192,6 +250,34 @@ func (c *client) IssueTx(ctx context.Context, txBytes []byte, options ...rpc.Opt
 	return res.TxID, err
 }
 
+func (c *client) SimulateTx(ctx context.Context, txBytes []byte, options ...rpc.Option) (*api.SimulateTxReply, error) {
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.SimulateTxReply{}
+	err = c.requester.SendRequest(ctx, "simulateTx", &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) DecodeTx(ctx context.Context, txBytes []byte, options ...rpc.Option) (*api.DecodeTxReply, error) {
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.DecodeTxReply{}
+	err = c.requester.SendRequest(ctx, "decodeTx", &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) IssueStopVertex(ctx context.Context, options ...rpc.Option) error {
 	return c.requester.SendRequest(ctx, "issueStopVertex", &struct{}{}, &struct{}{}, options...)
 }
@@ -241,6 +327,15 @@ func (c *client) GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option)
 	return txBytes, nil
 }
 
+func (c *client) GetImportStatus(ctx context.Context, sourceChain string, exportTxID ids.ID, options ...rpc.Option) (*api.GetImportStatusReply, error) {
+	res := &api.GetImportStatusReply{}
+	err := c.requester.SendRequest(ctx, "getImportStatus", &api.GetImportStatusArgs{
+		SourceChain: sourceChain,
+		ExportTxID:  exportTxID,
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) GetUTXOs(
 	ctx context.Context,
 	addrs []string,
@@ -287,6 +382,12 @@ func (c *client) GetAtomicUTXOs(
 	return utxos, res.EndIndex, nil
 }
 
+func (c *client) GetTxFee(ctx context.Context, options ...rpc.Option) (*GetTxFeeReply, error) {
+	res := &GetTxFeeReply{}
+	err := c.requester.SendRequest(ctx, "getTxFee", struct{}{}, res, options...)
+	return res, err
+}
+
 func (c *client) GetAssetDescription(ctx context.Context, assetID string, options ...rpc.Option) (*GetAssetDescriptionReply, error) {
 	res := &GetAssetDescriptionReply{}
 	err := c.requester.SendRequest(ctx, "getAssetDescription", &GetAssetDescriptionArgs{
@@ -459,6 +560,90 @@ func (c *client) ImportKey(ctx context.Context, user api.UserPass, privateKey st
 	return res.Address, err
 }
 
+func (c *client) CreateEd25519Address(ctx context.Context, user api.UserPass, options ...rpc.Option) (string, error) {
+	res := &api.JSONAddress{}
+	err := c.requester.SendRequest(ctx, "createEd25519Address", &user, res, options...)
+	return res.Address, err
+}
+
+func (c *client) ListEd25519Addresses(ctx context.Context, user api.UserPass, options ...rpc.Option) ([]string, error) {
+	res := &api.JSONAddresses{}
+	err := c.requester.SendRequest(ctx, "listEd25519Addresses", &user, res, options...)
+	return res.Addresses, err
+}
+
+func (c *client) ExportEd25519Key(ctx context.Context, user api.UserPass, addr string, options ...rpc.Option) (string, error) {
+	res := &ExportKeyReply{}
+	err := c.requester.SendRequest(ctx, "exportEd25519Key", &ExportKeyArgs{
+		UserPass: user,
+		Address:  addr,
+	}, res, options...)
+	return res.PrivateKey, err
+}
+
+func (c *client) ImportEd25519Key(ctx context.Context, user api.UserPass, privateKey string, options ...rpc.Option) (string, error) {
+	res := &api.JSONAddress{}
+	err := c.requester.SendRequest(ctx, "importEd25519Key", &ImportKeyArgs{
+		UserPass:   user,
+		PrivateKey: privateKey,
+	}, res, options...)
+	return res.Address, err
+}
+
+func (c *client) CreateEd25519Asset(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr,
+	name,
+	symbol string,
+	denomination byte,
+	holders []*Holder,
+	options ...rpc.Option,
+) (ids.ID, error) {
+	res := &FormattedAssetID{}
+	err := c.requester.SendRequest(ctx, "createEd25519Asset", &CreateEd25519AssetArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		Name:           name,
+		Symbol:         symbol,
+		Denomination:   denomination,
+		InitialHolders: holders,
+	}, res, options...)
+	return res.AssetID, err
+}
+
+func (c *client) SendEd25519(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	amount uint64,
+	assetID,
+	to,
+	memo string,
+	options ...rpc.Option,
+) (ids.ID, error) {
+	res := &api.JSONTxID{}
+	err := c.requester.SendRequest(ctx, "sendEd25519", &SendArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		SendOutput: SendOutput{
+			Amount:  cjson.Uint64(amount),
+			AssetID: assetID,
+			To:      to,
+		},
+		Memo: memo,
+	}, res, options...)
+	return res.TxID, err
+}
+
 func (c *client) Send(
 	ctx context.Context,
 	user api.UserPass,
@@ -586,6 +771,26 @@ func (c *client) MintNFT(
 	return res.TxID, err
 }
 
+func (c *client) BatchOperations(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	operations []BatchOperation,
+	options ...rpc.Option,
+) (ids.ID, error) {
+	res := &api.JSONTxID{}
+	err := c.requester.SendRequest(ctx, "batchOperations", &BatchOperationsArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		Operations: operations,
+	}, res, options...)
+	return res.TxID, err
+}
+
 func (c *client) Import(ctx context.Context, user api.UserPass, to, sourceChain string, options ...rpc.Option) (ids.ID, error) {
 	res := &api.JSONTxID{}
 	err := c.requester.SendRequest(ctx, "import", &ImportArgs{
@@ -0,0 +1,474 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/keystore"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/ed25519fx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errNoEd25519Holders = errors.New("ed25519 asset must have initial holders")
+	errNoEd25519Keys    = errors.New("user has no ed25519 keys or funds")
+)
+
+// CreateEd25519Address creates an address controlled by a new ed25519 key of
+// the user [args.Username]
+func (service *Service) CreateEd25519Address(r *http.Request, args *api.UserPass, reply *api.JSONAddress) error {
+	service.vm.ctx.Log.Debug("AVM: CreateEd25519Address called for user '%s'", args.Username)
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	defer user.Close()
+
+	sk, err := keystore.NewEd25519Key(user)
+	if err != nil {
+		return err
+	}
+
+	reply.Address, err = service.vm.FormatLocalAddress(sk.PublicKey().Address())
+	if err != nil {
+		return fmt.Errorf("problem formatting address: %w", err)
+	}
+
+	// Return an error if the DB can't close, this will execute before the above
+	// db close.
+	return user.Close()
+}
+
+// ListEd25519Addresses returns all of the addresses controlled by the ed25519
+// keys of user [args.Username]
+func (service *Service) ListEd25519Addresses(_ *http.Request, args *api.UserPass, response *api.JSONAddresses) error {
+	service.vm.ctx.Log.Debug("AVM: ListEd25519Addresses called for user '%s'", args.Username)
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	defer user.Close()
+
+	addresses, err := user.GetEd25519Addresses()
+	if err != nil {
+		return fmt.Errorf("problem retrieving addresses: %w", err)
+	}
+
+	response.Addresses = make([]string, len(addresses))
+	for i, address := range addresses {
+		response.Addresses[i], err = service.vm.FormatLocalAddress(address)
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+	}
+	return user.Close()
+}
+
+// ExportEd25519Key returns the ed25519 private key that controls
+// [args.Address] from the provided user
+func (service *Service) ExportEd25519Key(r *http.Request, args *ExportKeyArgs, reply *ExportKeyReply) error {
+	service.vm.ctx.Log.Debug("AVM: ExportEd25519Key called for user %q", args.Username)
+
+	addr, err := service.vm.ParseLocalAddress(args.Address)
+	if err != nil {
+		return fmt.Errorf("problem parsing address %q: %w", args.Address, err)
+	}
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	defer user.Close()
+
+	sk, err := user.GetEd25519Key(addr)
+	if err != nil {
+		return fmt.Errorf("problem retrieving private key: %w", err)
+	}
+
+	// We assume that the maximum size of a byte slice that
+	// can be stringified is at least the length of an ed25519 private key
+	privKeyStr, _ := formatting.EncodeWithChecksum(formatting.CB58, sk.Bytes())
+	reply.PrivateKey = constants.Ed25519SecretKeyPrefix + privKeyStr
+	return user.Close()
+}
+
+// ImportEd25519Key adds an ed25519 private key to the provided user
+func (service *Service) ImportEd25519Key(r *http.Request, args *ImportKeyArgs, reply *api.JSONAddress) error {
+	service.vm.ctx.Log.Debug("AVM: ImportEd25519Key called for user '%s'", args.Username)
+
+	if !strings.HasPrefix(args.PrivateKey, constants.Ed25519SecretKeyPrefix) {
+		return fmt.Errorf("private key missing %s prefix", constants.Ed25519SecretKeyPrefix)
+	}
+
+	trimmedPrivateKey := strings.TrimPrefix(args.PrivateKey, constants.Ed25519SecretKeyPrefix)
+	privKeyBytes, err := formatting.Decode(formatting.CB58, trimmedPrivateKey)
+	if err != nil {
+		return fmt.Errorf("problem parsing private key: %w", err)
+	}
+
+	factory := crypto.FactoryED25519{}
+	skIntf, err := factory.ToPrivateKey(privKeyBytes)
+	if err != nil {
+		return fmt.Errorf("problem parsing private key: %w", err)
+	}
+	sk := skIntf.(*crypto.PrivateKeyED25519)
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	defer user.Close()
+
+	if err := user.PutEd25519Keys(sk); err != nil {
+		return fmt.Errorf("problem saving key %w", err)
+	}
+
+	reply.Address, err = service.vm.FormatLocalAddress(sk.PublicKey().Address())
+	if err != nil {
+		return fmt.Errorf("problem formatting address: %w", err)
+	}
+
+	return user.Close()
+}
+
+// CreateEd25519AssetArgs are arguments for passing into CreateEd25519Asset
+// requests
+type CreateEd25519AssetArgs struct {
+	api.JSONSpendHeader           // User, password, from addrs, change addr
+	Name                string    `json:"name"`
+	Symbol              string    `json:"symbol"`
+	Denomination        byte      `json:"denomination"`
+	InitialHolders      []*Holder `json:"initialHolders"`
+}
+
+// CreateEd25519Asset returns ID of the newly created fixed cap asset, whose
+// initial holders hold its units with ed25519 keys. The chain must support the
+// ed25519 fx.
+func (service *Service) CreateEd25519Asset(r *http.Request, args *CreateEd25519AssetArgs, reply *AssetIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: CreateEd25519Asset called with name: %s symbol: %s number of holders: %d",
+		args.Name,
+		args.Symbol,
+		len(args.InitialHolders),
+	)
+
+	if len(args.InitialHolders) == 0 {
+		return errNoEd25519Holders
+	}
+
+	fxIndex, err := service.vm.getFx(&ed25519fx.TransferOutput{})
+	if err != nil {
+		return fmt.Errorf("chain doesn't support ed25519 outputs: %w", err)
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	// Get the UTXOs/keys for the from addresses
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+
+	// Parse the change address.
+	if len(kc.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	if err != nil {
+		return err
+	}
+
+	amountsSpent, ins, keys, err := service.vm.Spend(
+		utxos,
+		kc,
+		map[ids.ID]uint64{
+			service.vm.feeAssetID: service.vm.CreateAssetTxFee,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	outs := []*avax.TransferableOutput{}
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.CreateAssetTxFee {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: service.vm.feeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - service.vm.CreateAssetTxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+
+	initialState := &InitialState{
+		FxIndex: uint32(fxIndex),
+		Outs:    make([]verify.State, 0, len(args.InitialHolders)),
+	}
+	for _, holder := range args.InitialHolders {
+		addr, err := service.vm.ParseLocalAddress(holder.Address)
+		if err != nil {
+			return err
+		}
+		initialState.Outs = append(initialState.Outs, &ed25519fx.TransferOutput{
+			TransferOutput: secp256k1fx.TransferOutput{
+				Amt: uint64(holder.Amount),
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addr},
+				},
+			},
+		})
+	}
+	initialState.Sort(service.vm.codec)
+
+	tx := Tx{UnsignedTx: &CreateAssetTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		Name:         args.Name,
+		Symbol:       args.Symbol,
+		Denomination: args.Denomination,
+		States:       []*InitialState{initialState},
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+
+	assetID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.AssetID = assetID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// SendEd25519 sends [args.Amount] of [args.AssetID] from the ed25519 outputs
+// of the user to an ed25519 output owned by [args.To]. The asset must support
+// the ed25519 fx. Its change is returned to an ed25519 address of the user.
+// The fee is paid from the secp256k1 outputs of the user, and its change is
+// sent to [args.ChangeAddr].
+func (service *Service) SendEd25519(r *http.Request, args *SendArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: SendEd25519 called with username: %s", args.Username)
+
+	// Validate the memo field
+	memoBytes := []byte(args.Memo)
+	if l := len(memoBytes); l > avax.MaxMemoSize {
+		return fmt.Errorf("max memo length is %d but provided memo field is length %d", avax.MaxMemoSize, l)
+	} else if args.Amount == 0 {
+		return errZeroAmount
+	}
+
+	if _, err := service.vm.getFx(&ed25519fx.TransferOutput{}); err != nil {
+		return fmt.Errorf("chain doesn't support ed25519 outputs: %w", err)
+	}
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return fmt.Errorf("couldn't find asset %s", args.AssetID)
+	}
+	to, err := service.vm.ParseLocalAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address %q: %w", args.To, err)
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	// Load user's UTXOs/keys
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+	edUTXOs, edKc, err := service.vm.LoadEd25519User(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+
+	// Parse the change address.
+	if len(kc.Keys) == 0 {
+		return errNoKeys
+	}
+	if len(edKc.Keys) == 0 {
+		return errNoEd25519Keys
+	}
+	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	if err != nil {
+		return err
+	}
+	edChangeAddr := edKc.Keys[0].PublicKey().Address()
+
+	amount := uint64(args.Amount)
+	amountsSpent, ins, keys, err := service.vm.Spend(
+		utxos,
+		kc,
+		map[ids.ID]uint64{
+			service.vm.feeAssetID: service.vm.TxFee,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	edAmountsSpent, edIns, edKeys, err := service.vm.SpendEd25519(
+		edUTXOs,
+		edKc,
+		map[ids.ID]uint64{
+			assetID: amount,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	outs := []*avax.TransferableOutput{{
+		Asset: avax.Asset{ID: assetID},
+		Out: &ed25519fx.TransferOutput{TransferOutput: secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{to},
+			},
+		}},
+	}}
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.TxFee {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: service.vm.feeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - service.vm.TxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+	if amountSpent := edAmountsSpent[assetID]; amountSpent > amount {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: assetID},
+			Out: &ed25519fx.TransferOutput{TransferOutput: secp256k1fx.TransferOutput{
+				Amt: amountSpent - amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{edChangeAddr},
+				},
+			}},
+		})
+	}
+	avax.SortTransferableOutputs(outs, service.vm.codec)
+
+	signers := make([]inputSigners, 0, len(ins)+len(edIns))
+	for i, in := range ins {
+		signers = append(signers, inputSigners{in: in, keys: keys[i]})
+	}
+	for i, in := range edIns {
+		signers = append(signers, inputSigners{in: in, ed25519Keys: edKeys[i]})
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		iID, iIndex := signers[i].in.InputSource()
+		jID, jIndex := signers[j].in.InputSource()
+		switch bytes.Compare(iID[:], jID[:]) {
+		case -1:
+			return true
+		case 0:
+			return iIndex < jIndex
+		default:
+			return false
+		}
+	})
+	ins = make([]*avax.TransferableInput, len(signers))
+	for i, signer := range signers {
+		ins[i] = signer.in
+	}
+
+	tx := Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    service.vm.ctx.NetworkID,
+		BlockchainID: service.vm.ctx.ChainID,
+		Outs:         outs,
+		Ins:          ins,
+		Memo:         memoBytes,
+	}}}
+	if err := signInputs(&tx, service.vm.codec, signers); err != nil {
+		return err
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// inputSigners is an input along with the keys that sign its credential. The
+// input is spent either with secp256k1 [keys] or with [ed25519Keys].
+type inputSigners struct {
+	in          *avax.TransferableInput
+	keys        []*crypto.PrivateKeySECP256K1R
+	ed25519Keys []*crypto.PrivateKeyED25519
+}
+
+// signInputs signs the inputs of [tx], which are the inputs of [signers] in
+// the same order. The credentials are made in runs of inputs spent by the same
+// fx.
+func signInputs(tx *Tx, c codec.Manager, signers []inputSigners) error {
+	for len(signers) > 0 {
+		isEd25519 := signers[0].ed25519Keys != nil
+		end := 1
+		for end < len(signers) && (signers[end].ed25519Keys != nil) == isEd25519 {
+			end++
+		}
+
+		if isEd25519 {
+			keys := make([][]*crypto.PrivateKeyED25519, end)
+			for i, signer := range signers[:end] {
+				keys[i] = signer.ed25519Keys
+			}
+			if err := tx.SignEd25519Fx(c, keys); err != nil {
+				return err
+			}
+		} else {
+			keys := make([][]*crypto.PrivateKeySECP256K1R, end)
+			for i, signer := range signers[:end] {
+				keys[i] = signer.keys
+			}
+			if err := tx.SignSECP256K1Fx(c, keys); err != nil {
+				return err
+			}
+		}
+		signers = signers[end:]
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/keystore"
+	"github.com/ava-labs/avalanchego/vms/ed25519fx"
+)
+
+func TestEd25519Keys(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, vm, _ := GenesisVM(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+	userPass := api.UserPass{
+		Username: username,
+		Password: password,
+	}
+
+	createReply := &api.JSONAddress{}
+	assert.NoError(s.CreateEd25519Address(nil, &userPass, createReply))
+
+	exportReply := &ExportKeyReply{}
+	assert.NoError(s.ExportEd25519Key(nil, &ExportKeyArgs{
+		UserPass: userPass,
+		Address:  createReply.Address,
+	}, exportReply))
+
+	// The ed25519 keys are kept apart from the secp256k1 keys
+	assert.Error(s.ExportKey(nil, &ExportKeyArgs{
+		UserPass: userPass,
+		Address:  createReply.Address,
+	}, &ExportKeyReply{}))
+	assert.Error(s.ImportKey(nil, &ImportKeyArgs{
+		UserPass:   userPass,
+		PrivateKey: exportReply.PrivateKey,
+	}, &api.JSONAddress{}))
+
+	// Importing a new key adds its address
+	factory := crypto.FactoryED25519{}
+	sk, err := factory.NewPrivateKey()
+	assert.NoError(err)
+	skStr, err := formatting.EncodeWithChecksum(formatting.CB58, sk.Bytes())
+	assert.NoError(err)
+	importReply := &api.JSONAddress{}
+	assert.NoError(s.ImportEd25519Key(nil, &ImportKeyArgs{
+		UserPass:   userPass,
+		PrivateKey: constants.Ed25519SecretKeyPrefix + skStr,
+	}, importReply))
+	addr, err := vm.FormatLocalAddress(sk.PublicKey().Address())
+	assert.NoError(err)
+	assert.Equal(addr, importReply.Address)
+
+	// The exported key is imported back to the same address
+	assert.NoError(s.ImportEd25519Key(nil, &ImportKeyArgs{
+		UserPass:   userPass,
+		PrivateKey: exportReply.PrivateKey,
+	}, importReply))
+	assert.Equal(createReply.Address, importReply.Address)
+
+	listReply := &api.JSONAddresses{}
+	assert.NoError(s.ListEd25519Addresses(nil, &userPass, listReply))
+	assert.Equal([]string{createReply.Address, addr}, listReply.Addresses)
+}
+
+func TestEd25519Asset(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, vm, _ := GenesisVMWithArgs(t, []*common.Fx{{
+		ID: ed25519fx.ID,
+		Fx: &ed25519fx.Fx{},
+	}}, nil)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+
+	user, err := keystore.NewUserFromKeystore(vm.ctx.Keystore, username, password)
+	assert.NoError(err)
+	assert.NoError(user.PutKeys(keys[0]))
+	holderKey, err := keystore.NewEd25519Key(user)
+	assert.NoError(err)
+	assert.NoError(user.Close())
+
+	feeAddrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	assert.NoError(err)
+	holderAddrStr, err := vm.FormatLocalAddress(holderKey.PublicKey().Address())
+	assert.NoError(err)
+	recipient := ids.GenerateTestShortID()
+	recipientStr, err := vm.FormatLocalAddress(recipient)
+	assert.NoError(err)
+	spendHeader := api.JSONSpendHeader{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: feeAddrStr},
+	}
+	accept := func(txID ids.ID) {
+		tx := UniqueTx{vm: vm, txID: txID}
+		assert.NoError(tx.Accept())
+	}
+	// balance returns the ed25519 outputs of the asset owned by [addr]
+	balance := func(assetID ids.ID, addr ids.ShortID) uint64 {
+		addrs := ids.ShortSet{}
+		addrs.Add(addr)
+		utxos, err := avax.GetAllUTXOs(vm.state, addrs)
+		assert.NoError(err)
+		amount := uint64(0)
+		for _, utxo := range utxos {
+			if out, ok := utxo.Out.(*ed25519fx.TransferOutput); ok && utxo.AssetID() == assetID {
+				amount += out.Amt
+			}
+		}
+		return amount
+	}
+
+	createReply := &AssetIDChangeAddr{}
+	assert.NoError(s.CreateEd25519Asset(nil, &CreateEd25519AssetArgs{
+		JSONSpendHeader: spendHeader,
+		Name:            "ed25519 asset",
+		Symbol:          "EDA",
+		InitialHolders: []*Holder{{
+			Amount:  1000,
+			Address: holderAddrStr,
+		}},
+	}, createReply))
+	assetID := createReply.AssetID
+	accept(assetID)
+	assert.Equal(uint64(1000), balance(assetID, holderKey.PublicKey().Address()))
+
+	// The units are sent with the ed25519 key, and the fee is paid with the
+	// secp256k1 key
+	sendReply := &api.JSONTxIDChangeAddr{}
+	assert.NoError(s.SendEd25519(nil, &SendArgs{
+		JSONSpendHeader: spendHeader,
+		SendOutput: SendOutput{
+			Amount:  300,
+			AssetID: assetID.String(),
+			To:      recipientStr,
+		},
+	}, sendReply))
+	accept(sendReply.TxID)
+	assert.Equal(feeAddrStr, sendReply.ChangeAddr)
+	assert.Equal(uint64(700), balance(assetID, holderKey.PublicKey().Address()))
+	assert.Equal(uint64(300), balance(assetID, recipient))
+
+	// The user can't send more than its ed25519 keys hold
+	assert.Error(s.SendEd25519(nil, &SendArgs{
+		JSONSpendHeader: spendHeader,
+		SendOutput: SendOutput{
+			Amount:  701,
+			AssetID: assetID.String(),
+			To:      recipientStr,
+		},
+	}, &api.JSONTxIDChangeAddr{}))
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var _ common.FeeEstimator = &VM{}
+
+// EstimateFees implements the common.FeeEstimator interface. The fees of the
+// AVM are static, so they don't depend on the size of the tx.
+func (vm *VM) EstimateFees(txType string, _ uint64) (common.FeeEstimate, error) {
+	fees := map[string]uint64{
+		"base":        vm.TxFee,
+		"createAsset": vm.CreateAssetTxFee,
+		"operation":   vm.TxFee,
+		"import":      vm.TxFee,
+		"export":      vm.TxFee,
+	}
+	if txType != "" {
+		fee, ok := fees[txType]
+		if !ok {
+			return common.FeeEstimate{}, fmt.Errorf("%w: %q", common.ErrUnknownTxType, txType)
+		}
+		fees = map[string]uint64{txType: fee}
+	}
+	return common.FeeEstimate{
+		FeeAssetID: vm.feeAssetID,
+		Fees:       fees,
+	}, nil
+}
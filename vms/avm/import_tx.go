@@ -149,9 +149,15 @@ func (t *ImportTx) SemanticVerify(vm *VM, tx UnsignedTx, creds []verify.Verifiab
 // ExecuteWithSideEffects writes the batch with any additional side effects
 func (t *ImportTx) ExecuteWithSideEffects(vm *VM, batch database.Batch) error {
 	utxoIDs := make([][]byte, len(t.ImportedIns))
+	importedUTXOIDs := make([]*avax.UTXOID, len(t.ImportedIns))
 	for i, in := range t.ImportedIns {
 		inputID := in.UTXOID.InputID()
 		utxoIDs[i] = inputID[:]
+		importedUTXOIDs[i] = &in.UTXOID
 	}
-	return vm.ctx.SharedMemory.Apply(map[ids.ID]*atomic.Requests{t.SourceChain: {RemoveRequests: utxoIDs}}, batch)
+	indexBatch, err := vm.importIndex.Index(t.SourceChain, t.ID(), importedUTXOIDs)
+	if err != nil {
+		return err
+	}
+	return vm.ctx.SharedMemory.Apply(map[ids.ID]*atomic.Requests{t.SourceChain: {RemoveRequests: utxoIDs}}, batch, indexBatch)
 }
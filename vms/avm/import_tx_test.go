@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
@@ -454,6 +455,20 @@ func TestIssueImportTx(t *testing.T) {
 	if _, err := vm.ctx.SharedMemory.Get(platformID, [][]byte{id[:]}); err == nil {
 		t.Fatalf("shouldn't have been able to read the utxo")
 	}
+
+	// The import of the exported output is reported by the API
+	s := &Service{vm: vm}
+	reply := api.GetImportStatusReply{}
+	err = s.GetImportStatus(nil, &api.GetImportStatusArgs{
+		SourceChain: "P",
+		ExportTxID:  utxoID.TxID,
+	}, &reply)
+	assert.NoError(t, err)
+	assert.True(t, reply.Imported)
+	assert.Equal(t, []api.ImportedOutput{{
+		OutputIndex: 0,
+		ImportTxID:  parsedTx.ID(),
+	}}, reply.Imports)
 }
 
 // Test force accepting an import transaction.
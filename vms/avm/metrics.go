@@ -12,6 +12,7 @@ import (
 
 type metrics struct {
 	numTxRefreshes, numTxRefreshHits, numTxRefreshMisses prometheus.Counter
+	numOrphans                                           prometheus.Gauge
 
 	apiRequestMetric metric.APIInterceptor
 }
@@ -35,6 +36,11 @@ func (m *metrics) Initialize(
 		Name:      "tx_refresh_misses",
 		Help:      "Number of times unique txs have not been unique and weren't cached",
 	})
+	m.numOrphans = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "orphan_txs",
+		Help:      "Number of txs waiting for the txs they depend on to arrive",
+	})
 
 	apiRequestMetric, err := metric.NewAPIInterceptor(namespace, registerer)
 	m.apiRequestMetric = apiRequestMetric
@@ -44,6 +50,7 @@ func (m *metrics) Initialize(
 		registerer.Register(m.numTxRefreshes),
 		registerer.Register(m.numTxRefreshHits),
 		registerer.Register(m.numTxRefreshMisses),
+		registerer.Register(m.numOrphans),
 	)
 	return errs.Err
 }
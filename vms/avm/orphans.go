@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+)
+
+// maxOrphans is the maximum number of orphans held by the VM. Once reached,
+// the oldest orphan is dropped to make room for a new one.
+const maxOrphans = 1024
+
+// orphan is a tx that can't be verified until the txs it depends on are known
+type orphan struct {
+	tx *UniqueTx
+	// IDs of the txs [tx] depends on that aren't known yet
+	missing ids.Set
+}
+
+// orphanPool holds the orphans of the VM, so that they are issued once the
+// txs they depend on arrive, rather than dropped.
+type orphanPool struct {
+	// Maps the ID of an orphan to the orphan, in the order they were added
+	orphans linkedhashmap.LinkedHashmap
+	// Maps the ID of a missing tx to the IDs of the orphans that depend on it
+	dependents map[ids.ID]ids.Set
+}
+
+func newOrphanPool() *orphanPool {
+	return &orphanPool{
+		orphans:    linkedhashmap.New(),
+		dependents: make(map[ids.ID]ids.Set),
+	}
+}
+
+func (p *orphanPool) Len() int { return p.orphans.Len() }
+
+func (p *orphanPool) Has(txID ids.ID) bool {
+	_, has := p.orphans.Get(txID)
+	return has
+}
+
+// Add [tx] as an orphan waiting for the txs in [missing]. If the pool is full,
+// the oldest orphan is dropped.
+func (p *orphanPool) Add(tx *UniqueTx, missing ids.Set) {
+	txID := tx.ID()
+	p.Remove(txID)
+	if p.orphans.Len() >= maxOrphans {
+		if oldestID, _, ok := p.orphans.Oldest(); ok {
+			p.Remove(oldestID.(ids.ID))
+		}
+	}
+
+	p.orphans.Put(txID, &orphan{
+		tx:      tx,
+		missing: missing,
+	})
+	for missingID := range missing {
+		dependents := p.dependents[missingID]
+		dependents.Add(txID)
+		p.dependents[missingID] = dependents
+	}
+}
+
+// Remove the orphan [txID], if it is in the pool
+func (p *orphanPool) Remove(txID ids.ID) {
+	orphanIntf, ok := p.orphans.Get(txID)
+	if !ok {
+		return
+	}
+	p.orphans.Delete(txID)
+
+	for missingID := range orphanIntf.(*orphan).missing {
+		dependents := p.dependents[missingID]
+		dependents.Remove(txID)
+		if dependents.Len() == 0 {
+			delete(p.dependents, missingID)
+		} else {
+			p.dependents[missingID] = dependents
+		}
+	}
+}
+
+// Resolve marks [txID] as known and returns, after removing them from the
+// pool, the orphans that no longer wait for any tx.
+func (p *orphanPool) Resolve(txID ids.ID) []*UniqueTx {
+	dependents, ok := p.dependents[txID]
+	if !ok {
+		return nil
+	}
+	delete(p.dependents, txID)
+
+	var resolved []*UniqueTx
+	for orphanID := range dependents {
+		orphanIntf, ok := p.orphans.Get(orphanID)
+		if !ok {
+			continue
+		}
+		orphan := orphanIntf.(*orphan)
+		orphan.missing.Remove(txID)
+		if orphan.missing.Len() != 0 {
+			continue
+		}
+		p.orphans.Delete(orphanID)
+		resolved = append(resolved, orphan.tx)
+	}
+	return resolved
+}
+
+// missingDependencies returns the IDs of the txs [tx] depends on that aren't
+// known yet, or are orphans themselves.
+func (vm *VM) missingDependencies(tx *UniqueTx) (ids.Set, error) {
+	deps, err := tx.Dependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	missing := ids.Set{}
+	for _, dep := range deps {
+		depID := dep.ID()
+		if dep.Status() == choices.Unknown || vm.orphans.Has(depID) {
+			missing.Add(depID)
+		}
+	}
+	return missing, nil
+}
+
+// issueOrphans issues the orphans that were only waiting for [txID], which
+// just became known.
+func (vm *VM) issueOrphans(txID ids.ID) {
+	for _, tx := range vm.orphans.Resolve(txID) {
+		orphanID := tx.ID()
+		if err := tx.verifyWithoutCacheWrites(); err != nil {
+			missing, depsErr := vm.missingDependencies(tx)
+			if depsErr == nil && missing.Len() != 0 {
+				vm.orphans.Add(tx, missing)
+				continue
+			}
+			vm.ctx.Log.Debug("dropping orphan tx %s due to: %s", orphanID, err)
+			continue
+		}
+
+		vm.ctx.Log.Debug("issuing orphan tx %s as its dependencies arrived", orphanID)
+		vm.issueTx(tx)
+		vm.issueOrphans(orphanID)
+	}
+	vm.numOrphans.Set(float64(vm.orphans.Len()))
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// newChildTx returns a tx that spends the first output of [parentTx]
+func newChildTx(t *testing.T, vm *VM, parentTx *Tx, amount uint64) *Tx {
+	assetID := parentTx.UnsignedTx.(*BaseTx).Outs[0].AssetID()
+	key := keys[0]
+	tx := &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    networkID,
+		BlockchainID: chainID,
+		Ins: []*avax.TransferableInput{{
+			UTXOID: avax.UTXOID{
+				TxID:        parentTx.ID(),
+				OutputIndex: 0,
+			},
+			Asset: avax.Asset{ID: assetID},
+			In: &secp256k1fx.TransferInput{
+				Amt: amount,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{
+						0,
+					},
+				},
+			},
+		}},
+		Outs: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount - vm.TxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{key.PublicKey().Address()},
+				},
+			},
+		}},
+	}}}
+	if err := tx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestOrphanPool(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := newOrphanPool()
+
+	missingID := ids.GenerateTestID()
+	otherMissingID := ids.GenerateTestID()
+	orphan := &UniqueTx{txID: ids.GenerateTestID()}
+	pool.Add(orphan, ids.Set{missingID: struct{}{}, otherMissingID: struct{}{}})
+	assert.True(pool.Has(orphan.ID()))
+	assert.Equal(1, pool.Len())
+
+	// The orphan waits for all the txs it depends on
+	assert.Empty(pool.Resolve(missingID))
+	assert.True(pool.Has(orphan.ID()))
+	assert.Equal([]*UniqueTx{orphan}, pool.Resolve(otherMissingID))
+	assert.False(pool.Has(orphan.ID()))
+	assert.Zero(pool.Len())
+	assert.Empty(pool.dependents)
+
+	// Once full, the oldest orphan is dropped
+	firstOrphan := &UniqueTx{txID: ids.GenerateTestID()}
+	pool.Add(firstOrphan, ids.Set{missingID: struct{}{}})
+	for i := 1; i < maxOrphans; i++ {
+		pool.Add(&UniqueTx{txID: ids.GenerateTestID()}, ids.Set{missingID: struct{}{}})
+	}
+	assert.Equal(maxOrphans, pool.Len())
+	assert.True(pool.Has(firstOrphan.ID()))
+
+	pool.Add(orphan, ids.Set{otherMissingID: struct{}{}})
+	assert.Equal(maxOrphans, pool.Len())
+	assert.False(pool.Has(firstOrphan.ID()))
+	assert.True(pool.Has(orphan.ID()))
+	assert.Len(pool.Resolve(missingID), maxOrphans-1)
+}
+
+// Test that a tx spending the outputs of an unknown tx is held, and issued
+// after the tx it depends on is issued
+func TestIssueOrphanTx(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	parentTx := txs[1]
+	childTx := newChildTx(t, vm, parentTx, startBalance-vm.TxFee)
+	grandchildTx := newChildTx(t, vm, childTx, startBalance-2*vm.TxFee)
+
+	// The orphans aren't dropped, but aren't issued either
+	grandchildID, err := vm.IssueTx(grandchildTx.Bytes())
+	assert.NoError(err)
+	assert.Equal(grandchildTx.ID(), grandchildID)
+	childID, err := vm.IssueTx(childTx.Bytes())
+	assert.NoError(err)
+	assert.Equal(childTx.ID(), childID)
+	assert.Empty(vm.txs)
+	assert.Equal(2, vm.orphans.Len())
+
+	// Once the parent is issued, the orphans are issued after it
+	_, err = vm.IssueTx(parentTx.Bytes())
+	assert.NoError(err)
+	assert.Zero(vm.orphans.Len())
+
+	pendingTxs := vm.PendingTxs()
+	assert.Len(pendingTxs, 3)
+	assert.Equal(parentTx.ID(), pendingTxs[0].ID())
+	assert.Equal(childTx.ID(), pendingTxs[1].ID())
+	assert.Equal(grandchildTx.ID(), pendingTxs[2].ID())
+}
+
+// Test that orphans are issued once the tx they depend on is parsed by the
+// engine
+func TestParseTxIssuesOrphans(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	parentTx := txs[1]
+	childTx := newChildTx(t, vm, parentTx, startBalance-vm.TxFee)
+
+	_, err := vm.IssueTx(childTx.Bytes())
+	assert.NoError(err)
+	assert.True(vm.orphans.Has(childTx.ID()))
+
+	_, err = vm.ParseTx(parentTx.Bytes())
+	assert.NoError(err)
+	assert.False(vm.orphans.Has(childTx.ID()))
+
+	pendingTxs := vm.PendingTxs()
+	assert.Len(pendingTxs, 1)
+	assert.Equal(childTx.ID(), pendingTxs[0].ID())
+}
+
+// Test that txs that are invalid for other reasons than missing dependencies
+// are still dropped
+func TestIssueInvalidTxIsNotOrphan(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	// The parent is known, but the child spends more than the parent's output
+	parentTx := txs[1]
+	_, err := vm.IssueTx(parentTx.Bytes())
+	assert.NoError(err)
+	childTx := newChildTx(t, vm, parentTx, startBalance)
+
+	_, err = vm.IssueTx(childTx.Bytes())
+	assert.Error(err)
+	assert.Zero(vm.orphans.Len())
+}
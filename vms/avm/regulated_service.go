@@ -0,0 +1,559 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/regulatedfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// Max number of outputs that a single Freeze, Unfreeze or Clawback request
+// operates on. Further requests operate on the remaining outputs.
+const maxRegulatedOutputs = 256
+
+var (
+	errNoRoles         = errors.New("user doesn't hold a role of the asset")
+	errRoleDisabled    = errors.New("role is disabled")
+	errCantPerformRole = errors.New("user's keys don't satisfy the role")
+	errNothingToDo     = errors.New("address has no outputs of the asset to operate on")
+)
+
+// RoleOwners are the addresses that hold a role of a regulated asset, and the
+// number of them that must sign to perform it. A role without addresses is
+// disabled.
+type RoleOwners struct {
+	Threshold json.Uint32 `json:"threshold"`
+	Addresses []string    `json:"addresses"`
+}
+
+// APIRoles are the roles of a regulated asset
+type APIRoles struct {
+	// Admin may replace the roles
+	Admin RoleOwners `json:"admin"`
+	// Freezer may freeze and unfreeze the outputs of the asset
+	Freezer RoleOwners `json:"freezer"`
+	// Clawback may move any output of the asset to new owners
+	Clawback RoleOwners `json:"clawback"`
+}
+
+// CreateRegulatedAssetArgs are arguments for passing into CreateRegulatedAsset
+// requests
+type CreateRegulatedAssetArgs struct {
+	api.JSONSpendHeader           // User, password, from addrs, change addr
+	Name                string    `json:"name"`
+	Symbol              string    `json:"symbol"`
+	Denomination        byte      `json:"denomination"`
+	InitialHolders      []*Holder `json:"initialHolders"`
+	Roles               APIRoles  `json:"roles"`
+}
+
+// CreateRegulatedAsset returns ID of the newly created regulated asset. The
+// chain must support the regulated fx.
+func (service *Service) CreateRegulatedAsset(r *http.Request, args *CreateRegulatedAssetArgs, reply *AssetIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: CreateRegulatedAsset called with name: %s symbol: %s number of holders: %d",
+		args.Name,
+		args.Symbol,
+		len(args.InitialHolders),
+	)
+
+	fxIndex, err := service.vm.getFx(&regulatedfx.RolesOutput{})
+	if err != nil {
+		return fmt.Errorf("chain doesn't support regulated assets: %w", err)
+	}
+
+	roles, err := service.parseRoles(&args.Roles)
+	if err != nil {
+		return err
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	// Get the UTXOs/keys for the from addresses
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+
+	// Parse the change address.
+	if len(kc.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	if err != nil {
+		return err
+	}
+
+	amountsSpent, ins, keys, err := service.vm.Spend(
+		utxos,
+		kc,
+		map[ids.ID]uint64{
+			service.vm.feeAssetID: service.vm.CreateAssetTxFee,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	outs := []*avax.TransferableOutput{}
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.CreateAssetTxFee {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: service.vm.feeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - service.vm.CreateAssetTxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+
+	initialState := &InitialState{
+		FxIndex: uint32(fxIndex),
+		Outs:    make([]verify.State, 0, len(args.InitialHolders)+1),
+	}
+	for _, holder := range args.InitialHolders {
+		addr, err := service.vm.ParseLocalAddress(holder.Address)
+		if err != nil {
+			return err
+		}
+		initialState.Outs = append(initialState.Outs, &regulatedfx.TransferOutput{
+			TransferOutput: secp256k1fx.TransferOutput{
+				Amt: uint64(holder.Amount),
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addr},
+				},
+			},
+		})
+	}
+	initialState.Outs = append(initialState.Outs, roles)
+	initialState.Sort(service.vm.codec)
+
+	tx := Tx{UnsignedTx: &CreateAssetTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		Name:         args.Name,
+		Symbol:       args.Symbol,
+		Denomination: args.Denomination,
+		States:       []*InitialState{initialState},
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+
+	assetID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.AssetID = assetID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// SetAssetRolesArgs are arguments for passing into SetAssetRoles requests
+type SetAssetRolesArgs struct {
+	api.JSONSpendHeader          // User, password, from addrs, change addr
+	AssetID             string   `json:"assetID"`
+	Roles               APIRoles `json:"roles"`
+}
+
+// SetAssetRoles replaces the roles of a regulated asset. The user must hold
+// the admin role of the asset.
+func (service *Service) SetAssetRoles(r *http.Request, args *SetAssetRolesArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: SetAssetRoles called with username: %s", args.Username)
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	newRoles, err := service.parseRoles(&args.Roles)
+	if err != nil {
+		return err
+	}
+
+	txID, changeAddr, err := service.issueRegulatedOperation(
+		&args.JSONSpendHeader,
+		assetID,
+		nil,
+		func(roles *regulatedfx.RolesOutput) *secp256k1fx.OutputOwners { return &roles.Admin },
+		func(in secp256k1fx.Input, _ *regulatedfx.RolesOutput, _ []*avax.UTXO) FxOperation {
+			return &regulatedfx.SetRolesOperation{
+				Input: in,
+				Roles: *newRoles,
+			}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	reply.TxID = txID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// FreezeArgs are arguments for passing into Freeze and Unfreeze requests
+type FreezeArgs struct {
+	api.JSONSpendHeader        // User, password, from addrs, change addr
+	AssetID             string `json:"assetID"`
+	// Address whose outputs of the asset are frozen or unfrozen
+	Address string `json:"address"`
+}
+
+// Freeze freezes the outputs of a regulated asset owned by an address, so that
+// they can't be spent until they're unfrozen. The user must hold the freezer
+// role of the asset.
+func (service *Service) Freeze(r *http.Request, args *FreezeArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: Freeze called with username: %s", args.Username)
+
+	return service.freeze(args, reply, func(in secp256k1fx.Input, roles *regulatedfx.RolesOutput, utxos []*avax.UTXO) FxOperation {
+		op := &regulatedfx.FreezeOperation{
+			Input:   in,
+			Roles:   *roles,
+			Outputs: make([]*regulatedfx.FrozenOutput, len(utxos)),
+		}
+		for i, utxo := range utxos {
+			out := utxo.Out.(*regulatedfx.TransferOutput)
+			op.Outputs[i] = &regulatedfx.FrozenOutput{TransferOutput: out.TransferOutput}
+		}
+		return op
+	}, func(out verify.State) bool {
+		_, ok := out.(*regulatedfx.TransferOutput)
+		return ok
+	})
+}
+
+// Unfreeze unfreezes the frozen outputs of a regulated asset owned by an
+// address. The user must hold the freezer role of the asset.
+func (service *Service) Unfreeze(r *http.Request, args *FreezeArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: Unfreeze called with username: %s", args.Username)
+
+	return service.freeze(args, reply, func(in secp256k1fx.Input, roles *regulatedfx.RolesOutput, utxos []*avax.UTXO) FxOperation {
+		op := &regulatedfx.UnfreezeOperation{
+			Input:   in,
+			Roles:   *roles,
+			Outputs: make([]*regulatedfx.TransferOutput, len(utxos)),
+		}
+		for i, utxo := range utxos {
+			out := utxo.Out.(*regulatedfx.FrozenOutput)
+			op.Outputs[i] = &regulatedfx.TransferOutput{TransferOutput: out.TransferOutput}
+		}
+		return op
+	}, func(out verify.State) bool {
+		_, ok := out.(*regulatedfx.FrozenOutput)
+		return ok
+	})
+}
+
+func (service *Service) freeze(
+	args *FreezeArgs,
+	reply *api.JSONTxIDChangeAddr,
+	newOp func(secp256k1fx.Input, *regulatedfx.RolesOutput, []*avax.UTXO) FxOperation,
+	isTarget func(verify.State) bool,
+) error {
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	targets, err := service.regulatedUTXOs(assetID, args.Address, isTarget)
+	if err != nil {
+		return err
+	}
+
+	txID, changeAddr, err := service.issueRegulatedOperation(
+		&args.JSONSpendHeader,
+		assetID,
+		targets,
+		func(roles *regulatedfx.RolesOutput) *secp256k1fx.OutputOwners { return &roles.Freezer },
+		newOp,
+	)
+	if err != nil {
+		return err
+	}
+
+	reply.TxID = txID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// ClawbackArgs are arguments for passing into Clawback requests
+type ClawbackArgs struct {
+	api.JSONSpendHeader        // User, password, from addrs, change addr
+	AssetID             string `json:"assetID"`
+	// Address whose outputs of the asset, frozen or not, are clawed back
+	Address string `json:"address"`
+	// To receives the amount clawed back
+	To string `json:"to"`
+}
+
+// Clawback moves the outputs of a regulated asset owned by an address to a new
+// owner, without the signatures of their owners. The user must hold the
+// clawback role of the asset.
+func (service *Service) Clawback(r *http.Request, args *ClawbackArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: Clawback called with username: %s", args.Username)
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	to, err := service.vm.ParseLocalAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address %q: %w", args.To, err)
+	}
+	targets, err := service.regulatedUTXOs(assetID, args.Address, func(out verify.State) bool {
+		switch out.(type) {
+		case *regulatedfx.TransferOutput, *regulatedfx.FrozenOutput:
+			return true
+		default:
+			return false
+		}
+	})
+	if err != nil {
+		return err
+	}
+	amount := uint64(0)
+	for _, utxo := range targets {
+		amount, err = safemath.Add64(amount, utxo.Out.(avax.Amounter).Amount())
+		if err != nil {
+			return err
+		}
+	}
+
+	txID, changeAddr, err := service.issueRegulatedOperation(
+		&args.JSONSpendHeader,
+		assetID,
+		targets,
+		func(roles *regulatedfx.RolesOutput) *secp256k1fx.OutputOwners { return &roles.Clawback },
+		func(in secp256k1fx.Input, roles *regulatedfx.RolesOutput, _ []*avax.UTXO) FxOperation {
+			return &regulatedfx.ClawbackOperation{
+				Input: in,
+				Roles: *roles,
+				Output: regulatedfx.TransferOutput{
+					TransferOutput: secp256k1fx.TransferOutput{
+						Amt: amount,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{to},
+						},
+					},
+				},
+			}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	reply.TxID = txID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// parseRoles returns the roles output defined by [apiRoles]
+func (service *Service) parseRoles(apiRoles *APIRoles) (*regulatedfx.RolesOutput, error) {
+	roles := &regulatedfx.RolesOutput{}
+	for _, role := range []struct {
+		apiOwners *RoleOwners
+		owners    *secp256k1fx.OutputOwners
+	}{
+		{&apiRoles.Admin, &roles.Admin},
+		{&apiRoles.Freezer, &roles.Freezer},
+		{&apiRoles.Clawback, &roles.Clawback},
+	} {
+		role.owners.Threshold = uint32(role.apiOwners.Threshold)
+		for _, addrStr := range role.apiOwners.Addresses {
+			addr, err := service.vm.ParseLocalAddress(addrStr)
+			if err != nil {
+				return nil, err
+			}
+			role.owners.Addrs = append(role.owners.Addrs, addr)
+		}
+		role.owners.Sort()
+	}
+	return roles, roles.Verify()
+}
+
+// regulatedUTXOs returns up to [maxRegulatedOutputs] UTXOs of [assetID] that
+// reference [addrStr] and satisfy [isTarget], sorted by UTXO ID
+func (service *Service) regulatedUTXOs(assetID ids.ID, addrStr string, isTarget func(verify.State) bool) ([]*avax.UTXO, error) {
+	addr, err := service.vm.ParseLocalAddress(addrStr)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing address %q: %w", addrStr, err)
+	}
+	addrs := ids.ShortSet{}
+	addrs.Add(addr)
+	utxos, err := avax.GetAllUTXOs(service.vm.state, addrs)
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+
+	targets := make(map[ids.ID]*avax.UTXO)
+	utxoIDs := []*avax.UTXOID{}
+	for _, utxo := range utxos {
+		if len(targets) == maxRegulatedOutputs {
+			break
+		}
+		if utxo.AssetID() != assetID || !isTarget(utxo.Out) {
+			continue
+		}
+		targets[utxo.InputID()] = utxo
+		utxoIDs = append(utxoIDs, &utxo.UTXOID)
+	}
+	if len(targets) == 0 {
+		return nil, errNothingToDo
+	}
+
+	// The outputs of the operations are in the order of the UTXOs they consume
+	avax.SortUTXOIDs(utxoIDs)
+	sortedTargets := make([]*avax.UTXO, len(utxoIDs))
+	for i, utxoID := range utxoIDs {
+		sortedTargets[i] = targets[utxoID.InputID()]
+	}
+	return sortedTargets, nil
+}
+
+// issueRegulatedOperation issues a transaction with the operation returned by
+// [newOp]. The operation consumes the roles of [assetID] and [targets], and is
+// signed by the keys of the user that satisfy the role returned by [role].
+func (service *Service) issueRegulatedOperation(
+	header *api.JSONSpendHeader,
+	assetID ids.ID,
+	targets []*avax.UTXO,
+	role func(*regulatedfx.RolesOutput) *secp256k1fx.OutputOwners,
+	newOp func(secp256k1fx.Input, *regulatedfx.RolesOutput, []*avax.UTXO) FxOperation,
+) (ids.ID, ids.ShortID, error) {
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, header.From)
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, err
+	}
+
+	// Get the UTXOs/keys for the from addresses
+	feeUTXOs, feeKc, err := service.vm.LoadUser(header.Username, header.Password, fromAddrs)
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, err
+	}
+
+	// Parse the change address.
+	if len(feeKc.Keys) == 0 {
+		return ids.ID{}, ids.ShortID{}, errNoKeys
+	}
+	changeAddr, err := service.vm.selectChangeAddr(feeKc.Keys[0].PublicKey().Address(), header.ChangeAddr)
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, err
+	}
+
+	amountsSpent, ins, keys, err := service.vm.Spend(
+		feeUTXOs,
+		feeKc,
+		map[ids.ID]uint64{
+			service.vm.feeAssetID: service.vm.TxFee,
+		},
+	)
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, err
+	}
+
+	outs := []*avax.TransferableOutput{}
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.TxFee {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: service.vm.feeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - service.vm.TxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+
+	// Get all UTXOs/keys for the user. The roles of the asset are indexed by
+	// the addresses of all of its roles.
+	utxos, kc, err := service.vm.LoadUser(header.Username, header.Password, nil)
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, err
+	}
+	var (
+		rolesUTXO *avax.UTXO
+		roles     *regulatedfx.RolesOutput
+	)
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*regulatedfx.RolesOutput)
+		if ok && utxo.AssetID() == assetID {
+			rolesUTXO = utxo
+			roles = out
+			break
+		}
+	}
+	if roles == nil {
+		return ids.ID{}, ids.ShortID{}, errNoRoles
+	}
+
+	owners := role(roles)
+	if len(owners.Addrs) == 0 {
+		return ids.ID{}, ids.ShortID{}, errRoleDisabled
+	}
+	indices, signers, ok := kc.Match(owners, service.vm.clock.Unix())
+	if !ok {
+		return ids.ID{}, ids.ShortID{}, errCantPerformRole
+	}
+
+	utxoIDs := make([]*avax.UTXOID, 0, len(targets)+1)
+	utxoIDs = append(utxoIDs, &rolesUTXO.UTXOID)
+	for _, utxo := range targets {
+		utxoIDs = append(utxoIDs, &utxo.UTXOID)
+	}
+	avax.SortUTXOIDs(utxoIDs)
+
+	tx := Tx{UnsignedTx: &OperationTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		Ops: []*Operation{{
+			Asset:   avax.Asset{ID: assetID},
+			UTXOIDs: utxoIDs,
+			Op:      newOp(secp256k1fx.Input{SigIndices: indices}, roles, targets),
+		}},
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return ids.ID{}, ids.ShortID{}, err
+	}
+	if err := tx.SignRegulatedFx(service.vm.codec, [][]*crypto.PrivateKeySECP256K1R{signers}); err != nil {
+		return ids.ID{}, ids.ShortID{}, err
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, fmt.Errorf("problem issuing transaction: %w", err)
+	}
+	return txID, changeAddr, nil
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/keystore"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/regulatedfx"
+)
+
+func TestRegulatedAsset(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, vm, _ := GenesisVMWithArgs(t, []*common.Fx{{
+		ID: regulatedfx.ID,
+		Fx: &regulatedfx.Fx{},
+	}}, nil)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+
+	user, err := keystore.NewUserFromKeystore(vm.ctx.Keystore, username, password)
+	assert.NoError(err)
+	assert.NoError(user.PutKeys(keys...))
+	assert.NoError(user.Close())
+
+	regulatorAddr := keys[0].PublicKey().Address()
+	regulatorAddrStr, err := vm.FormatLocalAddress(regulatorAddr)
+	assert.NoError(err)
+	holderAddr := keys[1].PublicKey().Address()
+	holderAddrStr, err := vm.FormatLocalAddress(holderAddr)
+	assert.NoError(err)
+	spendHeader := api.JSONSpendHeader{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: regulatorAddrStr},
+	}
+	regulator := RoleOwners{
+		Threshold: 1,
+		Addresses: []string{regulatorAddrStr},
+	}
+	accept := func(txID ids.ID) {
+		tx := UniqueTx{vm: vm, txID: txID}
+		assert.NoError(tx.Accept())
+	}
+	// outputs returns the outputs of the asset owned by [addr]
+	outputs := func(assetID ids.ID, addr ids.ShortID) []verify.State {
+		addrs := ids.ShortSet{}
+		addrs.Add(addr)
+		utxos, err := avax.GetAllUTXOs(vm.state, addrs)
+		assert.NoError(err)
+		outs := []verify.State{}
+		for _, utxo := range utxos {
+			if utxo.AssetID() == assetID {
+				outs = append(outs, utxo.Out)
+			}
+		}
+		return outs
+	}
+
+	createReply := &AssetIDChangeAddr{}
+	assert.NoError(s.CreateRegulatedAsset(nil, &CreateRegulatedAssetArgs{
+		JSONSpendHeader: spendHeader,
+		Name:            "regulated asset",
+		Symbol:          "REG",
+		InitialHolders: []*Holder{{
+			Amount:  1000,
+			Address: holderAddrStr,
+		}},
+		Roles: APIRoles{
+			Admin:    regulator,
+			Freezer:  regulator,
+			Clawback: regulator,
+		},
+	}, createReply))
+	assetID := createReply.AssetID
+	accept(assetID)
+
+	holderOuts := outputs(assetID, holderAddr)
+	assert.Len(holderOuts, 1)
+	assert.IsType(&regulatedfx.TransferOutput{}, holderOuts[0])
+
+	// Freeze the outputs of the holder
+	freezeArgs := &FreezeArgs{
+		JSONSpendHeader: spendHeader,
+		AssetID:         assetID.String(),
+		Address:         holderAddrStr,
+	}
+	reply := &api.JSONTxIDChangeAddr{}
+	assert.NoError(s.Freeze(nil, freezeArgs, reply))
+	accept(reply.TxID)
+
+	holderOuts = outputs(assetID, holderAddr)
+	assert.Len(holderOuts, 1)
+	assert.IsType(&regulatedfx.FrozenOutput{}, holderOuts[0])
+	assert.ErrorIs(s.Freeze(nil, freezeArgs, &api.JSONTxIDChangeAddr{}), errNothingToDo)
+
+	// Unfreeze them
+	reply = &api.JSONTxIDChangeAddr{}
+	assert.NoError(s.Unfreeze(nil, freezeArgs, reply))
+	accept(reply.TxID)
+
+	holderOuts = outputs(assetID, holderAddr)
+	assert.Len(holderOuts, 1)
+	assert.IsType(&regulatedfx.TransferOutput{}, holderOuts[0])
+
+	// Claw them back
+	clawbackArgs := &ClawbackArgs{
+		JSONSpendHeader: spendHeader,
+		AssetID:         assetID.String(),
+		Address:         holderAddrStr,
+		To:              regulatorAddrStr,
+	}
+	reply = &api.JSONTxIDChangeAddr{}
+	assert.NoError(s.Clawback(nil, clawbackArgs, reply))
+	accept(reply.TxID)
+
+	assert.Empty(outputs(assetID, holderAddr))
+	clawedBack := uint64(0)
+	for _, out := range outputs(assetID, regulatorAddr) {
+		if out, ok := out.(*regulatedfx.TransferOutput); ok {
+			clawedBack += out.Amt
+		}
+	}
+	assert.Equal(uint64(1000), clawedBack)
+
+	// Give up the clawback role, and give the freezer role to someone else
+	otherAddrStr, err := vm.FormatLocalAddress(ids.GenerateTestShortID())
+	assert.NoError(err)
+	reply = &api.JSONTxIDChangeAddr{}
+	assert.NoError(s.SetAssetRoles(nil, &SetAssetRolesArgs{
+		JSONSpendHeader: spendHeader,
+		AssetID:         assetID.String(),
+		Roles: APIRoles{
+			Admin: regulator,
+			Freezer: RoleOwners{
+				Threshold: 1,
+				Addresses: []string{otherAddrStr},
+			},
+		},
+	}, reply))
+	accept(reply.TxID)
+
+	clawbackArgs.Address = regulatorAddrStr
+	assert.ErrorIs(s.Clawback(nil, clawbackArgs, &api.JSONTxIDChangeAddr{}), errRoleDisabled)
+
+	// Only the holders of a role can perform it
+	freezeArgs.Address = regulatorAddrStr
+	assert.ErrorIs(s.Freeze(nil, freezeArgs, &api.JSONTxIDChangeAddr{}), errCantPerformRole)
+}
@@ -32,22 +32,33 @@ const (
 
 	// Max number of items allowed in a page
 	maxPageSize uint64 = 1024
+
+	// Max number of operations that can be passed in as argument to
+	// BatchOperations
+	maxBatchedOperations = 256
+
+	mintOperationType    = "mint"
+	mintNFTOperationType = "mintNFT"
+	sendNFTOperationType = "sendNFT"
 )
 
 var (
-	errUnknownAssetID         = errors.New("unknown asset ID")
-	errTxNotCreateAsset       = errors.New("transaction doesn't create an asset")
-	errNoMinters              = errors.New("no minters provided")
-	errNoHoldersOrMinters     = errors.New("no minters or initialHolders provided")
-	errZeroAmount             = errors.New("amount must be positive")
-	errNoOutputs              = errors.New("no outputs to send")
-	errSpendOverflow          = errors.New("spent amount overflows uint64")
-	errInvalidMintAmount      = errors.New("amount minted must be positive")
-	errAddressesCantMintAsset = errors.New("provided addresses don't have the authority to mint the provided asset")
-	errInvalidUTXO            = errors.New("invalid utxo")
-	errNilTxID                = errors.New("nil transaction ID")
-	errNoAddresses            = errors.New("no addresses provided")
-	errNoKeys                 = errors.New("from addresses have no keys or funds")
+	errUnknownAssetID           = errors.New("unknown asset ID")
+	errTxNotCreateAsset         = errors.New("transaction doesn't create an asset")
+	errNoMinters                = errors.New("no minters provided")
+	errNoHoldersOrMinters       = errors.New("no minters or initialHolders provided")
+	errZeroAmount               = errors.New("amount must be positive")
+	errNoOutputs                = errors.New("no outputs to send")
+	errSpendOverflow            = errors.New("spent amount overflows uint64")
+	errInvalidMintAmount        = errors.New("amount minted must be positive")
+	errAddressesCantMintAsset   = errors.New("provided addresses don't have the authority to mint the provided asset")
+	errInvalidUTXO              = errors.New("invalid utxo")
+	errNilTxID                  = errors.New("nil transaction ID")
+	errNoAddresses              = errors.New("no addresses provided")
+	errNoKeys                   = errors.New("from addresses have no keys or funds")
+	errTooManyBatchedOperations = errors.New("too many operations in the batch")
+	errUnknownOperationType     = errors.New("unknown operation type")
+	errImportFromSameChain      = errors.New("source chain must be a different chain")
 )
 
 // Service defines the base service for the asset vm
@@ -75,6 +86,54 @@ func (service *Service) IssueTx(r *http.Request, args *api.FormattedTx, reply *a
 	return nil
 }
 
+// SimulateTx verifies a signed tx against the current state without issuing
+// it. Verification failures are reported in the reply rather than as errors.
+func (service *Service) SimulateTx(r *http.Request, args *api.FormattedTx, reply *api.SimulateTxReply) error {
+	service.vm.ctx.Log.Debug("AVM: SimulateTx called with %s", args.Tx)
+
+	txBytes, err := formatting.Decode(args.Encoding, args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx, err := service.vm.parsePrivateTx(txBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't parse tx: %w", err)
+	}
+
+	reply.TxID = tx.ID()
+	if err := service.vm.SimulateTx(tx); err != nil {
+		if err == errBootstrapping {
+			return err
+		}
+		reply.Reason = err.Error()
+		return nil
+	}
+	reply.Valid = true
+	return nil
+}
+
+// DecodeTx decodes a signed tx into JSON. The tx isn't verified and its inputs
+// don't need to exist.
+func (service *Service) DecodeTx(_ *http.Request, args *api.FormattedTx, reply *api.DecodeTxReply) error {
+	service.vm.ctx.Log.Debug("AVM: DecodeTx called with %s", args.Tx)
+
+	txBytes, err := formatting.Decode(args.Encoding, args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx, err := service.vm.parsePrivateTx(txBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't parse tx: %w", err)
+	}
+	if err := tx.Init(service.vm); err != nil {
+		return fmt.Errorf("couldn't initialize tx: %w", err)
+	}
+
+	reply.TxID = tx.ID()
+	reply.Tx = tx
+	return nil
+}
+
 func (service *Service) IssueStopVertex(_ *http.Request, _ *struct{}, _ *struct{}) error {
 	return service.vm.issueStopVertex()
 }
@@ -187,6 +246,35 @@ func (service *Service) GetTx(r *http.Request, args *api.GetTxArgs, reply *api.G
 	return nil
 }
 
+// GetImportStatus returns which outputs of an export tx on another chain were
+// imported into this chain, and by which txs
+func (service *Service) GetImportStatus(_ *http.Request, args *api.GetImportStatusArgs, reply *api.GetImportStatusReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetImportStatus called for %s from %s", args.ExportTxID, args.SourceChain)
+
+	sourceChain, err := service.vm.ctx.BCLookup.Lookup(args.SourceChain)
+	if err != nil {
+		return fmt.Errorf("problem parsing source chainID %q: %w", args.SourceChain, err)
+	}
+	if sourceChain == service.vm.ctx.ChainID {
+		return errImportFromSameChain
+	}
+
+	imports, err := service.vm.importIndex.GetImports(sourceChain, args.ExportTxID)
+	if err != nil {
+		return fmt.Errorf("problem retrieving imports of %s: %w", args.ExportTxID, err)
+	}
+
+	reply.Imported = len(imports) > 0
+	reply.Imports = make([]api.ImportedOutput, len(imports))
+	for i, imported := range imports {
+		reply.Imports[i] = api.ImportedOutput{
+			OutputIndex: json.Uint32(imported.OutputIndex),
+			ImportTxID:  imported.ImportTxID,
+		}
+	}
+	return nil
+}
+
 // GetUTXOs gets all utxos for passed in addresses
 func (service *Service) GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply *api.GetUTXOsReply) error {
 	service.vm.ctx.Log.Debug("AVM: GetUTXOs called for with %s", args.Addresses)
@@ -323,6 +411,34 @@ func (service *Service) GetAssetDescription(_ *http.Request, args *GetAssetDescr
 	return nil
 }
 
+// GetTxFeeReply is the response from GetTxFee
+type GetTxFeeReply struct {
+	// ID of the asset the fees are paid in
+	FeeAssetID ids.ID `json:"feeAssetID"`
+	// Fee of a transaction that doesn't create an asset
+	TxFee json.Uint64 `json:"txFee"`
+	// Fee of a transaction that creates an asset
+	CreateAssetTxFee json.Uint64 `json:"createAssetTxFee"`
+	// Address that receives the fees. Empty if the fees are burned.
+	FeeCollector string `json:"feeCollector"`
+}
+
+// GetTxFee returns the fees of the transactions issued to this chain, and the
+// asset they're paid in
+func (service *Service) GetTxFee(_ *http.Request, _ *struct{}, reply *GetTxFeeReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetTxFee called")
+
+	reply.FeeAssetID = service.vm.feeAssetID
+	reply.TxFee = json.Uint64(service.vm.TxFee)
+	reply.CreateAssetTxFee = json.Uint64(service.vm.CreateAssetTxFee)
+	if service.vm.feeCollector == ids.ShortEmpty {
+		return nil
+	}
+	var err error
+	reply.FeeCollector, err = service.vm.FormatLocalAddress(service.vm.feeCollector)
+	return err
+}
+
 // GetBalanceArgs are arguments for passing into GetBalance requests
 type GetBalanceArgs struct {
 	Address        string `json:"address"`
@@ -1425,6 +1541,232 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 	return err
 }
 
+// BatchOperation is an asset operation of a BatchOperations request
+type BatchOperation struct {
+	// Type is one of "mint", "mintNFT" and "sendNFT"
+	Type    string `json:"type"`
+	AssetID string `json:"assetID"`
+	To      string `json:"to"`
+	// Amount of the asset to mint. Only used by "mint" operations.
+	Amount json.Uint64 `json:"amount"`
+	// GroupID of the NFT to send. Only used by "sendNFT" operations.
+	GroupID json.Uint32 `json:"groupID"`
+	// Payload of the NFT to mint, encoded with Encoding. Only used by
+	// "mintNFT" operations.
+	Payload  string              `json:"payload"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// BatchOperationsArgs are arguments for passing into BatchOperations requests
+type BatchOperationsArgs struct {
+	api.JSONSpendHeader                  // User, password, from addrs, change addr
+	Operations          []BatchOperation `json:"operations"`
+}
+
+// BatchOperations issues a single transaction that performs all of
+// [Operations], paying the transaction fee once.
+//
+// The transaction is atomic: either every operation is accepted, or none is.
+// If an operation can't be created or doesn't verify, no transaction is issued
+// and the returned error reports the index of the first failing operation.
+// Each operation consumes its own UTXO, so an asset can only be minted once and
+// an NFT can only be sent once per batch.
+func (service *Service) BatchOperations(r *http.Request, args *BatchOperationsArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: BatchOperations called with username: %s", args.Username)
+
+	switch numOps := len(args.Operations); {
+	case numOps == 0:
+		return errNoOperations
+	case numOps > maxBatchedOperations:
+		return fmt.Errorf("%w: %d > %d", errTooManyBatchedOperations, numOps, maxBatchedOperations)
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	// Get the UTXOs/keys for the from addresses
+	feeUTXOs, feeKc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+
+	// Parse the change address.
+	if len(feeKc.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr, err := service.vm.selectChangeAddr(feeKc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	if err != nil {
+		return err
+	}
+
+	amountsSpent, ins, keys, err := service.vm.Spend(
+		feeUTXOs,
+		feeKc,
+		map[ids.ID]uint64{
+			service.vm.feeAssetID: service.vm.TxFee,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	outs := []*avax.TransferableOutput{}
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.TxFee {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: service.vm.feeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - service.vm.TxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+
+	// Get all UTXOs/keys for the user
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, nil)
+	if err != nil {
+		return err
+	}
+
+	ops := []*Operation{}
+	opKeys := [][]*crypto.PrivateKeySECP256K1R{}
+	// opIndices maps the created operations to the index of the requested
+	// operation, as the operations are sorted before being signed
+	opIndices := make(map[*Operation]int, len(args.Operations))
+	consumed := ids.Set{}
+	for i, batchOp := range args.Operations {
+		// Don't consume a UTXO that an earlier operation consumes
+		unconsumedUTXOs := make([]*avax.UTXO, 0, len(utxos))
+		for _, utxo := range utxos {
+			if !consumed.Contains(utxo.InputID()) {
+				unconsumedUTXOs = append(unconsumedUTXOs, utxo)
+			}
+		}
+
+		newOps, newKeys, err := service.batchOperation(batchOp, unconsumedUTXOs, kc)
+		if err != nil {
+			return fmt.Errorf("operation %d (%s) failed: %w", i, batchOp.Type, err)
+		}
+		for _, op := range newOps {
+			opIndices[op] = i
+			for _, utxoID := range op.UTXOIDs {
+				consumed.Add(utxoID.InputID())
+			}
+		}
+		ops = append(ops, newOps...)
+		opKeys = append(opKeys, newKeys...)
+	}
+	sortOperationsWithSigners(ops, opKeys, service.vm.codec)
+
+	tx := Tx{UnsignedTx: &OperationTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		Ops: ops,
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+	// The credential of each operation must be of the fx of the operation
+	for i, op := range ops {
+		signers := [][]*crypto.PrivateKeySECP256K1R{opKeys[i]}
+		if _, ok := op.Op.(*secp256k1fx.MintOperation); ok {
+			err = tx.SignSECP256K1Fx(service.vm.codec, signers)
+		} else {
+			err = tx.SignNFTFx(service.vm.codec, signers)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Verify the operations one by one, so that a failure can be attributed
+	// to the requested operation
+	for i, op := range ops {
+		if err := service.vm.verifyOperation(tx.UnsignedTx, op, tx.Creds[len(ins)+i].Verifiable); err != nil {
+			batchOp := args.Operations[opIndices[op]]
+			return fmt.Errorf("operation %d (%s) failed verification: %w", opIndices[op], batchOp.Type, err)
+		}
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// batchOperation returns the operations that perform [batchOp] by consuming
+// [utxos], and the keys that must sign them
+func (service *Service) batchOperation(
+	batchOp BatchOperation,
+	utxos []*avax.UTXO,
+	kc *secp256k1fx.Keychain,
+) (
+	[]*Operation,
+	[][]*crypto.PrivateKeySECP256K1R,
+	error,
+) {
+	assetID, err := service.vm.lookupAssetID(batchOp.AssetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	to, err := service.vm.ParseLocalAddress(batchOp.To)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem parsing to address %q: %w", batchOp.To, err)
+	}
+
+	switch batchOp.Type {
+	case mintOperationType:
+		if batchOp.Amount == 0 {
+			return nil, nil, errInvalidMintAmount
+		}
+		return service.vm.Mint(
+			utxos,
+			kc,
+			map[ids.ID]uint64{
+				assetID: uint64(batchOp.Amount),
+			},
+			to,
+		)
+	case mintNFTOperationType:
+		payloadBytes, err := formatting.Decode(batchOp.Encoding, batchOp.Payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("problem decoding payload bytes: %w", err)
+		}
+		return service.vm.MintNFT(
+			utxos,
+			kc,
+			assetID,
+			payloadBytes,
+			to,
+		)
+	case sendNFTOperationType:
+		return service.vm.SpendNFT(
+			utxos,
+			kc,
+			assetID,
+			uint32(batchOp.GroupID),
+			to,
+		)
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", errUnknownOperationType, batchOp.Type)
+	}
+}
+
 // ImportArgs are arguments for passing into Import requests
 type ImportArgs struct {
 	// User that controls To
@@ -216,6 +216,96 @@ func TestServiceIssueTx(t *testing.T) {
 	}
 }
 
+func TestServiceSimulateTx(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, vm, s, _, _ := setup(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	simulate := func(tx *Tx) *api.SimulateTxReply {
+		txStr, err := formatting.EncodeWithChecksum(formatting.Hex, tx.Bytes())
+		assert.NoError(err)
+		reply := &api.SimulateTxReply{}
+		assert.NoError(s.SimulateTx(nil, &api.FormattedTx{
+			Tx:       txStr,
+			Encoding: formatting.Hex,
+		}, reply))
+		assert.Equal(tx.ID(), reply.TxID)
+		return reply
+	}
+
+	tx := NewTx(t, genesisBytes, vm)
+	reply := simulate(tx)
+	assert.True(reply.Valid)
+	assert.Empty(reply.Reason)
+
+	// The simulated tx isn't issued
+	assert.Empty(vm.txs)
+	statusReply := &GetTxStatusReply{}
+	assert.NoError(s.GetTxStatus(nil, &api.JSONTxID{TxID: tx.ID()}, statusReply))
+	assert.Equal(choices.Unknown, statusReply.Status)
+
+	// A tx signed by the wrong key is reported as invalid
+	badTx := &Tx{UnsignedTx: tx.UnsignedTx}
+	assert.NoError(badTx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[1]}}))
+	reply = simulate(badTx)
+	assert.False(reply.Valid)
+	assert.NotEmpty(reply.Reason)
+
+	// Txs that can't be parsed are errors
+	err := s.SimulateTx(nil, &api.FormattedTx{}, &api.SimulateTxReply{})
+	assert.Error(err)
+}
+
+func TestServiceDecodeTx(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, vm, s, _, _ := setup(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	tx := NewTx(t, genesisBytes, vm)
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, tx.Bytes())
+	assert.NoError(err)
+
+	reply := &api.DecodeTxReply{}
+	assert.NoError(s.DecodeTx(nil, &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, reply))
+	assert.Equal(tx.ID(), reply.TxID)
+
+	txJSON, err := json2.Marshal(reply.Tx)
+	assert.NoError(err)
+	decoded := struct {
+		UnsignedTx struct {
+			Inputs  []interface{} `json:"inputs"`
+			Outputs []interface{} `json:"outputs"`
+		} `json:"unsignedTx"`
+		Credentials []interface{} `json:"credentials"`
+	}{}
+	assert.NoError(json2.Unmarshal(txJSON, &decoded))
+	assert.Len(decoded.UnsignedTx.Inputs, 1)
+	assert.Len(decoded.UnsignedTx.Outputs, 0)
+	assert.Len(decoded.Credentials, 1)
+
+	// Decoding doesn't issue the tx
+	assert.Empty(vm.txs)
+	_, err = vm.state.GetTx(tx.ID())
+	assert.Error(err)
+
+	err = s.DecodeTx(nil, &api.FormattedTx{
+		Tx:       "0x00",
+		Encoding: formatting.Hex,
+	}, &api.DecodeTxReply{})
+	assert.Error(err)
+}
+
 func TestServiceGetTxStatus(t *testing.T) {
 	genesisBytes, vm, s, _, _ := setup(t, true)
 	defer func() {
@@ -2386,6 +2476,114 @@ func TestNFTWorkflow(t *testing.T) {
 	}
 }
 
+func TestBatchOperations(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, _ := setupWithKeys(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	assert.NoError(err)
+	spendHeader := api.JSONSpendHeader{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: addrStr},
+	}
+	minterSets := []Owners{{
+		Threshold: 1,
+		Minters:   []string{addrStr},
+	}}
+
+	createReply := &AssetIDChangeAddr{}
+	assert.NoError(s.CreateVariableCapAsset(nil, &CreateAssetArgs{
+		JSONSpendHeader: spendHeader,
+		Name:            "test asset",
+		Symbol:          "TEST",
+		MinterSets:      minterSets,
+	}, createReply))
+	assetID := createReply.AssetID.String()
+	createAssetTx := UniqueTx{vm: vm, txID: createReply.AssetID}
+	assert.NoError(createAssetTx.Accept())
+
+	createReply = &AssetIDChangeAddr{}
+	assert.NoError(s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		JSONSpendHeader: spendHeader,
+		Name:            "test nft",
+		Symbol:          "NFT",
+		MinterSets:      minterSets,
+	}, createReply))
+	nftAssetID := createReply.AssetID.String()
+	createNFTTx := UniqueTx{vm: vm, txID: createReply.AssetID}
+	assert.NoError(createNFTTx.Accept())
+
+	payload, err := formatting.EncodeWithChecksum(formatting.Hex, []byte{1, 2, 3})
+	assert.NoError(err)
+	mint := BatchOperation{
+		Type:    mintOperationType,
+		AssetID: assetID,
+		To:      addrStr,
+		Amount:  100,
+	}
+	mintNFT := BatchOperation{
+		Type:     mintNFTOperationType,
+		AssetID:  nftAssetID,
+		To:       addrStr,
+		Payload:  payload,
+		Encoding: formatting.Hex,
+	}
+
+	// Failures report the index of the failing operation
+	err = s.BatchOperations(nil, &BatchOperationsArgs{
+		JSONSpendHeader: spendHeader,
+		Operations:      []BatchOperation{mint, {Type: "burn", AssetID: assetID, To: addrStr}},
+	}, &api.JSONTxIDChangeAddr{})
+	assert.ErrorIs(err, errUnknownOperationType)
+	assert.Contains(err.Error(), "operation 1 (burn)")
+
+	// The minter of an asset can only be consumed once per batch
+	err = s.BatchOperations(nil, &BatchOperationsArgs{
+		JSONSpendHeader: spendHeader,
+		Operations:      []BatchOperation{mintNFT, mint, mint},
+	}, &api.JSONTxIDChangeAddr{})
+	assert.ErrorIs(err, errAddressesCantMintAsset)
+	assert.Contains(err.Error(), "operation 2 (mint)")
+
+	err = s.BatchOperations(nil, &BatchOperationsArgs{
+		JSONSpendHeader: spendHeader,
+	}, &api.JSONTxIDChangeAddr{})
+	assert.ErrorIs(err, errNoOperations)
+
+	reply := &api.JSONTxIDChangeAddr{}
+	assert.NoError(s.BatchOperations(nil, &BatchOperationsArgs{
+		JSONSpendHeader: spendHeader,
+		Operations:      []BatchOperation{mintNFT, mint},
+	}, reply))
+	assert.Equal(addrStr, reply.ChangeAddr)
+
+	batchTx := UniqueTx{vm: vm, txID: reply.TxID}
+	assert.Equal(choices.Processing, batchTx.Status())
+	opTx, ok := batchTx.UnsignedTx.(*OperationTx)
+	assert.True(ok)
+	assert.Len(opTx.Ops, 2)
+	assert.NoError(batchTx.Accept())
+
+	// The minted NFT can be sent in a later batch
+	reply = &api.JSONTxIDChangeAddr{}
+	assert.NoError(s.BatchOperations(nil, &BatchOperationsArgs{
+		JSONSpendHeader: spendHeader,
+		Operations: []BatchOperation{{
+			Type:    sendNFTOperationType,
+			AssetID: nftAssetID,
+			To:      addrStr,
+		}},
+	}, reply))
+}
+
 func TestImportExportKey(t *testing.T) {
 	_, vm, s, _, _ := setup(t, true)
 	defer func() {
@@ -2739,3 +2937,72 @@ func TestImport(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceGetTxFee(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, genesisTx := setup(t, false)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	reply := &GetTxFeeReply{}
+	assert.NoError(s.GetTxFee(nil, nil, reply))
+	assert.Equal(genesisTx.ID(), reply.FeeAssetID)
+	assert.EqualValues(testTxFee, reply.TxFee)
+	assert.EqualValues(testTxFee, reply.CreateAssetTxFee)
+	assert.Empty(reply.FeeCollector)
+
+	vm.feeCollector = testChangeAddr
+	assert.NoError(s.GetTxFee(nil, nil, reply))
+	feeCollectorStr, err := vm.FormatLocalAddress(testChangeAddr)
+	assert.NoError(err)
+	assert.Equal(feeCollectorStr, reply.FeeCollector)
+}
+
+func TestFeeCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	_, vm, s, _, genesisTx := setupWithKeys(t, true)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.feeCollector = ids.GenerateTestShortID()
+
+	assetID := genesisTx.ID()
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	assert.NoError(err)
+	args := &SendArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass: api.UserPass{
+				Username: username,
+				Password: password,
+			},
+		},
+		SendOutput: SendOutput{
+			Amount:  500,
+			AssetID: assetID.String(),
+			To:      addrStr,
+		},
+	}
+	reply := &api.JSONTxIDChangeAddr{}
+	vm.timer.Cancel()
+	assert.NoError(s.Send(nil, args, reply))
+
+	tx := UniqueTx{vm: vm, txID: reply.TxID}
+	assert.NoError(tx.Accept())
+
+	// The fee is paid to the collector in an output that follows the outputs
+	// of the tx
+	addrs := ids.ShortSet{}
+	addrs.Add(vm.feeCollector)
+	utxos, err := avax.GetAllUTXOs(vm.state, addrs)
+	assert.NoError(err)
+	assert.Len(utxos, 1)
+	assert.Equal(reply.TxID, utxos[0].TxID)
+	assert.EqualValues(len(tx.UTXOs()), utxos[0].OutputIndex)
+	assert.Equal(assetID, utxos[0].AssetID())
+	assert.EqualValues(testTxFee, utxos[0].Out.(*secp256k1fx.TransferOutput).Amt)
+}
@@ -17,9 +17,12 @@ import (
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/ed25519fx"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/regulatedfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/vms/vestingfx"
 
 	cjson "github.com/ava-labs/avalanchego/utils/json"
 )
@@ -44,6 +47,24 @@ var (
 	_ FxOperation       = &propertyfx.MintOperation{}
 	_ FxOperation       = &propertyfx.BurnOperation{}
 	_ verify.Verifiable = &propertyfx.Credential{}
+
+	_ avax.TransferableIn  = &regulatedfx.TransferInput{}
+	_ avax.TransferableOut = &regulatedfx.TransferOutput{}
+	_ verify.State         = &regulatedfx.FrozenOutput{}
+	_ verify.State         = &regulatedfx.RolesOutput{}
+	_ FxOperation          = &regulatedfx.FreezeOperation{}
+	_ FxOperation          = &regulatedfx.UnfreezeOperation{}
+	_ FxOperation          = &regulatedfx.ClawbackOperation{}
+	_ FxOperation          = &regulatedfx.SetRolesOperation{}
+	_ verify.Verifiable    = &regulatedfx.Credential{}
+
+	_ verify.State      = &vestingfx.VestingOutput{}
+	_ FxOperation       = &vestingfx.ReleaseOperation{}
+	_ verify.Verifiable = &vestingfx.Credential{}
+
+	_ avax.TransferableIn  = &ed25519fx.TransferInput{}
+	_ avax.TransferableOut = &ed25519fx.TransferOutput{}
+	_ verify.Verifiable    = &ed25519fx.Credential{}
 )
 
 // StaticService defines the base service for the asset vm
@@ -215,6 +236,21 @@ func staticCodec() (codec.Manager, error) {
 		c.RegisterType(&propertyfx.MintOperation{}),
 		c.RegisterType(&propertyfx.BurnOperation{}),
 		c.RegisterType(&propertyfx.Credential{}),
+		c.RegisterType(&regulatedfx.TransferInput{}),
+		c.RegisterType(&regulatedfx.TransferOutput{}),
+		c.RegisterType(&regulatedfx.FrozenOutput{}),
+		c.RegisterType(&regulatedfx.RolesOutput{}),
+		c.RegisterType(&regulatedfx.FreezeOperation{}),
+		c.RegisterType(&regulatedfx.UnfreezeOperation{}),
+		c.RegisterType(&regulatedfx.ClawbackOperation{}),
+		c.RegisterType(&regulatedfx.SetRolesOperation{}),
+		c.RegisterType(&regulatedfx.Credential{}),
+		c.RegisterType(&vestingfx.VestingOutput{}),
+		c.RegisterType(&vestingfx.ReleaseOperation{}),
+		c.RegisterType(&vestingfx.Credential{}),
+		c.RegisterType(&ed25519fx.TransferInput{}),
+		c.RegisterType(&ed25519fx.TransferOutput{}),
+		c.RegisterType(&ed25519fx.Credential{}),
 		manager.RegisterCodec(codecVersion, c),
 	)
 	return manager, errs.Err
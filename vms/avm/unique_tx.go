@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
 var (
@@ -141,6 +142,9 @@ func (tx *UniqueTx) Accept() error {
 	}
 
 	outputUTXOs := tx.UTXOs()
+	if feeUTXO := tx.feeUTXO(); feeUTXO != nil {
+		outputUTXOs = append(outputUTXOs, feeUTXO)
+	}
 	// index input and output UTXOs
 	if err := tx.vm.addressTxsIndexer.Accept(tx.ID(), inputUTXOs, outputUTXOs); err != nil {
 		return fmt.Errorf("error indexing tx: %w", err)
@@ -186,6 +190,43 @@ func (tx *UniqueTx) Accept() error {
 	return nil
 }
 
+// feeUTXO returns the UTXO that pays the fee of this transaction to the fee
+// collector, or nil if the fee is burned. Its output index follows the indices
+// of the outputs of the transaction. Fees paid beyond the required fee are
+// still burned.
+func (tx *UniqueTx) feeUTXO() *avax.UTXO {
+	if tx.vm.feeCollector == ids.ShortEmpty {
+		return nil
+	}
+
+	fee := tx.vm.TxFee
+	numOutputs := len(tx.UTXOs())
+	switch utx := tx.UnsignedTx.(type) {
+	case *CreateAssetTx:
+		fee = tx.vm.CreateAssetTxFee
+	case *ExportTx:
+		numOutputs += len(utx.ExportedOuts)
+	}
+	if fee == 0 {
+		return nil
+	}
+
+	return &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        tx.ID(),
+			OutputIndex: uint32(numOutputs),
+		},
+		Asset: avax.Asset{ID: tx.vm.feeAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: fee,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{tx.vm.feeCollector},
+			},
+		},
+	}
+}
+
 // Reject is called when the transaction was finalized as rejected by consensus
 func (tx *UniqueTx) Reject() error {
 	defer tx.vm.db.Abort()
@@ -0,0 +1,442 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/vms/vestingfx"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// Max number of vesting positions that a single Release request releases.
+// Further requests release the remaining positions.
+const maxReleasedPositions = 256
+
+var (
+	errNoVestingHolders = errors.New("vesting asset must have vesting holders")
+	errNothingUnlocked  = errors.New("user's vesting positions of the asset have nothing unlocked")
+)
+
+// VestingHolder describes a vesting position of a vesting asset
+type VestingHolder struct {
+	Amount  json.Uint64 `json:"amount"`
+	Address string      `json:"address"`
+	// Start of the linear vesting, as a Unix timestamp
+	Start json.Uint64 `json:"start"`
+	// Cliff before which nothing is unlocked, as a Unix timestamp
+	Cliff json.Uint64 `json:"cliff"`
+	// End at which the whole amount is unlocked, as a Unix timestamp
+	End json.Uint64 `json:"end"`
+}
+
+// CreateVestingAssetArgs are arguments for passing into CreateVestingAsset
+// requests
+type CreateVestingAssetArgs struct {
+	api.JSONSpendHeader                  // User, password, from addrs, change addr
+	Name                string           `json:"name"`
+	Symbol              string           `json:"symbol"`
+	Denomination        byte             `json:"denomination"`
+	InitialHolders      []*Holder        `json:"initialHolders"`
+	VestingHolders      []*VestingHolder `json:"vestingHolders"`
+}
+
+// CreateVestingAsset returns ID of the newly created vesting asset. The
+// initial holders hold unlocked units of the asset, and the vesting holders
+// hold vesting positions that they release as they unlock. The chain must
+// support the vesting fx.
+func (service *Service) CreateVestingAsset(r *http.Request, args *CreateVestingAssetArgs, reply *AssetIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("AVM: CreateVestingAsset called with name: %s symbol: %s number of holders: %d number of vesting holders: %d",
+		args.Name,
+		args.Symbol,
+		len(args.InitialHolders),
+		len(args.VestingHolders),
+	)
+
+	if len(args.VestingHolders) == 0 {
+		return errNoVestingHolders
+	}
+
+	secpFxIndex, err := service.vm.getFx(&secp256k1fx.TransferOutput{})
+	if err != nil {
+		return err
+	}
+	vestingFxIndex, err := service.vm.getFx(&vestingfx.VestingOutput{})
+	if err != nil {
+		return fmt.Errorf("chain doesn't support vesting assets: %w", err)
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	// Get the UTXOs/keys for the from addresses
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+
+	// Parse the change address.
+	if len(kc.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	if err != nil {
+		return err
+	}
+
+	amountsSpent, ins, keys, err := service.vm.Spend(
+		utxos,
+		kc,
+		map[ids.ID]uint64{
+			service.vm.feeAssetID: service.vm.CreateAssetTxFee,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	outs := []*avax.TransferableOutput{}
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.CreateAssetTxFee {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: service.vm.feeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - service.vm.CreateAssetTxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+
+	// The released units are secp256k1fx outputs, so the asset supports the
+	// secp256k1fx even if it has no initial holders
+	secpState := &InitialState{
+		FxIndex: uint32(secpFxIndex),
+		Outs:    make([]verify.State, 0, len(args.InitialHolders)),
+	}
+	for _, holder := range args.InitialHolders {
+		addr, err := service.vm.ParseLocalAddress(holder.Address)
+		if err != nil {
+			return err
+		}
+		secpState.Outs = append(secpState.Outs, &secp256k1fx.TransferOutput{
+			Amt: uint64(holder.Amount),
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		})
+	}
+	secpState.Sort(service.vm.codec)
+
+	vestingState := &InitialState{
+		FxIndex: uint32(vestingFxIndex),
+		Outs:    make([]verify.State, 0, len(args.VestingHolders)),
+	}
+	for _, holder := range args.VestingHolders {
+		addr, err := service.vm.ParseLocalAddress(holder.Address)
+		if err != nil {
+			return err
+		}
+		vestingState.Outs = append(vestingState.Outs, &vestingfx.VestingOutput{
+			Schedule: vestingfx.Schedule{
+				Start: uint64(holder.Start),
+				Cliff: uint64(holder.Cliff),
+				End:   uint64(holder.End),
+			},
+			Amt: uint64(holder.Amount),
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		})
+	}
+	vestingState.Sort(service.vm.codec)
+
+	createAssetTx := &CreateAssetTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		Name:         args.Name,
+		Symbol:       args.Symbol,
+		Denomination: args.Denomination,
+		States:       []*InitialState{secpState, vestingState},
+	}
+	createAssetTx.Sort()
+	tx := Tx{UnsignedTx: createAssetTx}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+
+	assetID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.AssetID = assetID
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
+
+// GetVestingPositionsArgs are arguments for passing into GetVestingPositions
+// requests
+type GetVestingPositionsArgs struct {
+	api.JSONAddresses
+	// AssetID, if given, restricts the positions to those of this asset
+	AssetID string `json:"assetID"`
+}
+
+// VestingPosition describes a vesting output
+type VestingPosition struct {
+	UTXOID  string `json:"utxoID"`
+	AssetID ids.ID `json:"assetID"`
+	// Amount of the position, released or not
+	Amount json.Uint64 `json:"amount"`
+	// Released is the amount of the position that was released
+	Released json.Uint64 `json:"released"`
+	// Unlocked is the amount of the position that may be released now
+	Unlocked  json.Uint64 `json:"unlocked"`
+	Start     json.Uint64 `json:"start"`
+	Cliff     json.Uint64 `json:"cliff"`
+	End       json.Uint64 `json:"end"`
+	Threshold json.Uint32 `json:"threshold"`
+	Addresses []string    `json:"addresses"`
+}
+
+// GetVestingPositionsReply defines the GetVestingPositions replies returned
+// from the API
+type GetVestingPositionsReply struct {
+	Positions []VestingPosition `json:"positions"`
+}
+
+// GetVestingPositions returns the vesting positions that reference any of the
+// given addresses
+func (service *Service) GetVestingPositions(r *http.Request, args *GetVestingPositionsArgs, reply *GetVestingPositionsReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetVestingPositions called with addresses: %s assetID: %s", args.Addresses, args.AssetID)
+
+	if len(args.Addresses) == 0 {
+		return errNoAddresses
+	}
+	if len(args.Addresses) > maxGetUTXOsAddrs {
+		return fmt.Errorf("number of addresses given, %d, exceeds maximum, %d", len(args.Addresses), maxGetUTXOsAddrs)
+	}
+	addrs, err := avax.ParseLocalAddresses(service.vm, args.Addresses)
+	if err != nil {
+		return err
+	}
+	filterAsset := args.AssetID != ""
+	var assetID ids.ID
+	if filterAsset {
+		assetID, err = service.vm.lookupAssetID(args.AssetID)
+		if err != nil {
+			return err
+		}
+	}
+
+	utxos, err := avax.GetAllUTXOs(service.vm.state, addrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+
+	now := service.vm.clock.Unix()
+	reply.Positions = []VestingPosition{}
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*vestingfx.VestingOutput)
+		if !ok || (filterAsset && utxo.AssetID() != assetID) {
+			continue
+		}
+		position := VestingPosition{
+			UTXOID:    utxo.InputID().String(),
+			AssetID:   utxo.AssetID(),
+			Amount:    json.Uint64(out.Amt),
+			Released:  json.Uint64(out.Released),
+			Unlocked:  json.Uint64(out.Unlocked(now)),
+			Start:     json.Uint64(out.Schedule.Start),
+			Cliff:     json.Uint64(out.Schedule.Cliff),
+			End:       json.Uint64(out.Schedule.End),
+			Threshold: json.Uint32(out.Threshold),
+			Addresses: make([]string, len(out.Addrs)),
+		}
+		for i, addr := range out.Addrs {
+			position.Addresses[i], err = service.vm.FormatLocalAddress(addr)
+			if err != nil {
+				return err
+			}
+		}
+		reply.Positions = append(reply.Positions, position)
+	}
+	return nil
+}
+
+// ReleaseArgs are arguments for passing into Release requests
+type ReleaseArgs struct {
+	api.JSONSpendHeader        // User, password, from addrs, change addr
+	AssetID             string `json:"assetID"`
+	// To receives the released amount
+	To string `json:"to"`
+}
+
+// ReleaseReply defines the Release replies returned from the API
+type ReleaseReply struct {
+	api.JSONTxIDChangeAddr
+	// Amount released by the transaction
+	Amount json.Uint64 `json:"amount"`
+}
+
+// Release releases what is unlocked of the user's vesting positions of an
+// asset
+func (service *Service) Release(r *http.Request, args *ReleaseArgs, reply *ReleaseReply) error {
+	service.vm.ctx.Log.Debug("AVM: Release called with username: %s", args.Username)
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+	to, err := service.vm.ParseLocalAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address %q: %w", args.To, err)
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	// Get the UTXOs/keys for the from addresses
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password, fromAddrs)
+	if err != nil {
+		return err
+	}
+
+	// Parse the change address.
+	if len(kc.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	if err != nil {
+		return err
+	}
+
+	amountsSpent, ins, keys, err := service.vm.Spend(
+		utxos,
+		kc,
+		map[ids.ID]uint64{
+			service.vm.feeAssetID: service.vm.TxFee,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	outs := []*avax.TransferableOutput{}
+	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent > service.vm.TxFee {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: service.vm.feeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - service.vm.TxFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+
+	now := service.vm.clock.Unix()
+	ops := []*Operation{}
+	signers := [][]*crypto.PrivateKeySECP256K1R{}
+	released := uint64(0)
+	for _, utxo := range utxos {
+		if len(ops) == maxReleasedPositions {
+			break
+		}
+		out, ok := utxo.Out.(*vestingfx.VestingOutput)
+		if !ok || utxo.AssetID() != assetID {
+			continue
+		}
+		unlocked := out.Unlocked(now)
+		if unlocked == 0 {
+			continue
+		}
+		indices, owners, ok := kc.Match(&out.OutputOwners, now)
+		if !ok {
+			continue
+		}
+
+		op := &vestingfx.ReleaseOperation{
+			Input: secp256k1fx.Input{SigIndices: indices},
+			Output: secp256k1fx.TransferOutput{
+				Amt: unlocked,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		}
+		if remaining := *out; remaining.Released+unlocked < remaining.Amt {
+			remaining.Released += unlocked
+			op.Remaining = []*vestingfx.VestingOutput{&remaining}
+		}
+		ops = append(ops, &Operation{
+			Asset:   avax.Asset{ID: assetID},
+			UTXOIDs: []*avax.UTXOID{&utxo.UTXOID},
+			Op:      op,
+		})
+		signers = append(signers, owners)
+		released, err = safemath.Add64(released, unlocked)
+		if err != nil {
+			return err
+		}
+	}
+	if len(ops) == 0 {
+		return errNothingUnlocked
+	}
+	sortOperationsWithSigners(ops, signers, service.vm.codec)
+
+	tx := Tx{UnsignedTx: &OperationTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+		}},
+		Ops: ops,
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return err
+	}
+	if err := tx.SignVestingFx(service.vm.codec, signers); err != nil {
+		return err
+	}
+
+	txID, err := service.vm.IssueTx(tx.Bytes())
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	reply.Amount = json.Uint64(released)
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+	return err
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/components/keystore"
+	"github.com/ava-labs/avalanchego/vms/vestingfx"
+)
+
+func TestVestingAsset(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, vm, _ := GenesisVMWithArgs(t, []*common.Fx{{
+		ID: vestingfx.ID,
+		Fx: &vestingfx.Fx{},
+	}}, nil)
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	s := &Service{vm: vm}
+
+	user, err := keystore.NewUserFromKeystore(vm.ctx.Keystore, username, password)
+	assert.NoError(err)
+	assert.NoError(user.PutKeys(keys...))
+	assert.NoError(user.Close())
+
+	addrStrs := make([]string, len(keys))
+	for i, key := range keys {
+		addrStrs[i], err = vm.FormatLocalAddress(key.PublicKey().Address())
+		assert.NoError(err)
+	}
+	spendHeader := api.JSONSpendHeader{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: addrStrs[0]},
+	}
+	accept := func(txID ids.ID) {
+		tx := UniqueTx{vm: vm, txID: txID}
+		assert.NoError(tx.Accept())
+	}
+	positions := func() []VestingPosition {
+		reply := &GetVestingPositionsReply{}
+		assert.NoError(s.GetVestingPositions(nil, &GetVestingPositionsArgs{
+			JSONAddresses: api.JSONAddresses{Addresses: []string{addrStrs[1]}},
+		}, reply))
+		return reply.Positions
+	}
+
+	now := time.Unix(1_000_000, 0)
+	vm.clock.Set(now)
+	unix := uint64(now.Unix())
+
+	createReply := &AssetIDChangeAddr{}
+	assert.NoError(s.CreateVestingAsset(nil, &CreateVestingAssetArgs{
+		JSONSpendHeader: spendHeader,
+		Name:            "vesting asset",
+		Symbol:          "VEST",
+		VestingHolders: []*VestingHolder{{
+			Amount:  1000,
+			Address: addrStrs[1],
+			Start:   json.Uint64(unix),
+			Cliff:   json.Uint64(unix + 50),
+			End:     json.Uint64(unix + 200),
+		}},
+	}, createReply))
+	assetID := createReply.AssetID
+	accept(assetID)
+
+	vestingPositions := positions()
+	assert.Len(vestingPositions, 1)
+	assert.Equal(assetID, vestingPositions[0].AssetID)
+	assert.Equal(json.Uint64(1000), vestingPositions[0].Amount)
+	assert.Equal(json.Uint64(0), vestingPositions[0].Unlocked)
+	assert.Equal([]string{addrStrs[1]}, vestingPositions[0].Addresses)
+
+	// Nothing is unlocked before the cliff
+	releaseArgs := &ReleaseArgs{
+		JSONSpendHeader: spendHeader,
+		AssetID:         assetID.String(),
+		To:              addrStrs[2],
+	}
+	assert.ErrorIs(s.Release(nil, releaseArgs, &ReleaseReply{}), errNothingUnlocked)
+
+	// Release what vested by the cliff
+	vm.clock.Set(now.Add(50 * time.Second))
+	releaseReply := &ReleaseReply{}
+	assert.NoError(s.Release(nil, releaseArgs, releaseReply))
+	assert.Equal(json.Uint64(250), releaseReply.Amount)
+	accept(releaseReply.TxID)
+
+	vestingPositions = positions()
+	assert.Len(vestingPositions, 1)
+	assert.Equal(json.Uint64(250), vestingPositions[0].Released)
+	assert.Equal(json.Uint64(0), vestingPositions[0].Unlocked)
+
+	// The released units are spendable like any other
+	balanceReply := &GetBalanceReply{}
+	assert.NoError(s.GetBalance(nil, &GetBalanceArgs{
+		Address: addrStrs[2],
+		AssetID: assetID.String(),
+	}, balanceReply))
+	assert.Equal(json.Uint64(250), balanceReply.Balance)
+
+	// Release the rest once the vesting ends
+	vm.clock.Set(now.Add(time.Hour))
+	releaseReply = &ReleaseReply{}
+	assert.NoError(s.Release(nil, releaseArgs, releaseReply))
+	assert.Equal(json.Uint64(750), releaseReply.Amount)
+	accept(releaseReply.TxID)
+
+	assert.Empty(positions())
+	assert.ErrorIs(s.Release(nil, releaseArgs, &ReleaseReply{}), errNothingUnlocked)
+}
@@ -11,14 +11,14 @@ import (
 	"reflect"
 	"time"
 
-	"github.com/gorilla/rpc/v2"
-
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/avalanchego/api/doc"
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/pubsub"
@@ -35,6 +35,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/index"
 	"github.com/ava-labs/avalanchego/vms/components/keystore"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/ed25519fx"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
@@ -49,6 +50,8 @@ const (
 )
 
 var (
+	importIndexPrefix = []byte("importIndex")
+
 	errIncompatibleFx            = errors.New("incompatible feature extension")
 	errUnknownFx                 = errors.New("unknown feature extension")
 	errGenesisAssetMustHaveState = errors.New("genesis asset must have non-empty state")
@@ -79,12 +82,18 @@ type VM struct {
 	// State management
 	state State
 
+	// Records which import txs consumed the outputs exported from other chains
+	importIndex avax.ImportIndex
+
 	// Set to true once this VM is marked as `Bootstrapped` by the engine
 	bootstrapped bool
 
 	// asset id that will be used for fees
 	feeAssetID ids.ID
 
+	// address that receives the fees, which are burned if it's empty
+	feeCollector ids.ShortID
+
 	// Asset ID --> Bit set with fx IDs the asset supports
 	assetToFxCache *cache.LRU
 
@@ -94,6 +103,9 @@ type VM struct {
 	txs          []snowstorm.Tx
 	toEngine     chan<- common.Message
 
+	// Txs that are waiting for the txs they depend on to arrive
+	orphans *orphanPool
+
 	baseDB database.Database
 	db     *versiondb.Database
 
@@ -159,7 +171,9 @@ func (vm *VM) Initialize(
 	vm.toEngine = toEngine
 	vm.baseDB = db
 	vm.db = versiondb.New(db)
+	vm.importIndex = avax.NewImportIndex(prefixdb.New(importIndexPrefix, db))
 	vm.assetToFxCache = &cache.LRU{Size: assetToFxCacheSize}
+	vm.orphans = newOrphanPool()
 
 	vm.pubsub = pubsub.New(ctx.NetworkID, ctx.Log)
 
@@ -292,7 +306,7 @@ func (vm *VM) Version() (string, error) {
 func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	codec := cjson.NewCodec()
 
-	rpcServer := rpc.NewServer()
+	rpcServer := doc.NewServer()
 	rpcServer.RegisterCodec(codec, "application/json")
 	rpcServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	rpcServer.RegisterInterceptFunc(vm.metrics.apiRequestMetric.InterceptRequest)
@@ -302,7 +316,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		return nil, err
 	}
 
-	walletServer := rpc.NewServer()
+	walletServer := doc.NewServer()
 	walletServer.RegisterCodec(codec, "application/json")
 	walletServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	walletServer.RegisterInterceptFunc(vm.metrics.apiRequestMetric.InterceptRequest)
@@ -318,7 +332,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 }
 
 func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+	newServer := doc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
@@ -339,7 +353,14 @@ func (vm *VM) PendingTxs() []snowstorm.Tx {
 }
 
 func (vm *VM) ParseTx(b []byte) (snowstorm.Tx, error) {
-	return vm.parseTx(b)
+	tx, err := vm.parseTx(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// The orphans waiting for this tx can now be verified
+	vm.issueOrphans(tx.ID())
+	return tx, nil
 }
 
 func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
@@ -370,11 +391,46 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 	if err != nil {
 		return ids.ID{}, err
 	}
+	txID := tx.ID()
 	if err := tx.verifyWithoutCacheWrites(); err != nil {
-		return ids.ID{}, err
+		// If the tx spends the outputs of txs that haven't arrived yet, it is
+		// held until they do.
+		missing, depsErr := vm.missingDependencies(tx)
+		if depsErr != nil || missing.Len() == 0 {
+			return ids.ID{}, err
+		}
+		vm.ctx.Log.Debug("holding orphan tx %s until %s arrive", txID, missing)
+		vm.orphans.Add(tx, missing)
+		vm.numOrphans.Set(float64(vm.orphans.Len()))
+		return txID, nil
 	}
 	vm.issueTx(tx)
-	return tx.ID(), nil
+	vm.issueOrphans(txID)
+	return txID, nil
+}
+
+// SimulateTx runs the verification that IssueTx would run on [tx] without
+// persisting or issuing it. The returned error is why [tx] would be dropped.
+func (vm *VM) SimulateTx(tx *Tx) error {
+	if !vm.bootstrapped {
+		return errBootstrapping
+	}
+
+	if status, err := vm.state.GetStatus(tx.ID()); err == nil && status == choices.Rejected {
+		return errRejectedTx
+	}
+
+	if err := tx.SyntacticVerify(
+		vm.ctx,
+		vm.codec,
+		vm.feeAssetID,
+		vm.TxFee,
+		vm.CreateAssetTxFee,
+		len(vm.fxs),
+	); err != nil {
+		return err
+	}
+	return tx.SemanticVerify(vm, tx.UnsignedTx)
 }
 
 func (vm *VM) issueStopVertex() error {
@@ -454,6 +510,14 @@ func (vm *VM) initGenesis(genesisBytes []byte) error {
 		}
 	}
 
+	// The fee config declared by the subnet of this chain overrides the
+	// genesis
+	if vm.ctx.FeeAssetID != ids.Empty {
+		vm.ctx.Log.Info("Fee payments are using the subnet's fee AssetID: %s", vm.ctx.FeeAssetID)
+		vm.feeAssetID = vm.ctx.FeeAssetID
+	}
+	vm.feeCollector = vm.ctx.FeeCollector
+
 	if !stateInitialized {
 		return vm.state.SetInitialized()
 	}
@@ -769,6 +833,106 @@ func (vm *VM) Spend(
 	return amountsSpent, ins, keys, nil
 }
 
+// LoadEd25519User returns the UTXOs owned by the ed25519 keys of the user and
+// a keychain of these keys. If [addrsToUse] is non-empty, only the keys of
+// these addresses are used.
+func (vm *VM) LoadEd25519User(
+	username string,
+	password string,
+	addrsToUse ids.ShortSet,
+) (
+	[]*avax.UTXO,
+	*ed25519fx.Keychain,
+	error,
+) {
+	user, err := keystore.NewUserFromKeystore(vm.ctx.Keystore, username, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Drop any potential error closing the database to report the original
+	// error
+	defer user.Close()
+
+	kc, err := keystore.GetEd25519Keychain(user, addrsToUse)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	utxos, err := avax.GetAllUTXOs(vm.state, kc.Addresses())
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+	}
+
+	return utxos, kc, user.Close()
+}
+
+// SpendEd25519 is Spend for the ed25519fx outputs of [utxos]. Unlike Spend, it
+// doesn't sort the inputs, as they're usually spent along with secp256k1fx
+// inputs that pay the fee.
+func (vm *VM) SpendEd25519(
+	utxos []*avax.UTXO,
+	kc *ed25519fx.Keychain,
+	amounts map[ids.ID]uint64,
+) (
+	map[ids.ID]uint64,
+	[]*avax.TransferableInput,
+	[][]*crypto.PrivateKeyED25519,
+	error,
+) {
+	amountsSpent := make(map[ids.ID]uint64, len(amounts))
+	time := vm.clock.Unix()
+
+	ins := []*avax.TransferableInput{}
+	keys := [][]*crypto.PrivateKeyED25519{}
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		amount := amounts[assetID]
+		amountSpent := amountsSpent[assetID]
+
+		if amountSpent >= amount {
+			// we already have enough inputs allocated to this asset
+			continue
+		}
+
+		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		if err != nil {
+			// this utxo can't be spent with the current keys right now
+			continue
+		}
+		input, ok := inputIntf.(avax.TransferableIn)
+		if !ok {
+			// this input doesn't have an amount, so I don't care about it here
+			continue
+		}
+		newAmountSpent, err := safemath.Add64(amountSpent, input.Amount())
+		if err != nil {
+			// there was an error calculating the consumed amount, just error
+			return nil, nil, nil, errSpendOverflow
+		}
+		amountsSpent[assetID] = newAmountSpent
+
+		// add the new input to the array
+		ins = append(ins, &avax.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  avax.Asset{ID: assetID},
+			In:     input,
+		})
+		// add the required keys to the array
+		keys = append(keys, signers)
+	}
+
+	for asset, amount := range amounts {
+		if amountsSpent[asset] < amount {
+			return nil, nil, nil, fmt.Errorf("want to spend %d of asset %s but only have %d",
+				amount,
+				asset,
+				amountsSpent[asset],
+			)
+		}
+	}
+	return amountsSpent, ins, keys, nil
+}
+
 func (vm *VM) SpendNFT(
 	utxos []*avax.UTXO,
 	kc *secp256k1fx.Keychain,
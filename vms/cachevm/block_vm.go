@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cachevm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var (
+	_ block.ChainVM              = &blockVM{}
+	_ block.AbortableChainVM     = &blockVM{}
+	_ block.ScheduledChainVM     = &blockVM{}
+	_ block.ChainVerifierVM      = &blockVM{}
+	_ block.WrappingChainVM      = &blockVM{}
+	_ block.PreparingChainVM     = &blockVM{}
+	_ block.BatchedChainVM       = &batchedVM{}
+	_ block.HeightIndexedChainVM = &heightIndexedVM{}
+	_ block.BatchedChainVM       = &batchedHeightIndexedVM{}
+	_ block.HeightIndexedChainVM = &batchedHeightIndexedVM{}
+)
+
+// NewBlockVM returns a VM that serves GetBlock calls for accepted blocks from
+// [blocks] before falling back to [vm].
+//
+// The returned VM implements the same optional interfaces as [vm], so that
+// callers checking for them, such as the ProposerVM, behave as if [vm] wasn't
+// wrapped. AbortableChainVM is always implemented, and reports
+// block.ErrAbortableVMNotImplemented if [vm] doesn't implement it.
+// ScheduledChainVM is always implemented, and only notifies [vm] if [vm]
+// implements it. ChainVerifierVM and WrappingChainVM are always implemented,
+// and report block.ErrChainVerifierVMNotImplemented and
+// block.ErrWrappingVMNotImplemented if [vm] doesn't implement them.
+// PreparingChainVM is always implemented, and does nothing if [vm] doesn't
+// implement it.
+func NewBlockVM(vm block.ChainVM, blocks *DecidedBlocks) block.ChainVM {
+	cachedVM := &blockVM{
+		ChainVM: vm,
+		blocks:  blocks,
+		vmID:    blocks.newVMID(),
+	}
+	bVM, isBatched := vm.(block.BatchedChainVM)
+	hVM, isHeightIndexed := vm.(block.HeightIndexedChainVM)
+	switch {
+	case isBatched && isHeightIndexed:
+		return &batchedHeightIndexedVM{
+			blockVM:              cachedVM,
+			BatchedChainVM:       bVM,
+			HeightIndexedChainVM: hVM,
+		}
+	case isBatched:
+		return &batchedVM{
+			blockVM:        cachedVM,
+			BatchedChainVM: bVM,
+		}
+	case isHeightIndexed:
+		return &heightIndexedVM{
+			blockVM:              cachedVM,
+			HeightIndexedChainVM: hVM,
+		}
+	default:
+		return cachedVM
+	}
+}
+
+type blockVM struct {
+	block.ChainVM
+	blocks *DecidedBlocks
+	vmID   uint64
+}
+
+type batchedVM struct {
+	*blockVM
+	block.BatchedChainVM
+}
+
+type heightIndexedVM struct {
+	*blockVM
+	block.HeightIndexedChainVM
+}
+
+type batchedHeightIndexedVM struct {
+	*blockVM
+	block.BatchedChainVM
+	block.HeightIndexedChainVM
+}
+
+func (vm *blockVM) GetBlock(blkID ids.ID) (snowman.Block, error) {
+	if blk, ok := vm.blocks.get(vm.vmID, blkID); ok {
+		return blk, nil
+	}
+	blk, err := vm.ChainVM.GetBlock(blkID)
+	if err != nil {
+		return nil, err
+	}
+	vm.blocks.put(vm.vmID, blk)
+	return blk, nil
+}
+
+func (vm *blockVM) BuildBlockWithContext(ctx context.Context) (snowman.Block, error) {
+	aVM, ok := vm.ChainVM.(block.AbortableChainVM)
+	if !ok {
+		return nil, block.ErrAbortableVMNotImplemented
+	}
+	return aVM.BuildBlockWithContext(ctx)
+}
+
+func (vm *blockVM) SetProposerSlot(parentID ids.ID, slotStart time.Time) error {
+	sVM, ok := vm.ChainVM.(block.ScheduledChainVM)
+	if !ok {
+		return nil
+	}
+	return sVM.SetProposerSlot(parentID, slotStart)
+}
+
+func (vm *blockVM) VerifyChain(blks []snowman.Block) error {
+	cVM, ok := vm.ChainVM.(block.ChainVerifierVM)
+	if !ok {
+		return block.ErrChainVerifierVMNotImplemented
+	}
+	return cVM.VerifyChain(blks)
+}
+
+func (vm *blockVM) GetWrappingBlock(blkID ids.ID) (snowman.Block, error) {
+	wVM, ok := vm.ChainVM.(block.WrappingChainVM)
+	if !ok {
+		return nil, block.ErrWrappingVMNotImplemented
+	}
+	return wVM.GetWrappingBlock(blkID)
+}
+
+func (vm *blockVM) PrepareBlock(blkBytes []byte) {
+	block.PrepareBlock(vm.ChainVM, blkBytes)
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cachevm
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+func TestGetBlockCachesAcceptedBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	accepted := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	processing := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+
+	calls := map[ids.ID]int{}
+	innerVM := &block.TestVM{}
+	innerVM.T = t
+	innerVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		calls[blkID]++
+		switch blkID {
+		case accepted.ID():
+			return accepted, nil
+		case processing.ID():
+			return processing, nil
+		default:
+			return nil, database.ErrNotFound
+		}
+	}
+
+	blocks, err := NewDecidedBlocks(16, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	vm := NewBlockVM(innerVM, blocks)
+
+	for i := 0; i < 2; i++ {
+		blk, err := vm.GetBlock(accepted.ID())
+		assert.NoError(err)
+		assert.Equal(accepted, blk)
+
+		blk, err = vm.GetBlock(processing.ID())
+		assert.NoError(err)
+		assert.Equal(processing, blk)
+	}
+	assert.Equal(1, calls[accepted.ID()])
+	assert.Equal(2, calls[processing.ID()])
+
+	_, err = vm.GetBlock(ids.GenerateTestID())
+	assert.ErrorIs(err, database.ErrNotFound)
+
+	// Another VM sharing the cache doesn't see this VM's blocks
+	otherVM := NewBlockVM(innerVM, blocks)
+	_, err = otherVM.GetBlock(accepted.ID())
+	assert.NoError(err)
+	assert.Equal(2, calls[accepted.ID()])
+}
+
+func TestNewBlockVMPreservesInterfaces(t *testing.T) {
+	assert := assert.New(t)
+
+	blocks, err := NewDecidedBlocks(16, "", prometheus.NewRegistry())
+	assert.NoError(err)
+
+	vm := NewBlockVM(&block.TestVM{}, blocks)
+	_, isBatched := vm.(block.BatchedChainVM)
+	_, isHeightIndexed := vm.(block.HeightIndexedChainVM)
+	assert.False(isBatched)
+	assert.False(isHeightIndexed)
+
+	vm = NewBlockVM(&struct {
+		block.TestVM
+		block.TestBatchedVM
+		block.TestHeightIndexedVM
+	}{}, blocks)
+	_, isBatched = vm.(block.BatchedChainVM)
+	_, isHeightIndexed = vm.(block.HeightIndexedChainVM)
+	assert.True(isBatched)
+	assert.True(isHeightIndexed)
+}
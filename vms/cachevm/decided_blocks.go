@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cachevm
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+type blockKey struct {
+	// Distinguishes wrapped VMs of the same chain. A proposervm pre-fork
+	// block has the same ID as the inner block it wraps.
+	vmID  uint64
+	blkID ids.ID
+}
+
+// DecidedBlocks caches recently accepted blocks of all the chains running on
+// this node. Accepted blocks never change status, so they can be served
+// without asking the VM again.
+type DecidedBlocks struct {
+	blocks cache.LRU
+	// ID assigned to the next VM that uses this cache
+	nextVMID uint64
+
+	hits   prometheus.Counter
+	misses prometheus.Counter
+}
+
+// NewDecidedBlocks returns a cache holding up to [size] accepted blocks
+func NewDecidedBlocks(size int, namespace string, registerer prometheus.Registerer) (*DecidedBlocks, error) {
+	d := &DecidedBlocks{
+		blocks: cache.LRU{Size: size},
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "hits",
+			Help:      "Number of block lookups served from the decided block cache",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "misses",
+			Help:      "Number of block lookups that missed the decided block cache",
+		}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(d.hits),
+		registerer.Register(d.misses),
+	)
+	return d, errs.Err
+}
+
+// newVMID returns a unique ID that a VM can use to namespace its blocks
+func (d *DecidedBlocks) newVMID() uint64 {
+	return atomic.AddUint64(&d.nextVMID, 1)
+}
+
+func (d *DecidedBlocks) get(vmID uint64, blkID ids.ID) (snowman.Block, bool) {
+	blk, ok := d.blocks.Get(blockKey{vmID: vmID, blkID: blkID})
+	if !ok {
+		d.misses.Inc()
+		return nil, false
+	}
+	d.hits.Inc()
+	return blk.(snowman.Block), true
+}
+
+// put caches [blk] if it has been accepted
+func (d *DecidedBlocks) put(vmID uint64, blk snowman.Block) {
+	if blk.Status() != choices.Accepted {
+		return
+	}
+	d.blocks.Put(blockKey{vmID: vmID, blkID: blk.ID()}, blk)
+}
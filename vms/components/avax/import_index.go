@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// importIndexKeyLen is the length of a key of the import index:
+// the ID of the export tx followed by the index of the exported output.
+const importIndexKeyLen = 32 + wrappers.IntLen
+
+// ImportedOutput is an output exported from another chain that was consumed
+// by an accepted import tx
+type ImportedOutput struct {
+	// Index of the output in the export tx
+	OutputIndex uint32
+	// ID of the import tx that consumed the output
+	ImportTxID ids.ID
+}
+
+// ImportIndex records, for the outputs imported from other chains, which
+// accepted import tx consumed them. Only imports accepted after the index was
+// introduced are recorded.
+type ImportIndex interface {
+	// Index returns a batch that records that [importTxID], imported from
+	// [sourceChainID], consumed [utxoIDs]. The batch should be written
+	// atomically with the acceptance of the import tx.
+	Index(sourceChainID, importTxID ids.ID, utxoIDs []*UTXOID) (database.Batch, error)
+
+	// GetImports returns the outputs of [exportTxID], exported from
+	// [sourceChainID], that were imported into this chain, ordered by output
+	// index.
+	GetImports(sourceChainID, exportTxID ids.ID) ([]ImportedOutput, error)
+}
+
+type importIndex struct {
+	db database.Database
+}
+
+func NewImportIndex(db database.Database) ImportIndex {
+	return &importIndex{db: db}
+}
+
+func (i *importIndex) Index(sourceChainID, importTxID ids.ID, utxoIDs []*UTXOID) (database.Batch, error) {
+	batch := prefixdb.New(sourceChainID[:], i.db).NewBatch()
+	for _, utxoID := range utxoIDs {
+		key := make([]byte, importIndexKeyLen)
+		copy(key, utxoID.TxID[:])
+		binary.BigEndian.PutUint32(key[32:], utxoID.OutputIndex)
+		if err := batch.Put(key, importTxID[:]); err != nil {
+			return nil, err
+		}
+	}
+	return batch, nil
+}
+
+func (i *importIndex) GetImports(sourceChainID, exportTxID ids.ID) ([]ImportedOutput, error) {
+	iter := prefixdb.New(sourceChainID[:], i.db).NewIteratorWithPrefix(exportTxID[:])
+	defer iter.Release()
+
+	var imports []ImportedOutput
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != importIndexKeyLen {
+			continue
+		}
+		importTxID, err := ids.ToID(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, ImportedOutput{
+			OutputIndex: binary.BigEndian.Uint32(key[32:]),
+			ImportTxID:  importTxID,
+		})
+	}
+	return imports, iter.Error()
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestImportIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	sourceChainID := ids.GenerateTestID()
+	otherChainID := ids.GenerateTestID()
+	exportTxID := ids.GenerateTestID()
+	otherExportTxID := ids.GenerateTestID()
+	importTxID := ids.GenerateTestID()
+	otherImportTxID := ids.GenerateTestID()
+
+	db := memdb.New()
+	i := NewImportIndex(db)
+
+	imports, err := i.GetImports(sourceChainID, exportTxID)
+	assert.NoError(err)
+	assert.Empty(imports)
+
+	batch, err := i.Index(sourceChainID, importTxID, []*UTXOID{
+		{TxID: exportTxID, OutputIndex: 2},
+		{TxID: otherExportTxID, OutputIndex: 0},
+	})
+	assert.NoError(err)
+	// Nothing is recorded until the batch is written
+	imports, err = i.GetImports(sourceChainID, exportTxID)
+	assert.NoError(err)
+	assert.Empty(imports)
+	assert.NoError(batch.Write())
+
+	batch, err = i.Index(sourceChainID, otherImportTxID, []*UTXOID{
+		{TxID: exportTxID, OutputIndex: 1},
+	})
+	assert.NoError(err)
+	assert.NoError(batch.Write())
+
+	i = NewImportIndex(db)
+	imports, err = i.GetImports(sourceChainID, exportTxID)
+	assert.NoError(err)
+	assert.Equal([]ImportedOutput{
+		{OutputIndex: 1, ImportTxID: otherImportTxID},
+		{OutputIndex: 2, ImportTxID: importTxID},
+	}, imports)
+
+	imports, err = i.GetImports(sourceChainID, otherExportTxID)
+	assert.NoError(err)
+	assert.Equal([]ImportedOutput{{OutputIndex: 0, ImportTxID: importTxID}}, imports)
+
+	// Imports are recorded per source chain
+	imports, err = i.GetImports(otherChainID, exportTxID)
+	assert.NoError(err)
+	assert.Empty(imports)
+}
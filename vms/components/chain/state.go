@@ -4,6 +4,7 @@
 package chain
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/cache"
@@ -12,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -28,6 +30,10 @@ type State struct {
 	// create an unknown block, and building on top of the preferred block should never yield
 	// a block that has already been decided.
 	buildBlock func() (snowman.Block, error)
+	// buildBlockWithContext attempts to build a block like buildBlock, aborting
+	// the build once the context is done. It's nil if the VM can't abort
+	// builds.
+	buildBlockWithContext func(context.Context) (snowman.Block, error)
 
 	// getStatus returns the status of the block
 	getStatus func(snowman.Block) (choices.Status, error)
@@ -60,6 +66,9 @@ type Config struct {
 	UnmarshalBlock     func([]byte) (snowman.Block, error)
 	BuildBlock         func() (snowman.Block, error)
 	GetBlockIDAtHeight func(uint64) (ids.ID, error)
+
+	// BuildBlockWithContext is optional. If nil, builds can't be aborted.
+	BuildBlockWithContext func(context.Context) (snowman.Block, error)
 }
 
 // Block is an interface wrapping the normal snowman.Block interface to be used in
@@ -105,6 +114,7 @@ func (s *State) initialize(config *Config) {
 	s.verifiedBlocks = make(map[ids.ID]*BlockWrapper)
 	s.getBlock = config.GetBlock
 	s.buildBlock = config.BuildBlock
+	s.buildBlockWithContext = config.BuildBlockWithContext
 	s.unmarshalBlock = config.UnmarshalBlock
 	if config.GetBlockIDAtHeight == nil {
 		s.getStatus = func(blk snowman.Block) (choices.Status, error) { return blk.Status(), nil }
@@ -311,7 +321,25 @@ func (s *State) BuildBlock() (snowman.Block, error) {
 	if err != nil {
 		return nil, err
 	}
+	return s.addBuiltBlock(blk)
+}
+
+// BuildBlockWithContext attempts to build a new internal Block like
+// BuildBlock, aborting the build once [ctx] is done. If the VM can't abort
+// builds, block.ErrAbortableVMNotImplemented is returned.
+func (s *State) BuildBlockWithContext(ctx context.Context) (snowman.Block, error) {
+	if s.buildBlockWithContext == nil {
+		return nil, block.ErrAbortableVMNotImplemented
+	}
+	blk, err := s.buildBlockWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.addBuiltBlock(blk)
+}
 
+// addBuiltBlock wraps the newly built [blk] and adds it to the correct cache
+func (s *State) addBuiltBlock(blk snowman.Block) (snowman.Block, error) {
 	blkID := blk.ID()
 	// Defensive: buildBlock should not return a block that has already been verified.
 	// If it does, make sure to return the existing reference to the block.
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/encdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/ed25519fx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
@@ -23,6 +24,15 @@ var (
 	// this user controls
 	addressesKey = ids.Empty[:]
 
+	// Key in the database whose corresponding value is the list of addresses
+	// this user controls with ed25519 keys
+	ed25519AddressesKey = []byte("ed25519Addresses")
+
+	// Prefix of the keys in the database whose corresponding values are the
+	// ed25519 private keys. A secp256k1 private key is keyed by its address
+	// alone, so that the two key types can't collide.
+	ed25519KeyPrefix = []byte("ed25519Key")
+
 	errMaxAddresses = fmt.Errorf("keystore user has reached its limit of %d addresses", maxKeystoreAddresses)
 
 	_ User = &user{}
@@ -39,11 +49,22 @@ type User interface {
 
 	// GetKey returns the private key that controls the given address
 	GetKey(address ids.ShortID) (*crypto.PrivateKeySECP256K1R, error)
+
+	// Get the addresses controlled by the ed25519 keys of this user
+	GetEd25519Addresses() ([]ids.ShortID, error)
+
+	// PutEd25519Keys persists [privKeys]
+	PutEd25519Keys(privKeys ...*crypto.PrivateKeyED25519) error
+
+	// GetEd25519Key returns the ed25519 private key that controls the given
+	// address
+	GetEd25519Key(address ids.ShortID) (*crypto.PrivateKeyED25519, error)
 }
 
 type user struct {
-	factory crypto.FactorySECP256K1R
-	db      *encdb.Database
+	factory        crypto.FactorySECP256K1R
+	ed25519Factory crypto.FactoryED25519
+	db             *encdb.Database
 }
 
 // NewUserFromKeystore tracks a keystore user from the provided keystore
@@ -60,9 +81,16 @@ func NewUserFromDB(db *encdb.Database) User {
 	return &user{db: db}
 }
 
-func (u *user) GetAddresses() ([]ids.ShortID, error) {
+func (u *user) GetAddresses() ([]ids.ShortID, error) { return u.getAddresses(addressesKey) }
+
+func (u *user) GetEd25519Addresses() ([]ids.ShortID, error) {
+	return u.getAddresses(ed25519AddressesKey)
+}
+
+// getAddresses returns the list of addresses stored at [key]
+func (u *user) getAddresses(key []byte) ([]ids.ShortID, error) {
 	// Get user's addresses
-	addressBytes, err := u.db.Get(addressesKey)
+	addressBytes, err := u.db.Get(key)
 	if err == database.ErrNotFound {
 		// If user has no addresses, return empty list
 		return nil, nil
@@ -77,10 +105,28 @@ func (u *user) GetAddresses() ([]ids.ShortID, error) {
 }
 
 func (u *user) PutKeys(privKeys ...*crypto.PrivateKeySECP256K1R) error {
-	toStore := make([]*crypto.PrivateKeySECP256K1R, 0, len(privKeys))
+	keys := make([]crypto.PrivateKey, len(privKeys))
+	for i, privKey := range privKeys {
+		keys[i] = privKey
+	}
+	return u.putKeys(addressesKey, ids.ShortID.Bytes, keys)
+}
+
+func (u *user) PutEd25519Keys(privKeys ...*crypto.PrivateKeyED25519) error {
+	keys := make([]crypto.PrivateKey, len(privKeys))
+	for i, privKey := range privKeys {
+		keys[i] = privKey
+	}
+	return u.putKeys(ed25519AddressesKey, ed25519Key, keys)
+}
+
+// putKeys persists each of [privKeys] at the key [keyOf] its address, and adds
+// the addresses to the list stored at [addrsKey]
+func (u *user) putKeys(addrsKey []byte, keyOf func(ids.ShortID) []byte, privKeys []crypto.PrivateKey) error {
+	toStore := make([]crypto.PrivateKey, 0, len(privKeys))
 	for _, privKey := range privKeys {
 		address := privKey.PublicKey().Address() // address the privKey controls
-		hasAddress, err := u.db.Has(address.Bytes())
+		hasAddress, err := u.db.Has(keyOf(address))
 		if err != nil {
 			return err
 		}
@@ -94,7 +140,7 @@ func (u *user) PutKeys(privKeys ...*crypto.PrivateKeySECP256K1R) error {
 		return nil
 	}
 
-	addresses, err := u.GetAddresses()
+	addresses, err := u.getAddresses(addrsKey)
 	if err != nil {
 		return err
 	}
@@ -106,7 +152,7 @@ func (u *user) PutKeys(privKeys ...*crypto.PrivateKeySECP256K1R) error {
 	for _, privKey := range toStore {
 		address := privKey.PublicKey().Address() // address the privKey controls
 		// Address --> private key
-		if err := u.db.Put(address.Bytes(), privKey.Bytes()); err != nil {
+		if err := u.db.Put(keyOf(address), privKey.Bytes()); err != nil {
 			return err
 		}
 		addresses = append(addresses, address)
@@ -116,7 +162,7 @@ func (u *user) PutKeys(privKeys ...*crypto.PrivateKeySECP256K1R) error {
 	if err != nil {
 		return err
 	}
-	return u.db.Put(addressesKey, addressBytes)
+	return u.db.Put(addrsKey, addressBytes)
 }
 
 func (u *user) GetKey(address ids.ShortID) (*crypto.PrivateKeySECP256K1R, error) {
@@ -135,8 +181,33 @@ func (u *user) GetKey(address ids.ShortID) (*crypto.PrivateKeySECP256K1R, error)
 	return sk, nil
 }
 
+func (u *user) GetEd25519Key(address ids.ShortID) (*crypto.PrivateKeyED25519, error) {
+	bytes, err := u.db.Get(ed25519Key(address))
+	if err != nil {
+		return nil, err
+	}
+	skIntf, err := u.ed25519Factory.ToPrivateKey(bytes)
+	if err != nil {
+		return nil, err
+	}
+	sk, ok := skIntf.(*crypto.PrivateKeyED25519)
+	if !ok {
+		return nil, fmt.Errorf("expected private key to be type *crypto.PrivateKeyED25519 but is type %T", skIntf)
+	}
+	return sk, nil
+}
+
 func (u *user) Close() error { return u.db.Close() }
 
+// ed25519Key returns the key in the database of the ed25519 private key that
+// controls [address]
+func ed25519Key(address ids.ShortID) []byte {
+	key := make([]byte, len(ed25519KeyPrefix)+len(address))
+	copy(key, ed25519KeyPrefix)
+	copy(key[len(ed25519KeyPrefix):], address[:])
+	return key
+}
+
 // Create and store a new key that will be controlled by this user.
 func NewKey(u User) (*crypto.PrivateKeySECP256K1R, error) {
 	keys, err := NewKeys(u, 1)
@@ -193,3 +264,46 @@ func GetKeychain(u User, addresses ids.ShortSet) (*secp256k1fx.Keychain, error)
 	}
 	return kc, nil
 }
+
+// Create and store a new ed25519 key that will be controlled by this user.
+func NewEd25519Key(u User) (*crypto.PrivateKeyED25519, error) {
+	factory := crypto.FactoryED25519{}
+	skIntf, err := factory.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	sk, ok := skIntf.(*crypto.PrivateKeyED25519)
+	if !ok {
+		return nil, fmt.Errorf("expected private key to be type *crypto.PrivateKeyED25519 but is type %T", skIntf)
+	}
+	return sk, u.PutEd25519Keys(sk)
+}
+
+// GetEd25519Keychain returns a new keychain of the ed25519 keys of the [user].
+// If [addresses] is non-empty it fetches only the keys in addresses. If a key
+// is missing, it will be ignored.
+// If [addresses] is empty, then it will create a keychain using every ed25519
+// address in the provided [user].
+func GetEd25519Keychain(u User, addresses ids.ShortSet) (*ed25519fx.Keychain, error) {
+	addrsList := addresses.List()
+	if len(addrsList) == 0 {
+		var err error
+		addrsList, err = u.GetEd25519Addresses()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	kc := ed25519fx.NewKeychain()
+	for _, addr := range addrsList {
+		sk, err := u.GetEd25519Key(addr)
+		if err == database.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("problem retrieving private key for address %s: %w", addr, err)
+		}
+		kc.Add(sk)
+	}
+	return kc, nil
+}
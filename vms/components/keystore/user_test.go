@@ -86,3 +86,45 @@ func TestUser(t *testing.T) {
 	assert.Len(savedKeychain.Keys, 1, "key should have been added")
 	assert.Equal(sk.Bytes(), savedKeychain.Keys[0].Bytes(), "wrong key returned")
 }
+
+func TestUserEd25519Keys(t *testing.T) {
+	assert := assert.New(t)
+
+	db, err := encdb.New([]byte(testPassword), memdb.New())
+	assert.NoError(err)
+
+	u := NewUserFromDB(db)
+
+	addresses, err := u.GetEd25519Addresses()
+	assert.NoError(err)
+	assert.Empty(addresses, "new user shouldn't have ed25519 address")
+
+	sk, err := NewEd25519Key(u)
+	assert.NoError(err)
+
+	// Putting the same key multiple times should be a noop
+	err = u.PutEd25519Keys(sk)
+	assert.NoError(err)
+
+	addr := sk.PublicKey().Address()
+
+	savedSk, err := u.GetEd25519Key(addr)
+	assert.NoError(err)
+	assert.Equal(sk.Bytes(), savedSk.Bytes(), "wrong key returned")
+
+	addresses, err = u.GetEd25519Addresses()
+	assert.NoError(err)
+	assert.Equal([]ids.ShortID{addr}, addresses)
+
+	// ed25519 keys are kept apart from the secp256k1 keys
+	addresses, err = u.GetAddresses()
+	assert.NoError(err)
+	assert.Empty(addresses)
+	_, err = u.GetKey(addr)
+	assert.Error(err)
+
+	savedKeychain, err := GetEd25519Keychain(u, nil)
+	assert.NoError(err)
+	assert.Len(savedKeychain.Keys, 1, "key should have been added")
+	assert.Equal(sk.Bytes(), savedKeychain.Keys[0].Bytes(), "wrong key returned")
+}
@@ -19,6 +19,10 @@ var (
 // SameSubnet verifies that the provided [ctx] was provided to a chain in the
 // same subnet as [peerChainID], but not the same chain. If this verification
 // fails, a non-nil error will be returned.
+//
+// The peer chain must run on this node, so any two chains of a subnet this
+// node validates, and not only the chains of the primary network, may exchange
+// atomic requests.
 func SameSubnet(ctx *snow.Context, peerChainID ids.ID) error {
 	if peerChainID == ctx.ChainID {
 		return errSameChainID
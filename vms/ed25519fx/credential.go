@@ -0,0 +1,77 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519fx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+var errNilCredential = errors.New("nil credential")
+
+const (
+	defaultEncoding = formatting.Hex
+)
+
+// Signature is an ed25519 signature along with the public key that verifies
+// it. Unlike a secp256k1 public key, an ed25519 public key can't be recovered
+// from the signature, so the credential carries it.
+type Signature struct {
+	PublicKey [crypto.ED25519PKLen]byte  `serialize:"true"`
+	Sig       [crypto.ED25519SigLen]byte `serialize:"true"`
+}
+
+// MarshalJSON marshals [sig] to JSON
+// The public key and the signature are created using the hex formatter
+func (sig *Signature) MarshalJSON() ([]byte, error) {
+	publicKeyStr, err := formatting.EncodeWithoutChecksum(defaultEncoding, sig.PublicKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't convert public key to string: %w", err)
+	}
+	sigStr, err := formatting.EncodeWithoutChecksum(defaultEncoding, sig.Sig[:])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't convert signature to string: %w", err)
+	}
+	jsonFieldMap := map[string]interface{}{
+		"publicKey": publicKeyStr,
+		"signature": sigStr,
+	}
+	return json.Marshal(jsonFieldMap)
+}
+
+// Credential proves that the owners of an output assent to a tx. There's one
+// signature for each of the signature indices of the input.
+type Credential struct {
+	Sigs []Signature `serialize:"true" json:"signatures"`
+}
+
+func (cr *Credential) Verify() error {
+	switch {
+	case cr == nil:
+		return errNilCredential
+	default:
+		return nil
+	}
+}
+
+// Sign returns the credential of [keys] over the [hash] of the unsigned bytes
+// of a tx
+func Sign(hash []byte, keys []*crypto.PrivateKeyED25519) (*Credential, error) {
+	cred := &Credential{
+		Sigs: make([]Signature, len(keys)),
+	}
+	for i, key := range keys {
+		sig, err := key.SignHash(hash)
+		if err != nil {
+			return nil, err
+		}
+		copy(cred.Sigs[i].PublicKey[:], key.PublicKey().Bytes())
+		copy(cred.Sigs[i].Sig[:], sig)
+	}
+	return cred, nil
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519fx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+func TestCredentialVerifyNil(t *testing.T) {
+	cred := (*Credential)(nil)
+	assert.ErrorIs(t, cred.Verify(), errNilCredential)
+}
+
+func TestCredentialSerialize(t *testing.T) {
+	assert := assert.New(t)
+
+	c := linearcodec.NewDefault()
+	m := codec.NewDefaultManager()
+	assert.NoError(m.RegisterCodec(0, c))
+
+	cred := sign(t, newTestKey(t), newTestKey(t))
+	credBytes, err := m.Marshal(0, cred)
+	assert.NoError(err)
+	// Codec version, number of signatures, and the public key and signature
+	// of each signature
+	assert.Len(credBytes, 2+4+2*(32+64))
+
+	parsed := &Credential{}
+	_, err = m.Unmarshal(credBytes, parsed)
+	assert.NoError(err)
+	assert.Equal(cred, parsed)
+}
+
+func TestCredentialMarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	key := newTestKey(t)
+	cred := sign(t, key)
+	credJSON, err := json.Marshal(cred)
+	assert.NoError(err)
+
+	publicKey, err := formatting.EncodeWithoutChecksum(formatting.Hex, key.PublicKey().Bytes())
+	assert.NoError(err)
+	sig, err := formatting.EncodeWithoutChecksum(formatting.Hex, cred.Sigs[0].Sig[:])
+	assert.NoError(err)
+	assert.JSONEq(`{"signatures":[{"publicKey":"`+publicKey+`","signature":"`+sig+`"}]}`, string(credJSON))
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519fx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errWrongTxType                    = errors.New("wrong tx type")
+	errWrongUTXOType                  = errors.New("wrong utxo type")
+	errWrongInputType                 = errors.New("wrong input type")
+	errWrongCredentialType            = errors.New("wrong credential type")
+	errWrongOwnerType                 = errors.New("wrong owner type")
+	errCantOperate                    = errors.New("ed25519 outputs can only be transferred")
+	errTimelocked                     = errors.New("output is time locked")
+	errTooManySigners                 = errors.New("input has more signers than expected")
+	errTooFewSigners                  = errors.New("input has less signers than expected")
+	errInputOutputIndexOutOfBounds    = errors.New("input referenced a nonexistent address in the output")
+	errInputCredentialSignersMismatch = errors.New("input expected a different number of signers than provided in the credential")
+	errWrongSigner                    = errors.New("signature isn't from the expected owner")
+	errInvalidSignature               = errors.New("invalid signature")
+)
+
+// Fx supports outputs owned by ed25519 keys. The owners of a TransferOutput
+// are the addresses of ed25519 public keys, and they spend it by signing the
+// tx with the keys.
+type Fx struct {
+	secp256k1fx.Fx
+
+	factory      crypto.FactoryED25519
+	bootstrapped bool
+}
+
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	log := fx.VM.Logger()
+	log.Debug("initializing ed25519 fx")
+
+	c := fx.VM.CodecRegistry()
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&TransferInput{}),
+		c.RegisterType(&TransferOutput{}),
+		c.RegisterType(&Credential{}),
+	)
+	return errs.Err
+}
+
+func (fx *Fx) Bootstrapped() error { fx.bootstrapped = true; return fx.Fx.Bootstrapped() }
+
+// VerifyPermission returns nil iff [credIntf] proves that [controlGroup] assents to [txIntf]
+func (fx *Fx) VerifyPermission(txIntf, inIntf, credIntf, ownerIntf interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	in, ok := inIntf.(*secp256k1fx.Input)
+	if !ok {
+		return errWrongInputType
+	}
+	cred, ok := credIntf.(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+	owner, ok := ownerIntf.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return errWrongOwnerType
+	}
+	if err := verify.All(in, cred, owner); err != nil {
+		return err
+	}
+	return fx.VerifyCredentials(tx, in, cred, owner)
+}
+
+func (fx *Fx) VerifyOperation(_, _, _ interface{}, _ []interface{}) error { return errCantOperate }
+
+func (fx *Fx) VerifyTransfer(txIntf, inIntf, credIntf, utxoIntf interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	in, ok := inIntf.(*TransferInput)
+	if !ok {
+		return errWrongInputType
+	}
+	cred, ok := credIntf.(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+	out, ok := utxoIntf.(*TransferOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	if err := verify.All(out, in, cred); err != nil {
+		return err
+	} else if out.Amt != in.Amt {
+		return fmt.Errorf("utxo amount and input amount should be same but are %d and %d", out.Amt, in.Amt)
+	}
+	return fx.VerifyCredentials(tx, &in.Input, cred, &out.OutputOwners)
+}
+
+// VerifyCredentials ensures that the output can be spent by the input with the
+// credential. A nil return values means the output can be spent.
+func (fx *Fx) VerifyCredentials(tx secp256k1fx.Tx, in *secp256k1fx.Input, cred *Credential, out *secp256k1fx.OutputOwners) error {
+	numSigs := len(in.SigIndices)
+	switch {
+	case out.Locktime > fx.VM.Clock().Unix():
+		return errTimelocked
+	case out.Threshold < uint32(numSigs):
+		return errTooManySigners
+	case out.Threshold > uint32(numSigs):
+		return errTooFewSigners
+	case numSigs != len(cred.Sigs):
+		return errInputCredentialSignersMismatch
+	}
+
+	txHash := hashing.ComputeHash256(tx.UnsignedBytes())
+	for i, index := range in.SigIndices {
+		// Make sure the input references an address that exists
+		if index >= uint32(len(out.Addrs)) {
+			return errInputOutputIndexOutOfBounds
+		}
+		// Make sure each signature in the signature list is from an owner of
+		// the output being consumed. The public key is checked even during
+		// bootstrapping, as it's as cheap as checking a secp256k1 address.
+		sig := cred.Sigs[i]
+		pk, err := fx.factory.ToPublicKey(sig.PublicKey[:])
+		if err != nil {
+			return err
+		}
+		if expectedAddress := out.Addrs[index]; expectedAddress != pk.Address() {
+			return fmt.Errorf("%w: expected signature from %s but got from %s",
+				errWrongSigner,
+				expectedAddress,
+				pk.Address())
+		}
+		// Disable signature verification during bootstrapping
+		if fx.bootstrapped && !pk.VerifyHash(txHash, sig.Sig[:]) {
+			return errInvalidSignature
+		}
+	}
+
+	return nil
+}
+
+// CreateOutput creates a new output with the provided control group worth
+// the specified amount
+func (fx *Fx) CreateOutput(amount uint64, ownerIntf interface{}) (interface{}, error) {
+	owner, ok := ownerIntf.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return nil, errWrongOwnerType
+	}
+	if err := owner.Verify(); err != nil {
+		return nil, err
+	}
+	return &TransferOutput{TransferOutput: secp256k1fx.TransferOutput{
+		Amt:          amount,
+		OutputOwners: *owner,
+	}}, nil
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519fx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	txBytes = []byte{0, 1, 2, 3, 4, 5}
+	now     = time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+)
+
+func newTestFx(t *testing.T) *Fx {
+	vm := secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	vm.CLK.Set(now)
+
+	fx := &Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+	return fx
+}
+
+func newTestKey(t *testing.T) *crypto.PrivateKeyED25519 {
+	factory := crypto.FactoryED25519{}
+	key, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key.(*crypto.PrivateKeyED25519)
+}
+
+func sign(t *testing.T, keys ...*crypto.PrivateKeyED25519) *Credential {
+	cred, err := Sign(hashing.ComputeHash256(txBytes), keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cred
+}
+
+func TestFxInitialize(t *testing.T) {
+	newTestFx(t)
+
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyTransfer(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	key0 := newTestKey(t)
+	key1 := newTestKey(t)
+	addrs := []ids.ShortID{
+		key0.PublicKey().Address(),
+		ids.GenerateTestShortID(),
+		key1.PublicKey().Address(),
+	}
+	ids.SortShortIDs(addrs)
+
+	// The signers are ordered by their positions among the sorted owners
+	var (
+		sigIndices    []uint32
+		first, second *crypto.PrivateKeyED25519
+	)
+	for i, addr := range addrs {
+		switch addr {
+		case key0.PublicKey().Address():
+			sigIndices = append(sigIndices, uint32(i))
+			if first == nil {
+				first, second = key0, key1
+			}
+		case key1.PublicKey().Address():
+			sigIndices = append(sigIndices, uint32(i))
+			if first == nil {
+				first, second = key1, key0
+			}
+		}
+	}
+
+	out := &TransferOutput{TransferOutput: secp256k1fx.TransferOutput{
+		Amt: 1,
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 2,
+			Addrs:     addrs,
+		},
+	}}
+	in := &TransferInput{TransferInput: secp256k1fx.TransferInput{
+		Amt:   1,
+		Input: secp256k1fx.Input{SigIndices: sigIndices},
+	}}
+	assert.NoError(fx.VerifyTransfer(tx, in, sign(t, first, second), out))
+
+	// The signatures must be from the referenced owners, in order
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, sign(t, second, first), out), errWrongSigner)
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, sign(t, first, newTestKey(t)), out), errWrongSigner)
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, sign(t, first), out), errInputCredentialSignersMismatch)
+
+	// The signatures must be over the tx
+	cred := sign(t, first, second)
+	cred.Sigs[1].Sig[0]++
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, cred, out), errInvalidSignature)
+	assert.ErrorIs(fx.VerifyTransfer(&secp256k1fx.TestTx{Bytes: []byte{1}}, in, sign(t, first, second), out), errInvalidSignature)
+
+	// Signatures aren't verified while bootstrapping, but the signers are
+	fx.bootstrapped = false
+	assert.NoError(fx.VerifyTransfer(tx, in, cred, out))
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, sign(t, second, first), out), errWrongSigner)
+	fx.bootstrapped = true
+
+	out.Locktime = uint64(now.Unix()) + 1
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, sign(t, first, second), out), errTimelocked)
+	out.Locktime = 0
+
+	in.SigIndices = []uint32{sigIndices[0], 3}
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, sign(t, first, second), out), errInputOutputIndexOutOfBounds)
+	in.SigIndices = []uint32{sigIndices[0]}
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, sign(t, first), out), errTooFewSigners)
+	in.SigIndices = sigIndices
+
+	in.Amt = 2
+	assert.Error(fx.VerifyTransfer(tx, in, sign(t, first, second), out))
+	in.Amt = 1
+
+	assert.ErrorIs(fx.VerifyTransfer(nil, in, sign(t, first, second), out), errWrongTxType)
+	assert.ErrorIs(fx.VerifyTransfer(tx, &in.TransferInput, sign(t, first, second), out), errWrongInputType)
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, &secp256k1fx.Credential{}, out), errWrongCredentialType)
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, sign(t, first, second), &out.TransferOutput), errWrongUTXOType)
+}
+
+func TestFxVerifyOperation(t *testing.T) {
+	fx := newTestFx(t)
+	assert.ErrorIs(t, fx.VerifyOperation(nil, nil, nil, nil), errCantOperate)
+}
+
+func TestFxVerifyPermission(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	key := newTestKey(t)
+	owners := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{key.PublicKey().Address()},
+	}
+	in := &secp256k1fx.Input{SigIndices: []uint32{0}}
+	assert.NoError(fx.VerifyPermission(tx, in, sign(t, key), owners))
+	assert.ErrorIs(fx.VerifyPermission(tx, in, sign(t, newTestKey(t)), owners), errWrongSigner)
+	assert.ErrorIs(fx.VerifyPermission(tx, in, &secp256k1fx.Credential{}, owners), errWrongCredentialType)
+}
+
+func TestFxCreateOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	owners := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+	}
+	out, err := fx.CreateOutput(5, owners)
+	assert.NoError(err)
+	assert.Equal(&TransferOutput{TransferOutput: secp256k1fx.TransferOutput{
+		Amt:          5,
+		OutputOwners: *owners,
+	}}, out)
+
+	_, err = fx.CreateOutput(5, &secp256k1fx.OutputOwners{Threshold: 1})
+	assert.Error(err)
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519fx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var errCantSpend = errors.New("unable to spend this UTXO")
+
+// Keychain is a collection of ed25519 keys that can be used to spend outputs
+type Keychain struct {
+	factory        *crypto.FactoryED25519
+	addrToKeyIndex map[ids.ShortID]int
+
+	// These can be used to iterate over. However, they should not be modified externally.
+	Addrs ids.ShortSet
+	Keys  []*crypto.PrivateKeyED25519
+}
+
+// NewKeychain returns a new keychain containing [keys]
+func NewKeychain(keys ...*crypto.PrivateKeyED25519) *Keychain {
+	kc := &Keychain{
+		factory:        &crypto.FactoryED25519{},
+		addrToKeyIndex: make(map[ids.ShortID]int),
+	}
+	for _, key := range keys {
+		kc.Add(key)
+	}
+	return kc
+}
+
+// Add a new key to the key chain
+func (kc *Keychain) Add(key *crypto.PrivateKeyED25519) {
+	addr := key.PublicKey().Address()
+	if _, ok := kc.addrToKeyIndex[addr]; !ok {
+		kc.addrToKeyIndex[addr] = len(kc.Keys)
+		kc.Keys = append(kc.Keys, key)
+		kc.Addrs.Add(addr)
+	}
+}
+
+// Get the key that controls [id], if it's in the keychain
+func (kc Keychain) Get(id ids.ShortID) (*crypto.PrivateKeyED25519, bool) {
+	if i, ok := kc.addrToKeyIndex[id]; ok {
+		return kc.Keys[i], true
+	}
+	return nil, false
+}
+
+// Addresses returns a list of addresses this keychain manages
+func (kc Keychain) Addresses() ids.ShortSet { return kc.Addrs }
+
+// New returns a newly generated private key
+func (kc *Keychain) New() (*crypto.PrivateKeyED25519, error) {
+	skGen, err := kc.factory.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	sk := skGen.(*crypto.PrivateKeyED25519)
+	kc.Add(sk)
+	return sk, nil
+}
+
+// Spend attempts to create an input
+func (kc *Keychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable, []*crypto.PrivateKeyED25519, error) {
+	switch out := out.(type) {
+	case *TransferOutput:
+		if sigIndices, keys, able := kc.Match(&out.OutputOwners, time); able {
+			return &TransferInput{TransferInput: secp256k1fx.TransferInput{
+				Amt: out.Amt,
+				Input: secp256k1fx.Input{
+					SigIndices: sigIndices,
+				},
+			}}, keys, nil
+		}
+		return nil, nil, errCantSpend
+	}
+	return nil, nil, fmt.Errorf("can't spend UTXO because it is unexpected type %T", out)
+}
+
+// Match attempts to match a list of addresses up to the provided threshold
+func (kc *Keychain) Match(owners *secp256k1fx.OutputOwners, time uint64) ([]uint32, []*crypto.PrivateKeyED25519, bool) {
+	if time < owners.Locktime {
+		return nil, nil, false
+	}
+	sigs := make([]uint32, 0, owners.Threshold)
+	keys := make([]*crypto.PrivateKeyED25519, 0, owners.Threshold)
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(keys)) < owners.Threshold; i++ {
+		if key, exists := kc.Get(owners.Addrs[i]); exists {
+			sigs = append(sigs, i)
+			keys = append(keys, key)
+		}
+	}
+	return sigs, keys, uint32(len(keys)) == owners.Threshold
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519fx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestKeychainSpend(t *testing.T) {
+	assert := assert.New(t)
+
+	kc := NewKeychain()
+	key, err := kc.New()
+	assert.NoError(err)
+	addrs := kc.Addresses()
+	assert.True(addrs.Contains(key.PublicKey().Address()))
+
+	got, ok := kc.Get(key.PublicKey().Address())
+	assert.True(ok)
+	assert.Equal(key, got)
+	_, ok = kc.Get(ids.GenerateTestShortID())
+	assert.False(ok)
+
+	out := &TransferOutput{TransferOutput: secp256k1fx.TransferOutput{
+		Amt: 7,
+		OutputOwners: secp256k1fx.OutputOwners{
+			Locktime:  10,
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.GenerateTestShortID(),
+				key.PublicKey().Address(),
+			},
+		},
+	}}
+
+	// Locked outputs can't be spent
+	_, _, err = kc.Spend(out, 9)
+	assert.ErrorIs(err, errCantSpend)
+
+	in, keys, err := kc.Spend(out, 10)
+	assert.NoError(err)
+	assert.Equal(&TransferInput{TransferInput: secp256k1fx.TransferInput{
+		Amt:   7,
+		Input: secp256k1fx.Input{SigIndices: []uint32{1}},
+	}}, in)
+	assert.Len(keys, 1)
+	assert.Equal(key, keys[0])
+
+	// Outputs of other fxs can't be spent with ed25519 keys
+	_, _, err = kc.Spend(&out.TransferOutput, 10)
+	assert.Error(err)
+}
@@ -0,0 +1,14 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519fx
+
+import (
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// TransferInput spends a TransferOutput. Its signature indices reference the
+// owners whose ed25519 keys sign the credential of the input.
+type TransferInput struct {
+	secp256k1fx.TransferInput `serialize:"true"`
+}
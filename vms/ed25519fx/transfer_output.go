@@ -0,0 +1,14 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519fx
+
+import (
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// TransferOutput is an amount that its owners spend by signing with their
+// ed25519 keys. The owners are the addresses of the ed25519 public keys.
+type TransferOutput struct {
+	secp256k1fx.TransferOutput `serialize:"true"`
+}
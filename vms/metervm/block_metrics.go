@@ -24,11 +24,13 @@ type blockMetrics struct {
 	accept,
 	reject,
 	getAncestors,
-	batchedParseBlock metric.Averager
+	batchedParseBlock,
+	verifyChain metric.Averager
 }
 
 func (m *blockMetrics) Initialize(
 	supportsBatchedFetching bool,
+	supportsChainVerification bool,
 	namespace string,
 	reg prometheus.Registerer,
 ) error {
@@ -50,5 +52,8 @@ func (m *blockMetrics) Initialize(
 		m.getAncestors = newAverager(namespace, "get_ancestors", reg, &errs)
 		m.batchedParseBlock = newAverager(namespace, "batched_parse_block", reg, &errs)
 	}
+	if supportsChainVerification {
+		m.verifyChain = newAverager(namespace, "verify_chain", reg, &errs)
+	}
 	return errs.Err
 }
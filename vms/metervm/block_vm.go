@@ -42,7 +42,8 @@ func (vm *blockVM) Initialize(
 ) error {
 	registerer := prometheus.NewRegistry()
 	_, supportsBatchedFetching := vm.ChainVM.(block.BatchedChainVM)
-	if err := vm.blockMetrics.Initialize(supportsBatchedFetching, "", registerer); err != nil {
+	_, supportsChainVerification := vm.ChainVM.(block.ChainVerifierVM)
+	if err := vm.blockMetrics.Initialize(supportsBatchedFetching, supportsChainVerification, "", registerer); err != nil {
 		return err
 	}
 
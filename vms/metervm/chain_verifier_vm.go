@@ -0,0 +1,32 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.ChainVerifierVM = &blockVM{}
+
+func (vm *blockVM) VerifyChain(blks []snowman.Block) error {
+	cVM, ok := vm.ChainVM.(block.ChainVerifierVM)
+	if !ok {
+		return block.ErrChainVerifierVMNotImplemented
+	}
+
+	innerBlks := make([]snowman.Block, len(blks))
+	for i, blk := range blks {
+		if meteredBlk, ok := blk.(*meterBlock); ok {
+			blk = meteredBlk.Block
+		}
+		innerBlks[i] = blk
+	}
+
+	start := vm.clock.Time()
+	err := cVM.VerifyChain(innerBlks)
+	end := vm.clock.Time()
+	vm.blockMetrics.verifyChain.Observe(float64(end.Sub(start)))
+	return err
+}
@@ -0,0 +1,14 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.PreparingChainVM = &blockVM{}
+
+func (vm *blockVM) PrepareBlock(blkBytes []byte) {
+	block.PrepareBlock(vm.ChainVM, blkBytes)
+}
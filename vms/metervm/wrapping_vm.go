@@ -0,0 +1,20 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.WrappingChainVM = &blockVM{}
+
+func (vm *blockVM) GetWrappingBlock(blkID ids.ID) (snowman.Block, error) {
+	wVM, ok := vm.ChainVM.(block.WrappingChainVM)
+	if !ok {
+		return nil, block.ErrWrappingVMNotImplemented
+	}
+	return wVM.GetWrappingBlock(blkID)
+}
@@ -190,6 +190,20 @@ func (mr *MockManagerMockRecorder) RegisterFactory(vmID, factory interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFactory", reflect.TypeOf((*MockManager)(nil).RegisterFactory), vmID, factory)
 }
 
+// RemoveAlias mocks base method.
+func (m *MockManager) RemoveAlias(alias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAlias", alias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAlias indicates an expected call of RemoveAlias.
+func (mr *MockManagerMockRecorder) RemoveAlias(alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAlias", reflect.TypeOf((*MockManager)(nil).RemoveAlias), alias)
+}
+
 // RemoveAliases mocks base method.
 func (m *MockManager) RemoveAliases(id ids.ID) {
 	m.ctrl.T.Helper()
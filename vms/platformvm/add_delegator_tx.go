@@ -246,6 +246,11 @@ func (tx *UnsignedAddDelegatorTx) Execute(
 			return nil, nil, errOverDelegated
 		}
 
+		// Ensure the stake of the subnet doesn't change too quickly
+		if err := vm.verifyChurn(currentStakers, pendingStakers, currentTimestamp, constants.PrimaryNetworkID, tx.Validator.Wght, tx.StartTime(), tx.EndTime()); err != nil {
+			return nil, nil, err
+		}
+
 		// Verify the flowcheck
 		if err := vm.semanticVerifySpend(parentState, tx, tx.Ins, outs, stx.Creds, vm.AddStakerTxFee, vm.ctx.AVAXAssetID); err != nil {
 			return nil, nil, fmt.Errorf("failed semanticVerifySpend: %w", err)
@@ -210,6 +210,11 @@ func (tx *UnsignedAddSubnetValidatorTx) Execute(
 			return nil, nil, err
 		}
 
+		// Ensure the stake of the subnet doesn't change too quickly
+		if err := vm.verifyChurn(currentStakers, pendingStakers, currentTimestamp, tx.Validator.Subnet, tx.Validator.Wght, tx.StartTime(), tx.EndTime()); err != nil {
+			return nil, nil, err
+		}
+
 		// Verify the flowcheck
 		if err := vm.semanticVerifySpend(parentState, tx, tx.Ins, tx.Outs, baseTxCreds, vm.TxFee, vm.ctx.AVAXAssetID); err != nil {
 			return nil, nil, err
@@ -219,6 +219,11 @@ func (tx *UnsignedAddValidatorTx) Execute(
 			)
 		}
 
+		// Ensure the stake of the subnet doesn't change too quickly
+		if err := vm.verifyChurn(currentStakers, pendingStakers, currentTimestamp, constants.PrimaryNetworkID, tx.Validator.Wght, tx.StartTime(), tx.EndTime()); err != nil {
+			return nil, nil, err
+		}
+
 		// Verify the flowcheck
 		if err := vm.semanticVerifySpend(parentState, tx, tx.Ins, outs, stx.Creds, vm.AddStakerTxFee, vm.ctx.AVAXAssetID); err != nil {
 			return nil, nil, fmt.Errorf("failed semanticVerifySpend: %w", err)
@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bloom"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+const (
+	// addressFilterFalsePositiveRate is the probability that the filter of a
+	// block reports an address the block doesn't involve
+	addressFilterFalsePositiveRate = 0.01
+	maxAddressFilterBytes          = 64 * units.KiB
+)
+
+var errNoAddressFilter = errors.New("no address filter is indexed at this height")
+
+// BlockAddressFilter is the filter of the addresses involved in an accepted
+// block
+type BlockAddressFilter struct {
+	Height  uint64
+	BlockID ids.ID
+	// Filter is nil if the block doesn't involve any address
+	Filter bloom.Filter
+}
+
+// Contains returns true if [addr] may be involved in the block. False
+// positives are possible, false negatives aren't.
+func (f *BlockAddressFilter) Contains(addr ids.ShortID) bool {
+	return f.Filter != nil && f.Filter.Check(addr[:])
+}
+
+// blockAddressFilter returns the bytes of the filter of the addresses [blk]
+// involves, or nil if it doesn't involve any address. Must be called before the
+// state changes of [blk] are applied to the internal state, so that the UTXOs
+// it consumes can still be read.
+func (vm *VM) blockAddressFilter(blk Block) ([]byte, error) {
+	addrs := ids.ShortSet{}
+	for _, tx := range blockTxs(blk) {
+		if err := vm.addTxAddresses(addrs, tx); err != nil {
+			return nil, fmt.Errorf("couldn't get the addresses of tx %s: %w", tx.ID(), err)
+		}
+	}
+	if addrs.Len() == 0 {
+		return nil, nil
+	}
+
+	filter, err := bloom.NewSerializable(uint64(addrs.Len()), addressFilterFalsePositiveRate, maxAddressFilterBytes)
+	if err != nil {
+		return nil, err
+	}
+	for addr := range addrs {
+		addr := addr
+		filter.Add(addr[:])
+	}
+	return filter.Bytes()
+}
+
+// addTxAddresses adds to [addrs] the owners of the UTXOs [tx] consumes and
+// produces and of the stakes and rewards it adds or removes.
+//
+// The owners of imported UTXOs aren't included, as they're only readable from
+// shared memory once the node is bootstrapped.
+func (vm *VM) addTxAddresses(addrs ids.ShortSet, tx *Tx) error {
+	var baseTx *BaseTx
+	switch utx := tx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		baseTx = &utx.BaseTx
+		addOutputAddresses(addrs, utx.Stake)
+		addOwnerAddresses(addrs, utx.RewardsOwner)
+	case *UnsignedAddDelegatorTx:
+		baseTx = &utx.BaseTx
+		addOutputAddresses(addrs, utx.Stake)
+		addOwnerAddresses(addrs, utx.RewardsOwner)
+	case *UnsignedAddSubnetValidatorTx:
+		baseTx = &utx.BaseTx
+	case *UnsignedCreateChainTx:
+		baseTx = &utx.BaseTx
+	case *UnsignedCreateSubnetTx:
+		baseTx = &utx.BaseTx
+		addOwnerAddresses(addrs, utx.Owner)
+	case *UnsignedImportTx:
+		baseTx = &utx.BaseTx
+	case *UnsignedExportTx:
+		baseTx = &utx.BaseTx
+		addOutputAddresses(addrs, utx.ExportedOutputs)
+	case *UnsignedSetDelegationFeeTx:
+		baseTx = &utx.BaseTx
+	case *UnsignedSetSubnetFeeConfigTx:
+		baseTx = &utx.BaseTx
+	case *UnsignedSetSubnetVMConfigTx:
+		baseTx = &utx.BaseTx
+	case *UnsignedRemoveSubnetValidatorTx:
+		baseTx = &utx.BaseTx
+	case *UnsignedRewardValidatorTx:
+		// The stake is returned to, and the reward paid to, the owners
+		// specified by the staker tx
+		stakerTx, _, err := vm.internalState.GetTx(utx.TxID)
+		if err != nil {
+			return fmt.Errorf("couldn't get staker tx %s: %w", utx.TxID, err)
+		}
+		switch staker := stakerTx.UnsignedTx.(type) {
+		case *UnsignedAddValidatorTx:
+			addOutputAddresses(addrs, staker.Stake)
+			addOwnerAddresses(addrs, staker.RewardsOwner)
+		case *UnsignedAddDelegatorTx:
+			addOutputAddresses(addrs, staker.Stake)
+			addOwnerAddresses(addrs, staker.RewardsOwner)
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	addOutputAddresses(addrs, baseTx.Outs)
+	for _, in := range baseTx.Ins {
+		utxo, err := vm.internalState.GetUTXO(in.InputID())
+		if err == database.ErrNotFound {
+			// The UTXO was produced by an earlier tx of the same block, so its
+			// owners were already added
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't get UTXO %s: %w", in.InputID(), err)
+		}
+		addOwnerAddresses(addrs, utxo.Out)
+	}
+	return nil
+}
+
+func addOutputAddresses(addrs ids.ShortSet, outs []*avax.TransferableOutput) {
+	for _, out := range outs {
+		addOwnerAddresses(addrs, out.Out)
+	}
+}
+
+// addOwnerAddresses adds the addresses of [owner] to [addrs], if it has any
+func addOwnerAddresses(addrs ids.ShortSet, owner interface{}) {
+	if lockedOut, ok := owner.(*StakeableLockOut); ok {
+		owner = lockedOut.TransferableOut
+	}
+	addressable, ok := owner.(avax.Addressable)
+	if !ok {
+		return
+	}
+	for _, addrBytes := range addressable.Addresses() {
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			continue
+		}
+		addrs.Add(addr)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bloom"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+func TestGetAddressFilters(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	service := &Service{vm: vm}
+
+	to := ids.GenerateTestShortID()
+	tx, err := vm.newExportTx(
+		defaultTxFee,
+		xChainID,
+		to,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[1].PublicKey().Address(),
+	)
+	assert.NoError(err)
+	assert.NoError(vm.blockBuilder.AddUnverifiedTx(tx))
+
+	blk, err := vm.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(blk.Verify())
+	assert.NoError(blk.Accept())
+
+	reply := GetAddressFiltersReply{}
+	assert.NoError(service.GetAddressFilters(nil, &GetAddressFiltersArgs{
+		Encoding: formatting.Hex,
+	}, &reply))
+	assert.Len(reply.Filters, 2)
+
+	genesisFilter := reply.Filters[0]
+	assert.EqualValues(0, genesisFilter.Height)
+	assert.Empty(genesisFilter.Filter)
+
+	blkFilter := reply.Filters[1]
+	assert.EqualValues(1, blkFilter.Height)
+	assert.Equal(blk.ID(), blkFilter.BlockID)
+	filterBytes, err := formatting.Decode(reply.Encoding, blkFilter.Filter)
+	assert.NoError(err)
+	filter, err := bloom.Parse(filterBytes)
+	assert.NoError(err)
+
+	// The filter contains the owner of the consumed UTXO, the change address
+	// and the recipient of the exported UTXO
+	addrs := []ids.ShortID{
+		keys[0].PublicKey().Address(),
+		keys[1].PublicKey().Address(),
+		to,
+	}
+	for _, addr := range addrs {
+		addr := addr
+		assert.True(filter.Check(addr[:]), "should have contained %s", addr)
+	}
+
+	falsePositives := 0
+	for i := 0; i < 100; i++ {
+		addr := ids.GenerateTestShortID()
+		if filter.Check(addr[:]) {
+			falsePositives++
+		}
+	}
+	assert.Less(falsePositives, 20)
+
+	// Filters start at the requested height and are capped by the limit
+	reply = GetAddressFiltersReply{}
+	assert.NoError(service.GetAddressFilters(nil, &GetAddressFiltersArgs{
+		StartHeight: 1,
+		Encoding:    formatting.Hex,
+	}, &reply))
+	assert.Len(reply.Filters, 1)
+	assert.Equal(blk.ID(), reply.Filters[0].BlockID)
+
+	reply = GetAddressFiltersReply{}
+	assert.NoError(service.GetAddressFilters(nil, &GetAddressFiltersArgs{
+		Limit:    1,
+		Encoding: formatting.Hex,
+	}, &reply))
+	assert.Len(reply.Filters, 1)
+	assert.EqualValues(0, reply.Filters[0].Height)
+
+	reply = GetAddressFiltersReply{}
+	assert.NoError(service.GetAddressFilters(nil, &GetAddressFiltersArgs{
+		StartHeight: 2,
+		Encoding:    formatting.Hex,
+	}, &reply))
+	assert.Empty(reply.Filters)
+}
+
+func TestBlockAddressFilterContains(t *testing.T) {
+	assert := assert.New(t)
+
+	addr := ids.GenerateTestShortID()
+	emptyFilter := BlockAddressFilter{}
+	assert.False(emptyFilter.Contains(addr))
+
+	filter, err := bloom.NewSerializable(1, addressFilterFalsePositiveRate, maxAddressFilterBytes)
+	assert.NoError(err)
+	filter.Add(addr[:])
+	blkFilter := BlockAddressFilter{Filter: filter}
+	assert.True(blkFilter.Contains(addr))
+}
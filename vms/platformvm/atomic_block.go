@@ -118,7 +118,7 @@ func (ab *AtomicBlock) Verify() error {
 	onAccept, err := tx.AtomicExecute(ab.vm, parentState, &ab.Tx)
 	if err != nil {
 		txID := tx.ID()
-		ab.vm.droppedTxCache.Put(txID, err.Error()) // cache tx as dropped
+		ab.vm.markDropped(txID, err.Error())
 		return fmt.Errorf("tx %s failed semantic verification: %w", txID, err)
 	}
 	onAccept.AddTx(&ab.Tx, status.Committed)
@@ -163,7 +163,16 @@ func (ab *AtomicBlock) Accept() error {
 		)
 	}
 
-	if err := tx.AtomicAccept(ab.vm.ctx, batch); err != nil {
+	indexBatches, err := ab.vm.indexImports([]*Tx{&ab.Tx})
+	if err != nil {
+		return fmt.Errorf(
+			"failed to index imports of block %s: %w",
+			blkID,
+			err,
+		)
+	}
+
+	if err := tx.AtomicAccept(ab.vm.ctx, append(indexBatches, batch)...); err != nil {
 		return fmt.Errorf(
 			"failed to atomically accept tx %s in block %s: %w",
 			tx.ID(),
@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -97,19 +98,10 @@ func (m *blockBuilder) AddUnverifiedTx(tx *Tx) error {
 		return nil
 	}
 
-	// Get the preferred block (which we want to build off)
-	preferred, err := m.vm.Preferred()
+	preferredState, err := m.preferredState()
 	if err != nil {
-		return fmt.Errorf("couldn't get preferred block: %w", err)
-	}
-
-	preferredDecision, ok := preferred.(decision)
-	if !ok {
-		// The preferred block should always be a decision block
-		return errInvalidBlockType
+		return err
 	}
-
-	preferredState := preferredDecision.onAccept()
 	if err := tx.UnsignedTx.SemanticVerify(m.vm, preferredState, tx); err != nil {
 		m.MarkDropped(txID)
 		return err
@@ -121,6 +113,42 @@ func (m *blockBuilder) AddUnverifiedTx(tx *Tx) error {
 	return m.vm.GossipTx(tx)
 }
 
+// SimulateTx verifies a transaction against the state it would be issued
+// into, without adding it to the mempool.
+func (m *blockBuilder) SimulateTx(tx *Tx) error {
+	// Initialize the transaction
+	if err := tx.Sign(Codec, nil); err != nil {
+		return err
+	}
+
+	if len(tx.Bytes()) > TargetTxSize {
+		return errTxTooBig
+	}
+
+	preferredState, err := m.preferredState()
+	if err != nil {
+		return err
+	}
+	return tx.UnsignedTx.SemanticVerify(m.vm, preferredState, tx)
+}
+
+// preferredState returns the state that txs added to the mempool are verified
+// against.
+func (m *blockBuilder) preferredState() (MutableState, error) {
+	// Get the preferred block (which we want to build off)
+	preferred, err := m.vm.Preferred()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get preferred block: %w", err)
+	}
+
+	preferredDecision, ok := preferred.(decision)
+	if !ok {
+		// The preferred block should always be a decision block
+		return nil, errInvalidBlockType
+	}
+	return preferredDecision.onAccept(), nil
+}
+
 // AddVerifiedTx attempts to add a transaction to the mempool
 func (m *blockBuilder) AddVerifiedTx(tx *Tx) error {
 	if m.dropIncoming {
@@ -136,11 +164,19 @@ func (m *blockBuilder) AddVerifiedTx(tx *Tx) error {
 		return err
 	}
 	m.ResetTimer()
+	m.vm.txNotifier.notify()
 	return nil
 }
 
 // BuildBlock builds a block to be added to consensus
 func (m *blockBuilder) BuildBlock() (snowman.Block, error) {
+	return m.BuildBlockWithContext(context.Background())
+}
+
+// BuildBlockWithContext builds a block to be added to consensus. If [ctx] is
+// done before the block is built, the mempool transactions taken for the block
+// are put back into the mempool and ctx.Err() is returned.
+func (m *blockBuilder) BuildBlockWithContext(ctx context.Context) (snowman.Block, error) {
 	m.dropIncoming = true
 	defer func() {
 		m.dropIncoming = false
@@ -166,7 +202,15 @@ func (m *blockBuilder) BuildBlock() (snowman.Block, error) {
 	// Try building a standard block.
 	if m.HasDecisionTxs() {
 		txs := m.PopDecisionTxs(TargetBlockSize)
-		return m.vm.newStandardBlock(preferredID, nextHeight, txs)
+		blk, err := m.vm.newStandardBlock(preferredID, nextHeight, txs)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			m.requeue(txs...)
+			return nil, err
+		}
+		return blk, nil
 	}
 
 	// Try building a proposal block that rewards a staker.
@@ -219,7 +263,25 @@ func (m *blockBuilder) BuildBlock() (snowman.Block, error) {
 		return m.vm.newProposalBlock(preferredID, nextHeight, *advanceTimeTx)
 	}
 
-	return m.vm.newProposalBlock(preferredID, nextHeight, *tx)
+	blk, err := m.vm.newProposalBlock(preferredID, nextHeight, *tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		m.requeue(tx)
+		return nil, err
+	}
+	return blk, nil
+}
+
+// requeue puts the transactions of a block whose build was aborted back into
+// the mempool
+func (m *blockBuilder) requeue(txs ...*Tx) {
+	for _, tx := range txs {
+		if err := m.Add(tx); err != nil {
+			m.vm.ctx.Log.Debug("couldn't requeue tx %s of an aborted block: %s", tx.ID(), err)
+		}
+	}
 }
 
 // ResetTimer Check if there is a block ready to be added to consensus. If so, notify the
@@ -352,7 +414,7 @@ func (m *blockBuilder) dropTooEarlyMempoolProposalTxs() bool {
 			startTime,
 		)
 
-		m.vm.droppedTxCache.Put(txID, errMsg) // cache tx as dropped
+		m.vm.markDropped(txID, errMsg)
 		m.vm.ctx.Log.Debug("dropping tx %s: %s", txID, errMsg)
 	}
 	return false
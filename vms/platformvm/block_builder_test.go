@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -48,6 +49,43 @@ func TestBlockBuilderAddLocalTx(t *testing.T) {
 	assert.False(has, "tx included in block is still recorded into mempool")
 }
 
+// shows that the txs of a block whose build is aborted are kept in the mempool
+// and included in a later block
+func TestBlockBuilderAbortedBuildRequeuesTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.gossipActivationTime = time.Unix(0, 0) // enable mempool gossiping
+	mempool := &vm.blockBuilder
+
+	tx := getValidTx(vm, t)
+	txID := tx.ID()
+
+	err := mempool.AddUnverifiedTx(tx)
+	assert.NoError(err, "couldn't add tx to mempool")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = vm.BuildBlockWithContext(ctx)
+	assert.ErrorIs(err, context.Canceled)
+	assert.True(mempool.Has(txID), "tx of the aborted block should be requeued")
+
+	blkIntf, err := vm.BuildBlock()
+	assert.NoError(err, "couldn't build block out of mempool")
+
+	blk, ok := blkIntf.(*StandardBlock)
+	assert.True(ok, "expected standard block")
+	assert.Len(blk.Txs, 1, "standard block should include a single transaction")
+	assert.Equal(txID, blk.Txs[0].ID(), "standard block does not include expected transaction")
+	assert.False(mempool.Has(txID), "tx included in block is still recorded into mempool")
+}
+
 // shows that valid tx is not added to mempool if this would exceed its maximum
 // size
 func TestBlockBuilderMaxMempoolSizeHandling(t *testing.T) {
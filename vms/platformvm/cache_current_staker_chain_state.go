@@ -40,6 +40,10 @@ type currentStakerChainState interface {
 		numTxsToRemove int,
 	) (currentStakerChainState, error)
 	DeleteNextStaker() (currentStakerChainState, error)
+	// DeleteSubnetValidator removes the subnet validator added by [txID]
+	// before its end time. The removed tx isn't applied by the returned state,
+	// so it must be deleted from the internal state by the caller.
+	DeleteSubnetValidator(txID ids.ID) (currentStakerChainState, *Tx, error)
 
 	// Stakers returns the current stakers on the network sorted in order of the
 	// order of their future removal from the validator set.
@@ -267,6 +271,49 @@ func (cs *currentStakerChainStateImpl) DeleteNextStaker() (currentStakerChainSta
 	return newCS, nil
 }
 
+func (cs *currentStakerChainStateImpl) DeleteSubnetValidator(txID ids.ID) (currentStakerChainState, *Tx, error) {
+	removed, exists := cs.validatorsByTxID[txID]
+	if !exists {
+		return nil, nil, database.ErrNotFound
+	}
+	tx, ok := removed.addStakerTx.UnsignedTx.(*UnsignedAddSubnetValidatorTx)
+	if !ok {
+		return nil, nil, errWrongTxType
+	}
+
+	newCS := &currentStakerChainStateImpl{
+		validatorsByNodeID: make(map[ids.ShortID]*currentValidatorImpl, len(cs.validatorsByNodeID)),
+		validatorsByTxID:   make(map[ids.ID]*validatorReward, len(cs.validatorsByTxID)-1),
+		validators:         make([]*Tx, 0, len(cs.validators)-1),
+	}
+	for _, vdrTx := range cs.validators {
+		if vdrTx.ID() != txID {
+			newCS.validators = append(newCS.validators, vdrTx) // sorted in order of removal
+		}
+	}
+	for nodeID, vdr := range cs.validatorsByNodeID {
+		newCS.validatorsByNodeID[nodeID] = vdr
+	}
+	for vdrTxID, vdr := range cs.validatorsByTxID {
+		if vdrTxID != txID {
+			newCS.validatorsByTxID[vdrTxID] = vdr
+		}
+	}
+
+	oldVdr := newCS.validatorsByNodeID[tx.Validator.NodeID]
+	newVdr := *oldVdr
+	newVdr.subnets = make(map[ids.ID]*UnsignedAddSubnetValidatorTx, len(oldVdr.subnets)-1)
+	for subnetID, addTx := range oldVdr.subnets {
+		if subnetID != tx.Validator.Subnet {
+			newVdr.subnets[subnetID] = addTx
+		}
+	}
+	newCS.validatorsByNodeID[tx.Validator.NodeID] = &newVdr
+
+	newCS.setNextStaker()
+	return newCS, removed.addStakerTx, nil
+}
+
 func (cs *currentStakerChainStateImpl) Stakers() []*Tx {
 	return cs.validators
 }
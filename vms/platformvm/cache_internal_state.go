@@ -4,8 +4,11 @@
 package platformvm
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,20 +32,25 @@ import (
 )
 
 var (
-	validatorsPrefix      = []byte("validators")
-	currentPrefix         = []byte("current")
-	pendingPrefix         = []byte("pending")
-	validatorPrefix       = []byte("validator")
-	delegatorPrefix       = []byte("delegator")
-	subnetValidatorPrefix = []byte("subnetValidator")
-	validatorDiffsPrefix  = []byte("validatorDiffs")
-	blockPrefix           = []byte("block")
-	txPrefix              = []byte("tx")
-	rewardUTXOsPrefix     = []byte("rewardUTXOs")
-	utxoPrefix            = []byte("utxo")
-	subnetPrefix          = []byte("subnet")
-	chainPrefix           = []byte("chain")
-	singletonPrefix       = []byte("singleton")
+	validatorsPrefix        = []byte("validators")
+	currentPrefix           = []byte("current")
+	pendingPrefix           = []byte("pending")
+	validatorPrefix         = []byte("validator")
+	delegatorPrefix         = []byte("delegator")
+	subnetValidatorPrefix   = []byte("subnetValidator")
+	validatorDiffsPrefix    = []byte("validatorDiffs")
+	validatorSetRootsPrefix = []byte("validatorSetRoots")
+	blockPrefix             = []byte("block")
+	txPrefix                = []byte("tx")
+	rewardUTXOsPrefix       = []byte("rewardUTXOs")
+	utxoPrefix              = []byte("utxo")
+	subnetPrefix            = []byte("subnet")
+	chainPrefix             = []byte("chain")
+	singletonPrefix         = []byte("singleton")
+	addressFilterPrefix     = []byte("addressFilter")
+	delegationFeePrefix     = []byte("delegationFee")
+	subnetFeeConfigPrefix   = []byte("subnetFeeConfig")
+	subnetVMConfigPrefix    = []byte("subnetVMConfig")
 
 	timestampKey     = []byte("timestamp")
 	currentSupplyKey = []byte("current supply")
@@ -68,6 +76,7 @@ const (
 	rewardUTXOsCacheSize    = 2048
 	chainCacheSize          = 2048
 	chainDBCacheSize        = 2048
+	delegationFeeCacheSize  = 2048
 )
 
 type InternalState interface {
@@ -81,6 +90,15 @@ type InternalState interface {
 	DeleteCurrentStaker(tx *Tx)
 	GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.ShortID]*ValidatorWeightDiff, error)
 
+	// GetValidatorSetRoot returns the Merkle root of the validator set of
+	// [subnetID] at [height], as committed when the validator set last changed
+	// at or before [height]. Roots are only committed for the primary network
+	// and the whitelisted subnets.
+	GetValidatorSetRoot(height uint64, subnetID ids.ID) (ids.ID, error)
+	// AddValidatorSetRoot commits [root] as the Merkle root of the validator
+	// set of [subnetID] from [height] until the validator set next changes
+	AddValidatorSetRoot(height uint64, subnetID ids.ID, root ids.ID)
+
 	AddPendingStaker(tx *Tx)
 	DeletePendingStaker(tx *Tx)
 
@@ -93,6 +111,11 @@ type InternalState interface {
 	GetBlock(blockID ids.ID) (Block, error)
 	AddBlock(block Block)
 
+	// GetAddressFilter returns the ID of the block accepted at [height] and the
+	// bytes of the filter of the addresses it involves
+	GetAddressFilter(height uint64) (ids.ID, []byte, error)
+	AddAddressFilter(height uint64, blkID ids.ID, filter []byte)
+
 	Abort()
 	Commit() error
 	CommitBatch() (database.Batch, error)
@@ -122,10 +145,12 @@ type InternalState interface {
  * | | '-. subnetValidator
  * | |   '-. list
  * | |     '-- txID -> nil
- * | '-. diffs
- * |   '-. height+subnet
- * |     '-. list
- * |       '-- nodeID -> weightChange
+ * | |-. diffs
+ * | | '-. height+subnet
+ * | |   '-. list
+ * | |     '-- nodeID -> weightChange
+ * | '-. roots
+ * |   '-- subnetID+inverted height -> validator set root
  * |-. blocks
  * | '-- blockID -> block bytes
  * |-. txs
@@ -143,6 +168,15 @@ type InternalState interface {
  * | '-. subnetID
  * |   '-. list
  * |     '-- txID -> nil
+ * |-. addressFilters
+ * | '-- height -> blockID + address filter bytes
+ * |-. delegationFees
+ * | '-. validatorTxID
+ * |   '-- txID -> nil
+ * |-. subnetFeeConfigs
+ * | '-- subnetID -> txID
+ * |-. subnetVMConfigs
+ * | '-- subnetID -> txID
  * '-. singletons
  *   |-- initializedKey -> nil
  *   |-- timestampKey -> timestamp
@@ -184,6 +218,9 @@ type internalStateImpl struct {
 	validatorDiffsCache cache.Cacher // cache of heightWithSubnet -> map[ids.ShortID]*ValidatorWeightDiff
 	validatorDiffsDB    database.Database
 
+	addedValidatorSetRoots map[string]ids.ID // map of validatorSetRootKey -> validator set root
+	validatorSetRootsDB    database.Database
+
 	addedBlocks map[ids.ID]Block // map of blockID -> Block
 	blockCache  cache.Cacher     // cache of blockID -> Block, if the entry is nil, it is not in the database
 	blockDB     database.Database
@@ -214,6 +251,19 @@ type internalStateImpl struct {
 	originalCurrentSupply, currentSupply uint64
 	originalLastAccepted, lastAccepted   ids.ID
 	singletonDB                          database.Database
+
+	addedAddressFilters map[uint64]*stateAddressFilter // map of height -> filter of the block at that height
+	addressFilterDB     database.Database
+
+	addedDelegationFeeChanges map[ids.ID][]*Tx // maps validatorTxID -> the newly scheduled changes of the validator's delegation fee
+	delegationFeeCache        cache.Cacher     // cache of validatorTxID -> the scheduled changes after all local modifications []*Tx
+	delegationFeeDB           database.Database
+
+	addedSubnetFeeConfigs map[ids.ID]*Tx // maps subnetID -> the tx that newly set the fee config of the subnet's chains
+	subnetFeeConfigDB     database.Database
+
+	addedSubnetVMConfigs map[ids.ID]*Tx // maps subnetID -> the tx that newly set the config of the subnet's VMs
+	subnetVMConfigDB     database.Database
 }
 
 type ValidatorWeightDiff struct {
@@ -236,6 +286,11 @@ type stateBlk struct {
 	Status choices.Status `serialize:"true"`
 }
 
+type stateAddressFilter struct {
+	BlkID  ids.ID `serialize:"true"`
+	Filter []byte `serialize:"true"`
+}
+
 func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl {
 	baseDB := versiondb.New(db)
 
@@ -252,6 +307,7 @@ func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl
 	pendingSubnetValidatorBaseDB := prefixdb.New(subnetValidatorPrefix, pendingValidatorsDB)
 
 	validatorDiffsDB := prefixdb.New(validatorDiffsPrefix, validatorsDB)
+	validatorSetRootsDB := prefixdb.New(validatorSetRootsPrefix, validatorsDB)
 
 	rewardUTXODB := prefixdb.New(rewardUTXOsPrefix, baseDB)
 	utxoDB := prefixdb.New(utxoPrefix, baseDB)
@@ -280,6 +336,8 @@ func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl
 		pendingSubnetValidatorBaseDB: pendingSubnetValidatorBaseDB,
 		pendingSubnetValidatorList:   linkeddb.NewDefault(pendingSubnetValidatorBaseDB),
 		validatorDiffsDB:             validatorDiffsDB,
+		addedValidatorSetRoots:       make(map[string]ids.ID),
+		validatorSetRootsDB:          validatorSetRootsDB,
 
 		addedBlocks: make(map[ids.ID]Block),
 		blockDB:     prefixdb.New(blockPrefix, baseDB),
@@ -300,6 +358,18 @@ func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl
 		chainDB:     prefixdb.New(chainPrefix, baseDB),
 
 		singletonDB: prefixdb.New(singletonPrefix, baseDB),
+
+		addedAddressFilters: make(map[uint64]*stateAddressFilter),
+		addressFilterDB:     prefixdb.New(addressFilterPrefix, baseDB),
+
+		addedDelegationFeeChanges: make(map[ids.ID][]*Tx),
+		delegationFeeDB:           prefixdb.New(delegationFeePrefix, baseDB),
+
+		addedSubnetFeeConfigs: make(map[ids.ID]*Tx),
+		subnetFeeConfigDB:     prefixdb.New(subnetFeeConfigPrefix, baseDB),
+
+		addedSubnetVMConfigs: make(map[ids.ID]*Tx),
+		subnetVMConfigDB:     prefixdb.New(subnetVMConfigPrefix, baseDB),
 	}
 }
 
@@ -311,6 +381,7 @@ func (st *internalStateImpl) initCaches() {
 	st.utxoState = avax.NewUTXOState(st.utxoDB, GenesisCodec)
 	st.chainCache = &cache.LRU{Size: chainCacheSize}
 	st.chainDBCache = &cache.LRU{Size: chainDBCacheSize}
+	st.delegationFeeCache = &cache.LRU{Size: delegationFeeCacheSize}
 }
 
 func (st *internalStateImpl) initMeteredCaches(metrics prometheus.Registerer) error {
@@ -369,6 +440,15 @@ func (st *internalStateImpl) initMeteredCaches(metrics prometheus.Registerer) er
 		metrics,
 		&cache.LRU{Size: chainDBCacheSize},
 	)
+	if err != nil {
+		return err
+	}
+
+	delegationFeeCache, err := metercacher.New(
+		"delegation_fee_cache",
+		metrics,
+		&cache.LRU{Size: delegationFeeCacheSize},
+	)
 	st.validatorDiffsCache = validatorDiffsCache
 	st.blockCache = blockCache
 	st.txCache = txCache
@@ -376,6 +456,7 @@ func (st *internalStateImpl) initMeteredCaches(metrics prometheus.Registerer) er
 	st.utxoState = utxoState
 	st.chainCache = chainCache
 	st.chainDBCache = chainDBCache
+	st.delegationFeeCache = delegationFeeCache
 	return err
 }
 
@@ -534,6 +615,87 @@ func (st *internalStateImpl) getChainDB(subnetID ids.ID) linkeddb.LinkedDB {
 	return chainDB
 }
 
+func (st *internalStateImpl) GetDelegationFeeChanges(validatorTxID ids.ID) ([]*Tx, error) {
+	if changesIntf, cached := st.delegationFeeCache.Get(validatorTxID); cached {
+		return changesIntf.([]*Tx), nil
+	}
+	changeDB := prefixdb.New(validatorTxID[:], st.delegationFeeDB)
+	changeDBIt := changeDB.NewIterator()
+	defer changeDBIt.Release()
+
+	txs := []*Tx(nil)
+	for changeDBIt.Next() {
+		txID, err := ids.ToID(changeDBIt.Key())
+		if err != nil {
+			return nil, err
+		}
+		changeTx, _, err := st.GetTx(txID)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, changeTx)
+	}
+	if err := changeDBIt.Error(); err != nil {
+		return nil, err
+	}
+	txs = append(txs, st.addedDelegationFeeChanges[validatorTxID]...)
+	st.delegationFeeCache.Put(validatorTxID, txs)
+	return txs, nil
+}
+
+func (st *internalStateImpl) AddDelegationFeeChange(setDelegationFeeTxIntf *Tx) {
+	setDelegationFeeTx := setDelegationFeeTxIntf.UnsignedTx.(*UnsignedSetDelegationFeeTx)
+	validatorTxID := setDelegationFeeTx.ValidatorTxID
+	st.addedDelegationFeeChanges[validatorTxID] = append(st.addedDelegationFeeChanges[validatorTxID], setDelegationFeeTxIntf)
+	if changesIntf, cached := st.delegationFeeCache.Get(validatorTxID); cached {
+		changes := changesIntf.([]*Tx)
+		changes = append(changes, setDelegationFeeTxIntf)
+		st.delegationFeeCache.Put(validatorTxID, changes)
+	}
+}
+
+func (st *internalStateImpl) GetSubnetFeeConfig(subnetID ids.ID) (*Tx, error) {
+	if tx, exists := st.addedSubnetFeeConfigs[subnetID]; exists {
+		return tx, nil
+	}
+	txIDBytes, err := st.subnetFeeConfigDB.Get(subnetID[:])
+	if err != nil {
+		return nil, err
+	}
+	txID, err := ids.ToID(txIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	tx, _, err := st.GetTx(txID)
+	return tx, err
+}
+
+func (st *internalStateImpl) SetSubnetFeeConfig(setSubnetFeeConfigTxIntf *Tx) {
+	setSubnetFeeConfigTx := setSubnetFeeConfigTxIntf.UnsignedTx.(*UnsignedSetSubnetFeeConfigTx)
+	st.addedSubnetFeeConfigs[setSubnetFeeConfigTx.SubnetID] = setSubnetFeeConfigTxIntf
+}
+
+func (st *internalStateImpl) GetSubnetVMConfig(subnetID ids.ID) (*Tx, error) {
+	if tx, exists := st.addedSubnetVMConfigs[subnetID]; exists {
+		return tx, nil
+	}
+	txIDBytes, err := st.subnetVMConfigDB.Get(subnetID[:])
+	if err != nil {
+		return nil, err
+	}
+	txID, err := ids.ToID(txIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	tx, _, err := st.GetTx(txID)
+	return tx, err
+}
+
+func (st *internalStateImpl) SetSubnetVMConfig(setSubnetVMConfigTxIntf *Tx) {
+	setSubnetVMConfigTx := setSubnetVMConfigTxIntf.UnsignedTx.(*UnsignedSetSubnetVMConfigTx)
+	st.addedSubnetVMConfigs[setSubnetVMConfigTx.SubnetID] = setSubnetVMConfigTxIntf
+}
+
 func (st *internalStateImpl) GetTx(txID ids.ID) (*Tx, status.Status, error) {
 	if tx, exists := st.addedTxs[txID]; exists {
 		return tx.tx, tx.status, nil
@@ -673,6 +835,28 @@ func (st *internalStateImpl) AddBlock(block Block) {
 	st.addedBlocks[block.ID()] = block
 }
 
+func (st *internalStateImpl) GetAddressFilter(height uint64) (ids.ID, []byte, error) {
+	if filter, exists := st.addedAddressFilters[height]; exists {
+		return filter.BlkID, filter.Filter, nil
+	}
+	filterBytes, err := st.addressFilterDB.Get(database.PackUInt64(height))
+	if err != nil {
+		return ids.ID{}, nil, err
+	}
+	filter := stateAddressFilter{}
+	if _, err := GenesisCodec.Unmarshal(filterBytes, &filter); err != nil {
+		return ids.ID{}, nil, err
+	}
+	return filter.BlkID, filter.Filter, nil
+}
+
+func (st *internalStateImpl) AddAddressFilter(height uint64, blkID ids.ID, filter []byte) {
+	st.addedAddressFilters[height] = &stateAddressFilter{
+		BlkID:  blkID,
+		Filter: filter,
+	}
+}
+
 func (st *internalStateImpl) UTXOIDs(addr []byte, start ids.ID, limit int) ([]ids.ID, error) {
 	return st.utxoState.UTXOIDs(addr, start, limit)
 }
@@ -782,6 +966,50 @@ func (st *internalStateImpl) GetValidatorWeightDiffs(height uint64, subnetID ids
 	return weightDiffs, nil
 }
 
+// validatorSetRootKey returns the key that the validator set root of
+// [subnetID] committed at [height] is stored under. Heights are inverted so
+// that iterating from the key of a height finds the last root committed at or
+// before that height.
+func validatorSetRootKey(height uint64, subnetID ids.ID) []byte {
+	key := make([]byte, len(subnetID)+wrappers.LongLen)
+	copy(key, subnetID[:])
+	binary.BigEndian.PutUint64(key[len(subnetID):], math.MaxUint64-height)
+	return key
+}
+
+func (st *internalStateImpl) GetValidatorSetRoot(height uint64, subnetID ids.ID) (ids.ID, error) {
+	// Roots that haven't been written yet are at the latest heights
+	start := validatorSetRootKey(height, subnetID)
+	var (
+		latestKey  []byte
+		latestRoot ids.ID
+	)
+	for key, root := range st.addedValidatorSetRoots {
+		if key := []byte(key); bytes.HasPrefix(key, subnetID[:]) && bytes.Compare(key, start) >= 0 &&
+			(latestKey == nil || bytes.Compare(key, latestKey) < 0) {
+			latestKey = key
+			latestRoot = root
+		}
+	}
+	if latestKey != nil {
+		return latestRoot, nil
+	}
+
+	it := st.validatorSetRootsDB.NewIteratorWithStartAndPrefix(start, subnetID[:])
+	defer it.Release()
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return ids.Empty, err
+		}
+		return ids.Empty, database.ErrNotFound
+	}
+	return ids.ToID(it.Value())
+}
+
+func (st *internalStateImpl) AddValidatorSetRoot(height uint64, subnetID ids.ID, root ids.ID) {
+	st.addedValidatorSetRoots[string(validatorSetRootKey(height, subnetID))] = root
+}
+
 func (st *internalStateImpl) Abort() {
 	st.baseDB.Abort()
 }
@@ -802,6 +1030,9 @@ func (st *internalStateImpl) CommitBatch() (database.Batch, error) {
 	if err := st.writePendingStakers(); err != nil {
 		return nil, fmt.Errorf("failed to write pending stakers with: %w", err)
 	}
+	if err := st.writeValidatorSetRoots(); err != nil {
+		return nil, fmt.Errorf("failed to write validator set roots with: %w", err)
+	}
 	if err := st.writeUptimes(); err != nil {
 		return nil, fmt.Errorf("failed to write uptimes with: %w", err)
 	}
@@ -826,6 +1057,18 @@ func (st *internalStateImpl) CommitBatch() (database.Batch, error) {
 	if err := st.writeSingletons(); err != nil {
 		return nil, fmt.Errorf("failed to write singletons with: %w", err)
 	}
+	if err := st.writeAddressFilters(); err != nil {
+		return nil, fmt.Errorf("failed to write address filters with: %w", err)
+	}
+	if err := st.writeDelegationFeeChanges(); err != nil {
+		return nil, fmt.Errorf("failed to write delegation fee changes with: %w", err)
+	}
+	if err := st.writeSubnetFeeConfigs(); err != nil {
+		return nil, fmt.Errorf("failed to write subnet fee configs with: %w", err)
+	}
+	if err := st.writeSubnetVMConfigs(); err != nil {
+		return nil, fmt.Errorf("failed to write subnet VM configs with: %w", err)
+	}
 	return st.baseDB.CommitBatch()
 }
 
@@ -848,6 +1091,10 @@ func (st *internalStateImpl) Close() error {
 		st.subnetBaseDB.Close(),
 		st.chainDB.Close(),
 		st.singletonDB.Close(),
+		st.addressFilterDB.Close(),
+		st.delegationFeeDB.Close(),
+		st.subnetFeeConfigDB.Close(),
+		st.subnetVMConfigDB.Close(),
 		st.baseDB.Close(),
 	)
 	return errs.Err
@@ -1042,6 +1289,20 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 			}
 		}
 		st.validatorDiffsCache.Put(string(prefixBytes), nodeUpdates)
+
+		// Commit to the validator sets that this node tracks once they change
+		if len(nodeUpdates) == 0 {
+			continue
+		}
+		vdrs, ok := st.vm.Validators.GetValidators(subnetID)
+		if !ok {
+			continue
+		}
+		root, err := validatorSetRoot(vdrs)
+		if err != nil {
+			return err
+		}
+		st.AddValidatorSetRoot(st.currentHeight, subnetID, root)
 	}
 
 	// Attempt to update the stake metrics
@@ -1055,6 +1316,19 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 	return nil
 }
 
+func (st *internalStateImpl) writeValidatorSetRoots() error {
+	for key, root := range st.addedValidatorSetRoots {
+		delete(st.addedValidatorSetRoots, key)
+
+		// Copy so value passed into [Put] doesn't get overwritten next iteration
+		root := root
+		if err := st.validatorSetRootsDB.Put([]byte(key), root[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (st *internalStateImpl) writePendingStakers() error {
 	for _, tx := range st.addedPendingStakers {
 		var db database.KeyValueWriter
@@ -1228,6 +1502,42 @@ func (st *internalStateImpl) writeChains() error {
 	return nil
 }
 
+func (st *internalStateImpl) writeDelegationFeeChanges() error {
+	for validatorTxID, changes := range st.addedDelegationFeeChanges {
+		changeDB := prefixdb.New(validatorTxID[:], st.delegationFeeDB)
+		for _, change := range changes {
+			txID := change.ID()
+			if err := changeDB.Put(txID[:], nil); err != nil {
+				return err
+			}
+		}
+		delete(st.addedDelegationFeeChanges, validatorTxID)
+	}
+	return nil
+}
+
+func (st *internalStateImpl) writeSubnetFeeConfigs() error {
+	for subnetID, tx := range st.addedSubnetFeeConfigs {
+		txID := tx.ID()
+		if err := st.subnetFeeConfigDB.Put(subnetID[:], txID[:]); err != nil {
+			return err
+		}
+		delete(st.addedSubnetFeeConfigs, subnetID)
+	}
+	return nil
+}
+
+func (st *internalStateImpl) writeSubnetVMConfigs() error {
+	for subnetID, tx := range st.addedSubnetVMConfigs {
+		txID := tx.ID()
+		if err := st.subnetVMConfigDB.Put(subnetID[:], txID[:]); err != nil {
+			return err
+		}
+		delete(st.addedSubnetVMConfigs, subnetID)
+	}
+	return nil
+}
+
 func (st *internalStateImpl) writeSingletons() error {
 	if !st.originalTimestamp.Equal(st.timestamp) {
 		if err := database.PutTimestamp(st.singletonDB, timestampKey, st.timestamp); err != nil {
@@ -1601,6 +1911,7 @@ func (st *internalStateImpl) init(genesisBytes []byte) error {
 	}
 	genesisBlock.status = choices.Accepted
 	st.AddBlock(genesisBlock)
+	st.AddAddressFilter(0, genesisBlock.ID(), nil)
 	st.SetLastAccepted(genesisBlock.ID())
 
 	if err := st.singletonDB.Put(initializedKey, nil); err != nil {
@@ -1609,3 +1920,18 @@ func (st *internalStateImpl) init(genesisBytes []byte) error {
 
 	return st.Commit()
 }
+
+func (st *internalStateImpl) writeAddressFilters() error {
+	for height, filter := range st.addedAddressFilters {
+		filterBytes, err := GenesisCodec.Marshal(CodecVersion, filter)
+		if err != nil {
+			return err
+		}
+
+		delete(st.addedAddressFilters, height)
+		if err := st.addressFilterDB.Put(database.PackUInt64(height), filterBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -23,6 +23,11 @@ type pendingStakerChainState interface {
 
 	AddStaker(addStakerTx *Tx) pendingStakerChainState
 	DeleteStakers(numToRemove int) pendingStakerChainState
+	// DeleteSubnetValidator removes the subnet validator added by [txID]
+	// before it starts validating. The removed tx isn't applied by the
+	// returned state, so it must be deleted from the internal state by the
+	// caller.
+	DeleteSubnetValidator(txID ids.ID) (pendingStakerChainState, *Tx, error)
 
 	// Stakers returns the list of pending validators in order of their removal
 	// from the pending staker set
@@ -194,6 +199,49 @@ func (ps *pendingStakerChainStateImpl) DeleteStakers(numToRemove int) pendingSta
 	return newPS
 }
 
+func (ps *pendingStakerChainStateImpl) DeleteSubnetValidator(txID ids.ID) (pendingStakerChainState, *Tx, error) {
+	var removed *Tx
+	newPS := &pendingStakerChainStateImpl{
+		validatorsByNodeID:      ps.validatorsByNodeID,
+		validatorExtrasByNodeID: make(map[ids.ShortID]*validatorImpl, len(ps.validatorExtrasByNodeID)),
+		validators:              make([]*Tx, 0, len(ps.validators)),
+	}
+	for _, vdrTx := range ps.validators {
+		if vdrTx.ID() == txID {
+			removed = vdrTx
+			continue
+		}
+		newPS.validators = append(newPS.validators, vdrTx) // sorted in order of addition
+	}
+	if removed == nil {
+		return nil, nil, database.ErrNotFound
+	}
+	tx, ok := removed.UnsignedTx.(*UnsignedAddSubnetValidatorTx)
+	if !ok {
+		return nil, nil, errWrongTxType
+	}
+
+	for nodeID, vdr := range ps.validatorExtrasByNodeID {
+		newPS.validatorExtrasByNodeID[nodeID] = vdr
+	}
+	vdr := newPS.validatorExtrasByNodeID[tx.Validator.NodeID]
+	if len(vdr.delegators) == 0 && len(vdr.subnets) == 1 {
+		delete(newPS.validatorExtrasByNodeID, tx.Validator.NodeID)
+		return newPS, removed, nil
+	}
+	newSubnets := make(map[ids.ID]*UnsignedAddSubnetValidatorTx, len(vdr.subnets)-1)
+	for subnetID, subnetTx := range vdr.subnets {
+		if subnetID != tx.Validator.Subnet {
+			newSubnets[subnetID] = subnetTx
+		}
+	}
+	newPS.validatorExtrasByNodeID[tx.Validator.NodeID] = &validatorImpl{
+		delegators: vdr.delegators,
+		subnets:    newSubnets,
+	}
+	return newPS, removed, nil
+}
+
 func (ps *pendingStakerChainStateImpl) Stakers() []*Tx {
 	return ps.validators
 }
@@ -51,6 +51,21 @@ type MutableState interface {
 	GetChains(subnetID ids.ID) ([]*Tx, error)
 	AddChain(createChainTx *Tx)
 
+	// GetDelegationFeeChanges returns the txs that scheduled a change of the
+	// delegation fee of the validator added by [validatorTxID]
+	GetDelegationFeeChanges(validatorTxID ids.ID) ([]*Tx, error)
+	AddDelegationFeeChange(setDelegationFeeTx *Tx)
+
+	// GetSubnetFeeConfig returns the tx that set the fee config of the chains
+	// of [subnetID], or database.ErrNotFound if the subnet has no config
+	GetSubnetFeeConfig(subnetID ids.ID) (*Tx, error)
+	SetSubnetFeeConfig(setSubnetFeeConfigTx *Tx)
+
+	// GetSubnetVMConfig returns the tx that set the config of the VMs of
+	// [subnetID], or database.ErrNotFound if the subnet has no config
+	GetSubnetVMConfig(subnetID ids.ID) (*Tx, error)
+	SetSubnetVMConfig(setSubnetVMConfigTx *Tx)
+
 	GetTx(txID ids.ID) (*Tx, status.Status, error)
 	AddTx(tx *Tx, status status.Status)
 }
@@ -58,6 +73,10 @@ type MutableState interface {
 type VersionedState interface {
 	MutableState
 
+	// DeleteSubnetValidator removes [nodeID] from the current, or pending,
+	// validators of [subnetID].
+	DeleteSubnetValidator(subnetID ids.ID, nodeID ids.ShortID) error
+
 	SetBase(MutableState)
 	Apply(InternalState)
 }
@@ -78,6 +97,19 @@ type versionedStateImpl struct {
 	addedChains  map[ids.ID][]*Tx
 	cachedChains map[ids.ID][]*Tx
 
+	// map of validatorTxID -> []*Tx
+	addedDelegationFeeChanges map[ids.ID][]*Tx
+
+	// map of subnetID -> *Tx
+	addedSubnetFeeConfigs map[ids.ID]*Tx
+
+	// map of subnetID -> *Tx
+	addedSubnetVMConfigs map[ids.ID]*Tx
+
+	// subnet validators removed before their end time
+	deletedCurrentStakers []*Tx
+	deletedPendingStakers []*Tx
+
 	// map of txID -> []*UTXO
 	addedRewardUTXOs map[ids.ID][]*avax.UTXO
 
@@ -210,6 +242,59 @@ func (vs *versionedStateImpl) AddChain(createChainTx *Tx) {
 	vs.cachedChains[tx.SubnetID] = append(cachedChains, createChainTx)
 }
 
+func (vs *versionedStateImpl) GetDelegationFeeChanges(validatorTxID ids.ID) ([]*Tx, error) {
+	changes, err := vs.parentState.GetDelegationFeeChanges(validatorTxID)
+	if err != nil {
+		return nil, err
+	}
+	addedChanges := vs.addedDelegationFeeChanges[validatorTxID]
+	if len(addedChanges) == 0 {
+		return changes, nil
+	}
+	newChanges := make([]*Tx, len(changes)+len(addedChanges))
+	copy(newChanges, changes)
+	copy(newChanges[len(changes):], addedChanges)
+	return newChanges, nil
+}
+
+func (vs *versionedStateImpl) AddDelegationFeeChange(setDelegationFeeTx *Tx) {
+	tx := setDelegationFeeTx.UnsignedTx.(*UnsignedSetDelegationFeeTx)
+	if vs.addedDelegationFeeChanges == nil {
+		vs.addedDelegationFeeChanges = make(map[ids.ID][]*Tx)
+	}
+	vs.addedDelegationFeeChanges[tx.ValidatorTxID] = append(vs.addedDelegationFeeChanges[tx.ValidatorTxID], setDelegationFeeTx)
+}
+
+func (vs *versionedStateImpl) GetSubnetFeeConfig(subnetID ids.ID) (*Tx, error) {
+	if tx, exists := vs.addedSubnetFeeConfigs[subnetID]; exists {
+		return tx, nil
+	}
+	return vs.parentState.GetSubnetFeeConfig(subnetID)
+}
+
+func (vs *versionedStateImpl) SetSubnetFeeConfig(setSubnetFeeConfigTx *Tx) {
+	tx := setSubnetFeeConfigTx.UnsignedTx.(*UnsignedSetSubnetFeeConfigTx)
+	if vs.addedSubnetFeeConfigs == nil {
+		vs.addedSubnetFeeConfigs = make(map[ids.ID]*Tx)
+	}
+	vs.addedSubnetFeeConfigs[tx.SubnetID] = setSubnetFeeConfigTx
+}
+
+func (vs *versionedStateImpl) GetSubnetVMConfig(subnetID ids.ID) (*Tx, error) {
+	if tx, exists := vs.addedSubnetVMConfigs[subnetID]; exists {
+		return tx, nil
+	}
+	return vs.parentState.GetSubnetVMConfig(subnetID)
+}
+
+func (vs *versionedStateImpl) SetSubnetVMConfig(setSubnetVMConfigTx *Tx) {
+	tx := setSubnetVMConfigTx.UnsignedTx.(*UnsignedSetSubnetVMConfigTx)
+	if vs.addedSubnetVMConfigs == nil {
+		vs.addedSubnetVMConfigs = make(map[ids.ID]*Tx)
+	}
+	vs.addedSubnetVMConfigs[tx.SubnetID] = setSubnetVMConfigTx
+}
+
 func (vs *versionedStateImpl) GetTx(txID ids.ID) (*Tx, status.Status, error) {
 	tx, exists := vs.addedTxs[txID]
 	if !exists {
@@ -293,6 +378,33 @@ func (vs *versionedStateImpl) PendingStakerChainState() pendingStakerChainState
 	return vs.pendingStakerChainState
 }
 
+func (vs *versionedStateImpl) DeleteSubnetValidator(subnetID ids.ID, nodeID ids.ShortID) error {
+	if vdr, err := vs.currentStakerChainState.GetValidator(nodeID); err == nil {
+		if addTx, ok := vdr.SubnetValidators()[subnetID]; ok {
+			newCS, removed, err := vs.currentStakerChainState.DeleteSubnetValidator(addTx.ID())
+			if err != nil {
+				return err
+			}
+			vs.currentStakerChainState = newCS
+			vs.deletedCurrentStakers = append(vs.deletedCurrentStakers, removed)
+			return nil
+		}
+	}
+
+	vdr := vs.pendingStakerChainState.GetValidator(nodeID)
+	addTx, ok := vdr.SubnetValidators()[subnetID]
+	if !ok {
+		return errNotSubnetValidator
+	}
+	newPS, removed, err := vs.pendingStakerChainState.DeleteSubnetValidator(addTx.ID())
+	if err != nil {
+		return err
+	}
+	vs.pendingStakerChainState = newPS
+	vs.deletedPendingStakers = append(vs.deletedPendingStakers, removed)
+	return nil
+}
+
 func (vs *versionedStateImpl) SetBase(parentState MutableState) {
 	vs.parentState = parentState
 }
@@ -308,6 +420,17 @@ func (vs *versionedStateImpl) Apply(is InternalState) {
 			is.AddChain(chain)
 		}
 	}
+	for _, changes := range vs.addedDelegationFeeChanges {
+		for _, change := range changes {
+			is.AddDelegationFeeChange(change)
+		}
+	}
+	for _, tx := range vs.addedSubnetFeeConfigs {
+		is.SetSubnetFeeConfig(tx)
+	}
+	for _, tx := range vs.addedSubnetVMConfigs {
+		is.SetSubnetVMConfig(tx)
+	}
 	for _, tx := range vs.addedTxs {
 		is.AddTx(tx.tx, tx.status)
 	}
@@ -323,6 +446,12 @@ func (vs *versionedStateImpl) Apply(is InternalState) {
 			is.DeleteUTXO(utxo.utxoID)
 		}
 	}
+	for _, tx := range vs.deletedCurrentStakers {
+		is.DeleteCurrentStaker(tx)
+	}
+	for _, tx := range vs.deletedPendingStakers {
+		is.DeletePendingStaker(tx)
+	}
 	vs.currentStakerChainState.Apply(is)
 	vs.pendingStakerChainState.Apply(is)
 }
@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package churn
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+)
+
+var (
+	errInvalidPeriod  = errors.New("churn period must be a positive number of seconds")
+	errInvalidMaxRate = errors.New("max churn rate can't exceed the percent denominator")
+)
+
+// Config limits how quickly the stake of a subnet can change. Time is split
+// into consecutive periods, and the stakers that start, or stop, staking in a
+// period can only add up to a portion of the subnet's current stake.
+//
+// The first staker of a period is always allowed, so that a subnet with little
+// stake can still grow.
+type Config struct {
+	// Period is the duration of the periods the churn is measured over
+	Period time.Duration `json:"period"`
+
+	// MaxRate is the portion, out of [reward.PercentDenominator], of the
+	// current stake of a subnet that may start, or stop, staking in a period.
+	// If 0, the churn isn't limited.
+	MaxRate uint64 `json:"maxRate"`
+}
+
+// Enabled returns true if the churn is limited
+func (c *Config) Enabled() bool { return c.MaxRate > 0 }
+
+// Verify returns nil if the config is valid
+func (c *Config) Verify() error {
+	switch {
+	case !c.Enabled():
+		return nil
+	case c.Period < time.Second:
+		return errInvalidPeriod
+	case c.MaxRate > reward.PercentDenominator:
+		return errInvalidMaxRate
+	default:
+		return nil
+	}
+}
+
+// PeriodOf returns the index of the period that contains [t]
+func (c *Config) PeriodOf(t time.Time) int64 {
+	return t.Unix() / int64(c.Period/time.Second)
+}
+
+// Limit returns the weight that may churn in a period of a subnet whose
+// current stake is [totalWeight]
+func (c *Config) Limit(totalWeight uint64) uint64 {
+	limit := new(big.Int).SetUint64(totalWeight)
+	limit.Mul(limit, new(big.Int).SetUint64(c.MaxRate))
+	limit.Div(limit, new(big.Int).SetUint64(reward.PercentDenominator))
+	return limit.Uint64()
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package churn
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+)
+
+func TestConfigVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	config := Config{}
+	assert.False(config.Enabled())
+	assert.NoError(config.Verify())
+
+	config.MaxRate = reward.PercentDenominator / 10
+	assert.True(config.Enabled())
+	assert.ErrorIs(config.Verify(), errInvalidPeriod)
+
+	config.Period = 500 * time.Millisecond
+	assert.ErrorIs(config.Verify(), errInvalidPeriod)
+
+	config.Period = time.Hour
+	assert.NoError(config.Verify())
+
+	config.MaxRate = reward.PercentDenominator + 1
+	assert.ErrorIs(config.Verify(), errInvalidMaxRate)
+}
+
+func TestConfigPeriodOf(t *testing.T) {
+	assert := assert.New(t)
+
+	config := Config{Period: time.Hour}
+	assert.EqualValues(0, config.PeriodOf(time.Unix(0, 0)))
+	assert.EqualValues(0, config.PeriodOf(time.Unix(3599, 0)))
+	assert.EqualValues(1, config.PeriodOf(time.Unix(3600, 0)))
+}
+
+func TestConfigLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	config := Config{MaxRate: reward.PercentDenominator / 4}
+	assert.EqualValues(0, config.Limit(0))
+	assert.EqualValues(25, config.Limit(100))
+	// The limit doesn't overflow
+	assert.EqualValues(math.MaxUint64/4, config.Limit(math.MaxUint64))
+}
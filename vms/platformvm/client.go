@@ -5,15 +5,18 @@ package platformvm
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bloom"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/x/merkledb"
 )
 
 // Interface compliance
@@ -95,6 +98,48 @@ type Client interface {
 		endTime uint64,
 		options ...rpc.Option,
 	) (ids.ID, error)
+	// SetDelegationFee issues a transaction to schedule a change of the
+	// delegation fee of the primary network validator [nodeID] and returns the
+	// txID
+	SetDelegationFee(
+		ctx context.Context,
+		user api.UserPass,
+		from []string,
+		changeAddr string,
+		nodeID string,
+		delegationFeeRate float32,
+		effectiveTime uint64,
+		options ...rpc.Option,
+	) (ids.ID, error)
+	// SetSubnetFeeConfig issues a transaction to declare the asset the fees
+	// of the chains of [subnetID] are paid in, and the address that collects
+	// them, and returns the txID
+	SetSubnetFeeConfig(
+		ctx context.Context,
+		user api.UserPass,
+		from []string,
+		changeAddr string,
+		subnetID ids.ID,
+		feeAssetID ids.ID,
+		feeCollector string,
+		options ...rpc.Option,
+	) (ids.ID, error)
+	// GetSubnetFeeConfig returns the fee config of the chains of [subnetID]
+	GetSubnetFeeConfig(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*GetSubnetFeeConfigReply, error)
+	// SetSubnetVMConfig issues a transaction to publish [config] for the VMs
+	// of the chains of [subnetID], and returns the txID
+	SetSubnetVMConfig(
+		ctx context.Context,
+		user api.UserPass,
+		from []string,
+		changeAddr string,
+		subnetID ids.ID,
+		config []byte,
+		options ...rpc.Option,
+	) (ids.ID, error)
+	// GetSubnetVMConfig returns the latest config published by [subnetID] for
+	// the VMs of its chains
+	GetSubnetVMConfig(ctx context.Context, subnetID ids.ID, options ...rpc.Option) ([]byte, error)
 	// AddSubnetValidator issues a transaction to add validator [nodeID] to subnet
 	// with ID [subnetID] and returns the txID
 	AddSubnetValidator(
@@ -109,6 +154,23 @@ type Client interface {
 		endTime uint64,
 		options ...rpc.Option,
 	) (ids.ID, error)
+	// RemoveSubnetValidator issues a transaction to remove validator [nodeID]
+	// from subnet with ID [subnetID] and returns the txID
+	RemoveSubnetValidator(
+		ctx context.Context,
+		user api.UserPass,
+		from []string,
+		changeAddr string,
+		subnetID ids.ID,
+		nodeID ids.ShortID,
+		options ...rpc.Option,
+	) (ids.ID, error)
+	// GetPendingSubnetValidatorChanges returns the txs in the mempool that add
+	// validators to, or remove validators from, subnet with ID [subnetID]
+	GetPendingSubnetValidatorChanges(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*GetPendingSubnetValidatorChangesReply, error)
+	// GetSubnetMemberships returns the subnets that [nodeID] currently
+	// validates or is scheduled to validate
+	GetSubnetMemberships(ctx context.Context, nodeID ids.ShortID, options ...rpc.Option) ([]APISubnetMembership, error)
 	// CreateSubnet issues a transaction to create [subnet] and returns the txID
 	CreateSubnet(
 		ctx context.Context,
@@ -162,6 +224,14 @@ type Client interface {
 	GetBlockchains(ctx context.Context, options ...rpc.Option) ([]APIBlockchain, error)
 	// IssueTx issues the transaction and returns its txID
 	IssueTx(ctx context.Context, tx []byte, options ...rpc.Option) (ids.ID, error)
+	// SimulateTx verifies [tx] against the current state without issuing it.
+	// If [tx] is invalid, the reason is returned in the reply.
+	SimulateTx(ctx context.Context, tx []byte, options ...rpc.Option) (*api.SimulateTxReply, error)
+	// DecodeTx decodes the signed [tx] into JSON without verifying it
+	DecodeTx(ctx context.Context, tx []byte, options ...rpc.Option) (*api.DecodeTxReply, error)
+	// GetImportStatus returns which outputs of [exportTxID], exported from
+	// [sourceChain], were imported into this chain, and by which txs
+	GetImportStatus(ctx context.Context, sourceChain string, exportTxID ids.ID, options ...rpc.Option) (*api.GetImportStatusReply, error)
 	// GetTx returns the byte representation of the transaction corresponding to [txID]
 	GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error)
 	// GetTxStatus returns the status of the transaction corresponding to [txID]
@@ -175,6 +245,26 @@ type Client interface {
 		freq time.Duration,
 		options ...rpc.Option,
 	) (*GetTxStatusResponse, error)
+	// AwaitTxStatus waits for up to [timeout] for the stage of [txID] to
+	// differ from [stage], and returns the stage of the tx.
+	AwaitTxStatus(
+		ctx context.Context,
+		txID ids.ID,
+		stage TxStage,
+		timeout time.Duration,
+		includeProof bool,
+		options ...rpc.Option,
+	) (*AwaitTxStatusReply, error)
+	// IssueTxAndAwait issues [tx] and follows its lifecycle until it's
+	// accepted or rejected. [onStage] is called with each stage the tx
+	// reaches.
+	IssueTxAndAwait(
+		ctx context.Context,
+		tx []byte,
+		includeProof bool,
+		onStage func(*AwaitTxStatusReply),
+		options ...rpc.Option,
+	) (*AwaitTxStatusReply, error)
 	// GetStake returns the amount of nAVAX that [addresses] have cumulatively
 	// staked on the Primary Network.
 	GetStake(ctx context.Context, addrs []string, options ...rpc.Option) (*GetStakeReply, error)
@@ -200,19 +290,32 @@ type Client interface {
 	// GetValidatorsAt returns the weights of the validator set of a provided subnet
 	// at the specified height.
 	GetValidatorsAt(ctx context.Context, subnetID ids.ID, height uint64, options ...rpc.Option) (map[string]uint64, error)
+	// GetValidatorSetProof returns the Merkle root of the validator set of a
+	// provided subnet at the specified height, the weight of [nodeID] in that
+	// validator set and a proof of the weight against the root. The root is
+	// computed by the node, and isn't committed to by P-chain blocks.
+	GetValidatorSetProof(ctx context.Context, subnetID ids.ID, height uint64, nodeID ids.ShortID, options ...rpc.Option) (ids.ID, uint64, *merkledb.Proof, error)
 	// GetBlock returns the block with the given id.
 	GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error)
+	// GetAddressFilters returns the filters of the addresses involved in up to
+	// [limit] blocks accepted from [startHeight].
+	GetAddressFilters(ctx context.Context, startHeight uint64, limit uint32, options ...rpc.Option) ([]BlockAddressFilter, error)
+	// GetBlocksByHeight returns the bytes of the blocks accepted from
+	// [startHeight] to [endHeight], inclusive.
+	GetBlocksByHeight(ctx context.Context, startHeight, endHeight uint64, options ...rpc.Option) ([][]byte, error)
 }
 
 // Client implementation for interacting with the P Chain endpoint
 type client struct {
-	requester rpc.EndpointRequester
+	requester   rpc.EndpointRequester
+	txRequester rpc.EndpointRequester
 }
 
 // NewClient returns a Client for interacting with the P Chain endpoint
 func NewClient(uri string) Client {
 	return &client{
-		requester: rpc.NewEndpointRequester(uri, "/ext/P", "platform"),
+		requester:   rpc.NewEndpointRequester(uri, "/ext/P", "platform"),
+		txRequester: rpc.NewEndpointRequester(uri, "/ext/P"+txServiceEndpoint, "platform"),
 	}
 }
 
@@ -435,6 +538,102 @@ func (c *client) AddDelegator(
 	return res.TxID, err
 }
 
+func (c *client) SetDelegationFee(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	nodeID string,
+	delegationFeeRate float32,
+	effectiveTime uint64,
+	options ...rpc.Option,
+) (ids.ID, error) {
+	res := &api.JSONTxID{}
+	err := c.requester.SendRequest(ctx, "setDelegationFee", &SetDelegationFeeArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		NodeID:            nodeID,
+		DelegationFeeRate: json.Float32(delegationFeeRate),
+		EffectiveTime:     json.Uint64(effectiveTime),
+	}, res, options...)
+	return res.TxID, err
+}
+
+func (c *client) SetSubnetFeeConfig(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	subnetID ids.ID,
+	feeAssetID ids.ID,
+	feeCollector string,
+	options ...rpc.Option,
+) (ids.ID, error) {
+	res := &api.JSONTxID{}
+	err := c.requester.SendRequest(ctx, "setSubnetFeeConfig", &SetSubnetFeeConfigArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		SubnetID:     subnetID,
+		FeeAssetID:   feeAssetID,
+		FeeCollector: feeCollector,
+	}, res, options...)
+	return res.TxID, err
+}
+
+func (c *client) GetSubnetFeeConfig(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*GetSubnetFeeConfigReply, error) {
+	res := &GetSubnetFeeConfigReply{}
+	err := c.requester.SendRequest(ctx, "getSubnetFeeConfig", &GetSubnetFeeConfigArgs{
+		SubnetID: subnetID,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) SetSubnetVMConfig(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	subnetID ids.ID,
+	config []byte,
+	options ...rpc.Option,
+) (ids.ID, error) {
+	configStr, err := formatting.EncodeWithChecksum(formatting.Hex, config)
+	if err != nil {
+		return ids.ID{}, err
+	}
+
+	res := &api.JSONTxID{}
+	err = c.requester.SendRequest(ctx, "setSubnetVMConfig", &SetSubnetVMConfigArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		SubnetID: subnetID,
+		Config:   configStr,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	return res.TxID, err
+}
+
+func (c *client) GetSubnetVMConfig(ctx context.Context, subnetID ids.ID, options ...rpc.Option) ([]byte, error) {
+	res := &GetSubnetVMConfigReply{}
+	err := c.requester.SendRequest(ctx, "getSubnetVMConfig", &GetSubnetVMConfigArgs{
+		SubnetID: subnetID,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	if err != nil {
+		return nil, err
+	}
+	return formatting.Decode(res.Encoding, res.Config)
+}
+
 func (c *client) AddSubnetValidator(
 	ctx context.Context,
 	user api.UserPass,
@@ -466,6 +665,44 @@ func (c *client) AddSubnetValidator(
 	return res.TxID, err
 }
 
+func (c *client) RemoveSubnetValidator(
+	ctx context.Context,
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	subnetID ids.ID,
+	nodeID ids.ShortID,
+	options ...rpc.Option,
+) (ids.ID, error) {
+	res := &api.JSONTxID{}
+	err := c.requester.SendRequest(ctx, "removeSubnetValidator", &RemoveSubnetValidatorArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		NodeID:   nodeID.PrefixedString(constants.NodeIDPrefix),
+		SubnetID: subnetID,
+	}, res, options...)
+	return res.TxID, err
+}
+
+func (c *client) GetPendingSubnetValidatorChanges(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*GetPendingSubnetValidatorChangesReply, error) {
+	res := &GetPendingSubnetValidatorChangesReply{}
+	err := c.requester.SendRequest(ctx, "getPendingSubnetValidatorChanges", &GetPendingSubnetValidatorChangesArgs{
+		SubnetID: subnetID,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) GetSubnetMemberships(ctx context.Context, nodeID ids.ShortID, options ...rpc.Option) ([]APISubnetMembership, error) {
+	res := &GetSubnetMembershipsReply{}
+	err := c.requester.SendRequest(ctx, "getSubnetMemberships", &GetSubnetMembershipsArgs{
+		NodeID: nodeID.PrefixedString(constants.NodeIDPrefix),
+	}, res, options...)
+	return res.Subnets, err
+}
+
 func (c *client) CreateSubnet(
 	ctx context.Context,
 	user api.UserPass,
@@ -612,6 +849,43 @@ func (c *client) IssueTx(ctx context.Context, txBytes []byte, options ...rpc.Opt
 	return res.TxID, err
 }
 
+func (c *client) SimulateTx(ctx context.Context, txBytes []byte, options ...rpc.Option) (*api.SimulateTxReply, error) {
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.SimulateTxReply{}
+	err = c.requester.SendRequest(ctx, "simulateTx", &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) DecodeTx(ctx context.Context, txBytes []byte, options ...rpc.Option) (*api.DecodeTxReply, error) {
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.DecodeTxReply{}
+	err = c.requester.SendRequest(ctx, "decodeTx", &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) GetImportStatus(ctx context.Context, sourceChain string, exportTxID ids.ID, options ...rpc.Option) (*api.GetImportStatusReply, error) {
+	res := &api.GetImportStatusReply{}
+	err := c.requester.SendRequest(ctx, "getImportStatus", &api.GetImportStatusArgs{
+		SourceChain: sourceChain,
+		ExportTxID:  exportTxID,
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error) {
 	res := &api.FormattedTx{}
 	err := c.requester.SendRequest(ctx, "getTx", &api.GetTxArgs{
@@ -654,6 +928,65 @@ func (c *client) AwaitTxDecided(ctx context.Context, txID ids.ID, includeReason
 	}
 }
 
+func (c *client) AwaitTxStatus(
+	ctx context.Context,
+	txID ids.ID,
+	stage TxStage,
+	timeout time.Duration,
+	includeProof bool,
+	options ...rpc.Option,
+) (*AwaitTxStatusReply, error) {
+	res := &AwaitTxStatusReply{}
+	err := c.txRequester.SendRequest(ctx, "awaitTxStatus", &AwaitTxStatusArgs{
+		TxID:         txID,
+		Stage:        stage,
+		Timeout:      json.Uint64(timeout.Milliseconds()),
+		IncludeProof: includeProof,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) IssueTxAndAwait(
+	ctx context.Context,
+	txBytes []byte,
+	includeProof bool,
+	onStage func(*AwaitTxStatusReply),
+	options ...rpc.Option,
+) (*AwaitTxStatusReply, error) {
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	args := &AwaitTxStatusArgs{
+		FormattedTx: api.FormattedTx{
+			Tx:       txStr,
+			Encoding: formatting.Hex,
+		},
+		IncludeProof: includeProof,
+	}
+	for {
+		res := &AwaitTxStatusReply{}
+		if err := c.txRequester.SendRequest(ctx, "awaitTxStatus", args, res, options...); err != nil {
+			return nil, err
+		}
+		if res.Stage != args.Stage && onStage != nil {
+			onStage(res)
+		}
+		switch res.Stage {
+		case TxStageAccepted, TxStageRejected:
+			return res, nil
+		}
+
+		// The tx only needs to be issued once
+		args = &AwaitTxStatusArgs{
+			TxID:         res.TxID,
+			Stage:        res.Stage,
+			IncludeProof: includeProof,
+		}
+	}
+}
+
 func (c *client) GetStake(ctx context.Context, addrs []string, options ...rpc.Option) (*GetStakeReply, error) {
 	res := new(GetStakeReply)
 	err := c.requester.SendRequest(ctx, "getStake", &api.JSONAddresses{
@@ -717,6 +1050,28 @@ func (c *client) GetValidatorsAt(ctx context.Context, subnetID ids.ID, height ui
 	return res.Validators, err
 }
 
+func (c *client) GetValidatorSetProof(ctx context.Context, subnetID ids.ID, height uint64, nodeID ids.ShortID, options ...rpc.Option) (ids.ID, uint64, *merkledb.Proof, error) {
+	res := &GetValidatorSetProofReply{}
+	if err := c.requester.SendRequest(ctx, "getValidatorSetProof", &GetValidatorSetProofArgs{
+		Height:   json.Uint64(height),
+		SubnetID: subnetID,
+		NodeID:   nodeID.PrefixedString(constants.NodeIDPrefix),
+		Encoding: formatting.Hex,
+	}, res, options...); err != nil {
+		return ids.ID{}, 0, nil, err
+	}
+
+	proofBytes, err := formatting.Decode(res.Encoding, res.Proof)
+	if err != nil {
+		return ids.ID{}, 0, nil, err
+	}
+	proof := &merkledb.Proof{}
+	if _, err := merkledb.Codec.Unmarshal(proofBytes, proof); err != nil {
+		return ids.ID{}, 0, nil, err
+	}
+	return res.Root, uint64(res.Weight), proof, nil
+}
+
 func (c *client) GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error) {
 	response := &api.FormattedBlock{}
 	if err := c.requester.SendRequest(ctx, "getBlock", &api.GetBlockArgs{
@@ -728,3 +1083,59 @@ func (c *client) GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Op
 
 	return formatting.Decode(response.Encoding, response.Block)
 }
+
+func (c *client) GetAddressFilters(ctx context.Context, startHeight uint64, limit uint32, options ...rpc.Option) ([]BlockAddressFilter, error) {
+	res := &GetAddressFiltersReply{}
+	if err := c.requester.SendRequest(ctx, "getAddressFilters", &GetAddressFiltersArgs{
+		StartHeight: json.Uint64(startHeight),
+		Limit:       json.Uint32(limit),
+		Encoding:    formatting.Hex,
+	}, res, options...); err != nil {
+		return nil, err
+	}
+
+	filters := make([]BlockAddressFilter, len(res.Filters))
+	for i, apiFilter := range res.Filters {
+		filters[i] = BlockAddressFilter{
+			Height:  uint64(apiFilter.Height),
+			BlockID: apiFilter.BlockID,
+		}
+		if apiFilter.Filter == "" {
+			continue
+		}
+		filterBytes, err := formatting.Decode(res.Encoding, apiFilter.Filter)
+		if err != nil {
+			return nil, err
+		}
+		filters[i].Filter, err = bloom.Parse(filterBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return filters, nil
+}
+
+func (c *client) GetBlocksByHeight(ctx context.Context, startHeight, endHeight uint64, options ...rpc.Option) ([][]byte, error) {
+	res := &GetBlocksByHeightReply{}
+	if err := c.requester.SendRequest(ctx, "getBlocksByHeight", &GetBlocksByHeightArgs{
+		StartHeight: json.Uint64(startHeight),
+		EndHeight:   json.Uint64(endHeight),
+		Encoding:    formatting.Hex,
+	}, res, options...); err != nil {
+		return nil, err
+	}
+
+	blks := make([][]byte, len(res.Blocks))
+	for i, apiBlk := range res.Blocks {
+		blkStr, ok := apiBlk.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected the block to be a string but got %T", apiBlk)
+		}
+		blkBytes, err := formatting.Decode(res.Encoding, blkStr)
+		if err != nil {
+			return nil, err
+		}
+		blks[i] = blkBytes
+	}
+	return blks, nil
+}
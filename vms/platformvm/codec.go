@@ -64,6 +64,11 @@ func init() {
 
 			c.RegisterType(&StakeableLockIn{}),
 			c.RegisterType(&StakeableLockOut{}),
+
+			c.RegisterType(&UnsignedSetDelegationFeeTx{}),
+			c.RegisterType(&UnsignedSetSubnetFeeConfigTx{}),
+			c.RegisterType(&UnsignedSetSubnetVMConfigTx{}),
+			c.RegisterType(&UnsignedRemoveSubnetValidatorTx{}),
 		)
 	}
 	errs.Add(
@@ -227,18 +227,29 @@ func (b *CommonBlock) Reject() error {
 	b.status = choices.Rejected
 	// TODO: don't write rejected blocks to disk
 	b.vm.internalState.AddBlock(b.self)
+	b.vm.txNotifier.notify()
 	return b.vm.internalState.Commit()
 }
 
 func (b *CommonBlock) Accept() error {
 	blkID := b.ID()
 
+	addressFilter, err := b.vm.blockAddressFilter(b.self)
+	if err != nil {
+		return fmt.Errorf("failed to build the address filter of %s: %w", blkID, err)
+	}
+
 	b.status = choices.Accepted
 	b.vm.internalState.AddBlock(b.self)
 	b.vm.internalState.SetLastAccepted(blkID)
 	b.vm.internalState.SetHeight(b.Hght)
+	b.vm.internalState.AddAddressFilter(b.Hght, blkID, addressFilter)
 	b.vm.lastAcceptedID = blkID
 	b.vm.recentlyAccepted.Add(blkID)
+	for _, tx := range blockTxs(b.self) {
+		b.vm.acceptedTxBlocks.Put(tx.ID(), blkID)
+	}
+	b.vm.txNotifier.notify()
 	return b.vm.metrics.AcceptBlock(b.self)
 }
 
@@ -275,6 +286,7 @@ func (cdb *CommonDecisionBlock) Reject() error {
 	cdb.status = choices.Rejected
 	// TODO: don't write rejected blocks to disk
 	cdb.vm.internalState.AddBlock(cdb.self)
+	cdb.vm.txNotifier.notify()
 	return cdb.vm.internalState.Commit()
 }
 
@@ -171,12 +171,12 @@ func (tx *UnsignedExportTx) AtomicExecute(
 }
 
 // Accept this transaction.
-func (tx *UnsignedExportTx) AtomicAccept(ctx *snow.Context, batch database.Batch) error {
+func (tx *UnsignedExportTx) AtomicAccept(ctx *snow.Context, batches ...database.Batch) error {
 	chainID, requests, err := tx.AtomicOperations()
 	if err != nil {
 		return err
 	}
-	return ctx.SharedMemory.Apply(map[ids.ID]*atomic.Requests{chainID: requests}, batch)
+	return ctx.SharedMemory.Apply(map[ids.ID]*atomic.Requests{chainID: requests}, batches...)
 }
 
 // Create a new transaction
@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/uptime"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/vms/platformvm/churn"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 )
 
@@ -70,6 +71,9 @@ type Factory struct {
 	// Config for the minting function
 	RewardConfig reward.Config
 
+	// Limits how quickly the stake of a subnet can change
+	ChurnConfig churn.Config
+
 	// Time of the AP3 network upgrade
 	ApricotPhase3Time time.Time
 
@@ -78,6 +82,9 @@ type Factory struct {
 
 	// Time of the AP5 network upgrade
 	ApricotPhase5Time time.Time
+
+	// Time of the AP6 network upgrade
+	ApricotPhase6Time time.Time
 }
 
 // New returns a new instance of the Platform Chain
@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var _ common.FeeEstimator = &VM{}
+
+// EstimateFees implements the common.FeeEstimator interface. The fees of the
+// P-chain are static, so they don't depend on the size of the tx. They're
+// estimated at the timestamp of the last accepted block.
+func (vm *VM) EstimateFees(txType string, _ uint64) (common.FeeEstimate, error) {
+	timestamp := vm.internalState.GetTimestamp()
+	fees := map[string]uint64{
+		"addValidator":          vm.AddStakerTxFee,
+		"addDelegator":          vm.AddStakerTxFee,
+		"addSubnetValidator":    vm.TxFee,
+		"removeSubnetValidator": vm.TxFee,
+		"createSubnet":          vm.getCreateSubnetTxFee(timestamp),
+		"createChain":           vm.getCreateBlockchainTxFee(timestamp),
+		"import":                vm.TxFee,
+		"export":                vm.TxFee,
+		"setDelegationFee":      vm.TxFee,
+		"setSubnetFeeConfig":    vm.TxFee,
+		"setSubnetVMConfig":     vm.TxFee,
+	}
+	if txType != "" {
+		fee, ok := fees[txType]
+		if !ok {
+			return common.FeeEstimate{}, fmt.Errorf("%w: %q", common.ErrUnknownTxType, txType)
+		}
+		fees = map[string]uint64{txType: fee}
+	}
+	return common.FeeEstimate{
+		FeeAssetID: vm.ctx.AVAXAssetID,
+		Fees:       fees,
+	}, nil
+}
@@ -188,12 +188,36 @@ func (tx *UnsignedImportTx) AtomicExecute(
 // we don't want to remove an imported UTXO in semanticVerify
 // only to have the transaction not be Accepted. This would be inconsistent.
 // Recall that imported UTXOs are not kept in a versionDB.
-func (tx *UnsignedImportTx) AtomicAccept(ctx *snow.Context, batch database.Batch) error {
+func (tx *UnsignedImportTx) AtomicAccept(ctx *snow.Context, batches ...database.Batch) error {
 	chainID, requests, err := tx.AtomicOperations()
 	if err != nil {
 		return err
 	}
-	return ctx.SharedMemory.Apply(map[ids.ID]*atomic.Requests{chainID: requests}, batch)
+	return ctx.SharedMemory.Apply(map[ids.ID]*atomic.Requests{chainID: requests}, batches...)
+}
+
+// indexImports returns the batches that record, in the import index, the
+// outputs consumed by the import txs in [txs]. The batches must be written
+// atomically with the acceptance of [txs].
+func (vm *VM) indexImports(txs []*Tx) ([]database.Batch, error) {
+	var batches []database.Batch
+	for _, tx := range txs {
+		importTx, ok := tx.UnsignedTx.(*UnsignedImportTx)
+		if !ok {
+			continue
+		}
+
+		utxoIDs := make([]*avax.UTXOID, len(importTx.ImportedInputs))
+		for i, in := range importTx.ImportedInputs {
+			utxoIDs[i] = &in.UTXOID
+		}
+		batch, err := vm.importIndex.Index(importTx.SourceChain, tx.ID(), utxoIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index import tx %s: %w", tx.ID(), err)
+		}
+		batches = append(batches, batch)
+	}
+	return batches, nil
 }
 
 // Create a new transaction
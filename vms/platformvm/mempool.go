@@ -37,6 +37,9 @@ type Mempool interface {
 	Add(tx *Tx) error
 	Has(txID ids.ID) bool
 	Get(txID ids.ID) *Tx
+	// List returns the txs that are waiting to be issued, in no particular
+	// order
+	List() []*Tx
 
 	AddDecisionTx(tx *Tx)
 	AddProposalTx(tx *Tx)
@@ -162,6 +165,10 @@ func (m *mempool) Get(txID ids.ID) *Tx {
 	return m.unissuedProposalTxs.Get(txID)
 }
 
+func (m *mempool) List() []*Tx {
+	return append(m.unissuedDecisionTxs.List(), m.unissuedProposalTxs.List()...)
+}
+
 func (m *mempool) AddDecisionTx(tx *Tx) {
 	m.unissuedDecisionTxs.Add(tx)
 	m.register(tx)
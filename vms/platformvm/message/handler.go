@@ -13,6 +13,7 @@ var _ Handler = NoopHandler{}
 
 type Handler interface {
 	HandleTx(nodeID ids.ShortID, requestID uint32, msg *Tx) error
+	HandleTxFilter(nodeID ids.ShortID, requestID uint32, msg *TxFilter) error
 }
 
 type NoopHandler struct {
@@ -27,3 +28,12 @@ func (h NoopHandler) HandleTx(nodeID ids.ShortID, requestID uint32, _ *Tx) error
 	)
 	return nil
 }
+
+func (h NoopHandler) HandleTxFilter(nodeID ids.ShortID, requestID uint32, _ *TxFilter) error {
+	h.Log.Debug(
+		"dropping unexpected TxFilter message from %s with requestID %s",
+		nodeID.PrefixedString(constants.NodeIDPrefix),
+		requestID,
+	)
+	return nil
+}
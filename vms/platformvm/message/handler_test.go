@@ -13,7 +13,8 @@ import (
 )
 
 type CounterHandler struct {
-	Tx int
+	Tx       int
+	TxFilter int
 }
 
 func (h *CounterHandler) HandleTx(ids.ShortID, uint32, *Tx) error {
@@ -21,6 +22,11 @@ func (h *CounterHandler) HandleTx(ids.ShortID, uint32, *Tx) error {
 	return nil
 }
 
+func (h *CounterHandler) HandleTxFilter(ids.ShortID, uint32, *TxFilter) error {
+	h.TxFilter++
+	return nil
+}
+
 func TestHandleTx(t *testing.T) {
 	assert := assert.New(t)
 
@@ -32,6 +38,17 @@ func TestHandleTx(t *testing.T) {
 	assert.Equal(1, handler.Tx)
 }
 
+func TestHandleTxFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := CounterHandler{}
+	msg := TxFilter{}
+
+	err := msg.Handle(&handler, ids.ShortEmpty, 0)
+	assert.NoError(err)
+	assert.Equal(1, handler.TxFilter)
+}
+
 func TestNoopHandler(t *testing.T) {
 	assert := assert.New(t)
 
@@ -41,4 +58,7 @@ func TestNoopHandler(t *testing.T) {
 
 	err := handler.HandleTx(ids.ShortEmpty, 0, nil)
 	assert.NoError(err)
+
+	err = handler.HandleTxFilter(ids.ShortEmpty, 0, nil)
+	assert.NoError(err)
 }
@@ -11,6 +11,7 @@ import (
 
 var (
 	_ Message = &Tx{}
+	_ Message = &TxFilter{}
 
 	errUnexpectedCodecVersion = errors.New("unexpected codec version")
 )
@@ -43,6 +44,18 @@ func (msg *Tx) Handle(handler Handler, nodeID ids.ShortID, requestID uint32) err
 	return handler.HandleTx(nodeID, requestID, msg)
 }
 
+// TxFilter is a bloom filter of the IDs of the txs the sender has, so that the
+// receiver can skip gossiping those txs to it.
+type TxFilter struct {
+	message
+
+	Filter []byte `serialize:"true"`
+}
+
+func (msg *TxFilter) Handle(handler Handler, nodeID ids.ShortID, requestID uint32) error {
+	return handler.HandleTxFilter(nodeID, requestID, msg)
+}
+
 func Parse(bytes []byte) (Message, error) {
 	var msg Message
 	version, err := c.Unmarshal(bytes, &msg)
@@ -33,6 +33,27 @@ func TestTx(t *testing.T) {
 	assert.Equal(tx, parsedMsg.Tx)
 }
 
+func TestTxFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	filter := utils.RandomBytes(64 * units.KiB)
+	builtMsg := TxFilter{
+		Filter: filter,
+	}
+	builtMsgBytes, err := Build(&builtMsg)
+	assert.NoError(err)
+	assert.Equal(builtMsgBytes, builtMsg.Bytes())
+
+	parsedMsgIntf, err := Parse(builtMsgBytes)
+	assert.NoError(err)
+	assert.Equal(builtMsgBytes, parsedMsgIntf.Bytes())
+
+	parsedMsg, ok := parsedMsgIntf.(*TxFilter)
+	assert.True(ok)
+
+	assert.Equal(filter, parsedMsg.Filter)
+}
+
 func TestParseGibberish(t *testing.T) {
 	assert := assert.New(t)
 
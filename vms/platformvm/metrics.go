@@ -39,7 +39,11 @@ type metrics struct {
 	numCreateSubnetTxs,
 	numExportTxs,
 	numImportTxs,
-	numRewardValidatorTxs prometheus.Counter
+	numRewardValidatorTxs,
+	numSetDelegationFeeTxs,
+	numSetSubnetFeeConfigTxs,
+	numSetSubnetVMConfigTxs,
+	numRemoveSubnetValidatorTxs prometheus.Counter
 
 	validatorSetsCached     prometheus.Counter
 	validatorSetsCreated    prometheus.Counter
@@ -121,6 +125,10 @@ func (m *metrics) Initialize(
 	m.numExportTxs = newTxMetrics(namespace, "export")
 	m.numImportTxs = newTxMetrics(namespace, "import")
 	m.numRewardValidatorTxs = newTxMetrics(namespace, "reward_validator")
+	m.numSetDelegationFeeTxs = newTxMetrics(namespace, "set_delegation_fee")
+	m.numSetSubnetFeeConfigTxs = newTxMetrics(namespace, "set_subnet_fee_config")
+	m.numSetSubnetVMConfigTxs = newTxMetrics(namespace, "set_subnet_vm_config")
+	m.numRemoveSubnetValidatorTxs = newTxMetrics(namespace, "remove_subnet_validator")
 
 	m.validatorSetsCached = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: namespace,
@@ -172,6 +180,10 @@ func (m *metrics) Initialize(
 		registerer.Register(m.numExportTxs),
 		registerer.Register(m.numImportTxs),
 		registerer.Register(m.numRewardValidatorTxs),
+		registerer.Register(m.numSetDelegationFeeTxs),
+		registerer.Register(m.numSetSubnetFeeConfigTxs),
+		registerer.Register(m.numSetSubnetVMConfigTxs),
+		registerer.Register(m.numRemoveSubnetValidatorTxs),
 
 		registerer.Register(m.validatorSetsCreated),
 		registerer.Register(m.validatorSetsCached),
@@ -232,6 +244,14 @@ func (m *metrics) AcceptTx(tx *Tx) error {
 		m.numExportTxs.Inc()
 	case *UnsignedRewardValidatorTx:
 		m.numRewardValidatorTxs.Inc()
+	case *UnsignedSetDelegationFeeTx:
+		m.numSetDelegationFeeTxs.Inc()
+	case *UnsignedSetSubnetFeeConfigTx:
+		m.numSetSubnetFeeConfigTxs.Inc()
+	case *UnsignedSetSubnetVMConfigTx:
+		m.numSetSubnetVMConfigTxs.Inc()
+	case *UnsignedRemoveSubnetValidatorTx:
+		m.numRemoveSubnetValidatorTxs.Inc()
 	default:
 		return fmt.Errorf("%w: %T", errUnknownTxType, tx.UnsignedTx)
 	}
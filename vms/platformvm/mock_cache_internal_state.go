@@ -50,6 +50,18 @@ func (mr *MockInternalStateMockRecorder) Abort() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abort", reflect.TypeOf((*MockInternalState)(nil).Abort))
 }
 
+// AddAddressFilter mocks base method.
+func (m *MockInternalState) AddAddressFilter(height uint64, blkID ids.ID, filter []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddAddressFilter", height, blkID, filter)
+}
+
+// AddAddressFilter indicates an expected call of AddAddressFilter.
+func (mr *MockInternalStateMockRecorder) AddAddressFilter(height, blkID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAddressFilter", reflect.TypeOf((*MockInternalState)(nil).AddAddressFilter), height, blkID, filter)
+}
+
 // AddBlock mocks base method.
 func (m *MockInternalState) AddBlock(block Block) {
 	m.ctrl.T.Helper()
@@ -86,6 +98,18 @@ func (mr *MockInternalStateMockRecorder) AddCurrentStaker(tx, potentialReward in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCurrentStaker", reflect.TypeOf((*MockInternalState)(nil).AddCurrentStaker), tx, potentialReward)
 }
 
+// AddDelegationFeeChange mocks base method.
+func (m *MockInternalState) AddDelegationFeeChange(setDelegationFeeTx *Tx) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddDelegationFeeChange", setDelegationFeeTx)
+}
+
+// AddDelegationFeeChange indicates an expected call of AddDelegationFeeChange.
+func (mr *MockInternalStateMockRecorder) AddDelegationFeeChange(setDelegationFeeTx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDelegationFeeChange", reflect.TypeOf((*MockInternalState)(nil).AddDelegationFeeChange), setDelegationFeeTx)
+}
+
 // AddPendingStaker mocks base method.
 func (m *MockInternalState) AddPendingStaker(tx *Tx) {
 	m.ctrl.T.Helper()
@@ -146,6 +170,18 @@ func (mr *MockInternalStateMockRecorder) AddUTXO(utxo interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUTXO", reflect.TypeOf((*MockInternalState)(nil).AddUTXO), utxo)
 }
 
+// AddValidatorSetRoot mocks base method.
+func (m *MockInternalState) AddValidatorSetRoot(height uint64, subnetID, root ids.ID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddValidatorSetRoot", height, subnetID, root)
+}
+
+// AddValidatorSetRoot indicates an expected call of AddValidatorSetRoot.
+func (mr *MockInternalStateMockRecorder) AddValidatorSetRoot(height, subnetID, root interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddValidatorSetRoot", reflect.TypeOf((*MockInternalState)(nil).AddValidatorSetRoot), height, subnetID, root)
+}
+
 // Close mocks base method.
 func (m *MockInternalState) Close() error {
 	m.ctrl.T.Helper()
@@ -239,6 +275,22 @@ func (mr *MockInternalStateMockRecorder) DeleteUTXO(utxoID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUTXO", reflect.TypeOf((*MockInternalState)(nil).DeleteUTXO), utxoID)
 }
 
+// GetAddressFilter mocks base method.
+func (m *MockInternalState) GetAddressFilter(height uint64) (ids.ID, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddressFilter", height)
+	ret0, _ := ret[0].(ids.ID)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAddressFilter indicates an expected call of GetAddressFilter.
+func (mr *MockInternalStateMockRecorder) GetAddressFilter(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressFilter", reflect.TypeOf((*MockInternalState)(nil).GetAddressFilter), height)
+}
+
 // GetBlock mocks base method.
 func (m *MockInternalState) GetBlock(blockID ids.ID) (Block, error) {
 	m.ctrl.T.Helper()
@@ -283,6 +335,21 @@ func (mr *MockInternalStateMockRecorder) GetCurrentSupply() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentSupply", reflect.TypeOf((*MockInternalState)(nil).GetCurrentSupply))
 }
 
+// GetDelegationFeeChanges mocks base method.
+func (m *MockInternalState) GetDelegationFeeChanges(validatorTxID ids.ID) ([]*Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelegationFeeChanges", validatorTxID)
+	ret0, _ := ret[0].([]*Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelegationFeeChanges indicates an expected call of GetDelegationFeeChanges.
+func (mr *MockInternalStateMockRecorder) GetDelegationFeeChanges(validatorTxID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegationFeeChanges", reflect.TypeOf((*MockInternalState)(nil).GetDelegationFeeChanges), validatorTxID)
+}
+
 // GetLastAccepted mocks base method.
 func (m *MockInternalState) GetLastAccepted() ids.ID {
 	m.ctrl.T.Helper()
@@ -327,6 +394,36 @@ func (mr *MockInternalStateMockRecorder) GetStartTime(nodeID interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStartTime", reflect.TypeOf((*MockInternalState)(nil).GetStartTime), nodeID)
 }
 
+// GetSubnetFeeConfig mocks base method.
+func (m *MockInternalState) GetSubnetFeeConfig(subnetID ids.ID) (*Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetFeeConfig", subnetID)
+	ret0, _ := ret[0].(*Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetFeeConfig indicates an expected call of GetSubnetFeeConfig.
+func (mr *MockInternalStateMockRecorder) GetSubnetFeeConfig(subnetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetFeeConfig", reflect.TypeOf((*MockInternalState)(nil).GetSubnetFeeConfig), subnetID)
+}
+
+// GetSubnetVMConfig mocks base method.
+func (m *MockInternalState) GetSubnetVMConfig(subnetID ids.ID) (*Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetVMConfig", subnetID)
+	ret0, _ := ret[0].(*Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetVMConfig indicates an expected call of GetSubnetVMConfig.
+func (mr *MockInternalStateMockRecorder) GetSubnetVMConfig(subnetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetVMConfig", reflect.TypeOf((*MockInternalState)(nil).GetSubnetVMConfig), subnetID)
+}
+
 // GetSubnets mocks base method.
 func (m *MockInternalState) GetSubnets() ([]*Tx, error) {
 	m.ctrl.T.Helper()
@@ -403,6 +500,21 @@ func (mr *MockInternalStateMockRecorder) GetUptime(nodeID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUptime", reflect.TypeOf((*MockInternalState)(nil).GetUptime), nodeID)
 }
 
+// GetValidatorSetRoot mocks base method.
+func (m *MockInternalState) GetValidatorSetRoot(height uint64, subnetID ids.ID) (ids.ID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorSetRoot", height, subnetID)
+	ret0, _ := ret[0].(ids.ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidatorSetRoot indicates an expected call of GetValidatorSetRoot.
+func (mr *MockInternalStateMockRecorder) GetValidatorSetRoot(height, subnetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorSetRoot", reflect.TypeOf((*MockInternalState)(nil).GetValidatorSetRoot), height, subnetID)
+}
+
 // GetValidatorWeightDiffs mocks base method.
 func (m *MockInternalState) GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.ShortID]*ValidatorWeightDiff, error) {
 	m.ctrl.T.Helper()
@@ -492,6 +604,30 @@ func (mr *MockInternalStateMockRecorder) SetPendingStakerChainState(arg0 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingStakerChainState", reflect.TypeOf((*MockInternalState)(nil).SetPendingStakerChainState), arg0)
 }
 
+// SetSubnetFeeConfig mocks base method.
+func (m *MockInternalState) SetSubnetFeeConfig(setSubnetFeeConfigTx *Tx) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSubnetFeeConfig", setSubnetFeeConfigTx)
+}
+
+// SetSubnetFeeConfig indicates an expected call of SetSubnetFeeConfig.
+func (mr *MockInternalStateMockRecorder) SetSubnetFeeConfig(setSubnetFeeConfigTx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubnetFeeConfig", reflect.TypeOf((*MockInternalState)(nil).SetSubnetFeeConfig), setSubnetFeeConfigTx)
+}
+
+// SetSubnetVMConfig mocks base method.
+func (m *MockInternalState) SetSubnetVMConfig(setSubnetVMConfigTx *Tx) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSubnetVMConfig", setSubnetVMConfigTx)
+}
+
+// SetSubnetVMConfig indicates an expected call of SetSubnetVMConfig.
+func (mr *MockInternalStateMockRecorder) SetSubnetVMConfig(setSubnetVMConfigTx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubnetVMConfig", reflect.TypeOf((*MockInternalState)(nil).SetSubnetVMConfig), setSubnetVMConfigTx)
+}
+
 // SetTimestamp mocks base method.
 func (m *MockInternalState) SetTimestamp(arg0 time.Time) {
 	m.ctrl.T.Helper()
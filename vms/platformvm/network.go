@@ -5,13 +5,18 @@ package platformvm
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/bloom"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/platformvm/message"
 )
 
@@ -19,6 +24,24 @@ const (
 	// We allow [recentCacheSize] to be fairly large because we only store hashes
 	// in the cache, not entire transactions.
 	recentCacheSize = 512
+
+	// txFilterGossipFrequency is how often the filter of the txs in the
+	// mempool is gossiped to the connected peers
+	txFilterGossipFrequency = 30 * time.Second
+	// Filters that haven't been refreshed by their peer within
+	// [txFilterExpiry] are dropped, as their peer has likely changed its
+	// mempool since
+	txFilterExpiry = 3 * txFilterGossipFrequency
+	// The filter of the txs in the mempool is sized so that, when it holds
+	// [txFilterMaxTxs] txs, a tx that isn't in the mempool is reported as in
+	// it with probability [txFilterFalsePositiveProbability]
+	txFilterMaxTxs                   = 4096
+	txFilterFalsePositiveProbability = .01
+	maxTxFilterSize                  = 64 * units.KiB
+
+	// txGossipSize is the number of peers a tx is gossiped to when some peers
+	// are known to have it already
+	txGossipSize = 10
 )
 
 type network struct {
@@ -29,6 +52,22 @@ type network struct {
 	mempool              *blockBuilder
 	vm                   *VM
 	recentTxs            *cache.LRU
+
+	// Periodically gossips the filter of the txs in the mempool
+	txFilterGossiper *timer.Repeater
+
+	lock sync.Mutex
+	// Connected peers
+	peers ids.ShortSet
+	// Maps a connected peer to the filter of the txs it is known to have
+	peerTxFilters map[ids.ShortID]*peerTxFilter
+}
+
+// peerTxFilter is the filter of the txs a peer has, according to the last
+// filter it sent and the txs that were exchanged with it since
+type peerTxFilter struct {
+	filter    bloom.Filter
+	refreshed time.Time
 }
 
 func newNetwork(activationTime time.Time, appSender common.AppSender, vm *VM) *network {
@@ -39,11 +78,39 @@ func newNetwork(activationTime time.Time, appSender common.AppSender, vm *VM) *n
 		mempool:              &vm.blockBuilder,
 		vm:                   vm,
 		recentTxs:            &cache.LRU{Size: recentCacheSize},
+		peerTxFilters:        make(map[ids.ShortID]*peerTxFilter),
 	}
+	n.txFilterGossiper = timer.NewRepeater(n.gossipTxFilter, txFilterGossipFrequency)
+	go vm.ctx.Log.RecoverAndPanic(n.txFilterGossiper.Dispatch)
 
 	return n
 }
 
+// Shutdown stops the periodic gossip of the filter of the mempool. The context
+// lock must be held.
+func (n *network) Shutdown() {
+	// The gossiper grabs the context lock, so it must be released before
+	// waiting for the gossiper to stop.
+	n.vm.ctx.Lock.Unlock()
+	n.txFilterGossiper.Stop()
+	n.vm.ctx.Lock.Lock()
+}
+
+func (n *network) connected(nodeID ids.ShortID) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.peers.Add(nodeID)
+}
+
+func (n *network) disconnected(nodeID ids.ShortID) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.peers.Remove(nodeID)
+	delete(n.peerTxFilters, nodeID)
+}
+
 func (n *network) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
 	// This VM currently only supports gossiping of txs, so there are no
 	// requests.
@@ -80,8 +147,14 @@ func (n *network) AppGossip(nodeID ids.ShortID, msgBytes []byte) error {
 		return nil
 	}
 
-	msg, ok := msgIntf.(*message.Tx)
-	if !ok {
+	var msg *message.Tx
+	switch msgIntf := msgIntf.(type) {
+	case *message.Tx:
+		msg = msgIntf
+	case *message.TxFilter:
+		n.setPeerTxFilter(nodeID, msgIntf.Filter)
+		return nil
+	default:
 		n.log.Debug(
 			"dropping unexpected message from %s",
 			nodeID.PrefixedString(constants.NodeIDPrefix),
@@ -102,6 +175,7 @@ func (n *network) AppGossip(nodeID ids.ShortID, msgBytes []byte) error {
 	tx.Initialize(unsignedBytes, msg.Tx)
 
 	txID := tx.ID()
+	n.markPeerHasTx(nodeID, txID)
 
 	// We need to grab the context lock here to avoid racy behavior with
 	// transaction verification + mempool modifications.
@@ -141,5 +215,159 @@ func (n *network) GossipTx(tx *Tx) error {
 	if err != nil {
 		return fmt.Errorf("GossipTx: failed to build Tx message with: %w", err)
 	}
-	return n.appSender.SendAppGossip(msgBytes)
+
+	peers, filtered := n.peersWithoutTx(txID)
+	if !filtered {
+		// No peer is known to have the tx, so it's gossiped as usual
+		return n.appSender.SendAppGossip(msgBytes)
+	}
+	if peers.Len() == 0 {
+		n.log.Verbo("not gossiping tx %s as all the peers have it", txID)
+		return nil
+	}
+	return n.appSender.SendAppGossipSpecific(peers, msgBytes)
+}
+
+// peersWithoutTx returns up to [txGossipSize] connected peers that aren't
+// known to have [txID], which are marked as having it. If no peer is known to
+// have [txID], false is returned.
+func (n *network) peersWithoutTx(txID ids.ID) (ids.ShortSet, bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	now := n.vm.clock.Time()
+	candidates := make([]ids.ShortID, 0, n.peers.Len())
+	for nodeID := range n.peers {
+		peerFilter, ok := n.peerTxFilters[nodeID]
+		if ok && now.Sub(peerFilter.refreshed) > txFilterExpiry {
+			delete(n.peerTxFilters, nodeID)
+			ok = false
+		}
+		if !ok || !peerFilter.filter.Check(txID[:]) {
+			candidates = append(candidates, nodeID)
+		}
+	}
+	if len(candidates) == n.peers.Len() {
+		return nil, false
+	}
+
+	peers := ids.NewShortSet(txGossipSize)
+	if len(candidates) <= txGossipSize {
+		peers.Add(candidates...)
+	} else {
+		s := sampler.NewUniform()
+		if err := s.Initialize(uint64(len(candidates))); err != nil {
+			n.log.Error("failed to initialize the peer sampler: %s", err)
+			return nil, false
+		}
+		indices, err := s.Sample(txGossipSize)
+		if err != nil {
+			n.log.Error("failed to sample peers: %s", err)
+			return nil, false
+		}
+		for _, index := range indices {
+			peers.Add(candidates[index])
+		}
+	}
+
+	for nodeID := range peers {
+		if peerFilter, ok := n.peerTxFilters[nodeID]; ok {
+			peerFilter.filter.Add(txID[:])
+		}
+	}
+	return peers, true
+}
+
+// markPeerHasTx records that [nodeID] has [txID], if the txs of [nodeID] are
+// tracked.
+func (n *network) markPeerHasTx(nodeID ids.ShortID, txID ids.ID) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if peerFilter, ok := n.peerTxFilters[nodeID]; ok {
+		peerFilter.filter.Add(txID[:])
+	}
+}
+
+// setPeerTxFilter replaces the filter of the txs [nodeID] has with [filterBytes]
+func (n *network) setPeerTxFilter(nodeID ids.ShortID, filterBytes []byte) {
+	if len(filterBytes) > maxTxFilterSize {
+		n.log.Debug(
+			"dropping tx filter of %d bytes from %s",
+			len(filterBytes),
+			nodeID.PrefixedString(constants.NodeIDPrefix),
+		)
+		return
+	}
+	filter, err := bloom.Parse(filterBytes)
+	if err != nil {
+		n.log.Debug(
+			"dropping tx filter from %s due to: %s",
+			nodeID.PrefixedString(constants.NodeIDPrefix),
+			err,
+		)
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if !n.peers.Contains(nodeID) {
+		return
+	}
+	n.peerTxFilters[nodeID] = &peerTxFilter{
+		filter:    filter,
+		refreshed: n.vm.clock.Time(),
+	}
+}
+
+// gossipTxFilter sends the filter of the txs in the mempool to the connected
+// peers, so that they don't gossip those txs back.
+func (n *network) gossipTxFilter() {
+	if time.Now().Before(n.gossipActivationTime) {
+		return
+	}
+
+	n.vm.ctx.Lock.Lock()
+	txs := n.mempool.List()
+	n.vm.ctx.Lock.Unlock()
+
+	if len(txs) == 0 {
+		// An empty filter wouldn't change what is gossiped to this node
+		return
+	}
+
+	filter, err := bloom.NewSerializable(txFilterMaxTxs, txFilterFalsePositiveProbability, maxTxFilterSize)
+	if err != nil {
+		n.log.Error("failed to create tx filter: %s", err)
+		return
+	}
+	for _, tx := range txs {
+		txID := tx.ID()
+		filter.Add(txID[:])
+	}
+	filterBytes, err := filter.Bytes()
+	if err != nil {
+		n.log.Error("failed to serialize tx filter: %s", err)
+		return
+	}
+	msgBytes, err := message.Build(&message.TxFilter{
+		Filter: filterBytes,
+	})
+	if err != nil {
+		n.log.Error("failed to build TxFilter message: %s", err)
+		return
+	}
+
+	n.lock.Lock()
+	peers := ids.NewShortSet(n.peers.Len())
+	peers.Union(n.peers)
+	n.lock.Unlock()
+
+	if peers.Len() == 0 {
+		return
+	}
+	if err := n.appSender.SendAppGossipSpecific(peers, msgBytes); err != nil {
+		n.log.Error("failed to gossip tx filter: %s", err)
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bloom"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/vms/platformvm/message"
@@ -178,3 +179,140 @@ func TestMempoolNewLocaTxIsGossiped(t *testing.T) {
 
 	assert.True(gossipedBytes == nil)
 }
+
+// show that txs aren't gossiped to the peers whose filter reports they have
+// them
+func TestMempoolTxFilterSkipsPeersWithTx(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, sender := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+		vm.ctx.Lock.Unlock()
+	}()
+
+	vm.gossipActivationTime = time.Unix(0, 0) // enable mempool gossiping
+
+	var (
+		gossiped         bool
+		specificPeers    ids.ShortSet
+		specificGossiped bool
+	)
+	sender.SendAppGossipF = func([]byte) error {
+		gossiped = true
+		return nil
+	}
+	sender.SendAppGossipSpecificF = func(nodeIDs ids.ShortSet, _ []byte) error {
+		specificGossiped = true
+		specificPeers = nodeIDs
+		return nil
+	}
+
+	nodeIDWithTx := ids.GenerateTestShortID()
+	nodeIDWithoutTx := ids.GenerateTestShortID()
+	assert.NoError(vm.Connected(nodeIDWithTx, nil))
+	assert.NoError(vm.Connected(nodeIDWithoutTx, nil))
+
+	tx := getValidTx(vm, t)
+	txID := tx.ID()
+
+	// [nodeIDWithTx] reports that it has the tx and [nodeIDWithoutTx] reports
+	// that it doesn't
+	sendFilter := func(nodeID ids.ShortID, txIDs ...ids.ID) {
+		filter, err := bloom.NewSerializable(txFilterMaxTxs, txFilterFalsePositiveProbability, maxTxFilterSize)
+		assert.NoError(err)
+		for _, txID := range txIDs {
+			filter.Add(txID[:])
+		}
+		filterBytes, err := filter.Bytes()
+		assert.NoError(err)
+		msgBytes, err := message.Build(&message.TxFilter{
+			Filter: filterBytes,
+		})
+		assert.NoError(err)
+		vm.ctx.Lock.Unlock()
+		err = vm.AppGossip(nodeID, msgBytes)
+		vm.ctx.Lock.Lock()
+		assert.NoError(err)
+	}
+	sendFilter(nodeIDWithTx, txID)
+	sendFilter(nodeIDWithoutTx)
+
+	// The tx is only gossiped to the peer that doesn't have it
+	assert.NoError(vm.blockBuilder.AddUnverifiedTx(tx))
+	assert.False(gossiped)
+	assert.True(specificGossiped)
+	assert.Equal(1, specificPeers.Len())
+	assert.True(specificPeers.Contains(nodeIDWithoutTx))
+
+	// Once every peer has the tx, it isn't gossiped anymore
+	gossiped = false
+	specificGossiped = false
+	vm.recentTxs.Flush()
+	assert.NoError(vm.GossipTx(tx))
+	assert.False(gossiped)
+	assert.False(specificGossiped)
+
+	// Expired filters are ignored
+	specificGossiped = false
+	vm.clock.Set(vm.clock.Time().Add(txFilterExpiry + time.Second))
+	vm.recentTxs.Flush()
+	assert.NoError(vm.GossipTx(tx))
+	assert.True(gossiped)
+	assert.False(specificGossiped)
+}
+
+// show that the filter of the txs in the mempool is gossiped to the connected
+// peers
+func TestMempoolTxFilterIsGossiped(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, sender := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(err)
+		vm.ctx.Lock.Unlock()
+	}()
+
+	vm.gossipActivationTime = time.Unix(0, 0) // enable mempool gossiping
+
+	var (
+		gossipedPeers ids.ShortSet
+		gossipedBytes []byte
+	)
+	sender.SendAppGossipF = func([]byte) error { return nil }
+	sender.SendAppGossipSpecificF = func(nodeIDs ids.ShortSet, b []byte) error {
+		gossipedPeers = nodeIDs
+		gossipedBytes = b
+		return nil
+	}
+
+	nodeID := ids.GenerateTestShortID()
+	assert.NoError(vm.Connected(nodeID, nil))
+
+	// Nothing is gossiped while the mempool is empty
+	vm.ctx.Lock.Unlock()
+	vm.gossipTxFilter()
+	vm.ctx.Lock.Lock()
+	assert.Nil(gossipedBytes)
+
+	tx := getValidTx(vm, t)
+	txID := tx.ID()
+	assert.NoError(vm.blockBuilder.AddUnverifiedTx(tx))
+
+	vm.ctx.Lock.Unlock()
+	vm.gossipTxFilter()
+	vm.ctx.Lock.Lock()
+	assert.True(gossipedPeers.Contains(nodeID))
+
+	msgIntf, err := message.Parse(gossipedBytes)
+	assert.NoError(err)
+	msg, ok := msgIntf.(*message.TxFilter)
+	assert.True(ok)
+	filter, err := bloom.Parse(msg.Filter)
+	assert.NoError(err)
+	assert.True(filter.Check(txID[:]))
+}
@@ -134,7 +134,7 @@ func (pb *ProposalBlock) Verify() error {
 	pb.onCommitState, pb.onAbortState, err = tx.Execute(pb.vm, parentState, &pb.Tx)
 	if err != nil {
 		txID := tx.ID()
-		pb.vm.droppedTxCache.Put(txID, err.Error()) // cache tx as dropped
+		pb.vm.markDropped(txID, err.Error())
 		return err
 	}
 	pb.onCommitState.AddTx(&pb.Tx, status.Committed)
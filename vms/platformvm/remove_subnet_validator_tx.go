@@ -0,0 +1,188 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+)
+
+var (
+	errRemoveSubnetValidatorBeforeAP6 = errors.New("subnet validator removals aren't allowed before apricot phase 6")
+	errRemovePrimaryNetworkValidator  = errors.New("primary network validators can't be removed")
+	errNotSubnetValidator             = errors.New("node isn't a current or pending validator of the subnet")
+
+	_ UnsignedDecisionTx = &UnsignedRemoveSubnetValidatorTx{}
+)
+
+// UnsignedRemoveSubnetValidatorTx is an unsigned removeSubnetValidatorTx. It
+// removes a node from the current, or pending, validators of a subnet before
+// its end time, so that the owner of a permissioned subnet can revoke the
+// membership of a node it added.
+//
+// As the removal isn't scheduled ahead of time, it isn't checked against the
+// churn limit of the subnet.
+type UnsignedRemoveSubnetValidatorTx struct {
+	// Metadata, inputs and outputs
+	BaseTx `serialize:"true"`
+	// ID of the node to remove
+	NodeID ids.ShortID `serialize:"true" json:"nodeID"`
+	// ID of the subnet the node is removed from
+	Subnet ids.ID `serialize:"true" json:"subnet"`
+	// Proves that the owner of the subnet authorized this removal
+	SubnetAuth verify.Verifiable `serialize:"true" json:"subnetAuthorization"`
+}
+
+func (tx *UnsignedRemoveSubnetValidatorTx) InputUTXOs() ids.Set { return nil }
+
+func (tx *UnsignedRemoveSubnetValidatorTx) AtomicOperations() (ids.ID, *atomic.Requests, error) {
+	return ids.ID{}, nil, nil
+}
+
+// SyntacticVerify verifies that this transaction is well-formed
+func (tx *UnsignedRemoveSubnetValidatorTx) SyntacticVerify(ctx *snow.Context) error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.syntacticallyVerified: // already passed syntactic verification
+		return nil
+	case tx.Subnet == constants.PrimaryNetworkID:
+		return errRemovePrimaryNetworkValidator
+	}
+
+	if err := tx.BaseTx.SyntacticVerify(ctx); err != nil {
+		return err
+	}
+	if err := tx.SubnetAuth.Verify(); err != nil {
+		return err
+	}
+
+	tx.syntacticallyVerified = true
+	return nil
+}
+
+// Attempts to verify this transaction with the provided state.
+func (tx *UnsignedRemoveSubnetValidatorTx) SemanticVerify(vm *VM, parentState MutableState, stx *Tx) error {
+	vs := newVersionedState(
+		parentState,
+		parentState.CurrentStakerChainState(),
+		parentState.PendingStakerChainState(),
+	)
+	_, err := tx.Execute(vm, vs, stx)
+	return err
+}
+
+// Execute this transaction.
+func (tx *UnsignedRemoveSubnetValidatorTx) Execute(
+	vm *VM,
+	vs VersionedState,
+	stx *Tx,
+) (
+	func() error,
+	error,
+) {
+	// Make sure this transaction is well formed.
+	if len(stx.Creds) == 0 {
+		return nil, errWrongNumberOfCredentials
+	}
+
+	if err := tx.SyntacticVerify(vm.ctx); err != nil {
+		return nil, err
+	}
+
+	if vs.GetTimestamp().Before(vm.ApricotPhase6Time) {
+		return nil, errRemoveSubnetValidatorBeforeAP6
+	}
+
+	// Select the credentials for each purpose
+	baseTxCredsLen := len(stx.Creds) - 1
+	baseTxCreds := stx.Creds[:baseTxCredsLen]
+	subnetCred := stx.Creds[baseTxCredsLen]
+
+	// Verify the flowcheck
+	if err := vm.semanticVerifySpend(vs, tx, tx.Ins, tx.Outs, baseTxCreds, vm.TxFee, vm.ctx.AVAXAssetID); err != nil {
+		return nil, err
+	}
+
+	subnetIntf, _, err := vs.GetTx(tx.Subnet)
+	if err == database.ErrNotFound {
+		return nil, fmt.Errorf("%s isn't a known subnet", tx.Subnet)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subnet, ok := subnetIntf.UnsignedTx.(*UnsignedCreateSubnetTx)
+	if !ok {
+		return nil, fmt.Errorf("%s isn't a subnet", tx.Subnet)
+	}
+
+	// Verify that this removal is authorized by the subnet
+	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, subnet.Owner); err != nil {
+		return nil, err
+	}
+
+	// Remove the validator
+	if err := vs.DeleteSubnetValidator(tx.Subnet, tx.NodeID); err != nil {
+		return nil, fmt.Errorf(
+			"couldn't remove %s from subnet %s: %w",
+			tx.NodeID.PrefixedString(constants.NodeIDPrefix),
+			tx.Subnet,
+			err,
+		)
+	}
+
+	// Consume the UTXOS
+	consumeInputs(vs, tx.Ins)
+	// Produce the UTXOS
+	txID := tx.ID()
+	produceOutputs(vs, txID, vm.ctx.AVAXAssetID, tx.Outs)
+	return nil, nil
+}
+
+// Create a new transaction
+func (vm *VM) newRemoveSubnetValidatorTx(
+	nodeID ids.ShortID, // ID of the node to remove
+	subnetID ids.ID, // ID of the subnet the node is removed from
+	keys []*crypto.PrivateKeySECP256K1R, // Keys to sign the tx
+	changeAddr ids.ShortID, // Address to send change to, if there is any
+) (*Tx, error) {
+	ins, outs, _, signers, err := vm.stake(keys, 0, vm.TxFee, changeAddr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
+	}
+
+	subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, subnetID, keys)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
+	}
+	signers = append(signers, subnetSigners)
+
+	// Create the tx
+	utx := &UnsignedRemoveSubnetValidatorTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    vm.ctx.NetworkID,
+			BlockchainID: vm.ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		NodeID:     nodeID,
+		Subnet:     subnetID,
+		SubnetAuth: subnetAuth,
+	}
+	tx := &Tx{UnsignedTx: utx}
+	if err := tx.Sign(Codec, signers); err != nil {
+		return nil, err
+	}
+	return tx, utx.SyntacticVerify(vm.ctx)
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+)
+
+func TestRemoveSubnetValidatorTxExecute(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	subnetID := testSubnet1.ID()
+	subnetKeys := []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]}
+	vm.WhitelistedSubnets.Add(subnetID)
+
+	execute := func(tx *Tx) error {
+		vs := newVersionedState(
+			vm.internalState,
+			vm.internalState.CurrentStakerChainState(),
+			vm.internalState.PendingStakerChainState(),
+		)
+		if _, err := tx.UnsignedTx.(UnsignedDecisionTx).Execute(vm, vs, tx); err != nil {
+			return err
+		}
+		vs.Apply(vm.internalState)
+		vm.internalState.AddTx(tx, status.Committed)
+		return vm.internalState.Commit()
+	}
+
+	// [currentNodeID] validates the subnet and [pendingNodeID] is scheduled to
+	// validate it
+	currentNodeID := keys[0].PublicKey().Address()
+	currentTx, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		currentNodeID,
+		subnetID,
+		subnetKeys,
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(err)
+	pendingNodeID := keys[1].PublicKey().Address()
+	pendingTx, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(defaultValidateStartTime.Add(time.Hour).Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		pendingNodeID,
+		subnetID,
+		subnetKeys,
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(err)
+
+	vm.internalState.AddCurrentStaker(currentTx, 0)
+	vm.internalState.AddTx(currentTx, status.Committed)
+	vm.internalState.AddPendingStaker(pendingTx)
+	vm.internalState.AddTx(pendingTx, status.Committed)
+	assert.NoError(vm.internalState.Commit())
+	assert.NoError(vm.internalState.(*internalStateImpl).loadCurrentValidators())
+	assert.NoError(vm.internalState.(*internalStateImpl).loadPendingValidators())
+	assert.NoError(vm.updateValidators())
+
+	subnetValidators, ok := vm.Validators.GetValidators(subnetID)
+	assert.True(ok)
+	assert.True(subnetValidators.Contains(currentNodeID))
+
+	// Removals are only allowed after apricot phase 6
+	timestamp := vm.internalState.GetTimestamp()
+	vm.ApricotPhase6Time = timestamp.Add(time.Second)
+	tx, err := vm.newRemoveSubnetValidatorTx(currentNodeID, subnetID, subnetKeys, ids.ShortEmpty)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errRemoveSubnetValidatorBeforeAP6)
+	vm.ApricotPhase6Time = timestamp
+
+	// A current validator is removed from the validator set of the subnet
+	assert.NoError(execute(tx))
+	currentValidator, err := vm.internalState.CurrentStakerChainState().GetValidator(currentNodeID)
+	assert.NoError(err)
+	assert.NotContains(currentValidator.SubnetValidators(), subnetID)
+	_, _, err = vm.internalState.CurrentStakerChainState().GetStaker(currentTx.ID())
+	assert.ErrorIs(err, database.ErrNotFound)
+	assert.False(subnetValidators.Contains(currentNodeID))
+
+	// A node can't be removed twice
+	tx, err = vm.newRemoveSubnetValidatorTx(currentNodeID, subnetID, subnetKeys, ids.ShortEmpty)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errNotSubnetValidator)
+
+	// A pending validator never starts validating the subnet
+	tx, err = vm.newRemoveSubnetValidatorTx(pendingNodeID, subnetID, subnetKeys, ids.ShortEmpty)
+	assert.NoError(err)
+	assert.NoError(execute(tx))
+	assert.NotContains(vm.internalState.PendingStakerChainState().GetValidator(pendingNodeID).SubnetValidators(), subnetID)
+	for _, stakerTx := range vm.internalState.PendingStakerChainState().Stakers() {
+		assert.NotEqual(pendingTx.ID(), stakerTx.ID())
+	}
+
+	// The removals are persisted
+	assert.NoError(vm.internalState.(*internalStateImpl).loadCurrentValidators())
+	assert.NoError(vm.internalState.(*internalStateImpl).loadPendingValidators())
+	_, _, err = vm.internalState.CurrentStakerChainState().GetStaker(currentTx.ID())
+	assert.ErrorIs(err, database.ErrNotFound)
+	assert.NotContains(vm.internalState.PendingStakerChainState().GetValidator(pendingNodeID).SubnetValidators(), subnetID)
+}
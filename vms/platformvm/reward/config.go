@@ -4,6 +4,7 @@
 package reward
 
 import (
+	"errors"
 	"math/big"
 	"time"
 )
@@ -11,6 +12,13 @@ import (
 // PercentDenominator is the denominator used to calculate percentages
 const PercentDenominator = 1_000_000
 
+var (
+	errConsumptionRateTooLarge = errors.New("consumption rate can't be larger than the percent denominator")
+	errMaxConsumptionBelowMin  = errors.New("max consumption rate must be >= min consumption rate")
+	errInvalidMintingPeriod    = errors.New("minting period must be > 0")
+	errInvalidSupplyCap        = errors.New("supply cap must be > 0")
+)
+
 // consumptionRateDenominator is the magnitude offset used to emulate
 // floating point fractions.
 var consumptionRateDenominator = new(big.Int).SetUint64(PercentDenominator)
@@ -32,3 +40,19 @@ type Config struct {
 	// asymptotic to.
 	SupplyCap uint64 `json:"supplyCap"`
 }
+
+// Verify returns nil if the reward function defined by the config is valid
+func (c *Config) Verify() error {
+	switch {
+	case c.MaxConsumptionRate > PercentDenominator:
+		return errConsumptionRateTooLarge
+	case c.MaxConsumptionRate < c.MinConsumptionRate:
+		return errMaxConsumptionBelowMin
+	case c.MintingPeriod <= 0:
+		return errInvalidMintingPeriod
+	case c.SupplyCap == 0:
+		return errInvalidSupplyCap
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reward
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      func(c *Config)
+		expectedErr error
+	}{
+		{
+			name:   "valid",
+			update: func(*Config) {},
+		},
+		{
+			name:        "consumption rate too large",
+			update:      func(c *Config) { c.MaxConsumptionRate = PercentDenominator + 1 },
+			expectedErr: errConsumptionRateTooLarge,
+		},
+		{
+			name:        "max consumption rate below min",
+			update:      func(c *Config) { c.MinConsumptionRate = c.MaxConsumptionRate + 1 },
+			expectedErr: errMaxConsumptionBelowMin,
+		},
+		{
+			name:        "no minting period",
+			update:      func(c *Config) { c.MintingPeriod = 0 },
+			expectedErr: errInvalidMintingPeriod,
+		},
+		{
+			name:        "no supply cap",
+			update:      func(c *Config) { c.SupplyCap = 0 },
+			expectedErr: errInvalidSupplyCap,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := defaultConfig
+			test.update(&config)
+			assert.Equal(t, test.expectedErr, config.Verify())
+		})
+	}
+}
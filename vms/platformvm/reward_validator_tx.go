@@ -207,9 +207,15 @@ func (tx *UnsignedRewardValidatorTx) Execute(
 		}
 		vdrTx := vdr.AddValidatorTx()
 
+		// The fee is the one in effect when the delegation started
+		shares, err := delegationFee(parentState, vdrTx, uStakerTx.StartTime())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get the delegation fee: %w", err)
+		}
+
 		// Calculate split of reward between delegator/delegatee
 		// The delegator gives stake to the validatee
-		delegatorShares := reward.PercentDenominator - uint64(vdrTx.Shares)             // parentTx.Shares <= reward.PercentDenominator so no underflow
+		delegatorShares := reward.PercentDenominator - uint64(shares)                   // shares <= reward.PercentDenominator so no underflow
 		delegatorReward := delegatorShares * (stakerReward / reward.PercentDenominator) // delegatorShares <= reward.PercentDenominator so no overflow
 		// Delay rounding as long as possible for small numbers
 		if optimisticReward, err := math.Mul64(delegatorShares, stakerReward); err == nil {
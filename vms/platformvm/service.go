@@ -4,9 +4,11 @@
 package platformvm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,6 +26,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/x/merkledb"
 )
 
 const (
@@ -65,6 +68,9 @@ var (
 	errMissingName                = errors.New("argument 'name' not given")
 	errMissingVMID                = errors.New("argument 'vmID' not given")
 	errMissingBlockchainID        = errors.New("argument 'blockchainID' not given")
+	errInvalidHeightRange         = errors.New("argument 'endHeight' must be >= 'startHeight'")
+	errTooManyBlocks              = fmt.Errorf("at most %d blocks can be requested", maxPageSize)
+	errImportFromSameChain        = errors.New("source chain must be a different chain")
 )
 
 // Service defines the API calls that can be made to the platform chain
@@ -731,6 +737,10 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 			weight := json.Uint64(staker.Validator.Weight())
 			potentialReward := json.Uint64(rewardAmount)
 			delegationFee := json.Float32(100 * float32(staker.Shares) / float32(reward.PercentDenominator))
+			delegationFeeChanges, err := service.getDelegationFeeChanges(tx.ID())
+			if err != nil {
+				return err
+			}
 			rawUptime, err := service.vm.uptimeManager.CalculateUptimePercentFrom(nodeID, startTime)
 			if err != nil {
 				return err
@@ -763,11 +773,12 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 					EndTime:     json.Uint64(staker.EndTime().Unix()),
 					StakeAmount: &weight,
 				},
-				Uptime:          &uptime,
-				Connected:       &connected,
-				PotentialReward: &potentialReward,
-				RewardOwner:     rewardOwner,
-				DelegationFee:   delegationFee,
+				Uptime:               &uptime,
+				Connected:            &connected,
+				PotentialReward:      &potentialReward,
+				RewardOwner:          rewardOwner,
+				DelegationFee:        delegationFee,
+				DelegationFeeChanges: delegationFeeChanges,
 			})
 		case *UnsignedAddSubnetValidatorTx:
 			if args.SubnetID != staker.Validator.Subnet {
@@ -872,6 +883,10 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 			nodeID := staker.Validator.ID()
 			weight := json.Uint64(staker.Validator.Weight())
 			delegationFee := json.Float32(100 * float32(staker.Shares) / float32(reward.PercentDenominator))
+			delegationFeeChanges, err := service.getDelegationFeeChanges(tx.ID())
+			if err != nil {
+				return err
+			}
 
 			connected := service.vm.uptimeManager.IsConnected(nodeID)
 			reply.Validators = append(reply.Validators, APIPrimaryValidator{
@@ -882,8 +897,9 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 					EndTime:     json.Uint64(staker.EndTime().Unix()),
 					StakeAmount: &weight,
 				},
-				DelegationFee: delegationFee,
-				Connected:     &connected,
+				DelegationFee:        delegationFee,
+				DelegationFeeChanges: delegationFeeChanges,
+				Connected:            &connected,
 			})
 		case *UnsignedAddSubnetValidatorTx:
 			if args.SubnetID != staker.Validator.Subnet {
@@ -1189,37 +1205,504 @@ func (service *Service) AddDelegator(_ *http.Request, args *AddDelegatorArgs, re
 	return errs.Err
 }
 
-// AddSubnetValidatorArgs are the arguments to AddSubnetValidator
-type AddSubnetValidatorArgs struct {
+// getDelegationFeeChanges returns the changes of the delegation fee of the
+// validator added by [validatorTxID]
+func (service *Service) getDelegationFeeChanges(validatorTxID ids.ID) ([]APIDelegationFeeChange, error) {
+	changeTxs, err := service.vm.internalState.GetDelegationFeeChanges(validatorTxID)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]APIDelegationFeeChange, len(changeTxs))
+	for i, changeTx := range changeTxs {
+		change := changeTx.UnsignedTx.(*UnsignedSetDelegationFeeTx)
+		changes[i] = APIDelegationFeeChange{
+			TxID:          changeTx.ID(),
+			EffectiveTime: json.Uint64(change.EffectiveTime),
+			DelegationFee: json.Float32(100 * float32(change.Shares) / float32(reward.PercentDenominator)),
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].EffectiveTime < changes[j].EffectiveTime
+	})
+	return changes, nil
+}
+
+// SetDelegationFeeArgs are the arguments to SetDelegationFee
+type SetDelegationFeeArgs struct {
+	// User, password, from addrs, change addr
+	api.JSONSpendHeader
+	// ID of the primary network validator whose fee changes. If omitted, this
+	// node's ID is used.
+	NodeID            string       `json:"nodeID"`
+	DelegationFeeRate json.Float32 `json:"delegationFeeRate"`
+	// Unix time the new fee is effective from. If omitted, the new fee is
+	// effective as soon as it's allowed to be.
+	EffectiveTime json.Uint64 `json:"effectiveTime"`
+}
+
+// SetDelegationFee creates and signs and issues a transaction to schedule a
+// change of the fee a primary network validator charges the delegations that
+// start at or after [args.EffectiveTime]. The keys of the user must own the
+// validator's rewards.
+func (service *Service) SetDelegationFee(_ *http.Request, args *SetDelegationFeeArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("Platform: SetDelegationFee called")
+
+	if args.DelegationFeeRate < 0 || args.DelegationFeeRate > 100 {
+		return errInvalidDelegationRate
+	}
+	shares := uint32(10000 * args.DelegationFeeRate)
+
+	// Parse the node ID
+	var nodeID ids.ShortID
+	if args.NodeID == "" {
+		nodeID = service.vm.ctx.NodeID // If omitted, use this node's ID
+	} else {
+		nID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+		if err != nil {
+			return err
+		}
+		nodeID = nID
+	}
+
+	// Find the validation period of the node
+	var vdrTx *UnsignedAddValidatorTx
+	vdr, err := service.vm.internalState.CurrentStakerChainState().GetValidator(nodeID)
+	switch err {
+	case nil:
+		vdrTx = vdr.AddValidatorTx()
+	case database.ErrNotFound:
+		vdrTx, err = service.vm.internalState.PendingStakerChainState().GetValidatorTx(nodeID)
+		if err == database.ErrNotFound {
+			return fmt.Errorf("%s isn't a current or pending validator", nodeID.PrefixedString(constants.NodeIDPrefix))
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	if args.EffectiveTime == 0 {
+		timestamp := service.vm.internalState.GetTimestamp()
+		effectiveTime := timestamp.Add(minAddStakerDelay)
+		fee, err := delegationFee(service.vm.internalState, vdrTx, effectiveTime)
+		if err != nil {
+			return err
+		}
+		if shares > fee {
+			effectiveTime = timestamp.Add(delegationFeeIncreaseNotice + minAddStakerDelay)
+		}
+		args.EffectiveTime = json.Uint64(effectiveTime.Unix())
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	defer user.Close()
+
+	// Get the user's keys
+	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// Parse the change address.
+	if len(privKeys.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	if args.ChangeAddr != "" {
+		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse changeAddr: %w", err)
+		}
+	}
+
+	// Create the transaction
+	tx, err := service.vm.newSetDelegationFeeTx(
+		vdrTx.ID(),                 // Validator tx ID
+		shares,                     // Shares
+		uint64(args.EffectiveTime), // Effective time
+		privKeys.Keys,              // Private keys
+		changeAddr,                 // Change address
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create tx: %w", err)
+	}
+
+	reply.TxID = tx.ID()
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		err,
+		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		user.Close(),
+	)
+	return errs.Err
+}
+
+// SetSubnetFeeConfigArgs are the arguments to SetSubnetFeeConfig
+type SetSubnetFeeConfigArgs struct {
+	// User, password, from addrs, change addr
+	api.JSONSpendHeader
+	// ID of the subnet whose chains are configured
+	SubnetID ids.ID `json:"subnetID"`
+	// ID of the asset the fees of the chains are paid in
+	FeeAssetID ids.ID `json:"feeAssetID"`
+	// Bech32 address, without a chain prefix, that receives the fees of the
+	// chains. If omitted, the fees are burned.
+	FeeCollector string `json:"feeCollector"`
+}
+
+// SetSubnetFeeConfig creates and signs and issues a transaction to declare the
+// asset the fees of the chains of [args.SubnetID] are paid in. The subnet must
+// not have any chains yet.
+func (service *Service) SetSubnetFeeConfig(_ *http.Request, args *SetSubnetFeeConfigArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("Platform: SetSubnetFeeConfig called")
+
+	feeCollector := ids.ShortEmpty
+	if args.FeeCollector != "" {
+		hrp, addrBytes, err := formatting.ParseBech32(args.FeeCollector)
+		if err != nil {
+			return fmt.Errorf("couldn't parse feeCollector: %w", err)
+		}
+		if expectedHRP := constants.GetHRP(service.vm.ctx.NetworkID); hrp != expectedHRP {
+			return fmt.Errorf("expected hrp %q of feeCollector but got %q", expectedHRP, hrp)
+		}
+		feeCollector, err = ids.ToShortID(addrBytes)
+		if err != nil {
+			return fmt.Errorf("couldn't parse feeCollector: %w", err)
+		}
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	defer user.Close()
+
+	// Get the user's keys
+	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// Parse the change address.
+	if len(privKeys.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	if args.ChangeAddr != "" {
+		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse changeAddr: %w", err)
+		}
+	}
+
+	// Create the transaction
+	tx, err := service.vm.newSetSubnetFeeConfigTx(
+		args.SubnetID,   // Subnet ID
+		args.FeeAssetID, // Fee asset ID
+		feeCollector,    // Fee collector
+		privKeys.Keys,   // Private keys
+		changeAddr,      // Change address
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create tx: %w", err)
+	}
+
+	reply.TxID = tx.ID()
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		err,
+		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		user.Close(),
+	)
+	return errs.Err
+}
+
+// GetSubnetFeeConfigArgs are the arguments to GetSubnetFeeConfig
+type GetSubnetFeeConfigArgs struct {
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// GetSubnetFeeConfigReply is the response from GetSubnetFeeConfig
+type GetSubnetFeeConfigReply struct {
+	// ID of the asset the fees of the chains are paid in
+	FeeAssetID ids.ID `json:"feeAssetID"`
+	// Bech32 address that receives the fees of the chains. Empty if the fees
+	// are burned.
+	FeeCollector string `json:"feeCollector"`
+}
+
+// GetSubnetFeeConfig returns the fee config of the chains of [args.SubnetID].
+// It errors if the subnet doesn't declare a fee config, in which case its
+// chains pay their fees as their VMs do by default.
+func (service *Service) GetSubnetFeeConfig(_ *http.Request, args *GetSubnetFeeConfigArgs, reply *GetSubnetFeeConfigReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetSubnetFeeConfig called")
+
+	tx, err := service.vm.internalState.GetSubnetFeeConfig(args.SubnetID)
+	if err == database.ErrNotFound {
+		return fmt.Errorf("subnet %s doesn't declare a fee config", args.SubnetID)
+	}
+	if err != nil {
+		return err
+	}
+	feeConfig := tx.UnsignedTx.(*UnsignedSetSubnetFeeConfigTx)
+	reply.FeeAssetID = feeConfig.FeeAssetID
+	if feeConfig.FeeCollector != ids.ShortEmpty {
+		hrp := constants.GetHRP(service.vm.ctx.NetworkID)
+		reply.FeeCollector, err = formatting.FormatBech32(hrp, feeConfig.FeeCollector.Bytes())
+	}
+	return err
+}
+
+// SetSubnetVMConfigArgs are the arguments to SetSubnetVMConfig
+type SetSubnetVMConfigArgs struct {
+	// User, password, from addrs, change addr
+	api.JSONSpendHeader
+	// ID of the subnet whose VMs are configured
+	SubnetID ids.ID `json:"subnetID"`
+	// Config given to the VMs of the chains of the subnet
+	Config string `json:"config"`
+	// Encoding format of [Config]
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// SetSubnetVMConfig creates and signs and issues a transaction to publish a
+// config for the VMs of the chains of [args.SubnetID]. Once the tx is accepted,
+// the nodes give the config to the VMs of the subnet.
+func (service *Service) SetSubnetVMConfig(_ *http.Request, args *SetSubnetVMConfigArgs, reply *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("Platform: SetSubnetVMConfig called")
+
+	config, err := formatting.Decode(args.Encoding, args.Config)
+	if err != nil {
+		return fmt.Errorf("problem parsing config: %w", err)
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	defer user.Close()
+
+	// Get the user's keys
+	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// Parse the change address.
+	if len(privKeys.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	if args.ChangeAddr != "" {
+		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse changeAddr: %w", err)
+		}
+	}
+
+	// Create the transaction
+	tx, err := service.vm.newSetSubnetVMConfigTx(
+		args.SubnetID, // Subnet ID
+		config,        // Config
+		privKeys.Keys, // Private keys
+		changeAddr,    // Change address
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create tx: %w", err)
+	}
+
+	reply.TxID = tx.ID()
+	reply.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		err,
+		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		user.Close(),
+	)
+	return errs.Err
+}
+
+// GetSubnetVMConfigArgs are the arguments to GetSubnetVMConfig
+type GetSubnetVMConfigArgs struct {
+	SubnetID ids.ID `json:"subnetID"`
+	// Encoding format to return the config in
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetSubnetVMConfigReply is the response from GetSubnetVMConfig
+type GetSubnetVMConfigReply struct {
+	// ID of the tx that published the config
+	TxID     ids.ID              `json:"txID"`
+	Config   string              `json:"config"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetSubnetVMConfig returns the latest config published by [args.SubnetID] for
+// the VMs of its chains. It errors if the subnet didn't publish a config.
+func (service *Service) GetSubnetVMConfig(_ *http.Request, args *GetSubnetVMConfigArgs, reply *GetSubnetVMConfigReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetSubnetVMConfig called")
+
+	tx, err := service.vm.internalState.GetSubnetVMConfig(args.SubnetID)
+	if err == database.ErrNotFound {
+		return fmt.Errorf("subnet %s didn't publish a VM config", args.SubnetID)
+	}
+	if err != nil {
+		return err
+	}
+	vmConfig := tx.UnsignedTx.(*UnsignedSetSubnetVMConfigTx)
+	reply.TxID = tx.ID()
+	reply.Encoding = args.Encoding
+	reply.Config, err = formatting.EncodeWithChecksum(args.Encoding, vmConfig.Config)
+	return err
+}
+
+// AddSubnetValidatorArgs are the arguments to AddSubnetValidator
+type AddSubnetValidatorArgs struct {
+	// User, password, from addrs, change addr
+	api.JSONSpendHeader
+	APIStaker
+	// ID of subnet to validate
+	SubnetID string `json:"subnetID"`
+}
+
+// AddSubnetValidator creates and signs and issues a transaction to add a
+// validator to a subnet other than the primary network
+func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValidatorArgs, response *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("Platform: AddSubnetValidator called")
+
+	now := service.vm.clock.Time()
+	minAddStakerTime := now.Add(minAddStakerDelay)
+	minAddStakerUnix := json.Uint64(minAddStakerTime.Unix())
+	maxAddStakerTime := now.Add(maxFutureStartTime)
+	maxAddStakerUnix := json.Uint64(maxAddStakerTime.Unix())
+
+	if args.StartTime == 0 {
+		args.StartTime = minAddStakerUnix
+	}
+
+	switch {
+	case args.SubnetID == "":
+		return errNoSubnetID
+	case args.StartTime < minAddStakerUnix:
+		return errStartTimeTooSoon
+	case args.StartTime > maxAddStakerUnix:
+		return errStartTimeTooLate
+	}
+
+	// Parse the node ID
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return fmt.Errorf("error parsing nodeID: %q: %w", args.NodeID, err)
+	}
+
+	// Parse the subnet ID
+	subnetID, err := ids.FromString(args.SubnetID)
+	if err != nil {
+		return fmt.Errorf("problem parsing subnetID %q: %w", args.SubnetID, err)
+	}
+	if subnetID == constants.PrimaryNetworkID {
+		return errNamedSubnetCantBePrimary
+	}
+
+	// Parse the from addresses
+	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
+	if err != nil {
+		return err
+	}
+
+	user, err := keystore.NewUserFromKeystore(service.vm.ctx.Keystore, args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	defer user.Close()
+
+	keys, err := keystore.GetKeychain(user, fromAddrs)
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// Parse the change address.
+	if len(keys.Keys) == 0 {
+		return errNoKeys
+	}
+	changeAddr := keys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	if args.ChangeAddr != "" {
+		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse changeAddr: %w", err)
+		}
+	}
+
+	// Create the transaction
+	tx, err := service.vm.newAddSubnetValidatorTx(
+		args.weight(),          // Stake amount
+		uint64(args.StartTime), // Start time
+		uint64(args.EndTime),   // End time
+		nodeID,                 // Node ID
+		subnetID,               // Subnet ID
+		keys.Keys,              // Keys
+		changeAddr,             // Change address
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create tx: %w", err)
+	}
+
+	response.TxID = tx.ID()
+	response.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		err,
+		service.vm.blockBuilder.AddUnverifiedTx(tx),
+		user.Close(),
+	)
+	return errs.Err
+}
+
+// RemoveSubnetValidatorArgs are the arguments to RemoveSubnetValidator
+type RemoveSubnetValidatorArgs struct {
 	// User, password, from addrs, change addr
 	api.JSONSpendHeader
-	APIStaker
-	// ID of subnet to validate
-	SubnetID string `json:"subnetID"`
+	// ID of the node to remove
+	NodeID string `json:"nodeID"`
+	// ID of the subnet the node is removed from
+	SubnetID ids.ID `json:"subnetID"`
 }
 
-// AddSubnetValidator creates and signs and issues a transaction to add a
-// validator to a subnet other than the primary network
-func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValidatorArgs, response *api.JSONTxIDChangeAddr) error {
-	service.vm.ctx.Log.Debug("Platform: AddSubnetValidator called")
-
-	now := service.vm.clock.Time()
-	minAddStakerTime := now.Add(minAddStakerDelay)
-	minAddStakerUnix := json.Uint64(minAddStakerTime.Unix())
-	maxAddStakerTime := now.Add(maxFutureStartTime)
-	maxAddStakerUnix := json.Uint64(maxAddStakerTime.Unix())
-
-	if args.StartTime == 0 {
-		args.StartTime = minAddStakerUnix
-	}
+// RemoveSubnetValidator creates and signs and issues a transaction to remove a
+// current or pending validator from a subnet other than the primary network
+func (service *Service) RemoveSubnetValidator(_ *http.Request, args *RemoveSubnetValidatorArgs, response *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Debug("Platform: RemoveSubnetValidator called")
 
-	switch {
-	case args.SubnetID == "":
-		return errNoSubnetID
-	case args.StartTime < minAddStakerUnix:
-		return errStartTimeTooSoon
-	case args.StartTime > maxAddStakerUnix:
-		return errStartTimeTooLate
+	if args.SubnetID == constants.PrimaryNetworkID {
+		return errNamedSubnetCantBePrimary
 	}
 
 	// Parse the node ID
@@ -1228,15 +1711,6 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 		return fmt.Errorf("error parsing nodeID: %q: %w", args.NodeID, err)
 	}
 
-	// Parse the subnet ID
-	subnetID, err := ids.FromString(args.SubnetID)
-	if err != nil {
-		return fmt.Errorf("problem parsing subnetID %q: %w", args.SubnetID, err)
-	}
-	if subnetID == constants.PrimaryNetworkID {
-		return errNamedSubnetCantBePrimary
-	}
-
 	// Parse the from addresses
 	fromAddrs, err := avax.ParseLocalAddresses(service.vm, args.From)
 	if err != nil {
@@ -1267,14 +1741,11 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 	}
 
 	// Create the transaction
-	tx, err := service.vm.newAddSubnetValidatorTx(
-		args.weight(),          // Stake amount
-		uint64(args.StartTime), // Start time
-		uint64(args.EndTime),   // End time
-		nodeID,                 // Node ID
-		subnetID,               // Subnet ID
-		keys.Keys,              // Keys
-		changeAddr,             // Change address
+	tx, err := service.vm.newRemoveSubnetValidatorTx(
+		nodeID,        // Node ID
+		args.SubnetID, // Subnet ID
+		keys.Keys,     // Keys
+		changeAddr,    // Change address
 	)
 	if err != nil {
 		return fmt.Errorf("couldn't create tx: %w", err)
@@ -1292,6 +1763,125 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 	return errs.Err
 }
 
+// GetPendingSubnetValidatorChangesArgs are the arguments to
+// GetPendingSubnetValidatorChanges
+type GetPendingSubnetValidatorChangesArgs struct {
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// APISubnetValidatorRemoval is the repr. of a removal of a subnet validator
+// sent over APIs
+type APISubnetValidatorRemoval struct {
+	TxID   ids.ID `json:"txID"`
+	NodeID string `json:"nodeID"`
+}
+
+// GetPendingSubnetValidatorChangesReply is the response from
+// GetPendingSubnetValidatorChanges
+type GetPendingSubnetValidatorChangesReply struct {
+	Additions []APIStaker                 `json:"additions"`
+	Removals  []APISubnetValidatorRemoval `json:"removals"`
+}
+
+// GetPendingSubnetValidatorChanges returns the txs in the mempool of this node
+// that add validators to, or remove validators from, [args.SubnetID]. These
+// txs haven't been issued into a block yet.
+func (service *Service) GetPendingSubnetValidatorChanges(_ *http.Request, args *GetPendingSubnetValidatorChangesArgs, reply *GetPendingSubnetValidatorChangesReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetPendingSubnetValidatorChanges called")
+
+	reply.Additions = []APIStaker{}
+	reply.Removals = []APISubnetValidatorRemoval{}
+	for _, tx := range service.vm.blockBuilder.List() {
+		switch utx := tx.UnsignedTx.(type) {
+		case *UnsignedAddSubnetValidatorTx:
+			if utx.Validator.Subnet != args.SubnetID {
+				continue
+			}
+			weight := json.Uint64(utx.Validator.Weight())
+			reply.Additions = append(reply.Additions, APIStaker{
+				TxID:      tx.ID(),
+				NodeID:    utx.Validator.ID().PrefixedString(constants.NodeIDPrefix),
+				StartTime: json.Uint64(utx.StartTime().Unix()),
+				EndTime:   json.Uint64(utx.EndTime().Unix()),
+				Weight:    &weight,
+			})
+		case *UnsignedRemoveSubnetValidatorTx:
+			if utx.Subnet != args.SubnetID {
+				continue
+			}
+			reply.Removals = append(reply.Removals, APISubnetValidatorRemoval{
+				TxID:   tx.ID(),
+				NodeID: utx.NodeID.PrefixedString(constants.NodeIDPrefix),
+			})
+		}
+	}
+	return nil
+}
+
+// GetSubnetMembershipsArgs are the arguments to GetSubnetMemberships
+type GetSubnetMembershipsArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// APISubnetMembership is the repr. of a node validating a subnet sent over
+// APIs
+type APISubnetMembership struct {
+	APIStaker
+	SubnetID ids.ID `json:"subnetID"`
+	// True if the node hasn't started validating the subnet yet
+	Pending bool `json:"pending"`
+}
+
+// GetSubnetMembershipsReply is the response from GetSubnetMemberships
+type GetSubnetMembershipsReply struct {
+	Subnets []APISubnetMembership `json:"subnets"`
+}
+
+// GetSubnetMemberships returns the subnets, other than the primary network,
+// that [args.NodeID] currently validates or is scheduled to validate
+func (service *Service) GetSubnetMemberships(_ *http.Request, args *GetSubnetMembershipsArgs, reply *GetSubnetMembershipsReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetSubnetMemberships called")
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return fmt.Errorf("error parsing nodeID: %q: %w", args.NodeID, err)
+	}
+
+	reply.Subnets = []APISubnetMembership{}
+	addMemberships := func(subnets map[ids.ID]*UnsignedAddSubnetValidatorTx, pending bool) {
+		for subnetID, tx := range subnets {
+			weight := json.Uint64(tx.Validator.Weight())
+			reply.Subnets = append(reply.Subnets, APISubnetMembership{
+				APIStaker: APIStaker{
+					TxID:      tx.ID(),
+					NodeID:    args.NodeID,
+					StartTime: json.Uint64(tx.StartTime().Unix()),
+					EndTime:   json.Uint64(tx.EndTime().Unix()),
+					Weight:    &weight,
+				},
+				SubnetID: subnetID,
+				Pending:  pending,
+			})
+		}
+	}
+
+	currentValidator, err := service.vm.internalState.CurrentStakerChainState().GetValidator(nodeID)
+	switch err {
+	case nil:
+		addMemberships(currentValidator.SubnetValidators(), false)
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+	pendingValidator := service.vm.internalState.PendingStakerChainState().GetValidator(nodeID)
+	addMemberships(pendingValidator.SubnetValidators(), true)
+
+	sort.Slice(reply.Subnets, func(i, j int) bool {
+		return bytes.Compare(reply.Subnets[i].SubnetID[:], reply.Subnets[j].SubnetID[:]) < 0
+	})
+	return nil
+}
+
 // CreateSubnetArgs are the arguments to CreateSubnet
 type CreateSubnetArgs struct {
 	// User, password, from addrs, change addr
@@ -1922,6 +2512,82 @@ func (service *Service) IssueTx(_ *http.Request, args *api.FormattedTx, response
 	return nil
 }
 
+// SimulateTx verifies a signed tx against the preferred state without issuing
+// it. Verification failures are reported in the reply rather than as errors.
+func (service *Service) SimulateTx(_ *http.Request, args *api.FormattedTx, response *api.SimulateTxReply) error {
+	service.vm.ctx.Log.Debug("Platform: SimulateTx called")
+
+	txBytes, err := formatting.Decode(args.Encoding, args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx := &Tx{}
+	if _, err := Codec.Unmarshal(txBytes, tx); err != nil {
+		return fmt.Errorf("couldn't parse tx: %w", err)
+	}
+
+	err = service.vm.blockBuilder.SimulateTx(tx)
+	response.TxID = tx.ID()
+	if err != nil {
+		response.Reason = err.Error()
+		return nil
+	}
+	response.Valid = true
+	return nil
+}
+
+// DecodeTx decodes a signed tx into JSON. The tx isn't verified and its inputs
+// don't need to exist.
+func (service *Service) DecodeTx(_ *http.Request, args *api.FormattedTx, response *api.DecodeTxReply) error {
+	service.vm.ctx.Log.Debug("Platform: DecodeTx called")
+
+	txBytes, err := formatting.Decode(args.Encoding, args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx := &Tx{}
+	if _, err := Codec.Unmarshal(txBytes, tx); err != nil {
+		return fmt.Errorf("couldn't parse tx: %w", err)
+	}
+	if err := tx.Sign(Codec, nil); err != nil {
+		return fmt.Errorf("couldn't initialize tx: %w", err)
+	}
+	tx.InitCtx(service.vm.ctx)
+
+	response.TxID = tx.ID()
+	response.Tx = tx
+	return nil
+}
+
+// GetImportStatus returns which outputs of an export tx on another chain were
+// imported into this chain, and by which txs
+func (service *Service) GetImportStatus(_ *http.Request, args *api.GetImportStatusArgs, response *api.GetImportStatusReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetImportStatus called for %s from %s", args.ExportTxID, args.SourceChain)
+
+	sourceChain, err := service.vm.ctx.BCLookup.Lookup(args.SourceChain)
+	if err != nil {
+		return fmt.Errorf("problem parsing source chainID %q: %w", args.SourceChain, err)
+	}
+	if sourceChain == service.vm.ctx.ChainID {
+		return errImportFromSameChain
+	}
+
+	imports, err := service.vm.importIndex.GetImports(sourceChain, args.ExportTxID)
+	if err != nil {
+		return fmt.Errorf("problem retrieving imports of %s: %w", args.ExportTxID, err)
+	}
+
+	response.Imported = len(imports) > 0
+	response.Imports = make([]api.ImportedOutput, len(imports))
+	for i, imported := range imports {
+		response.Imports[i] = api.ImportedOutput{
+			OutputIndex: json.Uint32(imported.OutputIndex),
+			ImportTxID:  imported.ImportTxID,
+		}
+	}
+	return nil
+}
+
 // GetTx gets a tx
 func (service *Service) GetTx(_ *http.Request, args *api.GetTxArgs, response *api.GetTxReply) error {
 	service.vm.ctx.Log.Debug("Platform: GetTx called")
@@ -2337,6 +3003,134 @@ func (service *Service) GetValidatorsAt(_ *http.Request, args *GetValidatorsAtAr
 	return nil
 }
 
+// GetValidatorSetProofArgs are the arguments for GetValidatorSetProof
+type GetValidatorSetProofArgs struct {
+	Height   json.Uint64         `json:"height"`
+	SubnetID ids.ID              `json:"subnetID"`
+	NodeID   string              `json:"nodeID"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetValidatorSetProofReply is the response from GetValidatorSetProof
+type GetValidatorSetProofReply struct {
+	// Merkle root of the validator set, as committed by this node when the
+	// validator set last changed
+	Root ids.ID `json:"root"`
+	// Weight of the node, or 0 if it wasn't a validator
+	Weight json.Uint64 `json:"weight"`
+	// Proof of [Weight] against [Root]
+	Proof    string              `json:"proof"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetValidatorSetProof returns the Merkle root of the validator set of a
+// provided subnet at the specified height, and a proof of the weight of the
+// provided node in that validator set. The root is the one committed when the
+// validator set last changed, so it is the same for every height until the
+// validator set changes again. The proof can be checked with
+// VerifyValidatorSetProof.
+//
+// The root isn't committed to by P-chain blocks, so this doesn't remove the
+// need to trust the node that serves it. Clients that don't trust a single
+// node should only accept roots that several nodes they trust agree on.
+func (service *Service) GetValidatorSetProof(_ *http.Request, args *GetValidatorSetProofArgs, reply *GetValidatorSetProofReply) error {
+	service.vm.ctx.Log.Debug(
+		"Platform: GetValidatorSetProof called with Height %d, SubnetID %s and NodeID %s",
+		args.Height,
+		args.SubnetID,
+		args.NodeID,
+	)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return fmt.Errorf("couldn't parse nodeID: %w", err)
+	}
+
+	root, weight, proof, err := service.vm.GetValidatorSetProof(uint64(args.Height), args.SubnetID, nodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't create proof: %w", err)
+	}
+
+	proofBytes, err := merkledb.Codec.Marshal(merkledb.CodecVersion, proof)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal proof: %w", err)
+	}
+	reply.Proof, err = formatting.EncodeWithChecksum(args.Encoding, proofBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't encode proof as string: %w", err)
+	}
+	reply.Root = root
+	reply.Weight = json.Uint64(weight)
+	reply.Encoding = args.Encoding
+	return nil
+}
+
+// GetAddressFiltersArgs are the arguments for GetAddressFilters
+type GetAddressFiltersArgs struct {
+	StartHeight json.Uint64         `json:"startHeight"`
+	Limit       json.Uint32         `json:"limit"`
+	Encoding    formatting.Encoding `json:"encoding"`
+}
+
+// APIAddressFilter is the filter of the addresses involved in an accepted
+// block
+type APIAddressFilter struct {
+	Height  json.Uint64 `json:"height"`
+	BlockID ids.ID      `json:"blockID"`
+	// Filter is empty if the block doesn't involve any address
+	Filter string `json:"filter"`
+}
+
+// GetAddressFiltersReply is the response from GetAddressFilters
+type GetAddressFiltersReply struct {
+	Filters  []APIAddressFilter  `json:"filters"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetAddressFilters returns the bloom filters of the addresses involved in the
+// blocks accepted from [StartHeight], in order of height. Light wallets can
+// check the filters for their addresses to find which blocks they need to
+// fetch.
+func (service *Service) GetAddressFilters(_ *http.Request, args *GetAddressFiltersArgs, reply *GetAddressFiltersReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetAddressFilters called with StartHeight %d", args.StartHeight)
+
+	limit := int(args.Limit)
+	if limit <= 0 || maxPageSize < limit {
+		limit = maxPageSize
+	}
+
+	lastAccepted, err := service.vm.getBlock(service.vm.lastAcceptedID)
+	if err != nil {
+		return fmt.Errorf("couldn't get last accepted block: %w", err)
+	}
+	lastAcceptedHeight := lastAccepted.Height()
+
+	reply.Filters = []APIAddressFilter{}
+	for height := uint64(args.StartHeight); height <= lastAcceptedHeight && len(reply.Filters) < limit; height++ {
+		blkID, filterBytes, err := service.vm.internalState.GetAddressFilter(height)
+		if err == database.ErrNotFound {
+			return fmt.Errorf("%w: %d", errNoAddressFilter, height)
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't get address filter at height %d: %w", height, err)
+		}
+
+		filter := APIAddressFilter{
+			Height:  json.Uint64(height),
+			BlockID: blkID,
+		}
+		if len(filterBytes) != 0 {
+			filter.Filter, err = formatting.EncodeWithChecksum(args.Encoding, filterBytes)
+			if err != nil {
+				return fmt.Errorf("couldn't encode address filter as string: %w", err)
+			}
+		}
+		reply.Filters = append(reply.Filters, filter)
+	}
+	reply.Encoding = args.Encoding
+	return nil
+}
+
 func (service *Service) GetBlock(_ *http.Request, args *api.GetBlockArgs, response *api.GetBlockResponse) error {
 	service.vm.ctx.Log.Debug("Platform: GetBlock called with args %s", args)
 
@@ -2358,3 +3152,156 @@ func (service *Service) GetBlock(_ *http.Request, args *api.GetBlockArgs, respon
 
 	return nil
 }
+
+// GetBlocksByHeightArgs are the arguments for GetBlocksByHeight
+type GetBlocksByHeightArgs struct {
+	StartHeight json.Uint64         `json:"startHeight"`
+	EndHeight   json.Uint64         `json:"endHeight"`
+	Encoding    formatting.Encoding `json:"encoding"`
+}
+
+// APIBlock is an accepted block with its txs decoded
+type APIBlock struct {
+	ID       ids.ID      `json:"id"`
+	ParentID ids.ID      `json:"parentID"`
+	Height   json.Uint64 `json:"height"`
+	// Type is one of "standard", "proposal", "atomic", "commit" and "abort"
+	Type string  `json:"type"`
+	Txs  []APITx `json:"txs"`
+}
+
+// APITx is a decoded tx of an accepted block
+type APITx struct {
+	TxID ids.ID `json:"txID"`
+	// Type is the kind of the unsigned tx, such as "addValidator"
+	Type string `json:"type"`
+	Tx   *Tx    `json:"tx"`
+}
+
+// GetBlocksByHeightReply is the response from GetBlocksByHeight
+type GetBlocksByHeightReply struct {
+	// If Encoding is formatting.JSON, Blocks are APIBlocks. Otherwise, they are
+	// the string representations of the bytes of the blocks under Encoding.
+	Blocks   []interface{}       `json:"blocks"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetBlocksByHeight returns the blocks accepted from [StartHeight] to
+// [EndHeight], inclusive, in order of height. Heights past the last accepted
+// block are ignored.
+func (service *Service) GetBlocksByHeight(_ *http.Request, args *GetBlocksByHeightArgs, reply *GetBlocksByHeightReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetBlocksByHeight called with StartHeight %d and EndHeight %d", args.StartHeight, args.EndHeight)
+
+	switch {
+	case args.EndHeight < args.StartHeight:
+		return errInvalidHeightRange
+	case args.EndHeight-args.StartHeight >= maxPageSize:
+		return errTooManyBlocks
+	}
+
+	lastAccepted, err := service.vm.getBlock(service.vm.lastAcceptedID)
+	if err != nil {
+		return fmt.Errorf("couldn't get last accepted block: %w", err)
+	}
+	endHeight := uint64(args.EndHeight)
+	if lastAcceptedHeight := lastAccepted.Height(); lastAcceptedHeight < endHeight {
+		endHeight = lastAcceptedHeight
+	}
+
+	reply.Blocks = []interface{}{}
+	for height := uint64(args.StartHeight); height <= endHeight; height++ {
+		// The index of the address filters is also the index of the accepted
+		// blocks by height
+		blkID, _, err := service.vm.internalState.GetAddressFilter(height)
+		if err == database.ErrNotFound {
+			return fmt.Errorf("%w: %d", errNoAddressFilter, height)
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't get the ID of the block at height %d: %w", height, err)
+		}
+		blk, err := service.vm.getBlock(blkID)
+		if err != nil {
+			return fmt.Errorf("couldn't get block %s: %w", blkID, err)
+		}
+
+		if args.Encoding != formatting.JSON {
+			blkStr, err := formatting.EncodeWithChecksum(args.Encoding, blk.Bytes())
+			if err != nil {
+				return fmt.Errorf("couldn't encode block %s as string: %w", blkID, err)
+			}
+			reply.Blocks = append(reply.Blocks, blkStr)
+			continue
+		}
+
+		apiBlk := APIBlock{
+			ID:       blkID,
+			ParentID: blk.Parent(),
+			Height:   json.Uint64(height),
+			Type:     blockTypeName(blk),
+			Txs:      []APITx{},
+		}
+		for _, tx := range blockTxs(blk) {
+			tx.InitCtx(service.vm.ctx)
+			apiBlk.Txs = append(apiBlk.Txs, APITx{
+				TxID: tx.ID(),
+				Type: txTypeName(tx.UnsignedTx),
+				Tx:   tx,
+			})
+		}
+		reply.Blocks = append(reply.Blocks, apiBlk)
+	}
+	reply.Encoding = args.Encoding
+	return nil
+}
+
+// blockTypeName returns the name of the kind of [blk] reported by the API
+func blockTypeName(blk Block) string {
+	switch blk.(type) {
+	case *StandardBlock:
+		return "standard"
+	case *ProposalBlock:
+		return "proposal"
+	case *AtomicBlock:
+		return "atomic"
+	case *CommitBlock:
+		return "commit"
+	case *AbortBlock:
+		return "abort"
+	default:
+		return "unknown"
+	}
+}
+
+// txTypeName returns the name of the kind of [utx] reported by the API
+func txTypeName(utx UnsignedTx) string {
+	switch utx.(type) {
+	case *UnsignedAddDelegatorTx:
+		return "addDelegator"
+	case *UnsignedAddSubnetValidatorTx:
+		return "addSubnetValidator"
+	case *UnsignedAddValidatorTx:
+		return "addValidator"
+	case *UnsignedAdvanceTimeTx:
+		return "advanceTime"
+	case *UnsignedCreateChainTx:
+		return "createChain"
+	case *UnsignedCreateSubnetTx:
+		return "createSubnet"
+	case *UnsignedExportTx:
+		return "export"
+	case *UnsignedImportTx:
+		return "import"
+	case *UnsignedRewardValidatorTx:
+		return "rewardValidator"
+	case *UnsignedSetDelegationFeeTx:
+		return "setDelegationFee"
+	case *UnsignedSetSubnetFeeConfigTx:
+		return "setSubnetFeeConfig"
+	case *UnsignedSetSubnetVMConfigTx:
+		return "setSubnetVMConfig"
+	case *UnsignedRemoveSubnetValidatorTx:
+		return "removeSubnetValidator"
+	default:
+		return "unknown"
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
@@ -30,6 +31,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/x/merkledb"
 
 	cjson "github.com/ava-labs/avalanchego/utils/json"
 	vmkeystore "github.com/ava-labs/avalanchego/vms/components/keystore"
@@ -767,6 +769,128 @@ func TestGetTimestamp(t *testing.T) {
 	assert.Equal(newTimestamp, reply.Timestamp)
 }
 
+func TestGetValidatorSetProof(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		err := service.vm.Shutdown()
+		assert.NoError(err)
+
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	vdrSet, err := service.vm.GetValidatorSet(0, constants.PrimaryNetworkID)
+	assert.NoError(err)
+	assert.NotEmpty(vdrSet)
+
+	var root ids.ID
+	for nodeID, weight := range vdrSet {
+		reply := GetValidatorSetProofReply{}
+		err := service.GetValidatorSetProof(nil, &GetValidatorSetProofArgs{
+			Height:   0,
+			SubnetID: constants.PrimaryNetworkID,
+			NodeID:   nodeID.PrefixedString(constants.NodeIDPrefix),
+			Encoding: formatting.Hex,
+		}, &reply)
+		assert.NoError(err)
+		assert.Equal(weight, uint64(reply.Weight))
+
+		// Every proof is against the same root
+		if root == ids.Empty {
+			root = reply.Root
+		}
+		assert.Equal(root, reply.Root)
+
+		proofBytes, err := formatting.Decode(reply.Encoding, reply.Proof)
+		assert.NoError(err)
+		proof := &merkledb.Proof{}
+		_, err = merkledb.Codec.Unmarshal(proofBytes, proof)
+		assert.NoError(err)
+
+		assert.NoError(VerifyValidatorSetProof(reply.Root, nodeID, weight, proof))
+		assert.Error(VerifyValidatorSetProof(reply.Root, nodeID, weight+1, proof))
+		assert.Error(VerifyValidatorSetProof(reply.Root, nodeID, 0, proof))
+	}
+
+	// Nodes that aren't validators are proven to have no weight
+	reply := GetValidatorSetProofReply{}
+	nodeID := ids.GenerateTestShortID()
+	err = service.GetValidatorSetProof(nil, &GetValidatorSetProofArgs{
+		Height:   0,
+		SubnetID: constants.PrimaryNetworkID,
+		NodeID:   nodeID.PrefixedString(constants.NodeIDPrefix),
+		Encoding: formatting.Hex,
+	}, &reply)
+	assert.NoError(err)
+	assert.Zero(reply.Weight)
+	assert.Equal(root, reply.Root)
+
+	proofBytes, err := formatting.Decode(reply.Encoding, reply.Proof)
+	assert.NoError(err)
+	proof := &merkledb.Proof{}
+	_, err = merkledb.Codec.Unmarshal(proofBytes, proof)
+	assert.NoError(err)
+	assert.NoError(VerifyValidatorSetProof(reply.Root, nodeID, 0, proof))
+
+	// Proofs are against the root committed to by the state
+	committedRoot, err := service.vm.internalState.GetValidatorSetRoot(0, constants.PrimaryNetworkID)
+	assert.NoError(err)
+	assert.Equal(committedRoot, root)
+
+	// Proofs aren't served if the validator set doesn't match its commitment
+	service.vm.internalState.AddValidatorSetRoot(0, constants.PrimaryNetworkID, ids.GenerateTestID())
+	err = service.GetValidatorSetProof(nil, &GetValidatorSetProofArgs{
+		Height:   0,
+		SubnetID: constants.PrimaryNetworkID,
+		NodeID:   nodeID.PrefixedString(constants.NodeIDPrefix),
+		Encoding: formatting.Hex,
+	}, &reply)
+	assert.ErrorIs(err, errValidatorSetRootMismatch)
+
+	// Proofs aren't served for validator sets that weren't committed to
+	err = service.GetValidatorSetProof(nil, &GetValidatorSetProofArgs{
+		Height:   0,
+		SubnetID: ids.GenerateTestID(),
+		NodeID:   nodeID.PrefixedString(constants.NodeIDPrefix),
+		Encoding: formatting.Hex,
+	}, &reply)
+	assert.ErrorIs(err, errNoValidatorSetRoot)
+}
+
+func TestGetValidatorSetRoot(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	subnetID := ids.GenerateTestID()
+	root0 := ids.GenerateTestID()
+	root5 := ids.GenerateTestID()
+	vm.internalState.AddValidatorSetRoot(0, subnetID, root0)
+	vm.internalState.AddValidatorSetRoot(5, subnetID, root5)
+
+	check := func() {
+		// The root of a height is the last one committed at or before it
+		for height, expectedRoot := range map[uint64]ids.ID{0: root0, 4: root0, 5: root5, 7: root5} {
+			root, err := vm.internalState.GetValidatorSetRoot(height, subnetID)
+			assert.NoError(err)
+			assert.Equal(expectedRoot, root)
+		}
+		// Roots of other subnets aren't returned
+		_, err := vm.internalState.GetValidatorSetRoot(7, ids.GenerateTestID())
+		assert.ErrorIs(err, database.ErrNotFound)
+	}
+	check()
+	assert.NoError(vm.internalState.Commit())
+	check()
+}
+
 func TestGetBlock(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -822,3 +946,232 @@ func TestGetBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBlocksByHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	service := &Service{vm: vm}
+
+	tx, err := vm.newExportTx(
+		defaultTxFee,
+		xChainID,
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[1].PublicKey().Address(),
+	)
+	assert.NoError(err)
+	assert.NoError(vm.blockBuilder.AddUnverifiedTx(tx))
+
+	blk, err := vm.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(blk.Verify())
+	assert.NoError(blk.Accept())
+
+	// Heights past the last accepted block are ignored
+	reply := GetBlocksByHeightReply{}
+	assert.NoError(service.GetBlocksByHeight(nil, &GetBlocksByHeightArgs{
+		StartHeight: 1,
+		EndHeight:   5,
+		Encoding:    formatting.JSON,
+	}, &reply))
+	assert.Len(reply.Blocks, 1)
+	apiBlk, ok := reply.Blocks[0].(APIBlock)
+	assert.True(ok)
+	assert.Equal(blk.ID(), apiBlk.ID)
+	assert.Equal(blk.Parent(), apiBlk.ParentID)
+	assert.EqualValues(1, apiBlk.Height)
+	assert.Equal("standard", apiBlk.Type)
+	assert.Len(apiBlk.Txs, 1)
+	assert.Equal(tx.ID(), apiBlk.Txs[0].TxID)
+	assert.Equal("export", apiBlk.Txs[0].Type)
+	_, err = json.Marshal(reply)
+	assert.NoError(err)
+
+	reply = GetBlocksByHeightReply{}
+	assert.NoError(service.GetBlocksByHeight(nil, &GetBlocksByHeightArgs{
+		EndHeight: 1,
+		Encoding:  formatting.Hex,
+	}, &reply))
+	assert.Len(reply.Blocks, 2)
+	blkBytes, err := formatting.Decode(reply.Encoding, reply.Blocks[1].(string))
+	assert.NoError(err)
+	assert.Equal(blk.Bytes(), blkBytes)
+
+	err = service.GetBlocksByHeight(nil, &GetBlocksByHeightArgs{
+		StartHeight: 1,
+		Encoding:    formatting.JSON,
+	}, &GetBlocksByHeightReply{})
+	assert.ErrorIs(err, errInvalidHeightRange)
+
+	err = service.GetBlocksByHeight(nil, &GetBlocksByHeightArgs{
+		EndHeight: maxPageSize,
+		Encoding:  formatting.JSON,
+	}, &GetBlocksByHeightReply{})
+	assert.ErrorIs(err, errTooManyBlocks)
+}
+
+func TestSimulateTx(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	simulate := func(tx *Tx) *api.SimulateTxReply {
+		txStr, err := formatting.EncodeWithChecksum(formatting.Hex, tx.Bytes())
+		assert.NoError(err)
+		reply := &api.SimulateTxReply{}
+		assert.NoError(service.SimulateTx(nil, &api.FormattedTx{
+			Tx:       txStr,
+			Encoding: formatting.Hex,
+		}, reply))
+		assert.Equal(tx.ID(), reply.TxID)
+		return reply
+	}
+
+	tx, err := service.vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		constants.AVMID,
+		nil,
+		"chain name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	assert.NoError(err)
+	reply := simulate(tx)
+	assert.True(reply.Valid)
+	assert.Empty(reply.Reason)
+
+	// The simulated tx isn't added to the mempool
+	assert.False(service.vm.blockBuilder.Has(tx.ID()))
+
+	// A validator can't start in the past
+	tx, err = service.vm.newAddValidatorTx(
+		service.vm.MinValidatorStake,
+		uint64(service.vm.clock.Time().Add(-time.Hour).Unix()),
+		uint64(service.vm.clock.Time().Add(defaultMinStakingDuration).Unix()),
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+		0,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	assert.NoError(err)
+	reply = simulate(tx)
+	assert.False(reply.Valid)
+	assert.NotEmpty(reply.Reason)
+	assert.False(service.vm.blockBuilder.Has(tx.ID()))
+}
+
+func TestDecodeTx(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	tx, err := service.vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		constants.AVMID,
+		nil,
+		"chain name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	assert.NoError(err)
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, tx.Bytes())
+	assert.NoError(err)
+
+	reply := &api.DecodeTxReply{}
+	assert.NoError(service.DecodeTx(nil, &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, reply))
+	assert.Equal(tx.ID(), reply.TxID)
+
+	txJSON, err := json.Marshal(reply.Tx)
+	assert.NoError(err)
+	decoded := struct {
+		UnsignedTx struct {
+			BaseTx struct {
+				Inputs []interface{} `json:"inputs"`
+			} `json:"inputs"`
+			ChainName string `json:"chainName"`
+		} `json:"unsignedTx"`
+		Credentials []interface{} `json:"credentials"`
+	}{}
+	assert.NoError(json.Unmarshal(txJSON, &decoded))
+	assert.Len(decoded.UnsignedTx.BaseTx.Inputs, len(tx.UnsignedTx.(*UnsignedCreateChainTx).Ins))
+	assert.Equal("chain name", decoded.UnsignedTx.ChainName)
+	assert.Len(decoded.Credentials, len(tx.Creds))
+
+	// Decoding doesn't issue the tx
+	assert.False(service.vm.blockBuilder.Has(tx.ID()))
+}
+
+func TestGetSubnetValidatorChangesAndMemberships(t *testing.T) {
+	assert := assert.New(t)
+
+	service := defaultService(t)
+	vm := service.vm
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	subnetID := testSubnet1.ID()
+	subnetKeys := []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]}
+	nodeID := keys[0].PublicKey().Address()
+	nodeIDStr := nodeID.PrefixedString(constants.NodeIDPrefix)
+
+	addTx, err := vm.newAddSubnetValidatorTx(
+		defaultWeight,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		nodeID,
+		subnetID,
+		subnetKeys,
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(err)
+	vm.internalState.AddCurrentStaker(addTx, 0)
+	vm.internalState.AddTx(addTx, status.Committed)
+	assert.NoError(vm.internalState.Commit())
+	assert.NoError(vm.internalState.(*internalStateImpl).loadCurrentValidators())
+
+	membershipsReply := GetSubnetMembershipsReply{}
+	assert.NoError(service.GetSubnetMemberships(nil, &GetSubnetMembershipsArgs{NodeID: nodeIDStr}, &membershipsReply))
+	assert.Len(membershipsReply.Subnets, 1)
+	assert.Equal(subnetID, membershipsReply.Subnets[0].SubnetID)
+	assert.Equal(addTx.ID(), membershipsReply.Subnets[0].TxID)
+	assert.False(membershipsReply.Subnets[0].Pending)
+
+	// Changes in the mempool are reported until they're issued
+	removeTx, err := vm.newRemoveSubnetValidatorTx(nodeID, subnetID, subnetKeys, ids.ShortEmpty)
+	assert.NoError(err)
+	vm.blockBuilder.AddDecisionTx(removeTx)
+
+	changesReply := GetPendingSubnetValidatorChangesReply{}
+	assert.NoError(service.GetPendingSubnetValidatorChanges(nil, &GetPendingSubnetValidatorChangesArgs{SubnetID: subnetID}, &changesReply))
+	assert.Empty(changesReply.Additions)
+	assert.Equal([]APISubnetValidatorRemoval{{TxID: removeTx.ID(), NodeID: nodeIDStr}}, changesReply.Removals)
+
+	changesReply = GetPendingSubnetValidatorChangesReply{}
+	assert.NoError(service.GetPendingSubnetValidatorChanges(nil, &GetPendingSubnetValidatorChangesArgs{SubnetID: ids.GenerateTestID()}, &changesReply))
+	assert.Empty(changesReply.Removals)
+}
@@ -0,0 +1,322 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// delegationFeeIncreaseNotice is how long before it's effective an increase
+// of a delegation fee must be accepted. As stakers can't be added more than
+// [maxFutureStartTime] before they start, the delegators that were added
+// before an increase was accepted are never charged the increased fee.
+const delegationFeeIncreaseNotice = maxFutureStartTime
+
+var (
+	errSetDelegationFeeBeforeAP6           = errors.New("delegation fee changes aren't allowed before apricot phase 6")
+	errNotAValidator                       = errors.New("tx didn't add a primary network validator")
+	errValidatorNotStaking                 = errors.New("validator isn't current or pending")
+	errDelegationFeeChangeAfterEnd         = errors.New("delegation fee change is effective after the validator stops validating")
+	errDelegationFeeChangeInPast           = errors.New("delegation fee change is effective before the current chain time")
+	errDelegationFeeChangeNotOrdered       = errors.New("delegation fee change isn't effective after the previously scheduled changes")
+	errDelegationFeeIncreaseNoticeTooShort = fmt.Errorf("delegation fee increase must be effective more than %s after the current chain time", delegationFeeIncreaseNotice)
+
+	_ UnsignedDecisionTx = &UnsignedSetDelegationFeeTx{}
+)
+
+// UnsignedSetDelegationFeeTx is an unsigned setDelegationFeeTx. It schedules
+// a change of the fee a primary network validator charges the delegations
+// that start at or after the change is effective. The fee of the delegations
+// that started before is unchanged.
+type UnsignedSetDelegationFeeTx struct {
+	// Metadata, inputs and outputs
+	BaseTx `serialize:"true"`
+	// ID of the tx that added the validator whose fee changes
+	ValidatorTxID ids.ID `serialize:"true" json:"validatorTxID"`
+	// New fee charged to delegators as a percentage, times 10,000
+	Shares uint32 `serialize:"true" json:"shares"`
+	// Unix time the new fee is effective from
+	EffectiveTime uint64 `serialize:"true" json:"effectiveTime"`
+	// Proves that the rewards owner of the validator authorized this change
+	ValidatorAuth verify.Verifiable `serialize:"true" json:"validatorAuthorization"`
+}
+
+// EffectiveTimestamp returns the time the new fee is effective from
+func (tx *UnsignedSetDelegationFeeTx) EffectiveTimestamp() time.Time {
+	return time.Unix(int64(tx.EffectiveTime), 0)
+}
+
+func (tx *UnsignedSetDelegationFeeTx) InputUTXOs() ids.Set { return nil }
+
+func (tx *UnsignedSetDelegationFeeTx) AtomicOperations() (ids.ID, *atomic.Requests, error) {
+	return ids.ID{}, nil, nil
+}
+
+// SyntacticVerify verifies that this transaction is well-formed
+func (tx *UnsignedSetDelegationFeeTx) SyntacticVerify(ctx *snow.Context) error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.syntacticallyVerified: // already passed syntactic verification
+		return nil
+	case tx.Shares > reward.PercentDenominator:
+		return errTooManyShares
+	}
+
+	if err := tx.BaseTx.SyntacticVerify(ctx); err != nil {
+		return err
+	}
+	if err := tx.ValidatorAuth.Verify(); err != nil {
+		return err
+	}
+
+	tx.syntacticallyVerified = true
+	return nil
+}
+
+// Attempts to verify this transaction with the provided state.
+func (tx *UnsignedSetDelegationFeeTx) SemanticVerify(vm *VM, parentState MutableState, stx *Tx) error {
+	vs := newVersionedState(
+		parentState,
+		parentState.CurrentStakerChainState(),
+		parentState.PendingStakerChainState(),
+	)
+	_, err := tx.Execute(vm, vs, stx)
+	return err
+}
+
+// Execute this transaction.
+func (tx *UnsignedSetDelegationFeeTx) Execute(
+	vm *VM,
+	vs VersionedState,
+	stx *Tx,
+) (
+	func() error,
+	error,
+) {
+	// Make sure this transaction is well formed.
+	if len(stx.Creds) == 0 {
+		return nil, errWrongNumberOfCredentials
+	}
+
+	if err := tx.SyntacticVerify(vm.ctx); err != nil {
+		return nil, err
+	}
+
+	currentTimestamp := vs.GetTimestamp()
+	if currentTimestamp.Before(vm.ApricotPhase6Time) {
+		return nil, errSetDelegationFeeBeforeAP6
+	}
+
+	// Select the credentials for each purpose
+	baseTxCredsLen := len(stx.Creds) - 1
+	baseTxCreds := stx.Creds[:baseTxCredsLen]
+	validatorCred := stx.Creds[baseTxCredsLen]
+
+	vdrTx, err := getStakingValidatorTx(vs, tx.ValidatorTxID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.Shares < vm.MinDelegationFee {
+		return nil, errInsufficientDelegationFee
+	}
+
+	effectiveTime := tx.EffectiveTimestamp()
+	if !effectiveTime.Before(vdrTx.EndTime()) {
+		return nil, errDelegationFeeChangeAfterEnd
+	}
+	if effectiveTime.Before(currentTimestamp) {
+		return nil, errDelegationFeeChangeInPast
+	}
+
+	changes, err := vs.GetDelegationFeeChanges(tx.ValidatorTxID)
+	if err != nil {
+		return nil, err
+	}
+	for _, changeTx := range changes {
+		change := changeTx.UnsignedTx.(*UnsignedSetDelegationFeeTx)
+		if !effectiveTime.After(change.EffectiveTimestamp()) {
+			return nil, errDelegationFeeChangeNotOrdered
+		}
+	}
+
+	// Delegators must be given notice of an increase of the fee
+	fee := delegationFeeAt(vdrTx.Shares, changes, effectiveTime)
+	if tx.Shares > fee && !effectiveTime.After(currentTimestamp.Add(delegationFeeIncreaseNotice)) {
+		return nil, errDelegationFeeIncreaseNoticeTooShort
+	}
+
+	if err := vm.fx.VerifyPermission(tx, tx.ValidatorAuth, validatorCred, vdrTx.RewardsOwner); err != nil {
+		return nil, err
+	}
+
+	// Verify the flowcheck
+	if err := vm.semanticVerifySpend(vs, tx, tx.Ins, tx.Outs, baseTxCreds, vm.TxFee, vm.ctx.AVAXAssetID); err != nil {
+		return nil, err
+	}
+
+	// Consume the UTXOS
+	consumeInputs(vs, tx.Ins)
+	// Produce the UTXOS
+	txID := tx.ID()
+	produceOutputs(vs, txID, vm.ctx.AVAXAssetID, tx.Outs)
+	// Schedule the change
+	vs.AddDelegationFeeChange(stx)
+
+	return nil, nil
+}
+
+// getStakingValidatorTx returns the tx [validatorTxID], if it added a primary
+// network validator that is current or pending
+func getStakingValidatorTx(vs MutableState, validatorTxID ids.ID) (*UnsignedAddValidatorTx, error) {
+	txIntf, _, err := vs.GetTx(validatorTxID)
+	if err == database.ErrNotFound {
+		return nil, errValidatorNotStaking
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get validator tx %s: %w", validatorTxID, err)
+	}
+	vdrTx, ok := txIntf.UnsignedTx.(*UnsignedAddValidatorTx)
+	if !ok {
+		return nil, errNotAValidator
+	}
+
+	_, _, err = vs.CurrentStakerChainState().GetStaker(validatorTxID)
+	switch err {
+	case nil:
+		return vdrTx, nil
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	pendingTx, err := vs.PendingStakerChainState().GetValidatorTx(vdrTx.Validator.NodeID)
+	switch {
+	case err == database.ErrNotFound:
+		return nil, errValidatorNotStaking
+	case err != nil:
+		return nil, err
+	case pendingTx.ID() != validatorTxID:
+		return nil, errValidatorNotStaking
+	default:
+		return vdrTx, nil
+	}
+}
+
+// delegationFeeAt returns the fee charged to the delegations that start at
+// [startTime], given the [shares] the validator was added with and the
+// [changes] of its fee that were scheduled since
+func delegationFeeAt(shares uint32, changes []*Tx, startTime time.Time) uint32 {
+	var effectiveTime time.Time
+	for _, changeTx := range changes {
+		change := changeTx.UnsignedTx.(*UnsignedSetDelegationFeeTx)
+		changeTime := change.EffectiveTimestamp()
+		if changeTime.After(startTime) || changeTime.Before(effectiveTime) {
+			continue
+		}
+		shares = change.Shares
+		effectiveTime = changeTime
+	}
+	return shares
+}
+
+// delegationFee returns the fee the validator added by [vdrTx] charges the
+// delegations that start at [startTime]
+func delegationFee(state MutableState, vdrTx *UnsignedAddValidatorTx, startTime time.Time) (uint32, error) {
+	changes, err := state.GetDelegationFeeChanges(vdrTx.ID())
+	if err != nil {
+		return 0, err
+	}
+	return delegationFeeAt(vdrTx.Shares, changes, startTime), nil
+}
+
+// Create a new transaction
+func (vm *VM) newSetDelegationFeeTx(
+	validatorTxID ids.ID, // ID of the tx that added the validator
+	shares uint32, // New fee charged to delegators
+	effectiveTime uint64, // Unix time the new fee is effective from
+	keys []*crypto.PrivateKeySECP256K1R, // Keys that pay the fee and own the validator's rewards
+	changeAddr ids.ShortID, // Address to send change to, if there is any
+) (*Tx, error) {
+	ins, outs, _, signers, err := vm.stake(keys, 0, vm.TxFee, changeAddr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
+	}
+
+	validatorAuth, validatorSigners, err := vm.authorizeValidator(vm.internalState, validatorTxID, keys)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't authorize tx's validator restrictions: %w", err)
+	}
+	signers = append(signers, validatorSigners)
+
+	// Create the tx
+	utx := &UnsignedSetDelegationFeeTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    vm.ctx.NetworkID,
+			BlockchainID: vm.ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		ValidatorTxID: validatorTxID,
+		Shares:        shares,
+		EffectiveTime: effectiveTime,
+		ValidatorAuth: validatorAuth,
+	}
+	tx := &Tx{UnsignedTx: utx}
+	if err := tx.Sign(Codec, signers); err != nil {
+		return nil, err
+	}
+	return tx, utx.SyntacticVerify(vm.ctx)
+}
+
+// authorizeValidator returns the input and the keys that prove the ownership
+// of the rewards of the validator added by [validatorTxID]
+func (vm *VM) authorizeValidator(
+	vs MutableState,
+	validatorTxID ids.ID,
+	keys []*crypto.PrivateKeySECP256K1R,
+) (
+	verify.Verifiable, // Input that names owners
+	[]*crypto.PrivateKeySECP256K1R, // Keys that prove ownership
+	error,
+) {
+	vdrTxIntf, _, err := vs.GetTx(validatorTxID)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"failed to fetch validator tx %s: %w",
+			validatorTxID,
+			err,
+		)
+	}
+	vdrTx, ok := vdrTxIntf.UnsignedTx.(*UnsignedAddValidatorTx)
+	if !ok {
+		return nil, nil, errWrongTxType
+	}
+
+	owner, ok := vdrTx.RewardsOwner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return nil, nil, errUnknownOwners
+	}
+
+	kc := secp256k1fx.NewKeychain(keys...)
+	now := uint64(vm.clock.Time().Unix())
+	indices, signers, matches := kc.Match(owner, now)
+	if !matches {
+		return nil, nil, errCantSign
+	}
+	return &secp256k1fx.Input{SigIndices: indices}, signers, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+)
+
+func TestSetDelegationFeeTxExecute(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	// The validator is pending, and validates for longer than the notice of
+	// an increase of its fee
+	timestamp := vm.internalState.GetTimestamp()
+	ownerKeys := []*crypto.PrivateKeySECP256K1R{keys[0]}
+	changeAddr := keys[0].PublicKey().Address()
+	addVdrTx, err := vm.newAddValidatorTx(
+		vm.MinValidatorStake,
+		uint64(timestamp.Add(time.Second).Unix()),
+		uint64(timestamp.Add(defaultMaxStakingDuration).Unix()),
+		ids.GenerateTestShortID(),
+		keys[0].PublicKey().Address(),
+		0,
+		ownerKeys,
+		ids.ShortEmpty,
+	)
+	assert.NoError(err)
+	vm.internalState.AddPendingStaker(addVdrTx)
+	vm.internalState.AddTx(addVdrTx, status.Committed)
+	assert.NoError(vm.internalState.Commit())
+	assert.NoError(vm.internalState.(*internalStateImpl).loadPendingValidators())
+	vdrTx := addVdrTx.UnsignedTx.(*UnsignedAddValidatorTx)
+	vdrTxID := addVdrTx.ID()
+
+	execute := func(tx *Tx) error {
+		vs := newVersionedState(
+			vm.internalState,
+			vm.internalState.CurrentStakerChainState(),
+			vm.internalState.PendingStakerChainState(),
+		)
+		if _, err := tx.UnsignedTx.(UnsignedDecisionTx).Execute(vm, vs, tx); err != nil {
+			return err
+		}
+		vs.Apply(vm.internalState)
+		return vm.internalState.Commit()
+	}
+	unix := func(t time.Time) uint64 { return uint64(t.Unix()) }
+
+	// Increases of the fee must be given notice
+	tx, err := vm.newSetDelegationFeeTx(vdrTxID, 20_000, unix(timestamp.Add(time.Hour)), ownerKeys, changeAddr)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errDelegationFeeIncreaseNoticeTooShort)
+
+	increaseTime := timestamp.Add(delegationFeeIncreaseNotice + time.Second)
+	tx, err = vm.newSetDelegationFeeTx(vdrTxID, 20_000, unix(increaseTime), ownerKeys, changeAddr)
+	assert.NoError(err)
+	assert.NoError(execute(tx))
+
+	// Changes must be scheduled in order
+	tx, err = vm.newSetDelegationFeeTx(vdrTxID, 10_000, unix(increaseTime), ownerKeys, changeAddr)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errDelegationFeeChangeNotOrdered)
+
+	// Decreases of the fee don't need notice
+	decreaseTime := increaseTime.Add(time.Second)
+	tx, err = vm.newSetDelegationFeeTx(vdrTxID, 10_000, unix(decreaseTime), ownerKeys, changeAddr)
+	assert.NoError(err)
+	assert.NoError(execute(tx))
+
+	tx, err = vm.newSetDelegationFeeTx(vdrTxID, 10_000, unix(vdrTx.EndTime()), ownerKeys, changeAddr)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errDelegationFeeChangeAfterEnd)
+
+	// Only the rewards owner of the validator can change its fee
+	_, err = vm.newSetDelegationFeeTx(vdrTxID, 10_000, unix(decreaseTime.Add(time.Second)), []*crypto.PrivateKeySECP256K1R{keys[1]}, ids.ShortEmpty)
+	assert.ErrorIs(err, errCantSign)
+
+	vm.ApricotPhase6Time = timestamp.Add(time.Second)
+	tx, err = vm.newSetDelegationFeeTx(vdrTxID, 10_000, unix(decreaseTime.Add(time.Second)), ownerKeys, changeAddr)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errSetDelegationFeeBeforeAP6)
+
+	// The fee of a delegation is the one in effect when it starts
+	for startTime, expectedShares := range map[time.Time]uint32{
+		timestamp:                      vdrTx.Shares,
+		increaseTime.Add(-time.Second): vdrTx.Shares,
+		increaseTime:                   20_000,
+		decreaseTime:                   10_000,
+		vdrTx.EndTime():                10_000,
+	} {
+		shares, err := delegationFee(vm.internalState, vdrTx, startTime)
+		assert.NoError(err)
+		assert.Equal(expectedShares, shares)
+	}
+}
+
+func TestSetDelegationFeeTxNotAValidator(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	_, err := getStakingValidatorTx(vm.internalState, testSubnet1.ID())
+	assert.ErrorIs(err, errNotAValidator)
+
+	_, err = getStakingValidatorTx(vm.internalState, ids.GenerateTestID())
+	assert.ErrorIs(err, errValidatorNotStaking)
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+)
+
+var (
+	errSetSubnetFeeConfigBeforeAP6 = errors.New("subnet fee configs aren't allowed before apricot phase 6")
+	errPrimaryNetworkFeeConfig     = errors.New("primary network's fee config can't be set")
+	errNoFeeAsset                  = errors.New("fee config must name the fee asset")
+	errSubnetHasChains             = errors.New("subnet's fee config must be set before its chains are created")
+
+	_ UnsignedDecisionTx = &UnsignedSetSubnetFeeConfigTx{}
+)
+
+// UnsignedSetSubnetFeeConfigTx is an unsigned setSubnetFeeConfigTx. It
+// declares the asset the fees of the chains of a subnet are paid in, and
+// whether they're burned or collected. The chains created afterwards are given
+// the config, and their VMs enforce it.
+//
+// The config can only be set, or replaced, before the first chain of the
+// subnet is created, so that the chains of the subnet never change fees.
+type UnsignedSetSubnetFeeConfigTx struct {
+	// Metadata, inputs and outputs
+	BaseTx `serialize:"true"`
+	// ID of the subnet whose chains are configured
+	SubnetID ids.ID `serialize:"true" json:"subnetID"`
+	// ID of the asset the fees of the chains are paid in
+	FeeAssetID ids.ID `serialize:"true" json:"feeAssetID"`
+	// Address that receives the fees of the chains. If empty, the fees are
+	// burned.
+	FeeCollector ids.ShortID `serialize:"true" json:"feeCollector"`
+	// Proves that the owner of the subnet authorized this config
+	SubnetAuth verify.Verifiable `serialize:"true" json:"subnetAuthorization"`
+}
+
+func (tx *UnsignedSetSubnetFeeConfigTx) InputUTXOs() ids.Set { return nil }
+
+func (tx *UnsignedSetSubnetFeeConfigTx) AtomicOperations() (ids.ID, *atomic.Requests, error) {
+	return ids.ID{}, nil, nil
+}
+
+// SyntacticVerify verifies that this transaction is well-formed
+func (tx *UnsignedSetSubnetFeeConfigTx) SyntacticVerify(ctx *snow.Context) error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.syntacticallyVerified: // already passed syntactic verification
+		return nil
+	case tx.SubnetID == constants.PrimaryNetworkID:
+		return errPrimaryNetworkFeeConfig
+	case tx.FeeAssetID == ids.Empty:
+		return errNoFeeAsset
+	}
+
+	if err := tx.BaseTx.SyntacticVerify(ctx); err != nil {
+		return err
+	}
+	if err := tx.SubnetAuth.Verify(); err != nil {
+		return err
+	}
+
+	tx.syntacticallyVerified = true
+	return nil
+}
+
+// Attempts to verify this transaction with the provided state.
+func (tx *UnsignedSetSubnetFeeConfigTx) SemanticVerify(vm *VM, parentState MutableState, stx *Tx) error {
+	vs := newVersionedState(
+		parentState,
+		parentState.CurrentStakerChainState(),
+		parentState.PendingStakerChainState(),
+	)
+	_, err := tx.Execute(vm, vs, stx)
+	return err
+}
+
+// Execute this transaction.
+func (tx *UnsignedSetSubnetFeeConfigTx) Execute(
+	vm *VM,
+	vs VersionedState,
+	stx *Tx,
+) (
+	func() error,
+	error,
+) {
+	// Make sure this transaction is well formed.
+	if len(stx.Creds) == 0 {
+		return nil, errWrongNumberOfCredentials
+	}
+
+	if err := tx.SyntacticVerify(vm.ctx); err != nil {
+		return nil, err
+	}
+
+	if vs.GetTimestamp().Before(vm.ApricotPhase6Time) {
+		return nil, errSetSubnetFeeConfigBeforeAP6
+	}
+
+	// Select the credentials for each purpose
+	baseTxCredsLen := len(stx.Creds) - 1
+	baseTxCreds := stx.Creds[:baseTxCredsLen]
+	subnetCred := stx.Creds[baseTxCredsLen]
+
+	// Verify the flowcheck
+	if err := vm.semanticVerifySpend(vs, tx, tx.Ins, tx.Outs, baseTxCreds, vm.TxFee, vm.ctx.AVAXAssetID); err != nil {
+		return nil, err
+	}
+
+	subnetIntf, _, err := vs.GetTx(tx.SubnetID)
+	if err == database.ErrNotFound {
+		return nil, fmt.Errorf("%s isn't a known subnet", tx.SubnetID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subnet, ok := subnetIntf.UnsignedTx.(*UnsignedCreateSubnetTx)
+	if !ok {
+		return nil, fmt.Errorf("%s isn't a subnet", tx.SubnetID)
+	}
+
+	chains, err := vs.GetChains(tx.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chains) != 0 {
+		return nil, errSubnetHasChains
+	}
+
+	// Verify that this config is authorized by the subnet
+	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, subnet.Owner); err != nil {
+		return nil, err
+	}
+
+	// Consume the UTXOS
+	consumeInputs(vs, tx.Ins)
+	// Produce the UTXOS
+	txID := tx.ID()
+	produceOutputs(vs, txID, vm.ctx.AVAXAssetID, tx.Outs)
+	// Set the config of the chains of the subnet
+	vs.SetSubnetFeeConfig(stx)
+
+	return nil, nil
+}
+
+// Create a new transaction
+func (vm *VM) newSetSubnetFeeConfigTx(
+	subnetID ids.ID, // ID of the subnet whose chains are configured
+	feeAssetID ids.ID, // Asset the fees of the chains are paid in
+	feeCollector ids.ShortID, // Address that receives the fees, if they aren't burned
+	keys []*crypto.PrivateKeySECP256K1R, // Keys to sign the tx
+	changeAddr ids.ShortID, // Address to send change to, if there is any
+) (*Tx, error) {
+	ins, outs, _, signers, err := vm.stake(keys, 0, vm.TxFee, changeAddr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
+	}
+
+	subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, subnetID, keys)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
+	}
+	signers = append(signers, subnetSigners)
+
+	// Create the tx
+	utx := &UnsignedSetSubnetFeeConfigTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    vm.ctx.NetworkID,
+			BlockchainID: vm.ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		SubnetID:     subnetID,
+		FeeAssetID:   feeAssetID,
+		FeeCollector: feeCollector,
+		SubnetAuth:   subnetAuth,
+	}
+	tx := &Tx{UnsignedTx: utx}
+	if err := tx.Sign(Codec, signers); err != nil {
+		return nil, err
+	}
+	return tx, utx.SyntacticVerify(vm.ctx)
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+)
+
+type chainsRecorder struct {
+	chains.MockManager
+	created []chains.ChainParameters
+}
+
+func (r *chainsRecorder) CreateChain(chainParams chains.ChainParameters) {
+	r.created = append(r.created, chainParams)
+}
+
+func TestSetSubnetFeeConfigTxExecute(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	recorder := &chainsRecorder{}
+	vm.Chains = recorder
+	vm.WhitelistedSubnets.Add(testSubnet1.ID())
+
+	execute := func(tx *Tx) error {
+		vs := newVersionedState(
+			vm.internalState,
+			vm.internalState.CurrentStakerChainState(),
+			vm.internalState.PendingStakerChainState(),
+		)
+		if _, err := tx.UnsignedTx.(UnsignedDecisionTx).Execute(vm, vs, tx); err != nil {
+			return err
+		}
+		vs.Apply(vm.internalState)
+		vm.internalState.AddTx(tx, status.Committed)
+		return vm.internalState.Commit()
+	}
+
+	subnetID := testSubnet1.ID()
+	subnetKeys := []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]}
+	changeAddr := keys[0].PublicKey().Address()
+
+	_, err := vm.internalState.GetSubnetFeeConfig(subnetID)
+	assert.Error(err)
+
+	_, err = vm.newSetSubnetFeeConfigTx(subnetID, ids.Empty, ids.ShortEmpty, subnetKeys, changeAddr)
+	assert.ErrorIs(err, errNoFeeAsset)
+
+	// The fee config is only allowed after apricot phase 6
+	timestamp := vm.internalState.GetTimestamp()
+	vm.ApricotPhase6Time = timestamp.Add(time.Second)
+	feeAssetID := ids.GenerateTestID()
+	tx, err := vm.newSetSubnetFeeConfigTx(subnetID, feeAssetID, ids.ShortEmpty, subnetKeys, changeAddr)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errSetSubnetFeeConfigBeforeAP6)
+	vm.ApricotPhase6Time = timestamp
+
+	assert.NoError(execute(tx))
+
+	// The config can be replaced while the subnet has no chains
+	feeCollector := ids.GenerateTestShortID()
+	tx, err = vm.newSetSubnetFeeConfigTx(subnetID, feeAssetID, feeCollector, subnetKeys, changeAddr)
+	assert.NoError(err)
+	assert.NoError(execute(tx))
+
+	configTx, err := vm.internalState.GetSubnetFeeConfig(subnetID)
+	assert.NoError(err)
+	assert.Equal(tx.ID(), configTx.ID())
+
+	// The chains of the subnet are given the config
+	chainTx, err := vm.newCreateChainTx(subnetID, nil, constants.AVMID, nil, "chain name", subnetKeys, changeAddr)
+	assert.NoError(err)
+	assert.NoError(vm.createChain(chainTx))
+	assert.Len(recorder.created, 1)
+	assert.Equal(feeAssetID, recorder.created[0].FeeAssetID)
+	assert.Equal(feeCollector, recorder.created[0].FeeCollector)
+
+	// Once the subnet has a chain, its config can't change
+	vm.internalState.AddChain(chainTx)
+	assert.NoError(vm.internalState.Commit())
+	tx, err = vm.newSetSubnetFeeConfigTx(subnetID, feeAssetID, ids.ShortEmpty, subnetKeys, changeAddr)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errSubnetHasChains)
+}
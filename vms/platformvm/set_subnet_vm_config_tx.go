@@ -0,0 +1,194 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+)
+
+// maxSubnetVMConfigSize is the largest config a subnet can publish for the VMs
+// of its chains
+const maxSubnetVMConfigSize = 32 * units.KiB
+
+var (
+	errSetSubnetVMConfigBeforeAP6 = errors.New("subnet VM configs aren't allowed before apricot phase 6")
+	errPrimaryNetworkVMConfig     = errors.New("primary network's VM config can't be set")
+	errSubnetVMConfigTooLarge     = fmt.Errorf("subnet VM config is larger than %d bytes", maxSubnetVMConfigSize)
+
+	_ UnsignedDecisionTx = &UnsignedSetSubnetVMConfigTx{}
+)
+
+// UnsignedSetSubnetVMConfigTx is an unsigned setSubnetVMConfigTx. It publishes
+// a config blob for the VMs of the chains of a subnet, so that the owner of the
+// subnet can change the parameters of its VMs without distributing files to
+// each node. The latest config is given to the VMs when their chains are
+// created, and delivered to the running VMs once the tx is accepted.
+//
+// The P-chain doesn't interpret the config.
+type UnsignedSetSubnetVMConfigTx struct {
+	// Metadata, inputs and outputs
+	BaseTx `serialize:"true"`
+	// ID of the subnet whose VMs are configured
+	SubnetID ids.ID `serialize:"true" json:"subnetID"`
+	// Config given to the VMs of the chains of the subnet
+	Config []byte `serialize:"true" json:"config"`
+	// Proves that the owner of the subnet authorized this config
+	SubnetAuth verify.Verifiable `serialize:"true" json:"subnetAuthorization"`
+}
+
+func (tx *UnsignedSetSubnetVMConfigTx) InputUTXOs() ids.Set { return nil }
+
+func (tx *UnsignedSetSubnetVMConfigTx) AtomicOperations() (ids.ID, *atomic.Requests, error) {
+	return ids.ID{}, nil, nil
+}
+
+// SyntacticVerify verifies that this transaction is well-formed
+func (tx *UnsignedSetSubnetVMConfigTx) SyntacticVerify(ctx *snow.Context) error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.syntacticallyVerified: // already passed syntactic verification
+		return nil
+	case tx.SubnetID == constants.PrimaryNetworkID:
+		return errPrimaryNetworkVMConfig
+	case len(tx.Config) > maxSubnetVMConfigSize:
+		return errSubnetVMConfigTooLarge
+	}
+
+	if err := tx.BaseTx.SyntacticVerify(ctx); err != nil {
+		return err
+	}
+	if err := tx.SubnetAuth.Verify(); err != nil {
+		return err
+	}
+
+	tx.syntacticallyVerified = true
+	return nil
+}
+
+// Attempts to verify this transaction with the provided state.
+func (tx *UnsignedSetSubnetVMConfigTx) SemanticVerify(vm *VM, parentState MutableState, stx *Tx) error {
+	vs := newVersionedState(
+		parentState,
+		parentState.CurrentStakerChainState(),
+		parentState.PendingStakerChainState(),
+	)
+	_, err := tx.Execute(vm, vs, stx)
+	return err
+}
+
+// Execute this transaction.
+func (tx *UnsignedSetSubnetVMConfigTx) Execute(
+	vm *VM,
+	vs VersionedState,
+	stx *Tx,
+) (
+	func() error,
+	error,
+) {
+	// Make sure this transaction is well formed.
+	if len(stx.Creds) == 0 {
+		return nil, errWrongNumberOfCredentials
+	}
+
+	if err := tx.SyntacticVerify(vm.ctx); err != nil {
+		return nil, err
+	}
+
+	if vs.GetTimestamp().Before(vm.ApricotPhase6Time) {
+		return nil, errSetSubnetVMConfigBeforeAP6
+	}
+
+	// Select the credentials for each purpose
+	baseTxCredsLen := len(stx.Creds) - 1
+	baseTxCreds := stx.Creds[:baseTxCredsLen]
+	subnetCred := stx.Creds[baseTxCredsLen]
+
+	// Verify the flowcheck
+	if err := vm.semanticVerifySpend(vs, tx, tx.Ins, tx.Outs, baseTxCreds, vm.TxFee, vm.ctx.AVAXAssetID); err != nil {
+		return nil, err
+	}
+
+	subnetIntf, _, err := vs.GetTx(tx.SubnetID)
+	if err == database.ErrNotFound {
+		return nil, fmt.Errorf("%s isn't a known subnet", tx.SubnetID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subnet, ok := subnetIntf.UnsignedTx.(*UnsignedCreateSubnetTx)
+	if !ok {
+		return nil, fmt.Errorf("%s isn't a subnet", tx.SubnetID)
+	}
+
+	// Verify that this config is authorized by the subnet
+	if err := vm.fx.VerifyPermission(tx, tx.SubnetAuth, subnetCred, subnet.Owner); err != nil {
+		return nil, err
+	}
+
+	// Consume the UTXOS
+	consumeInputs(vs, tx.Ins)
+	// Produce the UTXOS
+	txID := tx.ID()
+	produceOutputs(vs, txID, vm.ctx.AVAXAssetID, tx.Outs)
+	// Set the config of the VMs of the subnet
+	vs.SetSubnetVMConfig(stx)
+
+	// The running chains of the subnet are given the config once it's
+	// accepted
+	onAccept := func() error {
+		vm.Chains.SetSubnetVMConfig(tx.SubnetID, tx.Config)
+		return nil
+	}
+	return onAccept, nil
+}
+
+// Create a new transaction
+func (vm *VM) newSetSubnetVMConfigTx(
+	subnetID ids.ID, // ID of the subnet whose VMs are configured
+	config []byte, // Config given to the VMs of the subnet
+	keys []*crypto.PrivateKeySECP256K1R, // Keys to sign the tx
+	changeAddr ids.ShortID, // Address to send change to, if there is any
+) (*Tx, error) {
+	ins, outs, _, signers, err := vm.stake(keys, 0, vm.TxFee, changeAddr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
+	}
+
+	subnetAuth, subnetSigners, err := vm.authorize(vm.internalState, subnetID, keys)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
+	}
+	signers = append(signers, subnetSigners)
+
+	// Create the tx
+	utx := &UnsignedSetSubnetVMConfigTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    vm.ctx.NetworkID,
+			BlockchainID: vm.ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		SubnetID:   subnetID,
+		Config:     config,
+		SubnetAuth: subnetAuth,
+	}
+	tx := &Tx{UnsignedTx: utx}
+	if err := tx.Sign(Codec, signers); err != nil {
+		return nil, err
+	}
+	return tx, utx.SyntacticVerify(vm.ctx)
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+)
+
+type vmConfigRecorder struct {
+	chainsRecorder
+	configs map[ids.ID][]byte
+}
+
+func (r *vmConfigRecorder) SetSubnetVMConfig(subnetID ids.ID, config []byte) {
+	r.configs[subnetID] = config
+}
+
+func TestSetSubnetVMConfigTxExecute(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	recorder := &vmConfigRecorder{configs: make(map[ids.ID][]byte)}
+	vm.Chains = recorder
+	vm.WhitelistedSubnets.Add(testSubnet1.ID())
+
+	execute := func(tx *Tx) error {
+		vs := newVersionedState(
+			vm.internalState,
+			vm.internalState.CurrentStakerChainState(),
+			vm.internalState.PendingStakerChainState(),
+		)
+		onAccept, err := tx.UnsignedTx.(UnsignedDecisionTx).Execute(vm, vs, tx)
+		if err != nil {
+			return err
+		}
+		vs.Apply(vm.internalState)
+		vm.internalState.AddTx(tx, status.Committed)
+		if err := vm.internalState.Commit(); err != nil {
+			return err
+		}
+		return onAccept()
+	}
+
+	subnetID := testSubnet1.ID()
+	subnetKeys := []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]}
+	changeAddr := keys[0].PublicKey().Address()
+
+	_, err := vm.internalState.GetSubnetVMConfig(subnetID)
+	assert.Error(err)
+
+	_, err = vm.newSetSubnetVMConfigTx(subnetID, make([]byte, maxSubnetVMConfigSize+1), subnetKeys, changeAddr)
+	assert.ErrorIs(err, errSubnetVMConfigTooLarge)
+
+	// The VM config is only allowed after apricot phase 6
+	timestamp := vm.internalState.GetTimestamp()
+	vm.ApricotPhase6Time = timestamp.Add(time.Second)
+	tx, err := vm.newSetSubnetVMConfigTx(subnetID, []byte("config 1"), subnetKeys, changeAddr)
+	assert.NoError(err)
+	assert.ErrorIs(execute(tx), errSetSubnetVMConfigBeforeAP6)
+	vm.ApricotPhase6Time = timestamp
+
+	assert.NoError(execute(tx))
+	assert.Equal([]byte("config 1"), recorder.configs[subnetID])
+
+	// New chains of the subnet are given the config
+	chainTx, err := vm.newCreateChainTx(subnetID, nil, constants.AVMID, nil, "chain name", subnetKeys, changeAddr)
+	assert.NoError(err)
+	assert.NoError(vm.createChain(chainTx))
+	assert.Len(recorder.created, 1)
+	assert.Equal([]byte("config 1"), recorder.created[0].SubnetVMConfig)
+	vm.internalState.AddChain(chainTx)
+	assert.NoError(vm.internalState.Commit())
+
+	// The config can be replaced after the subnet has chains
+	tx, err = vm.newSetSubnetVMConfigTx(subnetID, []byte("config 2"), subnetKeys, changeAddr)
+	assert.NoError(err)
+	assert.NoError(execute(tx))
+	assert.Equal([]byte("config 2"), recorder.configs[subnetID])
+
+	configTx, err := vm.internalState.GetSubnetVMConfig(subnetID)
+	assert.NoError(err)
+	assert.Equal(tx.ID(), configTx.ID())
+}
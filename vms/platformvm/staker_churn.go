@@ -0,0 +1,108 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var errChurnLimitExceeded = errors.New("staker exceeds the churn limit of the subnet")
+
+// verifyChurn returns an error if a staker of [subnetID] with [weight] that
+// stakes from [startTime] to [endTime] would make the stake that starts, or
+// stops, in either period exceed the churn limit of the subnet.
+//
+// The churn isn't limited before apricot phase 6, so that stakers that were
+// added before the limit existed are still valid when the chain is replayed.
+func (vm *VM) verifyChurn(
+	currentStakers currentStakerChainState,
+	pendingStakers pendingStakerChainState,
+	currentTimestamp time.Time,
+	subnetID ids.ID,
+	weight uint64,
+	startTime time.Time,
+	endTime time.Time,
+) error {
+	if !vm.ChurnConfig.Enabled() || currentTimestamp.Before(vm.ApricotPhase6Time) {
+		return nil
+	}
+
+	vdrs, err := currentStakers.ValidatorSet(subnetID)
+	if err != nil {
+		return err
+	}
+	limit := vm.ChurnConfig.Limit(vdrs.Weight())
+
+	startPeriod := vm.ChurnConfig.PeriodOf(startTime)
+	endPeriod := vm.ChurnConfig.PeriodOf(endTime)
+	var startingWeight, stoppingWeight uint64
+	addChurn := func(stakers []*Tx, pending bool) error {
+		for _, stakerTx := range stakers {
+			stakerSubnetID, staker, ok := stakerOf(stakerTx)
+			if !ok || stakerSubnetID != subnetID {
+				continue
+			}
+			if pending && vm.ChurnConfig.PeriodOf(staker.StartTime()) == startPeriod {
+				if startingWeight, err = safemath.Add64(startingWeight, staker.Weight()); err != nil {
+					return err
+				}
+			}
+			if vm.ChurnConfig.PeriodOf(staker.EndTime()) == endPeriod {
+				if stoppingWeight, err = safemath.Add64(stoppingWeight, staker.Weight()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := addChurn(currentStakers.Stakers(), false); err != nil {
+		return err
+	}
+	if err := addChurn(pendingStakers.Stakers(), true); err != nil {
+		return err
+	}
+
+	if err := verifyPeriodChurn(startingWeight, weight, limit); err != nil {
+		return fmt.Errorf("%w in the period of its start time %s", err, startTime)
+	}
+	if err := verifyPeriodChurn(stoppingWeight, weight, limit); err != nil {
+		return fmt.Errorf("%w in the period of its end time %s", err, endTime)
+	}
+	return nil
+}
+
+// verifyPeriodChurn returns an error if adding [weight] to the [churnWeight]
+// of a period exceeds [limit]. The first staker of a period is always allowed.
+func verifyPeriodChurn(churnWeight, weight, limit uint64) error {
+	if churnWeight == 0 {
+		return nil
+	}
+	newChurnWeight, err := safemath.Add64(churnWeight, weight)
+	if err != nil || newChurnWeight > limit {
+		return fmt.Errorf("%w: %d stake would churn, out of at most %d", errChurnLimitExceeded, newChurnWeight, limit)
+	}
+	return nil
+}
+
+// stakerOf returns the subnet and the staking period of the staker added by
+// [tx], if it adds a staker
+func stakerOf(tx *Tx) (ids.ID, TimedTx, bool) {
+	switch utx := tx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		return constants.PrimaryNetworkID, utx, true
+	case *UnsignedAddDelegatorTx:
+		return constants.PrimaryNetworkID, utx, true
+	case *UnsignedAddSubnetValidatorTx:
+		return utx.Validator.Subnet, utx, true
+	default:
+		return ids.Empty, nil, false
+	}
+}
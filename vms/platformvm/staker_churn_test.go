@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm/churn"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+)
+
+func newChurnTestValidatorTx(weight uint64, startTime, endTime time.Time) *Tx {
+	return &Tx{UnsignedTx: &UnsignedAddValidatorTx{
+		Validator: Validator{
+			NodeID: ids.GenerateTestShortID(),
+			Start:  uint64(startTime.Unix()),
+			End:    uint64(endTime.Unix()),
+			Wght:   weight,
+		},
+	}}
+}
+
+func TestVerifyChurn(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	currentStakers := vm.internalState.CurrentStakerChainState()
+	pendingStakers := vm.internalState.PendingStakerChainState()
+	vdrs, err := currentStakers.ValidatorSet(constants.PrimaryNetworkID)
+	assert.NoError(err)
+	limit := vdrs.Weight() / 10
+
+	startTime := defaultGenesisTime.Add(time.Hour)
+	endTime := startTime.Add(defaultMinStakingDuration)
+
+	// The churn isn't limited by default
+	assert.NoError(vm.verifyChurn(currentStakers, pendingStakers, defaultGenesisTime, constants.PrimaryNetworkID, 2*limit, startTime, endTime))
+
+	vm.ChurnConfig = churn.Config{
+		Period:  time.Hour,
+		MaxRate: reward.PercentDenominator / 10,
+	}
+
+	// The first staker of a period is always allowed
+	assert.NoError(vm.verifyChurn(currentStakers, pendingStakers, defaultGenesisTime, constants.PrimaryNetworkID, 2*limit, startTime, endTime))
+
+	pendingStakers = pendingStakers.AddStaker(newChurnTestValidatorTx(limit/2, startTime, endTime))
+	assert.NoError(vm.verifyChurn(currentStakers, pendingStakers, defaultGenesisTime, constants.PrimaryNetworkID, limit/2, startTime, endTime))
+	err = vm.verifyChurn(currentStakers, pendingStakers, defaultGenesisTime, constants.PrimaryNetworkID, limit, startTime, endTime)
+	assert.True(errors.Is(err, errChurnLimitExceeded))
+
+	// Stakers that stop in the same period also churn
+	err = vm.verifyChurn(currentStakers, pendingStakers, defaultGenesisTime, constants.PrimaryNetworkID, limit, startTime.Add(time.Hour), endTime)
+	assert.True(errors.Is(err, errChurnLimitExceeded))
+
+	// Other periods and subnets aren't affected
+	assert.NoError(vm.verifyChurn(currentStakers, pendingStakers, defaultGenesisTime, constants.PrimaryNetworkID, limit, startTime.Add(time.Hour), endTime.Add(time.Hour)))
+	assert.NoError(vm.verifyChurn(currentStakers, pendingStakers, defaultGenesisTime, testSubnet1.ID(), limit, startTime, endTime))
+
+	// The churn isn't limited before apricot phase 6
+	vm.ApricotPhase6Time = defaultGenesisTime.Add(time.Second)
+	assert.NoError(vm.verifyChurn(currentStakers, pendingStakers, defaultGenesisTime, constants.PrimaryNetworkID, limit, startTime, endTime))
+	err = vm.verifyChurn(currentStakers, pendingStakers, vm.ApricotPhase6Time, constants.PrimaryNetworkID, limit, startTime, endTime)
+	assert.True(errors.Is(err, errChurnLimitExceeded))
+}
@@ -114,7 +114,7 @@ func (sb *StandardBlock) Verify() error {
 
 		onAccept, err := utx.Execute(sb.vm, sb.onAcceptState, tx)
 		if err != nil {
-			sb.vm.droppedTxCache.Put(txID, err.Error()) // cache tx as dropped
+			sb.vm.markDropped(txID, err.Error())
 			return err
 		}
 
@@ -207,7 +207,16 @@ func (sb *StandardBlock) Accept() error {
 		)
 	}
 
-	if err := sb.vm.ctx.SharedMemory.Apply(sharedMemoryOps, batch); err != nil {
+	indexBatches, err := sb.vm.indexImports(sb.Txs)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to index imports of block %s: %w",
+			blkID,
+			err,
+		)
+	}
+
+	if err := sb.vm.ctx.SharedMemory.Apply(sharedMemoryOps, append(indexBatches, batch)...); err != nil {
 		return fmt.Errorf("failed to apply vm's state to shared memory: %w", err)
 	}
 
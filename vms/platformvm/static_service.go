@@ -76,10 +76,21 @@ type APIPrimaryValidator struct {
 	Uptime             *json.Float32 `json:"uptime,omitempty"`
 	Connected          *bool         `json:"connected,omitempty"`
 	Staked             []APIUTXO     `json:"staked,omitempty"`
+	// The changes of the delegation fee scheduled since the validator was
+	// added. [DelegationFee] is the fee the validator was added with.
+	DelegationFeeChanges []APIDelegationFeeChange `json:"delegationFeeChanges,omitempty"`
 	// The delegators delegating to this validator
 	Delegators []APIPrimaryDelegator `json:"delegators"`
 }
 
+// APIDelegationFeeChange is the repr. of a scheduled change of a delegation
+// fee sent over APIs.
+type APIDelegationFeeChange struct {
+	TxID          ids.ID       `json:"txID"`
+	EffectiveTime json.Uint64  `json:"effectiveTime"`
+	DelegationFee json.Float32 `json:"delegationFee"`
+}
+
 // APIPrimaryDelegator is the repr. of a primary network delegator sent over APIs.
 type APIPrimaryDelegator struct {
 	APIStaker
@@ -84,7 +84,7 @@ type UnsignedAtomicTx interface {
 	AtomicExecute(vm *VM, parentState MutableState, stx *Tx) (VersionedState, error)
 
 	// Accept this transaction with the additionally provided state transitions.
-	AtomicAccept(ctx *snow.Context, batch database.Batch) error
+	AtomicAccept(ctx *snow.Context, batches ...database.Batch) error
 }
 
 // Tx is a signed transaction
@@ -0,0 +1,59 @@
+//go:build go1.18
+// +build go1.18
+
+// ^ Only build this file if native fuzzing is supported
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+// FuzzParseTx checks that any tx gossiped by a peer is either rejected or
+// parsed into a tx that encodes back to the same bytes. The corpus is seeded
+// with the txs of the Mainnet and Fuji genesis.
+func FuzzParseTx(f *testing.F) {
+	for _, config := range []*genesis.Config{&genesis.MainnetConfig, &genesis.FujiConfig} {
+		genesisBytes, _, err := genesis.FromConfig(config)
+		if err != nil {
+			f.Fatal(err)
+		}
+		g := platformvm.Genesis{}
+		if _, err := platformvm.GenesisCodec.Unmarshal(genesisBytes, &g); err != nil {
+			f.Fatal(err)
+		}
+		if err := g.Initialize(); err != nil {
+			f.Fatal(err)
+		}
+		for _, tx := range append(g.Validators, g.Chains...) {
+			f.Add(tx.Bytes())
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, txBytes []byte) {
+		// Parsed like the txs gossiped by peers
+		tx := &platformvm.Tx{}
+		if _, err := platformvm.Codec.Unmarshal(txBytes, tx); err != nil {
+			return
+		}
+		unsignedBytes, err := platformvm.Codec.Marshal(platformvm.CodecVersion, &tx.UnsignedTx)
+		if err != nil {
+			t.Fatalf("failed to marshal the unsigned tx: %s", err)
+		}
+		tx.Initialize(unsignedBytes, txBytes)
+
+		signedBytes, err := platformvm.Codec.Marshal(platformvm.CodecVersion, tx)
+		if err != nil {
+			t.Fatalf("failed to marshal the tx: %s", err)
+		}
+		if !bytes.Equal(signedBytes, txBytes) {
+			t.Fatalf("parsed tx encodes to %x instead of %x", signedBytes, txBytes)
+		}
+	})
+}
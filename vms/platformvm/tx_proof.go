@@ -0,0 +1,159 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+var (
+	errTxNotInBlock        = errors.New("tx isn't in the block")
+	errInvalidProofIndex   = errors.New("proof index is out of range")
+	errWrongNumSiblings    = errors.New("proof has the wrong number of siblings")
+	errWrongInclusionProof = errors.New("proof doesn't match the root")
+)
+
+// TxInclusionProof proves that a tx is in a block.
+//
+// The root is the Merkle root of the IDs of the block's txs, in the order they
+// appear in the block. Each node of the tree is the hash of the concatenation
+// of its children. If a level has an odd number of nodes, the last node is
+// moved up to the next level unchanged. The root can be recomputed from the
+// block with TxsRoot.
+type TxInclusionProof struct {
+	TxID    ids.ID      `json:"txID"`
+	BlockID ids.ID      `json:"blockID"`
+	Root    ids.ID      `json:"root"`
+	Index   json.Uint32 `json:"index"`
+	NumTxs  json.Uint32 `json:"numTxs"`
+	// Siblings of the path from the tx to the root, starting at the leaves
+	Siblings []ids.ID `json:"siblings"`
+}
+
+// Verify returns nil if the proof shows that [TxID] is at [Index] of a block
+// whose txs have the Merkle root [Root].
+func (p *TxInclusionProof) Verify() error {
+	index := uint32(p.Index)
+	numNodes := uint32(p.NumTxs)
+	if index >= numNodes {
+		return errInvalidProofIndex
+	}
+
+	node := p.TxID
+	siblings := p.Siblings
+	for ; numNodes > 1; numNodes = (numNodes + 1) / 2 {
+		switch {
+		case index%2 == 1:
+			if len(siblings) == 0 {
+				return errWrongNumSiblings
+			}
+			node = hashTxNodes(siblings[0], node)
+			siblings = siblings[1:]
+		case index+1 < numNodes:
+			if len(siblings) == 0 {
+				return errWrongNumSiblings
+			}
+			node = hashTxNodes(node, siblings[0])
+			siblings = siblings[1:]
+		}
+		index /= 2
+	}
+	if len(siblings) != 0 {
+		return errWrongNumSiblings
+	}
+	if node != p.Root {
+		return errWrongInclusionProof
+	}
+	return nil
+}
+
+// TxsRoot returns the Merkle root of [txIDs]
+func TxsRoot(txIDs []ids.ID) ids.ID {
+	if len(txIDs) == 0 {
+		return ids.Empty
+	}
+	level := txIDs
+	for len(level) > 1 {
+		level = nextTxLevel(level)
+	}
+	return level[0]
+}
+
+// newTxInclusionProof returns a proof of the tx at [index] of [txIDs], which
+// are the txs of [blkID]
+func newTxInclusionProof(blkID ids.ID, txIDs []ids.ID, index int) *TxInclusionProof {
+	proof := &TxInclusionProof{
+		TxID:    txIDs[index],
+		BlockID: blkID,
+		Index:   json.Uint32(index),
+		NumTxs:  json.Uint32(len(txIDs)),
+	}
+	level := txIDs
+	for len(level) > 1 {
+		switch {
+		case index%2 == 1:
+			proof.Siblings = append(proof.Siblings, level[index-1])
+		case index+1 < len(level):
+			proof.Siblings = append(proof.Siblings, level[index+1])
+		}
+		level = nextTxLevel(level)
+		index /= 2
+	}
+	proof.Root = level[0]
+	return proof
+}
+
+func nextTxLevel(level []ids.ID) []ids.ID {
+	next := make([]ids.ID, 0, (len(level)+1)/2)
+	for i := 0; i+1 < len(level); i += 2 {
+		next = append(next, hashTxNodes(level[i], level[i+1]))
+	}
+	if len(level)%2 == 1 {
+		next = append(next, level[len(level)-1])
+	}
+	return next
+}
+
+func hashTxNodes(left, right ids.ID) ids.ID {
+	return hashing.ComputeHash256Array(append(left[:], right[:]...))
+}
+
+// blockTxs returns the txs of [blk] in the order they appear in the block
+func blockTxs(blk Block) []*Tx {
+	switch blk := blk.(type) {
+	case *StandardBlock:
+		return blk.Txs
+	case *AtomicBlock:
+		return []*Tx{&blk.Tx}
+	case *ProposalBlock:
+		return []*Tx{&blk.Tx}
+	default:
+		return nil
+	}
+}
+
+// getTxInclusionProof returns a proof that [txID] is in [blkID]
+func (vm *VM) getTxInclusionProof(blkID ids.ID, txID ids.ID) (*TxInclusionProof, error) {
+	blk, err := vm.getBlock(blkID)
+	if err != nil {
+		return nil, err
+	}
+	txs := blockTxs(blk)
+	txIDs := make([]ids.ID, len(txs))
+	index := -1
+	for i, tx := range txs {
+		txIDs[i] = tx.ID()
+		if txIDs[i] == txID {
+			index = i
+		}
+	}
+	if index == -1 {
+		return nil, errTxNotInBlock
+	}
+	return newTxInclusionProof(blkID, txIDs, index), nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestTxInclusionProof(t *testing.T) {
+	assert := assert.New(t)
+
+	blkID := ids.GenerateTestID()
+	for numTxs := 1; numTxs <= 9; numTxs++ {
+		txIDs := make([]ids.ID, numTxs)
+		for i := range txIDs {
+			txIDs[i] = ids.GenerateTestID()
+		}
+		root := TxsRoot(txIDs)
+
+		for i, txID := range txIDs {
+			proof := newTxInclusionProof(blkID, txIDs, i)
+			assert.Equal(txID, proof.TxID)
+			assert.Equal(blkID, proof.BlockID)
+			assert.Equal(root, proof.Root)
+			assert.NoError(proof.Verify())
+
+			// The proof only holds for the tx at that index
+			tampered := *proof
+			tampered.TxID = ids.GenerateTestID()
+			assert.ErrorIs(tampered.Verify(), errWrongInclusionProof)
+
+			tampered = *proof
+			tampered.Root = ids.GenerateTestID()
+			assert.ErrorIs(tampered.Verify(), errWrongInclusionProof)
+
+			tampered = *proof
+			tampered.Index = tampered.NumTxs
+			assert.ErrorIs(tampered.Verify(), errInvalidProofIndex)
+
+			if numTxs > 1 {
+				tampered = *proof
+				tampered.Siblings = tampered.Siblings[1:]
+				assert.Error(tampered.Verify())
+			}
+
+			tampered = *proof
+			tampered.Siblings = append(tampered.Siblings, ids.GenerateTestID())
+			assert.ErrorIs(tampered.Verify(), errWrongNumSiblings)
+		}
+	}
+}
+
+func TestTxsRoot(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(ids.Empty, TxsRoot(nil))
+
+	txID0 := ids.GenerateTestID()
+	txID1 := ids.GenerateTestID()
+	txID2 := ids.GenerateTestID()
+	assert.Equal(txID0, TxsRoot([]ids.ID{txID0}))
+	assert.Equal(hashTxNodes(txID0, txID1), TxsRoot([]ids.ID{txID0, txID1}))
+
+	// The last node of an odd level is moved up unchanged
+	assert.Equal(
+		hashTxNodes(hashTxNodes(txID0, txID1), txID2),
+		TxsRoot([]ids.ID{txID0, txID1, txID2}),
+	)
+
+	// The root depends on the order of the txs
+	assert.NotEqual(TxsRoot([]ids.ID{txID0, txID1}), TxsRoot([]ids.ID{txID1, txID0}))
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	// txServiceEndpoint is the extension of the endpoint that serves the
+	// TxService
+	txServiceEndpoint = "/txs"
+
+	defaultAwaitTxStatusTimeout = 30 * time.Second
+	maxAwaitTxStatusTimeout     = time.Minute
+)
+
+// TxService allows clients to follow the lifecycle of their txs.
+//
+// Unlike Service, TxService is served without holding the context lock, so
+// that requests can wait for a tx without blocking the chain. Each method
+// acquires the lock while it reads the state of the chain.
+type TxService struct{ vm *VM }
+
+// AwaitTxStatusArgs are the arguments for AwaitTxStatus
+type AwaitTxStatusArgs struct {
+	// If [Tx] is provided, it's issued and its ID is awaited instead of
+	// [TxID].
+	api.FormattedTx
+	TxID ids.ID `json:"txID"`
+	// Stage of the tx that the client last observed. The response is sent
+	// once the tx reaches a different stage.
+	Stage TxStage `json:"stage"`
+	// Maximum number of milliseconds to wait for the stage to change
+	Timeout json.Uint64 `json:"timeout"`
+	// If true, a proof of the tx's inclusion in its block is returned once
+	// the tx is accepted
+	IncludeProof bool `json:"includeProof"`
+}
+
+// AwaitTxStatusReply is the response from AwaitTxStatus
+type AwaitTxStatusReply struct {
+	TxID  ids.ID  `json:"txID"`
+	Stage TxStage `json:"stage"`
+	// Block the tx is in. Empty if the tx isn't in a block, or if the tx was
+	// decided before the node last started.
+	BlockID ids.ID `json:"blockID"`
+	// Reason the tx was rejected. Only non-empty if [Stage] is rejected
+	Reason string `json:"reason,omitempty"`
+	// Proof of the tx's inclusion in [BlockID]. Only provided if requested
+	// and [Stage] is accepted.
+	Proof *TxInclusionProof `json:"proof,omitempty"`
+}
+
+// AwaitTxStatus returns the stage of a tx once it differs from the stage
+// provided in the arguments, or once the timeout expires. A client subscribes
+// to the lifecycle of a tx by calling AwaitTxStatus with the last stage it
+// received until the tx is accepted or rejected.
+func (service *TxService) AwaitTxStatus(r *http.Request, args *AwaitTxStatusArgs, reply *AwaitTxStatusReply) error {
+	vm := service.vm
+	vm.ctx.Log.Debug("Platform: AwaitTxStatus called with txID %s and stage %s", args.TxID, args.Stage)
+
+	timeout := defaultAwaitTxStatusTimeout
+	if args.Timeout != 0 {
+		timeout = time.Duration(args.Timeout) * time.Millisecond
+	}
+	if timeout > maxAwaitTxStatusTimeout {
+		timeout = maxAwaitTxStatusTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	txID := args.TxID
+	if args.Tx != "" {
+		response := api.JSONTxID{}
+		vm.ctx.Lock.Lock()
+		err := (&Service{vm: vm}).IssueTx(r, &args.FormattedTx, &response)
+		vm.ctx.Lock.Unlock()
+		if err != nil {
+			return err
+		}
+		txID = response.TxID
+	}
+
+	for {
+		// Start waiting before the stage is read so that a change made after
+		// the read isn't missed.
+		changed := vm.txNotifier.wait()
+
+		vm.ctx.Lock.Lock()
+		err := service.getTxStatus(txID, args.IncludeProof, reply)
+		vm.ctx.Lock.Unlock()
+		if err != nil {
+			return err
+		}
+		if reply.Stage != args.Stage {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return nil
+		case <-r.Context().Done():
+			return r.Context().Err()
+		}
+	}
+}
+
+// getTxStatus populates [reply] with the current stage of [txID].
+//
+// Invariant: the context lock must be held.
+func (service *TxService) getTxStatus(txID ids.ID, includeProof bool, reply *AwaitTxStatusReply) error {
+	stage, err := service.vm.getTxStage(txID)
+	if err != nil {
+		return fmt.Errorf("couldn't get stage of tx %s: %w", txID, err)
+	}
+
+	*reply = AwaitTxStatusReply{
+		TxID:    txID,
+		Stage:   stage.stage,
+		BlockID: stage.blkID,
+		Reason:  stage.reason,
+	}
+	if !includeProof || stage.stage != TxStageAccepted || stage.blkID == ids.Empty {
+		return nil
+	}
+	reply.Proof, err = service.vm.getTxInclusionProof(stage.blkID, txID)
+	if err != nil {
+		return fmt.Errorf("couldn't prove inclusion of tx %s in block %s: %w", txID, stage.blkID, err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+func TestAwaitTxStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	defer func() {
+		vm.ctx.Lock.Lock()
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	service := &TxService{vm: vm}
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	vm.ctx.Lock.Lock()
+	tx, err := vm.newCreateSubnetTx(
+		1,
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(),
+	)
+	vm.ctx.Lock.Unlock()
+	assert.NoError(err)
+	txID := tx.ID()
+
+	// The current stage is returned if it differs from the provided stage
+	reply := AwaitTxStatusReply{}
+	assert.NoError(service.AwaitTxStatus(request, &AwaitTxStatusArgs{TxID: txID}, &reply))
+	assert.Equal(txID, reply.TxID)
+	assert.Equal(TxStageUnknown, reply.Stage)
+
+	// The current stage is returned once the timeout expires
+	reply = AwaitTxStatusReply{}
+	assert.NoError(service.AwaitTxStatus(request, &AwaitTxStatusArgs{
+		TxID:    txID,
+		Stage:   TxStageUnknown,
+		Timeout: 1,
+	}, &reply))
+	assert.Equal(TxStageUnknown, reply.Stage)
+
+	// The tx is issued if it's provided
+	txStr, err := formatting.EncodeWithChecksum(formatting.Hex, tx.Bytes())
+	assert.NoError(err)
+	issueArgs := &AwaitTxStatusArgs{
+		FormattedTx: api.FormattedTx{
+			Tx:       txStr,
+			Encoding: formatting.Hex,
+		},
+		Stage: TxStageUnknown,
+	}
+	reply = AwaitTxStatusReply{}
+	assert.NoError(service.AwaitTxStatus(request, issueArgs, &reply))
+	assert.Equal(txID, reply.TxID)
+	assert.Equal(TxStagePending, reply.Stage)
+
+	// await calls AwaitTxStatus in the background, so that the chain can be
+	// changed while the call is waiting
+	await := func(stage TxStage) <-chan AwaitTxStatusReply {
+		replies := make(chan AwaitTxStatusReply, 1)
+		go func() {
+			reply := AwaitTxStatusReply{}
+			assert.NoError(service.AwaitTxStatus(request, &AwaitTxStatusArgs{
+				TxID:         txID,
+				Stage:        stage,
+				IncludeProof: true,
+			}, &reply))
+			replies <- reply
+		}()
+		return replies
+	}
+
+	replies := await(TxStagePending)
+	vm.ctx.Lock.Lock()
+	blk, err := vm.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(blk.Verify())
+	assert.NoError(vm.SetPreference(blk.ID()))
+	vm.ctx.Lock.Unlock()
+
+	reply = <-replies
+	assert.Equal(TxStageInBlock, reply.Stage)
+	assert.Equal(blk.ID(), reply.BlockID)
+	assert.Nil(reply.Proof)
+
+	replies = await(TxStageInBlock)
+	vm.ctx.Lock.Lock()
+	assert.NoError(blk.Accept())
+	vm.ctx.Lock.Unlock()
+
+	reply = <-replies
+	assert.Equal(TxStageAccepted, reply.Stage)
+	assert.Equal(blk.ID(), reply.BlockID)
+	assert.Empty(reply.Reason)
+	if assert.NotNil(reply.Proof) {
+		assert.NoError(reply.Proof.Verify())
+		assert.Equal(txID, reply.Proof.TxID)
+		assert.Equal(blk.ID(), reply.Proof.BlockID)
+
+		txs := blockTxs(blk.(Block))
+		txIDs := make([]ids.ID, len(txs))
+		for i, tx := range txs {
+			txIDs[i] = tx.ID()
+		}
+		assert.Equal(TxsRoot(txIDs), reply.Proof.Root)
+	}
+}
+
+func TestAwaitTxStatusDropped(t *testing.T) {
+	assert := assert.New(t)
+
+	vm, _, _ := defaultVM()
+	defer func() {
+		vm.ctx.Lock.Lock()
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+	service := &TxService{vm: vm}
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	txID := ids.GenerateTestID()
+	replies := make(chan AwaitTxStatusReply, 1)
+	go func() {
+		reply := AwaitTxStatusReply{}
+		assert.NoError(service.AwaitTxStatus(request, &AwaitTxStatusArgs{
+			TxID:  txID,
+			Stage: TxStageUnknown,
+		}, &reply))
+		replies <- reply
+	}()
+
+	vm.ctx.Lock.Lock()
+	vm.markDropped(txID, "invalid tx")
+	vm.ctx.Lock.Unlock()
+
+	reply := <-replies
+	assert.Equal(TxStageRejected, reply.Stage)
+	assert.Equal("invalid tx", reply.Reason)
+	assert.Equal(ids.Empty, reply.BlockID)
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+)
+
+const (
+	acceptedTxBlocksCacheSize = 2048
+
+	abortedProposalReason = "proposal was aborted"
+)
+
+// TxStage is a step in the lifecycle of a tx
+type TxStage string
+
+const (
+	// TxStageUnknown means the tx isn't being tracked by the node
+	TxStageUnknown TxStage = "unknown"
+	// TxStagePending means the tx is in the mempool
+	TxStagePending TxStage = "pending"
+	// TxStageInBlock means the tx is in a processing block of the preferred
+	// chain
+	TxStageInBlock TxStage = "inBlock"
+	// TxStageAccepted means the tx was committed
+	TxStageAccepted TxStage = "accepted"
+	// TxStageRejected means the tx was aborted or recently dropped
+	TxStageRejected TxStage = "rejected"
+)
+
+// txNotifier wakes up the callers waiting for the stage of a tx to change
+type txNotifier struct {
+	lock    sync.Mutex
+	changed chan struct{}
+}
+
+// wait returns a channel that is closed on the next call to notify
+func (n *txNotifier) wait() <-chan struct{} {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.changed == nil {
+		n.changed = make(chan struct{})
+	}
+	return n.changed
+}
+
+// notify reports that the stage of a tx may have changed
+func (n *txNotifier) notify() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.changed != nil {
+		close(n.changed)
+		n.changed = nil
+	}
+}
+
+type txStage struct {
+	stage TxStage
+	// block the tx is in. Only set if the tx is in a block.
+	blkID ids.ID
+	// reason the tx was rejected. Only set if the tx was rejected.
+	reason string
+}
+
+// getTxStage returns the current stage of [txID].
+//
+// Invariant: the context lock must be held.
+func (vm *VM) getTxStage(txID ids.ID) (txStage, error) {
+	_, txStatus, err := vm.internalState.GetTx(txID)
+	if err == nil {
+		// The block is only known if the tx was accepted recently
+		var blkID ids.ID
+		if blkIDIntf, ok := vm.acceptedTxBlocks.Get(txID); ok {
+			blkID, _ = blkIDIntf.(ids.ID)
+		}
+		switch txStatus {
+		case status.Committed:
+			return txStage{stage: TxStageAccepted, blkID: blkID}, nil
+		case status.Aborted:
+			return txStage{stage: TxStageRejected, blkID: blkID, reason: abortedProposalReason}, nil
+		default:
+			return txStage{}, fmt.Errorf("unexpected status %s of tx %s", txStatus, txID)
+		}
+	}
+	if err != database.ErrNotFound {
+		return txStage{}, err
+	}
+
+	blkID, inBlock, err := vm.processingBlockOf(txID)
+	if err != nil {
+		return txStage{}, err
+	}
+	if inBlock {
+		return txStage{stage: TxStageInBlock, blkID: blkID}, nil
+	}
+
+	if vm.blockBuilder.Has(txID) {
+		return txStage{stage: TxStagePending}, nil
+	}
+
+	reason, ok := vm.droppedTxCache.Get(txID)
+	if !ok {
+		return txStage{stage: TxStageUnknown}, nil
+	}
+	reasonStr, ok := reason.(string)
+	if !ok {
+		return txStage{}, errCorruptedReason
+	}
+	return txStage{stage: TxStageRejected, reason: reasonStr}, nil
+}
+
+// processingBlockOf returns the ID of the block of the preferred chain that
+// [txID] is in, if [txID] is in a block that isn't decided.
+func (vm *VM) processingBlockOf(txID ids.ID) (ids.ID, bool, error) {
+	blk, err := vm.Preferred()
+	if err != nil {
+		return ids.ID{}, false, err
+	}
+	for {
+		// An accepted proposal block is still processing until one of its
+		// options is accepted.
+		_, isProposal := blk.(*ProposalBlock)
+		accepted := blk.Status() == choices.Accepted
+		if accepted && !isProposal {
+			return ids.ID{}, false, nil
+		}
+		for _, tx := range blockTxs(blk) {
+			if tx.ID() == txID {
+				return blk.ID(), true, nil
+			}
+		}
+		if accepted {
+			return ids.ID{}, false, nil
+		}
+		blk, err = blk.parentBlock()
+		if err != nil {
+			return ids.ID{}, false, err
+		}
+	}
+}
+
+// markDropped records that [txID] was dropped because of [reason]
+func (vm *VM) markDropped(txID ids.ID, reason string) {
+	vm.droppedTxCache.Put(txID, reason)
+	vm.txNotifier.notify()
+}
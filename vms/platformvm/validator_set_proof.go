@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+)
+
+const validatorSetTriesCacheSize = 16
+
+var (
+	errWrongProofKey            = errors.New("proof is for a different node ID")
+	errWrongProofWeight         = errors.New("proof is for a different weight")
+	errNoValidatorSetRoot       = errors.New("no validator set root was committed")
+	errValidatorSetRootMismatch = errors.New("validator set doesn't match its committed root")
+)
+
+// newValidatorSetTrie returns a Merkle trie that maps the node ID of each
+// validator in [vdrSet] to its big endian encoded weight
+func newValidatorSetTrie(vdrSet map[ids.ShortID]uint64) (merkledb.Trie, error) {
+	trie, err := merkledb.New(memdb.New())
+	if err != nil {
+		return nil, err
+	}
+	for nodeID, weight := range vdrSet {
+		if err := trie.Put(nodeID[:], database.PackUInt64(weight)); err != nil {
+			return nil, err
+		}
+	}
+	return trie, nil
+}
+
+// validatorSetRoot returns the Merkle root of the trie of [vdrs]
+func validatorSetRoot(vdrs validators.Set) (ids.ID, error) {
+	vdrList := vdrs.List()
+	vdrSet := make(map[ids.ShortID]uint64, len(vdrList))
+	for _, vdr := range vdrList {
+		vdrSet[vdr.ID()] = vdr.Weight()
+	}
+	trie, err := newValidatorSetTrie(vdrSet)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return trie.GetMerkleRoot(), nil
+}
+
+// commitValidatorSetRoots commits the roots of the validator sets that this
+// node tracks at the last accepted height, if they weren't committed before.
+// Afterwards, the roots are committed whenever the validator sets change.
+//
+// The roots are only committed to this node's database. They aren't part of
+// P-chain blocks, so consensus doesn't attest to them: honest nodes compute
+// the same roots, but a node can report any root along with a proof against
+// it.
+func (vm *VM) commitValidatorSetRoots() error {
+	height, err := vm.GetCurrentHeight()
+	if err != nil {
+		return err
+	}
+
+	subnetIDs := make([]ids.ID, 0, vm.WhitelistedSubnets.Len()+1)
+	subnetIDs = append(subnetIDs, constants.PrimaryNetworkID)
+	subnetIDs = append(subnetIDs, vm.WhitelistedSubnets.List()...)
+	for _, subnetID := range subnetIDs {
+		_, err := vm.internalState.GetValidatorSetRoot(height, subnetID)
+		if err == nil {
+			continue
+		}
+		if err != database.ErrNotFound {
+			return err
+		}
+
+		vdrs, ok := vm.Validators.GetValidators(subnetID)
+		if !ok {
+			continue
+		}
+		root, err := validatorSetRoot(vdrs)
+		if err != nil {
+			return err
+		}
+		vm.internalState.AddValidatorSetRoot(height, subnetID, root)
+	}
+	return vm.internalState.Commit()
+}
+
+// getValidatorSetTrie returns the Merkle trie of the validator set of
+// [subnetID] at [height], whose committed root is [root]. Tries are cached by
+// their roots, so that the trie of a validator set is shared by every height
+// until the validator set changes.
+func (vm *VM) getValidatorSetTrie(height uint64, subnetID ids.ID, root ids.ID) (merkledb.Trie, error) {
+	if trieIntf, ok := vm.validatorSetTries.Get(root); ok {
+		trie, ok := trieIntf.(merkledb.Trie)
+		if !ok {
+			return nil, errWrongCacheType
+		}
+		return trie, nil
+	}
+
+	vdrSet, err := vm.GetValidatorSet(height, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	trie, err := newValidatorSetTrie(vdrSet)
+	if err != nil {
+		return nil, err
+	}
+	if trieRoot := trie.GetMerkleRoot(); trieRoot != root {
+		return nil, fmt.Errorf("%w: expected %s but got %s", errValidatorSetRootMismatch, root, trieRoot)
+	}
+	vm.validatorSetTries.Put(root, trie)
+	return trie, nil
+}
+
+// GetValidatorSetProof returns the Merkle root of the validator set of
+// [subnetID] at [height] that was committed when the validator set changed,
+// the weight of [nodeID] in it and a proof of that weight against the root.
+// The proof is only as trustworthy as the root, which this node computed
+// itself. vm.ctx.Lock should be held.
+func (vm *VM) GetValidatorSetProof(height uint64, subnetID ids.ID, nodeID ids.ShortID) (ids.ID, uint64, *merkledb.Proof, error) {
+	root, err := vm.internalState.GetValidatorSetRoot(height, subnetID)
+	if err == database.ErrNotFound {
+		return ids.Empty, 0, nil, fmt.Errorf("%w for subnet %s at height %d", errNoValidatorSetRoot, subnetID, height)
+	}
+	if err != nil {
+		return ids.Empty, 0, nil, err
+	}
+	trie, err := vm.getValidatorSetTrie(height, subnetID, root)
+	if err != nil {
+		return ids.Empty, 0, nil, err
+	}
+	proof, err := trie.GetProof(nodeID[:])
+	if err != nil {
+		return ids.Empty, 0, nil, err
+	}
+	var weight uint64
+	if proof.HasValue {
+		weight, err = database.ParseUInt64(proof.Value)
+		if err != nil {
+			return ids.Empty, 0, nil, err
+		}
+	}
+	return root, weight, proof, nil
+}
+
+// VerifyValidatorSetProof returns nil iff [proof] proves that [nodeID] had
+// [weight] in the validator set with Merkle root [root]. A weight of 0 proves
+// that [nodeID] wasn't a validator. It doesn't prove that [root] is the root
+// of the validator set, which callers must get from nodes they trust.
+func VerifyValidatorSetProof(root ids.ID, nodeID ids.ShortID, weight uint64, proof *merkledb.Proof) error {
+	if !bytes.Equal(proof.Key, nodeID[:]) {
+		return errWrongProofKey
+	}
+	if isValidator := weight != 0; proof.HasValue != isValidator ||
+		(isValidator && !bytes.Equal(proof.Value, database.PackUInt64(weight))) {
+		return errWrongProofWeight
+	}
+	return proof.Verify(root)
+}
@@ -4,20 +4,21 @@
 package platformvm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
-	"github.com/gorilla/rpc/v2"
-
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/avalanchego/api/doc"
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -58,17 +59,20 @@ const (
 )
 
 var (
+	importIndexPrefix = []byte("importIndex")
+
 	errInvalidID         = errors.New("invalid ID")
 	errDSCantValidate    = errors.New("new blockchain can't be validated by primary network")
 	errStartTimeTooEarly = errors.New("start time is before the current chain time")
 	errStartAfterEndTime = errors.New("start time is after the end time")
 	errWrongCacheType    = errors.New("unexpectedly cached type")
 
-	_ block.ChainVM        = &VM{}
-	_ validators.Connector = &VM{}
-	_ secp256k1fx.VM       = &VM{}
-	_ validators.State     = &VM{}
-	_ Fx                   = &secp256k1fx.Fx{}
+	_ block.ChainVM          = &VM{}
+	_ block.AbortableChainVM = &VM{}
+	_ validators.Connector   = &VM{}
+	_ secp256k1fx.VM         = &VM{}
+	_ validators.State       = &VM{}
+	_ Fx                     = &secp256k1fx.Fx{}
 )
 
 type VM struct {
@@ -96,6 +100,9 @@ type VM struct {
 
 	internalState InternalState
 
+	// Records which import txs consumed the outputs exported from other chains
+	importIndex avax.ImportIndex
+
 	// ID of the preferred block
 	preferred ids.ID
 
@@ -116,10 +123,21 @@ type VM struct {
 	// Value: String repr. of the verification error
 	droppedTxCache cache.LRU
 
+	// Contains the blocks that recently accepted txs were accepted in, so
+	// proofs of their inclusion can be served.
+	// Key: Tx ID
+	// Value: Block ID
+	acceptedTxBlocks cache.LRU
+
+	// Notified when the stage of a tx may have changed
+	txNotifier txNotifier
+
 	// Maps caches for each subnet that is currently whitelisted.
 	// Key: Subnet ID
 	// Value: cache mapping height -> validator set map
 	validatorSetCaches map[ids.ID]cache.Cacher
+	// Subnet ID prefixed by height --> Merkle trie of the validator set
+	validatorSetTries cache.LRU
 
 	// Key: block ID
 	// Value: the block
@@ -170,7 +188,9 @@ func (vm *VM) Initialize(
 	}
 
 	vm.droppedTxCache = cache.LRU{Size: droppedTxCacheSize}
+	vm.acceptedTxBlocks = cache.LRU{Size: acceptedTxBlocksCacheSize}
 	vm.validatorSetCaches = make(map[ids.ID]cache.Cacher)
+	vm.validatorSetTries = cache.LRU{Size: validatorSetTriesCacheSize}
 	vm.currentBlocks = make(map[ids.ID]Block)
 
 	if err := vm.blockBuilder.Initialize(vm, toEngine, registerer); err != nil {
@@ -187,6 +207,7 @@ func (vm *VM) Initialize(
 		return err
 	}
 	vm.internalState = is
+	vm.importIndex = avax.NewImportIndex(prefixdb.New(importIndexPrefix, vm.dbManager.Current().Database))
 
 	// Initialize the utility to track validator uptimes
 	vm.uptimeManager = uptime.NewManager(is)
@@ -217,6 +238,13 @@ func (vm *VM) Initialize(
 	vm.lastAcceptedID = is.GetLastAccepted()
 	ctx.Log.Info("initializing last accepted block as %s", vm.lastAcceptedID)
 
+	if err := vm.commitValidatorSetRoots(); err != nil {
+		return fmt.Errorf(
+			"failed to commit validator set roots: %w",
+			err,
+		)
+	}
+
 	// Build off the most recently accepted block
 	return vm.SetPreference(vm.lastAcceptedID)
 }
@@ -284,6 +312,24 @@ func (vm *VM) createChain(tx *Tx) error {
 	for _, fxID := range unsignedTx.FxIDs {
 		chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
 	}
+	feeConfigTx, err := vm.internalState.GetSubnetFeeConfig(unsignedTx.SubnetID)
+	switch err {
+	case nil:
+		feeConfig := feeConfigTx.UnsignedTx.(*UnsignedSetSubnetFeeConfigTx)
+		chainParams.FeeAssetID = feeConfig.FeeAssetID
+		chainParams.FeeCollector = feeConfig.FeeCollector
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+	vmConfigTx, err := vm.internalState.GetSubnetVMConfig(unsignedTx.SubnetID)
+	switch err {
+	case nil:
+		chainParams.SubnetVMConfig = vmConfigTx.UnsignedTx.(*UnsignedSetSubnetVMConfigTx).Config
+	case database.ErrNotFound:
+	default:
+		return err
+	}
 	vm.Chains.CreateChain(chainParams)
 	return nil
 }
@@ -340,6 +386,9 @@ func (vm *VM) Shutdown() error {
 	}
 
 	vm.blockBuilder.Shutdown()
+	if vm.network != nil {
+		vm.network.Shutdown()
+	}
 
 	if vm.bootstrapped.GetValue() {
 		primaryValidatorSet, exist := vm.Validators.GetValidators(constants.PrimaryNetworkID)
@@ -372,6 +421,12 @@ func (vm *VM) Shutdown() error {
 // BuildBlock builds a block to be added to consensus
 func (vm *VM) BuildBlock() (snowman.Block, error) { return vm.blockBuilder.BuildBlock() }
 
+// BuildBlockWithContext builds a block to be added to consensus, unless [ctx]
+// is done first
+func (vm *VM) BuildBlockWithContext(ctx context.Context) (snowman.Block, error) {
+	return vm.blockBuilder.BuildBlockWithContext(ctx)
+}
+
 func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
 	var blk Block
 	if _, err := Codec.Unmarshal(b, &blk); err != nil {
@@ -413,6 +468,7 @@ func (vm *VM) SetPreference(blkID ids.ID) error {
 	}
 	vm.preferred = blkID
 	vm.blockBuilder.ResetTimer()
+	vm.txNotifier.notify()
 	return nil
 }
 
@@ -428,7 +484,7 @@ func (vm *VM) Version() (string, error) {
 // * keys are API endpoint extensions
 // * values are API handlers
 func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
-	server := rpc.NewServer()
+	server := doc.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
 	server.RegisterInterceptFunc(vm.metrics.apiRequestMetrics.InterceptRequest)
@@ -437,10 +493,23 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		return nil, err
 	}
 
+	txServer := doc.NewServer()
+	txServer.RegisterCodec(json.NewCodec(), "application/json")
+	txServer.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
+	txServer.RegisterInterceptFunc(vm.metrics.apiRequestMetrics.InterceptRequest)
+	txServer.RegisterAfterFunc(vm.metrics.apiRequestMetrics.AfterRequest)
+	if err := txServer.RegisterService(&TxService{vm: vm}, "platform"); err != nil {
+		return nil, err
+	}
+
 	return map[string]*common.HTTPHandler{
 		"": {
 			Handler: server,
 		},
+		txServiceEndpoint: {
+			LockOptions: common.NoLock,
+			Handler:     txServer,
+		},
 	}, nil
 }
 
@@ -448,7 +517,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 // * keys are API endpoint extensions
 // * values are API handlers
 func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
-	server := rpc.NewServer()
+	server := doc.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
 	if err := server.RegisterService(&StaticService{}, "platform"); err != nil {
@@ -464,10 +533,12 @@ func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
 }
 
 func (vm *VM) Connected(vdrID ids.ShortID, _ version.Application) error {
+	vm.network.connected(vdrID)
 	return vm.uptimeManager.Connect(vdrID)
 }
 
 func (vm *VM) Disconnected(vdrID ids.ShortID) error {
+	vm.network.disconnected(vdrID)
 	if err := vm.uptimeManager.Disconnect(vdrID); err != nil {
 		return err
 	}
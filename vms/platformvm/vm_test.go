@@ -14,6 +14,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database"
@@ -29,6 +30,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common/tracker"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/drops"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
@@ -1061,6 +1063,29 @@ func TestRewardValidatorAccept(t *testing.T) {
 	if _, err := currentStakers.GetValidator(keys[1].PublicKey().Address()); err == nil {
 		t.Fatal("should have removed a genesis validator")
 	}
+
+	// The root of the validator set is committed once the validator set changes
+	genesisRoot, err := vm.internalState.GetValidatorSetRoot(0, constants.PrimaryNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := vm.internalState.GetValidatorSetRoot(commit.Height(), constants.PrimaryNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root == genesisRoot {
+		t.Fatal("should have committed the root of the new validator set")
+	}
+	provenRoot, weight, _, err := vm.GetValidatorSetProof(commit.Height(), constants.PrimaryNetworkID, keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provenRoot != root {
+		t.Fatal("should have proven against the committed root")
+	}
+	if weight != 0 {
+		t.Fatal("should have proven that the genesis validator was removed")
+	}
 }
 
 // Test case where primary network validator not rewarded
@@ -1630,6 +1655,21 @@ func TestAtomicImport(t *testing.T) {
 	if _, err := vm.ctx.SharedMemory.Get(vm.ctx.XChainID, [][]byte{inputID[:]}); err == nil {
 		t.Fatalf("shouldn't have been able to read the utxo")
 	}
+
+	// The import of the exported output is reported by the API
+	service := &Service{vm: vm}
+	reply := api.GetImportStatusReply{}
+	if err := service.GetImportStatus(nil, &api.GetImportStatusArgs{
+		SourceChain: "X",
+		ExportTxID:  utxoID.TxID,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, reply.Imported)
+	assert.Equal(t, []api.ImportedOutput{{
+		OutputIndex: 1,
+		ImportTxID:  tx.ID(),
+	}}, reply.Imports)
 }
 
 // test optimistic asset import
@@ -2031,7 +2071,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 	metrics := prometheus.NewRegistry()
 	mc, err := message.NewCreator(metrics, true, "dummyNamespace", 10*time.Second)
 	assert.NoError(t, err)
-	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, timeoutManager, time.Second, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, mc, timeoutManager, time.Second, ids.Set{}, nil, router.HealthConfig{}, drops.NewNoOpRecorder(), "", prometheus.NewRegistry())
 	assert.NoError(t, err)
 
 	externalSender := &sender.ExternalSenderTest{TB: t}
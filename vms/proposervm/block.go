@@ -5,11 +5,14 @@ package proposervm
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/proposervm/block"
 	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
 )
@@ -17,21 +20,39 @@ import (
 const (
 	// allowable block issuance in the future
 	maxSkew = 10 * time.Second
+
+	// maxBuildDelay is the time after the timestamp of a block this node
+	// builds by which its inner block must be built
+	maxBuildDelay = proposer.WindowDuration
 )
 
 var (
-	errUnsignedChild            = errors.New("expected child to be signed")
-	errUnexpectedBlockType      = errors.New("unexpected proposer block type")
-	errInnerParentMismatch      = errors.New("inner parentID didn't match expected parent")
-	errTimeNotMonotonic         = errors.New("time must monotonically increase")
-	errPChainHeightNotMonotonic = errors.New("non monotonically increasing P-chain height")
-	errPChainHeightNotReached   = errors.New("block P-chain height larger than current P-chain height")
-	errTimeTooAdvanced          = errors.New("time is too far advanced")
-	errProposerWindowNotStarted = errors.New("proposer window hasn't started")
-	errProposersNotActivated    = errors.New("proposers haven't been activated yet")
-	errPChainHeightTooLow       = errors.New("block P-chain height is too low")
+	errUnsignedChild            = wrappers.NewInvalid(errors.New("expected child to be signed"))
+	errUnexpectedBlockType      = wrappers.NewInvalid(errors.New("unexpected proposer block type"))
+	errInnerParentMismatch      = wrappers.NewInvalid(errors.New("inner parentID didn't match expected parent"))
+	errTimeNotMonotonic         = wrappers.NewInvalid(errors.New("time must monotonically increase"))
+	errPChainHeightNotMonotonic = wrappers.NewInvalid(errors.New("non monotonically increasing P-chain height"))
+	errProposerWindowNotStarted = wrappers.NewInvalid(errors.New("proposer window hasn't started"))
+	errProposersNotActivated    = wrappers.NewInvalid(errors.New("proposers haven't been activated yet"))
+	errPChainHeightTooLow       = wrappers.NewInvalid(errors.New("block P-chain height is too low"))
+
+	// These blocks may become valid once this node's view of the P-chain and
+	// its clock catch up.
+	errPChainHeightNotReached = wrappers.NewTransient(errors.New("block P-chain height larger than current P-chain height"))
+	errTimeTooAdvanced        = wrappers.NewTransient(errors.New("time is too far advanced"))
 )
 
+// missingParent wraps the error returned when the parent [parentID] of a block
+// being verified can't be fetched. If the parent isn't known, the engine
+// retries verification later. Other errors, such as database failures, are
+// returned as they are.
+func missingParent(parentID ids.ID, err error) error {
+	if errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("%w %s: %v", snowman.ErrMissingParent, parentID, err)
+	}
+	return fmt.Errorf("couldn't get parent %s: %w", parentID, err)
+}
+
 type Block interface {
 	snowman.Block
 
@@ -110,35 +131,63 @@ func (p *postForkCommonComponents) Verify(parentTimestamp time.Time, parentPChai
 		if err != nil {
 			p.vm.ctx.Log.Error("failed to get current P-Chain height while processing %s: %s",
 				childID, err)
-			return err
+			return wrappers.NewTransient(err)
 		}
 		if childPChainHeight > currentPChainHeight {
 			return errPChainHeightNotReached
 		}
 
-		childHeight := child.Height()
-		proposerID := child.Proposer()
-		minDelay, err := p.vm.Windower.Delay(childHeight, parentPChainHeight, proposerID)
+		// The proposer window and the signature only depend on the bytes of
+		// the child and its parent, so they don't need to be checked again if
+		// the child was verified before a restart or passed VerifyChain.
+		previouslyVerified, err := p.vm.State.IsVerified(childID)
 		if err != nil {
 			return err
 		}
-
-		delay := childTimestamp.Sub(parentTimestamp)
-		if delay < minDelay {
-			return errProposerWindowNotStarted
+		if _, ok := p.vm.chainVerified.Get(childID); ok {
+			p.vm.chainVerified.Evict(childID)
+			previouslyVerified = true
 		}
-
-		// Verify the signature of the node
-		shouldHaveProposer := delay < proposer.MaxDelay
-		if err := child.SignedBlock.Verify(shouldHaveProposer, p.vm.ctx.ChainID); err != nil {
-			return err
+		if previouslyVerified {
+			p.vm.metrics.memoizedVerifications.Inc()
+			p.vm.ctx.Log.Debug("skipping proposer verification of post-fork block %s as it was previously verified",
+				childID)
+		} else {
+			childHeight := child.Height()
+			proposerID := child.Proposer()
+			minDelay, err := p.vm.Windower.Delay(childHeight, parentPChainHeight, proposerID)
+			if err != nil {
+				return err
+			}
+
+			delay := childTimestamp.Sub(parentTimestamp)
+			if delay < minDelay {
+				return errProposerWindowNotStarted
+			}
+
+			// Verify the signature of the node
+			shouldHaveProposer := delay < proposer.MaxDelay
+			if err := child.SignedBlock.Verify(shouldHaveProposer, p.vm.ctx.ChainID); err != nil {
+				return err
+			}
+
+			p.vm.ctx.Log.Debug("verified post-fork block %s - parent timestamp %v, expected delay %v, block timestamp %v",
+				childID, parentTimestamp, minDelay, childTimestamp)
 		}
-
-		p.vm.ctx.Log.Debug("verified post-fork block %s - parent timestamp %v, expected delay %v, block timestamp %v",
-			childID, parentTimestamp, minDelay, childTimestamp)
+		p.vm.trackSignedBlock(child)
 	}
 
-	return p.vm.verifyAndRecordInnerBlk(child)
+	// The inner block is verified even if the child was verified before a
+	// restart. The inner VM only keeps the state that results from verifying
+	// a block in memory, and it needs that state to verify the block's
+	// children and to accept it.
+	if err := p.vm.verifyAndRecordInnerBlk(child); err != nil {
+		return err
+	}
+	if p.vm.bootstrapped {
+		p.vm.markVerified(child)
+	}
+	return nil
 }
 
 // Return the child (a *postForkBlock) of this block
@@ -148,7 +197,7 @@ func (p *postForkCommonComponents) buildChild(
 	parentPChainHeight uint64,
 ) (Block, error) {
 	// Child's timestamp is the later of now and this block's timestamp
-	newTimestamp := p.vm.Time().Truncate(time.Second)
+	newTimestamp := p.vm.blockTime().Truncate(time.Second)
 	if newTimestamp.Before(parentTimestamp) {
 		newTimestamp = parentTimestamp
 	}
@@ -183,9 +232,17 @@ func (p *postForkCommonComponents) buildChild(
 			p.vm.notifyInnerBlockReady()
 			return nil, errProposerWindowNotStarted
 		}
+
+		if err := p.vm.verifyNotSigned(parentHeight+1, parentID); err != nil {
+			return nil, err
+		}
 	}
 
-	innerBlock, err := p.vm.ChainVM.BuildBlock()
+	// The timestamp of the child is picked before the inner block is built.
+	// Once the timestamp is a full proposer window old, the next proposer may
+	// already have built a competing block with a fresher timestamp, so the
+	// build is aborted rather than producing a block that is likely to lose.
+	innerBlock, err := p.vm.buildInnerBlock(newTimestamp.Add(maxBuildDelay))
 	if err != nil {
 		return nil, err
 	}
@@ -215,6 +272,12 @@ func (p *postForkCommonComponents) buildChild(
 		if err != nil {
 			return nil, err
 		}
+
+		// The signature is recorded before the block is handed to the engine
+		// to be broadcast
+		if err := p.vm.recordSigned(innerBlock.Height(), statelessChild); err != nil {
+			return nil, err
+		}
 	}
 
 	child := &postForkBlock{
@@ -33,6 +33,10 @@ type SignedBlock interface {
 	PChainHeight() uint64
 	Timestamp() time.Time
 	Proposer() ids.ShortID
+	// Certificate and signature of the proposer, which are empty if the block
+	// has no proposer
+	Certificate() []byte
+	SignatureBytes() []byte
 
 	Verify(shouldHaveProposer bool, chainID ids.ID) error
 }
@@ -54,6 +58,12 @@ type statelessBlock struct {
 	cert      *x509.Certificate
 	proposer  ids.ShortID
 	bytes     []byte
+
+	// The result of checking the signature against [signatureChainID], so
+	// that the signature is only checked once
+	signatureChecked bool
+	signatureChainID ids.ID
+	signatureErr     error
 }
 
 func (b *statelessBlock) ID() ids.ID       { return b.id }
@@ -85,9 +95,11 @@ func (b *statelessBlock) initialize(bytes []byte) error {
 	return nil
 }
 
-func (b *statelessBlock) PChainHeight() uint64  { return b.StatelessBlock.PChainHeight }
-func (b *statelessBlock) Timestamp() time.Time  { return b.timestamp }
-func (b *statelessBlock) Proposer() ids.ShortID { return b.proposer }
+func (b *statelessBlock) PChainHeight() uint64   { return b.StatelessBlock.PChainHeight }
+func (b *statelessBlock) Timestamp() time.Time   { return b.timestamp }
+func (b *statelessBlock) Proposer() ids.ShortID  { return b.proposer }
+func (b *statelessBlock) Certificate() []byte    { return b.StatelessBlock.Certificate }
+func (b *statelessBlock) SignatureBytes() []byte { return b.Signature }
 
 func (b *statelessBlock) Verify(shouldHaveProposer bool, chainID ids.ID) error {
 	if !shouldHaveProposer {
@@ -99,11 +111,18 @@ func (b *statelessBlock) Verify(shouldHaveProposer bool, chainID ids.ID) error {
 		return errMissingProposer
 	}
 
+	if b.signatureChecked && b.signatureChainID == chainID {
+		return b.signatureErr
+	}
+
 	header, err := BuildHeader(chainID, b.StatelessBlock.ParentID, b.id)
 	if err != nil {
 		return err
 	}
 
 	headerBytes := header.Bytes()
-	return b.cert.CheckSignature(b.cert.SignatureAlgorithm, headerBytes, b.Signature)
+	b.signatureErr = b.cert.CheckSignature(b.cert.SignatureAlgorithm, headerBytes, b.Signature)
+	b.signatureChecked = true
+	b.signatureChainID = chainID
+	return b.signatureErr
 }
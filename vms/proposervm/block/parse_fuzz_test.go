@@ -0,0 +1,71 @@
+//go:build go1.18
+// +build go1.18
+
+// ^ Only build this file if native fuzzing is supported
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"bytes"
+	"crypto"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+)
+
+// FuzzParseProposerBlock checks that any bytes received from a peer are either
+// rejected or parsed into a block that encodes back to the same bytes
+func FuzzParseProposerBlock(f *testing.F) {
+	tlsCert, err := staking.NewTLSCert()
+	if err != nil {
+		f.Fatal(err)
+	}
+	parentID := ids.ID{1}
+	timestamp := time.Unix(123, 0)
+	chainID := ids.ID{2}
+
+	signedBlock, err := Build(parentID, timestamp, 3, tlsCert.Leaf, []byte{4}, chainID, tlsCert.PrivateKey.(crypto.Signer))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(signedBlock.Bytes())
+
+	unsignedBlock, err := BuildUnsigned(parentID, timestamp, 3, []byte{4})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(unsignedBlock.Bytes())
+
+	option, err := BuildOption(parentID, []byte{4})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(option.Bytes())
+
+	header, err := BuildHeader(chainID, parentID, ids.ID{5})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(header.Bytes())
+
+	f.Fuzz(func(t *testing.T, blockBytes []byte) {
+		// Headers are parsed from the same untrusted bytes as the blocks
+		_, _ = ParseHeader(blockBytes)
+
+		blk, err := Parse(blockBytes)
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(blk.Bytes(), blockBytes) {
+			t.Fatalf("parsed block encodes to %x instead of %x", blk.Bytes(), blockBytes)
+		}
+		if signed, ok := blk.(SignedBlock); ok {
+			_ = signed.Verify(true, chainID)
+			_ = signed.Verify(false, chainID)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
+)
+
+// chainVerifiedCacheSize is the number of blocks whose proposer checks passed
+// in VerifyChain that are remembered until they're verified
+const chainVerifiedCacheSize = 4096
+
+var (
+	_ block.ChainVerifierVM = &VM{}
+	_ validators.State      = &batchValidatorState{}
+
+	errChainNotLinked = wrappers.NewInvalid(errors.New("block isn't the child of the previous block"))
+)
+
+// VerifyChain verifies the proposer layer of [blks], which are ordered by
+// increasing height with each block being the parent of the next one. Each
+// post-fork block is checked against its parent to:
+// 1) wrap a child of its parent's inner block
+// 2) have a P-Chain height and timestamp that don't decrease
+// 3) have a valid signature from its proposer
+// 4) have a timestamp within its proposer's window, if this node's view of the
+//    P-chain reached the block's P-Chain height
+//
+// The validator set at each P-Chain height is fetched once for the whole batch
+// and the parents are resolved from the batch, which makes verifying long
+// runs of historical blocks much cheaper than verifying them one by one. The
+// first block is only checked if its parent is already known. The inner blocks
+// aren't verified, so Verify must still be called on each block. Verify
+// doesn't repeat the proposer checks of blocks that passed them here.
+//
+// Like Verify, VerifyChain doesn't check proposer windows or signatures while
+// the node is bootstrapping.
+func (vm *VM) VerifyChain(blks []snowman.Block) error {
+	if len(blks) == 0 {
+		return nil
+	}
+
+	var (
+		currentPChainHeight uint64
+		// [windower] is nil if the proposers aren't checked
+		windower proposer.Windower
+	)
+	if vm.bootstrapped {
+		var err error
+		currentPChainHeight, err = vm.ctx.ValidatorState.GetCurrentHeight()
+		if err != nil {
+			return wrappers.NewTransient(err)
+		}
+		windower = proposer.New(
+			&batchValidatorState{
+				State: vm.ctx.ValidatorState,
+				sets:  make(map[uint64]map[ids.ShortID]uint64),
+			},
+			vm.ctx.SubnetID,
+			vm.ctx.ChainID,
+		)
+	}
+
+	parent, err := vm.getBlock(blks[0].Parent())
+	if err != nil {
+		// The parent may not have been fetched yet
+		parent = nil
+	}
+	// The timestamp and P-Chain height that the child of [parent] is verified
+	// against. The P-Chain height is only known if [parent] is a post-fork
+	// block.
+	var (
+		parentTimestamp    time.Time
+		parentPChainHeight uint64
+		parentIsPostFork   bool
+	)
+	switch parentBlk := parent.(type) {
+	case *postForkBlock:
+		parentTimestamp = parentBlk.Timestamp()
+		parentPChainHeight = parentBlk.PChainHeight()
+		parentIsPostFork = true
+	case *preForkBlock:
+		parentTimestamp = parentBlk.Timestamp()
+	default:
+		// The header of an option's parent isn't resolved, so its child is
+		// only checked once the option is verified
+		parent = nil
+	}
+
+	for i, snowmanBlk := range blks {
+		blk, ok := snowmanBlk.(Block)
+		if !ok {
+			return errUnexpectedBlockType
+		}
+		if i > 0 && blk.Parent() != blks[i-1].ID() {
+			return fmt.Errorf("%w: %s", errChainNotLinked, blk.ID())
+		}
+
+		switch blk := blk.(type) {
+		case *postForkBlock:
+			if parent != nil {
+				if err := vm.verifyChainLink(
+					windower,
+					currentPChainHeight,
+					parent,
+					parentTimestamp,
+					parentPChainHeight,
+					parentIsPostFork,
+					blk,
+				); err != nil {
+					return fmt.Errorf("couldn't verify %s: %w", blk.ID(), err)
+				}
+			}
+			parentTimestamp = blk.Timestamp()
+			parentPChainHeight = blk.PChainHeight()
+			parentIsPostFork = true
+		case *postForkOption:
+			// An option has the timestamp and P-Chain height of its parent
+			if parent != nil && blk.innerBlk.Parent() != parent.getInnerBlk().ID() {
+				return fmt.Errorf("couldn't verify %s: %w", blk.ID(), errInnerParentMismatch)
+			}
+		default:
+			parentTimestamp = blk.Timestamp()
+			parentIsPostFork = false
+		}
+		parent = blk
+	}
+	return nil
+}
+
+// verifyChainLink verifies the proposer layer of [child] against its parent,
+// as described in VerifyChain. The proposer of [child] is only checked if
+// [windower] isn't nil.
+func (vm *VM) verifyChainLink(
+	windower proposer.Windower,
+	currentPChainHeight uint64,
+	parent Block,
+	parentTimestamp time.Time,
+	parentPChainHeight uint64,
+	parentIsPostFork bool,
+	child *postForkBlock,
+) error {
+	if child.innerBlk.Parent() != parent.getInnerBlk().ID() {
+		return errInnerParentMismatch
+	}
+	childTimestamp := child.Timestamp()
+	if childTimestamp.Before(parentTimestamp) {
+		return errTimeNotMonotonic
+	}
+	if !parentIsPostFork {
+		// The first post-fork block is unsigned, and its P-Chain height is
+		// checked against the fork when it's verified
+		return child.SignedBlock.Verify(false, vm.ctx.ChainID)
+	}
+
+	childPChainHeight := child.PChainHeight()
+	if childPChainHeight < parentPChainHeight {
+		return errPChainHeightNotMonotonic
+	}
+	if windower == nil {
+		return nil
+	}
+
+	delay := childTimestamp.Sub(parentTimestamp)
+	shouldHaveProposer := delay < proposer.MaxDelay
+	if childPChainHeight > currentPChainHeight {
+		// The proposer window is left for Verify to check
+		return child.SignedBlock.Verify(shouldHaveProposer, vm.ctx.ChainID)
+	}
+
+	minDelay, err := windower.Delay(child.Height(), parentPChainHeight, child.Proposer())
+	if err != nil {
+		return err
+	}
+	if delay < minDelay {
+		return errProposerWindowNotStarted
+	}
+	if err := child.SignedBlock.Verify(shouldHaveProposer, vm.ctx.ChainID); err != nil {
+		return err
+	}
+	vm.chainVerified.Put(child.ID(), nil)
+	return nil
+}
+
+// batchValidatorState fetches the validator set at each P-Chain height only
+// once
+type batchValidatorState struct {
+	validators.State
+
+	// Height --> validator set. Only the subnet of the chain is queried.
+	sets map[uint64]map[ids.ShortID]uint64
+}
+
+func (s *batchValidatorState) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+	if set, ok := s.sets[height]; ok {
+		return set, nil
+	}
+	set, err := s.State.GetValidatorSet(height, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	s.sets[height] = set
+	return set, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+func TestVerifyChain(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, valState, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	proVM.Set(coreGenBlk.Timestamp())
+
+	// This node is the only proposer, so its blocks are signed
+	validatorSetCalls := 0
+	valState.GetValidatorSetF = func(uint64, ids.ID) (map[ids.ShortID]uint64, error) {
+		validatorSetCalls++
+		return map[ids.ShortID]uint64{proVM.ctx.NodeID: 10}, nil
+	}
+
+	coreBlks := make([]*snowman.TestBlock, 3)
+	parent := snowman.Block(coreGenBlk)
+	for i := range coreBlks {
+		coreBlks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i + 1)),
+				StatusV: choices.Processing,
+			},
+			BytesV:     []byte{byte(i + 1)},
+			ParentV:    parent.ID(),
+			HeightV:    parent.Height() + 1,
+			TimestampV: parent.Timestamp(),
+		}
+		parent = coreBlks[i]
+	}
+	coreVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == coreGenBlk.ID() {
+			return coreGenBlk, nil
+		}
+		for _, blk := range coreBlks {
+			if blkID == blk.ID() {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range coreBlks {
+			if bytes.Equal(b, blk.Bytes()) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	coreVM.SetPreferenceF = func(ids.ID) error { return nil }
+
+	proBlks := make([]snowman.Block, len(coreBlks))
+	for i, coreBlk := range coreBlks {
+		coreBlk := coreBlk
+		coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+		proBlk, err := proVM.BuildBlock()
+		assert.NoError(err)
+		assert.NoError(proBlk.Verify())
+		assert.NoError(proVM.SetPreference(proBlk.ID()))
+		proBlks[i] = proBlk
+	}
+
+	// While bootstrapping, proposers aren't checked, as in Verify
+	proVM.bootstrapped = false
+	validatorSetCalls = 0
+	assert.NoError(proVM.VerifyChain(proBlks))
+	assert.Zero(validatorSetCalls)
+	for _, proBlk := range proBlks {
+		_, ok := proVM.chainVerified.Get(proBlk.ID())
+		assert.False(ok)
+	}
+	proVM.bootstrapped = true
+
+	// The validator set is only fetched once for the whole batch
+	assert.NoError(proVM.VerifyChain(proBlks))
+	assert.Equal(1, validatorSetCalls)
+
+	// Verify doesn't repeat the proposer checks that passed
+	nextCoreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(uint64(len(coreBlks) + 1)),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{byte(len(coreBlks) + 1)},
+		ParentV:    parent.ID(),
+		HeightV:    parent.Height() + 1,
+		TimestampV: parent.Timestamp(),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return nextCoreBlk, nil }
+	nextProBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(proVM.VerifyChain([]snowman.Block{proBlks[len(proBlks)-1], nextProBlk}))
+	_, ok := proVM.chainVerified.Get(nextProBlk.ID())
+	assert.True(ok)
+	validatorSetCalls = 0
+	assert.NoError(nextProBlk.Verify())
+	assert.Zero(validatorSetCalls)
+	_, ok = proVM.chainVerified.Get(nextProBlk.ID())
+	assert.False(ok)
+
+	// The blocks must extend each other
+	err = proVM.VerifyChain([]snowman.Block{proBlks[0], proBlks[2]})
+	assert.ErrorIs(err, errChainNotLinked)
+
+	// A block can't be timestamped before its parent
+	parentBlk := proBlks[1].(*postForkBlock)
+	forgedBlk, err := block.Build(
+		parentBlk.ID(),
+		parentBlk.Timestamp().Add(-time.Second),
+		parentBlk.PChainHeight(),
+		proVM.ctx.StakingCertLeaf,
+		coreBlks[2].Bytes(),
+		proVM.ctx.ChainID,
+		proVM.ctx.StakingLeafSigner,
+	)
+	assert.NoError(err)
+	forgedProBlk, err := proVM.ParseBlock(forgedBlk.Bytes())
+	assert.NoError(err)
+	err = proVM.VerifyChain([]snowman.Block{proBlks[0], proBlks[1], forgedProBlk})
+	assert.ErrorIs(err, errTimeNotMonotonic)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/tieredb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/vms/proposervm/state"
+)
+
+// maxOffloadsPerAccept bounds the number of blocks offloaded when a block is
+// accepted, so that enabling cold storage on a node that already has a long
+// chain doesn't stall consensus while its old blocks are uploaded.
+const maxOffloadsPerAccept = 16
+
+// ColdStorageConfig describes when the accepted blocks of the VM are moved
+// from the local database to an object store
+type ColdStorageConfig struct {
+	// Store receives the offloaded blocks. If nil, blocks are never
+	// offloaded.
+	Store tieredb.ObjectStore
+
+	// Depth is the number of accepted blocks kept locally below the last
+	// accepted block
+	Depth uint64
+
+	// CacheSize is the number of offloaded blocks kept in memory once read
+	CacheSize int
+}
+
+// offloadBlocks moves the accepted blocks that are more than [Depth] blocks
+// below [lastAcceptedHeight] to cold storage. The changes are written to
+// [vm.db] and must be committed by the caller.
+func (vm *VM) offloadBlocks(lastAcceptedHeight uint64) error {
+	if vm.coldStorage.Store == nil || lastAcceptedHeight <= vm.coldStorage.Depth {
+		return nil
+	}
+	maxHeight := lastAcceptedHeight - vm.coldStorage.Depth
+
+	height, err := vm.State.GetOffloadedHeight()
+	if err == database.ErrNotFound {
+		// Nothing was offloaded yet, start from the first post-fork block
+		height, err = vm.State.GetForkHeight()
+		if err == database.ErrNotFound {
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	startHeight := height
+	for ; height <= maxHeight && height-startHeight < maxOffloadsPerAccept; height++ {
+		blkID, err := vm.State.GetBlockIDAtHeight(height)
+		if err == database.ErrNotFound {
+			// The height index is being rebuilt. The remaining blocks will be
+			// offloaded once it's complete.
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := vm.State.OffloadBlock(blkID); err != nil {
+			// Failing to reach the object store shouldn't stop the chain. The
+			// block will be offloaded again when the next block is accepted.
+			vm.ctx.Log.Warn("failed to offload block %s at height %d: %s", blkID, height, err)
+			break
+		}
+		vm.metrics.blocksOffloaded.Inc()
+	}
+
+	if height == startHeight {
+		return nil
+	}
+	return vm.State.SetOffloadedHeight(height)
+}
+
+// newState returns the state of the VM stored in [db]
+func (vm *VM) newState(db *versiondb.Database) state.State {
+	if vm.coldStorage.Store == nil {
+		return state.New(db)
+	}
+	return state.NewTiered(db, vm.coldStorage.Store, vm.coldStorage.CacheSize)
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/tieredb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/vms/proposervm/state"
+
+	statelessblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+// unavailableStore fails to upload objects while [unavailable] is true
+type unavailableStore struct {
+	tieredb.ObjectStore
+	unavailable bool
+}
+
+func (s *unavailableStore) Put(name string, value []byte) error {
+	if s.unavailable {
+		return errors.New("unavailable")
+	}
+	return s.ObjectStore.Put(name, value)
+}
+
+func TestOffloadBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0)
+	store := tieredb.NewMemoryStore()
+	unavailable := &unavailableStore{ObjectStore: store}
+	proVM.coldStorage = ColdStorageConfig{
+		Store:     unavailable,
+		Depth:     2,
+		CacheSize: 4,
+	}
+	proVM.State = proVM.newState(proVM.db)
+
+	parentID := coreGenBlk.ID()
+	blkIDs := []ids.ID{parentID}
+	for height := uint64(1); height <= 5; height++ {
+		blk, err := statelessblock.BuildUnsigned(parentID, time.Time{}, 0, []byte{byte(height)})
+		assert.NoError(err)
+		assert.NoError(proVM.State.PutBlock(blk, choices.Accepted))
+		assert.NoError(proVM.State.SetBlockIDAtHeight(height, blk.ID()))
+		parentID = blk.ID()
+		blkIDs = append(blkIDs, parentID)
+	}
+	assert.NoError(proVM.State.SetForkHeight(1))
+
+	// Nothing is offloaded while the object store is unavailable
+	unavailable.unavailable = true
+	assert.NoError(proVM.offloadBlocks(5))
+	assert.Equal(0, store.Len())
+	unavailable.unavailable = false
+
+	// Only the blocks more than 2 blocks below the last accepted block are
+	// offloaded
+	assert.NoError(proVM.offloadBlocks(5))
+	assert.NoError(proVM.db.Commit())
+	assert.Equal(3, store.Len())
+	height, err := proVM.State.GetOffloadedHeight()
+	assert.NoError(err)
+	assert.EqualValues(4, height)
+
+	assert.NoError(proVM.offloadBlocks(5))
+	assert.Equal(3, store.Len())
+
+	// The offloaded blocks are still returned by a new state, which doesn't
+	// have them cached
+	s := state.NewTiered(proVM.db, store, 4)
+	for _, blkID := range blkIDs[1:] {
+		blk, status, err := s.GetBlock(blkID)
+		assert.NoError(err)
+		assert.Equal(blkID, blk.ID())
+		assert.Equal(choices.Accepted, status)
+	}
+}
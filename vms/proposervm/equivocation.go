@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/evidence"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+var (
+	errDuplicateIdentity = errors.New("another node is proposing blocks with this node's staking key")
+	errAlreadySigned     = errors.New("this node already signed a block with the same parent")
+)
+
+// trackSignedBlock records that [blk] was signed by its proposer. An honest
+// proposer never signs two different blocks with the same parent. If the
+// proposer of [blk] previously signed a different block with the same parent,
+// the equivocation is reported.
+//
+// If the equivocating proposer is this node, and this node didn't build both
+// of the blocks, then another node must be running with this node's staking
+// key.
+func (vm *VM) trackSignedBlock(blk *postForkBlock) {
+	proposerID := blk.Proposer()
+	if proposerID == ids.ShortEmpty {
+		return
+	}
+
+	parentID := blk.ParentID()
+	blkID := blk.ID()
+	proposers, ok := vm.signedBlocks[parentID]
+	if !ok {
+		proposers = make(map[ids.ShortID]ids.ID)
+		vm.signedBlocks[parentID] = proposers
+	}
+	previousID, ok := proposers[proposerID]
+	if !ok {
+		proposers[proposerID] = blkID
+		return
+	}
+	if previousID == blkID {
+		return
+	}
+
+	vm.metrics.equivocations.Inc()
+	vm.ctx.Log.Warn("proposer %s%s signed conflicting blocks %s and %s with parent %s",
+		constants.NodeIDPrefix, proposerID, previousID, blkID, parentID)
+	vm.recordConflictingBlocks(proposerID, previousID, blk)
+
+	if proposerID != vm.ctx.NodeID || (vm.builtBlocks.Contains(previousID) && vm.builtBlocks.Contains(blkID)) {
+		return
+	}
+
+	vm.duplicateIdentity = true
+	vm.metrics.duplicateIdentity.Set(1)
+	vm.ctx.Log.Error("detected blocks %s and %s signed with this node's staking key that weren't both built by this node. The staking key may have been copied",
+		previousID, blkID)
+}
+
+// recordConflictingBlocks persists both signed blocks as evidence that
+// [proposerID] equivocated.
+func (vm *VM) recordConflictingBlocks(proposerID ids.ShortID, previousID ids.ID, blk *postForkBlock) {
+	if vm.ctx.Evidence == nil {
+		return
+	}
+	previous, err := vm.getPostForkBlock(previousID)
+	if err != nil {
+		vm.ctx.Log.Debug("couldn't fetch conflicting block %s to record as evidence: %s", previousID, err)
+		return
+	}
+	err = vm.ctx.Evidence.Record(&evidence.Evidence{
+		Type:       evidence.ConflictingBlocks,
+		ChainID:    vm.ctx.ChainID,
+		NodeID:     proposerID,
+		Timestamp:  vm.Time().Unix(),
+		Containers: [][]byte{previous.Bytes(), blk.Bytes()},
+	})
+	if err != nil {
+		vm.ctx.Log.Warn("failed to record evidence of conflicting blocks %s and %s: %s", previousID, blk.ID(), err)
+	}
+}
+
+// pruneSignedBlocks stops tracking signed blocks whose parent is [parentID].
+// This should be called once the children of [parentID] can no longer be
+// issued into consensus.
+func (vm *VM) pruneSignedBlocks(parentID ids.ID) {
+	delete(vm.signedBlocks, parentID)
+}
+
+// verifyNotSigned returns an error if this node already signed a block with
+// [parentID] at [height]. Signing another block would equivocate, which may
+// happen if this node restarted after signing a block that it didn't issue.
+func (vm *VM) verifyNotSigned(height uint64, parentID ids.ID) error {
+	signedID, err := vm.State.GetSigned(height, parentID)
+	switch err {
+	case nil:
+		vm.ctx.Log.Warn("refusing to sign a block with parent %s at height %d, as block %s was already signed",
+			parentID, height, signedID)
+		return errAlreadySigned
+	case database.ErrNotFound:
+		return nil
+	default:
+		return err
+	}
+}
+
+// recordSigned persists that this node signed [blk] at [height], so that no
+// conflicting block is signed later on.
+func (vm *VM) recordSigned(height uint64, blk block.SignedBlock) error {
+	if err := vm.State.PutSigned(height, blk.ParentID(), blk.ID()); err != nil {
+		return err
+	}
+	return vm.db.Commit()
+}
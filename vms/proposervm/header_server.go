@@ -0,0 +1,23 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+// GetPostForkBlockAtHeight returns the accepted post-fork block at [height].
+// The first post-fork block, options and blocks issued outside of the proposer
+// windows are returned even though they weren't signed by a proposer.
+func (vm *VM) GetPostForkBlockAtHeight(height uint64) (block.Block, error) {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	blkID, err := vm.GetBlockIDAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	blk, _, err := vm.State.GetBlock(blkID)
+	return blk, err
+}
@@ -7,10 +7,15 @@ import (
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/migration"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 )
 
+// backfillCommitFrequency is the number of blocks backfilled into the height
+// index between checkpoints
+const backfillCommitFrequency = 1024
+
 // shouldHeightIndexBeRepaired checks if index needs repairing and stores a
 // checkpoint if repairing is needed.
 //
@@ -53,14 +58,13 @@ func (vm *VM) shouldHeightIndexBeRepaired() (bool, error) {
 
 // vm.ctx.Lock should be held
 func (vm *VM) VerifyHeightIndex() error {
+	if vm.hIndexer.IsRepaired() {
+		return nil
+	}
 	if _, ok := vm.ChainVM.(block.HeightIndexedChainVM); !ok {
 		return block.ErrHeightIndexedVMNotImplemented
 	}
-
-	if !vm.hIndexer.IsRepaired() {
-		return block.ErrIndexIncomplete
-	}
-	return nil
+	return block.ErrIndexIncomplete
 }
 
 // vm.ctx.Lock should be held
@@ -69,9 +73,12 @@ func (vm *VM) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
 		return ids.Empty, block.ErrIndexIncomplete
 	}
 
-	// The indexer will only report that the index has been repaired if the
-	// underlying VM supports indexing.
-	innerHVM := vm.ChainVM.(block.HeightIndexedChainVM)
+	innerHVM, ok := vm.ChainVM.(block.HeightIndexedChainVM)
+	if !ok {
+		// The pre-fork blocks of inner VMs that don't index their blocks were
+		// backfilled into the height index
+		return vm.State.GetBlockIDAtHeight(height)
+	}
 	switch forkHeight, err := vm.State.GetForkHeight(); err {
 	case nil:
 		if height < forkHeight {
@@ -135,3 +142,77 @@ func (vm *VM) storeHeightEntry(height uint64, blkID ids.ID) error {
 	vm.ctx.Log.Debug("indexed block %s at height %d", blkID, height)
 	return vm.State.SetBlockIDAtHeight(height, blkID)
 }
+
+// indexPreForkBlock adds the accepted pre-fork block [blkID] to the height
+// index, unless the inner VM indexes its own blocks
+func (vm *VM) indexPreForkBlock(height uint64, blkID ids.ID) error {
+	if _, ok := vm.ChainVM.(block.HeightIndexedChainVM); ok {
+		return nil
+	}
+	if err := vm.State.SetBlockIDAtHeight(height, blkID); err != nil {
+		return err
+	}
+	return vm.db.Commit()
+}
+
+// backfillHeightIndex indexes the accepted chain when the proposervm starts
+// over an inner VM that doesn't index its own blocks. The chain is walked back
+// from the last accepted block to genesis. Pre-fork blocks are indexed under
+// their own ID, which is the ID of the inner block they wrap, and post-fork
+// blocks under the ID of the proposer block.
+//
+// Inner VMs that index their own blocks are left to the height indexer.
+func (vm *VM) backfillHeightIndex(_ database.Database, progress migration.Progress) error {
+	if _, ok := vm.ChainVM.(block.HeightIndexedChainVM); ok {
+		return nil
+	}
+
+	lastAcceptedID, err := vm.LastAccepted()
+	if err != nil {
+		return err
+	}
+	lastAccepted, err := vm.getBlock(lastAcceptedID)
+	if err != nil {
+		return err
+	}
+	total := lastAccepted.Height() + 1
+
+	blkID := lastAcceptedID
+	if marker := progress.Marker(); marker != nil {
+		blkID, err = ids.ToID(marker)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		blk, err := vm.getBlock(blkID)
+		if err != nil {
+			return fmt.Errorf("couldn't get accepted block %s: %w", blkID, err)
+		}
+		height := blk.Height()
+		if err := vm.State.SetBlockIDAtHeight(height, blkID); err != nil {
+			return err
+		}
+		if _, isPreFork := blk.(*preForkBlock); !isPreFork {
+			// The chain is walked backwards, so the fork height ends up being
+			// the height of the first post-fork block
+			if err := vm.State.SetForkHeight(height); err != nil {
+				return err
+			}
+		}
+		if height == 0 {
+			return vm.db.Commit()
+		}
+
+		blkID = blk.Parent()
+		if done := total - height; done%backfillCommitFrequency == 0 {
+			if err := vm.db.Commit(); err != nil {
+				return err
+			}
+			if err := progress.Checkpoint(blkID[:], done, total); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+)
+
+var errInvariantViolated = errors.New("proposervm invariant violated")
+
+// checkStoredBlock returns an error if the state committed after storing [blk]
+// is inconsistent with [blk]. It's a no-op unless invariant checks are enabled.
+func (vm *VM) checkStoredBlock(blk PostForkBlock) error {
+	if !vm.ctx.InvariantChecks {
+		return nil
+	}
+
+	blkID := blk.ID()
+	height := blk.Height()
+	indexedID, err := vm.State.GetBlockIDAtHeight(height)
+	switch {
+	case err == database.ErrNotFound:
+		indexedID = ids.Empty
+	case err != nil:
+		return err
+	}
+
+	switch blk.Status() {
+	case choices.Accepted:
+		lastAcceptedID, err := vm.State.GetLastAccepted()
+		if err != nil {
+			return err
+		}
+		if lastAcceptedID != blkID {
+			return fmt.Errorf("%w: block %s was accepted but the last accepted block is %s", errInvariantViolated, blkID, lastAcceptedID)
+		}
+
+		if indexedID == ids.Empty {
+			// The index is only missing entries while it's being repaired
+			if vm.hIndexer.IsRepaired() && !vm.resetHeightIndexOngoing.GetValue() {
+				return fmt.Errorf("%w: accepted block %s isn't indexed at height %d", errInvariantViolated, blkID, height)
+			}
+			return nil
+		}
+		if indexedID != blkID {
+			return fmt.Errorf("%w: block %s was accepted at height %d but block %s is indexed", errInvariantViolated, blkID, height, indexedID)
+		}
+
+		forkHeight, err := vm.State.GetForkHeight()
+		if err != nil {
+			return fmt.Errorf("%w: block %s is indexed but the fork height isn't: %s", errInvariantViolated, blkID, err)
+		}
+		if forkHeight > height {
+			return fmt.Errorf("%w: block %s is indexed at height %d below the fork height %d", errInvariantViolated, blkID, height, forkHeight)
+		}
+	case choices.Rejected:
+		if indexedID == blkID {
+			return fmt.Errorf("%w: rejected block %s is indexed at height %d", errInvariantViolated, blkID, height)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
+)
+
+func TestCheckStoredBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0)
+	proVM.ctx.InvariantChecks = true
+
+	coreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1},
+		ParentV:    coreGenBlk.ID(),
+		HeightV:    coreGenBlk.Height() + 1,
+		TimestampV: coreGenBlk.Timestamp().Add(proposer.MaxDelay),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+
+	blk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(blk.Verify())
+	assert.NoError(blk.Accept())
+
+	// An accepted block that the height index doesn't point to is caught
+	assert.NoError(proVM.State.SetBlockIDAtHeight(blk.Height(), ids.GenerateTestID()))
+	assert.NoError(proVM.db.Commit())
+	err = proVM.checkStoredBlock(blk.(PostForkBlock))
+	assert.True(errors.Is(err, errInvariantViolated))
+
+	// The checks are skipped unless they're enabled
+	proVM.ctx.InvariantChecks = false
+	assert.NoError(proVM.checkStoredBlock(blk.(PostForkBlock)))
+}
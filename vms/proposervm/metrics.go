@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+type vmMetrics struct {
+	equivocations         prometheus.Counter
+	duplicateIdentity     prometheus.Gauge
+	memoizedVerifications prometheus.Counter
+
+	// proposerWindowsHit counts the accepted blocks proposed by this node.
+	// proposerWindowsMissed counts the accepted blocks proposed by another
+	// node after this node's proposer window had started.
+	proposerWindowsHit, proposerWindowsMissed prometheus.Counter
+
+	blocksOffloaded prometheus.Counter
+	buildsAborted   prometheus.Counter
+	preparedBlocks  prometheus.Counter
+}
+
+func (m *vmMetrics) Initialize(namespace string, registerer prometheus.Registerer) error {
+	m.equivocations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "equivocations",
+		Help:      "Number of times a proposer was observed signing two different blocks with the same parent",
+	})
+	m.duplicateIdentity = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "duplicate_identity",
+		Help:      "1 if a block signed with this node's staking key was observed that this node didn't build, 0 otherwise",
+	})
+	m.memoizedVerifications = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "memoized_verifications",
+		Help:      "Number of blocks whose proposer verification was skipped because they passed it before a restart",
+	})
+	m.proposerWindowsHit = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "proposer_windows_hit",
+		Help:      "Number of accepted blocks that were proposed by this node",
+	})
+	m.proposerWindowsMissed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "proposer_windows_missed",
+		Help:      "Number of accepted blocks that were proposed by another node after this node's proposer window had started",
+	})
+	m.blocksOffloaded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_offloaded",
+		Help:      "Number of accepted blocks moved to cold storage",
+	})
+	m.buildsAborted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "builds_aborted",
+		Help:      "Number of inner blocks whose build was aborted because it ran past its deadline",
+	})
+	m.preparedBlocks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "prepared_blocks",
+		Help:      "Number of parsed blocks that were prepared before the chain's lock was held",
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.equivocations),
+		registerer.Register(m.duplicateIdentity),
+		registerer.Register(m.memoizedVerifications),
+		registerer.Register(m.proposerWindowsHit),
+		registerer.Register(m.proposerWindowsMissed),
+		registerer.Register(m.blocksOffloaded),
+		registerer.Register(m.buildsAborted),
+		registerer.Register(m.preparedBlocks),
+	)
+	return errs.Err
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/migration"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// migrationSchema is the schema the proposervm's database is versioned
+	// under
+	migrationSchema = "proposervm"
+
+	// innerMigrationSchema is the schema of the state the proposervm derives
+	// from the inner VM's chain. Its migrations run once the inner VM is
+	// initialized, since they read the inner VM's blocks.
+	innerMigrationSchema = "proposervm-inner"
+)
+
+var (
+	migrationPrefix = []byte("migration")
+
+	// migrations upgrade the proposervm's database, in order of their
+	// versions. A migration must be added here whenever the format of the
+	// stored state changes.
+	migrations []migration.Migration
+)
+
+// migrate upgrades [db] to the format this version of the proposervm expects.
+// The version of [db] is recorded in [db] itself, so that older versions of the
+// proposervm refuse to run on it.
+func migrate(log logging.Logger, db database.Database) error {
+	return runMigrations(log, db, migrationSchema, migrations)
+}
+
+// migrateInner upgrades the state derived from the inner VM's chain. It must
+// be called once the inner VM is initialized.
+func (vm *VM) migrateInner(db database.Database) error {
+	return runMigrations(vm.ctx.Log, db, innerMigrationSchema, []migration.Migration{
+		{
+			Version: 1,
+			Name:    "backfill height index",
+			Migrate: vm.backfillHeightIndex,
+		},
+	})
+}
+
+func runMigrations(log logging.Logger, db database.Database, schema string, migrations []migration.Migration) error {
+	manager := migration.NewManager(log, prefixdb.New(migrationPrefix, db))
+	for _, m := range migrations {
+		if err := manager.Register(schema, m); err != nil {
+			return err
+		}
+	}
+	return manager.Migrate(schema, db)
+}
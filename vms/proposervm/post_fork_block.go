@@ -27,13 +27,27 @@ func (b *postForkBlock) Accept() error {
 		return err
 	}
 
+	if err := b.vm.State.DeleteVerified(blkID); err != nil {
+		return err
+	}
+
+	// Conflicting blocks can no longer be issued once a block is accepted at
+	// their height
+	if err := b.vm.State.PruneSigned(b.Height()); err != nil {
+		return err
+	}
+
 	// Persist this block, its height index, and its status
 	b.status = choices.Accepted
 	if err := b.vm.storePostForkBlock(b); err != nil {
 		return err
 	}
 
+	b.vm.trackProposerWindow(b)
+
 	delete(b.vm.verifiedBlocks, blkID)
+	b.vm.builtBlocks.Remove(blkID)
+	b.vm.pruneSignedBlocks(b.ParentID())
 	b.vm.lastAcceptedTime = b.Timestamp()
 
 	// mark the inner block as accepted and all conflicting inner blocks as
@@ -43,11 +57,22 @@ func (b *postForkBlock) Accept() error {
 
 func (b *postForkBlock) Reject() error {
 	// We do not reject the inner block here because it may be accepted later
-	delete(b.vm.verifiedBlocks, b.ID())
+	blkID := b.ID()
+	delete(b.vm.verifiedBlocks, blkID)
+	b.vm.builtBlocks.Remove(blkID)
+	b.vm.pruneSignedBlocks(blkID)
+
+	if err := b.vm.State.DeleteVerified(blkID); err != nil {
+		return err
+	}
 
 	// Persist this block with its status
 	b.status = choices.Rejected
-	return b.vm.storePostForkBlock(b)
+	if err := b.vm.storePostForkBlock(b); err != nil {
+		return err
+	}
+	b.vm.recordRejection(b)
+	return nil
 }
 
 func (b *postForkBlock) Status() choices.Status { return b.status }
@@ -61,9 +86,10 @@ func (b *postForkBlock) Parent() ids.ID {
 // If Verify() returns nil, Accept() or Reject() will eventually be called on
 // [b] and [b.innerBlk]
 func (b *postForkBlock) Verify() error {
-	parent, err := b.vm.getBlock(b.ParentID())
+	parentID := b.ParentID()
+	parent, err := b.vm.getBlock(parentID)
 	if err != nil {
-		return err
+		return missingParent(parentID, err)
 	}
 	return parent.verifyPostForkChild(b)
 }
@@ -81,6 +107,7 @@ func (b *postForkBlock) Options() ([2]snowman.Block, error) {
 	if err != nil {
 		return [2]snowman.Block{}, err
 	}
+	b.vm.innerOracleBlocks.SetValue(true)
 
 	parentID := b.ID()
 	outerOptions := [2]snowman.Block{}
@@ -51,11 +51,17 @@ func (b *postForkOption) Reject() error {
 	// we do not reject the inner block here because that block may be contained
 	// in the proposer block that causing this block to be rejected.
 
-	delete(b.vm.verifiedBlocks, b.ID())
+	blkID := b.ID()
+	delete(b.vm.verifiedBlocks, blkID)
+	b.vm.pruneSignedBlocks(blkID)
 
 	// Persist this block and its status
 	b.status = choices.Rejected
-	return b.vm.storePostForkBlock(b)
+	if err := b.vm.storePostForkBlock(b); err != nil {
+		return err
+	}
+	b.vm.recordRejection(b)
+	return nil
 }
 
 func (b *postForkOption) Status() choices.Status { return b.status }
@@ -67,9 +73,10 @@ func (b *postForkOption) Parent() ids.ID {
 // If Verify returns nil, Accept or Reject is eventually called on [b] and
 // [b.innerBlk].
 func (b *postForkOption) Verify() error {
-	parent, err := b.vm.getBlock(b.ParentID())
+	parentID := b.ParentID()
+	parent, err := b.vm.getBlock(parentID)
 	if err != nil {
-		return err
+		return missingParent(parentID, err)
 	}
 	b.timestamp = parent.Timestamp()
 	return parent.verifyPostForkOption(b)
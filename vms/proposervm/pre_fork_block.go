@@ -9,6 +9,7 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/proposervm/block"
 )
 
@@ -19,10 +20,18 @@ type preForkBlock struct {
 	vm *VM
 }
 
+func (b *preForkBlock) Accept() error {
+	if err := b.Block.Accept(); err != nil {
+		return err
+	}
+	return b.vm.indexPreForkBlock(b.Height(), b.ID())
+}
+
 func (b *preForkBlock) Verify() error {
-	parent, err := b.vm.getPreForkBlock(b.Block.Parent())
+	parentID := b.Block.Parent()
+	parent, err := b.vm.getPreForkBlock(parentID)
 	if err != nil {
-		return err
+		return missingParent(parentID, err)
 	}
 	return parent.verifyPreForkChild(b)
 }
@@ -87,7 +96,7 @@ func (b *preForkBlock) verifyPostForkChild(child *postForkBlock) error {
 	currentPChainHeight, err := b.vm.ctx.ValidatorState.GetCurrentHeight()
 	if err != nil {
 		b.vm.ctx.Log.Error("couldn't retrieve current P-Chain height while verifying %s: %s", childID, err)
-		return err
+		return wrappers.NewTransient(err)
 	}
 	if childPChainHeight > currentPChainHeight {
 		return errPChainHeightNotReached
@@ -157,7 +166,7 @@ func (b *preForkBlock) buildChild() (Block, error) {
 	// The chain is currently forking
 
 	parentID := b.ID()
-	newTimestamp := b.vm.Time().Truncate(time.Second)
+	newTimestamp := b.vm.blockTime().Truncate(time.Second)
 	if newTimestamp.Before(parentTimestamp) {
 		newTimestamp = parentTimestamp
 	}
@@ -169,7 +178,7 @@ func (b *preForkBlock) buildChild() (Block, error) {
 		return nil, err
 	}
 
-	innerBlock, err := b.vm.ChainVM.BuildBlock()
+	innerBlock, err := b.vm.buildInnerBlock(newTimestamp.Add(maxBuildDelay))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/units"
+
+	statelessblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+// preparedBlocksCacheSize is the total size of the prepared blocks that are
+// waiting to be parsed. It's enough to hold the containers of the few messages
+// that are prepared at once.
+const preparedBlocksCacheSize = 8 * units.MiB
+
+var _ block.PreparingChainVM = &VM{}
+
+func newPreparedBlocksCache() *cache.SizedLRU {
+	return &cache.SizedLRU{
+		MaxSize: preparedBlocksCacheSize,
+		Size: func(_, value interface{}) int {
+			return len(value.(statelessblock.Block).Bytes())
+		},
+	}
+}
+
+// PrepareBlock parses [b] and checks the signature of its proposer, which are
+// the parts of parsing and verifying a post-fork block that don't depend on
+// the state of the VM. ParseBlock of the same bytes then uses the prepared
+// block. The inner block is prepared by the inner VM.
+func (vm *VM) PrepareBlock(b []byte) {
+	statelessBlock, err := statelessblock.Parse(b)
+	if err != nil {
+		// [b] may be a pre-fork block, which is parsed by the inner VM
+		block.PrepareBlock(vm.ChainVM, b)
+		return
+	}
+
+	// Signatures aren't checked while bootstrapping. Once bootstrapped, the
+	// block remembers the result of the check for when it's verified.
+	signedBlock, ok := statelessBlock.(statelessblock.SignedBlock)
+	if ok && signedBlock.Proposer() != ids.ShortEmpty && vm.prepareSignatures.GetValue() {
+		_ = signedBlock.Verify(true, vm.ctx.ChainID)
+	}
+
+	vm.preparedBlocks.Put(hashing.ComputeHash256Array(b), statelessBlock)
+	block.PrepareBlock(vm.ChainVM, statelessBlock.Block())
+}
+
+// parseStatelessBlock parses [b], using the block prepared from [b] if there
+// is one
+func (vm *VM) parseStatelessBlock(b []byte) (statelessblock.Block, error) {
+	key := hashing.ComputeHash256Array(b)
+	if blkIntf, ok := vm.preparedBlocks.Get(key); ok {
+		vm.preparedBlocks.Evict(key)
+		vm.metrics.preparedBlocks.Inc()
+		return blkIntf.(statelessblock.Block), nil
+	}
+	return statelessblock.Parse(b)
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
+)
+
+// ProposerWindow describes when this node may propose the child of the
+// preferred block
+type ProposerWindow struct {
+	// Height of the block to be proposed
+	Height          json.Uint64 `json:"height"`
+	ParentID        ids.ID      `json:"parentID"`
+	ParentTimestamp time.Time   `json:"parentTimestamp"`
+	// P-chain height the proposers of [Height] are sampled at
+	PChainHeight json.Uint64 `json:"pChainHeight"`
+	// True if this node is one of the proposers of [Height]. If it isn't, it
+	// may only propose the block once anyone can.
+	Proposer bool `json:"proposer"`
+	// Time from which this node may propose the block
+	WindowStart time.Time `json:"windowStart"`
+}
+
+// ProposerWindow returns the proposer window of this node for the child of the
+// preferred block.
+// Assumes the context lock is held.
+func (vm *VM) ProposerWindow() (ProposerWindow, error) {
+	parent, err := vm.getBlock(vm.preferred)
+	if err != nil {
+		return ProposerWindow{}, err
+	}
+	parentPChainHeight, err := parent.pChainHeight()
+	if err != nil {
+		return ProposerWindow{}, err
+	}
+
+	height := parent.Height() + 1
+	minDelay, err := vm.Windower.Delay(height, parentPChainHeight, vm.ctx.NodeID)
+	if err != nil {
+		return ProposerWindow{}, err
+	}
+	parentTimestamp := parent.Timestamp()
+	return ProposerWindow{
+		Height:          json.Uint64(height),
+		ParentID:        parent.ID(),
+		ParentTimestamp: parentTimestamp,
+		PChainHeight:    json.Uint64(parentPChainHeight),
+		Proposer:        minDelay < proposer.MaxDelay,
+		WindowStart:     parentTimestamp.Add(minDelay),
+	}, nil
+}
+
+// trackProposerWindow records whether this node used its proposer window at the
+// height of the accepted block [blk]. The window was missed if [blk] was
+// proposed by another node after this node's window had started.
+func (vm *VM) trackProposerWindow(blk *postForkBlock) {
+	if !vm.bootstrapped {
+		return
+	}
+	if blk.Proposer() == vm.ctx.NodeID {
+		vm.metrics.proposerWindowsHit.Inc()
+		return
+	}
+
+	parentID := blk.ParentID()
+	parent, err := vm.getBlock(parentID)
+	if err != nil {
+		vm.ctx.Log.Debug("couldn't get parent %s of accepted block %s to check the proposer window: %s",
+			parentID, blk.ID(), err)
+		return
+	}
+	parentPChainHeight, err := parent.pChainHeight()
+	if err != nil {
+		vm.ctx.Log.Debug("couldn't get P-chain height of %s to check the proposer window: %s",
+			parentID, err)
+		return
+	}
+	minDelay, err := vm.Windower.Delay(blk.Height(), parentPChainHeight, vm.ctx.NodeID)
+	if err != nil {
+		vm.ctx.Log.Debug("couldn't get the proposer window of this node at height %d: %s",
+			blk.Height(), err)
+		return
+	}
+	if minDelay >= proposer.MaxDelay {
+		// This node wasn't one of the proposers of [blk]'s height
+		return
+	}
+
+	if delay := blk.Timestamp().Sub(parent.Timestamp()); delay >= minDelay {
+		vm.metrics.proposerWindowsMissed.Inc()
+	}
+}
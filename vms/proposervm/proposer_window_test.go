@@ -0,0 +1,28 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProposerWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+
+	window, err := proVM.ProposerWindow()
+	assert.NoError(err)
+	assert.EqualValues(coreGenBlk.Height()+1, window.Height)
+	assert.Equal(coreGenBlk.ID(), window.ParentID)
+	assert.Equal(coreGenBlk.Timestamp(), window.ParentTimestamp)
+	assert.True(window.Proposer)
+
+	minDelay, err := proVM.Windower.Delay(coreGenBlk.Height()+1, uint64(window.PChainHeight), proVM.ctx.NodeID)
+	assert.NoError(err)
+	assert.Equal(coreGenBlk.Timestamp().Add(minDelay), window.WindowStart)
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+)
+
+// maxRejectedBlocks is the number of recently rejected blocks whose rejection
+// is retained
+const maxRejectedBlocks = 512
+
+// RejectionReason describes why consensus rejected a block
+type RejectionReason string
+
+const (
+	// RejectedConflictAccepted means that a block with the same parent was
+	// accepted
+	RejectedConflictAccepted RejectionReason = "conflicting block accepted"
+	// RejectedParentRejected means that the block's parent was rejected
+	RejectedParentRejected RejectionReason = "parent rejected"
+	// RejectedUnknown means that the conflicting block couldn't be determined
+	RejectedUnknown RejectionReason = "unknown"
+)
+
+// RejectedBlock describes the rejection of a ProposerBlock
+type RejectedBlock struct {
+	BlockID    ids.ID          `json:"blockID"`
+	ParentID   ids.ID          `json:"parentID"`
+	Height     json.Uint64     `json:"height"`
+	Reason     RejectionReason `json:"reason"`
+	RejectedAt time.Time       `json:"rejectedAt"`
+	// ConflictingAncestorID is the block, either this block or one of its
+	// ancestors, that conflicted with an accepted block
+	ConflictingAncestorID ids.ID `json:"conflictingAncestorID"`
+	// AcceptedBlockID is the accepted block that [ConflictingAncestorID]
+	// conflicted with. It's empty if the reason is unknown.
+	AcceptedBlockID ids.ID `json:"acceptedBlockID"`
+}
+
+// rejectedBlocks retains the most recently rejected blocks. It may be read
+// without holding the context lock.
+type rejectedBlocks struct {
+	lock sync.Mutex
+	// Block ID --> *RejectedBlock, from oldest to newest rejection
+	blocks linkedhashmap.LinkedHashmap
+}
+
+func newRejectedBlocks() *rejectedBlocks {
+	return &rejectedBlocks{
+		blocks: linkedhashmap.New(),
+	}
+}
+
+func (r *rejectedBlocks) put(blk *RejectedBlock) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.blocks.Put(blk.BlockID, blk)
+	for r.blocks.Len() > maxRejectedBlocks {
+		oldestID, _, _ := r.blocks.Oldest()
+		r.blocks.Delete(oldestID)
+	}
+}
+
+func (r *rejectedBlocks) get(blkID ids.ID) (*RejectedBlock, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	blk, ok := r.blocks.Get(blkID)
+	if !ok {
+		return nil, false
+	}
+	return blk.(*RejectedBlock), true
+}
+
+// list returns the retained rejections, from newest to oldest
+func (r *rejectedBlocks) list() []RejectedBlock {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	blks := make([]RejectedBlock, r.blocks.Len())
+	i := len(blks) - 1
+	for it := r.blocks.NewIterator(); it.Next(); i-- {
+		blks[i] = *it.Value().(*RejectedBlock)
+	}
+	return blks
+}
+
+// RejectedBlocks returns the most recently rejected ProposerBlocks, from newest
+// to oldest, along with the reason they were rejected.
+func (vm *VM) RejectedBlocks() []RejectedBlock {
+	return vm.rejectedBlocks.list()
+}
+
+// recordRejection retains why [blk] was rejected. Consensus rejects a block
+// either because a block with the same parent was accepted, or because its
+// parent was rejected.
+func (vm *VM) recordRejection(blk PostForkBlock) {
+	blkID := blk.ID()
+	parentID := blk.Parent()
+	rejection := &RejectedBlock{
+		BlockID:               blkID,
+		ParentID:              parentID,
+		Height:                json.Uint64(blk.Height()),
+		Reason:                RejectedUnknown,
+		RejectedAt:            vm.Time(),
+		ConflictingAncestorID: blkID,
+	}
+
+	if parentRejection, ok := vm.rejectedBlocks.get(parentID); ok {
+		rejection.Reason = RejectedParentRejected
+		rejection.ConflictingAncestorID = parentRejection.ConflictingAncestorID
+		rejection.AcceptedBlockID = parentRejection.AcceptedBlockID
+	} else if parent, err := vm.getPostForkBlock(parentID); err == nil && parent.Status() == choices.Rejected {
+		// The parent's rejection is no longer retained
+		rejection.Reason = RejectedParentRejected
+		rejection.ConflictingAncestorID = parentID
+	} else if acceptedID, err := vm.State.GetBlockIDAtHeight(blk.Height()); err == nil && acceptedID != blkID {
+		rejection.Reason = RejectedConflictAccepted
+		rejection.AcceptedBlockID = acceptedID
+	}
+
+	vm.ctx.Log.Debug("rejected block %s at height %d: %s", blkID, blk.Height(), rejection.Reason)
+	vm.rejectedBlocks.put(rejection)
+}
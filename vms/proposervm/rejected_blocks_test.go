@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+
+	statelessblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+func TestRejectedBlocksRetainReason(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	proVM.Set(coreGenBlk.Timestamp())
+
+	newCoreBlk := func(i uint64, parent snowman.Block) *snowman.TestBlock {
+		return &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(i),
+				StatusV: choices.Processing,
+			},
+			BytesV:     []byte{byte(i)},
+			ParentV:    parent.ID(),
+			HeightV:    parent.Height() + 1,
+			TimestampV: parent.Timestamp(),
+		}
+	}
+	localCoreBlk := newCoreBlk(1, coreGenBlk)
+	netCoreBlk := newCoreBlk(2, coreGenBlk)
+	childCoreBlk := newCoreBlk(3, netCoreBlk)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return localCoreBlk, nil }
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range []snowman.Block{coreGenBlk, localCoreBlk, netCoreBlk, childCoreBlk} {
+			if bytes.Equal(b, blk.Bytes()) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+
+	builtBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(builtBlk.Verify())
+
+	newProBlk := func(parentID ids.ID, coreBlk *snowman.TestBlock) *postForkBlock {
+		slb, err := statelessblock.BuildUnsigned(
+			parentID,
+			coreBlk.Timestamp(),
+			0,
+			coreBlk.Bytes(),
+		)
+		assert.NoError(err)
+		return &postForkBlock{
+			SignedBlock: slb,
+			postForkCommonComponents: postForkCommonComponents{
+				vm:       proVM,
+				innerBlk: coreBlk,
+				status:   choices.Processing,
+			},
+		}
+	}
+	netBlk := newProBlk(builtBlk.Parent(), netCoreBlk)
+	childBlk := newProBlk(netBlk.ID(), childCoreBlk)
+
+	assert.NoError(builtBlk.Accept())
+	assert.NoError(netBlk.Reject())
+	assert.NoError(childBlk.Reject())
+
+	rejected := proVM.RejectedBlocks()
+	assert.Len(rejected, 2)
+
+	// Rejections are returned from newest to oldest
+	assert.Equal(childBlk.ID(), rejected[0].BlockID)
+	assert.Equal(RejectedParentRejected, rejected[0].Reason)
+	assert.Equal(netBlk.ID(), rejected[0].ConflictingAncestorID)
+	assert.Equal(builtBlk.ID(), rejected[0].AcceptedBlockID)
+
+	assert.Equal(netBlk.ID(), rejected[1].BlockID)
+	assert.Equal(RejectedConflictAccepted, rejected[1].Reason)
+	assert.Equal(netBlk.ID(), rejected[1].ConflictingAncestorID)
+	assert.Equal(builtBlk.ID(), rejected[1].AcceptedBlockID)
+}
+
+func TestRejectedBlocksBounded(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newRejectedBlocks()
+	for i := uint64(0); i < maxRejectedBlocks+10; i++ {
+		r.put(&RejectedBlock{BlockID: ids.Empty.Prefix(i)})
+	}
+
+	blks := r.list()
+	assert.Len(blks, maxRejectedBlocks)
+	assert.Equal(ids.Empty.Prefix(maxRejectedBlocks+9), blks[0].BlockID)
+
+	_, ok := r.get(ids.Empty.Prefix(9))
+	assert.False(ok)
+	_, ok = r.get(ids.Empty.Prefix(10))
+	assert.True(ok)
+}
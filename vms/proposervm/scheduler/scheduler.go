@@ -10,6 +10,11 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
+// Clock tells the time that the build block times are compared against
+type Clock interface {
+	Time() time.Time
+}
+
 type Scheduler interface {
 	Dispatch(startTime time.Time)
 	SetBuildBlockTime(t time.Time)
@@ -22,6 +27,8 @@ type Scheduler interface {
 // propose a block under the congestion control mechanism.
 type scheduler struct {
 	log logging.Logger
+	// Tells the time that the build block times are compared against
+	clock Clock
 	// The VM sends a message on this channel when it wants to tell the engine
 	// that the engine should call the VM's BuildBlock method
 	fromVM <-chan common.Message
@@ -34,10 +41,11 @@ type scheduler struct {
 	newBuildBlockTime chan time.Time
 }
 
-func New(log logging.Logger, toEngine chan<- common.Message) (Scheduler, chan<- common.Message) {
+func New(log logging.Logger, toEngine chan<- common.Message, clock Clock) (Scheduler, chan<- common.Message) {
 	vmToEngine := make(chan common.Message, cap(toEngine))
 	return &scheduler{
 		log:               log,
+		clock:             clock,
 		fromVM:            vmToEngine,
 		toEngine:          toEngine,
 		newBuildBlockTime: make(chan time.Time),
@@ -45,7 +53,7 @@ func New(log logging.Logger, toEngine chan<- common.Message) (Scheduler, chan<-
 }
 
 func (s *scheduler) Dispatch(buildBlockTime time.Time) {
-	timer := time.NewTimer(time.Until(buildBlockTime))
+	timer := time.NewTimer(s.until(buildBlockTime))
 waitloop:
 	for {
 		select {
@@ -63,7 +71,7 @@ waitloop:
 
 			// The time at which we should notify the engine that it should try
 			// to build a block has changed
-			timer.Reset(time.Until(buildBlockTime))
+			timer.Reset(s.until(buildBlockTime))
 			continue waitloop
 		}
 
@@ -88,13 +96,18 @@ waitloop:
 				}
 				// We know [timer.C] was drained in the first select statement
 				// so its safe to call [timer.Reset]
-				timer.Reset(time.Until(buildBlockTime))
+				timer.Reset(s.until(buildBlockTime))
 				continue waitloop
 			}
 		}
 	}
 }
 
+// until returns the duration until [t] on the scheduler's clock
+func (s *scheduler) until(t time.Time) time.Duration {
+	return t.Sub(s.clock.Time())
+}
+
 func (s *scheduler) SetBuildBlockTime(t time.Time) {
 	s.newBuildBlockTime <- t
 }
@@ -9,13 +9,14 @@ import (
 
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 )
 
 func TestDelayFromNew(t *testing.T) {
 	toEngine := make(chan common.Message, 10)
 	startTime := time.Now().Add(50 * time.Millisecond)
 
-	s, fromVM := New(logging.NoLog{}, toEngine)
+	s, fromVM := New(logging.NoLog{}, toEngine, &mockable.Clock{})
 	defer s.Close()
 	go s.Dispatch(startTime)
 
@@ -32,7 +33,7 @@ func TestDelayFromSetTime(t *testing.T) {
 	now := time.Now()
 	startTime := now.Add(50 * time.Millisecond)
 
-	s, fromVM := New(logging.NoLog{}, toEngine)
+	s, fromVM := New(logging.NoLog{}, toEngine, &mockable.Clock{})
 	defer s.Close()
 	go s.Dispatch(now)
 
@@ -51,7 +52,7 @@ func TestReceipt(t *testing.T) {
 	now := time.Now()
 	startTime := now.Add(50 * time.Millisecond)
 
-	s, fromVM := New(logging.NoLog{}, toEngine)
+	s, fromVM := New(logging.NoLog{}, toEngine, &mockable.Clock{})
 	defer s.Close()
 	go s.Dispatch(now)
 
@@ -61,3 +62,21 @@ func TestReceipt(t *testing.T) {
 
 	<-toEngine
 }
+
+func TestDelayFromClock(t *testing.T) {
+	toEngine := make(chan common.Message, 10)
+	clock := &mockable.Clock{}
+	clock.Set(time.Now().Add(time.Hour))
+
+	s, fromVM := New(logging.NoLog{}, toEngine, clock)
+	defer s.Close()
+	go s.Dispatch(time.Now())
+
+	// The build block time has been reached on the clock, even though it
+	// hasn't been reached on the local time
+	s.SetBuildBlockTime(clock.Time())
+
+	fromVM <- common.PendingTxs
+
+	<-toEngine
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/cache/metercacher"
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/tieredb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/vms/proposervm/block"
@@ -30,6 +31,10 @@ var (
 type BlockState interface {
 	GetBlock(blkID ids.ID) (block.Block, choices.Status, error)
 	PutBlock(blk block.Block, status choices.Status) error
+
+	// OffloadBlock moves the block to cold storage, from which GetBlock keeps
+	// returning it. It's a no-op if the state has no cold storage.
+	OffloadBlock(blkID ids.ID) error
 }
 
 type blockState struct {
@@ -38,6 +43,9 @@ type blockState struct {
 	blkCache cache.Cacher
 
 	db database.Database
+	// tiered is nil if blocks can't be offloaded. Otherwise, [db] is
+	// [tiered].
+	tiered *tieredb.Database
 }
 
 type blockWrapper struct {
@@ -54,6 +62,23 @@ func NewBlockState(db database.Database) BlockState {
 	}
 }
 
+// NewTieredBlockState returns a BlockState whose blocks can be offloaded to
+// [store]. [offloadedDB] records which blocks were offloaded and
+// [coldCacheSize] is the number of offloaded blocks kept in memory once read.
+func NewTieredBlockState(
+	db database.Database,
+	offloadedDB database.Database,
+	store tieredb.ObjectStore,
+	coldCacheSize int,
+) BlockState {
+	tiered := tieredb.New(db, offloadedDB, store, coldCacheSize)
+	return &blockState{
+		blkCache: &cache.LRU{Size: blockCacheSize},
+		db:       tiered,
+		tiered:   tiered,
+	}
+}
+
 func NewMeteredBlockState(db database.Database, namespace string, metrics prometheus.Registerer) (BlockState, error) {
 	blkCache, err := metercacher.New(
 		fmt.Sprintf("%s_block_cache", namespace),
@@ -124,3 +149,10 @@ func (s *blockState) PutBlock(blk block.Block, status choices.Status) error {
 	s.blkCache.Put(blkID, &blkWrapper)
 	return s.db.Put(blkID[:], bytes)
 }
+
+func (s *blockState) OffloadBlock(blkID ids.ID) error {
+	if s.tiered == nil {
+		return nil
+	}
+	return s.tiered.Offload(blkID[:])
+}
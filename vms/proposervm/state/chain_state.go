@@ -10,10 +10,12 @@ import (
 
 const (
 	lastAcceptedByte byte = iota
+	offloadedHeightByte
 )
 
 var (
-	lastAcceptedKey = []byte{lastAcceptedByte}
+	lastAcceptedKey    = []byte{lastAcceptedByte}
+	offloadedHeightKey = []byte{offloadedHeightByte}
 
 	_ ChainState = &chainState{}
 )
@@ -22,6 +24,12 @@ type ChainState interface {
 	SetLastAccepted(blkID ids.ID) error
 	DeleteLastAccepted() error
 	GetLastAccepted() (ids.ID, error)
+
+	// The offloaded height is the height of the next accepted block to be
+	// moved to cold storage. Before the first block is offloaded, it won't be
+	// found.
+	SetOffloadedHeight(height uint64) error
+	GetOffloadedHeight() (uint64, error)
 }
 
 type chainState struct {
@@ -61,3 +69,11 @@ func (s *chainState) GetLastAccepted() (ids.ID, error) {
 	s.lastAccepted = lastAccepted
 	return lastAccepted, nil
 }
+
+func (s *chainState) SetOffloadedHeight(height uint64) error {
+	return database.PutUInt64(s.db, offloadedHeightKey, height)
+}
+
+func (s *chainState) GetOffloadedHeight() (uint64, error) {
+	return database.GetUInt64(s.db, offloadedHeightKey)
+}
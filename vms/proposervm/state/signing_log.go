@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var _ SigningLog = &signingLog{}
+
+// SigningLog records the blocks this node signed, by their height and parent,
+// so that this node never signs two conflicting blocks, even across restarts.
+type SigningLog interface {
+	// GetSigned returns the ID of the block this node signed with [parentID]
+	// at [height]. If no such block was signed, database.ErrNotFound is
+	// returned.
+	GetSigned(height uint64, parentID ids.ID) (ids.ID, error)
+	PutSigned(height uint64, parentID ids.ID, blkID ids.ID) error
+
+	// PruneSigned removes the records of all blocks with a height of at most
+	// [height].
+	PruneSigned(height uint64) error
+}
+
+type signingLog struct {
+	db database.Database
+}
+
+func NewSigningLog(db database.Database) SigningLog {
+	return &signingLog{db: db}
+}
+
+func (s *signingLog) GetSigned(height uint64, parentID ids.ID) (ids.ID, error) {
+	blkIDBytes, err := s.db.Get(signingKey(height, parentID))
+	if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(blkIDBytes)
+}
+
+func (s *signingLog) PutSigned(height uint64, parentID ids.ID, blkID ids.ID) error {
+	return s.db.Put(signingKey(height, parentID), blkID[:])
+}
+
+func (s *signingLog) PruneSigned(height uint64) error {
+	// Keys are prefixed with the big endian height, so the records to prune are
+	// iterated over first
+	it := s.db.NewIterator()
+	defer it.Release()
+
+	var pruned [][]byte
+	for it.Next() {
+		key := it.Key()
+		p := wrappers.Packer{Bytes: key}
+		if blkHeight := p.UnpackLong(); p.Errored() || blkHeight > height {
+			break
+		}
+		pruned = append(pruned, key)
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	for _, key := range pruned {
+		if err := s.db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func signingKey(height uint64, parentID ids.ID) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen+len(parentID))}
+	p.PackLong(height)
+	p.PackFixedBytes(parentID[:])
+	return p.Bytes
+}
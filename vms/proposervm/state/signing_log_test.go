@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestSigningLog(t *testing.T) {
+	a := assert.New(t)
+
+	sl := NewSigningLog(memdb.New())
+
+	parentID0 := ids.GenerateTestID()
+	parentID1 := ids.GenerateTestID()
+	blkID0 := ids.GenerateTestID()
+	blkID1 := ids.GenerateTestID()
+	blkID2 := ids.GenerateTestID()
+
+	_, err := sl.GetSigned(1, parentID0)
+	a.Equal(database.ErrNotFound, err)
+
+	a.NoError(sl.PutSigned(1, parentID0, blkID0))
+	a.NoError(sl.PutSigned(2, parentID1, blkID1))
+	a.NoError(sl.PutSigned(256, parentID0, blkID2))
+
+	signedID, err := sl.GetSigned(1, parentID0)
+	a.NoError(err)
+	a.Equal(blkID0, signedID)
+
+	// The same parent at a different height is a different record
+	signedID, err = sl.GetSigned(256, parentID0)
+	a.NoError(err)
+	a.Equal(blkID2, signedID)
+
+	a.NoError(sl.PruneSigned(2))
+	_, err = sl.GetSigned(1, parentID0)
+	a.Equal(database.ErrNotFound, err)
+	_, err = sl.GetSigned(2, parentID1)
+	a.Equal(database.ErrNotFound, err)
+	signedID, err = sl.GetSigned(256, parentID0)
+	a.NoError(err)
+	a.Equal(blkID2, signedID)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/tieredb"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 )
 
@@ -14,29 +15,58 @@ var (
 	chainStatePrefix  = []byte("chain")
 	blockStatePrefix  = []byte("block")
 	heightIndexPrefix = []byte("height")
+	verifiedPrefix    = []byte("verified")
+	offloadedPrefix   = []byte("offloaded")
+	signingLogPrefix  = []byte("signing")
 )
 
 type State interface {
 	ChainState
 	BlockState
 	HeightIndex
+	VerifiedState
+	SigningLog
 }
 
 type state struct {
 	ChainState
 	BlockState
 	HeightIndex
+	VerifiedState
+	SigningLog
 }
 
 func New(db *versiondb.Database) State {
 	chainDB := prefixdb.New(chainStatePrefix, db)
 	blockDB := prefixdb.New(blockStatePrefix, db)
 	heightDB := prefixdb.New(heightIndexPrefix, db)
+	verifiedDB := prefixdb.New(verifiedPrefix, db)
+	signingLogDB := prefixdb.New(signingLogPrefix, db)
 
 	return &state{
-		ChainState:  NewChainState(chainDB),
-		BlockState:  NewBlockState(blockDB),
-		HeightIndex: NewHeightIndex(heightDB, db),
+		ChainState:    NewChainState(chainDB),
+		BlockState:    NewBlockState(blockDB),
+		HeightIndex:   NewHeightIndex(heightDB, db),
+		VerifiedState: NewVerifiedState(verifiedDB),
+		SigningLog:    NewSigningLog(signingLogDB),
+	}
+}
+
+// NewTiered returns a State whose accepted blocks can be offloaded to [store]
+func NewTiered(db *versiondb.Database, store tieredb.ObjectStore, coldCacheSize int) State {
+	chainDB := prefixdb.New(chainStatePrefix, db)
+	blockDB := prefixdb.New(blockStatePrefix, db)
+	heightDB := prefixdb.New(heightIndexPrefix, db)
+	verifiedDB := prefixdb.New(verifiedPrefix, db)
+	signingLogDB := prefixdb.New(signingLogPrefix, db)
+	offloadedDB := prefixdb.New(offloadedPrefix, db)
+
+	return &state{
+		ChainState:    NewChainState(chainDB),
+		BlockState:    NewTieredBlockState(blockDB, offloadedDB, store, coldCacheSize),
+		HeightIndex:   NewHeightIndex(heightDB, db),
+		VerifiedState: NewVerifiedState(verifiedDB),
+		SigningLog:    NewSigningLog(signingLogDB),
 	}
 }
 
@@ -44,6 +74,8 @@ func NewMetered(db *versiondb.Database, namespace string, metrics prometheus.Reg
 	chainDB := prefixdb.New(chainStatePrefix, db)
 	blockDB := prefixdb.New(blockStatePrefix, db)
 	heightDB := prefixdb.New(heightIndexPrefix, db)
+	verifiedDB := prefixdb.New(verifiedPrefix, db)
+	signingLogDB := prefixdb.New(signingLogPrefix, db)
 
 	blockState, err := NewMeteredBlockState(blockDB, namespace, metrics)
 	if err != nil {
@@ -51,8 +83,10 @@ func NewMetered(db *versiondb.Database, namespace string, metrics prometheus.Reg
 	}
 
 	return &state{
-		ChainState:  NewChainState(chainDB),
-		BlockState:  blockState,
-		HeightIndex: NewHeightIndex(heightDB, db),
+		ChainState:    NewChainState(chainDB),
+		BlockState:    blockState,
+		HeightIndex:   NewHeightIndex(heightDB, db),
+		VerifiedState: NewVerifiedState(verifiedDB),
+		SigningLog:    NewSigningLog(signingLogDB),
 	}, nil
 }
@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var _ VerifiedState = &verifiedState{}
+
+// VerifiedState records the processing blocks that passed verification, so
+// their verification doesn't need to be repeated after a restart. Each block
+// is stored with its height, so that the records of blocks that can no longer
+// be issued can be pruned.
+type VerifiedState interface {
+	PutVerified(blkID ids.ID, height uint64) error
+	IsVerified(blkID ids.ID) (bool, error)
+	DeleteVerified(blkID ids.ID) error
+
+	// PruneVerified removes the records of all blocks with a height of at
+	// most [height].
+	PruneVerified(height uint64) error
+}
+
+type verifiedState struct {
+	db database.Database
+}
+
+func NewVerifiedState(db database.Database) VerifiedState {
+	return &verifiedState{db: db}
+}
+
+func (s *verifiedState) PutVerified(blkID ids.ID, height uint64) error {
+	return database.PutUInt64(s.db, blkID[:], height)
+}
+
+func (s *verifiedState) IsVerified(blkID ids.ID) (bool, error) {
+	return s.db.Has(blkID[:])
+}
+
+func (s *verifiedState) DeleteVerified(blkID ids.ID) error {
+	return s.db.Delete(blkID[:])
+}
+
+func (s *verifiedState) PruneVerified(height uint64) error {
+	it := s.db.NewIterator()
+	defer it.Release()
+
+	var pruned [][]byte
+	for it.Next() {
+		blkHeight, err := database.ParseUInt64(it.Value())
+		if err != nil {
+			return err
+		}
+		if blkHeight <= height {
+			pruned = append(pruned, it.Key())
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	for _, key := range pruned {
+		if err := s.db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func testVerifiedState(a *assert.Assertions, vs VerifiedState) {
+	blkID0 := ids.GenerateTestID()
+	blkID1 := ids.GenerateTestID()
+	blkID2 := ids.GenerateTestID()
+
+	verified, err := vs.IsVerified(blkID0)
+	a.NoError(err)
+	a.False(verified)
+
+	a.NoError(vs.PutVerified(blkID0, 1))
+	a.NoError(vs.PutVerified(blkID1, 2))
+	a.NoError(vs.PutVerified(blkID2, 3))
+
+	verified, err = vs.IsVerified(blkID0)
+	a.NoError(err)
+	a.True(verified)
+
+	a.NoError(vs.DeleteVerified(blkID0))
+	verified, err = vs.IsVerified(blkID0)
+	a.NoError(err)
+	a.False(verified)
+
+	a.NoError(vs.PruneVerified(2))
+	verified, err = vs.IsVerified(blkID1)
+	a.NoError(err)
+	a.False(verified)
+	verified, err = vs.IsVerified(blkID2)
+	a.NoError(err)
+	a.True(verified)
+}
+
+func TestVerifiedState(t *testing.T) {
+	a := assert.New(t)
+
+	db := memdb.New()
+	vs := NewVerifiedState(db)
+
+	testVerifiedState(a, vs)
+}
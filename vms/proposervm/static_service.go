@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+// StaticService defines the static API methods exposed by the ProposerVM. They
+// don't require a running chain.
+type StaticService struct{}
+
+// DecodeBlockArgs are the arguments for calling DecodeBlock
+type DecodeBlockArgs struct {
+	Block    string              `json:"block"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// DecodeBlockReply is the response from calling DecodeBlock. The header
+// fields of options and unsigned blocks that they don't have are omitted.
+type DecodeBlockReply struct {
+	ID       ids.ID `json:"id"`
+	ParentID ids.ID `json:"parentID"`
+	// Option is true if the block is an option of an oracle block
+	Option       bool         `json:"option"`
+	Timestamp    *json.Uint64 `json:"timestamp,omitempty"`
+	PChainHeight *json.Uint64 `json:"pChainHeight,omitempty"`
+	// Proposer is empty if the block isn't signed by a proposer
+	Proposer    string              `json:"proposer,omitempty"`
+	Certificate string              `json:"certificate,omitempty"`
+	Signature   string              `json:"signature,omitempty"`
+	InnerBlock  string              `json:"innerBlock"`
+	Encoding    formatting.Encoding `json:"encoding"`
+}
+
+// DecodeBlock decodes the bytes of a ProposerBlock without verifying it
+func (s *StaticService) DecodeBlock(_ *http.Request, args *DecodeBlockArgs, reply *DecodeBlockReply) error {
+	blkBytes, err := formatting.Decode(args.Encoding, args.Block)
+	if err != nil {
+		return err
+	}
+	blk, err := block.Parse(blkBytes)
+	if err != nil {
+		return err
+	}
+
+	reply.ID = blk.ID()
+	reply.ParentID = blk.ParentID()
+	reply.Encoding = args.Encoding
+	reply.InnerBlock, err = formatting.EncodeWithChecksum(args.Encoding, blk.Block())
+	if err != nil {
+		return err
+	}
+
+	signedBlk, ok := blk.(block.SignedBlock)
+	if !ok {
+		reply.Option = true
+		return nil
+	}
+	timestamp := json.Uint64(signedBlk.Timestamp().Unix())
+	pChainHeight := json.Uint64(signedBlk.PChainHeight())
+	reply.Timestamp = &timestamp
+	reply.PChainHeight = &pChainHeight
+
+	proposer := signedBlk.Proposer()
+	if proposer == ids.ShortEmpty {
+		return nil
+	}
+	reply.Proposer = proposer.PrefixedString(constants.NodeIDPrefix)
+	reply.Certificate, err = formatting.EncodeWithChecksum(args.Encoding, signedBlk.Certificate())
+	if err != nil {
+		return err
+	}
+	reply.Signature, err = formatting.EncodeWithChecksum(args.Encoding, signedBlk.SignatureBytes())
+	return err
+}
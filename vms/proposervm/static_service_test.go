@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"crypto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+func TestStaticServiceDecodeSignedBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	parentID := ids.GenerateTestID()
+	timestamp := time.Unix(123, 0)
+	innerBytes := []byte{1, 2, 3}
+	blk, err := block.Build(
+		parentID,
+		timestamp,
+		42,
+		pTestCert.Leaf,
+		innerBytes,
+		ids.GenerateTestID(),
+		pTestCert.PrivateKey.(crypto.Signer),
+	)
+	assert.NoError(err)
+	blkStr, err := formatting.EncodeWithChecksum(formatting.Hex, blk.Bytes())
+	assert.NoError(err)
+
+	service := StaticService{}
+	reply := DecodeBlockReply{}
+	assert.NoError(service.DecodeBlock(nil, &DecodeBlockArgs{
+		Block:    blkStr,
+		Encoding: formatting.Hex,
+	}, &reply))
+
+	assert.Equal(blk.ID(), reply.ID)
+	assert.Equal(parentID, reply.ParentID)
+	assert.False(reply.Option)
+	assert.Equal(json.Uint64(timestamp.Unix()), *reply.Timestamp)
+	assert.Equal(json.Uint64(42), *reply.PChainHeight)
+	proposer := ids.ShortID(hashing.ComputeHash160Array(hashing.ComputeHash256(pTestCert.Leaf.Raw)))
+	assert.Equal(proposer.PrefixedString(constants.NodeIDPrefix), reply.Proposer)
+	assert.NotEmpty(reply.Signature)
+
+	decodedInner, err := formatting.Decode(formatting.Hex, reply.InnerBlock)
+	assert.NoError(err)
+	assert.Equal(innerBytes, decodedInner)
+}
+
+func TestStaticServiceDecodeOption(t *testing.T) {
+	assert := assert.New(t)
+
+	parentID := ids.GenerateTestID()
+	blk, err := block.BuildOption(parentID, []byte{1, 2, 3})
+	assert.NoError(err)
+	blkStr, err := formatting.EncodeWithChecksum(formatting.CB58, blk.Bytes())
+	assert.NoError(err)
+
+	service := StaticService{}
+	reply := DecodeBlockReply{}
+	assert.NoError(service.DecodeBlock(nil, &DecodeBlockArgs{
+		Block:    blkStr,
+		Encoding: formatting.CB58,
+	}, &reply))
+
+	assert.Equal(blk.ID(), reply.ID)
+	assert.Equal(parentID, reply.ParentID)
+	assert.True(reply.Option)
+	assert.Nil(reply.Timestamp)
+	assert.Empty(reply.Proposer)
+}
+
+func TestStaticServiceDecodeInvalidBlock(t *testing.T) {
+	blkStr, err := formatting.EncodeWithChecksum(formatting.Hex, []byte{1, 2, 3})
+	assert.NoError(t, err)
+
+	service := StaticService{}
+	err = service.DecodeBlock(nil, &DecodeBlockArgs{
+		Block:    blkStr,
+		Encoding: formatting.Hex,
+	}, &DecodeBlockReply{})
+	assert.Error(t, err)
+}
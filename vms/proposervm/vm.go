@@ -5,8 +5,14 @@ package proposervm
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/api/doc"
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
@@ -18,6 +24,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/vms/proposervm/indexer"
@@ -34,21 +41,42 @@ const (
 	// are only specific to the second.
 	minBlockDelay         = time.Second
 	checkIndexedFrequency = 10 * time.Second
+
+	// staticEndpoint is the extension of the VM's static API endpoint that the
+	// ProposerVM's static API is served at
+	staticEndpoint = "/proposervm"
 )
 
 var (
 	_ block.ChainVM              = &VM{}
 	_ block.BatchedChainVM       = &VM{}
 	_ block.HeightIndexedChainVM = &VM{}
+	_ block.WrappingChainVM      = &VM{}
 
 	dbPrefix = []byte("proposervm")
 )
 
+// TimeSource provides the current time
+type TimeSource interface {
+	Time() time.Time
+}
+
 type VM struct {
 	block.ChainVM
 	activationTime      time.Time
 	minimumPChainHeight uint64
 
+	// stopProposingOnDuplicateIdentity causes this node to stop building
+	// blocks once another node is detected proposing blocks with this node's
+	// staking key.
+	stopProposingOnDuplicateIdentity bool
+
+	coldStorage ColdStorageConfig
+
+	// blockTimeSource provides the time that built blocks are timestamped
+	// with. If nil, the local clock is used.
+	blockTimeSource TimeSource
+
 	state.State
 	resetHeightIndexOngoing utils.AtomicBool
 	hIndexer                indexer.HeightIndexer
@@ -56,7 +84,7 @@ type VM struct {
 	proposer.Windower
 	tree.Tree
 	scheduler.Scheduler
-	mockable.Clock
+	*mockable.Clock
 
 	ctx         *snow.Context
 	db          *versiondb.Database
@@ -70,11 +98,42 @@ type VM struct {
 	bootstrapped   bool
 	context        context.Context
 	onShutdown     func()
+	metrics        vmMetrics
+
+	// Parent ID --> Proposer ID --> Block ID
+	// Each element is the first signed block that was observed from a
+	// proposer on top of a processing block.
+	signedBlocks map[ids.ID]map[ids.ShortID]ids.ID
+	// builtBlocks contains the processing blocks built by this node
+	builtBlocks ids.Set
+	// duplicateIdentity is true if another node was observed proposing
+	// blocks with this node's staking key
+	duplicateIdentity bool
 
 	// lastAcceptedOptionTime is set to the last accepted PostForkBlock's
 	// timestamp if the last accepted block has been a PostForkOption block
 	// since having initialized the VM.
 	lastAcceptedTime time.Time
+
+	// rejectedBlocks retains why the most recently rejected blocks were
+	// rejected
+	rejectedBlocks *rejectedBlocks
+
+	// innerVMInfo is collected when the VM is initialized
+	innerVMInfo InnerVMInfo
+	// innerOracleBlocks is set once the inner VM is observed issuing an
+	// oracle block
+	innerOracleBlocks utils.AtomicBool
+
+	// Hash of the block bytes --> Block prepared by PrepareBlock that hasn't
+	// been parsed yet
+	preparedBlocks *cache.SizedLRU
+	// prepareSignatures mirrors [bootstrapped] for PrepareBlock, which is
+	// called without the chain's lock held
+	prepareSignatures utils.AtomicBool
+	// IDs of the blocks whose proposer checks passed in VerifyChain, which
+	// Verify doesn't repeat
+	chainVerified *cache.LRU
 }
 
 func New(
@@ -82,11 +141,21 @@ func New(
 	activationTime time.Time,
 	minimumPChainHeight uint64,
 	resetHeightIndex bool,
+	stopProposingOnDuplicateIdentity bool,
+	coldStorage ColdStorageConfig,
+	blockTimeSource TimeSource,
 ) *VM {
 	proVM := &VM{
-		ChainVM:             vm,
-		activationTime:      activationTime,
-		minimumPChainHeight: minimumPChainHeight,
+		ChainVM:                          vm,
+		activationTime:                   activationTime,
+		minimumPChainHeight:              minimumPChainHeight,
+		stopProposingOnDuplicateIdentity: stopProposingOnDuplicateIdentity,
+		coldStorage:                      coldStorage,
+		blockTimeSource:                  blockTimeSource,
+		rejectedBlocks:                   newRejectedBlocks(),
+		preparedBlocks:                   newPreparedBlocksCache(),
+		chainVerified:                    &cache.LRU{Size: chainVerifiedCacheSize},
+		Clock:                            &mockable.Clock{},
 	}
 
 	proVM.resetHeightIndexOngoing.SetValue(resetHeightIndex)
@@ -103,27 +172,55 @@ func (vm *VM) Initialize(
 	fxs []*common.Fx,
 	appSender common.AppSender,
 ) error {
+	registerer := prometheus.NewRegistry()
+	if err := vm.metrics.Initialize("", registerer); err != nil {
+		return err
+	}
+
+	optionalGatherer := metrics.NewOptionalGatherer()
+	multiGatherer := metrics.NewMultiGatherer()
+	if err := multiGatherer.Register("proposervm", registerer); err != nil {
+		return err
+	}
+	if err := multiGatherer.Register("", optionalGatherer); err != nil {
+		return err
+	}
+	if err := ctx.Metrics.Register(multiGatherer); err != nil {
+		return err
+	}
+	ctx.Metrics = optionalGatherer
+
 	vm.ctx = ctx
+	if ctx.Clock != nil {
+		vm.Clock = ctx.Clock
+	}
 	rawDB := dbManager.Current().Database
 	prefixDB := prefixdb.New(dbPrefix, rawDB)
+	if err := migrate(ctx.Log, prefixDB); err != nil {
+		return err
+	}
 	vm.db = versiondb.New(prefixDB)
-	vm.State = state.New(vm.db)
+	vm.State = vm.newState(vm.db)
 	vm.Windower = proposer.New(ctx.ValidatorState, ctx.SubnetID, ctx.ChainID)
 	vm.Tree = tree.New()
 
 	indexerDB := versiondb.New(vm.db)
-	indexerState := state.New(indexerDB)
+	indexerState := vm.newState(indexerDB)
 	vm.hIndexer = indexer.NewHeightIndexer(vm, vm.ctx.Log, indexerState)
 
-	scheduler, vmToEngine := scheduler.New(vm.ctx.Log, toEngine)
+	// The build block times are compared against the time that built blocks
+	// are timestamped with, so that the engine isn't notified before this
+	// node's proposer window started at the block's timestamp
+	scheduler, vmToEngine := scheduler.New(vm.ctx.Log, toEngine, blockClock{vm: vm})
 	vm.Scheduler = scheduler
 	vm.toScheduler = vmToEngine
 
 	go ctx.Log.RecoverAndPanic(func() {
-		scheduler.Dispatch(time.Now())
+		scheduler.Dispatch(vm.blockTime())
 	})
 
 	vm.verifiedBlocks = make(map[ids.ID]PostForkBlock)
+	vm.signedBlocks = make(map[ids.ID]map[ids.ShortID]ids.ID)
 	context, cancel := context.WithCancel(context.Background())
 	vm.context = context
 	vm.onShutdown = cancel
@@ -150,10 +247,23 @@ func (vm *VM) Initialize(
 		return err
 	}
 
+	vm.initInnerVMInfo()
+
+	if err := vm.pruneVerified(); err != nil {
+		return err
+	}
+
+	if err := vm.migrateInner(prefixDB); err != nil {
+		return err
+	}
+
 	// check and possibly rebuild height index
 	innerHVM, ok := vm.ChainVM.(block.HeightIndexedChainVM)
 	if !ok {
-		return nil // nothing else to do
+		// The height index was backfilled when the proposervm first started,
+		// and is updated as blocks are accepted
+		vm.hIndexer.MarkRepaired()
+		return nil
 	}
 
 	// asynchronously rebuild height index, if needed
@@ -246,16 +356,66 @@ func (vm *VM) Shutdown() error {
 
 func (vm *VM) SetState(state snow.State) error {
 	vm.bootstrapped = (state == snow.NormalOp)
+	vm.prepareSignatures.SetValue(vm.bootstrapped)
 	return vm.ChainVM.SetState(state)
 }
 
+func (vm *VM) HealthCheck() (interface{}, error) {
+	innerIntf, innerErr := vm.ChainVM.HealthCheck()
+	if !vm.duplicateIdentity {
+		return innerIntf, innerErr
+	}
+
+	intf := map[string]interface{}{
+		"innerVM":           innerIntf,
+		"duplicateIdentity": true,
+	}
+	if innerErr == nil {
+		return intf, errDuplicateIdentity
+	}
+	return intf, fmt.Errorf("%s ; inner vm: %s", errDuplicateIdentity, innerErr)
+}
+
+// CreateStaticHandlers returns the static handlers of the inner VM along with
+// the ProposerVM's static API, which is served at [staticEndpoint].
+func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
+	handlers, err := vm.ChainVM.CreateStaticHandlers()
+	if err != nil {
+		return nil, err
+	}
+	if handlers == nil {
+		handlers = make(map[string]*common.HTTPHandler)
+	}
+
+	server := doc.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
+	if err := server.RegisterService(&StaticService{}, "proposervm"); err != nil {
+		return nil, err
+	}
+	handlers[staticEndpoint] = &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     server,
+	}
+	return handlers, nil
+}
+
 func (vm *VM) BuildBlock() (snowman.Block, error) {
+	if vm.duplicateIdentity && vm.stopProposingOnDuplicateIdentity {
+		return nil, errDuplicateIdentity
+	}
+
 	preferredBlock, err := vm.getBlock(vm.preferred)
 	if err != nil {
 		return nil, err
 	}
 
-	return preferredBlock.buildChild()
+	blk, err := preferredBlock.buildChild()
+	if err != nil {
+		return nil, err
+	}
+	vm.builtBlocks.Add(blk.ID())
+	return blk, nil
 }
 
 func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
@@ -269,6 +429,24 @@ func (vm *VM) GetBlock(id ids.ID) (snowman.Block, error) {
 	return vm.getBlock(id)
 }
 
+// GetWrappingBlock returns the block with [id]. Peers may request a post-fork
+// block by the ID of the inner block it wraps, so if [id] is the ID of an
+// inner block wrapped by a post-fork block, the post-fork block is returned.
+func (vm *VM) GetWrappingBlock(id ids.ID) (snowman.Block, error) {
+	blk, err := vm.getBlock(id)
+	if err != nil {
+		return nil, err
+	}
+	preForkBlk, ok := blk.(*preForkBlock)
+	if !ok {
+		return blk, nil
+	}
+	if postForkBlk, ok := vm.getBlockFromWrappedBlkID(preForkBlk.Block); ok {
+		return postForkBlk, nil
+	}
+	return blk, nil
+}
+
 func (vm *VM) SetPreference(preferred ids.ID) error {
 	if vm.preferred == preferred {
 		return nil
@@ -309,6 +487,15 @@ func (vm *VM) SetPreference(preferred ids.ID) error {
 
 	vm.ctx.Log.Debug("set preference to %s with timestamp %v; build time scheduled at %v",
 		blk.ID(), preferredTime, nextStartTime)
+
+	// Let the inner VM prepare its next block ahead of the proposer window. The
+	// slot is only a hint, so failing to deliver it doesn't fail the
+	// preference change.
+	if sVM, ok := vm.ChainVM.(block.ScheduledChainVM); ok {
+		if err := sVM.SetProposerSlot(blk.getInnerBlk().ID(), nextStartTime); err != nil {
+			vm.ctx.Log.Debug("failed to notify the inner VM of the proposer slot at %v: %s", nextStartTime, err)
+		}
+	}
 	return nil
 }
 
@@ -421,7 +608,7 @@ func (vm *VM) setLastAcceptedOptionTime() error {
 }
 
 func (vm *VM) parsePostForkBlock(b []byte) (PostForkBlock, error) {
-	statelessBlock, err := statelessblock.Parse(b)
+	statelessBlock, err := vm.parseStatelessBlock(b)
 	if err != nil {
 		return nil, err
 	}
@@ -473,12 +660,47 @@ func (vm *VM) parsePreForkBlock(b []byte) (*preForkBlock, error) {
 }
 
 func (vm *VM) getBlock(id ids.ID) (Block, error) {
-	if blk, err := vm.getPostForkBlock(id); err == nil {
+	blk, err := vm.getPostForkBlock(id)
+	if err == nil {
 		return blk, nil
 	}
+	if err != database.ErrNotFound {
+		return nil, err
+	}
 	return vm.getPreForkBlock(id)
 }
 
+// getBlockFromWrappedBlkID returns a post-fork block that wraps [innerBlk], if
+// there is one. Processing blocks are looked up among the verified blocks, and
+// accepted blocks through the height index.
+func (vm *VM) getBlockFromWrappedBlkID(innerBlk snowman.Block) (PostForkBlock, bool) {
+	innerBlkID := innerBlk.ID()
+	for _, blk := range vm.verifiedBlocks {
+		if blk.getInnerBlk().ID() == innerBlkID {
+			return blk, true
+		}
+	}
+
+	if innerBlk.Status() != choices.Accepted {
+		return nil, false
+	}
+	height := innerBlk.Height()
+	forkHeight, err := vm.State.GetForkHeight()
+	if err != nil || height < forkHeight {
+		// The inner block was accepted before the fork, so it isn't wrapped
+		return nil, false
+	}
+	blkID, err := vm.State.GetBlockIDAtHeight(height)
+	if err != nil {
+		return nil, false
+	}
+	blk, err := vm.getPostForkBlock(blkID)
+	if err != nil || blk.getInnerBlk().ID() != innerBlkID {
+		return nil, false
+	}
+	return blk, true
+}
+
 func (vm *VM) getPostForkBlock(blkID ids.ID) (PostForkBlock, error) {
 	block, exists := vm.verifiedBlocks[blkID]
 	if exists {
@@ -529,12 +751,21 @@ func (vm *VM) storePostForkBlock(blk PostForkBlock) error {
 		return err
 	}
 
-	height := blk.Height()
-	blkID := blk.ID()
-	if err := vm.updateHeightIndex(height, blkID); err != nil {
+	// Only accepted blocks are indexed by height, so that rejecting a block
+	// doesn't overwrite the accepted block at its height
+	if blk.Status() == choices.Accepted {
+		height := blk.Height()
+		if err := vm.updateHeightIndex(height, blk.ID()); err != nil {
+			return err
+		}
+		if err := vm.offloadBlocks(height); err != nil {
+			return err
+		}
+	}
+	if err := vm.db.Commit(); err != nil {
 		return err
 	}
-	return vm.db.Commit()
+	return vm.checkStoredBlock(blk)
 }
 
 func (vm *VM) verifyAndRecordInnerBlk(postFork PostForkBlock) error {
@@ -557,6 +788,37 @@ func (vm *VM) verifyAndRecordInnerBlk(postFork PostForkBlock) error {
 	return nil
 }
 
+// markVerified records that [blk] passed verification, so the proposer checks
+// can be skipped if [blk] is verified again after a restart. The record is
+// only written to disk by the next commit, which happens when a block is
+// decided or the VM shuts down, so verifying a block doesn't write to disk.
+// Losing the record only means the checks will be repeated, so failing to
+// record it is logged rather than failing the verification of the inner
+// block.
+func (vm *VM) markVerified(blk PostForkBlock) {
+	blkID := blk.ID()
+	if err := vm.State.PutVerified(blkID, blk.Height()); err != nil {
+		vm.ctx.Log.Warn("failed to record the verification of %s: %s", blkID, err)
+	}
+}
+
+// pruneVerified removes the verification records of blocks that can no longer
+// be issued because a block at their height was already accepted.
+func (vm *VM) pruneVerified() error {
+	lastAcceptedID, err := vm.LastAccepted()
+	if err != nil {
+		return err
+	}
+	lastAccepted, err := vm.getBlock(lastAcceptedID)
+	if err != nil {
+		return err
+	}
+	if err := vm.State.PruneVerified(lastAccepted.Height()); err != nil {
+		return err
+	}
+	return vm.db.Commit()
+}
+
 // notifyInnerBlockReady tells the scheduler that the inner VM is ready to build
 // a new block
 func (vm *VM) notifyInnerBlockReady() {
@@ -567,6 +829,40 @@ func (vm *VM) notifyInnerBlockReady() {
 	}
 }
 
+// blockTime returns the time that a block built now is timestamped with
+func (vm *VM) blockTime() time.Time {
+	if vm.blockTimeSource != nil {
+		return vm.blockTimeSource.Time()
+	}
+	return vm.Time()
+}
+
+// blockClock tells the time that blocks built by [vm] are timestamped with
+type blockClock struct {
+	vm *VM
+}
+
+func (c blockClock) Time() time.Time {
+	return c.vm.blockTime()
+}
+
+// buildInnerBlock builds a block with the inner VM, aborting the build if it
+// runs past [deadline] and the inner VM supports it. The inner VM keeps the
+// transactions of an aborted block pending, so the scheduler is notified to
+// attempt to build again.
+func (vm *VM) buildInnerBlock(deadline time.Time) (snowman.Block, error) {
+	ctx, cancel := context.WithTimeout(vm.context, deadline.Sub(vm.blockTime()))
+	defer cancel()
+
+	blk, err := block.BuildBlock(ctx, vm.ChainVM)
+	if err != nil && ctx.Err() != nil {
+		vm.ctx.Log.Debug("aborted building the inner block past %s: %s", deadline, err)
+		vm.metrics.buildsAborted.Inc()
+		vm.notifyInnerBlockReady()
+	}
+	return blk, err
+}
+
 func (vm *VM) optimalPChainHeight(minPChainHeight uint64) (uint64, error) {
 	minimumHeight, err := vm.ctx.ValidatorState.GetMinimumHeight()
 	if err != nil {
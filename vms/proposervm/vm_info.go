@@ -0,0 +1,62 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+// InnerVMInfo describes the VM wrapped by the ProposerVM, so that operators can
+// see which optimizations are active for a chain.
+type InnerVMInfo struct {
+	Version string `json:"version"`
+	// OracleBlocks is true if the inner VM was observed issuing oracle blocks
+	OracleBlocks bool `json:"oracleBlocks"`
+	// BatchedParsing is true if the inner VM implements block.BatchedChainVM
+	BatchedParsing bool `json:"batchedParsing"`
+	// HeightIndex is true if the inner VM implements
+	// block.HeightIndexedChainVM
+	HeightIndex bool `json:"heightIndex"`
+}
+
+// InnerVMInfo returns the version and capabilities of the inner VM. It may be
+// called without holding the context lock once the VM is initialized.
+func (vm *VM) InnerVMInfo() InnerVMInfo {
+	info := vm.innerVMInfo
+	info.OracleBlocks = vm.innerOracleBlocks.GetValue()
+	return info
+}
+
+// initInnerVMInfo collects the version and capabilities of the initialized
+// inner VM. They're only reported, so failing to collect them isn't fatal.
+func (vm *VM) initInnerVMInfo() {
+	version, err := vm.ChainVM.Version()
+	if err != nil {
+		vm.ctx.Log.Warn("couldn't fetch the version of the inner VM: %s", err)
+	}
+	_, isBatched := vm.ChainVM.(block.BatchedChainVM)
+	_, isHeightIndexed := vm.ChainVM.(block.HeightIndexedChainVM)
+	vm.innerVMInfo = InnerVMInfo{
+		Version:        version,
+		BatchedParsing: isBatched,
+		HeightIndex:    isHeightIndexed,
+	}
+
+	// Oracle blocks are only issued occasionally, so they're also recorded
+	// when their options are fetched later on
+	lastAcceptedID, err := vm.ChainVM.LastAccepted()
+	if err != nil {
+		return
+	}
+	lastAccepted, err := vm.ChainVM.GetBlock(lastAcceptedID)
+	if err != nil {
+		return
+	}
+	if oracleBlk, ok := lastAccepted.(snowman.OracleBlock); ok {
+		if _, err := oracleBlk.Options(); err == nil {
+			vm.innerOracleBlocks.SetValue(true)
+		}
+	}
+}
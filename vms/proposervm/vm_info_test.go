@@ -0,0 +1,63 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+func TestInnerVMInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	proVM.Set(coreGenBlk.Timestamp())
+
+	info := proVM.InnerVMInfo()
+	assert.False(info.BatchedParsing)
+	assert.False(info.HeightIndex)
+	assert.False(info.OracleBlocks)
+
+	oracleCoreBlk := &TestOptionsBlock{
+		TestBlock: snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(1111),
+				StatusV: choices.Processing,
+			},
+			BytesV:     []byte{1},
+			ParentV:    coreGenBlk.ID(),
+			HeightV:    coreGenBlk.Height() + 1,
+			TimestampV: coreGenBlk.Timestamp(),
+		},
+	}
+	for i := range oracleCoreBlk.opts {
+		oracleCoreBlk.opts[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(2222 + i)),
+				StatusV: choices.Processing,
+			},
+			BytesV:     []byte{byte(2 + i)},
+			ParentV:    oracleCoreBlk.ID(),
+			HeightV:    oracleCoreBlk.Height() + 1,
+			TimestampV: oracleCoreBlk.Timestamp(),
+		}
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return oracleCoreBlk, nil }
+
+	builtBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	oracleBlk, ok := builtBlk.(snowman.OracleBlock)
+	assert.True(ok)
+	_, err = oracleBlk.Options()
+	assert.NoError(err)
+
+	// Fetching the options shows that the inner VM issues oracle blocks
+	assert.True(proVM.InnerVMInfo().OracleBlocks)
+}
@@ -5,6 +5,7 @@ package proposervm
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/tls"
 	"errors"
@@ -13,19 +14,27 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/evidence"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
+	"github.com/ava-labs/avalanchego/vms/proposervm/state"
 
 	statelessblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
 )
@@ -102,7 +111,7 @@ func initTestProposerVM(
 		}
 	}
 
-	proVM := New(coreVM, proBlkStartTime, minPChainHeight, false)
+	proVM := New(coreVM, proBlkStartTime, minPChainHeight, false, false, ColdStorageConfig{}, nil)
 
 	valState := &validators.TestState{
 		T: t,
@@ -176,6 +185,48 @@ func TestBuildBlockTimestampAreRoundedToSeconds(t *testing.T) {
 	}
 }
 
+func TestBuildBlockTimestampUsesBlockTimeSource(t *testing.T) {
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	localTime := time.Now().Truncate(time.Second)
+	proVM.Set(localTime)
+
+	// The validators report a time that is ahead of the local clock
+	vdrs := validators.NewSet()
+	vdrID := ids.GenerateTestShortID()
+	if err := vdrs.AddWeight(vdrID, 1); err != nil {
+		t.Fatal(err)
+	}
+	peerTime := timer.NewPeerTime(vdrs, 1, 1, &mockable.Clock{})
+	peerTime.Clock.Set(localTime)
+	peerTime.Observe(vdrID, localTime.Add(3*time.Second))
+	proVM.blockTimeSource = peerTime
+
+	// The build block times are scheduled on the same time source
+	if expected, now := localTime.Add(3*time.Second), (blockClock{vm: proVM}).Time(); !now.Equal(expected) {
+		t.Fatalf("expected the scheduler's time to be %s but got %s", expected, now)
+	}
+
+	coreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(111),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1},
+		ParentV:    coreGenBlk.ID(),
+		HeightV:    coreGenBlk.Height() + 1,
+		TimestampV: coreGenBlk.Timestamp(),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+
+	builtBlk, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := localTime.Add(3 * time.Second); !builtBlk.Timestamp().Equal(expected) {
+		t.Fatalf("expected timestamp %s but got %s", expected, builtBlk.Timestamp())
+	}
+}
+
 func TestBuildBlockIsIdempotent(t *testing.T) {
 	// given the same core block, BuildBlock returns the same proposer block
 	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
@@ -838,7 +889,7 @@ func TestExpiredBuildBlock(t *testing.T) {
 		}
 	}
 
-	proVM := New(coreVM, time.Time{}, 0, false)
+	proVM := New(coreVM, time.Time{}, 0, false, false, ColdStorageConfig{}, nil)
 
 	valState := &validators.TestState{
 		T: t,
@@ -967,7 +1018,7 @@ func TestExpiredBuildBlock(t *testing.T) {
 	}
 
 	proVM.Set(statelessBlock.Timestamp().Add(proposer.MaxDelay))
-	proVM.Scheduler.SetBuildBlockTime(time.Now())
+	proVM.Scheduler.SetBuildBlockTime(proVM.Time())
 
 	// The engine should have been notified to attempt to build a block now that
 	// the window has started again
@@ -1168,7 +1219,7 @@ func TestInnerVMRollback(t *testing.T) {
 
 	dbManager := manager.NewMemDB(version.DefaultVersion1_0_0)
 
-	proVM := New(coreVM, time.Time{}, 0, false)
+	proVM := New(coreVM, time.Time{}, 0, false, false, ColdStorageConfig{}, nil)
 
 	if err := proVM.Initialize(ctx, dbManager, nil, nil, nil, nil, nil, nil); err != nil {
 		t.Fatalf("failed to initialize proposerVM with %s", err)
@@ -1250,7 +1301,7 @@ func TestInnerVMRollback(t *testing.T) {
 
 	coreBlk.StatusV = choices.Processing
 
-	proVM = New(coreVM, time.Time{}, 0, false)
+	proVM = New(coreVM, time.Time{}, 0, false, false, ColdStorageConfig{}, nil)
 
 	if err := proVM.Initialize(ctx, dbManager, nil, nil, nil, nil, nil, nil); err != nil {
 		t.Fatalf("failed to initialize proposerVM with %s", err)
@@ -1710,3 +1761,661 @@ func TestLaggedPChainHeight(t *testing.T) {
 	pChainHeight := block.PChainHeight()
 	assert.Equal(pChainHeight, coreGenBlk.Height())
 }
+
+func TestDuplicateIdentityDetection(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	coreVM.HealthCheckF = func() (interface{}, error) { return nil, nil }
+	proVM.stopProposingOnDuplicateIdentity = true
+
+	newSignedBlk := func(innerBytes []byte) *postForkBlock {
+		innerBlk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			BytesV:     innerBytes,
+			ParentV:    coreGenBlk.ID(),
+			HeightV:    coreGenBlk.Height() + 1,
+			TimestampV: coreGenBlk.Timestamp(),
+		}
+		slb, err := statelessblock.Build(
+			coreGenBlk.ID(),
+			coreGenBlk.Timestamp(),
+			defaultPChainHeight,
+			proVM.ctx.StakingCertLeaf,
+			innerBlk.Bytes(),
+			proVM.ctx.ChainID,
+			proVM.ctx.StakingLeafSigner,
+		)
+		assert.NoError(err)
+		return &postForkBlock{
+			SignedBlock: slb,
+			postForkCommonComponents: postForkCommonComponents{
+				vm:       proVM,
+				innerBlk: innerBlk,
+				status:   choices.Processing,
+			},
+		}
+	}
+
+	evidenceStore, err := evidence.NewStore(memdb.New())
+	assert.NoError(err)
+	proVM.ctx.Evidence = evidenceStore
+
+	blk0 := newSignedBlk([]byte{1})
+	blk1 := newSignedBlk([]byte{2})
+	proVM.verifiedBlocks[blk0.ID()] = blk0
+
+	// Seeing the same block multiple times isn't an equivocation.
+	proVM.trackSignedBlock(blk0)
+	proVM.trackSignedBlock(blk0)
+	assert.False(proVM.duplicateIdentity)
+	_, err = proVM.HealthCheck()
+	assert.NoError(err)
+
+	// If this node built both blocks, this node isn't being impersonated.
+	proVM.builtBlocks.Add(blk0.ID(), blk1.ID())
+	proVM.trackSignedBlock(blk1)
+	assert.False(proVM.duplicateIdentity)
+
+	proVM.builtBlocks.Remove(blk1.ID())
+	proVM.trackSignedBlock(blk1)
+	assert.True(proVM.duplicateIdentity)
+
+	_, err = proVM.HealthCheck()
+	assert.Error(err)
+
+	_, err = proVM.BuildBlock()
+	assert.ErrorIs(err, errDuplicateIdentity)
+
+	// Both conflicting blocks are recorded as evidence against the proposer.
+	recorded, err := evidenceStore.GetByNodeID(proVM.ctx.NodeID)
+	assert.NoError(err)
+	assert.NotEmpty(recorded)
+	assert.Equal(evidence.ConflictingBlocks, recorded[0].Type)
+	assert.Equal([][]byte{blk0.Bytes(), blk1.Bytes()}, recorded[0].Containers)
+
+	// Once the parent's children are decided, the slot is no longer tracked.
+	proVM.pruneSignedBlocks(coreGenBlk.ID())
+	assert.Empty(proVM.signedBlocks)
+}
+
+// acceptForkBlock accepts the first post-fork block, so that its children go
+// through the proposer checks.
+func acceptForkBlock(t *testing.T, coreVM *block.TestVM, proVM *VM, coreGenBlk *snowman.TestBlock) (*snowman.TestBlock, snowman.Block) {
+	assert := assert.New(t)
+
+	coreForkBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1},
+		ParentV:    coreGenBlk.ID(),
+		HeightV:    coreGenBlk.Height() + 1,
+		TimestampV: coreGenBlk.Timestamp(),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreForkBlk, nil }
+	coreVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case coreGenBlk.ID():
+			return coreGenBlk, nil
+		case coreForkBlk.ID():
+			return coreForkBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, coreGenBlk.Bytes()):
+			return coreGenBlk, nil
+		case bytes.Equal(b, coreForkBlk.Bytes()):
+			return coreForkBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+
+	forkBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(forkBlk.Verify())
+	assert.NoError(forkBlk.Accept())
+	assert.NoError(proVM.SetPreference(forkBlk.ID()))
+	return coreForkBlk, forkBlk
+}
+
+func TestVerificationIsMemoized(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	coreForkBlk, forkBlk := acceptForkBlock(t, coreVM, proVM, coreGenBlk)
+
+	// The block is signed by a node that isn't a validator, so its proposer
+	// window hasn't started yet
+	tlsCert, err := staking.NewTLSCert()
+	assert.NoError(err)
+	innerBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{2},
+		ParentV:    coreForkBlk.ID(),
+		HeightV:    coreForkBlk.Height() + 1,
+		TimestampV: coreForkBlk.Timestamp(),
+	}
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, coreForkBlk.Bytes()):
+			return coreForkBlk, nil
+		case bytes.Equal(b, innerBlk.Bytes()):
+			return innerBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	slb, err := statelessblock.Build(
+		forkBlk.ID(),
+		forkBlk.Timestamp(),
+		defaultPChainHeight,
+		tlsCert.Leaf,
+		innerBlk.Bytes(),
+		proVM.ctx.ChainID,
+		tlsCert.PrivateKey.(crypto.Signer),
+	)
+	assert.NoError(err)
+
+	blk, err := proVM.ParseBlock(slb.Bytes())
+	assert.NoError(err)
+	assert.ErrorIs(blk.Verify(), errProposerWindowNotStarted)
+
+	// A block that passed verification before a restart skips the proposer
+	// checks
+	assert.NoError(proVM.State.PutVerified(blk.ID(), blk.Height()))
+	assert.NoError(blk.Verify())
+
+	// Decided blocks are no longer recorded
+	assert.NoError(blk.Accept())
+	verified, err := proVM.State.IsVerified(blk.ID())
+	assert.NoError(err)
+	assert.False(verified)
+
+	// Records of blocks at or below the last accepted height are pruned
+	prunedID := ids.GenerateTestID()
+	keptID := ids.GenerateTestID()
+	assert.NoError(proVM.State.PutVerified(prunedID, blk.Height()))
+	assert.NoError(proVM.State.PutVerified(keptID, blk.Height()+1))
+	coreVM.LastAcceptedF = func() (ids.ID, error) { return innerBlk.ID(), nil }
+	coreVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == innerBlk.ID() {
+			return innerBlk, nil
+		}
+		return nil, errUnknownBlock
+	}
+	assert.NoError(proVM.pruneVerified())
+	verified, err = proVM.State.IsVerified(prunedID)
+	assert.NoError(err)
+	assert.False(verified)
+	verified, err = proVM.State.IsVerified(keptID)
+	assert.NoError(err)
+	assert.True(verified)
+}
+
+func TestVerifiedBlocksAreRecorded(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	coreForkBlk, forkBlk := acceptForkBlock(t, coreVM, proVM, coreGenBlk)
+	proVM.Set(forkBlk.Timestamp().Add(proposer.MaxDelay))
+
+	coreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{2},
+		ParentV:    coreForkBlk.ID(),
+		HeightV:    coreForkBlk.Height() + 1,
+		TimestampV: coreForkBlk.Timestamp(),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+
+	blk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(blk.Verify())
+
+	verified, err := proVM.State.IsVerified(blk.ID())
+	assert.NoError(err)
+	assert.True(verified)
+
+	// The record is written to disk with the next commit, rather than when
+	// the block is verified
+	committedState := state.New(versiondb.New(proVM.db.GetDatabase()))
+	verified, err = committedState.IsVerified(blk.ID())
+	assert.NoError(err)
+	assert.False(verified)
+	assert.NoError(proVM.db.Commit())
+	verified, err = committedState.IsVerified(blk.ID())
+	assert.NoError(err)
+	assert.True(verified)
+
+	assert.NoError(blk.Reject())
+	verified, err = proVM.State.IsVerified(blk.ID())
+	assert.NoError(err)
+	assert.False(verified)
+}
+
+// abortableTestVM is a TestVM that supports aborting block builds
+type abortableTestVM struct {
+	*block.TestVM
+	buildBlockWithContextF func(ctx context.Context) (snowman.Block, error)
+}
+
+func (vm *abortableTestVM) BuildBlockWithContext(ctx context.Context) (snowman.Block, error) {
+	return vm.buildBlockWithContextF(ctx)
+}
+
+func TestBuildBlockDeadline(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	now := time.Now()
+	proVM.Set(now)
+
+	coreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(111),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1},
+		ParentV:    coreGenBlk.ID(),
+		HeightV:    coreGenBlk.Height() + 1,
+		TimestampV: coreGenBlk.Timestamp(),
+	}
+	proVM.ChainVM = &abortableTestVM{
+		TestVM: coreVM,
+		buildBlockWithContextF: func(ctx context.Context) (snowman.Block, error) {
+			// The inner block must be built within a proposer window of the
+			// timestamp of the proposer block
+			deadline, ok := ctx.Deadline()
+			assert.True(ok)
+			assert.False(deadline.After(time.Now().Add(maxBuildDelay)))
+			assert.True(deadline.After(time.Now().Add(maxBuildDelay - time.Second - time.Since(now))))
+			return coreBlk, nil
+		},
+	}
+
+	builtBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.Equal(coreBlk, builtBlk.(*postForkBlock).innerBlk)
+}
+
+func TestBuildBlockAborted(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, _, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	proVM.ChainVM = &abortableTestVM{
+		TestVM: coreVM,
+		buildBlockWithContextF: func(ctx context.Context) (snowman.Block, error) {
+			// Shutting down the proposer VM aborts the build
+			proVM.onShutdown()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	_, err := proVM.BuildBlock()
+	assert.ErrorIs(err, context.Canceled)
+	assert.Zero(proVM.builtBlocks.Len())
+}
+
+// scheduledTestVM is a TestVM that records the proposer slots it's notified of
+type scheduledTestVM struct {
+	*block.TestVM
+	parentID  ids.ID
+	slotStart time.Time
+	err       error
+}
+
+func (vm *scheduledTestVM) SetProposerSlot(parentID ids.ID, slotStart time.Time) error {
+	vm.parentID = parentID
+	vm.slotStart = slotStart
+	return vm.err
+}
+
+func TestSetPreferenceNotifiesProposerSlot(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	proVM.Set(coreGenBlk.Timestamp())
+
+	coreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(111),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1},
+		ParentV:    coreGenBlk.ID(),
+		HeightV:    coreGenBlk.Height() + 1,
+		TimestampV: coreGenBlk.Timestamp(),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+	coreVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case coreGenBlk.ID():
+			return coreGenBlk, nil
+		case coreBlk.ID():
+			return coreBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	coreVM.SetPreferenceF = func(ids.ID) error { return nil }
+	scheduledVM := &scheduledTestVM{TestVM: coreVM}
+	proVM.ChainVM = scheduledVM
+
+	builtBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(builtBlk.Verify())
+	assert.NoError(proVM.SetPreference(builtBlk.ID()))
+
+	pChainHeight, err := builtBlk.(*postForkBlock).pChainHeight()
+	assert.NoError(err)
+	delay, err := proVM.Delay(builtBlk.Height()+1, pChainHeight, proVM.ctx.NodeID)
+	assert.NoError(err)
+	assert.Equal(coreBlk.ID(), scheduledVM.parentID)
+	assert.Equal(builtBlk.Timestamp().Add(delay), scheduledVM.slotStart)
+
+	// Failing to notify the inner VM doesn't fail the preference change
+	scheduledVM.err = errors.New("unavailable")
+	assert.NoError(proVM.SetPreference(coreGenBlk.ID()))
+	assert.NoError(proVM.SetPreference(builtBlk.ID()))
+}
+
+func TestHeightIndexBackfilledOnFirstStart(t *testing.T) {
+	assert := assert.New(t)
+
+	// The inner VM accepted a chain before the proposervm was started
+	coreBlks := make([]*snowman.TestBlock, 3)
+	for i := range coreBlks {
+		coreBlks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i)),
+				StatusV: choices.Accepted,
+			},
+			BytesV:     []byte{byte(i)},
+			HeightV:    uint64(i),
+			TimestampV: genesisTimestamp,
+		}
+		if i > 0 {
+			coreBlks[i].ParentV = coreBlks[i-1].ID()
+		}
+	}
+	lastAccepted := coreBlks[len(coreBlks)-1]
+	coreVM := &block.TestVM{
+		TestVM: common.TestVM{
+			T: t,
+		},
+	}
+	coreVM.InitializeF = func(*snow.Context, manager.Manager,
+		[]byte, []byte, []byte, chan<- common.Message,
+		[]*common.Fx, common.AppSender) error {
+		return nil
+	}
+	coreVM.LastAcceptedF = func() (ids.ID, error) { return lastAccepted.ID(), nil }
+	coreVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range coreBlks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+
+	proVM := New(coreVM, mockable.MaxTime, 0, false, false, ColdStorageConfig{}, nil) // disable ProBlks
+	ctx := snow.DefaultContextTest()
+	dbManager := manager.NewMemDB(version.DefaultVersion1_0_0)
+	assert.NoError(proVM.Initialize(ctx, dbManager, nil, nil, nil, nil, nil, nil))
+
+	assert.NoError(proVM.VerifyHeightIndex())
+	for _, blk := range coreBlks {
+		blkID, err := proVM.GetBlockIDAtHeight(blk.Height())
+		assert.NoError(err)
+		assert.Equal(blk.ID(), blkID)
+	}
+
+	// Pre-fork blocks accepted afterwards are indexed as well
+	coreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(uint64(len(coreBlks))),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{byte(len(coreBlks))},
+		ParentV:    lastAccepted.ID(),
+		HeightV:    lastAccepted.Height() + 1,
+		TimestampV: genesisTimestamp,
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+	assert.NoError(proVM.SetPreference(lastAccepted.ID()))
+	builtBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(builtBlk.Verify())
+	assert.NoError(builtBlk.Accept())
+
+	blkID, err := proVM.GetBlockIDAtHeight(coreBlk.Height())
+	assert.NoError(err)
+	assert.Equal(coreBlk.ID(), blkID)
+}
+
+func TestGetWrappingBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	proVM.Set(coreGenBlk.Timestamp())
+
+	coreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(111),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1},
+		ParentV:    coreGenBlk.ID(),
+		HeightV:    coreGenBlk.Height() + 1,
+		TimestampV: coreGenBlk.Timestamp(),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+	coreVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case coreGenBlk.ID():
+			return coreGenBlk, nil
+		case coreBlk.ID():
+			return coreBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, coreGenBlk.Bytes()):
+			return coreGenBlk, nil
+		case bytes.Equal(b, coreBlk.Bytes()):
+			return coreBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+
+	builtBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(builtBlk.Verify())
+
+	// The processing block is found by the ID of its inner block
+	blk, err := proVM.GetWrappingBlock(coreBlk.ID())
+	assert.NoError(err)
+	assert.Equal(builtBlk.ID(), blk.ID())
+
+	// GetBlock always returns the block with the requested ID
+	blk, err = proVM.GetBlock(coreBlk.ID())
+	assert.NoError(err)
+	assert.Equal(coreBlk.ID(), blk.ID())
+
+	// Once accepted, it's found through the height index
+	assert.NoError(builtBlk.Accept())
+	blk, err = proVM.GetWrappingBlock(coreBlk.ID())
+	assert.NoError(err)
+	assert.Equal(builtBlk.ID(), blk.ID())
+
+	// Blocks accepted before the fork aren't wrapped
+	blk, err = proVM.GetWrappingBlock(coreGenBlk.ID())
+	assert.NoError(err)
+	assert.IsType(&preForkBlock{}, blk)
+}
+
+func TestRefuseToSignConflictingBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, valState, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	proVM.Set(coreGenBlk.Timestamp())
+
+	// This node is the only proposer, so its blocks are signed
+	valState.GetValidatorSetF = func(uint64, ids.ID) (map[ids.ShortID]uint64, error) {
+		return map[ids.ShortID]uint64{proVM.ctx.NodeID: 10}, nil
+	}
+
+	coreBlks := make([]*snowman.TestBlock, 3)
+	for i := range coreBlks {
+		parent := snowman.Block(coreGenBlk)
+		if i > 0 {
+			parent = coreBlks[0]
+		}
+		coreBlks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i + 1)),
+				StatusV: choices.Processing,
+			},
+			BytesV:     []byte{byte(i + 1)},
+			ParentV:    parent.ID(),
+			HeightV:    parent.Height() + 1,
+			TimestampV: parent.Timestamp(),
+		}
+	}
+	coreVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == coreGenBlk.ID() {
+			return coreGenBlk, nil
+		}
+		for _, blk := range coreBlks {
+			if blkID == blk.ID() {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range coreBlks {
+			if bytes.Equal(b, blk.Bytes()) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	coreVM.SetPreferenceF = func(ids.ID) error { return nil }
+
+	// The first post-fork block isn't signed
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlks[0], nil }
+	parentBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(parentBlk.Verify())
+	assert.NoError(proVM.SetPreference(parentBlk.ID()))
+
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlks[1], nil }
+	builtBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.Equal(proVM.ctx.NodeID, builtBlk.(*postForkBlock).Proposer())
+
+	signedID, err := proVM.State.GetSigned(builtBlk.Height(), parentBlk.ID())
+	assert.NoError(err)
+	assert.Equal(builtBlk.ID(), signedID)
+
+	// A different block with the same parent must not be signed
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlks[2], nil }
+	_, err = proVM.BuildBlock()
+	assert.ErrorIs(err, errAlreadySigned)
+
+	// Once a block is accepted at that height, the record is pruned
+	assert.NoError(parentBlk.Accept())
+	assert.NoError(builtBlk.Verify())
+	assert.NoError(builtBlk.Accept())
+	_, err = proVM.State.GetSigned(builtBlk.Height(), parentBlk.ID())
+	assert.Equal(database.ErrNotFound, err)
+}
+
+func TestMissingParentOnlyIfNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	parentID := ids.GenerateTestID()
+	err := missingParent(parentID, database.ErrNotFound)
+	assert.ErrorIs(err, snowman.ErrMissingParent)
+
+	// Failures to read the parent aren't reported as the parent being missing
+	err = missingParent(parentID, database.ErrClosed)
+	assert.ErrorIs(err, database.ErrClosed)
+	assert.NotErrorIs(err, snowman.ErrMissingParent)
+}
+
+func TestPreparedBlocksAreParsed(t *testing.T) {
+	assert := assert.New(t)
+
+	coreVM, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	coreForkBlk, forkBlk := acceptForkBlock(t, coreVM, proVM, coreGenBlk)
+
+	innerBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{2},
+		ParentV:    coreForkBlk.ID(),
+		HeightV:    coreForkBlk.Height() + 1,
+		TimestampV: coreForkBlk.Timestamp(),
+	}
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, coreForkBlk.Bytes()):
+			return coreForkBlk, nil
+		case bytes.Equal(b, innerBlk.Bytes()):
+			return innerBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	slb, err := statelessblock.Build(
+		forkBlk.ID(),
+		forkBlk.Timestamp(),
+		defaultPChainHeight,
+		proVM.ctx.StakingCertLeaf,
+		innerBlk.Bytes(),
+		proVM.ctx.ChainID,
+		proVM.ctx.StakingLeafSigner,
+	)
+	assert.NoError(err)
+
+	proVM.PrepareBlock(slb.Bytes())
+	blk, err := proVM.ParseBlock(slb.Bytes())
+	assert.NoError(err)
+	assert.Equal(slb.ID(), blk.ID())
+
+	metric := &dto.Metric{}
+	assert.NoError(proVM.metrics.preparedBlocks.Write(metric))
+	assert.Equal(1.0, metric.GetCounter().GetValue())
+
+	// The prepared block is only used once
+	_, ok := proVM.preparedBlocks.Get(hashing.ComputeHash256Array(slb.Bytes()))
+	assert.False(ok)
+
+	// Blocks that the proposervm can't parse are left to the inner VM
+	proVM.PrepareBlock(innerBlk.Bytes())
+	assert.Zero(proVM.preparedBlocks.Len())
+}
@@ -6,13 +6,16 @@ package registry
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
 )
 
 var _ VMRegisterer = &vmRegisterer{}
@@ -89,7 +92,14 @@ func (r *vmRegisterer) createStaticHandlers(vmID ids.ID, factory vms.Factory) (m
 		return nil, fmt.Errorf("%s doesn't implement VM", vmID)
 	}
 
-	handlers, err := commonVM.CreateStaticHandlers()
+	// Snowman chains are run inside of the ProposerVM, so its static API is
+	// served alongside the VM's
+	handlerVM := commonVM
+	if chainVM, ok := vm.(block.ChainVM); ok {
+		handlerVM = proposervm.New(chainVM, time.Time{}, 0, false, false, proposervm.ColdStorageConfig{}, nil)
+	}
+
+	handlers, err := handlerVM.CreateStaticHandlers()
 	if err != nil {
 		r.config.Log.Error("creating static API endpoints for %q errored with: %s", vmID, err)
 
@@ -98,6 +98,7 @@ func TestRegisterAddRouteFails(t *testing.T) {
 	resources.mockManager.EXPECT().RegisterFactory(id, vmFactory).Times(1).Return(nil)
 	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
 	vm.On("CreateStaticHandlers").Once().Return(handlers, nil)
+	resources.expectProposerVMRoute(false)
 	// We fail to create an endpoint for the handler
 	resources.mockServer.EXPECT().
 		AddRoute(handlers["foo"], gomock.Any(), constants.VMAliasPrefix+id.String(), "foo").
@@ -122,6 +123,7 @@ func TestRegisterAliasLookupFails(t *testing.T) {
 	resources.mockManager.EXPECT().RegisterFactory(id, vmFactory).Times(1).Return(nil)
 	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
 	vm.On("CreateStaticHandlers").Once().Return(handlers, nil)
+	resources.expectProposerVMRoute(false)
 	// Registering the route fails
 	resources.mockServer.EXPECT().
 		AddRoute(handlers["foo"], gomock.Any(), constants.VMAliasPrefix+id.String(), "foo").
@@ -148,6 +150,7 @@ func TestRegisterAddAliasesFails(t *testing.T) {
 	resources.mockManager.EXPECT().RegisterFactory(id, vmFactory).Times(1).Return(nil)
 	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
 	vm.On("CreateStaticHandlers").Once().Return(handlers, nil)
+	resources.expectProposerVMRoute(false)
 	resources.mockServer.EXPECT().
 		AddRoute(handlers["foo"], gomock.Any(), constants.VMAliasPrefix+id.String(), "foo").
 		Times(1).
@@ -177,6 +180,7 @@ func TestRegisterHappyCase(t *testing.T) {
 	resources.mockManager.EXPECT().RegisterFactory(id, vmFactory).Times(1).Return(nil)
 	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
 	vm.On("CreateStaticHandlers").Once().Return(handlers, nil)
+	resources.expectProposerVMRoute(false)
 	resources.mockServer.EXPECT().
 		AddRoute(handlers["foo"], gomock.Any(), constants.VMAliasPrefix+id.String(), "foo").
 		Times(1).
@@ -267,6 +271,7 @@ func TestRegisterWithReadLockAddRouteWithReadLockFails(t *testing.T) {
 	resources.mockManager.EXPECT().RegisterFactory(id, vmFactory).Times(1).Return(nil)
 	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
 	vm.On("CreateStaticHandlers").Once().Return(handlers, nil)
+	resources.expectProposerVMRoute(true)
 	// We fail to create an endpoint for the handler
 	resources.mockServer.EXPECT().
 		AddRouteWithReadLock(handlers["foo"], gomock.Any(), constants.VMAliasPrefix+id.String(), "foo").
@@ -291,6 +296,7 @@ func TestRegisterWithReadLockAliasLookupFails(t *testing.T) {
 	resources.mockManager.EXPECT().RegisterFactory(id, vmFactory).Times(1).Return(nil)
 	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
 	vm.On("CreateStaticHandlers").Once().Return(handlers, nil)
+	resources.expectProposerVMRoute(true)
 	// RegisterWithReadLocking the route fails
 	resources.mockServer.EXPECT().
 		AddRouteWithReadLock(handlers["foo"], gomock.Any(), constants.VMAliasPrefix+id.String(), "foo").
@@ -317,6 +323,7 @@ func TestRegisterWithReadLockAddAliasesFails(t *testing.T) {
 	resources.mockManager.EXPECT().RegisterFactory(id, vmFactory).Times(1).Return(nil)
 	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
 	vm.On("CreateStaticHandlers").Once().Return(handlers, nil)
+	resources.expectProposerVMRoute(true)
 	resources.mockServer.EXPECT().
 		AddRouteWithReadLock(handlers["foo"], gomock.Any(), constants.VMAliasPrefix+id.String(), "foo").
 		Times(1).
@@ -346,6 +353,7 @@ func TestRegisterWithReadLockHappyCase(t *testing.T) {
 	resources.mockManager.EXPECT().RegisterFactory(id, vmFactory).Times(1).Return(nil)
 	vmFactory.EXPECT().New(nil).Times(1).Return(vm, nil)
 	vm.On("CreateStaticHandlers").Once().Return(handlers, nil)
+	resources.expectProposerVMRoute(true)
 	resources.mockServer.EXPECT().
 		AddRouteWithReadLock(handlers["foo"], gomock.Any(), constants.VMAliasPrefix+id.String(), "foo").
 		Times(1).
@@ -367,6 +375,23 @@ type vmRegistererTestResources struct {
 	registerer  VMRegisterer
 }
 
+// expectProposerVMRoute expects the ProposerVM's static API to be added. It
+// may not be added if adding another route fails first.
+func (r *vmRegistererTestResources) expectProposerVMRoute(withReadLock bool) {
+	endpoint := constants.VMAliasPrefix + id.String()
+	if withReadLock {
+		r.mockServer.EXPECT().
+			AddRouteWithReadLock(gomock.Any(), gomock.Any(), endpoint, "/proposervm").
+			MaxTimes(1).
+			Return(nil)
+		return
+	}
+	r.mockServer.EXPECT().
+		AddRoute(gomock.Any(), gomock.Any(), endpoint, "/proposervm").
+		MaxTimes(1).
+		Return(nil)
+}
+
 func initRegistererTest(t *testing.T) *vmRegistererTestResources {
 	ctrl := gomock.NewController(t)
 
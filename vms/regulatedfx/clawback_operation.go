@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var errNilClawbackOperation = errors.New("nil clawback operation")
+
+// ClawbackOperation is signed by the clawback role of an asset. It consumes the
+// roles of the asset and any TransferOutputs and FrozenOutputs, whatever their
+// owners, and produces the same roles and a single TransferOutput of the
+// consumed amount.
+type ClawbackOperation struct {
+	Input  secp256k1fx.Input `serialize:"true" json:"input"`
+	Roles  RolesOutput       `serialize:"true" json:"roles"`
+	Output TransferOutput    `serialize:"true" json:"output"`
+}
+
+func (op *ClawbackOperation) InitCtx(ctx *snow.Context) {
+	op.Roles.InitCtx(ctx)
+	op.Output.InitCtx(ctx)
+}
+
+func (op *ClawbackOperation) Cost() (uint64, error) {
+	return op.Input.Cost()
+}
+
+func (op *ClawbackOperation) Outs() []verify.State {
+	return []verify.State{&op.Roles, &op.Output}
+}
+
+func (op *ClawbackOperation) Verify() error {
+	switch {
+	case op == nil:
+		return errNilClawbackOperation
+	default:
+		return verify.All(&op.Input, &op.Roles, &op.Output)
+	}
+}
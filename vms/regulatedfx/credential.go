@@ -0,0 +1,14 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// Credential signs the inputs and operations of this fx. The signatures of an
+// operation are from the owners of the role that performs it.
+type Credential struct {
+	secp256k1fx.Credential `serialize:"true"`
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errNilFreezeOperation   = errors.New("nil freeze operation")
+	errNilUnfreezeOperation = errors.New("nil unfreeze operation")
+	errNoOutputs            = errors.New("operation has no outputs")
+)
+
+// FreezeOperation is signed by the freezer of an asset. It consumes the roles
+// of the asset and TransferOutputs, and produces the same roles and a
+// FrozenOutput for each consumed TransferOutput, in the order they're consumed.
+type FreezeOperation struct {
+	Input   secp256k1fx.Input `serialize:"true" json:"input"`
+	Roles   RolesOutput       `serialize:"true" json:"roles"`
+	Outputs []*FrozenOutput   `serialize:"true" json:"outputs"`
+}
+
+func (op *FreezeOperation) InitCtx(ctx *snow.Context) {
+	op.Roles.InitCtx(ctx)
+	for _, out := range op.Outputs {
+		out.InitCtx(ctx)
+	}
+}
+
+func (op *FreezeOperation) Cost() (uint64, error) {
+	return op.Input.Cost()
+}
+
+func (op *FreezeOperation) Outs() []verify.State {
+	outs := make([]verify.State, 0, len(op.Outputs)+1)
+	outs = append(outs, &op.Roles)
+	for _, out := range op.Outputs {
+		outs = append(outs, out)
+	}
+	return outs
+}
+
+func (op *FreezeOperation) Verify() error {
+	switch {
+	case op == nil:
+		return errNilFreezeOperation
+	case len(op.Outputs) == 0:
+		return errNoOutputs
+	}
+	if err := verify.All(&op.Input, &op.Roles); err != nil {
+		return err
+	}
+	for _, out := range op.Outputs {
+		if err := out.Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnfreezeOperation is signed by the freezer of an asset. It consumes the
+// roles of the asset and FrozenOutputs, and produces the same roles and a
+// TransferOutput for each consumed FrozenOutput, in the order they're consumed.
+type UnfreezeOperation struct {
+	Input   secp256k1fx.Input `serialize:"true" json:"input"`
+	Roles   RolesOutput       `serialize:"true" json:"roles"`
+	Outputs []*TransferOutput `serialize:"true" json:"outputs"`
+}
+
+func (op *UnfreezeOperation) InitCtx(ctx *snow.Context) {
+	op.Roles.InitCtx(ctx)
+	for _, out := range op.Outputs {
+		out.InitCtx(ctx)
+	}
+}
+
+func (op *UnfreezeOperation) Cost() (uint64, error) {
+	return op.Input.Cost()
+}
+
+func (op *UnfreezeOperation) Outs() []verify.State {
+	outs := make([]verify.State, 0, len(op.Outputs)+1)
+	outs = append(outs, &op.Roles)
+	for _, out := range op.Outputs {
+		outs = append(outs, out)
+	}
+	return outs
+}
+
+func (op *UnfreezeOperation) Verify() error {
+	switch {
+	case op == nil:
+		return errNilUnfreezeOperation
+	case len(op.Outputs) == 0:
+		return errNoOutputs
+	}
+	if err := verify.All(&op.Input, &op.Roles); err != nil {
+		return err
+	}
+	for _, out := range op.Outputs {
+		if err := out.Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
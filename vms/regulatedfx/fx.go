@@ -0,0 +1,233 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	errWrongTxType         = errors.New("wrong tx type")
+	errWrongInputType      = errors.New("wrong input type")
+	errWrongUTXOType       = errors.New("wrong utxo type")
+	errWrongOperationType  = errors.New("wrong operation type")
+	errWrongCredentialType = errors.New("wrong credential type")
+	errWrongNumberOfUTXOs  = errors.New("wrong number of UTXOs for the operation")
+	errWrongNumberOfRoles  = errors.New("operation must consume exactly one roles output")
+	errRolesNotPreserved   = errors.New("operation must produce the roles it consumes")
+	errRoleDisabled        = errors.New("role is disabled")
+	errWrongOutput         = errors.New("produced output doesn't match the consumed output")
+	errWrongAmount         = errors.New("produced amount doesn't match the consumed amount")
+)
+
+// Fx supports regulated assets. Their holders transfer them like secp256k1fx
+// assets, but the roles defined by the RolesOutput of an asset may freeze,
+// unfreeze and claw back the outputs of the asset without the signatures of
+// their owners.
+type Fx struct{ secp256k1fx.Fx }
+
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	log := fx.VM.Logger()
+	log.Debug("initializing regulated fx")
+
+	c := fx.VM.CodecRegistry()
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&TransferInput{}),
+		c.RegisterType(&TransferOutput{}),
+		c.RegisterType(&FrozenOutput{}),
+		c.RegisterType(&RolesOutput{}),
+		c.RegisterType(&FreezeOperation{}),
+		c.RegisterType(&UnfreezeOperation{}),
+		c.RegisterType(&ClawbackOperation{}),
+		c.RegisterType(&SetRolesOperation{}),
+		c.RegisterType(&Credential{}),
+	)
+	return errs.Err
+}
+
+func (fx *Fx) VerifyTransfer(txIntf, inIntf, credIntf, utxoIntf interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	in, ok := inIntf.(*TransferInput)
+	if !ok {
+		return errWrongInputType
+	}
+	cred, ok := credIntf.(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+	// A FrozenOutput isn't a TransferOutput, so it can't be spent
+	out, ok := utxoIntf.(*TransferOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	return fx.VerifySpend(tx, &in.TransferInput, &cred.Credential, &out.TransferOutput)
+}
+
+func (fx *Fx) VerifyOperation(txIntf, opIntf, credIntf interface{}, utxosIntf []interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	cred, ok := credIntf.(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+	if err := verify.All(cred); err != nil {
+		return err
+	}
+	roles, others, err := splitRoles(utxosIntf)
+	if err != nil {
+		return err
+	}
+
+	switch op := opIntf.(type) {
+	case *FreezeOperation:
+		return fx.verifyFreezeOperation(tx, op, cred, roles, others)
+	case *UnfreezeOperation:
+		return fx.verifyUnfreezeOperation(tx, op, cred, roles, others)
+	case *ClawbackOperation:
+		return fx.verifyClawbackOperation(tx, op, cred, roles, others)
+	case *SetRolesOperation:
+		return fx.verifySetRolesOperation(tx, op, cred, roles, others)
+	default:
+		return errWrongOperationType
+	}
+}
+
+func (fx *Fx) verifyFreezeOperation(tx secp256k1fx.Tx, op *FreezeOperation, cred *Credential, roles *RolesOutput, utxos []interface{}) error {
+	if err := verify.All(op); err != nil {
+		return err
+	}
+	if len(utxos) != len(op.Outputs) {
+		return errWrongNumberOfUTXOs
+	}
+	for i, utxoIntf := range utxos {
+		utxo, ok := utxoIntf.(*TransferOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		if !equalOutputs(&utxo.TransferOutput, &op.Outputs[i].TransferOutput) {
+			return fmt.Errorf("%w: output %d", errWrongOutput, i)
+		}
+	}
+	if !op.Roles.Equals(roles) {
+		return errRolesNotPreserved
+	}
+	return fx.verifyRole(tx, &op.Input, cred, &roles.Freezer)
+}
+
+func (fx *Fx) verifyUnfreezeOperation(tx secp256k1fx.Tx, op *UnfreezeOperation, cred *Credential, roles *RolesOutput, utxos []interface{}) error {
+	if err := verify.All(op); err != nil {
+		return err
+	}
+	if len(utxos) != len(op.Outputs) {
+		return errWrongNumberOfUTXOs
+	}
+	for i, utxoIntf := range utxos {
+		utxo, ok := utxoIntf.(*FrozenOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		if !equalOutputs(&utxo.TransferOutput, &op.Outputs[i].TransferOutput) {
+			return fmt.Errorf("%w: output %d", errWrongOutput, i)
+		}
+	}
+	if !op.Roles.Equals(roles) {
+		return errRolesNotPreserved
+	}
+	return fx.verifyRole(tx, &op.Input, cred, &roles.Freezer)
+}
+
+func (fx *Fx) verifyClawbackOperation(tx secp256k1fx.Tx, op *ClawbackOperation, cred *Credential, roles *RolesOutput, utxos []interface{}) error {
+	if err := verify.All(op); err != nil {
+		return err
+	}
+	if len(utxos) == 0 {
+		return errWrongNumberOfUTXOs
+	}
+	amount := uint64(0)
+	for _, utxoIntf := range utxos {
+		var utxo *secp256k1fx.TransferOutput
+		switch out := utxoIntf.(type) {
+		case *TransferOutput:
+			utxo = &out.TransferOutput
+		case *FrozenOutput:
+			utxo = &out.TransferOutput
+		default:
+			return errWrongUTXOType
+		}
+		var err error
+		amount, err = safemath.Add64(amount, utxo.Amt)
+		if err != nil {
+			return err
+		}
+	}
+	if amount != op.Output.Amt {
+		return fmt.Errorf("%w: consumed %d but produced %d", errWrongAmount, amount, op.Output.Amt)
+	}
+	if !op.Roles.Equals(roles) {
+		return errRolesNotPreserved
+	}
+	return fx.verifyRole(tx, &op.Input, cred, &roles.Clawback)
+}
+
+func (fx *Fx) verifySetRolesOperation(tx secp256k1fx.Tx, op *SetRolesOperation, cred *Credential, roles *RolesOutput, utxos []interface{}) error {
+	if err := verify.All(op); err != nil {
+		return err
+	}
+	if len(utxos) != 0 {
+		return errWrongNumberOfUTXOs
+	}
+	return fx.verifyRole(tx, &op.Input, cred, &roles.Admin)
+}
+
+// verifyRole verifies that [cred] is signed by the owners of [role]
+func (fx *Fx) verifyRole(tx secp256k1fx.Tx, in *secp256k1fx.Input, cred *Credential, role *secp256k1fx.OutputOwners) error {
+	if len(role.Addrs) == 0 {
+		return errRoleDisabled
+	}
+	return fx.VerifyCredentials(tx, in, &cred.Credential, role)
+}
+
+// splitRoles returns the single RolesOutput of [utxos] and the other UTXOs, in
+// order
+func splitRoles(utxos []interface{}) (*RolesOutput, []interface{}, error) {
+	var roles *RolesOutput
+	others := make([]interface{}, 0, len(utxos))
+	for _, utxo := range utxos {
+		out, ok := utxo.(*RolesOutput)
+		if !ok {
+			others = append(others, utxo)
+			continue
+		}
+		if roles != nil {
+			return nil, nil, errWrongNumberOfRoles
+		}
+		roles = out
+	}
+	if roles == nil {
+		return nil, nil, errWrongNumberOfRoles
+	}
+	return roles, others, roles.Verify()
+}
+
+// equalOutputs returns true if [a] and [b] have the same amount and owners
+func equalOutputs(a, b *secp256k1fx.TransferOutput) bool {
+	return a.Amt == b.Amt && a.OutputOwners.Equals(&b.OutputOwners)
+}
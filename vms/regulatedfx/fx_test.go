@@ -0,0 +1,261 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var txBytes = []byte{0, 1, 2, 3, 4, 5}
+
+type testRoles struct {
+	admin, freezer, clawback *crypto.PrivateKeySECP256K1R
+	roles                    *RolesOutput
+}
+
+func newTestFx(t *testing.T) *Fx {
+	vm := secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	vm.CLK.Set(time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC))
+
+	fx := &Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+	return fx
+}
+
+func newTestKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key.(*crypto.PrivateKeySECP256K1R)
+}
+
+func newTestRoles(t *testing.T) *testRoles {
+	r := &testRoles{
+		admin:    newTestKey(t),
+		freezer:  newTestKey(t),
+		clawback: newTestKey(t),
+	}
+	r.roles = &RolesOutput{
+		Admin:    owners(r.admin),
+		Freezer:  owners(r.freezer),
+		Clawback: owners(r.clawback),
+	}
+	return r
+}
+
+func owners(key *crypto.PrivateKeySECP256K1R) secp256k1fx.OutputOwners {
+	return secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{key.PublicKey().Address()},
+	}
+}
+
+func sign(t *testing.T, key *crypto.PrivateKeySECP256K1R) *Credential {
+	sig, err := key.Sign(txBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred := &Credential{Credential: secp256k1fx.Credential{
+		Sigs: make([][crypto.SECP256K1RSigLen]byte, 1),
+	}}
+	copy(cred.Sigs[0][:], sig)
+	return cred
+}
+
+func transferOutput(amount uint64, key *crypto.PrivateKeySECP256K1R) secp256k1fx.TransferOutput {
+	return secp256k1fx.TransferOutput{
+		Amt:          amount,
+		OutputOwners: owners(key),
+	}
+}
+
+func TestFxInitialize(t *testing.T) {
+	newTestFx(t)
+
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyTransfer(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	holder := newTestKey(t)
+	in := &TransferInput{TransferInput: secp256k1fx.TransferInput{
+		Amt:   1,
+		Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+	}}
+	cred := sign(t, holder)
+
+	utxo := &TransferOutput{TransferOutput: transferOutput(1, holder)}
+	assert.NoError(fx.VerifyTransfer(tx, in, cred, utxo))
+
+	frozenUTXO := &FrozenOutput{TransferOutput: transferOutput(1, holder)}
+	assert.ErrorIs(fx.VerifyTransfer(tx, in, cred, frozenUTXO), errWrongUTXOType)
+
+	assert.ErrorIs(fx.VerifyTransfer(tx, &secp256k1fx.TransferInput{}, cred, utxo), errWrongInputType)
+}
+
+func TestFxVerifyFreezeOperation(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	r := newTestRoles(t)
+	holder := newTestKey(t)
+	utxos := []interface{}{
+		&TransferOutput{TransferOutput: transferOutput(1, holder)},
+		r.roles,
+		&TransferOutput{TransferOutput: transferOutput(2, holder)},
+	}
+	op := &FreezeOperation{
+		Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		Roles: *r.roles,
+		Outputs: []*FrozenOutput{
+			{TransferOutput: transferOutput(1, holder)},
+			{TransferOutput: transferOutput(2, holder)},
+		},
+	}
+	assert.NoError(fx.VerifyOperation(tx, op, sign(t, r.freezer), utxos))
+
+	// Only the freezer can freeze outputs
+	assert.Error(fx.VerifyOperation(tx, op, sign(t, holder), utxos))
+	assert.Error(fx.VerifyOperation(tx, op, sign(t, r.admin), utxos))
+
+	// The frozen outputs must match the consumed outputs
+	op.Outputs[1].Amt = 3
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.freezer), utxos), errWrongOutput)
+	op.Outputs[1].Amt = 2
+
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.freezer), utxos[:2]), errWrongNumberOfUTXOs)
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.freezer), []interface{}{utxos[0], utxos[2]}), errWrongNumberOfRoles)
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.freezer), append(utxos, r.roles)), errWrongNumberOfRoles)
+
+	// The roles must be preserved
+	op.Roles.Freezer = owners(holder)
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.freezer), utxos), errRolesNotPreserved)
+
+	// A disabled role can't be used
+	r.roles.Freezer = secp256k1fx.OutputOwners{}
+	op.Roles = *r.roles
+	assert.ErrorIs(fx.VerifyOperation(tx, op, &Credential{}, utxos), errRoleDisabled)
+}
+
+func TestFxVerifyUnfreezeOperation(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	r := newTestRoles(t)
+	holder := newTestKey(t)
+	utxos := []interface{}{
+		r.roles,
+		&FrozenOutput{TransferOutput: transferOutput(1, holder)},
+	}
+	op := &UnfreezeOperation{
+		Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		Roles: *r.roles,
+		Outputs: []*TransferOutput{
+			{TransferOutput: transferOutput(1, holder)},
+		},
+	}
+	assert.NoError(fx.VerifyOperation(tx, op, sign(t, r.freezer), utxos))
+	assert.Error(fx.VerifyOperation(tx, op, sign(t, r.clawback), utxos))
+
+	// Outputs that aren't frozen can't be unfrozen
+	utxos[1] = &TransferOutput{TransferOutput: transferOutput(1, holder)}
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.freezer), utxos), errWrongUTXOType)
+}
+
+func TestFxVerifyClawbackOperation(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	r := newTestRoles(t)
+	holder := newTestKey(t)
+	recipient := newTestKey(t)
+	utxos := []interface{}{
+		&TransferOutput{TransferOutput: transferOutput(1, holder)},
+		&FrozenOutput{TransferOutput: transferOutput(2, holder)},
+		r.roles,
+	}
+	op := &ClawbackOperation{
+		Input:  secp256k1fx.Input{SigIndices: []uint32{0}},
+		Roles:  *r.roles,
+		Output: TransferOutput{TransferOutput: transferOutput(3, recipient)},
+	}
+	assert.NoError(fx.VerifyOperation(tx, op, sign(t, r.clawback), utxos))
+	assert.Error(fx.VerifyOperation(tx, op, sign(t, r.freezer), utxos))
+
+	op.Output.Amt = 2
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.clawback), utxos), errWrongAmount)
+
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.clawback), utxos[2:]), errWrongNumberOfUTXOs)
+}
+
+func TestFxVerifySetRolesOperation(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	r := newTestRoles(t)
+	newFreezer := newTestKey(t)
+	utxos := []interface{}{r.roles}
+	op := &SetRolesOperation{
+		Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		Roles: RolesOutput{
+			Admin:   r.roles.Admin,
+			Freezer: owners(newFreezer),
+			// The clawback role is given up
+		},
+	}
+	assert.NoError(fx.VerifyOperation(tx, op, sign(t, r.admin), utxos))
+
+	// Only the admin can set the roles
+	assert.Error(fx.VerifyOperation(tx, op, sign(t, r.freezer), utxos))
+
+	utxos = append(utxos, &TransferOutput{TransferOutput: transferOutput(1, newFreezer)})
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, r.admin), utxos), errWrongNumberOfUTXOs)
+}
+
+func TestFxVerifyOperationWrongTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	r := newTestRoles(t)
+	utxos := []interface{}{r.roles}
+	op := &SetRolesOperation{
+		Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		Roles: *r.roles,
+	}
+	cred := sign(t, r.admin)
+
+	assert.ErrorIs(fx.VerifyOperation(nil, op, cred, utxos), errWrongTxType)
+	assert.ErrorIs(fx.VerifyOperation(tx, op, &secp256k1fx.Credential{}, utxos), errWrongCredentialType)
+	assert.ErrorIs(fx.VerifyOperation(tx, &secp256k1fx.MintOperation{}, cred, utxos), errWrongOperationType)
+}
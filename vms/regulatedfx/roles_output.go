@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errNilRolesOutput = errors.New("nil roles output")
+
+	_ verify.State = &RolesOutput{}
+)
+
+// RolesOutput defines who may regulate an asset. It should be created once,
+// in the initial state of the asset. The operations of the asset consume it
+// and produce it again, so that there is always a single set of roles.
+//
+// A role without addresses is disabled, so that an issuer can give up a role.
+type RolesOutput struct {
+	// Admin may replace the roles of the asset, including its own
+	Admin secp256k1fx.OutputOwners `serialize:"true" json:"admin"`
+	// Freezer may freeze and unfreeze the outputs of the asset
+	Freezer secp256k1fx.OutputOwners `serialize:"true" json:"freezer"`
+	// Clawback may move any output of the asset to new owners
+	Clawback secp256k1fx.OutputOwners `serialize:"true" json:"clawback"`
+}
+
+func (out *RolesOutput) InitCtx(ctx *snow.Context) {
+	out.Admin.InitCtx(ctx)
+	out.Freezer.InitCtx(ctx)
+	out.Clawback.InitCtx(ctx)
+}
+
+// Addresses returns the addresses of all the roles, so that the holders of a
+// role can find the roles of the asset
+func (out *RolesOutput) Addresses() [][]byte {
+	addrs := ids.ShortSet{}
+	addrs.Add(out.Admin.Addrs...)
+	addrs.Add(out.Freezer.Addrs...)
+	addrs.Add(out.Clawback.Addrs...)
+	addrsBytes := make([][]byte, 0, addrs.Len())
+	for _, addr := range addrs.SortedList() {
+		addrsBytes = append(addrsBytes, addr.Bytes())
+	}
+	return addrsBytes
+}
+
+// Equals returns true if [other] defines the same roles
+func (out *RolesOutput) Equals(other *RolesOutput) bool {
+	return out.Admin.Equals(&other.Admin) &&
+		out.Freezer.Equals(&other.Freezer) &&
+		out.Clawback.Equals(&other.Clawback)
+}
+
+func (out *RolesOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilRolesOutput
+	default:
+		return verify.All(&out.Admin, &out.Freezer, &out.Clawback)
+	}
+}
+
+func (out *RolesOutput) VerifyState() error { return out.Verify() }
@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestRolesOutputVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	var nilRoles *RolesOutput
+	assert.ErrorIs(nilRoles.Verify(), errNilRolesOutput)
+
+	// Roles without addresses are disabled, but valid
+	roles := &RolesOutput{}
+	assert.NoError(roles.Verify())
+
+	roles.Freezer = secp256k1fx.OutputOwners{
+		Threshold: 2,
+		Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+	}
+	assert.Error(roles.Verify())
+}
+
+func TestRolesOutputEquals(t *testing.T) {
+	assert := assert.New(t)
+
+	owners := secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+	}
+	roles := &RolesOutput{Admin: owners}
+	assert.True(roles.Equals(&RolesOutput{Admin: owners}))
+	assert.False(roles.Equals(&RolesOutput{Freezer: owners}))
+	assert.False(roles.Equals(&RolesOutput{Admin: owners, Clawback: owners}))
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var errNilSetRolesOperation = errors.New("nil set roles operation")
+
+// SetRolesOperation is signed by the admin of an asset. It consumes the roles
+// of the asset and produces [Roles] in their place.
+type SetRolesOperation struct {
+	Input secp256k1fx.Input `serialize:"true" json:"input"`
+	Roles RolesOutput       `serialize:"true" json:"roles"`
+}
+
+func (op *SetRolesOperation) InitCtx(ctx *snow.Context) {
+	op.Roles.InitCtx(ctx)
+}
+
+func (op *SetRolesOperation) Cost() (uint64, error) {
+	return op.Input.Cost()
+}
+
+func (op *SetRolesOperation) Outs() []verify.State {
+	return []verify.State{&op.Roles}
+}
+
+func (op *SetRolesOperation) Verify() error {
+	switch {
+	case op == nil:
+		return errNilSetRolesOperation
+	default:
+		return verify.All(&op.Input, &op.Roles)
+	}
+}
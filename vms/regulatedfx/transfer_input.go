@@ -0,0 +1,13 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// TransferInput spends a TransferOutput of a regulated asset
+type TransferInput struct {
+	secp256k1fx.TransferInput `serialize:"true"`
+}
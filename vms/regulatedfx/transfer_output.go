@@ -0,0 +1,20 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package regulatedfx
+
+import (
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// TransferOutput is an amount of a regulated asset that its owners can spend,
+// unless it's frozen or clawed back first
+type TransferOutput struct {
+	secp256k1fx.TransferOutput `serialize:"true"`
+}
+
+// FrozenOutput is an amount of a regulated asset that its owners can't spend
+// until the freezer of the asset unfreezes it
+type FrozenOutput struct {
+	secp256k1fx.TransferOutput `serialize:"true"`
+}
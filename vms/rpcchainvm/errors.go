@@ -4,8 +4,16 @@
 package rpcchainvm
 
 import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 var (
@@ -14,12 +22,30 @@ var (
 		2: database.ErrNotFound,
 		3: block.ErrHeightIndexedVMNotImplemented,
 		4: block.ErrIndexIncomplete,
+		5: block.ErrAbortableVMNotImplemented,
 	}
 	errorToErrCode = map[error]uint32{
 		database.ErrClosed:                     1,
 		database.ErrNotFound:                   2,
 		block.ErrHeightIndexedVMNotImplemented: 3,
 		block.ErrIndexIncomplete:               4,
+		block.ErrAbortableVMNotImplemented:     5,
+	}
+
+	// gRPC codes used to carry the class of a verification error across the
+	// plugin boundary
+	errorClassToCode = map[wrappers.ErrorClass]codes.Code{
+		wrappers.FatalError:     codes.Aborted,
+		wrappers.InvalidError:   codes.InvalidArgument,
+		wrappers.TransientError: codes.ResourceExhausted,
+	}
+	codeToErrorClass = map[codes.Code]wrappers.ErrorClass{
+		codes.Aborted:           wrappers.FatalError,
+		codes.InvalidArgument:   wrappers.InvalidError,
+		codes.ResourceExhausted: wrappers.TransientError,
+		// gRPC reports that the plugin is unreachable, such as when it
+		// crashed, as unavailable
+		codes.Unavailable: wrappers.FatalError,
 	}
 )
 
@@ -29,3 +55,37 @@ func errorToRPCError(err error) error {
 	}
 	return err
 }
+
+// verifyErrorToStatus converts an error returned by Verify into a gRPC status
+// error that can be converted back by statusToVerifyError.
+func verifyErrorToStatus(err error) error {
+	if errors.Is(err, snowman.ErrMissingParent) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if code, ok := errorClassToCode[wrappers.Classify(err)]; ok {
+		return status.Error(code, err.Error())
+	}
+	return err
+}
+
+// statusToVerifyError recovers the class of an error returned by Verify in the
+// plugin process.
+func statusToVerifyError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	if st.Code() == codes.NotFound {
+		return fmt.Errorf("%w: %s", snowman.ErrMissingParent, st.Message())
+	}
+	switch codeToErrorClass[st.Code()] {
+	case wrappers.FatalError:
+		return wrappers.NewFatal(err)
+	case wrappers.InvalidError:
+		return wrappers.NewInvalid(err)
+	case wrappers.TransientError:
+		return wrappers.NewTransient(err)
+	default:
+		return err
+	}
+}
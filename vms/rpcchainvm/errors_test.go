@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+func TestVerifyErrorRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, class := range []wrappers.ErrorClass{
+		wrappers.FatalError,
+		wrappers.InvalidError,
+		wrappers.TransientError,
+	} {
+		var err error
+		switch class {
+		case wrappers.FatalError:
+			err = wrappers.NewFatal(errors.New("fatal"))
+		case wrappers.InvalidError:
+			err = wrappers.NewInvalid(errors.New("invalid"))
+		case wrappers.TransientError:
+			err = wrappers.NewTransient(errors.New("transient"))
+		}
+		assert.Equal(class, wrappers.Classify(statusToVerifyError(verifyErrorToStatus(err))), class.String())
+	}
+
+	missingParent := fmt.Errorf("%w: parent", snowman.ErrMissingParent)
+	assert.ErrorIs(statusToVerifyError(verifyErrorToStatus(missingParent)), snowman.ErrMissingParent)
+
+	unclassified := errors.New("unclassified")
+	assert.Equal(wrappers.UnclassifiedError, wrappers.Classify(statusToVerifyError(verifyErrorToStatus(unclassified))))
+
+	// A plugin that can't be reached isn't expected to recover
+	unavailable := status.Error(codes.Unavailable, "transport is closing")
+	assert.Equal(wrappers.FatalError, wrappers.Classify(statusToVerifyError(unavailable)))
+}
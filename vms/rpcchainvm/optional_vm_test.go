@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+
+	vmpb "github.com/ava-labs/avalanchego/proto/pb/vm"
+)
+
+const bufSize = 1024 * 1024
+
+type scheduledTestVM struct {
+	*block.TestVM
+
+	parentID  ids.ID
+	slotStart time.Time
+}
+
+func (vm *scheduledTestVM) SetProposerSlot(parentID ids.ID, slotStart time.Time) error {
+	vm.parentID = parentID
+	vm.slotStart = slotStart
+	return nil
+}
+
+type abortableTestVM struct {
+	*block.TestVM
+
+	buildBlockWithContextF func(context.Context) (snowman.Block, error)
+}
+
+func (vm *abortableTestVM) BuildBlockWithContext(ctx context.Context) (snowman.Block, error) {
+	return vm.buildBlockWithContextF(ctx)
+}
+
+// newTestClient serves [vm] over an in-memory connection and returns a client
+// of it, along with a function that tears them down
+func newTestClient(t *testing.T, vm block.ChainVM) (*VMClient, func()) {
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	vmpb.RegisterVMServer(server, NewServer(vm))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			t.Logf("Server exited with error: %v", err)
+		}
+	}()
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+
+	conn, err := grpc.DialContext(context.Background(), "", dialer, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewClient(vmpb.NewVMClient(conn)), func() {
+		server.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	}
+}
+
+func TestSetProposerSlot(t *testing.T) {
+	assert := assert.New(t)
+
+	vm := &scheduledTestVM{TestVM: &block.TestVM{}}
+	client, stop := newTestClient(t, vm)
+	defer stop()
+
+	parentID := ids.GenerateTestID()
+	slotStart := time.Unix(1000, 0)
+	assert.NoError(client.SetProposerSlot(parentID, slotStart))
+	assert.Equal(parentID, vm.parentID)
+	assert.True(slotStart.Equal(vm.slotStart))
+
+	// VMs that aren't scheduled aren't notified
+	unscheduledClient, unscheduledStop := newTestClient(t, &block.TestVM{})
+	defer unscheduledStop()
+
+	assert.NoError(unscheduledClient.SetProposerSlot(parentID, slotStart))
+}
+
+func TestBuildBlockWithContext(t *testing.T) {
+	assert := assert.New(t)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV:    ids.GenerateTestID(),
+		HeightV:    1,
+		TimestampV: time.Unix(1000, 0),
+		BytesV:     []byte{1},
+	}
+	vm := &abortableTestVM{
+		TestVM: &block.TestVM{},
+		buildBlockWithContextF: func(context.Context) (snowman.Block, error) {
+			return blk, nil
+		},
+	}
+	client, stop := newTestClient(t, vm)
+	defer stop()
+
+	builtBlk, err := client.buildBlockWithContext(context.Background())
+	assert.NoError(err)
+	assert.Equal(blk.ID(), builtBlk.ID())
+	assert.Equal(blk.Parent(), builtBlk.Parent())
+	assert.Equal(blk.Height(), builtBlk.Height())
+	assert.True(blk.Timestamp().Equal(builtBlk.Timestamp()))
+	assert.Equal(blk.Bytes(), builtBlk.Bytes())
+
+	// Builds are aborted once the request is cancelled
+	vm.buildBlockWithContextF = func(ctx context.Context) (snowman.Block, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.buildBlockWithContext(ctx)
+	assert.Error(err)
+	assert.False(client.abortableVMNotImplemented)
+}
+
+func TestBuildBlockWithContextNotImplemented(t *testing.T) {
+	assert := assert.New(t)
+
+	client, stop := newTestClient(t, &block.TestVM{})
+	defer stop()
+
+	_, err := client.buildBlockWithContext(context.Background())
+	assert.ErrorIs(err, block.ErrAbortableVMNotImplemented)
+	assert.True(client.abortableVMNotImplemented)
+
+	// The plugin isn't asked again
+	stop()
+	_, err = client.buildBlockWithContext(context.Background())
+	assert.ErrorIs(err, block.ErrAbortableVMNotImplemented)
+}
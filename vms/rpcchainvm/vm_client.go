@@ -16,7 +16,9 @@ import (
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
@@ -59,6 +61,8 @@ var (
 	_ block.ChainVM              = &VMClient{}
 	_ block.BatchedChainVM       = &VMClient{}
 	_ block.HeightIndexedChainVM = &VMClient{}
+	_ block.AbortableChainVM     = &VMClient{}
+	_ block.ScheduledChainVM     = &VMClient{}
 )
 
 const (
@@ -88,6 +92,9 @@ type VMClient struct {
 
 	grpcServerMetrics *grpc_prometheus.ServerMetrics
 
+	// true once the plugin reported that it can't abort builds
+	abortableVMNotImplemented bool
+
 	ctx *snow.Context
 }
 
@@ -234,6 +241,8 @@ func (vm *VMClient) Initialize(
 			GetBlock:            vm.getBlock,
 			UnmarshalBlock:      vm.parseBlock,
 			BuildBlock:          vm.buildBlock,
+
+			BuildBlockWithContext: vm.buildBlockWithContext,
 		},
 	)
 	if err != nil {
@@ -387,7 +396,33 @@ func (vm *VMClient) buildBlock() (snowman.Block, error) {
 	if err != nil {
 		return nil, err
 	}
+	return vm.newBuiltBlock(resp)
+}
+
+// buildBlockWithContext builds a block in the plugin, which aborts the build
+// once [ctx] is done. Plugins that can't abort builds are only asked once.
+func (vm *VMClient) buildBlockWithContext(ctx context.Context) (snowman.Block, error) {
+	if vm.abortableVMNotImplemented {
+		return nil, block.ErrAbortableVMNotImplemented
+	}
 
+	resp, err := vm.client.BuildBlockWithContext(ctx, &emptypb.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		// The plugin predates BuildBlockWithContext
+		err = block.ErrAbortableVMNotImplemented
+	} else if err == nil && resp.Err != 0 {
+		err = errCodeToError[resp.Err]
+	}
+	if err == block.ErrAbortableVMNotImplemented {
+		vm.abortableVMNotImplemented = true
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vm.newBuiltBlock(resp)
+}
+
+func (vm *VMClient) newBuiltBlock(resp *vmpb.BuildBlockResponse) (snowman.Block, error) {
 	id, err := ids.ToID(resp.Id)
 	if err != nil {
 		return nil, err
@@ -498,6 +533,22 @@ func (vm *VMClient) SetPreference(id ids.ID) error {
 	return err
 }
 
+func (vm *VMClient) SetProposerSlot(parentID ids.ID, slotStart time.Time) error {
+	slotStartBytes, err := slotStart.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	_, err = vm.client.SetProposerSlot(context.Background(), &vmpb.SetProposerSlotRequest{
+		ParentId:  parentID[:],
+		SlotStart: slotStartBytes,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		// The plugin predates SetProposerSlot, so it can't be notified
+		return nil
+	}
+	return err
+}
+
 func (vm *VMClient) HealthCheck() (interface{}, error) {
 	resp, err := vm.client.Health(context.Background(), &vmpb.HealthRequest{
 		GrpcChecks: vm.grpcHealthChecks,
@@ -718,7 +769,7 @@ func (b *BlockClient) Verify() error {
 		Bytes: b.bytes,
 	})
 	if err != nil {
-		return err
+		return statusToVerifyError(err)
 	}
 	return b.time.UnmarshalBinary(resp.Timestamp)
 }
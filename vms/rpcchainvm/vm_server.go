@@ -23,6 +23,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids/galiasreader"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/common/appsender"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
@@ -330,6 +331,31 @@ func (vm *VMServer) BuildBlock(context.Context, *emptypb.Empty) (*vmpb.BuildBloc
 	if err != nil {
 		return nil, err
 	}
+	return newBuildBlockResponse(blk)
+}
+
+// BuildBlockWithContext builds a block, aborting the build once the client
+// cancels the request, if the VM supports it
+func (vm *VMServer) BuildBlockWithContext(ctx context.Context, _ *emptypb.Empty) (*vmpb.BuildBlockResponse, error) {
+	aVM, ok := vm.vm.(block.AbortableChainVM)
+	if !ok {
+		return &vmpb.BuildBlockResponse{
+			Err: errorToErrCode[block.ErrAbortableVMNotImplemented],
+		}, nil
+	}
+	blk, err := aVM.BuildBlockWithContext(ctx)
+	if err == block.ErrAbortableVMNotImplemented {
+		return &vmpb.BuildBlockResponse{
+			Err: errorToErrCode[err],
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newBuildBlockResponse(blk)
+}
+
+func newBuildBlockResponse(blk snowman.Block) (*vmpb.BuildBlockResponse, error) {
 	blkID := blk.ID()
 	parentID := blk.Parent()
 	timeBytes, err := blk.Timestamp().MarshalBinary()
@@ -427,6 +453,22 @@ func (vm *VMServer) SetPreference(_ context.Context, req *vmpb.SetPreferenceRequ
 	return &emptypb.Empty{}, vm.vm.SetPreference(id)
 }
 
+func (vm *VMServer) SetProposerSlot(_ context.Context, req *vmpb.SetProposerSlotRequest) (*emptypb.Empty, error) {
+	sVM, ok := vm.vm.(block.ScheduledChainVM)
+	if !ok {
+		return &emptypb.Empty{}, nil
+	}
+	parentID, err := ids.ToID(req.ParentId)
+	if err != nil {
+		return nil, err
+	}
+	slotStart := time.Time{}
+	if err := slotStart.UnmarshalBinary(req.SlotStart); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, sVM.SetProposerSlot(parentID, slotStart)
+}
+
 func (vm *VMServer) Health(ctx context.Context, req *vmpb.HealthRequest) (*vmpb.HealthResponse, error) {
 	// Perform health checks for vm client gRPC servers
 	err := vm.grpcHealthChecks(ctx, req.GrpcChecks)
@@ -552,7 +594,7 @@ func (vm *VMServer) BlockVerify(_ context.Context, req *vmpb.BlockVerifyRequest)
 		return nil, err
 	}
 	if err := blk.Verify(); err != nil {
-		return nil, err
+		return nil, verifyErrorToStatus(err)
 	}
 	timeBytes, err := blk.Timestamp().MarshalBinary()
 	return &vmpb.BlockVerifyResponse{
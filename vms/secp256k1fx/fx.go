@@ -7,17 +7,12 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 )
 
-const (
-	defaultCacheSize = 256
-)
-
 var (
 	errWrongVMType                    = errors.New("wrong vm type")
 	errWrongTxType                    = errors.New("wrong tx type")
@@ -51,7 +46,7 @@ func (fx *Fx) Initialize(vmIntf interface{}) error {
 	log.Debug("initializing secp561k1 fx")
 
 	fx.SECPFactory = crypto.FactorySECP256K1R{
-		Cache: cache.LRU{Size: defaultCacheSize},
+		SharedCache: crypto.RecoverCache,
 	}
 	c := fx.VM.CodecRegistry()
 	errs := wrappers.Errs{}
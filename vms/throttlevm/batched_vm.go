@@ -0,0 +1,52 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttlevm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.BatchedChainVM = &blockVM{}
+
+func (vm *blockVM) GetAncestors(
+	blkID ids.ID,
+	maxBlocksNum int,
+	maxBlocksSize int,
+	maxBlocksRetrivalTime time.Duration,
+) ([][]byte, error) {
+	rVM, ok := vm.ChainVM.(block.BatchedChainVM)
+	if !ok {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+	return rVM.GetAncestors(
+		blkID,
+		maxBlocksNum,
+		maxBlocksSize,
+		maxBlocksRetrivalTime,
+	)
+}
+
+func (vm *blockVM) BatchedParseBlock(blks [][]byte) ([]snowman.Block, error) {
+	rVM, ok := vm.ChainVM.(block.BatchedChainVM)
+	if !ok {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+
+	start := vm.throttle()
+	blocks, err := rVM.BatchedParseBlock(blks)
+	vm.meter(start)
+
+	wrappedBlocks := make([]snowman.Block, len(blocks))
+	for i, block := range blocks {
+		wrappedBlocks[i] = &throttledBlock{
+			Block: block,
+			vm:    vm,
+		}
+	}
+	return wrappedBlocks, err
+}
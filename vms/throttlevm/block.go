@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttlevm
+
+import "github.com/ava-labs/avalanchego/snow/consensus/snowman"
+
+var (
+	_ snowman.Block       = &throttledBlock{}
+	_ snowman.OracleBlock = &throttledBlock{}
+)
+
+type throttledBlock struct {
+	snowman.Block
+
+	vm *blockVM
+}
+
+func (tb *throttledBlock) Verify() error {
+	start := tb.vm.throttle()
+	err := tb.Block.Verify()
+	tb.vm.meter(start)
+	return err
+}
+
+func (tb *throttledBlock) Options() ([2]snowman.Block, error) {
+	oracleBlock, ok := tb.Block.(snowman.OracleBlock)
+	if !ok {
+		return [2]snowman.Block{}, snowman.ErrNotOracle
+	}
+
+	blks, err := oracleBlock.Options()
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+	return [2]snowman.Block{
+		&throttledBlock{
+			Block: blks[0],
+			vm:    tb.vm,
+		},
+		&throttledBlock{
+			Block: blks[1],
+			vm:    tb.vm,
+		},
+	}, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttlevm
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var _ block.ChainVM = &blockVM{}
+
+// NewBlockVM returns a VM that accounts for the time [vm] spends building,
+// parsing and verifying blocks, and delays these calls once [vm] used up its
+// share of the CPU. Since the calls are made with the chain's lock held, only
+// the chain of [vm] is slowed down, and the handlers of the other chains keep
+// their share of the CPU.
+func NewBlockVM(vm block.ChainVM, config Config) block.ChainVM {
+	return &blockVM{
+		ChainVM: vm,
+		bucket:  newCPUBucket(config),
+	}
+}
+
+type blockVM struct {
+	block.ChainVM
+	bucket *cpuBucket
+
+	throttled     prometheus.Counter
+	throttledTime prometheus.Counter
+}
+
+func (vm *blockVM) Initialize(
+	ctx *snow.Context,
+	db manager.Manager,
+	genesisBytes,
+	upgradeBytes,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+	appSender common.AppSender,
+) error {
+	vm.throttled = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "throttled",
+		Help: "Number of VM calls that were delayed because the VM used up its share of the CPU",
+	})
+	vm.throttledTime = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "throttled_time",
+		Help: "Time (in ns) the VM calls were delayed for because the VM used up its share of the CPU",
+	})
+	registerer := prometheus.NewRegistry()
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(vm.throttled),
+		registerer.Register(vm.throttledTime),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+
+	optionalGatherer := metrics.NewOptionalGatherer()
+	multiGatherer := metrics.NewMultiGatherer()
+	if err := multiGatherer.Register("throttlevm", registerer); err != nil {
+		return err
+	}
+	if err := multiGatherer.Register("", optionalGatherer); err != nil {
+		return err
+	}
+	if err := ctx.Metrics.Register(multiGatherer); err != nil {
+		return err
+	}
+	ctx.Metrics = optionalGatherer
+
+	return vm.ChainVM.Initialize(ctx, db, genesisBytes, upgradeBytes, configBytes, toEngine, fxs, appSender)
+}
+
+// throttle waits for the VM to have CPU time left, and returns the time the
+// call starts at
+func (vm *blockVM) throttle() time.Time {
+	if delay := vm.bucket.wait(); delay > 0 {
+		vm.throttled.Inc()
+		vm.throttledTime.Add(float64(delay))
+	}
+	return vm.bucket.clock.Time()
+}
+
+// meter charges the VM for the call that started at [start]
+func (vm *blockVM) meter(start time.Time) {
+	vm.bucket.consume(vm.bucket.clock.Time().Sub(start))
+}
+
+func (vm *blockVM) BuildBlock() (snowman.Block, error) {
+	start := vm.throttle()
+	blk, err := vm.ChainVM.BuildBlock()
+	vm.meter(start)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledBlock{
+		Block: blk,
+		vm:    vm,
+	}, nil
+}
+
+func (vm *blockVM) ParseBlock(b []byte) (snowman.Block, error) {
+	start := vm.throttle()
+	blk, err := vm.ChainVM.ParseBlock(b)
+	vm.meter(start)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledBlock{
+		Block: blk,
+		vm:    vm,
+	}, nil
+}
+
+func (vm *blockVM) GetBlock(blkID ids.ID) (snowman.Block, error) {
+	blk, err := vm.ChainVM.GetBlock(blkID)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledBlock{
+		Block: blk,
+		vm:    vm,
+	}, nil
+}
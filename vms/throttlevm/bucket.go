@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttlevm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+// Config of the CPU throttler of a chain's VM
+type Config struct {
+	// CPUPortion is the portion of a CPU core the VM may spend in the
+	// throttled calls on average. If 0, the VM isn't throttled.
+	CPUPortion float64 `json:"cpuPortion"`
+
+	// MaxBurst is the CPU time the VM may spend in the throttled calls at once
+	// after it was idle
+	MaxBurst time.Duration `json:"maxBurst"`
+}
+
+// Enabled returns true if VMs should be throttled
+func (c Config) Enabled() bool {
+	return c.CPUPortion > 0
+}
+
+// cpuBucket is a token bucket of CPU time. It refills at [rate] seconds of CPU
+// time per second, up to [maxBurst]. The calls that are metered are allowed
+// to overdraw it, and the next call waits until it's refilled.
+type cpuBucket struct {
+	clock mockable.Clock
+	// sleep is replaced in tests
+	sleep func(time.Duration)
+
+	rate     float64
+	maxBurst time.Duration
+
+	lock       sync.Mutex
+	tokens     time.Duration
+	lastRefill time.Time
+}
+
+func newCPUBucket(config Config) *cpuBucket {
+	b := &cpuBucket{
+		sleep:    time.Sleep,
+		rate:     config.CPUPortion,
+		maxBurst: config.MaxBurst,
+		tokens:   config.MaxBurst,
+	}
+	b.lastRefill = b.clock.Time()
+	return b
+}
+
+// refill assumes [b.lock] is held
+func (b *cpuBucket) refill() {
+	now := b.clock.Time()
+	elapsed := now.Sub(b.lastRefill)
+	b.lastRefill = now
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens += time.Duration(float64(elapsed) * b.rate)
+	if b.tokens > b.maxBurst {
+		b.tokens = b.maxBurst
+	}
+}
+
+// wait blocks until the bucket is no longer overdrawn, and returns the time it
+// waited
+func (b *cpuBucket) wait() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refill()
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	// Waiting with the lock held makes the concurrent callers wait in turn
+	delay := time.Duration(float64(-b.tokens)/b.rate) + 1
+	b.sleep(delay)
+	b.refill()
+	return delay
+}
+
+// consume removes [duration] of CPU time from the bucket
+func (b *cpuBucket) consume(duration time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refill()
+	b.tokens -= duration
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttlevm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBucket(config Config) *cpuBucket {
+	b := newCPUBucket(config)
+	now := time.Unix(0, 0)
+	b.clock.Set(now)
+	b.lastRefill = now
+	b.sleep = func(d time.Duration) {
+		now = now.Add(d)
+		b.clock.Set(now)
+	}
+	return b
+}
+
+func TestCPUBucket(t *testing.T) {
+	assert := assert.New(t)
+
+	b := newTestBucket(Config{
+		CPUPortion: 0.5,
+		MaxBurst:   time.Second,
+	})
+
+	// The bucket starts full
+	assert.Zero(b.wait())
+	b.consume(500 * time.Millisecond)
+	assert.Zero(b.wait())
+
+	// Overdrawing the bucket delays the next call until it's refilled
+	b.consume(time.Second)
+	assert.Equal(time.Second+1, b.wait())
+	assert.Zero(b.wait())
+
+	// The bucket doesn't refill past the max burst
+	b.clock.Set(b.clock.Time().Add(time.Hour))
+	b.consume(2 * time.Second)
+	assert.Equal(2*time.Second+1, b.wait())
+}
+
+func TestConfigEnabled(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(Config{}.Enabled())
+	assert.False(Config{MaxBurst: time.Second}.Enabled())
+	assert.True(Config{CPUPortion: 0.25}.Enabled())
+}
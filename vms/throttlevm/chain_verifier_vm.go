@@ -0,0 +1,31 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttlevm
+
+import (
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.ChainVerifierVM = &blockVM{}
+
+func (vm *blockVM) VerifyChain(blks []snowman.Block) error {
+	cVM, ok := vm.ChainVM.(block.ChainVerifierVM)
+	if !ok {
+		return block.ErrChainVerifierVMNotImplemented
+	}
+
+	innerBlks := make([]snowman.Block, len(blks))
+	for i, blk := range blks {
+		if throttledBlk, ok := blk.(*throttledBlock); ok {
+			blk = throttledBlk.Block
+		}
+		innerBlks[i] = blk
+	}
+
+	start := vm.throttle()
+	err := cVM.VerifyChain(innerBlks)
+	vm.meter(start)
+	return err
+}
@@ -0,0 +1,18 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttlevm
+
+import (
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ block.PreparingChainVM = &blockVM{}
+
+// PrepareBlock is charged to the VM like ParseBlock is, but doesn't wait for
+// the VM to have CPU time left. The next throttled call waits instead.
+func (vm *blockVM) PrepareBlock(blkBytes []byte) {
+	start := vm.bucket.clock.Time()
+	block.PrepareBlock(vm.ChainVM, blkBytes)
+	vm.meter(start)
+}
@@ -0,0 +1,14 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vestingfx
+
+import (
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// Credential signs the operations of this fx. The signatures of a release are
+// from the owners of the vesting position.
+type Credential struct {
+	secp256k1fx.Credential `serialize:"true"`
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vestingfx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errWrongTxType         = errors.New("wrong tx type")
+	errWrongUTXOType       = errors.New("wrong utxo type")
+	errWrongOperationType  = errors.New("wrong operation type")
+	errWrongCredentialType = errors.New("wrong credential type")
+	errWrongNumberOfUTXOs  = errors.New("wrong number of UTXOs for the operation")
+	errCantTransfer        = errors.New("vesting outputs can't be transferred, only released")
+	errNotUnlocked         = errors.New("released amount isn't unlocked")
+	errWrongRemaining      = errors.New("remaining vesting output doesn't match the consumed output")
+)
+
+// Fx supports vesting positions. A VestingOutput unlocks its amount following
+// its schedule, and its owners release the unlocked amount into secp256k1fx
+// TransferOutputs. The schedule is checked against the time of the chain when
+// the release is verified.
+type Fx struct{ secp256k1fx.Fx }
+
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	log := fx.VM.Logger()
+	log.Debug("initializing vesting fx")
+
+	c := fx.VM.CodecRegistry()
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&VestingOutput{}),
+		c.RegisterType(&ReleaseOperation{}),
+		c.RegisterType(&Credential{}),
+	)
+	return errs.Err
+}
+
+func (fx *Fx) VerifyTransfer(_, _, _, _ interface{}) error { return errCantTransfer }
+
+func (fx *Fx) VerifyOperation(txIntf, opIntf, credIntf interface{}, utxosIntf []interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	op, ok := opIntf.(*ReleaseOperation)
+	if !ok {
+		return errWrongOperationType
+	}
+	cred, ok := credIntf.(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+	if len(utxosIntf) != 1 {
+		return errWrongNumberOfUTXOs
+	}
+	out, ok := utxosIntf[0].(*VestingOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	if err := verify.All(op, cred, out); err != nil {
+		return err
+	}
+
+	unlocked := out.Unlocked(fx.VM.Clock().Unix())
+	if op.Output.Amt > unlocked {
+		return fmt.Errorf("%w: releasing %d but %d is unlocked", errNotUnlocked, op.Output.Amt, unlocked)
+	}
+	// [unlocked] is at most [out.Amt - out.Released], so this can't overflow
+	released := out.Released + op.Output.Amt
+	if released == out.Amt {
+		if len(op.Remaining) != 0 {
+			return errWrongRemaining
+		}
+	} else if len(op.Remaining) != 1 || !equalRemaining(out, op.Remaining[0], released) {
+		return errWrongRemaining
+	}
+	return fx.VerifyCredentials(tx, &op.Input, &cred.Credential, &out.OutputOwners)
+}
+
+// equalRemaining returns true if [remaining] is [out] with [released] of its
+// amount released
+func equalRemaining(out, remaining *VestingOutput, released uint64) bool {
+	return remaining.Schedule == out.Schedule &&
+		remaining.Amt == out.Amt &&
+		remaining.Released == released &&
+		remaining.OutputOwners.Equals(&out.OutputOwners)
+}
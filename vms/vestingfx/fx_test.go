@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vestingfx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	txBytes = []byte{0, 1, 2, 3, 4, 5}
+	now     = time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+)
+
+func newTestFx(t *testing.T) *Fx {
+	vm := secp256k1fx.TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	vm.CLK.Set(now)
+
+	fx := &Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+	return fx
+}
+
+func newTestKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key.(*crypto.PrivateKeySECP256K1R)
+}
+
+func owners(key *crypto.PrivateKeySECP256K1R) secp256k1fx.OutputOwners {
+	return secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{key.PublicKey().Address()},
+	}
+}
+
+func sign(t *testing.T, key *crypto.PrivateKeySECP256K1R) *Credential {
+	sig, err := key.Sign(txBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred := &Credential{Credential: secp256k1fx.Credential{
+		Sigs: make([][crypto.SECP256K1RSigLen]byte, 1),
+	}}
+	copy(cred.Sigs[0][:], sig)
+	return cred
+}
+
+func TestFxInitialize(t *testing.T) {
+	newTestFx(t)
+
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyTransfer(t *testing.T) {
+	fx := newTestFx(t)
+	assert.ErrorIs(t, fx.VerifyTransfer(nil, nil, nil, nil), errCantTransfer)
+}
+
+func TestFxVerifyReleaseOperation(t *testing.T) {
+	assert := assert.New(t)
+
+	fx := newTestFx(t)
+	tx := &secp256k1fx.TestTx{Bytes: txBytes}
+	holder := newTestKey(t)
+	unix := uint64(now.Unix())
+
+	// Half of the position is unlocked
+	utxo := &VestingOutput{
+		Schedule: Schedule{
+			Start: unix - 100,
+			Cliff: unix - 50,
+			End:   unix + 100,
+		},
+		Amt:          1000,
+		Released:     200,
+		OutputOwners: owners(holder),
+	}
+	utxos := []interface{}{utxo}
+	remaining := *utxo
+	remaining.Released = 500
+	op := &ReleaseOperation{
+		Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		Output: secp256k1fx.TransferOutput{
+			Amt:          300,
+			OutputOwners: owners(newTestKey(t)),
+		},
+		Remaining: []*VestingOutput{&remaining},
+	}
+	assert.NoError(fx.VerifyOperation(tx, op, sign(t, holder), utxos))
+
+	// Only the owners of the position can release it
+	assert.Error(fx.VerifyOperation(tx, op, sign(t, newTestKey(t)), utxos))
+
+	// Only the unlocked amount can be released
+	op.Output.Amt = 301
+	remaining.Released = 501
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, holder), utxos), errNotUnlocked)
+
+	// The remaining position must keep the schedule, amount and owners of the
+	// consumed position
+	op.Output.Amt = 100
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, holder), utxos), errWrongRemaining)
+	remaining.Released = 300
+	assert.NoError(fx.VerifyOperation(tx, op, sign(t, holder), utxos))
+	remaining.Schedule.End++
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, holder), utxos), errWrongRemaining)
+	remaining.Schedule.End--
+	remaining.OutputOwners = owners(newTestKey(t))
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, holder), utxos), errWrongRemaining)
+	remaining.OutputOwners = owners(holder)
+
+	op.Remaining = nil
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, holder), utxos), errWrongRemaining)
+
+	// Releasing all of the position leaves nothing to vest
+	utxo.Schedule.End = unix
+	utxo.Released = 700
+	op.Output.Amt = 300
+	assert.NoError(fx.VerifyOperation(tx, op, sign(t, holder), utxos))
+	op.Remaining = []*VestingOutput{&remaining}
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, holder), utxos), errWrongRemaining)
+
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, holder), nil), errWrongNumberOfUTXOs)
+	assert.ErrorIs(fx.VerifyOperation(tx, op, sign(t, holder), []interface{}{&secp256k1fx.TransferOutput{}}), errWrongUTXOType)
+	assert.ErrorIs(fx.VerifyOperation(tx, &secp256k1fx.MintOperation{}, sign(t, holder), utxos), errWrongOperationType)
+	assert.ErrorIs(fx.VerifyOperation(tx, op, &secp256k1fx.Credential{}, utxos), errWrongCredentialType)
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vestingfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errNilReleaseOperation = errors.New("nil release operation")
+	errTooManyRemaining    = errors.New("release operation can produce at most one vesting output")
+)
+
+// ReleaseOperation is signed by the owners of a VestingOutput. It consumes the
+// VestingOutput, and produces a TransferOutput of an amount that is unlocked
+// and the VestingOutput of the amount left to release, if any.
+type ReleaseOperation struct {
+	Input  secp256k1fx.Input          `serialize:"true" json:"input"`
+	Output secp256k1fx.TransferOutput `serialize:"true" json:"output"`
+	// Remaining is empty if the operation releases all of the position
+	Remaining []*VestingOutput `serialize:"true" json:"remaining"`
+}
+
+func (op *ReleaseOperation) InitCtx(ctx *snow.Context) {
+	op.Output.InitCtx(ctx)
+	for _, out := range op.Remaining {
+		out.InitCtx(ctx)
+	}
+}
+
+func (op *ReleaseOperation) Cost() (uint64, error) {
+	return op.Input.Cost()
+}
+
+func (op *ReleaseOperation) Outs() []verify.State {
+	outs := []verify.State{&op.Output}
+	for _, out := range op.Remaining {
+		outs = append(outs, out)
+	}
+	return outs
+}
+
+func (op *ReleaseOperation) Verify() error {
+	switch {
+	case op == nil:
+		return errNilReleaseOperation
+	case len(op.Remaining) > 1:
+		return errTooManyRemaining
+	}
+	if err := verify.All(&op.Input, &op.Output); err != nil {
+		return err
+	}
+	for _, out := range op.Remaining {
+		if err := out.Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vestingfx
+
+import (
+	"encoding/json"
+	"errors"
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errNilVestingOutput   = errors.New("nil vesting output")
+	errNoValueOutput      = errors.New("output has no value")
+	errAllReleased        = errors.New("vesting output has nothing left to release")
+	errScheduleNotOrdered = errors.New("vesting schedule must start before its cliff and end after it")
+
+	_ verify.State = &VestingOutput{}
+)
+
+// Schedule unlocks an amount linearly between Start and End. Nothing is
+// unlocked before Cliff, and the amount that vested by then is unlocked at
+// Cliff. The times are Unix timestamps, in seconds.
+type Schedule struct {
+	Start uint64 `serialize:"true" json:"start"`
+	Cliff uint64 `serialize:"true" json:"cliff"`
+	End   uint64 `serialize:"true" json:"end"`
+}
+
+// Vested returns how much of [amount] is unlocked at [time]
+func (s *Schedule) Vested(amount, time uint64) uint64 {
+	switch {
+	case time < s.Cliff:
+		return 0
+	case time >= s.End:
+		return amount
+	}
+	// [time] is in [Start, End), so the quotient is less than [amount] and
+	// fits in 64 bits
+	hi, lo := bits.Mul64(amount, time-s.Start)
+	vested, _ := bits.Div64(hi, lo, s.End-s.Start)
+	return vested
+}
+
+func (s *Schedule) Verify() error {
+	if s.Start > s.Cliff || s.Cliff > s.End {
+		return errScheduleNotOrdered
+	}
+	return nil
+}
+
+// VestingOutput is a position of Amt units of an asset that unlock following
+// its Schedule. Releasing the unlocked units produces a secp256k1fx
+// TransferOutput of them, and a VestingOutput of the units left to release.
+type VestingOutput struct {
+	Schedule Schedule `serialize:"true" json:"schedule"`
+	// Amt is the amount of the position, released or not
+	Amt uint64 `serialize:"true" json:"amount"`
+	// Released is the amount of the position that was released
+	Released uint64 `serialize:"true" json:"released"`
+
+	// OutputOwners may release the unlocked amount
+	secp256k1fx.OutputOwners `serialize:"true"`
+}
+
+// MarshalJSON marshals the schedule, the amounts and the embedded
+// OutputOwners struct into a JSON readable format
+func (out *VestingOutput) MarshalJSON() ([]byte, error) {
+	result, err := out.OutputOwners.Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	result["schedule"] = out.Schedule
+	result["amount"] = out.Amt
+	result["released"] = out.Released
+	return json.Marshal(result)
+}
+
+// Unlocked returns how much of the position may be released at [time]
+func (out *VestingOutput) Unlocked(time uint64) uint64 {
+	vested := out.Schedule.Vested(out.Amt, time)
+	if vested < out.Released {
+		return 0
+	}
+	return vested - out.Released
+}
+
+func (out *VestingOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilVestingOutput
+	case out.Amt == 0:
+		return errNoValueOutput
+	case out.Released >= out.Amt:
+		return errAllReleased
+	default:
+		return verify.All(&out.Schedule, &out.OutputOwners)
+	}
+}
+
+func (out *VestingOutput) VerifyState() error { return out.Verify() }
+
+func (out *VestingOutput) Owners() interface{} { return &out.OutputOwners }
@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vestingfx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestScheduleVested(t *testing.T) {
+	assert := assert.New(t)
+
+	s := Schedule{
+		Start: 100,
+		Cliff: 150,
+		End:   200,
+	}
+	for time, expected := range map[uint64]uint64{
+		0:   0,
+		149: 0,
+		150: 500,
+		175: 750,
+		199: 990,
+		200: 1000,
+		300: 1000,
+	} {
+		assert.Equal(expected, s.Vested(1000, time), "at %d", time)
+	}
+
+	// The vested amount doesn't overflow for large amounts
+	assert.Equal(uint64(math.MaxUint64/2), s.Vested(math.MaxUint64, 150))
+
+	// A schedule that starts, reaches its cliff and ends at once is a locktime
+	s = Schedule{Start: 100, Cliff: 100, End: 100}
+	assert.Equal(uint64(0), s.Vested(1000, 99))
+	assert.Equal(uint64(1000), s.Vested(1000, 100))
+}
+
+func TestVestingOutputVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	var nilOut *VestingOutput
+	assert.ErrorIs(nilOut.Verify(), errNilVestingOutput)
+
+	out := &VestingOutput{
+		Schedule: Schedule{Start: 100, Cliff: 150, End: 200},
+		Amt:      1000,
+		Released: 999,
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+		},
+	}
+	assert.NoError(out.Verify())
+
+	out.Released = 1000
+	assert.ErrorIs(out.Verify(), errAllReleased)
+	out.Released = 0
+
+	out.Schedule.Cliff = 250
+	assert.ErrorIs(out.Verify(), errScheduleNotOrdered)
+	out.Schedule.Cliff = 50
+	assert.ErrorIs(out.Verify(), errScheduleNotOrdered)
+	out.Schedule.Cliff = 150
+
+	out.Amt = 0
+	assert.ErrorIs(out.Verify(), errNoValueOutput)
+	out.Amt = 1000
+
+	out.Threshold = 2
+	assert.Error(out.Verify())
+}
+
+func TestVestingOutputUnlocked(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &VestingOutput{
+		Schedule: Schedule{Start: 100, Cliff: 150, End: 200},
+		Amt:      1000,
+		Released: 600,
+	}
+	assert.Equal(uint64(0), out.Unlocked(150))
+	assert.Equal(uint64(150), out.Unlocked(175))
+	assert.Equal(uint64(400), out.Unlocked(200))
+}
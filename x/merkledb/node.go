@@ -0,0 +1,136 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var errStubConflict = errors.New("node conflicts with a pruned subtree")
+
+// node is a node of the trie. Every node other than the root either has a
+// value or at least two children, so a node's children may be many nibbles
+// deeper than the node itself.
+type node struct {
+	path     Path
+	hasValue bool
+	value    []byte
+	children [NodeBranchFactor]*node
+
+	// If true, this node stands in for a subtree that isn't known locally. Its
+	// ID is fixed and it has no value or children. Stubs are only created
+	// while verifying range proofs.
+	stub bool
+
+	// Cached ID of this node, which is valid iff [idValid] is true
+	id      ids.ID
+	idValid bool
+}
+
+// ID returns the hash of this node, computing the IDs of any descendants whose
+// IDs were invalidated.
+func (n *node) ID() ids.ID {
+	if n.idValid {
+		return n.id
+	}
+	proofNode := n.proofNode()
+	n.id = proofNode.ID()
+	n.idValid = true
+	return n.id
+}
+
+// proofNode returns the representation of this node that is included in
+// proofs
+func (n *node) proofNode() ProofNode {
+	proofNode := ProofNode{
+		KeyPath:  n.path,
+		HasValue: n.hasValue,
+		Value:    n.value,
+	}
+	for i, child := range n.children {
+		if child == nil {
+			continue
+		}
+		proofNode.Children = append(proofNode.Children, ProofChild{
+			Index:   byte(i),
+			KeyPath: child.path,
+			ID:      child.ID(),
+		})
+	}
+	return proofNode
+}
+
+// compact returns the node that should replace this node in its parent after
+// one of its descendants was removed
+func (n *node) compact() *node {
+	if n.hasValue {
+		return n
+	}
+
+	var (
+		onlyChild   *node
+		numChildren int
+	)
+	for _, child := range n.children {
+		if child != nil {
+			onlyChild = child
+			numChildren++
+		}
+	}
+	switch numChildren {
+	case 0:
+		return nil
+	case 1:
+		return onlyChild
+	default:
+		return n
+	}
+}
+
+// ProofChild is a reference from a ProofNode to one of its children
+type ProofChild struct {
+	// Nibble of the child's path following the parent's path
+	Index   byte   `serialize:"true"`
+	KeyPath Path   `serialize:"true"`
+	ID      ids.ID `serialize:"true"`
+}
+
+// ProofNode is a node of the trie as it is included in proofs
+type ProofNode struct {
+	KeyPath  Path   `serialize:"true"`
+	HasValue bool   `serialize:"true"`
+	Value    []byte `serialize:"true"`
+	// Sorted by index
+	Children []ProofChild `serialize:"true"`
+}
+
+// ID returns the hash of this node
+func (n *ProofNode) ID() ids.ID {
+	p := wrappers.Packer{MaxSize: math.MaxInt32}
+	p.PackBytes(n.KeyPath)
+	p.PackBool(n.HasValue)
+	p.PackBytes(n.Value)
+	p.PackByte(byte(len(n.Children)))
+	for _, child := range n.Children {
+		p.PackByte(child.Index)
+		p.PackBytes(child.KeyPath)
+		p.PackFixedBytes(child.ID[:])
+	}
+	return hashing.ComputeHash256Array(p.Bytes)
+}
+
+// child returns the child at [index], if there is one
+func (n *ProofNode) child(index byte) (ProofChild, bool) {
+	for _, child := range n.Children {
+		if child.Index == index {
+			return child, true
+		}
+	}
+	return ProofChild{}, false
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+)
+
+// NodeBranchFactor is the maximum number of children of a node
+const NodeBranchFactor = 16
+
+// Path is the location of a node in the trie. Each element is a nibble of the
+// keys stored below the node, so the path of a key is twice as long as the key.
+type Path []byte
+
+// NewPath returns the path of [key]
+func NewPath(key []byte) Path {
+	p := make(Path, 2*len(key))
+	for i, b := range key {
+		p[2*i] = b >> 4
+		p[2*i+1] = b & 0x0f
+	}
+	return p
+}
+
+// Key returns the key with this path. Only paths of even length correspond to
+// a key.
+func (p Path) Key() ([]byte, bool) {
+	if len(p)%2 != 0 {
+		return nil, false
+	}
+	key := make([]byte, len(p)/2)
+	for i := range key {
+		key[i] = p[2*i]<<4 | p[2*i+1]
+	}
+	return key, true
+}
+
+// HasPrefix returns true if [prefix] is a prefix of this path
+func (p Path) HasPrefix(prefix Path) bool {
+	return bytes.HasPrefix(p, prefix)
+}
+
+// Compare returns -1, 0 or 1 depending on whether this path sorts before,
+// equal to or after [other]. Paths sort in the same order as their keys.
+func (p Path) Compare(other Path) int {
+	return bytes.Compare(p, other)
+}
+
+// valid returns true if every element of this path is a nibble
+func (p Path) valid() bool {
+	for _, nibble := range p {
+		if nibble >= NodeBranchFactor {
+			return false
+		}
+	}
+	return true
+}
+
+// commonPrefixLen returns the length of the longest common prefix of [a] and
+// [b]
+func commonPrefixLen(a, b Path) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	errEmptyProof         = errors.New("proof is empty")
+	errInvalidPath        = errors.New("proof contains an invalid path")
+	errNonRootStart       = errors.New("proof doesn't start at the root")
+	errUnexpectedID       = errors.New("proof node has an unexpected ID")
+	errUnexpectedNode     = errors.New("proof node isn't on the path to the key")
+	errUnexpectedValue    = errors.New("proven value doesn't match the value in the trie")
+	errIncompleteProof    = errors.New("proof ends before the key's path ends")
+	errInvalidRange       = errors.New("start is after end")
+	errUnsortedKeys       = errors.New("keys aren't sorted")
+	errKeyOutOfRange      = errors.New("key is outside of the proven range")
+	errMissingEndProof    = errors.New("end proof is missing")
+	errUnexpectedEndProof = errors.New("end proof was provided for an unbounded range")
+	errOddLengthValuePath = errors.New("proof node with a value has an odd length path")
+	errRootMismatch       = errors.New("range proof doesn't match the expected root")
+)
+
+// Proof proves that [Key] maps to [Value], or that [Key] isn't in the trie if
+// [HasValue] is false.
+type Proof struct {
+	Key      []byte `serialize:"true"`
+	HasValue bool   `serialize:"true"`
+	Value    []byte `serialize:"true"`
+	// Nodes from the root to the deepest node whose path is a prefix of the
+	// key's path
+	Path []ProofNode `serialize:"true"`
+}
+
+// Verify returns nil iff this proof is valid for a trie with root
+// [expectedRoot]
+func (p *Proof) Verify(expectedRoot ids.ID) error {
+	lastNode, err := verifyProofPath(p.Path, NewPath(p.Key), expectedRoot)
+	if err != nil {
+		return err
+	}
+
+	keyPath := NewPath(p.Key)
+	if len(lastNode.KeyPath) == len(keyPath) {
+		if lastNode.HasValue != p.HasValue || !bytes.Equal(lastNode.Value, p.Value) {
+			return errUnexpectedValue
+		}
+		return nil
+	}
+
+	// The proof ends at an ancestor of the key, so it must show that there is
+	// no node on the key's path below it.
+	if p.HasValue {
+		return errUnexpectedValue
+	}
+	if child, ok := lastNode.child(keyPath[len(lastNode.KeyPath)]); ok && keyPath.HasPrefix(child.KeyPath) {
+		return errIncompleteProof
+	}
+	return nil
+}
+
+// verifyProofPath verifies that [proofPath] is a chain of nodes, following
+// [keyPath], from the node with ID [expectedRoot]. Returns the last node of the
+// chain.
+func verifyProofPath(proofPath []ProofNode, keyPath Path, expectedRoot ids.ID) (*ProofNode, error) {
+	if len(proofPath) == 0 {
+		return nil, errEmptyProof
+	}
+	if len(proofPath[0].KeyPath) != 0 {
+		return nil, errNonRootStart
+	}
+
+	expectedID := expectedRoot
+	for i := range proofPath {
+		proofNode := &proofPath[i]
+		if !proofNode.KeyPath.valid() {
+			return nil, errInvalidPath
+		}
+		if proofNode.ID() != expectedID {
+			return nil, fmt.Errorf("%w at depth %d", errUnexpectedID, i)
+		}
+		if !keyPath.HasPrefix(proofNode.KeyPath) {
+			return nil, errUnexpectedNode
+		}
+		if i == len(proofPath)-1 {
+			return proofNode, nil
+		}
+
+		if len(proofNode.KeyPath) == len(keyPath) {
+			return nil, errUnexpectedNode
+		}
+		child, ok := proofNode.child(keyPath[len(proofNode.KeyPath)])
+		if !ok || !bytes.Equal(child.KeyPath, proofPath[i+1].KeyPath) {
+			return nil, errUnexpectedNode
+		}
+		expectedID = child.ID
+	}
+	return nil, errEmptyProof
+}
+
+// KeyValue is a key value pair of the trie
+type KeyValue struct {
+	Key   []byte `serialize:"true"`
+	Value []byte `serialize:"true"`
+}
+
+// RangeProof proves that [KeyValues] are all of the key value pairs of the
+// trie from the start of the range to the last key in [KeyValues]. If
+// [KeyValues] is empty, it proves that the trie has no keys in the range.
+type RangeProof struct {
+	// Proof path of the start of the range
+	StartProof []ProofNode `serialize:"true"`
+	// Proof path of the last key in [KeyValues], or of the end of the range if
+	// [KeyValues] is empty. Empty if [KeyValues] and the end of the range are
+	// both empty.
+	EndProof []ProofNode `serialize:"true"`
+	// Sorted by key
+	KeyValues []KeyValue `serialize:"true"`
+}
+
+// Verify returns nil iff this proof is valid for the range [start, end] of a
+// trie with root [expectedRoot]. A nil [end] is treated as a key after all
+// keys in the trie.
+//
+// The trie is rebuilt from [KeyValues], with the subtrees outside of the
+// proven range replaced by the IDs given in the proof paths. The proof is
+// valid iff the rebuilt trie has root [expectedRoot].
+func (p *RangeProof) Verify(start, end []byte, expectedRoot ids.ID) error {
+	if end != nil && bytes.Compare(start, end) > 0 {
+		return errInvalidRange
+	}
+	if len(p.StartProof) == 0 {
+		return errEmptyProof
+	}
+	for i, keyValue := range p.KeyValues {
+		if i > 0 && bytes.Compare(p.KeyValues[i-1].Key, keyValue.Key) >= 0 {
+			return errUnsortedKeys
+		}
+		if bytes.Compare(keyValue.Key, start) < 0 || (end != nil && bytes.Compare(keyValue.Key, end) > 0) {
+			return errKeyOutOfRange
+		}
+	}
+
+	// Determine the last key of the proven range
+	var (
+		startPath   = NewPath(start)
+		endPath     Path
+		hasEndBound = true
+	)
+	switch {
+	case len(p.KeyValues) > 0:
+		endPath = NewPath(p.KeyValues[len(p.KeyValues)-1].Key)
+	case end != nil:
+		endPath = NewPath(end)
+	default:
+		hasEndBound = false
+	}
+	switch {
+	case hasEndBound && len(p.EndProof) == 0:
+		return errMissingEndProof
+	case !hasEndBound && len(p.EndProof) > 0:
+		return errUnexpectedEndProof
+	}
+
+	// keyOutOfRange returns true if the key with [path] isn't in the proven
+	// range
+	keyOutOfRange := func(path Path) bool {
+		return path.Compare(startPath) < 0 || (hasEndBound && path.Compare(endPath) > 0)
+	}
+	// subtreeOutOfRange returns true if no key with [path] as a prefix is in
+	// the proven range
+	subtreeOutOfRange := func(path Path) bool {
+		return keyOutOfRange(path) && !startPath.HasPrefix(path)
+	}
+
+	t := newTrie(nil)
+	for _, keyValue := range p.KeyValues {
+		if err := t.insert(&node{
+			path:     NewPath(keyValue.Key),
+			hasValue: true,
+			value:    keyValue.Value,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Add the values and subtrees outside of the range. Start and end proofs
+	// share their first nodes, so each path is only added once.
+	var (
+		addedValues = make(map[string]struct{})
+		addedStubs  = make(map[string]struct{})
+	)
+	for _, proofPath := range [][]ProofNode{p.StartProof, p.EndProof} {
+		for _, proofNode := range proofPath {
+			if !proofNode.KeyPath.valid() {
+				return errInvalidPath
+			}
+
+			if _, added := addedValues[string(proofNode.KeyPath)]; proofNode.HasValue && !added && keyOutOfRange(proofNode.KeyPath) {
+				if len(proofNode.KeyPath)%2 != 0 {
+					return errOddLengthValuePath
+				}
+				if err := t.insert(&node{
+					path:     proofNode.KeyPath,
+					hasValue: true,
+					value:    proofNode.Value,
+				}); err != nil {
+					return err
+				}
+				addedValues[string(proofNode.KeyPath)] = struct{}{}
+			}
+
+			for _, child := range proofNode.Children {
+				if !child.KeyPath.valid() {
+					return errInvalidPath
+				}
+				if _, added := addedStubs[string(child.KeyPath)]; added || !subtreeOutOfRange(child.KeyPath) {
+					continue
+				}
+				if err := t.insert(&node{
+					path:    child.KeyPath,
+					stub:    true,
+					id:      child.ID,
+					idValid: true,
+				}); err != nil {
+					return err
+				}
+				addedStubs[string(child.KeyPath)] = struct{}{}
+			}
+		}
+	}
+
+	if t.root.ID() != expectedRoot {
+		return errRootMismatch
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func newTestTrie(t *testing.T, keyValues map[string][]byte) Trie {
+	trie, err := New(memdb.New())
+	assert.NoError(t, err)
+	for key, value := range keyValues {
+		assert.NoError(t, trie.Put([]byte(key), value))
+	}
+	return trie
+}
+
+func TestProof(t *testing.T) {
+	assert := assert.New(t)
+
+	trie := newTestTrie(t, map[string][]byte{
+		"":      []byte("empty"),
+		"key":   []byte("value"),
+		"key1":  []byte("value1"),
+		"key12": []byte("value12"),
+		"other": []byte("other"),
+	})
+	root := trie.GetMerkleRoot()
+
+	for _, key := range []string{"", "key", "key1", "key12", "other"} {
+		proof, err := trie.GetProof([]byte(key))
+		assert.NoError(err)
+		assert.True(proof.HasValue)
+		assert.NoError(proof.Verify(root), "failed to verify %q", key)
+	}
+
+	// Proofs of absence
+	for _, key := range []string{"k", "key0", "key123", "zzz"} {
+		proof, err := trie.GetProof([]byte(key))
+		assert.NoError(err)
+		assert.False(proof.HasValue)
+		assert.NoError(proof.Verify(root), "failed to verify %q", key)
+	}
+
+	proof, err := trie.GetProof([]byte("key1"))
+	assert.NoError(err)
+
+	// The proof isn't valid for a different root
+	assert.Error(proof.Verify(ids.GenerateTestID()))
+
+	// The proof doesn't prove a different value or the absence of the key
+	proof.Value = []byte("wrong")
+	assert.ErrorIs(proof.Verify(root), errUnexpectedValue)
+	proof.HasValue = false
+	proof.Value = nil
+	assert.ErrorIs(proof.Verify(root), errUnexpectedValue)
+
+	// A present key can't be proven absent by truncating its proof
+	proof, err = trie.GetProof([]byte("key12"))
+	assert.NoError(err)
+	proof.HasValue = false
+	proof.Value = nil
+	proof.Path = proof.Path[:len(proof.Path)-1]
+	assert.ErrorIs(proof.Verify(root), errIncompleteProof)
+}
+
+func TestRangeProofRandom(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(0)) // #nosec G404
+	keyValues := newRandomKeyValues(r, 300)
+	trie := newTestTrie(t, keyValues)
+	root := trie.GetMerkleRoot()
+
+	keys := make([]string, 0, len(keyValues))
+	for key := range keyValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for i := 0; i < 100; i++ {
+		start := make([]byte, r.Intn(3))
+		_, _ = r.Read(start)
+		var end []byte
+		if r.Intn(4) != 0 {
+			end = make([]byte, r.Intn(3))
+			_, _ = r.Read(end)
+			if bytes.Compare(start, end) > 0 {
+				start, end = end, start
+			}
+		}
+		maxLength := 1 + r.Intn(50)
+
+		proof, err := trie.GetRangeProof(start, end, maxLength)
+		assert.NoError(err)
+		assert.LessOrEqual(len(proof.KeyValues), maxLength)
+		assert.NoError(proof.Verify(start, end, root))
+
+		// The proof contains exactly the keys from [start] to its last key
+		var expectedKeys []string
+		for _, key := range keys {
+			if key >= string(start) && (end == nil || key <= string(end)) && len(expectedKeys) < maxLength {
+				expectedKeys = append(expectedKeys, key)
+			}
+		}
+		var provenKeys []string
+		for _, keyValue := range proof.KeyValues {
+			provenKeys = append(provenKeys, string(keyValue.Key))
+		}
+		assert.Equal(expectedKeys, provenKeys)
+
+		if len(proof.KeyValues) == 0 {
+			continue
+		}
+
+		// Omitting a key invalidates the proof
+		omitted := *proof
+		index := r.Intn(len(proof.KeyValues))
+		omitted.KeyValues = append(
+			append([]KeyValue{}, proof.KeyValues[:index]...),
+			proof.KeyValues[index+1:]...,
+		)
+		if index != len(proof.KeyValues)-1 {
+			assert.Error(omitted.Verify(start, end, root))
+		}
+
+		// Modifying a value invalidates the proof
+		modified := *proof
+		modified.KeyValues = append([]KeyValue{}, proof.KeyValues...)
+		modified.KeyValues[index] = KeyValue{
+			Key:   proof.KeyValues[index].Key,
+			Value: append([]byte{1}, proof.KeyValues[index].Value...),
+		}
+		assert.ErrorIs(modified.Verify(start, end, root), errRootMismatch)
+	}
+}
+
+func TestRangeProofEmptyRange(t *testing.T) {
+	assert := assert.New(t)
+
+	trie := newTestTrie(t, map[string][]byte{
+		"a": []byte("a"),
+		"c": []byte("c"),
+	})
+	root := trie.GetMerkleRoot()
+
+	proof, err := trie.GetRangeProof([]byte("b"), []byte("b1"), 10)
+	assert.NoError(err)
+	assert.Empty(proof.KeyValues)
+	assert.NoError(proof.Verify([]byte("b"), []byte("b1"), root))
+
+	// An empty proof isn't valid for a range containing a key
+	assert.Error(proof.Verify([]byte("b"), []byte("c"), root))
+
+	_, err = trie.GetRangeProof(nil, nil, 0)
+	assert.ErrorIs(err, errInvalidMaxLength)
+	assert.ErrorIs(proof.Verify([]byte("b"), []byte("a"), root), errInvalidRange)
+}
+
+func TestRangeProofEmptyTrie(t *testing.T) {
+	assert := assert.New(t)
+
+	trie := newTestTrie(t, nil)
+	proof, err := trie.GetRangeProof(nil, nil, 10)
+	assert.NoError(err)
+	assert.Empty(proof.KeyValues)
+	assert.NoError(proof.Verify(nil, nil, trie.GetMerkleRoot()))
+}
+
+func TestProofMarshalling(t *testing.T) {
+	assert := assert.New(t)
+
+	trie := newTestTrie(t, map[string][]byte{
+		"key":  []byte("value"),
+		"key1": []byte("value1"),
+	})
+	root := trie.GetMerkleRoot()
+
+	proof, err := trie.GetProof([]byte("key1"))
+	assert.NoError(err)
+
+	proofBytes, err := Codec.Marshal(CodecVersion, proof)
+	assert.NoError(err)
+
+	parsedProof := &Proof{}
+	_, err = Codec.Unmarshal(proofBytes, parsedProof)
+	assert.NoError(err)
+	assert.NoError(parsedProof.Verify(root))
+}
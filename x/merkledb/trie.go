@@ -0,0 +1,393 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var (
+	errInvalidMaxLength = errors.New("max length must be positive")
+
+	_ Trie              = &trie{}
+	_ database.Iterator = &iterator{}
+)
+
+// Trie is a key value store that commits to its contents with a Merkle root,
+// and can prove the value, or absence, of keys and ranges of keys against that
+// root.
+type Trie interface {
+	database.KeyValueReaderWriterDeleter
+	database.Iteratee
+
+	// GetMerkleRoot returns the root of the trie
+	GetMerkleRoot() ids.ID
+
+	// GetProof returns a proof of the value of [key], or of its absence if
+	// [key] isn't in the trie.
+	GetProof(key []byte) (*Proof, error)
+
+	// GetRangeProof returns a proof of at most [maxLength] consecutive key
+	// value pairs, starting at [start] and ending at or before [end]. A nil
+	// [end] is treated as a key after all keys in the trie.
+	GetRangeProof(start, end []byte, maxLength int) (*RangeProof, error)
+}
+
+type trie struct {
+	// Calculating node IDs modifies the nodes, so the lock is held
+	// exclusively whenever IDs may be calculated.
+	lock sync.RWMutex
+	// The root is never removed or compacted, so that its path is always
+	// empty.
+	root *node
+	// db is written to before the trie is modified, so that the trie can be
+	// rebuilt from [db].
+	db database.KeyValueWriterDeleter
+}
+
+// New returns a trie containing the key value pairs of [db]. Modifications of
+// the trie are written through to [db].
+func New(db database.Database) (Trie, error) {
+	t := newTrie(db)
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		if err := t.insert(&node{
+			path:     NewPath(it.Key()),
+			hasValue: true,
+			value:    utils.CopyBytes(it.Value()),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return t, it.Error()
+}
+
+func newTrie(db database.KeyValueWriterDeleter) *trie {
+	return &trie{
+		root: &node{},
+		db:   db,
+	}
+}
+
+func (t *trie) Has(key []byte) (bool, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.get(NewPath(key)) != nil, nil
+}
+
+func (t *trie) Get(key []byte) ([]byte, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	n := t.get(NewPath(key))
+	if n == nil {
+		return nil, database.ErrNotFound
+	}
+	return utils.CopyBytes(n.value), nil
+}
+
+// get returns the node with a value at [key], or nil if there isn't one
+//
+// Assumes [t.lock] is held.
+func (t *trie) get(key Path) *node {
+	n := t.root
+	for len(n.path) < len(key) {
+		n = n.children[key[len(n.path)]]
+		if n == nil || !key.HasPrefix(n.path) {
+			return nil
+		}
+	}
+	if len(n.path) != len(key) || !n.hasValue {
+		return nil
+	}
+	return n
+}
+
+func (t *trie) Put(key, value []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.db != nil {
+		if err := t.db.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return t.insert(&node{
+		path:     NewPath(key),
+		hasValue: true,
+		value:    utils.CopyBytes(value),
+	})
+}
+
+// insert [leaf] into the trie. If [leaf] is a stub, it must not overlap with
+// any existing node.
+//
+// Assumes [t.lock] is held.
+func (t *trie) insert(leaf *node) error {
+	key := leaf.path
+	n := t.root
+	for {
+		if n.stub {
+			return errStubConflict
+		}
+		n.idValid = false
+
+		if len(n.path) == len(key) {
+			if leaf.stub {
+				return errStubConflict
+			}
+			n.hasValue = true
+			n.value = leaf.value
+			return nil
+		}
+
+		index := key[len(n.path)]
+		child := n.children[index]
+		switch {
+		case child == nil:
+			n.children[index] = leaf
+			return nil
+		case key.HasPrefix(child.path):
+			n = child
+		default:
+			// Split the compressed path to [child] at the point where [key]
+			// diverges from it. The loop continues at the new branch, which
+			// either takes the value or gets [leaf] as a new child.
+			branch := &node{
+				path: key[:commonPrefixLen(key, child.path)],
+			}
+			branch.children[child.path[len(branch.path)]] = child
+			n.children[index] = branch
+			n = branch
+		}
+	}
+}
+
+func (t *trie) Delete(key []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.db != nil {
+		if err := t.db.Delete(key); err != nil {
+			return err
+		}
+	}
+	t.remove(t.root, NewPath(key))
+	return nil
+}
+
+// remove the value at [key] from the subtree rooted at [n]. Returns true if
+// the subtree was modified.
+//
+// Assumes [t.lock] is held.
+func (t *trie) remove(n *node, key Path) bool {
+	if len(n.path) == len(key) {
+		if !n.hasValue {
+			return false
+		}
+		n.hasValue = false
+		n.value = nil
+		n.idValid = false
+		return true
+	}
+
+	index := key[len(n.path)]
+	child := n.children[index]
+	if child == nil || !key.HasPrefix(child.path) || !t.remove(child, key) {
+		return false
+	}
+	n.children[index] = child.compact()
+	n.idValid = false
+	return true
+}
+
+func (t *trie) GetMerkleRoot() ids.ID {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.root.ID()
+}
+
+func (t *trie) GetProof(key []byte) (*Proof, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	proof := &Proof{
+		Key:  utils.CopyBytes(key),
+		Path: t.proofPath(NewPath(key)),
+	}
+	if n := t.get(NewPath(key)); n != nil {
+		proof.HasValue = true
+		proof.Value = utils.CopyBytes(n.value)
+	}
+	return proof, nil
+}
+
+func (t *trie) GetRangeProof(start, end []byte, maxLength int) (*RangeProof, error) {
+	if maxLength <= 0 {
+		return nil, errInvalidMaxLength
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	proof := &RangeProof{
+		StartProof: t.proofPath(NewPath(start)),
+	}
+	endPath := NewPath(end)
+	t.walk(t.root, NewPath(start), nil, func(key Path, value []byte) bool {
+		if end != nil && key.Compare(endPath) > 0 {
+			return false
+		}
+		keyBytes, _ := key.Key()
+		proof.KeyValues = append(proof.KeyValues, KeyValue{
+			Key:   keyBytes,
+			Value: utils.CopyBytes(value),
+		})
+		return len(proof.KeyValues) < maxLength
+	})
+
+	switch {
+	case len(proof.KeyValues) > 0:
+		lastKey := proof.KeyValues[len(proof.KeyValues)-1].Key
+		proof.EndProof = t.proofPath(NewPath(lastKey))
+	case end != nil:
+		proof.EndProof = t.proofPath(endPath)
+	}
+	return proof, nil
+}
+
+// proofPath returns the nodes from the root to the deepest node whose path is
+// a prefix of [key]
+//
+// Assumes [t.lock] is held exclusively.
+func (t *trie) proofPath(key Path) []ProofNode {
+	var proofNodes []ProofNode
+	n := t.root
+	for {
+		proofNode := n.proofNode()
+		proofNode.KeyPath = Path(utils.CopyBytes(proofNode.KeyPath))
+		proofNode.Value = utils.CopyBytes(proofNode.Value)
+		proofNodes = append(proofNodes, proofNode)
+		if len(n.path) >= len(key) {
+			return proofNodes
+		}
+		n = n.children[key[len(n.path)]]
+		if n == nil || !key.HasPrefix(n.path) {
+			return proofNodes
+		}
+	}
+}
+
+// walk calls [f] with the key value pairs below [n], in order, that are at or
+// after [start] and that have [prefix]. Returns false once [f] returns false.
+//
+// Assumes [t.lock] is held.
+func (t *trie) walk(n *node, start, prefix Path, f func(Path, []byte) bool) bool {
+	// Skip subtrees that only contain keys before [start]
+	if !start.HasPrefix(n.path) && n.path.Compare(start) < 0 {
+		return true
+	}
+	// Skip subtrees that can't contain keys with [prefix]
+	if !n.path.HasPrefix(prefix) && !prefix.HasPrefix(n.path) {
+		return true
+	}
+
+	if n.hasValue && n.path.Compare(start) >= 0 && n.path.HasPrefix(prefix) {
+		if !f(n.path, n.value) {
+			return false
+		}
+	}
+	for _, child := range n.children {
+		if child != nil && !t.walk(child, start, prefix, f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *trie) NewIterator() database.Iterator {
+	return t.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (t *trie) NewIteratorWithStart(start []byte) database.Iterator {
+	return t.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (t *trie) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return t.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix returns an iterator over the contents of the
+// trie at the time it was created
+func (t *trie) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var (
+		keys   []string
+		values [][]byte
+	)
+	t.walk(t.root, NewPath(start), NewPath(prefix), func(key Path, value []byte) bool {
+		keyBytes, _ := key.Key()
+		keys = append(keys, string(keyBytes))
+		values = append(values, utils.CopyBytes(value))
+		return true
+	})
+	return &iterator{
+		keys:   keys,
+		values: values,
+	}
+}
+
+// iterator iterates over a snapshot of the trie's key value pairs
+type iterator struct {
+	initialized bool
+	keys        []string
+	values      [][]byte
+}
+
+func (it *iterator) Next() bool {
+	if !it.initialized {
+		it.initialized = true
+		return len(it.keys) > 0
+	}
+	if len(it.keys) > 0 {
+		it.keys[0] = ""
+		it.keys = it.keys[1:]
+		it.values[0] = nil
+		it.values = it.values[1:]
+	}
+	return len(it.keys) > 0
+}
+
+func (it *iterator) Error() error { return nil }
+
+func (it *iterator) Key() []byte {
+	if len(it.keys) > 0 {
+		return []byte(it.keys[0])
+	}
+	return nil
+}
+
+func (it *iterator) Value() []byte {
+	if len(it.values) > 0 {
+		return it.values[0]
+	}
+	return nil
+}
+
+func (it *iterator) Release() {
+	it.keys = nil
+	it.values = nil
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range []func(t *testing.T, db database.Database){
+		database.TestSimpleKeyValue,
+		database.TestKeyEmptyValue,
+		database.TestIterator,
+		database.TestIteratorStart,
+		database.TestIteratorPrefix,
+		database.TestIteratorStartPrefix,
+		database.TestIteratorMemorySafety,
+	} {
+		trie, err := New(memdb.New())
+		assert.NoError(t, err)
+		test(t, &testDatabase{Trie: trie})
+	}
+}
+
+// testDatabase allows the database tests that only read, write and iterate to
+// be run against a trie
+type testDatabase struct {
+	Trie
+	database.Database
+}
+
+func (db *testDatabase) Has(key []byte) (bool, error)   { return db.Trie.Has(key) }
+func (db *testDatabase) Get(key []byte) ([]byte, error) { return db.Trie.Get(key) }
+func (db *testDatabase) Put(key, value []byte) error    { return db.Trie.Put(key, value) }
+func (db *testDatabase) Delete(key []byte) error        { return db.Trie.Delete(key) }
+func (db *testDatabase) NewIterator() database.Iterator { return db.Trie.NewIterator() }
+func (db *testDatabase) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.Trie.NewIteratorWithStart(start)
+}
+
+func (db *testDatabase) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.Trie.NewIteratorWithPrefix(prefix)
+}
+
+func (db *testDatabase) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return db.Trie.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+func newRandomKeyValues(r *rand.Rand, n int) map[string][]byte {
+	keyValues := make(map[string][]byte, n)
+	for len(keyValues) < n {
+		key := make([]byte, r.Intn(4))
+		_, _ = r.Read(key)
+		value := make([]byte, r.Intn(8))
+		_, _ = r.Read(value)
+		keyValues[string(key)] = value
+	}
+	return keyValues
+}
+
+func TestRootIndependentOfOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(0)) // #nosec G404
+	keyValues := newRandomKeyValues(r, 200)
+
+	keys := make([]string, 0, len(keyValues))
+	for key := range keyValues {
+		keys = append(keys, key)
+	}
+
+	trie0, err := New(memdb.New())
+	assert.NoError(err)
+	emptyRoot := trie0.GetMerkleRoot()
+	for _, key := range keys {
+		assert.NoError(trie0.Put([]byte(key), keyValues[key]))
+	}
+
+	trie1, err := New(memdb.New())
+	assert.NoError(err)
+	r.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	for _, key := range keys {
+		assert.NoError(trie1.Put([]byte(key), keyValues[key]))
+		// Computing the root in between writes must not affect the result
+		_ = trie1.GetMerkleRoot()
+	}
+	root := trie0.GetMerkleRoot()
+	assert.Equal(root, trie1.GetMerkleRoot())
+	assert.NotEqual(emptyRoot, root)
+
+	// Removing every key returns the trie to its empty state
+	for _, key := range keys {
+		assert.NoError(trie1.Delete([]byte(key)))
+	}
+	assert.Equal(emptyRoot, trie1.GetMerkleRoot())
+}
+
+func TestRootChangesOnUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	trie, err := New(memdb.New())
+	assert.NoError(err)
+	assert.NoError(trie.Put([]byte("key"), []byte("value")))
+	root := trie.GetMerkleRoot()
+
+	assert.NoError(trie.Put([]byte("key"), []byte("other")))
+	assert.NotEqual(root, trie.GetMerkleRoot())
+
+	assert.NoError(trie.Put([]byte("key"), []byte("value")))
+	assert.Equal(root, trie.GetMerkleRoot())
+
+	// Deleting a key that isn't in the trie is a no-op
+	assert.NoError(trie.Delete([]byte("ke")))
+	assert.NoError(trie.Delete([]byte("keys")))
+	assert.Equal(root, trie.GetMerkleRoot())
+}
+
+func TestReloadFromDatabase(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	trie, err := New(db)
+	assert.NoError(err)
+
+	r := rand.New(rand.NewSource(1)) // #nosec G404
+	for key, value := range newRandomKeyValues(r, 100) {
+		assert.NoError(trie.Put([]byte(key), value))
+	}
+	assert.NoError(trie.Delete([]byte{}))
+
+	reloaded, err := New(db)
+	assert.NoError(err)
+	assert.Equal(trie.GetMerkleRoot(), reloaded.GetMerkleRoot())
+}